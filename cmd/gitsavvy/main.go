@@ -0,0 +1,25 @@
+// Command gitsavvy is svf under its original name. It is built from the
+// same command tree, so the two binaries are interchangeable.
+package main
+
+import (
+	"os"
+
+	// Register AI providers
+	_ "github.com/chazuruo/svf/internal/ai/openai"
+	"github.com/chazuruo/svf/internal/cli"
+)
+
+// Version is set at build time using ldflags
+var Version = "dev"
+
+// Commit is set at build time using ldflags
+var Commit = "unknown"
+
+// Date is set at build time using ldflags
+var Date = "unknown"
+
+func main() {
+	rootCmd := cli.NewRootCommand("gitsavvy", cli.BuildInfo{Version: Version, Commit: Commit, Date: Date})
+	os.Exit(cli.Execute(rootCmd))
+}
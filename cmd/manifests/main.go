@@ -0,0 +1,69 @@
+// Command manifests writes the Homebrew formula and Scoop manifest of a
+// release from the checksums file goreleaser leaves in the dist directory.
+// Run it through make manifests after a release build.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/chazuruo/svf/internal/packaging"
+	"github.com/chazuruo/svf/internal/upgrade"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "manifests: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	release := packaging.Release{
+		Name:        "svf",
+		Description: "Git-backed workflow automation tool",
+		Homepage:    "https://github.com/chazu/faire",
+		License:     "MIT",
+		Repo:        "chazu/faire",
+	}
+	dist := flag.String("dist", "dist", "directory with the release archives and checksums file")
+	out := flag.String("out", "", "directory to write svf.rb and svf.json to (default: the dist directory)")
+	flag.StringVar(&release.Version, "version", "", "release version, e.g. v1.2.3")
+	flag.Parse()
+
+	if release.Version == "" {
+		return fmt.Errorf("--version is required")
+	}
+	if *out == "" {
+		*out = *dist
+	}
+
+	checksums, err := os.ReadFile(filepath.Join(*dist, upgrade.ChecksumsName(release.Name, release.Version)))
+	if err != nil {
+		return err
+	}
+	release.Checksums = upgrade.ParseChecksums(string(checksums))
+
+	formula, err := packaging.Homebrew(release)
+	if err != nil {
+		return fmt.Errorf("homebrew formula: %w", err)
+	}
+	manifest, err := packaging.Scoop(release)
+	if err != nil {
+		return fmt.Errorf("scoop manifest: %w", err)
+	}
+
+	for _, f := range []struct {
+		name string
+		data []byte
+	}{{release.Name + ".rb", formula}, {release.Name + ".json", manifest}} {
+		path := filepath.Join(*out, f.name)
+		if err := os.WriteFile(path, f.data, 0o644); err != nil {
+			return err
+		}
+		fmt.Println("Wrote", path)
+	}
+	return nil
+}
@@ -0,0 +1,362 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// systemPromptTokens approximates the fixed instructions sent with every request.
+const systemPromptTokens = 150
+
+// EstimateTokens roughly estimates the number of tokens in s. Most
+// tokenizers average about four characters per token for English and code.
+func EstimateTokens(s string) int {
+	n := utf8.RuneCountInString(s)
+	if n == 0 {
+		return 0
+	}
+	return (n + 3) / 4
+}
+
+// EstimateRequest estimates the prompt tokens of a generation request,
+// including history context, existing workflows and any workflow being
+// refined.
+func EstimateRequest(req GenerateRequest) int {
+	tokens := systemPromptTokens + EstimateTokens(req.Prompt)
+	if req.Context != nil {
+		tokens += EstimateTokens(strings.Join(req.Context.History, "\n"))
+		for _, wf := range req.Context.ExistingWorkflows {
+			tokens += EstimateTokens(SummarizeWorkflow(wf))
+		}
+	}
+	for _, wf := range []*workflows.Workflow{req.Previous, req.Target} {
+		if wf == nil {
+			continue
+		}
+		if data, err := workflows.MarshalWorkflow(wf); err == nil {
+			tokens += EstimateTokens(string(data))
+		}
+	}
+	return tokens
+}
+
+// Pricing is the price of a model in US dollars per million tokens.
+type Pricing struct {
+	Input  float64
+	Output float64
+}
+
+// modelPricing lists known model prices. Unknown models have no estimate.
+var modelPricing = map[string]Pricing{
+	"gpt-4o":        {Input: 2.50, Output: 10.00},
+	"gpt-4o-mini":   {Input: 0.15, Output: 0.60},
+	"gpt-4.1":       {Input: 2.00, Output: 8.00},
+	"gpt-4.1-mini":  {Input: 0.40, Output: 1.60},
+	"gpt-4.1-nano":  {Input: 0.10, Output: 0.40},
+	"gpt-4-turbo":   {Input: 10.00, Output: 30.00},
+	"gpt-3.5-turbo": {Input: 0.50, Output: 1.50},
+}
+
+// localProviders run on the user's machine and cost nothing per token.
+var localProviders = map[string]bool{
+	"ollama": true,
+}
+
+// EstimateCost estimates the cost in US dollars of a request. known is
+// false when the model's pricing is not known.
+func EstimateCost(provider, model string, inputTokens, outputTokens int) (cost float64, known bool) {
+	if localProviders[provider] {
+		return 0, true
+	}
+	p, ok := modelPricing[model]
+	if !ok {
+		return 0, false
+	}
+	return (float64(inputTokens)*p.Input + float64(outputTokens)*p.Output) / 1e6, true
+}
+
+// Estimate describes the expected size and cost of a request.
+type Estimate struct {
+	Provider     string
+	Model        string
+	InputTokens  int
+	OutputTokens int
+	Cost         float64
+	CostKnown    bool
+
+	// Truncated is set when the input exceeds the prompt budget and will
+	// be shortened; InputTokens is then the budgeted size.
+	Truncated      bool
+	OriginalTokens int
+}
+
+// NewEstimate estimates a request of inputTokens against cfg, assuming
+// the response uses up to cfg.MaxTokens and the input is cut down to
+// cfg.MaxPromptTokens.
+func NewEstimate(cfg *Config, inputTokens int) Estimate {
+	e := Estimate{
+		Provider:       cfg.Provider,
+		Model:          cfg.Model,
+		InputTokens:    inputTokens,
+		OutputTokens:   cfg.MaxTokens,
+		OriginalTokens: inputTokens,
+	}
+	if budget := cfg.MaxPromptTokens + systemPromptTokens; cfg.MaxPromptTokens > 0 && inputTokens > budget {
+		e.InputTokens = budget
+		e.Truncated = true
+	}
+	e.Cost, e.CostKnown = EstimateCost(cfg.Provider, cfg.Model, e.InputTokens, e.OutputTokens)
+	return e
+}
+
+// String formats the estimate for display.
+func (e Estimate) String() string {
+	s := fmt.Sprintf("~%d prompt tokens", e.InputTokens)
+	if e.Truncated {
+		s += fmt.Sprintf(" (truncated from ~%d)", e.OriginalTokens)
+	}
+	switch {
+	case localProviders[e.Provider]:
+		s += fmt.Sprintf(", no cost (%s/%s runs locally)", e.Provider, e.Model)
+	case e.CostKnown:
+		s += fmt.Sprintf(", up to ~$%.4f (%s/%s)", e.Cost, e.Provider, e.Model)
+	default:
+		s += fmt.Sprintf(", cost unknown for %s/%s", e.Provider, e.Model)
+	}
+	return s
+}
+
+// Truncation selects how oversized input is cut down to the budget.
+type Truncation string
+
+const (
+	// TruncateHead keeps the beginning of the input.
+	TruncateHead Truncation = "head"
+	// TruncateTail keeps the end of the input.
+	TruncateTail Truncation = "tail"
+	// TruncateSummarize collapses blank and repeated lines, then keeps the
+	// beginning and end with a note about what was omitted.
+	TruncateSummarize Truncation = "summarize"
+)
+
+// ParseTruncation parses a truncation strategy name.
+func ParseTruncation(s string) (Truncation, error) {
+	switch t := Truncation(strings.ToLower(strings.TrimSpace(s))); t {
+	case TruncateHead, TruncateTail, TruncateSummarize:
+		return t, nil
+	case "":
+		return TruncateSummarize, nil
+	}
+	return "", fmt.Errorf("invalid truncation strategy %q (valid: head, tail, summarize)", s)
+}
+
+// Truncate cuts s down to roughly maxTokens using strategy. It returns s
+// unchanged when it fits or maxTokens is not positive.
+func Truncate(s string, maxTokens int, strategy Truncation) (string, bool) {
+	if maxTokens <= 0 || EstimateTokens(s) <= maxTokens {
+		return s, false
+	}
+
+	maxRunes := maxTokens * 4
+	runes := []rune(s)
+
+	switch strategy {
+	case TruncateHead:
+		return string(runes[:maxRunes]) + "\n[... truncated ...]", true
+
+	case TruncateTail:
+		return "[... truncated ...]\n" + string(runes[len(runes)-maxRunes:]), true
+
+	default:
+		collapsed := collapseLines(s)
+		if EstimateTokens(collapsed) <= maxTokens {
+			return collapsed, true
+		}
+		runes = []rune(collapsed)
+		half := maxRunes / 2
+		omitted := len(runes) - 2*half
+		return fmt.Sprintf("%s\n[... %d characters omitted ...]\n%s",
+			string(runes[:half]), omitted, string(runes[len(runes)-half:])), true
+	}
+}
+
+// collapseLines removes blank lines and folds runs of identical lines.
+func collapseLines(s string) string {
+	var out []string
+	var prev string
+	repeats := 0
+
+	flush := func() {
+		if repeats > 0 {
+			out = append(out, fmt.Sprintf("[previous line repeated %d more times]", repeats))
+			repeats = 0
+		}
+	}
+
+	for _, line := range strings.Split(s, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if line == prev {
+			repeats++
+			continue
+		}
+		flush()
+		out = append(out, line)
+		prev = line
+	}
+	flush()
+
+	return strings.Join(out, "\n")
+}
+
+// Usage is the token usage of a single provider request.
+type Usage struct {
+	At               time.Time `json:"at"`
+	Provider         string    `json:"provider"`
+	Model            string    `json:"model"`
+	Operation        string    `json:"operation"`
+	PromptTokens     int       `json:"prompt_tokens"`
+	CompletionTokens int       `json:"completion_tokens"`
+	Estimated        bool      `json:"estimated,omitempty"`
+}
+
+// UsageReporter is implemented by providers that report the token usage
+// of their most recent request.
+type UsageReporter interface {
+	LastUsage() (Usage, bool)
+}
+
+// usageLogMu serializes appends to the usage log.
+var usageLogMu sync.Mutex
+
+// UsageLogPath returns the path of the local AI usage log.
+func UsageLogPath() (string, error) {
+	dir, err := config.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "ai-usage.jsonl"), nil
+}
+
+// LogUsage appends a usage entry to the local usage log.
+func LogUsage(u Usage) error {
+	path, err := UsageLogPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(u)
+	if err != nil {
+		return err
+	}
+
+	usageLogMu.Lock()
+	defer usageLogMu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create usage log directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open usage log: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// budgetedProvider enforces a prompt token budget and logs usage.
+type budgetedProvider struct {
+	Provider
+	maxTokens  int
+	truncation Truncation
+}
+
+// WithBudget wraps p so oversized prompts, history and command output are
+// truncated to maxTokens using truncation before being sent, and the usage
+// of every request is appended to the usage log when p reports it.
+func WithBudget(p Provider, maxTokens int, truncation Truncation) Provider {
+	return &budgetedProvider{Provider: p, maxTokens: maxTokens, truncation: truncation}
+}
+
+// remaining returns the budget left once used tokens are spent. It is at
+// least 1 when there is a budget, so an exhausted budget truncates the
+// rest to almost nothing instead of disabling truncation.
+func (b *budgetedProvider) remaining(used int) int {
+	if b.maxTokens <= 0 {
+		return 0
+	}
+	return max(1, b.maxTokens-used)
+}
+
+// GenerateWorkflow implements Provider.
+func (b *budgetedProvider) GenerateWorkflow(ctx context.Context, req GenerateRequest) (*workflows.Workflow, error) {
+	req.Prompt, _ = Truncate(req.Prompt, b.maxTokens, b.truncation)
+	if req.Context != nil && len(req.Context.History) > 0 {
+		// History is shared with the prompt budget; keep the rest for it
+		history, truncated := Truncate(strings.Join(req.Context.History, "\n"), b.remaining(EstimateTokens(req.Prompt)), b.truncation)
+		if truncated {
+			c := *req.Context
+			c.History = strings.Split(history, "\n")
+			req.Context = &c
+		}
+	}
+	if b.maxTokens > 0 && req.Context != nil && len(req.Context.ExistingWorkflows) > 0 {
+		// Existing workflows get what the prompt and history leave, most
+		// relevant first
+		remaining := b.maxTokens - EstimateTokens(req.Prompt) - EstimateTokens(strings.Join(req.Context.History, "\n"))
+		if packed := packWorkflows(req.Context.ExistingWorkflows, remaining); len(packed) < len(req.Context.ExistingWorkflows) {
+			c := *req.Context
+			c.ExistingWorkflows = packed
+			req.Context = &c
+		}
+	}
+
+	wf, err := b.Provider.GenerateWorkflow(ctx, req)
+	b.logUsage("generate")
+	return wf, err
+}
+
+// Explain implements Provider.
+func (b *budgetedProvider) Explain(ctx context.Context, req ExplainRequest) (string, error) {
+	req.Command, _ = Truncate(req.Command, b.maxTokens, b.truncation)
+	req.Output, _ = Truncate(req.Output, b.remaining(EstimateTokens(req.Command)), b.truncation)
+
+	text, err := b.Provider.Explain(ctx, req)
+	b.logUsage("explain")
+	return text, err
+}
+
+// LastUsage implements UsageReporter when the wrapped provider does.
+func (b *budgetedProvider) LastUsage() (Usage, bool) {
+	if r, ok := b.Provider.(UsageReporter); ok {
+		return r.LastUsage()
+	}
+	return Usage{}, false
+}
+
+// logUsage records the wrapped provider's last usage, if reported.
+func (b *budgetedProvider) logUsage(operation string) {
+	u, ok := b.LastUsage()
+	if !ok {
+		return
+	}
+	u.Operation = operation
+	if u.At.IsZero() {
+		u.At = time.Now()
+	}
+	// Usage logging is best effort and never fails a request
+	_ = LogUsage(u)
+}
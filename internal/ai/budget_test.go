@@ -0,0 +1,191 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	assert.Equal(t, 0, EstimateTokens(""))
+	assert.Equal(t, 1, EstimateTokens("abc"))
+	assert.Equal(t, 2, EstimateTokens("abcdefgh"))
+	assert.Equal(t, 25, EstimateTokens(strings.Repeat("x", 100)))
+}
+
+func TestEstimateCost(t *testing.T) {
+	cost, known := EstimateCost("openai", "gpt-4o-mini", 1_000_000, 1_000_000)
+	assert.True(t, known)
+	assert.InDelta(t, 0.75, cost, 1e-9)
+
+	cost, known = EstimateCost("ollama", "llama2", 5000, 5000)
+	assert.True(t, known)
+	assert.Zero(t, cost)
+
+	_, known = EstimateCost("openai", "some-unknown-model", 100, 100)
+	assert.False(t, known)
+}
+
+func TestNewEstimate_Truncated(t *testing.T) {
+	cfg := &Config{Provider: "openai", Model: "gpt-4o", MaxTokens: 100, MaxPromptTokens: 1000}
+
+	e := NewEstimate(cfg, 500)
+	assert.False(t, e.Truncated)
+	assert.Equal(t, 500, e.InputTokens)
+
+	e = NewEstimate(cfg, 5000)
+	assert.True(t, e.Truncated)
+	assert.Equal(t, 1000+systemPromptTokens, e.InputTokens)
+	assert.Equal(t, 5000, e.OriginalTokens)
+	assert.Contains(t, e.String(), "truncated from ~5000")
+	assert.Contains(t, e.String(), "openai/gpt-4o")
+}
+
+func TestParseTruncation(t *testing.T) {
+	for in, want := range map[string]Truncation{
+		"head":      TruncateHead,
+		"TAIL":      TruncateTail,
+		"summarize": TruncateSummarize,
+		"":          TruncateSummarize,
+	} {
+		got, err := ParseTruncation(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+
+	_, err := ParseTruncation("middle")
+	assert.Error(t, err)
+}
+
+func TestTruncate(t *testing.T) {
+	text := "first line\n" + strings.Repeat("filler\n", 200) + "last line"
+
+	out, truncated := Truncate(text, 0, TruncateHead)
+	assert.False(t, truncated)
+	assert.Equal(t, text, out)
+
+	out, truncated = Truncate(text, 10, TruncateHead)
+	assert.True(t, truncated)
+	assert.True(t, strings.HasPrefix(out, "first line"))
+	assert.NotContains(t, out, "last line")
+
+	out, truncated = Truncate(text, 10, TruncateTail)
+	assert.True(t, truncated)
+	assert.True(t, strings.HasSuffix(out, "last line"))
+	assert.NotContains(t, out, "first line")
+
+	// Repeated lines collapse, keeping both ends
+	out, truncated = Truncate(text, 30, TruncateSummarize)
+	assert.True(t, truncated)
+	assert.Contains(t, out, "first line")
+	assert.Contains(t, out, "last line")
+	assert.Contains(t, out, "repeated 199 more times")
+}
+
+func TestTruncate_SummarizeKeepsEnds(t *testing.T) {
+	var lines []string
+	for i := 0; i < 200; i++ {
+		lines = append(lines, strings.Repeat(string(rune('a'+i%26)), 10))
+	}
+	text := strings.Join(lines, "\n")
+
+	out, truncated := Truncate(text, 20, TruncateSummarize)
+	assert.True(t, truncated)
+	assert.True(t, strings.HasPrefix(out, lines[0]))
+	assert.True(t, strings.HasSuffix(out, lines[len(lines)-1]))
+	assert.Contains(t, out, "characters omitted")
+}
+
+// usageProvider records requests and reports fixed usage.
+type usageProvider struct {
+	lastGenerate GenerateRequest
+	lastExplain  ExplainRequest
+}
+
+func (p *usageProvider) Name() string { return "usage" }
+
+func (p *usageProvider) GenerateWorkflow(_ context.Context, req GenerateRequest) (*workflows.Workflow, error) {
+	p.lastGenerate = req
+	return &workflows.Workflow{Title: "ok"}, nil
+}
+
+func (p *usageProvider) Explain(_ context.Context, req ExplainRequest) (string, error) {
+	p.lastExplain = req
+	return "ok", nil
+}
+
+func (p *usageProvider) LastUsage() (Usage, bool) {
+	return Usage{Provider: "usage", Model: "m", PromptTokens: 12, CompletionTokens: 34}, true
+}
+
+func TestWithBudget(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	inner := &usageProvider{}
+	p := WithBudget(inner, 10, TruncateTail)
+
+	_, err := p.GenerateWorkflow(context.Background(), GenerateRequest{
+		Prompt:  "deploy",
+		Context: &GenerateContext{History: []string{strings.Repeat("a", 100), "git push"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "deploy", inner.lastGenerate.Prompt)
+	assert.Equal(t, "git push", inner.lastGenerate.Context.History[len(inner.lastGenerate.Context.History)-1])
+	assert.LessOrEqual(t, EstimateTokens(strings.Join(inner.lastGenerate.Context.History, "\n")), 20)
+
+	_, err = p.Explain(context.Background(), ExplainRequest{
+		Type:    ExplainFailure,
+		Command: "make",
+		Output:  strings.Repeat("noise\n", 100) + "error: boom",
+	})
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(inner.lastExplain.Output, "error: boom"))
+
+	// Both requests are logged
+	path, err := UsageLogPath()
+	require.NoError(t, err)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+
+	var u Usage
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &u))
+	assert.Equal(t, "generate", u.Operation)
+	assert.Equal(t, 12, u.PromptTokens)
+	assert.Equal(t, 34, u.CompletionTokens)
+	assert.False(t, u.At.IsZero())
+
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &u))
+	assert.Equal(t, "explain", u.Operation)
+
+	info, err := os.Stat(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.True(t, info.IsDir())
+}
+
+func TestWithBudget_Exhausted(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	inner := &usageProvider{}
+	p := WithBudget(inner, 10, TruncateTail)
+
+	// The command uses the whole budget; the output still gets truncated
+	_, err := p.Explain(context.Background(), ExplainRequest{
+		Type:    ExplainFailure,
+		Command: strings.Repeat("x", 100),
+		Output:  strings.Repeat("noise\n", 100) + "error: boom",
+	})
+	require.NoError(t, err)
+	assert.LessOrEqual(t, EstimateTokens(inner.lastExplain.Output), 10)
+	assert.True(t, strings.HasSuffix(inner.lastExplain.Output, "boom"))
+}
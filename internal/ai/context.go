@@ -0,0 +1,75 @@
+package ai
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// SummarizeWorkflow describes an existing workflow for the prompt: its
+// title, ID, tags, placeholders and steps, with secrets redacted. Summaries
+// show the provider the team's conventions without sending whole files.
+func SummarizeWorkflow(wf *workflows.Workflow) string {
+	var b strings.Builder
+
+	b.WriteString(Redact(wf.Title))
+	if wf.ID != "" {
+		fmt.Fprintf(&b, " (id: %s)", wf.ID)
+	}
+	b.WriteString("\n")
+	if wf.Description != "" {
+		fmt.Fprintf(&b, "  description: %s\n", Redact(firstLine(wf.Description)))
+	}
+	if len(wf.Tags) > 0 {
+		fmt.Fprintf(&b, "  tags: %s\n", strings.Join(wf.Tags, ", "))
+	}
+	if len(wf.Placeholders) > 0 {
+		names := make([]string, 0, len(wf.Placeholders))
+		for name := range wf.Placeholders {
+			names = append(names, "<"+name+">")
+		}
+		sort.Strings(names)
+		fmt.Fprintf(&b, "  placeholders: %s\n", strings.Join(names, ", "))
+	}
+	b.WriteString("  steps:\n")
+	for i, step := range wf.Steps {
+		command := step.Command
+		if step.Script != "" {
+			command = "script " + step.Script
+		}
+		if step.Name != "" {
+			fmt.Fprintf(&b, "    %d. %s: %s\n", i+1, Redact(step.Name), Redact(firstLine(command)))
+		} else {
+			fmt.Fprintf(&b, "    %d. %s\n", i+1, Redact(firstLine(command)))
+		}
+	}
+	if wf.Next != "" {
+		fmt.Fprintf(&b, "  next: %s\n", wf.Next)
+	}
+
+	return b.String()
+}
+
+// packWorkflows returns the leading workflows whose summaries fit in
+// maxTokens.
+func packWorkflows(wfs []*workflows.Workflow, maxTokens int) []*workflows.Workflow {
+	used := 0
+	for i, wf := range wfs {
+		used += EstimateTokens(SummarizeWorkflow(wf))
+		if used > maxTokens {
+			return wfs[:i]
+		}
+	}
+	return wfs
+}
+
+// firstLine returns the first line of s, marking any dropped lines.
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i] + " ..."
+	}
+	return s
+}
@@ -0,0 +1,58 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+func TestSummarizeWorkflow(t *testing.T) {
+	wf := &workflows.Workflow{
+		ID:           "01HDEPLOY",
+		Title:        "Deploy API",
+		Description:  "Roll out the API\nSee the runbook for details.",
+		Tags:         []string{"deploy", "k8s"},
+		Placeholders: map[string]workflows.Placeholder{"namespace": {}, "image": {}},
+		Steps: []workflows.Step{
+			{Name: "login", Command: "mysql --password=hunter2secretpassword -h db.example.com"},
+			{Name: "apply", Command: "kubectl -n <namespace> set image deploy/api api=<image>"},
+			{Script: "verify.sh"},
+		},
+		Next: "smoke-test",
+	}
+
+	got := SummarizeWorkflow(wf)
+	assert.Contains(t, got, "Deploy API (id: 01HDEPLOY)\n")
+	assert.Contains(t, got, "  description: Roll out the API ...\n")
+	assert.Contains(t, got, "  tags: deploy, k8s\n")
+	assert.Contains(t, got, "  placeholders: <image>, <namespace>\n")
+	assert.Contains(t, got, "    2. apply: kubectl -n <namespace> set image deploy/api api=<image>\n")
+	assert.Contains(t, got, "    3. script verify.sh\n")
+	assert.Contains(t, got, "  next: smoke-test\n")
+	assert.NotContains(t, got, "hunter2secretpassword")
+}
+
+func TestWithBudget_PacksExistingWorkflows(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	existing := make([]*workflows.Workflow, 5)
+	for i := range existing {
+		existing[i] = &workflows.Workflow{Title: "Deploy", Steps: []workflows.Step{{Command: strings.Repeat("x", 40)}}}
+	}
+	each := EstimateTokens(SummarizeWorkflow(existing[0]))
+
+	inner := &usageProvider{}
+	p := WithBudget(inner, EstimateTokens("deploy")+2*each, TruncateTail)
+
+	_, err := p.GenerateWorkflow(context.Background(), GenerateRequest{
+		Prompt:  "deploy",
+		Context: &GenerateContext{ExistingWorkflows: existing},
+	})
+	require.NoError(t, err)
+	assert.Len(t, inner.lastGenerate.Context.ExistingWorkflows, 2)
+}
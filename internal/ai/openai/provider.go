@@ -9,9 +9,13 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/chazuruo/svf/internal/ai"
+	svferrors "github.com/chazuruo/svf/internal/errors"
 	"github.com/chazuruo/svf/internal/workflows"
 )
 
@@ -19,6 +23,9 @@ import (
 type Provider struct {
 	config *ai.Config
 	client *http.Client
+
+	mu        sync.Mutex
+	lastUsage *ai.Usage
 }
 
 // NewProvider creates a new OpenAI-compatible provider.
@@ -64,6 +71,31 @@ The workflow should be in YAML format with the following structure:
   - confirm: Whether to prompt before running (optional)`
 
 	userPrompt := fmt.Sprintf("Generate a workflow for: %s", req.Prompt)
+	if req.Prompt == "" && req.Context != nil && len(req.Context.History) > 0 {
+		userPrompt = "Generate a workflow from the shell commands below."
+	}
+	if req.Previous != nil {
+		// Refinement: send the current workflow along with the instructions
+		current, err := workflows.MarshalWorkflow(req.Previous)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal workflow: %w", err)
+		}
+		userPrompt = fmt.Sprintf("Revise the following workflow: %s\n\nReturn the complete revised workflow.\n\nCurrent workflow:\n```yaml\n%s```", req.Prompt, current)
+	}
+	if req.Target != nil {
+		// Steps for an existing workflow: send it so the new steps fit in
+		current, err := workflows.MarshalWorkflow(req.Target)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal workflow: %w", err)
+		}
+		where := "at the end"
+		if req.InsertAt < len(req.Target.Steps) {
+			where = fmt.Sprintf("before step %d (%s)", req.InsertAt+1, req.Target.Steps[req.InsertAt].Name)
+		}
+		userPrompt = fmt.Sprintf("Generate the step(s) to add to the workflow below for: %s\n\nThey go %s. "+
+			"Return a workflow containing only the new steps, reusing the workflow's <placeholders> where they apply.\n\nWorkflow:\n```yaml\n%s```",
+			req.Prompt, where, current)
+	}
 	if req.Context != nil {
 		userPrompt += "\n\nContext:"
 		if req.Context.CurrentDirectory != "" {
@@ -75,6 +107,20 @@ The workflow should be in YAML format with the following structure:
 		if req.Context.OS != "" {
 			userPrompt += fmt.Sprintf("\n- OS: %s", req.Context.OS)
 		}
+		if len(req.Context.History) > 0 {
+			userPrompt += "\n\nShell commands I ran (clean these up into reusable steps, " +
+				"dropping mistakes and replacing environment-specific values with <placeholders>):"
+			for _, c := range req.Context.History {
+				userPrompt += "\n- " + c
+			}
+		}
+		if len(req.Context.ExistingWorkflows) > 0 {
+			userPrompt += "\n\nExisting workflows from my team. Follow their naming, tags and " +
+				"<placeholder> conventions, and chain to one with next: <id> instead of repeating its steps:"
+			for _, wf := range req.Context.ExistingWorkflows {
+				userPrompt += "\n\n- " + ai.SummarizeWorkflow(wf)
+			}
+		}
 	}
 
 	// Call the API
@@ -124,6 +170,10 @@ func (p *Provider) Explain(ctx context.Context, req ai.ExplainRequest) (string,
 		systemPrompt = "You are a command-line expert. Explain shell commands clearly, including what they do and any risks."
 		userPrompt = fmt.Sprintf("Explain this command: %s", req.Command)
 
+	case ai.ExplainFailure:
+		systemPrompt = "You are an on-call operations assistant. Diagnose failed shell commands: list the most likely causes first, then concrete next actions to verify and fix them."
+		userPrompt = fmt.Sprintf("This command failed with exit code %d:\n\n%s\n\nOutput:\n%s", req.ExitCode, req.Command, req.Output)
+
 	case ai.ExplainStep:
 		systemPrompt = "You are a command-line expert. Explain shell commands clearly."
 		step := req.Workflow.Steps[req.StepIndex]
@@ -167,9 +217,16 @@ type message struct {
 // chatResponse represents a chat API response.
 type chatResponse struct {
  Choices []choice `json:"choices"`
+ Usage   *usage    `json:"usage,omitempty"`
  Error   *apiError `json:"error,omitempty"`
 }
 
+// usage represents token usage reported by the API.
+type usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
 // choice represents a choice in the response.
 type choice struct {
 	Message message `json:"message"`
@@ -232,7 +289,15 @@ func (p *Provider) callAPI(ctx context.Context, systemPrompt, userPrompt string)
 
 	// Check status code
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+		statusErr := &ai.StatusError{
+			StatusCode: resp.StatusCode,
+			Body:       string(body),
+			RetryAfter: retryAfter(resp.Header.Get("Retry-After")),
+		}
+		if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+			return "", &svferrors.AuthError{Service: p.Name(), Err: statusErr}
+		}
+		return "", statusErr
 	}
 
 	// Parse response
@@ -250,7 +315,46 @@ func (p *Provider) callAPI(ctx context.Context, systemPrompt, userPrompt string)
 		return "", fmt.Errorf("no choices in response")
 	}
 
-	return chatResp.Choices[0].Message.Content, nil
+	content := chatResp.Choices[0].Message.Content
+	p.recordUsage(chatResp.Usage, systemPrompt+userPrompt, content)
+
+	return content, nil
+}
+
+// recordUsage stores the usage of the last request, estimating it when
+// the server doesn't report any.
+func (p *Provider) recordUsage(u *usage, prompt, completion string) {
+	provider := p.config.Provider
+	if provider == "" {
+		provider = p.Name()
+	}
+	last := &ai.Usage{
+		At:       time.Now(),
+		Provider: provider,
+		Model:    p.config.Model,
+	}
+	if u != nil {
+		last.PromptTokens = u.PromptTokens
+		last.CompletionTokens = u.CompletionTokens
+	} else {
+		last.PromptTokens = ai.EstimateTokens(prompt)
+		last.CompletionTokens = ai.EstimateTokens(completion)
+		last.Estimated = true
+	}
+
+	p.mu.Lock()
+	p.lastUsage = last
+	p.mu.Unlock()
+}
+
+// LastUsage returns the token usage of the most recent request.
+func (p *Provider) LastUsage() (ai.Usage, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.lastUsage == nil {
+		return ai.Usage{}, false
+	}
+	return *p.lastUsage, true
 }
 
 // extractYAML extracts YAML from a markdown code block.
@@ -283,6 +387,16 @@ func extractYAML(s string) string {
 	return result.String()
 }
 
+// retryAfter parses a Retry-After header given in seconds. HTTP dates are
+// ignored in favor of the retry policy's backoff.
+func retryAfter(header string) time.Duration {
+	secs, err := strconv.Atoi(strings.TrimSpace(header))
+	if err != nil || secs < 0 {
+		return 0
+	}
+	return time.Duration(secs) * time.Second
+}
+
 func init() {
 	// Register the provider
 	ai.RegisterProvider("openai", func(cfg *ai.Config) (ai.Provider, error) {
@@ -4,8 +4,9 @@ package ai
 import (
 	"context"
 	"fmt"
-	"regexp"
+	"os"
 
+	"github.com/chazuruo/svf/internal/redact"
 	"github.com/chazuruo/svf/internal/workflows"
 )
 
@@ -29,6 +30,17 @@ type GenerateRequest struct {
 	// Context provides additional context about the user's environment.
 	Context *GenerateContext
 
+	// Previous is a workflow to revise. When set, Prompt holds follow-up
+	// instructions for refining it rather than a fresh description.
+	Previous *workflows.Workflow
+
+	// Target is a workflow to add steps to. When set, only the new steps
+	// are generated, to go before step InsertAt (0-based; the number of
+	// steps appends them), and they are returned as a workflow holding
+	// just those steps.
+	Target   *workflows.Workflow
+	InsertAt int
+
 	// Options for generation.
 	Options GenerateOptions
 }
@@ -47,8 +59,14 @@ type GenerateContext struct {
 	// Architecture is the system architecture.
 	Architecture string
 
-	// ExistingWorkflows are workflows the user has for reference.
+	// ExistingWorkflows are the team's workflows whose conventions the
+	// generated one should follow, most relevant first. Providers send
+	// them as SummarizeWorkflow summaries.
 	ExistingWorkflows []*workflows.Workflow
+
+	// History contains (redacted) shell commands the user ran, to be
+	// turned into a cleaned, parameterized workflow.
+	History []string
 }
 
 // GenerateOptions controls generation behavior.
@@ -79,6 +97,12 @@ type ExplainRequest struct {
 
 	// DetailLevel controls explanation depth.
 	DetailLevel DetailLevel
+
+	// ExitCode is the exit code of the failed command (if Type is ExplainFailure).
+	ExitCode int
+
+	// Output is the (redacted) output of the failed command (if Type is ExplainFailure).
+	Output string
 }
 
 // ExplainType is what to explain.
@@ -93,6 +117,9 @@ const (
 
 	// ExplainStep explains a specific workflow step.
 	ExplainStep
+
+	// ExplainFailure diagnoses a failed command from its exit code and output.
+	ExplainFailure
 )
 
 // DetailLevel controls explanation depth.
@@ -128,6 +155,21 @@ type Config struct {
 
 	// MaxTokens is the maximum tokens to generate.
 	MaxTokens int
+
+	// MaxPromptTokens caps the estimated tokens sent per request (0 for no limit).
+	MaxPromptTokens int
+
+	// Truncation selects how input over MaxPromptTokens is shortened.
+	Truncation Truncation
+
+	// Retry controls retries of rate-limited and failing requests.
+	Retry RetryPolicy
+
+	// Fallbacks are providers to try, in order, when this one fails.
+	Fallbacks []*Config
+
+	// OnRetry, if set, is called before each retry and fallback.
+	OnRetry func(RetryStatus)
 }
 
 // DefaultConfig returns default configuration.
@@ -157,12 +199,40 @@ func NewProvider(cfg *Config) (Provider, error) {
 		cfg = DefaultConfig()
 	}
 
-	factory, ok := providers[cfg.Provider]
-	if !ok {
-		return nil, fmt.Errorf("unknown provider: %s", cfg.Provider)
+	chain := make([]Provider, 0, 1+len(cfg.Fallbacks))
+	for _, c := range append([]*Config{cfg}, cfg.Fallbacks...) {
+		factory, ok := providers[c.Provider]
+		if !ok {
+			return nil, fmt.Errorf("unknown provider: %s", c.Provider)
+		}
+		provider, err := factory(c)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, provider)
 	}
 
-	return factory(cfg)
+	provider := WithFailover(chain, cfg.Retry, cfg.OnRetry)
+	return WithBudget(provider, cfg.MaxPromptTokens, cfg.Truncation), nil
+}
+
+// NewFallback returns the configuration of a fallback provider, reading
+// its API key from apiKeyEnv. Empty fields keep DefaultConfig's values.
+func NewFallback(provider, model, baseURL, apiKeyEnv string) *Config {
+	cfg := DefaultConfig()
+	if provider != "" {
+		cfg.Provider = provider
+	}
+	if model != "" {
+		cfg.Model = model
+	}
+	if baseURL != "" {
+		cfg.BaseURL = baseURL
+	}
+	if apiKeyEnv != "" {
+		cfg.APIKey = os.Getenv(apiKeyEnv)
+	}
+	return cfg
 }
 
 // ExplainError is an error from the provider.
@@ -184,43 +254,8 @@ func (e *ExplainError) Unwrap() error {
 	return e.Cause
 }
 
-// Redact redacts sensitive information from prompts.
-// This provides automatic redaction using the same detection patterns as the TUI.
+// Redact redacts sensitive information from prompts using the basic
+// redaction patterns.
 func Redact(s string) string {
-	// Import redaction patterns from TUI package
-	// For now, we'll use a simple implementation that can be enhanced
-	if s == "" {
-		return s
-	}
-
-	// Use the detectSensitiveItems function from the TUI package
-	// Since we're in a different package, we'll re-implement the patterns here
-	patterns := []string{
-		// API Keys
-		`(?i)(api[_-]?key|apikey|key)[\"']?\s*[:=]\s*[\"']?[a-zA-Z0-9_\-]{20,}[\"']?`,
-		`(?i)sk-[a-zA-Z0-9]{20,}`,
-		`(?i)pk-[a-zA-Z0-9]{20,}`,
-		`(?i)AKIA[0-9A-Z]{16}`,
-		// Passwords
-		`(?i)(password|passwd|pass)[\"']?\s*[:=]\s*[\"']?[^\s\"']+[\"']?`,
-		// Tokens
-		`(?i)(token|access[_-]?token|refresh[_-]?token)[\"']?\s*[:=]\s*[\"']?[a-zA-Z0-9_\-\.~=]{20,}[\"']?`,
-		`(?i)gh[pousr]_[a-zA-Z0-9]{36,}`,
-		// Bearer tokens
-		`(?i)bearer\s+[a-zA-Z0-9_\-\.~=]+`,
-		// Secrets
-		`(?i)(secret|secret[_-]?key|secret[_-]?id)[\"']?\s*[:=]\s*[\"']?[a-zA-Z0-9_\-]{16,}[\"']?`,
-		// Email
-		`[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}`,
-		// Private keys
-		`-----BEGIN\s+(RSA\s+)?PRIVATE\s+KEY-----`,
-	}
-
-	result := s
-	for _, p := range patterns {
-		re := regexp.MustCompile(p)
-		result = re.ReplaceAllString(result, "<REDACTED>")
-	}
-
-	return result
+	return redact.String(s, redact.Basic)
 }
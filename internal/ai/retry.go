@@ -0,0 +1,201 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// StatusError is an HTTP error response from a provider's API.
+type StatusError struct {
+	StatusCode int
+	Body       string
+	// RetryAfter is the delay the server asked for, if any.
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("API error (status %d): %s", e.StatusCode, e.Body)
+}
+
+// Retryable reports whether a request that failed with err may succeed
+// if sent again: the provider was rate limited or had a server error.
+func Retryable(err error) bool {
+	var status *StatusError
+	if !errors.As(err, &status) {
+		return false
+	}
+	return status.StatusCode == http.StatusTooManyRequests || status.StatusCode >= 500
+}
+
+// RetryPolicy controls how often and how long a provider is retried.
+type RetryPolicy struct {
+	// MaxAttempts is the number of requests per provider, including the
+	// first (0 for DefaultRetryPolicy's).
+	MaxAttempts int
+	// BaseDelay is the wait before the second attempt; it doubles for
+	// each attempt after that.
+	BaseDelay time.Duration
+	// MaxDelay caps the wait, including one asked for by the server.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy tries each provider three times, waiting 2s and 4s.
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 3, BaseDelay: 2 * time.Second, MaxDelay: time.Minute}
+
+// delay returns the wait before attempt (2 or more) after err.
+func (p RetryPolicy) delay(attempt int, err error) time.Duration {
+	d := p.BaseDelay << (attempt - 2)
+	var status *StatusError
+	if errors.As(err, &status) && status.RetryAfter > d {
+		d = status.RetryAfter
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	return d
+}
+
+// RetryStatus reports that a request failed and is being retried, or
+// sent to a fallback provider.
+type RetryStatus struct {
+	// Provider is the provider about to be tried.
+	Provider string
+	// Attempt is the 1-based attempt about to be made on Provider.
+	Attempt     int
+	MaxAttempts int
+	// Wait is the delay before the attempt.
+	Wait time.Duration
+	// Err is why the previous attempt failed.
+	Err error
+	// Fallback is set when the previous provider gave up.
+	Fallback bool
+}
+
+// String describes the status for display, e.g. "status 429; attempt 2/3
+// in 2s".
+func (s RetryStatus) String() string {
+	reason := "request failed"
+	var status *StatusError
+	if errors.As(s.Err, &status) {
+		reason = fmt.Sprintf("status %d", status.StatusCode)
+	} else if s.Err != nil {
+		reason = s.Err.Error()
+	}
+	if s.Fallback {
+		return fmt.Sprintf("%s; falling back to %s", reason, s.Provider)
+	}
+	return fmt.Sprintf("%s; attempt %d/%d in %s", reason, s.Attempt, s.MaxAttempts, s.Wait)
+}
+
+// failoverProvider retries rate-limited and failing requests with
+// exponential backoff, then falls back to the next provider.
+type failoverProvider struct {
+	providers []Provider
+	policy    RetryPolicy
+	notify    func(RetryStatus)
+
+	// sleep waits for d or until ctx is done. Tests replace it.
+	sleep func(ctx context.Context, d time.Duration) error
+
+	// last is the provider that handled the latest request.
+	last Provider
+}
+
+// WithFailover wraps providers so a request is retried on the same
+// provider while it fails with a Retryable error, up to
+// policy.MaxAttempts, and is then sent to the next provider. Any other
+// error moves on to the next provider at once. notify, if set, is called
+// before every retry and fallback.
+func WithFailover(providers []Provider, policy RetryPolicy, notify func(RetryStatus)) Provider {
+	if policy.MaxAttempts <= 0 {
+		policy = DefaultRetryPolicy
+	}
+	return &failoverProvider{providers: providers, policy: policy, notify: notify, sleep: sleepContext, last: providers[0]}
+}
+
+// Name returns the name of the provider that handled the latest request,
+// initially the primary one.
+func (f *failoverProvider) Name() string {
+	return f.last.Name()
+}
+
+// GenerateWorkflow implements Provider.
+func (f *failoverProvider) GenerateWorkflow(ctx context.Context, req GenerateRequest) (*workflows.Workflow, error) {
+	var wf *workflows.Workflow
+	err := f.do(ctx, func(p Provider) error {
+		var err error
+		wf, err = p.GenerateWorkflow(ctx, req)
+		return err
+	})
+	return wf, err
+}
+
+// Explain implements Provider.
+func (f *failoverProvider) Explain(ctx context.Context, req ExplainRequest) (string, error) {
+	var text string
+	err := f.do(ctx, func(p Provider) error {
+		var err error
+		text, err = p.Explain(ctx, req)
+		return err
+	})
+	return text, err
+}
+
+// LastUsage implements UsageReporter when the latest provider does.
+func (f *failoverProvider) LastUsage() (Usage, bool) {
+	if r, ok := f.last.(UsageReporter); ok {
+		return r.LastUsage()
+	}
+	return Usage{}, false
+}
+
+// do runs call against each provider in turn until one succeeds.
+func (f *failoverProvider) do(ctx context.Context, call func(Provider) error) error {
+	var err error
+	for i, p := range f.providers {
+		if i > 0 {
+			f.report(RetryStatus{Provider: p.Name(), Attempt: 1, MaxAttempts: f.policy.MaxAttempts, Err: err, Fallback: true})
+		}
+		f.last = p
+		for attempt := 1; ; attempt++ {
+			if attempt > 1 {
+				wait := f.policy.delay(attempt, err)
+				f.report(RetryStatus{Provider: p.Name(), Attempt: attempt, MaxAttempts: f.policy.MaxAttempts, Wait: wait, Err: err})
+				if serr := f.sleep(ctx, wait); serr != nil {
+					return serr
+				}
+			}
+			err = call(p)
+			if err == nil || ctx.Err() != nil {
+				return err
+			}
+			if !Retryable(err) || attempt >= f.policy.MaxAttempts {
+				break
+			}
+		}
+	}
+	return err
+}
+
+func (f *failoverProvider) report(s RetryStatus) {
+	if f.notify != nil {
+		f.notify(s)
+	}
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
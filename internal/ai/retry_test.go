@@ -0,0 +1,134 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// flakyProvider fails with errs in turn, then succeeds.
+type flakyProvider struct {
+	name  string
+	errs  []error
+	calls int
+}
+
+func (p *flakyProvider) Name() string { return p.name }
+
+func (p *flakyProvider) GenerateWorkflow(ctx context.Context, req GenerateRequest) (*workflows.Workflow, error) {
+	p.calls++
+	if p.calls <= len(p.errs) {
+		return nil, p.errs[p.calls-1]
+	}
+	return &workflows.Workflow{Title: p.name}, nil
+}
+
+func (p *flakyProvider) Explain(ctx context.Context, req ExplainRequest) (string, error) {
+	return "", nil
+}
+
+// newTestFailover returns a failover provider that records its waits
+// instead of sleeping.
+func newTestFailover(providers []Provider, statuses *[]RetryStatus, waits *[]time.Duration) *failoverProvider {
+	f := WithFailover(providers, DefaultRetryPolicy, func(s RetryStatus) {
+		*statuses = append(*statuses, s)
+	}).(*failoverProvider)
+	f.sleep = func(_ context.Context, d time.Duration) error {
+		*waits = append(*waits, d)
+		return nil
+	}
+	return f
+}
+
+func TestWithFailover_RetriesRateLimit(t *testing.T) {
+	rateLimited := &StatusError{StatusCode: 429}
+	primary := &flakyProvider{name: "openai", errs: []error{rateLimited, rateLimited}}
+
+	var statuses []RetryStatus
+	var waits []time.Duration
+	f := newTestFailover([]Provider{primary}, &statuses, &waits)
+
+	wf, err := f.GenerateWorkflow(context.Background(), GenerateRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "openai", wf.Title)
+	assert.Equal(t, 3, primary.calls)
+	assert.Equal(t, []time.Duration{2 * time.Second, 4 * time.Second}, waits)
+	require.Len(t, statuses, 2)
+	assert.Equal(t, "status 429; attempt 3/3 in 4s", statuses[1].String())
+}
+
+func TestWithFailover_FallsBack(t *testing.T) {
+	tests := []struct {
+		name         string
+		errs         []error
+		primaryCalls int
+	}{
+		{"after retries", []error{&StatusError{StatusCode: 503}, &StatusError{StatusCode: 503}, &StatusError{StatusCode: 503}}, 3},
+		{"at once on other errors", []error{&StatusError{StatusCode: 401}}, 1},
+		{"at once on connection errors", []error{errors.New("connection refused")}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary := &flakyProvider{name: "openai", errs: tt.errs}
+			secondary := &flakyProvider{name: "ollama"}
+
+			var statuses []RetryStatus
+			var waits []time.Duration
+			f := newTestFailover([]Provider{primary, secondary}, &statuses, &waits)
+
+			wf, err := f.GenerateWorkflow(context.Background(), GenerateRequest{})
+			require.NoError(t, err)
+			assert.Equal(t, "ollama", wf.Title)
+			assert.Equal(t, "ollama", f.Name())
+			assert.Equal(t, tt.primaryCalls, primary.calls)
+			last := statuses[len(statuses)-1]
+			assert.True(t, last.Fallback)
+			assert.Equal(t, "ollama", last.Provider)
+		})
+	}
+}
+
+func TestWithFailover_AllFail(t *testing.T) {
+	primary := &flakyProvider{name: "openai", errs: []error{&StatusError{StatusCode: 401}}}
+	secondary := &flakyProvider{name: "ollama", errs: []error{errors.New("connection refused")}}
+
+	var statuses []RetryStatus
+	var waits []time.Duration
+	f := newTestFailover([]Provider{primary, secondary}, &statuses, &waits)
+
+	_, err := f.GenerateWorkflow(context.Background(), GenerateRequest{})
+	assert.EqualError(t, err, "connection refused")
+}
+
+func TestWithFailover_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	primary := &flakyProvider{name: "openai", errs: []error{&StatusError{StatusCode: 429}}}
+	f := WithFailover([]Provider{primary}, DefaultRetryPolicy, func(RetryStatus) { cancel() })
+
+	_, err := f.GenerateWorkflow(ctx, GenerateRequest{})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, primary.calls)
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 5, BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+
+	assert.Equal(t, time.Second, p.delay(2, nil))
+	assert.Equal(t, 4*time.Second, p.delay(4, nil))
+	assert.Equal(t, 10*time.Second, p.delay(6, nil))
+	assert.Equal(t, 7*time.Second, p.delay(2, &StatusError{StatusCode: 429, RetryAfter: 7 * time.Second}))
+	assert.Equal(t, 10*time.Second, p.delay(2, &StatusError{StatusCode: 429, RetryAfter: time.Hour}))
+}
+
+func TestRetryable(t *testing.T) {
+	assert.True(t, Retryable(&StatusError{StatusCode: 429}))
+	assert.True(t, Retryable(&StatusError{StatusCode: 502}))
+	assert.False(t, Retryable(&StatusError{StatusCode: 400}))
+	assert.False(t, Retryable(errors.New("boom")))
+}
@@ -0,0 +1,400 @@
+// Package api exposes core svf operations as a JSON HTTP API for editor
+// extensions and chat bots.
+//
+// Every request must carry the server's token as "Authorization: Bearer
+// <token>". Endpoints:
+//
+//	GET  /v1/workflows            list indexed workflows
+//	GET  /v1/search?q=&tag=&regex search with the svf search query syntax
+//	GET  /v1/workflows/{ref}      load a workflow by ID, alias, slug or path
+//	POST /v1/run                  run a workflow non-interactively
+//	POST /v1/lint                 lint one workflow, or all of them
+//
+// Errors are returned as {"error": "..."} with a matching status code.
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/index"
+	"github.com/chazuruo/svf/internal/lint"
+	"github.com/chazuruo/svf/internal/placeholders"
+	"github.com/chazuruo/svf/internal/redact"
+	runnerpkg "github.com/chazuruo/svf/internal/runner"
+	"github.com/chazuruo/svf/internal/runpath"
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/chazuruo/svf/internal/workflows/store"
+	"gopkg.in/yaml.v3"
+)
+
+// Options configures a Server.
+type Options struct {
+	// Token authenticates requests. Required.
+	Token string
+
+	// CheckView, if set, is called before a workflow is returned and can
+	// refuse it, e.g. to record access to sensitive workflows.
+	CheckView func(wf *workflows.Workflow) error
+
+	// RecordRun, if set, is called after a workflow runs, e.g. to save the
+	// run to history.
+	RecordRun func(wf *workflows.Workflow, results []runnerpkg.StepResult, started time.Time, success bool)
+}
+
+// Server serves the API for one repository.
+type Server struct {
+	config  *config.Config
+	store   store.Store
+	builder *index.Builder
+	opts    Options
+}
+
+// New creates an API server. It returns an error if opts has no token.
+func New(cfg *config.Config, str store.Store, opts Options) (*Server, error) {
+	if opts.Token == "" {
+		return nil, errors.New("an API token is required")
+	}
+	return &Server{
+		config:  cfg,
+		store:   str,
+		builder: index.NewBuilder(cfg.Repo.Path, cfg),
+		opts:    opts,
+	}, nil
+}
+
+// Handler returns the HTTP handler serving the API.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/workflows", s.handleList)
+	mux.HandleFunc("GET /v1/search", s.handleSearch)
+	mux.HandleFunc("GET /v1/workflows/{ref...}", s.handleView)
+	mux.HandleFunc("POST /v1/run", s.handleRun)
+	mux.HandleFunc("POST /v1/lint", s.handleLint)
+	return s.authenticate(mux)
+}
+
+// authenticate rejects requests without the server's bearer token.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	want := []byte("Bearer " + s.opts.Token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got := []byte(r.Header.Get("Authorization"))
+		if subtle.ConstantTimeCompare(got, want) != 1 {
+			writeError(w, http.StatusUnauthorized, errors.New("missing or invalid token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	idx, err := s.builder.LoadFresh()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, idx.Workflows)
+}
+
+// SearchResult is a search hit returned by /v1/search.
+type SearchResult struct {
+	index.WorkflowEntry
+	Score   float64  `json:"score"`
+	Matches []string `json:"matches"`
+}
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	idx, err := s.builder.LoadFresh()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	q := r.URL.Query()
+	results, err := idx.Query(index.SearchOptions{
+		Query: q.Get("q"),
+		Tags:  q["tag"],
+		Regex: q.Get("regex") == "true",
+	})
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid query: %w", err))
+		return
+	}
+
+	out := make([]SearchResult, len(results))
+	for i, res := range results {
+		out[i] = SearchResult{WorkflowEntry: res.Entry, Score: res.Score, Matches: res.Matches}
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// WorkflowResponse is returned by /v1/workflows/{ref}.
+type WorkflowResponse struct {
+	Path     string         `json:"path"`     // Repo-relative path to workflow.yaml
+	Workflow map[string]any `json:"workflow"` // The workflow with its YAML field names
+}
+
+func (s *Server) handleView(w http.ResponseWriter, r *http.Request) {
+	ref, wf, err := s.load(r.Context(), r.PathValue("ref"))
+	if err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+	if s.opts.CheckView != nil {
+		if err := s.opts.CheckView(wf); err != nil {
+			writeError(w, http.StatusForbidden, err)
+			return
+		}
+	}
+	doc, err := workflowDocument(wf)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, WorkflowResponse{Path: s.relPath(ref.Path), Workflow: doc})
+}
+
+// workflowDocument converts a workflow to a generic map through YAML, so
+// JSON clients see the same field names as workflow files.
+func workflowDocument(wf *workflows.Workflow) (map[string]any, error) {
+	data, err := workflows.MarshalWorkflow(wf)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// RunRequest is the body of /v1/run.
+type RunRequest struct {
+	Workflow         string            `json:"workflow"` // ID, alias, slug or path
+	Params           map[string]string `json:"params"`
+	DryRun           bool              `json:"dry_run"`
+	ConfirmDangerous bool              `json:"confirm_dangerous"` // Required to run dangerous commands
+}
+
+// StepRun is the outcome of one step in a RunResponse.
+type StepRun struct {
+	Step       int    `json:"step"` // 1-based
+	Name       string `json:"name,omitempty"`
+	Command    string `json:"command"`
+	Ran        bool   `json:"ran"`
+	Skipped    string `json:"skipped,omitempty"` // Why the step didn't run, e.g. "platform"
+	Success    bool   `json:"success"`
+	ExitCode   int    `json:"exit_code"`
+	Output     string `json:"output,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// RunResponse is returned by /v1/run.
+type RunResponse struct {
+	Success    bool      `json:"success"`
+	ExitCode   int       `json:"exit_code"`
+	FailedStep int       `json:"failed_step,omitempty"` // 1-based, 0 if none failed
+	Steps      []StepRun `json:"steps"`
+}
+
+func (s *Server) handleRun(w http.ResponseWriter, r *http.Request) {
+	var req RunRequest
+	if err := decodeBody(w, r, &req); err != nil {
+		writeError(w, bodyStatus(err), err)
+		return
+	}
+
+	ref, wf, err := s.load(r.Context(), req.Workflow)
+	if err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+
+	resp, err := s.run(r.Context(), wf, ref, req)
+	if err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// run executes a workflow's steps in order, like 'svf run --yes', through
+// the shared run path.
+func (s *Server) run(ctx context.Context, wf *workflows.Workflow, ref store.WorkflowRef, req RunRequest) (RunResponse, error) {
+	res, err := runpath.Run(ctx, s.config, wf, ref, runpath.RunOptions{
+		Options:          runpath.Options{DryRun: req.DryRun, Out: io.Discard},
+		Params:           req.Params,
+		ConfirmDangerous: req.ConfirmDangerous,
+		Record:           s.opts.RecordRun,
+	})
+	if errors.Is(err, runnerpkg.ErrDangerous) {
+		return RunResponse{}, fmt.Errorf("%w; set confirm_dangerous to run it", err)
+	}
+	if err != nil {
+		return RunResponse{}, err
+	}
+
+	resp := RunResponse{
+		Success:    res.Success,
+		ExitCode:   res.ExitCode,
+		FailedStep: res.FailedStep,
+		Steps:      make([]StepRun, len(res.Steps)),
+	}
+	for i, step := range res.Steps {
+		result := res.Results[i]
+		run := StepRun{Step: i + 1, Name: step.Name, Command: step.Command, Skipped: result.SkipReason}
+		if step.Error != nil {
+			run.Error = step.Error.Error()
+		}
+		if step.Ran {
+			run.Ran = true
+			run.Success = result.Success
+			run.ExitCode = result.ExitCode
+			run.Output = redact.String(result.Output, s.config.Runner.RedactLogs)
+			run.DurationMS = result.Duration.Milliseconds()
+			if result.Error != nil {
+				run.Error = redact.String(result.Error.Error(), s.config.Runner.RedactLogs)
+			}
+		}
+		resp.Steps[i] = run
+	}
+
+	return resp, nil
+}
+
+// LintRequest is the body of /v1/lint. An empty Workflow lints every
+// workflow in the repository.
+type LintRequest struct {
+	Workflow string `json:"workflow"`
+}
+
+// LintResult is the lint outcome for one workflow.
+type LintResult struct {
+	Path     string        `json:"path"`
+	Problems []lint.Problem `json:"problems"`
+}
+
+func (s *Server) handleLint(w http.ResponseWriter, r *http.Request) {
+	var req LintRequest
+	if r.ContentLength != 0 {
+		if err := decodeBody(w, r, &req); err != nil {
+			writeError(w, bodyStatus(err), err)
+			return
+		}
+	}
+
+	var refs []store.WorkflowRef
+	if req.Workflow != "" {
+		ref, err := store.Resolve(r.Context(), s.store, s.config.Repo.Path, s.config.Workflows.Root, req.Workflow)
+		if err != nil {
+			writeError(w, statusFor(err), err)
+			return
+		}
+		refs = []store.WorkflowRef{ref}
+	} else {
+		var err error
+		refs, err = s.store.List(r.Context(), store.Filter{})
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	results := make([]LintResult, 0, len(refs))
+	for _, ref := range refs {
+		result := LintResult{Path: s.relPath(ref.Path), Problems: []lint.Problem{}}
+		wf, err := s.store.Load(r.Context(), ref)
+		if err != nil {
+			result.Problems = append(result.Problems, lint.Problem{Severity: lint.SeverityError, Message: err.Error()})
+		} else if problems := lint.Check(wf); len(problems) > 0 {
+			result.Problems = problems
+		}
+		results = append(results, result)
+	}
+	writeJSON(w, http.StatusOK, results)
+}
+
+// load resolves and loads a workflow.
+func (s *Server) load(ctx context.Context, query string) (store.WorkflowRef, *workflows.Workflow, error) {
+	ref, err := store.Resolve(ctx, s.store, s.config.Repo.Path, s.config.Workflows.Root, query)
+	if err != nil {
+		return store.WorkflowRef{}, nil, err
+	}
+	wf, err := s.store.Load(ctx, ref)
+	if err != nil {
+		return store.WorkflowRef{}, nil, fmt.Errorf("failed to load workflow: %w", err)
+	}
+	return ref, wf, nil
+}
+
+// relPath returns path relative to the repo.
+func (s *Server) relPath(path string) string {
+	if rel, err := filepath.Rel(s.config.Repo.Path, path); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return path
+}
+
+// statusFor maps an error to an HTTP status code.
+func statusFor(err error) int {
+	var ambiguous *store.AmbiguousError
+	var missing *placeholders.MissingError
+	var invalid *runnerpkg.ParamError
+	var refused *runpath.RefusedError
+	var running *runpath.RunningError
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		return http.StatusNotFound
+	case errors.As(err, &refused):
+		return http.StatusForbidden
+	case errors.As(err, &ambiguous), errors.As(err, &running), errors.Is(err, runnerpkg.ErrDangerous):
+		return http.StatusConflict
+	case errors.As(err, &missing), errors.As(err, &invalid):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// maxBodyBytes bounds request bodies, which only ever hold a workflow
+// reference and its parameters.
+const maxBodyBytes = 1 << 20
+
+// decodeBody decodes the JSON request body into v, reading at most
+// maxBodyBytes of it.
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) error {
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes)).Decode(v); err != nil {
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+	return nil
+}
+
+// bodyStatus returns the status code for an error from decodeBody.
+func bodyStatus(err error) int {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
+
+// writeJSON writes v as the JSON response body.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an {"error": ...} response.
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,314 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/lock"
+	runnerpkg "github.com/chazuruo/svf/internal/runner"
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/chazuruo/svf/internal/workflows/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testToken = "secret-token"
+
+// setupServer creates a repo with a greeting workflow and an API server
+// for it. recorded counts the runs passed to RecordRun.
+func setupServer(t *testing.T) (ts *httptest.Server, recorded *int) {
+	t.Helper()
+	dir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.Repo.Path = dir
+	cfg.Identity.Path = "team/alice"
+
+	str, err := store.New(gitrepo.New(dir), cfg)
+	require.NoError(t, err)
+	_, err = str.Save(context.Background(), &workflows.Workflow{
+		SchemaVersion: workflows.SchemaVersion,
+		ID:            "wf_greet",
+		Title:         "Greet",
+		Aliases:       []string{"hello"},
+		Placeholders: map[string]workflows.Placeholder{
+			"name": {Default: "world", Validate: "^[a-z]+$"},
+		},
+		Steps: []workflows.Step{
+			{Name: "Say hello", Command: "echo hello <name>"},
+			{Name: "Fail", Command: "exit 3"},
+			{Name: "Never", Command: "echo unreachable"},
+		},
+	}, store.SaveOptions{})
+	require.NoError(t, err)
+
+	recorded = new(int)
+	srv, err := New(cfg, str, Options{
+		Token: testToken,
+		RecordRun: func(*workflows.Workflow, []runnerpkg.StepResult, time.Time, bool) {
+			*recorded++
+		},
+	})
+	require.NoError(t, err)
+
+	ts = httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+	return ts, recorded
+}
+
+// call sends an authenticated request and decodes the JSON response.
+func call(t *testing.T, ts *httptest.Server, method, path string, body, out any) int {
+	t.Helper()
+	var buf bytes.Buffer
+	if body != nil {
+		require.NoError(t, json.NewEncoder(&buf).Encode(body))
+	}
+	req, err := http.NewRequest(method, ts.URL+path, &buf)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	if out != nil {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+	}
+	return resp.StatusCode
+}
+
+func TestNew_RequiresToken(t *testing.T) {
+	_, err := New(config.DefaultConfig(), nil, Options{})
+	assert.Error(t, err)
+}
+
+func TestServer_Auth(t *testing.T) {
+	ts, _ := setupServer(t)
+
+	resp, err := http.Get(ts.URL + "/v1/workflows")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestServer_ListSearchView(t *testing.T) {
+	ts, _ := setupServer(t)
+
+	var list []map[string]any
+	assert.Equal(t, http.StatusOK, call(t, ts, "GET", "/v1/workflows", nil, &list))
+	require.Len(t, list, 1)
+	assert.Equal(t, "Greet", list[0]["title"])
+
+	var results []SearchResult
+	assert.Equal(t, http.StatusOK, call(t, ts, "GET", "/v1/search?q=greet", nil, &results))
+	require.Len(t, results, 1)
+	assert.Equal(t, "wf_greet", results[0].ID)
+
+	var errResp map[string]string
+	assert.Equal(t, http.StatusBadRequest, call(t, ts, "GET", "/v1/search?q=%22open", nil, &errResp))
+	assert.Contains(t, errResp["error"], "invalid query")
+
+	var view WorkflowResponse
+	assert.Equal(t, http.StatusOK, call(t, ts, "GET", "/v1/workflows/hello", nil, &view))
+	assert.Equal(t, "workflows/team/alice/greet/workflow.yaml", view.Path)
+	assert.Equal(t, "Greet", view.Workflow["title"])
+
+	assert.Equal(t, http.StatusNotFound, call(t, ts, "GET", "/v1/workflows/nope", nil, nil))
+}
+
+func TestServer_Run(t *testing.T) {
+	ts, recorded := setupServer(t)
+
+	var resp RunResponse
+	status := call(t, ts, "POST", "/v1/run", RunRequest{Workflow: "hello", Params: map[string]string{"name": "svf"}}, &resp)
+	require.Equal(t, http.StatusOK, status)
+	assert.False(t, resp.Success)
+	assert.Equal(t, 3, resp.ExitCode)
+	assert.Equal(t, 2, resp.FailedStep)
+	require.Len(t, resp.Steps, 3)
+	assert.Equal(t, "hello svf\n", resp.Steps[0].Output)
+	assert.True(t, resp.Steps[1].Ran)
+	assert.False(t, resp.Steps[2].Ran)
+	assert.Equal(t, 1, *recorded)
+
+	// Dry runs resolve commands without running or recording them
+	resp = RunResponse{}
+	require.Equal(t, http.StatusOK, call(t, ts, "POST", "/v1/run", RunRequest{Workflow: "hello", DryRun: true}, &resp))
+	assert.Equal(t, "echo hello world", resp.Steps[0].Command)
+	assert.False(t, resp.Steps[0].Ran)
+	assert.Equal(t, 1, *recorded)
+
+	// Parameters are validated against their placeholders
+	status = call(t, ts, "POST", "/v1/run", RunRequest{Workflow: "hello", Params: map[string]string{"name": "Bad Name"}}, nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestServer_RunBodyTooLarge(t *testing.T) {
+	ts, recorded := setupServer(t)
+
+	params := map[string]string{"name": strings.Repeat("a", maxBodyBytes)}
+	status := call(t, ts, "POST", "/v1/run", RunRequest{Workflow: "hello", Params: params}, nil)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, status)
+	assert.Equal(t, 0, *recorded)
+}
+
+func TestServer_ReadOnlyIndex(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.Repo.Path = dir
+	cfg.Identity.Path = "team/alice"
+	str, err := store.New(gitrepo.New(dir), cfg)
+	require.NoError(t, err)
+	_, err = str.Save(context.Background(), &workflows.Workflow{
+		SchemaVersion: workflows.SchemaVersion,
+		Title:         "Greet",
+		Steps:         []workflows.Step{{Command: "echo hello"}},
+	}, store.SaveOptions{})
+	require.NoError(t, err)
+	require.NoError(t, os.RemoveAll(filepath.Join(dir, cfg.Workflows.IndexPath)))
+
+	cfg.ReadOnly = true
+	srv, err := New(cfg, str, Options{Token: testToken})
+	require.NoError(t, err)
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+
+	var list []map[string]any
+	require.Equal(t, http.StatusOK, call(t, ts, "GET", "/v1/workflows", nil, &list))
+	assert.Len(t, list, 1)
+	assert.NoFileExists(t, filepath.Join(dir, cfg.Workflows.IndexPath))
+}
+
+func TestServer_Lint(t *testing.T) {
+	ts, _ := setupServer(t)
+
+	var results []LintResult
+	require.Equal(t, http.StatusOK, call(t, ts, "POST", "/v1/lint", LintRequest{}, &results))
+	require.Len(t, results, 1)
+	assert.Empty(t, results[0].Problems)
+}
+
+func TestServer_RunCapture(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.Repo.Path = dir
+	cfg.Identity.Path = "team/alice"
+
+	str, err := store.New(gitrepo.New(dir), cfg)
+	require.NoError(t, err)
+	_, err = str.Save(context.Background(), &workflows.Workflow{
+		SchemaVersion: workflows.SchemaVersion,
+		Title:         "Release",
+		Steps: []workflows.Step{
+			{Command: `echo '{"tag": "v1.4.2"}'`, Capture: map[string]workflows.Extractor{"tag": {JSONPath: ".tag"}}},
+			{Command: "echo publishing <tag>"},
+		},
+	}, store.SaveOptions{})
+	require.NoError(t, err)
+
+	srv, err := New(cfg, str, Options{Token: testToken})
+	require.NoError(t, err)
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+
+	// Lint doesn't flag captured placeholders as undeclared
+	var lint []LintResult
+	require.Equal(t, http.StatusOK, call(t, ts, "POST", "/v1/lint", LintRequest{}, &lint))
+	require.Len(t, lint, 1)
+	assert.Empty(t, lint[0].Problems)
+
+	var resp RunResponse
+	require.Equal(t, http.StatusOK, call(t, ts, "POST", "/v1/run", RunRequest{Workflow: "release", DryRun: true}, &resp))
+	assert.Equal(t, "echo publishing <tag>", resp.Steps[1].Command)
+
+	resp = RunResponse{}
+	require.Equal(t, http.StatusOK, call(t, ts, "POST", "/v1/run", RunRequest{Workflow: "release"}, &resp))
+	assert.True(t, resp.Success)
+	assert.Equal(t, "echo publishing v1.4.2", resp.Steps[1].Command)
+	assert.Equal(t, "publishing v1.4.2\n", resp.Steps[1].Output)
+}
+
+func TestServer_RunExclusive(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.Repo.Path = dir
+	cfg.Identity.Path = "team/alice"
+
+	str, err := store.New(gitrepo.New(dir), cfg)
+	require.NoError(t, err)
+	release := filepath.Join(dir, "release")
+	_, err = str.Save(context.Background(), &workflows.Workflow{
+		SchemaVersion: workflows.SchemaVersion,
+		ID:            "wf_failover",
+		Title:         "Fail over",
+		Exclusive:     true,
+		Steps:         []workflows.Step{{Command: fmt.Sprintf("while [ ! -e %q ]; do sleep 0.05; done", release)}},
+	}, store.SaveOptions{})
+	require.NoError(t, err)
+
+	srv, err := New(cfg, str, Options{Token: testToken})
+	require.NoError(t, err)
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+
+	first := make(chan int, 1)
+	go func() {
+		first <- call(t, ts, "POST", "/v1/run", RunRequest{Workflow: "wf_failover"}, nil)
+	}()
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(lock.NamedPath(dir, "wf_failover"))
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// A concurrent run is refused while the first one holds the lock
+	assert.Equal(t, http.StatusConflict, call(t, ts, "POST", "/v1/run", RunRequest{Workflow: "wf_failover"}, nil))
+
+	require.NoError(t, os.WriteFile(release, nil, 0644))
+	assert.Equal(t, http.StatusOK, <-first)
+
+	// The lock is released once the run is over
+	assert.Equal(t, http.StatusOK, call(t, ts, "POST", "/v1/run", RunRequest{Workflow: "wf_failover"}, nil))
+}
+
+func TestServer_RunCleanup(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.Repo.Path = dir
+	cfg.Identity.Path = "team/alice"
+
+	str, err := store.New(gitrepo.New(dir), cfg)
+	require.NoError(t, err)
+	_, err = str.Save(context.Background(), &workflows.Workflow{
+		SchemaVersion: workflows.SchemaVersion,
+		Title:         "Drain",
+		Placeholders:  map[string]workflows.Placeholder{"node": {Default: "node-1"}},
+		Steps: []workflows.Step{
+			{Command: "echo cordoned <node>"},
+			{Command: "exit 3"},
+		},
+		Cleanup: []workflows.Step{{Command: "echo <node> > uncordoned"}},
+	}, store.SaveOptions{})
+	require.NoError(t, err)
+
+	srv, err := New(cfg, str, Options{Token: testToken})
+	require.NoError(t, err)
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+
+	var resp RunResponse
+	require.Equal(t, http.StatusOK, call(t, ts, "POST", "/v1/run", RunRequest{Workflow: "drain"}, &resp))
+	assert.False(t, resp.Success)
+
+	// A failed run is cleaned up
+	data, err := os.ReadFile(filepath.Join(dir, "uncordoned"))
+	require.NoError(t, err, "cleanup step didn't run")
+	assert.Equal(t, "node-1\n", string(data))
+}
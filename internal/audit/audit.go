@@ -0,0 +1,283 @@
+// Package audit records access to sensitive workflows for compliance
+// reviews.
+//
+// Viewing or running a workflow tagged "sensitive" appends one JSON line
+// to .svf/audit/<YYYY-MM>.jsonl in the repository. Like usage stats, the
+// files are marked merge=union so appends from different clones merge
+// without conflicts. When the repository can't be written to, events go
+// to the same files in a local directory instead.
+//
+// Every event is signed with an ed25519 key kept outside the repository,
+// one per user and machine, and the public key travels with the event. A
+// line edited without re-signing fails verification, and the key's
+// fingerprint shows which machine wrote it. The log is not tamper-proof:
+// anyone can sign a rewritten line with a key of their own, which only
+// shows as an unfamiliar fingerprint, and deleted lines leave no trace in
+// the log itself; the repository's git history is the record of those.
+package audit
+
+import (
+	"bufio"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// Dir is the repo-relative directory holding the audit log.
+const Dir = ".svf/audit"
+
+// attributesLine makes git merge audit files by taking lines from both sides.
+const attributesLine = ".svf/audit/*.jsonl merge=union"
+
+// SensitiveTag marks workflows whose access is audited.
+const SensitiveTag = "sensitive"
+
+// Actions recorded in the audit log.
+const (
+	ActionView = "view"
+	ActionRun  = "run"
+)
+
+// IsSensitive reports whether access to wf is audited.
+func IsSensitive(wf *workflows.Workflow) bool {
+	for _, tag := range wf.Tags {
+		if tag == SensitiveTag {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is one recorded access.
+type Event struct {
+	Nonce    string    `json:"n"`
+	At       time.Time `json:"at"`
+	Action   string    `json:"action"`
+	Workflow string    `json:"workflow"`
+	Title    string    `json:"title"`
+	Actor    string    `json:"actor"`
+	Host     string    `json:"host,omitempty"`
+
+	// Key is the base64 public key that signed the event, and Sig the
+	// base64 signature of the event without Sig.
+	Key string `json:"key"`
+	Sig string `json:"sig"`
+}
+
+// Entry is an event read back from a log.
+type Entry struct {
+	Event
+	// Verified is false if the signature doesn't match the event.
+	Verified bool
+	// Local is set for events from the local log rather than the repo.
+	Local bool
+}
+
+// Fingerprint returns a short identifier of the signing key.
+func (e Event) Fingerprint() string {
+	sum := sha256.Sum256([]byte(e.Key))
+	return hex.EncodeToString(sum[:4])
+}
+
+// payload returns the signed bytes of e.
+func (e Event) payload() ([]byte, error) {
+	e.Sig = ""
+	return json.Marshal(e)
+}
+
+// Sign sets e's key and signature.
+func (e *Event) Sign(key ed25519.PrivateKey) error {
+	e.Key = base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey))
+	data, err := e.payload()
+	if err != nil {
+		return err
+	}
+	e.Sig = base64.StdEncoding.EncodeToString(ed25519.Sign(key, data))
+	return nil
+}
+
+// Verify reports whether e's signature matches its contents.
+func (e Event) Verify() bool {
+	pub, err := base64.StdEncoding.DecodeString(e.Key)
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return false
+	}
+	sig, err := base64.StdEncoding.DecodeString(e.Sig)
+	if err != nil {
+		return false
+	}
+	data, err := e.payload()
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(pub, data, sig)
+}
+
+// LocalDir returns the directory of the local audit log, used when the
+// repository can't be written to.
+func LocalDir() (string, error) {
+	dir, err := config.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "audit"), nil
+}
+
+// KeyPath returns the path of the signing key.
+func KeyPath() (string, error) {
+	dir, err := config.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "audit.key"), nil
+}
+
+// LoadKey reads the signing key at path, creating one if it doesn't exist.
+func LoadKey(path string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		seed, err := base64.StdEncoding.DecodeString(string(data))
+		if err != nil || len(seed) != ed25519.SeedSize {
+			return nil, fmt.Errorf("invalid audit key %s", path)
+		}
+		return ed25519.NewKeyFromSeed(seed), nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read audit key: %w", err)
+	}
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate audit key: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create audit key directory: %w", err)
+	}
+	seed := base64.StdEncoding.EncodeToString(key.Seed())
+	if err := os.WriteFile(path, []byte(seed), 0600); err != nil {
+		return nil, fmt.Errorf("failed to write audit key: %w", err)
+	}
+	return key, nil
+}
+
+// Record signs e with key and appends it to the repo's audit log, or to
+// the log in localDir if the repo can't be written to. With an empty
+// repoPath it appends to the log in localDir only. It returns the file
+// written.
+func Record(repoPath, localDir string, key ed25519.PrivateKey, e Event) (string, error) {
+	e.Nonce = uuid.NewString()[:8]
+	e.At = e.At.UTC()
+	if err := e.Sign(key); err != nil {
+		return "", fmt.Errorf("failed to sign audit event: %w", err)
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal audit event: %w", err)
+	}
+	name := e.At.Format("2006-01") + ".jsonl"
+
+	var repoErr error
+	if repoPath != "" {
+		path := filepath.Join(repoPath, Dir, name)
+		repoErr = appendLine(path, line)
+		if repoErr == nil {
+			repoErr = gitrepo.EnsureAttribute(repoPath, attributesLine)
+			if repoErr == nil {
+				return path, nil
+			}
+		}
+	}
+	if localDir == "" {
+		if repoErr == nil {
+			repoErr = fmt.Errorf("no audit log to record to")
+		}
+		return "", repoErr
+	}
+
+	path := filepath.Join(localDir, name)
+	if err := appendLine(path, line); err != nil {
+		return "", errors.Join(repoErr, err)
+	}
+	return path, nil
+}
+
+// appendLine appends line to the file at path, creating it if needed.
+func appendLine(path string, line []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create audit directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// Load reads the events in the repo's audit log and, if localDir is set,
+// the local one, oldest first. Lines that don't parse are skipped; events
+// whose signature doesn't match are returned unverified.
+func Load(repoPath, localDir string) ([]Entry, error) {
+	entries, err := loadDir(filepath.Join(repoPath, Dir), false)
+	if err != nil {
+		return nil, err
+	}
+	if localDir != "" {
+		local, err := loadDir(localDir, true)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, local...)
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].At.Before(entries[j].At) })
+	return entries, nil
+}
+
+// loadDir reads the events in the audit files in dir.
+func loadDir(dir string, local bool) ([]Entry, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	var entries []Entry
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open audit log: %w", err)
+		}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var e Event
+			if err := json.Unmarshal(scanner.Bytes(), &e); err != nil || e.Action == "" {
+				continue
+			}
+			entries = append(entries, Entry{Event: e, Verified: e.Verify(), Local: local})
+		}
+		err = scanner.Err()
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+	}
+	return entries, nil
+}
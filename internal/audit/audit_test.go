@@ -0,0 +1,114 @@
+package audit
+
+import (
+	"crypto/ed25519"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+func testKey(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	key, err := LoadKey(filepath.Join(t.TempDir(), "audit.key"))
+	require.NoError(t, err)
+	return key
+}
+
+func TestIsSensitive(t *testing.T) {
+	assert.True(t, IsSensitive(&workflows.Workflow{Tags: []string{"db", "sensitive"}}))
+	assert.False(t, IsSensitive(&workflows.Workflow{Tags: []string{"db"}}))
+}
+
+func TestLoadKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "audit.key")
+
+	key, err := LoadKey(path)
+	require.NoError(t, err)
+	again, err := LoadKey(path)
+	require.NoError(t, err)
+	assert.True(t, key.Equal(again))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestRecordAndLoad(t *testing.T) {
+	repo := t.TempDir()
+	key := testKey(t)
+	at := time.Date(2026, 3, 9, 12, 0, 0, 0, time.UTC)
+
+	path, err := Record(repo, "", key, Event{At: at.Add(time.Hour), Action: ActionRun, Workflow: "wf_db", Title: "Fail over DB", Actor: "platform/alice"})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(repo, Dir, "2026-03.jsonl"), path)
+	_, err = Record(repo, "", key, Event{At: at, Action: ActionView, Workflow: "wf_db", Title: "Fail over DB", Actor: "platform/bob"})
+	require.NoError(t, err)
+
+	attrs, err := os.ReadFile(filepath.Join(repo, ".gitattributes"))
+	require.NoError(t, err)
+	assert.Contains(t, string(attrs), attributesLine)
+
+	entries, err := Load(repo, "")
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "platform/bob", entries[0].Actor)
+	assert.Equal(t, ActionRun, entries[1].Action)
+	assert.True(t, entries[0].Verified)
+	assert.True(t, entries[1].Verified)
+}
+
+func TestLoad_DetectsTampering(t *testing.T) {
+	repo := t.TempDir()
+	path, err := Record(repo, "", testKey(t), Event{At: time.Now(), Action: ActionRun, Workflow: "wf_db", Actor: "platform/alice"})
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	edited := strings.Replace(string(data), "platform/alice", "platform/mallory", 1)
+	require.NoError(t, os.WriteFile(path, []byte(edited), 0644))
+
+	entries, err := Load(repo, "")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.False(t, entries[0].Verified)
+}
+
+func TestRecord_FallsBackToLocalLog(t *testing.T) {
+	// A file where the repo should be can't be written to
+	repo := filepath.Join(t.TempDir(), "repo")
+	require.NoError(t, os.WriteFile(repo, nil, 0444))
+	local := t.TempDir()
+
+	path, err := Record(repo, local, testKey(t), Event{At: time.Now(), Action: ActionView, Workflow: "wf_db"})
+	require.NoError(t, err)
+	assert.Equal(t, local, filepath.Dir(path))
+
+	entries, err := Load(repo, local)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.True(t, entries[0].Local)
+	assert.True(t, entries[0].Verified)
+
+	_, err = Record(repo, "", testKey(t), Event{At: time.Now(), Action: ActionView})
+	assert.Error(t, err)
+}
+
+func TestRecord_LocalOnly(t *testing.T) {
+	local := t.TempDir()
+
+	path, err := Record("", local, testKey(t), Event{At: time.Now(), Action: ActionRun, Workflow: "wf_db"})
+	require.NoError(t, err)
+	assert.Equal(t, local, filepath.Dir(path))
+	_, err = os.Stat(Dir)
+	assert.True(t, os.IsNotExist(err), "nothing is written relative to the working directory")
+
+	_, err = Record("", "", testKey(t), Event{At: time.Now(), Action: ActionRun})
+	assert.Error(t, err)
+}
@@ -0,0 +1,184 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"github.com/spf13/cobra"
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/index"
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+// AliasOptions contains the options for the alias-id subcommands.
+type AliasOptions struct {
+	ConfigPath string
+	NoCommit   bool
+}
+
+// NewAliasCommand creates the alias-id command.
+func NewAliasCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "alias-id",
+		Short: "Manage short names for workflows",
+		Long: `Manage aliases: short, memorable names a workflow can be referenced by
+anywhere a workflow reference is accepted, e.g. 'svf run db-failover'.
+
+Aliases are stored in the workflow's aliases field. An alias must be unique
+across the repository and must not match another workflow's ID or slug;
+'svf index' warns about any collisions it finds.`,
+	}
+
+	cmd.AddCommand(newAliasAddCommand())
+	cmd.AddCommand(newAliasRemoveCommand())
+
+	return cmd
+}
+
+// newAliasAddCommand creates the alias-id add command.
+func newAliasAddCommand() *cobra.Command {
+	opts := &AliasOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "add <workflow-ref> <alias>",
+		Short:   "Add an alias to a workflow",
+		Example: `  svf alias-id add shared/database-failover-procedure db-failover`,
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAlias(opts, args[0], args[1], true)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ConfigPath, "config", "", "config file path")
+	cmd.Flags().BoolVar(&opts.NoCommit, "no-commit", false, "do not commit the change")
+
+	return cmd
+}
+
+// newAliasRemoveCommand creates the alias-id remove command.
+func newAliasRemoveCommand() *cobra.Command {
+	opts := &AliasOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "remove <workflow-ref> <alias>",
+		Aliases: []string{"rm"},
+		Short:   "Remove an alias from a workflow",
+		Example: `  svf alias-id remove db-failover db-failover`,
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAlias(opts, args[0], args[1], false)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ConfigPath, "config", "", "config file path")
+	cmd.Flags().BoolVar(&opts.NoCommit, "no-commit", false, "do not commit the change")
+
+	return cmd
+}
+
+func runAlias(opts *AliasOptions, refStr, alias string, add bool) error {
+	ctx := context.Background()
+
+	// Load config
+	cfg, err := loadConfig(opts.ConfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Open repo
+	repo := gitrepo.New(cfg.Repo.Path)
+	if !repo.IsInitialized(ctx) {
+		return fmt.Errorf("repository not initialized. Run 'svf init' first")
+	}
+
+	// Create store
+	str, err := store.New(repo, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+
+	ref, err := resolveWorkflowRef(ctx, str, cfg, refStr)
+	if err != nil {
+		return err
+	}
+	wf, err := str.Load(ctx, ref)
+	if err != nil {
+		return fmt.Errorf("failed to load workflow: %w", err)
+	}
+
+	var message string
+	if add {
+		if wf.HasAlias(alias) {
+			fmt.Printf("%s already has alias %s.\n", wf.Title, alias)
+			return nil
+		}
+		if err := workflows.ValidateAlias(alias); err != nil {
+			return err
+		}
+		idx, _, err := index.NewBuilder(cfg.Repo.Path, cfg).LoadOrRebuild()
+		if err != nil {
+			return fmt.Errorf("failed to load index: %w", err)
+		}
+		if err := checkAliasAvailable(idx, cfg, ref.Path, alias); err != nil {
+			return err
+		}
+		wf.Aliases = append(wf.Aliases, alias)
+		message = fmt.Sprintf("Add alias %s to workflow: %s", alias, wf.Title)
+	} else {
+		if !wf.HasAlias(alias) {
+			return fmt.Errorf("%s has no alias %q", wf.Title, alias)
+		}
+		wf.Aliases = slices.DeleteFunc(wf.Aliases, func(a string) bool { return a == alias })
+		message = fmt.Sprintf("Remove alias %s from workflow: %s", alias, wf.Title)
+	}
+
+	saveOpts := store.SaveOptions{
+		Commit:  !opts.NoCommit,
+		Message: message,
+		Path:    ref.Path,
+		Doc:     ref.Doc,
+	}
+	if _, err := saveAndPublish(ctx, repo, str, cfg, wf, saveOpts); err != nil {
+		return fmt.Errorf("failed to save workflow: %w", err)
+	}
+
+	if add {
+		fmt.Printf("✓ %s can now be referenced as %s\n", wf.Title, alias)
+	} else {
+		fmt.Printf("✓ Removed alias %s from %s\n", alias, wf.Title)
+	}
+	return nil
+}
+
+// checkAliasAvailable returns an error if giving the workflow at path the
+// alias would collide with another workflow's alias, ID or slug.
+func checkAliasAvailable(idx *index.Index, cfg *config.Config, path, alias string) error {
+	rel, err := filepath.Rel(cfg.Repo.Path, path)
+	if err != nil {
+		rel = path
+	}
+
+	candidate := &index.Index{Workflows: slices.Clone(idx.Workflows)}
+	found := false
+	for i, entry := range candidate.Workflows {
+		if entry.Path == rel {
+			candidate.Workflows[i].Aliases = append(slices.Clone(entry.Aliases), alias)
+			found = true
+		}
+	}
+	if !found {
+		candidate.Workflows = append(candidate.Workflows, index.WorkflowEntry{Path: rel, Aliases: []string{alias}})
+	}
+
+	for _, c := range candidate.AliasCollisions() {
+		if c.Alias == alias {
+			return fmt.Errorf("cannot add %s: %s", alias, c)
+		}
+	}
+	return nil
+}
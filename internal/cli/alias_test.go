@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/index"
+)
+
+func TestCheckAliasAvailable(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Repo.Path = "/repo"
+	idx := &index.Index{Workflows: []index.WorkflowEntry{
+		{ID: "wf_failover", Path: "shared/db-failover-procedure/workflow.yaml", Aliases: []string{"db-failover"}},
+		{ID: "wf_restore", Path: "workflows/team/bob/restore/workflow.yaml"},
+	}}
+	failover := filepath.Join(cfg.Repo.Path, "shared", "db-failover-procedure", "workflow.yaml")
+	restore := filepath.Join(cfg.Repo.Path, "workflows", "team", "bob", "restore", "workflow.yaml")
+
+	tests := []struct {
+		name    string
+		path    string
+		alias   string
+		wantErr bool
+	}{
+		{name: "free alias", path: failover, alias: "failover"},
+		{name: "own slug", path: failover, alias: "db-failover-procedure"},
+		{name: "another workflow's alias", path: restore, alias: "db-failover", wantErr: true},
+		{name: "another workflow's slug", path: failover, alias: "restore", wantErr: true},
+		{name: "another workflow's ID", path: failover, alias: "wf_restore", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkAliasAvailable(idx, cfg, tt.path, tt.alias)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkAliasAvailable(%q) error = %v, wantErr %v", tt.alias, err, tt.wantErr)
+			}
+		})
+	}
+}
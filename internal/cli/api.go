@@ -0,0 +1,188 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/chazuruo/svf/internal/api"
+	"github.com/chazuruo/svf/internal/audit"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	runnerpkg "github.com/chazuruo/svf/internal/runner"
+	"github.com/chazuruo/svf/internal/runpath"
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+// apiTokenEnv is the environment variable holding the API token.
+const apiTokenEnv = "SVF_API_TOKEN"
+
+// APIOptions contains the options for the api command.
+type APIOptions struct {
+	ConfigPath string
+	Host       string
+	Port       int
+	Socket     string
+	Token      string
+}
+
+// NewAPICommand creates the api command.
+func NewAPICommand() *cobra.Command {
+	opts := &APIOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "api",
+		Short: "Serve a JSON API for editor and bot integrations",
+		Long: `Serve list, search, view, run and lint over a local JSON HTTP API, so
+IDE extensions and ChatOps bots can drive svf without scraping CLI output.
+
+Endpoints:
+  GET  /v1/workflows             List indexed workflows
+  GET  /v1/search?q=&tag=&regex  Search (same query syntax as 'svf search')
+  GET  /v1/workflows/{ref}       Load a workflow by ID, alias, slug or path
+  POST /v1/run                   Run a workflow: {"workflow", "params",
+                                 "dry_run", "confirm_dangerous"}
+  POST /v1/lint                  Lint {"workflow"}, or every workflow
+
+Requests must send "Authorization: Bearer <token>". The token is taken from
+--token or $SVF_API_TOKEN; if neither is set a random one is generated and
+printed at startup.
+
+The API listens on localhost by default, or on a Unix socket with --socket.`,
+		Example: `  svf api --port 7070
+  svf api --socket ~/.svf/api.sock
+  curl -H "Authorization: Bearer $SVF_API_TOKEN" localhost:7070/v1/search?q=deploy`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAPI(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ConfigPath, "config", "", "config file path")
+	cmd.Flags().StringVar(&opts.Host, "host", "127.0.0.1", "address to listen on")
+	cmd.Flags().IntVar(&opts.Port, "port", 7070, "port to listen on")
+	cmd.Flags().StringVar(&opts.Socket, "socket", "", "listen on this Unix socket instead of TCP")
+	cmd.Flags().StringVar(&opts.Token, "token", "", "API token (default $SVF_API_TOKEN, or generated)")
+
+	return cmd
+}
+
+func runAPI(opts *APIOptions) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Load config
+	cfg, err := loadConfig(opts.ConfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Open repo
+	repo := gitrepo.New(cfg.Repo.Path)
+	if !repo.IsInitialized(ctx) {
+		return fmt.Errorf("repository not initialized. Run 'svf init' first")
+	}
+
+	// Create store
+	str, err := store.New(repo, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+
+	token, generated, err := apiToken(opts.Token)
+	if err != nil {
+		return err
+	}
+
+	srv, err := api.New(cfg, str, api.Options{
+		Token: token,
+		CheckView: func(wf *workflows.Workflow) error {
+			return runpath.RecordAccess(cfg, wf, audit.ActionView)
+		},
+		RecordRun: func(wf *workflows.Workflow, results []runnerpkg.StepResult, started time.Time, success bool) {
+			recordRun(cfg, wf, results, started, success, false)
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	var listener net.Listener
+	if opts.Socket != "" {
+		err = removeStaleSocket(opts.Socket)
+		if err == nil {
+			listener, err = net.Listen("unix", opts.Socket)
+		}
+		if err == nil {
+			err = os.Chmod(opts.Socket, 0600)
+		}
+	} else {
+		listener, err = net.Listen("tcp", net.JoinHostPort(opts.Host, strconv.Itoa(opts.Port)))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+
+	httpServer := &http.Server{
+		Handler:           srv.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = httpServer.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Printf("Serving the svf API on %s (Ctrl+C to stop)\n", listener.Addr())
+	if generated {
+		fmt.Printf("Token: %s\n", token)
+	}
+	if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("server failed: %w", err)
+	}
+	return nil
+}
+
+// removeStaleSocket removes the socket a previous run left at path. Any
+// other kind of file there is left alone and reported.
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%s exists and is not a socket", path)
+	}
+	return os.Remove(path)
+}
+
+// apiToken returns the token from the flag or environment, or a new random
+// one. generated reports whether it was generated.
+func apiToken(flag string) (token string, generated bool, err error) {
+	if flag != "" {
+		return flag, false, nil
+	}
+	if env := os.Getenv(apiTokenEnv); env != "" {
+		return env, false, nil
+	}
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", false, fmt.Errorf("failed to generate token: %w", err)
+	}
+	return hex.EncodeToString(buf), true, nil
+}
@@ -5,12 +5,17 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 	"github.com/chazuruo/svf/internal/ai"
 	"github.com/chazuruo/svf/internal/config"
+	svferrors "github.com/chazuruo/svf/internal/errors"
+	"github.com/chazuruo/svf/internal/diff"
 	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/history"
+	"github.com/chazuruo/svf/internal/index"
 	"github.com/chazuruo/svf/internal/tui"
 	"github.com/chazuruo/svf/internal/workflows"
 	"github.com/chazuruo/svf/internal/workflows/store"
@@ -27,6 +32,37 @@ type AskOptions struct {
 	Identity   string
 	NoCommit   bool
 	JSON       bool
+
+	// FromHistory includes selected shell history commands as context.
+	FromHistory  bool
+	HistoryShell string
+	HistoryLimit int
+	HistorySince string
+
+	// HistoryBounded is set when --history-limit or --since was given.
+	// Without a picker to review the commands, --no-tui requires it.
+	HistoryBounded bool
+
+	// History holds the selected, redacted history commands.
+	History []string
+
+	captureOptions
+
+	// Context is a search query selecting existing workflows whose
+	// summaries are sent as examples, at most ContextLimit of them.
+	Context      string
+	ContextLimit int
+
+	// Existing holds the workflows selected by Context.
+	Existing []*workflows.Workflow
+
+	// Workflow is the workflow --as step adds steps to, picked
+	// interactively when empty.
+	Workflow string
+	// At is the 1-based step the new steps go before; 0 appends them.
+	At int
+	// Yes saves added steps without confirming the diff.
+	Yes bool
 }
 
 // NewAskCommand creates the ask command.
@@ -43,7 +79,9 @@ The command will:
 2. Show redaction UI to review sensitive data
 3. Send to AI provider for generation
 4. Show result in workflow editor for review
-5. Save to repository (unless --no-commit)
+5. Optionally refine it with follow-up instructions (Ctrl+R), reviewing
+   each revision as a diff against the previous version
+6. Save to repository (unless --no-commit)
 
 Provider selection:
 - Use --provider to specify (openai, ollama, etc.)
@@ -52,9 +90,27 @@ Provider selection:
 
 Output format:
 - Use --as workflow to generate a full workflow (default)
-- Use --as step to generate a single step
-- Use --identity to set the workflow identity path`,
+- Use --as step to generate one or more steps and insert them into an
+  existing workflow (--workflow, or picked interactively) before step
+  --at (default: append). The change is shown as a diff to confirm, or
+  saved directly with --yes, and committed like an edit
+- Use --identity to set the workflow identity path
+
+History context:
+- Use --from-history to pick recent shell commands (after redaction) that
+  are sent as context, so the AI turns what you just did into a cleaned,
+  parameterized workflow. A prompt is optional in this mode.
+- Use --history-limit, --since and --shell to control which commands load.
+  With --no-tui there is no picker, so --history-limit or --since is
+  required and every loaded command is sent after automatic redaction
+
+Repository context:
+- Use --context with a search query (e.g. tag:deploy) to send redacted
+  summaries of matching workflows, so the result follows your team's
+  naming, tags and placeholders and chains to existing workflows instead
+  of repeating them. --context-limit caps how many are sent (default 5)`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.HistoryBounded = cmd.Flags().Changed("history-limit") || cmd.Flags().Changed("since")
 			return runAsk(opts)
 		},
 	}
@@ -68,6 +124,17 @@ Output format:
 	cmd.Flags().StringVar(&opts.Identity, "identity", "", "Identity path for the workflow")
 	cmd.Flags().BoolVar(&opts.NoCommit, "no-commit", false, "Don't commit to git after saving")
 	cmd.Flags().BoolVar(&opts.JSON, "json", false, "Output result as JSON")
+	cmd.Flags().BoolVar(&opts.FromHistory, "from-history", false, "Use selected shell history commands as context")
+	cmd.Flags().StringVar(&opts.HistoryShell, "shell", "", "Shell history to read with --from-history (bash/zsh, default: auto-detect)")
+	cmd.Flags().IntVar(&opts.HistoryLimit, "history-limit", 50, "Maximum number of history entries to load with --from-history")
+	cmd.Flags().StringVar(&opts.HistorySince, "since", "", "Only load history since duration with --from-history (e.g., 1h)")
+	cmd.Flags().BoolVar(&opts.CaptureContext, "capture-context", false, "Save the OS and shell in the workflow's metadata")
+	cmd.Flags().StringSliceVar(&opts.CaptureVersions, "capture-versions", nil, "Save these tools' versions in the workflow's metadata (e.g. kubectl,terraform)")
+	cmd.Flags().StringVar(&opts.Context, "context", "", "Send summaries of existing workflows matching this search query (e.g. tag:deploy)")
+	cmd.Flags().IntVar(&opts.ContextLimit, "context-limit", 5, "Maximum number of workflows to send with --context")
+	cmd.Flags().StringVar(&opts.Workflow, "workflow", "", "Workflow to add steps to with --as step")
+	cmd.Flags().IntVar(&opts.At, "at", 0, "Insert the steps before this step number with --as step (default: append)")
+	cmd.Flags().BoolVar(&opts.Yes, "yes", false, "Save the steps without confirming the diff with --as step")
 
 	return cmd
 }
@@ -87,15 +154,173 @@ func runAsk(opts *AskOptions) error {
 		return fmt.Errorf("repository not initialized. Run 'svf init' first")
 	}
 
+	// Collect shell history context
+	if opts.FromHistory {
+		commands, ok, err := collectHistoryContext(opts, cfg)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Canceled.")
+			return nil
+		}
+		opts.History = commands
+	}
+
+	// Collect existing workflows as examples
+	if opts.Context != "" {
+		existing, err := loadContextWorkflows(ctx, repo, cfg, opts.Context, opts.ContextLimit)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Sending %d existing workflows as context.\n", len(existing))
+		opts.Existing = existing
+	}
+
+	switch opts.As {
+	case "workflow":
+	case "step":
+		return runAskStep(ctx, opts, cfg, repo)
+	default:
+		return fmt.Errorf("invalid --as %q: use workflow or step", opts.As)
+	}
+
 	// Check for --no-tui flag
 	if IsNoTUI() {
 		return runAskNonInteractive(ctx, opts, cfg)
 	}
 
+	// Line mode when no TUI is available
+	if GetInteractionMode(cfg) == ModeLine {
+		return runAskLine(ctx, opts, cfg)
+	}
+
 	// Interactive mode
 	return runAskInteractive(ctx, opts, cfg)
 }
 
+// runAskLine runs ask command with sequential line prompts.
+func runAskLine(ctx context.Context, opts *AskOptions, cfg *config.Config) error {
+	p := tui.NewStdioLinePrompter()
+
+	prompt := opts.Prompt
+	if prompt == "" {
+		var err error
+		prompt, err = p.Ask("Describe the workflow you want", "")
+		if err != nil {
+			return err
+		}
+		if prompt == "" && len(opts.History) == 0 {
+			fmt.Println("Canceled.")
+			return nil
+		}
+	}
+
+	// Review sensitive data before sending the prompt to the provider
+	if prompt != "" {
+		redacted, ok, err := tui.RedactLine(prompt, p)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Canceled.")
+			return nil
+		}
+		prompt = redacted
+	}
+
+	aiCfg := buildAIConfig(opts, cfg)
+	estimate := estimateGenerate(aiCfg, prompt, opts)
+	p.Printf("Estimated request: %s\n", estimate)
+	if cfg.AI.ConfirmSend {
+		send, err := p.Confirm("Send to "+aiCfg.Provider+"?", true)
+		if err != nil {
+			return err
+		}
+		if !send {
+			fmt.Println("Canceled.")
+			return nil
+		}
+	}
+
+	provider, err := ai.NewProvider(aiCfg)
+	if err != nil {
+		return &svferrors.AIProviderError{NotConfigured: true, Err: fmt.Errorf("failed to create AI provider: %w", err)}
+	}
+	if provider == nil {
+		return &svferrors.AIProviderError{NotConfigured: true}
+	}
+
+	wf, err := generateWorkflow(ctx, provider, prompt, opts)
+	if err != nil {
+		return &svferrors.AIProviderError{Err: fmt.Errorf("failed to generate workflow: %w", err)}
+	}
+
+	if err := outputWorkflowText(wf); err != nil {
+		return err
+	}
+
+	// Review loop: save, refine with follow-up instructions, or quit
+	choices := []tui.Choice{
+		{Key: "s", Label: "save"},
+		{Key: "r", Label: "refine"},
+		{Key: "q", Label: "quit"},
+	}
+	for {
+		choice, err := p.Choose("\nWhat next?", choices, "s")
+		if err != nil {
+			return err
+		}
+		if choice == "q" {
+			fmt.Println("Canceled.")
+			return nil
+		}
+		if choice == "s" {
+			break
+		}
+
+		instructions, err := p.Ask("What should change", "")
+		if err != nil {
+			return err
+		}
+		if instructions == "" {
+			continue
+		}
+
+		revised, err := refineWorkflow(ctx, provider, wf, instructions)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to refine workflow: %v\n", err)
+			continue
+		}
+
+		lines, err := diff.Workflows(wf, revised)
+		if err != nil {
+			return err
+		}
+		if !diff.HasChanges(lines) {
+			fmt.Println("\nThe revision made no changes.")
+			continue
+		}
+		fmt.Printf("\n%s\n", diff.Format(lines))
+
+		accept, err := p.Confirm("Accept this revision?", true)
+		if err != nil {
+			return err
+		}
+		if accept {
+			wf = revised
+		}
+	}
+
+	repo := gitrepo.New(cfg.Repo.Path)
+	if err := saveWorkflowToRepo(ctx, repo, wf, opts, cfg); err != nil {
+		return fmt.Errorf("failed to save workflow: %w", err)
+	}
+
+	fmt.Printf("\nWorkflow saved: %s\n", wf.Title)
+	return nil
+}
+
 // runAskInteractive runs ask command in TUI mode.
 func runAskInteractive(ctx context.Context, opts *AskOptions, cfg *config.Config) error {
 	// Build TUI options
@@ -106,6 +331,8 @@ func runAskInteractive(ctx context.Context, opts *AskOptions, cfg *config.Config
 		As:        opts.As,
 		Identity:  opts.Identity,
 		NoCommit:  opts.NoCommit,
+		History:   opts.History,
+		Existing:  opts.Existing,
 	}
 
 	// Create and run the ask TUI
@@ -151,7 +378,7 @@ func runAskInteractive(ctx context.Context, opts *AskOptions, cfg *config.Config
 func saveWorkflowToRepo(ctx context.Context, repo gitrepo.Repo, wf *workflows.Workflow, opts *AskOptions, cfg *config.Config) error {
 	// Generate ID if not set
 	if wf.ID == "" {
-		wf.ID = generateULID()
+		wf.ID = workflows.NewID()
 	}
 
 	// Validate workflow
@@ -159,6 +386,8 @@ func saveWorkflowToRepo(ctx context.Context, repo gitrepo.Repo, wf *workflows.Wo
 		return fmt.Errorf("workflow title is required")
 	}
 
+	opts.capture(ctx, wf, history.DetectShell())
+
 	// Create store
 	st, err := store.New(repo, cfg)
 	if err != nil {
@@ -169,6 +398,7 @@ func saveWorkflowToRepo(ctx context.Context, repo gitrepo.Repo, wf *workflows.Wo
 	saveOpts := store.SaveOptions{
 		Commit: !opts.NoCommit,
 		Message: fmt.Sprintf("Add workflow: %s", wf.Title),
+		AllowSecrets: AllowSecrets,
 	}
 
 	// Set identity path if provided
@@ -187,16 +417,10 @@ func saveWorkflowToRepo(ctx context.Context, repo gitrepo.Repo, wf *workflows.Wo
 	return nil
 }
 
-// generateULID generates a unique ULID for the workflow ID.
-func generateULID() string {
-	// Simple ID generation - in production use a proper ULID library
-	return fmt.Sprintf("wf_%d", os.Getpid())
-}
-
 // runAskNonInteractive runs ask command in non-interactive mode.
 func runAskNonInteractive(ctx context.Context, opts *AskOptions, cfg *config.Config) error {
 	// Check if prompt is provided
-	if opts.Prompt == "" {
+	if opts.Prompt == "" && len(opts.History) == 0 {
 		return fmt.Errorf("prompt required for non-interactive mode.\nUsage: svf ask --prompt \"your prompt here\"")
 	}
 
@@ -206,21 +430,21 @@ func runAskNonInteractive(ctx context.Context, opts *AskOptions, cfg *config.Con
 	// Create provider
 	provider, err := ai.NewProvider(aiCfg)
 	if err != nil {
-		os.Exit(30) // Provider not configured
-		return fmt.Errorf("failed to create AI provider: %w", err)
+		return &svferrors.AIProviderError{NotConfigured: true, Err: fmt.Errorf("failed to create AI provider: %w", err)}
 	}
 
 	// Check if provider is configured
 	if provider == nil {
-		os.Exit(30) // Provider not configured
-		return fmt.Errorf("AI provider not configured. Please configure AI in settings or use --provider flag")
+		return &svferrors.AIProviderError{NotConfigured: true}
 	}
 
+	// Report the estimate on stderr so stdout stays machine-readable
+	fmt.Fprintf(os.Stderr, "Estimated request: %s\n", estimateGenerate(aiCfg, opts.Prompt, opts))
+
 	// Generate workflow
 	wf, err := generateWorkflow(ctx, provider, opts.Prompt, opts)
 	if err != nil {
-		os.Exit(31) // Provider error
-		return fmt.Errorf("failed to generate workflow: %w", err)
+		return &svferrors.AIProviderError{Err: fmt.Errorf("failed to generate workflow: %w", err)}
 	}
 
 	// Output result
@@ -279,10 +503,25 @@ func buildAIConfig(opts *AskOptions, cfg *config.Config) *ai.Config {
 	if cfg.AI.BaseURL != "" {
 		aiCfg.BaseURL = cfg.AI.BaseURL
 	}
+	aiCfg.MaxPromptTokens = cfg.AI.MaxPromptTokens
+	aiCfg.Truncation = ai.Truncation(cfg.AI.Truncation)
+	aiCfg.Retry.MaxAttempts = cfg.AI.MaxAttempts
+	aiCfg.OnRetry = func(s ai.RetryStatus) {
+		fmt.Fprintf(os.Stderr, "AI: %s\n", s)
+	}
+	for _, fb := range cfg.AI.Fallbacks {
+		aiCfg.Fallbacks = append(aiCfg.Fallbacks, ai.NewFallback(fb.Provider, fb.Model, fb.BaseURL, fb.APIKeyEnv))
+	}
 
 	return aiCfg
 }
 
+// estimateGenerate estimates the size and cost of generating from prompt.
+func estimateGenerate(aiCfg *ai.Config, prompt string, opts *AskOptions) ai.Estimate {
+	req := ai.GenerateRequest{Prompt: prompt, Context: askContext(opts)}
+	return ai.NewEstimate(aiCfg, ai.EstimateRequest(req))
+}
+
 // generateWorkflow generates a workflow from a prompt.
 func generateWorkflow(ctx context.Context, provider ai.Provider, prompt string, opts *AskOptions) (*workflows.Workflow, error) {
 	req := ai.GenerateRequest{
@@ -290,6 +529,145 @@ func generateWorkflow(ctx context.Context, provider ai.Provider, prompt string,
 		Options: ai.GenerateOptions{
 			IncludePlaceholders: true,
 		},
+		Context: askContext(opts),
+	}
+
+	return provider.GenerateWorkflow(ctx, req)
+}
+
+// askContext returns the history and existing workflows to send with a
+// generation request, or nil if there are none.
+func askContext(opts *AskOptions) *ai.GenerateContext {
+	if len(opts.History) == 0 && len(opts.Existing) == 0 {
+		return nil
+	}
+	return &ai.GenerateContext{History: opts.History, ExistingWorkflows: opts.Existing}
+}
+
+// loadContextWorkflows loads up to limit workflows matching query, best
+// match first, to send as examples with a generation request.
+func loadContextWorkflows(ctx context.Context, repo gitrepo.Repo, cfg *config.Config, query string, limit int) ([]*workflows.Workflow, error) {
+	idx, _, err := index.NewBuilder(cfg.Repo.Path, cfg).LoadOrRebuild()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load index: %w", err)
+	}
+	results, err := idx.Query(index.SearchOptions{Query: query, MaxResults: limit})
+	if err != nil {
+		return nil, fmt.Errorf("invalid --context query: %w", err)
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no workflows match --context %q", query)
+	}
+
+	st, err := store.New(repo, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create store: %w", err)
+	}
+	existing := make([]*workflows.Workflow, 0, len(results))
+	for _, r := range results {
+		entry := r.Entry
+		wf, err := st.Load(ctx, entryRef(cfg, &entry))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", entry.Path, err)
+		}
+		existing = append(existing, wf)
+	}
+	return existing, nil
+}
+
+// collectHistoryContext loads shell history and lets the user pick and
+// redact the commands to send as context. With --no-tui, all loaded
+// commands are used, so the user must have bounded them with
+// --history-limit or --since. Every command is also passed through
+// automatic redaction. ok is false if the user canceled.
+func collectHistoryContext(opts *AskOptions, cfg *config.Config) ([]string, bool, error) {
+	mode := GetInteractionMode(cfg)
+	if mode == ModeNone && !opts.HistoryBounded {
+		return nil, false, fmt.Errorf("--from-history with --no-tui sends commands without review: set --history-limit or --since to choose which")
+	}
+
+	commands, _, err := loadHistory(opts.HistoryShell, opts.HistoryLimit, opts.HistorySince)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(commands) == 0 {
+		return nil, false, fmt.Errorf("no commands found in shell history")
+	}
+
+	var text string
+
+	switch mode {
+	case ModeNone:
+		text = joinHistoryCommands(commands)
+
+	case ModeLine:
+		p := tui.NewStdioLinePrompter()
+		selected, err := tui.PickHistoryLine(commands, p)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to select commands: %w", err)
+		}
+		if len(selected) == 0 {
+			return nil, false, nil
+		}
+
+		var ok bool
+		text, ok, err = tui.RedactLine(joinHistoryCommands(selected), p)
+		if err != nil || !ok {
+			return nil, false, err
+		}
+
+	default:
+		picker, err := tea.NewProgram(tui.NewHistoryPickerModel(commands), tea.WithAltScreen()).Run()
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to run TUI: %w", err)
+		}
+		finalPicker := picker.(tui.HistoryPickerModel)
+		if finalPicker.DidQuit() {
+			return nil, false, nil
+		}
+		selected := finalPicker.GetSelectedCommands()
+		if len(selected) == 0 {
+			return nil, false, nil
+		}
+
+		redaction, err := tea.NewProgram(tui.NewRedactionModel(joinHistoryCommands(selected)), tea.WithAltScreen()).Run()
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to run TUI: %w", err)
+		}
+		finalRedaction := redaction.(tui.RedactionModel)
+		if !finalRedaction.DidConfirm() {
+			return nil, false, nil
+		}
+		text = finalRedaction.GetRedactedContent()
+	}
+
+	var result []string
+	for _, line := range strings.Split(ai.Redact(text), "\n") {
+		if strings.TrimSpace(line) != "" {
+			result = append(result, line)
+		}
+	}
+	return result, true, nil
+}
+
+// joinHistoryCommands joins history commands one per line.
+func joinHistoryCommands(commands []history.Command) string {
+	lines := make([]string, len(commands))
+	for i, c := range commands {
+		lines[i] = c.Command
+	}
+	return strings.Join(lines, "\n")
+}
+
+// refineWorkflow asks the provider to revise a workflow according to
+// follow-up instructions.
+func refineWorkflow(ctx context.Context, provider ai.Provider, previous *workflows.Workflow, instructions string) (*workflows.Workflow, error) {
+	req := ai.GenerateRequest{
+		Prompt:   ai.Redact(instructions),
+		Previous: previous,
+		Options: ai.GenerateOptions{
+			IncludePlaceholders: true,
+		},
 	}
 
 	return provider.GenerateWorkflow(ctx, req)
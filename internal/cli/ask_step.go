@@ -0,0 +1,206 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/chazuruo/svf/internal/ai"
+	"github.com/chazuruo/svf/internal/config"
+	svferrors "github.com/chazuruo/svf/internal/errors"
+	"github.com/chazuruo/svf/internal/diff"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/tui"
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+// runAskStep generates steps with AI and inserts them into an existing
+// workflow (--as step). The change is shown as a diff and, once confirmed
+// (or with --yes), saved and committed like an edit. Without a terminal,
+// --workflow and --prompt are required and only --yes saves.
+func runAskStep(ctx context.Context, opts *AskOptions, cfg *config.Config, repo gitrepo.Repo) error {
+	str, err := store.New(repo, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+	ref, err := resolveOrPickWorkflow(ctx, str, cfg, opts.Workflow)
+	if err != nil {
+		return err
+	}
+	target, err := str.Load(ctx, ref)
+	if err != nil {
+		return fmt.Errorf("failed to load workflow: %w", err)
+	}
+	if opts.At < 0 || opts.At > len(target.Steps)+1 {
+		return fmt.Errorf("invalid --at %d: %s has %d steps", opts.At, target.Title, len(target.Steps))
+	}
+
+	var p *tui.LinePrompter
+	if !IsNoTUI() && GetInteractionMode(cfg) != ModeNone {
+		p = tui.NewStdioLinePrompter()
+	}
+
+	prompt := opts.Prompt
+	if prompt == "" {
+		if p == nil {
+			return fmt.Errorf("prompt required for non-interactive mode.\nUsage: svf ask --as step --workflow <workflow-ref> --prompt \"your prompt here\"")
+		}
+		if prompt, err = p.Ask("Describe the step(s) to add to "+target.Title, ""); err != nil {
+			return err
+		}
+		if prompt == "" {
+			fmt.Println("Canceled.")
+			return nil
+		}
+	}
+
+	at := opts.At
+	if at == 0 && p != nil && len(target.Steps) > 0 {
+		if at, err = askInsertPosition(p, target); err != nil {
+			return err
+		}
+	}
+	// at is 1-based from here on; convert to the index the steps go at
+	insertAt := len(target.Steps)
+	if at > 0 {
+		insertAt = at - 1
+	}
+
+	aiCfg := buildAIConfig(opts, cfg)
+	if p != nil {
+		// Review sensitive data before sending the prompt to the provider
+		redacted, ok, err := tui.RedactLine(prompt, p)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Canceled.")
+			return nil
+		}
+		prompt = redacted
+
+		p.Printf("Estimated request: %s\n", estimateSteps(aiCfg, prompt, target, insertAt))
+		if cfg.AI.ConfirmSend {
+			send, err := p.Confirm("Send to "+aiCfg.Provider+"?", true)
+			if err != nil {
+				return err
+			}
+			if !send {
+				fmt.Println("Canceled.")
+				return nil
+			}
+		}
+	} else {
+		fmt.Fprintf(os.Stderr, "Estimated request: %s\n", estimateSteps(aiCfg, prompt, target, insertAt))
+	}
+
+	provider, err := ai.NewProvider(aiCfg)
+	if err != nil {
+		return &svferrors.AIProviderError{NotConfigured: true, Err: fmt.Errorf("failed to create AI provider: %w", err)}
+	}
+	if provider == nil {
+		return &svferrors.AIProviderError{NotConfigured: true}
+	}
+
+	steps, err := generateSteps(ctx, provider, prompt, target, insertAt)
+	if err != nil {
+		return &svferrors.AIProviderError{Err: fmt.Errorf("failed to generate steps: %w", err)}
+	}
+
+	updated := insertSteps(target, insertAt, steps)
+	lines, err := diff.Workflows(target, updated)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n%s\n", diff.Format(lines))
+
+	if !opts.Yes {
+		if p == nil {
+			fmt.Println("Rerun with --yes to save these steps.")
+			return nil
+		}
+		save, err := p.Confirm(fmt.Sprintf("Add %d step(s) to %s?", len(steps), target.Title), true)
+		if err != nil {
+			return err
+		}
+		if !save {
+			fmt.Println("Canceled.")
+			return nil
+		}
+	}
+
+	saveOpts := store.SaveOptions{
+		Commit: !opts.NoCommit,
+		Path:   ref.Path,
+		Doc:    ref.Doc,
+	}
+	if _, err := saveAndPublish(ctx, repo, str, cfg, updated, saveOpts); err != nil {
+		return fmt.Errorf("failed to save workflow: %w", err)
+	}
+
+	fmt.Printf("✓ Added %d step(s) to %s\n", len(steps), target.Title)
+	return nil
+}
+
+// askInsertPosition lists wf's steps and asks which one the new steps go
+// before. It returns the 1-based step, or len(wf.Steps)+1 to append.
+func askInsertPosition(p *tui.LinePrompter, wf *workflows.Workflow) (int, error) {
+	p.Printf("\nSteps of %s:\n", wf.Title)
+	for i, step := range wf.Steps {
+		p.Printf("  %d. %s\n", i+1, step.Name)
+	}
+	end := len(wf.Steps) + 1
+	for {
+		answer, err := p.Ask(fmt.Sprintf("Insert before step (1-%d, %d to append)", len(wf.Steps), end), strconv.Itoa(end))
+		if err != nil {
+			return 0, err
+		}
+		at, err := strconv.Atoi(strings.TrimSpace(answer))
+		if err == nil && at >= 1 && at <= end {
+			return at, nil
+		}
+		p.Printf("Enter a number from 1 to %d.\n", end)
+	}
+}
+
+// estimateSteps estimates the size and cost of generating steps for
+// target.
+func estimateSteps(aiCfg *ai.Config, prompt string, target *workflows.Workflow, insertAt int) ai.Estimate {
+	req := ai.GenerateRequest{Prompt: prompt, Target: target, InsertAt: insertAt}
+	return ai.NewEstimate(aiCfg, ai.EstimateRequest(req))
+}
+
+// generateSteps asks the provider for steps to insert into target at
+// index insertAt.
+func generateSteps(ctx context.Context, provider ai.Provider, prompt string, target *workflows.Workflow, insertAt int) ([]workflows.Step, error) {
+	req := ai.GenerateRequest{
+		Prompt:   prompt,
+		Target:   target,
+		InsertAt: insertAt,
+		Options: ai.GenerateOptions{
+			IncludePlaceholders: true,
+		},
+	}
+	generated, err := provider.GenerateWorkflow(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(generated.Steps) == 0 {
+		return nil, fmt.Errorf("no steps were generated")
+	}
+	return generated.Steps, nil
+}
+
+// insertSteps returns a copy of wf with steps inserted at index at.
+func insertSteps(wf *workflows.Workflow, at int, steps []workflows.Step) *workflows.Workflow {
+	updated := *wf
+	updated.Steps = make([]workflows.Step, 0, len(wf.Steps)+len(steps))
+	updated.Steps = append(updated.Steps, wf.Steps[:at]...)
+	updated.Steps = append(updated.Steps, steps...)
+	updated.Steps = append(updated.Steps, wf.Steps[at:]...)
+	return &updated
+}
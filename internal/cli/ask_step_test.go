@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chazuruo/svf/internal/ai"
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stepProvider returns a workflow holding steps, remembering the request.
+type stepProvider struct {
+	steps []workflows.Step
+	req   ai.GenerateRequest
+}
+
+func (p *stepProvider) Name() string { return "steps" }
+
+func (p *stepProvider) GenerateWorkflow(_ context.Context, req ai.GenerateRequest) (*workflows.Workflow, error) {
+	p.req = req
+	return &workflows.Workflow{Title: "New steps", Steps: p.steps}, nil
+}
+
+func (p *stepProvider) Explain(context.Context, ai.ExplainRequest) (string, error) {
+	return "", nil
+}
+
+func TestGenerateAndInsertSteps(t *testing.T) {
+	target := &workflows.Workflow{
+		Title: "Deploy",
+		Steps: []workflows.Step{{Name: "Build"}, {Name: "Deploy"}},
+	}
+	provider := &stepProvider{steps: []workflows.Step{{Name: "Test", Command: "make test"}}}
+
+	steps, err := generateSteps(context.Background(), provider, "run the tests", target, 1)
+	require.NoError(t, err)
+	assert.Same(t, target, provider.req.Target)
+	assert.Equal(t, 1, provider.req.InsertAt)
+
+	updated := insertSteps(target, 1, steps)
+	names := func(wf *workflows.Workflow) []string {
+		var names []string
+		for _, s := range wf.Steps {
+			names = append(names, s.Name)
+		}
+		return names
+	}
+	assert.Equal(t, []string{"Build", "Test", "Deploy"}, names(updated))
+	assert.Equal(t, []string{"Build", "Deploy"}, names(target), "target is left unchanged")
+	assert.Equal(t, []string{"Build", "Deploy", "Test"}, names(insertSteps(target, 2, steps)))
+	assert.Equal(t, []string{"Test", "Build", "Deploy"}, names(insertSteps(target, 0, steps)))
+
+	provider.steps = nil
+	_, err = generateSteps(context.Background(), provider, "nothing", target, 2)
+	assert.Error(t, err)
+}
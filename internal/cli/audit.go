@@ -0,0 +1,204 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/chazuruo/svf/internal/audit"
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+// AuditShowOptions contains the options for the audit show command.
+type AuditShowOptions struct {
+	ConfigPath string
+	Workflow   string
+	Actor      string
+	Days       int
+	JSON       bool
+}
+
+// NewAuditCommand creates the audit command.
+func NewAuditCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Review access to sensitive workflows",
+		Long: `Review who viewed and ran workflows tagged "sensitive".
+
+Every view or run of a sensitive workflow is appended to a signed audit
+log under .svf/audit in the repository, which 'svf sync' commits and
+shares. When the repository can't be written to, events go to a local log
+in $XDG_STATE_HOME/svf/audit instead.`,
+	}
+	cmd.AddCommand(newAuditShowCommand())
+	return cmd
+}
+
+// newAuditShowCommand creates the audit show command.
+func newAuditShowCommand() *cobra.Command {
+	opts := &AuditShowOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "List recorded access to sensitive workflows",
+		Long: `List recorded views and runs of sensitive workflows, oldest first.
+
+Each event is signed with a key kept on the machine that recorded it. The
+KEY column shows the key's fingerprint; events whose signature doesn't
+match their contents, e.g. because a line was edited by hand, are marked
+INVALID and make the command exit with an error. A line rewritten and
+signed with another key shows an unfamiliar fingerprint instead, and
+deleted lines only show in the repository's git history.`,
+		Example: `  svf audit show
+  svf audit show --workflow db-failover --days 30
+  svf audit show --actor platform/alice --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAuditShow(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ConfigPath, "config", "", "config file path")
+	cmd.Flags().StringVar(&opts.Workflow, "workflow", "", "only show access to this workflow (ID, alias, slug or path)")
+	cmd.Flags().StringVar(&opts.Actor, "actor", "", "only show access by this identity path")
+	cmd.Flags().IntVar(&opts.Days, "days", 0, "only show access in the last this many days (0 for all)")
+	cmd.Flags().BoolVar(&opts.JSON, "json", false, "output as JSON")
+
+	return cmd
+}
+
+// auditEntry is an event in the audit show output.
+type auditEntry struct {
+	audit.Event
+	Verified bool `json:"verified"`
+	Local    bool `json:"local,omitempty"`
+}
+
+func runAuditShow(opts *AuditShowOptions) error {
+	ctx := context.Background()
+
+	// Load config
+	var cfg *config.Config
+	var err error
+	if opts.ConfigPath != "" {
+		cfg, err = config.Load(opts.ConfigPath)
+	} else {
+		cfg, err = config.LoadWithDefaults()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Open repo
+	repo := gitrepo.New(cfg.Repo.Path)
+	if !repo.IsInitialized(ctx) {
+		return fmt.Errorf("repository not initialized. Run 'svf init' first")
+	}
+
+	workflowID := ""
+	if opts.Workflow != "" {
+		str, err := store.New(repo, cfg)
+		if err != nil {
+			return fmt.Errorf("failed to create store: %w", err)
+		}
+		ref, err := resolveWorkflowRef(ctx, str, cfg, opts.Workflow)
+		if err != nil {
+			return err
+		}
+		wf, err := str.Load(ctx, ref)
+		if err != nil {
+			return fmt.Errorf("failed to load workflow: %w", err)
+		}
+		workflowID = wf.ID
+	}
+
+	localDir, err := audit.LocalDir()
+	if err != nil {
+		return err
+	}
+	entries, err := audit.Load(cfg.Repo.Path, localDir)
+	if err != nil {
+		return fmt.Errorf("failed to load audit log: %w", err)
+	}
+	entries = filterAudit(entries, workflowID, opts.Actor, opts.Days, time.Now())
+
+	invalid := 0
+	for _, e := range entries {
+		if !e.Verified {
+			invalid++
+		}
+	}
+
+	if opts.JSON {
+		out := make([]auditEntry, len(entries))
+		for i, e := range entries {
+			out[i] = auditEntry{Event: e.Event, Verified: e.Verified, Local: e.Local}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(out); err != nil {
+			return err
+		}
+	} else {
+		printAudit(entries)
+	}
+
+	if invalid > 0 {
+		return fmt.Errorf("%d audit events failed signature verification", invalid)
+	}
+	return nil
+}
+
+// filterAudit returns the entries for workflowID by actor in the last
+// days before now; empty filters match everything.
+func filterAudit(entries []audit.Entry, workflowID, actor string, days int, now time.Time) []audit.Entry {
+	var cutoff time.Time
+	if days > 0 {
+		cutoff = now.AddDate(0, 0, -days)
+	}
+	var filtered []audit.Entry
+	for _, e := range entries {
+		if workflowID != "" && e.Workflow != workflowID {
+			continue
+		}
+		if actor != "" && e.Actor != actor {
+			continue
+		}
+		if e.At.Before(cutoff) {
+			continue
+		}
+		filtered = append(filtered, e)
+	}
+	return filtered
+}
+
+// printAudit prints entries as a table.
+func printAudit(entries []audit.Entry) {
+	if len(entries) == 0 {
+		fmt.Println("No access to sensitive workflows recorded.")
+		return
+	}
+
+	fmt.Printf("%-20s  %-6s  %-20s  %-8s  %s\n", "TIME", "ACTION", "ACTOR", "KEY", "WORKFLOW")
+	for _, e := range entries {
+		key := e.Fingerprint()
+		if !e.Verified {
+			key = "INVALID"
+		}
+		actor := e.Actor
+		if e.Host != "" {
+			actor += "@" + e.Host
+		}
+		workflow := fmt.Sprintf("%s (%s)", e.Title, e.Workflow)
+		if e.Local {
+			workflow += " [local]"
+		}
+		fmt.Printf("%-20s  %-6s  %-20s  %-8s  %s\n", e.At.Local().Format("2006-01-02 15:04:05"), e.Action, actor, key, workflow)
+	}
+}
@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chazuruo/svf/internal/audit"
+)
+
+func TestFilterAudit(t *testing.T) {
+	now := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+	entries := []audit.Entry{
+		{Event: audit.Event{At: now.AddDate(0, 0, -40), Workflow: "wf_db", Actor: "platform/alice"}},
+		{Event: audit.Event{At: now.AddDate(0, 0, -1), Workflow: "wf_db", Actor: "platform/bob"}},
+		{Event: audit.Event{At: now.AddDate(0, 0, -1), Workflow: "wf_keys", Actor: "platform/alice"}},
+	}
+
+	tests := []struct {
+		name     string
+		workflow string
+		actor    string
+		days     int
+		want     int
+	}{
+		{"all", "", "", 0, 3},
+		{"workflow", "wf_db", "", 0, 2},
+		{"actor", "", "platform/alice", 0, 2},
+		{"days", "", "", 30, 2},
+		{"combined", "wf_db", "platform/alice", 30, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filterAudit(entries, tt.workflow, tt.actor, tt.days, now); len(got) != tt.want {
+				t.Errorf("filterAudit() = %d entries, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+)
+
+// bootstrapCommitMessage is the message of the commit made by --bootstrap.
+const bootstrapCommitMessage = "Bootstrap svf workflow repository"
+
+// repoIsEmpty reports whether the repository has no commits yet.
+func repoIsEmpty(ctx context.Context, repo gitrepo.Repo) bool {
+	_, err := repo.ResolveRev(ctx, "HEAD")
+	return err != nil
+}
+
+// bootstrapRepo scaffolds the recommended layout of a new workflow
+// repository on top of the folder structure and index merge driver set up
+// by init: a .gitkeep in each empty directory so it is committed, the
+// .svf/templates directory for readme and export templates, a top-level
+// README and a CODEOWNERS stub. Existing files are left alone. Unless
+// noCommit is set, everything is committed as the initial commit.
+func bootstrapRepo(ctx context.Context, cfg *config.Config, noCommit bool) error {
+	repoPath := cfg.Repo.Path
+	dirs := []string{
+		filepath.Join(cfg.Workflows.Root, cfg.Identity.Path),
+		cfg.Workflows.SharedRoot,
+		cfg.Workflows.DraftRoot,
+		filepath.Join(".svf", "templates"),
+	}
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Join(repoPath, dir), 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", dir, err)
+		}
+		if err := writeFileIfMissing(filepath.Join(repoPath, dir, ".gitkeep"), ""); err != nil {
+			return err
+		}
+	}
+
+	if err := writeFileIfMissing(filepath.Join(repoPath, "README.md"), bootstrapReadme(cfg)); err != nil {
+		return err
+	}
+	if err := writeFileIfMissing(filepath.Join(repoPath, "CODEOWNERS"), bootstrapCodeowners(cfg)); err != nil {
+		return err
+	}
+
+	if noCommit {
+		fmt.Println("✓ Repository bootstrapped (not committed)")
+		return nil
+	}
+	repo := gitrepo.New(repoPath)
+	if err := repo.AddAll(ctx); err != nil {
+		return fmt.Errorf("failed to add files: %w", err)
+	}
+	if _, err := repo.CommitAll(ctx, bootstrapCommitMessage); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+	fmt.Println("✓ Repository bootstrapped and committed")
+	return nil
+}
+
+// writeFileIfMissing writes content to path unless the file exists.
+func writeFileIfMissing(path, content string) error {
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// bootstrapReadme returns the top-level README of a bootstrapped repository.
+func bootstrapReadme(cfg *config.Config) string {
+	var b strings.Builder
+	b.WriteString("# Workflows\n\n")
+	b.WriteString("Runbooks and command workflows managed with [svf](https://github.com/chazuruo/svf).\n\n")
+	b.WriteString("## Layout\n\n")
+	b.WriteString("| Path | Contents |\n")
+	b.WriteString("|------|----------|\n")
+	fmt.Fprintf(&b, "| `%s/<identity>/` | Personal and team workflows, one directory per identity |\n", cfg.Workflows.Root)
+	fmt.Fprintf(&b, "| `%s/` | Workflows shared across the organization |\n", cfg.Workflows.SharedRoot)
+	fmt.Fprintf(&b, "| `%s/` | Work-in-progress workflows |\n", cfg.Workflows.DraftRoot)
+	b.WriteString("| `.svf/` | The workflow index and templates for `svf readme` and `svf export` |\n\n")
+	b.WriteString("## Getting started\n\n")
+	b.WriteString("```bash\n")
+	b.WriteString("svf init --remote <this repository's URL> --identity <team/you>\n")
+	b.WriteString("svf list\n")
+	b.WriteString("svf run <workflow>\n")
+	b.WriteString("```\n")
+	return b.String()
+}
+
+// bootstrapCodeowners returns the CODEOWNERS stub of a bootstrapped
+// repository.
+func bootstrapCodeowners(cfg *config.Config) string {
+	var b strings.Builder
+	b.WriteString("# Owners review changes to the workflows under their paths.\n")
+	b.WriteString("# Replace the examples with your users and teams, e.g.:\n")
+	b.WriteString("#\n")
+	fmt.Fprintf(&b, "# /%s/platform/   @your-org/platform\n", cfg.Workflows.Root)
+	fmt.Fprintf(&b, "# /%s/            @your-org/sre\n", cfg.Workflows.SharedRoot)
+	return b.String()
+}
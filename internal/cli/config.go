@@ -0,0 +1,95 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/chazuruo/svf/internal/config"
+)
+
+// ConfigMigrateOptions contains the options for the config migrate command.
+type ConfigMigrateOptions struct {
+	ConfigPath string
+	DryRun     bool
+}
+
+// NewConfigCommand creates the config command.
+func NewConfigCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage the svf config file",
+		// Loading the config for the UI would migrate it before
+		// 'config migrate --dry-run' could report the changes
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			configureTheme(nil)
+			configureLocale(nil)
+		},
+	}
+
+	cmd.AddCommand(newConfigMigrateCommand())
+
+	return cmd
+}
+
+// newConfigMigrateCommand creates the config migrate command.
+func newConfigMigrateCommand() *cobra.Command {
+	opts := &ConfigMigrateOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Upgrade the config file to the current schema version",
+		Long: `Upgrade the config file to the current schema version, e.g. moving
+renamed keys to their new names.
+
+svf migrates the config file automatically when it loads it, so this is
+mostly useful with --dry-run to see what would change. The original file
+is kept next to it as config.toml.v<old version>.bak.`,
+		Example: `  svf config migrate --dry-run   # Show what would change
+  svf config migrate`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runConfigMigrate(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ConfigPath, "config", "", "config file path")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "report the changes without writing them")
+
+	return cmd
+}
+
+func runConfigMigrate(opts *ConfigMigrateOptions) error {
+	// Don't load the config here: loading migrates it
+	path := opts.ConfigPath
+	if path == "" {
+		path = config.DetectConfigPath()
+	}
+	if path == "" {
+		return fmt.Errorf("no config file found. Run 'svf init' first")
+	}
+
+	result, err := config.MigrateFile(path, opts.DryRun)
+	if err != nil {
+		return err
+	}
+	if !result.Migrated() {
+		fmt.Printf("%s is up to date (version %d).\n", path, result.To)
+		return nil
+	}
+
+	if opts.DryRun {
+		fmt.Printf("Would migrate %s from version %d to %d:\n", path, result.From, result.To)
+	} else {
+		fmt.Printf("Migrated %s from version %d to %d:\n", path, result.From, result.To)
+	}
+	for _, change := range result.Changes {
+		fmt.Printf("  - %s\n", change)
+	}
+	if opts.DryRun {
+		fmt.Println("\nRun without --dry-run to apply.")
+	} else {
+		fmt.Printf("\nBackup: %s\n", result.Backup)
+	}
+	return nil
+}
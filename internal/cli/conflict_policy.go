@@ -0,0 +1,147 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/index"
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+// generatedConflicts are conflicted files svf generates itself. They are
+// resolved by taking theirs and regenerating rather than asking the user.
+type generatedConflicts struct {
+	// Index is set if the search index was conflicted.
+	Index bool
+
+	// Readmes are the repo-relative paths of conflicted workflow READMEs.
+	Readmes []string
+}
+
+// empty reports whether no generated files were conflicted.
+func (g generatedConflicts) empty() bool {
+	return !g.Index && len(g.Readmes) == 0
+}
+
+// splitConflicts separates generated files (the search index and README.md
+// files next to a workflow) from workflow content conflicts.
+func splitConflicts(cfg *config.Config, conflicts []string) (generatedConflicts, []string) {
+	var generated generatedConflicts
+	var content []string
+
+	indexPath := filepath.Clean(cfg.Workflows.IndexPath)
+	for _, file := range conflicts {
+		switch {
+		case filepath.Clean(file) == indexPath:
+			generated.Index = true
+		case isGeneratedReadme(cfg.Repo.Path, file):
+			generated.Readmes = append(generated.Readmes, file)
+		default:
+			content = append(content, file)
+		}
+	}
+	return generated, content
+}
+
+// isGeneratedReadme reports whether a repo-relative path is a README.md
+// generated for the workflow in the same directory.
+func isGeneratedReadme(repoPath, file string) bool {
+	if filepath.Base(file) != "README.md" {
+		return false
+	}
+	return workflows.FindFile(filepath.Join(repoPath, filepath.Dir(file))) != ""
+}
+
+// autoResolveGenerated takes theirs for conflicted generated files and
+// returns the workflow content conflicts left for the user. The files are
+// regenerated afterwards by regenerateGenerated.
+func autoResolveGenerated(ctx context.Context, repo gitrepo.Repo, cfg *config.Config, conflicts []string) (generatedConflicts, []string) {
+	generated, content := splitConflicts(cfg, conflicts)
+
+	var resolved generatedConflicts
+	if generated.Index {
+		if err := repo.ResolveFile(ctx, cfg.Workflows.IndexPath, gitrepo.ResolveTheirs); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to auto-resolve %s: %v\n", cfg.Workflows.IndexPath, err)
+			content = append(content, cfg.Workflows.IndexPath)
+		} else {
+			resolved.Index = true
+		}
+	}
+	for _, file := range generated.Readmes {
+		if err := repo.ResolveFile(ctx, file, gitrepo.ResolveTheirs); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to auto-resolve %s: %v\n", file, err)
+			content = append(content, file)
+			continue
+		}
+		resolved.Readmes = append(resolved.Readmes, file)
+	}
+
+	if n := len(resolved.Readmes); resolved.Index || n > 0 {
+		if resolved.Index {
+			n++
+		}
+		fmt.Printf("✓ Auto-resolved %d generated file(s)\n", n)
+	}
+	return resolved, content
+}
+
+// regenerateGenerated regenerates and stages auto-resolved generated files.
+// READMEs whose workflow is still conflicted are left as taken and
+// regenerated on a later save or 'svf readme regen'.
+func regenerateGenerated(ctx context.Context, repo gitrepo.Repo, cfg *config.Config, generated generatedConflicts) {
+	if generated.empty() {
+		return
+	}
+
+	remaining, err := repo.GetConflicts(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to get conflicts: %v\n", err)
+		return
+	}
+	conflicted := make(map[string]bool, len(remaining))
+	for _, file := range remaining {
+		conflicted[filepath.Clean(file)] = true
+	}
+
+	if len(generated.Readmes) > 0 {
+		str, err := store.New(repo, cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to create store: %v\n", err)
+			return
+		}
+		for _, file := range generated.Readmes {
+			workflowPath := workflows.FindFile(filepath.Join(cfg.Repo.Path, filepath.Dir(file)))
+			if workflowPath == "" || conflicted[filepath.Join(filepath.Dir(file), filepath.Base(workflowPath))] {
+				continue
+			}
+			if _, err := str.RegenerateReadme(ctx, store.WorkflowRef{Path: workflowPath}); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to regenerate %s: %v\n", file, err)
+				continue
+			}
+			if err := repo.Add(ctx, file); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to stage %s: %v\n", file, err)
+			}
+		}
+	}
+
+	// The index covers every workflow, so wait until none are conflicted
+	if generated.Index && len(remaining) == 0 {
+		builder := index.NewBuilder(cfg.Repo.Path, cfg)
+		idx, err := builder.Build()
+		if err == nil {
+			err = builder.Save(idx)
+		}
+		if err == nil {
+			err = repo.Add(ctx, cfg.Workflows.IndexPath)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to regenerate index: %v\n", err)
+		}
+	}
+}
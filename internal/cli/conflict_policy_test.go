@@ -0,0 +1,194 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/index"
+	"github.com/chazuruo/svf/internal/testutil"
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+// TestSplitConflicts verifies that the index and workflow READMEs are
+// treated as generated files.
+func TestSplitConflicts(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Repo.Path = t.TempDir()
+
+	wfDir := filepath.Join(cfg.Repo.Path, "workflows", "team", "deploy")
+	if err := os.MkdirAll(wfDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(wfDir, "workflow.yaml"), []byte("title: Deploy\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	generated, content := splitConflicts(cfg, []string{
+		".svf/index.json",
+		"workflows/team/deploy/README.md",
+		"workflows/team/deploy/workflow.yaml",
+		"README.md",
+	})
+
+	if !generated.Index {
+		t.Error("generated.Index = false, want true")
+	}
+	if len(generated.Readmes) != 1 || generated.Readmes[0] != "workflows/team/deploy/README.md" {
+		t.Errorf("generated.Readmes = %v, want [workflows/team/deploy/README.md]", generated.Readmes)
+	}
+	want := []string{"workflows/team/deploy/workflow.yaml", "README.md"}
+	if len(content) != len(want) || content[0] != want[0] || content[1] != want[1] {
+		t.Errorf("content = %v, want %v", content, want)
+	}
+}
+
+// TestAutoResolveGenerated verifies that conflicts in generated files are
+// resolved and regenerated without leaving anything for the user.
+func TestAutoResolveGenerated(t *testing.T) {
+	ctx := context.Background()
+	setGitIdentity(t)
+
+	cfg := config.DefaultConfig()
+	cfg.Repo.Path = t.TempDir()
+	cfg.Identity.Path = "team/test"
+	repo := gitrepo.New(cfg.Repo.Path)
+	if err := repo.Init(ctx, gitrepo.InitOptions{}); err != nil {
+		t.Fatalf("failed to init repo: %v", err)
+	}
+	str, err := store.New(repo, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wf := &workflows.Workflow{
+		SchemaVersion: workflows.SchemaVersion,
+		Title:         "Deploy API",
+		Steps:         []workflows.Step{{Name: "apply", Command: "kubectl apply -f api.yaml"}},
+	}
+	ref, err := str.Save(ctx, wf, store.SaveOptions{Commit: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	readme := filepath.Join(filepath.Dir(ref.Path), "README.md")
+	indexPath := filepath.Join(cfg.Repo.Path, cfg.Workflows.IndexPath)
+
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = cfg.Repo.Path
+		if out, err := cmd.CombinedOutput(); err != nil && args[0] != "merge" {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	diverge := func(content string) {
+		t.Helper()
+		for _, path := range []string{readme, indexPath} {
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		git("add", "-A")
+		git("commit", "-m", content)
+	}
+
+	git("checkout", "-b", "other")
+	diverge("theirs\n")
+	git("checkout", "-")
+	diverge("ours\n")
+	git("merge", "other")
+
+	conflicts, err := repo.GetConflicts(ctx)
+	if err != nil || len(conflicts) != 2 {
+		t.Fatalf("GetConflicts() = %v, %v; want 2 conflicts", conflicts, err)
+	}
+
+	generated, content := autoResolveGenerated(ctx, repo, cfg, conflicts)
+	if len(content) != 0 {
+		t.Fatalf("content conflicts = %v, want none", content)
+	}
+	regenerateGenerated(ctx, repo, cfg, generated)
+
+	if has, _ := repo.HasConflicts(ctx); has {
+		t.Error("HasConflicts() = true after auto-resolution")
+	}
+
+	got, err := os.ReadFile(readme)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rel, _ := filepath.Rel(cfg.Repo.Path, ref.Path)
+	want, err := store.RenderReadme(nil, wf, rel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Errorf("README not regenerated:\n%s", got)
+	}
+
+	idx, err := index.NewBuilder(cfg.Repo.Path, cfg).Load()
+	if err != nil {
+		t.Fatalf("index not regenerated: %v", err)
+	}
+	if len(idx.Workflows) != 1 || idx.Workflows[0].Title != "Deploy API" {
+		t.Errorf("index workflows = %+v, want Deploy API", idx.Workflows)
+	}
+}
+
+// TestHandleConflicts_FinishesIntegration verifies that a rebase is
+// continued once its conflicts are resolved, and abandoned with
+// --conflicts abort.
+func TestHandleConflicts_FinishesIntegration(t *testing.T) {
+	ctx := context.Background()
+	file := "workflows/team/deploy/workflow.yaml"
+
+	setup := func(t *testing.T) (*config.Config, *testutil.FakeRepo) {
+		cfg := config.DefaultConfig()
+		cfg.Repo.Path = t.TempDir()
+		repo := testutil.NewFakeRepo(cfg.Repo.Path)
+		if err := os.MkdirAll(filepath.Join(cfg.Repo.Path, filepath.Dir(file)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(cfg.Repo.Path, file), []byte("<<<<<<< ours\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		repo.IntegrateResult = gitrepo.IntegrateResult{Conflicts: true, ConflictFiles: []string{file}}
+		if _, err := repo.Integrate(ctx, gitrepo.StrategyRebase); err != nil {
+			t.Fatal(err)
+		}
+		repo.SetConflictDetails(gitrepo.ConflictDetails{Path: file, Ours: []byte("title: Ours\n"), Theirs: []byte("title: Theirs\n")})
+		return cfg, repo
+	}
+
+	t.Run("theirs", func(t *testing.T) {
+		cfg, repo := setup(t)
+		if err := handleConflicts(ctx, repo, cfg, "theirs", &IntegrateResult{Conflicts: true}); err != nil {
+			t.Fatalf("handleConflicts() error = %v", err)
+		}
+		if state, _ := repo.GetMergeState(ctx); state.InProgress() {
+			t.Errorf("merge state = %+v, want the rebase continued", state)
+		}
+		if commits := repo.Commits(); len(commits) != 1 || string(commits[0].Files[file]) != "title: Theirs\n" {
+			t.Errorf("commits = %+v, want theirs committed", commits)
+		}
+	})
+
+	t.Run("abort", func(t *testing.T) {
+		cfg, repo := setup(t)
+		if err := handleConflicts(ctx, repo, cfg, "abort", &IntegrateResult{Conflicts: true}); err == nil {
+			t.Fatal("handleConflicts(abort) error = nil, want error")
+		}
+		state, _ := repo.GetMergeState(ctx)
+		if state.InProgress() || len(state.Conflicts) != 0 {
+			t.Errorf("merge state = %+v, want the rebase abandoned", state)
+		}
+	})
+}
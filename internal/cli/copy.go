@@ -0,0 +1,147 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+// CopyOptions contains the options for the copy command.
+type CopyOptions struct {
+	ConfigPath string
+	NoTags     bool
+	Edit       bool
+	Raw        bool
+	NoCommit   bool
+}
+
+// NewCopyCommand creates the copy command.
+func NewCopyCommand() *cobra.Command {
+	opts := &CopyOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "copy <workflow-ref> [new-title]",
+		Short: "Start a new workflow from a copy of another",
+		Long: `Copy a workflow, yours or anyone's, into your identity path.
+
+The copy gets a fresh ID and a slug from its title, which defaults to the
+original's title with " (copy)" appended. Step scripts and files come
+along. The original's aliases, owners and review are not copied, and
+--no-tags drops its tags too.
+
+Use --edit to open the copy in the editor before it is saved, or --raw to
+edit its YAML in $EDITOR. The copy is committed like any new workflow.`,
+		Example: `  svf copy shared/rotate-certs "Rotate staging certificates"
+  svf copy deploy-api --edit
+  svf copy wf_01HV3K8Q "Deploy worker" --no-tags --raw`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			title := ""
+			if len(args) > 1 {
+				title = args[1]
+			}
+			if opts.Raw {
+				opts.Edit = true
+			}
+			return runCopy(opts, args[0], title)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ConfigPath, "config", "", "config file path")
+	cmd.Flags().BoolVar(&opts.NoTags, "no-tags", false, "do not copy the workflow's tags")
+	cmd.Flags().BoolVarP(&opts.Edit, "edit", "e", false, "open the copy in the editor before saving")
+	cmd.Flags().BoolVar(&opts.Raw, "raw", false, "edit the copy's YAML in $EDITOR (implies --edit)")
+	cmd.Flags().BoolVar(&opts.NoCommit, "no-commit", false, "skip git commit after saving")
+
+	return cmd
+}
+
+func runCopy(opts *CopyOptions, refStr, title string) error {
+	ctx := context.Background()
+
+	// Load config
+	cfg, err := loadConfig(opts.ConfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Open repo
+	repo := gitrepo.New(cfg.Repo.Path)
+	if !repo.IsInitialized(ctx) {
+		return fmt.Errorf("repository not initialized. Run 'svf init' first")
+	}
+
+	// Create store
+	str, err := store.New(repo, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+
+	ref, err := resolveWorkflowRef(ctx, str, cfg, refStr)
+	if err != nil {
+		return err
+	}
+	wf, err := str.Load(ctx, ref)
+	if err != nil {
+		return fmt.Errorf("failed to load workflow: %w", err)
+	}
+
+	if title == "" {
+		title = wf.Title + " (copy)"
+	}
+	copied, err := wf.Copy(title, !opts.NoTags)
+	if err != nil {
+		return err
+	}
+
+	if opts.Edit {
+		if GetInteractionMode(cfg) == ModeNone {
+			return fmt.Errorf("--edit needs an interactive terminal")
+		}
+		edited, err := editWorkflow(ctx, cfg, copied, opts.Raw, 0)
+		if err != nil {
+			return fmt.Errorf("failed to edit workflow: %w", err)
+		}
+		if edited == nil {
+			fmt.Println("Quit without saving.")
+			return nil
+		}
+		copied = edited
+	}
+
+	if err := copied.Validate(); err != nil {
+		return fmt.Errorf("workflow validation failed: %w", err)
+	}
+	if dir := copyDestination(cfg, copied.Title); workflows.FindFile(dir) != "" {
+		return fmt.Errorf("you already have a workflow at %s; give the copy another title", dir)
+	}
+
+	saveOpts := store.SaveOptions{
+		Commit:    !opts.NoCommit,
+		SourceDir: filepath.Dir(ref.Path),
+	}
+	saved, err := saveAndPublish(ctx, repo, str, cfg, copied, saveOpts)
+	if err != nil {
+		return fmt.Errorf("failed to save workflow: %w", err)
+	}
+
+	fmt.Printf("✓ Copied %s to %s (id: %s)\n", wf.Title, saved.Slug, saved.ID)
+	return nil
+}
+
+// copyDestination returns the directory a copy titled title is saved to:
+// its slug under the configured identity's directory.
+func copyDestination(cfg *config.Config, title string) string {
+	identity := cfg.Identity.Path
+	if identity == "" {
+		identity = "default"
+	}
+	return filepath.Join(cfg.Repo.Path, cfg.Workflows.Root, identity, store.Slugify(title))
+}
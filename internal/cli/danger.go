@@ -0,0 +1,127 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/charmbracelet/huh"
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/lock"
+	runnerpkg "github.com/chazuruo/svf/internal/runner"
+)
+
+// learnDangerRules offers to turn each command flagged as dangerous during
+// a run into a rule in the repository's danger rule pack, through a form
+// prefilled with a pattern derived from the command. Added rules are
+// committed so the whole team is warned about such commands. Problems are
+// reported as warnings; they never fail the run. In read-only mode
+// nothing is offered, since the rules would have to be committed.
+func learnDangerRules(ctx context.Context, cfg *config.Config, commands []string) {
+	if cfg.ReadOnly {
+		fmt.Fprintf(os.Stderr, "Note: %d flagged command(s) not added to the danger rules in read-only mode\n", len(commands))
+		return
+	}
+	var added []runnerpkg.DangerRule
+	for _, command := range commands {
+		rule, ok, err := askDangerRule(command)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			return
+		}
+		if ok {
+			added = append(added, rule)
+		}
+	}
+	if len(added) == 0 {
+		return
+	}
+
+	if err := saveDangerRules(ctx, cfg, added); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to save danger rules: %v\n", err)
+		return
+	}
+	fmt.Printf("✓ Added %d danger rule(s) to %s\n", len(added), runnerpkg.DangerRulesFile)
+}
+
+// askDangerRule asks how to describe a flagged command as a danger rule.
+// ok is false if the user chose not to add one.
+func askDangerRule(command string) (rule runnerpkg.DangerRule, ok bool, err error) {
+	rule.Pattern = runnerpkg.DerivePattern(command)
+	add := true
+
+	fmt.Printf("\nYou flagged this command as dangerous:\n  %s\n", command)
+	err = huh.NewForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title("Add a danger rule so your team is warned before running it?").
+				Value(&add),
+		),
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Pattern").
+				Description("Regular expression matched against commands").
+				Value(&rule.Pattern).
+				Validate(func(s string) error {
+					re, err := regexp.Compile(s)
+					if err != nil {
+						return err
+					}
+					if !re.MatchString(command) {
+						return errors.New("the pattern must match the flagged command")
+					}
+					return nil
+				}),
+			huh.NewInput().
+				Title("Name").
+				Description("Shown in the warning, e.g. \"Namespace deletion\"").
+				Value(&rule.Name).
+				Validate(func(s string) error {
+					if s == "" {
+						return errors.New("a name is required")
+					}
+					return nil
+				}),
+			huh.NewInput().
+				Title("Risk").
+				Description("What could go wrong").
+				Value(&rule.Risk),
+		).WithHideFunc(func() bool { return !add }),
+	).Run()
+	if err != nil {
+		return rule, false, fmt.Errorf("form error: %w", err)
+	}
+	return rule, add, nil
+}
+
+// saveDangerRules adds rules to the repository's rule pack and commits it.
+func saveDangerRules(ctx context.Context, cfg *config.Config, rules []runnerpkg.DangerRule) error {
+	l, err := lock.Acquire(cfg.Repo.Path, lock.Options{Op: "danger rules"})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = l.Release() }()
+
+	for _, rule := range rules {
+		if err := runnerpkg.AddDangerRule(cfg.Repo.Path, rule); err != nil {
+			return err
+		}
+	}
+
+	repo := gitrepo.New(cfg.Repo.Path)
+	if err := repo.Add(ctx, runnerpkg.DangerRulesFile); err != nil {
+		return fmt.Errorf("failed to add %s: %w", runnerpkg.DangerRulesFile, err)
+	}
+	message := fmt.Sprintf("Add danger rule: %s", rules[0].Name)
+	if len(rules) > 1 {
+		message = fmt.Sprintf("Add %d danger rules", len(rules))
+	}
+	if _, err := repo.CommitAll(ctx, message); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+	return nil
+}
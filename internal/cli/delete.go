@@ -0,0 +1,153 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/runpath"
+	"github.com/chazuruo/svf/internal/tui"
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+// DeleteOptions contains the options for the delete command.
+type DeleteOptions struct {
+	ConfigPath string
+	Force      bool
+	Shared     bool
+	NoCommit   bool
+}
+
+// NewDeleteCommand creates the delete command.
+func NewDeleteCommand() *cobra.Command {
+	opts := &DeleteOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "delete <workflow-ref>",
+		Short: "Delete a workflow",
+		Long: `Delete a workflow from the repository.
+
+The workflow is summarized and you are asked to confirm; --force skips
+the question, as needed in scripts. The removal is committed following
+identity.mode: on the current branch in direct mode, or on a new feature
+branch for review in PR mode. The search index is updated.
+
+Shared workflows are used by others, so deleting one also requires
+--shared. A workflow sharing its file with others loses only its own
+document, and workflows nested in its directory are kept.`,
+		Example: `  svf delete old-deploy
+  svf delete shared/rotate-certs --shared
+  svf delete wf_01HV3K8Q --force`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDelete(opts, args[0])
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ConfigPath, "config", "", "config file path")
+	cmd.Flags().BoolVarP(&opts.Force, "force", "f", false, "delete without asking")
+	cmd.Flags().BoolVar(&opts.Shared, "shared", false, "allow deleting a shared workflow")
+	cmd.Flags().BoolVar(&opts.NoCommit, "no-commit", false, "do not commit the removal")
+
+	return cmd
+}
+
+func runDelete(opts *DeleteOptions, refStr string) error {
+	ctx := context.Background()
+
+	// Load config
+	cfg, err := loadConfig(opts.ConfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Open repo
+	repo := gitrepo.New(cfg.Repo.Path)
+	if !repo.IsInitialized(ctx) {
+		return fmt.Errorf("repository not initialized. Run 'svf init' first")
+	}
+
+	// Create store
+	str, err := store.New(repo, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+
+	ref, err := resolveWorkflowRef(ctx, str, cfg, refStr)
+	if err != nil {
+		return err
+	}
+	wf, err := str.Load(ctx, ref)
+	if err != nil {
+		return fmt.Errorf("failed to load workflow: %w", err)
+	}
+
+	if runpath.IsShared(cfg, ref.Path) && !opts.Shared {
+		return fmt.Errorf("%s is a shared workflow; pass --shared to delete it", wf.Title)
+	}
+
+	if !opts.Force {
+		if GetInteractionMode(cfg) == ModeNone {
+			return fmt.Errorf("use --force to delete without prompting")
+		}
+		p := tui.NewStdioLinePrompter()
+		p.Printf("%s", deleteSummary(cfg, wf, ref))
+		ok, err := p.Confirm(fmt.Sprintf("Delete %s?", wf.Title), false)
+		if err != nil && !errors.Is(err, tui.ErrNoInput) {
+			return err
+		}
+		if !ok {
+			fmt.Println("Canceled.")
+			return nil
+		}
+	}
+
+	message := fmt.Sprintf("Delete workflow: %s", wf.Title)
+	if opts.NoCommit {
+		if err := str.Delete(ctx, ref); err != nil {
+			return err
+		}
+		refreshIndexEntry(cfg, ref.Path)
+	} else if err := deleteAndPublish(ctx, repo, str, cfg, ref, message); err != nil {
+		return fmt.Errorf("failed to delete workflow: %w", err)
+	}
+
+	fmt.Printf("✓ Deleted %s\n", wf.Title)
+	return nil
+}
+
+// deleteSummary describes the workflow about to be deleted.
+func deleteSummary(cfg *config.Config, wf *workflows.Workflow, ref store.WorkflowRef) string {
+	var b strings.Builder
+	location := ref.Location()
+	if rel, err := filepath.Rel(cfg.Repo.Path, location); err == nil {
+		location = rel
+	}
+	fmt.Fprintf(&b, "Workflow: %s\n", wf.Title)
+	fmt.Fprintf(&b, "Path:     %s\n", location)
+	if wf.ID != "" {
+		fmt.Fprintf(&b, "ID:       %s\n", wf.ID)
+	}
+	if wf.Description != "" {
+		fmt.Fprintf(&b, "About:    %s\n", wf.Description)
+	}
+	if len(wf.Owners) > 0 {
+		fmt.Fprintf(&b, "Owners:   %s\n", strings.Join(wf.Owners, ", "))
+	}
+	fmt.Fprintf(&b, "Steps:    %d\n", len(wf.Steps))
+	for i, step := range wf.Steps {
+		name := step.Name
+		if name == "" {
+			name = step.Command
+		}
+		fmt.Fprintf(&b, "  %d. %s\n", i+1, name)
+	}
+	return b.String()
+}
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+func TestDeleteAndPublish(t *testing.T) {
+	ctx := context.Background()
+	setGitIdentity(t)
+
+	cfg := config.DefaultConfig()
+	cfg.Repo.Path = t.TempDir()
+	cfg.Identity.Path = "team/test"
+	cfg.Identity.Mode = "direct"
+	repo := gitrepo.New(cfg.Repo.Path)
+	if err := repo.Init(ctx, gitrepo.InitOptions{}); err != nil {
+		t.Fatalf("failed to init repo: %v", err)
+	}
+	str, err := store.New(repo, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newWorkflow := func(title string) *workflows.Workflow {
+		return &workflows.Workflow{
+			SchemaVersion: workflows.SchemaVersion,
+			Title:         title,
+			Steps:         []workflows.Step{{Command: "echo " + title}},
+		}
+	}
+
+	ref, err := str.Save(ctx, newWorkflow("Old Deploy"), store.SaveOptions{Commit: true})
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	before, err := repo.ResolveRev(ctx, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := deleteAndPublish(ctx, repo, str, cfg, ref, "Delete workflow: Old Deploy"); err != nil {
+		t.Fatalf("deleteAndPublish() error = %v", err)
+	}
+	if _, err := os.Stat(ref.Path); !os.IsNotExist(err) {
+		t.Error("workflow file still exists after delete")
+	}
+	out, err := exec.Command("git", "-C", cfg.Repo.Path, "log", "-1", "--format=%s", "--name-status").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(out), "Delete workflow: Old Deploy") || !strings.Contains(string(out), "D\tworkflows/team/test/old-deploy/workflow.yaml") {
+		t.Errorf("last commit = %q", out)
+	}
+	status, err := repo.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Dirty {
+		t.Errorf("repo left dirty: %+v", status.Entries)
+	}
+
+	// An uncommitted workflow is removed without a commit
+	draft, err := str.Save(ctx, newWorkflow("Scratch"), store.SaveOptions{})
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	head, err := repo.ResolveRev(ctx, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := deleteAndPublish(ctx, repo, str, cfg, draft, "Delete workflow: Scratch"); err != nil {
+		t.Fatalf("deleteAndPublish() of an uncommitted workflow error = %v", err)
+	}
+	if after, _ := repo.ResolveRev(ctx, "HEAD"); after != head || head == before {
+		t.Errorf("expected exactly one commit for the committed workflow")
+	}
+	if _, err := os.Stat(draft.Path); !os.IsNotExist(err) {
+		t.Error("uncommitted workflow still exists after delete")
+	}
+}
+
+func TestDeleteSummary(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Repo.Path = "/repo"
+	wf := &workflows.Workflow{
+		ID:          "wf_01HV3K8Q",
+		Title:       "Rotate certificates",
+		Description: "Renews the edge certificates",
+		Steps: []workflows.Step{
+			{Name: "Renew", Command: "certbot renew"},
+			{Command: "systemctl reload nginx"},
+		},
+	}
+	ref := store.WorkflowRef{Path: "/repo/shared/certs/workflow.yaml"}
+
+	got := deleteSummary(cfg, wf, ref)
+	for _, want := range []string{
+		"Workflow: Rotate certificates",
+		"Path:     shared/certs/workflow.yaml",
+		"ID:       wf_01HV3K8Q",
+		"Steps:    2",
+		"  1. Renew",
+		"  2. systemctl reload nginx",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("deleteSummary() missing %q:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/chazuruo/svf/internal/diff"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+// DiffOptions contains the options for the diff command.
+type DiffOptions struct {
+	ConfigPath string
+	Against    string
+	Raw        bool
+}
+
+// NewDiffCommand creates the diff command.
+func NewDiffCommand() *cobra.Command {
+	opts := &DiffOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "diff <workflow-ref>",
+		Short: "Show how a workflow changed",
+		Long: `Show a semantic diff of a workflow: metadata changes, steps added, removed
+or changed, and placeholder changes.
+
+The working copy of the workflow is compared against a commit, branch or
+tag (HEAD by default). Use --raw for a line diff of the YAML instead.`,
+		Example: `  svf diff deploy-api
+  svf diff deploy-api --against origin/main
+  svf diff deploy-api --against HEAD~3 --raw`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDiff(opts, args[0])
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ConfigPath, "config", "", "config file path")
+	cmd.Flags().StringVar(&opts.Against, "against", "HEAD", "commit, branch or tag to compare against")
+	cmd.Flags().BoolVar(&opts.Raw, "raw", false, "show a line diff of the YAML")
+
+	return cmd
+}
+
+func runDiff(opts *DiffOptions, refStr string) error {
+	ctx := context.Background()
+
+	// Load config
+	cfg, err := loadConfig(opts.ConfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Open repo
+	repo := gitrepo.New(cfg.Repo.Path)
+	if !repo.IsInitialized(ctx) {
+		return fmt.Errorf("repository not initialized. Run 'svf init' first")
+	}
+
+	// Create store
+	str, err := store.New(repo, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+
+	ref, err := resolveWorkflowRef(ctx, str, cfg, refStr)
+	if err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(cfg.Repo.Path, ref.Path)
+	if err != nil {
+		return fmt.Errorf("failed to resolve workflow path: %w", err)
+	}
+
+	current, err := str.Load(ctx, ref)
+	if err != nil {
+		return fmt.Errorf("failed to load workflow: %w", err)
+	}
+	previous, err := loadWorkflowAt(ctx, repo, opts.Against, rel, current.ID, ref.Doc)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s (%s → working copy)\n\n", docLabel(rel, ref.Doc), opts.Against)
+
+	if opts.Raw {
+		lines, err := diff.Workflows(previous, current)
+		if err != nil {
+			return err
+		}
+		if !diff.HasChanges(lines) {
+			fmt.Println("No changes.")
+			return nil
+		}
+		fmt.Print(diff.Format(lines))
+		return nil
+	}
+
+	changes := diff.Semantic(previous, current)
+	if len(changes) == 0 {
+		fmt.Println("No changes.")
+		return nil
+	}
+	fmt.Print(diff.FormatSemantic(changes))
+	return nil
+}
+
+// loadWorkflowAt loads a workflow as of a revision: the document of its
+// file with the workflow's ID, or else the one at the 1-based doc (0 for
+// a single-workflow file). Returns nil if there is none at that revision.
+func loadWorkflowAt(ctx context.Context, repo gitrepo.Repo, rev, path, id string, doc int) (*workflows.Workflow, error) {
+	wfs, err := loadWorkflowsAt(ctx, repo, rev, path)
+	if err != nil {
+		return nil, err
+	}
+	return matchDocument(wfs, id, doc), nil
+}
+
+// loadWorkflowsAt loads every workflow in a file as of a revision.
+// Returns nil if the file doesn't exist at that revision.
+func loadWorkflowsAt(ctx context.Context, repo gitrepo.Repo, rev, path string) ([]*workflows.Workflow, error) {
+	data, err := repo.ShowFile(ctx, rev, path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s at %s: %w", path, rev, err)
+	}
+
+	wfs, err := workflows.UnmarshalWorkflows(data, workflows.FormatForPath(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s at %s: %w", path, rev, err)
+	}
+	return wfs, nil
+}
+
+// matchDocument returns the workflow in wfs with the given ID, or else
+// the one at the 1-based doc (0 for the first) unless it has another ID.
+func matchDocument(wfs []*workflows.Workflow, id string, doc int) *workflows.Workflow {
+	if id != "" {
+		for _, wf := range wfs {
+			if wf.ID == id {
+				return wf
+			}
+		}
+	}
+	i := max(doc-1, 0)
+	if i >= len(wfs) || (id != "" && wfs[i].ID != "") {
+		return nil
+	}
+	return wfs[i]
+}
+
+// docLabel names a workflow file, with "#N" for a document of a
+// multi-document file.
+func docLabel(path string, doc int) string {
+	if doc > 0 {
+		return fmt.Sprintf("%s#%d", path, doc)
+	}
+	return path
+}
+
+// describeWorkflowChanges summarizes the workflows changed between two
+// revisions as semantic diffs, or returns "" if none changed. Documents
+// of a multi-document file are matched by ID, or else by position.
+func describeWorkflowChanges(ctx context.Context, repo gitrepo.Repo, from, to string) (string, error) {
+	files, err := repo.ChangedFiles(ctx, from, to)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	describe := func(label string, old, updated *workflows.Workflow) {
+		changes := diff.Semantic(old, updated)
+		if len(changes) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "%s\n", label)
+		for _, line := range strings.Split(strings.TrimSuffix(diff.FormatSemantic(changes), "\n"), "\n") {
+			fmt.Fprintf(&b, "  %s\n", line)
+		}
+	}
+	for _, file := range files {
+		if !workflows.IsWorkflowFile(filepath.Base(file)) {
+			continue
+		}
+
+		olds, err := loadWorkflowsAt(ctx, repo, from, file)
+		if err != nil {
+			return "", err
+		}
+		updated, err := loadWorkflowsAt(ctx, repo, to, file)
+		if err != nil {
+			return "", err
+		}
+
+		// Number documents only in files holding several
+		doc := func(i, n int) int {
+			if n > 1 {
+				return i + 1
+			}
+			return 0
+		}
+		matched := make(map[*workflows.Workflow]bool)
+		for i, wf := range updated {
+			old := matchDocument(olds, wf.ID, i+1)
+			matched[old] = true
+			describe(docLabel(file, doc(i, len(updated))), old, wf)
+		}
+		for i, old := range olds {
+			if !matched[old] {
+				describe(docLabel(file, doc(i, len(olds))), old, nil)
+			}
+		}
+	}
+	return b.String(), nil
+}
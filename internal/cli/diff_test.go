@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+// TestDescribeWorkflowChanges verifies the semantic review of workflows
+// changed between two commits.
+func TestDescribeWorkflowChanges(t *testing.T) {
+	ctx := context.Background()
+	setGitIdentity(t)
+
+	cfg := config.DefaultConfig()
+	cfg.Repo.Path = t.TempDir()
+	cfg.Identity.Path = "team/test"
+	repo := gitrepo.New(cfg.Repo.Path)
+	if err := repo.Init(ctx, gitrepo.InitOptions{}); err != nil {
+		t.Fatalf("failed to init repo: %v", err)
+	}
+	str, err := store.New(repo, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wf := &workflows.Workflow{
+		SchemaVersion: workflows.SchemaVersion,
+		Title:         "Deploy API",
+		Steps:         []workflows.Step{{Name: "apply", Command: "kubectl apply -f api.yaml"}},
+	}
+	ref, err := str.Save(ctx, wf, store.SaveOptions{Commit: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, err := repo.ResolveRev(ctx, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wf.Steps = append(wf.Steps, workflows.Step{Name: "verify", Command: "curl -f https://api/health"})
+	if _, err := str.Save(ctx, wf, store.SaveOptions{Commit: true, Path: ref.Path}); err != nil {
+		t.Fatal(err)
+	}
+
+	review, err := describeWorkflowChanges(ctx, repo, before, "HEAD")
+	if err != nil {
+		t.Fatalf("describeWorkflowChanges() error = %v", err)
+	}
+	for _, want := range []string{"workflows/team/test/deploy-api/workflow.yaml\n", `  + step 2 "verify"`} {
+		if !strings.Contains(review, want) {
+			t.Errorf("review missing %q:\n%s", want, review)
+		}
+	}
+
+	// A workflow missing at the old revision is reported as added
+	old, err := loadWorkflowAt(ctx, repo, before, "workflows/team/test/other/workflow.yaml", "", 0)
+	if err != nil || old != nil {
+		t.Errorf("loadWorkflowAt(missing) = %v, %v; want nil, nil", old, err)
+	}
+
+	// Documents of a multi-document file are diffed one by one
+	path := filepath.Join(cfg.Repo.Path, "workflows", "team", "test", "pair", "workflow.yaml")
+	write := func(second string) string {
+		t.Helper()
+		data := "schema_version: 1\nid: wf_first\ntitle: First\nsteps:\n  - command: \"true\"\n---\n" +
+			"schema_version: 1\nid: wf_second\ntitle: Second\nsteps:\n  - command: " + second + "\n"
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.AddAll(ctx); err != nil {
+			t.Fatal(err)
+		}
+		hash, err := repo.CommitAll(ctx, "Update pair")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return hash
+	}
+	before = write("make old")
+	write("make new")
+
+	review, err = describeWorkflowChanges(ctx, repo, before, "HEAD")
+	if err != nil {
+		t.Fatalf("describeWorkflowChanges() error = %v", err)
+	}
+	if !strings.Contains(review, "workflows/team/test/pair/workflow.yaml#2\n") || !strings.Contains(review, "make new") {
+		t.Errorf("review missing the second document's change:\n%s", review)
+	}
+	if strings.Contains(review, "#1") {
+		t.Errorf("review lists the unchanged first document:\n%s", review)
+	}
+
+	second, err := loadWorkflowAt(ctx, repo, before, "workflows/team/test/pair/workflow.yaml", "wf_second", 2)
+	if err != nil || second == nil || second.Steps[0].Command != "make old" {
+		t.Errorf("loadWorkflowAt(wf_second) = %+v, %v; want the second document", second, err)
+	}
+}
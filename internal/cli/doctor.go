@@ -0,0 +1,122 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/index"
+	"github.com/chazuruo/svf/internal/lock"
+)
+
+// DoctorOptions contains the options for the doctor command.
+type DoctorOptions struct {
+	ConfigPath string
+	NoFix      bool
+}
+
+// NewDoctorCommand creates the doctor command.
+func NewDoctorCommand() *cobra.Command {
+	opts := &DoctorOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the repository and search index for problems",
+		Long: `Diagnose common problems with the svf setup.
+
+Checks:
+- Configuration is valid
+- Repository is initialized
+- Whether another svf operation holds the repository lock
+- Search index integrity (checksums, missing or changed workflows)
+
+Index problems are repaired by rebuilding the index unless --no-fix is given.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDoctor(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ConfigPath, "config", "", "config file path")
+	cmd.Flags().BoolVar(&opts.NoFix, "no-fix", false, "report problems without repairing them")
+
+	return cmd
+}
+
+func runDoctor(opts *DoctorOptions) error {
+	ctx := context.Background()
+
+	// Load config
+	cfg, err := loadConfig(opts.ConfigPath)
+	if err != nil {
+		fmt.Printf("✗ Config: %v\n", err)
+		return fmt.Errorf("doctor found problems")
+	}
+	fmt.Println("✓ Config is valid")
+
+	// Open repo
+	repo := gitrepo.New(cfg.Repo.Path)
+	if !repo.IsInitialized(ctx) {
+		fmt.Printf("✗ Repository not initialized at %s. Run 'svf init' first\n", cfg.Repo.Path)
+		return fmt.Errorf("doctor found problems")
+	}
+	fmt.Printf("✓ Repository initialized at %s\n", cfg.Repo.Path)
+
+	// Report a held lock; stale locks are broken automatically on the next write
+	if info, err := lock.Read(cfg.Repo.Path); err == nil {
+		fmt.Printf("! Repository lock held by pid %d on %s (%s since %s)\n",
+			info.PID, info.Host, info.Op, info.AcquiredAt.Format(time.RFC3339))
+	} else if !os.IsNotExist(err) {
+		fmt.Printf("! Repository lock file is unreadable: %v\n", err)
+	}
+
+	unresolved, err := checkIndex(cfg, opts.NoFix)
+	if err != nil {
+		return err
+	}
+	if unresolved > 0 {
+		return fmt.Errorf("doctor found %d unresolved problem(s)", unresolved)
+	}
+
+	return nil
+}
+
+// checkIndex verifies the search index, rebuilding it unless noFix is
+// set. It returns the number of problems left unresolved.
+func checkIndex(cfg *config.Config, noFix bool) (int, error) {
+	builder := index.NewBuilder(cfg.Repo.Path, cfg)
+
+	problems, err := builder.Verify()
+	if err != nil {
+		return 0, fmt.Errorf("failed to verify index: %w", err)
+	}
+	if len(problems) == 0 {
+		fmt.Println("✓ Search index is consistent")
+		return 0, nil
+	}
+
+	fmt.Printf("✗ Search index has %d problem(s):\n", len(problems))
+	for _, p := range problems {
+		fmt.Printf("  - %s\n", p)
+	}
+
+	if noFix {
+		fmt.Println("Run 'svf doctor' without --no-fix to rebuild the index.")
+		return len(problems), nil
+	}
+
+	idx, err := builder.Rebuild()
+	if err != nil {
+		return 0, fmt.Errorf("failed to rebuild index: %w", err)
+	}
+	if cfg.ReadOnly {
+		fmt.Printf("✓ Rebuilt search index in memory with %d workflows (read-only mode, not saved)\n", len(idx.Workflows))
+		return 0, nil
+	}
+	fmt.Printf("✓ Rebuilt search index with %d workflows\n", len(idx.Workflows))
+	return 0, nil
+}
@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/index"
+)
+
+func TestCheckIndex_ReadOnly(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Repo.Path = t.TempDir()
+	cfg.ReadOnly = true
+	path := filepath.Join(cfg.Repo.Path, cfg.Workflows.Root, "team", "alice", "deploy", "workflow.yaml")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("title: Deploy\nsteps:\n  - command: \"true\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	unresolved, err := checkIndex(cfg, false)
+	if err != nil || unresolved != 0 {
+		t.Fatalf("checkIndex() = %d, %v; want 0, nil", unresolved, err)
+	}
+	indexPath := index.NewBuilder(cfg.Repo.Path, cfg).GetIndexPath()
+	if _, err := os.Stat(indexPath); !os.IsNotExist(err) {
+		t.Errorf("index saved in read-only mode: %v", err)
+	}
+}
@@ -6,6 +6,9 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
@@ -23,8 +26,12 @@ type EditOptions struct {
 	WorkflowID string
 	OutputPath string
 	NoCommit   bool
+	Message    string // Commit message instead of the generated one
+	Amend      bool   // Amend the last commit instead of creating one
 	NoTUI      bool   // For LLM automation
 	InputFile  string // For --no-tui mode
+	Raw        bool   // Edit the YAML in $EDITOR
+	Step       int    // 1-based step to open the editor at (implies Raw)
 }
 
 // NewEditCommand creates the edit command for creating/editing workflows.
@@ -32,7 +39,7 @@ func NewEditCommand() *cobra.Command {
 	opts := &EditOptions{}
 
 	cmd := &cobra.Command{
-		Use:   "edit",
+		Use:   "edit [workflow-ref]",
 		Short: "Create or edit workflows using the TUI editor",
 		Long: `Launch the terminal UI editor for creating and editing workflows.
 
@@ -43,28 +50,70 @@ The edit command opens a full-screen TUI editor where you can:
 - Configure placeholders for user input
 - Save workflows with automatic YAML generation
 
+Use --raw to edit the workflow YAML in your editor (editor.command or
+$EDITOR) instead. The YAML is validated when the editor exits and the
+editor is reopened on errors. --step N opens the editor at step N's line
+(using +N, or --goto for VS Code-style editors) and implies --raw.
+
+Saving validates the workflow, regenerates its README.md, updates the
+search index, and commits according to identity.mode: on the current
+branch in direct mode (pushing when git.push_on_save is set), or on a new
+feature branch in PR mode.
+
 In non-TUI mode (--no-tui), you can import workflows from YAML files:
-- Use --file to specify a YAML file to import
+- Use --file to specify a YAML file to import (the step scripts and files
+  it references are copied along)
 - Use --output to save to a specific path
 - Use --no-commit to skip automatic git commit
 
+Commit messages are generated from git.commit_template, e.g.
+"workflow(platform/chaz): add deploy-api", with a body listing the steps
+that changed. Use --message to write your own, and --amend to fold a quick
+fix into the last commit (direct mode only).
+
 Examples:
   faire edit                    # Create a new workflow (TUI mode)
   faire edit --workflow my-id   # Edit existing workflow by ID (TUI mode)
+  faire edit --workflow my-id --raw  # Edit the YAML in $EDITOR
+  faire edit my-id --step 3     # Edit the YAML at step 3
+  faire edit my-id --amend      # Fix up the last commit
   faire edit --output /path/save.yaml  # Save to specific path (TUI mode)
   faire edit --no-tui --file workflow.yaml  # Import from file (non-TUI)
   cat workflow.yaml | faire edit --no-tui  # Import from stdin (non-TUI)`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				if opts.WorkflowID != "" && opts.WorkflowID != args[0] {
+					return fmt.Errorf("workflow given both as an argument and with --workflow")
+				}
+				opts.WorkflowID = args[0]
+			}
+			if opts.Amend && opts.NoCommit {
+				return fmt.Errorf("--amend cannot be used with --no-commit")
+			}
+			if opts.Step != 0 {
+				if opts.WorkflowID == "" {
+					return fmt.Errorf("--step requires a workflow to edit")
+				}
+				if opts.Step < 0 {
+					return fmt.Errorf("--step must be a step number starting at 1")
+				}
+				opts.Raw = true
+			}
 			return runEdit(opts)
 		},
 	}
 
 	cmd.Flags().StringVar(&opts.ConfigPath, "config", "", "config file path")
-	cmd.Flags().StringVar(&opts.WorkflowID, "workflow", "", "workflow ID to edit (creates new if empty)")
-	cmd.Flags().StringVar(&opts.OutputPath, "output", "", "output path for workflow.yaml")
-	cmd.Flags().StringVar(&opts.InputFile, "file", "", "input YAML file (for --no-tui mode)")
+	cmd.Flags().StringVar(&opts.WorkflowID, "workflow", "", "workflow ID, slug, or path to edit (creates new if empty)")
+	cmd.Flags().StringVar(&opts.OutputPath, "output", "", "output path for the workflow file (.yaml, .toml or .json)")
+	cmd.Flags().StringVar(&opts.InputFile, "file", "", "input workflow file, YAML, TOML or JSON by extension (for --no-tui mode)")
 	cmd.Flags().BoolVar(&opts.NoCommit, "no-commit", false, "skip git commit after saving")
+	cmd.Flags().StringVarP(&opts.Message, "message", "m", "", "commit message (default: generated from git.commit_template)")
+	cmd.Flags().BoolVar(&opts.Amend, "amend", false, "amend the last commit instead of creating a new one")
 	cmd.Flags().BoolVar(&opts.NoTUI, "no-tui", false, "disable TUI/interactive mode (use with --file)")
+	cmd.Flags().BoolVar(&opts.Raw, "raw", false, "edit the workflow YAML in $EDITOR instead of the TUI editor")
+	cmd.Flags().IntVar(&opts.Step, "step", 0, "open the YAML in $EDITOR at this step (1-based)")
 
 	return cmd
 }
@@ -82,20 +131,15 @@ func runEditInteractive(opts *EditOptions) error {
 	ctx := context.Background()
 
 	// Load config
-	cfg, err := config.Load(opts.ConfigPath)
+	cfg, err := loadConfig(opts.ConfigPath)
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	// Initialize repo
-	repoPath, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("failed to get working directory: %w", err)
-	}
-
-	repo := gitrepo.New(repoPath)
-	if err != nil {
-		return fmt.Errorf("failed to open git repo: %w", err)
+	// Open repo
+	repo := gitrepo.New(cfg.Repo.Path)
+	if !repo.IsInitialized(ctx) {
+		return fmt.Errorf("repository not initialized. Run 'svf init' first")
 	}
 
 	// Create store
@@ -106,24 +150,15 @@ func runEditInteractive(opts *EditOptions) error {
 
 	// Load or create workflow
 	var wf *workflows.Workflow
+	var existingPath string
+	var existingDoc int
 	if opts.WorkflowID != "" {
 		// Load existing workflow
-		refs, err := str.List(ctx, store.Filter{})
+		ref, err := resolveWorkflowRef(ctx, str, cfg, opts.WorkflowID)
 		if err != nil {
-			return fmt.Errorf("failed to list workflows: %w", err)
-		}
-
-		var ref store.WorkflowRef
-		for _, r := range refs {
-			if r.ID == opts.WorkflowID || r.Slug == opts.WorkflowID {
-				ref = r
-				break
-			}
-		}
-
-		if ref.ID == "" {
-			return fmt.Errorf("workflow not found: %s", opts.WorkflowID)
+			return err
 		}
+		existingPath, existingDoc = ref.Path, ref.Doc
 
 		wf, err = str.Load(ctx, ref)
 		if err != nil {
@@ -142,34 +177,28 @@ func runEditInteractive(opts *EditOptions) error {
 		}
 	}
 
-	// Launch TUI editor
-	editor := tui.NewWorkflowEditor(ctx, wf)
-	p := tea.NewProgram(editor, tea.WithAltScreen())
-
-	finalModel, err := p.Run()
+	// Launch editor, falling back to line prompts without a TUI
+	editedWf, err := editWorkflow(ctx, cfg, wf, opts.Raw, opts.Step)
 	if err != nil {
-		return fmt.Errorf("failed to run TUI: %w", err)
+		return fmt.Errorf("failed to edit workflow: %w", err)
 	}
-
-	finalEditor := finalModel.(tui.WorkflowEditorModel)
-
-	// Handle quit without save
-	if finalEditor.DidQuit() {
+	if editedWf == nil {
 		fmt.Println("Quit without saving.")
 		return nil
 	}
 
-	// Get the edited workflow
-	editedWf := finalEditor.GetWorkflow()
-
 	// Validate workflow
 	if err := editedWf.Validate(); err != nil {
 		return fmt.Errorf("workflow validation failed: %w", err)
 	}
 
-	// Save workflow
+	// Save workflow, in place when editing an existing one
 	saveOpts := store.SaveOptions{
-		Commit: !opts.NoCommit,
+		Commit:  !opts.NoCommit,
+		Message: opts.Message,
+		Amend:   opts.Amend,
+		Path:    existingPath,
+		Doc:     existingDoc,
 	}
 
 	if opts.OutputPath != "" {
@@ -179,8 +208,8 @@ func runEditInteractive(opts *EditOptions) error {
 		}
 		fmt.Printf("Workflow saved to: %s\n", opts.OutputPath)
 	} else {
-		// Save using store
-		ref, err := str.Save(ctx, editedWf, saveOpts)
+		// Save using store and the configured git flow
+		ref, err := saveAndPublish(ctx, repo, str, cfg, editedWf, saveOpts)
 		if err != nil {
 			return fmt.Errorf("failed to save workflow: %w", err)
 		}
@@ -190,9 +219,158 @@ func runEditInteractive(opts *EditOptions) error {
 	return nil
 }
 
-// saveWorkflowToPath saves a workflow to a specific file path.
+// editWorkflow opens a workflow in the TUI editor, in line prompts when
+// there is no TUI, or as YAML in the user's editor when raw is set (at the
+// given 1-based step, if non-zero). Returns nil if the user quit without
+// saving.
+func editWorkflow(ctx context.Context, cfg *config.Config, wf *workflows.Workflow, raw bool, step int) (*workflows.Workflow, error) {
+	if raw {
+		return editWorkflowRaw(cfg, wf, step, tui.NewStdioLinePrompter())
+	}
+	if GetInteractionMode(cfg) == ModeLine {
+		saved, err := tui.EditWorkflowLine(wf, tui.NewStdioLinePrompter())
+		if err != nil || !saved {
+			return nil, err
+		}
+		return wf, nil
+	}
+
+	editor := tui.NewWorkflowEditor(ctx, wf)
+	p := tea.NewProgram(editor, tea.WithAltScreen())
+
+	finalModel, err := p.Run()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run TUI: %w", err)
+	}
+
+	finalEditor := finalModel.(tui.WorkflowEditorModel)
+
+	// Handle quit without save
+	if finalEditor.DidQuit() {
+		return nil, nil
+	}
+	return finalEditor.GetWorkflow(), nil
+}
+
+// editWorkflowRaw edits a workflow as YAML in the user's editor, opened
+// at the given 1-based step when step is non-zero. The editor is reopened
+// while the YAML fails to parse or validate, until the user gives up.
+// Returns nil if the workflow was left unchanged or the user gave up.
+func editWorkflowRaw(cfg *config.Config, wf *workflows.Workflow, step int, p *tui.LinePrompter) (*workflows.Workflow, error) {
+	original, err := workflows.MarshalWorkflow(wf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal workflow: %w", err)
+	}
+
+	line := 0
+	if step != 0 {
+		if line, err = workflows.StepLine(original, step-1); err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := os.CreateTemp("", "svf-*.yaml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := f.Name()
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	_, err = f.Write(original)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	for {
+		if err := runEditor(cfg, tmpPath, line); err != nil {
+			return nil, err
+		}
+
+		data, err := os.ReadFile(tmpPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read edited workflow: %w", err)
+		}
+		if string(data) == string(original) {
+			return nil, nil
+		}
+
+		edited, err := workflows.UnmarshalWorkflow(data)
+		if err == nil {
+			// Keep the workflow's identity if the ID line was dropped
+			if edited.ID == "" {
+				edited.ID = wf.ID
+			}
+			err = edited.Validate()
+		}
+		if err == nil {
+			return edited, nil
+		}
+
+		p.Printf("Invalid workflow: %v\n", err)
+		retry, promptErr := p.Confirm("Re-open the editor?", true)
+		if promptErr != nil {
+			return nil, promptErr
+		}
+		if !retry {
+			return nil, nil
+		}
+	}
+}
+
+// runEditor opens path in the configured editor, attached to the terminal,
+// positioned at line when it is non-zero.
+func runEditor(cfg *config.Config, path string, line int) error {
+	command := cfg.Editor.Command
+	if command == "" {
+		command = os.Getenv("VISUAL")
+	}
+	if command == "" {
+		command = os.Getenv("EDITOR")
+	}
+	if command == "" {
+		command = "vi"
+	}
+
+	// Allow commands with arguments, e.g. "code --wait"
+	args := editorArgs(strings.Fields(command), path, line)
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor %q failed: %w", command, err)
+	}
+	return nil
+}
+
+// editorArgs appends path to an editor command line, positioned at line
+// when it is non-zero: "--goto path:line" for VS Code and its forks,
+// "path:line" for editors that take that form, and "+line path" (vi, vim,
+// emacs, nano and most others) otherwise.
+func editorArgs(args []string, path string, line int) []string {
+	if line <= 0 {
+		return append(args, path)
+	}
+
+	name := strings.TrimSuffix(filepath.Base(args[0]), ".exe")
+	switch name {
+	case "code", "code-insiders", "codium", "cursor", "windsurf":
+		return append(args, "--goto", fmt.Sprintf("%s:%d", path, line))
+	case "subl", "zed", "hx", "helix":
+		return append(args, fmt.Sprintf("%s:%d", path, line))
+	default:
+		return append(args, fmt.Sprintf("+%d", line), path)
+	}
+}
+
+// saveWorkflowToPath saves a workflow to a specific file path, in the
+// format given by its extension.
 func saveWorkflowToPath(wf *workflows.Workflow, path string) error {
-	data, err := workflows.MarshalWorkflow(wf)
+	data, err := workflows.MarshalWorkflowAs(wf, workflows.FormatForPath(path))
 	if err != nil {
 		return fmt.Errorf("failed to marshal workflow: %w", err)
 	}
@@ -209,7 +387,7 @@ func runEditNonInteractive(opts *EditOptions) error {
 	ctx := context.Background()
 
 	// Load config
-	cfg, err := config.LoadWithDefaults()
+	cfg, err := loadConfig(opts.ConfigPath)
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
@@ -234,7 +412,7 @@ func runEditNonInteractive(opts *EditOptions) error {
 		if err != nil {
 			return fmt.Errorf("failed to read input file: %w", err)
 		}
-		wf, err = workflows.UnmarshalWorkflow(data)
+		wf, err = workflows.UnmarshalWorkflowAs(data, workflows.FormatForPath(opts.InputFile))
 		if err != nil {
 			return fmt.Errorf("failed to parse workflow: %w", err)
 		}
@@ -258,20 +436,30 @@ func runEditNonInteractive(opts *EditOptions) error {
 		return fmt.Errorf("workflow validation failed: %w", err)
 	}
 
-	// Save workflow
+	// Save workflow, with companion files next to the input file
 	saveOpts := store.SaveOptions{
-		Commit: !opts.NoCommit,
+		Commit:  !opts.NoCommit,
+		Message: opts.Message,
+		Amend:   opts.Amend,
+	}
+	if opts.InputFile != "" {
+		saveOpts.SourceDir = filepath.Dir(opts.InputFile)
 	}
 
 	if opts.OutputPath != "" {
 		// Save to specific path
+		if saveOpts.SourceDir != "" {
+			if err := workflows.CopyCompanions(wf, saveOpts.SourceDir, filepath.Dir(opts.OutputPath)); err != nil {
+				return fmt.Errorf("failed to save workflow: %w", err)
+			}
+		}
 		if err := saveWorkflowToPath(wf, opts.OutputPath); err != nil {
 			return fmt.Errorf("failed to save workflow: %w", err)
 		}
 		fmt.Printf("Workflow saved to: %s\n", opts.OutputPath)
 	} else {
-		// Save using store
-		ref, err := str.Save(ctx, wf, saveOpts)
+		// Save using store and the configured git flow
+		ref, err := saveAndPublish(ctx, repo, str, cfg, wf, saveOpts)
 		if err != nil {
 			return fmt.Errorf("failed to save workflow: %w", err)
 		}
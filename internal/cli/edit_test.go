@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEditorArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		command []string
+		line    int
+		want    []string
+	}{
+		{"no line", []string{"vim"}, 0, []string{"vim", "/tmp/wf.yaml"}},
+		{"vim", []string{"vim"}, 12, []string{"vim", "+12", "/tmp/wf.yaml"}},
+		{"emacs with flags", []string{"emacs", "-nw"}, 7, []string{"emacs", "-nw", "+7", "/tmp/wf.yaml"}},
+		{"vs code", []string{"/usr/local/bin/code", "--wait"}, 5, []string{"/usr/local/bin/code", "--wait", "--goto", "/tmp/wf.yaml:5"}},
+		{"sublime", []string{"subl", "-w"}, 3, []string{"subl", "-w", "/tmp/wf.yaml:3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := editorArgs(tt.command, "/tmp/wf.yaml", tt.line)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("editorArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// versionArgs are the arguments that print a tool's version, for tools
+// that don't support --version.
+var versionArgs = map[string][]string{
+	"go":      {"version"},
+	"helm":    {"version", "--short"},
+	"kubectl": {"version", "--client"},
+	"java":    {"-version"},
+}
+
+// toolVersionTimeout bounds how long a tool may take to print its version.
+const toolVersionTimeout = 5 * time.Second
+
+// captureOptions are the --capture-context and --capture-versions flags
+// of the commands that create workflows.
+type captureOptions struct {
+	// CaptureContext saves the OS and shell in the workflow's metadata;
+	// CaptureVersions also saves these tools' versions.
+	CaptureContext  bool
+	CaptureVersions []string
+}
+
+// capture saves the environment in wf's metadata when the flags ask for it.
+func (o captureOptions) capture(ctx context.Context, wf *workflows.Workflow, shell string) {
+	if o.CaptureContext || len(o.CaptureVersions) > 0 {
+		wf.Metadata = captureEnvironment(ctx, shell, o.CaptureVersions)
+	}
+}
+
+// captureEnvironment returns the metadata block saved with
+// --capture-context and --capture-versions: the OS, architecture and
+// shell, and the version of each tool. Tools that aren't installed or
+// don't report a version are left out with a warning.
+func captureEnvironment(ctx context.Context, shell string, tools []string) *workflows.Metadata {
+	meta := &workflows.Metadata{
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+		Shell:      shell,
+		CapturedAt: time.Now().UTC().Truncate(time.Second),
+	}
+	for _, tool := range tools {
+		version, err := toolVersion(ctx, tool)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: can't capture the %s version: %v\n", tool, err)
+			continue
+		}
+		if meta.Tools == nil {
+			meta.Tools = make(map[string]string)
+		}
+		meta.Tools[tool] = version
+	}
+	return meta
+}
+
+// toolVersion runs a tool to get its version, returning the first line
+// of its output.
+func toolVersion(ctx context.Context, tool string) (string, error) {
+	if _, err := exec.LookPath(tool); err != nil {
+		return "", fmt.Errorf("not installed")
+	}
+	args, ok := versionArgs[tool]
+	if !ok {
+		args = []string{"--version"}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, toolVersionTimeout)
+	defer cancel()
+	// Some tools, like java, print their version on stderr
+	output, err := exec.CommandContext(ctx, tool, args...).CombinedOutput()
+	if err != nil {
+		return "", err
+	}
+	version := firstLine(string(output))
+	if version == "" {
+		return "", fmt.Errorf("no version output")
+	}
+	return version, nil
+}
+
+// firstLine returns the first non-blank line of s, trimmed.
+func firstLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// formatEnvironment describes a metadata block as lines for svf view.
+func formatEnvironment(meta *workflows.Metadata) []string {
+	var lines []string
+	var env []string
+	if meta.OS != "" {
+		platform := meta.OS
+		if meta.Arch != "" {
+			platform += "/" + meta.Arch
+		}
+		env = append(env, platform)
+	}
+	if meta.Shell != "" {
+		env = append(env, meta.Shell)
+	}
+	if len(env) > 0 {
+		line := "Environment: " + strings.Join(env, ", ")
+		if !meta.CapturedAt.IsZero() {
+			line += fmt.Sprintf(" (captured %s)", meta.CapturedAt.Format("2006-01-02"))
+		}
+		lines = append(lines, line)
+	}
+	if len(meta.Tools) > 0 {
+		names := make([]string, 0, len(meta.Tools))
+		for name := range meta.Tools {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		lines = append(lines, "Tool versions:")
+		for _, name := range names {
+			lines = append(lines, fmt.Sprintf("  %s: %s", name, meta.Tools[name]))
+		}
+	}
+	return lines
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaptureEnvironment(t *testing.T) {
+	meta := captureEnvironment(context.Background(), "zsh", []string{"go", "svf-no-such-tool"})
+
+	assert.Equal(t, runtime.GOOS, meta.OS)
+	assert.Equal(t, runtime.GOARCH, meta.Arch)
+	assert.Equal(t, "zsh", meta.Shell)
+	assert.False(t, meta.CapturedAt.IsZero())
+	require.Contains(t, meta.Tools, "go")
+	assert.True(t, strings.HasPrefix(meta.Tools["go"], "go version"), meta.Tools["go"])
+	assert.NotContains(t, meta.Tools, "svf-no-such-tool")
+}
+
+func TestFirstLine(t *testing.T) {
+	assert.Equal(t, "Terraform v1.6.2", firstLine("\n  Terraform v1.6.2\non linux_amd64\n"))
+	assert.Equal(t, "", firstLine(" \n"))
+}
+
+func TestFormatEnvironment(t *testing.T) {
+	meta := &workflows.Metadata{
+		OS:         "linux",
+		Arch:       "amd64",
+		Shell:      "bash",
+		Tools:      map[string]string{"terraform": "Terraform v1.6.2", "kubectl": "Client Version: v1.29.0"},
+		CapturedAt: time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC),
+	}
+	assert.Equal(t, []string{
+		"Environment: linux/amd64, bash (captured 2026-10-16)",
+		"Tool versions:",
+		"  kubectl: Client Version: v1.29.0",
+		"  terraform: Terraform v1.6.2",
+	}, formatEnvironment(meta))
+
+	assert.Equal(t, []string{"Tool versions:", "  go: go1.24"},
+		formatEnvironment(&workflows.Metadata{Tools: map[string]string{"go": "go1.24"}}))
+}
@@ -0,0 +1,114 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	svferrors "github.com/chazuruo/svf/internal/errors"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/runlog"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+// Execute runs root and reports the error it returns, if any, on stderr
+// with a hint on what to do about it. It returns the process exit code,
+// which tells kinds of errors apart (see svferrors.ExitCode).
+func Execute(root *cobra.Command) int {
+	cmd, err := root.ExecuteC()
+	if err == nil {
+		return svferrors.ExitOK
+	}
+	// Cobra checks subcommands and required flags before any command runs
+	if msg := err.Error(); strings.HasPrefix(msg, "unknown command ") || strings.HasPrefix(msg, "required flag") {
+		err = usageError(cmd, err)
+	}
+	renderError(root.ErrOrStderr(), cmd, err)
+	return svferrors.ExitCode(err)
+}
+
+// markUsageErrors makes flag and argument errors of root and its
+// subcommands usage errors, reported with a pointer to the command's help
+// instead of the full usage.
+func markUsageErrors(root *cobra.Command) {
+	root.SilenceErrors = true
+	root.SilenceUsage = true
+	root.SetFlagErrorFunc(usageError)
+
+	var wrap func(cmd *cobra.Command)
+	wrap = func(cmd *cobra.Command) {
+		if args := cmd.Args; args != nil {
+			cmd.Args = func(cmd *cobra.Command, a []string) error {
+				if err := args(cmd, a); err != nil {
+					return usageError(cmd, err)
+				}
+				return nil
+			}
+		}
+		for _, sub := range cmd.Commands() {
+			wrap(sub)
+		}
+	}
+	wrap(root)
+}
+
+// usageError marks err, an error in how cmd was invoked, as a usage error.
+func usageError(cmd *cobra.Command, err error) error {
+	hint := fmt.Sprintf("Run '%s --help' for usage.", cmd.CommandPath())
+	return svferrors.WithExitCode(svferrors.WithHint(err, hint), svferrors.ExitUsage)
+}
+
+// renderError writes err, returned by cmd, to w as the user should see
+// it: Git's command lines and raw output are replaced by Git's own
+// explanation, and a hint follows when one applies.
+func renderError(w io.Writer, cmd *cobra.Command, err error) {
+	fmt.Fprintf(w, "Error: %s\n", errorMessage(err))
+	if hint := errorHint(cmd, err); hint != "" {
+		fmt.Fprintf(w, "Hint: %s\n", hint)
+	}
+}
+
+// errorMessage returns err's message with any Git command failure in it
+// reduced to the reason Git gave.
+func errorMessage(err error) string {
+	msg := err.Error()
+	var gitErr *gitrepo.GitError
+	if errors.As(err, &gitErr) {
+		msg = strings.Replace(msg, gitErr.Error(), gitErr.Reason(), 1)
+	}
+	return strings.TrimSpace(msg)
+}
+
+// errorHint returns what the user can do about err: the hint attached to
+// it, or else one for the kind of error it is.
+func errorHint(cmd *cobra.Command, err error) string {
+	if hint := svferrors.Hint(err); hint != "" {
+		return hint
+	}
+	name := cmd.Root().Name()
+
+	var aiErr *svferrors.AIProviderError
+	var authErr *svferrors.AuthError
+	var conflict *store.ConflictError
+	switch {
+	case errors.As(err, &aiErr) && aiErr.NotConfigured:
+		return "Set provider under [ai] in the config, or pass --provider."
+	case errors.As(err, &authErr) && authErr.Service == "git":
+		return "Check your Git credentials for the remote, e.g. with 'git fetch' in the workflow repo."
+	case errors.As(err, &authErr):
+		return "Check the API key of the AI provider (api_key_env under [ai] in the config)."
+	case errors.Is(err, store.ErrNotFound):
+		return fmt.Sprintf("Run '%s list' or '%s search <text>' to find a workflow.", name, name)
+	case errors.Is(err, runlog.ErrNotFound):
+		return "Use 'last' for the most recent run; runs are only kept on the machine they ran on."
+	case errors.As(err, &conflict):
+		return "Save again to merge the other changes into yours."
+	case svferrors.IsInvalid(err):
+		return "Fix the workflow file; see Workflow Format in the user guide."
+	}
+	return ""
+}
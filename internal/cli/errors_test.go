@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	svferrors "github.com/chazuruo/svf/internal/errors"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+// testRoot returns a root command like svf's with one subcommand that
+// returns err.
+func testRoot(err error) (*cobra.Command, *bytes.Buffer) {
+	root := &cobra.Command{Use: "svf"}
+	root.AddCommand(&cobra.Command{
+		Use:  "view <workflow-ref>",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error { return err },
+	})
+	markUsageErrors(root)
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	return root, &out
+}
+
+func TestExecute(t *testing.T) {
+	gitErr := &gitrepo.GitError{
+		Args:   []string{"push", "origin", "main"},
+		Err:    errors.New("exit status 128: To origin\nfatal: 'origin' does not appear to be a git repository\n"),
+		Output: "To origin\nfatal: 'origin' does not appear to be a git repository\n",
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		err      error
+		wantCode int
+		want     []string
+		notWant  []string
+	}{
+		{
+			name:     "success",
+			args:     []string{"view", "deploy"},
+			wantCode: svferrors.ExitOK,
+		},
+		{
+			name:     "missing argument",
+			args:     []string{"view"},
+			wantCode: svferrors.ExitUsage,
+			want:     []string{"Error: accepts 1 arg(s), received 0", "Hint: Run 'svf view --help' for usage."},
+			notWant:  []string{"Usage:"},
+		},
+		{
+			name:     "unknown flag",
+			args:     []string{"view", "deploy", "--nope"},
+			wantCode: svferrors.ExitUsage,
+			want:     []string{"Error: unknown flag: --nope", "Hint: Run 'svf view --help' for usage."},
+		},
+		{
+			name:     "unknown command",
+			args:     []string{"veiw"},
+			wantCode: svferrors.ExitUsage,
+			want:     []string{`Error: unknown command "veiw"`},
+		},
+		{
+			name:     "workflow not found",
+			args:     []string{"view", "deploy"},
+			err:      fmt.Errorf("%w: deploy", store.ErrNotFound),
+			wantCode: svferrors.ExitNotFound,
+			want:     []string{"Error: workflow not found: deploy", "Hint: Run 'svf list' or 'svf search <text>' to find a workflow."},
+		},
+		{
+			name:     "git failure",
+			args:     []string{"view", "deploy"},
+			err:      fmt.Errorf("failed to push: %w", gitErr),
+			wantCode: svferrors.ExitFailure,
+			want:     []string{"Error: failed to push: 'origin' does not appear to be a git repository"},
+			notWant:  []string{"git push", "exit status"},
+		},
+		{
+			name:     "git authentication",
+			args:     []string{"view", "deploy"},
+			err:      fmt.Errorf("failed to push: %w", &svferrors.AuthError{Service: "git", Err: gitErr}),
+			wantCode: svferrors.ExitAuth,
+			want:     []string{"Hint: Check your Git credentials"},
+		},
+		{
+			name:     "AI provider not configured",
+			args:     []string{"view", "deploy"},
+			err:      &svferrors.AIProviderError{NotConfigured: true},
+			wantCode: svferrors.ExitAINotConfigured,
+			want:     []string{"Error: AI provider not configured", "Hint: Set provider under [ai]"},
+		},
+		{
+			name:     "step failed",
+			args:     []string{"view", "deploy"},
+			err:      svferrors.WithExitCode(errors.New("workflow failed at step 2"), svferrors.ExitStepFailed),
+			wantCode: svferrors.ExitStepFailed,
+			want:     []string{"Error: workflow failed at step 2"},
+			notWant:  []string{"Hint:"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, out := testRoot(tt.err)
+			root.SetArgs(tt.args)
+			if code := Execute(root); code != tt.wantCode {
+				t.Errorf("Execute() = %d, want %d; output:\n%s", code, tt.wantCode, out)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output missing %q:\n%s", want, out)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(out.String(), notWant) {
+					t.Errorf("output contains %q:\n%s", notWant, out)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"github.com/chazuruo/svf/internal/ai"
+	"github.com/chazuruo/svf/internal/config"
+	svferrors "github.com/chazuruo/svf/internal/errors"
+	"github.com/chazuruo/svf/internal/explain"
+	"github.com/chazuruo/svf/internal/runlog"
+	"github.com/chazuruo/svf/internal/tui"
+)
+
+// ExplainOptions contains the options for the explain command.
+type ExplainOptions struct {
+	ConfigPath string
+	Provider   string
+	Model      string
+	APIKeyEnv  string
+	Offline    bool
+	Run        string
+	Save       bool
+}
+
+// NewExplainCommand creates the explain command.
+func NewExplainCommand() *cobra.Command {
+	opts := &ExplainOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "explain [command]",
+		Short: "Explain a command or a failed run",
+		Long: `Explain a shell command, or ask the AI why a recorded run failed.
+
+Forms:
+  svf explain "<command>"      Explain a command (rule-based with --offline)
+  svf explain --run <run-id>   Diagnose the failed step of a run
+
+Failed runs are recorded locally by 'svf run'. The run ID can be a full ID,
+a unique prefix, or "last" for the most recent run. The failed step's
+command, exit code and redacted output are sent to the AI provider, and the
+likely causes and next actions can be appended to the run record.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Run != "" {
+				return runExplainRun(opts)
+			}
+			if len(args) == 0 {
+				return fmt.Errorf("command or --run <run-id> required\nUsage: svf explain \"<command>\"")
+			}
+			return runExplainCommand(opts, args[0])
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ConfigPath, "config", "", "config file path")
+	cmd.Flags().StringVar(&opts.Provider, "provider", "", "AI provider (openai, ollama, etc.)")
+	cmd.Flags().StringVar(&opts.Model, "model", "", "Model name")
+	cmd.Flags().StringVar(&opts.APIKeyEnv, "api-key-env", "", "Environment variable for API key")
+	cmd.Flags().BoolVar(&opts.Offline, "offline", false, "rule-based explanation only (no AI)")
+	cmd.Flags().StringVar(&opts.Run, "run", "", "explain the failed step of a recorded run")
+	cmd.Flags().BoolVar(&opts.Save, "save", false, "append the findings to the run record without asking")
+
+	return cmd
+}
+
+// newExplainProvider creates the AI provider for explain, or nil when offline.
+func newExplainProvider(opts *ExplainOptions, cfg *config.Config) (ai.Provider, error) {
+	if opts.Offline {
+		return nil, nil
+	}
+	return ai.NewProvider(explainAIConfig(opts, cfg))
+}
+
+// explainAIConfig builds the AI config for explain.
+func explainAIConfig(opts *ExplainOptions, cfg *config.Config) *ai.Config {
+	return buildAIConfig(&AskOptions{
+		Provider:  opts.Provider,
+		Model:     opts.Model,
+		APIKeyEnv: opts.APIKeyEnv,
+	}, cfg)
+}
+
+func runExplainCommand(opts *ExplainOptions, command string) error {
+	ctx := context.Background()
+
+	// Load config
+	cfg, err := loadConfig(opts.ConfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Fall back to rule-based explanations if no provider is available
+	provider, err := newExplainProvider(opts, cfg)
+	if err != nil {
+		provider = nil
+	}
+
+	e := explain.NewExplainer(&explain.Options{Provider: provider, Offline: opts.Offline})
+	result := e.ExplainCommand(ctx, command)
+
+	risk := explain.ParseRiskLevel(result.Risk)
+	fmt.Printf("Command: %s\n", result.Command)
+	fmt.Printf("Risk: %s %s\n\n", risk.Icon(), risk)
+	fmt.Println(result.Explanation)
+	return nil
+}
+
+func runExplainRun(opts *ExplainOptions) error {
+	ctx := context.Background()
+
+	// Load config
+	cfg, err := loadConfig(opts.ConfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	runs, err := runlog.NewDefaultStore()
+	if err != nil {
+		return err
+	}
+
+	rec, err := runs.Load(opts.Run)
+	if err != nil {
+		return fmt.Errorf("failed to load run: %w", err)
+	}
+
+	failed := rec.FailedStep()
+	if failed == nil {
+		return fmt.Errorf("run %s has no failed step (status: %s)", rec.ID, rec.Status())
+	}
+
+	if opts.Offline {
+		return fmt.Errorf("explaining a failed run requires an AI provider (remove --offline)")
+	}
+
+	provider, err := newExplainProvider(opts, cfg)
+	if err != nil {
+		return &svferrors.AIProviderError{NotConfigured: true, Err: fmt.Errorf("failed to create AI provider: %w", err)}
+	}
+	if provider == nil {
+		return &svferrors.AIProviderError{NotConfigured: true}
+	}
+
+	output := failed.Output
+	if failed.Error != "" {
+		output = strings.TrimSpace(output + "\n" + failed.Error)
+	}
+
+	estimate := ai.NewEstimate(explainAIConfig(opts, cfg), ai.EstimateTokens(failed.Command+"\n"+output))
+	fmt.Fprintf(os.Stderr, "Estimated request: %s\n", estimate)
+
+	e := explain.NewExplainer(&explain.Options{Provider: provider})
+	findings, err := e.ExplainFailure(ctx, failed.Command, failed.ExitCode, output)
+	if err != nil {
+		return &svferrors.AIProviderError{Err: fmt.Errorf("failed to explain run: %w", err)}
+	}
+
+	title := fmt.Sprintf("Why did %q fail?", rec.WorkflowTitle)
+	subtitle := fmt.Sprintf("Step %d: %s (exit code %d)\n$ %s", failed.Index+1, failed.Name, failed.ExitCode, failed.Command)
+
+	save := opts.Save
+	switch GetInteractionMode(cfg) {
+	case ModeTUI:
+		model := tui.NewExplainViewModel(title, subtitle, findings, !opts.Save)
+		finalModel, err := tea.NewProgram(model, tea.WithAltScreen()).Run()
+		if err != nil {
+			return fmt.Errorf("failed to run TUI: %w", err)
+		}
+		save = save || finalModel.(tui.ExplainViewModel).DidSave()
+
+	case ModeLine:
+		fmt.Printf("%s\n%s\n\n%s\n\n", title, subtitle, findings)
+		if !save {
+			save, err = tui.NewStdioLinePrompter().Confirm("Append findings to the run record?", false)
+			if err != nil {
+				return err
+			}
+		}
+
+	default:
+		fmt.Printf("%s\n%s\n\n%s\n", title, subtitle, findings)
+	}
+
+	if !save {
+		return nil
+	}
+
+	if err := runs.AddFinding(rec.ID, runlog.Finding{
+		At:     time.Now(),
+		Source: "ai:" + provider.Name(),
+		Text:   findings,
+	}); err != nil {
+		return fmt.Errorf("failed to update run record: %w", err)
+	}
+
+	fmt.Printf("✓ Findings appended to run %s\n", rec.ID)
+	return nil
+}
@@ -4,6 +4,9 @@ package cli
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -34,12 +37,28 @@ func NewExportCommand() *cobra.Command {
 The workflow reference can be:
 - A slug (e.g., "my-workflow")
 - A path (e.g., "workflows/platform/chaz/my-workflow")
-- An ID (e.g., "wf_abc123")
+- An ID or a unique ID prefix (e.g., "wf_01HV3K8Q")
+
+References matching several workflows (e.g. the same slug under two
+identities) are rejected with the list of candidates.
 
 Supported formats:
 - md (default): Markdown
 - yaml: YAML format
 - json: JSON format
+- toml: TOML format
+- ansible: Ansible playbook with a shell task per step
+- taskfile: Taskfile.yml (https://taskfile.dev) with a task per step
+
+The yaml, json and toml formats contain the whole workflow and can be
+imported again with "svf edit --no-tui --file". Written to a file with
+--out, they bring the workflow's companion files (step scripts and files)
+along.
+
+The ansible and taskfile formats are a starting point for moving a runbook
+to configuration management. Placeholders become variables, and dangerous
+or confirmed steps ask before running. Workflows with companion scripts or
+files or with captured values can't be converted.
 
 Template locations (searched in order):
 1. .svf/templates/export.<format> (repo-specific)
@@ -49,6 +68,9 @@ Template locations (searched in order):
 Examples:
   svf export my-workflow                    # Export as Markdown to stdout
   svf export my-workflow --format json      # Export as JSON
+  svf export my-workflow --format toml --out workflow.toml
+  svf export my-workflow --format ansible --out playbook.yml
+  svf export my-workflow --format taskfile --out Taskfile.yml
   svf export my-workflow --out output.md    # Export to file
   svf export my-workflow --update-readme    # Update README.md
   svf export my-workflow --template custom.tmpl`,
@@ -59,7 +81,7 @@ Examples:
 	}
 
 	cmd.Flags().StringVar(&opts.ConfigPath, "config", "", "config file path")
-	cmd.Flags().StringVarP(&opts.Format, "format", "f", "md", "output format (md, yaml, json)")
+	cmd.Flags().StringVarP(&opts.Format, "format", "f", "md", "output format (md, yaml, json, toml, ansible, taskfile)")
 	cmd.Flags().StringVarP(&opts.Out, "out", "o", "-", "output path (default: stdout)")
 	cmd.Flags().BoolVarP(&opts.UpdateReadme, "update-readme", "u", false, "update README.md with exported content")
 	cmd.Flags().StringVarP(&opts.CustomTemplate, "template", "t", "", "custom template file")
@@ -89,7 +111,7 @@ func runExport(opts *ExportOptions, workflowRef string) error {
 	}
 
 	// Resolve workflow reference
-	ref, err := resolveWorkflowRef(ctx, str, workflowRef)
+	ref, err := resolveWorkflowRef(ctx, str, cfg, workflowRef)
 	if err != nil {
 		return err
 	}
@@ -102,8 +124,10 @@ func runExport(opts *ExportOptions, workflowRef string) error {
 
 	// Parse format
 	format := export.Format(opts.Format)
-	if format != export.FormatMarkdown && format != export.FormatYAML && format != export.FormatJSON {
-		return fmt.Errorf("invalid format: %s (must be md, yaml, or json)", opts.Format)
+	switch format {
+	case export.FormatMarkdown, export.FormatYAML, export.FormatJSON, export.FormatTOML, export.FormatAnsible, export.FormatTaskfile:
+	default:
+		return fmt.Errorf("invalid format: %s (must be md, yaml, json, toml, ansible, or taskfile)", opts.Format)
 	}
 
 	// Determine output path
@@ -119,6 +143,7 @@ func runExport(opts *ExportOptions, workflowRef string) error {
 		UpdateReadme:   opts.UpdateReadme,
 		CustomTemplate: opts.CustomTemplate,
 		RepoPath:       cfg.Repo.Path,
+		SourceDir:      filepath.Dir(ref.Path),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create exporter: %w", err)
@@ -142,6 +167,9 @@ func runExport(opts *ExportOptions, workflowRef string) error {
 	// Write output
 	if opts.Out == "-" || opts.Out == "" {
 		fmt.Print(output)
+		if files := wf.CompanionFiles(); len(files) > 0 && format != export.FormatMarkdown {
+			fmt.Fprintf(os.Stderr, "Warning: companion files are not included on stdout (use --out): %s\n", strings.Join(files, ", "))
+		}
 	} else {
 		fmt.Printf("Exported workflow to: %s\n", opts.Out)
 	}
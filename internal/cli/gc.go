@@ -0,0 +1,190 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/chazuruo/svf/internal/gc"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/lock"
+	"github.com/chazuruo/svf/internal/runlog"
+	"github.com/chazuruo/svf/internal/tui"
+)
+
+// GCOptions contains the options for the gc command.
+type GCOptions struct {
+	ConfigPath string
+	DraftAge   int
+	Yes        bool
+	DryRun     bool
+	NoCommit   bool
+}
+
+// NewGCCommand creates the gc command.
+func NewGCCommand() *cobra.Command {
+	opts := &GCOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "gc",
+		Short: "Clean up orphaned workflow directories and leftovers",
+		Long: `Find and remove leftovers in the workflow repository:
+
+- orphaned directories: directories under the workflow and shared roots
+  with no workflow file anywhere below them
+- unreferenced files: files in a workflow directory other than the
+  workflow, its README.md and tests.yaml, and its companion files
+- stale drafts: draft workflows unchanged for longer than --draft-age days,
+  going by their last commit, or file times for uncommitted files
+- orphaned runs: run records made in this repository for workflows that
+  no longer exist
+
+Each leftover is offered for removal in turn. Removals in the repository
+are committed together in a single commit.`,
+		Example: `  svf gc --dry-run          # List leftovers without removing anything
+  svf gc                    # Choose what to remove
+  svf gc --yes --draft-age 90`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runGC(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ConfigPath, "config", "", "config file path")
+	cmd.Flags().IntVar(&opts.DraftAge, "draft-age", 30, "days after which an unchanged draft is stale (0 to keep all drafts)")
+	cmd.Flags().BoolVarP(&opts.Yes, "yes", "y", false, "remove every leftover without asking")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "list leftovers without removing them")
+	cmd.Flags().BoolVar(&opts.NoCommit, "no-commit", false, "do not commit the removals")
+
+	return cmd
+}
+
+func runGC(opts *GCOptions) error {
+	ctx := context.Background()
+
+	// Load config
+	cfg, err := loadConfig(opts.ConfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Open repo
+	repo := gitrepo.New(cfg.Repo.Path)
+	if !repo.IsInitialized(ctx) {
+		return fmt.Errorf("repository not initialized. Run 'svf init' first")
+	}
+
+	if opts.DraftAge < 0 {
+		return fmt.Errorf("--draft-age must not be negative")
+	}
+
+	gcOpts := gc.Options{
+		RepoPath: cfg.Repo.Path,
+		Config:   cfg,
+		DraftAge: time.Duration(opts.DraftAge) * 24 * time.Hour,
+	}
+	if opts.DraftAge > 0 {
+		if gcOpts.Committed, err = repo.LastCommitTimes(ctx, cfg.Workflows.DraftRoot); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to read git history, aging drafts by modification time: %v\n", err)
+		}
+	}
+	if runs, err := runlog.NewDefaultStore(); err == nil {
+		gcOpts.Runs = runs
+	} else {
+		fmt.Fprintf(os.Stderr, "Warning: skipping run records: %v\n", err)
+	}
+
+	// Keep other svf processes from saving while files are removed
+	l, err := lock.Acquire(cfg.Repo.Path, lock.Options{Op: "gc"})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = l.Release() }()
+
+	items, err := gc.Find(gcOpts)
+	if err != nil {
+		return fmt.Errorf("failed to find leftovers: %w", err)
+	}
+	if len(items) == 0 {
+		fmt.Println("Nothing to clean up.")
+		return nil
+	}
+
+	fmt.Printf("Found %d leftover(s):\n", len(items))
+	for _, item := range items {
+		fmt.Printf("  %-20s %s (%s)\n", item.Kind, item.Path, item.Detail)
+	}
+	if opts.DryRun {
+		return nil
+	}
+
+	selected := items
+	if !opts.Yes {
+		if GetInteractionMode(cfg) == ModeNone {
+			return fmt.Errorf("use --yes to remove leftovers without prompting")
+		}
+		selected, err = chooseGCItems(tui.NewStdioLinePrompter(), items)
+		if err != nil {
+			if errors.Is(err, tui.ErrNoInput) {
+				fmt.Println("Canceled.")
+				return nil
+			}
+			return err
+		}
+	}
+	if len(selected) == 0 {
+		fmt.Println("Nothing removed.")
+		return nil
+	}
+
+	if err := gc.Remove(gcOpts, selected); err != nil {
+		return fmt.Errorf("failed to remove leftovers: %w", err)
+	}
+
+	inRepo := 0
+	for _, item := range selected {
+		if item.InRepo() {
+			inRepo++
+		}
+	}
+	if inRepo > 0 && !opts.NoCommit {
+		if err := repo.AddAll(ctx); err != nil {
+			return fmt.Errorf("failed to add files: %w", err)
+		}
+		if _, err := repo.CommitAll(ctx, fmt.Sprintf("Clean up %d orphaned workflow item(s)", inRepo)); err != nil {
+			return fmt.Errorf("failed to commit: %w", err)
+		}
+	}
+
+	fmt.Printf("\n✓ Removed %d leftover(s)\n", len(selected))
+	return nil
+}
+
+// chooseGCItems asks about each item and returns those to remove.
+func chooseGCItems(p *tui.LinePrompter, items []gc.Item) ([]gc.Item, error) {
+	var selected []gc.Item
+	for i, item := range items {
+		choice, err := p.Choose(fmt.Sprintf("\nRemove %s %s?", item.Kind, item.Path), []tui.Choice{
+			{Key: "y", Label: "remove"},
+			{Key: "n", Label: "keep"},
+			{Key: "a", Label: "remove all remaining"},
+			{Key: "q", Label: "stop"},
+		}, "n")
+		if err != nil {
+			return nil, err
+		}
+		switch choice {
+		case "y":
+			selected = append(selected, item)
+		case "a":
+			return append(selected, items[i:]...), nil
+		case "q":
+			return selected, nil
+		}
+	}
+	return selected, nil
+}
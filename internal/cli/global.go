@@ -2,8 +2,15 @@
 package cli
 
 import (
+	"fmt"
+	"os"
+	"strings"
 	"sync"
 
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/i18n"
+	"github.com/chazuruo/svf/internal/tui"
+	"github.com/chazuruo/svf/internal/tui/theme"
 	"github.com/spf13/cobra"
 )
 
@@ -12,7 +19,15 @@ var (
 	// This is set by the global --no-tui flag.
 	NoTUI bool
 
-	// noTUIMutex protects NoTUI for concurrent access.
+	// NoColor indicates that colored output should be disabled.
+	// This is set by the global --no-color flag.
+	NoColor bool
+
+	// AllowSecrets saves workflows that appear to contain raw secrets.
+	// This is set by the global --allow-secrets flag.
+	AllowSecrets bool
+
+	// noTUIMutex protects NoTUI and NoColor for concurrent access.
 	noTUIMutex sync.RWMutex
 )
 
@@ -20,6 +35,10 @@ var (
 func AddGlobalFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().BoolVar(&NoTUI, "no-tui", false,
 		"disable TUI/interactive mode; use plain text or JSON output")
+	cmd.PersistentFlags().BoolVar(&NoColor, "no-color", false,
+		"disable colored output (also honors NO_COLOR)")
+	cmd.PersistentFlags().BoolVar(&AllowSecrets, "allow-secrets", false,
+		"save workflows even if they appear to contain raw secrets")
 }
 
 // IsNoTUI returns true if TUI mode is disabled.
@@ -28,3 +47,100 @@ func IsNoTUI() bool {
 	defer noTUIMutex.RUnlock()
 	return NoTUI
 }
+
+// InteractionMode describes how a command interacts with the user.
+type InteractionMode int
+
+const (
+	// ModeTUI uses full-screen Bubble Tea models.
+	ModeTUI InteractionMode = iota
+	// ModeLine uses plain sequential prompts on stdin/stdout.
+	ModeLine
+	// ModeNone disables prompting entirely (--no-tui).
+	ModeNone
+)
+
+// GetInteractionMode picks the interaction mode for a command.
+// --no-tui disables interaction; tui.enabled = false or a non-terminal
+// stdin/stdout (or TERM=dumb) degrades to line mode.
+func GetInteractionMode(cfg *config.Config) InteractionMode {
+	if IsNoTUI() {
+		return ModeNone
+	}
+	if cfg != nil && !cfg.TUI.Enabled {
+		return ModeLine
+	}
+	if !tui.Available() {
+		return ModeLine
+	}
+	return ModeTUI
+}
+
+// IsNoColor returns true if colored output is disabled by flag or NO_COLOR.
+func IsNoColor() bool {
+	noTUIMutex.RLock()
+	defer noTUIMutex.RUnlock()
+	return NoColor || theme.NoColorEnv()
+}
+
+// configureUI selects the TUI theme and message locale from cfg, which is
+// nil if the config couldn't be loaded.
+func configureUI(cfg *config.Config) {
+	configureTheme(cfg)
+	configureLocale(cfg)
+}
+
+// configureTheme selects the TUI theme from the config's tui.theme,
+// NO_COLOR, and --no-color.
+func configureTheme(cfg *config.Config) {
+	name := ""
+	if cfg != nil {
+		name = cfg.TUI.Theme
+	}
+
+	t, ok := theme.Resolve(name, IsNoColor())
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Warning: unknown theme %q (available: %s), using %s\n",
+			name, strings.Join(theme.Names(), ", "), t.Name)
+	}
+	theme.Set(t)
+}
+
+// configureLocale selects the message locale from the config's tui.locale,
+// following the environment when it is "auto" or the config can't load.
+func configureLocale(cfg *config.Config) {
+	name := i18n.Auto
+	if cfg != nil {
+		name = cfg.TUI.Locale
+	}
+
+	locale, ok := i18n.Resolve(name)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Warning: unknown locale %q (available: %s), using %s\n",
+			name, strings.Join(i18n.Locales(), ", "), locale)
+	}
+	i18n.Set(locale)
+}
+
+// loadUIConfig loads the config honoring a --config flag if the command has one.
+// Errors are ignored here; the command itself reports them.
+func loadUIConfig(cmd *cobra.Command) *config.Config {
+	var path string
+	if f := cmd.Flags().Lookup("config"); f != nil {
+		path = f.Value.String()
+	}
+	cfg, err := loadConfig(path)
+	if err != nil {
+		return nil
+	}
+	return cfg
+}
+
+// loadConfig loads the config file at path, given with --config, or the
+// default config when path is empty.
+func loadConfig(path string) (*config.Config, error) {
+	if path != "" {
+		return config.Load(path)
+	}
+	return config.LoadWithDefaults()
+}
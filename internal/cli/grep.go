@@ -0,0 +1,302 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/tui"
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+// GrepOptions contains the options for the grep command.
+type GrepOptions struct {
+	ConfigPath   string
+	Replace      string
+	FixedStrings bool
+	IgnoreCase   bool
+	DryRun       bool
+	Yes          bool
+	Message      string
+}
+
+// NewGrepCommand creates the grep command.
+func NewGrepCommand() *cobra.Command {
+	opts := &GrepOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "grep <pattern>",
+		Short: "Search or replace across step commands",
+		Long: `Search the step commands of every workflow for a regular expression.
+
+With --replace, each match is shown for review and can be accepted or
+rejected. All accepted edits are saved in a single commit, or on a single
+feature branch when identity.mode is "pr". In the replacement, $1 or
+${name} refer to capture groups unless --fixed-strings is set.
+
+Use --yes to accept every match without review (required with --no-tui)
+or --dry-run to only show the changes.`,
+		Example: `  svf grep 'kubectl delete'
+  svf grep registry.old.example.com --replace registry.example.com -F
+  svf grep 'image=([^ ]+):latest' --replace 'image=$1:stable' --dry-run`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runGrep(opts, args[0], cmd.Flags().Changed("replace"))
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ConfigPath, "config", "", "config file path")
+	cmd.Flags().StringVar(&opts.Replace, "replace", "", "replace matches with this text")
+	cmd.Flags().BoolVarP(&opts.FixedStrings, "fixed-strings", "F", false, "treat the pattern and replacement as literal text")
+	cmd.Flags().BoolVarP(&opts.IgnoreCase, "ignore-case", "i", false, "match case-insensitively")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "show replacements without applying them")
+	cmd.Flags().BoolVarP(&opts.Yes, "yes", "y", false, "accept every replacement without review")
+	cmd.Flags().StringVarP(&opts.Message, "message", "m", "", "commit message for the replacements")
+
+	return cmd
+}
+
+// grepMatch is one occurrence of the pattern in a step command.
+type grepMatch struct {
+	// Path is the workflow.yaml path.
+	Path     string
+	Workflow *workflows.Workflow
+
+	// Doc is the workflow's document in a multi-document file, or 0.
+	Doc int
+
+	// Step is the 0-based step index.
+	Step int
+
+	// Start and End are the byte offsets of the match in the command.
+	Start, End int
+
+	// Replacement is the text the match is replaced with.
+	Replacement string
+}
+
+func runGrep(opts *GrepOptions, pattern string, replacing bool) error {
+	ctx := context.Background()
+
+	// Load config
+	var cfg *config.Config
+	var err error
+	if opts.ConfigPath != "" {
+		cfg, err = config.Load(opts.ConfigPath)
+	} else {
+		cfg, err = config.LoadWithDefaults()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if replacing && !opts.DryRun && cfg.ReadOnly {
+		return fmt.Errorf("'svf grep --replace' is disabled in read-only mode (readonly = true in the config); use --dry-run to preview the replacements")
+	}
+
+	// Open repo
+	repo := gitrepo.New(cfg.Repo.Path)
+	if !repo.IsInitialized(ctx) {
+		return fmt.Errorf("repository not initialized. Run 'svf init' first")
+	}
+
+	// Create store
+	str, err := store.New(repo, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+
+	re, err := compileGrepPattern(pattern, opts.FixedStrings, opts.IgnoreCase)
+	if err != nil {
+		return err
+	}
+
+	refs, err := str.List(ctx, store.Filter{})
+	if err != nil {
+		return fmt.Errorf("failed to list workflows: %w", err)
+	}
+	sort.Slice(refs, func(i, j int) bool { return refs[i].Path < refs[j].Path })
+
+	var matches []grepMatch
+	for _, ref := range refs {
+		wf, err := str.Load(ctx, ref)
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", ref.Path, err)
+		}
+		for _, m := range findGrepMatches(re, wf, ref.Path, opts.Replace, opts.FixedStrings) {
+			m.Doc = ref.Doc
+			matches = append(matches, m)
+		}
+	}
+
+	if len(matches) == 0 {
+		fmt.Println("No matches.")
+		return nil
+	}
+
+	if !replacing {
+		for _, m := range matches {
+			fmt.Printf("%s: %s\n", grepLocation(cfg, m), m.Workflow.Steps[m.Step].Command)
+		}
+		fmt.Printf("\n%d match(es) in %d workflow(s)\n", len(matches), countWorkflows(matches))
+		return nil
+	}
+
+	review := make([]tui.ReplaceMatch, len(matches))
+	for i, m := range matches {
+		command := m.Workflow.Steps[m.Step].Command
+		review[i] = tui.ReplaceMatch{
+			Location: grepLocation(cfg, m),
+			Before:   command,
+			After:    command[:m.Start] + m.Replacement + command[m.End:],
+			Accepted: opts.Yes,
+		}
+	}
+
+	if opts.DryRun {
+		for _, r := range review {
+			fmt.Printf("%s\n  - %s\n  + %s\n", r.Location, r.Before, r.After)
+		}
+		fmt.Printf("\n%d replacement(s) in %d workflow(s) (dry run)\n", len(matches), countWorkflows(matches))
+		return nil
+	}
+
+	if !opts.Yes {
+		var ok bool
+		switch GetInteractionMode(cfg) {
+		case ModeTUI:
+			review, ok, err = tui.RunReplaceReview(review)
+		case ModeLine:
+			review, ok, err = tui.ReviewReplacementsLine(review, tui.NewStdioLinePrompter())
+		default:
+			return fmt.Errorf("reviewing replacements requires interaction; use --yes to accept all or --dry-run")
+		}
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Replace canceled.")
+			return nil
+		}
+	}
+
+	var accepted []grepMatch
+	for i, r := range review {
+		if r.Accepted {
+			accepted = append(accepted, matches[i])
+		}
+	}
+	if len(accepted) == 0 {
+		fmt.Println("No replacements accepted.")
+		return nil
+	}
+
+	edits := applyGrepMatches(accepted)
+	message := opts.Message
+	if message == "" {
+		message = fmt.Sprintf("Replace %q with %q in %d workflow(s)", pattern, opts.Replace, len(edits))
+	}
+	if err := saveAllAndPublish(ctx, repo, str, cfg, edits, "replace-"+store.Slugify(pattern), message); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Replaced %d match(es) in %d workflow(s)\n", len(accepted), len(edits))
+	return nil
+}
+
+// compileGrepPattern compiles a grep pattern, quoting it for literal
+// matching with fixed set.
+func compileGrepPattern(pattern string, fixed, ignoreCase bool) (*regexp.Regexp, error) {
+	if fixed {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if ignoreCase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+	return re, nil
+}
+
+// findGrepMatches returns the matches of re in a workflow's step commands.
+// The replacement is expanded per match ($1, ${name}) unless fixed is set.
+// Empty matches are skipped.
+func findGrepMatches(re *regexp.Regexp, wf *workflows.Workflow, path, replace string, fixed bool) []grepMatch {
+	var matches []grepMatch
+	for i, step := range wf.Steps {
+		for _, loc := range re.FindAllStringSubmatchIndex(step.Command, -1) {
+			if loc[0] == loc[1] {
+				continue
+			}
+			replacement := replace
+			if !fixed {
+				replacement = string(re.ExpandString(nil, replace, step.Command, loc))
+			}
+			matches = append(matches, grepMatch{
+				Path:        path,
+				Workflow:    wf,
+				Step:        i,
+				Start:       loc[0],
+				End:         loc[1],
+				Replacement: replacement,
+			})
+		}
+	}
+	return matches
+}
+
+// applyGrepMatches replaces the given matches in their step commands and
+// returns one edit per changed workflow, in the order first seen. Matches
+// must be in the order findGrepMatches returns them.
+func applyGrepMatches(matches []grepMatch) []workflowEdit {
+	// Replace from the end of each command so earlier offsets stay valid
+	for i := len(matches) - 1; i >= 0; i-- {
+		m := matches[i]
+		step := &m.Workflow.Steps[m.Step]
+		step.Command = step.Command[:m.Start] + m.Replacement + step.Command[m.End:]
+	}
+
+	var edits []workflowEdit
+	seen := make(map[*workflows.Workflow]bool)
+	for _, m := range matches {
+		if !seen[m.Workflow] {
+			seen[m.Workflow] = true
+			edits = append(edits, workflowEdit{Workflow: m.Workflow, Path: m.Path, Doc: m.Doc})
+		}
+	}
+	return edits
+}
+
+// grepLocation describes where a match is, e.g.
+// "workflows/team/deploy/workflow.yaml step 2 (push)".
+func grepLocation(cfg *config.Config, m grepMatch) string {
+	rel, err := filepath.Rel(cfg.Repo.Path, m.Path)
+	if err != nil {
+		rel = m.Path
+	}
+	if m.Doc > 0 {
+		rel = fmt.Sprintf("%s#%d", rel, m.Doc)
+	}
+	location := fmt.Sprintf("%s step %d", rel, m.Step+1)
+	if name := m.Workflow.Steps[m.Step].Name; name != "" {
+		location += fmt.Sprintf(" (%s)", name)
+	}
+	return location
+}
+
+// countWorkflows returns the number of distinct workflows in matches.
+func countWorkflows(matches []grepMatch) int {
+	seen := make(map[*workflows.Workflow]bool)
+	for _, m := range matches {
+		seen[m.Workflow] = true
+	}
+	return len(seen)
+}
@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+func TestFindAndApplyGrepMatches(t *testing.T) {
+	wf := &workflows.Workflow{
+		Title: "Deploy",
+		Steps: []workflows.Step{
+			{Name: "pull", Command: "docker pull old.example.com/api && docker pull old.example.com/web"},
+			{Name: "list", Command: "kubectl get pods"},
+			{Name: "push", Command: "docker push OLD.example.com/api"},
+		},
+	}
+
+	re, err := compileGrepPattern("old.example.com/(\\w+)", false, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	matches := findGrepMatches(re, wf, "workflows/deploy/workflow.yaml", "new.example.com/$1", false)
+	if len(matches) != 3 {
+		t.Fatalf("findGrepMatches() = %d matches, want 3", len(matches))
+	}
+	if matches[1].Replacement != "new.example.com/web" {
+		t.Errorf("Replacement = %q, want new.example.com/web", matches[1].Replacement)
+	}
+
+	// Reject the second match
+	edits := applyGrepMatches([]grepMatch{matches[0], matches[2]})
+	if len(edits) != 1 || edits[0].Workflow != wf {
+		t.Fatalf("applyGrepMatches() = %+v, want one edit", edits)
+	}
+	if want := "docker pull new.example.com/api && docker pull old.example.com/web"; wf.Steps[0].Command != want {
+		t.Errorf("step 1 = %q, want %q", wf.Steps[0].Command, want)
+	}
+	if want := "docker push new.example.com/api"; wf.Steps[2].Command != want {
+		t.Errorf("step 3 = %q, want %q", wf.Steps[2].Command, want)
+	}
+}
+
+func TestFindGrepMatches_FixedStrings(t *testing.T) {
+	wf := &workflows.Workflow{Steps: []workflows.Step{{Command: "echo a.b $1 axb"}}}
+
+	re, err := compileGrepPattern("a.b", true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	matches := findGrepMatches(re, wf, "wf.yaml", "$1", true)
+	if len(matches) != 1 {
+		t.Fatalf("findGrepMatches() = %d matches, want 1", len(matches))
+	}
+	applyGrepMatches(matches)
+	if want := "echo $1 $1 axb"; wf.Steps[0].Command != want {
+		t.Errorf("command = %q, want %q", wf.Steps[0].Command, want)
+	}
+
+	if _, err := compileGrepPattern("(", false, false); err == nil {
+		t.Error("compileGrepPattern() with invalid pattern: expected error")
+	}
+}
+
+func TestSaveAllAndPublish_SingleCommit(t *testing.T) {
+	ctx := context.Background()
+	setGitIdentity(t)
+
+	cfg := config.DefaultConfig()
+	cfg.Repo.Path = t.TempDir()
+	cfg.Identity.Path = "team/test"
+	cfg.Identity.Mode = "direct"
+	repo := gitrepo.New(cfg.Repo.Path)
+	if err := repo.Init(ctx, gitrepo.InitOptions{}); err != nil {
+		t.Fatalf("failed to init repo: %v", err)
+	}
+	str, err := store.New(repo, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var edits []workflowEdit
+	for _, title := range []string{"Pull images", "Push images"} {
+		wf := &workflows.Workflow{
+			SchemaVersion: workflows.SchemaVersion,
+			Title:         title,
+			Steps:         []workflows.Step{{Command: "docker pull old.example.com/api"}},
+		}
+		ref, err := str.Save(ctx, wf, store.SaveOptions{Commit: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		wf.Steps[0].Command = "docker pull new.example.com/api"
+		edits = append(edits, workflowEdit{Workflow: wf, Path: ref.Path})
+	}
+
+	if err := saveAllAndPublish(ctx, repo, str, cfg, edits, "replace-old", "Replace registry"); err != nil {
+		t.Fatalf("saveAllAndPublish() error = %v", err)
+	}
+
+	out, err := exec.Command("git", "-C", cfg.Repo.Path, "show", "--stat", "--format=%s", "HEAD").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git show: %v\n%s", err, out)
+	}
+	if !strings.HasPrefix(string(out), "Replace registry\n") {
+		t.Errorf("HEAD subject = %q, want Replace registry", out)
+	}
+	for _, slug := range []string{"pull-images", "push-images"} {
+		if !strings.Contains(string(out), slug+"/workflow.yaml") {
+			t.Errorf("HEAD does not change %s:\n%s", slug, out)
+		}
+	}
+
+	for _, edit := range edits {
+		loaded, err := str.Load(ctx, store.WorkflowRef{Path: edit.Path})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if loaded.Steps[0].Command != "docker pull new.example.com/api" {
+			t.Errorf("%s command = %q", edit.Path, loaded.Steps[0].Command)
+		}
+	}
+}
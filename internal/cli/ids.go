@@ -0,0 +1,159 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/index"
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+// IDsAssignOptions contains the options for the ids assign command.
+type IDsAssignOptions struct {
+	ConfigPath string
+	DryRun     bool
+	NoCommit   bool
+}
+
+// NewIDsCommand creates the ids command.
+func NewIDsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ids",
+		Short: "Manage workflow IDs",
+		Long: `Manage the stable IDs that identify workflows.
+
+Workflows without an ID are identified by their path, which changes when
+they are renamed or moved. New workflows get an ID when saved; use
+'svf ids assign' to give existing workflows one.`,
+	}
+
+	cmd.AddCommand(newIDsAssignCommand())
+
+	return cmd
+}
+
+// newIDsAssignCommand creates the ids assign command.
+func newIDsAssignCommand() *cobra.Command {
+	opts := &IDsAssignOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "assign",
+		Short: "Assign IDs to workflows that lack them",
+		Long: `Assign a generated ID to every workflow without one, rewrite the
+workflow files in place, rebuild the search index, and commit the changes.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runIDsAssign(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ConfigPath, "config", "", "config file path")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "list the workflows that would get an ID")
+	cmd.Flags().BoolVar(&opts.NoCommit, "no-commit", false, "do not commit the changes")
+
+	return cmd
+}
+
+func runIDsAssign(opts *IDsAssignOptions) error {
+	ctx := context.Background()
+
+	// Load config
+	cfg, err := loadConfig(opts.ConfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Open repo
+	repo := gitrepo.New(cfg.Repo.Path)
+	if !repo.IsInitialized(ctx) {
+		return fmt.Errorf("repository not initialized. Run 'svf init' first")
+	}
+
+	// Create store
+	str, err := store.New(repo, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+
+	assigned, err := assignMissingIDs(ctx, str, cfg, opts.DryRun)
+	if err != nil {
+		return err
+	}
+
+	if assigned == 0 {
+		fmt.Println("All workflows already have IDs.")
+		return nil
+	}
+	if opts.DryRun {
+		fmt.Printf("\n%d workflow(s) would get an ID.\n", assigned)
+		return nil
+	}
+
+	// IDs are part of the index, so refresh it
+	if _, err := index.NewBuilder(cfg.Repo.Path, cfg).Rebuild(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to rebuild index: %v\n", err)
+	}
+
+	if !opts.NoCommit {
+		if err := repo.AddAll(ctx); err != nil {
+			return fmt.Errorf("failed to add files: %w", err)
+		}
+		if _, err := repo.CommitAll(ctx, fmt.Sprintf("Assign IDs to %d workflow(s)", assigned)); err != nil {
+			return fmt.Errorf("failed to commit: %w", err)
+		}
+	}
+
+	fmt.Printf("\n✓ Assigned IDs to %d workflow(s)\n", assigned)
+	return nil
+}
+
+// assignMissingIDs gives every workflow without an ID a new one, saving
+// it in place. It returns the number of workflows changed (or that would
+// be, for a dry run).
+func assignMissingIDs(ctx context.Context, str store.Store, cfg *config.Config, dryRun bool) (int, error) {
+	// Read every file: the index can't tell a missing ID from a made-up one
+	refs, err := str.List(ctx, store.Filter{Verify: true})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list workflows: %w", err)
+	}
+
+	assigned := 0
+	for _, ref := range refs {
+		if ref.ID != "" {
+			continue
+		}
+
+		rel, err := filepath.Rel(cfg.Repo.Path, ref.Path)
+		if err != nil {
+			rel = ref.Path
+		}
+
+		if dryRun {
+			fmt.Printf("  %s\n", rel)
+			assigned++
+			continue
+		}
+
+		wf, err := str.Load(ctx, ref)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping %s: %v\n", rel, err)
+			continue
+		}
+
+		wf.ID = workflows.NewID()
+		if _, err := str.Save(ctx, wf, store.SaveOptions{Path: ref.Path, Doc: ref.Doc, AllowSecrets: AllowSecrets}); err != nil {
+			return assigned, fmt.Errorf("failed to save %s: %w", rel, err)
+		}
+
+		fmt.Printf("✓ %s → %s\n", rel, wf.ID)
+		assigned++
+	}
+
+	return assigned, nil
+}
@@ -0,0 +1,220 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/importer"
+	"github.com/chazuruo/svf/internal/tui"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+// ImportOptions contains the options for the import command.
+type ImportOptions struct {
+	From     string
+	Title    string
+	Tags     string
+	NoCommit bool
+	NoEdit   bool
+	Raw      bool
+}
+
+// NewImportCommand creates the import command.
+func NewImportCommand() *cobra.Command {
+	opts := &ImportOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Create a workflow from an existing runbook",
+		Long: `Create a workflow from a runbook written in another format.
+
+From Markdown (--from markdown), each fenced code block becomes a step named
+after the heading above it, and the first level-1 heading and the text
+under it become the workflow's title and description:
+- sh, bash, zsh, shell and console blocks are imported as commands. In
+  transcripts, only the lines after a "$ " prompt are kept.
+- Blocks without a language (or marked text) may be commands or output.
+  You are asked about each one; without a terminal they are skipped.
+- Blocks in other languages (yaml, json, python...) are left out.
+
+The workflow is then opened in the editor for cleanup, e.g. to turn values
+into placeholders, before it is saved and committed like an edit.
+
+Examples:
+  svf import --from markdown runbook.md
+  svf import --from markdown docs/failover.md --title "Database failover"
+  svf import --from markdown runbook.md --raw      # Clean up the YAML in $EDITOR
+  svf --no-tui import --from markdown runbook.md   # Save without prompting`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runImport(opts, args[0])
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.From, "from", "markdown", "format of the runbook (markdown)")
+	cmd.Flags().StringVar(&opts.Title, "title", "", "workflow title (default: the runbook's first heading)")
+	cmd.Flags().StringVar(&opts.Tags, "tags", "", "workflow tags (comma-separated)")
+	cmd.Flags().BoolVar(&opts.NoCommit, "no-commit", false, "skip git commit after saving")
+	cmd.Flags().BoolVar(&opts.NoEdit, "no-edit", false, "save without opening the editor")
+	cmd.Flags().BoolVar(&opts.Raw, "raw", false, "clean up the workflow YAML in $EDITOR instead of the TUI editor")
+
+	return cmd
+}
+
+func runImport(opts *ImportOptions, path string) error {
+	ctx := context.Background()
+
+	if opts.From != "markdown" && opts.From != "md" {
+		return fmt.Errorf("unsupported --from %q (supported: markdown)", opts.From)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read runbook: %w", err)
+	}
+	rb, err := importer.ParseMarkdown(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	// Load config
+	cfg, err := config.LoadWithDefaults()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Open repo
+	repo := gitrepo.New(cfg.Repo.Path)
+	if !repo.IsInitialized(ctx) {
+		return fmt.Errorf("repository not initialized. Run 'svf init' first")
+	}
+
+	// Create store
+	str, err := store.New(repo, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+
+	var p *tui.LinePrompter
+	if GetInteractionMode(cfg) != ModeNone {
+		p = tui.NewStdioLinePrompter()
+	}
+
+	include, err := resolveBlocks(rb, p)
+	if err != nil {
+		return err
+	}
+	wf := rb.Workflow(func(b importer.Block) bool { return include[b.Line] })
+	if len(wf.Steps) == 0 {
+		return fmt.Errorf("no commands to import in %s", path)
+	}
+	if opts.Title != "" {
+		wf.Title = opts.Title
+	}
+	if wf.Title == "" {
+		wf.Title = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	wf.Tags = parseTags(opts.Tags)
+
+	fmt.Printf("Imported %d step(s) from %s\n", len(wf.Steps), path)
+
+	// Clean up in the editor before saving
+	if p != nil && !opts.NoEdit {
+		edited, err := editWorkflow(ctx, cfg, wf, opts.Raw, 0)
+		if err != nil {
+			return fmt.Errorf("failed to edit workflow: %w", err)
+		}
+		if edited == nil {
+			fmt.Println("Quit without saving.")
+			return nil
+		}
+		wf = edited
+	}
+
+	if err := wf.Validate(); err != nil {
+		return fmt.Errorf("workflow validation failed: %w", err)
+	}
+
+	ref, err := saveAndPublish(ctx, repo, str, cfg, wf, store.SaveOptions{Commit: !opts.NoCommit})
+	if err != nil {
+		return fmt.Errorf("failed to save workflow: %w", err)
+	}
+	fmt.Printf("Workflow saved: %s (id: %s)\n", ref.Slug, ref.ID)
+	return nil
+}
+
+// resolveBlocks decides which code blocks of rb become steps, keyed by
+// their line. Shell blocks are imported and blocks in other languages left
+// out; the user is asked about each ambiguous block, which are skipped when
+// p is nil. What was left out is reported on stderr.
+func resolveBlocks(rb *importer.Runbook, p *tui.LinePrompter) (map[int]bool, error) {
+	include := make(map[int]bool, len(rb.Blocks))
+	var skipped []string
+	otherLangs := map[string]bool{}
+	for _, b := range rb.Blocks {
+		switch b.Kind {
+		case importer.BlockCommand:
+			include[b.Line] = true
+		case importer.BlockOther:
+			otherLangs[b.Lang] = true
+		case importer.BlockAmbiguous:
+			if p == nil {
+				skipped = append(skipped, strconv.Itoa(b.Line))
+				continue
+			}
+			keep, err := askAboutBlock(p, b)
+			if err != nil {
+				return nil, err
+			}
+			include[b.Line] = keep
+		}
+	}
+
+	if len(skipped) > 0 {
+		fmt.Fprintf(os.Stderr, "Skipped code block(s) without a language at line %s; mark them ```sh to import them\n", strings.Join(skipped, ", "))
+	}
+	if len(otherLangs) > 0 {
+		langs := make([]string, 0, len(otherLangs))
+		for lang := range otherLangs {
+			langs = append(langs, lang)
+		}
+		sort.Strings(langs)
+		fmt.Fprintf(os.Stderr, "Left out %s code block(s)\n", strings.Join(langs, ", "))
+	}
+	return include, nil
+}
+
+// askAboutBlock shows an ambiguous block and asks whether it is a step.
+func askAboutBlock(p *tui.LinePrompter, b importer.Block) (bool, error) {
+	where := fmt.Sprintf("line %d", b.Line)
+	if b.Heading != "" {
+		where += fmt.Sprintf(", under %q", b.Heading)
+	}
+	p.Printf("\nCode block without a language (%s):\n", where)
+	lines := strings.Split(b.Code, "\n")
+	for i, line := range lines {
+		if i == 10 {
+			p.Printf("    ... (%d more lines)\n", len(lines)-i)
+			break
+		}
+		p.Printf("    %s\n", line)
+	}
+	answer, err := p.Choose("Import it?", []tui.Choice{
+		{Key: "s", Label: "as a step"},
+		{Key: "k", Label: "skip (output or example)"},
+	}, "s")
+	if err != nil {
+		return false, err
+	}
+	return answer == "s", nil
+}
@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/chazuruo/svf/internal/importer"
+	"github.com/chazuruo/svf/internal/tui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveBlocks(t *testing.T) {
+	rb := &importer.Runbook{Blocks: []importer.Block{
+		{Line: 1, Kind: importer.BlockCommand, Code: "make deploy"},
+		{Line: 5, Kind: importer.BlockAmbiguous, Code: "make check"},
+		{Line: 9, Kind: importer.BlockAmbiguous, Code: "ok"},
+		{Line: 13, Kind: importer.BlockOther, Lang: "yaml", Code: "a: b"},
+	}}
+
+	// Without a prompter, ambiguous blocks are skipped
+	include, err := resolveBlocks(rb, nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[int]bool{1: true}, include)
+
+	p := tui.NewLinePrompter(strings.NewReader("s\nk\n"), io.Discard)
+	include, err = resolveBlocks(rb, p)
+	require.NoError(t, err)
+	assert.True(t, include[1])
+	assert.True(t, include[5])
+	assert.False(t, include[9])
+	assert.False(t, include[13])
+}
@@ -0,0 +1,84 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/index"
+)
+
+// IndexOptions contains the options for the index command.
+type IndexOptions struct {
+	ConfigPath string
+	Watch      bool
+}
+
+// NewIndexCommand creates the index command.
+func NewIndexCommand() *cobra.Command {
+	opts := &IndexOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "index",
+		Short: "Rebuild the search index",
+		Long: `Rebuild the search index from the workflow files in the repository.
+
+With --watch, svf keeps running and updates the index as workflow files are
+created, edited or removed, so search results stay fresh while you edit
+YAML directly. Press Ctrl+C to stop.`,
+		Example: `  svf index
+  svf index --watch`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runIndex(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ConfigPath, "config", "", "config file path")
+	cmd.Flags().BoolVar(&opts.Watch, "watch", false, "keep the index updated as workflow files change")
+
+	return cmd
+}
+
+func runIndex(opts *IndexOptions) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Load config
+	cfg, err := loadConfig(opts.ConfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Open repo
+	repo := gitrepo.New(cfg.Repo.Path)
+	if !repo.IsInitialized(ctx) {
+		return fmt.Errorf("repository not initialized. Run 'svf init' first")
+	}
+
+	builder := index.NewBuilder(cfg.Repo.Path, cfg)
+
+	if !opts.Watch {
+		idx, err := builder.Rebuild()
+		if err != nil {
+			return fmt.Errorf("failed to rebuild index: %w", err)
+		}
+		fmt.Printf("✓ Indexed %d workflow(s)\n", len(idx.Workflows))
+		return nil
+	}
+
+	fmt.Printf("Watching %s for changes (Ctrl+C to stop)\n", cfg.Repo.Path)
+	err = builder.Watch(ctx, index.WatchOptions{
+		OnUpdate: func(idx *index.Index, paths []string) {
+			fmt.Printf("✓ Updated index (%d workflow(s)) after changes to %d path(s)\n", len(idx.Workflows), len(paths))
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to watch workflows: %w", err)
+	}
+	return nil
+}
@@ -27,6 +27,7 @@ type InitOptions struct {
 	AuthorEmail string
 	SignCommits bool
 	NoCommit    bool
+	Bootstrap   bool
 }
 
 // NewInitCommand creates the init command.
@@ -44,6 +45,11 @@ The init command guides you through setting up your svf configuration:
 - Configure git author details
 - Choose write mode (direct or PR-based)
 
+When the repository has no commits yet, --bootstrap (offered in the
+wizard) scaffolds the recommended layout: the workflow, shared, drafts and
+.svf/templates directories, a top-level README, a CODEOWNERS stub and the
+index merge driver, committed as the initial commit.
+
 Use --no-tui with flags for scripted setup.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runInit(opts)
@@ -60,6 +66,7 @@ Use --no-tui with flags for scripted setup.`,
 	cmd.Flags().StringVar(&opts.AuthorEmail, "author-email", "", "git author email")
 	cmd.Flags().BoolVar(&opts.SignCommits, "sign", false, "sign commits")
 	cmd.Flags().BoolVar(&opts.NoCommit, "no-commit", false, "skip git commit after saving")
+	cmd.Flags().BoolVar(&opts.Bootstrap, "bootstrap", false, "scaffold and commit the recommended layout in a new repository")
 
 	return cmd
 }
@@ -90,92 +97,107 @@ func runInitInteractive(opts *InitOptions) error {
 
 	cfg := config.DefaultConfig()
 
-	// Step 1: Repo source
-	if err := huh.NewForm(
-		huh.NewGroup(
-			huh.NewSelect[string]().
-				Title("Repository source").
-				Options(
-					huh.NewOption("Clone from remote URL", "remote"),
-					huh.NewOption("Use existing local repository", "local"),
-				).
-				Value(&repoSource),
-		),
-	).Run(); err != nil {
-		return fmt.Errorf("form error: %w", err)
-	}
-
-	// Step 2: Repository details
-	repoGroup := []huh.Field{
-		huh.NewInput().
-			Title("Repository path").
-			Description("Local path where the repo will be/is located").
-			Value(&localPath).Placeholder(cfg.Repo.Path),
-	}
+	// Steps 1-5 are repeated until the repository inputs pass the
+	// preflight checks, or the user continues anyway
+	for {
+		// Step 1: Repo source
+		if err := huh.NewForm(
+			huh.NewGroup(
+				huh.NewSelect[string]().
+					Title("Repository source").
+					Options(
+						huh.NewOption("Clone from remote URL", "remote"),
+						huh.NewOption("Use existing local repository", "local"),
+					).
+					Value(&repoSource),
+			),
+		).Run(); err != nil {
+			return fmt.Errorf("form error: %w", err)
+		}
 
-	if repoSource == "remote" {
-		repoGroup = append([]huh.Field{
+		// Step 2: Repository details
+		repoGroup := []huh.Field{
 			huh.NewInput().
-				Title("Remote URL").
-				Description("Git remote URL (HTTPS or SSH)").
-				Value(&remoteURL).Placeholder("https://github.com/user/workflows.git"),
-		}, repoGroup...)
-	}
+				Title("Repository path").
+				Description("Local path where the repo will be/is located").
+				Value(&localPath).Placeholder(cfg.Repo.Path),
+		}
 
-	if err := huh.NewForm(
-		huh.NewGroup(repoGroup...),
-	).Run(); err != nil {
-		return fmt.Errorf("form error: %w", err)
-	}
+		if repoSource == "remote" {
+			repoGroup = append([]huh.Field{
+				huh.NewInput().
+					Title("Remote URL").
+					Description("Git remote URL (HTTPS or SSH)").
+					Value(&remoteURL).Placeholder("https://github.com/user/workflows.git"),
+			}, repoGroup...)
+		}
 
-	// Set defaults from input
-	if localPath == "" {
-		localPath = cfg.Repo.Path
-	}
+		if err := huh.NewForm(
+			huh.NewGroup(repoGroup...),
+		).Run(); err != nil {
+			return fmt.Errorf("form error: %w", err)
+		}
 
-	// Step 3: Identity path
-	if err := huh.NewForm(
-		huh.NewGroup(
-			huh.NewInput().
-				Title("Identity path").
-				Description("Your path in the repository (e.g., 'chaz' or 'platform/chaz')").
-				Value(&identityPath).Placeholder("username"),
-		),
-	).Run(); err != nil {
-		return fmt.Errorf("form error: %w", err)
-	}
+		// Set defaults from input
+		if localPath == "" {
+			localPath = cfg.Repo.Path
+		}
 
-	// Step 4: Mode
-	if err := huh.NewForm(
-		huh.NewGroup(
-			huh.NewSelect[string]().
-				Title("Write mode").
-				Description("How should changes be committed?").
-				Options(
-					huh.NewOption("Direct - commit directly to main branch", "direct"),
-					huh.NewOption("PR - create feature branches and PRs", "pr"),
-				).
-				Value(&mode),
-		),
-	).Run(); err != nil {
-		return fmt.Errorf("form error: %w", err)
-	}
+		// Step 3: Identity path
+		if err := askIdentityPath(&identityPath); err != nil {
+			return err
+		}
 
-	// Step 5: Git author details
-	if err := huh.NewForm(
-		huh.NewGroup(
-			huh.NewInput().
-				Title("Author name").
-				Value(&authorName).Placeholder(cfg.Git.AuthorName),
-			huh.NewInput().
-				Title("Author email").
-				Value(&authorEmail).Placeholder(cfg.Git.AuthorEmail),
-			huh.NewConfirm().
-				Title("Sign commits?").
-				Value(&signCommits),
-		),
-	).Run(); err != nil {
-		return fmt.Errorf("form error: %w", err)
+		// Step 4: Mode
+		if err := huh.NewForm(
+			huh.NewGroup(
+				huh.NewSelect[string]().
+					Title("Write mode").
+					Description("How should changes be committed?").
+					Options(
+						huh.NewOption("Direct - commit directly to main branch", "direct"),
+						huh.NewOption("PR - create feature branches and PRs", "pr"),
+					).
+					Value(&mode),
+			),
+		).Run(); err != nil {
+			return fmt.Errorf("form error: %w", err)
+		}
+
+		// Step 5: Git author details
+		if err := huh.NewForm(
+			huh.NewGroup(
+				huh.NewInput().
+					Title("Author name").
+					Value(&authorName).Placeholder(cfg.Git.AuthorName),
+				huh.NewInput().
+					Title("Author email").
+					Value(&authorEmail).Placeholder(cfg.Git.AuthorEmail),
+				huh.NewConfirm().
+					Title("Sign commits?").
+					Value(&signCommits),
+			),
+		).Run(); err != nil {
+			return fmt.Errorf("form error: %w", err)
+		}
+
+		if repoSource != "remote" {
+			remoteURL = ""
+		}
+		issues := preflightRepo(context.Background(), remoteURL, localPath)
+		if len(issues) == 0 {
+			break
+		}
+		choice, err := askPreflight(issues)
+		if err != nil {
+			return err
+		}
+		if choice == "abort" {
+			return errInitAborted
+		}
+		if choice == "continue" {
+			break
+		}
 	}
 
 	// Step 6: Clone or setup repo
@@ -190,8 +212,35 @@ func runInitInteractive(opts *InitOptions) error {
 		}
 	}
 
-	// Build config
-	finalCfg := buildConfig(cfg, localPath, identityPath, mode, branch, authorName, authorEmail, signCommits)
+	// Build config, asking for another identity path while the chosen one
+	// belongs to someone else
+	var finalCfg *config.Config
+	for {
+		finalCfg = buildConfig(cfg, localPath, identityPath, mode, branch, authorName, authorEmail, signCommits)
+		issues := preflightIdentity(context.Background(), finalCfg, authorEmail)
+		if err := finalCfg.Validate(); err != nil {
+			issues = append(issues, preflightIssue{
+				Problem: fmt.Sprintf("invalid configuration: %v", err),
+				Fix:     "go back and fix the identity path",
+			})
+		}
+		if len(issues) == 0 {
+			break
+		}
+		choice, err := askPreflight(issues)
+		if err != nil {
+			return err
+		}
+		if choice == "abort" {
+			return errInitAborted
+		}
+		if choice == "continue" {
+			break
+		}
+		if err := askIdentityPath(&identityPath); err != nil {
+			return err
+		}
+	}
 
 	// Validate config
 	if err := finalCfg.Validate(); err != nil {
@@ -203,6 +252,34 @@ func runInitInteractive(opts *InitOptions) error {
 		return err
 	}
 
+	if err := installIndexMergeDriver(context.Background(), finalCfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to install index merge driver: %v\n", err)
+	}
+
+	// Offer to scaffold a new repository
+	if repoIsEmpty(context.Background(), gitrepo.New(localPath)) {
+		bootstrap := opts.Bootstrap
+		if !bootstrap {
+			if err := huh.NewForm(
+				huh.NewGroup(
+					huh.NewConfirm().
+						Title("Bootstrap the repository?").
+						Description("Add a README, a CODEOWNERS stub and the recommended folders as the initial commit").
+						Value(&bootstrap),
+				),
+			).Run(); err != nil {
+				return fmt.Errorf("form error: %w", err)
+			}
+		}
+		if bootstrap {
+			if err := bootstrapRepo(context.Background(), finalCfg, opts.NoCommit); err != nil {
+				return fmt.Errorf("failed to bootstrap repository: %w", err)
+			}
+		}
+	} else if opts.Bootstrap {
+		fmt.Fprintln(os.Stderr, "Warning: repository already has commits; skipping --bootstrap")
+	}
+
 	// Write config
 	if err := writeConfigSpinner(finalCfg); err != nil {
 		return err
@@ -226,6 +303,21 @@ func runInitInteractive(opts *InitOptions) error {
 	return nil
 }
 
+// askIdentityPath asks for the user's identity path in the repository.
+func askIdentityPath(identityPath *string) error {
+	if err := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Identity path").
+				Description("Your path in the repository (e.g., 'chaz' or 'platform/chaz')").
+				Value(identityPath).Placeholder("username"),
+		),
+	).Run(); err != nil {
+		return fmt.Errorf("form error: %w", err)
+	}
+	return nil
+}
+
 // runInitNonInteractive runs init in non-TUI mode using flags.
 func runInitNonInteractive(opts *InitOptions) error {
 	cfg := config.DefaultConfig()
@@ -261,6 +353,12 @@ func runInitNonInteractive(opts *InitOptions) error {
 		cfg.Git.SignCommits = true
 	}
 
+	// Check the inputs before touching anything
+	if issues := preflightRepo(context.Background(), opts.Remote, cfg.Repo.Path); len(issues) > 0 {
+		printPreflightIssues(issues)
+		return errInitAborted
+	}
+
 	// Clone from remote if specified
 	if opts.Remote != "" {
 		if err := gitrepo.Clone(opts.Remote, cfg.Repo.Path, cfg.Repo.Branch); err != nil {
@@ -278,12 +376,32 @@ func runInitNonInteractive(opts *InitOptions) error {
 	if err := cfg.Validate(); err != nil {
 		return fmt.Errorf("config validation failed: %w", err)
 	}
+	if issues := preflightIdentity(context.Background(), cfg, opts.AuthorEmail); len(issues) > 0 {
+		for _, issue := range issues {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n  → %s\n", issue.Problem, issue.Fix)
+		}
+	}
 
 	// Create folder structure
 	if err := createFolderStructure(cfg.Repo.Path, cfg); err != nil {
 		return fmt.Errorf("failed to create folder structure: %w", err)
 	}
 
+	if err := installIndexMergeDriver(context.Background(), cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to install index merge driver: %v\n", err)
+	}
+
+	// Scaffold a new repository
+	if opts.Bootstrap {
+		if repoIsEmpty(context.Background(), gitrepo.New(cfg.Repo.Path)) {
+			if err := bootstrapRepo(context.Background(), cfg, opts.NoCommit); err != nil {
+				return fmt.Errorf("failed to bootstrap repository: %w", err)
+			}
+		} else {
+			fmt.Fprintln(os.Stderr, "Warning: repository already has commits; skipping --bootstrap")
+		}
+	}
+
 	// Write config
 	configPath := getConfigPath(opts.ConfigPath)
 	configDir := filepath.Dir(configPath)
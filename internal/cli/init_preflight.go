@@ -0,0 +1,136 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/huh"
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+)
+
+// preflightTimeout bounds the check that a remote is reachable.
+const preflightTimeout = 30 * time.Second
+
+// preflightIssue is a problem with the init inputs, found before the
+// config is written.
+type preflightIssue struct {
+	Problem string
+	Fix     string
+}
+
+// preflightRepo checks the repository inputs: that the remote can be
+// reached, and that the path it is cloned to, or the local repository,
+// is usable.
+func preflightRepo(ctx context.Context, remoteURL, localPath string) []preflightIssue {
+	var issues []preflightIssue
+
+	if remoteURL != "" {
+		fmt.Printf("Checking %s...\n", remoteURL)
+		ctx, cancel := context.WithTimeout(ctx, preflightTimeout)
+		defer cancel()
+		if err := gitrepo.CheckRemote(ctx, remoteURL); err != nil {
+			issues = append(issues, preflightIssue{
+				Problem: fmt.Sprintf("remote %s is not reachable: %v", remoteURL, err),
+				Fix:     "check the URL for typos and that your SSH key or credential helper can access it",
+			})
+		}
+	}
+
+	info, err := os.Stat(localPath)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		issues = append(issues, preflightIssue{
+			Problem: fmt.Sprintf("cannot access %s: %v", localPath, err),
+			Fix:     "choose another repository path",
+		})
+	case !info.IsDir():
+		issues = append(issues, preflightIssue{
+			Problem: fmt.Sprintf("%s is a file, not a directory", localPath),
+			Fix:     "choose another repository path",
+		})
+	case remoteURL != "":
+		if entries, err := os.ReadDir(localPath); err == nil && len(entries) > 0 {
+			issues = append(issues, preflightIssue{
+				Problem: fmt.Sprintf("%s already exists and is not empty, so the remote can't be cloned there", localPath),
+				Fix:     "choose another repository path, or use the existing repository as a local repository",
+			})
+		}
+	}
+	return issues
+}
+
+// preflightIdentity checks that the identity's directory in the repository
+// isn't another user's: when it already has commits, one of them must be
+// by authorEmail, or the repository's configured user.email when empty.
+// Without an email to compare, the check is skipped.
+func preflightIdentity(ctx context.Context, cfg *config.Config, authorEmail string) []preflightIssue {
+	rel := filepath.Join(cfg.Workflows.Root, cfg.Identity.Path)
+	if _, err := os.Stat(filepath.Join(cfg.Repo.Path, rel)); err != nil {
+		return nil
+	}
+	repo := gitrepo.New(cfg.Repo.Path)
+	authors, err := repo.PathAuthors(ctx, rel)
+	if err != nil || len(authors) == 0 {
+		return nil
+	}
+
+	if authorEmail == "" {
+		authorEmail, _ = repo.GetConfig(ctx, "user.email")
+	}
+	if authorEmail == "" || slices.ContainsFunc(authors, func(a string) bool { return strings.EqualFold(a, authorEmail) }) {
+		return nil
+	}
+
+	owners := authors
+	if len(owners) > 3 {
+		owners = append(owners[:3:3], "...")
+	}
+	return []preflightIssue{{
+		Problem: fmt.Sprintf("identity path %s already holds workflows committed by %s", rel, strings.Join(owners, ", ")),
+		Fix:     "choose your own identity path, e.g. team/yourname, or continue if the directory is yours",
+	}}
+}
+
+// printPreflightIssues prints the problems found and how to fix them.
+func printPreflightIssues(issues []preflightIssue) {
+	fmt.Println("\nPreflight checks found problems:")
+	for _, issue := range issues {
+		fmt.Printf("  ✗ %s\n    → %s\n", issue.Problem, issue.Fix)
+	}
+}
+
+// askPreflight shows the problems found and asks how to go on: "back" to
+// fix the inputs, "continue" anyway or "abort".
+func askPreflight(issues []preflightIssue) (string, error) {
+	printPreflightIssues(issues)
+
+	choice := "back"
+	if err := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("How do you want to proceed?").
+				Options(
+					huh.NewOption("Go back and fix the inputs", "back"),
+					huh.NewOption("Continue anyway", "continue"),
+					huh.NewOption("Abort", "abort"),
+				).
+				Value(&choice),
+		),
+	).Run(); err != nil {
+		return "", fmt.Errorf("form error: %w", err)
+	}
+	return choice, nil
+}
+
+// errInitAborted is returned when the user aborts init after a failed
+// preflight check. Nothing has been written at that point.
+var errInitAborted = errors.New("init aborted; no configuration was written")
@@ -5,6 +5,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/chazuruo/svf/internal/config"
@@ -100,3 +101,136 @@ func TestInitNonInteractive_ExistingRepo(t *testing.T) {
 		t.Errorf("repo.IsInitialized() = false after init, want true")
 	}
 }
+
+// TestInitNonInteractive_Bootstrap verifies that --bootstrap scaffolds a
+// new repository and commits it as the initial commit.
+func TestInitNonInteractive_Bootstrap(t *testing.T) {
+	tmpDir := t.TempDir()
+	repoPath := filepath.Join(tmpDir, "new-repo")
+	t.Setenv("GIT_AUTHOR_NAME", "Test User")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test User")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	opts := &InitOptions{
+		ConfigPath: filepath.Join(tmpDir, "config.toml"),
+		Local:      repoPath,
+		Identity:   "platform/testuser",
+		Mode:       "direct",
+		Bootstrap:  true,
+	}
+	if err := runInitNonInteractive(opts); err != nil {
+		t.Fatalf("runInitNonInteractive() error = %v", err)
+	}
+
+	for _, path := range []string{
+		"README.md",
+		"CODEOWNERS",
+		".gitattributes",
+		"workflows/platform/testuser/.gitkeep",
+		"shared/.gitkeep",
+		"drafts/.gitkeep",
+		".svf/templates/.gitkeep",
+	} {
+		if _, err := os.Stat(filepath.Join(repoPath, path)); err != nil {
+			t.Errorf("expected %s: %v", path, err)
+		}
+	}
+
+	repo := gitrepo.New(repoPath)
+	ctx := context.Background()
+	if repoIsEmpty(ctx, repo) {
+		t.Fatal("expected the bootstrap to be committed")
+	}
+	status, err := repo.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if status.Dirty {
+		t.Errorf("expected a clean working tree after bootstrapping, got %+v", status)
+	}
+
+	// A repository with commits is left alone
+	if err := os.Remove(filepath.Join(repoPath, "CODEOWNERS")); err != nil {
+		t.Fatal(err)
+	}
+	if err := runInitNonInteractive(opts); err != nil {
+		t.Fatalf("runInitNonInteractive() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(repoPath, "CODEOWNERS")); !os.IsNotExist(err) {
+		t.Error("expected no bootstrap in a repository with commits")
+	}
+}
+
+func TestPreflightRepo(t *testing.T) {
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+
+	remote := filepath.Join(tmpDir, "remote.git")
+	if err := os.MkdirAll(remote, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := gitrepo.New(remote).Init(ctx, gitrepo.InitOptions{Bare: true}); err != nil {
+		t.Fatalf("failed to create remote: %v", err)
+	}
+	if issues := preflightRepo(ctx, remote, filepath.Join(tmpDir, "clone")); len(issues) != 0 {
+		t.Errorf("expected no issues, got %+v", issues)
+	}
+
+	issues := preflightRepo(ctx, filepath.Join(tmpDir, "missing.git"), filepath.Join(tmpDir, "clone"))
+	if len(issues) != 1 || !strings.Contains(issues[0].Problem, "not reachable") {
+		t.Errorf("expected an unreachable remote, got %+v", issues)
+	}
+
+	// Cloning into a non-empty directory fails
+	if issues := preflightRepo(ctx, remote, tmpDir); len(issues) != 1 {
+		t.Errorf("expected a non-empty target, got %+v", issues)
+	}
+	// A local repository may exist already
+	if issues := preflightRepo(ctx, "", tmpDir); len(issues) != 0 {
+		t.Errorf("expected no issues for a local repository, got %+v", issues)
+	}
+}
+
+func TestPreflightIdentity(t *testing.T) {
+	ctx := context.Background()
+	repoPath := t.TempDir()
+	t.Setenv("GIT_AUTHOR_NAME", "Other User")
+	t.Setenv("GIT_AUTHOR_EMAIL", "other@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Other User")
+	t.Setenv("GIT_COMMITTER_EMAIL", "other@example.com")
+
+	repo := gitrepo.New(repoPath)
+	if err := repo.Init(ctx, gitrepo.InitOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(repoPath, "workflows", "platform", "other")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "workflow.yaml"), []byte("title: T\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.AddAll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.CommitAll(ctx, "Add workflow"); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Repo.Path = repoPath
+	cfg.Identity.Path = "platform/other"
+
+	issues := preflightIdentity(ctx, cfg, "me@example.com")
+	if len(issues) != 1 || !strings.Contains(issues[0].Problem, "other@example.com") {
+		t.Errorf("expected a collision, got %+v", issues)
+	}
+	if issues := preflightIdentity(ctx, cfg, "Other@example.com"); len(issues) != 0 {
+		t.Errorf("expected no issue for the directory's author, got %+v", issues)
+	}
+	cfg.Identity.Path = "platform/me"
+	if issues := preflightIdentity(ctx, cfg, "me@example.com"); len(issues) != 0 {
+		t.Errorf("expected no issue for a new identity path, got %+v", issues)
+	}
+}
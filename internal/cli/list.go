@@ -4,6 +4,7 @@ package cli
 import (
 	"context"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/rodaine/table"
 	"github.com/chazuruo/svf/internal/config"
 	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/index"
 	"github.com/chazuruo/svf/internal/workflows"
 	"github.com/chazuruo/svf/internal/workflows/store"
 )
@@ -22,6 +24,13 @@ type ListOptions struct {
 	Shared     bool
 	Tags       []string
 	Format     string
+	Sort       string
+	Verify     bool
+	Stale      bool
+	StaleDays  int
+
+	// IncludeDrafts lists drafts and templates too.
+	IncludeDrafts bool
 }
 
 // NewListCommand creates the list command.
@@ -34,7 +43,31 @@ func NewListCommand() *cobra.Command {
 		Long: `List all available workflows.
 
 Supports filtering by owner (mine/shared) and tags.
-Multiple output formats: table (default), json, plain.`,
+Multiple output formats: table (default), json, plain.
+
+--include-drafts lists drafts and templates too, marked [draft] and
+[template].
+
+--sort orders the list by title, updated (newest first), created (newest
+first), last-run (most recently run by you first) or run-count (most run
+by you first). last-run and run-count come from your local run history.
+
+Workflows are listed from the search index without reading their files,
+which keeps listing fast in large repositories. --verify reads every
+workflow file instead and warns about anything the index has missed.
+
+--stale lists only workflows that may be out of date, with why:
+  - neither run by you nor committed in --stale-days days (default 90)
+  - running commands that aren't installed on this machine
+  - owned by identities no longer in the repository's CODEOWNERS file
+An owner is in CODEOWNERS when a handle matches the last part of its
+identity path (@alice for platform/alice) or a rule names its directory.
+Workflows without an owners field are owned by the identity they are
+filed under.`,
+		Example: `  svf list --mine --sort last-run
+  svf list --tag k8s --sort run-count
+  svf list --verify
+  svf list --stale --stale-days 180 --format json`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runList(opts)
 		},
@@ -45,6 +78,11 @@ Multiple output formats: table (default), json, plain.`,
 	cmd.Flags().BoolVar(&opts.Shared, "shared", false, "only show shared workflows")
 	cmd.Flags().StringSliceVar(&opts.Tags, "tag", nil, "filter by tag (repeatable)")
 	cmd.Flags().StringVar(&opts.Format, "format", "table", "output format: table, json, plain")
+	cmd.Flags().StringVar(&opts.Sort, "sort", "", sortFlagUsage)
+	cmd.Flags().BoolVar(&opts.Verify, "verify", false, "read workflow files instead of trusting the search index")
+	cmd.Flags().BoolVar(&opts.Stale, "stale", false, "only list workflows that may be out of date, with why")
+	cmd.Flags().IntVar(&opts.StaleDays, "stale-days", defaultStaleDays, "with --stale, days without a run or change before a workflow is stale")
+	cmd.Flags().BoolVar(&opts.IncludeDrafts, "include-drafts", false, "include drafts and templates")
 
 	return cmd
 }
@@ -52,6 +90,18 @@ Multiple output formats: table (default), json, plain.`,
 func runList(opts *ListOptions) error {
 	ctx := context.Background()
 
+	if opts.Stale && opts.StaleDays <= 0 {
+		return fmt.Errorf("--stale-days must be at least 1")
+	}
+
+	var sorter *workflowSorter
+	if opts.Sort != "" {
+		var err error
+		if sorter, err = newWorkflowSorter(opts.Sort); err != nil {
+			return err
+		}
+	}
+
 	// Load config
 	var cfg *config.Config
 	var err error
@@ -84,6 +134,12 @@ func runList(opts *ListOptions) error {
 	if len(opts.Tags) > 0 {
 		filter.Tags = opts.Tags
 	}
+	filter.Verify = opts.Verify
+	filter.IncludeDrafts = opts.IncludeDrafts
+
+	if opts.Verify {
+		warnIndexProblems(cfg)
+	}
 
 	// List workflows
 	refs, err := str.List(ctx, filter)
@@ -91,12 +147,16 @@ func runList(opts *ListOptions) error {
 		return fmt.Errorf("failed to list workflows: %w", err)
 	}
 
-	// Load workflows to get title and tags (for display)
+	// Title and tags come from the index; only --verify and --stale, which
+	// checks the steps and owners, load workflows
 	var workflowInfos []workflowInfo
 	for _, ref := range refs {
-		wf, loadErr := str.Load(ctx, ref)
-		if loadErr != nil {
-			continue // Skip workflows we can't load
+		wf := &workflows.Workflow{ID: ref.ID, Title: ref.Title, Tags: ref.Tags}
+		if opts.Verify || opts.Stale {
+			var loadErr error
+			if wf, loadErr = str.Load(ctx, ref); loadErr != nil {
+				continue // Skip workflows we can't load
+			}
 		}
 		workflowInfos = append(workflowInfos, workflowInfo{
 			Ref:       ref,
@@ -104,6 +164,29 @@ func runList(opts *ListOptions) error {
 		})
 	}
 
+	if sorter != nil {
+		sorter.sort(workflowInfos, func(i int) sortItem {
+			info := workflowInfos[i]
+			return sortItem{ID: info.Ref.ID, Title: info.Workflow.Title, Updated: info.Ref.UpdatedAt}
+		})
+	}
+	ranks := loadPinRanks()
+	pinnedFirst(workflowInfos, ranks, func(i int) string { return workflowInfos[i].Ref.ID })
+	for i := range workflowInfos {
+		_, workflowInfos[i].Pinned = ranks[workflowInfos[i].Ref.ID]
+	}
+
+	if opts.Stale {
+		check := newStaleCheck(ctx, cfg, opts.StaleDays)
+		var stale []staleWorkflow
+		for _, info := range workflowInfos {
+			if s := check.check(info); len(s.Reasons) > 0 {
+				stale = append(stale, s)
+			}
+		}
+		return printStale(stale, opts.Format)
+	}
+
 	// Output
 	switch opts.Format {
 	case "json":
@@ -119,10 +202,38 @@ func runList(opts *ListOptions) error {
 	return nil
 }
 
+// warnIndexProblems warns about workflow files the search index is out of
+// date with.
+func warnIndexProblems(cfg *config.Config) {
+	problems, err := index.NewBuilder(cfg.Repo.Path, cfg).Verify()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to verify index: %v\n", err)
+		return
+	}
+	if len(problems) == 0 {
+		return
+	}
+	for _, p := range problems {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", p)
+	}
+	fmt.Fprintf(os.Stderr, "The search index is out of date. Run 'svf index' to rebuild it.\n")
+}
+
 // workflowInfo combines a WorkflowRef with its Workflow for display.
 type workflowInfo struct {
 	Ref      store.WorkflowRef
 	Workflow *workflows.Workflow
+	Pinned   bool
+}
+
+// title returns the workflow's title, marked if it is pinned, a draft or
+// a template.
+func (info workflowInfo) title() string {
+	title := index.KindBadge(info.Ref.Kind) + info.Workflow.Title
+	if info.Pinned {
+		return pinMarker + title
+	}
+	return title
 }
 
 // printListTable prints workflows in table format.
@@ -136,7 +247,7 @@ func printListTable(workflows []workflowInfo) {
 	for _, info := range workflows {
 		tags := strings.Join(info.Workflow.Tags, ", ")
 		updated := info.Ref.UpdatedAt.Format("2006-01-02")
-		tbl.AddRow(info.Ref.ID, info.Workflow.Title, tags, updated)
+		tbl.AddRow(info.Ref.ID, info.title(), tags, updated)
 	}
 	tbl.Print()
 }
@@ -153,7 +264,7 @@ func printListPlain(workflows []workflowInfo) {
 		if len(info.Workflow.Tags) > 0 {
 			tags = fmt.Sprintf(" [%s]", strings.Join(info.Workflow.Tags, ", "))
 		}
-		fmt.Printf("%s: %s%s\n", info.Ref.ID, info.Workflow.Title, tags)
+		fmt.Printf("%s: %s%s\n", info.Ref.ID, info.title(), tags)
 	}
 }
 
@@ -168,8 +279,12 @@ func printListJSON(workflows []workflowInfo) {
 		if len(info.Workflow.Tags) > 0 {
 			tags = fmt.Sprintf(`["%s"]`, strings.Join(info.Workflow.Tags, `", "`))
 		}
-		fmt.Printf(`{"id":"%s","title":"%s","tags":%s,"updated_at":"%s"}`,
-			info.Ref.ID, info.Workflow.Title, tags, info.Ref.UpdatedAt.Format(time.RFC3339))
+		kind := info.Ref.Kind
+		if kind == "" {
+			kind = index.KindWorkflow
+		}
+		fmt.Printf(`{"id":"%s","title":"%s","kind":"%s","tags":%s,"updated_at":"%s","pinned":%t}`,
+			info.Ref.ID, info.Workflow.Title, kind, tags, info.Ref.UpdatedAt.Format(time.RFC3339), info.Pinned)
 	}
 	fmt.Println("]")
 }
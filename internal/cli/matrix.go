@@ -0,0 +1,433 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chazuruo/svf/internal/config"
+	svferrors "github.com/chazuruo/svf/internal/errors"
+	"github.com/chazuruo/svf/internal/placeholders"
+	runnerpkg "github.com/chazuruo/svf/internal/runner"
+	"github.com/chazuruo/svf/internal/runpath"
+	"github.com/chazuruo/svf/internal/tui"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// parseMatrixFlags parses --matrix values of the form key=v1,v2.
+func parseMatrixFlags(flags []string) (map[string][]string, error) {
+	matrix := make(map[string][]string)
+	for _, flag := range flags {
+		key, list, ok := strings.Cut(flag, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --matrix %q: use key=value1,value2", flag)
+		}
+		var values []string
+		for _, v := range strings.Split(list, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+		if len(values) == 0 {
+			return nil, fmt.Errorf("invalid --matrix %q: no values", flag)
+		}
+		matrix[key] = values
+	}
+	return matrix, nil
+}
+
+// resolveMatrix returns the matrix to run: the workflow's matrix overridden
+// by --matrix flags and --stdin-placeholder values read from stdin, without
+// keys pinned with --param.
+func resolveMatrix(wf *workflows.Workflow, opts *RunOptions, stdin io.Reader) (map[string][]string, error) {
+	flags, err := parseMatrixFlags(opts.Matrix)
+	if err != nil {
+		return nil, err
+	}
+	if name := opts.StdinPlaceholder; name != "" {
+		if _, ok := opts.Params[name]; ok {
+			return nil, fmt.Errorf("<%s> can't be set with both --param and --stdin-placeholder", name)
+		}
+		if !slices.Contains(placeholders.CollectFromSteps(wf.Steps), name) {
+			return nil, fmt.Errorf("%s doesn't use <%s>", wf.Title, name)
+		}
+		values, err := readStdinValues(stdin)
+		if err != nil {
+			return nil, err
+		}
+		flags[name] = values
+	}
+
+	matrix := make(map[string][]string)
+	for key, values := range wf.Matrix {
+		matrix[key] = values
+	}
+	for key, values := range flags {
+		matrix[key] = values
+	}
+	for key := range opts.Params {
+		delete(matrix, key)
+	}
+	return matrix, nil
+}
+
+// readStdinValues reads one value per line, skipping blank lines and
+// lines starting with '#'.
+func readStdinValues(r io.Reader) ([]string, error) {
+	if f, ok := r.(*os.File); ok && tui.IsTerminal(f) {
+		return nil, fmt.Errorf("--stdin-placeholder reads values from a pipe, e.g. cat hosts.txt | svf run ...")
+	}
+
+	var values []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		values = append(values, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read stdin: %w", err)
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("no values on stdin")
+	}
+	return values, nil
+}
+
+// matrixCombo is one combination of a matrix run.
+type matrixCombo struct {
+	Label   string
+	Params  map[string]string
+	Results []runnerpkg.StepResult
+	Started time.Time
+	Row     tui.MatrixResult
+	Ran     bool
+}
+
+// runMatrixWorkflow runs a workflow once per matrix combination, with a
+// dashboard in the TUI or a line per combination otherwise, then prints a
+// summary. Combinations run unattended like 'svf run --yes'.
+func runMatrixWorkflow(ctx context.Context, wf *workflows.Workflow, matrix map[string][]string, opts *RunOptions, cfg *config.Config) error {
+	if opts.SendTo != "" {
+		return fmt.Errorf("--matrix can't be combined with --send-to")
+	}
+
+	for i := range wf.Steps {
+		wf.ApplyDefaults(&wf.Steps[i])
+	}
+
+	combos, err := matrixCombos(wf, matrix, opts.Params)
+	if err != nil {
+		return err
+	}
+
+	if opts.DryRun {
+		return printMatrixDryRun(os.Stdout, wf, combos)
+	}
+
+	interactive := canPrompt(opts, cfg)
+	if err := confirmMatrixDangers(wf, combos, cfg, interactive, opts.Yes, tui.NewStdioLinePrompter()); err != nil {
+		return err
+	}
+
+	run := func(ctx context.Context, i int) tui.MatrixResult {
+		c := combos[i]
+		c.Started = time.Now()
+		c.Ran = true
+		c.Results, c.Row = runMatrixCombo(ctx, wf, c.Params, cfg)
+		return c.Row
+	}
+
+	if interactive && GetInteractionMode(cfg) == ModeTUI {
+		labels := make([]string, len(combos))
+		for i, c := range combos {
+			labels[i] = c.Label
+		}
+		if _, err := tea.NewProgram(tui.NewMatrixModel(wf.Title, labels, run)).Run(); err != nil {
+			return fmt.Errorf("failed to run TUI: %w", err)
+		}
+	} else {
+		for i, c := range combos {
+			fmt.Printf("[%d/%d] %s\n", i+1, len(combos), c.Label)
+			run(ctx, i)
+			fmt.Printf("  %s %s\n", matrixRow(c).Status.Icon(), matrixRow(c).Detail())
+		}
+	}
+
+	// Runs are recorded afterwards so warnings don't garble the dashboard
+	for _, c := range combos {
+		if c.Ran {
+			comboWf := *wf
+			comboWf.Title = fmt.Sprintf("%s [%s]", wf.Title, c.Label)
+			recordRun(cfg, &comboWf, c.Results, c.Started, c.Row.Success, c.Row.Canceled)
+		}
+	}
+
+	return printMatrixSummary(os.Stdout, combos)
+}
+
+// matrixCombos builds the combinations and checks that each has a value
+// for every placeholder, so nothing runs if one can't.
+func matrixCombos(wf *workflows.Workflow, matrix map[string][]string, given map[string]string) ([]*matrixCombo, error) {
+	info := placeholders.ExtractWithMetadata(wf)
+
+	var combos []*matrixCombo
+	for _, values := range workflows.MatrixCombinations(matrix) {
+		params := make(map[string]string)
+		for name, ph := range info {
+			if ph.Default != "" {
+				params[name] = ph.Default
+			}
+		}
+		for k, v := range given {
+			params[k] = v
+		}
+		for k, v := range values {
+			params[k] = v
+		}
+
+		var missing []string
+		for name, ph := range info {
+			value, ok := params[name]
+			if !ok {
+				missing = append(missing, name)
+				continue
+			}
+			if err := placeholders.Validate(value, ph.Validate); err != nil {
+				return nil, fmt.Errorf("%s: <%s>: %w", workflows.MatrixLabel(values), name, err)
+			}
+		}
+		if len(missing) > 0 {
+			sort.Strings(missing)
+			return nil, svferrors.WithExitCode(fmt.Errorf("missing placeholder values (use --param or --matrix to provide): <%s>", strings.Join(missing, ">, <")), svferrors.ExitMissingParam)
+		}
+
+		combos = append(combos, &matrixCombo{Label: workflows.MatrixLabel(values), Params: params})
+	}
+	return combos, nil
+}
+
+// matrixPreview substitutes a combination's parameters, showing captured
+// placeholders as-is.
+func matrixPreview(wf *workflows.Workflow, step workflows.Step, params map[string]string) (string, error) {
+	preview := make(map[string]string, len(params))
+	for k, v := range params {
+		preview[k] = v
+	}
+	for _, s := range wf.Steps {
+		for name := range s.Capture {
+			if _, ok := preview[name]; !ok {
+				preview[name] = "<" + name + ">"
+			}
+		}
+	}
+	return placeholders.Substitute(step.Command, preview)
+}
+
+// printMatrixDryRun prints each combination's commands.
+func printMatrixDryRun(w io.Writer, wf *workflows.Workflow, combos []*matrixCombo) error {
+	for i, c := range combos {
+		fmt.Fprintf(w, "[%d/%d] %s\n", i+1, len(combos), c.Label)
+		for j, step := range wf.Steps {
+			cmd, err := matrixPreview(wf, step, c.Params)
+			if err != nil {
+				return fmt.Errorf("step %d: %w", j+1, err)
+			}
+			fmt.Fprintf(w, "  Step %d/%d: %s\n    Would execute: %s\n", j+1, len(wf.Steps), step.Name, cmd)
+		}
+	}
+	return nil
+}
+
+// confirmMatrixDangers asks once before a matrix runs dangerous commands,
+// since combinations run unattended. Without a prompt they only run with
+// --yes, which warns instead.
+func confirmMatrixDangers(wf *workflows.Workflow, combos []*matrixCombo, cfg *config.Config, interactive, yes bool, p *tui.LinePrompter) error {
+	if !cfg.Runner.DangerousCommandWarnings {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var warnings []string
+	warn := func(label, cmd string) {
+		if danger := runnerpkg.CheckDangerous(cmd); danger != nil {
+			warning := fmt.Sprintf("%s: %s", label, danger.Warning())
+			if !seen[warning] {
+				seen[warning] = true
+				warnings = append(warnings, warning)
+			}
+		}
+	}
+	for _, c := range combos {
+		for i, step := range wf.Steps {
+			cmd, err := matrixPreview(wf, step, c.Params)
+			if err != nil {
+				return fmt.Errorf("step %d: %w", i+1, err)
+			}
+			warn(fmt.Sprintf("step %d", i+1), cmd)
+		}
+		// Cleanup steps run unattended after a failed step; those using a
+		// placeholder without a value are skipped then
+		for i, step := range wf.Cleanup {
+			if cmd, err := matrixPreview(wf, step, c.Params); err == nil {
+				warn(fmt.Sprintf("cleanup step %d", i+1), cmd)
+			}
+		}
+	}
+	if len(warnings) == 0 {
+		return nil
+	}
+
+	if !interactive {
+		for _, w := range warnings {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+		}
+		if !yes {
+			return fmt.Errorf("refusing to run dangerous commands unattended; use --yes to run them")
+		}
+		return nil
+	}
+	for _, w := range warnings {
+		p.Printf("Warning: %s\n", w)
+	}
+	ok, err := p.Confirm(fmt.Sprintf("Run %d combinations with dangerous commands?", len(combos)), false)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errRunCanceled
+	}
+	return nil
+}
+
+// runMatrixCombo runs every step of one combination without output,
+// stopping at the first failure, after which the cleanup steps run.
+func runMatrixCombo(ctx context.Context, wf *workflows.Workflow, given map[string]string, cfg *config.Config) (results []runnerpkg.StepResult, row tui.MatrixResult) {
+	started := time.Now()
+	params := make(map[string]string, len(given))
+	for k, v := range given {
+		params[k] = v
+	}
+	defer func() {
+		if !row.Success {
+			runpath.Cleanup(ctx, cfg, wf, results, params, true, false, io.Discard)
+		}
+	}()
+
+	results = make([]runnerpkg.StepResult, len(wf.Steps))
+	row = tui.MatrixResult{Success: true}
+	for i, step := range wf.Steps {
+		if !step.RunsOn(runtime.GOOS, runtime.GOARCH) {
+			results[i] = runnerpkg.PlatformSkip(i)
+			continue
+		}
+		cmd, err := placeholders.Substitute(step.Command, params)
+		if err != nil {
+			row = tui.MatrixResult{FailedStep: i, ExitCode: 21, Err: fmt.Errorf("step %d: %w", i+1, err)}
+			break
+		}
+
+		result := runnerpkg.Exec(ctx, runnerpkg.ExecConfig{
+			Command: cmd,
+			Shell:   step.Shell,
+			CWD:     runnerpkg.ResolveCWD(step.CWD, wf.Defaults.CWD, cfg.Repo.Path),
+			Env:     step.Env,
+			Timeout: time.Duration(cfg.Runner.StepTimeout) * time.Second,
+		})
+		captured := runnerpkg.ApplyCaptures(&step, &result)
+		for k, v := range captured {
+			params[k] = v
+		}
+		results[i] = runnerpkg.StepResult{
+			Step:         i,
+			Success:      result.Success,
+			ExitCode:     result.ExitCode,
+			Output:       result.Output,
+			Duration:     result.Duration,
+			Error:        result.Error,
+			Captured:     captured,
+			StartedAt:    result.StartedAt,
+			Confirmation: result.Confirmation,
+		}
+
+		if result.ExitCode == 13 && ctx.Err() != nil {
+			row = tui.MatrixResult{Canceled: true, FailedStep: i, ExitCode: 13}
+			break
+		}
+		if !result.Success && !step.ContinueOnError {
+			row = tui.MatrixResult{FailedStep: i, ExitCode: result.ExitCode, Err: result.Error}
+			break
+		}
+	}
+	row.Duration = time.Since(started)
+	return results, row
+}
+
+// matrixRow returns the dashboard row for a combination.
+func matrixRow(c *matrixCombo) tui.MatrixRow {
+	row := tui.MatrixRow{Label: c.Label, Result: c.Row, Status: tui.MatrixSkipped}
+	if c.Ran {
+		row.Status = tui.MatrixFailed
+		if c.Row.Success {
+			row.Status = tui.MatrixSucceeded
+		}
+	}
+	return row
+}
+
+// printMatrixSummary prints each combination's result and the tail of
+// failed output, returning an error if any combination didn't succeed.
+func printMatrixSummary(w io.Writer, combos []*matrixCombo) error {
+	width := 0
+	for _, c := range combos {
+		width = max(width, len(c.Label))
+	}
+
+	failed, canceled := 0, false
+	fmt.Fprintf(w, "\nMatrix summary:\n")
+	for _, c := range combos {
+		row := matrixRow(c)
+		fmt.Fprintf(w, "  %s %-*s  %s\n", row.Status.Icon(), width, c.Label, row.Detail())
+		if row.Status == tui.MatrixSucceeded {
+			continue
+		}
+		failed++
+		canceled = canceled || c.Row.Canceled || !c.Ran
+		if c.Ran && !c.Row.Canceled && c.Row.FailedStep < len(c.Results) {
+			for _, line := range tailLines(c.Results[c.Row.FailedStep].Output, 5) {
+				fmt.Fprintf(w, "      %s\n", line)
+			}
+		}
+	}
+
+	if failed == 0 {
+		fmt.Fprintf(w, "\n✓ All %d combinations succeeded\n", len(combos))
+		return nil
+	}
+	if canceled {
+		return fmt.Errorf("matrix %w: %d of %d combinations did not succeed", svferrors.ErrCanceled, failed, len(combos))
+	}
+	return svferrors.WithExitCode(fmt.Errorf("matrix failed: %d of %d combinations failed", failed, len(combos)), svferrors.ExitStepFailed)
+}
+
+// tailLines returns the last n non-empty lines of s.
+func tailLines(s string, n int) []string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) == 1 && lines[0] == "" {
+		return nil
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
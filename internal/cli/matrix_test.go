@@ -0,0 +1,187 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/chazuruo/svf/internal/config"
+	runnerpkg "github.com/chazuruo/svf/internal/runner"
+	"github.com/chazuruo/svf/internal/runpath"
+	"github.com/chazuruo/svf/internal/tui"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+func TestParseMatrixFlags(t *testing.T) {
+	got, err := parseMatrixFlags([]string{"region=us-east-1, eu-west-1", "env=prod"})
+	if err != nil {
+		t.Fatalf("parseMatrixFlags() error = %v", err)
+	}
+	want := map[string][]string{"region": {"us-east-1", "eu-west-1"}, "env": {"prod"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseMatrixFlags() = %v, want %v", got, want)
+	}
+
+	for _, bad := range []string{"region", "=a", "region=,"} {
+		if _, err := parseMatrixFlags([]string{bad}); err == nil {
+			t.Errorf("parseMatrixFlags(%q) expected error", bad)
+		}
+	}
+}
+
+func TestResolveMatrix(t *testing.T) {
+	wf := &workflows.Workflow{Matrix: map[string][]string{
+		"region": {"us-east-1", "eu-west-1"},
+		"env":    {"staging", "prod"},
+	}}
+	opts := &RunOptions{
+		Matrix: []string{"region=ap-south-1"},
+		Params: map[string]string{"env": "prod"},
+	}
+
+	got, err := resolveMatrix(wf, opts, nil)
+	if err != nil {
+		t.Fatalf("resolveMatrix() error = %v", err)
+	}
+	want := map[string][]string{"region": {"ap-south-1"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("resolveMatrix() = %v, want %v", got, want)
+	}
+}
+
+func TestResolveMatrix_Stdin(t *testing.T) {
+	wf := &workflows.Workflow{Title: "Drain node", Steps: []workflows.Step{{Command: "kubectl drain <host>"}}}
+	stdin := "node-1\n\n# cordoned already\n  node-2  \n"
+
+	got, err := resolveMatrix(wf, &RunOptions{StdinPlaceholder: "host"}, strings.NewReader(stdin))
+	if err != nil {
+		t.Fatalf("resolveMatrix() error = %v", err)
+	}
+	want := map[string][]string{"host": {"node-1", "node-2"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("resolveMatrix() = %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		name    string
+		opts    *RunOptions
+		stdin   string
+		wantErr string
+	}{
+		{name: "unused placeholder", opts: &RunOptions{StdinPlaceholder: "node"}, stdin: "a\n", wantErr: "doesn't use <node>"},
+		{name: "also a param", opts: &RunOptions{StdinPlaceholder: "host", Params: map[string]string{"host": "a"}}, stdin: "a\n", wantErr: "both --param"},
+		{name: "empty input", opts: &RunOptions{StdinPlaceholder: "host"}, stdin: "\n# none\n", wantErr: "no values"},
+	}
+	for _, tt := range tests {
+		_, err := resolveMatrix(wf, tt.opts, strings.NewReader(tt.stdin))
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
+
+func TestConfirmMatrixDangers(t *testing.T) {
+	cfg := config.DefaultConfig()
+	wf := &workflows.Workflow{Steps: []workflows.Step{{Command: "rm -rf /var/lib/<host>"}}}
+	combos := []*matrixCombo{{Label: "host=a", Params: map[string]string{"host": "a"}}}
+
+	if err := confirmMatrixDangers(wf, combos, cfg, false, false, nil); err == nil {
+		t.Error("expected unattended dangerous run without --yes to be refused")
+	}
+	if err := confirmMatrixDangers(wf, combos, cfg, false, true, nil); err != nil {
+		t.Errorf("expected --yes to allow dangerous run, got %v", err)
+	}
+
+	var out bytes.Buffer
+	p := tui.NewLinePrompter(strings.NewReader("n\n"), &out)
+	if err := confirmMatrixDangers(wf, combos, cfg, true, false, p); err == nil {
+		t.Error("expected declined confirmation to cancel")
+	}
+}
+
+func TestMatrixCombos(t *testing.T) {
+	wf := &workflows.Workflow{
+		Placeholders: map[string]workflows.Placeholder{"app": {Default: "api"}},
+		Steps:        []workflows.Step{{Command: "deploy <app> --region <region> --tier <tier>"}},
+	}
+	matrix := map[string][]string{"region": {"us-east-1", "eu-west-1"}}
+
+	if _, err := matrixCombos(wf, matrix, nil); err == nil || !strings.Contains(err.Error(), "<tier>") {
+		t.Errorf("expected missing <tier> error, got %v", err)
+	}
+
+	combos, err := matrixCombos(wf, matrix, map[string]string{"tier": "web"})
+	if err != nil {
+		t.Fatalf("matrixCombos() error = %v", err)
+	}
+	if len(combos) != 2 {
+		t.Fatalf("expected 2 combinations, got %d", len(combos))
+	}
+	want := map[string]string{"app": "api", "region": "eu-west-1", "tier": "web"}
+	if combos[1].Label != "region=eu-west-1" || !reflect.DeepEqual(combos[1].Params, want) {
+		t.Errorf("combos[1] = %q %v, want region=eu-west-1 %v", combos[1].Label, combos[1].Params, want)
+	}
+}
+
+func TestRunMatrixCombo(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Repo.Path = t.TempDir()
+	wf := &workflows.Workflow{
+		Steps: []workflows.Step{
+			{Command: "echo <region>-1", Capture: map[string]workflows.Extractor{"id": {Line: 1}}},
+			{Command: `test "<id>" = us-east-1-1`},
+			{Command: "exit 3"},
+			{Command: "echo unreachable"},
+		},
+		Cleanup: []workflows.Step{{Command: "echo <id> > cleaned"}},
+	}
+
+	results, row := runMatrixCombo(context.Background(), wf, map[string]string{"region": "us-east-1"}, cfg)
+	if row.Success || row.FailedStep != 2 || row.ExitCode != 3 {
+		t.Errorf("expected failure at step 2 with exit code 3, got %+v", row)
+	}
+	if !results[1].Success {
+		t.Errorf("expected captured value in step 2, got %+v", results[1])
+	}
+	if runpath.StepRan(results[3]) {
+		t.Error("expected step 4 not to run")
+	}
+
+	// The failed combination was cleaned up, with its captured values
+	data, err := os.ReadFile(filepath.Join(cfg.Repo.Path, "cleaned"))
+	if err != nil {
+		t.Fatalf("cleanup step didn't run: %v", err)
+	}
+	if got := strings.TrimSpace(string(data)); got != "us-east-1-1" {
+		t.Errorf("cleanup step wrote %q, want us-east-1-1", got)
+	}
+}
+
+func TestPrintMatrixSummary(t *testing.T) {
+	combos := []*matrixCombo{
+		{Label: "region=us-east-1", Ran: true, Row: tui.MatrixResult{Success: true}},
+		{Label: "region=eu-west-1", Ran: true, Row: tui.MatrixResult{FailedStep: 0, ExitCode: 3}},
+	}
+	combos[1].Results = []runnerpkg.StepResult{{Output: "connecting\naccess denied\n"}}
+
+	var buf bytes.Buffer
+	err := printMatrixSummary(&buf, combos)
+	if err == nil || !strings.Contains(err.Error(), "1 of 2 combinations failed") {
+		t.Errorf("expected failure error, got %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"✓ region=us-east-1", "✗ region=eu-west-1  failed at step 1 (exit code 3)", "access denied"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("summary missing %q:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	if err := printMatrixSummary(&buf, combos[:1]); err != nil {
+		t.Errorf("expected success, got %v", err)
+	}
+}
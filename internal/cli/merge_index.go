@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/index"
+)
+
+// NewMergeIndexCommand creates the hidden merge-index command, the git
+// merge driver for the search index.
+func NewMergeIndexCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "merge-index <base> <ours> <theirs>",
+		Short: "Merge two versions of the search index (git merge driver)",
+		Long: `Merge two versions of the search index file, writing the result to
+<ours>. Git runs this as the svf-index merge driver, which 'svf init'
+registers for the index file, so index changes from both sides of a sync
+merge instead of conflicting.`,
+		Hidden: true,
+		Args:   cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := index.MergeFiles(args[0], args[1], args[2]); err != nil {
+				return fmt.Errorf("failed to merge index: %w", err)
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// installIndexMergeDriver marks the index file as merged by the svf-index
+// driver in .gitattributes and registers the driver.
+func installIndexMergeDriver(ctx context.Context, cfg *config.Config) error {
+	line := "/" + cfg.Workflows.IndexPath + " merge=" + index.MergeDriver
+	if err := gitrepo.EnsureAttribute(cfg.Repo.Path, line); err != nil {
+		return err
+	}
+	return registerIndexMergeDriver(ctx, cfg.Repo.Path)
+}
+
+// registerIndexMergeDriver registers the svf-index merge driver in the
+// repository's git config. Driver definitions don't travel with clones, so
+// sync registers it again before merging.
+func registerIndexMergeDriver(ctx context.Context, repoPath string) error {
+	prefix := "merge." + index.MergeDriver + "."
+	if err := gitrepo.SetConfig(ctx, repoPath, prefix+"name", "svf search index"); err != nil {
+		return err
+	}
+	return gitrepo.SetConfig(ctx, repoPath, prefix+"driver", mergeDriverCommand())
+}
+
+// mergeDriverCommand returns the merge driver command line. It runs this
+// binary by its path, so merges work when svf isn't on git's PATH.
+func mergeDriverCommand() string {
+	bin := "svf"
+	if exe, err := os.Executable(); err == nil {
+		bin = quoteDriverArg(exe)
+	}
+	return bin + " merge-index %O %A %B"
+}
+
+// quoteDriverArg single-quotes s for the shell git runs merge drivers with.
+func quoteDriverArg(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
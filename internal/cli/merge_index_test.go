@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestMergeDriverCommand(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Skip("executable path not available")
+	}
+
+	got := mergeDriverCommand()
+	if want := quoteDriverArg(exe) + " merge-index %O %A %B"; got != want {
+		t.Errorf("mergeDriverCommand() = %q, want %q", got, want)
+	}
+
+	// The quoted path survives the shell, spaces and quotes included
+	path := "/tmp/it's a dir/svf"
+	out, err := exec.Command("sh", "-c", "printf %s "+quoteDriverArg(path)).Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(string(out)) != path {
+		t.Errorf("shell read %q, want %q", out, path)
+	}
+}
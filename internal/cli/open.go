@@ -0,0 +1,147 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/tui"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+// OpenOptions contains the options for the open command.
+type OpenOptions struct {
+	ConfigPath string
+	PR         bool
+	Print      bool
+}
+
+// NewOpenCommand creates the open command.
+func NewOpenCommand() *cobra.Command {
+	opts := &OpenOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "open <workflow-ref>",
+		Short: "Open a workflow at the forge in the browser",
+		Long: `Open a workflow's file on GitHub or GitLab in the browser, on the
+current branch of the workflows repository. The forge is worked out from
+the URL of the configured remote (repo.remote).
+
+--pr opens the pull requests (merge requests on GitLab) of the latest
+branch other than the default branch that changes the workflow, such as
+the feature branch of its last save in PR mode.
+
+--print prints the URL instead of opening it.`,
+		Example: `  svf open db-failover
+  svf open db-failover --pr
+  svf open db-failover --print`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runOpen(opts, args[0])
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ConfigPath, "config", "", "config file path")
+	cmd.Flags().BoolVar(&opts.PR, "pr", false, "open the workflow's latest pull request")
+	cmd.Flags().BoolVar(&opts.Print, "print", false, "print the URL instead of opening it")
+
+	return cmd
+}
+
+func runOpen(opts *OpenOptions, workflowRef string) error {
+	ctx := context.Background()
+
+	// Load config
+	var cfg *config.Config
+	var err error
+	if opts.ConfigPath != "" {
+		cfg, err = config.Load(opts.ConfigPath)
+	} else {
+		cfg, err = config.LoadWithDefaults()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Open repo
+	repo := gitrepo.New(cfg.Repo.Path)
+	if !repo.IsInitialized(ctx) {
+		return fmt.Errorf("repository not initialized. Run 'svf init' first")
+	}
+
+	str, err := store.New(repo, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+	ref, err := resolveWorkflowRef(ctx, str, cfg, workflowRef)
+	if err != nil {
+		return err
+	}
+
+	target, err := workflowURL(ctx, repo, cfg, ref, opts.PR)
+	if err != nil {
+		return err
+	}
+	if opts.Print {
+		fmt.Println(target)
+		return nil
+	}
+	if err := tui.OpenURL(target); err != nil {
+		return fmt.Errorf("failed to open browser: %w (the URL is %s)", err, target)
+	}
+	fmt.Printf("Opened %s\n", target)
+	return nil
+}
+
+// workflowURL returns the forge URL of a workflow's file on the current
+// branch, or with pr of its latest branch's pull requests.
+func workflowURL(ctx context.Context, repo gitrepo.Repo, cfg *config.Config, ref store.WorkflowRef, pr bool) (string, error) {
+	remoteURL, err := repo.GetConfig(ctx, "remote."+cfg.Repo.Remote+".url")
+	if err != nil || remoteURL == "" {
+		return "", fmt.Errorf("remote %q has no URL. Add it with 'git -C %s remote add %s <url>'", cfg.Repo.Remote, cfg.Repo.Path, cfg.Repo.Remote)
+	}
+	forge, err := gitrepo.ParseForge(remoteURL)
+	if err != nil {
+		return "", err
+	}
+
+	rel, err := filepath.Rel(cfg.Repo.Path, ref.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to locate %s in the repository: %w", ref.Path, err)
+	}
+	rel = filepath.ToSlash(rel)
+
+	if pr {
+		branch, err := latestWorkflowBranch(ctx, repo, cfg, rel)
+		if err != nil {
+			return "", err
+		}
+		return forge.PullRequestsURL(branch), nil
+	}
+
+	branch, err := repo.GetCurrentBranch(ctx)
+	if err != nil || branch == "HEAD" {
+		// Detached or unreadable: fall back to the default branch
+		branch = cfg.Repo.Branch
+	}
+	return forge.FileURL(branch, rel), nil
+}
+
+// latestWorkflowBranch returns the branch, other than the default and PR
+// base branches, with the most recent commit changing the file at rel.
+func latestWorkflowBranch(ctx context.Context, repo gitrepo.Repo, cfg *config.Config, rel string) (string, error) {
+	branches, err := repo.PathBranches(ctx, rel)
+	if err != nil {
+		return "", err
+	}
+	for _, branch := range branches {
+		if branch != cfg.Repo.Branch && branch != cfg.Git.PRBaseBranch {
+			return branch, nil
+		}
+	}
+	return "", fmt.Errorf("no branch other than %s changes %s, so it has no pull request", cfg.Repo.Branch, rel)
+}
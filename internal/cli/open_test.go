@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/testutil"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+func TestWorkflowURL(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.DefaultConfig()
+	cfg.Repo.Path = t.TempDir()
+	repo := testutil.NewFakeRepo(cfg.Repo.Path)
+	ref := store.WorkflowRef{Path: filepath.Join(cfg.Repo.Path, "workflows", "platform", "db-failover", "workflow.yaml")}
+
+	if _, err := workflowURL(ctx, repo, cfg, ref, false); err == nil || !strings.Contains(err.Error(), "has no URL") {
+		t.Errorf("expected an error without a remote, got %v", err)
+	}
+
+	repo.SetConfig("remote.origin.url", "git@github.com:acme/runbooks.git")
+	got, err := workflowURL(ctx, repo, cfg, ref, false)
+	if err != nil {
+		t.Fatalf("workflowURL() error = %v", err)
+	}
+	if want := "https://github.com/acme/runbooks/blob/main/workflows/platform/db-failover/workflow.yaml"; got != want {
+		t.Errorf("workflowURL() = %s, want %s", got, want)
+	}
+}
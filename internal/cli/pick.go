@@ -0,0 +1,128 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/index"
+	"github.com/chazuruo/svf/internal/tui"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+// errPickCanceled is returned when the user closes the picker without
+// choosing a workflow.
+var errPickCanceled = errors.New("no workflow selected")
+
+// resolveOrPickWorkflow resolves query to a workflow. An empty query opens
+// the interactive picker. A query that doesn't name a workflow exactly is
+// fuzzy-matched against the search index: a single match is used
+// directly, and several matches are offered for disambiguation (or listed
+// in the error without a TUI).
+func resolveOrPickWorkflow(ctx context.Context, str store.Store, cfg *config.Config, query string) (store.WorkflowRef, error) {
+	mode := GetInteractionMode(cfg)
+
+	if query != "" {
+		ref, err := resolveWorkflowRef(ctx, str, cfg, query)
+		if err == nil {
+			return ref, nil
+		}
+		var ambiguous *store.AmbiguousError
+		if !errors.Is(err, store.ErrNotFound) && !errors.As(err, &ambiguous) {
+			return store.WorkflowRef{}, err
+		}
+		if ambiguous != nil && mode == ModeNone {
+			return store.WorkflowRef{}, err
+		}
+	} else if mode == ModeNone {
+		return store.WorkflowRef{}, fmt.Errorf("workflow reference required\nUsage: svf run <workflow-ref>\nUse 'svf search --query <text>' to find one")
+	}
+
+	idx, _, err := index.NewBuilder(cfg.Repo.Path, cfg).LoadOrRebuild()
+	if err != nil {
+		return store.WorkflowRef{}, fmt.Errorf("failed to load index: %w", err)
+	}
+
+	var results []index.SearchResult
+	if query != "" {
+		results = idx.FuzzySearch(index.SearchOptions{Query: query})
+		switch {
+		case len(results) == 0:
+			return store.WorkflowRef{}, fmt.Errorf("%w: %s", store.ErrNotFound, query)
+		case len(results) == 1:
+			entry := results[0].Entry
+			fmt.Fprintf(os.Stderr, "Matched %q: %s\n", query, entry.Title)
+			return entryRef(cfg, &entry), nil
+		case mode == ModeNone:
+			return store.WorkflowRef{}, fuzzyAmbiguousError(query, results)
+		}
+	}
+
+	var entry *index.WorkflowEntry
+	ranks := loadPinRanks()
+	if mode == ModeLine {
+		if query == "" {
+			results = idx.FuzzySearch(index.SearchOptions{})
+		}
+		pinResultsFirst(results, ranks)
+		entry, err = tui.SelectSearchResultLine(results, tui.NewStdioLinePrompter())
+		if err != nil {
+			return store.WorkflowRef{}, err
+		}
+	} else {
+		model := tui.NewSearchModel(idx)
+		orderSearchModel(&model, nil, ranks)
+		if query != "" {
+			model.SearchInput.SetValue(query)
+			model.SearchInput.CursorEnd()
+			model.PerformSearch()
+		}
+
+		finalModel, err := tea.NewProgram(model, tea.WithAltScreen()).Run()
+		if err != nil {
+			return store.WorkflowRef{}, fmt.Errorf("failed to run picker: %w", err)
+		}
+		picker := finalModel.(tui.SearchModel)
+		if picker.DidConfirm() {
+			entry = picker.GetSelectedEntry()
+		}
+	}
+
+	if entry == nil {
+		return store.WorkflowRef{}, errPickCanceled
+	}
+	return entryRef(cfg, entry), nil
+}
+
+// entryRef converts an index entry to a store reference.
+func entryRef(cfg *config.Config, entry *index.WorkflowEntry) store.WorkflowRef {
+	path := filepath.Join(cfg.Repo.Path, entry.Path)
+	return store.WorkflowRef{
+		ID:   entry.ID,
+		Slug: filepath.Base(filepath.Dir(path)),
+		Path: path,
+		Doc:  entry.Doc,
+	}
+}
+
+// fuzzyAmbiguousError lists the workflows a fuzzy query matched.
+func fuzzyAmbiguousError(query string, results []index.SearchResult) error {
+	const maxListed = 10
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%q matches %d workflows; use an ID or path instead:", query, len(results))
+	for i, r := range results {
+		if i == maxListed {
+			fmt.Fprintf(&b, "\n  ... and %d more", len(results)-maxListed)
+			break
+		}
+		fmt.Fprintf(&b, "\n  %s  %s", r.Entry.ID, r.Entry.Title)
+	}
+	return errors.New(b.String())
+}
@@ -0,0 +1,88 @@
+// Package cli provides tests for CLI commands.
+package cli
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+// setNoTUI sets the global --no-tui flag for the duration of a test.
+func setNoTUI(t *testing.T, v bool) {
+	t.Helper()
+	noTUIMutex.Lock()
+	prev := NoTUI
+	NoTUI = v
+	noTUIMutex.Unlock()
+	t.Cleanup(func() {
+		noTUIMutex.Lock()
+		NoTUI = prev
+		noTUIMutex.Unlock()
+	})
+}
+
+// TestResolveOrPickWorkflow_NoTUI verifies exact, fuzzy and ambiguous
+// workflow references without a TUI.
+func TestResolveOrPickWorkflow_NoTUI(t *testing.T) {
+	setNoTUI(t, true)
+	ctx := context.Background()
+
+	cfg := config.DefaultConfig()
+	cfg.Repo.Path = t.TempDir()
+	cfg.Identity.Path = "team/test"
+	repo := gitrepo.New(cfg.Repo.Path)
+	if err := repo.Init(ctx, gitrepo.InitOptions{}); err != nil {
+		t.Fatalf("failed to init repo: %v", err)
+	}
+
+	str, err := store.New(repo, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, title := range []string{"Deploy API", "Deploy Frontend", "Rotate Certificates"} {
+		wf := &workflows.Workflow{
+			SchemaVersion: workflows.SchemaVersion,
+			Title:         title,
+			Steps:         []workflows.Step{{Name: "run", Command: "echo " + title}},
+		}
+		if _, err := str.Save(ctx, wf, store.SaveOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Exact slug
+	ref, err := resolveOrPickWorkflow(ctx, str, cfg, "deploy-api")
+	if err != nil || ref.Slug != "deploy-api" {
+		t.Fatalf("exact slug: got %+v, %v", ref, err)
+	}
+
+	// Single fuzzy match
+	ref, err = resolveOrPickWorkflow(ctx, str, cfg, "certificates")
+	if err != nil || ref.Slug != "rotate-certificates" {
+		t.Fatalf("fuzzy match: got %+v, %v", ref, err)
+	}
+
+	// Several fuzzy matches are listed rather than guessed
+	_, err = resolveOrPickWorkflow(ctx, str, cfg, "deploy")
+	if err == nil || !strings.Contains(err.Error(), "Deploy API") || !strings.Contains(err.Error(), "Deploy Frontend") {
+		t.Fatalf("ambiguous match: got %v", err)
+	}
+
+	// No match
+	_, err = resolveOrPickWorkflow(ctx, str, cfg, "zzzqqq")
+	if !errors.Is(err, store.ErrNotFound) {
+		t.Fatalf("no match: got %v", err)
+	}
+
+	// No argument requires a TUI
+	_, err = resolveOrPickWorkflow(ctx, str, cfg, "")
+	if err == nil || !strings.Contains(err.Error(), "workflow reference required") {
+		t.Fatalf("empty ref: got %v", err)
+	}
+}
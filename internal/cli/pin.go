@@ -0,0 +1,293 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/index"
+	"github.com/chazuruo/svf/internal/pins"
+	"github.com/chazuruo/svf/internal/tui"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+// pinMarker marks pinned workflows in listings.
+const pinMarker = "★ "
+
+// PinOptions contains the options for the pin, unpin and pins commands.
+type PinOptions struct {
+	ConfigPath string
+	JSON       bool
+}
+
+// NewPinCommand creates the pin command.
+func NewPinCommand() *cobra.Command {
+	opts := &PinOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "pin <workflow-ref>",
+		Short: "Pin a workflow as a favorite",
+		Long: `Pin a workflow you use often. Pinned workflows come first, in the order
+they were pinned, in 'svf list', 'svf search' and the workflow picker, and
+'svf pins' lists them.
+
+Pins are kept on this machine in $XDG_STATE_HOME/svf/pins.json and are
+never committed.`,
+		Example: `  svf pin db-failover
+  svf unpin db-failover`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPin(opts, args[0], true)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ConfigPath, "config", "", "config file path")
+
+	return cmd
+}
+
+// NewUnpinCommand creates the unpin command.
+func NewUnpinCommand() *cobra.Command {
+	opts := &PinOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "unpin <workflow-ref>",
+		Short: "Unpin a workflow",
+		Long: `Unpin a workflow pinned with 'svf pin'. A pinned workflow that no longer
+exists can be unpinned by the ID 'svf pins' shows.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPin(opts, args[0], false)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ConfigPath, "config", "", "config file path")
+
+	return cmd
+}
+
+// NewPinsCommand creates the pins command.
+func NewPinsCommand() *cobra.Command {
+	opts := &PinOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "pins",
+		Short: "List pinned workflows",
+		Long:  `List the workflows pinned with 'svf pin', in the order they were pinned.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPins(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ConfigPath, "config", "", "config file path")
+	cmd.Flags().BoolVar(&opts.JSON, "json", false, "output as JSON")
+
+	return cmd
+}
+
+// loadPinConfig loads the config and checks the repository exists.
+func loadPinConfig(ctx context.Context, opts *PinOptions) (*config.Config, gitrepo.Repo, error) {
+	var cfg *config.Config
+	var err error
+	if opts.ConfigPath != "" {
+		cfg, err = config.Load(opts.ConfigPath)
+	} else {
+		cfg, err = config.LoadWithDefaults()
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	repo := gitrepo.New(cfg.Repo.Path)
+	if !repo.IsInitialized(ctx) {
+		return nil, nil, fmt.Errorf("repository not initialized. Run 'svf init' first")
+	}
+	return cfg, repo, nil
+}
+
+func runPin(opts *PinOptions, refStr string, pin bool) error {
+	ctx := context.Background()
+
+	cfg, repo, err := loadPinConfig(ctx, opts)
+	if err != nil {
+		return err
+	}
+	str, err := store.New(repo, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+	pinStore, err := pins.NewDefaultStore()
+	if err != nil {
+		return err
+	}
+
+	ref, err := resolveWorkflowRef(ctx, str, cfg, refStr)
+	if err != nil {
+		// Workflows that are gone can still be unpinned by ID
+		if !pin && errors.Is(err, store.ErrNotFound) {
+			removed, rmErr := pinStore.Remove(refStr)
+			if rmErr != nil {
+				return rmErr
+			}
+			if removed {
+				fmt.Printf("✓ Unpinned %s\n", refStr)
+				return nil
+			}
+		}
+		return err
+	}
+	wf, err := str.Load(ctx, ref)
+	if err != nil {
+		return fmt.Errorf("failed to load workflow: %w", err)
+	}
+	if wf.ID == "" {
+		return fmt.Errorf("%s has no ID to pin it by. Run 'svf ids assign' first", wf.Title)
+	}
+
+	if !pin {
+		removed, err := pinStore.Remove(wf.ID)
+		if err != nil {
+			return err
+		}
+		if !removed {
+			return fmt.Errorf("%s is not pinned", wf.Title)
+		}
+		fmt.Printf("✓ Unpinned %s\n", wf.Title)
+		return nil
+	}
+
+	added, err := pinStore.Add(pins.Pin{ID: wf.ID, Title: wf.Title, PinnedAt: time.Now()})
+	if err != nil {
+		return err
+	}
+	if !added {
+		fmt.Printf("%s is already pinned.\n", wf.Title)
+		return nil
+	}
+	fmt.Printf("✓ Pinned %s\n", wf.Title)
+	return nil
+}
+
+// pinnedWorkflow is a pin in the pins output.
+type pinnedWorkflow struct {
+	ID       string    `json:"id"`
+	Title    string    `json:"title"`
+	Tags     []string  `json:"tags,omitempty"`
+	PinnedAt time.Time `json:"pinned_at"`
+	Missing  bool      `json:"missing,omitempty"`
+}
+
+func runPins(opts *PinOptions) error {
+	ctx := context.Background()
+
+	cfg, _, err := loadPinConfig(ctx, opts)
+	if err != nil {
+		return err
+	}
+	pinStore, err := pins.NewDefaultStore()
+	if err != nil {
+		return err
+	}
+	pinned, err := pinStore.List()
+	if err != nil {
+		return err
+	}
+	idx, _, err := index.NewBuilder(cfg.Repo.Path, cfg).LoadOrRebuild()
+	if err != nil {
+		return fmt.Errorf("failed to load index: %w", err)
+	}
+
+	out := make([]pinnedWorkflow, len(pinned))
+	for i, p := range pinned {
+		out[i] = pinnedWorkflow{ID: p.ID, Title: p.Title, PinnedAt: p.PinnedAt}
+		if entry := idx.GetByID(p.ID); entry != nil {
+			out[i].Title, out[i].Tags = entry.Title, entry.Tags
+		} else {
+			out[i].Missing = true
+		}
+	}
+
+	if opts.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	if len(out) == 0 {
+		fmt.Println("No pinned workflows. Pin one with 'svf pin <workflow>'.")
+		return nil
+	}
+	for i, p := range out {
+		fmt.Printf("%3d. %s", i+1, p.Title)
+		if len(p.Tags) > 0 {
+			fmt.Printf(" [%s]", strings.Join(p.Tags, ", "))
+		}
+		if p.Missing {
+			fmt.Print(" (not in this repository)")
+		}
+		fmt.Printf("\n     %s\n", p.ID)
+	}
+	return nil
+}
+
+// loadPinRanks reads the pinned workflows, ranked in the order they were
+// pinned. Unreadable pins rank nothing.
+func loadPinRanks() map[string]int {
+	pinStore, err := pins.NewDefaultStore()
+	if err == nil {
+		var pinned []pins.Pin
+		if pinned, err = pinStore.List(); err == nil {
+			return pins.Ranks(pinned)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Warning: failed to read pins: %v\n", err)
+	return nil
+}
+
+// pinnedFirst moves pinned workflows to the front of a slice in place, in
+// the order they were pinned, keeping the order of the rest. id returns the
+// ID of the workflow at each index.
+func pinnedFirst(items any, ranks map[string]int, id func(i int) string) {
+	if len(ranks) == 0 {
+		return
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		ri, pi := ranks[id(i)]
+		rj, pj := ranks[id(j)]
+		if pi != pj {
+			return pi
+		}
+		return pi && ri < rj
+	})
+}
+
+// orderSearchModel has the model order each search's results by sorter, if
+// set, then with pinned workflows first, which it marks.
+func orderSearchModel(model *tui.SearchModel, sorter *workflowSorter, ranks map[string]int) {
+	model.Order = func(results []index.SearchResult) {
+		if sorter != nil {
+			sorter.sortResults(results)
+		}
+		pinResultsFirst(results, ranks)
+	}
+	model.Pinned = make(map[string]bool, len(ranks))
+	for id := range ranks {
+		model.Pinned[id] = true
+	}
+	model.PerformSearch()
+}
+
+// pinResultsFirst moves pinned workflows to the front of search results.
+func pinResultsFirst(results []index.SearchResult, ranks map[string]int) {
+	pinnedFirst(results, ranks, func(i int) string { return results[i].Entry.ID })
+}
@@ -0,0 +1,29 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/chazuruo/svf/internal/index"
+)
+
+func TestPinResultsFirst(t *testing.T) {
+	results := []index.SearchResult{
+		{Entry: index.WorkflowEntry{ID: "a"}},
+		{Entry: index.WorkflowEntry{ID: "b"}},
+		{Entry: index.WorkflowEntry{ID: "c"}},
+		{Entry: index.WorkflowEntry{ID: "d"}},
+	}
+	// d was pinned before b
+	pinResultsFirst(results, map[string]int{"d": 0, "b": 1, "gone": 2})
+
+	var got []string
+	for _, r := range results {
+		got = append(got, r.Entry.ID)
+	}
+	want := []string{"d", "b", "a", "c"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("pinResultsFirst() order = %v, want %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/chazuruo/svf/internal/config"
+	svferrors "github.com/chazuruo/svf/internal/errors"
+	"github.com/chazuruo/svf/internal/i18n"
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+// pipelineItem is one workflow of a run.
+type pipelineItem struct {
+	Ref      store.WorkflowRef
+	Workflow *workflows.Workflow
+	// CompanionDir holds the workflow's companion files when they aren't
+	// next to Ref.Path, as when running a workflow as of a revision.
+	CompanionDir string
+}
+
+// pipelinePosition is where a run is in its pipeline.
+type pipelinePosition struct {
+	Position int // 1-based; 0 when not in a pipeline
+	Titles   []string
+}
+
+// collectValues hands a run's final placeholder values to the pipeline.
+func (o *RunOptions) collectValues(values map[string]string) {
+	if o.values == nil {
+		return
+	}
+	for k, v := range values {
+		o.values[k] = v
+	}
+}
+
+// resolvePipeline resolves the workflows a run covers: each of a
+// comma-separated list of references, or a single workflow followed by
+// its chain of next workflows. A single reference may be missing or
+// inexact, in which case the user picks the workflow.
+func resolvePipeline(ctx context.Context, str store.Store, cfg *config.Config, refStr string) ([]pipelineItem, error) {
+	load := func(ref store.WorkflowRef) (pipelineItem, error) {
+		wf, err := str.Load(ctx, ref)
+		if err != nil {
+			return pipelineItem{}, fmt.Errorf("failed to load workflow: %w", err)
+		}
+		return pipelineItem{Ref: ref, Workflow: wf}, nil
+	}
+
+	if strings.Contains(refStr, ",") {
+		var items []pipelineItem
+		for _, part := range strings.Split(refStr, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				return nil, fmt.Errorf("empty workflow reference in %q", refStr)
+			}
+			ref, err := resolveWorkflowRef(ctx, str, cfg, part)
+			if err != nil {
+				return nil, err
+			}
+			item, err := load(ref)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	}
+
+	ref, err := resolveOrPickWorkflow(ctx, str, cfg, refStr)
+	if err != nil {
+		return nil, err
+	}
+	item, err := load(ref)
+	if err != nil {
+		return nil, err
+	}
+	items := []pipelineItem{item}
+	seen := map[string]bool{ref.Location(): true}
+	for next := item.Workflow.Next; next != ""; next = item.Workflow.Next {
+		title := item.Workflow.Title
+		ref, err := resolveWorkflowRef(ctx, str, cfg, next)
+		if err != nil {
+			return nil, fmt.Errorf("%s: next: %w", title, err)
+		}
+		if seen[ref.Location()] {
+			return nil, fmt.Errorf("%s: next leads back to a workflow already in the pipeline", title)
+		}
+		seen[ref.Location()] = true
+		if item, err = load(ref); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// runPipeline runs workflows in sequence, passing the placeholder values
+// each ends with on to the next. A failed workflow stops the pipeline
+// unless opts.ContinueOnError is set; canceling always stops it.
+func runPipeline(ctx context.Context, items []pipelineItem, opts *RunOptions, cfg *config.Config) error {
+	if len(opts.Matrix) > 0 || opts.StdinPlaceholder != "" || opts.SendTo != "" || opts.Preset != "" || opts.From != "" || opts.Until != "" {
+		return fmt.Errorf("--matrix, --stdin-placeholder, --send-to, --preset, --from and --until apply to a single workflow, not a pipeline")
+	}
+	titles := make([]string, len(items))
+	for i, item := range items {
+		if len(item.Workflow.Matrix) > 0 {
+			return fmt.Errorf("%s has a matrix and can't run in a pipeline", item.Workflow.Title)
+		}
+		titles[i] = item.Workflow.Title
+	}
+
+	// The runner TUI shows the pipeline itself, and JSON progress events
+	// name their workflow
+	jsonLog := opts.LogFormat == logFormatJSON
+	showHeader := !jsonLog && (opts.Yes || opts.Batch || IsNoTUI() || GetInteractionMode(cfg) != ModeTUI)
+
+	params := make(map[string]string, len(opts.Params))
+	for k, v := range opts.Params {
+		params[k] = v
+	}
+	var failed []string
+	for i, item := range items {
+		runOpts := *opts
+		runOpts.Params = params
+		runOpts.pipeline = pipelinePosition{Position: i + 1, Titles: titles}
+		runOpts.values = make(map[string]string)
+
+		if showHeader {
+			fmt.Printf("\n== %s ==\n", i18n.T("run.pipeline", i+1, len(items), item.Workflow.Title))
+		}
+		err := runWorkflow(ctx, item.Workflow, &runOpts, cfg)
+		for k, v := range runOpts.values {
+			params[k] = v
+		}
+
+		if err != nil {
+			if errors.Is(err, errRunCanceled) || !opts.ContinueOnError {
+				return fmt.Errorf("pipeline stopped at %s: %w", item.Workflow.Title, err)
+			}
+			failed = append(failed, item.Workflow.Title)
+			if i < len(items)-1 && !jsonLog {
+				fmt.Println(i18n.T("run.pipeline_continue", item.Workflow.Title))
+			}
+		}
+	}
+
+	if len(failed) > 0 {
+		return svferrors.WithExitCode(fmt.Errorf("pipeline failed: %s did not succeed", strings.Join(failed, ", ")), svferrors.ExitStepFailed)
+	}
+	if !jsonLog {
+		fmt.Println("\n" + i18n.T("run.pipeline_succeeded"))
+	}
+	return nil
+}
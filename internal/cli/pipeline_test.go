@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+// newPipelineStore returns a store in a new repo holding the given
+// workflows.
+func newPipelineStore(t *testing.T, wfs ...*workflows.Workflow) (store.Store, *config.Config) {
+	t.Helper()
+	ctx := context.Background()
+	setGitIdentity(t)
+
+	cfg := config.DefaultConfig()
+	cfg.Repo.Path = t.TempDir()
+	cfg.Identity.Path = "team/test"
+	cfg.Identity.Mode = "direct"
+	repo := gitrepo.New(cfg.Repo.Path)
+	if err := repo.Init(ctx, gitrepo.InitOptions{}); err != nil {
+		t.Fatalf("failed to init repo: %v", err)
+	}
+	str, err := store.New(repo, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, wf := range wfs {
+		wf.SchemaVersion = workflows.SchemaVersion
+		if _, err := str.Save(ctx, wf, store.SaveOptions{}); err != nil {
+			t.Fatalf("Save(%s) error = %v", wf.Title, err)
+		}
+	}
+	return str, cfg
+}
+
+func pipelineTitles(items []pipelineItem) string {
+	titles := make([]string, len(items))
+	for i, item := range items {
+		titles[i] = item.Workflow.Title
+	}
+	return strings.Join(titles, ",")
+}
+
+func TestResolvePipeline(t *testing.T) {
+	ctx := context.Background()
+	str, cfg := newPipelineStore(t,
+		&workflows.Workflow{ID: "wf_build", Title: "Build", Next: "wf_test", Steps: []workflows.Step{{Command: "make"}}},
+		&workflows.Workflow{ID: "wf_test", Title: "Test", Next: "wf_release", Steps: []workflows.Step{{Command: "make test"}}},
+		&workflows.Workflow{ID: "wf_release", Title: "Release", Steps: []workflows.Step{{Command: "make release"}}},
+		&workflows.Workflow{ID: "wf_ping", Title: "Ping", Next: "wf_pong", Steps: []workflows.Step{{Command: "echo ping"}}},
+		&workflows.Workflow{ID: "wf_pong", Title: "Pong", Next: "wf_ping", Steps: []workflows.Step{{Command: "echo pong"}}},
+		&workflows.Workflow{ID: "wf_dangling", Title: "Dangling", Next: "wf_missing", Steps: []workflows.Step{{Command: "true"}}},
+	)
+
+	tests := []struct {
+		ref     string
+		want    string
+		wantErr string
+	}{
+		{ref: "wf_release", want: "Release"},
+		{ref: "wf_build", want: "Build,Test,Release"},
+		{ref: "wf_test", want: "Test,Release"},
+		{ref: "wf_release, wf_build", want: "Release,Build"},
+		{ref: "wf_release,,wf_build", wantErr: "empty workflow reference"},
+		{ref: "wf_ping", wantErr: "leads back"},
+		{ref: "wf_dangling", wantErr: "Dangling: next"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			items, err := resolvePipeline(ctx, str, cfg, tt.ref)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("resolvePipeline(%q) error = %v, want %q", tt.ref, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolvePipeline(%q) error = %v", tt.ref, err)
+			}
+			if got := pipelineTitles(items); got != tt.want {
+				t.Errorf("resolvePipeline(%q) = %s, want %s", tt.ref, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunPipeline(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	str, cfg := newPipelineStore(t,
+		&workflows.Workflow{ID: "wf_version", Title: "Version", Steps: []workflows.Step{{
+			Command: "echo version=1.2.3",
+			Capture: map[string]workflows.Extractor{"version": {Regex: `version=(\S+)`}},
+		}}},
+		&workflows.Workflow{ID: "wf_check", Title: "Check", Steps: []workflows.Step{{Command: "test <version> = 1.2.3"}}},
+		&workflows.Workflow{ID: "wf_fail", Title: "Fail", Steps: []workflows.Step{{Command: "false"}}},
+	)
+	cfg.Runner.StreamOutput = false
+
+	run := func(refs string, opts RunOptions) error {
+		t.Helper()
+		items, err := resolvePipeline(ctx, str, cfg, refs)
+		if err != nil {
+			t.Fatalf("resolvePipeline(%q) error = %v", refs, err)
+		}
+		opts.Yes = true
+		opts.Local = true
+		return runPipeline(ctx, items, &opts, cfg)
+	}
+
+	if err := run("wf_version,wf_check", RunOptions{}); err != nil {
+		t.Errorf("captured values were not passed on: %v", err)
+	}
+
+	err := run("wf_fail,wf_check", RunOptions{Params: map[string]string{"version": "1.2.3"}})
+	if err == nil || !strings.Contains(err.Error(), "pipeline stopped at Fail") {
+		t.Errorf("failing pipeline error = %v, want it stopped at Fail", err)
+	}
+
+	err = run("wf_fail,wf_version,wf_check", RunOptions{ContinueOnError: true})
+	if err == nil || !strings.Contains(err.Error(), "Fail did not succeed") {
+		t.Errorf("--continue-on-error pipeline error = %v, want Fail reported", err)
+	}
+
+	err = run("wf_version,wf_check", RunOptions{From: "2"})
+	if err == nil || !strings.Contains(err.Error(), "not a pipeline") {
+		t.Errorf("--from pipeline error = %v, want it rejected", err)
+	}
+}
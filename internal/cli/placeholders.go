@@ -0,0 +1,238 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/placeholders"
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+// PlaceholdersOptions contains the options for the placeholders command.
+type PlaceholdersOptions struct {
+	ConfigPath string
+	JSON       bool
+}
+
+// NewPlaceholdersCommand creates the placeholders command.
+func NewPlaceholdersCommand() *cobra.Command {
+	opts := &PlaceholdersOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "placeholders <workflow-ref>",
+		Short: "Audit a workflow's placeholders",
+		Long: `List every placeholder of a workflow: the steps using it, with their
+line in the workflow file, its default and validation pattern, and where
+else its value can come from (a step's captured output, the matrix or
+presets).
+
+Placeholders used without a declaration and declared placeholders that
+no step uses are called out, which is handy before sharing a workflow.`,
+		Example: `  svf placeholders deploy-api
+  svf placeholders deploy-api --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPlaceholders(opts, args[0])
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ConfigPath, "config", "", "config file path")
+	cmd.Flags().BoolVar(&opts.JSON, "json", false, "output as JSON")
+
+	return cmd
+}
+
+// placeholderUse is a step using a placeholder, for output.
+type placeholderUse struct {
+	Step int    `json:"step"`
+	Name string `json:"name"`
+	Line int    `json:"line,omitempty"`
+}
+
+// placeholderReport is one placeholder's audit, for output.
+type placeholderReport struct {
+	Name       string           `json:"name"`
+	Declared   bool             `json:"declared"`
+	Prompt     string           `json:"prompt,omitempty"`
+	Default    string           `json:"default,omitempty"`
+	Validate   string           `json:"validate,omitempty"`
+	Secret     bool             `json:"secret,omitempty"`
+	UsedIn     []placeholderUse `json:"used_in"`
+	CapturedBy int              `json:"captured_by,omitempty"`
+	Matrix     bool             `json:"matrix,omitempty"`
+	Presets    []string         `json:"presets,omitempty"`
+	Unused     bool             `json:"unused,omitempty"`
+}
+
+func runPlaceholders(opts *PlaceholdersOptions, workflowRef string) error {
+	ctx := context.Background()
+
+	// Load config
+	cfg, err := loadConfig(opts.ConfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Open repo
+	repo := gitrepo.New(cfg.Repo.Path)
+	if !repo.IsInitialized(ctx) {
+		return fmt.Errorf("repository not initialized. Run 'svf init' first")
+	}
+
+	// Create store
+	str, err := store.New(repo, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+
+	ref, err := resolveWorkflowRef(ctx, str, cfg, workflowRef)
+	if err != nil {
+		return err
+	}
+	wf, err := str.Load(ctx, ref)
+	if err != nil {
+		return fmt.Errorf("failed to load workflow: %w", err)
+	}
+
+	reports := auditPlaceholders(wf, workflowStepLines(ref))
+	if opts.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(reports)
+	}
+	printPlaceholderReports(wf, ref, reports)
+	return nil
+}
+
+// workflowStepLines returns the line each step of the workflow starts on
+// in its file, or nil when they can't be told, as for TOML files.
+func workflowStepLines(ref store.WorkflowRef) []int {
+	if workflows.FormatForPath(ref.Path) == workflows.FormatTOML {
+		return nil
+	}
+	data, err := os.ReadFile(ref.Path)
+	if err != nil {
+		return nil
+	}
+	lines, err := workflows.StepLines(data, ref.Doc)
+	if err != nil {
+		return nil
+	}
+	return lines
+}
+
+// auditPlaceholders builds the reports for a workflow's placeholders.
+// Step numbers in the reports are 1-based.
+func auditPlaceholders(wf *workflows.Workflow, lines []int) []placeholderReport {
+	usages := placeholders.Audit(wf)
+	reports := make([]placeholderReport, 0, len(usages))
+	for _, u := range usages {
+		r := placeholderReport{
+			Name:     u.Name,
+			Declared: u.Declared,
+			Prompt:   u.Placeholder.Prompt,
+			Default:  u.Placeholder.Default,
+			Validate: u.Placeholder.Validate,
+			Secret:   u.Placeholder.Secret,
+			UsedIn:   []placeholderUse{},
+			Matrix:   u.Matrix,
+			Presets:  u.Presets,
+			Unused:   u.Unused(),
+		}
+		if u.CapturedBy >= 0 {
+			r.CapturedBy = u.CapturedBy + 1
+		}
+		for _, i := range u.Steps {
+			use := placeholderUse{Step: i + 1, Name: wf.Steps[i].Name}
+			if use.Name == "" {
+				use.Name = fmt.Sprintf("Step %d", i+1)
+			}
+			if i < len(lines) {
+				use.Line = lines[i]
+			}
+			r.UsedIn = append(r.UsedIn, use)
+		}
+		reports = append(reports, r)
+	}
+	return reports
+}
+
+// printPlaceholderReports prints the audit of a workflow's placeholders.
+func printPlaceholderReports(wf *workflows.Workflow, ref store.WorkflowRef, reports []placeholderReport) {
+	fmt.Printf("Placeholders in %s (%s)\n", wf.Title, ref.Location())
+	if len(reports) == 0 {
+		fmt.Println("\nNo placeholders.")
+		return
+	}
+
+	var undeclared, unused []string
+	for _, r := range reports {
+		fmt.Printf("\n<%s>\n", r.Name)
+		if r.Prompt != "" {
+			fmt.Printf("  Prompt:   %s\n", r.Prompt)
+		}
+		switch {
+		case r.Default == "":
+			fmt.Println("  Default:  none")
+		case r.Secret:
+			fmt.Println("  Default:  (secret)")
+		default:
+			fmt.Printf("  Default:  %s\n", r.Default)
+		}
+		if r.Validate != "" {
+			fmt.Printf("  Validate: %s\n", r.Validate)
+		} else {
+			fmt.Println("  Validate: none")
+		}
+
+		var sources []string
+		if r.CapturedBy > 0 {
+			sources = append(sources, fmt.Sprintf("captured by step %d", r.CapturedBy))
+		}
+		if r.Matrix {
+			sources = append(sources, "matrix")
+		}
+		if len(r.Presets) > 0 {
+			sources = append(sources, "presets "+strings.Join(r.Presets, ", "))
+		}
+		if len(sources) > 0 {
+			fmt.Printf("  Set by:   %s\n", strings.Join(sources, "; "))
+		}
+
+		if r.Unused {
+			fmt.Println("  Used in:  no steps")
+			unused = append(unused, "<"+r.Name+">")
+		}
+		for i, use := range r.UsedIn {
+			label := "            "
+			if i == 0 {
+				label = "  Used in:  "
+			}
+			if use.Line > 0 {
+				fmt.Printf("%s%d. %s (line %d)\n", label, use.Step, use.Name, use.Line)
+			} else {
+				fmt.Printf("%s%d. %s\n", label, use.Step, use.Name)
+			}
+		}
+		if !r.Declared && r.CapturedBy == 0 {
+			undeclared = append(undeclared, "<"+r.Name+">")
+		}
+	}
+
+	if len(undeclared) > 0 || len(unused) > 0 {
+		fmt.Println()
+	}
+	if len(undeclared) > 0 {
+		fmt.Printf("⚠ Not declared (prompted without a description or default): %s\n", strings.Join(undeclared, ", "))
+	}
+	if len(unused) > 0 {
+		fmt.Printf("⚠ Declared but unused: %s\n", strings.Join(unused, ", "))
+	}
+}
@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+func TestAuditPlaceholders(t *testing.T) {
+	wf := &workflows.Workflow{
+		Title:        "Deploy",
+		Placeholders: map[string]workflows.Placeholder{"region": {}},
+		Steps: []workflows.Step{
+			{Name: "build", Command: "make build"},
+			{Command: "deploy <env>"},
+		},
+	}
+
+	reports := auditPlaceholders(wf, []int{4, 6})
+	if len(reports) != 2 {
+		t.Fatalf("expected 2 reports, got %d", len(reports))
+	}
+	env := reports[0]
+	if env.Name != "env" || env.Declared || len(env.UsedIn) != 1 {
+		t.Fatalf("unexpected env report: %+v", env)
+	}
+	if use := env.UsedIn[0]; use.Step != 2 || use.Name != "Step 2" || use.Line != 6 {
+		t.Errorf("unexpected use: %+v", use)
+	}
+	if region := reports[1]; !region.Unused || len(region.UsedIn) != 0 {
+		t.Errorf("region should be unused: %+v", region)
+	}
+
+	// Without line numbers, uses have none
+	if reports := auditPlaceholders(wf, nil); reports[0].UsedIn[0].Line != 0 {
+		t.Error("expected no line without step lines")
+	}
+}
@@ -0,0 +1,200 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	//nolint:staticcheck // SA1019 - Using runner for StepResult type
+	runnerpkg "github.com/chazuruo/svf/internal/runner"
+)
+
+// Log formats of --log-format.
+const (
+	logFormatText = "text"
+	logFormatJSON = "json"
+)
+
+// Progress event names of --log-format json.
+const (
+	eventRunStarted     = "run_started"
+	eventStepPlanned    = "step_planned"
+	eventStepStarted    = "step_started"
+	eventStepFinished   = "step_finished"
+	eventStepOverBudget = "step_over_budget"
+	eventRunFinished    = "run_finished"
+)
+
+// Statuses of finished steps and runs.
+const (
+	statusSucceeded = "succeeded"
+	statusFailed    = "failed"
+	statusSkipped   = "skipped"
+	statusCanceled  = "canceled"
+)
+
+// progressEvent is one line of --log-format json output.
+type progressEvent struct {
+	Time          time.Time `json:"time"`
+	Event         string    `json:"event"`
+	Workflow      string    `json:"workflow"`
+	WorkflowID    string    `json:"workflow_id,omitempty"`
+	Step          int       `json:"step,omitempty"` // 1-based
+	Steps         int       `json:"steps,omitempty"`
+	Name          string    `json:"name,omitempty"`
+	Command       string    `json:"command,omitempty"`
+	CWD           string    `json:"cwd,omitempty"`
+	Status        string    `json:"status,omitempty"`
+	ExitCode      *int      `json:"exit_code,omitempty"`
+	DurationMS    *int64    `json:"duration_ms,omitempty"`
+	MaxDurationMS *int64    `json:"max_duration_ms,omitempty"`
+	Reason        string    `json:"reason,omitempty"`
+	Output        string    `json:"output,omitempty"`
+	Error         string    `json:"error,omitempty"`
+}
+
+// progress reports the steps of a batch run as they start and finish.
+// On a terminal it prints short lines with status glyphs; otherwise plain
+// timestamped log lines, or one JSON object per event with the json
+// format.
+type progress struct {
+	w        io.Writer
+	format   string
+	tty      bool
+	now      func() time.Time
+	workflow string
+	id       string
+	steps    int
+}
+
+// newProgress returns a progress reporter writing to w in format.
+func newProgress(w io.Writer, format string, tty bool) *progress {
+	return &progress{w: w, format: format, tty: tty, now: time.Now}
+}
+
+// JSON reports whether events are written as JSON, in which case nothing
+// else may be written to the same stream.
+func (p *progress) JSON() bool {
+	return p.format == logFormatJSON
+}
+
+// RunStarted reports the start of a workflow with the given number of steps.
+func (p *progress) RunStarted(workflow, id string, steps int) {
+	p.workflow, p.id, p.steps = workflow, id, steps
+	switch {
+	case p.JSON():
+		p.emit(progressEvent{Event: eventRunStarted, Steps: steps})
+	case p.tty:
+		fmt.Fprintf(p.w, "▶ %s (%d steps)\n", workflow, steps)
+	default:
+		p.logf("run started workflow=%q steps=%d", workflow, steps)
+	}
+}
+
+// StepPlanned reports a step a dry run would execute.
+func (p *progress) StepPlanned(i int, name, command, cwd, shell string) {
+	if p.JSON() {
+		p.emit(progressEvent{Event: eventStepPlanned, Step: i + 1, Name: name, Command: command, CWD: cwd})
+		return
+	}
+	fmt.Fprintf(p.w, "Step %d/%d: %s\n", i+1, p.steps, name)
+	fmt.Fprintf(p.w, "  Would execute: %s\n", command)
+	if cwd != "" {
+		fmt.Fprintf(p.w, "  Working directory: %s\n", cwd)
+	}
+	if shell != "" {
+		fmt.Fprintf(p.w, "  Shell: %s\n", shell)
+	}
+}
+
+// StepStarted reports that step i is about to run command.
+func (p *progress) StepStarted(i int, name, command string) {
+	switch {
+	case p.JSON():
+		p.emit(progressEvent{Event: eventStepStarted, Step: i + 1, Name: name, Command: command})
+	case p.tty:
+		fmt.Fprintf(p.w, "→ [%d/%d] %s\n", i+1, p.steps, name)
+	default:
+		p.logf("step %d/%d started name=%q", i+1, p.steps, name)
+	}
+}
+
+// StepOverBudget reports that step i is still running past its
+// max_duration.
+func (p *progress) StepOverBudget(i int, name string, maxDuration time.Duration) {
+	switch {
+	case p.JSON():
+		ms := maxDuration.Milliseconds()
+		p.emit(progressEvent{Event: eventStepOverBudget, Step: i + 1, Name: name, MaxDurationMS: &ms})
+	case p.tty:
+		fmt.Fprintf(p.w, "⚠ [%d/%d] %s is taking longer than the expected %s\n", i+1, p.steps, name, maxDuration)
+	default:
+		p.logf("step %d/%d over_budget name=%q max_duration=%s", i+1, p.steps, name, maxDuration)
+	}
+}
+
+// StepFinished reports the result of step i. output is the step's
+// output, already redacted, and is only included in JSON events.
+func (p *progress) StepFinished(i int, name string, result runnerpkg.StepResult, output string) {
+	status := stepStatus(result)
+
+	if p.JSON() {
+		ev := progressEvent{Event: eventStepFinished, Step: i + 1, Name: name, Status: status, Reason: result.SkipReason}
+		if !result.Skipped {
+			exitCode := result.ExitCode
+			ms := result.Duration.Milliseconds()
+			ev.ExitCode, ev.DurationMS, ev.Output = &exitCode, &ms, output
+		}
+		if result.Error != nil {
+			ev.Error = result.Error.Error()
+		}
+		p.emit(ev)
+		return
+	}
+
+	duration := result.Duration.Round(100 * time.Millisecond)
+	if p.tty {
+		switch status {
+		case statusSkipped:
+			fmt.Fprintf(p.w, "○ [%d/%d] %s skipped (%s)\n", i+1, p.steps, name, result.SkipReason)
+		case statusSucceeded:
+			fmt.Fprintf(p.w, "✓ [%d/%d] %s %s\n", i+1, p.steps, name, duration)
+		default:
+			fmt.Fprintf(p.w, "✗ [%d/%d] %s %s, exit code %d\n", i+1, p.steps, name, duration, result.ExitCode)
+		}
+		return
+	}
+	if result.Skipped {
+		p.logf("step %d/%d %s name=%q reason=%s", i+1, p.steps, status, name, result.SkipReason)
+		return
+	}
+	p.logf("step %d/%d %s name=%q exit_code=%d duration=%s", i+1, p.steps, status, name, result.ExitCode, duration)
+}
+
+// RunFinished reports how the workflow ended.
+func (p *progress) RunFinished(status string, elapsed time.Duration) {
+	ms := elapsed.Milliseconds()
+	switch {
+	case p.JSON():
+		p.emit(progressEvent{Event: eventRunFinished, Status: status, DurationMS: &ms})
+	case p.tty:
+		// The closing message says how the run ended
+	default:
+		p.logf("run %s workflow=%q duration=%s", status, p.workflow, elapsed.Round(100*time.Millisecond))
+	}
+}
+
+func (p *progress) logf(format string, args ...any) {
+	fmt.Fprintf(p.w, "%s %s\n", p.now().UTC().Format(time.RFC3339), fmt.Sprintf(format, args...))
+}
+
+func (p *progress) emit(ev progressEvent) {
+	ev.Time = p.now().UTC()
+	ev.Workflow, ev.WorkflowID = p.workflow, p.id
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return
+	}
+	fmt.Fprintln(p.w, string(data))
+}
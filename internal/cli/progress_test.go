@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	//nolint:staticcheck // SA1019 - Using runner for StepResult type
+	runnerpkg "github.com/chazuruo/svf/internal/runner"
+)
+
+// reportRun reports a three-step run: one step that succeeds, one that
+// is skipped for its platform, and one that runs over budget and fails.
+func reportRun(format string, tty bool) string {
+	var buf bytes.Buffer
+	p := newProgress(&buf, format, tty)
+	p.now = func() time.Time { return time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC) }
+
+	p.RunStarted("Deploy", "wf_deploy", 3)
+	p.StepStarted(0, "build", "make build")
+	p.StepFinished(0, "build", runnerpkg.StepResult{Step: 0, Success: true, Duration: 1234 * time.Millisecond}, "built\n")
+	p.StepFinished(1, "notify", runnerpkg.PlatformSkip(1), "")
+	p.StepStarted(2, "push", "docker push app")
+	p.StepOverBudget(2, "push", 30*time.Second)
+	p.StepFinished(2, "push", runnerpkg.StepResult{Step: 2, ExitCode: 1, Duration: 300 * time.Millisecond, Error: errors.New("exit status 1")}, "denied\n")
+	p.RunFinished(statusFailed, 2*time.Second)
+	return buf.String()
+}
+
+func TestProgress_Terminal(t *testing.T) {
+	want := `▶ Deploy (3 steps)
+→ [1/3] build
+✓ [1/3] build 1.2s
+○ [2/3] notify skipped (platform)
+→ [3/3] push
+⚠ [3/3] push is taking longer than the expected 30s
+✗ [3/3] push 300ms, exit code 1
+`
+	if got := reportRun(logFormatText, true); got != want {
+		t.Errorf("terminal progress =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestProgress_Plain(t *testing.T) {
+	want := `2026-10-16T09:30:00Z run started workflow="Deploy" steps=3
+2026-10-16T09:30:00Z step 1/3 started name="build"
+2026-10-16T09:30:00Z step 1/3 succeeded name="build" exit_code=0 duration=1.2s
+2026-10-16T09:30:00Z step 2/3 skipped name="notify" reason=platform
+2026-10-16T09:30:00Z step 3/3 started name="push"
+2026-10-16T09:30:00Z step 3/3 over_budget name="push" max_duration=30s
+2026-10-16T09:30:00Z step 3/3 failed name="push" exit_code=1 duration=300ms
+2026-10-16T09:30:00Z run failed workflow="Deploy" duration=2s
+`
+	if got := reportRun(logFormatText, false); got != want {
+		t.Errorf("plain progress =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestProgress_JSON(t *testing.T) {
+	// The format doesn't depend on the terminal
+	out := reportRun(logFormatJSON, true)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+
+	var events []progressEvent
+	for _, line := range lines {
+		var ev progressEvent
+		if err := json.Unmarshal([]byte(line), &ev); err != nil {
+			t.Fatalf("line %q is not JSON: %v", line, err)
+		}
+		if ev.Workflow != "Deploy" || ev.WorkflowID != "wf_deploy" {
+			t.Errorf("event %q doesn't name its workflow", line)
+		}
+		events = append(events, ev)
+	}
+
+	var names []string
+	for _, ev := range events {
+		names = append(names, ev.Event)
+	}
+	want := "run_started step_started step_finished step_finished step_started step_over_budget step_finished run_finished"
+	if got := strings.Join(names, " "); got != want {
+		t.Fatalf("events = %s, want %s", got, want)
+	}
+
+	if over := events[5]; over.Step != 3 || over.MaxDurationMS == nil || *over.MaxDurationMS != 30000 {
+		t.Errorf("unexpected step_over_budget event: %s", lines[5])
+	}
+	failed := events[6]
+	if failed.Step != 3 || failed.Status != statusFailed || failed.ExitCode == nil || *failed.ExitCode != 1 ||
+		failed.Output != "denied\n" || failed.Error != "exit status 1" || *failed.DurationMS != 300 {
+		t.Errorf("unexpected step_finished event: %s", lines[6])
+	}
+	if skipped := events[3]; skipped.Status != statusSkipped || skipped.Reason != runnerpkg.SkipPlatform || skipped.ExitCode != nil {
+		t.Errorf("unexpected skipped event: %s", lines[3])
+	}
+	if events[7].Status != statusFailed {
+		t.Errorf("unexpected run_finished event: %s", lines[7])
+	}
+}
@@ -0,0 +1,335 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/index"
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+// fetchTimeout bounds fetches made while saving, so an unreachable remote
+// doesn't hold up the save.
+const fetchTimeout = 30 * time.Second
+
+// saveAndPublish saves a workflow and applies the configured git flow.
+// In direct mode the change is committed on the current branch and pushed
+// when git.push_on_save is set. In PR mode it is committed on a new
+// feature branch in a separate worktree, which is pushed for review. The
+// workflow's search index entry is refreshed when the main checkout
+// changes.
+func saveAndPublish(ctx context.Context, repo gitrepo.Repo, str store.Store, cfg *config.Config, wf *workflows.Workflow, opts store.SaveOptions) (store.WorkflowRef, error) {
+	opts.AllowSecrets = opts.AllowSecrets || AllowSecrets
+
+	// Don't overwrite changes others made since the workflow was loaded
+	wf, err := mergeChangesSinceLoad(ctx, repo, str, cfg, wf, &opts)
+	if err != nil {
+		return store.WorkflowRef{}, err
+	}
+
+	if !opts.Commit {
+		ref, err := str.Save(ctx, wf, opts)
+		if err != nil {
+			return store.WorkflowRef{}, err
+		}
+		refreshIndexEntry(cfg, ref.Path)
+		return ref, nil
+	}
+
+	if cfg.Identity.Mode == "pr" {
+		if opts.Amend {
+			return store.WorkflowRef{}, fmt.Errorf("--amend is only supported in direct mode; in PR mode each save goes on a new feature branch")
+		}
+		return saveOnFeatureBranch(ctx, repo, cfg, wf, opts)
+	}
+
+	ref, err := str.Save(ctx, wf, opts)
+	if err != nil {
+		return store.WorkflowRef{}, err
+	}
+	refreshIndexEntry(cfg, ref.Path)
+
+	if cfg.Git.PushOnSave {
+		branch, err := repo.GetCurrentBranch(ctx)
+		if err == nil {
+			err = repo.Push(ctx, cfg.Repo.Remote, branch)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to push: %v\n", err)
+		} else {
+			fmt.Printf("✓ Pushed %s to %s\n", branch, cfg.Repo.Remote)
+		}
+	}
+
+	return ref, nil
+}
+
+// saveOnFeatureBranch commits a workflow on a new feature branch in a
+// temporary worktree and pushes it. The main checkout never switches
+// branches, so workflows running from the repo path aren't disturbed.
+func saveOnFeatureBranch(ctx context.Context, repo gitrepo.Repo, cfg *config.Config, wf *workflows.Workflow, opts store.SaveOptions) (store.WorkflowRef, error) {
+	var ref store.WorkflowRef
+	err := onFeatureBranch(ctx, repo, cfg, store.Slugify(wf.Title), func(wtRepo gitrepo.Repo, wtStore store.Store, dir string) error {
+		if opts.Path != "" {
+			opts.Path = rebasePath(opts.Path, cfg.Repo.Path, dir)
+		}
+
+		var err error
+		ref, err = wtStore.Save(ctx, wf, opts)
+		if err != nil {
+			return err
+		}
+		ref.Path = rebasePath(ref.Path, dir, cfg.Repo.Path)
+		return nil
+	})
+	return ref, err
+}
+
+// deleteAndPublish deletes a workflow and applies the configured git flow
+// like saveAndPublish: the removal is committed on the current branch in
+// direct mode, or on a feature branch in PR mode, where the main checkout
+// keeps the workflow until the branch is merged.
+func deleteAndPublish(ctx context.Context, repo gitrepo.Repo, str store.Store, cfg *config.Config, ref store.WorkflowRef, message string) error {
+	if cfg.Identity.Mode == "pr" {
+		return onFeatureBranch(ctx, repo, cfg, "delete-"+ref.Slug, func(wtRepo gitrepo.Repo, wtStore store.Store, dir string) error {
+			wtRef := ref
+			wtRef.Path = rebasePath(ref.Path, cfg.Repo.Path, dir)
+			if _, err := os.Stat(wtRef.Path); os.IsNotExist(err) {
+				return fmt.Errorf("%s is not committed yet; remove it with --no-commit", ref.Location())
+			}
+			return commitDelete(ctx, wtRepo, wtStore, wtRef, message)
+		})
+	}
+
+	if err := commitDelete(ctx, repo, str, ref, message); err != nil {
+		return err
+	}
+	refreshIndexEntry(cfg, ref.Path)
+
+	if cfg.Git.PushOnSave {
+		branch, err := repo.GetCurrentBranch(ctx)
+		if err == nil {
+			err = repo.Push(ctx, cfg.Repo.Remote, branch)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to push: %v\n", err)
+		} else {
+			fmt.Printf("✓ Pushed %s to %s\n", branch, cfg.Repo.Remote)
+		}
+	}
+	return nil
+}
+
+// commitDelete deletes a workflow and commits the removal.
+func commitDelete(ctx context.Context, repo gitrepo.Repo, str store.Store, ref store.WorkflowRef, message string) error {
+	dir := filepath.Dir(ref.Path)
+	// Stage the directory first so an uncommitted workflow leaves no trace
+	if err := repo.Add(ctx, dir); err != nil {
+		return fmt.Errorf("failed to add %s: %w", dir, err)
+	}
+	if err := str.Delete(ctx, ref); err != nil {
+		return err
+	}
+	if err := repo.Add(ctx, dir); err != nil {
+		return fmt.Errorf("failed to add %s: %w", dir, err)
+	}
+	if !hasStagedChanges(ctx, repo) {
+		// The workflow was never committed
+		return nil
+	}
+	if _, err := repo.CommitAll(ctx, message); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+	return nil
+}
+
+// hasStagedChanges reports whether the index differs from HEAD. It errs on
+// the side of true when the status can't be read.
+func hasStagedChanges(ctx context.Context, repo gitrepo.Repo) bool {
+	status, err := repo.Status(ctx)
+	if err != nil {
+		return true
+	}
+	for _, e := range status.Entries {
+		if e.X != ' ' && e.X != '?' && e.X != '!' {
+			return true
+		}
+	}
+	return false
+}
+
+// workflowEdit is a changed workflow to save in place at Path.
+type workflowEdit struct {
+	Workflow *workflows.Workflow
+	Path     string
+	Doc      int // Document in a multi-document file, or 0
+}
+
+// saveAllAndPublish saves several existing workflows and applies the
+// configured git flow with a single commit: on the current branch in
+// direct mode, or on one feature branch named after slug in PR mode.
+func saveAllAndPublish(ctx context.Context, repo gitrepo.Repo, str store.Store, cfg *config.Config, edits []workflowEdit, slug, message string) error {
+	if cfg.Identity.Mode == "pr" {
+		return onFeatureBranch(ctx, repo, cfg, slug, func(wtRepo gitrepo.Repo, wtStore store.Store, dir string) error {
+			rebased := make([]workflowEdit, len(edits))
+			for i, edit := range edits {
+				rebased[i] = workflowEdit{Workflow: edit.Workflow, Path: rebasePath(edit.Path, cfg.Repo.Path, dir), Doc: edit.Doc}
+			}
+			return commitEdits(ctx, wtRepo, wtStore, rebased, message)
+		})
+	}
+
+	if err := commitEdits(ctx, repo, str, edits, message); err != nil {
+		return err
+	}
+	paths := make([]string, len(edits))
+	for i, edit := range edits {
+		paths[i] = edit.Path
+	}
+	refreshIndexEntry(cfg, paths...)
+
+	if cfg.Git.PushOnSave {
+		branch, err := repo.GetCurrentBranch(ctx)
+		if err == nil {
+			err = repo.Push(ctx, cfg.Repo.Remote, branch)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to push: %v\n", err)
+		} else {
+			fmt.Printf("✓ Pushed %s to %s\n", branch, cfg.Repo.Remote)
+		}
+	}
+	return nil
+}
+
+// commitEdits saves workflows in place and commits them together.
+func commitEdits(ctx context.Context, repo gitrepo.Repo, str store.Store, edits []workflowEdit, message string) error {
+	for _, edit := range edits {
+		if _, err := str.Save(ctx, edit.Workflow, store.SaveOptions{Path: edit.Path, Doc: edit.Doc, AllowSecrets: AllowSecrets}); err != nil {
+			return fmt.Errorf("failed to save %s: %w", edit.Path, err)
+		}
+		// Stage the workflow directory so its README goes with it
+		if err := repo.Add(ctx, filepath.Dir(edit.Path)); err != nil {
+			return fmt.Errorf("failed to add %s: %w", edit.Path, err)
+		}
+	}
+	if _, err := repo.CommitAll(ctx, message); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+	return nil
+}
+
+// onFeatureBranch creates a feature branch off the remote's PR base branch
+// in a temporary worktree, calls fn with a repo and store rooted at the
+// worktree, and pushes the branch once fn has committed to it.
+func onFeatureBranch(ctx context.Context, repo gitrepo.Repo, cfg *config.Config, slug string, fn func(wtRepo gitrepo.Repo, wtStore store.Store, dir string) error) error {
+	branch := featureBranchName(cfg.Git.FeatureBranchTemplate, cfg.Identity.Path, slug, time.Now())
+
+	dir, err := os.MkdirTemp("", "svf-worktree-")
+	if err != nil {
+		return fmt.Errorf("failed to create worktree directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	// Clean up after worktrees left behind by interrupted saves
+	_ = repo.PruneWorktrees(ctx)
+	if err := repo.AddWorktree(ctx, dir, branch, featureBranchStart(ctx, repo, cfg)); err != nil {
+		return fmt.Errorf("failed to create branch %s: %w", branch, err)
+	}
+	defer func() {
+		if err := repo.RemoveWorktree(ctx, dir); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to remove worktree %s: %v\n", dir, err)
+		}
+	}()
+
+	// Save through a store rooted at the worktree
+	wtCfg := *cfg
+	wtCfg.Repo.Path = dir
+	wtRepo := gitrepo.New(dir)
+	wtStore, err := store.New(wtRepo, &wtCfg)
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+
+	if err := fn(wtRepo, wtStore, dir); err != nil {
+		return err
+	}
+
+	if err := wtRepo.Push(ctx, cfg.Repo.Remote, branch); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to push %s: %v\n", branch, err)
+		fmt.Printf("✓ Committed on branch %s; push it and open a pull request against %s\n", branch, cfg.Git.PRBaseBranch)
+		return nil
+	}
+
+	fmt.Printf("✓ Pushed branch %s; open a pull request against %s\n", branch, cfg.Git.PRBaseBranch)
+	return nil
+}
+
+// featureBranchStart fetches the remote and returns the PR base branch as
+// the remote has it, so pull requests hold only the saved change and not
+// unpushed commits of the current branch. It returns "" to branch from
+// HEAD when the remote branch is unknown, e.g. offline before any fetch.
+func featureBranchStart(ctx context.Context, repo gitrepo.Repo, cfg *config.Config) string {
+	if cfg.Repo.Remote == "" {
+		return ""
+	}
+	fetchCtx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	defer cancel()
+	if _, err := repo.Fetch(fetchCtx, cfg.Repo.Remote); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to fetch %s: %v\n", cfg.Repo.Remote, err)
+	}
+
+	start := cfg.Repo.Remote + "/" + cfg.Git.PRBaseBranch
+	if _, err := repo.ResolveRev(ctx, start); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %s not found; branching from HEAD\n", start)
+		return ""
+	}
+	return start
+}
+
+// rebasePath maps a path under one checkout to the same repo-relative path
+// under another. Paths outside from are returned unchanged.
+func rebasePath(path, from, to string) string {
+	rel, err := filepath.Rel(from, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return filepath.Join(to, rel)
+}
+
+// featureBranchName expands a feature branch template. Supported
+// placeholders are {identity}, {date} and {slug}.
+func featureBranchName(template, identity, slug string, now time.Time) string {
+	if template == "" {
+		template = "svf/{identity}/{date}/{slug}"
+	}
+	return strings.NewReplacer(
+		"{identity}", identity,
+		"{date}", now.Format("2006-01-02"),
+		"{slug}", slug,
+	).Replace(template)
+}
+
+// refreshIndexEntry updates the search index entries for workflow files.
+func refreshIndexEntry(cfg *config.Config, paths ...string) {
+	builder := index.NewBuilder(cfg.Repo.Path, cfg)
+	idx, _, err := builder.LoadOrRebuild()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load index: %v\n", err)
+		return
+	}
+	if !builder.Update(idx, paths) {
+		return
+	}
+	if err := builder.Save(idx); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to save index: %v\n", err)
+	}
+}
@@ -0,0 +1,202 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+// setGitIdentity sets a git author and committer for the test.
+func setGitIdentity(t *testing.T) {
+	t.Helper()
+	for _, k := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(k, "Test User")
+	}
+	for _, k := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(k, "test@example.com")
+	}
+}
+
+func TestFeatureBranchName(t *testing.T) {
+	now := time.Date(2025, 3, 9, 12, 0, 0, 0, time.UTC)
+
+	got := featureBranchName("svf/{identity}/{date}/{slug}", "platform/alice", "deploy-api", now)
+	if want := "svf/platform/alice/2025-03-09/deploy-api"; got != want {
+		t.Errorf("featureBranchName() = %q, want %q", got, want)
+	}
+
+	if got := featureBranchName("", "bob", "x", now); !strings.HasPrefix(got, "svf/bob/") {
+		t.Errorf("featureBranchName() with empty template = %q", got)
+	}
+}
+
+// TestSaveAndPublish_PRMode verifies that PR mode commits on a feature
+// branch and returns to the original branch.
+func TestSaveAndPublish_PRMode(t *testing.T) {
+	ctx := context.Background()
+	setGitIdentity(t)
+
+	cfg := config.DefaultConfig()
+	cfg.Repo.Path = t.TempDir()
+	cfg.Identity.Path = "team/test"
+	cfg.Identity.Mode = "pr"
+	repo := gitrepo.New(cfg.Repo.Path)
+	if err := repo.Init(ctx, gitrepo.InitOptions{}); err != nil {
+		t.Fatalf("failed to init repo: %v", err)
+	}
+	if err := os.WriteFile(cfg.Repo.Path+"/README.md", []byte("# test\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.AddAll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.CommitAll(ctx, "initial"); err != nil {
+		t.Fatal(err)
+	}
+	base, err := repo.GetCurrentBranch(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	str, err := store.New(repo, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wf := &workflows.Workflow{
+		SchemaVersion: workflows.SchemaVersion,
+		Title:         "Deploy API",
+		Steps:         []workflows.Step{{Name: "run", Command: "echo deploy"}},
+	}
+
+	// An uncommitted change in the main checkout must survive the save
+	scratch := filepath.Join(cfg.Repo.Path, "scratch.txt")
+	if err := os.WriteFile(scratch, []byte("in progress\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// There is no remote, so the push fails with a warning only
+	ref, err := saveAndPublish(ctx, repo, str, cfg, wf, store.SaveOptions{Commit: true})
+	if err != nil {
+		t.Fatalf("saveAndPublish() error = %v", err)
+	}
+
+	if branch, _ := repo.GetCurrentBranch(ctx); branch != base {
+		t.Errorf("current branch = %s, want %s", branch, base)
+	}
+	if !strings.HasPrefix(ref.Path, cfg.Repo.Path) {
+		t.Errorf("ref.Path = %s, want a path under %s", ref.Path, cfg.Repo.Path)
+	}
+	if _, err := os.Stat(ref.Path); !os.IsNotExist(err) {
+		t.Error("workflow should only exist on the feature branch")
+	}
+	if data, err := os.ReadFile(scratch); err != nil || string(data) != "in progress\n" {
+		t.Errorf("main checkout disturbed: scratch.txt = %q, %v", data, err)
+	}
+
+	branch := featureBranchName(cfg.Git.FeatureBranchTemplate, cfg.Identity.Path, "deploy-api", time.Now())
+	rel, err := filepath.Rel(cfg.Repo.Path, ref.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.ShowFile(ctx, branch, rel); err != nil {
+		t.Errorf("workflow missing on feature branch %s: %v", branch, err)
+	}
+
+	// The commit holds the workflow only, not the worktree's lock file
+	files, err := exec.Command("git", "-C", cfg.Repo.Path, "show", "--name-only", "--format=", branch).Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range strings.Fields(string(files)) {
+		if !strings.HasPrefix(f, filepath.ToSlash(filepath.Dir(rel))+"/") {
+			t.Errorf("feature branch commit contains %s, want only files of the workflow", f)
+		}
+	}
+
+	// The temporary worktree is cleaned up
+	out, err := exec.Command("git", "-C", cfg.Repo.Path, "worktree", "list").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(strings.Split(strings.TrimSpace(string(out)), "\n")); n != 1 {
+		t.Errorf("git worktree list shows %d worktrees, want 1:\n%s", n, out)
+	}
+}
+
+// TestSaveAndPublish_PRModeBranchesFromBase verifies that PR mode branches
+// from the remote's PR base branch, leaving unpushed commits of the
+// current branch out of the pull request.
+func TestSaveAndPublish_PRModeBranchesFromBase(t *testing.T) {
+	ctx := context.Background()
+	setGitIdentity(t)
+
+	remote := t.TempDir()
+	if out, err := exec.Command("git", "init", "--bare", "-q", remote).CombinedOutput(); err != nil {
+		t.Fatalf("git init --bare: %v\n%s", err, out)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Repo.Path = t.TempDir()
+	cfg.Identity.Path = "team/test"
+	cfg.Identity.Mode = "pr"
+	repo := gitrepo.New(cfg.Repo.Path)
+	if err := repo.Init(ctx, gitrepo.InitOptions{}); err != nil {
+		t.Fatalf("failed to init repo: %v", err)
+	}
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = cfg.Repo.Path
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	commit := func(name string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(cfg.Repo.Path, name), []byte(name+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		git("add", name)
+		git("commit", "-q", "-m", name)
+	}
+	commit("README.md")
+	git("remote", "add", cfg.Repo.Remote, remote)
+	git("push", "-q", cfg.Repo.Remote, "HEAD:refs/heads/"+cfg.Git.PRBaseBranch)
+	base := git("rev-parse", "HEAD")
+	commit("unpushed.txt")
+
+	str, err := store.New(repo, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wf := &workflows.Workflow{
+		SchemaVersion: workflows.SchemaVersion,
+		Title:         "Deploy API",
+		Steps:         []workflows.Step{{Name: "run", Command: "echo deploy"}},
+	}
+	if _, err := saveAndPublish(ctx, repo, str, cfg, wf, store.SaveOptions{Commit: true}); err != nil {
+		t.Fatalf("saveAndPublish() error = %v", err)
+	}
+
+	branch := featureBranchName(cfg.Git.FeatureBranchTemplate, cfg.Identity.Path, "deploy-api", time.Now())
+	if parent := git("rev-parse", branch+"^"); parent != base {
+		t.Errorf("feature branch parent = %s, want %s/%s at %s", parent, cfg.Repo.Remote, cfg.Git.PRBaseBranch, base)
+	}
+	if _, err := repo.ShowFile(ctx, branch, "unpushed.txt"); err == nil {
+		t.Error("feature branch contains the unpushed commit")
+	}
+	if pushed := git("ls-remote", cfg.Repo.Remote, branch); pushed == "" {
+		t.Errorf("branch %s wasn't pushed", branch)
+	}
+}
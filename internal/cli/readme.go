@@ -0,0 +1,144 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+// ReadmeRegenOptions contains the options for the readme regen command.
+type ReadmeRegenOptions struct {
+	ConfigPath string
+	All        bool
+	NoCommit   bool
+}
+
+// NewReadmeCommand creates the readme command.
+func NewReadmeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "readme",
+		Short: "Manage generated workflow READMEs",
+		Long: `Manage the README.md generated next to each workflow.
+
+READMEs are rendered on every save from a Go text/template. The template
+is read from workflows.readme_template in the config, or from
+.svf/templates/README.md.tmpl in the repository when present; otherwise a
+built-in template is used.
+
+Templates can use the workflow's fields ({{.Title}}, {{.Description}},
+{{.Tags}}, {{.Steps}}, {{.Placeholders}}, {{.Defaults}}) as well as
+{{.Slug}}, {{.Path}}, {{.PlaceholderNames}}, {{.StepsTable}} (a Markdown
+table of steps) and {{.PlaceholderDocs}} (a Markdown list of
+placeholders). The functions join, stepName and inc are available.`,
+	}
+
+	cmd.AddCommand(newReadmeRegenCommand())
+
+	return cmd
+}
+
+// newReadmeRegenCommand creates the readme regen command.
+func newReadmeRegenCommand() *cobra.Command {
+	opts := &ReadmeRegenOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "regen [workflow-ref]",
+		Short: "Regenerate workflow READMEs from the current template",
+		Long: `Regenerate README.md for one workflow, or for every workflow with --all,
+and commit the changed files.`,
+		Example: `  svf readme regen deploy-api
+  svf readme regen --all`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ref := ""
+			if len(args) > 0 {
+				ref = args[0]
+			}
+			if (ref == "") == !opts.All {
+				return fmt.Errorf("specify a workflow reference or --all")
+			}
+			return runReadmeRegen(opts, ref)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ConfigPath, "config", "", "config file path")
+	cmd.Flags().BoolVar(&opts.All, "all", false, "regenerate the README of every workflow")
+	cmd.Flags().BoolVar(&opts.NoCommit, "no-commit", false, "do not commit the changes")
+
+	return cmd
+}
+
+func runReadmeRegen(opts *ReadmeRegenOptions, refStr string) error {
+	ctx := context.Background()
+
+	// Load config
+	cfg, err := loadConfig(opts.ConfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Open repo
+	repo := gitrepo.New(cfg.Repo.Path)
+	if !repo.IsInitialized(ctx) {
+		return fmt.Errorf("repository not initialized. Run 'svf init' first")
+	}
+
+	// Create store
+	str, err := store.New(repo, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+
+	var refs []store.WorkflowRef
+	if opts.All {
+		refs, err = str.List(ctx, store.Filter{})
+		if err != nil {
+			return fmt.Errorf("failed to list workflows: %w", err)
+		}
+	} else {
+		ref, err := resolveWorkflowRef(ctx, str, cfg, refStr)
+		if err != nil {
+			return err
+		}
+		refs = []store.WorkflowRef{ref}
+	}
+
+	changed := 0
+	for _, ref := range refs {
+		rel, err := filepath.Rel(cfg.Repo.Path, filepath.Dir(ref.Path))
+		if err != nil {
+			rel = ref.Path
+		}
+
+		updated, err := str.RegenerateReadme(ctx, ref)
+		if err != nil {
+			return fmt.Errorf("failed to regenerate README for %s: %w", rel, err)
+		}
+		if updated {
+			fmt.Printf("✓ %s\n", filepath.Join(rel, "README.md"))
+			changed++
+		}
+	}
+
+	if changed == 0 {
+		fmt.Println("All READMEs are up to date.")
+		return nil
+	}
+
+	if !opts.NoCommit {
+		if err := repo.AddAll(ctx); err != nil {
+			return fmt.Errorf("failed to add files: %w", err)
+		}
+		if _, err := repo.CommitAll(ctx, fmt.Sprintf("Regenerate README for %d workflow(s)", changed)); err != nil {
+			return fmt.Errorf("failed to commit: %w", err)
+		}
+	}
+
+	fmt.Printf("\n✓ Regenerated %d README(s)\n", changed)
+	return nil
+}
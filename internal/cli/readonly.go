@@ -0,0 +1,63 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// annotationWritesRepo marks commands that write to the workflow repo.
+const annotationWritesRepo = "svf/writes-repo"
+
+// repoWriters are the paths of the commands that write to the workflow
+// repo. In read-only mode they are hidden and refused. Commands that only
+// write with a flag, like 'grep --replace' and 'status --fix', refuse it
+// themselves, the store refuses every write regardless, and the search
+// index is rebuilt in memory without being saved.
+var repoWriters = []string{
+	"ask",
+	"edit",
+	"delete",
+	"copy",
+	"import",
+	"record",
+	"history",
+	"whoops",
+	"alias-id",
+	"ids assign",
+	"index",
+	"readme regen",
+	"gc",
+	"release create",
+	"review approve",
+}
+
+// markRepoWriters annotates the commands in repoWriters under root, hiding
+// them from help and completion when readOnly is set.
+func markRepoWriters(root *cobra.Command, readOnly bool) {
+	for _, path := range repoWriters {
+		cmd, _, err := root.Find(strings.Fields(path))
+		if err != nil || cmd.CommandPath() != root.Name()+" "+path {
+			continue
+		}
+		if cmd.Annotations == nil {
+			cmd.Annotations = map[string]string{}
+		}
+		cmd.Annotations[annotationWritesRepo] = "true"
+		cmd.Hidden = readOnly
+	}
+}
+
+// refuseRepoWriter returns an error if cmd, or a command it belongs to,
+// writes to the repo. It guards read-only mode against hidden commands
+// being run anyway.
+func refuseRepoWriter(cmd *cobra.Command) error {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c.Annotations[annotationWritesRepo] != "" {
+			return fmt.Errorf("'%s' is disabled in read-only mode (readonly = true in the config)", cmd.CommandPath())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewRootCommand_ReadOnly(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("GITSAVVY_READONLY", "")
+	path := filepath.Join(home, ".config", "svf", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("readonly = true\n\n[identity]\npath = \"team/alice\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	root := NewRootCommand("svf", BuildInfo{})
+	for _, path := range repoWriters {
+		cmd, _, err := root.Find(strings.Fields(path))
+		if err != nil || cmd.CommandPath() != "svf "+path {
+			t.Errorf("svf %s not registered", path)
+			continue
+		}
+		if !cmd.Hidden {
+			t.Errorf("svf %s not hidden in read-only mode", path)
+		}
+	}
+	for _, name := range []string{"run", "sync", "search", "view"} {
+		if cmd, _, _ := root.Find([]string{name}); cmd.Hidden {
+			t.Errorf("svf %s hidden in read-only mode", name)
+		}
+	}
+
+	for _, args := range [][]string{
+		{"alias-id", "add", "wf", "short"},
+		{"ask", "--prompt", "restart nginx", "--no-tui"},
+		{"grep", "registry.old", "--replace", "registry.new", "--yes"},
+		{"index"},
+		{"status", "--fix"},
+	} {
+		root := NewRootCommand("svf", BuildInfo{})
+		root.SetArgs(args)
+		root.SetOut(io.Discard)
+		root.SetErr(io.Discard)
+		err := root.Execute()
+		if err == nil || !strings.Contains(err.Error(), "read-only mode") {
+			t.Errorf("svf %s: Execute() error = %v, want it refused in read-only mode", strings.Join(args, " "), err)
+		}
+	}
+
+	t.Setenv("GITSAVVY_READONLY", "false")
+	root = NewRootCommand("svf", BuildInfo{})
+	if cmd, _, _ := root.Find([]string{"edit"}); cmd.Hidden {
+		t.Error("svf edit hidden with read-only mode off")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/chazuruo/svf/internal/config"
 	"github.com/chazuruo/svf/internal/gitrepo"
 	"github.com/chazuruo/svf/internal/history"
+	"github.com/chazuruo/svf/internal/placeholders"
 	"github.com/chazuruo/svf/internal/recorder"
 	"github.com/chazuruo/svf/internal/tui"
 	"github.com/chazuruo/svf/internal/workflows"
@@ -31,6 +33,9 @@ type RecordOptions struct {
 	Draft      bool
 	NoCommit   bool
 	NoTUI      bool
+	NoSuggest  bool
+
+	captureOptions
 }
 
 // NewRecordCommand creates the record command.
@@ -62,6 +67,9 @@ Example:
 	cmd.Flags().StringVar(&opts.Identity, "identity", "", "identity path override")
 	cmd.Flags().BoolVar(&opts.Draft, "draft", false, "save as draft (don't commit)")
 	cmd.Flags().BoolVar(&opts.NoCommit, "no-commit", false, "skip git commit after saving")
+	cmd.Flags().BoolVar(&opts.NoSuggest, "no-suggest", false, "don't suggest placeholders for repeated values")
+	cmd.Flags().BoolVar(&opts.CaptureContext, "capture-context", false, "save the OS and shell in the workflow's metadata")
+	cmd.Flags().StringSliceVar(&opts.CaptureVersions, "capture-versions", nil, "save these tools' versions in the workflow's metadata (e.g. kubectl,terraform)")
 
 	return cmd
 }
@@ -138,6 +146,27 @@ func runRecord(opts *RecordOptions) error {
 
 	// Convert captured commands to a workflow
 	workflow := commandsToWorkflow(commands, opts.Title, opts.Desc, opts.Tags)
+	opts.capture(context.Background(), workflow, session.Shell)
+
+	if !opts.NoSuggest {
+		if err := suggestPlaceholders(workflow, GetInteractionMode(nil)); err != nil {
+			return err
+		}
+	}
+
+	// Without a TUI, review the workflow with line prompts
+	if GetInteractionMode(nil) == ModeLine {
+		saved, err := tui.EditWorkflowLine(workflow, tui.NewStdioLinePrompter())
+		if err != nil {
+			return fmt.Errorf("failed to edit workflow: %w", err)
+		}
+		if !saved {
+			fmt.Printf("Workflow discarded without saving.\n")
+			return nil
+		}
+		printRecordedWorkflow(workflow)
+		return nil
+	}
 
 	// Launch workflow editor
 	ctx := context.Background()
@@ -168,18 +197,23 @@ func runRecord(opts *RecordOptions) error {
 
 	// TODO: Save workflow to file
 	// For now, just show what was saved
-	fmt.Printf("\nWorkflow saved with %d steps:\n", len(finalWorkflow.Steps))
-	fmt.Printf("Title: %s\n", finalWorkflow.Title)
-	if finalWorkflow.Description != "" {
-		fmt.Printf("Description: %s\n", finalWorkflow.Description)
-	}
-	if len(finalWorkflow.Tags) > 0 {
-		fmt.Printf("Tags: %s\n", strings.Join(finalWorkflow.Tags, ", "))
-	}
+	printRecordedWorkflow(finalWorkflow)
 
 	return nil
 }
 
+// printRecordedWorkflow shows a summary of a recorded workflow.
+func printRecordedWorkflow(wf *workflows.Workflow) {
+	fmt.Printf("\nWorkflow saved with %d steps:\n", len(wf.Steps))
+	fmt.Printf("Title: %s\n", wf.Title)
+	if wf.Description != "" {
+		fmt.Printf("Description: %s\n", wf.Description)
+	}
+	if len(wf.Tags) > 0 {
+		fmt.Printf("Tags: %s\n", strings.Join(wf.Tags, ", "))
+	}
+}
+
 // parseCaptureFile parses the capture file and returns commands.
 func parseCaptureFile(path string) ([]CapturedCommand, error) {
 	file, err := os.Open(path)
@@ -194,7 +228,8 @@ func parseCaptureFile(path string) ([]CapturedCommand, error) {
 		return nil, fmt.Errorf("failed to read capture file: %w", err)
 	}
 
-	// Parse the file - format is timestamp\x1Fcwd\x1Fcommand
+	// Parse the file - format is timestamp\x1Fcwd\x1Fcommand, followed by
+	// \x1Fstatus\x1Fhost\x1Fsession from hooks that record them
 	var commands []CapturedCommand
 	lines := strings.Split(string(data), "\n")
 
@@ -206,7 +241,7 @@ func parseCaptureFile(path string) ([]CapturedCommand, error) {
 
 		// Split by unit separator (0x1F)
 		parts := strings.Split(line, "\x1F")
-		if len(parts) != 3 {
+		if len(parts) < 3 {
 			continue
 		}
 
@@ -222,6 +257,13 @@ func parseCaptureFile(path string) ([]CapturedCommand, error) {
 			CWD:       parts[1],
 			Command:   parts[2],
 		}
+		if len(parts) >= 6 {
+			if status, err := strconv.Atoi(parts[3]); err == nil {
+				cmd.ExitStatus = &status
+			}
+			cmd.Host = parts[4]
+			cmd.Session = parts[5]
+		}
 		commands = append(commands, cmd)
 	}
 
@@ -240,6 +282,9 @@ type RecordHistoryOptions struct {
 	Identity   string
 	Draft      bool
 	NoCommit   bool
+	NoSuggest  bool
+
+	captureOptions
 }
 
 // NewRecordHistoryCommand creates the record history command.
@@ -268,6 +313,9 @@ Selected commands are converted into workflow steps.`,
 	cmd.Flags().StringVar(&opts.Identity, "identity", "", "identity path override")
 	cmd.Flags().BoolVar(&opts.Draft, "draft", false, "save as draft (don't commit)")
 	cmd.Flags().BoolVar(&opts.NoCommit, "no-commit", false, "skip git commit after saving")
+	cmd.Flags().BoolVar(&opts.NoSuggest, "no-suggest", false, "don't suggest placeholders for repeated values")
+	cmd.Flags().BoolVar(&opts.CaptureContext, "capture-context", false, "save the OS and shell in the workflow's metadata")
+	cmd.Flags().StringSliceVar(&opts.CaptureVersions, "capture-versions", nil, "save these tools' versions in the workflow's metadata (e.g. kubectl,terraform)")
 
 	return cmd
 }
@@ -275,32 +323,9 @@ Selected commands are converted into workflow steps.`,
 func runRecordHistory(opts *RecordHistoryOptions) error {
 	ctx := context.Background()
 
-	// Detect shell if not specified
-	shell := opts.Shell
-	if shell == "" {
-		shell = history.DetectShell()
-	}
-
-	// Create parser
-	parser := history.NewParser(shell, opts.Limit)
-
-	// Parse history
-	var commands []history.Command
-	var err error
-
-	if opts.Since != "" {
-		// Parse duration (e.g., "1h", "1d")
-		duration, parseErr := time.ParseDuration(opts.Since)
-		if parseErr != nil {
-			return fmt.Errorf("failed to parse duration %q: %w", opts.Since, parseErr)
-		}
-		commands, err = parser.ParseSince(duration)
-	} else {
-		commands, err = parser.Parse()
-	}
-
+	commands, shell, err := loadHistory(opts.Shell, opts.Limit, opts.Since)
 	if err != nil {
-		return fmt.Errorf("failed to parse history: %w", err)
+		return err
 	}
 
 	if len(commands) == 0 {
@@ -317,25 +342,33 @@ func runRecordHistory(opts *RecordHistoryOptions) error {
 		return nil
 	}
 
-	// Launch history picker TUI
-	picker := tui.NewHistoryPickerModel(commands)
-	p := tea.NewProgram(picker, tea.WithAltScreen())
+	// Pick commands, falling back to line prompts without a TUI
+	var selectedCommands []history.Command
+	if GetInteractionMode(nil) == ModeLine {
+		selectedCommands, err = tui.PickHistoryLine(commands, tui.NewStdioLinePrompter())
+		if err != nil {
+			return fmt.Errorf("failed to select commands: %w", err)
+		}
+	} else {
+		picker := tui.NewHistoryPickerModel(commands)
+		p := tea.NewProgram(picker, tea.WithAltScreen())
 
-	finalModel, err := p.Run()
-	if err != nil {
-		return fmt.Errorf("failed to run TUI: %w", err)
-	}
+		finalModel, err := p.Run()
+		if err != nil {
+			return fmt.Errorf("failed to run TUI: %w", err)
+		}
 
-	finalPicker := finalModel.(tui.HistoryPickerModel)
+		finalPicker := finalModel.(tui.HistoryPickerModel)
 
-	// Handle quit without selection
-	if finalPicker.DidQuit() {
-		fmt.Println("Quit without selecting commands.")
-		return nil
-	}
+		// Handle quit without selection
+		if finalPicker.DidQuit() {
+			fmt.Println("Quit without selecting commands.")
+			return nil
+		}
 
-	// Get selected commands
-	selectedCommands := finalPicker.GetSelectedCommands()
+		// Get selected commands
+		selectedCommands = finalPicker.GetSelectedCommands()
+	}
 	if len(selectedCommands) == 0 {
 		fmt.Println("No commands selected.")
 		return nil
@@ -359,6 +392,13 @@ func runRecordHistory(opts *RecordHistoryOptions) error {
 	if wf.Title == "" {
 		wf.Title = fmt.Sprintf("Workflow from %s history", shell)
 	}
+	opts.capture(ctx, wf, shell)
+
+	if !opts.NoSuggest {
+		if err := suggestPlaceholders(wf, GetInteractionMode(nil)); err != nil {
+			return err
+		}
+	}
 
 	// Load config for saving
 	cfg, err := config.Load(opts.ConfigPath)
@@ -385,7 +425,8 @@ func runRecordHistory(opts *RecordHistoryOptions) error {
 
 	// Save workflow
 	saveOpts := store.SaveOptions{
-		Commit: !opts.NoCommit && !opts.Draft,
+		Commit:       !opts.NoCommit && !opts.Draft,
+		AllowSecrets: AllowSecrets,
 	}
 
 	ref, err := str.Save(ctx, wf, saveOpts)
@@ -397,6 +438,89 @@ func runRecordHistory(opts *RecordHistoryOptions) error {
 	return nil
 }
 
+// suggestPlaceholders offers to turn values repeated across the recorded
+// steps, like hostnames, namespaces and IDs, into placeholders. Accepted
+// suggestions are applied to wf; the rest leave it unchanged.
+func suggestPlaceholders(wf *workflows.Workflow, mode InteractionMode) error {
+	suggestions := placeholders.Suggest(wf)
+	if len(suggestions) == 0 {
+		return nil
+	}
+
+	review := suggestionMatches(wf, suggestions)
+	var ok bool
+	var err error
+	switch mode {
+	case ModeTUI:
+		review, ok, err = tui.RunReplaceReview(review)
+	case ModeLine:
+		review, ok, err = tui.ReviewReplacementsLine(review, tui.NewStdioLinePrompter())
+	default:
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to review placeholder suggestions: %w", err)
+	}
+	if !ok {
+		return nil
+	}
+
+	for i, r := range review {
+		if r.Accepted {
+			placeholders.ApplySuggestion(wf, suggestions[i])
+		}
+	}
+	return nil
+}
+
+// suggestionMatches describes each suggestion as a replacement to review,
+// previewed on the first step that uses it.
+func suggestionMatches(wf *workflows.Workflow, suggestions []placeholders.Suggestion) []tui.ReplaceMatch {
+	matches := make([]tui.ReplaceMatch, len(suggestions))
+	for i, s := range suggestions {
+		command := wf.Steps[s.Steps[0]].Command
+		matches[i] = tui.ReplaceMatch{
+			Location: fmt.Sprintf("<%s> = %s (%d steps)", s.Name, s.Value, len(s.Steps)),
+			Before:   command,
+			After:    placeholders.ReplaceLiteral(command, s.Value, "<"+s.Name+">"),
+		}
+	}
+	return matches
+}
+
+// loadHistory parses shell history, detecting the shell when empty and
+// keeping only commands newer than since (e.g. "1h") when set.
+func loadHistory(shell string, limit int, since string) ([]history.Command, string, error) {
+	// Detect shell if not specified
+	if shell == "" {
+		shell = history.DetectShell()
+	}
+
+	// Create parser
+	parser := history.NewParser(shell, limit)
+
+	// Parse history
+	var commands []history.Command
+	var err error
+
+	if since != "" {
+		// Parse duration (e.g., "1h", "1d")
+		duration, parseErr := time.ParseDuration(since)
+		if parseErr != nil {
+			return nil, shell, fmt.Errorf("failed to parse duration %q: %w", since, parseErr)
+		}
+		commands, err = parser.ParseSince(duration)
+	} else {
+		commands, err = parser.Parse()
+	}
+
+	if err != nil {
+		return nil, shell, fmt.Errorf("failed to parse history: %w", err)
+	}
+
+	return commands, shell, nil
+}
+
 // convertHistoryCommandsToSteps converts history commands to workflow steps.
 func convertHistoryCommandsToSteps(commands []history.Command) []workflows.Step {
 	steps := make([]workflows.Step, len(commands))
@@ -412,23 +536,39 @@ func convertHistoryCommandsToSteps(commands []history.Command) []workflows.Step
 		}
 
 		steps[i] = workflows.Step{
-			Name:    stepName,
-			Command: cmd.Command,
-			CWD:     cmd.CWD,
-			Shell:   cmd.Shell,
+			Name:       stepName,
+			Command:    cmd.Command,
+			CWD:        cmd.CWD,
+			Shell:      cmd.Shell,
+			Provenance: commandProvenance(cmd),
 		}
 	}
 
 	return steps
 }
 
-// CapturedCommand represents a command captured during recording.
-type CapturedCommand struct {
-	Timestamp int64  `json:"timestamp"`
-	CWD       string `json:"cwd"`
-	Command   string `json:"command"`
+// commandProvenance returns the provenance of a recorded command, or nil
+// when nothing is known of where it ran.
+func commandProvenance(cmd history.Command) *workflows.Provenance {
+	p := &workflows.Provenance{
+		Host:       cmd.Host,
+		Session:    cmd.Session,
+		Duration:   cmd.Duration,
+		ExitStatus: cmd.ExitStatus,
+	}
+	if cmd.Timestamp > 0 {
+		p.RanAt = time.Unix(cmd.Timestamp, 0).UTC()
+	}
+	if *p == (workflows.Provenance{}) {
+		return nil
+	}
+	return p
 }
 
+// CapturedCommand represents a command captured during recording. The
+// hooks record its exit status, host and tmux session as well.
+type CapturedCommand = history.Command
+
 // commandsToWorkflow converts captured commands to a workflow.
 func commandsToWorkflow(commands []CapturedCommand, title, desc, tagsStr string) *workflows.Workflow {
 	// Create workflow
@@ -453,9 +593,10 @@ func commandsToWorkflow(commands []CapturedCommand, title, desc, tagsStr string)
 		}
 
 		step := workflows.Step{
-			Name:    fmt.Sprintf("Step %d", i+1),
-			Command: cmd.Command,
-			CWD:     cmd.CWD,
+			Name:       fmt.Sprintf("Step %d", i+1),
+			Command:    cmd.Command,
+			CWD:        cmd.CWD,
+			Provenance: commandProvenance(cmd),
 		}
 
 		// Use command as name if it's short enough
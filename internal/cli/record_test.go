@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chazuruo/svf/internal/history"
+	"github.com/chazuruo/svf/internal/placeholders"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+func TestSuggestionMatches(t *testing.T) {
+	wf := &workflows.Workflow{
+		Steps: []workflows.Step{
+			{Command: "echo start"},
+			{Command: "kubectl -n staging get pods"},
+			{Command: "kubectl -n staging rollout status deploy/api"},
+		},
+	}
+
+	suggestions := placeholders.Suggest(wf)
+	if len(suggestions) != 1 {
+		t.Fatalf("Suggest() = %+v, want one suggestion", suggestions)
+	}
+
+	matches := suggestionMatches(wf, suggestions)
+	if len(matches) != 1 {
+		t.Fatalf("suggestionMatches() returned %d matches, want 1", len(matches))
+	}
+	m := matches[0]
+	if m.Location != "<namespace> = staging (2 steps)" {
+		t.Errorf("Location = %q", m.Location)
+	}
+	if m.Before != "kubectl -n staging get pods" || m.After != "kubectl -n <namespace> get pods" {
+		t.Errorf("Before/After = %q / %q", m.Before, m.After)
+	}
+	if m.Accepted {
+		t.Error("suggestions should start rejected")
+	}
+}
+
+func TestParseCaptureFileMetadata(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "capture")
+	data := "1700000000\x1F/srv\x1Fmake deploy\x1F2\x1Fweb1\x1Fops\n" +
+		"1700000060\x1F/srv\x1Fgit status\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	commands, err := parseCaptureFile(path)
+	if err != nil {
+		t.Fatalf("parseCaptureFile: %v", err)
+	}
+	if len(commands) != 2 {
+		t.Fatalf("expected 2 commands, got %d", len(commands))
+	}
+	first := commands[0]
+	if first.Command != "make deploy" || first.Host != "web1" || first.Session != "ops" {
+		t.Errorf("unexpected first command: %+v", first)
+	}
+	if first.ExitStatus == nil || *first.ExitStatus != 2 {
+		t.Errorf("expected exit status 2, got %v", first.ExitStatus)
+	}
+	if commands[1].ExitStatus != nil || commands[1].Host != "" {
+		t.Errorf("expected no metadata on the old-format line, got %+v", commands[1])
+	}
+}
+
+func TestCommandProvenance(t *testing.T) {
+	status := 0
+	cmd := history.Command{
+		Timestamp:  1700000000,
+		Command:    "make deploy",
+		Duration:   12,
+		ExitStatus: &status,
+		Host:       "web1",
+		Session:    "ops",
+	}
+	steps := convertHistoryCommandsToSteps([]history.Command{cmd})
+	p := steps[0].Provenance
+	if p == nil {
+		t.Fatal("expected provenance on the step")
+	}
+	if !p.RanAt.Equal(time.Unix(1700000000, 0)) || p.Host != "web1" || p.Session != "ops" || p.Duration != 12 {
+		t.Errorf("unexpected provenance: %+v", p)
+	}
+	if p.ExitStatus == nil || *p.ExitStatus != 0 {
+		t.Errorf("expected exit status 0, got %v", p.ExitStatus)
+	}
+
+	if got := commandProvenance(history.Command{Command: "ls"}); got != nil {
+		t.Errorf("expected no provenance without metadata, got %+v", got)
+	}
+}
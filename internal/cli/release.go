@@ -0,0 +1,218 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+// ReleaseOptions contains the options for the release commands.
+type ReleaseOptions struct {
+	ConfigPath string
+	Message    string
+	Push       bool
+}
+
+// NewReleaseCommand creates the release command.
+func NewReleaseCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "release",
+		Short: "Tag snapshots of the workflow repository",
+		Long: `Tag the workflow repository so workflows can be run as they were at that
+point, whatever has changed since.
+
+'svf run <workflow>@<release>' reads the workflow from the tagged commit
+rather than the working copy.`,
+	}
+
+	cmd.AddCommand(newReleaseCreateCommand())
+	cmd.AddCommand(newReleaseListCommand())
+
+	return cmd
+}
+
+// newReleaseCreateCommand creates the release create command.
+func newReleaseCreateCommand() *cobra.Command {
+	opts := &ReleaseOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Tag the current state of the repository",
+		Long: `Create an annotated tag at the last commit of the workflow repository.
+Uncommitted changes are not part of the release.`,
+		Example: `  svf release create v2024.06
+  svf release create v2024.06 -m "Before the database migration" --push`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runReleaseCreate(opts, args[0])
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ConfigPath, "config", "", "config file path")
+	cmd.Flags().StringVarP(&opts.Message, "message", "m", "", "tag message")
+	cmd.Flags().BoolVar(&opts.Push, "push", false, "push the tag to the remote")
+
+	return cmd
+}
+
+// newReleaseListCommand creates the release list command.
+func newReleaseListCommand() *cobra.Command {
+	opts := &ReleaseOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List releases, newest first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runReleaseList(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ConfigPath, "config", "", "config file path")
+
+	return cmd
+}
+
+// loadReleaseConfig loads the config and checks the repository exists.
+func loadReleaseConfig(ctx context.Context, opts *ReleaseOptions) (*config.Config, gitrepo.Repo, error) {
+	var cfg *config.Config
+	var err error
+	if opts.ConfigPath != "" {
+		cfg, err = config.Load(opts.ConfigPath)
+	} else {
+		cfg, err = config.LoadWithDefaults()
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	repo := gitrepo.New(cfg.Repo.Path)
+	if !repo.IsInitialized(ctx) {
+		return nil, nil, fmt.Errorf("repository not initialized. Run 'svf init' first")
+	}
+	return cfg, repo, nil
+}
+
+func runReleaseCreate(opts *ReleaseOptions, name string) error {
+	ctx := context.Background()
+
+	cfg, repo, err := loadReleaseConfig(ctx, opts)
+	if err != nil {
+		return err
+	}
+	if err := createRelease(ctx, repo, name, opts.Message); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Created release %s\n", name)
+
+	if opts.Push {
+		if err := repo.Push(ctx, cfg.Repo.Remote, name); err != nil {
+			return fmt.Errorf("failed to push %s: %w", name, err)
+		}
+		fmt.Printf("✓ Pushed %s to %s\n", name, cfg.Repo.Remote)
+	}
+	return nil
+}
+
+// createRelease tags HEAD as a release, warning when the working copy has
+// changes the release leaves out.
+func createRelease(ctx context.Context, repo gitrepo.Repo, name, message string) error {
+	if _, err := repo.ResolveRev(ctx, "HEAD"); err != nil {
+		return fmt.Errorf("nothing to release: the repository has no commits yet")
+	}
+	if status, err := repo.Status(ctx); err == nil && status.Dirty {
+		fmt.Fprintf(os.Stderr, "Warning: uncommitted changes are not part of the release\n")
+	}
+	if message == "" {
+		message = "svf release " + name
+	}
+	if err := repo.Tag(ctx, name, message); err != nil {
+		return fmt.Errorf("failed to create release %s: %w", name, err)
+	}
+	return nil
+}
+
+func runReleaseList(opts *ReleaseOptions) error {
+	ctx := context.Background()
+
+	_, repo, err := loadReleaseConfig(ctx, opts)
+	if err != nil {
+		return err
+	}
+	tags, err := repo.ListTags(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list releases: %w", err)
+	}
+	if len(tags) == 0 {
+		fmt.Println("No releases. Create one with 'svf release create <name>'.")
+		return nil
+	}
+	for _, tag := range tags {
+		fmt.Println(tag)
+	}
+	return nil
+}
+
+// splitSnapshotRef splits a "<workflow>@<revision>" reference. ok is false
+// for references without a revision.
+func splitSnapshotRef(refStr string) (workflowRef, rev string, ok bool) {
+	i := strings.LastIndex(refStr, "@")
+	if i <= 0 || i == len(refStr)-1 {
+		return refStr, "", false
+	}
+	return refStr[:i], refStr[i+1:], true
+}
+
+// loadSnapshot resolves and loads a workflow as of rev from git, leaving
+// the working copy untouched. Its companion files are written from rev to
+// a temporary directory, set as the item's CompanionDir, which the caller
+// removes after the run.
+func loadSnapshot(ctx context.Context, repo gitrepo.Repo, cfg *config.Config, refStr, rev string) (pipelineItem, error) {
+	revStore, err := store.NewRevisionStore(ctx, repo, cfg, rev)
+	if err != nil {
+		return pipelineItem{}, fmt.Errorf("unknown release, branch or commit %q. Run 'svf release list' to see the releases", rev)
+	}
+	ref, err := store.Resolve(ctx, revStore, cfg.Repo.Path, cfg.Workflows.Root, refStr)
+	if err != nil {
+		return pipelineItem{}, fmt.Errorf("at %s: %w", rev, err)
+	}
+	wf, err := revStore.Load(ctx, ref)
+	if err != nil {
+		return pipelineItem{}, err
+	}
+	item := pipelineItem{Ref: ref, Workflow: wf}
+
+	companions := wf.CompanionFiles()
+	if len(companions) == 0 {
+		return item, nil
+	}
+	dir, err := os.MkdirTemp("", "svf-snapshot-*")
+	if err != nil {
+		return pipelineItem{}, fmt.Errorf("failed to create a directory for companion files: %w", err)
+	}
+	for _, name := range companions {
+		data, err := revStore.ReadFile(ctx, ref, name)
+		if err == nil {
+			dest := filepath.Join(dir, filepath.FromSlash(name))
+			if err = os.MkdirAll(filepath.Dir(dest), 0755); err == nil {
+				// git show doesn't carry file modes, and companions may be
+				// run directly
+				err = os.WriteFile(dest, data, 0755)
+			}
+		}
+		if err != nil {
+			_ = os.RemoveAll(dir)
+			return pipelineItem{}, fmt.Errorf("failed to read companion file %s at %s: %w", name, rev, err)
+		}
+	}
+	item.CompanionDir = dir
+	return item, nil
+}
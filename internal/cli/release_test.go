@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/testutil"
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+func TestSplitSnapshotRef(t *testing.T) {
+	tests := []struct {
+		in      string
+		ref     string
+		rev     string
+		wantRev bool
+	}{
+		{"deploy-api@v2024.06", "deploy-api", "v2024.06", true},
+		{"deploy-api", "deploy-api", "", false},
+		{"deploy-api@", "deploy-api@", "", false},
+		{"@v1", "@v1", "", false},
+	}
+	for _, tt := range tests {
+		ref, rev, ok := splitSnapshotRef(tt.in)
+		if ref != tt.ref || rev != tt.rev || ok != tt.wantRev {
+			t.Errorf("splitSnapshotRef(%q) = %q, %q, %v; want %q, %q, %v", tt.in, ref, rev, ok, tt.ref, tt.rev, tt.wantRev)
+		}
+	}
+}
+
+// TestLoadSnapshot verifies that a workflow is run as of a release or
+// branch, not as in the working copy.
+func TestLoadSnapshot(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.DefaultConfig()
+	cfg.Repo.Path = t.TempDir()
+	cfg.Identity.Path = "team/test"
+	repo := testutil.NewFakeRepo(cfg.Repo.Path)
+	str, err := store.New(repo, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := createRelease(ctx, repo, "v1", ""); err == nil || !strings.Contains(err.Error(), "no commits") {
+		t.Errorf("expected an error releasing an empty repository, got %v", err)
+	}
+
+	wf := &workflows.Workflow{ID: "wf_deploy", Title: "Deploy", SchemaVersion: workflows.SchemaVersion, Steps: []workflows.Step{
+		{Command: "deploy --v1"},
+		{Script: "check.sh"},
+	}}
+	ref, err := str.Save(ctx, wf, store.SaveOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	script := filepath.Join(filepath.Dir(ref.Path), "check.sh")
+	if err := os.WriteFile(script, []byte("echo v1\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.AddAll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.CommitAll(ctx, "v1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := createRelease(ctx, repo, "v1", ""); err != nil {
+		t.Fatalf("createRelease() error = %v", err)
+	}
+
+	// Local edits, uncommitted
+	wf.Steps[0].Command = "deploy --local"
+	if _, err := str.Save(ctx, wf, store.SaveOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(script, []byte("echo local\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, rev := range []string{"v1", "main"} {
+		item, err := loadSnapshot(ctx, repo, cfg, "wf_deploy", rev)
+		if err != nil {
+			t.Fatalf("loadSnapshot(%s) error = %v", rev, err)
+		}
+		if got := item.Workflow.Steps[0].Command; got != "deploy --v1" {
+			t.Errorf("loadSnapshot(%s) command = %q, want the committed deploy --v1", rev, got)
+		}
+		data, err := os.ReadFile(filepath.Join(item.CompanionDir, "check.sh"))
+		if err != nil || string(data) != "echo v1\n" {
+			t.Errorf("loadSnapshot(%s) companion = %q, %v; want the committed script", rev, data, err)
+		}
+		_ = os.RemoveAll(item.CompanionDir)
+	}
+
+	// A workflow gone from the working copy is found at the revision
+	if err := str.Delete(ctx, ref); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadSnapshot(ctx, repo, cfg, "deploy", "v1"); err != nil {
+		t.Errorf("loadSnapshot() of a deleted workflow error = %v", err)
+	}
+
+	if _, err := loadSnapshot(ctx, repo, cfg, "wf_deploy", "v9"); err == nil || !strings.Contains(err.Error(), "unknown release") {
+		t.Errorf("expected an unknown release error, got %v", err)
+	}
+	if _, err := loadSnapshot(ctx, repo, cfg, "wf_missing", "v1"); err == nil || !strings.Contains(err.Error(), "at v1") {
+		t.Errorf("expected a not found error at v1, got %v", err)
+	}
+	if tags, _ := repo.ListTags(ctx); len(tags) != 1 || tags[0] != "v1" {
+		t.Errorf("ListTags() = %v, want [v1]", tags)
+	}
+}
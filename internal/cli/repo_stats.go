@@ -0,0 +1,431 @@
+package cli
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/index"
+	"github.com/chazuruo/svf/internal/lint"
+	"github.com/chazuruo/svf/internal/placeholders"
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+// repoStatsTop is how many placeholders and tags svf repo stats names as
+// the most used.
+const repoStatsTop = 5
+
+// RepoStatsOptions contains the options for the repo stats command.
+type RepoStatsOptions struct {
+	ConfigPath string
+	Months     int
+	JSON       bool
+}
+
+// NewRepoCommand creates the repo command.
+func NewRepoCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "repo",
+		Short: "Report on the workflow repository",
+	}
+
+	cmd.AddCommand(newRepoStatsCommand())
+
+	return cmd
+}
+
+// newRepoStatsCommand creates the repo stats command.
+func newRepoStatsCommand() *cobra.Command {
+	opts := &RepoStatsOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show statistics on the workflows in the repository",
+		Long: `Show the health of the workflow repository: how many workflows each team
+has, how the count grew month by month, how long workflows are, how they
+use placeholders and tags, and how many lint issues they have.
+
+A workflow's team is the first directory of its identity path (platform
+for platform/chaz); shared workflows count as "shared". Growth comes from
+the git history of the workflow folders, so uncommitted workflows aren't
+in it. Lint issues are the errors and warnings POST /v1/lint of 'svf api'
+reports. Use --json to feed dashboards; 'svf stats' covers how often
+workflows are run.`,
+		Example: `  svf repo stats
+  svf repo stats --months 24
+  svf repo stats --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRepoStats(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ConfigPath, "config", "", "config file path")
+	cmd.Flags().IntVar(&opts.Months, "months", 12, "months of growth to show")
+	cmd.Flags().BoolVar(&opts.JSON, "json", false, "output as JSON")
+
+	return cmd
+}
+
+// repoStatsReport is the repo stats command output.
+type repoStatsReport struct {
+	Workflows    int              `json:"workflows"`
+	Drafts       int              `json:"drafts"`
+	Templates    int              `json:"templates"`
+	Teams        []nameCount      `json:"teams"`
+	Growth       []growthMonth    `json:"growth"`
+	AvgSteps     float64          `json:"avg_steps"`
+	Placeholders placeholderUsage `json:"placeholders"`
+	Tags         tagCoverage      `json:"tags"`
+	Lint         lintCounts       `json:"lint"`
+}
+
+// nameCount is how many workflows a team, placeholder or tag has.
+type nameCount struct {
+	Name      string `json:"name"`
+	Workflows int    `json:"workflows"`
+}
+
+// growthMonth is how the number of workflows changed in a month.
+type growthMonth struct {
+	Month   string `json:"month"` // YYYY-MM
+	Added   int    `json:"added"`
+	Removed int    `json:"removed"`
+	Total   int    `json:"total"` // At the end of the month
+}
+
+// placeholderUsage is how workflows use placeholders.
+type placeholderUsage struct {
+	Workflows  int         `json:"workflows"`  // Workflows with any placeholder
+	Declared   int         `json:"declared"`   // Declarations across workflows
+	Secret     int         `json:"secret"`     // Declarations marked secret
+	Undeclared int         `json:"undeclared"` // Used in steps without a declaration
+	MostUsed   []nameCount `json:"most_used"`
+}
+
+// tagCoverage is how many workflows are tagged.
+type tagCoverage struct {
+	Tagged   int         `json:"tagged"`
+	Untagged int         `json:"untagged"`
+	Coverage float64     `json:"coverage"` // Percentage of workflows tagged
+	MostUsed []nameCount `json:"most_used"`
+}
+
+// lintCounts is how many lint issues workflows have.
+type lintCounts struct {
+	Errors     int `json:"errors"`
+	Warnings   int `json:"warnings"`
+	Workflows  int `json:"workflows"`  // Workflows with any issue
+	Unreadable int `json:"unreadable"` // Workflow files that failed to load
+}
+
+func runRepoStats(opts *RepoStatsOptions) error {
+	ctx := context.Background()
+
+	if opts.Months <= 0 {
+		return fmt.Errorf("--months must be at least 1")
+	}
+
+	// Load config
+	var cfg *config.Config
+	var err error
+	if opts.ConfigPath != "" {
+		cfg, err = config.Load(opts.ConfigPath)
+	} else {
+		cfg, err = config.LoadWithDefaults()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Open repo
+	repo := gitrepo.New(cfg.Repo.Path)
+	if !repo.IsInitialized(ctx) {
+		return fmt.Errorf("repository not initialized. Run 'svf init' first")
+	}
+
+	// Create store
+	str, err := store.New(repo, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+
+	refs, err := str.List(ctx, store.Filter{IncludeDrafts: true})
+	if err != nil {
+		return fmt.Errorf("failed to list workflows: %w", err)
+	}
+
+	// Drafts and templates are only counted; a nil Workflow is one that
+	// failed to load
+	var infos []workflowInfo
+	for _, ref := range refs {
+		info := workflowInfo{Ref: ref}
+		if ref.Kind == "" || ref.Kind == index.KindWorkflow {
+			info.Workflow, _ = str.Load(ctx, ref)
+		}
+		infos = append(infos, info)
+	}
+
+	changes, err := gitrepo.New(cfg.Repo.Path).FileChanges(ctx, workflows.FileNames, cfg.Workflows.Root, cfg.Workflows.SharedRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to read git history: %v\n", err)
+	}
+
+	report := buildRepoStats(cfg, infos, changes, opts.Months, time.Now())
+
+	if opts.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	printRepoStats(report, opts.Months)
+	return nil
+}
+
+// buildRepoStats builds the report from the workflows in the repo and the
+// changes git history made to workflow files, oldest first.
+func buildRepoStats(cfg *config.Config, infos []workflowInfo, changes []gitrepo.FileChange, months int, now time.Time) repoStatsReport {
+	report := repoStatsReport{
+		Teams:  []nameCount{},
+		Growth: []growthMonth{},
+		Placeholders: placeholderUsage{
+			MostUsed: []nameCount{},
+		},
+		Tags: tagCoverage{
+			MostUsed: []nameCount{},
+		},
+	}
+
+	teams := make(map[string]int)
+	placeholderNames := make(map[string]int)
+	tags := make(map[string]int)
+	steps := 0
+	for _, info := range infos {
+		switch info.Ref.Kind {
+		case index.KindDraft:
+			report.Drafts++
+			continue
+		case index.KindTemplate:
+			report.Templates++
+			continue
+		}
+		report.Workflows++
+		teams[workflowTeam(cfg, info.Ref.Path)]++
+
+		wf := info.Workflow
+		if wf == nil {
+			report.Lint.Unreadable++
+			continue
+		}
+		steps += len(wf.Steps)
+		countPlaceholders(&report.Placeholders, placeholderNames, wf)
+
+		if len(wf.Tags) > 0 {
+			report.Tags.Tagged++
+		}
+		for _, tag := range slices.Compact(slices.Sorted(slices.Values(wf.Tags))) {
+			tags[tag]++
+		}
+
+		if problems := lint.Check(wf); len(problems) > 0 {
+			report.Lint.Workflows++
+			for _, p := range problems {
+				if p.Severity == lint.SeverityError {
+					report.Lint.Errors++
+				} else {
+					report.Lint.Warnings++
+				}
+			}
+		}
+	}
+
+	loaded := report.Workflows - report.Lint.Unreadable
+	if loaded > 0 {
+		report.AvgSteps = float64(steps) / float64(loaded)
+		report.Tags.Coverage = float64(report.Tags.Tagged) * 100 / float64(loaded)
+	}
+	report.Tags.Untagged = loaded - report.Tags.Tagged
+
+	report.Teams = mostUsed(teams, 0)
+	report.Placeholders.MostUsed = mostUsed(placeholderNames, repoStatsTop)
+	report.Tags.MostUsed = mostUsed(tags, repoStatsTop)
+	report.Growth = workflowGrowth(changes, months, now)
+	return report
+}
+
+// workflowTeam returns the team of the workflow file at path: "shared"
+// under the shared root, otherwise the first directory under the
+// workflows root.
+func workflowTeam(cfg *config.Config, path string) string {
+	rel, err := filepath.Rel(cfg.Repo.Path, path)
+	if err != nil {
+		return "unknown"
+	}
+	if under(rel, cfg.Workflows.SharedRoot) {
+		return "shared"
+	}
+	inRoot, err := filepath.Rel(cfg.Workflows.Root, rel)
+	if err != nil || strings.HasPrefix(inRoot, "..") {
+		return "unknown"
+	}
+	team, _, _ := strings.Cut(filepath.ToSlash(inRoot), "/")
+	return team
+}
+
+// under reports whether the repo-relative path rel is inside dir.
+func under(rel, dir string) bool {
+	inDir, err := filepath.Rel(dir, rel)
+	return err == nil && !strings.HasPrefix(inDir, "..")
+}
+
+// countPlaceholders adds wf's placeholders to usage, and each placeholder
+// it declares or uses to the workflows using that name.
+func countPlaceholders(usage *placeholderUsage, names map[string]int, wf *workflows.Workflow) {
+	used := make(map[string]bool)
+	for name, p := range wf.Placeholders {
+		used[name] = true
+		usage.Declared++
+		if p.Secret {
+			usage.Secret++
+		}
+	}
+	for _, name := range placeholders.CollectFromSteps(wf.Steps) {
+		if _, ok := wf.Placeholders[name]; !ok && wf.CaptureStep(name) < 0 {
+			usage.Undeclared++
+		}
+		used[name] = true
+	}
+	if len(used) > 0 {
+		usage.Workflows++
+	}
+	for name := range used {
+		names[name]++
+	}
+}
+
+// mostUsed returns the names in counts by count, then name, limited to
+// the first n unless n is 0.
+func mostUsed(counts map[string]int, n int) []nameCount {
+	list := make([]nameCount, 0, len(counts))
+	for name, count := range counts {
+		list = append(list, nameCount{Name: name, Workflows: count})
+	}
+	slices.SortFunc(list, func(a, b nameCount) int {
+		if c := cmp.Compare(b.Workflows, a.Workflows); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+	if n > 0 && len(list) > n {
+		list = list[:n]
+	}
+	return list
+}
+
+// workflowGrowth returns how many workflows were added and removed in
+// each of the last months months up to now, and how many there were at
+// the end of each. A multi-document file counts once per workflow.
+func workflowGrowth(changes []gitrepo.FileChange, months int, now time.Time) []growthMonth {
+	first := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).AddDate(0, -(months - 1), 0)
+	growth := make([]growthMonth, months)
+	for i := range growth {
+		growth[i].Month = first.AddDate(0, i, 0).Format("2006-01")
+	}
+
+	total := 0
+	for _, c := range changes {
+		t := c.Time.In(now.Location())
+		i := (t.Year()-first.Year())*12 + int(t.Month()-first.Month())
+		if i >= months {
+			continue // Committed with a clock ahead of ours
+		}
+		delta := workflowCount(c.Path, c.After) - workflowCount(c.Path, c.Before)
+		total += delta
+		if i < 0 {
+			continue // Only counts toward the totals
+		}
+		if delta > 0 {
+			growth[i].Added += delta
+		} else {
+			growth[i].Removed -= delta
+		}
+	}
+
+	// Work back from the final total so each month ends with its own
+	for i := months - 1; i >= 0; i-- {
+		growth[i].Total = total
+		total -= growth[i].Added - growth[i].Removed
+	}
+	return growth
+}
+
+// workflowCount returns how many workflows a version of the workflow file
+// at path holds: none if it doesn't exist, and one if it can't be parsed.
+func workflowCount(path string, data []byte) int {
+	if data == nil {
+		return 0
+	}
+	if wfs, err := workflows.UnmarshalWorkflows(data, workflows.FormatForPath(path)); err == nil {
+		return len(wfs)
+	}
+	return 1
+}
+
+// printRepoStats prints the report as text.
+func printRepoStats(r repoStatsReport, months int) {
+	fmt.Printf("Workflows: %d (%d draft(s), %d template(s))\n\n", r.Workflows, r.Drafts, r.Templates)
+
+	fmt.Println("By team:")
+	if len(r.Teams) == 0 {
+		fmt.Println("  (none)")
+	}
+	for _, t := range r.Teams {
+		fmt.Printf("  %-30s %4d\n", t.Name, t.Workflows)
+	}
+	fmt.Println()
+
+	fmt.Printf("Growth (last %d months):\n", months)
+	for _, g := range r.Growth {
+		fmt.Printf("  %s  +%-3d -%-3d %4d\n", g.Month, g.Added, g.Removed, g.Total)
+	}
+	fmt.Println()
+
+	fmt.Printf("Steps: %.1f per workflow on average\n", r.AvgSteps)
+
+	p := r.Placeholders
+	fmt.Printf("Placeholders: used by %d workflow(s), %d declared, %d secret, %d undeclared\n",
+		p.Workflows, p.Declared, p.Secret, p.Undeclared)
+	if len(p.MostUsed) > 0 {
+		fmt.Printf("  Most used: %s\n", formatNameCounts(p.MostUsed))
+	}
+
+	fmt.Printf("Tags: %d workflow(s) tagged (%.0f%%), %d untagged\n", r.Tags.Tagged, r.Tags.Coverage, r.Tags.Untagged)
+	if len(r.Tags.MostUsed) > 0 {
+		fmt.Printf("  Most used: %s\n", formatNameCounts(r.Tags.MostUsed))
+	}
+
+	fmt.Printf("Lint: %d error(s), %d warning(s) in %d workflow(s)\n", r.Lint.Errors, r.Lint.Warnings, r.Lint.Workflows)
+	if r.Lint.Unreadable > 0 {
+		fmt.Printf("  %d workflow file(s) failed to load\n", r.Lint.Unreadable)
+	}
+}
+
+// formatNameCounts formats names with their counts, e.g. "deploy (10)".
+func formatNameCounts(counts []nameCount) string {
+	parts := make([]string, len(counts))
+	for i, c := range counts {
+		parts[i] = fmt.Sprintf("%s (%d)", c.Name, c.Workflows)
+	}
+	return strings.Join(parts, ", ")
+}
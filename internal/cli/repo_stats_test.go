@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/index"
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+func TestBuildRepoStats(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Repo.Path = "/repo"
+
+	infos := []workflowInfo{
+		{
+			Ref: store.WorkflowRef{Path: "/repo/workflows/platform/chaz/deploy/workflow.yaml"},
+			Workflow: &workflows.Workflow{
+				Title: "Deploy",
+				Tags:  []string{"deploy", "k8s"},
+				Placeholders: map[string]workflows.Placeholder{
+					"namespace": {Prompt: "Namespace"},
+					"token":     {Prompt: "Token", Secret: true},
+				},
+				Steps: []workflows.Step{
+					{Name: "Apply", Command: "kubectl -n <namespace> apply -f app.yaml --token <token>"},
+					{Name: "Check", Command: "kubectl -n <namespace> get pods"},
+				},
+			},
+		},
+		{
+			Ref: store.WorkflowRef{Path: "/repo/workflows/platform/ana/restart/workflow.yaml"},
+			Workflow: &workflows.Workflow{
+				Title: "Restart",
+				Tags:  []string{"k8s"},
+				Steps: []workflows.Step{
+					{Name: "Restart", Command: "kubectl -n <namespace> rollout restart deploy/app"},
+				},
+			},
+		},
+		{
+			Ref: store.WorkflowRef{Path: "/repo/shared/backup/workflow.yaml"},
+			Workflow: &workflows.Workflow{
+				Title: "Backup",
+				Steps: []workflows.Step{
+					{Name: "Dump", Command: "pg_dump app > app.sql"},
+					{Name: "Upload", Command: "aws s3 cp app.sql s3://backups/"},
+					{Name: "Clean", Command: "rm app.sql"},
+				},
+			},
+		},
+		{Ref: store.WorkflowRef{Path: "/repo/workflows/data/broken/workflow.yaml"}},
+		{Ref: store.WorkflowRef{Path: "/repo/drafts/chaz/whoops/workflow.yaml", Kind: index.KindDraft}},
+	}
+
+	report := buildRepoStats(cfg, infos, nil, 1, time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC))
+
+	if report.Workflows != 4 || report.Drafts != 1 || report.Templates != 0 {
+		t.Errorf("counts = %d workflows, %d drafts, %d templates; want 4, 1, 0", report.Workflows, report.Drafts, report.Templates)
+	}
+	wantTeams := []nameCount{{"platform", 2}, {"data", 1}, {"shared", 1}}
+	if !reflect.DeepEqual(report.Teams, wantTeams) {
+		t.Errorf("Teams = %v, want %v", report.Teams, wantTeams)
+	}
+	if report.AvgSteps != 2 {
+		t.Errorf("AvgSteps = %v, want 2", report.AvgSteps)
+	}
+
+	p := report.Placeholders
+	if p.Workflows != 2 || p.Declared != 2 || p.Secret != 1 || p.Undeclared != 1 {
+		t.Errorf("Placeholders = %+v, want 2 workflows, 2 declared, 1 secret, 1 undeclared", p)
+	}
+	if len(p.MostUsed) == 0 || p.MostUsed[0] != (nameCount{"namespace", 2}) {
+		t.Errorf("Placeholders.MostUsed = %v, want namespace (2) first", p.MostUsed)
+	}
+
+	if report.Tags.Tagged != 2 || report.Tags.Untagged != 1 {
+		t.Errorf("Tags = %+v, want 2 tagged, 1 untagged", report.Tags)
+	}
+	wantTags := []nameCount{{"k8s", 2}, {"deploy", 1}}
+	if !reflect.DeepEqual(report.Tags.MostUsed, wantTags) {
+		t.Errorf("Tags.MostUsed = %v, want %v", report.Tags.MostUsed, wantTags)
+	}
+
+	if report.Lint.Unreadable != 1 || report.Lint.Warnings == 0 {
+		t.Errorf("Lint = %+v, want 1 unreadable and warnings", report.Lint)
+	}
+}
+
+func TestWorkflowGrowth(t *testing.T) {
+	at := func(date string) time.Time {
+		t, _ := time.Parse("2006-01-02", date)
+		return t
+	}
+	one := []byte("title: A\nsteps:\n  - command: \"true\"\n")
+	two := []byte(string(one) + "---\ntitle: B\nsteps:\n  - command: \"true\"\n")
+	changes := []gitrepo.FileChange{
+		{Path: "workflows/chaz/a/workflow.yaml", After: one, Time: at("2025-06-01")},
+		{Path: "workflows/chaz/b/workflow.yaml", After: one, Time: at("2026-01-15")},
+		{Path: "shared/c/workflow.yaml", After: one, Time: at("2026-03-02")},
+		{Path: "workflows/chaz/a/workflow.yaml", Before: one, Time: at("2026-03-05")},
+		// A second workflow in an existing file
+		{Path: "workflows/chaz/b/workflow.yaml", Before: one, After: two, Time: at("2026-03-06")},
+	}
+
+	got := workflowGrowth(changes, 3, at("2026-03-10"))
+
+	want := []growthMonth{
+		{Month: "2026-01", Added: 1, Total: 2},
+		{Month: "2026-02", Total: 2},
+		{Month: "2026-03", Added: 2, Removed: 1, Total: 3},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("workflowGrowth() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,102 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/chazuruo/svf/internal/redact"
+	"github.com/chazuruo/svf/internal/report"
+	"github.com/chazuruo/svf/internal/runlog"
+)
+
+// ReportOptions contains the options for the report command.
+type ReportOptions struct {
+	Format   string
+	Out      string
+	Sections string
+	Redact   string
+}
+
+// NewReportCommand creates the report command.
+func NewReportCommand() *cobra.Command {
+	opts := &ReportOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "report <run-id>",
+		Short: "Export a run as an incident timeline for a postmortem",
+		Long: `Compile a recorded run into an incident timeline to attach to a
+postmortem: when each step started and how long it took, its command and
+result, which dangerous commands the operator confirmed, the step output
+and any findings attached with 'svf explain --run'.
+
+The run ID can be a full ID, a unique prefix, or "last" for the most
+recent run. Commands, output and findings are redacted (strict by
+default) on top of the redaction applied when the run was recorded.
+
+Sections: summary, timeline, outputs, findings (default: all).`,
+		Example: `  svf report last                            # Markdown to stdout
+  svf report run_20260314 --out incident.html  # HTML, by extension
+  svf report last --sections summary,timeline`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runReport(opts, args[0])
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Format, "format", "", "output format: md or html (default: from --out, else md)")
+	cmd.Flags().StringVar(&opts.Out, "out", "", "output file (default: stdout)")
+	cmd.Flags().StringVar(&opts.Sections, "sections", "", "comma-separated sections to include (default: all)")
+	cmd.Flags().StringVar(&opts.Redact, "redact", redact.Strict, "redaction level: none, basic or strict")
+
+	return cmd
+}
+
+func runReport(opts *ReportOptions, runID string) error {
+	format := report.Format(opts.Format)
+	if format == "" {
+		format = report.FormatMarkdown
+		if ext := strings.ToLower(filepath.Ext(opts.Out)); ext == ".html" || ext == ".htm" {
+			format = report.FormatHTML
+		}
+	}
+	if format != report.FormatMarkdown && format != report.FormatHTML {
+		return fmt.Errorf("invalid --format %q: use md or html", opts.Format)
+	}
+	switch opts.Redact {
+	case redact.None, redact.Basic, redact.Strict:
+	default:
+		return fmt.Errorf("invalid --redact %q: use none, basic or strict", opts.Redact)
+	}
+	sections, err := report.ParseSections(opts.Sections)
+	if err != nil {
+		return err
+	}
+
+	runs, err := runlog.NewDefaultStore()
+	if err != nil {
+		return err
+	}
+	rec, err := runs.Load(runID)
+	if err != nil {
+		return fmt.Errorf("failed to load run: %w", err)
+	}
+
+	out, err := report.Render(rec, report.Options{Format: format, Sections: sections, Redact: opts.Redact})
+	if err != nil {
+		return fmt.Errorf("failed to render report: %w", err)
+	}
+
+	if opts.Out == "" {
+		fmt.Print(out)
+		return nil
+	}
+	if err := os.WriteFile(opts.Out, []byte(out), 0644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	fmt.Printf("✓ Report for %s written to %s\n", rec.ID, opts.Out)
+	return nil
+}
@@ -0,0 +1,142 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+// ReviewApproveOptions contains the options for the review approve command.
+type ReviewApproveOptions struct {
+	ConfigPath string
+	NoCommit   bool
+}
+
+// NewReviewCommand creates the review command.
+func NewReviewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "review",
+		Short: "Manage workflow reviews",
+		Long: `Manage review approvals of workflows.
+
+An approval records reviewed_by, reviewed_at and a hash of the workflow's
+content. When the workflow changes afterwards, 'svf view' and 'svf run'
+warn that the review is stale. With runner.require_review set, 'svf run'
+refuses to run shared workflows that are unreviewed or stale.
+
+Only owners can approve: the identities listed in the workflow's owners
+field or, when it has none, the identity whose directory it lives in.`,
+	}
+
+	cmd.AddCommand(newReviewApproveCommand())
+
+	return cmd
+}
+
+// newReviewApproveCommand creates the review approve command.
+func newReviewApproveCommand() *cobra.Command {
+	opts := &ReviewApproveOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "approve <workflow-ref>",
+		Short: "Approve the current version of a workflow",
+		Example: `  svf review approve shared/rotate-certs
+  svf review approve deploy-api --no-commit`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runReviewApprove(opts, args[0])
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ConfigPath, "config", "", "config file path")
+	cmd.Flags().BoolVar(&opts.NoCommit, "no-commit", false, "do not commit the approval")
+
+	return cmd
+}
+
+func runReviewApprove(opts *ReviewApproveOptions, refStr string) error {
+	ctx := context.Background()
+
+	// Load config
+	cfg, err := loadConfig(opts.ConfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Open repo
+	repo := gitrepo.New(cfg.Repo.Path)
+	if !repo.IsInitialized(ctx) {
+		return fmt.Errorf("repository not initialized. Run 'svf init' first")
+	}
+
+	// Create store
+	str, err := store.New(repo, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+
+	ref, err := resolveWorkflowRef(ctx, str, cfg, refStr)
+	if err != nil {
+		return err
+	}
+	wf, err := str.Load(ctx, ref)
+	if err != nil {
+		return fmt.Errorf("failed to load workflow: %w", err)
+	}
+
+	if !canApprove(cfg, wf, ref.Path) {
+		if len(wf.Owners) == 0 {
+			return fmt.Errorf("only owners can approve %q; it has no owners field, so add one listing who may approve it", wf.Title)
+		}
+		return fmt.Errorf("only owners can approve %q (owners: %s; you are %s)", wf.Title, strings.Join(wf.Owners, ", "), cfg.Identity.Path)
+	}
+
+	if wf.ReviewStatus() == workflows.Reviewed {
+		fmt.Printf("%s is already approved (by %s on %s).\n", wf.Title, wf.ReviewedBy, wf.ReviewedAt.Format("2006-01-02"))
+		return nil
+	}
+
+	if err := wf.Approve(cfg.Identity.Path, time.Now()); err != nil {
+		return err
+	}
+
+	saveOpts := store.SaveOptions{
+		Commit:  !opts.NoCommit,
+		Message: fmt.Sprintf("Approve workflow: %s", wf.Title),
+		Path:    ref.Path,
+		Doc:     ref.Doc,
+	}
+	if _, err := saveAndPublish(ctx, repo, str, cfg, wf, saveOpts); err != nil {
+		return fmt.Errorf("failed to save approval: %w", err)
+	}
+
+	fmt.Printf("✓ Approved %s as %s\n", wf.Title, cfg.Identity.Path)
+	return nil
+}
+
+// canApprove reports whether the configured identity owns a workflow:
+// it is listed in owners or, without owners, the workflow lives under the
+// identity's directory.
+func canApprove(cfg *config.Config, wf *workflows.Workflow, path string) bool {
+	if len(wf.Owners) > 0 {
+		return wf.IsOwner(cfg.Identity.Path)
+	}
+	if cfg.Identity.Path == "" {
+		return false
+	}
+	rel, err := filepath.Rel(cfg.Repo.Path, path)
+	if err != nil {
+		return false
+	}
+	own := filepath.Join(cfg.Workflows.Root, cfg.Identity.Path) + string(filepath.Separator)
+	return strings.HasPrefix(rel, own)
+}
@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+func TestCanApprove(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Repo.Path = "/repo"
+	cfg.Identity.Path = "platform/alice"
+
+	own := filepath.Join(cfg.Repo.Path, "workflows", "platform", "alice", "deploy", "workflow.yaml")
+	other := filepath.Join(cfg.Repo.Path, "workflows", "platform", "alicia", "deploy", "workflow.yaml")
+	shared := filepath.Join(cfg.Repo.Path, "shared", "certs", "workflow.yaml")
+
+	tests := []struct {
+		name   string
+		owners []string
+		path   string
+		want   bool
+	}{
+		{name: "own directory", path: own, want: true},
+		{name: "similar identity", path: other, want: false},
+		{name: "shared without owners", path: shared, want: false},
+		{name: "listed owner", owners: []string{"platform/alice"}, path: shared, want: true},
+		{name: "owners override directory", owners: []string{"platform/bob"}, path: own, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf := &workflows.Workflow{Owners: tt.owners}
+			if got := canApprove(cfg, wf, tt.path); got != tt.want {
+				t.Errorf("canApprove() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
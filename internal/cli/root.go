@@ -0,0 +1,122 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"fmt"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// BuildInfo is the version metadata a binary is built with, set through
+// ldflags in its main package.
+type BuildInfo struct {
+	Version string
+	Commit  string
+	Date    string
+}
+
+// NewRootCommand creates the root command of a binary named name, with
+// every subcommand registered. All binaries (svf, gitsavvy) are built from
+// it, so they expose the same commands and flags; only the name in usage
+// and version output differs.
+func NewRootCommand(name string, build BuildInfo) *cobra.Command {
+	if build.Version != "" {
+		Version, Commit, BuildDate = build.Version, build.Commit, build.Date
+	}
+
+	root := &cobra.Command{
+		Use:   name,
+		Short: "Git-backed workflow automation tool",
+		Long: name + ` is a terminal-first workflow/runbook tool compatible with Savvy CLI,
+but stores all workflows and metadata in a Git repository instead of a hosted backend.`,
+		Version: fmt.Sprintf("%s (commit: %s, built: %s)", Version, Commit, BuildDate),
+		Run: func(cmd *cobra.Command, args []string) {
+			_ = cmd.Help()
+		},
+	}
+
+	// Read-only mode is decided before parsing, so the commands it hides
+	// are left out of help and completion too
+	readOnly := config.ReadOnlyMode()
+
+	// The config the running command starts with, for telemetry
+	var cfg *config.Config
+
+	// Add global flags
+	AddGlobalFlags(root)
+	root.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		cfg = loadUIConfig(cmd)
+		configureUI(cfg)
+		if readOnly {
+			return refuseRepoWriter(cmd)
+		}
+		return nil
+	}
+
+	root.CompletionOptions.DisableDefaultCmd = true
+
+	Register(root)
+	markRepoWriters(root, readOnly)
+	markUsageErrors(root)
+	recordTelemetry(root, func() *config.TelemetryConfig {
+		if cfg == nil {
+			return nil
+		}
+		return &cfg.Telemetry
+	})
+	return root
+}
+
+// Register adds all svf subcommands to root.
+func Register(root *cobra.Command) {
+	root.AddCommand(
+		NewWhoamiCommand(),
+		NewEditCommand(),
+		NewDeleteCommand(),
+		NewCopyCommand(),
+		NewInitCommand(),
+		NewRecordCommand(),
+		NewRecordHistoryCommand(),
+		NewWhoopsCommand(),
+		NewSyncCommand(),
+		NewMergeIndexCommand(),
+		NewStatusCommand(),
+		NewDoctorCommand(),
+		NewConfigCommand(),
+		NewIndexCommand(),
+		NewIDsCommand(),
+		NewAliasCommand(),
+		NewReadmeCommand(),
+		NewGCCommand(),
+		NewListCommand(),
+		NewPinCommand(),
+		NewUnpinCommand(),
+		NewPinsCommand(),
+		NewViewCommand(),
+		NewOpenCommand(),
+		NewReleaseCommand(),
+		NewPlaceholdersCommand(),
+		NewDiffCommand(),
+		NewReviewCommand(),
+		NewRunCommand(),
+		NewTestCommand(),
+		NewSearchCommand(),
+		NewGrepCommand(),
+		NewStatsCommand(),
+		NewRepoCommand(),
+		NewAuditCommand(),
+		NewServeCommand(),
+		NewAPICommand(),
+		NewShellInitCommand(),
+		NewShellNextCommand(),
+		NewAskCommand(),
+		NewExplainCommand(),
+		NewReportCommand(),
+		NewExportCommand(),
+		NewImportCommand(),
+		NewUpgradeCommand(),
+		NewTelemetryCommand(),
+		NewVersionCommand(),
+	)
+}
@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// commandTree lists every command path below root with its flags, with the
+// root's name left out.
+func commandTree(root *cobra.Command) []string {
+	var tree []string
+	var walk func(cmd *cobra.Command, path string)
+	walk = func(cmd *cobra.Command, path string) {
+		tree = append(tree, path+"\n"+cmd.Flags().FlagUsages())
+		for _, sub := range cmd.Commands() {
+			walk(sub, path+" "+sub.Name())
+		}
+	}
+	walk(root, "")
+	sort.Strings(tree)
+	return tree
+}
+
+func TestNewRootCommand_Parity(t *testing.T) {
+	build := BuildInfo{Version: "dev", Commit: "unknown", Date: "unknown"}
+	svf := NewRootCommand("svf", build)
+	gitsavvy := NewRootCommand("gitsavvy", build)
+
+	if gitsavvy.Name() != "gitsavvy" {
+		t.Errorf("root name = %q, want gitsavvy", gitsavvy.Name())
+	}
+	if got, want := commandTree(gitsavvy), commandTree(svf); !reflect.DeepEqual(got, want) {
+		t.Errorf("gitsavvy commands differ from svf:\n got %v\nwant %v", got, want)
+	}
+	for _, name := range []string{"run", "search", "version", "upgrade"} {
+		if cmd, _, err := gitsavvy.Find([]string{name}); err != nil || cmd.Name() != name {
+			t.Errorf("gitsavvy %s not registered", name)
+		}
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	content := "[repo]\npath = \"/test/repo\"\n\n[identity]\npath = \"testuser\"\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := loadConfig(path)
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+	if cfg.Repo.Path != "/test/repo" {
+		t.Errorf("Repo.Path = %q, want /test/repo", cfg.Repo.Path)
+	}
+	if _, err := loadConfig(filepath.Join(t.TempDir(), "missing.toml")); err == nil {
+		t.Error("loadConfig() of a missing file succeeded")
+	}
+}
@@ -3,15 +3,26 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 	"github.com/chazuruo/svf/internal/config"
+	svferrors "github.com/chazuruo/svf/internal/errors"
 	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/i18n"
 	"github.com/chazuruo/svf/internal/placeholders"
+	"github.com/chazuruo/svf/internal/redact"
+	"github.com/chazuruo/svf/internal/runlog"
+	"github.com/chazuruo/svf/internal/runpath"
+	"github.com/chazuruo/svf/internal/stats"
 	//nolint:staticcheck // SA1019 - Using runner for Exec, DangerChecker, Plan types (deprecated but needed)
 	runnerpkg "github.com/chazuruo/svf/internal/runner"
 	"github.com/chazuruo/svf/internal/tui"
@@ -33,8 +44,28 @@ type RunOptions struct {
 	DryRun     bool
 	LogPath    string
 	SaveParams bool
+	SendTo     string
+	IgnoreKube bool
+	IgnoreCloud bool
+	InstallMissing bool
+	Matrix     []string
+	StdinPlaceholder string
+	Preset     string
+	ContinueOnError bool
+	Batch      bool
+	LogFormat  string
+	SummaryFile string
+	BreakLock  bool
+
+	// pipeline places the run in a pipeline; zero when running alone
+	pipeline pipelinePosition
+	// values receives the placeholder values the run ended with, when set
+	values map[string]string
 }
 
+// errRunCanceled is returned when the user cancels a run.
+var errRunCanceled = fmt.Errorf("workflow %w", svferrors.ErrCanceled)
+
 // NewRunCommand creates the run command.
 func NewRunCommand() *cobra.Command {
 	opts := &RunOptions{
@@ -43,13 +74,31 @@ func NewRunCommand() *cobra.Command {
 	}
 
 	cmd := &cobra.Command{
-		Use:   "run [workflow-ref]",
+		Use:   "run [workflow-ref[,workflow-ref...]]",
 		Short: "Run a workflow interactively or non-interactively",
 		Long: `Execute a workflow step-by-step.
 
+Choosing a workflow:
+- No argument opens the interactive picker
+- An ID, slug, or path runs that workflow
+- Anything else is fuzzy-matched against the search index; a single match
+  runs directly, several matches open the picker to choose from
+- <workflow-ref>@<rev> runs the workflow as it was at a release tagged
+  with 'svf release create', a branch or a commit. The workflow and its
+  companion files are read from git, so local edits are ignored and the
+  working copy is untouched; its next chain isn't followed
+
+Pipelines (a,b,c or next):
+- A comma-separated list runs the workflows one after another
+- A single workflow with next: <workflow-ref> is followed by that
+  workflow, and so on along the chain
+- Placeholder values, entered or captured, carry over to later workflows
+- A failed workflow stops the pipeline unless --continue-on-error is set
+
 Interactive mode (default):
 - Shows step list with status icons
-- Prompts for placeholders once per unique value
+- Prompts for placeholders once per unique value, offering the
+  workflow's presets first
 - Press Enter to execute each step
 - Supports: s (skip), r (rerun), q (quit), e (edit step)
 
@@ -58,12 +107,62 @@ Non-interactive mode (--yes):
 - Requires placeholders via --param or config
 - Exit codes: 0 (success), 20 (step failed), 21 (missing param), 13 (canceled)
 
+Batch mode (--batch):
+- Runs without the TUI, like --no-tui; dangerous commands still ask
+  unless --yes is given
+- Prints a line as each step starts and finishes, with its duration and
+  status, or timestamped log lines when stdout is not a terminal
+- --log-format json prints one JSON object per event instead (run_started,
+  step_started, step_finished, run_finished), with step output included
+  in step_finished; it implies --batch
+
+Run summary (--summary-file <path>):
+- Writes a JSON summary when the run ends, in any mode: the status and
+  duration of each step, the placeholder values with secrets masked, and
+  the run record's path
+- For wrapper scripts and chat bots that report on runs
+
+Presets (--preset <name>):
+- Fills placeholders from a named preset in the workflow's presets
+- --param values take precedence over the preset
+
 Offline mode (--local):
 - Skip git fetch, use current checkout
 
 Dry run mode (--dry-run):
 - Show commands after placeholder substitution
-- Don't execute anything`,
+- Don't execute anything
+
+Send mode (--send-to tmux:<pane> or screen:<session>[/<window>]):
+- Types each command into an existing pane instead of running it, so it
+  runs in that shell's session and environment
+- Asks before sending each step (send, skip, quit) unless --yes is given
+
+Matrix mode (--matrix key=v1,v2 or a workflow matrix):
+- Runs the workflow once per combination of values, unattended
+- Shows a dashboard of each combination's status, then a summary
+- --param pins a matrix key to one value
+- Exit codes: 0 (all succeeded), 20 (any failed), 13 (canceled)
+- --stdin-placeholder NAME runs once per line of stdin with <NAME> set to
+  the line, e.g. cat hosts.txt | svf run drain-node --stdin-placeholder host
+
+Kubernetes guardrails:
+- Workflows with kube_context or kube_namespace are checked against the
+  active kubectl context before running
+- A mismatch asks for confirmation, or refuses to run with --yes or when
+  runner.kube_guard is "block"; --ignore-kube-context skips the check
+
+Cloud account guardrails:
+- Workflows with aws_profile, aws_account or gcp_project are checked
+  against AWS_PROFILE, 'aws sts get-caller-identity' and the gcloud project
+- A mismatch refuses to run; --ignore-cloud-account skips the check
+
+Exclusive workflows (exclusive: true):
+- Only one run at a time: svf takes a lock before running and refuses to
+  run, showing who holds it, while another run does
+- The lock is local to the repo's checkout, or with runner.run_lock set
+  to "remote", pushed to the remote for operators on other hosts
+- --break-lock takes over a lock left behind by a run that is over`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// If workflow ref is provided, use it
 			if len(args) > 0 {
@@ -75,15 +174,27 @@ Dry run mode (--dry-run):
 
 	cmd.Flags().StringVar(&opts.ConfigPath, "config", "", "config file path")
 	cmd.Flags().StringToStringVar(&opts.Params, "param", nil, "placeholder values (repeatable, e.g., --param key=value)")
+	cmd.Flags().StringVar(&opts.Preset, "preset", "", "fill placeholders from a named preset in the workflow")
 	cmd.Flags().BoolVar(&opts.Local, "local", false, "use local checkout only (no fetch)")
 	cmd.Flags().BoolVar(&opts.Yes, "yes", false, "non-interactive mode (auto-confirm all steps)")
+	cmd.Flags().BoolVar(&opts.Batch, "batch", false, "run without the TUI, printing a progress line per step")
+	cmd.Flags().StringVar(&opts.LogFormat, "log-format", logFormatText, "batch progress format: text or json (implies --batch)")
 	cmd.Flags().StringVar(&opts.CWD, "cwd", "", "working directory override")
 	cmd.Flags().StringVar(&opts.Until, "until", "", "stop before this step name")
 	cmd.Flags().StringVar(&opts.From, "from", "", "start from this step name")
 	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "show commands without executing")
 	cmd.Flags().StringVar(&opts.LogPath, "log", "", "write run log to file")
+	cmd.Flags().StringVar(&opts.SummaryFile, "summary-file", "", "write a JSON summary of the run to this file when it ends")
 	cmd.Flags().BoolVar(&opts.SaveParams, "save-params", false, "save provided parameters to workflow")
 	cmd.Flags().StringToStringVar(&opts.Env, "env", nil, "environment variables (repeatable, e.g., --env key=value)")
+	cmd.Flags().BoolVar(&opts.IgnoreKube, "ignore-kube-context", false, "run even if the kubectl context doesn't match the workflow's kube_context/kube_namespace")
+	cmd.Flags().BoolVar(&opts.IgnoreCloud, "ignore-cloud-account", false, "run even if the AWS profile/account or gcloud project doesn't match the workflow")
+	cmd.Flags().BoolVar(&opts.InstallMissing, "install-missing", false, "install missing prerequisites from the workflow's install hints before running")
+	cmd.Flags().StringArrayVar(&opts.Matrix, "matrix", nil, "run once per value (repeatable, e.g., --matrix region=us-east-1,eu-west-1)")
+	cmd.Flags().StringVar(&opts.StdinPlaceholder, "stdin-placeholder", "", "run once per line of stdin, setting this placeholder")
+	cmd.Flags().BoolVar(&opts.ContinueOnError, "continue-on-error", false, "in a pipeline, run the remaining workflows after one fails")
+	cmd.Flags().BoolVar(&opts.BreakLock, "break-lock", false, "take over the run lock of an exclusive workflow from another run")
+	cmd.Flags().StringVar(&opts.SendTo, "send-to", "", "send commands to a pane instead of running them (tmux:<pane> or screen:<session>[/<window>])")
 
 	return cmd
 }
@@ -91,6 +202,14 @@ Dry run mode (--dry-run):
 func runRun(opts *RunOptions) error {
 	ctx := context.Background()
 
+	switch opts.LogFormat {
+	case "", logFormatText:
+	case logFormatJSON:
+		opts.Batch = true
+	default:
+		return fmt.Errorf("invalid --log-format %q (must be text or json)", opts.LogFormat)
+	}
+
 	// Load config
 	cfg, err := config.LoadWithDefaults()
 	if err != nil {
@@ -109,31 +228,130 @@ func runRun(opts *RunOptions) error {
 		return fmt.Errorf("failed to create store: %w", err)
 	}
 
-	// Resolve workflow
-	if opts.WorkflowRef == "" {
-		return fmt.Errorf("workflow reference required\nUsage: svf run <workflow-ref>\nOr use --no-tui with --query to search")
+	// Resolve workflows, picking interactively when missing or inexact
+	var items []pipelineItem
+	if refStr, rev, ok := splitSnapshotRef(opts.WorkflowRef); ok && !strings.Contains(refStr, ",") {
+		var item pipelineItem
+		if item, err = loadSnapshot(ctx, repo, cfg, refStr, rev); err == nil {
+			items = []pipelineItem{item}
+			if item.CompanionDir != "" {
+				defer func() { _ = os.RemoveAll(item.CompanionDir) }()
+			}
+			fmt.Fprintf(os.Stderr, "Running %s as of %s; the working copy is not used\n", item.Workflow.Title, rev)
+		}
+	} else {
+		items, err = resolvePipeline(ctx, str, cfg, opts.WorkflowRef)
 	}
-
-	ref, err := resolveWorkflowRef(ctx, str, opts.WorkflowRef)
 	if err != nil {
+		if errors.Is(err, errPickCanceled) {
+			fmt.Println("Canceled.")
+			return nil
+		}
 		return err
 	}
 
-	// Load workflow
-	wf, err := str.Load(ctx, ref)
+	// Check every workflow before the first one starts
+	for _, item := range items {
+		if err := prepareWorkflow(ctx, cfg, item, opts); err != nil {
+			return err
+		}
+	}
+
+	// Dry runs execute nothing, so they don't take run locks
+	if !opts.DryRun {
+		unlock, err := lockExclusiveRuns(ctx, repo, cfg, items, opts.BreakLock)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+	if len(items) > 1 {
+		if opts.SummaryFile != "" {
+			return fmt.Errorf("--summary-file applies to a single workflow, not a pipeline")
+		}
+		return runPipeline(ctx, items, opts, cfg)
+	}
+	wf := items[0].Workflow
+
+	matrix, err := resolveMatrix(wf, opts, os.Stdin)
 	if err != nil {
-		return fmt.Errorf("failed to load workflow: %w", err)
+		return err
+	}
+	if err := applyPreset(wf, opts, matrix); err != nil {
+		return err
+	}
+	if opts.LogFormat == logFormatJSON && (len(matrix) > 0 || opts.SendTo != "") {
+		return fmt.Errorf("--log-format json can't be used with matrix or --send-to runs")
+	}
+	if opts.SummaryFile != "" && (len(matrix) > 0 || opts.SendTo != "" || opts.DryRun) {
+		return fmt.Errorf("--summary-file can't be used with matrix, --send-to or --dry-run runs")
+	}
+	if len(matrix) > 0 {
+		return runMatrixWorkflow(ctx, wf, matrix, opts, cfg)
 	}
 
-	// Check for --yes flag or global --no-tui
-	if opts.Yes || IsNoTUI() {
-		return runNonInteractive(ctx, wf, opts, cfg)
+	if opts.SendTo != "" {
+		return runSendTo(ctx, wf, opts, cfg)
 	}
 
-	// Interactive mode
+	return runWorkflow(ctx, wf, opts, cfg)
+}
+
+// prepareWorkflow readies a loaded workflow to run: it points steps at
+// companion files and takes it through the checks of the shared run path.
+func prepareWorkflow(ctx context.Context, cfg *config.Config, item pipelineItem, opts *RunOptions) error {
+	wf := item.Workflow
+	companionDir := item.CompanionDir
+	if companionDir == "" {
+		companionDir = filepath.Dir(item.Ref.Path)
+	}
+	wf.ResolveCompanions(companionDir)
+
+	prepare := runpath.Options{
+		DryRun:         opts.DryRun,
+		IgnoreKube:     opts.IgnoreKube,
+		IgnoreCloud:    opts.IgnoreCloud,
+		InstallMissing: opts.InstallMissing,
+		Yes:            opts.Yes,
+	}
+	if canPrompt(opts, cfg) {
+		prepare.Prompter = tui.NewStdioLinePrompter()
+	}
+	return runpath.Prepare(ctx, cfg, wf, item.Ref.Path, prepare)
+}
+
+// runWorkflow runs a workflow interactively, or unattended with --yes or
+// the global --no-tui.
+func runWorkflow(ctx context.Context, wf *workflows.Workflow, opts *RunOptions, cfg *config.Config) error {
+	if opts.Yes || opts.Batch || IsNoTUI() {
+		return runNonInteractive(ctx, wf, opts, cfg)
+	}
 	return runInteractive(ctx, wf, opts, cfg)
 }
 
+// canPrompt reports whether a run may ask the user questions: not with
+// --yes, without a terminal UI, or when stdin carries input values.
+func canPrompt(opts *RunOptions, cfg *config.Config) bool {
+	return !opts.Yes && opts.StdinPlaceholder == "" && GetInteractionMode(cfg) != ModeNone
+}
+
+// applyPreset adds the values of the --preset preset to opts.Params.
+// --param values and matrix keys take precedence over the preset.
+func applyPreset(wf *workflows.Workflow, opts *RunOptions, matrix map[string][]string) error {
+	if opts.Preset == "" {
+		return nil
+	}
+	params, err := wf.ApplyPreset(opts.Preset, opts.Params)
+	if err != nil {
+		return err
+	}
+	for key := range matrix {
+		delete(params, key)
+	}
+	opts.Params = params
+	return nil
+}
+
 // runNonInteractive executes a workflow without TUI.
 func runNonInteractive(ctx context.Context, wf *workflows.Workflow, opts *RunOptions, cfg *config.Config) error {
 	// Apply workflow defaults
@@ -141,6 +359,14 @@ func runNonInteractive(ctx context.Context, wf *workflows.Workflow, opts *RunOpt
 		wf.ApplyDefaults(&wf.Steps[i])
 	}
 
+	// With --log-format json, stdout carries only progress events
+	progress := newProgress(os.Stdout, opts.LogFormat, tui.IsTerminal(os.Stdout))
+	say := func(a ...any) {
+		if !progress.JSON() {
+			fmt.Println(a...)
+		}
+	}
+
 	// Check for --local flag - skip git fetch if set
 	if !opts.Local {
 		// TODO: Implement git fetch
@@ -148,10 +374,10 @@ func runNonInteractive(ctx context.Context, wf *workflows.Workflow, opts *RunOpt
 		if IsNoTUI() {
 			// LLM mode, don't show message
 		} else {
-			fmt.Println("Syncing with remote...")
+			say("Syncing with remote...")
 		}
 	} else {
-		fmt.Println("Using local checkout (--local mode)")
+		say("Using local checkout (--local mode)")
 	}
 
 	// Extract placeholders from workflow using placeholders package
@@ -177,19 +403,45 @@ func runNonInteractive(ctx context.Context, wf *workflows.Workflow, opts *RunOpt
 			}
 		}
 		if len(missing) > 0 {
-			return fmt.Errorf("missing placeholder values (use --param to provide): %s\nExample: --param %s=value",
-				fmt.Sprintf("<%s>", strings.Join(missing, ", <")), missing[0])
+			err := fmt.Errorf("missing placeholder values: %s", fmt.Sprintf("<%s>", strings.Join(missing, ">, <")))
+			hint := fmt.Sprintf("Provide them with --param, e.g. --param %s=value", missing[0])
+			return svferrors.WithExitCode(svferrors.WithHint(err, hint), svferrors.ExitMissingParam)
+		}
+	}
+
+	// A dry run can't capture step output, so captured placeholders are
+	// shown as-is
+	if opts.DryRun {
+		for _, step := range wf.Steps {
+			for name := range step.Capture {
+				if _, ok := allParams[name]; !ok {
+					allParams[name] = "<" + name + ">"
+				}
+			}
 		}
 	}
 
 	// Create runner with dangerous command checking
 	dangerChecker := runnerpkg.NewDangerChecker(cfg.Runner.DangerousCommandWarnings)
+	if err := dangerChecker.LoadRules(cfg.Repo.Path); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
 
 	// Execute each step
 	success := true
 	var failedStep int
+	started := time.Now()
+	results := make([]runnerpkg.StepResult, len(wf.Steps))
+	progress.RunStarted(wf.Title, wf.ID, len(wf.Steps))
 
 	for i, step := range wf.Steps {
+		// Steps limited to other platforms are skipped, not failed
+		if !step.RunsOn(runtime.GOOS, runtime.GOARCH) {
+			results[i] = runnerpkg.PlatformSkip(i)
+			progress.StepFinished(i, step.Name, results[i], "")
+			continue
+		}
+
 		// Substitute placeholders using placeholders package
 		cmd, err := placeholders.Substitute(step.Command, allParams)
 		if err != nil {
@@ -205,26 +457,24 @@ func runNonInteractive(ctx context.Context, wf *workflows.Workflow, opts *RunOpt
 		}
 
 		// Resolve working directory
-		cwd := step.CWD
-		if cwd == "" && wf.Defaults.CWD != "" {
-			cwd = wf.Defaults.CWD
-		}
-		if !filepath.IsAbs(cwd) && cfg.Repo.Path != "" {
-			cwd = filepath.Join(cfg.Repo.Path, cwd)
+		cwd := runnerpkg.ResolveCWD(step.CWD, wf.Defaults.CWD, cfg.Repo.Path)
+		if !opts.DryRun {
+			if err := runnerpkg.ValidateCWD(cwd); err != nil {
+				return fmt.Errorf("step %d (%s): %w", i+1, step.Name, err)
+			}
 		}
 
 		// Show command
-		fmt.Printf("Step %d/%d: %s\n", i+1, len(wf.Steps), step.Name)
 		if opts.DryRun {
-			fmt.Printf("  Would execute: %s\n", cmd)
-			if cwd != "" {
-				fmt.Printf("  Working directory: %s\n", cwd)
-			}
-			if step.Shell != "" {
-				fmt.Printf("  Shell: %s\n", step.Shell)
-			}
+			progress.StepPlanned(i, step.Name, cmd, cwd, step.Shell)
 			continue
 		}
+		progress.StepStarted(i, step.Name, cmd)
+
+		// A confirmation prompt would break the JSON stream
+		if progress.JSON() && !opts.Yes && dangerChecker.Check(cmd) != nil {
+			return fmt.Errorf("step %d (%s) runs a dangerous command; use --yes to run it with --log-format json", i+1, step.Name)
+		}
 
 		// Execute step using runner.Exec
 		execConfig := runnerpkg.ExecConfig{
@@ -232,22 +482,45 @@ func runNonInteractive(ctx context.Context, wf *workflows.Workflow, opts *RunOpt
 			Shell:         step.Shell,
 			CWD:           cwd,
 			Env:           step.Env,
-			Stream:        cfg.Runner.StreamOutput,
+			Stream:        cfg.Runner.StreamOutput && !progress.JSON(),
 			DangerChecker: dangerChecker,
 			AutoConfirm:   opts.Yes,
+			Timeout:       time.Duration(cfg.Runner.StepTimeout) * time.Second,
+			MaxDuration:   step.MaxDuration,
+			OnOverBudget:  overBudget(ctx, cfg, progress, wf.Title, i, step),
 		}
 
-		result := runnerpkg.Exec(context.Background(), execConfig)
+		result := runnerpkg.Exec(ctx, execConfig)
+		captured := runnerpkg.ApplyCaptures(&step, &result)
+		for k, v := range captured {
+			allParams[k] = v
+		}
+		results[i] = runnerpkg.StepResult{
+			Step:         i,
+			Success:      result.Success,
+			ExitCode:     result.ExitCode,
+			Output:       result.Output,
+			Duration:     result.Duration,
+			Error:        result.Error,
+			Captured:     captured,
+			StartedAt:    result.StartedAt,
+			Confirmation: result.Confirmation,
+		}
 
 		// Show output if streaming was not enabled
-		if !cfg.Runner.StreamOutput && result.Output != "" {
+		if !cfg.Runner.StreamOutput && !progress.JSON() && result.Output != "" {
 			fmt.Print(result.Output)
 		}
+		progress.StepFinished(i, step.Name, results[i], redact.String(result.Output, cfg.Runner.RedactLogs))
 
 		// Check for cancellation
 		if result.ExitCode == 13 {
-			fmt.Println("\nWorkflow canceled")
-			return fmt.Errorf("workflow canceled (exit code 13)")
+			runpath.Cleanup(ctx, cfg, wf, results, allParams, opts.Yes, !progress.JSON(), cleanupOutput(progress))
+			recordPath := recordRun(cfg, wf, results, started, false, true)
+			writeRunSummary(opts.SummaryFile, cfg, wf, results, allParams, started, statusCanceled, recordPath)
+			progress.RunFinished(statusCanceled, time.Since(started))
+			say("\n" + i18n.T("run.canceled"))
+			return errRunCanceled
 		}
 
 		// Check for failure
@@ -255,22 +528,36 @@ func runNonInteractive(ctx context.Context, wf *workflows.Workflow, opts *RunOpt
 			if !step.ContinueOnError {
 				success = false
 				failedStep = i
-				fmt.Printf("\n✗ Step failed with exit code %d\n", result.ExitCode)
 				if result.Error != nil {
-					fmt.Printf("  Error: %v\n", result.Error)
+					say(fmt.Sprintf("  Error: %v", result.Error))
 				}
 				break
 			}
-			fmt.Printf("\n⚠ Step failed (exit code %d) but continuing...\n", result.ExitCode)
+			say(fmt.Sprintf("\n⚠ Step failed (exit code %d) but continuing...", result.ExitCode))
 		}
 	}
 
+	if !opts.DryRun {
+		if !success {
+			runpath.Cleanup(ctx, cfg, wf, results, allParams, opts.Yes, !progress.JSON(), cleanupOutput(progress))
+		}
+		recordPath := recordRun(cfg, wf, results, started, success, false)
+		status := statusSucceeded
+		if !success {
+			status = statusFailed
+		}
+		writeRunSummary(opts.SummaryFile, cfg, wf, results, allParams, started, status, recordPath)
+	}
+	opts.collectValues(allParams)
+
 	if success {
-		fmt.Println("\n✓ Workflow completed successfully")
+		progress.RunFinished(statusSucceeded, time.Since(started))
+		say("\n" + i18n.T("run.succeeded"))
 		return nil
 	}
+	progress.RunFinished(statusFailed, time.Since(started))
 
-	return fmt.Errorf("workflow failed at step %d (exit code 20)", failedStep)
+	return svferrors.WithExitCode(fmt.Errorf("workflow failed at step %d", failedStep), svferrors.ExitStepFailed)
 }
 
 // runInteractive executes a workflow with TUI.
@@ -315,8 +602,40 @@ func runInteractive(ctx context.Context, wf *workflows.Workflow, opts *RunOption
 		RepoRoot:   cfg.Repo.Path,
 	}
 
+	started := time.Now()
+
+	// Without a TUI, run with sequential line prompts
+	if GetInteractionMode(cfg) == ModeLine {
+		result, err := tui.RunWorkflowLine(ctx, plan, cfg, tui.NewStdioLinePrompter())
+		if err != nil {
+			return err
+		}
+		if !result.Success {
+			runpath.Cleanup(ctx, cfg, &filteredWf, result.Results, result.Params, false, true, os.Stdout)
+		}
+		recordPath := recordRun(cfg, &filteredWf, result.Results, started, result.Success, result.Canceled)
+		writeRunSummary(opts.SummaryFile, cfg, &filteredWf, result.Results, result.Params, started, endStatus(result.Success, result.Canceled), recordPath)
+		opts.collectValues(result.Params)
+		if result.Canceled {
+			return errRunCanceled
+		}
+		if !result.Success {
+			return svferrors.WithExitCode(errors.New("workflow failed"), svferrors.ExitStepFailed)
+		}
+		fmt.Println("\n" + i18n.T("run.succeeded"))
+		return nil
+	}
+
 	// Create TUI runner model with full config support
 	model := tui.NewRunnerModelWithConfig(plan, cfg)
+	if opts.pipeline.Position > 0 {
+		model.SetPipeline(opts.pipeline.Position, opts.pipeline.Titles)
+	}
+	if runs, err := runlog.NewDefaultStore(); err == nil {
+		if records, err := runs.List(); err == nil {
+			model.SetStepEstimates(runlog.EstimateStepDurations(records, filteredWf.ID, len(filteredWf.Steps)))
+		}
+	}
 
 	// Run the TUI
 	p := tea.NewProgram(model)
@@ -327,12 +646,121 @@ func runInteractive(ctx context.Context, wf *workflows.Workflow, opts *RunOption
 
 	// Check result
 	result := finalModel.(tui.RunnerModel)
+	if !result.DidSucceed() {
+		runpath.Cleanup(ctx, cfg, &filteredWf, result.StepResults, result.Placeholders, false, true, os.Stdout)
+	}
+	recordPath := recordRun(cfg, &filteredWf, result.StepResults, started, result.DidSucceed(), result.DidCancel())
+	writeRunSummary(opts.SummaryFile, cfg, &filteredWf, result.StepResults, result.Placeholders, started, endStatus(result.DidSucceed(), result.DidCancel()), recordPath)
+	opts.collectValues(result.Placeholders)
+	if len(result.FlaggedCommands) > 0 {
+		learnDangerRules(ctx, cfg, result.FlaggedCommands)
+	}
 	if result.DidCancel() {
-		return fmt.Errorf("workflow canceled (exit code 13)")
+		return errRunCanceled
 	}
 	if !result.DidSucceed() {
-		return fmt.Errorf("workflow failed (exit code 20)")
+		return svferrors.WithExitCode(errors.New("workflow failed"), svferrors.ExitStepFailed)
 	}
 
 	return nil
 }
+
+// recordRun saves a local run record of the executed steps and returns
+// its path, or "" when nothing was recorded. Steps that never ran are
+// omitted. Commands are stored before placeholder substitution so secret
+// values are not persisted, and output is redacted at the
+// runner.redact_logs level. Recording problems are reported as warnings
+// and never fail the run.
+func recordRun(cfg *config.Config, wf *workflows.Workflow, results []runnerpkg.StepResult, started time.Time, success, canceled bool) string {
+	rec := &runlog.Record{
+		WorkflowID:    wf.ID,
+		WorkflowTitle: wf.Title,
+		Repo:          cfg.Repo.Path,
+		StartedAt:     started,
+		FinishedAt:    time.Now(),
+		Success:       success,
+		Canceled:      canceled,
+	}
+
+	for i, r := range results {
+		if i >= len(wf.Steps) || !runpath.StepRan(r) {
+			continue
+		}
+		step := runlog.StepRecord{
+			Index:        i,
+			Name:         wf.Steps[i].Name,
+			Command:      wf.Steps[i].Command,
+			ExitCode:     r.ExitCode,
+			Success:      r.Success,
+			Skipped:      r.Skipped,
+			SkipReason:   r.SkipReason,
+			Confirmation: r.Confirmation,
+			Output:       runlog.TruncateOutput(redact.String(r.Output, cfg.Runner.RedactLogs)),
+			StartedAt:    r.StartedAt,
+			Duration:     r.Duration,
+		}
+		if r.Error != nil {
+			step.Error = redact.String(r.Error.Error(), cfg.Runner.RedactLogs)
+		}
+		rec.Steps = append(rec.Steps, step)
+	}
+
+	if len(rec.Steps) == 0 {
+		return ""
+	}
+	recordUsage(cfg, wf, success, canceled)
+
+	runs, err := runlog.NewDefaultStore()
+	if err == nil {
+		err = runs.Save(rec)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record run: %v\n", err)
+		return ""
+	}
+
+	if !success && !canceled {
+		fmt.Fprintf(os.Stderr, "Run recorded as %s. Ask the AI about the failure with: svf explain --run %s\n", rec.ID, rec.ID)
+	}
+	return runs.Path(rec.ID)
+}
+
+// recordUsage adds the run to the repo's usage stats when
+// runner.usage_stats is enabled. Canceled runs and workflows without an ID
+// (e.g. run from a file outside the repo) aren't counted, and nothing is
+// recorded in read-only mode.
+func recordUsage(cfg *config.Config, wf *workflows.Workflow, success, canceled bool) {
+	if !cfg.Runner.UsageStats || cfg.ReadOnly || canceled || wf.ID == "" {
+		return
+	}
+	if err := stats.Record(cfg.Repo.Path, wf.ID, success, time.Now()); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record usage stats: %v\n", err)
+	}
+}
+
+// overBudget returns the OnOverBudget callback of a step with a
+// max_duration: it reports the overrun and runs the over-budget hook.
+func overBudget(ctx context.Context, cfg *config.Config, progress *progress, workflow string, i int, step workflows.Step) func() {
+	if step.MaxDuration == 0 {
+		return nil
+	}
+	return func() {
+		progress.StepOverBudget(i, step.Name, step.MaxDuration)
+		if cfg.Runner.OverBudgetHook == "" {
+			return
+		}
+		event := runnerpkg.BudgetEvent{Workflow: workflow, Step: i + 1, StepName: step.Name, MaxDuration: step.MaxDuration}
+		if err := runnerpkg.RunBudgetHook(ctx, cfg.Runner.OverBudgetHook, event); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+}
+
+// cleanupOutput returns where a batch run reports its cleanup steps: with
+// --log-format json stdout carries only progress events.
+func cleanupOutput(p *progress) io.Writer {
+	if p.JSON() {
+		return os.Stderr
+	}
+	return os.Stdout
+}
@@ -0,0 +1,48 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/runpath"
+)
+
+// lockExclusiveRuns takes the run lock of each exclusive workflow in
+// items, so that no one else runs them until the returned function
+// releases the locks. With breakLock, locks held by others are taken
+// over, for runs known to be over that left their lock behind.
+func lockExclusiveRuns(ctx context.Context, repo gitrepo.Repo, cfg *config.Config, items []pipelineItem, breakLock bool) (func(), error) {
+	var releases []func() error
+	unlock := func() {
+		for _, release := range releases {
+			if err := release(); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to release run lock: %v\n", err)
+			}
+		}
+	}
+
+	locked := make(map[string]bool)
+	for _, item := range items {
+		name := runLockName(item)
+		if !item.Workflow.Exclusive || locked[name] {
+			continue
+		}
+		locked[name] = true
+		release, err := runpath.Lock(ctx, repo, cfg, item.Workflow, name, breakLock)
+		if err != nil {
+			unlock()
+			return nil, err
+		}
+		releases = append(releases, release)
+	}
+	return unlock, nil
+}
+
+// runLockName names the run lock of a pipeline item.
+func runLockName(item pipelineItem) string {
+	return runpath.LockName(item.Ref, item.Workflow)
+}
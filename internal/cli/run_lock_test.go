@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/lock"
+	"github.com/chazuruo/svf/internal/testutil"
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+// TestLockExclusiveRuns verifies that exclusive workflows are locked,
+// locally or on the remote, that a held lock names its holder, and that
+// --break-lock takes it over.
+func TestLockExclusiveRuns(t *testing.T) {
+	ctx := context.Background()
+
+	for _, mode := range []string{"local", "remote"} {
+		t.Run(mode, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Repo.Path = t.TempDir()
+			cfg.Identity.Path = "team/alice"
+			cfg.Runner.RunLock = mode
+			repo := testutil.NewFakeRepo(cfg.Repo.Path)
+
+			failover := pipelineItem{
+				Ref:      store.WorkflowRef{ID: "wf_failover"},
+				Workflow: &workflows.Workflow{Title: "Fail over", Exclusive: true},
+			}
+			check := pipelineItem{
+				Ref:      store.WorkflowRef{ID: "wf_check"},
+				Workflow: &workflows.Workflow{Title: "Check"},
+			}
+
+			unlock, err := lockExclusiveRuns(ctx, repo, cfg, []pipelineItem{failover, check}, false)
+			if err != nil {
+				t.Fatalf("lockExclusiveRuns() error = %v", err)
+			}
+			unlock()
+
+			// Someone else is running it
+			host, _ := os.Hostname()
+			holder, _ := json.Marshal(lock.Info{PID: os.Getppid(), Host: host, Op: "run Fail over", User: "team/bob", AcquiredAt: time.Now()})
+			if mode == "local" {
+				path := lock.NamedPath(cfg.Repo.Path, "wf_failover")
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, holder, 0644); err != nil {
+					t.Fatal(err)
+				}
+			} else if err := repo.PushLock(ctx, cfg.Repo.Remote, "wf_failover", holder); err != nil {
+				t.Fatal(err)
+			}
+
+			// Non-exclusive workflows still run
+			unlock, err = lockExclusiveRuns(ctx, repo, cfg, []pipelineItem{check}, false)
+			if err != nil {
+				t.Fatalf("lockExclusiveRuns() of a non-exclusive workflow error = %v", err)
+			}
+			unlock()
+
+			_, err = lockExclusiveRuns(ctx, repo, cfg, []pipelineItem{failover}, false)
+			if err == nil || !strings.Contains(err.Error(), "team/bob") || !strings.Contains(err.Error(), "--break-lock") {
+				t.Fatalf("lockExclusiveRuns() error = %v, want the holder and --break-lock", err)
+			}
+
+			unlock, err = lockExclusiveRuns(ctx, repo, cfg, []pipelineItem{failover}, true)
+			if err != nil {
+				t.Fatalf("lockExclusiveRuns() with breakLock error = %v", err)
+			}
+			unlock()
+		})
+	}
+}
@@ -0,0 +1,146 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/redact"
+	//nolint:staticcheck // SA1019 - Using runner for StepResult type
+	runnerpkg "github.com/chazuruo/svf/internal/runner"
+	"github.com/chazuruo/svf/internal/runpath"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// statusNotRun is the status of steps a run ended before.
+const statusNotRun = "not_run"
+
+// runSummary is the JSON document --summary-file writes when a run ends,
+// for wrapper scripts and chat bots to report the result.
+type runSummary struct {
+	Workflow   string            `json:"workflow"`
+	WorkflowID string            `json:"workflow_id,omitempty"`
+	Status     string            `json:"status"`
+	StartedAt  time.Time         `json:"started_at"`
+	FinishedAt time.Time         `json:"finished_at"`
+	DurationMS int64             `json:"duration_ms"`
+	Values     map[string]string `json:"values,omitempty"` // Secret values masked
+	Steps      []runSummaryStep  `json:"steps"`
+	Artifacts  []runArtifact     `json:"artifacts,omitempty"`
+}
+
+// runSummaryStep is one step of a runSummary.
+type runSummaryStep struct {
+	Step       int               `json:"step"` // 1-based
+	Name       string            `json:"name"`
+	Status     string            `json:"status"`
+	Reason     string            `json:"reason,omitempty"`
+	ExitCode   *int              `json:"exit_code,omitempty"`
+	DurationMS *int64            `json:"duration_ms,omitempty"`
+	Error      string            `json:"error,omitempty"`
+	Captured   map[string]string `json:"captured,omitempty"` // Secret values masked
+}
+
+// runArtifact is a file the run left behind.
+type runArtifact struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+// endStatus returns the status of a run that ended.
+func endStatus(success, canceled bool) string {
+	switch {
+	case canceled:
+		return statusCanceled
+	case success:
+		return statusSucceeded
+	default:
+		return statusFailed
+	}
+}
+
+// stepStatus returns the status of a step that ran or was skipped.
+func stepStatus(r runnerpkg.StepResult) string {
+	switch {
+	case r.Skipped:
+		return statusSkipped
+	case r.ExitCode == 13:
+		return statusCanceled
+	case !r.Success:
+		return statusFailed
+	default:
+		return statusSucceeded
+	}
+}
+
+// buildRunSummary summarizes a run of wf that ended with the given
+// status and placeholder values. recordPath is the run record, if saved.
+func buildRunSummary(cfg *config.Config, wf *workflows.Workflow, results []runnerpkg.StepResult, values map[string]string, started, finished time.Time, status, recordPath string) runSummary {
+	summary := runSummary{
+		Workflow:   wf.Title,
+		WorkflowID: wf.ID,
+		Status:     status,
+		StartedAt:  started.UTC(),
+		FinishedAt: finished.UTC(),
+		DurationMS: finished.Sub(started).Milliseconds(),
+		Steps:      make([]runSummaryStep, len(wf.Steps)),
+	}
+
+	// Summarize masks the secret placeholders' values
+	for _, v := range runnerpkg.Summarize(wf, values, nil).Values {
+		if summary.Values == nil {
+			summary.Values = make(map[string]string)
+		}
+		summary.Values[v.Name] = v.Value
+	}
+
+	for i, step := range wf.Steps {
+		s := runSummaryStep{Step: i + 1, Name: step.Name, Status: statusNotRun}
+		if i < len(results) && runpath.StepRan(results[i]) {
+			r := results[i]
+			s.Status, s.Reason = stepStatus(r), r.SkipReason
+			if !r.Skipped {
+				exitCode, ms := r.ExitCode, r.Duration.Milliseconds()
+				s.ExitCode, s.DurationMS = &exitCode, &ms
+			}
+			if r.Error != nil {
+				s.Error = redact.String(r.Error.Error(), cfg.Runner.RedactLogs)
+			}
+			for name, value := range r.Captured {
+				if s.Captured == nil {
+					s.Captured = make(map[string]string)
+				}
+				if wf.Placeholders[name].Secret {
+					value = runnerpkg.MaskedValue
+				}
+				s.Captured[name] = value
+			}
+		}
+		summary.Steps[i] = s
+	}
+
+	if recordPath != "" {
+		summary.Artifacts = append(summary.Artifacts, runArtifact{Name: "run_record", Path: recordPath})
+	}
+	return summary
+}
+
+// writeRunSummary writes the --summary-file of a run that ended, if one
+// was asked for. Like the run record, a summary that can't be written is
+// a warning and doesn't change how the run ended.
+func writeRunSummary(path string, cfg *config.Config, wf *workflows.Workflow, results []runnerpkg.StepResult, values map[string]string, started time.Time, status, recordPath string) {
+	if path == "" {
+		return
+	}
+	summary := buildRunSummary(cfg, wf, results, values, started, time.Now(), status, recordPath)
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err == nil {
+		err = os.WriteFile(path, append(data, '\n'), 0644)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to write run summary %s: %v\n", path, err)
+	}
+}
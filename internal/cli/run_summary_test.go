@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/chazuruo/svf/internal/config"
+	//nolint:staticcheck // SA1019 - Using runner for StepResult type
+	runnerpkg "github.com/chazuruo/svf/internal/runner"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+func TestBuildRunSummary(t *testing.T) {
+	wf := &workflows.Workflow{
+		ID:    "wf_deploy",
+		Title: "Deploy",
+		Steps: []workflows.Step{
+			{Name: "login", Command: "login --token <token>"},
+			{Name: "build", Command: "make build"},
+			{Name: "push", Command: "docker push <image>"},
+			{Name: "notify", Command: "echo done"},
+		},
+		Placeholders: map[string]workflows.Placeholder{"token": {Secret: true}},
+	}
+	results := []runnerpkg.StepResult{
+		{Step: 0, Success: true, Duration: 2 * time.Second, Captured: map[string]string{"image": "app:1.2"}},
+		runnerpkg.PlatformSkip(1),
+		{Step: 2, ExitCode: 1, Error: errors.New("exit status 1"), Duration: time.Second},
+		{},
+	}
+	values := map[string]string{"token": "hunter2", "image": "app:1.2"}
+	started := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	summary := buildRunSummary(config.DefaultConfig(), wf, results, values, started, started.Add(3*time.Second), statusFailed, "/state/runs/r1.json")
+
+	if summary.Status != statusFailed || summary.DurationMS != 3000 || summary.WorkflowID != "wf_deploy" {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+	if want := map[string]string{"token": "***", "image": "app:1.2"}; !reflect.DeepEqual(summary.Values, want) {
+		t.Errorf("got values %v, want %v", summary.Values, want)
+	}
+
+	var statuses []string
+	for _, s := range summary.Steps {
+		statuses = append(statuses, s.Status)
+	}
+	if want := []string{statusSucceeded, statusSkipped, statusFailed, statusNotRun}; !reflect.DeepEqual(statuses, want) {
+		t.Errorf("got statuses %v, want %v", statuses, want)
+	}
+	if s := summary.Steps[0]; s.DurationMS == nil || *s.DurationMS != 2000 || s.Captured["image"] != "app:1.2" {
+		t.Errorf("unexpected first step: %+v", s)
+	}
+	if s := summary.Steps[1]; s.ExitCode != nil || s.Reason == "" {
+		t.Errorf("expected a skip reason and no exit code, got %+v", s)
+	}
+	if s := summary.Steps[2]; s.ExitCode == nil || *s.ExitCode != 1 || s.Error != "exit status 1" {
+		t.Errorf("unexpected failed step: %+v", s)
+	}
+	if want := []runArtifact{{Name: "run_record", Path: "/state/runs/r1.json"}}; !reflect.DeepEqual(summary.Artifacts, want) {
+		t.Errorf("got artifacts %v, want %v", summary.Artifacts, want)
+	}
+}
+
+func TestWriteRunSummary(t *testing.T) {
+	wf := &workflows.Workflow{
+		Title:        "Rotate",
+		Steps:        []workflows.Step{{Name: "rotate", Command: "rotate", Capture: map[string]workflows.Extractor{"key": {}}}},
+		Placeholders: map[string]workflows.Placeholder{"key": {Secret: true}},
+	}
+	results := []runnerpkg.StepResult{{Step: 0, Success: true, Captured: map[string]string{"key": "s3cr3t"}}}
+	path := filepath.Join(t.TempDir(), "summary.json")
+
+	writeRunSummary(path, config.DefaultConfig(), wf, results, nil, time.Now(), statusSucceeded, "")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected a summary file: %v", err)
+	}
+	var summary runSummary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		t.Fatalf("summary is not JSON: %v\n%s", err, data)
+	}
+	if summary.Status != statusSucceeded || summary.Steps[0].Captured["key"] != "***" {
+		t.Errorf("expected a succeeded run with the secret masked, got %s", data)
+	}
+}
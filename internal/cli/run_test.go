@@ -0,0 +1,114 @@
+// Package cli provides tests for CLI commands.
+package cli
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chazuruo/svf/internal/config"
+	//nolint:staticcheck // SA1019 - Using runner for StepResult type
+	runnerpkg "github.com/chazuruo/svf/internal/runner"
+	"github.com/chazuruo/svf/internal/runlog"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// TestRecordRun verifies that failed runs are recorded locally with only
+// the steps that actually ran.
+func TestRecordRun(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	wf := &workflows.Workflow{
+		ID:    "wf_test",
+		Title: "Deploy",
+		Steps: []workflows.Step{
+			{Name: "build", Command: "make build"},
+			{Name: "push", Command: "docker push <image>"},
+			{Name: "notify", Command: "echo done"},
+		},
+	}
+	results := []runnerpkg.StepResult{
+		{Step: 0, Success: true, Output: "ok", Duration: time.Second},
+		{Step: 1, ExitCode: 1, Output: "denied", Error: errors.New("exit status 1"), Duration: time.Second},
+		{},
+	}
+
+	recordRun(config.DefaultConfig(), wf, results, time.Now(), false, false)
+
+	store, err := runlog.NewDefaultStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec, err := store.Load("last")
+	if err != nil {
+		t.Fatalf("expected a recorded run: %v", err)
+	}
+
+	if rec.WorkflowID != "wf_test" || rec.Success {
+		t.Errorf("unexpected record: %+v", rec)
+	}
+	if len(rec.Steps) != 2 {
+		t.Fatalf("expected 2 recorded steps, got %d", len(rec.Steps))
+	}
+	failed := rec.FailedStep()
+	if failed == nil || failed.Command != "docker push <image>" || failed.Error != "exit status 1" {
+		t.Errorf("unexpected failed step: %+v", failed)
+	}
+}
+
+// TestRecordRun_RedactsOutput verifies that secrets in step output are
+// redacted before the run is saved.
+func TestRecordRun_RedactsOutput(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	wf := &workflows.Workflow{
+		ID:    "wf_test",
+		Title: "Login",
+		Steps: []workflows.Step{{Name: "login", Command: "./login.sh"}},
+	}
+	results := []runnerpkg.StepResult{
+		{Step: 0, ExitCode: 1, Output: "using password=hunter2\n", Error: errors.New("token=abcdefghijklmnopqrstuvwxyz rejected")},
+	}
+
+	recordRun(config.DefaultConfig(), wf, results, time.Now(), false, false)
+
+	store, err := runlog.NewDefaultStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec, err := store.Load("last")
+	if err != nil {
+		t.Fatalf("expected a recorded run: %v", err)
+	}
+	step := rec.Steps[0]
+	if strings.Contains(step.Output, "hunter2") || !strings.Contains(step.Output, "<REDACTED>") {
+		t.Errorf("output not redacted: %q", step.Output)
+	}
+	if strings.Contains(step.Error, "abcdefghijklmnopqrstuvwxyz") {
+		t.Errorf("error not redacted: %q", step.Error)
+	}
+}
+
+func TestApplyPreset(t *testing.T) {
+	wf := &workflows.Workflow{
+		Title:   "Deploy",
+		Presets: map[string]map[string]string{"staging": {"env": "staging", "replicas": "2", "region": "us-east-1"}},
+	}
+
+	opts := &RunOptions{Preset: "staging", Params: map[string]string{"replicas": "3"}}
+	matrix := map[string][]string{"region": {"us-east-1", "eu-west-1"}}
+	if err := applyPreset(wf, opts, matrix); err != nil {
+		t.Fatalf("applyPreset() error = %v", err)
+	}
+	want := map[string]string{"env": "staging", "replicas": "3"}
+	if !reflect.DeepEqual(opts.Params, want) {
+		t.Errorf("Params = %v, want %v", opts.Params, want)
+	}
+
+	opts = &RunOptions{Preset: "prod"}
+	if err := applyPreset(wf, opts, nil); err == nil || !strings.Contains(err.Error(), `unknown preset "prod"`) {
+		t.Errorf("applyPreset() error = %v, want unknown preset", err)
+	}
+}
@@ -0,0 +1,204 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/tui"
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+// mergeChangesSinceLoad brings changes made to a workflow's file since it
+// was loaded into wf before saving it: changes in the working copy, e.g.
+// from a sync in another terminal, or else changes pushed upstream by
+// someone else. Changes that don't overlap wf's are merged in; overlapping
+// ones are resolved in the conflict resolver. It returns the workflow to
+// save and sets opts.Base to the file it may replace.
+//
+// New workflows, forced saves and workflows the store didn't load are
+// returned unchanged.
+func mergeChangesSinceLoad(ctx context.Context, repo gitrepo.Repo, str store.Store, cfg *config.Config, wf *workflows.Workflow, opts *store.SaveOptions) (*workflows.Workflow, error) {
+	if opts.Force || opts.Path == "" {
+		return wf, nil
+	}
+	loaded := opts.Base
+	if fs, ok := str.(*store.FileSystemStore); ok && loaded == nil {
+		loaded = fs.Loaded(opts.Path)
+	}
+	if loaded == nil {
+		return wf, nil
+	}
+	opts.Base = loaded
+
+	rel := opts.Path
+	if r, err := filepath.Rel(repo.Path(), opts.Path); err == nil && !strings.HasPrefix(r, "..") {
+		rel = r
+	}
+
+	current, err := os.ReadFile(opts.Path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%s was deleted since the workflow was loaded", rel)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workflow: %w", err)
+	}
+
+	base, theirs, where := loaded.Content, current, "since it was loaded"
+	if store.BlobHash(current) == loaded.Blob {
+		// Only saves that commit, and may push, are worth a fetch
+		base, theirs = upstreamChange(ctx, repo, cfg, rel, opts.Commit)
+		if theirs == nil || store.BlobHash(theirs) == loaded.Blob {
+			return wf, nil
+		}
+		where = "upstream"
+	}
+
+	merged, err := mergeWorkflowFile(ctx, cfg, rel, wf, opts.Doc, base, theirs)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("✓ Merged changes made to %s %s\n", rel, where)
+
+	// The working copy's changes are part of the merge now
+	opts.Base = store.NewLoaded(current)
+	return merged, nil
+}
+
+// upstreamChange returns the workflow file at the repo-relative path rel
+// at the fork point and upstream, if it changed upstream since the current
+// branch forked from it. Upstream is the PR base branch in PR mode and the
+// current branch's counterpart on the remote otherwise. With fetch set the
+// remote is fetched first, for at most fetchTimeout; otherwise, or if the
+// fetch fails, upstream is as of the last fetch. It returns nils when
+// there is no change, or when upstream can't be read.
+func upstreamChange(ctx context.Context, repo gitrepo.Repo, cfg *config.Config, rel string, fetch bool) (base, theirs []byte) {
+	if cfg.Repo.Remote == "" {
+		return nil, nil
+	}
+	branch := cfg.Git.PRBaseBranch
+	if cfg.Identity.Mode != "pr" {
+		current, err := repo.GetCurrentBranch(ctx)
+		if err != nil {
+			return nil, nil
+		}
+		branch = current
+	}
+	if fetch {
+		fetchCtx, cancel := context.WithTimeout(ctx, fetchTimeout)
+		_, _ = repo.Fetch(fetchCtx, cfg.Repo.Remote)
+		cancel()
+	}
+
+	upstream := cfg.Repo.Remote + "/" + branch
+	fork, err := repo.MergeBase(ctx, "HEAD", upstream)
+	if err != nil {
+		return nil, nil
+	}
+	base, err = repo.ShowFile(ctx, fork, rel)
+	if err != nil {
+		return nil, nil
+	}
+	theirs, err = repo.ShowFile(ctx, upstream, rel)
+	if err != nil || bytes.Equal(base, theirs) {
+		return nil, nil
+	}
+	return base, theirs
+}
+
+// mergeWorkflowFile merges the changes from base to theirs, two versions
+// of the workflow file at rel, into wf, document doc of the file. Both
+// versions are rewritten the way svf saves them first, so formatting
+// differences don't conflict. Conflicts are resolved in the conflict
+// resolver, or returned as an error without a terminal.
+func mergeWorkflowFile(ctx context.Context, cfg *config.Config, rel string, wf *workflows.Workflow, doc int, base, theirs []byte) (*workflows.Workflow, error) {
+	format := workflows.FormatForPath(rel)
+	base, theirs = normalizeWorkflowFile(base, format), normalizeWorkflowFile(theirs, format)
+	ours, err := store.ReplaceDocument(base, format, wf, doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge %s: %w", rel, err)
+	}
+
+	merged, conflicts, err := gitrepo.MergeFile(ctx, ours, base, theirs)
+	if err != nil {
+		return nil, err
+	}
+	if conflicts {
+		if merged, err = resolveWorkflowConflict(ctx, cfg, rel, base, ours, theirs); err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := store.FindDocument(merged, format, wf.ID, doc)
+	if err != nil {
+		return nil, fmt.Errorf("merged %s is not a valid workflow: %w", rel, err)
+	}
+	if err := result.Validate(); err != nil {
+		return nil, fmt.Errorf("merged %s is not a valid workflow: %w", rel, err)
+	}
+	return result, nil
+}
+
+// resolveWorkflowConflict lets the user resolve the conflicting changes
+// to the workflow file at rel and returns the result.
+func resolveWorkflowConflict(ctx context.Context, cfg *config.Config, rel string, base, ours, theirs []byte) ([]byte, error) {
+	mode := GetInteractionMode(cfg)
+	if mode == ModeNone {
+		return nil, fmt.Errorf("%s changed since the workflow was loaded, and the changes conflict with yours; save again interactively to merge them", rel)
+	}
+
+	dir, err := os.MkdirTemp("", "svf-conflict-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create conflict directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	conflict, err := gitrepo.NewFileConflict(ctx, dir, rel, base, ours, theirs)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("%s changed since the workflow was loaded, and the changes conflict with yours.\n", rel)
+	var result *tui.ConflictResolverResult
+	if mode == ModeTUI {
+		result, err = tui.RunConflictResolver(ctx, conflict, []string{rel})
+	} else {
+		result, err = tui.RunConflictResolverLine(ctx, conflict, []string{rel}, tui.NewStdioLinePrompter())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("conflict resolver failed: %w", err)
+	}
+	if result.Aborted || result.ResolvedCount == 0 {
+		return nil, fmt.Errorf("save canceled: the conflicts in %s weren't resolved", rel)
+	}
+
+	merged, err := conflict.Working()
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Contains(merged, []byte("<<<<<<<")) {
+		return nil, fmt.Errorf("save canceled: %s still has conflict markers", rel)
+	}
+	return merged, nil
+}
+
+// normalizeWorkflowFile rewrites a workflow file the way svf saves it,
+// leaving content that doesn't parse as it is.
+func normalizeWorkflowFile(content []byte, format workflows.Format) []byte {
+	docs, err := workflows.UnmarshalWorkflows(content, format)
+	if err != nil {
+		return content
+	}
+	data, err := workflows.MarshalWorkflows(docs, format)
+	if err != nil {
+		return content
+	}
+	return data
+}
@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+// TestSaveAndPublish_MergesChangesSinceLoad verifies that saving a
+// workflow whose file changed since it was loaded merges the other
+// changes instead of overwriting them, and refuses to save over
+// conflicting ones without a terminal.
+func TestSaveAndPublish_MergesChangesSinceLoad(t *testing.T) {
+	ctx := context.Background()
+	setGitIdentity(t)
+	setNoTUI(t, true)
+
+	cfg := config.DefaultConfig()
+	cfg.Repo.Path = t.TempDir()
+	cfg.Identity.Path = "team/test"
+	cfg.Identity.Mode = "direct"
+	cfg.Repo.Remote = ""
+	repo := gitrepo.New(cfg.Repo.Path)
+	if err := repo.Init(ctx, gitrepo.InitOptions{}); err != nil {
+		t.Fatalf("failed to init repo: %v", err)
+	}
+	str, err := store.New(repo, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wf := &workflows.Workflow{
+		SchemaVersion: workflows.SchemaVersion,
+		Title:         "Deploy API",
+		Steps: []workflows.Step{
+			{Name: "build", Command: "make build"},
+			{Name: "test", Command: "make test"},
+			{Name: "deploy", Command: "make deploy"},
+		},
+	}
+	ref, err := saveAndPublish(ctx, repo, str, cfg, wf, store.SaveOptions{Commit: true})
+	if err != nil {
+		t.Fatalf("saveAndPublish() error = %v", err)
+	}
+
+	edit := func(from, to string) {
+		t.Helper()
+		data, err := os.ReadFile(ref.Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(ref.Path, []byte(strings.Replace(string(data), from, to, 1)), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Someone else changes the first step while we change the last one
+	loaded, err := str.Load(ctx, ref)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	edit("make build", "make build-all")
+	loaded.Steps[2].Command = "make deploy-canary"
+	if _, err := saveAndPublish(ctx, repo, str, cfg, loaded, store.SaveOptions{Path: ref.Path, Commit: true}); err != nil {
+		t.Fatalf("saveAndPublish() error = %v", err)
+	}
+	saved, err := str.Load(ctx, ref)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if saved.Steps[0].Command != "make build-all" || saved.Steps[2].Command != "make deploy-canary" {
+		t.Errorf("saved steps = %+v, want both changes", saved.Steps)
+	}
+
+	// Both changing the same step conflicts
+	edit("make test", "make test-unit")
+	saved.Steps[1].Command = "make test-all"
+	_, err = saveAndPublish(ctx, repo, str, cfg, saved, store.SaveOptions{Path: ref.Path, Commit: true})
+	if err == nil || !strings.Contains(err.Error(), "conflict") {
+		t.Fatalf("saveAndPublish() error = %v, want a conflict", err)
+	}
+	if data, _ := os.ReadFile(ref.Path); !strings.Contains(string(data), "make test-unit") {
+		t.Errorf("workflow after the conflict = %s, want their change kept", data)
+	}
+}
+
+// fetchRepo records the fetches made through it.
+type fetchRepo struct {
+	gitrepo.Repo
+	fetches     int
+	hasDeadline bool
+}
+
+func (r *fetchRepo) Fetch(ctx context.Context, remote string) (gitrepo.FetchResult, error) {
+	r.fetches++
+	_, r.hasDeadline = ctx.Deadline()
+	return r.Repo.Fetch(ctx, remote)
+}
+
+// TestUpstreamChange verifies that upstream changes are only fetched when
+// asked to, with a deadline, and are otherwise read from the last fetch.
+func TestUpstreamChange(t *testing.T) {
+	ctx := context.Background()
+	setGitIdentity(t)
+
+	remote := t.TempDir()
+	git := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git(remote, "init", "--bare", "-q")
+
+	cfg := config.DefaultConfig()
+	cfg.Repo.Path = t.TempDir()
+	cfg.Identity.Mode = "pr"
+	repo := &fetchRepo{Repo: gitrepo.New(cfg.Repo.Path)}
+	if err := repo.Init(ctx, gitrepo.InitOptions{}); err != nil {
+		t.Fatalf("failed to init repo: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(cfg.Repo.Path, "deploy.yaml"), []byte("title: Deploy\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git(cfg.Repo.Path, "add", "deploy.yaml")
+	git(cfg.Repo.Path, "commit", "-q", "-m", "base")
+	git(cfg.Repo.Path, "remote", "add", cfg.Repo.Remote, remote)
+	git(cfg.Repo.Path, "push", "-q", cfg.Repo.Remote, "HEAD:refs/heads/"+cfg.Git.PRBaseBranch)
+
+	// Someone else changes the workflow upstream
+	other := filepath.Join(t.TempDir(), "other")
+	git(remote, "clone", "-q", "-b", cfg.Git.PRBaseBranch, remote, other)
+	if err := os.WriteFile(filepath.Join(other, "deploy.yaml"), []byte("title: Deploy API\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git(other, "commit", "-q", "-am", "rename")
+	git(other, "push", "-q", "origin", "HEAD:refs/heads/"+cfg.Git.PRBaseBranch)
+
+	if base, theirs := upstreamChange(ctx, repo, cfg, "deploy.yaml", false); base != nil || theirs != nil {
+		t.Errorf("upstreamChange() without fetch = %q, %q; want nils before a fetch", base, theirs)
+	}
+	if repo.fetches != 0 {
+		t.Errorf("upstreamChange() without fetch fetched %d times", repo.fetches)
+	}
+
+	base, theirs := upstreamChange(ctx, repo, cfg, "deploy.yaml", true)
+	if string(base) != "title: Deploy\n" || string(theirs) != "title: Deploy API\n" {
+		t.Errorf("upstreamChange() with fetch = %q, %q; want the upstream change", base, theirs)
+	}
+	if repo.fetches != 1 || !repo.hasDeadline {
+		t.Errorf("upstreamChange() with fetch made %d fetches, deadline = %v; want 1 with a deadline", repo.fetches, repo.hasDeadline)
+	}
+
+	// Later saves see the fetched change without fetching again
+	if _, theirs := upstreamChange(ctx, repo, cfg, "deploy.yaml", false); string(theirs) != "title: Deploy API\n" {
+		t.Errorf("upstreamChange() after a fetch = %q, want the fetched change", theirs)
+	}
+	if repo.fetches != 1 {
+		t.Errorf("upstreamChange() without fetch fetched again (%d fetches)", repo.fetches)
+	}
+}
@@ -23,7 +23,12 @@ type SearchOptions struct {
 	Tags       []string
 	Mine       bool
 	Shared     bool
+	Regex      bool
 	JSON       bool
+	Sort       string
+
+	// IncludeDrafts includes drafts and templates in the results.
+	IncludeDrafts bool
 }
 
 // NewSearchCommand creates the search command.
@@ -47,7 +52,25 @@ Non-interactive mode (--query or --no-tui):
 Filters:
 - --mine: only show your workflows
 - --shared: only show shared workflows
-- --tag: filter by tag`,
+- --tag: filter by tag
+- --include-drafts: include drafts and templates, marked [draft] and
+  [template]
+
+Query syntax:
+- Free text is fuzzy-matched against titles, tags and content
+- field:value limits a term to one field: title:, desc:, tag:, cmd:,
+  path:, id: or kind: (tag: and kind: must match whole values; kind:draft
+  and kind:template include drafts and templates)
+- Quote values containing spaces, e.g. cmd:"kubectl delete"; a
+  backslash escapes the next character
+- --regex: treat each term as a regular expression
+
+Results are ranked by relevance; --sort orders them by title, updated,
+created, last-run or run-count instead, as for 'svf list'.`,
+		Example: `  svf search deploy
+  svf search 'tag:k8s cmd:"kubectl delete"'
+  svf search --regex 'cmd:--force(\s|$)'
+  svf search deploy --sort last-run`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) > 0 {
 				opts.Query = args[0]
@@ -61,7 +84,10 @@ Filters:
 	cmd.Flags().StringSliceVar(&opts.Tags, "tag", nil, "filter by tag (repeatable)")
 	cmd.Flags().BoolVar(&opts.Mine, "mine", false, "only show my workflows")
 	cmd.Flags().BoolVar(&opts.Shared, "shared", false, "only show shared workflows")
+	cmd.Flags().BoolVar(&opts.Regex, "regex", false, "treat query terms as regular expressions")
 	cmd.Flags().BoolVar(&opts.JSON, "json", false, "output results as JSON")
+	cmd.Flags().StringVar(&opts.Sort, "sort", "", sortFlagUsage)
+	cmd.Flags().BoolVar(&opts.IncludeDrafts, "include-drafts", false, "include drafts and templates")
 
 	return cmd
 }
@@ -69,6 +95,14 @@ Filters:
 func runSearch(opts *SearchOptions) error {
 	ctx := context.Background()
 
+	var sorter *workflowSorter
+	if opts.Sort != "" {
+		var err error
+		if sorter, err = newWorkflowSorter(opts.Sort); err != nil {
+			return err
+		}
+	}
+
 	// Load config
 	cfg, err := config.LoadWithDefaults()
 	if err != nil {
@@ -81,15 +115,15 @@ func runSearch(opts *SearchOptions) error {
 		return fmt.Errorf("repository not initialized. Run 'svf init' first")
 	}
 
-	// Check if index exists and is not stale
+	// Load the index, rebuilding it if it is missing or corrupt
 	builder := index.NewBuilder(cfg.Repo.Path, cfg)
-	idx, err := builder.Load()
+	idx, rebuilt, err := builder.LoadOrRebuild()
 	if err != nil {
-		if os.IsNotExist(err) {
-			return fmt.Errorf("search index not found. Run 'svf sync' to build it")
-		}
 		return fmt.Errorf("failed to load index: %w", err)
 	}
+	if rebuilt {
+		fmt.Fprintf(os.Stderr, "Search index was missing or corrupt; rebuilt it with %d workflows.\n", len(idx.Workflows))
+	}
 
 	// Check if index is stale
 	stale, err := builder.IsStale()
@@ -106,22 +140,24 @@ func runSearch(opts *SearchOptions) error {
 	nonInteractive := opts.Query != "" || opts.JSON || IsNoTUI()
 
 	if nonInteractive {
-		return searchNonInteractive(ctx, idx, opts, cfg)
+		return searchNonInteractive(ctx, idx, opts, cfg, sorter)
 	}
 
 	// Interactive mode
-	return searchInteractive(ctx, idx, opts, cfg)
+	return searchInteractive(ctx, idx, opts, cfg, sorter)
 }
 
 // searchNonInteractive performs non-interactive search.
-func searchNonInteractive(ctx context.Context, idx *index.Index, opts *SearchOptions, cfg *config.Config) error {
+func searchNonInteractive(ctx context.Context, idx *index.Index, opts *SearchOptions, cfg *config.Config, sorter *workflowSorter) error {
 	// Build search options
 	searchOpts := index.SearchOptions{
-		Query:      opts.Query,
-		Tags:       opts.Tags,
-		Mine:       opts.Mine,
-		Shared:     opts.Shared,
-		MaxResults: 0, // No limit
+		Query:         opts.Query,
+		Tags:          opts.Tags,
+		Mine:          opts.Mine,
+		Shared:        opts.Shared,
+		Regex:         opts.Regex,
+		IncludeDrafts: opts.IncludeDrafts,
+		MaxResults:    0, // No limit
 	}
 
 	// If --mine is specified without explicit identity path, use config identity path
@@ -130,20 +166,35 @@ func searchNonInteractive(ctx context.Context, idx *index.Index, opts *SearchOpt
 	}
 
 	// Perform search
-	results := idx.FuzzySearch(searchOpts)
+	results, err := idx.Query(searchOpts)
+	if err != nil {
+		return fmt.Errorf("invalid query: %w", err)
+	}
+	if sorter != nil {
+		sorter.sortResults(results)
+	}
+	ranks := loadPinRanks()
+	pinResultsFirst(results, ranks)
 
 	// Output results
 	if opts.JSON {
 		return outputJSON(results)
 	}
 
-	return outputPlain(results)
+	return outputPlain(results, ranks)
 }
 
 // searchInteractive performs interactive TUI search.
-func searchInteractive(ctx context.Context, idx *index.Index, opts *SearchOptions, cfg *config.Config) error {
+func searchInteractive(ctx context.Context, idx *index.Index, opts *SearchOptions, cfg *config.Config, sorter *workflowSorter) error {
+	if GetInteractionMode(cfg) == ModeLine {
+		return searchLine(idx, opts, cfg, sorter)
+	}
+
 	// Create TUI search model
 	model := tui.NewSearchModel(idx)
+	model.Regex = opts.Regex
+	model.IncludeDrafts = opts.IncludeDrafts
+	orderSearchModel(&model, sorter, loadPinRanks())
 
 	// Set initial query if provided
 	if opts.Query != "" {
@@ -187,27 +238,63 @@ func searchInteractive(ctx context.Context, idx *index.Index, opts *SearchOption
 
 	// Display selected workflow
 	if finalSearch.DidConfirm() {
-		entry := finalSearch.GetSelectedEntry()
-		if entry != nil {
-			fmt.Printf("\nSelected: %s\n", entry.Title)
-			fmt.Printf("ID: %s\n", entry.ID)
-			fmt.Printf("Path: %s\n", entry.Path)
-			if len(entry.Tags) > 0 {
-				fmt.Printf("Tags: [%s]\n", strings.Join(entry.Tags, ", "))
-			}
+		printSelectedEntry(finalSearch.GetSelectedEntry())
+	}
 
-			// Suggest next actions
-			fmt.Printf("\nNext steps:\n")
-			fmt.Printf("  svf view %s   # View workflow details\n", entry.ID)
-			fmt.Printf("  svf run %s    # Run the workflow\n", entry.ID)
-		}
+	return nil
+}
+
+// searchLine lists all matches and prompts for a selection without a TUI.
+func searchLine(idx *index.Index, opts *SearchOptions, cfg *config.Config, sorter *workflowSorter) error {
+	searchOpts := index.SearchOptions{
+		Tags:          opts.Tags,
+		Mine:          opts.Mine,
+		Shared:        opts.Shared,
+		IncludeDrafts: opts.IncludeDrafts,
+	}
+	if opts.Mine {
+		searchOpts.IdentityPath = cfg.Identity.Path
+	}
+
+	results := idx.FuzzySearch(searchOpts)
+	if sorter != nil {
+		sorter.sortResults(results)
+	}
+	pinResultsFirst(results, loadPinRanks())
+	entry, err := tui.SelectSearchResultLine(results, tui.NewStdioLinePrompter())
+	if err != nil {
+		return err
+	}
+	if entry == nil {
+		fmt.Println("Search cancelled.")
+		return nil
 	}
 
+	printSelectedEntry(entry)
 	return nil
 }
 
+// printSelectedEntry shows a selected workflow and suggested next actions.
+func printSelectedEntry(entry *index.WorkflowEntry) {
+	if entry == nil {
+		return
+	}
+
+	fmt.Printf("\nSelected: %s\n", entry.Title)
+	fmt.Printf("ID: %s\n", entry.ID)
+	fmt.Printf("Path: %s\n", entry.Location())
+	if len(entry.Tags) > 0 {
+		fmt.Printf("Tags: [%s]\n", strings.Join(entry.Tags, ", "))
+	}
+
+	// Suggest next actions
+	fmt.Printf("\nNext steps:\n")
+	fmt.Printf("  svf view %s   # View workflow details\n", entry.ID)
+	fmt.Printf("  svf run %s    # Run the workflow\n", entry.ID)
+}
+
 // outputPlain outputs search results in plain text format.
-func outputPlain(results []index.SearchResult) error {
+func outputPlain(results []index.SearchResult, pinned map[string]int) error {
 	if len(results) == 0 {
 		fmt.Println("No results found.")
 		return nil
@@ -217,7 +304,11 @@ func outputPlain(results []index.SearchResult) error {
 
 	for i, result := range results {
 		entry := result.Entry
-		fmt.Printf("%d. %s\n", i+1, entry.Title)
+		title := index.KindBadge(entry.Kind) + entry.Title
+		if _, ok := pinned[entry.ID]; ok {
+			title = pinMarker + title
+		}
+		fmt.Printf("%d. %s\n", i+1, title)
 		fmt.Printf("   ID: %s\n", entry.ID)
 		if len(entry.Tags) > 0 {
 			fmt.Printf("   Tags: %s\n", strings.Join(entry.Tags, ", "))
@@ -0,0 +1,146 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/chazuruo/svf/internal/config"
+	svferrors "github.com/chazuruo/svf/internal/errors"
+	"github.com/chazuruo/svf/internal/placeholders"
+	runnerpkg "github.com/chazuruo/svf/internal/runner"
+	"github.com/chazuruo/svf/internal/tui"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// commandSender types commands into a terminal, e.g. a tmux pane.
+type commandSender interface {
+	Send(ctx context.Context, command string) error
+	String() string
+}
+
+// runSendTo sends a workflow's steps to a tmux or screen pane instead of
+// executing them, asking before each step unless --yes is given.
+func runSendTo(ctx context.Context, wf *workflows.Workflow, opts *RunOptions, cfg *config.Config) error {
+	target, err := runnerpkg.ParseSendTarget(opts.SendTo)
+	if err != nil {
+		return err
+	}
+
+	interactive := !opts.Yes && GetInteractionMode(cfg) != ModeNone
+	p := tui.NewStdioLinePrompter()
+
+	params, err := sendToParams(wf, opts.Params, interactive, p)
+	if err != nil {
+		return err
+	}
+
+	return sendSteps(ctx, wf, params, target, opts, p)
+}
+
+// sendToParams resolves placeholder values from --param and defaults,
+// prompting for the rest when interactive. svf doesn't see the output of
+// sent steps, so captured placeholders are asked for too.
+func sendToParams(wf *workflows.Workflow, given map[string]string, interactive bool, p *tui.LinePrompter) (map[string]string, error) {
+	info := placeholders.ExtractAll(wf)
+	names := make([]string, 0, len(info))
+	for name := range info {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	params := make(map[string]string)
+	var missing []string
+	for _, name := range names {
+		if v, ok := given[name]; ok {
+			params[name] = v
+			continue
+		}
+		ph := info[name]
+		if !interactive {
+			if ph.Default == "" {
+				missing = append(missing, name)
+			}
+			params[name] = ph.Default
+			continue
+		}
+
+		question := ph.Prompt
+		if question == "" {
+			question = fmt.Sprintf("Value for <%s>", name)
+		}
+		for {
+			value, err := p.Ask(question, ph.Default)
+			if err != nil {
+				return nil, err
+			}
+			if err := placeholders.Validate(value, ph.Validate); err != nil {
+				p.Printf("%v\n", err)
+				continue
+			}
+			params[name] = value
+			break
+		}
+	}
+
+	if len(missing) > 0 {
+		return nil, svferrors.WithExitCode(fmt.Errorf("missing placeholder values (use --param to provide): <%s>", strings.Join(missing, ">, <")), svferrors.ExitMissingParam)
+	}
+	return params, nil
+}
+
+// sendSteps substitutes parameters into each step and sends it to target.
+// Steps' shell, cwd and env are not applied: the pane's own session
+// provides them.
+func sendSteps(ctx context.Context, wf *workflows.Workflow, params map[string]string, target commandSender, opts *RunOptions, p *tui.LinePrompter) error {
+	sent := 0
+	for i, step := range wf.Steps {
+		cmd, err := placeholders.Substitute(step.Command, params)
+		if err != nil {
+			return fmt.Errorf("step %d: %w", i+1, err)
+		}
+
+		name := step.Name
+		if name == "" {
+			name = fmt.Sprintf("Step %d", i+1)
+		}
+		p.Printf("Step %d/%d: %s\n  $ %s\n", i+1, len(wf.Steps), name, cmd)
+		if step.CWD != "" || len(step.Env) > 0 {
+			p.Printf("  Note: this step's cwd/env are not applied in %s\n", target)
+		}
+
+		if opts.DryRun {
+			continue
+		}
+
+		if !opts.Yes {
+			choice, err := p.Choose(fmt.Sprintf("Send to %s?", target), []tui.Choice{
+				{Key: "y", Label: "send"},
+				{Key: "s", Label: "skip"},
+				{Key: "q", Label: "quit"},
+			}, "y")
+			if err != nil {
+				return err
+			}
+			if choice == "s" {
+				continue
+			}
+			if choice == "q" {
+				p.Printf("Stopped after sending %d step(s).\n", sent)
+				return nil
+			}
+		}
+
+		if err := target.Send(ctx, cmd); err != nil {
+			return fmt.Errorf("failed to send step %d to %s: %w", i+1, target, err)
+		}
+		sent++
+	}
+
+	if !opts.DryRun {
+		p.Printf("✓ Sent %d step(s) to %s\n", sent, target)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/chazuruo/svf/internal/tui"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// fakeSender records the commands sent to it.
+type fakeSender struct {
+	sent []string
+}
+
+func (f *fakeSender) Send(ctx context.Context, command string) error {
+	f.sent = append(f.sent, command)
+	return nil
+}
+
+func (f *fakeSender) String() string { return "tmux pane %1" }
+
+func sendToWorkflow() *workflows.Workflow {
+	return &workflows.Workflow{
+		Title: "Deploy",
+		Placeholders: map[string]workflows.Placeholder{
+			"env": {Prompt: "Environment", Validate: "^(staging|prod)$"},
+		},
+		Steps: []workflows.Step{
+			{Name: "Build", Command: "make build"},
+			{Name: "Deploy", Command: "make deploy ENV=<env>"},
+			{Name: "Verify", Command: "make verify ENV=<env>"},
+		},
+	}
+}
+
+func TestSendSteps(t *testing.T) {
+	wf := sendToWorkflow()
+	params := map[string]string{"env": "staging"}
+
+	tests := []struct {
+		name  string
+		opts  RunOptions
+		input string
+		want  []string
+	}{
+		{name: "confirm each", input: "y\ns\n\n", want: []string{"make build", "make verify ENV=staging"}},
+		{name: "quit", input: "y\nq\n", want: []string{"make build"}},
+		{name: "yes", opts: RunOptions{Yes: true}, want: []string{"make build", "make deploy ENV=staging", "make verify ENV=staging"}},
+		{name: "dry run", opts: RunOptions{DryRun: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := &fakeSender{}
+			var out bytes.Buffer
+			p := tui.NewLinePrompter(strings.NewReader(tt.input), &out)
+
+			if err := sendSteps(context.Background(), wf, params, sender, &tt.opts, p); err != nil {
+				t.Fatalf("sendSteps() error = %v\n%s", err, out.String())
+			}
+			if !reflect.DeepEqual(sender.sent, tt.want) {
+				t.Errorf("sent %q, want %q", sender.sent, tt.want)
+			}
+		})
+	}
+}
+
+func TestSendToParams(t *testing.T) {
+	wf := sendToWorkflow()
+
+	// Interactive prompts re-ask until the value validates
+	var out bytes.Buffer
+	p := tui.NewLinePrompter(strings.NewReader("dev\nprod\n"), &out)
+	params, err := sendToParams(wf, nil, true, p)
+	if err != nil {
+		t.Fatalf("sendToParams() error = %v", err)
+	}
+	if params["env"] != "prod" {
+		t.Errorf("env = %q, want prod", params["env"])
+	}
+
+	// Non-interactive runs need every value up front
+	if _, err := sendToParams(wf, nil, false, p); err == nil {
+		t.Error("sendToParams() without values: expected error")
+	}
+	params, err = sendToParams(wf, map[string]string{"env": "staging"}, false, p)
+	if err != nil || params["env"] != "staging" {
+		t.Errorf("sendToParams() = %v, %v; want env=staging", params, err)
+	}
+}
@@ -0,0 +1,108 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/chazuruo/svf/internal/audit"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/runpath"
+	"github.com/chazuruo/svf/internal/web"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// ServeOptions contains the options for the serve command.
+type ServeOptions struct {
+	ConfigPath string
+	Host       string
+	Port       int
+}
+
+// NewServeCommand creates the serve command.
+func NewServeCommand() *cobra.Command {
+	opts := &ServeOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Browse workflows in a local web UI",
+		Long: `Serve a read-only web UI for browsing the workflow library.
+
+The UI lists workflows with search (the same query syntax as 'svf search')
+and tag filtering, and renders each workflow's README with copyable
+commands. Nothing can be edited or run from the browser.
+
+The server listens on localhost by default. Use --host 0.0.0.0 to share it
+on your network.`,
+		Example: `  svf serve
+  svf serve --port 9000`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runServe(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ConfigPath, "config", "", "config file path")
+	cmd.Flags().StringVar(&opts.Host, "host", "127.0.0.1", "address to listen on")
+	cmd.Flags().IntVar(&opts.Port, "port", 8080, "port to listen on")
+
+	return cmd
+}
+
+func runServe(opts *ServeOptions) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Load config
+	cfg, err := loadConfig(opts.ConfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Open repo
+	repo := gitrepo.New(cfg.Repo.Path)
+	if !repo.IsInitialized(ctx) {
+		return fmt.Errorf("repository not initialized. Run 'svf init' first")
+	}
+
+	srv, err := web.New(cfg, web.Options{
+		CheckView: func(wf *workflows.Workflow) error {
+			return runpath.RecordAccess(cfg, wf, audit.ActionView)
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create server: %w", err)
+	}
+
+	addr := net.JoinHostPort(opts.Host, strconv.Itoa(opts.Port))
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	httpServer := &http.Server{
+		Handler:           srv.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = httpServer.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Printf("Serving workflows at http://%s (Ctrl+C to stop)\n", listener.Addr())
+	if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("server failed: %w", err)
+	}
+	return nil
+}
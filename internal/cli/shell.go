@@ -0,0 +1,334 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/placeholders"
+	"github.com/chazuruo/svf/internal/runpath"
+	"github.com/chazuruo/svf/internal/tui"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+// ShellInitOptions contains the options for the shell-init command.
+type ShellInitOptions struct {
+	Key string
+}
+
+// NewShellInitCommand creates the shell-init command.
+func NewShellInitCommand() *cobra.Command {
+	opts := &ShellInitOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "shell-init <bash|zsh>",
+		Short: "Print shell widgets for inserting workflow commands at the prompt",
+		Long: `Print a shell widget that binds a hotkey (Ctrl+G by default) to step
+through a workflow from your prompt.
+
+The first press opens the search picker, asks for the workflow's
+placeholder values and inserts its first command at the prompt. Each
+further press inserts the next command, so you can review, edit and run
+them one by one in your own shell. After the last step the next press
+opens the picker again; run 'svf_reset' to abandon a workflow early.
+
+Add it to your shell's startup file:
+
+  eval "$(svf shell-init zsh)"    # ~/.zshrc
+  eval "$(svf shell-init bash)"   # ~/.bashrc`,
+		Example: `  eval "$(svf shell-init zsh)"
+  eval "$(svf shell-init bash --key o)"`,
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: []string{"bash", "zsh"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			script, err := shellInitScript(args[0], opts.Key)
+			if err != nil {
+				return err
+			}
+			fmt.Print(script)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Key, "key", "g", "letter to bind with Ctrl")
+
+	return cmd
+}
+
+// shellKeyRegex matches the keys shell-init can bind with Ctrl.
+var shellKeyRegex = regexp.MustCompile(`^[a-z]$`)
+
+// shellInitScript returns the widget script for a shell.
+func shellInitScript(shell, key string) (string, error) {
+	key = strings.ToLower(key)
+	if !shellKeyRegex.MatchString(key) {
+		return "", fmt.Errorf("invalid key %q: use a single letter", key)
+	}
+
+	switch shell {
+	case "zsh":
+		return fmt.Sprintf(zshWidget, strings.ToUpper(key)), nil
+	case "bash":
+		return fmt.Sprintf(bashWidget, key), nil
+	default:
+		return "", fmt.Errorf("unsupported shell %q: use bash or zsh", shell)
+	}
+}
+
+const zshWidget = `# svf shell integration
+_svf_next_command() {
+  local cmd
+  cmd="$(svf shell-next --session $$ </dev/tty)"
+  if [[ -n "$cmd" ]]; then
+    BUFFER="$cmd"
+    CURSOR=${#BUFFER}
+  fi
+  zle reset-prompt
+}
+svf_reset() { svf shell-next --session $$ --reset; }
+zle -N _svf_next_command
+bindkey '^%s' _svf_next_command
+`
+
+const bashWidget = `# svf shell integration
+_svf_next_command() {
+  local cmd
+  cmd="$(svf shell-next --session $$ </dev/tty)"
+  if [[ -n "$cmd" ]]; then
+    READLINE_LINE="$cmd"
+    READLINE_POINT=${#READLINE_LINE}
+  fi
+}
+svf_reset() { svf shell-next --session $$ --reset; }
+bind -x '"\C-%s": _svf_next_command'
+`
+
+// ShellNextOptions contains the options for the shell-next command.
+type ShellNextOptions struct {
+	ConfigPath string
+	Session    string
+	Reset      bool
+}
+
+// NewShellNextCommand creates the hidden shell-next command used by the
+// shell-init widgets.
+func NewShellNextCommand() *cobra.Command {
+	opts := &ShellNextOptions{}
+
+	cmd := &cobra.Command{
+		Use:    "shell-next [workflow-ref]",
+		Short:  "Print the next command of the shell session's workflow",
+		Hidden: true,
+		Args:   cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query := ""
+			if len(args) > 0 {
+				query = args[0]
+			}
+			return runShellNext(opts, query)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ConfigPath, "config", "", "config file path")
+	cmd.Flags().StringVar(&opts.Session, "session", "", "shell session ID (the shell's PID)")
+	cmd.Flags().BoolVar(&opts.Reset, "reset", false, "abandon the session's workflow")
+	_ = cmd.MarkFlagRequired("session")
+
+	return cmd
+}
+
+// shellSession is the workflow a shell is stepping through. Commands have
+// placeholders substituted, so the file is private to the user.
+type shellSession struct {
+	Title    string   `json:"title"`
+	Commands []string `json:"commands"`
+	Next     int      `json:"next"`
+}
+
+// shellSessionPath returns the state file for a shell session.
+func shellSessionPath(session string) (string, error) {
+	if strings.ContainsAny(session, `/\`) || session == "" || session == "." || session == ".." {
+		return "", fmt.Errorf("invalid session %q", session)
+	}
+	dir, err := config.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "shell", session+".json"), nil
+}
+
+// loadShellSession loads a session, returning nil if there is none.
+func loadShellSession(path string) (*shellSession, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s shellSession
+	if err := json.Unmarshal(data, &s); err != nil {
+		// A damaged session is abandoned rather than blocking the hotkey
+		return nil, nil
+	}
+	return &s, nil
+}
+
+// saveShellSession writes a session, or removes it once every command has
+// been handed out.
+func saveShellSession(path string, s *shellSession) error {
+	if s.Next >= len(s.Commands) {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// next returns the session's next command and advances past it.
+func (s *shellSession) next() string {
+	cmd := s.Commands[s.Next]
+	s.Next++
+	return cmd
+}
+
+func runShellNext(opts *ShellNextOptions, query string) error {
+	path, err := shellSessionPath(opts.Session)
+	if err != nil {
+		return err
+	}
+
+	if opts.Reset {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to reset session: %w", err)
+		}
+		return nil
+	}
+
+	session, err := loadShellSession(path)
+	if err != nil {
+		return fmt.Errorf("failed to load session: %w", err)
+	}
+
+	if session == nil || session.Next >= len(session.Commands) {
+		// The widget captures stdout, so prompts and the picker go to the
+		// terminal and only the command is printed
+		restore := useTerminalForUI()
+		session, err = startShellSession(opts.ConfigPath, query)
+		restore()
+		if err != nil {
+			if errors.Is(err, errPickCanceled) {
+				return nil
+			}
+			return err
+		}
+	}
+
+	fmt.Print(session.next())
+	return saveShellSession(path, session)
+}
+
+// useTerminalForUI points os.Stdout at the terminal until the returned
+// function is called.
+func useTerminalForUI() func() {
+	out := os.Stdout
+	tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+	if err != nil {
+		os.Stdout = os.Stderr
+		return func() { os.Stdout = out }
+	}
+	os.Stdout = tty
+	return func() {
+		os.Stdout = out
+		_ = tty.Close()
+	}
+}
+
+// startShellSession picks a workflow, asks for its placeholder values and
+// returns a session holding its substituted commands.
+func startShellSession(configPath, query string) (*shellSession, error) {
+	ctx := context.Background()
+
+	// Load config
+	cfg, err := loadConfig(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Open repo
+	repo := gitrepo.New(cfg.Repo.Path)
+	if !repo.IsInitialized(ctx) {
+		return nil, fmt.Errorf("repository not initialized. Run 'svf init' first")
+	}
+
+	// Create store
+	str, err := store.New(repo, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create store: %w", err)
+	}
+
+	ref, err := resolveOrPickWorkflow(ctx, str, cfg, query)
+	if err != nil {
+		return nil, err
+	}
+	wf, err := str.Load(ctx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load workflow: %w", err)
+	}
+	if err := runpath.CheckReview(cfg, wf, ref.Path); err != nil {
+		return nil, err
+	}
+	wf.ResolveCompanions(filepath.Dir(ref.Path))
+
+	interactive := GetInteractionMode(cfg) != ModeNone
+	p := tui.NewStdioLinePrompter()
+	var kubePrompter runpath.Prompter
+	if interactive {
+		kubePrompter = p
+	}
+	if err := runpath.CheckKube(ctx, cfg, wf, false, kubePrompter); err != nil {
+		return nil, err
+	}
+	if err := runpath.CheckCloud(ctx, wf, false); err != nil {
+		return nil, err
+	}
+
+	params, err := sendToParams(wf, nil, interactive, p)
+	if err != nil {
+		return nil, err
+	}
+
+	session := &shellSession{Title: wf.Title}
+	for i, step := range wf.Steps {
+		if !step.RunsOn(runtime.GOOS, runtime.GOARCH) {
+			continue
+		}
+		cmd, err := placeholders.Substitute(step.Command, params)
+		if err != nil {
+			return nil, fmt.Errorf("step %d: %w", i+1, err)
+		}
+		session.Commands = append(session.Commands, cmd)
+	}
+	if len(session.Commands) == 0 {
+		return nil, fmt.Errorf("%s has no steps", wf.Title)
+	}
+	return session, nil
+}
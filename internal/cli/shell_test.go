@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestShellInitScript(t *testing.T) {
+	zsh, err := shellInitScript("zsh", "g")
+	if err != nil || !strings.Contains(zsh, "bindkey '^G' _svf_next_command") {
+		t.Errorf("shellInitScript(zsh) = %q, %v", zsh, err)
+	}
+	bash, err := shellInitScript("bash", "O")
+	if err != nil || !strings.Contains(bash, `bind -x '"\C-o": _svf_next_command'`) {
+		t.Errorf("shellInitScript(bash) = %q, %v", bash, err)
+	}
+
+	for _, tt := range []struct{ shell, key string }{{"fish", "g"}, {"zsh", "gg"}, {"bash", "'"}} {
+		if _, err := shellInitScript(tt.shell, tt.key); err == nil {
+			t.Errorf("shellInitScript(%q, %q): expected error", tt.shell, tt.key)
+		}
+	}
+}
+
+func TestShellSession(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	if _, err := shellSessionPath("../evil"); err == nil {
+		t.Error("shellSessionPath() accepted a path")
+	}
+	path, err := shellSessionPath("4242")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s, err := loadShellSession(path); s != nil || err != nil {
+		t.Fatalf("loadShellSession() with no file = %v, %v", s, err)
+	}
+
+	session := &shellSession{Title: "Deploy", Commands: []string{"make build", "make deploy"}}
+	for _, want := range session.Commands {
+		loaded := session
+		if info, err := os.Stat(path); err == nil {
+			if info.Mode().Perm() != 0600 {
+				t.Errorf("session file mode = %v, want 0600", info.Mode().Perm())
+			}
+			if loaded, err = loadShellSession(path); err != nil || loaded == nil {
+				t.Fatalf("loadShellSession() = %v, %v", loaded, err)
+			}
+		}
+		if got := loaded.next(); got != want {
+			t.Errorf("next() = %q, want %q", got, want)
+		}
+		if err := saveShellSession(path, loaded); err != nil {
+			t.Fatal(err)
+		}
+		session = loaded
+	}
+
+	// The finished session is removed so the next press picks again
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("finished session file still exists: %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chazuruo/svf/internal/index"
+	"github.com/chazuruo/svf/internal/runlog"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// workflowSorts are the orders accepted by --sort.
+var workflowSorts = []string{"title", "updated", "created", "last-run", "run-count"}
+
+// sortFlagUsage is the help text of --sort.
+var sortFlagUsage = "sort by " + strings.Join(workflowSorts, ", ") + " (last-run and run-count use your run history)"
+
+// sortItem is what workflows are sorted on.
+type sortItem struct {
+	ID      string
+	Title   string
+	Updated time.Time
+}
+
+// workflowSorter orders workflows for --sort. Newest or most used comes
+// first, except for title; ties are broken by title.
+type workflowSorter struct {
+	by    string
+	usage map[string]runlog.Usage
+}
+
+// newWorkflowSorter checks a --sort value and, for the orders using it,
+// reads the run history. A missing history sorts as if nothing had run.
+func newWorkflowSorter(by string) (*workflowSorter, error) {
+	if !slices.Contains(workflowSorts, by) {
+		return nil, fmt.Errorf("unknown sort %q: use %s", by, strings.Join(workflowSorts, ", "))
+	}
+	s := &workflowSorter{by: by}
+	if by == "last-run" || by == "run-count" {
+		records, err := loadRunRecords()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to read run history: %v\n", err)
+		}
+		s.usage = runlog.SummarizeUsage(records)
+	}
+	return s, nil
+}
+
+// sortResults orders search results in place.
+func (s *workflowSorter) sortResults(results []index.SearchResult) {
+	s.sort(results, func(i int) sortItem {
+		e := results[i].Entry
+		updated, _ := time.Parse(time.RFC3339, e.UpdatedAt)
+		return sortItem{ID: e.ID, Title: e.Title, Updated: updated}
+	})
+}
+
+// loadRunRecords reads the local run history.
+func loadRunRecords() ([]*runlog.Record, error) {
+	runs, err := runlog.NewDefaultStore()
+	if err != nil {
+		return nil, err
+	}
+	return runs.List()
+}
+
+// sort orders a slice in place, given what to sort the element at each
+// index on.
+func (s *workflowSorter) sort(items any, item func(i int) sortItem) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return s.less(item(i), item(j))
+	})
+}
+
+// less reports whether a sorts before b.
+func (s *workflowSorter) less(a, b sortItem) bool {
+	switch s.by {
+	case "updated":
+		if !a.Updated.Equal(b.Updated) {
+			return a.Updated.After(b.Updated)
+		}
+	case "created":
+		ca, _ := workflows.IDTime(a.ID)
+		cb, _ := workflows.IDTime(b.ID)
+		if !ca.Equal(cb) {
+			return ca.After(cb)
+		}
+	case "last-run":
+		la, lb := s.usage[a.ID].LastRun, s.usage[b.ID].LastRun
+		if !la.Equal(lb) {
+			return la.After(lb)
+		}
+	case "run-count":
+		ra, rb := s.usage[a.ID].Runs, s.usage[b.ID].Runs
+		if ra != rb {
+			return ra > rb
+		}
+	}
+	return strings.ToLower(a.Title) < strings.ToLower(b.Title)
+}
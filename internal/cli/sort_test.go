@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chazuruo/svf/internal/index"
+	"github.com/chazuruo/svf/internal/runlog"
+)
+
+func TestWorkflowSorter(t *testing.T) {
+	base := time.Date(2026, 3, 9, 12, 0, 0, 0, time.UTC)
+	items := []sortItem{
+		{ID: "wf_01HV3K8Q0000000000000000AA", Title: "deploy", Updated: base},
+		{ID: "wf_01JB0000000000000000000000", Title: "Backup", Updated: base.Add(time.Hour)},
+		{ID: "legacy-id", Title: "Cleanup", Updated: base.Add(-time.Hour)},
+	}
+	usage := map[string]runlog.Usage{
+		"wf_01HV3K8Q0000000000000000AA": {Runs: 5, LastRun: base.Add(-24 * time.Hour)},
+		"legacy-id":                     {Runs: 1, LastRun: base},
+	}
+
+	tests := []struct {
+		by   string
+		want []string
+	}{
+		{"title", []string{"Backup", "Cleanup", "deploy"}},
+		{"updated", []string{"Backup", "deploy", "Cleanup"}},
+		{"created", []string{"Backup", "deploy", "Cleanup"}},
+		{"last-run", []string{"Cleanup", "deploy", "Backup"}},
+		{"run-count", []string{"deploy", "Cleanup", "Backup"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.by, func(t *testing.T) {
+			s := &workflowSorter{by: tt.by, usage: usage}
+			got := append([]sortItem(nil), items...)
+			s.sort(got, func(i int) sortItem { return got[i] })
+			for i, title := range tt.want {
+				if got[i].Title != title {
+					t.Fatalf("order = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+
+	if _, err := newWorkflowSorter("popularity"); err == nil {
+		t.Error("expected an unknown sort to be rejected")
+	}
+}
+
+func TestWorkflowSorter_SortResults(t *testing.T) {
+	results := []index.SearchResult{
+		{Entry: index.WorkflowEntry{Title: "Old", UpdatedAt: "2026-01-01T00:00:00Z"}, Score: 10},
+		{Entry: index.WorkflowEntry{Title: "New", UpdatedAt: "2026-03-01T00:00:00Z"}, Score: 1},
+	}
+	s := &workflowSorter{by: "updated"}
+	s.sortResults(results)
+	if results[0].Entry.Title != "New" {
+		t.Errorf("expected the newest result first, got %s", results[0].Entry.Title)
+	}
+}
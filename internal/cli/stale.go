@@ -0,0 +1,222 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rodaine/table"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/runlog"
+	runnerpkg "github.com/chazuruo/svf/internal/runner"
+)
+
+// defaultStaleDays is how long a workflow can go without being run or
+// changed before --stale reports it.
+const defaultStaleDays = 90
+
+// staleCheck finds the reasons a workflow may be out of date: it hasn't
+// been run or changed for a while, commands it runs aren't installed, or
+// its owners are gone from CODEOWNERS.
+type staleCheck struct {
+	cfg           *config.Config
+	now           time.Time
+	days          int
+	usage         map[string]runlog.Usage
+	committed     map[string]time.Time // Last commit by repo-relative, slash-separated path
+	codeowners    []gitrepo.CodeownersRule
+	hasCodeowners bool
+	lookPath      runnerpkg.LookPathFunc
+}
+
+// staleWorkflow is a workflow --stale reports and why.
+type staleWorkflow struct {
+	Info        workflowInfo
+	LastRun     time.Time
+	LastChanged time.Time
+	Reasons     []string
+}
+
+// newStaleCheck reads the run history, git history and CODEOWNERS file
+// the checks use. Any of them that can't be read is warned about and
+// treated as empty.
+func newStaleCheck(ctx context.Context, cfg *config.Config, days int) *staleCheck {
+	c := &staleCheck{cfg: cfg, now: time.Now(), days: days, lookPath: exec.LookPath}
+
+	records, err := loadRunRecords()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to read run history: %v\n", err)
+	}
+	c.usage = runlog.SummarizeUsage(records)
+
+	if c.committed, err = gitrepo.New(cfg.Repo.Path).LastCommitTimes(ctx, cfg.Workflows.Root, cfg.Workflows.SharedRoot); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to read git history: %v\n", err)
+	}
+
+	if c.codeowners, c.hasCodeowners, err = gitrepo.ReadCodeowners(cfg.Repo.Path); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to read CODEOWNERS: %v\n", err)
+	}
+	return c
+}
+
+// check returns why a workflow may be out of date; Reasons is empty if
+// it looks current.
+func (c *staleCheck) check(info workflowInfo) staleWorkflow {
+	s := staleWorkflow{Info: info, LastRun: c.usage[info.Ref.ID].LastRun}
+	rel, relErr := filepath.Rel(c.cfg.Repo.Path, info.Ref.Path)
+	if relErr == nil {
+		s.LastChanged = c.committed[filepath.ToSlash(rel)]
+	}
+	if s.LastChanged.IsZero() {
+		s.LastChanged = info.Ref.UpdatedAt // Not committed yet
+	}
+
+	cutoff := c.now.AddDate(0, 0, -c.days)
+	if s.LastRun.Before(cutoff) && s.LastChanged.Before(cutoff) {
+		s.Reasons = append(s.Reasons, fmt.Sprintf("not run or changed in %d days", c.days))
+	}
+
+	if missing := c.missingPrograms(info); len(missing) > 0 {
+		s.Reasons = append(s.Reasons, "commands not installed: "+strings.Join(missing, ", "))
+	}
+
+	if c.hasCodeowners && relErr == nil {
+		var gone []string
+		for _, owner := range c.owners(info, rel) {
+			if !codeownersLists(c.codeowners, c.cfg.Workflows.Root, owner) {
+				gone = append(gone, owner)
+			}
+		}
+		if len(gone) > 0 {
+			s.Reasons = append(s.Reasons, "owners not in CODEOWNERS: "+strings.Join(gone, ", "))
+		}
+	}
+	return s
+}
+
+// missingPrograms returns the programs the workflow's steps for this
+// platform run, or that it requires, which aren't on the PATH.
+func (c *staleCheck) missingPrograms(info workflowInfo) []string {
+	var programs []string
+	for _, req := range info.Workflow.Requires {
+		programs = append(programs, req.Command)
+	}
+	for i := range info.Workflow.Steps {
+		step := &info.Workflow.Steps[i]
+		if step.RunsOn(runtime.GOOS, runtime.GOARCH) {
+			programs = append(programs, runnerpkg.CommandPrograms(step.Command)...)
+		}
+	}
+
+	var missing []string
+	for _, program := range programs {
+		if slices.Contains(missing, program) {
+			continue
+		}
+		if _, err := c.lookPath(program); err != nil {
+			missing = append(missing, program)
+		}
+	}
+	return missing
+}
+
+// owners returns the identity paths owning a workflow: its owners field
+// or, without one, the identity whose directory it is in. Shared
+// workflows without owners have none.
+func (c *staleCheck) owners(info workflowInfo, rel string) []string {
+	if len(info.Workflow.Owners) > 0 {
+		return info.Workflow.Owners
+	}
+	// <root>/<identity path>/<slug>/workflow.yaml
+	identity, err := filepath.Rel(c.cfg.Workflows.Root, filepath.Dir(filepath.Dir(rel)))
+	if err != nil || identity == "." || strings.HasPrefix(identity, "..") {
+		return nil
+	}
+	return []string{filepath.ToSlash(identity)}
+}
+
+// codeownersLists reports whether an identity path appears in CODEOWNERS
+// rules: as a handle with the identity's last element, e.g. @alice or
+// @acme/alice for platform/alice, or as a pattern for its workflows
+// directory.
+func codeownersLists(rules []gitrepo.CodeownersRule, root, identity string) bool {
+	name := identity[strings.LastIndex(identity, "/")+1:]
+	dir := filepath.ToSlash(filepath.Join(root, identity))
+	for _, rule := range rules {
+		pattern := strings.TrimSuffix(strings.TrimSuffix(strings.Trim(rule.Pattern, "/"), "/**"), "/*")
+		if pattern == dir {
+			return true
+		}
+		for _, owner := range rule.Owners {
+			handle, ok := strings.CutPrefix(owner, "@")
+			if ok && strings.EqualFold(handle[strings.LastIndex(handle, "/")+1:], name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// dateOrNever formats a time as a date, or "never" for the zero time.
+func dateOrNever(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return t.Format("2006-01-02")
+}
+
+// printStale prints the stale workflow report in the --format.
+func printStale(stale []staleWorkflow, format string) error {
+	switch format {
+	case "json":
+		type jsonStale struct {
+			ID          string     `json:"id"`
+			Title       string     `json:"title"`
+			Path        string     `json:"path"`
+			LastRun     *time.Time `json:"last_run"`
+			LastChanged *time.Time `json:"last_changed"`
+			Reasons     []string   `json:"reasons"`
+		}
+		out := make([]jsonStale, len(stale))
+		for i, s := range stale {
+			out[i] = jsonStale{ID: s.Info.Ref.ID, Title: s.Info.Workflow.Title, Path: s.Info.Ref.Path, Reasons: s.Reasons}
+			if !s.LastRun.IsZero() {
+				out[i].LastRun = &s.LastRun
+			}
+			if !s.LastChanged.IsZero() {
+				out[i].LastChanged = &s.LastChanged
+			}
+		}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	case "plain":
+		for _, s := range stale {
+			fmt.Printf("%s: %s (%s)\n", s.Info.Ref.ID, s.Info.title(), strings.Join(s.Reasons, "; "))
+		}
+	case "table":
+		if len(stale) == 0 {
+			fmt.Println("No stale workflows.")
+			return nil
+		}
+		tbl := table.New("ID", "Title", "Last Run", "Changed", "Reasons")
+		for _, s := range stale {
+			tbl.AddRow(s.Info.Ref.ID, s.Info.title(), dateOrNever(s.LastRun), dateOrNever(s.LastChanged), strings.Join(s.Reasons, "; "))
+		}
+		tbl.Print()
+	default:
+		return fmt.Errorf("unknown format: %s", format)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/runlog"
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+func TestStaleCheck(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Repo.Path = "/repo"
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	old := now.AddDate(0, -6, 0)
+	recent := now.AddDate(0, 0, -10)
+
+	check := &staleCheck{
+		cfg:  cfg,
+		now:  now,
+		days: 90,
+		usage: map[string]runlog.Usage{
+			"wf_run": {Runs: 3, LastRun: recent},
+		},
+		committed: map[string]time.Time{
+			"workflows/platform/alice/old/workflow.yaml":     old,
+			"workflows/platform/alice/run/workflow.yaml":     old,
+			"workflows/platform/alice/changed/workflow.yaml": recent,
+			"workflows/platform/bob/tools/workflow.yaml":     recent,
+			"shared/db/workflow.yaml":                        recent,
+		},
+		codeowners:    gitrepo.ParseCodeowners("/workflows/platform/alice/ @acme/platform\n/shared/ @acme/sre @carol\n"),
+		hasCodeowners: true,
+		lookPath: func(file string) (string, error) {
+			if file == "kubectl" {
+				return "/usr/bin/kubectl", nil
+			}
+			return "", errors.New("not found")
+		},
+	}
+
+	info := func(id, rel string, wf workflows.Workflow) workflowInfo {
+		if len(wf.Steps) == 0 {
+			wf.Steps = []workflows.Step{{Command: "kubectl get pods"}}
+		}
+		return workflowInfo{Ref: store.WorkflowRef{ID: id, Path: filepath.Join(cfg.Repo.Path, rel)}, Workflow: &wf}
+	}
+	tests := []struct {
+		name string
+		info workflowInfo
+		want []string
+	}{
+		{
+			name: "not run or changed",
+			info: info("wf_old", "workflows/platform/alice/old/workflow.yaml", workflows.Workflow{}),
+			want: []string{"not run or changed in 90 days"},
+		},
+		{
+			name: "run recently",
+			info: info("wf_run", "workflows/platform/alice/run/workflow.yaml", workflows.Workflow{}),
+		},
+		{
+			name: "changed recently",
+			info: info("wf_changed", "workflows/platform/alice/changed/workflow.yaml", workflows.Workflow{}),
+		},
+		{
+			name: "missing commands and owner",
+			info: info("wf_tools", "workflows/platform/bob/tools/workflow.yaml", workflows.Workflow{
+				Requires: []workflows.Requirement{{Command: "jq"}},
+				Steps:    []workflows.Step{{Command: "terraform plan | jq ."}, {Command: "kubectl apply -f x"}},
+			}),
+			want: []string{"commands not installed: jq, terraform", "owners not in CODEOWNERS: platform/bob"},
+		},
+		{
+			name: "owners field",
+			info: info("wf_db", "shared/db/workflow.yaml", workflows.Workflow{Owners: []string{"team/carol", "team/dave"}}),
+			want: []string{"owners not in CODEOWNERS: team/dave"},
+		},
+		{
+			name: "shared without owners",
+			info: info("wf_shared", "shared/db/workflow.yaml", workflows.Workflow{}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := check.check(tt.info)
+			if !reflect.DeepEqual(got.Reasons, tt.want) {
+				t.Errorf("check() reasons = %q, want %q", got.Reasons, tt.want)
+			}
+		})
+	}
+
+	// Without a CODEOWNERS file owners aren't checked
+	check.hasCodeowners = false
+	if got := check.check(info("wf_db", "shared/db/workflow.yaml", workflows.Workflow{Owners: []string{"team/dave"}})); len(got.Reasons) != 0 {
+		t.Errorf("check() without CODEOWNERS reasons = %q, want none", got.Reasons)
+	}
+}
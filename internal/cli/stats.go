@@ -0,0 +1,192 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/index"
+	"github.com/chazuruo/svf/internal/stats"
+)
+
+// StatsOptions contains the options for the stats command.
+type StatsOptions struct {
+	ConfigPath string
+	Top        int
+	Days       int
+	MinRuns    int
+	FailRate   float64
+	JSON       bool
+}
+
+// NewStatsCommand creates the stats command.
+func NewStatsCommand() *cobra.Command {
+	opts := &StatsOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show workflow usage statistics",
+		Long: `Show which workflows are run most, which haven't been run recently and
+which fail often, to help prune dead runbooks.
+
+Usage is recorded per run when runner.usage_stats is enabled. Only the
+workflow ID, the day and whether the run succeeded are stored, under
+.svf/stats in the repository; 'svf sync' commits and shares them.`,
+		Example: `  svf stats
+  svf stats --days 30 --top 5
+  svf stats --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runStats(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ConfigPath, "config", "", "config file path")
+	cmd.Flags().IntVar(&opts.Top, "top", 10, "number of most-run workflows to show")
+	cmd.Flags().IntVar(&opts.Days, "days", 90, "report workflows not run in this many days")
+	cmd.Flags().IntVar(&opts.MinRuns, "min-runs", 3, "minimum runs before a workflow can be reported as failing")
+	cmd.Flags().Float64Var(&opts.FailRate, "fail-rate", 0.5, "failure rate at which a workflow is reported as failing")
+	cmd.Flags().BoolVar(&opts.JSON, "json", false, "output as JSON")
+
+	return cmd
+}
+
+// statsReport is the stats command output.
+type statsReport struct {
+	MostRun           []statsEntry `json:"most_run"`
+	NotRun            []statsEntry `json:"not_run"`
+	FrequentlyFailing []statsEntry `json:"frequently_failing"`
+}
+
+// statsEntry is a workflow in a stats report.
+type statsEntry struct {
+	ID       string `json:"id"`
+	Title    string `json:"title,omitempty"`
+	Runs     int    `json:"runs"`
+	Failures int    `json:"failures"`
+	LastRun  string `json:"last_run,omitempty"`
+}
+
+func runStats(opts *StatsOptions) error {
+	ctx := context.Background()
+
+	// Load config
+	var cfg *config.Config
+	var err error
+	if opts.ConfigPath != "" {
+		cfg, err = config.Load(opts.ConfigPath)
+	} else {
+		cfg, err = config.LoadWithDefaults()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Open repo
+	repo := gitrepo.New(cfg.Repo.Path)
+	if !repo.IsInitialized(ctx) {
+		return fmt.Errorf("repository not initialized. Run 'svf init' first")
+	}
+
+	events, err := stats.Load(cfg.Repo.Path)
+	if err != nil {
+		return fmt.Errorf("failed to load usage stats: %w", err)
+	}
+	if len(events) == 0 && !opts.JSON {
+		fmt.Println("No usage stats recorded yet.")
+		if !cfg.Runner.UsageStats {
+			fmt.Println("Set runner.usage_stats = true to start recording runs.")
+		}
+		return nil
+	}
+
+	idx, _, err := index.NewBuilder(cfg.Repo.Path, cfg).LoadOrRebuild()
+	if err != nil {
+		return fmt.Errorf("failed to load index: %w", err)
+	}
+
+	report := buildStatsReport(idx, stats.Summarize(events), opts, time.Now())
+
+	if opts.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+
+	printStatsSection("Most run", report.MostRun)
+	printStatsSection(fmt.Sprintf("Not run in %d days", opts.Days), report.NotRun)
+	printStatsSection(fmt.Sprintf("Frequently failing (%.0f%%+ of %d+ runs)", opts.FailRate*100, opts.MinRuns), report.FrequentlyFailing)
+	return nil
+}
+
+// buildStatsReport builds the three usage lists. Workflows are named from
+// the index; stats for workflows no longer in the index are only counted
+// in the most-run and failing lists.
+func buildStatsReport(idx *index.Index, usage map[string]*stats.Usage, opts *StatsOptions, now time.Time) statsReport {
+	entry := func(u stats.Usage) statsEntry {
+		e := statsEntry{ID: u.Workflow, Runs: u.Runs, Failures: u.Failures}
+		if wf := idx.GetByID(u.Workflow); wf != nil {
+			e.Title = wf.Title
+		}
+		if !u.LastRun.IsZero() {
+			e.LastRun = u.LastRun.Format("2006-01-02")
+		}
+		return e
+	}
+
+	report := statsReport{
+		MostRun:           []statsEntry{},
+		NotRun:            []statsEntry{},
+		FrequentlyFailing: []statsEntry{},
+	}
+	for _, u := range stats.MostRun(usage, opts.Top) {
+		report.MostRun = append(report.MostRun, entry(u))
+	}
+
+	// Drafts and templates aren't meant to be run
+	ids := make([]string, 0, len(idx.Workflows))
+	for _, wf := range idx.Workflows {
+		if wf.Kind != index.KindDraft && wf.Kind != index.KindTemplate {
+			ids = append(ids, wf.ID)
+		}
+	}
+	cutoff := now.AddDate(0, 0, -opts.Days)
+	for _, id := range stats.NotRunSince(usage, ids, cutoff) {
+		u := stats.Usage{Workflow: id}
+		if recorded, ok := usage[id]; ok {
+			u = *recorded
+		}
+		report.NotRun = append(report.NotRun, entry(u))
+	}
+
+	for _, u := range stats.FrequentlyFailing(usage, opts.MinRuns, opts.FailRate) {
+		report.FrequentlyFailing = append(report.FrequentlyFailing, entry(u))
+	}
+	return report
+}
+
+// printStatsSection prints a titled list of workflows.
+func printStatsSection(title string, entries []statsEntry) {
+	fmt.Printf("%s:\n", title)
+	if len(entries) == 0 {
+		fmt.Println("  (none)")
+	}
+	for _, e := range entries {
+		name := e.Title
+		if name == "" {
+			name = e.ID
+		}
+		last := e.LastRun
+		if last == "" {
+			last = "never"
+		}
+		fmt.Printf("  %-40s %4d run(s) %4d failed  last: %s\n", name, e.Runs, e.Failures, last)
+	}
+	fmt.Println()
+}
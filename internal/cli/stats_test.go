@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/index"
+	"github.com/chazuruo/svf/internal/stats"
+)
+
+// TestCommitUsageStats verifies that stats recorded on two branches are
+// committed and merge by union without conflicts.
+func TestCommitUsageStats(t *testing.T) {
+	ctx := context.Background()
+	setGitIdentity(t)
+
+	dir := t.TempDir()
+	repo := gitrepo.New(dir)
+	if err := repo.Init(ctx, gitrepo.InitOptions{}); err != nil {
+		t.Fatalf("failed to init repo: %v", err)
+	}
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("commit", "--allow-empty", "-m", "base")
+
+	// Nothing to commit yet
+	if err := commitUsageStats(ctx, repo); err != nil {
+		t.Fatalf("commitUsageStats() with no stats error = %v", err)
+	}
+
+	now := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	if err := stats.Record(dir, "wf_base", true, now); err != nil {
+		t.Fatal(err)
+	}
+	if err := commitUsageStats(ctx, repo); err != nil {
+		t.Fatalf("commitUsageStats() error = %v", err)
+	}
+
+	git("checkout", "-b", "other")
+	if err := stats.Record(dir, "wf_theirs", true, now); err != nil {
+		t.Fatal(err)
+	}
+	if err := commitUsageStats(ctx, repo); err != nil {
+		t.Fatal(err)
+	}
+	git("checkout", "-")
+	if err := stats.Record(dir, "wf_ours", false, now); err != nil {
+		t.Fatal(err)
+	}
+	if err := commitUsageStats(ctx, repo); err != nil {
+		t.Fatal(err)
+	}
+
+	git("merge", "--no-edit", "other")
+
+	events, err := stats.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 3 {
+		t.Errorf("stats.Load() after merge = %d events, want 3: %+v", len(events), events)
+	}
+	if status, err := repo.Status(ctx); err != nil || status.Dirty {
+		t.Errorf("Status() dirty = %v, err = %v; want clean", status.Dirty, err)
+	}
+
+	// Other staged changes block the commit without staging the stats
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("wip"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git("add", "notes.txt")
+	if err := stats.Record(dir, "wf_later", true, now); err != nil {
+		t.Fatal(err)
+	}
+	if err := commitUsageStats(ctx, repo); err == nil {
+		t.Fatal("commitUsageStats() with other changes staged succeeded")
+	}
+	staged, err := stagedPaths(ctx, repo)
+	if err != nil || len(staged) != 1 || staged[0] != "notes.txt" {
+		t.Errorf("staged after refusal = %v, %v; want only notes.txt", staged, err)
+	}
+}
+
+func TestBuildStatsReport(t *testing.T) {
+	idx := &index.Index{Workflows: []index.WorkflowEntry{
+		{ID: "wf_deploy", Title: "Deploy"},
+		{ID: "wf_unused", Title: "Unused"},
+	}}
+	usage := stats.Summarize([]stats.Event{
+		{Workflow: "wf_deploy", Date: "2026-03-01", Success: true},
+		{Workflow: "wf_gone", Date: "2026-03-01", Success: false},
+	})
+	opts := &StatsOptions{Top: 10, Days: 90, MinRuns: 1, FailRate: 0.5}
+
+	report := buildStatsReport(idx, usage, opts, time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC))
+
+	if len(report.MostRun) != 2 {
+		t.Errorf("MostRun = %+v, want 2 entries", report.MostRun)
+	}
+	if len(report.NotRun) != 1 || report.NotRun[0].Title != "Unused" || report.NotRun[0].LastRun != "" {
+		t.Errorf("NotRun = %+v, want [Unused]", report.NotRun)
+	}
+	if len(report.FrequentlyFailing) != 1 || report.FrequentlyFailing[0].ID != "wf_gone" {
+		t.Errorf("FrequentlyFailing = %+v, want [wf_gone]", report.FrequentlyFailing)
+	}
+}
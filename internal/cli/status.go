@@ -3,17 +3,21 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 	"github.com/chazuruo/svf/internal/config"
 	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/tui"
 )
 
 // StatusOptions contains the options for the status command.
 type StatusOptions struct {
 	ConfigPath string
 	JSON       bool
+	Fix        bool
 }
 
 // NewStatusCommand creates the status command.
@@ -30,7 +34,12 @@ Shows:
 - Last sync time
 - Index freshness
 - Identity path
-- Repository path`,
+- Repository path
+
+Also detects broken repository states: an unfinished merge or rebase, a
+detached HEAD, a missing remote and a missing identity directory. On a
+terminal, each repair is offered in turn; --fix applies them all without
+asking, except adding a remote, which needs its URL.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runStatus(opts)
 		},
@@ -38,6 +47,7 @@ Shows:
 
 	cmd.Flags().StringVar(&opts.ConfigPath, "config", "", "config file path")
 	cmd.Flags().BoolVar(&opts.JSON, "json", false, "output in JSON format")
+	cmd.Flags().BoolVar(&opts.Fix, "fix", false, "repair detected problems without asking")
 
 	return cmd
 }
@@ -50,6 +60,9 @@ func runStatus(opts *StatusOptions) error {
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
+	if opts.Fix && cfg.ReadOnly {
+		return fmt.Errorf("'svf status --fix' is disabled in read-only mode (readonly = true in the config)")
+	}
 
 	// Open repo
 	repo := gitrepo.New(cfg.Repo.Path)
@@ -67,11 +80,32 @@ func runStatus(opts *StatusOptions) error {
 		return fmt.Errorf("failed to get status: %w", err)
 	}
 
+	problems, err := diagnoseRepo(ctx, cfg, repo, status)
+	if err != nil {
+		return fmt.Errorf("failed to check repository: %w", err)
+	}
+
 	// Print status
 	if opts.JSON {
-		printStatusJSON(cfg, status)
+		printStatusJSON(cfg, status, problems)
 	} else {
 		printStatusPlain(cfg, status)
+		printProblems(problems)
+	}
+
+	switch {
+	case !repairable(problems), cfg.ReadOnly:
+	case opts.Fix && opts.JSON:
+		// stdout carries only the JSON
+		repairRepo(ctx, problems, nil, os.Stderr)
+	case opts.Fix:
+		fmt.Println()
+		repairRepo(ctx, problems, nil, os.Stdout)
+	case !opts.JSON && GetInteractionMode(cfg) != ModeNone && tui.IsTerminal(os.Stdin):
+		fmt.Println()
+		repairRepo(ctx, problems, tui.NewStdioLinePrompter(), os.Stdout)
+	case !opts.JSON:
+		fmt.Println("\nRun 'svf status --fix' to repair them.")
 	}
 
 	return nil
@@ -108,25 +142,41 @@ func printStatusPlain(cfg *config.Config, status gitrepo.Status) {
 	fmt.Println("  Index:     (not yet implemented)")
 }
 
-// printStatusJSON prints status in JSON format.
-func printStatusJSON(cfg *config.Config, status gitrepo.Status) {
-	fmt.Printf(`{
-  "repo": {
-    "path": "%s",
-    "branch": "%s",
-    "dirty": %t,
-    "ahead": %d,
-    "behind": %d
-  },
-  "identity": {
-    "path": "%s",
-    "mode": "%s"
-  },
-  "tool": {
-    "last_sync": null,
-    "index_fresh": null
-  }
+// statusJSON is the --json output of svf status.
+type statusJSON struct {
+	Repo struct {
+		Path     string `json:"path"`
+		Branch   string `json:"branch"`
+		Detached bool   `json:"detached"`
+		Dirty    bool   `json:"dirty"`
+		Ahead    int    `json:"ahead"`
+		Behind   int    `json:"behind"`
+	} `json:"repo"`
+	Identity struct {
+		Path string `json:"path"`
+		Mode string `json:"mode"`
+	} `json:"identity"`
+	Tool struct {
+		LastSync   *string `json:"last_sync"`
+		IndexFresh *bool   `json:"index_fresh"`
+	} `json:"tool"`
+	Problems []repoProblem `json:"problems"`
 }
-`, cfg.Repo.Path, status.Branch, status.Dirty, status.Ahead, status.Behind,
-	cfg.Identity.Path, cfg.Identity.Mode)
+
+// printStatusJSON prints status in JSON format.
+func printStatusJSON(cfg *config.Config, status gitrepo.Status, problems []repoProblem) {
+	var out statusJSON
+	out.Repo.Path, out.Repo.Branch, out.Repo.Detached = cfg.Repo.Path, status.Branch, status.Detached
+	out.Repo.Dirty, out.Repo.Ahead, out.Repo.Behind = status.Dirty, status.Ahead, status.Behind
+	out.Identity.Path, out.Identity.Mode = cfg.Identity.Path, cfg.Identity.Mode
+	out.Problems = problems
+	if out.Problems == nil {
+		out.Problems = []repoProblem{}
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return
+	}
+	fmt.Println(string(data))
 }
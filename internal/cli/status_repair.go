@@ -0,0 +1,212 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/tui"
+)
+
+// Kinds of repository problems found by svf status.
+const (
+	problemMerge      = "merge_in_progress"
+	problemRebase     = "rebase_in_progress"
+	problemDetached   = "detached_head"
+	problemNoRemote   = "missing_remote"
+	problemNoIdentity = "missing_identity_dir"
+)
+
+// repoProblem is a broken repository state and, if svf can repair it, how.
+type repoProblem struct {
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+	// Repair describes what the repair does; empty when there is none.
+	Repair string `json:"repair,omitempty"`
+	// Hint says how to fix the problem by hand when there is no repair.
+	Hint string `json:"hint,omitempty"`
+
+	// fix performs the repair. p asks for input the repair needs, and is
+	// nil when repairing without prompts.
+	fix func(ctx context.Context, p *tui.LinePrompter) error
+	// needsInput is set when fix can't run without p.
+	needsInput bool
+}
+
+// diagnoseRepo looks for states that keep svf from working: an unfinished
+// merge or rebase, a detached HEAD, a missing remote and a missing
+// identity directory.
+func diagnoseRepo(ctx context.Context, cfg *config.Config, repo gitrepo.Repo, status gitrepo.Status) ([]repoProblem, error) {
+	var problems []repoProblem
+
+	state, err := repo.GetMergeState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	switch state.Operation {
+	case gitrepo.OpMerge:
+		problems = append(problems, repoProblem{
+			Kind:    problemMerge,
+			Message: fmt.Sprintf("A merge is in progress with %d conflicted file(s)", len(state.Conflicts)),
+			Repair:  "abort the merge, restoring the state before it",
+			fix:     func(ctx context.Context, _ *tui.LinePrompter) error { return repo.AbortMerge(ctx) },
+		})
+	case gitrepo.OpRebase:
+		problems = append(problems, repoProblem{
+			Kind:    problemRebase,
+			Message: fmt.Sprintf("A rebase is in progress with %d conflicted file(s)", len(state.Conflicts)),
+			Repair:  "abort the rebase, returning to the original branch",
+			fix:     func(ctx context.Context, _ *tui.LinePrompter) error { return repo.AbortRebase(ctx) },
+		})
+	}
+
+	// A stopped rebase detaches HEAD too; aborting it reattaches it
+	if status.Detached && !state.InProgress() {
+		problem, err := detachedHeadProblem(ctx, cfg, repo)
+		if err != nil {
+			return nil, err
+		}
+		problems = append(problems, problem)
+	}
+
+	remotes, err := gitrepo.Remotes(ctx, cfg.Repo.Path)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(remotes, cfg.Repo.Remote) {
+		remote := cfg.Repo.Remote
+		problems = append(problems, repoProblem{
+			Kind:    problemNoRemote,
+			Message: fmt.Sprintf("Remote %q does not exist; sync and publish need it", remote),
+			Repair:  fmt.Sprintf("add remote %q", remote),
+			fix: func(ctx context.Context, p *tui.LinePrompter) error {
+				url, err := p.Ask(fmt.Sprintf("URL of remote %q", remote), "")
+				if err != nil {
+					return err
+				}
+				if url = strings.TrimSpace(url); url == "" {
+					return fmt.Errorf("no URL given")
+				}
+				return gitrepo.AddRemote(ctx, cfg.Repo.Path, remote, url)
+			},
+			needsInput: true,
+		})
+	}
+
+	if cfg.Identity.Path != "" {
+		rel := filepath.Join(cfg.Workflows.Root, cfg.Identity.Path)
+		dir := filepath.Join(cfg.Repo.Path, rel)
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			problems = append(problems, repoProblem{
+				Kind:    problemNoIdentity,
+				Message: fmt.Sprintf("Identity directory %s does not exist", rel),
+				Repair:  fmt.Sprintf("create %s", rel),
+				fix: func(context.Context, *tui.LinePrompter) error {
+					if err := os.MkdirAll(dir, 0755); err != nil {
+						return err
+					}
+					return writeFileIfMissing(filepath.Join(dir, ".gitkeep"), "")
+				},
+			})
+		}
+	}
+
+	return problems, nil
+}
+
+// detachedHeadProblem describes a detached HEAD. It is repaired by
+// switching to a branch at the same commit, or to the configured branch
+// when that loses no commits; otherwise the commits need a new branch.
+func detachedHeadProblem(ctx context.Context, cfg *config.Config, repo gitrepo.Repo) (repoProblem, error) {
+	problem := repoProblem{
+		Kind:    problemDetached,
+		Message: "HEAD is detached; new commits won't be on any branch",
+	}
+
+	branches, err := gitrepo.BranchesAt(ctx, cfg.Repo.Path, "HEAD")
+	if err != nil {
+		return problem, err
+	}
+	branch := ""
+	switch {
+	case len(branches) > 0:
+		branch = branches[0]
+		if slices.Contains(branches, cfg.Repo.Branch) {
+			branch = cfg.Repo.Branch
+		}
+	case cfg.Repo.Branch != "":
+		// An error means the configured branch doesn't exist
+		if ok, err := gitrepo.IsAncestor(ctx, cfg.Repo.Path, "HEAD", cfg.Repo.Branch); err == nil && ok {
+			branch = cfg.Repo.Branch
+		}
+	}
+
+	if branch == "" {
+		problem.Hint = "HEAD has commits on no branch; keep them with 'git switch -c <branch>'"
+		return problem, nil
+	}
+	problem.Repair = fmt.Sprintf("switch to branch %s", branch)
+	problem.fix = func(ctx context.Context, _ *tui.LinePrompter) error { return repo.Checkout(ctx, branch) }
+	return problem, nil
+}
+
+// repairRepo offers the repairs of problems, reporting them to w. With p
+// it asks before each one; without, every repair that needs no input is
+// applied. It returns the number of problems left unrepaired.
+func repairRepo(ctx context.Context, problems []repoProblem, p *tui.LinePrompter, w io.Writer) int {
+	left := 0
+	for _, problem := range problems {
+		if problem.fix == nil {
+			left++
+			continue
+		}
+		if p == nil && problem.needsInput {
+			fmt.Fprintf(w, "○ Skipped: %s (run 'svf status' on a terminal to enter the details)\n", problem.Repair)
+			left++
+			continue
+		}
+		if p != nil {
+			ok, err := p.Confirm(fmt.Sprintf("%s. Repair: %s?", problem.Message, problem.Repair), false)
+			if err != nil || !ok {
+				left++
+				continue
+			}
+		}
+		if err := problem.fix(ctx, p); err != nil {
+			fmt.Fprintf(w, "✗ Failed to %s: %v\n", problem.Repair, err)
+			left++
+			continue
+		}
+		fmt.Fprintf(w, "✓ Repaired: %s\n", problem.Repair)
+	}
+	return left
+}
+
+// repairable reports whether any of problems has a repair.
+func repairable(problems []repoProblem) bool {
+	return slices.ContainsFunc(problems, func(p repoProblem) bool { return p.fix != nil })
+}
+
+// printProblems lists problems under the plain status output.
+func printProblems(problems []repoProblem) {
+	if len(problems) == 0 {
+		return
+	}
+	fmt.Println("\nProblems:")
+	for _, problem := range problems {
+		fmt.Printf("  ✗ %s\n", problem.Message)
+		switch {
+		case problem.Repair != "":
+			fmt.Printf("    Repair: %s\n", problem.Repair)
+		case problem.Hint != "":
+			fmt.Printf("    Fix:    %s\n", problem.Hint)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/tui"
+)
+
+func problemKinds(problems []repoProblem) string {
+	kinds := make([]string, len(problems))
+	for i, p := range problems {
+		kinds[i] = p.Kind
+	}
+	return strings.Join(kinds, ",")
+}
+
+func TestDiagnoseRepo(t *testing.T) {
+	ctx := context.Background()
+	setGitIdentity(t)
+
+	cfg := config.DefaultConfig()
+	cfg.Repo.Path = t.TempDir()
+	cfg.Identity.Path = "team/test"
+	repo := gitrepo.New(cfg.Repo.Path)
+	if err := repo.Init(ctx, gitrepo.InitOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = cfg.Repo.Path
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, out)
+		}
+	}
+	for _, content := range []string{"one\n", "two\n"} {
+		if err := os.WriteFile(filepath.Join(cfg.Repo.Path, "README.md"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		git("add", "README.md")
+		git("commit", "-m", "update")
+	}
+	branch, err := repo.GetCurrentBranch(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Repo.Branch = branch
+	git("checkout", "--detach", "HEAD~1")
+
+	diagnose := func() []repoProblem {
+		t.Helper()
+		status, err := repo.Status(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		problems, err := diagnoseRepo(ctx, cfg, repo, status)
+		if err != nil {
+			t.Fatalf("diagnoseRepo() error = %v", err)
+		}
+		return problems
+	}
+
+	problems := diagnose()
+	if got, want := problemKinds(problems), "detached_head,missing_remote,missing_identity_dir"; got != want {
+		t.Fatalf("problems = %s, want %s", got, want)
+	}
+	if want := "switch to branch " + branch; problems[0].Repair != want {
+		t.Errorf("detached HEAD repair = %q, want %q", problems[0].Repair, want)
+	}
+
+	// Without prompts, the remote is left alone since it needs a URL
+	var out bytes.Buffer
+	if left := repairRepo(ctx, problems, nil, &out); left != 1 {
+		t.Errorf("repairRepo() left %d problems, want 1:\n%s", left, out.String())
+	}
+	if _, err := os.Stat(filepath.Join(cfg.Repo.Path, "workflows", "team", "test", ".gitkeep")); err != nil {
+		t.Errorf("identity directory not created: %v", err)
+	}
+	if got := problemKinds(diagnose()); got != "missing_remote" {
+		t.Fatalf("problems after repair = %s, want missing_remote", got)
+	}
+
+	// With a prompt, the remote's URL is asked for
+	remote := t.TempDir()
+	p := tui.NewLinePrompter(strings.NewReader("y\n"+remote+"\n"), &out)
+	if left := repairRepo(ctx, diagnose(), p, &out); left != 0 {
+		t.Errorf("repairRepo() left %d problems:\n%s", left, out.String())
+	}
+	if problems := diagnose(); len(problems) != 0 {
+		t.Errorf("problems after repair = %s, want none", problemKinds(problems))
+	}
+}
+
+func TestDiagnoseRepo_Merge(t *testing.T) {
+	ctx := context.Background()
+	setGitIdentity(t)
+
+	cfg := config.DefaultConfig()
+	cfg.Repo.Path = t.TempDir()
+	repo := gitrepo.New(cfg.Repo.Path)
+	if err := repo.Init(ctx, gitrepo.InitOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	commit := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(cfg.Repo.Path, "README.md"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.AddAll(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := repo.CommitAll(ctx, content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commit("base\n")
+	base, _ := repo.GetCurrentBranch(ctx)
+	if err := repo.CreateBranch(ctx, "other"); err != nil {
+		t.Fatal(err)
+	}
+	commit("theirs\n")
+	if err := repo.Checkout(ctx, base); err != nil {
+		t.Fatal(err)
+	}
+	commit("ours\n")
+	cmd := exec.Command("git", "merge", "other")
+	cmd.Dir = cfg.Repo.Path
+	_ = cmd.Run()
+
+	status, err := repo.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	problems, err := diagnoseRepo(ctx, cfg, repo, status)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) == 0 || problems[0].Kind != problemMerge {
+		t.Fatalf("problems = %s, want merge_in_progress first", problemKinds(problems))
+	}
+	if problems[0].Message != "A merge is in progress with 1 conflicted file(s)" {
+		t.Errorf("message = %q", problems[0].Message)
+	}
+
+	var out bytes.Buffer
+	repairRepo(ctx, problems[:1], nil, &out)
+	if state, _ := repo.GetMergeState(ctx); state.InProgress() {
+		t.Errorf("merge still in progress after repair:\n%s", out.String())
+	}
+}
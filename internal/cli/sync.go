@@ -4,12 +4,18 @@ package cli
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
+	"path/filepath"
+	"strings"
 
+	"github.com/chazuruo/svf/internal/audit"
 	"github.com/chazuruo/svf/internal/config"
 	"github.com/chazuruo/svf/internal/gitrepo"
 	"github.com/chazuruo/svf/internal/index"
+	"github.com/chazuruo/svf/internal/lock"
+	"github.com/chazuruo/svf/internal/redact"
+	"github.com/chazuruo/svf/internal/stats"
 	"github.com/chazuruo/svf/internal/tui"
 	"github.com/spf13/cobra"
 )
@@ -71,8 +77,33 @@ func runSync(opts *SyncOptions) error {
 		return fmt.Errorf("repository not initialized. Run 'svf init' first")
 	}
 
+	// Hold the repo lock for the whole sync so saves and index rebuilds
+	// from other svf processes don't race git
+	l, err := lock.Acquire(cfg.Repo.Path, lock.Options{Op: "sync"})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = l.Release() }()
+
+	// Resume a merge or rebase an earlier sync left with conflicts
+	if state, err := repo.GetMergeState(ctx); err == nil && state.InProgress() {
+		fmt.Printf("Resuming the unfinished %s...\n", state.Operation)
+		return handleConflicts(ctx, repo, cfg, opts.Conflicts, &IntegrateResult{Conflicts: true, ConflictFiles: state.Conflicts})
+	}
+
 	fmt.Println("Syncing with remote...")
 
+	// Share locally recorded usage stats and audit events with the rest of
+	// the sync. Read-only mode never commits.
+	if !cfg.ReadOnly {
+		if err := commitUsageStats(ctx, repo); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to commit usage stats: %v\n", err)
+		}
+		if err := commitAuditLog(ctx, repo); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to commit audit log: %v\n", err)
+		}
+	}
+
 	// Fetch from remote
 	remote := opts.Remote
 	if remote == "" {
@@ -83,25 +114,63 @@ func runSync(opts *SyncOptions) error {
 		return err
 	}
 
+	// Remember where we were to review incoming workflow changes
+	before, _ := repo.ResolveRev(ctx, "HEAD")
+
 	// Integrate changes
 	strategy := opts.Strategy
 	if strategy == "" {
 		strategy = cfg.Repo.SyncStrategy
 	}
+	// Read-only mode has no local commits to rebase or merge
+	if cfg.ReadOnly {
+		if opts.Strategy != "" && opts.Strategy != "ff-only" {
+			return fmt.Errorf("--strategy %s is not available in read-only mode; sync fast-forwards only", opts.Strategy)
+		}
+		strategy = "ff-only"
+	}
+
+	// Index changes merge with the svf-index driver rather than conflicting
+	if err := registerIndexMergeDriver(ctx, repo.Path()); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to register index merge driver: %v\n", err)
+	}
 
 	result, err := integrateChanges(ctx, repo, strategy, opts.Conflicts)
-	if err != nil {
+	if err != nil && (result == nil || !result.Conflicts) {
 		return err
 	}
 
 	// Handle conflicts if detected
 	if result.Conflicts {
-		return handleConflicts(ctx, repo, opts.Conflicts, result)
+		return handleConflicts(ctx, repo, cfg, opts.Conflicts, result)
 	}
 
 	// Show summary
 	printSyncSummary(result)
 
+	// Review what changed in workflows
+	if before != "" && result.NewCommits > 0 {
+		review, err := describeWorkflowChanges(ctx, repo, before, "HEAD")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to review workflow changes: %v\n", err)
+		} else if review != "" {
+			fmt.Printf("\nWorkflow changes:\n%s", review)
+		}
+		if cfg.Git.ScanSecrets {
+			if err := flagIncomingSecrets(ctx, repo, cfg, before, os.Stdout); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to scan incoming workflows for secrets: %v\n", err)
+			}
+		}
+	}
+
+	// The svf-index driver merged the index entry by entry; rebuild it from
+	// the merged workflow files, which it can't see
+	if result.Merged || result.Rebased {
+		if err := refreshMergedIndex(ctx, repo, cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to rebuild the merged index: %v\n", err)
+		}
+	}
+
 	// Rebuild index if needed or requested
 	if opts.Reindex || shouldRebuildIndex(ctx, repo, cfg) {
 		if err := rebuildIndex(ctx, repo, cfg); err != nil {
@@ -112,6 +181,124 @@ func runSync(opts *SyncOptions) error {
 	return nil
 }
 
+// commitUsageStats commits usage stats recorded since the last sync. The
+// stats files merge by union, so they never conflict on integrate. Nothing
+// is committed if other changes are staged.
+func commitUsageStats(ctx context.Context, repo gitrepo.Repo) error {
+	return commitRecords(ctx, repo, stats.Dir, "Record usage stats", "usage stats")
+}
+
+// commitAuditLog commits access to sensitive workflows recorded since the
+// last sync, like commitUsageStats.
+func commitAuditLog(ctx context.Context, repo gitrepo.Repo) error {
+	return commitRecords(ctx, repo, audit.Dir, "Record sensitive workflow access", "audit log")
+}
+
+// commitRecords commits the union-merged log files in dir, or the file
+// dir, with message, unless other changes are staged.
+func commitRecords(ctx context.Context, repo gitrepo.Repo, dir, message, what string) error {
+	if _, err := os.Stat(filepath.Join(repo.Path(), dir)); err != nil {
+		return nil
+	}
+
+	// Check before staging, so the files aren't left staged when other
+	// changes keep them from being committed
+	staged, err := stagedPaths(ctx, repo)
+	if err != nil {
+		return err
+	}
+	for _, path := range staged {
+		if path != ".gitattributes" && path != dir && !strings.HasPrefix(path, dir+"/") {
+			return fmt.Errorf("other changes are staged; commit them first")
+		}
+	}
+
+	for _, path := range []string{dir, ".gitattributes"} {
+		if _, err := os.Stat(filepath.Join(repo.Path(), path)); err != nil {
+			continue
+		}
+		if err := repo.Add(ctx, path); err != nil {
+			return err
+		}
+	}
+	if staged, err = stagedPaths(ctx, repo); err != nil || len(staged) == 0 {
+		return err
+	}
+
+	if _, err := repo.CommitAll(ctx, message); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Committed %s\n", what)
+	return nil
+}
+
+// refreshMergedIndex rebuilds the search index from the workflow files
+// after a merge or rebase and commits it if it changed, so entries of
+// workflows deleted or renamed on one side don't survive the merge.
+func refreshMergedIndex(ctx context.Context, repo gitrepo.Repo, cfg *config.Config) error {
+	builder := index.NewBuilder(cfg.Repo.Path, cfg)
+	idx, err := builder.Build()
+	if err != nil {
+		return fmt.Errorf("building index: %w", err)
+	}
+	if merged, err := builder.Load(); err == nil && merged.Checksum == idx.ComputeChecksum() {
+		return nil
+	}
+	if err := builder.Save(idx); err != nil {
+		return fmt.Errorf("saving index: %w", err)
+	}
+	return commitRecords(ctx, repo, cfg.Workflows.IndexPath, "Rebuild search index after sync", "rebuilt search index")
+}
+
+// stagedPaths returns the paths with changes staged for commit.
+func stagedPaths(ctx context.Context, repo gitrepo.Repo) ([]string, error) {
+	status, err := repo.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, entry := range status.Entries {
+		if entry.X != '.' && entry.X != '?' {
+			paths = append(paths, entry.Path)
+		}
+	}
+	return paths, nil
+}
+
+// flagIncomingSecrets warns about workflow files and companion scripts
+// changed since from that appear to contain raw credentials, so they can
+// be rotated and removed. Shared workflows are marked as such.
+func flagIncomingSecrets(ctx context.Context, repo gitrepo.Repo, cfg *config.Config, from string, w io.Writer) error {
+	files, err := repo.ChangedFiles(ctx, from, "HEAD")
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		root := strings.SplitN(filepath.ToSlash(file), "/", 2)[0]
+		if (root != cfg.Workflows.Root && root != cfg.Workflows.SharedRoot) || filepath.Base(file) == "README.md" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(repo.Path(), file))
+		if err != nil {
+			// Deleted files can't leak anything new
+			continue
+		}
+		findings := redact.Scan(string(data))
+		if len(findings) == 0 {
+			continue
+		}
+		label := "Incoming file"
+		if root == cfg.Workflows.SharedRoot {
+			label = "Incoming shared file"
+		}
+		fmt.Fprintf(w, "\n⚠️  %s %s appears to contain credentials:\n", label, file)
+		for _, f := range findings {
+			fmt.Fprintf(w, "  %s\n", f)
+		}
+	}
+	return nil
+}
+
 // fetchRemote fetches from the remote repository.
 func fetchRemote(ctx context.Context, repo gitrepo.Repo, remote string) error {
 	fmt.Printf("Fetching from %s...\n", remote)
@@ -183,18 +370,91 @@ func integrateChanges(ctx context.Context, repo gitrepo.Repo, strategy string, c
 	return result, nil
 }
 
-// handleConflicts handles conflicts based on the specified mode.
-func handleConflicts(ctx context.Context, repo gitrepo.Repo, mode string, result *IntegrateResult) error {
+// handleConflicts handles conflicts based on the specified mode. Conflicts
+// in generated files (the search index and workflow READMEs) are resolved
+// automatically; only workflow content conflicts are left to the mode.
+func handleConflicts(ctx context.Context, repo gitrepo.Repo, cfg *config.Config, mode string, result *IntegrateResult) error {
+	if mode == "abort" {
+		if err := abortIntegration(ctx, repo); err != nil {
+			return fmt.Errorf("failed to abort integration: %w", err)
+		}
+		return fmt.Errorf("integration aborted due to conflicts")
+	}
+
+	conflicts, err := repo.GetConflicts(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get conflicts: %w", err)
+	}
+	generated, content := autoResolveGenerated(ctx, repo, cfg, conflicts)
+	if len(content) == 0 {
+		regenerateGenerated(ctx, repo, cfg, generated)
+		fmt.Println("✓ All conflicts resolved")
+		return finishIntegration(ctx, repo)
+	}
+
+	err = resolveContentConflicts(ctx, repo, mode, result)
+	regenerateGenerated(ctx, repo, cfg, generated)
+	if err != nil {
+		return err
+	}
+	return finishIntegration(ctx, repo)
+}
+
+// finishIntegration continues the merge or rebase in progress once all
+// its conflicts are resolved. Unresolved conflicts are left for the next
+// sync, which resumes where this one stopped.
+func finishIntegration(ctx context.Context, repo gitrepo.Repo) error {
+	state, err := repo.GetMergeState(ctx)
+	if err != nil {
+		return err
+	}
+	if !state.InProgress() || len(state.Conflicts) > 0 {
+		return nil
+	}
+
+	switch state.Operation {
+	case gitrepo.OpMerge:
+		err = repo.ContinueMerge(ctx)
+	case gitrepo.OpRebase:
+		err = repo.ContinueRebase(ctx)
+	}
+	if err != nil {
+		// A later commit of a rebase can conflict too
+		if next, stateErr := repo.GetMergeState(ctx); stateErr == nil && len(next.Conflicts) > 0 {
+			fmt.Printf("The %s stopped at more conflicts; run 'svf sync' again to resolve them.\n", state.Operation)
+			return nil
+		}
+		return fmt.Errorf("failed to continue %s: %w", state.Operation, err)
+	}
+	fmt.Printf("✓ Completed the %s\n", state.Operation)
+	return nil
+}
+
+// abortIntegration abandons the merge or rebase in progress, if any.
+func abortIntegration(ctx context.Context, repo gitrepo.Repo) error {
+	state, err := repo.GetMergeState(ctx)
+	if err != nil {
+		return err
+	}
+	switch state.Operation {
+	case gitrepo.OpMerge:
+		return repo.AbortMerge(ctx)
+	case gitrepo.OpRebase:
+		return repo.AbortRebase(ctx)
+	}
+	return nil
+}
+
+// resolveContentConflicts resolves workflow content conflicts based on the
+// specified mode.
+func resolveContentConflicts(ctx context.Context, repo gitrepo.Repo, mode string, result *IntegrateResult) error {
 	switch mode {
 	case "ours":
 		// Accept ours for all conflicts
-		return resolveAllConflicts(ctx, repo, "ours")
+		return resolveAllConflicts(ctx, repo, gitrepo.ResolveOurs)
 	case "theirs":
 		// Accept theirs for all conflicts
-		return resolveAllConflicts(ctx, repo, "theirs")
-	case "abort":
-		// Abort the integration
-		return fmt.Errorf("integration aborted due to conflicts")
+		return resolveAllConflicts(ctx, repo, gitrepo.ResolveTheirs)
 	case "tui", "":
 		// Launch TUI conflict resolver
 		return launchConflictResolver(ctx, repo, result)
@@ -203,34 +463,26 @@ func handleConflicts(ctx context.Context, repo gitrepo.Repo, mode string, result
 	}
 }
 
-// resolveAllConflicts resolves all conflicts using the specified strategy.
-func resolveAllConflicts(ctx context.Context, repo gitrepo.Repo, strategy string) error {
+// resolveAllConflicts resolves all conflicts the same way.
+func resolveAllConflicts(ctx context.Context, repo gitrepo.Repo, resolution gitrepo.Resolution) error {
 	conflicts, err := repo.GetConflicts(ctx)
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("Resolving %d conflict(s) using '%s' strategy...\n", len(conflicts), strategy)
+	fmt.Printf("Resolving %d conflict(s) using '%s' strategy...\n", len(conflicts), resolution)
 
+	failed := 0
 	for _, file := range conflicts {
-		// Use git checkout to accept ours or theirs
-		var cmd *exec.Cmd
-		if strategy == "ours" {
-			cmd = exec.Command("git", "checkout", "--ours", file)
-		} else {
-			cmd = exec.Command("git", "checkout", "--theirs", file)
-		}
-
-		if err := cmd.Run(); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to resolve %s\n", file)
+		if err := repo.ResolveFile(ctx, file, resolution); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to resolve %s: %v\n", file, err)
+			failed++
 		}
-
-		// Mark as resolved
-		cmd = exec.Command("git", "add", file)
-		_ = cmd.Run()
 	}
 
-	fmt.Println("✓ All conflicts resolved")
+	if failed == 0 {
+		fmt.Println("✓ All conflicts resolved")
+	}
 	return nil
 }
 
@@ -245,24 +497,26 @@ func launchConflictResolver(ctx context.Context, repo gitrepo.Repo, result *Inte
 		return nil
 	}
 
-	// Run the TUI conflict resolver
-	tuiResult, err := tui.RunConflictResolver(conflicts)
+	// Run the conflict resolver, falling back to line prompts without a TUI
+	var tuiResult *tui.ConflictResolverResult
+	if GetInteractionMode(nil) == ModeTUI {
+		tuiResult, err = tui.RunConflictResolver(ctx, repo, conflicts)
+	} else {
+		tuiResult, err = tui.RunConflictResolverLine(ctx, repo, conflicts, tui.NewStdioLinePrompter())
+	}
 	if err != nil {
 		return fmt.Errorf("conflict resolver failed: %w", err)
 	}
 
 	// Handle the result
 	if tuiResult.Aborted {
-		return fmt.Errorf("conflict resolution aborted")
+		return fmt.Errorf("conflict resolution aborted; run 'svf sync' to resume or 'svf sync --conflicts abort' to abandon it")
 	}
 
 	// Show summary
 	fmt.Printf("\n✓ Resolved %d/%d conflicts\n", tuiResult.ResolvedCount, tuiResult.TotalCount)
 
-	if tuiResult.ResolvedCount == tuiResult.TotalCount {
-		fmt.Println("All conflicts resolved! You can now continue working.")
-		fmt.Println("Run 'svf sync' again to complete the sync if needed.")
-	} else {
+	if tuiResult.ResolvedCount < tuiResult.TotalCount {
 		fmt.Println("Some conflicts remain unresolved.")
 		fmt.Println("Run 'svf sync' again to continue resolving.")
 	}
@@ -303,13 +557,14 @@ func rebuildIndex(ctx context.Context, repo gitrepo.Repo, cfg *config.Config) er
 	fmt.Println("\nRebuilding search index...")
 	builder := index.NewBuilder(cfg.Repo.Path, cfg)
 
-	idx, err := builder.Build()
+	// Rebuild doesn't save the index in read-only mode
+	idx, err := builder.Rebuild()
 	if err != nil {
-		return fmt.Errorf("building index: %w", err)
+		return fmt.Errorf("rebuilding index: %w", err)
 	}
-
-	if err := builder.Save(idx); err != nil {
-		return fmt.Errorf("saving index: %w", err)
+	if cfg.ReadOnly {
+		fmt.Printf("✓ Index rebuilt in memory with %d workflows (read-only mode, not saved)\n", len(idx.Workflows))
+		return nil
 	}
 
 	fmt.Printf("✓ Index updated with %d workflows\n", len(idx.Workflows))
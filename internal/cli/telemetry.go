@@ -0,0 +1,160 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/telemetry"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// TelemetryReportOptions contains the options for the telemetry report
+// command.
+type TelemetryReportOptions struct {
+	JSON  bool
+	Since string
+}
+
+// NewTelemetryCommand creates the telemetry command.
+func NewTelemetryCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "telemetry",
+		Short: "Review the usage telemetry recorded on this machine",
+		Long: `With telemetry.enabled = true in the config, svf counts which commands
+run, which flags are set and which commands fail. The counters stay on this
+machine and are never uploaded. Only command and flag names are recorded,
+never arguments, flag values or workflow content.
+
+'svf telemetry report' prints a summary you can choose to share with the
+maintainers, e.g. in an issue.`,
+	}
+
+	cmd.AddCommand(newTelemetryReportCommand())
+	cmd.AddCommand(newTelemetryClearCommand())
+
+	return cmd
+}
+
+// newTelemetryReportCommand creates the telemetry report command.
+func newTelemetryReportCommand() *cobra.Command {
+	opts := &TelemetryReportOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Summarize recorded usage for sharing",
+		Example: `  svf telemetry report
+  svf telemetry report --since 2026-01 --json > usage.json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTelemetryReport(opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.JSON, "json", false, "output as JSON")
+	cmd.Flags().StringVar(&opts.Since, "since", "", "only include usage from this month on (YYYY-MM)")
+
+	return cmd
+}
+
+// newTelemetryClearCommand creates the telemetry clear command.
+func newTelemetryClearCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "clear",
+		Short: "Delete all recorded usage",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := telemetry.NewDefaultStore()
+			if err != nil {
+				return err
+			}
+			if err := store.Clear(); err != nil {
+				return err
+			}
+			fmt.Println("✓ Cleared recorded usage")
+			return nil
+		},
+	}
+}
+
+func runTelemetryReport(opts *TelemetryReportOptions) error {
+	if opts.Since != "" {
+		if _, err := time.Parse("2006-01", opts.Since); err != nil {
+			return fmt.Errorf("invalid --since %q: use YYYY-MM", opts.Since)
+		}
+	}
+
+	store, err := telemetry.NewDefaultStore()
+	if err != nil {
+		return err
+	}
+	data, err := store.Load()
+	if err != nil {
+		return err
+	}
+	report := telemetry.Summarize(data, opts.Since, Version, runtime.GOOS+"/"+runtime.GOARCH)
+
+	if opts.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+
+	fmt.Print(report.Text())
+	if cfg, err := config.LoadWithDefaults(); err == nil && !cfg.Telemetry.Enabled {
+		fmt.Println("\nTelemetry is off. Turn it on with telemetry.enabled = true in the config.")
+	}
+	if !report.Empty() {
+		fmt.Printf("\nNothing has been sent. Share this report (or --json) with the maintainers if you like;\nthe counters are in %s.\n", store.Path())
+	}
+	return nil
+}
+
+// recordTelemetry wraps every command under root so its use is counted
+// when telemetry is enabled. settings returns the telemetry config of the
+// running command, or nil if the config couldn't be loaded. Recording
+// problems are ignored; they never affect the command.
+func recordTelemetry(root *cobra.Command, settings func() *config.TelemetryConfig) {
+	var wrap func(cmd *cobra.Command)
+	wrap = func(cmd *cobra.Command) {
+		if run := cmd.RunE; run != nil {
+			cmd.RunE = func(cmd *cobra.Command, args []string) error {
+				err := run(cmd, args)
+				if t := settings(); t != nil && t.Enabled {
+					_ = recordUsageOf(cmd, err != nil, t.Record)
+				}
+				return err
+			}
+		}
+		for _, sub := range cmd.Commands() {
+			wrap(sub)
+		}
+	}
+	wrap(root)
+}
+
+// recordUsageOf counts a use of cmd in the default telemetry store.
+func recordUsageOf(cmd *cobra.Command, failed bool, include []string) error {
+	store, err := telemetry.NewDefaultStore()
+	if err != nil {
+		return err
+	}
+	return store.Record(commandUsage(cmd, failed), include)
+}
+
+// commandUsage describes a use of cmd: its path below the root and the
+// names of the flags that were set.
+func commandUsage(cmd *cobra.Command, failed bool) telemetry.Usage {
+	path := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name())
+	u := telemetry.Usage{Command: strings.TrimSpace(path), Failed: failed, At: time.Now()}
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		u.Flags = append(u.Flags, f.Name)
+	})
+	return u
+}
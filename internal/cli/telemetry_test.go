@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chazuruo/svf/internal/telemetry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordTelemetry(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_STATE_HOME", filepath.Join(home, "state"))
+	t.Setenv("GITSAVVY_TELEMETRY_ENABLED", "")
+	t.Setenv("GITSAVVY_REPO_PATH", filepath.Join(home, "repo"))
+
+	execute := func(args ...string) {
+		root := NewRootCommand("svf", BuildInfo{})
+		root.SetArgs(args)
+		root.SetOut(io.Discard)
+		root.SetErr(io.Discard)
+		stdout := os.Stdout
+		os.Stdout, _ = os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+		defer func() { os.Stdout = stdout }()
+		_ = root.Execute()
+	}
+	store, err := telemetry.NewDefaultStore()
+	require.NoError(t, err)
+
+	// Off by default
+	execute("version")
+	data, err := store.Load()
+	require.NoError(t, err)
+	assert.Empty(t, data.Months)
+
+	t.Setenv("GITSAVVY_TELEMETRY_ENABLED", "true")
+	execute("version", "--json")
+	execute("telemetry", "report", "--since", "yesterday")
+	data, err = store.Load()
+	require.NoError(t, err)
+	report := telemetry.Summarize(data, "", "", "")
+	assert.ElementsMatch(t, []telemetry.Count{{Name: "version", Count: 1}, {Name: "telemetry report", Count: 1}}, report.Commands)
+	assert.Equal(t, []telemetry.Count{{Name: "telemetry report --since", Count: 1}, {Name: "version --json", Count: 1}}, report.Flags)
+	assert.Equal(t, []telemetry.Count{{Name: "telemetry report", Count: 1}}, report.Errors)
+}
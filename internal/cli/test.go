@@ -0,0 +1,171 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	runnerpkg "github.com/chazuruo/svf/internal/runner"
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+// TestOptions contains the options for the test command.
+type TestOptions struct {
+	ConfigPath string
+	Verbose    bool
+}
+
+// NewTestCommand creates the test command.
+func NewTestCommand() *cobra.Command {
+	opts := &TestOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "test [workflow-ref...]",
+		Short: "Run workflow tests against mocked step output",
+		Long: `Run a workflow's test cases without executing any commands.
+
+Test cases live in the workflow's tests section or in a tests.yaml file
+next to workflow.yaml. Each case gives placeholder values and mocks the
+output and exit code of steps; svf substitutes every command, feeds the
+mocked output to the step's captures and checks the expected commands,
+outcome and values. Steps without a mock succeed with no output.
+
+Without arguments, every workflow that has tests is tested. The command
+exits non-zero if any test fails, so it can validate runbooks in CI.`,
+		Example: `  svf test
+  svf test deploy-api
+  svf test deploy-api --verbose`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTest(opts, args)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ConfigPath, "config", "", "config file path")
+	cmd.Flags().BoolVarP(&opts.Verbose, "verbose", "v", false, "show the substituted commands of each test")
+
+	return cmd
+}
+
+func runTest(opts *TestOptions, refStrs []string) error {
+	ctx := context.Background()
+
+	// Load config
+	cfg, err := loadConfig(opts.ConfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Open repo
+	repo := gitrepo.New(cfg.Repo.Path)
+	if !repo.IsInitialized(ctx) {
+		return fmt.Errorf("repository not initialized. Run 'svf init' first")
+	}
+
+	// Create store
+	str, err := store.New(repo, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+
+	var refs []store.WorkflowRef
+	if len(refStrs) == 0 {
+		if refs, err = str.List(ctx, store.Filter{}); err != nil {
+			return fmt.Errorf("failed to list workflows: %w", err)
+		}
+	}
+	for _, refStr := range refStrs {
+		ref, err := resolveWorkflowRef(ctx, str, cfg, refStr)
+		if err != nil {
+			return err
+		}
+		refs = append(refs, ref)
+	}
+
+	passed, failed := 0, 0
+	for _, ref := range refs {
+		wf, err := str.Load(ctx, ref)
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", ref.Path, err)
+		}
+		tests, err := loadWorkflowTests(wf, ref.Path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", wf.Title, err)
+		}
+		if len(tests) == 0 {
+			if len(refStrs) > 0 {
+				fmt.Printf("%s has no tests\n", wf.Title)
+			}
+			continue
+		}
+
+		fmt.Println(wf.Title)
+		for _, tc := range tests {
+			result := runnerpkg.RunTest(wf, tc)
+			printTestResult(os.Stdout, result, opts.Verbose)
+			if result.Passed() {
+				passed++
+			} else {
+				failed++
+			}
+		}
+	}
+
+	if passed+failed == 0 {
+		fmt.Println("No tests found.")
+		return nil
+	}
+	fmt.Printf("\n%d passed, %d failed\n", passed, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d test(s) failed", failed)
+	}
+	return nil
+}
+
+// loadWorkflowTests returns a workflow's test cases: its tests section
+// followed by those in the tests.yaml next to its file.
+func loadWorkflowTests(wf *workflows.Workflow, path string) ([]workflows.TestCase, error) {
+	tests := append([]workflows.TestCase(nil), wf.Tests...)
+
+	data, err := os.ReadFile(filepath.Join(filepath.Dir(path), workflows.TestsFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return tests, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", workflows.TestsFile, err)
+	}
+	fileTests, err := workflows.ParseTests(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", workflows.TestsFile, err)
+	}
+	tests = append(tests, fileTests...)
+
+	if err := wf.ValidateTests(tests); err != nil {
+		return nil, err
+	}
+	return tests, nil
+}
+
+// printTestResult prints one test's outcome and, for failures, what went
+// wrong.
+func printTestResult(w io.Writer, result runnerpkg.TestResult, verbose bool) {
+	if result.Passed() {
+		fmt.Fprintf(w, "  ✓ %s\n", result.Name)
+	} else {
+		fmt.Fprintf(w, "  ✗ %s\n", result.Name)
+		for _, failure := range result.Failures {
+			fmt.Fprintf(w, "      %s\n", failure)
+		}
+	}
+	if verbose {
+		for i, cmd := range result.Commands {
+			fmt.Fprintf(w, "      %d. %s\n", i+1, cmd)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	runnerpkg "github.com/chazuruo/svf/internal/runner"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+func TestLoadWorkflowTests(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "workflow.yaml")
+	wf := &workflows.Workflow{
+		Title: "Deploy",
+		Steps: []workflows.Step{{Name: "deploy", Command: "deploy"}},
+		Tests: []workflows.TestCase{{Name: "inline"}},
+	}
+
+	tests, err := loadWorkflowTests(wf, path)
+	if err != nil || len(tests) != 1 {
+		t.Fatalf("loadWorkflowTests() = %v, %v; want the inline test", tests, err)
+	}
+
+	file := "tests:\n  - name: from file\n    steps:\n      deploy:\n        exit_code: 1\n    expect:\n      status: failure\n"
+	if err := os.WriteFile(filepath.Join(dir, workflows.TestsFile), []byte(file), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tests, err = loadWorkflowTests(wf, path)
+	if err != nil {
+		t.Fatalf("loadWorkflowTests() error = %v", err)
+	}
+	if len(tests) != 2 || tests[1].Name != "from file" || tests[1].Steps["deploy"].ExitCode != 1 {
+		t.Errorf("loadWorkflowTests() = %+v", tests)
+	}
+
+	file = "tests:\n  - name: inline\n"
+	if err := os.WriteFile(filepath.Join(dir, workflows.TestsFile), []byte(file), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadWorkflowTests(wf, path); err == nil || !strings.Contains(err.Error(), "duplicate test name") {
+		t.Errorf("loadWorkflowTests() error = %v, want duplicate test name", err)
+	}
+}
+
+func TestPrintTestResult(t *testing.T) {
+	var buf bytes.Buffer
+	printTestResult(&buf, runnerpkg.TestResult{Name: "ok", Commands: []string{"echo hi"}}, true)
+	printTestResult(&buf, runnerpkg.TestResult{Name: "bad", Failures: []string{"workflow succeeded, want failure"}}, false)
+
+	want := "  ✓ ok\n      1. echo hi\n  ✗ bad\n      workflow succeeded, want failure\n"
+	if got := buf.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
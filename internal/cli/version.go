@@ -35,7 +35,7 @@ func NewVersionCommand() *cobra.Command {
 		Short: "Show version information",
 		Long:  `Display version information including semantic version, git commit hash, and build timestamp.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runVersion(jsonOutput)
+			return runVersion(cmd.Root().Name(), jsonOutput)
 		},
 	}
 
@@ -44,7 +44,7 @@ func NewVersionCommand() *cobra.Command {
 	return cmd
 }
 
-func runVersion(jsonOutput bool) error {
+func runVersion(name string, jsonOutput bool) error {
 	info := versionInfo{
 		Version:   Version,
 		Commit:    Commit,
@@ -62,7 +62,7 @@ func runVersion(jsonOutput bool) error {
 	}
 
 	// Text output
-	fmt.Printf("svf version %s\n", info.Version)
+	fmt.Printf("%s version %s\n", name, info.Version)
 	if info.Commit != "unknown" && info.Commit != "" {
 		fmt.Printf("commit: %s\n", info.Commit)
 	}
@@ -4,11 +4,17 @@ package cli
 import (
 	"context"
 	"fmt"
+	"os"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/chazuruo/svf/internal/audit"
 	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/export"
 	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/runpath"
 	"github.com/chazuruo/svf/internal/workflows"
 	"github.com/chazuruo/svf/internal/workflows/store"
 )
@@ -18,6 +24,8 @@ type ViewOptions struct {
 	ConfigPath string
 	Raw        bool
 	Markdown   bool
+	AsScript   bool
+	Shell      string
 }
 
 // NewViewCommand creates the view command.
@@ -32,12 +40,17 @@ func NewViewCommand() *cobra.Command {
 The workflow reference can be:
 - A slug (e.g., "my-workflow")
 - A path (e.g., "workflows/platform/chaz/my-workflow")
-- An ID (e.g., "wf_abc123")
+- An ID or a unique ID prefix (e.g., "wf_01HV3K8Q")
+
+References matching several workflows (e.g. the same slug under two
+identities) are rejected with the list of candidates.
 
 Output formats:
 - Default: Formatted display
 - --raw: Print raw YAML
-- --md: Print generated Markdown`,
+- --md: Print generated Markdown
+- --as-script: Print a standalone shell script (bash or zsh via --shell)
+  that runs the workflow without svf installed`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runView(opts, args[0])
@@ -47,6 +60,8 @@ Output formats:
 	cmd.Flags().StringVar(&opts.ConfigPath, "config", "", "config file path")
 	cmd.Flags().BoolVar(&opts.Raw, "raw", false, "print raw YAML")
 	cmd.Flags().BoolVar(&opts.Markdown, "md", false, "print Markdown")
+	cmd.Flags().BoolVar(&opts.AsScript, "as-script", false, "print a standalone shell script")
+	cmd.Flags().StringVar(&opts.Shell, "shell", "bash", "script shell for --as-script (bash, zsh)")
 
 	return cmd
 }
@@ -73,7 +88,7 @@ func runView(opts *ViewOptions, workflowRef string) error {
 	}
 
 	// Resolve workflow reference
-	ref, err := resolveWorkflowRef(ctx, str, workflowRef)
+	ref, err := resolveWorkflowRef(ctx, str, cfg, workflowRef)
 	if err != nil {
 		return err
 	}
@@ -83,6 +98,12 @@ func runView(opts *ViewOptions, workflowRef string) error {
 	if err != nil {
 		return fmt.Errorf("failed to load workflow: %w", err)
 	}
+	if warning := runpath.ReviewWarning(wf); warning != "" {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+	if err := runpath.RecordAccess(cfg, wf, audit.ActionView); err != nil {
+		return err
+	}
 
 	// Output
 	if opts.Raw {
@@ -91,27 +112,22 @@ func runView(opts *ViewOptions, workflowRef string) error {
 	if opts.Markdown {
 		return printWorkflowMarkdown(wf)
 	}
+	if opts.AsScript {
+		script, err := export.Script(wf, export.ScriptShell(opts.Shell))
+		if err != nil {
+			return err
+		}
+		fmt.Print(script)
+		return nil
+	}
 
 	return printWorkflowFormatted(wf)
 }
 
-// resolveWorkflowRef resolves a workflow reference string to a WorkflowRef.
-func resolveWorkflowRef(ctx context.Context, str store.Store, refStr string) (store.WorkflowRef, error) {
-	// Try as ID first
-	refs, err := str.List(ctx, store.Filter{})
-	if err != nil {
-		return store.WorkflowRef{}, err
-	}
-
-	// Look for exact match on ID or slug
-	for _, ref := range refs {
-		if ref.ID == refStr || ref.Slug == refStr {
-			return ref, nil
-		}
-	}
-
-	// Not found
-	return store.WorkflowRef{}, fmt.Errorf("workflow not found: %s", refStr)
+// resolveWorkflowRef resolves a workflow reference string (ID, ID prefix,
+// slug, or path) to a WorkflowRef.
+func resolveWorkflowRef(ctx context.Context, str store.Store, cfg *config.Config, refStr string) (store.WorkflowRef, error) {
+	return store.Resolve(ctx, str, cfg.Repo.Path, cfg.Workflows.Root, refStr)
 }
 
 // printWorkflowRaw prints the raw YAML of a workflow.
@@ -168,7 +184,11 @@ func printWorkflowMarkdown(wf *workflows.Workflow) error {
 	sb.WriteString("## Steps\n\n")
 	for i, step := range wf.Steps {
 		sb.WriteString(fmt.Sprintf("%d. **%s**\n", i+1, step.Name))
-		sb.WriteString(fmt.Sprintf("   ```\n   %s\n   ```\n\n", step.Command))
+		command := step.Command
+		if step.Script != "" {
+			command = step.Script
+		}
+		sb.WriteString(fmt.Sprintf("   ```\n   %s\n   ```\n\n", command))
 	}
 
 	fmt.Print(sb.String())
@@ -184,10 +204,112 @@ func printWorkflowFormatted(wf *workflows.Workflow) error {
 	if len(wf.Tags) > 0 {
 		fmt.Printf("Tags: %s\n", strings.Join(wf.Tags, ", "))
 	}
+	if len(wf.Aliases) > 0 {
+		fmt.Printf("Aliases: %s\n", strings.Join(wf.Aliases, ", "))
+	}
+	if names := wf.PresetNames(); len(names) > 0 {
+		fmt.Printf("Presets: %s\n", strings.Join(names, ", "))
+	}
+	if wf.Next != "" {
+		fmt.Printf("Next: %s\n", wf.Next)
+	}
+	if wf.KubeContext != "" {
+		fmt.Printf("Kube context: %s\n", wf.KubeContext)
+	}
+	if wf.KubeNamespace != "" {
+		fmt.Printf("Kube namespace: %s\n", wf.KubeNamespace)
+	}
+	if wf.AWSProfile != "" {
+		fmt.Printf("AWS profile: %s\n", wf.AWSProfile)
+	}
+	if wf.AWSAccount != "" {
+		fmt.Printf("AWS account: %s\n", wf.AWSAccount)
+	}
+	if wf.GCPProject != "" {
+		fmt.Printf("GCP project: %s\n", wf.GCPProject)
+	}
+	if len(wf.Owners) > 0 {
+		fmt.Printf("Owners: %s\n", strings.Join(wf.Owners, ", "))
+	}
+	if len(wf.Requires) > 0 {
+		names := make([]string, len(wf.Requires))
+		for i, req := range wf.Requires {
+			names[i] = req.Command
+		}
+		fmt.Printf("Requires: %s\n", strings.Join(names, ", "))
+	}
+	if wf.Exclusive {
+		fmt.Println("Exclusive: one run at a time")
+	}
+	if wf.Metadata != nil {
+		for _, line := range formatEnvironment(wf.Metadata) {
+			fmt.Println(line)
+		}
+	}
+	switch wf.ReviewStatus() {
+	case workflows.Reviewed:
+		fmt.Printf("Reviewed: by %s on %s\n", wf.ReviewedBy, wf.ReviewedAt.Format("2006-01-02"))
+	case workflows.StaleReview:
+		fmt.Printf("Reviewed: by %s on %s (stale: changed since)\n", wf.ReviewedBy, wf.ReviewedAt.Format("2006-01-02"))
+	}
 	fmt.Printf("\nSteps:\n")
 	for i, step := range wf.Steps {
 		fmt.Printf("  %d. %s\n", i+1, step.Name)
-		fmt.Printf("     %s\n", step.Command)
+		if step.Script != "" {
+			fmt.Printf("     Script: %s\n", step.Script)
+		} else {
+			fmt.Printf("     %s\n", step.Command)
+		}
+		if len(step.Files) > 0 {
+			names := make([]string, 0, len(step.Files))
+			for name, p := range step.Files {
+				names = append(names, fmt.Sprintf("<%s> = %s", name, p))
+			}
+			sort.Strings(names)
+			fmt.Printf("     Files: %s\n", strings.Join(names, ", "))
+		}
+		if len(step.Capture) > 0 {
+			names := make([]string, 0, len(step.Capture))
+			for name := range step.Capture {
+				names = append(names, "<"+name+">")
+			}
+			sort.Strings(names)
+			fmt.Printf("     Captures: %s\n", strings.Join(names, ", "))
+		}
+		if len(step.Platforms) > 0 {
+			fmt.Printf("     Platforms: %s\n", strings.Join(step.Platforms, ", "))
+		}
+		for _, link := range step.Links {
+			if link.Title != "" {
+				fmt.Printf("     Link: %s (%s)\n", link.Title, link.URL)
+			} else {
+				fmt.Printf("     Link: %s\n", link.URL)
+			}
+		}
+		if p := step.Provenance; p != nil {
+			fmt.Printf("     Recorded: %s\n", provenanceSummary(p))
+		}
 	}
 	return nil
 }
+
+// provenanceSummary describes where a recorded step ran on one line.
+func provenanceSummary(p *workflows.Provenance) string {
+	var parts []string
+	if !p.RanAt.IsZero() {
+		parts = append(parts, p.RanAt.Local().Format("2006-01-02 15:04"))
+	}
+	if p.Host != "" {
+		parts = append(parts, "on "+p.Host)
+	}
+	if p.Session != "" {
+		parts = append(parts, "in tmux session "+p.Session)
+	}
+	if p.Duration > 0 {
+		parts = append(parts, fmt.Sprintf("took %s", time.Duration(p.Duration)*time.Second))
+	}
+	if p.ExitStatus != nil {
+		parts = append(parts, fmt.Sprintf("exit %d", *p.ExitStatus))
+	}
+	return strings.Join(parts, ", ")
+}
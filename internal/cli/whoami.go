@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"github.com/chazuruo/svf/internal/app"
+	svferrors "github.com/chazuruo/svf/internal/errors"
 	"github.com/spf13/cobra"
 )
 
@@ -46,11 +47,9 @@ func runWhoami(opts *WhoamiOptions) error {
 			return fmt.Errorf("config error: %w", err)
 		}
 		// No config file found - provide helpful error message
-		fmt.Fprintf(os.Stderr, "Error: Config file not found\n")
-		fmt.Fprintf(os.Stderr, "Expected location: ~/.config/gitsavvy/config.toml\n")
-		fmt.Fprintf(os.Stderr, "Run 'gitsavvy init' to create a default config, or create the file manually.\n")
-		os.Exit(2)
-		return err // unreachable but needed for type safety
+		return svferrors.WithHint(
+			fmt.Errorf("config file %w (expected at ~/.config/gitsavvy/config.toml)", svferrors.ErrNotFound),
+			"Run 'gitsavvy init' to create a default config, or create the file manually.")
 	}
 
 	if opts.JSON {
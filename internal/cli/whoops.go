@@ -0,0 +1,215 @@
+// Package cli provides Cobra command definitions for svf.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/history"
+	"github.com/chazuruo/svf/internal/placeholders"
+	"github.com/chazuruo/svf/internal/redact"
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+// defaultWhoopsCount is how many commands svf whoops captures by default.
+const defaultWhoopsCount = 10
+
+// redactedPlaceholder is the placeholder secrets masked by svf whoops
+// become.
+const redactedPlaceholder = "REDACTED"
+
+// WhoopsOptions contains the options for the whoops command.
+type WhoopsOptions struct {
+	Shell  string
+	Title  string
+	Tags   string
+	Commit bool
+}
+
+// NewWhoopsCommand creates the whoops command.
+func NewWhoopsCommand() *cobra.Command {
+	opts := &WhoopsOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "whoops [N]",
+		Short: "Save the last N shell commands as a draft workflow",
+		Long: `Capture the commands you just ran before they're forgotten.
+
+Takes the last N commands from your shell history (default 10), leaving
+out svf's own, and saves them as a draft workflow under the drafts folder
+without asking anything:
+- Values repeated across commands, like hosts and namespaces, become
+  placeholders with the value as the default
+- Likely secrets are masked and become a secret <REDACTED> placeholder
+
+Clean the draft up later, when the incident is over. Bash writes history
+when the shell exits; run 'history -a' first to include this session's
+commands.
+
+Examples:
+  svf whoops                         # Last 10 commands
+  svf whoops 25 --title "Redis OOM on cache-3"
+  svf whoops --commit                # Commit the draft too`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			n := defaultWhoopsCount
+			if len(args) > 0 {
+				var err error
+				if n, err = strconv.Atoi(args[0]); err != nil || n < 1 {
+					return fmt.Errorf("N must be a positive number, got %q", args[0])
+				}
+			}
+			return runWhoops(opts, n)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Shell, "shell", "", "shell whose history to read (bash/zsh, default: auto-detect)")
+	cmd.Flags().StringVar(&opts.Title, "title", "", "workflow title (default: Whoops and the time)")
+	cmd.Flags().StringVar(&opts.Tags, "tags", "", "workflow tags (comma-separated)")
+	cmd.Flags().BoolVar(&opts.Commit, "commit", false, "commit the draft")
+
+	return cmd
+}
+
+func runWhoops(opts *WhoopsOptions, n int) error {
+	ctx := context.Background()
+
+	shell := opts.Shell
+	if shell == "" {
+		shell = history.DetectShell()
+	}
+	all, err := history.NewParser(shell, math.MaxInt).Parse()
+	if err != nil {
+		return fmt.Errorf("failed to parse history: %w", err)
+	}
+	commands := lastCommands(all, n)
+	if len(commands) == 0 {
+		return fmt.Errorf("no commands found in %s history", shell)
+	}
+
+	// Load config
+	cfg, err := config.LoadWithDefaults()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Open repo
+	repo := gitrepo.New(cfg.Repo.Path)
+	if !repo.IsInitialized(ctx) {
+		return fmt.Errorf("repository not initialized. Run 'svf init' first")
+	}
+
+	// Create store
+	str, err := store.New(repo, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+
+	title := opts.Title
+	if title == "" {
+		title = "Whoops " + time.Now().Format("2006-01-02 15:04")
+	}
+	wf, suggestions, redacted := whoopsWorkflow(commands, title)
+	wf.Tags = parseTags(opts.Tags)
+
+	ref, err := saveDraft(ctx, repo, str, cfg, wf, opts.Commit)
+	if err != nil {
+		return fmt.Errorf("failed to save draft: %w", err)
+	}
+
+	rel, err := filepath.Rel(cfg.Repo.Path, ref.Path)
+	if err != nil {
+		rel = ref.Path
+	}
+	fmt.Printf("✓ Saved the last %d command(s) to %s\n", len(wf.Steps), rel)
+	for _, s := range suggestions {
+		fmt.Printf("  <%s> = %s (%d steps)\n", s.Name, s.Value, len(s.Steps))
+	}
+	if redacted > 0 {
+		fmt.Printf("  Masked likely secrets in %d step(s) as <%s>\n", redacted, redactedPlaceholder)
+	}
+	return nil
+}
+
+// lastCommands returns the last n commands of a history, oldest first,
+// leaving out svf's own.
+func lastCommands(commands []history.Command, n int) []history.Command {
+	var kept []history.Command
+	for i := len(commands) - 1; i >= 0 && len(kept) < n; i-- {
+		if fields := strings.Fields(commands[i].Command); len(fields) > 0 && fields[0] == "svf" {
+			continue
+		}
+		kept = append(kept, commands[i])
+	}
+	for i, j := 0, len(kept)-1; i < j; i, j = i+1, j-1 {
+		kept[i], kept[j] = kept[j], kept[i]
+	}
+	return kept
+}
+
+// whoopsWorkflow builds a workflow from captured commands without asking
+// anything: likely secrets are masked as a secret placeholder and every
+// placeholder suggestion is applied. It returns the applied suggestions
+// and how many steps had secrets masked.
+func whoopsWorkflow(commands []history.Command, title string) (*workflows.Workflow, []placeholders.Suggestion, int) {
+	wf := &workflows.Workflow{
+		SchemaVersion: workflows.SchemaVersion,
+		Title:         title,
+		Steps:         convertHistoryCommandsToSteps(commands),
+	}
+
+	redacted := 0
+	for i, step := range wf.Steps {
+		if len(redact.Scan(step.Command)) == 0 {
+			continue
+		}
+		wf.Steps[i].Command = redact.String(step.Command, redact.Basic)
+		redacted++
+	}
+	if redacted > 0 {
+		wf.Placeholders = map[string]workflows.Placeholder{
+			redactedPlaceholder: {Prompt: "Secret masked when the commands were captured", Secret: true},
+		}
+	}
+
+	suggestions := placeholders.Suggest(wf)
+	for _, s := range suggestions {
+		placeholders.ApplySuggestion(wf, s)
+	}
+	return wf, suggestions, redacted
+}
+
+// saveDraft saves wf under the drafts folder, in a directory of the
+// identity's named after its title, and commits it if commit is set.
+func saveDraft(ctx context.Context, repo gitrepo.Repo, str store.Store, cfg *config.Config, wf *workflows.Workflow, commit bool) (store.WorkflowRef, error) {
+	identity := cfg.Identity.Path
+	if identity == "" {
+		identity = "default"
+	}
+	dir := filepath.Join(repo.Path(), cfg.Workflows.DraftRoot, identity)
+
+	var existing []string
+	if entries, err := os.ReadDir(dir); err == nil {
+		for _, e := range entries {
+			existing = append(existing, e.Name())
+		}
+	}
+	format, err := workflows.ParseFormat(cfg.Workflows.Format)
+	if err != nil {
+		return store.WorkflowRef{}, err
+	}
+	path := filepath.Join(dir, store.GenerateUniqueSlug(wf.Title, existing), workflows.FileName(format))
+
+	return saveAndPublish(ctx, repo, str, cfg, wf, store.SaveOptions{Path: path, Commit: commit})
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/history"
+	"github.com/chazuruo/svf/internal/testutil"
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+func TestLastCommands(t *testing.T) {
+	var commands []history.Command
+	for _, c := range []string{"make build", "kubectl get pods", "svf run deploy", "kubectl logs api", "svf whoops"} {
+		commands = append(commands, history.Command{Command: c})
+	}
+
+	got := lastCommands(commands, 2)
+	if len(got) != 2 || got[0].Command != "kubectl get pods" || got[1].Command != "kubectl logs api" {
+		t.Errorf("lastCommands(2) = %+v, want the last two, oldest first, without svf", got)
+	}
+	if got := lastCommands(commands, 10); len(got) != 3 {
+		t.Errorf("lastCommands(10) = %+v, want all three non-svf commands", got)
+	}
+}
+
+// TestWhoops verifies that captured commands get placeholders and masked
+// secrets without prompting, and are saved under the drafts folder.
+func TestWhoops(t *testing.T) {
+	ctx := context.Background()
+	commands := []history.Command{
+		{Command: "kubectl -n payments get pods"},
+		{Command: "mysql --password=hunter2 -h db-1"},
+		{Command: "kubectl -n payments rollout restart deploy/api"},
+	}
+
+	wf, suggestions, redacted := whoopsWorkflow(commands, "Whoops 2025-03-09 12:00")
+	if redacted != 1 || strings.Contains(wf.Steps[1].Command, "hunter2") {
+		t.Errorf("redacted = %d, step = %q; want the password masked", redacted, wf.Steps[1].Command)
+	}
+	if !wf.Placeholders[redactedPlaceholder].Secret {
+		t.Errorf("placeholders = %+v, want a secret %s", wf.Placeholders, redactedPlaceholder)
+	}
+	if len(suggestions) != 1 || suggestions[0].Value != "payments" {
+		t.Fatalf("suggestions = %+v, want the namespace", suggestions)
+	}
+	if want := "kubectl -n <" + suggestions[0].Name + "> get pods"; wf.Steps[0].Command != want {
+		t.Errorf("step = %q, want %q", wf.Steps[0].Command, want)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Repo.Path = t.TempDir()
+	cfg.Identity.Path = "team/alice"
+	cfg.Identity.Mode = "direct"
+	cfg.Git.ScanSecrets = true
+	repo := testutil.NewFakeRepo(cfg.Repo.Path)
+	str, err := store.New(repo, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ref, err := saveDraft(ctx, repo, str, cfg, wf, false)
+	if err != nil {
+		t.Fatalf("saveDraft() error = %v", err)
+	}
+	if want := filepath.Join(cfg.Repo.Path, "drafts", "team/alice", "whoops-2025-03-09-12-00", "workflow.yaml"); ref.Path != want {
+		t.Errorf("Path = %s, want %s", ref.Path, want)
+	}
+	if len(repo.Commits()) != 0 {
+		t.Errorf("saveDraft() made %d commit(s), want none", len(repo.Commits()))
+	}
+
+	// A second capture with the same title gets its own directory
+	again, err := saveDraft(ctx, repo, str, cfg, &workflows.Workflow{SchemaVersion: 1, Title: wf.Title, Steps: wf.Steps}, false)
+	if err != nil {
+		t.Fatalf("saveDraft() error = %v", err)
+	}
+	if filepath.Dir(again.Path) == filepath.Dir(ref.Path) {
+		t.Errorf("second draft saved to %s too", again.Path)
+	}
+}
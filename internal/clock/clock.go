@@ -0,0 +1,22 @@
+// Package clock abstracts the current time so that timestamps written by
+// the store and index can be fixed in tests.
+package clock
+
+import "time"
+
+// Clock tells the current time.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+}
+
+// Real is the system clock.
+var Real Clock = realClock{}
+
+// realClock reads the system clock.
+type realClock struct{}
+
+// Now returns time.Now().
+func (realClock) Now() time.Time {
+	return time.Now()
+}
@@ -16,6 +16,13 @@ import (
 // Config is the top-level configuration struct for git-savvy.
 // It contains all configuration sections as embedded structs.
 type Config struct {
+	// Version is the config file schema version; see CurrentVersion.
+	Version int `toml:"version"`
+
+	// ReadOnly makes svf a consumer of shared workflows: commands that
+	// write to the repo are hidden and refused, and sync fast-forwards only.
+	ReadOnly bool `toml:"readonly"`
+
 	Repo        RepoConfig        `toml:"repo"`
 	Identity    IdentityConfig    `toml:"identity"`
 	Git         GitConfig         `toml:"git"`
@@ -25,6 +32,7 @@ type Config struct {
 	TUI         TUIConfig         `toml:"tui"`
 	Editor      EditorConfig      `toml:"editor"`
 	AI          AIConfig          `toml:"ai"`
+	Telemetry   TelemetryConfig   `toml:"telemetry"`
 }
 
 // RepoConfig contains repository-related settings.
@@ -43,6 +51,9 @@ type RepoConfig struct {
 	SyncStrategy string `toml:"sync_strategy"`
 
 	// AutoReindex controls whether to rebuild the index after sync.
+	//
+	// Deprecated: sync honors workflows.index.auto_rebuild; the config
+	// migration moves auto_reindex there.
 	AutoReindex bool `toml:"auto_reindex"`
 }
 
@@ -80,6 +91,15 @@ type GitConfig struct {
 	// FeatureBranchTemplate is the template for feature branch names.
 	// Supported placeholders: {identity}, {date}, {slug}.
 	FeatureBranchTemplate string `toml:"feature_branch_template"`
+
+	// CommitTemplate is the template for the subject of commits made on
+	// save. Supported placeholders: {scope}, {action}, {slug}, {title}.
+	// The body lists the steps that changed.
+	CommitTemplate string `toml:"commit_template"`
+
+	// ScanSecrets refuses to save workflows that appear to contain raw
+	// secrets, and flags incoming workflows that do on sync.
+	ScanSecrets bool `toml:"scan_secrets"`
 }
 
 // WorkflowsConfig contains workflow-related settings.
@@ -93,12 +113,25 @@ type WorkflowsConfig struct {
 	// DraftRoot is the repo-relative path to draft workflows.
 	DraftRoot string `toml:"draft_root"`
 
+	// TemplateRoot is the repo-relative path to workflow templates:
+	// workflows meant to be copied rather than run as they are.
+	TemplateRoot string `toml:"template_root"`
+
 	// IndexPath is the repo-relative path to the index file.
 	IndexPath string `toml:"index_path"`
 
 	// SchemaVersion is the workflow schema version.
 	SchemaVersion int `toml:"schema_version"`
 
+	// Format is the file format of new workflows: yaml, toml or json.
+	// Existing workflows keep the format they were written in.
+	Format string `toml:"format"`
+
+	// ReadmeTemplate is the repo-relative path to a Go text/template used
+	// to generate each workflow's README.md. If unset,
+	// .svf/templates/README.md.tmpl is used when present.
+	ReadmeTemplate string `toml:"readme_template"`
+
 	// Index contains search index settings.
 	Index IndexConfig `toml:"index"`
 }
@@ -107,6 +140,11 @@ type WorkflowsConfig struct {
 type IndexConfig struct {
 	// AutoRebuild controls whether to automatically rebuild the index after sync.
 	AutoRebuild bool `toml:"auto_rebuild"`
+
+	// Synonyms map words to the word searches should treat them as, e.g.
+	// "k8s" = "kubernetes". They add to and override the built-in ones;
+	// mapping a word to itself disables a built-in synonym.
+	Synonyms map[string]string `toml:"synonyms"`
 }
 
 // RunnerConfig contains workflow runner settings.
@@ -126,6 +164,39 @@ type RunnerConfig struct {
 
 	// DangerousCommandWarnings enables warnings for potentially dangerous commands.
 	DangerousCommandWarnings bool `toml:"dangerous_command_warnings"`
+
+	// StepTimeout is the maximum number of seconds a step may run before
+	// it and its child processes are terminated. 0 means no limit.
+	StepTimeout int `toml:"step_timeout"`
+
+	// OverBudgetHook is a shell command run when a step takes longer than
+	// its max_duration, with the workflow, step and budget in SVF_*
+	// environment variables. Empty runs nothing; the runner still warns.
+	OverBudgetHook string `toml:"over_budget_hook"`
+
+	// RedactLogs controls how step output is redacted before it is saved
+	// to run logs. Output shown live in the terminal is never redacted.
+	// Valid values: "none", "basic", "strict".
+	RedactLogs string `toml:"redact_logs"`
+
+	// UsageStats records anonymized run counts per workflow under
+	// .svf/stats in the repo so teams can find unused runbooks. Opt-in.
+	UsageStats bool `toml:"usage_stats"`
+
+	// RequireReview refuses to run shared workflows that haven't been
+	// approved with 'svf review approve' or changed since their approval.
+	RequireReview bool `toml:"require_review"`
+
+	// KubeGuard controls what happens when a workflow's kube_context or
+	// kube_namespace doesn't match the active kubectl context.
+	// Valid values: "prompt" (ask, refuse when non-interactive), "block".
+	KubeGuard string `toml:"kube_guard"`
+
+	// RunLock controls where runs of exclusive workflows are locked.
+	// Valid values: "local" (a lock file in the repo, for operators on
+	// one host), "remote" (a lock ref pushed to repo.remote, shared by
+	// everyone who pushes there).
+	RunLock string `toml:"run_lock"`
 }
 
 // PlaceholdersConfig contains placeholder/parameter settings.
@@ -147,11 +218,15 @@ type TUIConfig struct {
 	// Enabled controls whether to use the TUI (when false, falls back to CLI).
 	Enabled bool `toml:"enabled"`
 
-	// Theme is the TUI theme name.
+	// Theme is the TUI theme name (default, light, high-contrast, no-color).
 	Theme string `toml:"theme"`
 
 	// ShowHelp controls whether to show the help panel by default.
 	ShowHelp bool `toml:"show_help"`
+
+	// Locale is the language of TUI and CLI messages (auto, en, ja).
+	// "auto" follows LC_ALL, LC_MESSAGES or LANG.
+	Locale string `toml:"locale"`
 }
 
 // EditorConfig contains editor settings.
@@ -183,6 +258,39 @@ type AIConfig struct {
 
 	// ConfirmSend prompts for confirmation before sending data to AI.
 	ConfirmSend bool `toml:"confirm_send"`
+
+	// MaxPromptTokens caps the estimated tokens sent per request (0 for no limit).
+	MaxPromptTokens int `toml:"max_prompt_tokens"`
+
+	// Truncation selects how input over the budget is shortened.
+	// Valid values: "head", "tail", "summarize".
+	Truncation string `toml:"truncation"`
+
+	// MaxAttempts is the number of requests sent to a provider that is
+	// rate limited or failing before falling back (default: 3).
+	MaxAttempts int `toml:"max_attempts"`
+
+	// Fallbacks are providers to try, in order, when the main one fails.
+	Fallbacks []AIFallbackConfig `toml:"fallbacks"`
+}
+
+// AIFallbackConfig is a fallback AI provider.
+type AIFallbackConfig struct {
+	Provider  string `toml:"provider"`
+	BaseURL   string `toml:"base_url"`
+	Model     string `toml:"model"`
+	APIKeyEnv string `toml:"api_key_env"`
+}
+
+// TelemetryConfig contains the settings for anonymous usage telemetry,
+// which is recorded on this machine only and never uploaded.
+type TelemetryConfig struct {
+	// Enabled records feature usage (must be explicitly enabled).
+	Enabled bool `toml:"enabled"`
+
+	// Record lists what is recorded.
+	// Valid values: "commands", "flags", "errors".
+	Record []string `toml:"record"`
 }
 
 // DefaultConfig returns a Config with all default values set.
@@ -203,6 +311,7 @@ func DefaultConfig() *Config {
 	}
 
 	return &Config{
+		Version: CurrentVersion,
 		Repo: RepoConfig{
 			Path:         filepath.Join(homeDir, ".local", "share", "svf", "repo"),
 			Remote:       "origin",
@@ -222,13 +331,17 @@ func DefaultConfig() *Config {
 			PushOnSave:           false,
 			PRBaseBranch:         "main",
 			FeatureBranchTemplate: "svf/{identity}/{date}/{slug}",
+			CommitTemplate:       "workflow({scope}): {action} {slug}",
+			ScanSecrets:          true,
 		},
 		Workflows: WorkflowsConfig{
 			Root:         "workflows",
 			SharedRoot:   "shared",
 			DraftRoot:    "drafts",
+			TemplateRoot: "templates",
 			IndexPath:    ".svf/index.json",
 			SchemaVersion: 1,
+			Format:       "yaml",
 			Index: IndexConfig{
 				AutoRebuild: true,
 			},
@@ -239,6 +352,9 @@ func DefaultConfig() *Config {
 			StreamOutput:             true,
 			MaxOutputLines:           5000,
 			DangerousCommandWarnings: true,
+			RedactLogs:               "basic",
+			KubeGuard:                "prompt",
+			RunLock:                  "local",
 		},
 		Placeholders: PlaceholdersConfig{
 			PromptStyle:      "form",
@@ -249,6 +365,7 @@ func DefaultConfig() *Config {
 			Enabled:  true,
 			Theme:    "default",
 			ShowHelp: true,
+			Locale:   "auto",
 		},
 		Editor: EditorConfig{
 			Command: "",
@@ -261,6 +378,13 @@ func DefaultConfig() *Config {
 			APIKeyEnv:  "",
 			Redact:     "basic",
 			ConfirmSend: true,
+			MaxPromptTokens: 8000,
+			Truncation: "summarize",
+			MaxAttempts: 3,
+		},
+		Telemetry: TelemetryConfig{
+			Enabled: false,
+			Record:  []string{"commands", "flags", "errors"},
 		},
 	}
 }
@@ -330,12 +454,23 @@ func (c *Config) Validate() error {
 	if c.Workflows.DraftRoot == "" {
 		return fmt.Errorf("workflows.draft_root cannot be empty")
 	}
+	if c.Workflows.TemplateRoot == "" {
+		return fmt.Errorf("workflows.template_root cannot be empty")
+	}
 	if c.Workflows.IndexPath == "" {
 		return fmt.Errorf("workflows.index_path cannot be empty")
 	}
 	if c.Workflows.SchemaVersion < 1 {
 		return fmt.Errorf("workflows.schema_version must be >= 1; got %d", c.Workflows.SchemaVersion)
 	}
+	validFormats := map[string]bool{
+		"yaml": true,
+		"toml": true,
+		"json": true,
+	}
+	if !validFormats[c.Workflows.Format] {
+		return fmt.Errorf("workflows.format must be one of: yaml, toml, json; got %q", c.Workflows.Format)
+	}
 
 	// Validate Runner section
 	validShells := map[string]bool{
@@ -350,6 +485,23 @@ func (c *Config) Validate() error {
 	if c.Runner.MaxOutputLines < 0 {
 		return fmt.Errorf("runner.max_output_lines must be >= 0; got %d", c.Runner.MaxOutputLines)
 	}
+	if c.Runner.StepTimeout < 0 {
+		return fmt.Errorf("runner.step_timeout must be >= 0; got %d", c.Runner.StepTimeout)
+	}
+	validLogRedactLevels := map[string]bool{
+		"none":   true,
+		"basic":  true,
+		"strict": true,
+	}
+	if !validLogRedactLevels[c.Runner.RedactLogs] {
+		return fmt.Errorf("runner.redact_logs must be one of: none, basic, strict; got %q", c.Runner.RedactLogs)
+	}
+	if c.Runner.KubeGuard != "prompt" && c.Runner.KubeGuard != "block" {
+		return fmt.Errorf("runner.kube_guard must be one of: prompt, block; got %q", c.Runner.KubeGuard)
+	}
+	if c.Runner.RunLock != "local" && c.Runner.RunLock != "remote" {
+		return fmt.Errorf("runner.run_lock must be one of: local, remote; got %q", c.Runner.RunLock)
+	}
 
 	// Validate Placeholders section
 	validPromptStyles := map[string]bool{
@@ -375,6 +527,9 @@ func (c *Config) Validate() error {
 	if c.TUI.Theme == "" {
 		return fmt.Errorf("tui.theme cannot be empty")
 	}
+	if c.TUI.Locale == "" {
+		return fmt.Errorf("tui.locale cannot be empty")
+	}
 
 	// Validate AI section (only if enabled)
 	if c.AI.Enabled {
@@ -396,6 +551,38 @@ func (c *Config) Validate() error {
 	if !validRedactLevels[c.AI.Redact] {
 		return fmt.Errorf("ai.redact must be one of: none, basic, strict; got %q", c.AI.Redact)
 	}
+	if c.AI.MaxPromptTokens < 0 {
+		return fmt.Errorf("ai.max_prompt_tokens cannot be negative")
+	}
+	if c.AI.MaxAttempts < 0 {
+		return fmt.Errorf("ai.max_attempts cannot be negative")
+	}
+	for i, fb := range c.AI.Fallbacks {
+		if fb.Provider == "" {
+			return fmt.Errorf("ai.fallbacks[%d].provider cannot be empty", i)
+		}
+	}
+	validTruncations := map[string]bool{
+		"":          true,
+		"head":      true,
+		"tail":      true,
+		"summarize": true,
+	}
+	if !validTruncations[c.AI.Truncation] {
+		return fmt.Errorf("ai.truncation must be one of: head, tail, summarize; got %q", c.AI.Truncation)
+	}
+
+	// Validate Telemetry section
+	validTelemetry := map[string]bool{
+		"commands": true,
+		"flags":    true,
+		"errors":   true,
+	}
+	for _, r := range c.Telemetry.Record {
+		if !validTelemetry[r] {
+			return fmt.Errorf("telemetry.record must contain only: commands, flags, errors; got %q", r)
+		}
+	}
 
 	return nil
 }
@@ -35,11 +35,14 @@ func TestDefaultConfig(t *testing.T) {
 		{"git.push_on_save", cfg.Git.PushOnSave, false, false},
 		{"git.pr_base_branch", cfg.Git.PRBaseBranch, "main", false},
 		{"git.feature_branch_template", cfg.Git.FeatureBranchTemplate, "svf/{identity}/{date}/{slug}", false},
+		{"git.commit_template", cfg.Git.CommitTemplate, "workflow({scope}): {action} {slug}", false},
+		{"git.scan_secrets", cfg.Git.ScanSecrets, true, false},
 
 		// Workflows section defaults
 		{"workflows.root", cfg.Workflows.Root, "workflows", false},
 		{"workflows.shared_root", cfg.Workflows.SharedRoot, "shared", false},
 		{"workflows.draft_root", cfg.Workflows.DraftRoot, "drafts", false},
+		{"workflows.template_root", cfg.Workflows.TemplateRoot, "templates", false},
 		{"workflows.index_path", cfg.Workflows.IndexPath, ".svf/index.json", false},
 		{"workflows.schema_version", cfg.Workflows.SchemaVersion, 1, false},
 
@@ -49,6 +52,11 @@ func TestDefaultConfig(t *testing.T) {
 		{"runner.stream_output", cfg.Runner.StreamOutput, true, false},
 		{"runner.max_output_lines", cfg.Runner.MaxOutputLines, 5000, false},
 		{"runner.dangerous_command_warnings", cfg.Runner.DangerousCommandWarnings, true, false},
+		{"runner.redact_logs", cfg.Runner.RedactLogs, "basic", false},
+		{"runner.usage_stats", cfg.Runner.UsageStats, false, false},
+		{"runner.require_review", cfg.Runner.RequireReview, false, false},
+		{"runner.kube_guard", cfg.Runner.KubeGuard, "prompt", false},
+		{"runner.run_lock", cfg.Runner.RunLock, "local", false},
 
 		// Placeholders section defaults
 		{"placeholders.prompt_style", cfg.Placeholders.PromptStyle, "form", false},
@@ -59,6 +67,7 @@ func TestDefaultConfig(t *testing.T) {
 		{"tui.enabled", cfg.TUI.Enabled, true, false},
 		{"tui.theme", cfg.TUI.Theme, "default", false},
 		{"tui.show_help", cfg.TUI.ShowHelp, true, false},
+		{"tui.locale", cfg.TUI.Locale, "auto", false},
 
 		// Editor section defaults
 		{"editor.command", cfg.Editor.Command, "", false}, // Empty - uses $EDITOR
@@ -180,6 +189,10 @@ func TestValidate_EmptyRequiredFields(t *testing.T) {
 			name: "empty workflows.draft_root",
 			mutate: func(c *Config) { c.Workflows.DraftRoot = "" },
 			wantErr: "workflows.draft_root cannot be empty",
+		},		{
+			name: "empty workflows.template_root",
+			mutate: func(c *Config) { c.Workflows.TemplateRoot = "" },
+			wantErr: "workflows.template_root cannot be empty",
 		},
 		{
 			name: "empty workflows.index_path",
@@ -201,6 +214,26 @@ func TestValidate_EmptyRequiredFields(t *testing.T) {
 			mutate: func(c *Config) { c.Runner.MaxOutputLines = -1 },
 			wantErr: "runner.max_output_lines must be >= 0",
 		},
+		{
+			name: "negative step_timeout",
+			mutate: func(c *Config) { c.Runner.StepTimeout = -1 },
+			wantErr: "runner.step_timeout must be >= 0",
+		},
+		{
+			name: "invalid redact_logs",
+			mutate: func(c *Config) { c.Runner.RedactLogs = "all" },
+			wantErr: "runner.redact_logs must be one of",
+		},
+		{
+			name: "invalid kube_guard",
+			mutate: func(c *Config) { c.Runner.KubeGuard = "warn" },
+			wantErr: "runner.kube_guard must be one of",
+		},
+		{
+			name: "invalid run_lock",
+			mutate: func(c *Config) { c.Runner.RunLock = "git" },
+			wantErr: "runner.run_lock must be one of",
+		},
 		{
 			name: "invalid prompt_style",
 			mutate: func(c *Config) { c.Placeholders.PromptStyle = "invalid" },
@@ -221,6 +254,11 @@ func TestValidate_EmptyRequiredFields(t *testing.T) {
 			mutate: func(c *Config) { c.TUI.Theme = "" },
 			wantErr: "tui.theme cannot be empty",
 		},
+		{
+			name: "empty tui.locale",
+			mutate: func(c *Config) { c.TUI.Locale = "" },
+			wantErr: "tui.locale cannot be empty",
+		},
 		{
 			name: "ai.enabled but empty provider",
 			mutate: func(c *Config) {
@@ -38,6 +38,40 @@ func DetectConfigPath() string {
 	return ""
 }
 
+// StateDir returns the directory of svf's local state, such as run
+// records and pins: $XDG_STATE_HOME/svf, defaulting to ~/.local/state/svf.
+// It is never committed.
+func StateDir() (string, error) {
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, "svf"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".local", "state", "svf"), nil
+}
+
+// ReadOnlyMode reports whether readonly is set in the config file at
+// DetectConfigPath or through GITSAVVY_READONLY. Unlike Load it neither
+// migrates nor validates the file, so it is cheap enough to decide which
+// commands to offer before any of them runs.
+func ReadOnlyMode() bool {
+	cfg := &Config{}
+	if path := DetectConfigPath(); path != "" {
+		if data, err := os.ReadFile(path); err == nil {
+			var file struct {
+				ReadOnly bool `toml:"readonly"`
+			}
+			if toml.Unmarshal(data, &file) == nil {
+				cfg.ReadOnly = file.ReadOnly
+			}
+		}
+	}
+	applyEnvOverrides(cfg)
+	return cfg.ReadOnly
+}
+
 // Load loads a config from the specified path.
 // If the file doesn't exist, returns an error.
 // After loading, applies environment variable overrides and validates.
@@ -61,6 +95,23 @@ func Load(path string) (*Config, error) {
 		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
 	}
 
+	// Bring files written by older versions of svf up to date, keeping a
+	// backup of the original. A file that can't be rewritten is still
+	// loaded with the migrations applied.
+	migration, err := Migrate(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate config file %s: %w", path, err)
+	}
+	if migration.Migrated() {
+		if _, err := MigrateFile(path, false); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		cfg = DefaultConfig()
+		if err := toml.Unmarshal(migration.Data, cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse migrated config file %s: %w", path, err)
+		}
+	}
+
 	// Apply environment variable overrides
 	applyEnvOverrides(cfg)
 
@@ -135,6 +186,8 @@ func applyEnvOverrides(c *Config) {
 		}
 	}
 
+	applyBool("GITSAVVY_READONLY", &c.ReadOnly)
+
 	// Repo section
 	applyString("GITSAVVY_REPO_PATH", &c.Repo.Path)
 	applyString("GITSAVVY_REPO_REMOTE", &c.Repo.Remote)
@@ -154,13 +207,18 @@ func applyEnvOverrides(c *Config) {
 	applyBool("GITSAVVY_GIT_PUSH_ON_SAVE", &c.Git.PushOnSave)
 	applyString("GITSAVVY_GIT_PR_BASE_BRANCH", &c.Git.PRBaseBranch)
 	applyString("GITSAVVY_GIT_FEATURE_BRANCH_TEMPLATE", &c.Git.FeatureBranchTemplate)
+	applyString("GITSAVVY_GIT_COMMIT_TEMPLATE", &c.Git.CommitTemplate)
+	applyBool("GITSAVVY_GIT_SCAN_SECRETS", &c.Git.ScanSecrets)
 
 	// Workflows section
 	applyString("GITSAVVY_WORKFLOWS_ROOT", &c.Workflows.Root)
 	applyString("GITSAVVY_WORKFLOWS_SHARED_ROOT", &c.Workflows.SharedRoot)
 	applyString("GITSAVVY_WORKFLOWS_DRAFT_ROOT", &c.Workflows.DraftRoot)
+	applyString("GITSAVVY_WORKFLOWS_TEMPLATE_ROOT", &c.Workflows.TemplateRoot)
 	applyString("GITSAVVY_WORKFLOWS_INDEX_PATH", &c.Workflows.IndexPath)
 	applyInt("GITSAVVY_WORKFLOWS_SCHEMA_VERSION", &c.Workflows.SchemaVersion)
+	applyString("GITSAVVY_WORKFLOWS_README_TEMPLATE", &c.Workflows.ReadmeTemplate)
+	applyString("GITSAVVY_WORKFLOWS_FORMAT", &c.Workflows.Format)
 
 	// Runner section
 	applyString("GITSAVVY_RUNNER_DEFAULT_SHELL", &c.Runner.DefaultShell)
@@ -168,6 +226,12 @@ func applyEnvOverrides(c *Config) {
 	applyBool("GITSAVVY_RUNNER_STREAM_OUTPUT", &c.Runner.StreamOutput)
 	applyInt("GITSAVVY_RUNNER_MAX_OUTPUT_LINES", &c.Runner.MaxOutputLines)
 	applyBool("GITSAVVY_RUNNER_DANGEROUS_COMMAND_WARNINGS", &c.Runner.DangerousCommandWarnings)
+	applyInt("GITSAVVY_RUNNER_STEP_TIMEOUT", &c.Runner.StepTimeout)
+	applyString("GITSAVVY_RUNNER_REDACT_LOGS", &c.Runner.RedactLogs)
+	applyBool("GITSAVVY_RUNNER_USAGE_STATS", &c.Runner.UsageStats)
+	applyBool("GITSAVVY_RUNNER_REQUIRE_REVIEW", &c.Runner.RequireReview)
+	applyString("GITSAVVY_RUNNER_KUBE_GUARD", &c.Runner.KubeGuard)
+	applyString("GITSAVVY_RUNNER_RUN_LOCK", &c.Runner.RunLock)
 
 	// Placeholders section
 	applyString("GITSAVVY_PLACEHOLDERS_PROMPT_STYLE", &c.Placeholders.PromptStyle)
@@ -178,6 +242,7 @@ func applyEnvOverrides(c *Config) {
 	applyBool("GITSAVVY_TUI_ENABLED", &c.TUI.Enabled)
 	applyString("GITSAVVY_TUI_THEME", &c.TUI.Theme)
 	applyBool("GITSAVVY_TUI_SHOW_HELP", &c.TUI.ShowHelp)
+	applyString("GITSAVVY_TUI_LOCALE", &c.TUI.Locale)
 
 	// Editor section
 	applyString("GITSAVVY_EDITOR_COMMAND", &c.Editor.Command)
@@ -190,6 +255,9 @@ func applyEnvOverrides(c *Config) {
 	applyString("GITSAVVY_AI_API_KEY_ENV", &c.AI.APIKeyEnv)
 	applyString("GITSAVVY_AI_REDACT", &c.AI.Redact)
 	applyBool("GITSAVVY_AI_CONFIRM_SEND", &c.AI.ConfirmSend)
+
+	// Telemetry section
+	applyBool("GITSAVVY_TELEMETRY_ENABLED", &c.Telemetry.Enabled)
 }
 
 // expandPath expands ~ to the home directory in the repo path.
@@ -309,6 +309,35 @@ path = "configuser"
 	}
 }
 
+// TestReadOnlyMode tests reading readonly without loading the config.
+func TestReadOnlyMode(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("GITSAVVY_READONLY", "")
+
+	if ReadOnlyMode() {
+		t.Error("expected read-only mode off without a config")
+	}
+
+	path := filepath.Join(home, ".config", "svf", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// An outdated section doesn't keep readonly from being read
+	content := "readonly = true\n\n[repo]\nauto_reindex = \"yes\"\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !ReadOnlyMode() {
+		t.Error("expected read-only mode from the config file")
+	}
+
+	t.Setenv("GITSAVVY_READONLY", "false")
+	if ReadOnlyMode() {
+		t.Error("expected GITSAVVY_READONLY to override the config file")
+	}
+}
+
 // saveEnv saves current environment variables.
 func saveEnv() map[string]string {
 	env := make(map[string]string)
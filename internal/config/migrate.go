@@ -0,0 +1,196 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// CurrentVersion is the version of the config file schema written by this
+// svf. Files without a version key are version 0.
+const CurrentVersion = 1
+
+// Migration upgrades a config file from one schema version to the next.
+type Migration struct {
+	// From is the version the migration applies to; it produces From+1.
+	From int
+
+	// Description says what the migration is for.
+	Description string
+
+	// Apply changes the raw config in place and returns a description of
+	// each change made, such as a renamed key.
+	Apply func(raw map[string]any) []string
+}
+
+// migrations are applied in order to bring a config file up to
+// CurrentVersion. Each one is listed once and never changed after
+// release; later changes to the schema get a new migration.
+var migrations = []Migration{
+	{
+		From:        0,
+		Description: "move repo.auto_reindex to workflows.index.auto_rebuild",
+		Apply: func(raw map[string]any) []string {
+			return moveKey(raw, "repo.auto_reindex", "workflows.index.auto_rebuild")
+		},
+	},
+}
+
+// MigrationResult describes the migration of a config file.
+type MigrationResult struct {
+	// From and To are the file's version before and after migrating.
+	From int
+	To   int
+
+	// Changes describe each change made, in order.
+	Changes []string
+
+	// Data is the migrated file, or the original data if no migration
+	// was needed.
+	Data []byte
+
+	// Backup is the path the original file was copied to, when the file
+	// was rewritten.
+	Backup string
+}
+
+// Migrated reports whether any migration was applied.
+func (r *MigrationResult) Migrated() bool {
+	return r.From != r.To
+}
+
+// Migrate applies the pending migrations to the TOML config in data. A
+// file newer than CurrentVersion is an error: it was written by a newer
+// svf, and rewriting it could lose settings.
+func Migrate(data []byte) (*MigrationResult, error) {
+	raw := make(map[string]any)
+	if err := toml.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	version := 0
+	if v, ok := raw["version"]; ok {
+		n, ok := v.(int64)
+		if !ok {
+			return nil, fmt.Errorf("config version must be an integer; got %v", v)
+		}
+		version = int(n)
+	}
+	if version > CurrentVersion {
+		return nil, fmt.Errorf("config version %d is newer than this svf supports (%d); upgrade svf", version, CurrentVersion)
+	}
+
+	result := &MigrationResult{From: version, To: version, Data: data}
+	if version == CurrentVersion {
+		return result, nil
+	}
+
+	for _, m := range migrations {
+		if m.From < version {
+			continue
+		}
+		result.Changes = append(result.Changes, m.Apply(raw)...)
+	}
+	raw["version"] = int64(CurrentVersion)
+	result.Changes = append(result.Changes, fmt.Sprintf("set version = %d", CurrentVersion))
+	result.To = CurrentVersion
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(raw); err != nil {
+		return nil, fmt.Errorf("failed to encode migrated config: %w", err)
+	}
+	result.Data = buf.Bytes()
+	return result, nil
+}
+
+// MigrateFile migrates the config file at path. Unless dryRun is set, a
+// migrated file is first copied to a backup next to it, named after its
+// old version (config.toml.v0.bak), and then rewritten.
+func MigrateFile(path string, dryRun bool) (*MigrationResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+	result, err := Migrate(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate config file %s: %w", path, err)
+	}
+	if dryRun || !result.Migrated() {
+		return result, nil
+	}
+
+	backup := fmt.Sprintf("%s.v%d.bak", path, result.From)
+	if err := os.WriteFile(backup, data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to back up config file: %w", err)
+	}
+	if err := os.WriteFile(path, result.Data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write migrated config file: %w", err)
+	}
+	result.Backup = backup
+	return result, nil
+}
+
+// moveKey moves the value at the dotted key from to the dotted key to,
+// unless to is already set, in which case from is dropped. It returns the
+// change made, if any.
+func moveKey(raw map[string]any, from, to string) []string {
+	value, ok := lookupKey(raw, from)
+	if !ok {
+		return nil
+	}
+	deleteKey(raw, from)
+	if _, exists := lookupKey(raw, to); exists {
+		return []string{fmt.Sprintf("removed %s (%s is already set)", from, to)}
+	}
+	setKey(raw, to, value)
+	return []string{fmt.Sprintf("moved %s to %s", from, to)}
+}
+
+// splitKey splits a dotted key into its table path and final key.
+func splitKey(key string) ([]string, string) {
+	parts := strings.Split(key, ".")
+	return parts[:len(parts)-1], parts[len(parts)-1]
+}
+
+// table returns the nested table at path, creating missing tables when
+// create is set.
+func table(raw map[string]any, path []string, create bool) map[string]any {
+	t := raw
+	for _, name := range path {
+		next, ok := t[name].(map[string]any)
+		if !ok {
+			if !create {
+				return nil
+			}
+			next = make(map[string]any)
+			t[name] = next
+		}
+		t = next
+	}
+	return t
+}
+
+func lookupKey(raw map[string]any, key string) (any, bool) {
+	path, name := splitKey(key)
+	t := table(raw, path, false)
+	if t == nil {
+		return nil, false
+	}
+	v, ok := t[name]
+	return v, ok
+}
+
+func deleteKey(raw map[string]any, key string) {
+	path, name := splitKey(key)
+	if t := table(raw, path, false); t != nil {
+		delete(t, name)
+	}
+}
+
+func setKey(raw map[string]any, key string, value any) {
+	path, name := splitKey(key)
+	table(raw, path, true)[name] = value
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const v0Config = `[repo]
+path = "/tmp/repo"
+auto_reindex = false
+
+[identity]
+path = "platform/chaz"
+mode = "direct"
+`
+
+func TestMigrate(t *testing.T) {
+	result, err := Migrate([]byte(v0Config))
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if result.From != 0 || result.To != CurrentVersion || !result.Migrated() {
+		t.Errorf("expected a migration from 0 to %d, got %d to %d", CurrentVersion, result.From, result.To)
+	}
+	want := []string{"moved repo.auto_reindex to workflows.index.auto_rebuild", "set version = 1"}
+	if strings.Join(result.Changes, "; ") != strings.Join(want, "; ") {
+		t.Errorf("Changes = %q, want %q", result.Changes, want)
+	}
+	data := string(result.Data)
+	if strings.Contains(data, "auto_reindex") || !strings.Contains(data, "auto_rebuild = false") || !strings.Contains(data, "version = 1") {
+		t.Errorf("unexpected migrated config:\n%s", data)
+	}
+
+	// An up-to-date file is left alone
+	again, err := Migrate(result.Data)
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if again.Migrated() || len(again.Changes) != 0 || string(again.Data) != data {
+		t.Errorf("expected no changes to a current config, got %q", again.Changes)
+	}
+
+	// The new key wins over the old one
+	result, err = Migrate([]byte("[repo]\nauto_reindex = false\n[workflows.index]\nauto_rebuild = true\n"))
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if result.Changes[0] != "removed repo.auto_reindex (workflows.index.auto_rebuild is already set)" {
+		t.Errorf("unexpected change %q", result.Changes[0])
+	}
+
+	if _, err := Migrate([]byte("version = 99\n")); err == nil || !strings.Contains(err.Error(), "newer than this svf supports") {
+		t.Errorf("expected an error for a newer config, got %v", err)
+	}
+}
+
+func TestLoad_Migrates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(v0Config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Version != CurrentVersion || cfg.Workflows.Index.AutoRebuild {
+		t.Errorf("expected a migrated config, got version %d, auto_rebuild %v", cfg.Version, cfg.Workflows.Index.AutoRebuild)
+	}
+
+	backup, err := os.ReadFile(path + ".v0.bak")
+	if err != nil {
+		t.Fatalf("expected a backup: %v", err)
+	}
+	if string(backup) != v0Config {
+		t.Error("backup should hold the original config")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "version = 1") {
+		t.Errorf("expected the config to be rewritten, got:\n%s", data)
+	}
+}
+
+func TestMigrateFile_DryRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(v0Config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := MigrateFile(path, true)
+	if err != nil {
+		t.Fatalf("MigrateFile() error = %v", err)
+	}
+	if !result.Migrated() || result.Backup != "" {
+		t.Errorf("unexpected dry-run result: %+v", result)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != v0Config {
+		t.Error("a dry run must not change the file")
+	}
+}
@@ -0,0 +1,142 @@
+// Package diff provides line-based diffs for workflows and other text.
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// Op is the kind of change a diff line represents.
+type Op int
+
+const (
+	// Equal is a line present in both inputs.
+	Equal Op = iota
+	// Delete is a line only present in the old input.
+	Delete
+	// Insert is a line only present in the new input.
+	Insert
+)
+
+// Line is a single line of a diff.
+type Line struct {
+	Op   Op
+	Text string
+}
+
+// String returns the line with a unified-diff style prefix.
+func (l Line) String() string {
+	switch l.Op {
+	case Delete:
+		return "- " + l.Text
+	case Insert:
+		return "+ " + l.Text
+	default:
+		return "  " + l.Text
+	}
+}
+
+// Lines computes a line diff between old and new using the longest
+// common subsequence of their lines.
+func Lines(old, new string) []Line {
+	return diffStrings(splitLines(old), splitLines(new))
+}
+
+// diffStrings diffs two string slices using their longest common
+// subsequence.
+func diffStrings(a, b []string) []Line {
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var result []Line
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			result = append(result, Line{Op: Equal, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			result = append(result, Line{Op: Delete, Text: a[i]})
+			i++
+		default:
+			result = append(result, Line{Op: Insert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		result = append(result, Line{Op: Delete, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		result = append(result, Line{Op: Insert, Text: b[j]})
+	}
+
+	return result
+}
+
+// Workflows diffs the YAML form of two workflows.
+func Workflows(old, new *workflows.Workflow) ([]Line, error) {
+	oldData, err := marshal(old)
+	if err != nil {
+		return nil, err
+	}
+	newData, err := marshal(new)
+	if err != nil {
+		return nil, err
+	}
+	return Lines(oldData, newData), nil
+}
+
+// HasChanges reports whether a diff contains any insertions or deletions.
+func HasChanges(lines []Line) bool {
+	for _, l := range lines {
+		if l.Op != Equal {
+			return true
+		}
+	}
+	return false
+}
+
+// Format renders a diff as text with +/- prefixes.
+func Format(lines []Line) string {
+	var b strings.Builder
+	for _, l := range lines {
+		b.WriteString(l.String())
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// marshal returns the YAML form of a workflow, or "" for nil.
+func marshal(wf *workflows.Workflow) (string, error) {
+	if wf == nil {
+		return "", nil
+	}
+	data, err := workflows.MarshalWorkflow(wf)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal workflow: %w", err)
+	}
+	return string(data), nil
+}
+
+// splitLines splits s into lines, ignoring a trailing newline.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
@@ -0,0 +1,51 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+func TestLines(t *testing.T) {
+	lines := Lines("a\nb\nc\n", "a\nx\nc\nd\n")
+
+	assert.Equal(t, []Line{
+		{Op: Equal, Text: "a"},
+		{Op: Delete, Text: "b"},
+		{Op: Insert, Text: "x"},
+		{Op: Equal, Text: "c"},
+		{Op: Insert, Text: "d"},
+	}, lines)
+	assert.True(t, HasChanges(lines))
+	assert.Equal(t, "  a\n- b\n+ x\n  c\n+ d\n", Format(lines))
+}
+
+func TestLinesEmpty(t *testing.T) {
+	assert.Empty(t, Lines("", ""))
+	assert.Equal(t, []Line{{Op: Insert, Text: "new"}}, Lines("", "new"))
+	assert.False(t, HasChanges(Lines("same\n", "same")))
+}
+
+func TestWorkflows(t *testing.T) {
+	old := &workflows.Workflow{
+		Title: "Deploy",
+		Steps: []workflows.Step{{Name: "apply", Command: "kubectl apply -f app.yaml"}},
+	}
+	updated := &workflows.Workflow{
+		Title: "Deploy",
+		Steps: []workflows.Step{{Name: "apply", Command: "helm upgrade app ./chart"}},
+	}
+
+	lines, err := Workflows(old, updated)
+	require.NoError(t, err)
+
+	out := Format(lines)
+	assert.Contains(t, out, "- ")
+	assert.Contains(t, out, "kubectl apply -f app.yaml")
+	assert.Contains(t, out, "+ ")
+	assert.Contains(t, out, "helm upgrade app ./chart")
+	assert.Contains(t, out, "  title: Deploy")
+}
@@ -0,0 +1,320 @@
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// Kind is the kind of a semantic change.
+type Kind int
+
+const (
+	// Added is something only present in the new workflow.
+	Added Kind = iota
+	// Removed is something only present in the old workflow.
+	Removed
+	// Modified is something present in both with different fields.
+	Modified
+)
+
+// Section names the part of a workflow a change applies to.
+const (
+	SectionWorkflow    = "workflow"
+	SectionStep        = "step"
+	SectionPlaceholder = "placeholder"
+)
+
+// Change is a single semantic change between two workflows.
+type Change struct {
+	Kind    Kind
+	Section string
+
+	// Name is the workflow title, step name or placeholder name.
+	Name string
+
+	// Index is the 1-based step position: the old position for removed
+	// steps and the new one otherwise. Zero for other sections.
+	Index int
+
+	// Details describe the changed fields, e.g. `command: "a" → "b"`.
+	Details []string
+}
+
+// String returns the change as a header line followed by indented details.
+func (c Change) String() string {
+	var b strings.Builder
+	switch c.Kind {
+	case Added:
+		b.WriteString("+ ")
+	case Removed:
+		b.WriteString("- ")
+	default:
+		b.WriteString("~ ")
+	}
+
+	switch c.Section {
+	case SectionStep:
+		fmt.Fprintf(&b, "step %d %q", c.Index, c.Name)
+	case SectionPlaceholder:
+		fmt.Fprintf(&b, "placeholder <%s>", c.Name)
+	default:
+		fmt.Fprintf(&b, "workflow %q", c.Name)
+	}
+
+	for _, d := range c.Details {
+		b.WriteString("\n    ")
+		b.WriteString(d)
+	}
+	return b.String()
+}
+
+// Semantic compares two workflows field by field: metadata, steps (matched
+// by name, or by command for unnamed steps) and placeholders. A nil old or
+// new workflow is reported as the whole workflow being added or removed.
+func Semantic(old, new *workflows.Workflow) []Change {
+	switch {
+	case old == nil && new == nil:
+		return nil
+	case old == nil:
+		return []Change{{Kind: Added, Section: SectionWorkflow, Name: new.Title,
+			Details: []string{fmt.Sprintf("%d step(s)", len(new.Steps))}}}
+	case new == nil:
+		return []Change{{Kind: Removed, Section: SectionWorkflow, Name: old.Title}}
+	}
+
+	var changes []Change
+
+	var meta []string
+	meta = appendField(meta, "title", old.Title, new.Title)
+	meta = appendField(meta, "description", old.Description, new.Description)
+	if d := setDiff(old.Tags, new.Tags); d != "" {
+		meta = append(meta, "tags: "+d)
+	}
+	meta = appendField(meta, "shell", old.Defaults.Shell, new.Defaults.Shell)
+	meta = appendField(meta, "cwd", old.Defaults.CWD, new.Defaults.CWD)
+	meta = appendField(meta, "confirm_each_step", boolPtrString(old.Defaults.ConfirmEachStep), boolPtrString(new.Defaults.ConfirmEachStep))
+	if len(meta) > 0 {
+		changes = append(changes, Change{Kind: Modified, Section: SectionWorkflow, Name: new.Title, Details: meta})
+	}
+
+	changes = append(changes, stepChanges(old.Steps, new.Steps)...)
+	changes = append(changes, placeholderChanges(old.Placeholders, new.Placeholders)...)
+
+	return changes
+}
+
+// FormatSemantic renders semantic changes, one per block.
+func FormatSemantic(changes []Change) string {
+	var b strings.Builder
+	for _, c := range changes {
+		b.WriteString(c.String())
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// stepChanges matches steps by key and reports added, removed and
+// modified steps.
+func stepChanges(old, new []workflows.Step) []Change {
+	keys := func(steps []workflows.Step) []string {
+		k := make([]string, len(steps))
+		for i, s := range steps {
+			if s.Name != "" {
+				k[i] = "name:" + s.Name
+			} else {
+				k[i] = "command:" + s.Command
+			}
+		}
+		return k
+	}
+
+	var changes []Change
+	i, j := 0, 0
+	for _, l := range diffStrings(keys(old), keys(new)) {
+		switch l.Op {
+		case Equal:
+			if details := stepDetails(old[i], new[j]); len(details) > 0 {
+				changes = append(changes, Change{Kind: Modified, Section: SectionStep,
+					Name: stepLabel(new[j], j), Index: j + 1, Details: details})
+			}
+			i++
+			j++
+		case Delete:
+			changes = append(changes, Change{Kind: Removed, Section: SectionStep,
+				Name: stepLabel(old[i], i), Index: i + 1})
+			i++
+		case Insert:
+			changes = append(changes, Change{Kind: Added, Section: SectionStep,
+				Name: stepLabel(new[j], j), Index: j + 1,
+				Details: []string{"command: " + quote(new[j].Command)}})
+			j++
+		}
+	}
+	return changes
+}
+
+// stepDetails describes the field changes between two matched steps.
+func stepDetails(old, new workflows.Step) []string {
+	var d []string
+	d = appendField(d, "command", old.Command, new.Command)
+	d = appendField(d, "shell", old.Shell, new.Shell)
+	d = appendField(d, "cwd", old.CWD, new.CWD)
+	if e := envDiff(old.Env, new.Env); e != "" {
+		d = append(d, "env: "+e)
+	}
+	d = appendField(d, "continue_on_error", fmt.Sprint(old.ContinueOnError), fmt.Sprint(new.ContinueOnError))
+	d = appendField(d, "confirmation", confirmationString(old.Confirmation), confirmationString(new.Confirmation))
+	return d
+}
+
+// placeholderChanges reports added, removed and modified placeholders in
+// name order.
+func placeholderChanges(old, new map[string]workflows.Placeholder) []Change {
+	names := make(map[string]bool)
+	for name := range old {
+		names[name] = true
+	}
+	for name := range new {
+		names[name] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	var changes []Change
+	for _, name := range sorted {
+		o, inOld := old[name]
+		n, inNew := new[name]
+		switch {
+		case !inOld:
+			var details []string
+			if n.Prompt != "" {
+				details = append(details, "prompt: "+quote(n.Prompt))
+			}
+			changes = append(changes, Change{Kind: Added, Section: SectionPlaceholder, Name: name, Details: details})
+		case !inNew:
+			changes = append(changes, Change{Kind: Removed, Section: SectionPlaceholder, Name: name})
+		default:
+			var d []string
+			d = appendField(d, "prompt", o.Prompt, n.Prompt)
+			if o.Secret || n.Secret {
+				if o.Default != n.Default {
+					d = append(d, "default: (secret) changed")
+				}
+			} else {
+				d = appendField(d, "default", o.Default, n.Default)
+			}
+			d = appendField(d, "validate", o.Validate, n.Validate)
+			d = appendField(d, "secret", fmt.Sprint(o.Secret), fmt.Sprint(n.Secret))
+			if len(d) > 0 {
+				changes = append(changes, Change{Kind: Modified, Section: SectionPlaceholder, Name: name, Details: d})
+			}
+		}
+	}
+	return changes
+}
+
+// appendField appends `name: "old" → "new"` to details if the values differ.
+func appendField(details []string, name, old, new string) []string {
+	if old == new {
+		return details
+	}
+	return append(details, fmt.Sprintf("%s: %s → %s", name, quote(old), quote(new)))
+}
+
+// setDiff describes added and removed values, e.g. "+prod -dev".
+func setDiff(old, new []string) string {
+	inOld := make(map[string]bool, len(old))
+	for _, v := range old {
+		inOld[v] = true
+	}
+	inNew := make(map[string]bool, len(new))
+	for _, v := range new {
+		inNew[v] = true
+	}
+
+	var parts []string
+	for _, v := range new {
+		if !inOld[v] {
+			parts = append(parts, "+"+v)
+		}
+	}
+	for _, v := range old {
+		if !inNew[v] {
+			parts = append(parts, "-"+v)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// envDiff describes changed environment variables.
+func envDiff(old, new map[string]string) string {
+	keys := make(map[string]bool)
+	for k := range old {
+		keys[k] = true
+	}
+	for k := range new {
+		keys[k] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var parts []string
+	for _, k := range sorted {
+		o, inOld := old[k]
+		n, inNew := new[k]
+		switch {
+		case !inOld:
+			parts = append(parts, fmt.Sprintf("+%s=%s", k, n))
+		case !inNew:
+			parts = append(parts, "-"+k)
+		case o != n:
+			parts = append(parts, fmt.Sprintf("~%s=%s", k, n))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// stepLabel returns a step's name, or "Step N" for unnamed steps.
+func stepLabel(s workflows.Step, i int) string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return fmt.Sprintf("Step %d", i+1)
+}
+
+// confirmationString describes a step confirmation setting.
+func confirmationString(c *workflows.StepConfirmation) string {
+	switch {
+	case c == nil:
+		return ""
+	case c.Prompt == "":
+		return "true"
+	default:
+		return c.Prompt
+	}
+}
+
+// boolPtrString formats an optional bool, with "" for unset.
+func boolPtrString(b *bool) string {
+	if b == nil {
+		return ""
+	}
+	return fmt.Sprint(*b)
+}
+
+// quote quotes a value for display, showing "" as (none).
+func quote(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return fmt.Sprintf("%q", s)
+}
@@ -0,0 +1,75 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+func TestSemantic(t *testing.T) {
+	old := &workflows.Workflow{
+		Title: "Deploy",
+		Tags:  []string{"ops", "dev"},
+		Steps: []workflows.Step{
+			{Name: "build", Command: "make build"},
+			{Name: "test", Command: "make test"},
+			{Name: "apply", Command: "kubectl apply -f app.yaml"},
+		},
+		Placeholders: map[string]workflows.Placeholder{
+			"env":   {Prompt: "Environment", Default: "staging"},
+			"token": {Prompt: "Token", Secret: true, Default: "old"},
+		},
+	}
+	updated := &workflows.Workflow{
+		Title: "Deploy app",
+		Tags:  []string{"ops", "prod"},
+		Steps: []workflows.Step{
+			{Name: "build", Command: "make build"},
+			{Name: "apply", Command: "helm upgrade app ./chart", Env: map[string]string{"KUBECONFIG": "~/.kube/prod"}},
+			{Name: "verify", Command: "curl -f https://app/health"},
+		},
+		Placeholders: map[string]workflows.Placeholder{
+			"env":    {Prompt: "Environment", Default: "production"},
+			"token":  {Prompt: "Token", Secret: true, Default: "new"},
+			"region": {Prompt: "Region"},
+		},
+	}
+
+	changes := Semantic(old, updated)
+
+	assert.Equal(t, []Change{
+		{Kind: Modified, Section: SectionWorkflow, Name: "Deploy app", Details: []string{
+			`title: "Deploy" → "Deploy app"`,
+			"tags: +prod -dev",
+		}},
+		{Kind: Removed, Section: SectionStep, Name: "test", Index: 2},
+		{Kind: Modified, Section: SectionStep, Name: "apply", Index: 2, Details: []string{
+			`command: "kubectl apply -f app.yaml" → "helm upgrade app ./chart"`,
+			"env: +KUBECONFIG=~/.kube/prod",
+		}},
+		{Kind: Added, Section: SectionStep, Name: "verify", Index: 3, Details: []string{
+			`command: "curl -f https://app/health"`,
+		}},
+		{Kind: Modified, Section: SectionPlaceholder, Name: "env", Details: []string{
+			`default: "staging" → "production"`,
+		}},
+		{Kind: Added, Section: SectionPlaceholder, Name: "region", Details: []string{`prompt: "Region"`}},
+		{Kind: Modified, Section: SectionPlaceholder, Name: "token", Details: []string{"default: (secret) changed"}},
+	}, changes)
+
+	out := FormatSemantic(changes)
+	assert.Contains(t, out, "- step 2 \"test\"\n")
+	assert.Contains(t, out, "~ placeholder <env>\n    default: \"staging\" → \"production\"\n")
+	assert.NotContains(t, out, `"old"`)
+}
+
+func TestSemanticWholeWorkflow(t *testing.T) {
+	wf := &workflows.Workflow{Title: "Deploy", Steps: []workflows.Step{{Command: "make"}}}
+
+	assert.Empty(t, Semantic(wf, wf))
+	assert.Nil(t, Semantic(nil, nil))
+	assert.Equal(t, []Change{{Kind: Added, Section: SectionWorkflow, Name: "Deploy", Details: []string{"1 step(s)"}}}, Semantic(nil, wf))
+	assert.Equal(t, []Change{{Kind: Removed, Section: SectionWorkflow, Name: "Deploy"}}, Semantic(wf, nil))
+}
@@ -14,11 +14,23 @@
 //   - ErrGit - git operation failed
 //   - ErrIO - file I/O error
 //   - ErrCanceled - user canceled operation
+//   - ErrAuth - credentials missing or rejected
+//   - ErrAIProvider - AI provider failed or not configured
 //
 // Wrapped error types (add context):
 //   - WorkflowError{Op, Err, ID} - workflow operation errors
 //   - GitError{Op, Err, Cmd} - git command errors
 //   - ConfigError{Path, Err} - configuration errors
+//   - ValidationError{Subject, Err} - invalid input, matches ErrInvalid
+//   - ConflictError{Path, Err} - conflicting changes, matches ErrConflict
+//   - AuthError{Service, Err} - authentication failures, matches ErrAuth
+//   - AIProviderError{Provider, NotConfigured, Err} - AI provider errors
+//
+// # Rendering
+//
+// WithHint attaches a suggestion for the user to an error, and ExitCode
+// maps an error to the process exit code svf reports it with. Commands
+// return errors; the CLI's entry point renders them once.
 //
 // # Usage
 //
@@ -64,6 +76,28 @@ var (
 
 	// ErrCanceled indicates the user canceled an operation.
 	ErrCanceled = baseError("canceled")
+
+	// ErrAuth indicates credentials were missing or rejected.
+	ErrAuth = baseError("authentication failed")
+
+	// ErrAIProvider indicates an AI provider failed or isn't configured.
+	ErrAIProvider = baseError("AI provider error")
+)
+
+// Exit codes svf reports errors with.
+const (
+	ExitOK              = 0
+	ExitFailure         = 1
+	ExitUsage           = 2
+	ExitNotFound        = 3
+	ExitInvalid         = 4
+	ExitConflict        = 5
+	ExitAuth            = 6
+	ExitCanceled        = 13
+	ExitStepFailed      = 20
+	ExitMissingParam    = 21
+	ExitAINotConfigured = 30
+	ExitAIProvider      = 31
 )
 
 // baseError is a string that implements error.
@@ -126,6 +160,160 @@ func (e *ConfigError) Error() string {
 
 func (e *ConfigError) Unwrap() error { return e.Err }
 
+// ValidationError represents invalid input, such as a malformed workflow.
+type ValidationError struct {
+	// Subject is what failed validation (optional).
+	Subject string
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	if e.Subject != "" {
+		return fmt.Sprintf("%s: %s", e.Subject, e.Err)
+	}
+	return e.Err.Error()
+}
+
+func (e *ValidationError) Unwrap() error { return e.Err }
+
+// Is reports whether target is ErrInvalid.
+func (e *ValidationError) Is(target error) bool { return target == ErrInvalid }
+
+// ConflictError represents changes that conflict with someone else's.
+type ConflictError struct {
+	// Path is the conflicting file (optional).
+	Path string
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *ConflictError) Error() string {
+	if e.Path != "" {
+		return fmt.Sprintf("%s: %s", e.Path, e.Err)
+	}
+	return e.Err.Error()
+}
+
+func (e *ConflictError) Unwrap() error { return e.Err }
+
+// Is reports whether target is ErrConflict.
+func (e *ConflictError) Is(target error) bool { return target == ErrConflict }
+
+// AuthError represents credentials a service rejected or that are missing.
+type AuthError struct {
+	// Service is what rejected the credentials (e.g., "git", "openai").
+	Service string
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *AuthError) Error() string {
+	return fmt.Sprintf("%s authentication failed: %s", e.Service, e.Err)
+}
+
+func (e *AuthError) Unwrap() error { return e.Err }
+
+// Is reports whether target is ErrAuth.
+func (e *AuthError) Is(target error) bool { return target == ErrAuth }
+
+// AIProviderError represents an AI provider that failed, or that isn't
+// configured.
+type AIProviderError struct {
+	// Provider is the provider's name (optional).
+	Provider string
+	// NotConfigured is set when no usable provider is configured.
+	NotConfigured bool
+	// Err is the underlying error (optional when NotConfigured).
+	Err error
+}
+
+func (e *AIProviderError) Error() string {
+	if e.Err == nil {
+		return "AI provider not configured"
+	}
+	return e.Err.Error()
+}
+
+func (e *AIProviderError) Unwrap() error { return e.Err }
+
+// Is reports whether target is ErrAIProvider.
+func (e *AIProviderError) Is(target error) bool { return target == ErrAIProvider }
+
+// ExitError sets the exit code an error is reported with.
+type ExitError struct {
+	// Code is the process exit code.
+	Code int
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *ExitError) Error() string { return e.Err.Error() }
+
+func (e *ExitError) Unwrap() error { return e.Err }
+
+// WithExitCode returns err reported with exit code code.
+func WithExitCode(err error, code int) error {
+	return &ExitError{Code: code, Err: err}
+}
+
+// WithHint attaches hint, a suggestion on what to do about err, to err.
+// The hint isn't part of the error's message.
+func WithHint(err error, hint string) error {
+	return &hintError{hint: hint, err: err}
+}
+
+// hintError is an error with a hint for the user.
+type hintError struct {
+	hint string
+	err  error
+}
+
+func (e *hintError) Error() string { return e.err.Error() }
+func (e *hintError) Unwrap() error { return e.err }
+
+// Hint returns the hint attached to err closest to its surface, or "" if
+// it has none.
+func Hint(err error) string {
+	var he *hintError
+	if errors.As(err, &he) {
+		return he.hint
+	}
+	return ""
+}
+
+// ExitCode returns the exit code err is reported with: the code set with
+// WithExitCode, or else one for the kind of error err is.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var ee *ExitError
+	if errors.As(err, &ee) {
+		return ee.Code
+	}
+	var ae *AIProviderError
+	if errors.As(err, &ae) {
+		if ae.NotConfigured {
+			return ExitAINotConfigured
+		}
+		return ExitAIProvider
+	}
+	switch {
+	case IsCanceled(err):
+		return ExitCanceled
+	case IsAuth(err):
+		return ExitAuth
+	case IsNotFound(err):
+		return ExitNotFound
+	case IsInvalid(err):
+		return ExitInvalid
+	case IsConflict(err):
+		return ExitConflict
+	}
+	return ExitFailure
+}
+
 // Wrap adds context to an error by wrapping it with an operation name.
 // The returned error implements Unwrap() allowing errors.Is and errors.As
 // to work with the wrapped error.
@@ -177,6 +365,16 @@ func IsCanceled(err error) bool {
 	return errors.Is(err, ErrCanceled)
 }
 
+// IsAuth reports whether err is or wraps ErrAuth.
+func IsAuth(err error) bool {
+	return errors.Is(err, ErrAuth)
+}
+
+// IsAIProvider reports whether err is or wraps ErrAIProvider.
+func IsAIProvider(err error) bool {
+	return errors.Is(err, ErrAIProvider)
+}
+
 // AsWorkflowError reports whether err can be typed as a *WorkflowError.
 func AsWorkflowError(err error) (*WorkflowError, bool) {
 	var we *WorkflowError
@@ -367,3 +367,76 @@ func TestErrorChaining(t *testing.T) {
 		}
 	})
 }
+
+// TestTypedErrors verifies that typed errors match their sentinels.
+func TestTypedErrors(t *testing.T) {
+	cause := errors.New("boom")
+	tests := []struct {
+		name     string
+		err      error
+		sentinel error
+		message  string
+	}{
+		{"ValidationError", &faireerrors.ValidationError{Subject: "deploy.yaml", Err: cause}, faireerrors.ErrInvalid, "deploy.yaml: boom"},
+		{"ConflictError", &faireerrors.ConflictError{Err: cause}, faireerrors.ErrConflict, "boom"},
+		{"AuthError", &faireerrors.AuthError{Service: "git", Err: cause}, faireerrors.ErrAuth, "git authentication failed: boom"},
+		{"AIProviderError", &faireerrors.AIProviderError{NotConfigured: true}, faireerrors.ErrAIProvider, "AI provider not configured"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wrapped := fmt.Errorf("outer: %w", tt.err)
+			if !errors.Is(wrapped, tt.sentinel) {
+				t.Errorf("errors.Is(%v, %v) = false, want true", wrapped, tt.sentinel)
+			}
+			if got := tt.err.Error(); got != tt.message {
+				t.Errorf("Error() = %q, want %q", got, tt.message)
+			}
+		})
+	}
+}
+
+// TestExitCode verifies the exit code each kind of error is reported with.
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, faireerrors.ExitOK},
+		{"plain", errors.New("boom"), faireerrors.ExitFailure},
+		{"not found", fmt.Errorf("workflow %w", faireerrors.ErrNotFound), faireerrors.ExitNotFound},
+		{"invalid", &faireerrors.ValidationError{Err: errors.New("bad")}, faireerrors.ExitInvalid},
+		{"conflict", &faireerrors.ConflictError{Err: errors.New("changed")}, faireerrors.ExitConflict},
+		{"auth", &faireerrors.AuthError{Service: "git", Err: errors.New("denied")}, faireerrors.ExitAuth},
+		{"canceled", faireerrors.Wrap(faireerrors.ErrCanceled, "run"), faireerrors.ExitCanceled},
+		{"AI not configured", &faireerrors.AIProviderError{NotConfigured: true}, faireerrors.ExitAINotConfigured},
+		{"AI auth", &faireerrors.AIProviderError{Err: &faireerrors.AuthError{Service: "openai", Err: errors.New("401")}}, faireerrors.ExitAIProvider},
+		{"explicit", faireerrors.WithExitCode(faireerrors.ErrNotFound, faireerrors.ExitMissingParam), faireerrors.ExitMissingParam},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := faireerrors.ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestWithHint verifies that hints ride along without changing messages.
+func TestWithHint(t *testing.T) {
+	err := fmt.Errorf("outer: %w", faireerrors.WithHint(faireerrors.ErrNotFound, "Run 'svf list'."))
+	if got := err.Error(); got != "outer: not found" {
+		t.Errorf("Error() = %q, want %q", got, "outer: not found")
+	}
+	if got := faireerrors.Hint(err); got != "Run 'svf list'." {
+		t.Errorf("Hint() = %q, want %q", got, "Run 'svf list'.")
+	}
+	if !faireerrors.IsNotFound(err) {
+		t.Error("IsNotFound(hinted error) = false, want true")
+	}
+	if got := faireerrors.Hint(errors.New("plain")); got != "" {
+		t.Errorf("Hint(plain) = %q, want empty", got)
+	}
+}
@@ -103,6 +103,36 @@ func (e *Explainer) ExplainWorkflow(ctx context.Context, wf *workflows.Workflow,
 	return e.provider.Explain(ctx, req)
 }
 
+// maxFailureOutput limits how much failed-step output is sent to the AI.
+const maxFailureOutput = 4000
+
+// ExplainFailure asks the AI for likely causes of and next actions for a
+// failed command. The output is redacted and only its tail is sent.
+func (e *Explainer) ExplainFailure(ctx context.Context, command string, exitCode int, output string) (string, error) {
+	if e.provider == nil {
+		return "", fmt.Errorf("AI provider not configured")
+	}
+
+	if e.offline {
+		return "", fmt.Errorf("offline mode enabled (remove --offline flag to use AI)")
+	}
+
+	output = ai.Redact(output)
+	if len(output) > maxFailureOutput {
+		output = "...\n" + output[len(output)-maxFailureOutput:]
+	}
+
+	req := ai.ExplainRequest{
+		Type:        ai.ExplainFailure,
+		Command:     ai.Redact(command),
+		ExitCode:    exitCode,
+		Output:      output,
+		DetailLevel: ai.DetailNormal,
+	}
+
+	return e.provider.Explain(ctx, req)
+}
+
 // explainWithAI uses the AI provider for explanation.
 func (e *Explainer) explainWithAI(ctx context.Context, cmd string, explainType ExplainType) (Explanation, error) {
 	req := ai.ExplainRequest{
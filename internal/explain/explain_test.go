@@ -0,0 +1,64 @@
+package explain
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/chazuruo/svf/internal/ai"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// fakeProvider records the last explain request.
+type fakeProvider struct {
+	last ai.ExplainRequest
+}
+
+func (p *fakeProvider) Name() string { return "fake" }
+
+func (p *fakeProvider) GenerateWorkflow(ctx context.Context, req ai.GenerateRequest) (*workflows.Workflow, error) {
+	return nil, nil
+}
+
+func (p *fakeProvider) Explain(ctx context.Context, req ai.ExplainRequest) (string, error) {
+	p.last = req
+	return "check the credentials", nil
+}
+
+func TestExplainFailure(t *testing.T) {
+	p := &fakeProvider{}
+	e := NewExplainer(&Options{Provider: p})
+
+	output := strings.Repeat("x", maxFailureOutput) + "\npassword=hunter22\nerror: denied"
+	got, err := e.ExplainFailure(context.Background(), "deploy --token", 2, output)
+	if err != nil {
+		t.Fatalf("ExplainFailure() error = %v", err)
+	}
+	if got != "check the credentials" {
+		t.Errorf("unexpected explanation %q", got)
+	}
+
+	if p.last.Type != ai.ExplainFailure || p.last.ExitCode != 2 {
+		t.Errorf("unexpected request: %+v", p.last)
+	}
+	if strings.Contains(p.last.Output, "hunter22") {
+		t.Error("expected output to be redacted")
+	}
+	if !strings.HasSuffix(p.last.Output, "error: denied") {
+		t.Error("expected the tail of the output to be kept")
+	}
+	if len(p.last.Output) > maxFailureOutput+10 {
+		t.Errorf("expected output to be truncated, got %d bytes", len(p.last.Output))
+	}
+}
+
+func TestExplainFailureRequiresProvider(t *testing.T) {
+	if _, err := NewExplainer(nil).ExplainFailure(context.Background(), "false", 1, ""); err == nil {
+		t.Error("expected error without a provider")
+	}
+
+	e := NewExplainer(&Options{Provider: &fakeProvider{}, Offline: true})
+	if _, err := e.ExplainFailure(context.Background(), "false", 1, ""); err == nil {
+		t.Error("expected error in offline mode")
+	}
+}
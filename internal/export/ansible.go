@@ -0,0 +1,287 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/chazuruo/svf/internal/placeholders"
+	//nolint:staticcheck // SA1019 - Using runner for dangerous command detection
+	runnerpkg "github.com/chazuruo/svf/internal/runner"
+	"github.com/chazuruo/svf/internal/workflows"
+	"gopkg.in/yaml.v3"
+)
+
+// ansiblePlay is an Ansible play running a workflow on the local machine.
+type ansiblePlay struct {
+	Name        string              `yaml:"name"`
+	Hosts       string              `yaml:"hosts"`
+	Connection  string              `yaml:"connection"`
+	GatherFacts bool                `yaml:"gather_facts"`
+	Vars        map[string]string   `yaml:"vars,omitempty"`
+	VarsPrompt  []ansibleVarsPrompt `yaml:"vars_prompt,omitempty"`
+	Tasks       []ansibleTask       `yaml:"tasks"`
+}
+
+// ansibleVarsPrompt asks for a variable when the play starts, unless it is
+// passed with --extra-vars.
+type ansibleVarsPrompt struct {
+	Name    string `yaml:"name"`
+	Prompt  string `yaml:"prompt"`
+	Private bool   `yaml:"private"`
+}
+
+// ansibleTask is a shell, pause or fail task, or a block of tasks.
+type ansibleTask struct {
+	Name         string            `yaml:"name"`
+	Shell        string            `yaml:"ansible.builtin.shell,omitempty"`
+	Pause        *ansiblePause     `yaml:"ansible.builtin.pause,omitempty"`
+	Fail         *ansibleFail      `yaml:"ansible.builtin.fail,omitempty"`
+	Args         *ansibleShellArgs `yaml:"args,omitempty"`
+	Environment  map[string]string `yaml:"environment,omitempty"`
+	IgnoreErrors bool              `yaml:"ignore_errors,omitempty"`
+	Block        []ansibleTask     `yaml:"block,omitempty"`
+	Rescue       []ansibleTask     `yaml:"rescue,omitempty"`
+}
+
+type ansibleShellArgs struct {
+	Chdir      string `yaml:"chdir,omitempty"`
+	Executable string `yaml:"executable,omitempty"`
+}
+
+type ansiblePause struct {
+	Prompt string `yaml:"prompt"`
+}
+
+type ansibleFail struct {
+	Msg string `yaml:"msg"`
+}
+
+// ansibleEscaper keeps Jinja syntax already in commands literal.
+var ansibleEscaper = strings.NewReplacer(
+	"{{", "{{ '{{' }}",
+	"{%", "{{ '{%' }}",
+	"{#", "{{ '{#' }}",
+)
+
+// Ansible renders a workflow as an Ansible playbook with one shell task
+// per step, run against localhost. Placeholders with a default become play
+// vars, the others are prompted for with vars_prompt; both can be set with
+// --extra-vars. Dangerous commands and steps with confirmations get a pause
+// task first, and cleanup steps run in a rescue section when a step fails.
+//
+// The playbook is meant as a starting point for moving a runbook to
+// configuration management: only simple workflows can be converted, not
+// ones with companion scripts or files or with captured values.
+func Ansible(wf *workflows.Workflow) (string, error) {
+	if err := checkConvertible(wf, "Ansible"); err != nil {
+		return "", err
+	}
+
+	play := ansiblePlay{
+		Name:        wf.Title,
+		Hosts:       "localhost",
+		Connection:  "local",
+		GatherFacts: false,
+	}
+
+	infos := placeholders.ExtractAll(wf)
+	for _, name := range sortedPlaceholderNames(infos) {
+		info := infos[name]
+		v := ansibleVarName(name)
+		if info.Default != "" {
+			if play.Vars == nil {
+				play.Vars = map[string]string{}
+			}
+			play.Vars[v] = ansibleEscaper.Replace(info.Default)
+			continue
+		}
+		prompt := info.Prompt
+		if prompt == "" {
+			prompt = name
+		}
+		play.VarsPrompt = append(play.VarsPrompt, ansibleVarsPrompt{Name: v, Prompt: prompt, Private: info.Secret})
+	}
+
+	var tasks []ansibleTask
+	for i, step := range wf.Steps {
+		tasks = append(tasks, ansibleStepTasks(wf, i, step)...)
+	}
+	if len(wf.Cleanup) > 0 {
+		var rescue []ansibleTask
+		for i, step := range wf.Cleanup {
+			task := ansibleStepTasks(wf, i, step)
+			// Cleanup keeps going when one of its steps fails
+			task[len(task)-1].IgnoreErrors = true
+			rescue = append(rescue, task...)
+		}
+		rescue = append(rescue, ansibleTask{
+			Name: "Fail after cleanup",
+			Fail: &ansibleFail{Msg: "A step failed; cleanup ran."},
+		})
+		tasks = []ansibleTask{{Name: wf.Title, Block: tasks, Rescue: rescue}}
+	}
+	play.Tasks = tasks
+
+	body, err := marshalYAML([]ansiblePlay{play})
+	if err != nil {
+		return "", err
+	}
+	return convertedHeader(wf, "ansible-playbook playbook.yml --extra-vars 'name=value'") + "---\n" + body, nil
+}
+
+// ansibleStepTasks returns the tasks for one step: a pause when it needs
+// confirming, then its shell task.
+func ansibleStepTasks(wf *workflows.Workflow, i int, step workflows.Step) []ansibleTask {
+	name := stepName(i, step)
+	var tasks []ansibleTask
+	if confirm := stepConfirmation(step); len(confirm) > 0 {
+		tasks = append(tasks, ansibleTask{
+			Name:  "Confirm " + name,
+			Pause: &ansiblePause{Prompt: strings.Join(confirm, "\n") + "\nPress enter to continue, or Ctrl+C and A to abort"},
+		})
+	}
+
+	task := ansibleTask{
+		Name:         name,
+		Shell:        ansibleTemplate(step.Command),
+		IgnoreErrors: step.ContinueOnError,
+	}
+	args := ansibleShellArgs{Chdir: ansibleTemplate(stepCWD(wf, step)), Executable: stepShell(wf, step)}
+	if args != (ansibleShellArgs{}) {
+		task.Args = &args
+	}
+	for k, v := range step.Env {
+		if task.Environment == nil {
+			task.Environment = map[string]string{}
+		}
+		task.Environment[k] = ansibleTemplate(v)
+	}
+	return append(tasks, task)
+}
+
+// ansibleTemplate turns the <parameter> tokens in s into Jinja variables.
+func ansibleTemplate(s string) string {
+	return scriptPlaceholderRegex.ReplaceAllStringFunc(ansibleEscaper.Replace(s), func(m string) string {
+		return "{{ " + ansibleVarName(m[1:len(m)-1]) + " }}"
+	})
+}
+
+// ansibleVarName converts a placeholder name to an Ansible variable name.
+func ansibleVarName(name string) string {
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+// checkConvertible returns an error if wf uses features that have no
+// counterpart in the target format: companion scripts and files, and
+// captured values.
+func checkConvertible(wf *workflows.Workflow, target string) error {
+	steps := append(append([]workflows.Step(nil), wf.Steps...), wf.Cleanup...)
+	for i, step := range steps {
+		var feature string
+		switch {
+		case step.Script != "":
+			feature = "a companion script"
+		case len(step.Files) > 0:
+			feature = "companion files"
+		case len(step.Capture) > 0:
+			feature = "captured values"
+		default:
+			continue
+		}
+		name := step.Name
+		if name == "" {
+			name = fmt.Sprintf("step %d", i+1)
+		}
+		return fmt.Errorf("cannot export to %s: %q uses %s", target, name, feature)
+	}
+	return nil
+}
+
+// convertedHeader returns the comment opening a converted workflow, with
+// an example of how to run it.
+func convertedHeader(wf *workflows.Workflow, usage string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", wf.Title)
+	if wf.Description != "" {
+		for _, line := range strings.Split(strings.TrimSpace(wf.Description), "\n") {
+			fmt.Fprintf(&b, "# %s\n", line)
+		}
+	}
+	if wf.ID != "" {
+		fmt.Fprintf(&b, "#\n# Workflow ID: %s\n", wf.ID)
+	}
+	b.WriteString("#\n# Generated by svf as a starting point; review it before use.\n")
+	fmt.Fprintf(&b, "# Run with: %s\n", usage)
+	return b.String()
+}
+
+// stepName returns the name of step i, numbering unnamed steps.
+func stepName(i int, step workflows.Step) string {
+	if step.Name == "" {
+		return fmt.Sprintf("Step %d", i+1)
+	}
+	return step.Name
+}
+
+// stepConfirmation returns the warning lines for a step that needs
+// confirming: a dangerous command or an explicit confirmation.
+func stepConfirmation(step workflows.Step) []string {
+	var confirm []string
+	if danger := runnerpkg.CheckDangerous(step.Command); danger != nil {
+		confirm = append(confirm,
+			fmt.Sprintf("WARNING: %s detected", danger.Name),
+			fmt.Sprintf("Risk: %s", danger.Risk))
+	}
+	if step.Confirmation != nil {
+		if step.Confirmation.Prompt != "" {
+			confirm = append(confirm, step.Confirmation.Prompt)
+		} else {
+			confirm = append(confirm, "This step requires confirmation.")
+		}
+	}
+	return confirm
+}
+
+// stepCWD returns the working directory of a step, falling back to the
+// workflow default.
+func stepCWD(wf *workflows.Workflow, step workflows.Step) string {
+	if step.CWD != "" {
+		return step.CWD
+	}
+	return wf.Defaults.CWD
+}
+
+// stepShell returns the shell of a step, falling back to the workflow
+// default.
+func stepShell(wf *workflows.Workflow, step workflows.Step) string {
+	if step.Shell != "" {
+		return step.Shell
+	}
+	return wf.Defaults.Shell
+}
+
+// sortedPlaceholderNames returns the names in infos, sorted.
+func sortedPlaceholderNames(infos map[string]placeholders.PlaceholderInfo) []string {
+	names := make([]string, 0, len(infos))
+	for name := range infos {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// marshalYAML encodes v as YAML indented by two spaces.
+func marshalYAML(v any) (string, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return "", fmt.Errorf("failed to marshal YAML: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return "", fmt.Errorf("failed to marshal YAML: %w", err)
+	}
+	return buf.String(), nil
+}
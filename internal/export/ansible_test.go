@@ -0,0 +1,76 @@
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+func convertTestWorkflow() *workflows.Workflow {
+	wf := scriptTestWorkflow()
+	wf.Steps = append(wf.Steps, workflows.Step{
+		Command:         "docker ps --format '{{.Names}}' --filter name=<token>",
+		CWD:             "~/src/<user-name>",
+		Env:             map[string]string{"OWNER": "<user-name>"},
+		Shell:           "bash",
+		ContinueOnError: true,
+	})
+	wf.Placeholders["token"] = workflows.Placeholder{Prompt: "Token", Secret: true}
+	wf.Cleanup = []workflows.Step{{Name: "undo", Command: "rm out.txt"}}
+	return wf
+}
+
+func TestAnsible(t *testing.T) {
+	playbook, err := Ansible(convertTestWorkflow())
+	if err != nil {
+		t.Fatalf("Ansible() error = %v", err)
+	}
+	for _, want := range []string{
+		"hosts: localhost",
+		"user_name: world",
+		"- name: token\n      prompt: Token\n      private: true",
+		`ansible.builtin.shell: echo "hello {{ user_name }}" > out.txt`,
+		"- name: Confirm wipe\n          ansible.builtin.pause:",
+		"docker ps --format '{{ '{{' }}.Names}}' --filter name={{ token }}",
+		"chdir: ~/src/{{ user_name }}",
+		"executable: bash",
+		"OWNER: '{{ user_name }}'",
+		"rescue:\n        - name: undo",
+		"ansible.builtin.fail:",
+	} {
+		if !strings.Contains(playbook, want) {
+			t.Errorf("expected playbook to contain %q\n%s", want, playbook)
+		}
+	}
+
+	var plays []map[string]any
+	if err := yaml.Unmarshal([]byte(playbook), &plays); err != nil || len(plays) != 1 {
+		t.Fatalf("playbook is not a single play: %v\n%s", err, playbook)
+	}
+}
+
+func TestConvert_Unsupported(t *testing.T) {
+	tests := []struct {
+		name string
+		step workflows.Step
+		want string
+	}{
+		{"script", workflows.Step{Name: "migrate", Script: "migrate.sh"}, `"migrate" uses a companion script`},
+		{"files", workflows.Step{Command: "kubectl apply -f <manifest>", Files: map[string]string{"manifest": "app.yaml"}}, `"step 1" uses companion files`},
+		{"capture", workflows.Step{Name: "get id", Command: "make id", Capture: map[string]workflows.Extractor{"id": {}}}, `"get id" uses captured values`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf := &workflows.Workflow{Title: "Convert", Steps: []workflows.Step{tt.step}}
+			if _, err := Ansible(wf); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Ansible() error = %v, want %q", err, tt.want)
+			}
+			if _, err := Taskfile(wf); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Taskfile() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -22,6 +22,12 @@ const (
 	FormatYAML Format = "yaml"
 	// FormatJSON exports as JSON.
 	FormatJSON Format = "json"
+	// FormatTOML exports as TOML.
+	FormatTOML Format = "toml"
+	// FormatAnsible exports as an Ansible playbook.
+	FormatAnsible Format = "ansible"
+	// FormatTaskfile exports as a Taskfile.yml.
+	FormatTaskfile Format = "taskfile"
 )
 
 // Exporter exports workflows in various formats.
@@ -31,6 +37,7 @@ type Exporter struct {
 	updateReadme bool
 	template    *template.Template
 	repoPath    string
+	sourceDir   string
 }
 
 // Options contains export options.
@@ -40,6 +47,11 @@ type Options struct {
 	UpdateReadme  bool
 	CustomTemplate string
 	RepoPath      string
+
+	// SourceDir is the directory of the workflow file. Workflow file
+	// exports (yaml, json, toml) written to Out copy the workflow's
+	// companion files from it, so the export can be imported again.
+	SourceDir string
 }
 
 // NewExporter creates a new exporter.
@@ -49,6 +61,7 @@ func NewExporter(opts Options) (*Exporter, error) {
 		outPath:     opts.Out,
 		updateReadme: opts.UpdateReadme,
 		repoPath:    opts.RepoPath,
+		sourceDir:   opts.SourceDir,
 	}
 
 	// Load template
@@ -68,20 +81,17 @@ func (e *Exporter) loadTemplate(customPath string) (*template.Template, error) {
 		return e.parseTemplateFile(customPath)
 	}
 
-	// Check built-in templates based on format
-	var tmplContent string
+	// Check built-in templates based on format. The workflow file formats
+	// have no template: the workflow is marshaled whole, so the export can
+	// be imported again.
 	switch e.format {
 	case FormatMarkdown:
-		tmplContent = builtinMarkdownTemplate
-	case FormatYAML:
-		tmplContent = builtinYAMLTemplate
-	case FormatJSON:
-		tmplContent = builtinJSONTemplate
+		return template.New("export").Parse(builtinMarkdownTemplate)
+	case FormatYAML, FormatJSON, FormatTOML, FormatAnsible, FormatTaskfile:
+		return nil, nil
 	default:
 		return nil, fmt.Errorf("unsupported format: %s", e.format)
 	}
-
-	return template.New("export").Parse(tmplContent)
 }
 
 // parseTemplateFile parses a template file.
@@ -118,20 +128,41 @@ func (e *Exporter) parseTemplateFile(path string) (*template.Template, error) {
 
 // Export exports a workflow.
 func (e *Exporter) Export(wf *workflows.Workflow) (string, error) {
-	var buf bytes.Buffer
-
-	data := e.templateData(wf)
-	if err := e.template.Execute(&buf, data); err != nil {
-		return "", fmt.Errorf("executing template: %w", err)
+	var output string
+	if e.template == nil {
+		var err error
+		switch e.format {
+		case FormatAnsible:
+			output, err = Ansible(wf)
+		case FormatTaskfile:
+			output, err = Taskfile(wf)
+		default:
+			var data []byte
+			data, err = workflows.MarshalWorkflowAs(wf, workflows.Format(e.format))
+			output = string(data)
+		}
+		if err != nil {
+			return "", err
+		}
+	} else {
+		var buf bytes.Buffer
+		data := e.templateData(wf)
+		if err := e.template.Execute(&buf, data); err != nil {
+			return "", fmt.Errorf("executing template: %w", err)
+		}
+		output = buf.String()
 	}
 
-	output := buf.String()
-
 	// Write to file if outPath is specified
 	if e.outPath != "" && e.outPath != "-" {
 		if err := os.WriteFile(e.outPath, []byte(output), 0644); err != nil {
 			return "", fmt.Errorf("writing output file: %w", err)
 		}
+		if e.template == nil && e.sourceDir != "" {
+			if err := workflows.CopyCompanions(wf, e.sourceDir, filepath.Dir(e.outPath)); err != nil {
+				return "", err
+			}
+		}
 	}
 
 	return output, nil
@@ -227,8 +258,11 @@ func (e *Exporter) templateData(wf *workflows.Workflow) map[string]interface{} {
 			"index":            i + 1,
 			"name":             step.Name,
 			"command":          step.Command,
+			"script":           step.Script,
+			"files":            step.Files,
 			"shell":            step.Shell,
 			"cwd":              step.CWD,
+			"links":            step.Links,
 			"env":              step.Env,
 			"continueOnError":  step.ContinueOnError,
 		}
@@ -256,11 +290,4 @@ func (e *Exporter) templateData(wf *workflows.Workflow) map[string]interface{} {
 }
 
 // builtinMarkdownTemplate is the default Markdown template.
-const builtinMarkdownTemplate = "# {{.Title}}\n\n{{if .ID}}**ID:** {{.ID}}{{end}}\n{{if .Description}}{{.Description}}{{end}}\n{{if .Tags}}**Tags:** {{range $i, $tag := .Tags}}{{if $i}}, {{end}}{{$tag}}{{end}}{{end}}\n\n## Steps\n\n{{range .Steps}}### {{.index}}. {{if .name}}{{.name}}{{else}}Step{{end}}\n\n" + "```{{if .shell}}{{.shell}}{{else}}bash{{end}}\n{{.command}}\n```\n" + "{{if .cwd}}**Working Directory:** {{.cwd}}{{end}}\n{{if .env}}**Environment Variables:**\n{{range $key, $value := .env}}- {{$key}}={{$value}}\n{{end}}{{end}}\n{{if .continueOnError}}**Continues on error:** Yes{{end}}\n\n{{end}}\n{{if .Placeholders}}\n## Placeholders\n\n{{range $key, $ph := .Placeholders}}- **<{{$key}}>**\n  {{if $ph.prompt}}{{$ph.prompt}}{{else}}{{$key}}{{end}}\n  {{if $ph.default}}(default: {{$ph.default}}){{end}}\n  {{if $ph.secret}}*This value is secret and will be masked in output*{{end}}\n{{end}}\n{{end}}\n\n{{if .Defaults}}\n## Defaults\n\n{{if .Defaults.shell}}**Shell:** {{.Defaults.shell}}{{end}}\n{{if .Defaults.cwd}}**Working Directory:** {{.Defaults.cwd}}{{end}}\n{{if .Defaults.confirmEachStep}}**Confirm Each Step:** {{.Defaults.confirmEachStep}}{{end}}\n{{end}}\n\n---\n*Generated by svf*\n"
-
-// builtinYAMLTemplate is the default YAML template.
-const builtinYAMLTemplate = "{{if .ID}}id: {{.ID}}\n{{end}}title: {{.Title}}\n{{if .Description}}description: {{.Description}}\n{{end}}{{if .Tags}}tags:\n{{range $tag := .Tags}}  - {{$tag}}\n{{end}}{{end}}{{if .Defaults}}defaults:\n  {{if .Defaults.shell}}shell: {{.Defaults.shell}}\n  {{end}}{{if .Defaults.cwd}}cwd: {{.Defaults.cwd}}\n  {{end}}{{if .Defaults.confirmEachStep}}confirm_each_step: {{.Defaults.confirmEachStep}}\n  {{end}}{{end}}steps:\n{{range .Steps}}  - name: {{.name}}\n    command: {{.command}}\n    {{if .shell}}shell: {{.shell}}\n    {{end}}{{if .cwd}}cwd: {{.cwd}}\n    {{end}}{{if .continueOnError}}continue_on_error: {{.continueOnError}}\n    {{end}}{{if .env}}env:\n{{range $key, $value := .env}}      {{$key}}: {{$value}}\n{{end}}  {{end}}{{end}}\n{{if .Placeholders}}placeholders:\n{{range $key, $ph := .Placeholders}}  {{$key}}:\n    prompt: {{$ph.prompt}}\n    default: {{$ph.default}}\n    {{if $ph.validate}}validate: {{$ph.validate}}\n    {{end}}{{if $ph.secret}}secret: {{$ph.secret}}\n    {{end}}{{end}}\n{{end}}\n"
-
-// builtinJSONTemplate is the default JSON template.
-// Note: For JSON output, consider using encoding/json directly.
-const builtinJSONTemplate = "{\n  \"title\": \"{{.Title}}\",\n{{if .Description}}  \"description\": \"{{.Description}}\",\n{{end}}{{if .Tags}}  \"tags\": [{{range $i, $tag := .Tags}}{{if $i}}, {{end}}\"{{$tag}}\"{{end}}],\n{{end}}  \"steps\": [\n{{range $i, $step := .Steps}}{{if $i}},{{end}}    {\n      \"name\": \"{{.name}}\",\n      \"command\": \"{{.command}}\"\n    }{{end}}\n  ]\n}\n"
+const builtinMarkdownTemplate = "# {{.Title}}\n\n{{if .ID}}**ID:** {{.ID}}{{end}}\n{{if .Description}}{{.Description}}{{end}}\n{{if .Tags}}**Tags:** {{range $i, $tag := .Tags}}{{if $i}}, {{end}}{{$tag}}{{end}}{{end}}\n\n## Steps\n\n{{range .Steps}}### {{.index}}. {{if .name}}{{.name}}{{else}}Step{{end}}\n\n" + "```{{if .shell}}{{.shell}}{{else}}bash{{end}}\n{{if .script}}{{.script}}{{else}}{{.command}}{{end}}\n```\n" + "{{if .cwd}}**Working Directory:** {{.cwd}}{{end}}\n{{if .links}}**Links:**\n{{range .links}}- [{{.Label}}]({{.URL}})\n{{end}}{{end}}{{if .env}}**Environment Variables:**\n{{range $key, $value := .env}}- {{$key}}={{$value}}\n{{end}}{{end}}\n{{if .continueOnError}}**Continues on error:** Yes{{end}}\n\n{{end}}\n{{if .Placeholders}}\n## Placeholders\n\n{{range $key, $ph := .Placeholders}}- **<{{$key}}>**\n  {{if $ph.prompt}}{{$ph.prompt}}{{else}}{{$key}}{{end}}\n  {{if $ph.default}}(default: {{$ph.default}}){{end}}\n  {{if $ph.secret}}*This value is secret and will be masked in output*{{end}}\n{{end}}\n{{end}}\n\n{{if .Defaults}}\n## Defaults\n\n{{if .Defaults.shell}}**Shell:** {{.Defaults.shell}}{{end}}\n{{if .Defaults.cwd}}**Working Directory:** {{.Defaults.cwd}}{{end}}\n{{if .Defaults.confirmEachStep}}**Confirm Each Step:** {{.Defaults.confirmEachStep}}{{end}}\n{{end}}\n\n---\n*Generated by svf*\n"
@@ -142,6 +142,37 @@ func TestExporter_Export(t *testing.T) {
 	}
 }
 
+func TestExporter_ExportLinks(t *testing.T) {
+	wf := &workflows.Workflow{
+		SchemaVersion: 1,
+		Title:         "Failover",
+		Steps: []workflows.Step{{
+			Name:    "promote",
+			Command: "promote",
+			Links: []workflows.Link{
+				{URL: "https://grafana.example.com/d/db"},
+				{Title: "Runbook", URL: "https://wiki.example.com/failover"},
+			},
+		}},
+	}
+	e, err := NewExporter(Options{Format: FormatMarkdown, RepoPath: "/tmp/test"})
+	if err != nil {
+		t.Fatalf("NewExporter() error = %v", err)
+	}
+	output, err := e.Export(wf)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	for _, want := range []string{
+		"- [https://grafana.example.com/d/db](https://grafana.example.com/d/db)",
+		"- [Runbook](https://wiki.example.com/failover)",
+	} {
+		if !contains(output, want) {
+			t.Errorf("expected %q in markdown:\n%s", want, output)
+		}
+	}
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > len(substr) && containsSubstring(s, substr))
 }
@@ -495,3 +526,35 @@ func boolPtr(b bool) *bool {
 	return &b
 }
 
+
+func TestExporter_ExportRoundTrip(t *testing.T) {
+	wf := &workflows.Workflow{
+		SchemaVersion: 1,
+		Title:         "Round Trip",
+		Placeholders:  map[string]workflows.Placeholder{"env": {Default: "staging"}},
+		Steps: []workflows.Step{
+			{Name: "deploy", Command: "deploy --env <env>", Env: map[string]string{"DEBUG": "1"}},
+		},
+	}
+
+	for _, format := range []Format{FormatYAML, FormatJSON, FormatTOML} {
+		t.Run(string(format), func(t *testing.T) {
+			e, err := NewExporter(Options{Format: format})
+			if err != nil {
+				t.Fatalf("NewExporter() error = %v", err)
+			}
+			output, err := e.Export(wf)
+			if err != nil {
+				t.Fatalf("Export() error = %v", err)
+			}
+
+			got, err := workflows.UnmarshalWorkflowAs([]byte(output), workflows.Format(format))
+			if err != nil {
+				t.Fatalf("exported %s does not import: %v\n%s", format, err, output)
+			}
+			if got.Steps[0].Env["DEBUG"] != "1" || got.Placeholders["env"].Default != "staging" {
+				t.Errorf("imported workflow = %+v", got)
+			}
+		})
+	}
+}
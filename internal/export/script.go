@@ -0,0 +1,239 @@
+// Package export provides workflow export functionality with template support.
+package export
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/chazuruo/svf/internal/placeholders"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// ScriptShell is a shell that scripts can be generated for.
+type ScriptShell string
+
+const (
+	// ScriptBash generates a bash script.
+	ScriptBash ScriptShell = "bash"
+	// ScriptZsh generates a zsh script.
+	ScriptZsh ScriptShell = "zsh"
+)
+
+// exitCanceled matches the exit code svf uses when a run is canceled.
+const exitCanceled = 13
+
+// scriptPlaceholderRegex matches <parameter> tokens in commands.
+var scriptPlaceholderRegex = regexp.MustCompile(`<([a-zA-Z_][a-zA-Z0-9_-]*)>`)
+
+// Script renders a workflow as a standalone shell script that can run
+// without svf installed. Placeholders become read prompts for SVF_<NAME>
+// variables (skipped when the variable is already set in the environment),
+// dangerous commands and steps with confirmations become y/N checks, and
+// step names become comments.
+func Script(wf *workflows.Workflow, shell ScriptShell) (string, error) {
+	if shell == "" {
+		shell = ScriptBash
+	}
+	if shell != ScriptBash && shell != ScriptZsh {
+		return "", fmt.Errorf("unsupported script shell: %s (use bash or zsh)", shell)
+	}
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "#!/usr/bin/env %s\n", shell)
+	fmt.Fprintf(&b, "# %s\n", wf.Title)
+	if wf.Description != "" {
+		for _, line := range strings.Split(strings.TrimSpace(wf.Description), "\n") {
+			fmt.Fprintf(&b, "# %s\n", line)
+		}
+	}
+	if wf.ID != "" {
+		fmt.Fprintf(&b, "#\n# Workflow ID: %s\n", wf.ID)
+	}
+	b.WriteString("#\n# Generated by svf. Relative working directories are resolved\n")
+	b.WriteString("# against the directory the script is run from.\n\n")
+	b.WriteString("set -eu -o pipefail\n\n")
+
+	// Placeholders, including captured ones, which the script can't
+	// extract itself
+	infos := placeholders.ExtractAll(wf)
+	names := make([]string, 0, len(infos))
+	for name := range infos {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if err := checkVarNames(names, scriptVarName); err != nil {
+		return "", err
+	}
+
+	if len(names) > 0 {
+		b.WriteString("# Parameters (set SVF_<NAME> in the environment to skip the prompts)\n")
+		for _, name := range names {
+			writeScriptPrompt(&b, shell, infos[name])
+		}
+		b.WriteString("\n")
+	}
+
+	// Steps
+	for i, step := range wf.Steps {
+		writeScriptStep(&b, shell, wf, i, step)
+	}
+
+	b.WriteString("echo \"Workflow completed.\"\n")
+
+	return b.String(), nil
+}
+
+// scriptVarName converts a placeholder name to a shell variable name. The
+// SVF_ prefix keeps placeholders such as <path> or <home> from clobbering
+// the environment the commands run in.
+func scriptVarName(name string) string {
+	return "SVF_" + taskfileVarName(name)
+}
+
+// checkVarNames returns an error if two placeholder names map to the same
+// variable, such as <foo-bar> and <foo_bar>.
+func checkVarNames(names []string, varName func(string) string) error {
+	seen := make(map[string]string, len(names))
+	for _, name := range names {
+		v := varName(name)
+		if other, ok := seen[v]; ok {
+			return fmt.Errorf("placeholders <%s> and <%s> both map to variable %s; rename one", other, name, v)
+		}
+		seen[v] = name
+	}
+	return nil
+}
+
+// writeScriptPrompt writes the prompt and validation for one placeholder.
+func writeScriptPrompt(b *strings.Builder, shell ScriptShell, info placeholders.PlaceholderInfo) {
+	v := scriptVarName(info.Name)
+
+	prompt := info.Prompt
+	if prompt == "" {
+		prompt = info.Name
+	}
+	if info.Default != "" && !info.Secret {
+		prompt += fmt.Sprintf(" [%s]", info.Default)
+	}
+	prompt += ": "
+
+	flags := "-r"
+	if info.Secret {
+		flags = "-rs"
+	}
+
+	fmt.Fprintf(b, "if [ -z \"${%s:-}\" ]; then\n", v)
+	if shell == ScriptZsh {
+		fmt.Fprintf(b, "  read %s %s\n", flags, shellQuote(v+"?"+prompt))
+	} else {
+		fmt.Fprintf(b, "  read %s -p %s %s\n", flags, shellQuote(prompt), v)
+	}
+	if info.Secret {
+		b.WriteString("  echo\n")
+	}
+	if info.Default != "" {
+		fmt.Fprintf(b, "  %s=\"${%s:-%s}\"\n", v, v, escapeDoubleQuoted(info.Default))
+	}
+	b.WriteString("fi\n")
+
+	if info.Validate != "" {
+		fmt.Fprintf(b, "re=%s\n", shellQuote(info.Validate))
+		fmt.Fprintf(b, "if ! [[ \"$%s\" =~ $re ]]; then\n", v)
+		fmt.Fprintf(b, "  echo %s >&2\n", shellQuote(fmt.Sprintf("Invalid value for <%s>: must match %s", info.Name, info.Validate)))
+		b.WriteString("  exit 1\n")
+		b.WriteString("fi\n")
+	}
+	fmt.Fprintf(b, "export %s\n", v)
+}
+
+// writeScriptStep writes one workflow step.
+func writeScriptStep(b *strings.Builder, shell ScriptShell, wf *workflows.Workflow, i int, step workflows.Step) {
+	name := step.Name
+	if name == "" {
+		name = fmt.Sprintf("Step %d", i+1)
+	}
+	fmt.Fprintf(b, "# Step %d: %s\n", i+1, name)
+	fmt.Fprintf(b, "echo %s\n", shellQuote(fmt.Sprintf("==> Step %d/%d: %s", i+1, len(wf.Steps), name)))
+
+	// Confirmations for dangerous commands and explicit step confirmations
+	if confirm := stepConfirmation(step); len(confirm) > 0 {
+		for _, line := range confirm {
+			fmt.Fprintf(b, "echo %s\n", shellQuote(line))
+		}
+		if shell == ScriptZsh {
+			b.WriteString("read -r \"yn?Continue? [y/N] \"\n")
+		} else {
+			b.WriteString("read -r -p \"Continue? [y/N] \" yn\n")
+		}
+		b.WriteString("case \"$yn\" in\n")
+		b.WriteString("  [Yy] | [Yy][Ee][Ss]) ;;\n")
+		fmt.Fprintf(b, "  *) echo \"Aborted.\" >&2; exit %d ;;\n", exitCanceled)
+		b.WriteString("esac\n")
+	}
+
+	command := scriptPlaceholderRegex.ReplaceAllStringFunc(step.Command, func(m string) string {
+		return "${" + scriptVarName(m[1:len(m)-1]) + "}"
+	})
+
+	// Run each step in a subshell so cwd and env don't leak between steps
+	var body []string
+	cwd := step.CWD
+	if cwd == "" {
+		cwd = wf.Defaults.CWD
+	}
+	if cwd != "" {
+		body = append(body, "cd "+scriptPath(cwd))
+	}
+	envKeys := make([]string, 0, len(step.Env))
+	for k := range step.Env {
+		envKeys = append(envKeys, k)
+	}
+	sort.Strings(envKeys)
+	for _, k := range envKeys {
+		body = append(body, fmt.Sprintf("export %s=%s", k, shellQuote(step.Env[k])))
+	}
+
+	stepShell := step.Shell
+	if stepShell == "" {
+		stepShell = wf.Defaults.Shell
+	}
+	if stepShell != "" && stepShell != string(shell) {
+		command = fmt.Sprintf("%s -c %s", stepShell, shellQuote(command))
+	}
+
+	b.WriteString("(\n")
+	for _, line := range body {
+		b.WriteString("  " + line + "\n")
+	}
+	// The command is written verbatim so heredocs keep working
+	b.WriteString(command + "\n")
+	if step.ContinueOnError {
+		fmt.Fprintf(b, ") || echo %s >&2\n\n", shellQuote(fmt.Sprintf("Step %d failed, continuing", i+1)))
+	} else {
+		b.WriteString(")\n\n")
+	}
+}
+
+// scriptPath double-quotes a path for cd, so a leading ~ and $VAR
+// references expand as they do when svf runs the step.
+func scriptPath(p string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "`", "\\`")
+	if p == "~" || strings.HasPrefix(p, "~/") {
+		return "\"$HOME" + r.Replace(p[1:]) + "\""
+	}
+	return "\"" + r.Replace(p) + "\""
+}
+
+// shellQuote single-quotes s for POSIX shells.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// escapeDoubleQuoted escapes s for use inside a double-quoted string.
+func escapeDoubleQuoted(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "`", "\\`")
+	return r.Replace(s)
+}
@@ -0,0 +1,131 @@
+package export
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+func scriptTestWorkflow() *workflows.Workflow {
+	return &workflows.Workflow{
+		Title: "Deploy",
+		Steps: []workflows.Step{
+			{Name: "greet", Command: "echo \"hello <user-name>\" > out.txt"},
+			{Name: "wipe", Command: "rm -rf /tmp/svf-script-test-<user-name>"},
+		},
+		Placeholders: map[string]workflows.Placeholder{
+			"user-name": {Prompt: "Who", Default: "world", Validate: "^[a-z]+$"},
+		},
+	}
+}
+
+func TestScript(t *testing.T) {
+	tests := []struct {
+		shell    ScriptShell
+		contains []string
+	}{
+		{ScriptBash, []string{
+			"#!/usr/bin/env bash",
+			"# Step 1: greet",
+			"read -r -p 'Who [world]: ' SVF_USER_NAME",
+			"echo \"hello ${SVF_USER_NAME}\" > out.txt",
+			"WARNING: Recursive delete detected",
+			"read -r -p \"Continue? [y/N] \" yn",
+		}},
+		{ScriptZsh, []string{
+			"#!/usr/bin/env zsh",
+			"read -r 'SVF_USER_NAME?Who [world]: '",
+			"read -r \"yn?Continue? [y/N] \"",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.shell), func(t *testing.T) {
+			script, err := Script(scriptTestWorkflow(), tt.shell)
+			if err != nil {
+				t.Fatalf("Script() error = %v", err)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(script, want) {
+					t.Errorf("expected script to contain %q\n%s", want, script)
+				}
+			}
+		})
+	}
+
+	if _, err := Script(scriptTestWorkflow(), "fish"); err == nil {
+		t.Error("expected error for unsupported shell")
+	}
+
+	// Placeholders that map to the same variable are rejected
+	wf := scriptTestWorkflow()
+	wf.Steps = append(wf.Steps, workflows.Step{Name: "copy", Command: "cp <user_name> /tmp"})
+	if _, err := Script(wf, ScriptBash); err == nil || !strings.Contains(err.Error(), "SVF_USER_NAME") {
+		t.Errorf("expected collision error, got %v", err)
+	}
+}
+
+func TestScriptPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"~", `"$HOME"`},
+		{"~/src/my app", `"$HOME/src/my app"`},
+		{"$GOPATH/src", `"$GOPATH/src"`},
+		{`/tmp/it's "here"`, `"/tmp/it's \"here\""`},
+	}
+	for _, tt := range tests {
+		if got := scriptPath(tt.path); got != tt.want {
+			t.Errorf("scriptPath(%q) = %s, want %s", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestScriptRuns(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash not available")
+	}
+
+	wf := scriptTestWorkflow()
+	wf.Steps = wf.Steps[:1]
+	script, err := Script(wf, ScriptBash)
+	if err != nil {
+		t.Fatalf("Script() error = %v", err)
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "run.sh")
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Accept the default value at the prompt
+	cmd := exec.Command("bash", path)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "SVF_USER_NAME=")
+	cmd.Stdin = strings.NewReader("\n")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("script failed: %v\n%s", err, out)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "out.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(string(data)) != "hello world" {
+		t.Errorf("expected 'hello world', got %q", data)
+	}
+
+	// Invalid values are rejected
+	cmd = exec.Command("bash", path)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "SVF_USER_NAME=Bad Value")
+	if err := cmd.Run(); err == nil {
+		t.Error("expected validation failure")
+	}
+}
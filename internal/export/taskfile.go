@@ -0,0 +1,168 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chazuruo/svf/internal/placeholders"
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/chazuruo/svf/internal/workflows/store"
+	"gopkg.in/yaml.v3"
+)
+
+// taskfileTask is a task in a Taskfile.
+type taskfileTask struct {
+	Desc        string            `yaml:"desc,omitempty"`
+	Summary     string            `yaml:"summary,omitempty"`
+	Internal    bool              `yaml:"internal,omitempty"`
+	Requires    *taskfileRequires `yaml:"requires,omitempty"`
+	Dir         string            `yaml:"dir,omitempty"`
+	Env         map[string]string `yaml:"env,omitempty"`
+	Prompt      string            `yaml:"prompt,omitempty"`
+	IgnoreError bool              `yaml:"ignore_error,omitempty"`
+	Cmds        []any             `yaml:"cmds"`
+}
+
+type taskfileRequires struct {
+	Vars []string `yaml:"vars"`
+}
+
+// taskfileCall is a command calling another task.
+type taskfileCall struct {
+	Task string `yaml:"task"`
+}
+
+// taskfileEscaper keeps Go template syntax already in commands literal.
+var taskfileEscaper = strings.NewReplacer("{{", `{{"{{"}}`)
+
+// Taskfile renders a workflow as a Taskfile.yml (https://taskfile.dev).
+// The workflow becomes a task named after its slug that calls an internal
+// task per step, so each step keeps its own directory, environment and
+// confirmation prompt. Placeholders become vars, set on the command line
+// with NAME=value; those without a default are required. Cleanup steps
+// become a separate cleanup task to run after a failed run.
+//
+// Like Ansible, only simple workflows can be converted.
+func Taskfile(wf *workflows.Workflow) (string, error) {
+	if err := checkConvertible(wf, "Taskfile"); err != nil {
+		return "", err
+	}
+
+	name := store.Slugify(wf.Title)
+	if name == "" {
+		name = "workflow"
+	}
+
+	infos := placeholders.ExtractAll(wf)
+	names := sortedPlaceholderNames(infos)
+	if err := checkVarNames(names, taskfileVarName); err != nil {
+		return "", err
+	}
+	vars := map[string]string{}
+	var required []string
+	for _, ph := range names {
+		info := infos[ph]
+		if info.Default == "" {
+			required = append(required, taskfileVarName(ph))
+			continue
+		}
+		vars[taskfileVarName(ph)] = taskfileEscaper.Replace(info.Default)
+	}
+
+	tasks := &yaml.Node{Kind: yaml.MappingNode}
+	addTask := func(name string, task taskfileTask) error {
+		var value yaml.Node
+		if err := value.Encode(task); err != nil {
+			return fmt.Errorf("failed to marshal YAML: %w", err)
+		}
+		tasks.Content = append(tasks.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name}, &value)
+		return nil
+	}
+
+	main := taskfileTask{
+		Desc:    wf.Title,
+		Summary: strings.TrimSpace(wf.Description),
+	}
+	if len(required) > 0 {
+		main.Requires = &taskfileRequires{Vars: required}
+	}
+	for i := range wf.Steps {
+		main.Cmds = append(main.Cmds, taskfileCall{Task: fmt.Sprintf("step-%d", i+1)})
+	}
+	if err := addTask(name, main); err != nil {
+		return "", err
+	}
+	for i, step := range wf.Steps {
+		if err := addTask(fmt.Sprintf("step-%d", i+1), taskfileStep(wf, i, step)); err != nil {
+			return "", err
+		}
+	}
+
+	if len(wf.Cleanup) > 0 {
+		cleanup := taskfileTask{Desc: "Clean up after a failed run of " + name}
+		for i := range wf.Cleanup {
+			cleanup.Cmds = append(cleanup.Cmds, taskfileCall{Task: fmt.Sprintf("cleanup-%d", i+1)})
+		}
+		if err := addTask("cleanup", cleanup); err != nil {
+			return "", err
+		}
+		for i, step := range wf.Cleanup {
+			task := taskfileStep(wf, i, step)
+			// Cleanup keeps going when one of its steps fails
+			task.IgnoreError = true
+			if err := addTask(fmt.Sprintf("cleanup-%d", i+1), task); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	doc := struct {
+		Version string            `yaml:"version"`
+		Vars    map[string]string `yaml:"vars,omitempty"`
+		Tasks   *yaml.Node        `yaml:"tasks"`
+	}{Version: "3", Vars: vars, Tasks: tasks}
+	body, err := marshalYAML(doc)
+	if err != nil {
+		return "", err
+	}
+	return convertedHeader(wf, "task "+name+" NAME=value") + "\n" + body, nil
+}
+
+// taskfileStep returns the internal task running one step.
+func taskfileStep(wf *workflows.Workflow, i int, step workflows.Step) taskfileTask {
+	command := taskfileTemplate(step.Command)
+	// Taskfile runs commands with its own POSIX shell interpreter
+	if shell := stepShell(wf, step); shell != "" && shell != "sh" {
+		command = fmt.Sprintf("%s -c %s", shell, shellQuote(command))
+	}
+
+	task := taskfileTask{
+		Desc:        stepName(i, step),
+		Internal:    true,
+		Dir:         taskfileTemplate(stepCWD(wf, step)),
+		IgnoreError: step.ContinueOnError,
+		Cmds:        []any{command},
+	}
+	for k, v := range step.Env {
+		if task.Env == nil {
+			task.Env = map[string]string{}
+		}
+		task.Env[k] = taskfileTemplate(v)
+	}
+	if confirm := stepConfirmation(step); len(confirm) > 0 {
+		task.Prompt = strings.Join(confirm, "\n") + "\nContinue?"
+	}
+	return task
+}
+
+// taskfileVarName converts a placeholder name to a Taskfile var name.
+func taskfileVarName(name string) string {
+	return strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// taskfileTemplate turns the <parameter> tokens in s into Taskfile vars.
+func taskfileTemplate(s string) string {
+	return scriptPlaceholderRegex.ReplaceAllStringFunc(taskfileEscaper.Replace(s), func(m string) string {
+		return "{{." + taskfileVarName(m[1:len(m)-1]) + "}}"
+	})
+}
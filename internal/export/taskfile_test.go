@@ -0,0 +1,48 @@
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestTaskfile(t *testing.T) {
+	taskfile, err := Taskfile(convertTestWorkflow())
+	if err != nil {
+		t.Fatalf("Taskfile() error = %v", err)
+	}
+	for _, want := range []string{
+		"# Run with: task deploy NAME=value",
+		"USER_NAME: world",
+		"requires:\n      vars:\n        - TOKEN",
+		"cmds:\n      - task: step-1\n      - task: step-2\n      - task: step-3",
+		`- echo "hello {{.USER_NAME}}" > out.txt`,
+		"prompt: |-\n      WARNING: Recursive delete detected",
+		`bash -c 'docker ps --format '\''{{"{{"}}.Names}}'\'' --filter name={{.TOKEN}}'`,
+		"dir: ~/src/{{.USER_NAME}}",
+		"OWNER: '{{.USER_NAME}}'",
+		"ignore_error: true",
+		"cleanup:\n    desc: Clean up after a failed run of deploy",
+	} {
+		if !strings.Contains(taskfile, want) {
+			t.Errorf("expected Taskfile to contain %q\n%s", want, taskfile)
+		}
+	}
+
+	var doc struct {
+		Version string
+		Tasks   yaml.Node
+	}
+	if err := yaml.Unmarshal([]byte(taskfile), &doc); err != nil {
+		t.Fatalf("Taskfile is not valid YAML: %v\n%s", err, taskfile)
+	}
+	var names []string
+	for i := 0; i < len(doc.Tasks.Content); i += 2 {
+		names = append(names, doc.Tasks.Content[i].Value)
+	}
+	want := "deploy step-1 step-2 step-3 cleanup cleanup-1"
+	if got := strings.Join(names, " "); doc.Version != "3" || got != want {
+		t.Errorf("version %q, tasks %q; want 3, %q", doc.Version, got, want)
+	}
+}
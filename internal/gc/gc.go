@@ -0,0 +1,374 @@
+// Package gc finds leftovers in a workflow repository: directories without
+// a workflow, files no workflow references, drafts nobody has touched in a
+// while, and run records of workflows that no longer exist.
+package gc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/runlog"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// Kind is a kind of leftover.
+type Kind string
+
+const (
+	// OrphanedDir is a directory under the workflow roots with no
+	// workflow file anywhere below it.
+	OrphanedDir Kind = "orphaned directory"
+	// UnreferencedFile is a file in a workflow directory that isn't the
+	// workflow, its README or tests, or one of its companion files.
+	UnreferencedFile Kind = "unreferenced file"
+	// StaleDraft is a draft workflow directory unchanged for longer than
+	// Options.DraftAge.
+	StaleDraft Kind = "stale draft"
+	// OrphanedRun is a run record of a workflow no longer in the repo.
+	OrphanedRun Kind = "orphaned run"
+)
+
+// Item is one leftover.
+type Item struct {
+	Kind Kind
+	// Path is repo-relative, or the run ID for OrphanedRun.
+	Path string
+	// Detail says why the item is a leftover, e.g. the draft's age.
+	Detail string
+}
+
+// InRepo reports whether removing the item changes the repository.
+func (i Item) InRepo() bool {
+	return i.Kind != OrphanedRun
+}
+
+// Options configures Find.
+type Options struct {
+	RepoPath string
+	Config   *config.Config
+
+	// DraftAge is how long a draft may go unchanged before it is stale.
+	// Zero disables the check.
+	DraftAge time.Duration
+
+	// Committed is when each file was last committed, by slash-separated
+	// repo-relative path. Drafts age from it, since checkouts reset
+	// modification times; files it lacks, like untracked drafts, age from
+	// their modification time.
+	Committed map[string]time.Time
+
+	// Runs is the run record store to check; nil skips run records.
+	Runs *runlog.Store
+
+	// Now is the current time (default: time.Now).
+	Now time.Time
+}
+
+// Find returns the leftovers in the repository, sorted by kind and path.
+func Find(opts Options) ([]Item, error) {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	f := &finder{opts: opts, ids: make(map[string]bool)}
+
+	for _, root := range []string{opts.Config.Workflows.Root, opts.Config.Workflows.SharedRoot} {
+		if err := f.scanRoot(root); err != nil {
+			return nil, err
+		}
+	}
+	if err := f.scanDrafts(opts.Config.Workflows.DraftRoot); err != nil {
+		return nil, err
+	}
+	if opts.Runs != nil {
+		if err := f.scanRuns(); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(f.items, func(i, j int) bool {
+		if f.items[i].Kind != f.items[j].Kind {
+			return kindOrder(f.items[i].Kind) < kindOrder(f.items[j].Kind)
+		}
+		return f.items[i].Path < f.items[j].Path
+	})
+	return f.items, nil
+}
+
+// kindOrder is the order kinds are listed in.
+func kindOrder(k Kind) int {
+	switch k {
+	case OrphanedDir:
+		return 0
+	case UnreferencedFile:
+		return 1
+	case StaleDraft:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// Remove deletes items: files and directories in the repository, and run
+// records from opts.Runs.
+func Remove(opts Options, items []Item) error {
+	for _, item := range items {
+		if item.Kind == OrphanedRun {
+			if opts.Runs == nil {
+				continue
+			}
+			if err := opts.Runs.Delete(item.Path); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(opts.RepoPath, item.Path)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// finder accumulates items while scanning.
+type finder struct {
+	opts  Options
+	items []Item
+	// ids are the IDs of every workflow found, for run records.
+	ids map[string]bool
+	// unparsed is set when a workflow file failed to load, so its IDs
+	// are unknown and run records can't be told orphaned.
+	unparsed bool
+}
+
+// add records an item at an absolute path.
+func (f *finder) add(kind Kind, path, detail string) {
+	rel, err := filepath.Rel(f.opts.RepoPath, path)
+	if err != nil {
+		rel = path
+	}
+	f.items = append(f.items, Item{Kind: kind, Path: filepath.ToSlash(rel), Detail: detail})
+}
+
+// scanRoot looks for orphaned directories and unreferenced files below a
+// repo-relative workflow root.
+func (f *finder) scanRoot(root string) error {
+	if root == "" {
+		return nil
+	}
+	dir := filepath.Join(f.opts.RepoPath, root)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			if err := f.scanDir(filepath.Join(dir, e.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// scanDir reports dir if it holds no workflow at all, checks the files of
+// a workflow directory, and otherwise descends.
+func (f *finder) scanDir(dir string) error {
+	has, err := hasWorkflow(dir)
+	if err != nil {
+		return err
+	}
+	if !has {
+		f.add(OrphanedDir, dir, "no workflow file")
+		return nil
+	}
+
+	if path := workflows.FindFile(dir); path != "" {
+		return f.scanWorkflowDir(dir, path)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			if err := f.scanDir(filepath.Join(dir, e.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// scanWorkflowDir reports the files of a workflow directory that nothing
+// references. Subdirectories holding their own workflow are scanned as
+// workflow directories in turn.
+func (f *finder) scanWorkflowDir(dir, path string) error {
+	keep := map[string]bool{
+		filepath.Base(path): true,
+		"README.md":         true,
+		workflows.TestsFile: true,
+	}
+	wfs, err := workflows.LoadAll(path)
+	if err != nil {
+		// Leave directories with broken workflows alone
+		f.unparsed = true
+		return nil
+	}
+	for _, wf := range wfs {
+		if wf.ID != "" {
+			f.ids[wf.ID] = true
+		}
+		for _, p := range wf.CompanionFiles() {
+			keep[filepath.FromSlash(p)] = true
+		}
+	}
+
+	return filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p == dir {
+				return nil
+			}
+			if sub := workflows.FindFile(p); sub != "" {
+				if err := f.scanWorkflowDir(p, sub); err != nil {
+					return err
+				}
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !keep[rel] && d.Name()[0] != '.' {
+			f.add(UnreferencedFile, p, "not referenced by "+filepath.Base(path))
+		}
+		return nil
+	})
+}
+
+// hasWorkflow reports whether dir or any directory below it holds a
+// workflow file.
+func hasWorkflow(dir string) (bool, error) {
+	found := false
+	err := filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && workflows.IsWorkflowFile(d.Name()) {
+			found = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return found, err
+}
+
+// scanDrafts reports draft workflow directories whose most recently
+// changed file changed longer than DraftAge ago. Drafts still count as existing workflows for run records.
+func (f *finder) scanDrafts(root string) error {
+	if root == "" {
+		return nil
+	}
+	dir := filepath.Join(f.opts.RepoPath, root)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil
+	}
+	return filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return err
+		}
+		path := workflows.FindFile(p)
+		if path == "" {
+			return nil
+		}
+		wfs, err := workflows.LoadAll(path)
+		if err != nil {
+			f.unparsed = true
+		}
+		for _, wf := range wfs {
+			if wf.ID != "" {
+				f.ids[wf.ID] = true
+			}
+		}
+
+		if f.opts.DraftAge > 0 {
+			modified, err := f.lastChange(p)
+			if err != nil {
+				return err
+			}
+			if age := f.opts.Now.Sub(modified); age > f.opts.DraftAge {
+				f.add(StaleDraft, p, "unchanged for "+formatDays(age))
+			}
+		}
+		return filepath.SkipDir
+	})
+}
+
+// lastChange returns when the files in dir last changed: when they were
+// last committed, or modified if they aren't in Committed.
+func (f *finder) lastChange(dir string) (time.Time, error) {
+	var newest time.Time
+	err := filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		changed, ok := time.Time{}, false
+		if rel, err := filepath.Rel(f.opts.RepoPath, p); err == nil {
+			changed, ok = f.opts.Committed[filepath.ToSlash(rel)]
+		}
+		if !ok {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			changed = info.ModTime()
+		}
+		if changed.After(newest) {
+			newest = changed
+		}
+		return nil
+	})
+	return newest, err
+}
+
+// scanRuns reports run records made in this repository for workflows
+// that no longer exist. Records from other repositories, or from before
+// records named their repository, are left alone. None are reported when
+// a workflow file failed to load, as its workflows may still exist.
+func (f *finder) scanRuns() error {
+	if f.unparsed {
+		return nil
+	}
+	records, err := f.opts.Runs.List()
+	if err != nil {
+		return err
+	}
+	repo := filepath.Clean(f.opts.RepoPath)
+	for _, r := range records {
+		if r.Repo == "" || filepath.Clean(r.Repo) != repo || r.WorkflowID == "" || f.ids[r.WorkflowID] {
+			continue
+		}
+		f.items = append(f.items, Item{Kind: OrphanedRun, Path: r.ID, Detail: r.WorkflowTitle + " no longer exists"})
+	}
+	return nil
+}
+
+// formatDays formats a duration in whole days.
+func formatDays(d time.Duration) string {
+	days := int(d.Hours() / 24)
+	if days == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", days)
+}
@@ -0,0 +1,171 @@
+package gc
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/runlog"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFind(t *testing.T) {
+	repoPath := t.TempDir()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	deploy := filepath.Join(repoPath, "workflows", "platform", "alice", "deploy")
+	writeFile(t, filepath.Join(deploy, "workflow.yaml"), "id: wf_deploy\ntitle: Deploy\nsteps:\n  - script: ./restart.sh\n")
+	writeFile(t, filepath.Join(deploy, "README.md"), "# Deploy\n")
+	writeFile(t, filepath.Join(deploy, "restart.sh"), "systemctl restart api\n")
+	writeFile(t, filepath.Join(deploy, "old.sh"), "echo old\n")
+	writeFile(t, filepath.Join(repoPath, "workflows", "platform", "alice", "renamed", "README.md"), "# Renamed\n")
+	if err := os.MkdirAll(filepath.Join(repoPath, "shared", "leftover"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	oldDraft := filepath.Join(repoPath, "drafts", "old-draft", "workflow.yaml")
+	writeFile(t, oldDraft, "id: wf_old\ntitle: Old\nsteps:\n  - command: true\n")
+	if err := os.Chtimes(oldDraft, now.AddDate(0, 0, -45), now.AddDate(0, 0, -45)); err != nil {
+		t.Fatal(err)
+	}
+	newDraft := filepath.Join(repoPath, "drafts", "new-draft", "workflow.yaml")
+	writeFile(t, newDraft, "title: New\nsteps:\n  - command: true\n")
+	if err := os.Chtimes(newDraft, now, now); err != nil {
+		t.Fatal(err)
+	}
+
+	runs := runlog.NewStore(t.TempDir())
+	for _, r := range []*runlog.Record{
+		{ID: "run_20260101T000000_00000000", WorkflowID: "wf_gone", WorkflowTitle: "Gone", Repo: repoPath},
+		{ID: "run_20260101T000001_00000001", WorkflowID: "wf_deploy", WorkflowTitle: "Deploy", Repo: repoPath},
+		{ID: "run_20260101T000002_00000002", WorkflowID: "wf_old", WorkflowTitle: "Old", Repo: repoPath},
+		{ID: "run_20260101T000003_00000003", WorkflowID: "wf_other", WorkflowTitle: "Other", Repo: "/elsewhere"},
+	} {
+		if err := runs.Save(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	opts := Options{
+		RepoPath: repoPath,
+		Config:   config.DefaultConfig(),
+		DraftAge: 30 * 24 * time.Hour,
+		Runs:     runs,
+		Now:      now,
+	}
+	items, err := Find(opts)
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+
+	want := []Item{
+		{Kind: OrphanedDir, Path: "shared/leftover", Detail: "no workflow file"},
+		{Kind: OrphanedDir, Path: "workflows/platform/alice/renamed", Detail: "no workflow file"},
+		{Kind: UnreferencedFile, Path: "workflows/platform/alice/deploy/old.sh", Detail: "not referenced by workflow.yaml"},
+		{Kind: StaleDraft, Path: "drafts/old-draft", Detail: "unchanged for 45 days"},
+		{Kind: OrphanedRun, Path: "run_20260101T000000_00000000", Detail: "Gone no longer exists"},
+	}
+	if !reflect.DeepEqual(items, want) {
+		t.Fatalf("Find() =\n%+v\nwant\n%+v", items, want)
+	}
+
+	if err := Remove(opts, items); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(deploy, "old.sh")); !os.IsNotExist(err) {
+		t.Errorf("old.sh was not removed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(deploy, "restart.sh")); err != nil {
+		t.Errorf("companion file was removed: %v", err)
+	}
+	if _, err := runs.Load("run_20260101T000000_00000000"); err == nil {
+		t.Error("orphaned run record was not removed")
+	}
+
+	items, err = Find(opts)
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	// Runs of the removed draft are orphaned now
+	want = []Item{{Kind: OrphanedRun, Path: "run_20260101T000002_00000002", Detail: "Old no longer exists"}}
+	if !reflect.DeepEqual(items, want) {
+		t.Errorf("Find() after Remove() = %+v, want %+v", items, want)
+	}
+}
+
+func TestFind_BrokenWorkflowKeepsRuns(t *testing.T) {
+	repoPath := t.TempDir()
+	writeFile(t, filepath.Join(repoPath, "workflows", "alice", "broken", "workflow.yaml"), "id: wf_broken\ntitle: [\n")
+
+	runs := runlog.NewStore(t.TempDir())
+	if err := runs.Save(&runlog.Record{ID: "run_20260101T000000_00000000", WorkflowID: "wf_broken", WorkflowTitle: "Broken", Repo: repoPath}); err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := Find(Options{RepoPath: repoPath, Config: config.DefaultConfig(), Runs: runs})
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if len(items) != 0 {
+		t.Errorf("Find() = %+v, want no items while a workflow fails to load", items)
+	}
+}
+
+// TestFind_DraftAgeFromCommits verifies that committed drafts age from
+// their last commit, whatever their modification time, and untracked ones
+// from their modification time.
+func TestFind_DraftAgeFromCommits(t *testing.T) {
+	repoPath := t.TempDir()
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	drafts := map[string]time.Time{
+		"checked-out": now,                    // freshly checked out, committed long ago
+		"edited":      now.AddDate(0, 0, -60), // committed long ago, with a new file
+		"untracked":   now.AddDate(0, 0, -45),
+	}
+	for name, modified := range drafts {
+		path := filepath.Join(repoPath, "drafts", name, "workflow.yaml")
+		writeFile(t, path, "title: "+name+"\nsteps:\n  - command: true\n")
+		if err := os.Chtimes(path, modified, modified); err != nil {
+			t.Fatal(err)
+		}
+	}
+	notes := filepath.Join(repoPath, "drafts", "edited", "notes.md")
+	writeFile(t, notes, "wip\n")
+	if err := os.Chtimes(notes, now, now); err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := Find(Options{
+		RepoPath: repoPath,
+		Config:   config.DefaultConfig(),
+		DraftAge: 30 * 24 * time.Hour,
+		Committed: map[string]time.Time{
+			"drafts/checked-out/workflow.yaml": now.AddDate(0, 0, -90),
+			"drafts/edited/workflow.yaml":      now.AddDate(0, 0, -60),
+		},
+		Now: now,
+	})
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+
+	want := []Item{
+		{Kind: StaleDraft, Path: "drafts/checked-out", Detail: "unchanged for 90 days"},
+		{Kind: StaleDraft, Path: "drafts/untracked", Detail: "unchanged for 45 days"},
+	}
+	if !reflect.DeepEqual(items, want) {
+		t.Errorf("Find() =\n%+v\nwant\n%+v", items, want)
+	}
+}
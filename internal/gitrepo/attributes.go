@@ -0,0 +1,45 @@
+package gitrepo
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// EnsureAttribute adds line to the .gitattributes file at the root of the
+// repository at repoPath, unless it is already there.
+func EnsureAttribute(repoPath, line string) error {
+	path := filepath.Join(repoPath, ".gitattributes")
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read .gitattributes: %w", err)
+	}
+	for _, existing := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(existing) == line {
+			return nil
+		}
+	}
+
+	content := string(data)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	content += line + "\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write .gitattributes: %w", err)
+	}
+	return nil
+}
+
+// SetConfig sets a git config value in the local config of the repository
+// at repoPath.
+func SetConfig(ctx context.Context, repoPath, key, value string) error {
+	cmd := exec.CommandContext(ctx, "git", "-C", repoPath, "config", "--local", key, value)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git config %s failed: %w: %s", key, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
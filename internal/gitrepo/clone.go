@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 )
 
 // CloneOptions contains options for cloning a repository.
@@ -61,3 +62,22 @@ func CloneWithResult(ctx context.Context, opts CloneOptions) (Repo, error) {
 
 	return New(opts.Path), nil
 }
+
+// CheckRemote verifies that remote can be reached and read with the
+// current credentials, without cloning it. Git is not allowed to prompt
+// for credentials, so an unauthenticated remote fails instead of hanging.
+func CheckRemote(ctx context.Context, remote string) error {
+	cmd := exec.CommandContext(ctx, "git", "ls-remote", "--heads", remote)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if os.Getenv("GIT_SSH_COMMAND") == "" {
+		cmd.Env = append(cmd.Env, "GIT_SSH_COMMAND=ssh -o BatchMode=yes")
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		msg := strings.TrimSpace(string(out))
+		if msg == "" {
+			msg = err.Error()
+		}
+		return fmt.Errorf("git ls-remote failed: %s", msg)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package gitrepo
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CodeownersPaths are where forges look for a CODEOWNERS file, relative to
+// the repository root, in the order they look.
+var CodeownersPaths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS", ".gitlab/CODEOWNERS"}
+
+// CodeownersRule is a line of a CODEOWNERS file: a path pattern and the
+// users, teams or emails owning the files it matches.
+type CodeownersRule struct {
+	Pattern string
+	Owners  []string
+}
+
+// ReadCodeowners reads the first CODEOWNERS file of the repository at
+// repoPath. ok is false if the repository has none.
+func ReadCodeowners(repoPath string) (rules []CodeownersRule, ok bool, err error) {
+	for _, name := range CodeownersPaths {
+		data, err := os.ReadFile(filepath.Join(repoPath, filepath.FromSlash(name)))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		return ParseCodeowners(string(data)), true, nil
+	}
+	return nil, false, nil
+}
+
+// ParseCodeowners parses the rules of a CODEOWNERS file, skipping
+// comments, blank lines and GitLab section headers.
+func ParseCodeowners(data string) []CodeownersRule {
+	var rules []CodeownersRule
+	for _, line := range strings.Split(data, "\n") {
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "[") || strings.HasPrefix(fields[0], "^[") {
+			continue
+		}
+		rules = append(rules, CodeownersRule{Pattern: fields[0], Owners: fields[1:]})
+	}
+	return rules
+}
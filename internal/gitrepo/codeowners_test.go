@@ -0,0 +1,22 @@
+package gitrepo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCodeowners(t *testing.T) {
+	got := ParseCodeowners(`# Owners review changes
+/workflows/platform/   @acme/platform @alice  # team and lead
+
+[Database]
+/shared/db/ dba@example.com
+`)
+	want := []CodeownersRule{
+		{Pattern: "/workflows/platform/", Owners: []string{"@acme/platform", "@alice"}},
+		{Pattern: "/shared/db/", Owners: []string{"dba@example.com"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseCodeowners() = %+v, want %+v", got, want)
+	}
+}
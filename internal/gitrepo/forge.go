@@ -0,0 +1,93 @@
+package gitrepo
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// ForgeKind identifies the hosting service of a remote.
+type ForgeKind string
+
+const (
+	// ForgeGitHub is GitHub or GitHub Enterprise.
+	ForgeGitHub ForgeKind = "github"
+	// ForgeGitLab is GitLab, hosted or self-managed.
+	ForgeGitLab ForgeKind = "gitlab"
+)
+
+// Forge is the web interface of a repository's remote.
+type Forge struct {
+	Kind ForgeKind
+	// BaseURL is the repository's web URL, e.g. https://github.com/org/repo.
+	BaseURL string
+}
+
+// ParseForge derives the web interface from a remote URL, in any of the
+// forms git accepts: https://host/org/repo.git, git@host:org/repo.git or
+// ssh://git@host:22/org/repo.git. The host must name GitHub or GitLab.
+func ParseForge(remoteURL string) (Forge, error) {
+	raw := strings.TrimSpace(remoteURL)
+	var host, repoPath string
+	if strings.Contains(raw, "://") {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return Forge{}, fmt.Errorf("invalid remote URL %q: %w", remoteURL, err)
+		}
+		host, repoPath = u.Hostname(), u.Path
+	} else if at := strings.Index(raw, ":"); at > 0 {
+		// scp-like syntax: [user@]host:org/repo.git
+		host, repoPath = raw[:at], raw[at+1:]
+		if i := strings.LastIndex(host, "@"); i >= 0 {
+			host = host[i+1:]
+		}
+	}
+	repoPath = strings.TrimSuffix(strings.Trim(repoPath, "/"), ".git")
+	if host == "" || repoPath == "" {
+		return Forge{}, fmt.Errorf("can't tell the repository from remote URL %q", remoteURL)
+	}
+
+	var kind ForgeKind
+	switch {
+	case strings.Contains(host, "github"):
+		kind = ForgeGitHub
+	case strings.Contains(host, "gitlab"):
+		kind = ForgeGitLab
+	default:
+		return Forge{}, fmt.Errorf("unsupported forge %s: only GitHub and GitLab remotes can be opened", host)
+	}
+	return Forge{Kind: kind, BaseURL: "https://" + host + "/" + repoPath}, nil
+}
+
+// FileURL returns the URL of file, relative to the repository root, on
+// branch.
+func (f Forge) FileURL(branch, file string) string {
+	p := path.Join("blob", branch, file)
+	if f.Kind == ForgeGitLab {
+		p = "-/" + p
+	}
+	return f.BaseURL + "/" + escapePath(p)
+}
+
+// PullRequestsURL returns the URL of the pull requests (merge requests on
+// GitLab) opened from branch, in any state.
+func (f Forge) PullRequestsURL(branch string) string {
+	if f.Kind == ForgeGitLab {
+		return f.BaseURL + "/-/merge_requests?" + url.Values{
+			"scope":         {"all"},
+			"state":         {"all"},
+			"source_branch": {branch},
+		}.Encode()
+	}
+	return f.BaseURL + "/pulls?" + url.Values{"q": {"is:pr head:" + branch}}.Encode()
+}
+
+// escapePath escapes each segment of a slash-separated path.
+func escapePath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,88 @@
+package gitrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ErrLockHeld is returned by PushLock when the lock ref already exists on
+// the remote.
+var ErrLockHeld = errors.New("lock is held")
+
+// lockFile is the file holding a lock's content in its commit.
+const lockFile = "lock.json"
+
+// lockRef returns the ref of the lock named name.
+func lockRef(name string) string {
+	return "refs/svf/locks/" + name
+}
+
+// PushLock takes a lock shared through the remote by pushing a commit
+// holding content to refs/svf/locks/<name>. Pushes never overwrite an
+// existing ref, so only one of several concurrent pushes succeeds.
+func (r *gitRepo) PushLock(ctx context.Context, remote, name string, content []byte) error {
+	blobFile, err := os.CreateTemp("", "svf-lock-")
+	if err != nil {
+		return fmt.Errorf("failed to create lock file: %w", err)
+	}
+	defer func() { _ = os.Remove(blobFile.Name()) }()
+	_, werr := blobFile.Write(content)
+	cerr := blobFile.Close()
+	if werr != nil || cerr != nil {
+		return fmt.Errorf("failed to write lock file: %w", errors.Join(werr, cerr))
+	}
+
+	_, blob, err := r.runGit(ctx, "hash-object", "-w", blobFile.Name())
+	if err != nil {
+		return err
+	}
+
+	// Build the tree in a throwaway index so the real one is untouched
+	indexFile := blobFile.Name() + ".index"
+	defer func() { _ = os.Remove(indexFile) }()
+	env := []string{"GIT_INDEX_FILE=" + indexFile}
+	if _, _, err := r.runGitEnv(ctx, env, "update-index", "--add", "--cacheinfo", "100644,"+strings.TrimSpace(blob)+","+lockFile); err != nil {
+		return err
+	}
+	_, tree, err := r.runGitEnv(ctx, env, "write-tree")
+	if err != nil {
+		return err
+	}
+	_, commit, err := r.runGit(ctx, "commit-tree", strings.TrimSpace(tree), "-m", "Lock "+name)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = r.runGit(ctx, "push", remote, strings.TrimSpace(commit)+":"+lockRef(name))
+	if err != nil && strings.Contains(err.Error(), "[rejected]") {
+		return fmt.Errorf("%s: %w", name, ErrLockHeld)
+	}
+	return err
+}
+
+// ReadLock fetches the lock named name from the remote and returns its
+// content.
+func (r *gitRepo) ReadLock(ctx context.Context, remote, name string) ([]byte, error) {
+	ref := lockRef(name)
+	if _, _, err := r.runGit(ctx, "fetch", remote, "+"+ref+":"+ref); err != nil {
+		if strings.Contains(err.Error(), "couldn't find remote ref") {
+			_, _, _ = r.runGit(ctx, "update-ref", "-d", ref)
+			return nil, fmt.Errorf("%s: %w", name, os.ErrNotExist)
+		}
+		return nil, err
+	}
+	return r.ShowFile(ctx, ref, lockFile)
+}
+
+// DeleteLock releases the lock named name on the remote.
+func (r *gitRepo) DeleteLock(ctx context.Context, remote, name string) error {
+	ref := lockRef(name)
+	if _, _, err := r.runGit(ctx, "push", remote, "--delete", ref); err != nil {
+		return err
+	}
+	_, _, _ = r.runGit(ctx, "update-ref", "-d", ref)
+	return nil
+}
@@ -0,0 +1,58 @@
+package gitrepo
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"testing"
+)
+
+func TestGitRepo_Locks(t *testing.T) {
+	ctx := context.Background()
+
+	remote := t.TempDir()
+	if out, err := exec.Command("git", "init", "--bare", remote).CombinedOutput(); err != nil {
+		t.Fatalf("git init --bare: %v: %s", err, out)
+	}
+	clone := func() Repo {
+		t.Helper()
+		dir := t.TempDir()
+		repo := New(dir)
+		if err := repo.Init(ctx, InitOptions{}); err != nil {
+			t.Fatalf("Init() error = %v", err)
+		}
+		setupGitConfig(dir)
+		if out, err := exec.Command("git", "-C", dir, "remote", "add", "origin", remote).CombinedOutput(); err != nil {
+			t.Fatalf("git remote add: %v: %s", err, out)
+		}
+		return repo
+	}
+	alice, bob := clone(), clone()
+
+	if _, err := alice.ReadLock(ctx, "origin", "failover"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("ReadLock() of a free lock error = %v, want not exist", err)
+	}
+	if err := alice.PushLock(ctx, "origin", "failover", []byte(`{"user":"alice"}`)); err != nil {
+		t.Fatalf("PushLock() error = %v", err)
+	}
+
+	// Bob can't take it, but sees who holds it
+	if err := bob.PushLock(ctx, "origin", "failover", []byte(`{"user":"bob"}`)); !errors.Is(err, ErrLockHeld) {
+		t.Fatalf("second PushLock() error = %v, want ErrLockHeld", err)
+	}
+	content, err := bob.ReadLock(ctx, "origin", "failover")
+	if err != nil || string(content) != `{"user":"alice"}` {
+		t.Errorf("ReadLock() = %q, %v", content, err)
+	}
+
+	if err := alice.DeleteLock(ctx, "origin", "failover"); err != nil {
+		t.Fatalf("DeleteLock() error = %v", err)
+	}
+	if _, err := bob.ReadLock(ctx, "origin", "failover"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("ReadLock() after DeleteLock error = %v, want not exist", err)
+	}
+	if err := bob.PushLock(ctx, "origin", "failover", []byte(`{"user":"bob"}`)); err != nil {
+		t.Errorf("PushLock() after DeleteLock error = %v", err)
+	}
+}
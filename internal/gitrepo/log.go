@@ -0,0 +1,164 @@
+package gitrepo
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FileChange is a change a commit made to a file.
+type FileChange struct {
+	// Path is slash-separated and relative to the repository.
+	Path string
+	Time time.Time
+
+	// Before and After are the file's content before and after the
+	// commit: Before is nil when the commit added the file and After when
+	// it deleted it.
+	Before, After []byte
+}
+
+// PathAuthors returns the distinct author emails of the commits touching
+// path, relative to the repository, most recent first.
+func (r *gitRepo) PathAuthors(ctx context.Context, path string) ([]string, error) {
+	_, out, err := r.runGit(ctx, "log", "--format=%ae", "--", path)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var authors []string
+	for _, email := range strings.Split(out, "\n") {
+		if email = strings.TrimSpace(email); email != "" && !seen[email] {
+			seen[email] = true
+			authors = append(authors, email)
+		}
+	}
+	return authors, nil
+}
+
+// PathBranches returns the branches, local or remote-tracking, with commits
+// touching path, relative to the repository, by their most recent such
+// commit. Remote-tracking branches are named without their remote, so a
+// branch that is both local and pushed appears once.
+func (r *gitRepo) PathBranches(ctx context.Context, path string) ([]string, error) {
+	_, out, err := r.runGit(ctx, "log", "--all", "--source", "--format=%S", "--", path)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var branches []string
+	for _, ref := range strings.Split(out, "\n") {
+		ref = strings.TrimSpace(ref)
+		var branch string
+		if b, ok := strings.CutPrefix(ref, "refs/heads/"); ok {
+			branch = b
+		} else if remote, ok := strings.CutPrefix(ref, "refs/remotes/"); ok {
+			if _, b, ok := strings.Cut(remote, "/"); ok && b != "HEAD" {
+				branch = b
+			}
+		}
+		if branch != "" && !seen[branch] {
+			seen[branch] = true
+			branches = append(branches, branch)
+		}
+	}
+	return branches, nil
+}
+
+// LastCommitTimes returns when each file under paths, relative to the
+// repository, was last committed, by slash-separated repo-relative path.
+// Uncommitted files are left out. One git log covers all the files, so
+// this is cheap for a whole directory tree.
+func (r *gitRepo) LastCommitTimes(ctx context.Context, paths ...string) (map[string]time.Time, error) {
+	args := append([]string{"-c", "core.quotePath=false", "log", "--format=%x00%ct", "--name-only", "--"}, paths...)
+	_, out, err := r.runGit(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	times := make(map[string]time.Time)
+	var committed time.Time
+	for _, line := range strings.Split(out, "\n") {
+		if ts, ok := strings.CutPrefix(line, "\x00"); ok {
+			if committed, err = parseCommitTime(ts); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		// Commits are newest first, so the first time seen is the last
+		if line = strings.TrimSpace(line); line != "" {
+			if _, ok := times[line]; !ok {
+				times[line] = committed
+			}
+		}
+	}
+	return times, nil
+}
+
+// FileChanges returns the changes commits made to the files named one of
+// names under dirs, relative to the repository, oldest first. Renames
+// count as a deletion and an addition.
+func (r *gitRepo) FileChanges(ctx context.Context, names []string, dirs ...string) ([]FileChange, error) {
+	args := append([]string{"-c", "core.quotePath=false", "log", "--reverse", "--no-renames",
+		"--format=%x00%ct", "--raw", "--no-abbrev", "--"}, dirs...)
+	_, out, err := r.runGit(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Files usually keep their content across many commits, so each blob
+	// is read once
+	blobs := make(map[string][]byte)
+	readBlob := func(hash string) ([]byte, error) {
+		if strings.Trim(hash, "0") == "" {
+			return nil, nil // The file doesn't exist on this side
+		}
+		if data, ok := blobs[hash]; ok {
+			return data, nil
+		}
+		_, content, err := r.runGit(ctx, "cat-file", "blob", hash)
+		if err != nil {
+			return nil, err
+		}
+		blobs[hash] = []byte(content)
+		return blobs[hash], nil
+	}
+
+	var changes []FileChange
+	var committed time.Time
+	for _, line := range strings.Split(out, "\n") {
+		if ts, ok := strings.CutPrefix(line, "\x00"); ok {
+			if committed, err = parseCommitTime(ts); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		// :<old mode> <new mode> <old blob> <new blob> <status>\t<path>
+		meta, file, ok := strings.Cut(line, "\t")
+		fields := strings.Fields(meta)
+		if !ok || len(fields) != 5 || !slices.Contains(names, path.Base(file)) {
+			continue
+		}
+		change := FileChange{Path: file, Time: committed}
+		if change.Before, err = readBlob(fields[2]); err != nil {
+			return nil, err
+		}
+		if change.After, err = readBlob(fields[3]); err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// parseCommitTime parses a commit time printed with %ct.
+func parseCommitTime(ts string) (time.Time, error) {
+	secs, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unexpected git log output %q", ts)
+	}
+	return time.Unix(secs, 0), nil
+}
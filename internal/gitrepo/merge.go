@@ -0,0 +1,194 @@
+package gitrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MergeOperation is the kind of integration a repository is in the middle of.
+type MergeOperation string
+
+const (
+	// OpNone means no merge or rebase is in progress.
+	OpNone MergeOperation = ""
+	// OpMerge means a merge is in progress.
+	OpMerge MergeOperation = "merge"
+	// OpRebase means a rebase is in progress.
+	OpRebase MergeOperation = "rebase"
+)
+
+// MergeState describes an unfinished merge or rebase.
+type MergeState struct {
+	// Operation is the merge or rebase in progress, or OpNone.
+	Operation MergeOperation
+	// Conflicts contains the paths that are still conflicted.
+	Conflicts []string
+}
+
+// InProgress reports whether a merge or rebase is in progress.
+func (s MergeState) InProgress() bool {
+	return s.Operation != OpNone
+}
+
+// ConflictDetails holds the versions of a conflicted path. A version is
+// nil when the path doesn't exist there, e.g. Base when both sides added
+// the path, or Theirs when they deleted it.
+type ConflictDetails struct {
+	// Path is the repo-relative path.
+	Path string
+	// Base is the common ancestor's version.
+	Base []byte
+	// Ours is the version on the branch being merged into.
+	Ours []byte
+	// Theirs is the version being merged in.
+	Theirs []byte
+	// Working is the working tree file, with conflict markers.
+	Working []byte
+}
+
+// Resolution is how ResolveFile resolves a conflicted path.
+type Resolution string
+
+const (
+	// ResolveOurs keeps our version.
+	ResolveOurs Resolution = "ours"
+	// ResolveTheirs takes their version.
+	ResolveTheirs Resolution = "theirs"
+	// ResolveManual keeps the working tree file as edited by the user.
+	ResolveManual Resolution = "manual"
+)
+
+// ErrNotConflicted is returned by GetConflictDetails for a path without
+// conflicts.
+var ErrNotConflicted = errors.New("path is not conflicted")
+
+// GetMergeState reports whether a merge or rebase is in progress.
+func (r *gitRepo) GetMergeState(ctx context.Context) (MergeState, error) {
+	var state MergeState
+
+	// A rebase stopped at a conflicting commit also has no MERGE_HEAD
+	for _, marker := range []struct {
+		path string
+		op   MergeOperation
+	}{
+		{"rebase-merge", OpRebase},
+		{"rebase-apply", OpRebase},
+		{"MERGE_HEAD", OpMerge},
+	} {
+		exists, err := r.gitPathExists(ctx, marker.path)
+		if err != nil {
+			return state, err
+		}
+		if exists {
+			state.Operation = marker.op
+			break
+		}
+	}
+
+	conflicts, err := r.GetConflicts(ctx)
+	if err != nil {
+		return state, err
+	}
+	state.Conflicts = conflicts
+	return state, nil
+}
+
+// gitPathExists reports whether a path inside the git directory exists.
+func (r *gitRepo) gitPathExists(ctx context.Context, name string) (bool, error) {
+	_, output, err := r.runGit(ctx, "rev-parse", "--git-path", name)
+	if err != nil {
+		return false, err
+	}
+	path := strings.TrimSpace(output)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(r.path, path)
+	}
+	_, err = os.Stat(path)
+	return err == nil, nil
+}
+
+// GetConflictDetails returns the versions of a conflicted path from the
+// index stages: 1 (base), 2 (ours) and 3 (theirs).
+func (r *gitRepo) GetConflictDetails(ctx context.Context, path string) (ConflictDetails, error) {
+	details := ConflictDetails{Path: path}
+
+	// Format: <mode> <object> <stage>\t<path>
+	_, output, err := r.runGit(ctx, "ls-files", "-u", "--", path)
+	if err != nil {
+		return details, err
+	}
+	output = strings.TrimSpace(output)
+	if output == "" {
+		return details, fmt.Errorf("%s: %w", path, ErrNotConflicted)
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		meta, _, _ := strings.Cut(line, "\t")
+		fields := strings.Fields(meta)
+		if len(fields) != 3 {
+			continue
+		}
+		_, content, err := r.runGit(ctx, "cat-file", "blob", fields[1])
+		if err != nil {
+			return details, err
+		}
+		switch fields[2] {
+		case "1":
+			details.Base = []byte(content)
+		case "2":
+			details.Ours = []byte(content)
+		case "3":
+			details.Theirs = []byte(content)
+		}
+	}
+
+	working, err := os.ReadFile(filepath.Join(r.path, path))
+	if err != nil && !os.IsNotExist(err) {
+		return details, err
+	}
+	details.Working = working
+	return details, nil
+}
+
+// ResolveFile resolves a conflicted path and stages it.
+func (r *gitRepo) ResolveFile(ctx context.Context, path string, resolution Resolution) error {
+	switch resolution {
+	case ResolveOurs, ResolveTheirs:
+		if _, _, err := r.runGit(ctx, "checkout", "--"+string(resolution), "--", path); err != nil {
+			return err
+		}
+	case ResolveManual:
+	default:
+		return fmt.Errorf("unknown resolution: %s", resolution)
+	}
+	_, _, err := r.runGit(ctx, "add", "--", path)
+	return err
+}
+
+// ContinueMerge commits the merge with git's prepared message.
+func (r *gitRepo) ContinueMerge(ctx context.Context) error {
+	_, _, err := r.runGit(ctx, "commit", "--no-edit")
+	return err
+}
+
+// ContinueRebase continues the rebase, keeping each commit's message.
+func (r *gitRepo) ContinueRebase(ctx context.Context) error {
+	_, _, err := r.runGitEnv(ctx, []string{"GIT_EDITOR=true"}, "rebase", "--continue")
+	return err
+}
+
+// AbortMerge abandons the merge.
+func (r *gitRepo) AbortMerge(ctx context.Context) error {
+	_, _, err := r.runGit(ctx, "merge", "--abort")
+	return err
+}
+
+// AbortRebase abandons the rebase.
+func (r *gitRepo) AbortRebase(ctx context.Context) error {
+	_, _, err := r.runGit(ctx, "rebase", "--abort")
+	return err
+}
@@ -0,0 +1,144 @@
+package gitrepo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// MergeFile merges the changes between base and theirs into ours with git
+// merge-file. It returns the result, with diff3-style conflict markers
+// labeled ours, base and theirs where the changes overlap, and whether
+// there were any.
+func MergeFile(ctx context.Context, ours, base, theirs []byte) ([]byte, bool, error) {
+	dir, err := os.MkdirTemp("", "svf-merge-")
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create merge directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	versions := []struct {
+		name    string
+		content []byte
+	}{{"ours", ours}, {"base", base}, {"theirs", theirs}}
+	args := []string{"merge-file", "-p", "--diff3"}
+	for _, v := range versions {
+		args = append(args, "-L", v.name)
+	}
+	for _, v := range versions {
+		path := filepath.Join(dir, v.name)
+		if err := os.WriteFile(path, v.content, 0600); err != nil {
+			return nil, false, fmt.Errorf("failed to write %s version: %w", v.name, err)
+		}
+		args = append(args, path)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+
+	// The exit status is the number of conflicts, or negative on errors
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 && exitErr.ExitCode() < 128 {
+		return stdout.Bytes(), true, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("git merge-file failed: %w: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return stdout.Bytes(), false, nil
+}
+
+// FileConflict is a conflicted file outside of a merge or rebase: three
+// versions kept in memory, with the merge result in a working file in a
+// directory of its own. It offers the conflict resolver what a repo does
+// during a merge, for a single file.
+type FileConflict struct {
+	dir     string
+	details ConflictDetails
+}
+
+// NewFileConflict writes the merge of ours and theirs, with conflict
+// markers, to the file name in dir.
+func NewFileConflict(ctx context.Context, dir, name string, base, ours, theirs []byte) (*FileConflict, error) {
+	merged, _, err := MergeFile(ctx, ours, base, theirs)
+	if err != nil {
+		return nil, err
+	}
+	c := &FileConflict{
+		dir: dir,
+		details: ConflictDetails{
+			Path:   name,
+			Base:   base,
+			Ours:   ours,
+			Theirs: theirs,
+		},
+	}
+	if err := c.write(merged); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Path returns the directory of the working file.
+func (c *FileConflict) Path() string {
+	return c.dir
+}
+
+// GetConflictDetails returns the versions of the file and its working
+// copy.
+func (c *FileConflict) GetConflictDetails(ctx context.Context, path string) (ConflictDetails, error) {
+	if path != c.details.Path {
+		return ConflictDetails{}, fmt.Errorf("%s: %w", path, ErrNotConflicted)
+	}
+	details := c.details
+	working, err := c.Working()
+	if err != nil {
+		return details, err
+	}
+	details.Working = working
+	return details, nil
+}
+
+// ResolveFile resolves the file by writing the chosen version to the
+// working file; a manual resolution keeps the working file as it is.
+func (c *FileConflict) ResolveFile(ctx context.Context, path string, resolution Resolution) error {
+	if path != c.details.Path {
+		return fmt.Errorf("%s: %w", path, ErrNotConflicted)
+	}
+	switch resolution {
+	case ResolveOurs:
+		return c.write(c.details.Ours)
+	case ResolveTheirs:
+		return c.write(c.details.Theirs)
+	case ResolveManual:
+		return nil
+	default:
+		return fmt.Errorf("unknown resolution: %s", resolution)
+	}
+}
+
+// Working returns the content of the working file.
+func (c *FileConflict) Working() ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(c.dir, c.details.Path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", c.details.Path, err)
+	}
+	return data, nil
+}
+
+func (c *FileConflict) write(content []byte) error {
+	path := filepath.Join(c.dir, c.details.Path)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", c.details.Path, err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package gitrepo
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestMergeFile(t *testing.T) {
+	ctx := context.Background()
+	base := []byte("one\ntwo\nthree\nfour\n")
+
+	merged, conflicts, err := MergeFile(ctx, []byte("ONE\ntwo\nthree\nfour\n"), base, []byte("one\ntwo\nthree\nFOUR\n"))
+	if err != nil {
+		t.Fatalf("MergeFile() error = %v", err)
+	}
+	if conflicts || string(merged) != "ONE\ntwo\nthree\nFOUR\n" {
+		t.Errorf("MergeFile() = %q, %v; want both changes without conflicts", merged, conflicts)
+	}
+
+	merged, conflicts, err = MergeFile(ctx, []byte("uno\ntwo\nthree\nfour\n"), base, []byte("eins\ntwo\nthree\nfour\n"))
+	if err != nil {
+		t.Fatalf("MergeFile() error = %v", err)
+	}
+	if !conflicts {
+		t.Error("MergeFile() reported no conflicts for overlapping changes")
+	}
+	for _, want := range []string{"<<<<<<< ours", "uno", "||||||| base", "one", ">>>>>>> theirs", "eins"} {
+		if !strings.Contains(string(merged), want) {
+			t.Errorf("merged = %q, want it to contain %q", merged, want)
+		}
+	}
+}
+
+func TestFileConflict(t *testing.T) {
+	ctx := context.Background()
+	base, ours, theirs := []byte("a\n"), []byte("b\n"), []byte("c\n")
+
+	c, err := NewFileConflict(ctx, t.TempDir(), "workflows/x/workflow.yaml", base, ours, theirs)
+	if err != nil {
+		t.Fatalf("NewFileConflict() error = %v", err)
+	}
+	details, err := c.GetConflictDetails(ctx, "workflows/x/workflow.yaml")
+	if err != nil {
+		t.Fatalf("GetConflictDetails() error = %v", err)
+	}
+	if !strings.Contains(string(details.Working), "<<<<<<<") {
+		t.Errorf("Working = %q, want conflict markers", details.Working)
+	}
+	if _, err := c.GetConflictDetails(ctx, "other.yaml"); err == nil {
+		t.Error("GetConflictDetails() of another file succeeded")
+	}
+
+	if err := c.ResolveFile(ctx, "workflows/x/workflow.yaml", ResolveTheirs); err != nil {
+		t.Fatalf("ResolveFile() error = %v", err)
+	}
+	if working, _ := c.Working(); string(working) != "c\n" {
+		t.Errorf("Working() after ResolveTheirs = %q", working)
+	}
+	if err := c.ResolveFile(ctx, "workflows/x/workflow.yaml", ResolveOurs); err != nil {
+		t.Fatalf("ResolveFile() error = %v", err)
+	}
+	if working, _ := c.Working(); string(working) != "b\n" {
+		t.Errorf("Working() after ResolveOurs = %q", working)
+	}
+}
@@ -9,9 +9,14 @@ package gitrepo
 import (
 	"context"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
+
+	svferrors "github.com/chazuruo/svf/internal/errors"
 )
 
 // gitRepo represents a Git repository.
@@ -47,6 +52,10 @@ type Repo interface {
 	// CommitAll commits all staged changes with the given message.
 	CommitAll(ctx context.Context, message string) (hash string, err error)
 
+	// AmendCommit replaces the last commit with one including the staged
+	// changes. An empty message keeps the last commit's message.
+	AmendCommit(ctx context.Context, message string) (hash string, err error)
+
 	// GetCurrentBranch returns the current branch name.
 	GetCurrentBranch(ctx context.Context) (string, error)
 
@@ -59,11 +68,112 @@ type Repo interface {
 	// GetConflicts returns the list of files with unresolved conflicts.
 	GetConflicts(ctx context.Context) ([]string, error)
 
+	// GetMergeState reports whether a merge or rebase is in progress and
+	// which paths are still conflicted.
+	GetMergeState(ctx context.Context) (MergeState, error)
+
+	// GetConflictDetails returns the base, ours, theirs and working tree
+	// versions of a conflicted path.
+	GetConflictDetails(ctx context.Context, path string) (ConflictDetails, error)
+
+	// ResolveFile resolves a conflicted path and stages it: ResolveOurs
+	// and ResolveTheirs check out that side, ResolveManual stages the
+	// working tree file as the user left it.
+	ResolveFile(ctx context.Context, path string, resolution Resolution) error
+
+	// ContinueMerge concludes a merge whose conflicts are all resolved.
+	ContinueMerge(ctx context.Context) error
+
+	// ContinueRebase continues a rebase whose conflicts are all resolved.
+	// It stops again, with MergeState conflicts, if a later commit
+	// conflicts.
+	ContinueRebase(ctx context.Context) error
+
+	// AbortMerge abandons a merge, restoring the pre-merge state.
+	AbortMerge(ctx context.Context) error
+
+	// AbortRebase abandons a rebase, restoring the original branch.
+	AbortRebase(ctx context.Context) error
+
 	// Fetch fetches changes from a remote.
 	Fetch(ctx context.Context, remote string) (FetchResult, error)
 
 	// Integrate integrates changes with the specified strategy.
 	Integrate(ctx context.Context, strategy IntegrateStrategy) (IntegrateResult, error)
+
+	// CreateBranch creates a branch at HEAD and switches to it.
+	CreateBranch(ctx context.Context, name string) error
+
+	// Checkout switches to an existing branch.
+	Checkout(ctx context.Context, name string) error
+
+	// Push pushes a branch to a remote and sets its upstream.
+	Push(ctx context.Context, remote, branch string) error
+
+	// AddWorktree creates a branch at start, or HEAD if start is empty,
+	// and checks it out in a new worktree at path, leaving the main
+	// checkout untouched.
+	AddWorktree(ctx context.Context, path, branch, start string) error
+
+	// RemoveWorktree removes a worktree, discarding uncommitted changes.
+	RemoveWorktree(ctx context.Context, path string) error
+
+	// PruneWorktrees removes bookkeeping for worktrees whose directories
+	// no longer exist.
+	PruneWorktrees(ctx context.Context) error
+
+	// ResolveRev resolves a revision (commit, branch, tag) to a commit hash.
+	ResolveRev(ctx context.Context, rev string) (string, error)
+
+	// MergeBase returns the best common ancestor of two revisions.
+	MergeBase(ctx context.Context, a, b string) (string, error)
+
+	// ShowFile returns the content of a repo-relative path at a revision.
+	// Returns an error wrapping os.ErrNotExist if the path doesn't exist
+	// at that revision.
+	ShowFile(ctx context.Context, rev, path string) ([]byte, error)
+
+	// ChangedFiles returns the repo-relative paths changed between two
+	// revisions.
+	ChangedFiles(ctx context.Context, from, to string) ([]string, error)
+
+	// ListFiles returns the repo-relative paths of the files under dir at
+	// a revision.
+	ListFiles(ctx context.Context, rev, dir string) ([]string, error)
+
+	// PathAuthors returns the distinct author emails of the commits
+	// touching path, most recent first.
+	PathAuthors(ctx context.Context, path string) ([]string, error)
+
+	// PathBranches returns the branches, local or remote-tracking, with
+	// commits touching path, by their most recent such commit.
+	PathBranches(ctx context.Context, path string) ([]string, error)
+
+	// LastCommitTimes returns when each file under paths was last
+	// committed, by slash-separated repo-relative path.
+	LastCommitTimes(ctx context.Context, paths ...string) (map[string]time.Time, error)
+
+	// FileChanges returns the changes commits made to the files named one
+	// of names under dirs, oldest first.
+	FileChanges(ctx context.Context, names []string, dirs ...string) ([]FileChange, error)
+
+	// Tag creates an annotated tag at HEAD.
+	Tag(ctx context.Context, name, message string) error
+
+	// ListTags returns the repository's tags, newest first.
+	ListTags(ctx context.Context) ([]string, error)
+
+	// PushLock takes the lock named name on a remote, recording content
+	// in it. Returns an error wrapping ErrLockHeld if it is already taken.
+	PushLock(ctx context.Context, remote, name string, content []byte) error
+
+	// ReadLock returns the content of the lock named name on a remote.
+	// Returns an error wrapping os.ErrNotExist if it isn't taken.
+	ReadLock(ctx context.Context, remote, name string) ([]byte, error)
+
+	// DeleteLock releases the lock named name on a remote, whoever took
+	// it.
+	DeleteLock(ctx context.Context, remote, name string) error
 }
 
 // FetchResult contains the result of a fetch operation.
@@ -110,8 +220,10 @@ type InitOptions struct {
 
 // Status represents the status of a Git repository.
 type Status struct {
-	// Branch is the current branch name.
+	// Branch is the current branch name, "(detached)" without one.
 	Branch string
+	// Detached is true if HEAD is not on a branch.
+	Detached bool
 	// Dirty is true if there are uncommitted changes.
 	Dirty bool
 	// Ahead is the number of commits ahead of upstream.
@@ -144,6 +256,8 @@ type GitError struct {
 	Err error
 	// ExitCode is the exit code from the Git command.
 	ExitCode int
+	// Output is what the Git command printed.
+	Output string
 }
 
 // Error returns the error message.
@@ -156,6 +270,62 @@ func (e *GitError) Unwrap() error {
 	return e.Err
 }
 
+// Reason returns Git's own explanation of the failure: the last "fatal:"
+// or "error:" line it printed, or else its last line of output.
+func (e *GitError) Reason() string {
+	var last, reason string
+	for _, line := range strings.Split(e.Output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		last = line
+		for _, prefix := range []string{"fatal: ", "error: "} {
+			if strings.HasPrefix(line, prefix) {
+				reason = strings.TrimPrefix(line, prefix)
+			}
+		}
+	}
+	switch {
+	case reason != "":
+		return reason
+	case last != "":
+		return last
+	}
+	return fmt.Sprintf("git %s failed with exit code %d", e.subcommand(), e.ExitCode)
+}
+
+// subcommand returns the Git subcommand that was run, e.g. "push".
+func (e *GitError) subcommand() string {
+	for _, arg := range e.Args {
+		if !strings.HasPrefix(arg, "-") {
+			return arg
+		}
+	}
+	return ""
+}
+
+// authFailures are what Git prints when a remote rejects or lacks
+// credentials.
+var authFailures = []string{
+	"Authentication failed",
+	"Permission denied (publickey",
+	"could not read Username",
+	"could not read Password",
+	"Invalid username or password",
+	"terminal prompts disabled",
+}
+
+// authFailed reports whether Git's output says credentials were rejected.
+func authFailed(output string) bool {
+	for _, s := range authFailures {
+		if strings.Contains(output, s) {
+			return true
+		}
+	}
+	return false
+}
+
 // New creates a new Repo instance for the given path.
 func New(path string) Repo {
 	return &gitRepo{
@@ -177,6 +347,11 @@ func (r *gitRepo) Close() error {
 
 // runGit executes a git command with the given arguments.
 func (r *gitRepo) runGit(ctx context.Context, args ...string) (*exec.Cmd, string, error) {
+	return r.runGitEnv(ctx, nil, args...)
+}
+
+// runGitEnv executes a git command with extra environment variables.
+func (r *gitRepo) runGitEnv(ctx context.Context, env []string, args ...string) (*exec.Cmd, string, error) {
 	cmdArgs := []string{}
 	if r.gitDir != "" {
 		cmdArgs = append(cmdArgs, "--git-dir="+r.gitDir)
@@ -188,6 +363,9 @@ func (r *gitRepo) runGit(ctx context.Context, args ...string) (*exec.Cmd, string
 
 	cmd := exec.CommandContext(ctx, "git", cmdArgs...)
 	cmd.Dir = r.path
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
 
 	output, err := cmd.CombinedOutput()
 	if err != nil {
@@ -195,11 +373,16 @@ func (r *gitRepo) runGit(ctx context.Context, args ...string) (*exec.Cmd, string
 		if ee, ok := err.(*exec.ExitError); ok {
 			exitCode = ee.ExitCode()
 		}
-		return cmd, "", &GitError{
+		gitErr := &GitError{
 			Args:     cmdArgs,
 			Err:      fmt.Errorf("%w: %s", err, string(output)),
 			ExitCode: exitCode,
+			Output:   string(output),
 		}
+		if authFailed(gitErr.Output) {
+			return cmd, "", &svferrors.AuthError{Service: "git", Err: gitErr}
+		}
+		return cmd, "", gitErr
 	}
 
 	return cmd, string(output), nil
@@ -251,6 +434,23 @@ func (r *gitRepo) CommitAll(ctx context.Context, message string) (string, error)
 	return strings.TrimSpace(hashOutput), nil
 }
 
+// AmendCommit amends the last commit with the staged changes.
+func (r *gitRepo) AmendCommit(ctx context.Context, message string) (string, error) {
+	args := []string{"commit", "--amend", "--no-edit"}
+	if message != "" {
+		args = []string{"commit", "--amend", "-m", message}
+	}
+	if _, _, err := r.runGit(ctx, args...); err != nil {
+		return "", err
+	}
+
+	_, hashOutput, err := r.runGit(ctx, "rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(hashOutput), nil
+}
+
 // GetCurrentBranch returns the current branch name.
 func (r *gitRepo) GetCurrentBranch(ctx context.Context) (string, error) {
 	_, output, err := r.runGit(ctx, "rev-parse", "--abbrev-ref", "HEAD")
@@ -260,6 +460,132 @@ func (r *gitRepo) GetCurrentBranch(ctx context.Context) (string, error) {
 	return strings.TrimSpace(output), nil
 }
 
+// CreateBranch creates a branch at HEAD and switches to it.
+func (r *gitRepo) CreateBranch(ctx context.Context, name string) error {
+	_, _, err := r.runGit(ctx, "checkout", "-b", name)
+	return err
+}
+
+// Checkout switches to an existing branch.
+func (r *gitRepo) Checkout(ctx context.Context, name string) error {
+	_, _, err := r.runGit(ctx, "checkout", name)
+	return err
+}
+
+// Push pushes a branch to a remote and sets its upstream.
+func (r *gitRepo) Push(ctx context.Context, remote, branch string) error {
+	_, _, err := r.runGit(ctx, "push", "--set-upstream", remote, branch)
+	return err
+}
+
+// AddWorktree creates a branch at start, or HEAD if start is empty, in a
+// new worktree at path. The branch doesn't track start.
+func (r *gitRepo) AddWorktree(ctx context.Context, path, branch, start string) error {
+	args := []string{"worktree", "add", "--no-track", "-b", branch, path}
+	if start != "" {
+		args = append(args, start)
+	}
+	_, _, err := r.runGit(ctx, args...)
+	return err
+}
+
+// RemoveWorktree removes a worktree, discarding uncommitted changes.
+func (r *gitRepo) RemoveWorktree(ctx context.Context, path string) error {
+	_, _, err := r.runGit(ctx, "worktree", "remove", "--force", path)
+	return err
+}
+
+// PruneWorktrees removes bookkeeping for worktrees that no longer exist.
+func (r *gitRepo) PruneWorktrees(ctx context.Context) error {
+	_, _, err := r.runGit(ctx, "worktree", "prune")
+	return err
+}
+
+// ResolveRev resolves a revision (commit, branch, tag) to a commit hash.
+func (r *gitRepo) ResolveRev(ctx context.Context, rev string) (string, error) {
+	_, output, err := r.runGit(ctx, "rev-parse", "--verify", rev+"^{commit}")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// MergeBase returns the best common ancestor of two revisions.
+func (r *gitRepo) MergeBase(ctx context.Context, a, b string) (string, error) {
+	_, output, err := r.runGit(ctx, "merge-base", a, b)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// ShowFile returns the content of a repo-relative path at a revision.
+func (r *gitRepo) ShowFile(ctx context.Context, rev, path string) ([]byte, error) {
+	// Distinguish a missing path from other failures
+	spec := rev + ":" + filepath.ToSlash(path)
+	if _, _, err := r.runGit(ctx, "cat-file", "-e", spec); err != nil {
+		if _, resolveErr := r.ResolveRev(ctx, rev); resolveErr != nil {
+			return nil, resolveErr
+		}
+		return nil, fmt.Errorf("%s: %w", spec, os.ErrNotExist)
+	}
+
+	_, output, err := r.runGit(ctx, "show", spec)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(output), nil
+}
+
+// ChangedFiles returns the repo-relative paths changed between two revisions.
+func (r *gitRepo) ChangedFiles(ctx context.Context, from, to string) ([]string, error) {
+	_, output, err := r.runGit(ctx, "diff", "--name-only", from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	output = strings.TrimSpace(output)
+	if output == "" {
+		return []string{}, nil
+	}
+	return strings.Split(output, "\n"), nil
+}
+
+// ListFiles returns the repo-relative paths of the files under dir at a
+// revision.
+func (r *gitRepo) ListFiles(ctx context.Context, rev, dir string) ([]string, error) {
+	_, output, err := r.runGit(ctx, "ls-tree", "-r", "--name-only", rev, "--", filepath.ToSlash(dir))
+	if err != nil {
+		return nil, err
+	}
+
+	output = strings.TrimSpace(output)
+	if output == "" {
+		return []string{}, nil
+	}
+	return strings.Split(output, "\n"), nil
+}
+
+// Tag creates an annotated tag at HEAD.
+func (r *gitRepo) Tag(ctx context.Context, name, message string) error {
+	_, _, err := r.runGit(ctx, "tag", "-a", name, "-m", message)
+	return err
+}
+
+// ListTags returns the repository's tags, newest first.
+func (r *gitRepo) ListTags(ctx context.Context) ([]string, error) {
+	_, output, err := r.runGit(ctx, "tag", "--list", "--sort=-creatordate")
+	if err != nil {
+		return nil, err
+	}
+
+	output = strings.TrimSpace(output)
+	if output == "" {
+		return []string{}, nil
+	}
+	return strings.Split(output, "\n"), nil
+}
+
 // GetConfig reads a git config value.
 func (r *gitRepo) GetConfig(ctx context.Context, key string) (string, error) {
 	_, output, err := r.runGit(ctx, "config", "--get", key)
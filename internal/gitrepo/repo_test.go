@@ -2,6 +2,8 @@ package gitrepo
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -510,6 +512,173 @@ func TestGitRepo_GetConflicts(t *testing.T) {
 	})
 }
 
+// conflictedRepo returns a repo in the middle of merging a branch that
+// changes test.txt from "base" to "theirs" into one that changed it to
+// "ours".
+func conflictedRepo(t *testing.T) (string, Repo) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	repo := New(tmpDir)
+	ctx := context.Background()
+
+	_ = repo.Init(ctx, InitOptions{})
+	setupGitConfig(tmpDir)
+	makeCommit(t, tmpDir, "test.txt", "base", "base commit")
+	base := getBranchName(t, tmpDir)
+
+	if err := repo.CreateBranch(ctx, "other"); err != nil {
+		t.Fatalf("CreateBranch() error = %v", err)
+	}
+	makeCommit(t, tmpDir, "test.txt", "theirs", "their commit")
+	if err := repo.Checkout(ctx, base); err != nil {
+		t.Fatalf("Checkout() error = %v", err)
+	}
+	makeCommit(t, tmpDir, "test.txt", "ours", "our commit")
+
+	cmd := exec.CommandContext(ctx, "git", "merge", "other")
+	cmd.Dir = tmpDir
+	_ = cmd.Run()
+	return tmpDir, repo
+}
+
+func TestGitRepo_ResolveFile(t *testing.T) {
+	tmpDir, repo := conflictedRepo(t)
+	ctx := context.Background()
+
+	if err := repo.ResolveFile(ctx, "test.txt", "sideways"); err == nil {
+		t.Error("ResolveFile(sideways) error = nil, want error")
+	}
+	if err := repo.ResolveFile(ctx, "test.txt", ResolveTheirs); err != nil {
+		t.Fatalf("ResolveFile() error = %v", err)
+	}
+
+	if has, _ := repo.HasConflicts(ctx); has {
+		t.Error("HasConflicts() = true after ResolveFile()")
+	}
+	data, err := os.ReadFile(filepath.Join(tmpDir, "test.txt"))
+	if err != nil || string(data) != "theirs" {
+		t.Errorf("test.txt = %q, %v; want %q", data, err, "theirs")
+	}
+}
+
+func TestGitRepo_ResolveFile_Manual(t *testing.T) {
+	tmpDir, repo := conflictedRepo(t)
+	ctx := context.Background()
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "test.txt"), []byte("both"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.ResolveFile(ctx, "test.txt", ResolveManual); err != nil {
+		t.Fatalf("ResolveFile() error = %v", err)
+	}
+	if err := repo.ContinueMerge(ctx); err != nil {
+		t.Fatalf("ContinueMerge() error = %v", err)
+	}
+
+	state, err := repo.GetMergeState(ctx)
+	if err != nil {
+		t.Fatalf("GetMergeState() error = %v", err)
+	}
+	if state.InProgress() || len(state.Conflicts) != 0 {
+		t.Errorf("GetMergeState() = %+v after ContinueMerge(), want no merge", state)
+	}
+	data, err := repo.ShowFile(ctx, "HEAD", "test.txt")
+	if err != nil || string(data) != "both" {
+		t.Errorf("HEAD:test.txt = %q, %v; want %q", data, err, "both")
+	}
+}
+
+func TestGitRepo_GetMergeState(t *testing.T) {
+	tmpDir, repo := conflictedRepo(t)
+	ctx := context.Background()
+
+	state, err := repo.GetMergeState(ctx)
+	if err != nil {
+		t.Fatalf("GetMergeState() error = %v", err)
+	}
+	if state.Operation != OpMerge || len(state.Conflicts) != 1 || state.Conflicts[0] != "test.txt" {
+		t.Errorf("GetMergeState() = %+v, want a merge conflicted on test.txt", state)
+	}
+
+	details, err := repo.GetConflictDetails(ctx, "test.txt")
+	if err != nil {
+		t.Fatalf("GetConflictDetails() error = %v", err)
+	}
+	if string(details.Base) != "base" || string(details.Ours) != "ours" || string(details.Theirs) != "theirs" {
+		t.Errorf("GetConflictDetails() = base %q, ours %q, theirs %q", details.Base, details.Ours, details.Theirs)
+	}
+	if !strings.Contains(string(details.Working), "<<<<<<<") {
+		t.Errorf("GetConflictDetails().Working = %q, want conflict markers", details.Working)
+	}
+
+	if err := repo.AbortMerge(ctx); err != nil {
+		t.Fatalf("AbortMerge() error = %v", err)
+	}
+	if state, _ := repo.GetMergeState(ctx); state.InProgress() {
+		t.Errorf("GetMergeState() = %+v after AbortMerge(), want no merge", state)
+	}
+	if _, err := repo.GetConflictDetails(ctx, "test.txt"); !errors.Is(err, ErrNotConflicted) {
+		t.Errorf("GetConflictDetails() error = %v, want ErrNotConflicted", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(tmpDir, "test.txt"))
+	if string(data) != "ours" {
+		t.Errorf("test.txt = %q after AbortMerge(), want %q", data, "ours")
+	}
+}
+
+func TestGitRepo_Rebase_ContinueAndAbort(t *testing.T) {
+	ctx := context.Background()
+
+	rebase := func(t *testing.T) (string, Repo) {
+		tmpDir, repo := conflictedRepo(t)
+		if err := repo.AbortMerge(ctx); err != nil {
+			t.Fatalf("AbortMerge() error = %v", err)
+		}
+		cmd := exec.CommandContext(ctx, "git", "rebase", "other")
+		cmd.Dir = tmpDir
+		_ = cmd.Run()
+
+		state, err := repo.GetMergeState(ctx)
+		if err != nil {
+			t.Fatalf("GetMergeState() error = %v", err)
+		}
+		if state.Operation != OpRebase || len(state.Conflicts) != 1 {
+			t.Fatalf("GetMergeState() = %+v, want a conflicted rebase", state)
+		}
+		return tmpDir, repo
+	}
+
+	t.Run("continue", func(t *testing.T) {
+		_, repo := rebase(t)
+		if err := repo.ContinueRebase(ctx); err == nil {
+			t.Error("ContinueRebase() with conflicts error = nil, want error")
+		}
+		if err := repo.ResolveFile(ctx, "test.txt", ResolveTheirs); err != nil {
+			t.Fatalf("ResolveFile() error = %v", err)
+		}
+		if err := repo.ContinueRebase(ctx); err != nil {
+			t.Fatalf("ContinueRebase() error = %v", err)
+		}
+		if state, _ := repo.GetMergeState(ctx); state.InProgress() {
+			t.Errorf("GetMergeState() = %+v after ContinueRebase(), want no rebase", state)
+		}
+	})
+
+	t.Run("abort", func(t *testing.T) {
+		tmpDir, repo := rebase(t)
+		if err := repo.AbortRebase(ctx); err != nil {
+			t.Fatalf("AbortRebase() error = %v", err)
+		}
+		if state, _ := repo.GetMergeState(ctx); state.InProgress() {
+			t.Errorf("GetMergeState() = %+v after AbortRebase(), want no rebase", state)
+		}
+		data, _ := os.ReadFile(filepath.Join(tmpDir, "test.txt"))
+		if string(data) != "ours" {
+			t.Errorf("test.txt = %q after AbortRebase(), want %q", data, "ours")
+		}
+	})
+}
+
 func TestGitRepo_Integrate_FFOnly(t *testing.T) {
 	remoteDir := setupTestRemote(t)
 	localDir := cloneFromRemote(t, remoteDir)
@@ -726,3 +895,399 @@ func TestGitRepo_Integrate_RebaseConflicts(t *testing.T) {
 	// Abort any in-progress rebase
 	_ = exec.CommandContext(ctx, "git", "rebase", "--abort").Run()
 }
+
+func TestGitRepo_CreateBranch_Checkout_Push(t *testing.T) {
+	remoteDir := setupTestRemote(t)
+	localDir := cloneFromRemote(t, remoteDir)
+
+	repo := New(localDir)
+	ctx := context.Background()
+
+	makeCommit(t, localDir, "test.txt", "content", "initial commit")
+	base := getBranchName(t, localDir)
+
+	if err := repo.CreateBranch(ctx, "feature/test"); err != nil {
+		t.Fatalf("CreateBranch() error = %v", err)
+	}
+	if got := getBranchName(t, localDir); got != "feature/test" {
+		t.Errorf("branch after CreateBranch() = %s, want feature/test", got)
+	}
+
+	makeCommit(t, localDir, "feature.txt", "feature", "feature commit")
+	if err := repo.Push(ctx, "origin", "feature/test"); err != nil {
+		t.Fatalf("Push() error = %v", err)
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "--verify", "refs/heads/feature/test")
+	cmd.Dir = remoteDir
+	if err := cmd.Run(); err != nil {
+		t.Errorf("remote is missing the pushed branch: %v", err)
+	}
+
+	if err := repo.Checkout(ctx, base); err != nil {
+		t.Fatalf("Checkout() error = %v", err)
+	}
+	if got := getBranchName(t, localDir); got != base {
+		t.Errorf("branch after Checkout() = %s, want %s", got, base)
+	}
+	if _, err := os.Stat(filepath.Join(localDir, "feature.txt")); !os.IsNotExist(err) {
+		t.Error("feature.txt should not exist on the base branch")
+	}
+}
+
+func TestGitRepo_Worktrees(t *testing.T) {
+	tmpDir := t.TempDir()
+	repo := New(tmpDir)
+	ctx := context.Background()
+
+	_ = repo.Init(ctx, InitOptions{})
+	setupGitConfig(tmpDir)
+	makeCommit(t, tmpDir, "test.txt", "content", "initial commit")
+	base := getBranchName(t, tmpDir)
+
+	wtDir := filepath.Join(t.TempDir(), "wt")
+	if err := repo.AddWorktree(ctx, wtDir, "feature/wt", ""); err != nil {
+		t.Fatalf("AddWorktree() error = %v", err)
+	}
+	if got := getBranchName(t, wtDir); got != "feature/wt" {
+		t.Errorf("worktree branch = %s, want feature/wt", got)
+	}
+	if got := getBranchName(t, tmpDir); got != base {
+		t.Errorf("main checkout branch = %s, want %s", got, base)
+	}
+
+	setupGitConfig(wtDir)
+	makeCommit(t, wtDir, "feature.txt", "feature", "feature commit")
+	if _, err := os.Stat(filepath.Join(tmpDir, "feature.txt")); !os.IsNotExist(err) {
+		t.Error("feature.txt should not appear in the main checkout")
+	}
+
+	if err := repo.RemoveWorktree(ctx, wtDir); err != nil {
+		t.Fatalf("RemoveWorktree() error = %v", err)
+	}
+	if _, err := os.Stat(wtDir); !os.IsNotExist(err) {
+		t.Error("worktree directory should be removed")
+	}
+	if _, err := repo.ShowFile(ctx, "feature/wt", "feature.txt"); err != nil {
+		t.Errorf("feature branch should keep its commit: %v", err)
+	}
+	if err := repo.PruneWorktrees(ctx); err != nil {
+		t.Errorf("PruneWorktrees() error = %v", err)
+	}
+
+	// A start point other than HEAD
+	startDir := filepath.Join(t.TempDir(), "start")
+	if err := repo.AddWorktree(ctx, startDir, "feature/start", "feature/wt"); err != nil {
+		t.Fatalf("AddWorktree() with start error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(startDir, "feature.txt")); err != nil {
+		t.Errorf("worktree should start from feature/wt: %v", err)
+	}
+	if err := repo.RemoveWorktree(ctx, startDir); err != nil {
+		t.Fatalf("RemoveWorktree() error = %v", err)
+	}
+}
+
+func TestGitRepo_ShowFile_ChangedFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	repo := New(tmpDir)
+	ctx := context.Background()
+
+	if err := repo.Init(ctx, InitOptions{}); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	setupGitConfig(tmpDir)
+
+	makeCommit(t, tmpDir, "a.txt", "one\n", "first")
+	first, err := repo.ResolveRev(ctx, "HEAD")
+	if err != nil {
+		t.Fatalf("ResolveRev() error = %v", err)
+	}
+	makeCommit(t, tmpDir, "a.txt", "two\n", "second")
+	makeCommit(t, tmpDir, "b.txt", "new\n", "third")
+
+	data, err := repo.ShowFile(ctx, first, "a.txt")
+	if err != nil || string(data) != "one\n" {
+		t.Errorf("ShowFile(first, a.txt) = %q, %v; want %q", data, err, "one\n")
+	}
+
+	if _, err := repo.ShowFile(ctx, first, "b.txt"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("ShowFile(first, b.txt) error = %v, want os.ErrNotExist", err)
+	}
+	if _, err := repo.ShowFile(ctx, "no-such-rev", "a.txt"); err == nil || errors.Is(err, os.ErrNotExist) {
+		t.Errorf("ShowFile(bad rev) error = %v, want a git error", err)
+	}
+
+	changed, err := repo.ChangedFiles(ctx, first, "HEAD")
+	if err != nil {
+		t.Fatalf("ChangedFiles() error = %v", err)
+	}
+	if strings.Join(changed, ",") != "a.txt,b.txt" {
+		t.Errorf("ChangedFiles() = %v, want [a.txt b.txt]", changed)
+	}
+}
+
+func TestPathBranches(t *testing.T) {
+	remoteDir := setupTestRemote(t)
+	localDir := cloneFromRemote(t, remoteDir)
+
+	repo := New(localDir)
+	ctx := context.Background()
+
+	// Commits in the same second would tie, so date them a day apart
+	t.Setenv("GIT_COMMITTER_DATE", "2024-01-01 12:00:00 +0000")
+	makeCommit(t, localDir, "deploy.yaml", "v1", "initial commit")
+	base := getBranchName(t, localDir)
+
+	// A pushed branch changing the file, then a newer one
+	for i, branch := range []string{"svf/old", "svf/new"} {
+		t.Setenv("GIT_COMMITTER_DATE", fmt.Sprintf("2024-01-0%d 12:00:00 +0000", i+2))
+		if err := repo.Checkout(ctx, base); err != nil {
+			t.Fatalf("Checkout() error = %v", err)
+		}
+		if err := repo.CreateBranch(ctx, branch); err != nil {
+			t.Fatalf("CreateBranch() error = %v", err)
+		}
+		makeCommit(t, localDir, "deploy.yaml", branch, "change on "+branch)
+		if err := repo.Push(ctx, "origin", branch); err != nil {
+			t.Fatalf("Push() error = %v", err)
+		}
+	}
+	// A branch that doesn't touch the file
+	if err := repo.Checkout(ctx, base); err != nil {
+		t.Fatalf("Checkout() error = %v", err)
+	}
+	if err := repo.CreateBranch(ctx, "svf/other"); err != nil {
+		t.Fatalf("CreateBranch() error = %v", err)
+	}
+	makeCommit(t, localDir, "other.txt", "x", "other change")
+
+	branches, err := repo.PathBranches(ctx, "deploy.yaml")
+	if err != nil {
+		t.Fatalf("PathBranches() error = %v", err)
+	}
+	if len(branches) != 3 || branches[0] != "svf/new" || branches[1] != "svf/old" || branches[2] != base {
+		t.Errorf("PathBranches() = %v, want [svf/new svf/old %s]", branches, base)
+	}
+}
+
+func TestLastCommitTimes(t *testing.T) {
+	tmpDir := t.TempDir()
+	repo := New(tmpDir)
+	ctx := context.Background()
+	if err := repo.Init(ctx, InitOptions{}); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	setupGitConfig(tmpDir)
+	if err := os.Mkdir(filepath.Join(tmpDir, "workflows"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("GIT_COMMITTER_DATE", "2024-01-01 12:00:00 +0000")
+	makeCommit(t, tmpDir, "workflows/a.yaml", "v1", "add a")
+	makeCommit(t, tmpDir, "workflows/b.yaml", "v1", "add b")
+	t.Setenv("GIT_COMMITTER_DATE", "2024-03-01 12:00:00 +0000")
+	makeCommit(t, tmpDir, "workflows/a.yaml", "v2", "change a")
+	makeCommit(t, tmpDir, "other.txt", "x", "outside the path")
+
+	times, err := repo.LastCommitTimes(ctx, "workflows")
+	if err != nil {
+		t.Fatalf("LastCommitTimes() error = %v", err)
+	}
+	want := map[string]string{"workflows/a.yaml": "2024-03-01", "workflows/b.yaml": "2024-01-01"}
+	if len(times) != len(want) {
+		t.Fatalf("LastCommitTimes() = %v, want %v", times, want)
+	}
+	for path, date := range want {
+		if got := times[path].UTC().Format("2006-01-02"); got != date {
+			t.Errorf("LastCommitTimes()[%s] = %s, want %s", path, got, date)
+		}
+	}
+}
+
+func TestFileChanges(t *testing.T) {
+	tmpDir := t.TempDir()
+	repo := New(tmpDir)
+	ctx := context.Background()
+	if err := repo.Init(ctx, InitOptions{}); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	setupGitConfig(tmpDir)
+	if err := os.Mkdir(filepath.Join(tmpDir, "workflows"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("GIT_COMMITTER_DATE", "2024-01-01 12:00:00 +0000")
+	makeCommit(t, tmpDir, "workflows/a.yaml", "v1", "add a")
+	makeCommit(t, tmpDir, "workflows/a.yaml", "v2", "change a")
+	t.Setenv("GIT_COMMITTER_DATE", "2024-03-01 12:00:00 +0000")
+	makeCommit(t, tmpDir, "workflows/b.yaml", "v1", "add b")
+	makeCommit(t, tmpDir, "workflows/notes.md", "x", "not a named file")
+	makeCommit(t, tmpDir, "other.txt", "x", "outside the path")
+	cmd := exec.Command("git", "rm", "-q", "workflows/a.yaml")
+	cmd.Dir = tmpDir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git rm: %v\n%s", err, out)
+	}
+	cmd = exec.Command("git", "commit", "-q", "-m", "remove a")
+	cmd.Dir = tmpDir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git commit: %v\n%s", err, out)
+	}
+
+	changes, err := repo.FileChanges(ctx, []string{"a.yaml", "b.yaml"}, "workflows")
+	if err != nil {
+		t.Fatalf("FileChanges() error = %v", err)
+	}
+	want := []string{
+		"workflows/a.yaml 2024-01-01 \"\" -> \"v1\"",
+		"workflows/a.yaml 2024-01-01 \"v1\" -> \"v2\"",
+		"workflows/b.yaml 2024-03-01 \"\" -> \"v1\"",
+		"workflows/a.yaml 2024-03-01 \"v2\" -> \"\"",
+	}
+	var got []string
+	for _, c := range changes {
+		got = append(got, fmt.Sprintf("%s %s %q -> %q", c.Path, c.Time.UTC().Format("2006-01-02"), c.Before, c.After))
+	}
+	if strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Errorf("FileChanges() = %v, want %v", got, want)
+	}
+	if changes[0].Before != nil || changes[3].After != nil {
+		t.Errorf("FileChanges() = %v, want no content before the addition and after the deletion", got)
+	}
+}
+
+func TestGitRepo_Tag_ListTags(t *testing.T) {
+	tmpDir := t.TempDir()
+	repo := New(tmpDir)
+	ctx := context.Background()
+
+	_ = repo.Init(ctx, InitOptions{})
+	setupGitConfig(tmpDir)
+
+	tags, err := repo.ListTags(ctx)
+	if err != nil || len(tags) != 0 {
+		t.Fatalf("ListTags() = %v, %v; want no tags", tags, err)
+	}
+
+	t.Setenv("GIT_COMMITTER_DATE", "2024-06-01 12:00:00 +0000")
+	makeCommit(t, tmpDir, "deploy.yaml", "v1", "first")
+	if err := repo.Tag(ctx, "v2024.06", "June"); err != nil {
+		t.Fatalf("Tag() error = %v", err)
+	}
+	t.Setenv("GIT_COMMITTER_DATE", "2024-07-01 12:00:00 +0000")
+	makeCommit(t, tmpDir, "deploy.yaml", "v2", "second")
+	if err := repo.Tag(ctx, "v2024.07", "July"); err != nil {
+		t.Fatalf("Tag() error = %v", err)
+	}
+	if err := repo.Tag(ctx, "v2024.07", "again"); err == nil {
+		t.Error("Tag() with an existing name should fail")
+	}
+
+	tags, err = repo.ListTags(ctx)
+	if err != nil {
+		t.Fatalf("ListTags() error = %v", err)
+	}
+	if len(tags) != 2 || tags[0] != "v2024.07" || tags[1] != "v2024.06" {
+		t.Errorf("ListTags() = %v, want [v2024.07 v2024.06]", tags)
+	}
+
+	data, err := repo.ShowFile(ctx, "v2024.06", "deploy.yaml")
+	if err != nil || string(data) != "v1" {
+		t.Errorf("ShowFile(v2024.06) = %q, %v; want v1", data, err)
+	}
+}
+
+func TestRemotesBranchesAt(t *testing.T) {
+	tmpDir := t.TempDir()
+	repo := New(tmpDir)
+	ctx := context.Background()
+	if err := repo.Init(ctx, InitOptions{}); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	setupGitConfig(tmpDir)
+	makeCommit(t, tmpDir, "a.txt", "1", "first")
+	base := getBranchName(t, tmpDir)
+	makeCommit(t, tmpDir, "a.txt", "2", "second")
+
+	remotes, err := Remotes(ctx, tmpDir)
+	if err != nil || len(remotes) != 0 {
+		t.Fatalf("Remotes() = %v, %v; want none", remotes, err)
+	}
+	if err := AddRemote(ctx, tmpDir, "origin", setupTestRemote(t)); err != nil {
+		t.Fatalf("AddRemote() error = %v", err)
+	}
+	if remotes, _ := Remotes(ctx, tmpDir); len(remotes) != 1 || remotes[0] != "origin" {
+		t.Errorf("Remotes() = %v, want [origin]", remotes)
+	}
+
+	// Detach HEAD at the first commit
+	cmd := exec.CommandContext(ctx, "git", "checkout", "--detach", "HEAD~1")
+	cmd.Dir = tmpDir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git checkout failed: %v: %s", err, out)
+	}
+	status, err := repo.Status(ctx)
+	if err != nil || !status.Detached {
+		t.Fatalf("Status() = %+v, %v; want detached", status, err)
+	}
+
+	if branches, _ := BranchesAt(ctx, tmpDir, "HEAD"); len(branches) != 0 {
+		t.Errorf("BranchesAt(HEAD) = %v, want none", branches)
+	}
+	if branches, _ := BranchesAt(ctx, tmpDir, base); len(branches) != 1 || branches[0] != base {
+		t.Errorf("BranchesAt(%s) = %v", base, branches)
+	}
+	if ok, err := IsAncestor(ctx, tmpDir, "HEAD", base); err != nil || !ok {
+		t.Errorf("IsAncestor(HEAD, %s) = %v, %v; want true", base, ok, err)
+	}
+	if ok, err := IsAncestor(ctx, tmpDir, base, "HEAD"); err != nil || ok {
+		t.Errorf("IsAncestor(%s, HEAD) = %v, %v; want false", base, ok, err)
+	}
+	if _, err := IsAncestor(ctx, tmpDir, "HEAD", "no-such-branch"); err == nil {
+		t.Error("IsAncestor() with a missing branch should fail")
+	}
+}
+
+func TestGitError_Reason(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *GitError
+		want string
+	}{
+		{
+			name: "last fatal line",
+			err:  &GitError{Args: []string{"push", "origin", "main"}, Output: "To origin\nerror: failed to push some refs\nfatal: the remote hung up\n"},
+			want: "the remote hung up",
+		},
+		{
+			name: "last line without prefix",
+			err:  &GitError{Args: []string{"commit"}, Output: "On branch main\nnothing to commit, working tree clean\n"},
+			want: "nothing to commit, working tree clean",
+		},
+		{
+			name: "no output",
+			err:  &GitError{Args: []string{"--git-dir=/x", "fetch"}, ExitCode: 128},
+			want: "git fetch failed with exit code 128",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Reason(); got != tt.want {
+				t.Errorf("Reason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthFailed(t *testing.T) {
+	if !authFailed("fatal: Authentication failed for 'https://example.com/repo.git/'") {
+		t.Error("authFailed() = false for a rejected password, want true")
+	}
+	if !authFailed("git@example.com: Permission denied (publickey).") {
+		t.Error("authFailed() = false for a rejected key, want true")
+	}
+	if authFailed("fatal: couldn't find remote ref main") {
+		t.Error("authFailed() = true for a missing ref, want false")
+	}
+}
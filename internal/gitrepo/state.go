@@ -0,0 +1,51 @@
+package gitrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Remotes returns the names of the remotes of the repository at repoPath.
+func Remotes(ctx context.Context, repoPath string) ([]string, error) {
+	out, err := exec.CommandContext(ctx, "git", "-C", repoPath, "remote").Output()
+	if err != nil {
+		return nil, fmt.Errorf("git remote failed: %w", err)
+	}
+	return strings.Fields(string(out)), nil
+}
+
+// AddRemote adds a remote called name fetching from url.
+func AddRemote(ctx context.Context, repoPath, name, url string) error {
+	out, err := exec.CommandContext(ctx, "git", "-C", repoPath, "remote", "add", name, url).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git remote add failed: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// BranchesAt returns the local branches pointing at rev.
+func BranchesAt(ctx context.Context, repoPath, rev string) ([]string, error) {
+	out, err := exec.CommandContext(ctx, "git", "-C", repoPath, "for-each-ref", "--format=%(refname:short)", "--points-at", rev, "refs/heads").Output()
+	if err != nil {
+		return nil, fmt.Errorf("git for-each-ref failed: %w", err)
+	}
+	return strings.Fields(string(out)), nil
+}
+
+// IsAncestor reports whether ancestor is reachable from rev, so switching
+// from ancestor to rev loses no commits.
+func IsAncestor(ctx context.Context, repoPath, ancestor, rev string) (bool, error) {
+	err := exec.CommandContext(ctx, "git", "-C", repoPath, "merge-base", "--is-ancestor", ancestor, rev).Run()
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.As(err, &exitErr) && exitErr.ExitCode() == 1:
+		return false, nil
+	default:
+		return false, fmt.Errorf("git merge-base failed: %w", err)
+	}
+}
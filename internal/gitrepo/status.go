@@ -30,6 +30,7 @@ func (r *gitRepo) Status(ctx context.Context) (Status, error) {
 
 		if strings.HasPrefix(line, "# branch.head ") {
 			status.Branch = strings.TrimPrefix(line, "# branch.head ")
+			status.Detached = status.Branch == "(detached)"
 		} else if strings.HasPrefix(line, "# branch.ab ") {
 			// Format: # branch.ab +<ahead> -<behind>
 			ab := strings.TrimPrefix(line, "# branch.ab ")
@@ -4,6 +4,7 @@ package history
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -11,12 +12,29 @@ import (
 	"time"
 )
 
-// Command represents a single command from shell history.
+// Command represents a single command from shell history. The metadata
+// fields are set where the source records them: zsh's extended history
+// has the duration but not the exit status, which only the svf record
+// hooks capture, along with the tmux session.
 type Command struct {
-	Timestamp int64     `json:"timestamp"`
-	CWD       string    `json:"cwd,omitempty"`
-	Command   string    `json:"command"`
-	Shell     string    `json:"shell"`
+	Timestamp  int64  `json:"timestamp"`
+	CWD        string `json:"cwd,omitempty"`
+	Command    string `json:"command"`
+	Shell      string `json:"shell"`
+	Duration   int64  `json:"duration,omitempty"`    // Seconds the command ran
+	ExitStatus *int   `json:"exit_status,omitempty"` // Nil when unknown
+	Host       string `json:"host,omitempty"`
+	Session    string `json:"session,omitempty"` // tmux session name
+}
+
+// Summary returns the command's first line, noting how many more lines a
+// multi-line command has, for one-line lists.
+func (c Command) Summary() string {
+	first, rest, multi := strings.Cut(c.Command, "\n")
+	if !multi {
+		return first
+	}
+	return fmt.Sprintf("%s … (+%d lines)", first, strings.Count(rest, "\n")+1)
 }
 
 // Parser parses shell history files.
@@ -38,14 +56,28 @@ func NewParser(shell string, limit int) *Parser {
 
 // Parse parses the history file for the configured shell.
 func (p *Parser) Parse() ([]Command, error) {
+	var commands []Command
+	var err error
 	switch p.shell {
 	case "bash":
-		return p.parseBash()
+		commands, err = p.parseBash()
 	case "zsh":
-		return p.parseZsh()
+		commands, err = p.parseZsh()
 	default:
 		return nil, fmt.Errorf("unsupported shell: %s (supported: bash, zsh)", p.shell)
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The history file is this host's. It is shared by every session of
+	// the shell, so the tmux session a command ran in isn't known.
+	if host, err := os.Hostname(); err == nil {
+		for i := range commands {
+			commands[i].Host = host
+		}
+	}
+	return commands, nil
 }
 
 // parseBash parses bash history files.
@@ -68,62 +100,79 @@ func (p *Parser) parseBash() ([]Command, error) {
 	}
 	defer func() { _ = file.Close() }()
 
+	commands, err := p.parseBashReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading bash history: %w", err)
+	}
+	return commands, nil
+}
+
+// parseBashReader parses bash history from r. Multi-line commands (saved
+// with shopt -s lithist) span several physical lines: with timestamps,
+// every line up to the next timestamp belongs to the entry; without them,
+// lines are joined while the command is incomplete, e.g. inside a heredoc
+// or quotes, or after a trailing backslash or pipe.
+func (p *Parser) parseBashReader(r io.Reader) ([]Command, error) {
 	var commands []Command
 	var currentTimestamp int64
-	scanner := bufio.NewScanner(file)
+	var current logicalCommand
+	timestamped := false
 
 	timestampRegex := regexp.MustCompile(`^#(\d+)$`)
 
+	// flush adds the current command, reporting whether the limit is hit
+	flush := func() bool {
+		cmd := strings.TrimSpace(current.String())
+		current.reset()
+		if cmd == "" || p.shouldSkipCommand(cmd) {
+			return false
+		}
+		commands = append(commands, Command{
+			Timestamp: currentTimestamp,
+			Command:   cmd,
+			Shell:     "bash",
+		})
+		return len(commands) >= p.limit
+	}
+
+	scanner := newScanner(r)
 	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
+		line := strings.TrimRight(scanner.Text(), "\r")
 
-		// Skip empty lines
-		if line == "" {
+		// A timestamp line starts a new entry
+		if matches := timestampRegex.FindStringSubmatch(strings.TrimSpace(line)); matches != nil && (timestamped || current.complete() || len(current.lines) == 0) {
+			if flush() {
+				return commands, nil
+			}
+			timestamped = true
+			if ts, err := parseTimestamp(matches[1]); err == nil {
+				currentTimestamp = ts
+			}
 			continue
 		}
 
-		// Check for timestamp line
-		if matches := timestampRegex.FindStringSubmatch(line); matches != nil {
-			var err error
-			currentTimestamp, err = parseTimestamp(matches[1])
-			if err != nil {
-				continue
-			}
+		// Skip blank lines and comments between commands
+		if trimmed := strings.TrimSpace(line); len(current.lines) == 0 && (trimmed == "" || strings.HasPrefix(trimmed, "#")) {
 			continue
 		}
 
-		// If we have a command, add it
-		if line != "" && !strings.HasPrefix(line, "#") {
-			// Skip built-ins and common non-useful commands
-			if p.shouldSkipCommand(line) {
-				continue
-			}
-
-			commands = append(commands, Command{
-				Timestamp: currentTimestamp,
-				Command:   line,
-				Shell:     "bash",
-			})
-
-			// Check limit
-			if len(commands) >= p.limit {
-				break
-			}
+		current.add(line)
+		if !timestamped && current.complete() && flush() {
+			return commands, nil
 		}
 	}
-
 	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading bash history: %w", err)
+		return nil, err
 	}
-
+	flush()
 	return commands, nil
 }
 
 // parseZsh parses zsh history files.
-// Zsh history format: :timestamp:duration;command
+// Zsh history format: : timestamp:duration;command
 // Example:
-//   :1616420000:0;ls -la
-//   :1616420100:0;git status
+//   : 1616420000:0;ls -la
+//   : 1616420100:0;git status
 func (p *Parser) parseZsh() ([]Command, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
@@ -137,66 +186,79 @@ func (p *Parser) parseZsh() ([]Command, error) {
 	}
 	defer func() { _ = file.Close() }()
 
+	commands, err := p.parseZshReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading zsh history: %w", err)
+	}
+	return commands, nil
+}
+
+// parseZshReader parses zsh history from r, in either the extended
+// (": timestamp:duration;command") or plain format. Zsh writes newlines
+// inside a command as a backslash at the end of the physical line, so
+// heredocs and backslash-continued commands are rejoined into one entry.
+func (p *Parser) parseZshReader(r io.Reader) ([]Command, error) {
 	var commands []Command
-	scanner := bufio.NewScanner(file)
+	var lines []string
+	var currentTimestamp, currentDuration int64
 
-	// Zsh history format: :timestamp:duration;command
-	// But can also have multi-line commands with backslash continuation
-	zshRegex := regexp.MustCompile(`^:(\d+):(\d+);(.*)`)
+	zshRegex := regexp.MustCompile(`^: *(\d+):(\d+);(.*)`)
 
-	var currentCmd strings.Builder
-	var currentTimestamp int64
+	// flush adds the current command, reporting whether the limit is hit
+	flush := func() bool {
+		cmd := strings.TrimSpace(strings.Join(lines, "\n"))
+		lines = nil
+		if cmd == "" || p.shouldSkipCommand(cmd) {
+			return false
+		}
+		commands = append(commands, Command{
+			Timestamp: currentTimestamp,
+			Command:   cmd,
+			Shell:     "zsh",
+			Duration:  currentDuration,
+		})
+		return len(commands) >= p.limit
+	}
 
+	continued := false
+	scanner := newScanner(r)
 	for scanner.Scan() {
-		line := scanner.Text()
-
-		// Check if this is a new history entry
-		if matches := zshRegex.FindStringSubmatch(line); matches != nil {
-			// Save previous command if exists
-			if currentCmd.Len() > 0 {
-				cmd := strings.TrimSpace(currentCmd.String())
-				if cmd != "" && !p.shouldSkipCommand(cmd) {
-					commands = append(commands, Command{
-						Timestamp: currentTimestamp,
-						Command:   cmd,
-						Shell:     "zsh",
-					})
-					if len(commands) >= p.limit {
-						break
-					}
-				}
-				currentCmd.Reset()
-			}
+		line := unmetafy(scanner.Text())
 
-			// Parse new entry
-			currentTimestamp, _ = parseTimestamp(matches[1])
-			currentCmd.WriteString(matches[3])
-		} else if currentCmd.Len() > 0 {
-			// Continuation of previous command (multi-line)
-			currentCmd.WriteString("\n")
-			currentCmd.WriteString(line)
+		if !continued {
+			if flush() {
+				return commands, nil
+			}
+			currentTimestamp, currentDuration = 0, 0
+			if matches := zshRegex.FindStringSubmatch(line); matches != nil {
+				currentTimestamp, _ = parseTimestamp(matches[1])
+				currentDuration, _ = parseTimestamp(matches[2])
+				line = matches[3]
+			}
 		}
-	}
 
-	// Don't forget the last command
-	if currentCmd.Len() > 0 && len(commands) < p.limit {
-		cmd := strings.TrimSpace(currentCmd.String())
-		if cmd != "" && !p.shouldSkipCommand(cmd) {
-			commands = append(commands, Command{
-				Timestamp: currentTimestamp,
-				Command:   cmd,
-				Shell:     "zsh",
-			})
+		// A trailing backslash escapes a newline inside the command
+		continued = strings.HasSuffix(line, "\\")
+		if continued {
+			line = line[:len(line)-1]
 		}
+		lines = append(lines, line)
 	}
-
 	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading zsh history: %w", err)
+		return nil, err
 	}
-
+	flush()
 	return commands, nil
 }
 
+// newScanner returns a line scanner that allows long entries such as
+// heredocs with large bodies.
+func newScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	return scanner
+}
+
 // shouldSkipCommand returns true if a command should be skipped.
 func (p *Parser) shouldSkipCommand(cmd string) bool {
 	cmd = strings.TrimSpace(cmd)
@@ -0,0 +1,180 @@
+package history
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func commandStrings(commands []Command) []string {
+	out := make([]string, len(commands))
+	for i, c := range commands {
+		out[i] = c.Command
+	}
+	return out
+}
+
+func TestParseBashReader(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "one command per line",
+			input: "git status\nmake build\n",
+			want:  []string{"git status", "make build"},
+		},
+		{
+			name:  "backslash continuation",
+			input: "docker run \\\n  --rm \\\n  alpine true\nmake test\n",
+			want:  []string{"docker run \\\n  --rm \\\n  alpine true", "make test"},
+		},
+		{
+			name:  "heredoc",
+			input: "cat <<EOF > /tmp/conf\nkey: value\n  nested: true\nEOF\nmake test\n",
+			want:  []string{"cat <<EOF > /tmp/conf\nkey: value\n  nested: true\nEOF", "make test"},
+		},
+		{
+			name:  "quoted heredoc with tabs",
+			input: "kubectl apply -f - <<-'YAML'\n\tkind: Pod\n\tYAML\n",
+			want:  []string{"kubectl apply -f - <<-'YAML'\n\tkind: Pod\n\tYAML"},
+		},
+		{
+			name:  "open quote and trailing pipe",
+			input: "echo 'one\ntwo'\ngrep foo file |\n  sort\n",
+			want:  []string{"echo 'one\ntwo'", "grep foo file |\n  sort"},
+		},
+		{
+			name:  "here-strings, shifts and comments aren't heredocs",
+			input: "cat <<< hi\necho $((1<<2))\necho done # trailing |\n# comment\n",
+			want:  []string{"cat <<< hi", "echo $((1<<2))", "echo done # trailing |"},
+		},
+		{
+			name:  "timestamps group lithist entries",
+			input: "#1616420000\nfor i in 1 2\ndo echo $i\ndone\n#1616420100\ngit status\n",
+			want:  []string{"for i in 1 2\ndo echo $i\ndone", "git status"},
+		},
+		{
+			name:  "skipped commands",
+			input: "cd /tmp\nls -la\nmake\n",
+			want:  []string{"make"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commands, err := NewParser("bash", 0).parseBashReader(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := commandStrings(commands); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBashReaderTimestamps(t *testing.T) {
+	input := "#1616420000\nmake build\n#1616420100\nmake test\n"
+	commands, err := NewParser("bash", 0).parseBashReader(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(commands) != 2 || commands[0].Timestamp != 1616420000 || commands[1].Timestamp != 1616420100 {
+		t.Errorf("unexpected commands: %+v", commands)
+	}
+}
+
+func TestParseZshReader(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "extended history",
+			input: ": 1616420000:0;git status\n: 1616420100:3;make build\n",
+			want:  []string{"git status", "make build"},
+		},
+		{
+			name:  "extended history without space",
+			input: ":1616420000:0;git status\n",
+			want:  []string{"git status"},
+		},
+		{
+			name:  "heredoc",
+			input: ": 1616420000:0;cat <<EOF > /tmp/conf\\\nkey: value\\\nEOF\n: 1616420100:0;make test\n",
+			want:  []string{"cat <<EOF > /tmp/conf\nkey: value\nEOF", "make test"},
+		},
+		{
+			name:  "backslash continuation",
+			input: ": 1616420000:0;docker run \\\\\n  --rm alpine true\n",
+			want:  []string{"docker run \\\n  --rm alpine true"},
+		},
+		{
+			name:  "plain history",
+			input: "git status\nfor i in 1 2; do\\\n  echo $i\\\ndone\n",
+			want:  []string{"git status", "for i in 1 2; do\n  echo $i\ndone"},
+		},
+		{
+			name:  "metafied bytes",
+			input: ": 1616420000:0;echo \xe3\x81\x83\xa2\n",
+			want:  []string{"echo \xe3\x81\x82"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commands, err := NewParser("zsh", 0).parseZshReader(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := commandStrings(commands); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseZshReaderLimit(t *testing.T) {
+	input := ": 1:0;make a\n: 2:0;make b\\\n--flag\n: 3:0;make c\n"
+	commands, err := NewParser("zsh", 2).parseZshReader(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"make a", "make b\n--flag"}
+	if got := commandStrings(commands); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if commands[1].Timestamp != 2 {
+		t.Errorf("expected timestamp 2, got %d", commands[1].Timestamp)
+	}
+}
+
+func TestParseZshReaderDuration(t *testing.T) {
+	input := ": 1616420000:0;git status\n: 1616420100:42;make build\ngit log\n"
+	commands, err := NewParser("zsh", 0).parseZshReader(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got []int64
+	for _, c := range commands {
+		got = append(got, c.Duration)
+	}
+	if want := []int64{0, 42, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got durations %v, want %v", got, want)
+	}
+	if commands[1].ExitStatus != nil {
+		t.Errorf("expected no exit status from zsh history, got %d", *commands[1].ExitStatus)
+	}
+}
+
+func TestCommandSummary(t *testing.T) {
+	if got := (Command{Command: "git status"}).Summary(); got != "git status" {
+		t.Errorf("got %q", got)
+	}
+	if got := (Command{Command: "cat <<EOF\nkey: value\nEOF"}).Summary(); got != "cat <<EOF … (+2 lines)" {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,181 @@
+package history
+
+import (
+	"strings"
+)
+
+// heredoc is a here-document whose body hasn't ended yet.
+type heredoc struct {
+	delim     string
+	stripTabs bool // <<- allows leading tabs before the delimiter
+}
+
+// logicalCommand collects the physical lines of one shell command and
+// reports when it is syntactically complete: no open quotes, no
+// unterminated heredocs, and no trailing backslash or |, && or ||.
+type logicalCommand struct {
+	lines    []string
+	quote    byte      // open quote character, if any
+	pending  []heredoc // heredocs started on the current line
+	heredocs []heredoc // heredocs whose bodies are being read
+	cont     bool      // last line ended with a continuation
+}
+
+// add appends a physical line and updates the parse state.
+func (c *logicalCommand) add(line string) {
+	c.lines = append(c.lines, line)
+
+	if len(c.heredocs) > 0 {
+		h := c.heredocs[0]
+		body := line
+		if h.stripTabs {
+			body = strings.TrimLeft(body, "\t")
+		}
+		if body == h.delim {
+			c.heredocs = c.heredocs[1:]
+		}
+		return
+	}
+
+	c.cont = false
+	end := c.scan(line)
+	if c.quote == 0 {
+		c.heredocs = append(c.heredocs, c.pending...)
+		c.pending = nil
+		if !c.cont {
+			trimmed := strings.TrimRight(line[:end], " \t")
+			c.cont = strings.HasSuffix(trimmed, "|") || strings.HasSuffix(trimmed, "&&")
+		}
+	}
+}
+
+// scan lexes one line for quotes, escapes, comments and heredoc operators,
+// returning where the line's code ends (before any comment).
+func (c *logicalCommand) scan(line string) int {
+	wordStart := true
+	for i := 0; i < len(line); i++ {
+		ch := line[i]
+		switch c.quote {
+		case '\'':
+			if ch == '\'' {
+				c.quote = 0
+			}
+			continue
+		case '"':
+			switch ch {
+			case '\\':
+				if i == len(line)-1 {
+					return len(line)
+				}
+				i++
+			case '"':
+				c.quote = 0
+			}
+			continue
+		}
+
+		switch ch {
+		case '\\':
+			if i == len(line)-1 {
+				c.cont = true
+				return len(line)
+			}
+			i++
+		case '\'', '"':
+			c.quote = ch
+		case '#':
+			if wordStart {
+				return i
+			}
+		case '<':
+			if strings.HasPrefix(line[i:], "<<<") {
+				i += 2
+			} else if strings.HasPrefix(line[i:], "<<") {
+				var h heredoc
+				var ok bool
+				if h, i, ok = parseHeredoc(line, i+2); ok {
+					c.pending = append(c.pending, h)
+				}
+			}
+		}
+		wordStart = ch == ' ' || ch == '\t' || ch == ';' || ch == '&' || ch == '|' || ch == '(' || ch == '`'
+	}
+	return len(line)
+}
+
+// parseHeredoc reads the delimiter of a heredoc operator whose "<<" ends
+// just before i, returning the index of the delimiter's last byte.
+func parseHeredoc(line string, i int) (heredoc, int, bool) {
+	var h heredoc
+	if i < len(line) && line[i] == '-' {
+		h.stripTabs = true
+		i++
+	}
+	for i < len(line) && (line[i] == ' ' || line[i] == '\t') {
+		i++
+	}
+
+	var delim strings.Builder
+	start := i
+	for i < len(line) {
+		ch := line[i]
+		if ch == '\'' || ch == '"' {
+			end := strings.IndexByte(line[i+1:], ch)
+			if end < 0 {
+				return h, start - 1, false
+			}
+			delim.WriteString(line[i+1 : i+1+end])
+			i += end + 2
+			continue
+		}
+		if ch == '\\' {
+			i++
+			continue
+		}
+		if strings.IndexByte(" \t;&|<>()", ch) >= 0 {
+			break
+		}
+		delim.WriteByte(ch)
+		i++
+	}
+
+	// Rule out arithmetic shifts like $((1<<2))
+	h.delim = delim.String()
+	if h.delim == "" || (h.delim[0] >= '0' && h.delim[0] <= '9') {
+		return h, start - 1, false
+	}
+	return h, i - 1, true
+}
+
+// complete reports whether the collected lines form a whole command.
+func (c *logicalCommand) complete() bool {
+	return len(c.lines) > 0 && c.quote == 0 && len(c.heredocs) == 0 && !c.cont
+}
+
+// String returns the command with its lines joined by newlines.
+func (c *logicalCommand) String() string {
+	return strings.Join(c.lines, "\n")
+}
+
+// reset clears the command for the next one.
+func (c *logicalCommand) reset() {
+	*c = logicalCommand{}
+}
+
+// unmetafy decodes zsh's history encoding, where bytes that are special to
+// zsh are written as 0x83 followed by the byte XOR 32.
+func unmetafy(line string) string {
+	if strings.IndexByte(line, 0x83) < 0 {
+		return line
+	}
+	b := make([]byte, 0, len(line))
+	for i := 0; i < len(line); i++ {
+		if line[i] == 0x83 && i+1 < len(line) {
+			i++
+			b = append(b, line[i]^32)
+			continue
+		}
+		b = append(b, line[i])
+	}
+	return string(b)
+}
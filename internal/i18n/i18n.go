@@ -0,0 +1,129 @@
+// Package i18n provides the message catalogs for svf's user-facing text.
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// Auto selects the locale from LC_ALL, LC_MESSAGES or LANG.
+	Auto = "auto"
+	// English is the default locale and the source of every message.
+	English = "en"
+	// Japanese is the Japanese locale.
+	Japanese = "ja"
+)
+
+// Catalog maps message IDs to fmt format strings.
+type Catalog map[string]string
+
+var catalogs = map[string]Catalog{
+	English:  english,
+	Japanese: japanese,
+}
+
+// aliases maps alternative names to locales.
+var aliases = map[string]string{
+	"english":  English,
+	"japanese": Japanese,
+	"jp":       Japanese,
+}
+
+var (
+	current      = English
+	currentMutex sync.RWMutex
+)
+
+// T returns the message with the given ID in the active locale, formatted
+// with args. Messages missing from the locale fall back to English, and
+// unknown IDs are returned as-is.
+func T(id string, args ...any) string {
+	currentMutex.RLock()
+	locale := current
+	currentMutex.RUnlock()
+
+	format, ok := catalogs[locale][id]
+	if !ok {
+		if format, ok = english[id]; !ok {
+			format = id
+		}
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Current returns the active locale.
+func Current() string {
+	currentMutex.RLock()
+	defer currentMutex.RUnlock()
+	return current
+}
+
+// Set makes locale the active locale. Unknown locales are ignored.
+func Set(locale string) {
+	if _, ok := catalogs[locale]; !ok {
+		return
+	}
+	currentMutex.Lock()
+	defer currentMutex.Unlock()
+	current = locale
+}
+
+// Locales returns the names of all available locales.
+func Locales() []string {
+	names := make([]string, 0, len(catalogs))
+	for name := range catalogs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Resolve picks the locale for the given config name. An empty name or
+// "auto" follows the environment, falling back to English. ok is false
+// when name is unknown, in which case English is returned.
+func Resolve(name string) (locale string, ok bool) {
+	if name == "" || name == Auto {
+		return fromEnv(), true
+	}
+	if locale, ok := normalize(name); ok {
+		return locale, true
+	}
+	return English, false
+}
+
+// fromEnv returns the locale named by the first set of LC_ALL,
+// LC_MESSAGES and LANG, as POSIX does, or English.
+func fromEnv() string {
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		value := os.Getenv(key)
+		if value == "" {
+			continue
+		}
+		if locale, ok := normalize(value); ok {
+			return locale
+		}
+		return English
+	}
+	return English
+}
+
+// normalize maps names like "ja", "ja_JP.UTF-8" or "japanese" to a
+// locale with a catalog.
+func normalize(name string) (string, bool) {
+	name = strings.ToLower(name)
+	if alias, ok := aliases[name]; ok {
+		return alias, true
+	}
+	if i := strings.IndexAny(name, "_-.@"); i >= 0 {
+		name = name[:i]
+	}
+	_, ok := catalogs[name]
+	return name, ok
+}
@@ -0,0 +1,105 @@
+package i18n
+
+import (
+	"regexp"
+	"testing"
+)
+
+// verbRegex matches fmt verbs, ignoring escaped percent signs.
+var verbRegex = regexp.MustCompile(`%[-+# 0]*[0-9]*(\.[0-9]+)?[a-zA-Z]`)
+
+func TestCatalogsMatchEnglish(t *testing.T) {
+	for _, locale := range Locales() {
+		for id, format := range catalogs[locale] {
+			source, ok := english[id]
+			if !ok {
+				t.Errorf("%s: message %q is not in the English catalog", locale, id)
+				continue
+			}
+			got := verbRegex.FindAllString(format, -1)
+			want := verbRegex.FindAllString(source, -1)
+			if len(got) != len(want) {
+				t.Errorf("%s: message %q has verbs %v, English has %v", locale, id, got, want)
+				continue
+			}
+			for i := range got {
+				if got[i] != want[i] {
+					t.Errorf("%s: message %q has verbs %v, English has %v", locale, id, got, want)
+					break
+				}
+			}
+		}
+	}
+
+	for id := range english {
+		if _, ok := japanese[id]; !ok {
+			t.Errorf("ja: missing message %q", id)
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	defer Set(English)
+
+	if got := T("runner.step", 2); got != "Step 2" {
+		t.Errorf("expected %q, got %q", "Step 2", got)
+	}
+
+	Set(Japanese)
+	if got := T("runner.step", 2); got != "ステップ 2" {
+		t.Errorf("expected %q, got %q", "ステップ 2", got)
+	}
+	if got := T("no.such.message"); got != "no.such.message" {
+		t.Errorf("expected unknown IDs to be returned as-is, got %q", got)
+	}
+
+	Set("xx")
+	if Current() != Japanese {
+		t.Errorf("expected unknown locales to be ignored, got %q", Current())
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		lang     string
+		expected string
+		ok       bool
+	}{
+		{"named locale", "ja", "", Japanese, true},
+		{"alias", "japanese", "", Japanese, true},
+		{"region and encoding", "ja_JP.UTF-8", "", Japanese, true},
+		{"unknown falls back", "fr", "", English, false},
+		{"auto follows LANG", Auto, "ja_JP.UTF-8", Japanese, true},
+		{"empty follows LANG", "", "ja_JP.UTF-8", Japanese, true},
+		{"auto with unknown LANG", Auto, "fr_FR.UTF-8", English, true},
+		{"auto without LANG", Auto, "", English, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("LC_ALL", "")
+			t.Setenv("LC_MESSAGES", "")
+			t.Setenv("LANG", tt.lang)
+
+			locale, ok := Resolve(tt.input)
+			if locale != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, locale)
+			}
+			if ok != tt.ok {
+				t.Errorf("expected ok=%v, got %v", tt.ok, ok)
+			}
+		})
+	}
+}
+
+func TestResolvePrefersLCAll(t *testing.T) {
+	t.Setenv("LC_ALL", "C")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "ja_JP.UTF-8")
+
+	if locale, _ := Resolve(Auto); locale != English {
+		t.Errorf("expected LC_ALL=C to select English, got %q", locale)
+	}
+}
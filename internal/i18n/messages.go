@@ -0,0 +1,236 @@
+package i18n
+
+// english is the source catalog. Every message ID used in svf must be
+// here; other catalogs may omit messages, which then show in English.
+var english = Catalog{
+	// Line prompts
+	"prompt.answer_yes_no":  "Please answer y or n.",
+	"prompt.unknown_choice": "Unknown choice %q.",
+
+	// Runner keys
+	"runner.key.run":          "run step",
+	"runner.key.skip":         "skip",
+	"runner.key.rerun":        "rerun",
+	"runner.key.quit":         "quit",
+	"runner.key.edit":         "edit step",
+	"runner.key.help":         "help",
+	"runner.key.placeholders": "placeholders",
+	"runner.key.confirm":      "confirm",
+	"runner.key.search":       "search log",
+	"runner.key.match":        "next/prev match",
+	"runner.key.copy":         "copy output",
+	"runner.key.flag_danger":  "flag as dangerous",
+	"runner.key.links":        "links",
+	"runner.key.pause":        "pause/resume",
+
+	// Runner
+	"runner.step":              "Step %d",
+	"runner.steps":             "Steps",
+	"runner.log":               "Log",
+	"runner.elapsed":           "Elapsed %s",
+	"runner.eta":               "ETA %s",
+	"runner.search":            "Search log...",
+	"runner.confirm_terminate": "Step still running — terminate? [y/N]",
+	"runner.terminating":       "Terminating step...",
+	"runner.match":             "Match %d/%d",
+	"runner.copy_empty":        "Step %d has no output to copy",
+	"runner.copy_failed":       "Copy failed: %v",
+	"runner.copied":            "Copied output of step %d",
+	"runner.flagged":           "Flagged step %d as dangerous; you can add a danger rule when the run ends",
+	"runner.succeeded":         "✓ Workflow completed successfully!",
+	"runner.canceled":          "Workflow canceled.",
+	"runner.failed":            "✗ Workflow failed.",
+	"runner.results":           "Step Results:",
+	"runner.exit":              "Press Enter to exit...",
+	"runner.skipped_platform":  "skipped (platform)",
+	"runner.links":             "Links (%d)",
+	"runner.links_expand":      "l to expand",
+	"runner.links_keys":        "↑/↓ select · o open · l close",
+	"runner.no_links":          "This step has no links",
+	"runner.link_opened":       "Opened %s",
+	"runner.link_failed":       "Failed to open %s in the browser",
+	"runner.over_budget":       "⚠ Taking longer than the expected %s",
+	"runner.hook_failed":       "Over-budget hook failed: %v",
+	"runner.paused":            "⏸ PAUSED — press space to resume",
+	"runner.pausing":           "⏸ PAUSED after this step — press space to resume",
+
+	// Placeholder values view
+	"values.title":   "Placeholder Values",
+	"values.name":    "Name",
+	"values.value":   "Value",
+	"values.not_set": "(not set)",
+	"values.footer":  "[Enter/Esc/P] Close",
+
+	// Run summary
+	"summary.title":        "Ready to run: %s",
+	"summary.steps":        "Steps: %d",
+	"summary.values":       "Values:",
+	"summary.guardrails":   "Target:",
+	"summary.dangerous":    "⚠ %d dangerous step(s): %s",
+	"summary.no_dangerous": "No dangerous steps.",
+	"summary.footer":       "[Enter] Start [Q/Esc] Cancel",
+	"summary.confirm":      "Start the run?",
+
+	// Placeholder prompting
+	"placeholders.title":     "Workflow Placeholders",
+	"placeholders.enter_for": "Enter value for <%s>",
+	"placeholders.enter":     "Enter value",
+	"placeholders.value_for": "Value for <%s>",
+	"placeholders.used_in":   "Used in: %s",
+	"placeholders.default":   "Default: %s",
+	"placeholders.error":     "Error: %s",
+	"placeholders.footer":    "[Enter] Submit (%d remaining) [Ctrl+C] Cancel",
+
+	// Presets
+	"presets.title":   "Workflow Presets",
+	"presets.footer":  "[1-9] Use preset [Enter] Enter values [Ctrl+C] Cancel",
+	"presets.list":    "Presets:",
+	"presets.ask":     "Preset number (blank to enter values)",
+	"presets.invalid": "Enter a number from 1 to %d.",
+
+	// Line runner
+	"line.step":        "Step %d/%d: %s",
+	"line.run":         "run",
+	"line.skip":        "skip",
+	"line.quit":        "quit",
+	"line.retry":       "retry",
+	"line.cwd_missing": "Working directory %s does not exist.",
+	"line.cwd_ask":     "Run in directory",
+	"line.step_ok":     "✓ Step %d succeeded (%s)",
+	"line.step_failed": "✗ Step %d failed with exit code %d",
+	"line.over_budget": "⚠ Step %d is taking longer than the expected %s",
+	"line.error":       "  Error: %v",
+
+	// Matrix dashboard
+	"matrix.title":     "%s — matrix (%d combinations)",
+	"matrix.running":   "running...",
+	"matrix.canceled":  "canceled",
+	"matrix.failed_at": "failed at step %d (exit code %d)",
+	"matrix.skipped":   "skipped",
+	"matrix.cancel":    "q: cancel",
+	"matrix.canceling": "Canceling...",
+
+	// svf run
+	"run.succeeded":          "✓ Workflow completed successfully",
+	"run.canceled":           "Workflow canceled",
+	"run.pipeline":           "Pipeline %d/%d: %s",
+	"run.pipeline_succeeded": "✓ Pipeline completed successfully",
+	"run.pipeline_continue":  "⚠ %s failed; continuing with the pipeline",
+}
+
+// japanese is the Japanese catalog.
+var japanese = Catalog{
+	// Line prompts
+	"prompt.answer_yes_no":  "y または n で答えてください。",
+	"prompt.unknown_choice": "不明な選択です: %q",
+
+	// Runner keys
+	"runner.key.run":          "ステップを実行",
+	"runner.key.skip":         "スキップ",
+	"runner.key.rerun":        "再実行",
+	"runner.key.quit":         "終了",
+	"runner.key.edit":         "ステップを編集",
+	"runner.key.help":         "ヘルプ",
+	"runner.key.placeholders": "プレースホルダー",
+	"runner.key.confirm":      "確定",
+	"runner.key.search":       "ログを検索",
+	"runner.key.match":        "次/前の一致",
+	"runner.key.copy":         "出力をコピー",
+	"runner.key.flag_danger":  "危険としてマーク",
+	"runner.key.links":        "リンク",
+	"runner.key.pause":        "一時停止/再開",
+
+	// Runner
+	"runner.step":              "ステップ %d",
+	"runner.steps":             "ステップ",
+	"runner.log":               "ログ",
+	"runner.elapsed":           "経過 %s",
+	"runner.eta":               "残り約 %s",
+	"runner.search":            "ログを検索...",
+	"runner.confirm_terminate": "ステップは実行中です — 終了しますか? [y/N]",
+	"runner.terminating":       "ステップを終了しています...",
+	"runner.match":             "一致 %d/%d",
+	"runner.copy_empty":        "ステップ %d にはコピーできる出力がありません",
+	"runner.copy_failed":       "コピーに失敗しました: %v",
+	"runner.copied":            "ステップ %d の出力をコピーしました",
+	"runner.flagged":           "ステップ %d を危険としてマークしました。実行終了後に危険ルールを追加できます",
+	"runner.succeeded":         "✓ ワークフローが正常に完了しました!",
+	"runner.canceled":          "ワークフローはキャンセルされました。",
+	"runner.failed":            "✗ ワークフローが失敗しました。",
+	"runner.results":           "ステップの結果:",
+	"runner.exit":              "Enter キーで終了...",
+	"runner.skipped_platform":  "スキップ (プラットフォーム)",
+	"runner.links":             "リンク (%d)",
+	"runner.links_expand":      "l で展開",
+	"runner.links_keys":        "↑/↓ 選択 · o 開く · l 閉じる",
+	"runner.no_links":          "このステップにはリンクがありません",
+	"runner.link_opened":       "%s を開きました",
+	"runner.link_failed":       "%s をブラウザで開けませんでした",
+	"runner.over_budget":       "⚠ 想定時間 %s を超えています",
+	"runner.hook_failed":       "超過時フックが失敗しました: %v",
+	"runner.paused":            "⏸ 一時停止中 — スペースで再開",
+	"runner.pausing":           "⏸ このステップの後で一時停止 — スペースで再開",
+
+	// Placeholder values view
+	"values.title":   "プレースホルダーの値",
+	"values.name":    "名前",
+	"values.value":   "値",
+	"values.not_set": "(未設定)",
+	"values.footer":  "[Enter/Esc/P] 閉じる",
+
+	// Run summary
+	"summary.title":        "実行の準備完了: %s",
+	"summary.steps":        "ステップ数: %d",
+	"summary.values":       "値:",
+	"summary.guardrails":   "対象:",
+	"summary.dangerous":    "⚠ 危険なステップ %d 件: %s",
+	"summary.no_dangerous": "危険なステップはありません。",
+	"summary.footer":       "[Enter] 開始 [Q/Esc] キャンセル",
+	"summary.confirm":      "実行を開始しますか?",
+
+	// Placeholder prompting
+	"placeholders.title":     "ワークフローのプレースホルダー",
+	"placeholders.enter_for": "<%s> の値を入力してください",
+	"placeholders.enter":     "値を入力",
+	"placeholders.value_for": "<%s> の値",
+	"placeholders.used_in":   "使用箇所: %s",
+	"placeholders.default":   "デフォルト: %s",
+	"placeholders.error":     "エラー: %s",
+	"placeholders.footer":    "[Enter] 確定 (残り %d) [Ctrl+C] キャンセル",
+
+	// Presets
+	"presets.title":   "ワークフローのプリセット",
+	"presets.footer":  "[1-9] プリセットを使用 [Enter] 値を入力 [Ctrl+C] キャンセル",
+	"presets.list":    "プリセット:",
+	"presets.ask":     "プリセット番号 (空欄で値を入力)",
+	"presets.invalid": "1 から %d までの番号を入力してください。",
+
+	// Line runner
+	"line.step":        "ステップ %d/%d: %s",
+	"line.run":         "実行",
+	"line.skip":        "スキップ",
+	"line.quit":        "終了",
+	"line.retry":       "再試行",
+	"line.cwd_missing": "作業ディレクトリ %s が存在しません。",
+	"line.cwd_ask":     "実行するディレクトリ",
+	"line.step_ok":     "✓ ステップ %d が成功しました (%s)",
+	"line.step_failed": "✗ ステップ %d が終了コード %d で失敗しました",
+	"line.over_budget": "⚠ ステップ %d が想定時間 %s を超えています",
+	"line.error":       "  エラー: %v",
+
+	// Matrix dashboard
+	"matrix.title":     "%s — マトリックス (%d 通り)",
+	"matrix.running":   "実行中...",
+	"matrix.canceled":  "キャンセル",
+	"matrix.failed_at": "ステップ %d で失敗 (終了コード %d)",
+	"matrix.skipped":   "スキップ",
+	"matrix.cancel":    "q: キャンセル",
+	"matrix.canceling": "キャンセルしています...",
+
+	// svf run
+	"run.succeeded":          "✓ ワークフローが正常に完了しました",
+	"run.canceled":           "ワークフローはキャンセルされました",
+	"run.pipeline":           "パイプライン %d/%d: %s",
+	"run.pipeline_succeeded": "✓ パイプラインが正常に完了しました",
+	"run.pipeline_continue":  "⚠ %s が失敗しました。パイプラインを続行します",
+}
@@ -0,0 +1,246 @@
+// Package importer converts runbooks written in other formats into
+// workflows.
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// BlockKind says how a fenced code block is imported.
+type BlockKind int
+
+const (
+	// BlockCommand is a shell block that becomes a step.
+	BlockCommand BlockKind = iota
+	// BlockAmbiguous has no language, or a plain text one; it may be
+	// commands or output, and is imported only if the user says so.
+	BlockAmbiguous
+	// BlockOther is code in another language (YAML, JSON, Python...),
+	// which is left out.
+	BlockOther
+)
+
+// Block is a fenced code block in a Markdown runbook.
+type Block struct {
+	// Heading is the closest heading above the block, if any.
+	Heading string
+	// Lang is the first word of the fence's info string, lowercased.
+	Lang string
+	// Code is the block's content, without the fences.
+	Code string
+	// Line is the 1-based line of the opening fence.
+	Line int
+	// Kind is how the block is imported, from its language.
+	Kind BlockKind
+}
+
+// Runbook is a parsed Markdown runbook.
+type Runbook struct {
+	// Title is the text of the first level-1 heading.
+	Title string
+	// Description is the prose between the title and the first other
+	// heading or code block.
+	Description string
+	Blocks      []Block
+}
+
+// shellLangs are the fence languages imported as commands. console and
+// shell-session blocks are transcripts: only their $-prompted lines are
+// kept.
+var shellLangs = map[string]bool{
+	"sh": true, "bash": true, "zsh": true, "shell": true, "ksh": true,
+	"console": true, "shell-session": true, "shellsession": true,
+}
+
+// ambiguousLangs are the fence languages that may hold commands or output.
+var ambiguousLangs = map[string]bool{
+	"": true, "text": true, "txt": true, "plain": true, "plaintext": true,
+}
+
+// ParseMarkdown reads the headings and fenced code blocks of a Markdown
+// runbook. Indented code blocks and setext headings are not recognized.
+func ParseMarkdown(data []byte) (*Runbook, error) {
+	rb := &Runbook{}
+	var (
+		heading     string
+		fence       string // Opening fence while inside a block
+		block       Block
+		code        []string
+		description []string
+		inIntro     bool // Between the title and anything else
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				block.Code = strings.Join(code, "\n")
+				rb.Blocks = append(rb.Blocks, block)
+				fence = ""
+				continue
+			}
+			code = append(code, line)
+			continue
+		}
+
+		if f := openingFence(trimmed); f != "" {
+			lang := ""
+			if info := strings.Fields(strings.TrimPrefix(trimmed, f)); len(info) > 0 {
+				lang = strings.ToLower(info[0])
+			}
+			fence = f
+			block = Block{Heading: heading, Lang: lang, Line: n, Kind: classify(lang)}
+			code = nil
+			inIntro = false
+			continue
+		}
+
+		if level, text := parseHeading(trimmed); level > 0 {
+			if level == 1 && rb.Title == "" {
+				rb.Title = text
+				inIntro = true
+				continue
+			}
+			heading = text
+			inIntro = false
+			continue
+		}
+
+		if inIntro {
+			description = append(description, trimmed)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read Markdown: %w", err)
+	}
+	if fence != "" {
+		return nil, fmt.Errorf("line %d: code block is never closed", block.Line)
+	}
+
+	rb.Description = strings.TrimSpace(strings.Join(description, "\n"))
+	return rb, nil
+}
+
+// openingFence returns the fence (``` or ~~~, possibly longer) opening a
+// code block on line, or "".
+func openingFence(line string) string {
+	for _, c := range []string{"`", "~"} {
+		if !strings.HasPrefix(line, c+c+c) {
+			continue
+		}
+		n := len(line) - len(strings.TrimLeft(line, c))
+		// Backtick fences can't have backticks in their info string
+		if c == "`" && strings.Contains(line[n:], "`") {
+			return ""
+		}
+		return line[:n]
+	}
+	return ""
+}
+
+// parseHeading returns the level and text of an ATX heading, or 0.
+func parseHeading(line string) (int, string) {
+	level := len(line) - len(strings.TrimLeft(line, "#"))
+	if level == 0 || level > 6 {
+		return 0, ""
+	}
+	rest := line[level:]
+	if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+		return 0, ""
+	}
+	text := strings.TrimSpace(strings.TrimRight(strings.TrimSpace(rest), "#"))
+	return level, text
+}
+
+// classify returns how a block with the given language is imported.
+func classify(lang string) BlockKind {
+	switch {
+	case shellLangs[lang]:
+		return BlockCommand
+	case ambiguousLangs[lang]:
+		return BlockAmbiguous
+	default:
+		return BlockOther
+	}
+}
+
+// Command returns the command a block runs. For transcripts (console
+// blocks, or any block whose lines start with "$ ") only the prompted
+// lines and their continuations are kept, without the prompt.
+func (b Block) Command() string {
+	lines := strings.Split(b.Code, "\n")
+	prompted := false
+	for _, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "$ ") {
+			prompted = true
+			break
+		}
+	}
+	if !prompted {
+		return strings.TrimSpace(b.Code)
+	}
+
+	var commands []string
+	continued := false
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "$ "):
+			commands = append(commands, strings.TrimPrefix(trimmed, "$ "))
+		case continued:
+			commands = append(commands, line)
+		default:
+			continue
+		}
+		continued = strings.HasSuffix(trimmed, "\\")
+	}
+	return strings.Join(commands, "\n")
+}
+
+// Workflow builds a workflow with a step for each block include returns
+// true for. Steps are named after the heading above their block, numbered
+// when a heading has several.
+func (rb *Runbook) Workflow(include func(Block) bool) *workflows.Workflow {
+	wf := &workflows.Workflow{
+		SchemaVersion: workflows.SchemaVersion,
+		Title:         rb.Title,
+		Description:   rb.Description,
+		Placeholders:  map[string]workflows.Placeholder{},
+	}
+
+	perHeading := map[string]int{}
+	for _, b := range rb.Blocks {
+		if include(b) {
+			perHeading[b.Heading]++
+		}
+	}
+	seen := map[string]int{}
+	for _, b := range rb.Blocks {
+		if !include(b) {
+			continue
+		}
+		command := b.Command()
+		if command == "" {
+			continue
+		}
+		name := b.Heading
+		seen[name]++
+		switch {
+		case name == "":
+			name = fmt.Sprintf("Step %d", len(wf.Steps)+1)
+		case perHeading[name] > 1:
+			name = fmt.Sprintf("%s (%d)", name, seen[name])
+		}
+		wf.Steps = append(wf.Steps, workflows.Step{Name: name, Command: command})
+	}
+	return wf
+}
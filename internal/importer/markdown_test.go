@@ -0,0 +1,84 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const runbook = "# Restart the API\n" +
+	"\n" +
+	"Use this when the API stops responding.\n" +
+	"Page the on-call first.\n" +
+	"\n" +
+	"## Check the pods\n" +
+	"\n" +
+	"```console\n" +
+	"$ kubectl get pods \\\n" +
+	"    -n api\n" +
+	"NAME      READY\n" +
+	"api-1     0/1\n" +
+	"```\n" +
+	"\n" +
+	"## Restart\n" +
+	"\n" +
+	"```bash\n" +
+	"kubectl rollout restart deploy/api -n api\n" +
+	"```\n" +
+	"\n" +
+	"~~~sh\n" +
+	"kubectl rollout status deploy/api -n api\n" +
+	"~~~\n" +
+	"\n" +
+	"Expected output:\n" +
+	"\n" +
+	"```\n" +
+	"deployment \"api\" successfully rolled out\n" +
+	"```\n" +
+	"\n" +
+	"## Config\n" +
+	"\n" +
+	"```yaml\n" +
+	"# not a heading\n" +
+	"replicas: 3\n" +
+	"```\n"
+
+func TestParseMarkdown(t *testing.T) {
+	rb, err := ParseMarkdown([]byte(runbook))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Restart the API", rb.Title)
+	assert.Equal(t, "Use this when the API stops responding.\nPage the on-call first.", rb.Description)
+	require.Len(t, rb.Blocks, 5)
+
+	kinds := []BlockKind{BlockCommand, BlockCommand, BlockCommand, BlockAmbiguous, BlockOther}
+	headings := []string{"Check the pods", "Restart", "Restart", "Restart", "Config"}
+	for i, b := range rb.Blocks {
+		assert.Equal(t, kinds[i], b.Kind, "block %d", i)
+		assert.Equal(t, headings[i], b.Heading, "block %d", i)
+	}
+	assert.Equal(t, 8, rb.Blocks[0].Line)
+	assert.Equal(t, "kubectl get pods \\\n    -n api", rb.Blocks[0].Command())
+	assert.Equal(t, "sh", rb.Blocks[2].Lang)
+
+	_, err = ParseMarkdown([]byte("# Broken\n\n```sh\necho hi\n"))
+	assert.ErrorContains(t, err, "line 3")
+}
+
+func TestRunbookWorkflow(t *testing.T) {
+	rb, err := ParseMarkdown([]byte(runbook))
+	require.NoError(t, err)
+
+	wf := rb.Workflow(func(b Block) bool { return b.Kind == BlockCommand })
+	assert.Equal(t, "Restart the API", wf.Title)
+	require.Len(t, wf.Steps, 3)
+	assert.Equal(t, "Check the pods", wf.Steps[0].Name)
+	assert.Equal(t, "Restart (1)", wf.Steps[1].Name)
+	assert.Equal(t, "Restart (2)", wf.Steps[2].Name)
+	assert.Equal(t, "kubectl rollout status deploy/api -n api", wf.Steps[2].Command)
+
+	wf = rb.Workflow(func(b Block) bool { return b.Kind != BlockOther })
+	require.Len(t, wf.Steps, 4)
+	assert.Equal(t, "Restart (3)", wf.Steps[3].Name)
+}
@@ -0,0 +1,58 @@
+package index
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// AliasCollision is an alias that doesn't identify a single workflow: it
+// is declared by several workflows or shadows another workflow's ID or
+// slug.
+type AliasCollision struct {
+	Alias string
+	Paths []string // Paths of the workflows involved, sorted
+}
+
+// String returns a human-readable description of the collision.
+func (c AliasCollision) String() string {
+	return fmt.Sprintf("alias %q is ambiguous between %s", c.Alias, strings.Join(c.Paths, ", "))
+}
+
+// AliasCollisions returns the aliases that collide with another alias, ID
+// or slug, sorted by alias.
+func (i *Index) AliasCollisions() []AliasCollision {
+	// Every name each workflow can be referenced by
+	names := make(map[string]map[string]bool)
+	add := func(name, path string) {
+		if names[name] == nil {
+			names[name] = make(map[string]bool)
+		}
+		names[name][path] = true
+	}
+	aliases := make(map[string]bool)
+	for _, entry := range i.Workflows {
+		add(entry.ID, entry.Path)
+		add(filepath.Base(filepath.Dir(entry.Path)), entry.Path)
+		for _, alias := range entry.Aliases {
+			add(alias, entry.Path)
+			aliases[alias] = true
+		}
+	}
+
+	var collisions []AliasCollision
+	for alias := range aliases {
+		if len(names[alias]) < 2 {
+			continue
+		}
+		c := AliasCollision{Alias: alias}
+		for path := range names[alias] {
+			c.Paths = append(c.Paths, path)
+		}
+		sort.Strings(c.Paths)
+		collisions = append(collisions, c)
+	}
+	sort.Slice(collisions, func(a, b int) bool { return collisions[a].Alias < collisions[b].Alias })
+	return collisions
+}
@@ -0,0 +1,54 @@
+package index
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestIndex_AliasCollisions(t *testing.T) {
+	idx := &Index{Workflows: []WorkflowEntry{
+		{ID: "wf_failover", Path: "shared/db-failover-procedure/workflow.yaml", Aliases: []string{"db-failover", "failover"}},
+		{ID: "wf_restore", Path: "workflows/team/bob/restore/workflow.yaml", Aliases: []string{"failover"}},
+		{ID: "wf_backup", Path: "workflows/team/bob/backup/workflow.yaml", Aliases: []string{"restore", "wf_restore"}},
+	}}
+
+	got := idx.AliasCollisions()
+	want := []AliasCollision{
+		{Alias: "failover", Paths: []string{"shared/db-failover-procedure/workflow.yaml", "workflows/team/bob/restore/workflow.yaml"}},
+		{Alias: "restore", Paths: []string{"workflows/team/bob/backup/workflow.yaml", "workflows/team/bob/restore/workflow.yaml"}},
+		{Alias: "wf_restore", Paths: []string{"workflows/team/bob/backup/workflow.yaml", "workflows/team/bob/restore/workflow.yaml"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AliasCollisions() = %+v, want %+v", got, want)
+	}
+}
+
+func TestBuilder_Build_Aliases(t *testing.T) {
+	tmpDir := t.TempDir()
+	dir := filepath.Join(tmpDir, "shared", "db-failover-procedure")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := `schema_version: 1
+title: Database failover
+aliases: [db-failover]
+steps:
+  - command: pg_ctl promote
+`
+	if err := os.WriteFile(filepath.Join(dir, "workflow.yaml"), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	idx, err := NewBuilder(tmpDir, nil).Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if len(idx.Workflows) != 1 || !reflect.DeepEqual(idx.Workflows[0].Aliases, []string{"db-failover"}) {
+		t.Errorf("Build() entries = %+v, want aliases [db-failover]", idx.Workflows)
+	}
+	if c := idx.AliasCollisions(); len(c) != 0 {
+		t.Errorf("AliasCollisions() = %+v, want none", c)
+	}
+}
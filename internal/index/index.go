@@ -3,54 +3,101 @@ package index
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/chazuruo/svf/internal/clock"
 	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/lock"
 	"github.com/chazuruo/svf/internal/workflows"
 )
 
 const (
 	// CurrentSchemaVersion is the index schema version
-	CurrentSchemaVersion = 1
+	CurrentSchemaVersion = 7
+)
+
+// Kinds of indexed workflows, by the folder they are in.
+const (
+	// KindWorkflow is a workflow in the workflows or shared folder.
+	KindWorkflow = "workflow"
+	// KindDraft is a work-in-progress workflow in the drafts folder.
+	KindDraft = "draft"
+	// KindTemplate is a workflow in the templates folder, meant to be
+	// copied rather than run as it is.
+	KindTemplate = "template"
 )
 
 // Index represents the search index.
 type Index struct {
 	Version   int             `json:"version"`
 	UpdatedAt string          `json:"updated_at"`
+	Checksum  string          `json:"checksum"` // Checksum of Workflows, set on save
 	Workflows []WorkflowEntry `json:"workflows"`
+
+	// Synonyms are the configured synonyms the entries' terms were built
+	// with; queries are normalized with the same ones.
+	Synonyms map[string]string `json:"synonyms,omitempty"`
 }
 
 // WorkflowEntry represents a workflow in the index.
 type WorkflowEntry struct {
-	ID         string   `json:"id"`
-	Title      string   `json:"title"`
-	Path       string   `json:"path"`
-	Tags       []string `json:"tags"`
-	UpdatedAt  string   `json:"updated_at"`
-	SearchText string   `json:"search_text"` // Concatenated searchable text
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	Path        string   `json:"path"`
+	Kind        string   `json:"kind"`          // KindWorkflow, KindDraft or KindTemplate
+	Doc         int      `json:"doc,omitempty"` // 1-based document in a multi-document file, 0 otherwise
+	Tags        []string `json:"tags"`
+	Aliases     []string `json:"aliases,omitempty"`
+	Commands    []string `json:"commands,omitempty"` // Step commands, for cmd: queries
+	UpdatedAt   string   `json:"updated_at"`
+	Hash        string   `json:"hash"`            // Content hash of the workflow file
+	SearchText  string   `json:"search_text"`     // Concatenated searchable text
+	Terms       []string `json:"terms,omitempty"` // Normalized words of SearchText, sorted
 }
 
 // Builder builds and maintains the search index.
 type Builder struct {
-	config   *config.Config
-	repoPath string
+	config     *config.Config
+	repoPath   string
+	clock      clock.Clock
+	normalizer *Normalizer
+}
+
+// Option configures a Builder.
+type Option func(*Builder)
+
+// WithClock sets the clock used for the index's updated_at time.
+func WithClock(c clock.Clock) Option {
+	return func(b *Builder) {
+		b.clock = c
+	}
 }
 
 // NewBuilder creates a new index builder.
-func NewBuilder(repoPath string, cfg *config.Config) *Builder {
+func NewBuilder(repoPath string, cfg *config.Config, opts ...Option) *Builder {
 	if cfg == nil {
 		cfg = config.DefaultConfig()
 	}
-	return &Builder{
-		config:   cfg,
-		repoPath: repoPath,
+	b := &Builder{
+		config:     cfg,
+		repoPath:   repoPath,
+		clock:      clock.Real,
+		normalizer: NewNormalizer(cfg.Workflows.Index.Synonyms),
 	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
 }
 
 // GetIndexPath returns the full path to the index file.
@@ -58,43 +105,181 @@ func (b *Builder) GetIndexPath() string {
 	return filepath.Join(b.repoPath, b.config.Workflows.IndexPath)
 }
 
-// Build builds the index by scanning workflow directories.
+// Build builds the index by scanning workflow directories. Workflow files
+// are parsed in parallel; the result is the same regardless of scheduling.
 func (b *Builder) Build() (*Index, error) {
 	index := &Index{
 		Version:   CurrentSchemaVersion,
-		UpdatedAt: time.Now().Format(time.RFC3339),
+		UpdatedAt: b.clock.Now().Format(time.RFC3339),
 		Workflows: []WorkflowEntry{},
+		Synonyms:  b.synonyms(),
 	}
 
-	// Scan workflows directory (user workflows)
-	workflowsDir := filepath.Join(b.repoPath, b.config.Workflows.Root)
-	if err := b.scanDirectory(workflowsDir, index, false); err != nil {
-		return nil, fmt.Errorf("scanning workflows directory: %w", err)
+	var jobs []indexJob
+	for _, r := range b.roots() {
+		if err := b.scanDirectory(r, &jobs); err != nil {
+			return nil, fmt.Errorf("scanning %s directory: %w", r.dir, err)
+		}
 	}
 
-	// Scan shared directory
-	sharedDir := filepath.Join(b.repoPath, b.config.Workflows.SharedRoot)
-	if err := b.scanDirectory(sharedDir, index, true); err != nil {
-		return nil, fmt.Errorf("scanning shared directory: %w", err)
+	for _, entry := range b.indexAll(jobs) {
+		index.Workflows = append(index.Workflows, *entry)
 	}
 
-	// Sort by title for consistent ordering
-	sort.Slice(index.Workflows, func(i, j int) bool {
-		return index.Workflows[i].Title < index.Workflows[j].Title
-	})
+	index.sortEntries()
+
+	for _, c := range index.AliasCollisions() {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", c)
+	}
 
 	return index, nil
 }
 
-// scanDirectory scans a directory for workflow files.
-// Recursively finds all workflow.yaml files and extracts identity path from directory structure.
-func (b *Builder) scanDirectory(dir string, index *Index, isShared bool) error {
-	return b.scanDirectoryRecursive(dir, "", index, isShared)
+// sortEntries sorts entries by title for consistent ordering, breaking ties
+// by path and document.
+func (i *Index) sortEntries() {
+	sort.Slice(i.Workflows, func(a, b int) bool {
+		if i.Workflows[a].Title != i.Workflows[b].Title {
+			return i.Workflows[a].Title < i.Workflows[b].Title
+		}
+		if i.Workflows[a].Path != i.Workflows[b].Path {
+			return i.Workflows[a].Path < i.Workflows[b].Path
+		}
+		return i.Workflows[a].Doc < i.Workflows[b].Doc
+	})
+}
+
+// root is a folder of workflows the index covers.
+type root struct {
+	// dir is the repo-relative folder.
+	dir string
+	// kind is the kind of the workflows in it.
+	kind string
+	// shared is set for the shared folder.
+	shared bool
+}
+
+// roots returns the folders the index covers: shared and user workflows,
+// drafts and templates. Unset folders are left out. Shared comes first so
+// rootFor finds it even inside the workflows folder.
+func (b *Builder) roots() []root {
+	all := []root{
+		{dir: b.config.Workflows.SharedRoot, kind: KindWorkflow, shared: true},
+		{dir: b.config.Workflows.Root, kind: KindWorkflow},
+		{dir: b.config.Workflows.DraftRoot, kind: KindDraft},
+		{dir: b.config.Workflows.TemplateRoot, kind: KindTemplate},
+	}
+	roots := all[:0]
+	for _, r := range all {
+		if r.dir != "" {
+			roots = append(roots, r)
+		}
+	}
+	return roots
+}
+
+// indexJob is a workflow file found while scanning.
+type indexJob struct {
+	path         string
+	identityPath string
+	slug         string
+	root         root
+}
+
+// indexAll parses the workflow files with a worker pool bounded by
+// GOMAXPROCS. Entries are returned in job order, skipping files that
+// failed to index; warnings are printed in job order too.
+func (b *Builder) indexAll(jobs []indexJob) []*WorkflowEntry {
+	results := make([][]*WorkflowEntry, len(jobs))
+	errs := make([]error, len(jobs))
+
+	workers := min(runtime.GOMAXPROCS(0), len(jobs))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				job := jobs[i]
+				results[i], errs[i] = b.indexWorkflow(job.path, job.identityPath, job.slug, job.root)
+			}
+		}()
+	}
+	for i := range jobs {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	var entries []*WorkflowEntry
+	for i, err := range errs {
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to index %s: %v\n", jobs[i].path, err)
+		}
+		entries = append(entries, results[i]...)
+	}
+
+	return entries
+}
+
+// scanDirectory scans a root for workflow files.
+// Its top-level folders, one per identity or team, are walked in parallel,
+// at most GOMAXPROCS at a time. Jobs and warnings keep directory order.
+func (b *Builder) scanDirectory(r root, jobs *[]indexJob) error {
+	dir := filepath.Join(b.repoPath, r.dir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil // Directory doesn't exist, skip
+		}
+		return err
+	}
+
+	type subtree struct {
+		jobs     []indexJob
+		warnings []string
+	}
+	subtrees := make([]subtree, len(entries))
+	sem := make(chan struct{}, runtime.GOMAXPROCS(0))
+	var wg sync.WaitGroup
+	for i, entry := range entries {
+		fullPath := filepath.Join(dir, entry.Name())
+		if !entry.IsDir() {
+			if workflows.IsWorkflowFile(entry.Name()) {
+				subtrees[i].jobs = []indexJob{{path: fullPath, slug: filepath.Base(dir), root: r}}
+			}
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			s := &subtrees[i]
+			if err := b.scanDirectoryRecursive(fullPath, entry.Name(), &s.jobs, &s.warnings, r); err != nil {
+				s.warnings = append(s.warnings, fmt.Sprintf("failed to scan %s: %v", fullPath, err))
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, s := range subtrees {
+		for _, w := range s.warnings {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+		}
+		*jobs = append(*jobs, s.jobs...)
+	}
+	return nil
 }
 
 // scanDirectoryRecursive recursively scans a directory for workflow files.
-// identityPath accumulates the path components as we recurse.
-func (b *Builder) scanDirectoryRecursive(dir string, identityPath string, index *Index, isShared bool) error {
+// identityPath accumulates the path components as we recurse. Folders that
+// can't be read are added to warnings and skipped.
+func (b *Builder) scanDirectoryRecursive(dir string, identityPath string, jobs *[]indexJob, warnings *[]string, r root) error {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -109,33 +294,33 @@ func (b *Builder) scanDirectoryRecursive(dir string, identityPath string, index
 		if entry.IsDir() {
 			// Recurse into subdirectory
 			newIdentityPath := filepath.Join(identityPath, entry.Name())
-			if err := b.scanDirectoryRecursive(fullPath, newIdentityPath, index, isShared); err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to scan %s: %v\n", fullPath, err)
+			if err := b.scanDirectoryRecursive(fullPath, newIdentityPath, jobs, warnings, r); err != nil {
+				*warnings = append(*warnings, fmt.Sprintf("failed to scan %s: %v", fullPath, err))
 			}
 			continue
 		}
 
 		// Check if this is a workflow file
-		if entry.Name() == "workflow.yaml" || entry.Name() == "workflow.yml" {
+		if workflows.IsWorkflowFile(entry.Name()) {
 			// Extract slug from parent directory
-			slug := filepath.Base(dir)
-			wfEntry, err := b.indexWorkflow(fullPath, identityPath, slug, isShared)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to index %s: %v\n", fullPath, err)
-				continue
-			}
-
-			if wfEntry != nil {
-				index.Workflows = append(index.Workflows, *wfEntry)
-			}
+			*jobs = append(*jobs, indexJob{
+				path:         fullPath,
+				identityPath: identityPath,
+				slug:         filepath.Base(dir),
+				root:         r,
+			})
 		}
 	}
 
 	return nil
 }
 
-// indexWorkflow indexes a single workflow file.
-func (b *Builder) indexWorkflow(path string, identityPath string, slug string, isShared bool) (*WorkflowEntry, error) {
+// indexWorkflow indexes a workflow file, returning an entry for each
+// workflow in it. Documents of a multi-document file without an ID get a
+// sub-ID: the ID generated from the path with "#N" for document N.
+// Generated IDs of drafts and templates start with their folder, so they
+// don't collide with the workflows they become.
+func (b *Builder) indexWorkflow(path string, identityPath string, slug string, r root) ([]*WorkflowEntry, error) {
 	// Read file
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -148,8 +333,8 @@ func (b *Builder) indexWorkflow(path string, identityPath string, slug string, i
 		return nil, err
 	}
 
-	// Parse workflow
-	wf, err := workflows.UnmarshalWorkflow(data)
+	// Parse workflows
+	wfs, err := workflows.UnmarshalWorkflows(data, workflows.FormatForPath(path))
 	if err != nil {
 		return nil, err
 	}
@@ -160,18 +345,48 @@ func (b *Builder) indexWorkflow(path string, identityPath string, slug string, i
 		relPath = path
 	}
 
-	// Use workflow ID if available, otherwise generate from path
-	id := wf.ID
-	if id == "" {
-		prefix := ""
-		if isShared {
-			prefix = "shared/"
-		} else {
-			prefix = identityPath + "/"
+	entries := make([]*WorkflowEntry, len(wfs))
+	for i, wf := range wfs {
+		doc := 0
+		if len(wfs) > 1 {
+			doc = i + 1
+		}
+
+		// Use workflow ID if available, otherwise generate from path
+		id := wf.ID
+		if id == "" {
+			prefix := ""
+			switch {
+			case r.shared:
+				prefix = "shared/"
+			case r.kind != KindWorkflow:
+				prefix = filepath.ToSlash(filepath.Join(r.dir, identityPath)) + "/"
+			default:
+				prefix = identityPath + "/"
+			}
+			id = prefix + slug
+			if doc > 0 {
+				id = fmt.Sprintf("%s#%d", id, doc)
+			}
 		}
-		id = prefix + slug
+
+		entries[i] = newEntry(wf, id, relPath, doc, info.ModTime(), hashContent(data))
+		entries[i].Kind = r.kind
+		entries[i].Terms = b.normalizer.Terms(entries[i].SearchText)
 	}
+	return entries, nil
+}
 
+// synonyms returns the configured synonyms, or nil if there are none.
+func (b *Builder) synonyms() map[string]string {
+	if len(b.config.Workflows.Index.Synonyms) == 0 {
+		return nil
+	}
+	return maps.Clone(b.config.Workflows.Index.Synonyms)
+}
+
+// newEntry builds the index entry for a workflow.
+func newEntry(wf *workflows.Workflow, id, relPath string, doc int, modTime time.Time, hash string) *WorkflowEntry {
 	// Build searchable text from title, description, tags, and commands
 	var searchText strings.Builder
 	searchText.WriteString(wf.Title)
@@ -184,23 +399,41 @@ func (b *Builder) indexWorkflow(path string, identityPath string, slug string, i
 		searchText.WriteString(tag)
 		searchText.WriteString(" ")
 	}
+	for _, alias := range wf.Aliases {
+		searchText.WriteString(alias)
+		searchText.WriteString(" ")
+	}
+	var commands []string
 	for _, step := range wf.Steps {
 		searchText.WriteString(step.Command)
 		searchText.WriteString(" ")
+		commands = append(commands, step.Command)
 	}
 
 	return &WorkflowEntry{
-		ID:         id,
-		Title:      wf.Title,
-		Path:       relPath,
-		Tags:       wf.Tags,
-		UpdatedAt:  info.ModTime().Format(time.RFC3339),
-		SearchText: strings.TrimSpace(searchText.String()),
-	}, nil
+		ID:          id,
+		Title:       wf.Title,
+		Description: wf.Description,
+		Path:        relPath,
+		Doc:         doc,
+		Tags:        wf.Tags,
+		Aliases:     wf.Aliases,
+		Commands:    commands,
+		UpdatedAt:   modTime.Format(time.RFC3339),
+		Hash:        hash,
+		SearchText:  strings.TrimSpace(searchText.String()),
+	}
 }
 
-// Save saves the index to disk.
+// Save saves the index to disk. The repository lock is held while
+// writing so concurrent svf processes can't interleave writes.
 func (b *Builder) Save(index *Index) error {
+	l, err := lock.Acquire(b.repoPath, lock.Options{Op: "index"})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = l.Release() }()
+
 	indexPath := b.GetIndexPath()
 
 	// Ensure directory exists
@@ -209,16 +442,25 @@ func (b *Builder) Save(index *Index) error {
 		return err
 	}
 
+	index.Checksum = index.ComputeChecksum()
+
 	// Marshal with pretty formatting
 	data, err := json.MarshalIndent(index, "", "  ")
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(indexPath, data, 0644)
+	// Write atomically so readers never see a partial index
+	tmp := indexPath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, indexPath)
 }
 
-// Load loads the index from disk.
+// Load loads the index from disk. It returns an error wrapping ErrCorrupt
+// when the file can't be parsed or fails its checksum (e.g. after an
+// interrupted write).
 func (b *Builder) Load() (*Index, error) {
 	indexPath := b.GetIndexPath()
 	data, err := os.ReadFile(indexPath)
@@ -228,7 +470,12 @@ func (b *Builder) Load() (*Index, error) {
 
 	var index Index
 	if err := json.Unmarshal(data, &index); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %v", ErrCorrupt, err)
+	}
+
+	// Indexes from older schemas have no checksum; callers rebuild them
+	if index.Version == CurrentSchemaVersion && !index.VerifyChecksum() {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrCorrupt)
 	}
 
 	return &index, nil
@@ -241,6 +488,9 @@ func (b *Builder) IsStale() (bool, error) {
 		if os.IsNotExist(err) {
 			return true, nil // No index exists, need to build
 		}
+		if errors.Is(err, ErrCorrupt) {
+			return true, nil // Corrupt index, need to rebuild
+		}
 		return false, err
 	}
 
@@ -249,6 +499,11 @@ func (b *Builder) IsStale() (bool, error) {
 		return true, nil
 	}
 
+	// Terms built with other synonyms wouldn't match normalized queries
+	if !maps.Equal(index.Synonyms, b.synonyms()) {
+		return true, nil
+	}
+
 	// Check if any workflow file is newer than the index
 	indexPath := b.GetIndexPath()
 	indexInfo, err := os.Stat(indexPath)
@@ -256,24 +511,17 @@ func (b *Builder) IsStale() (bool, error) {
 		return false, err
 	}
 
-	// Check workflows directory
-	workflowsDir := filepath.Join(b.repoPath, b.config.Workflows.Root)
-	stale, err := b.checkDirectoryStale(workflowsDir, indexInfo.ModTime())
-	if err != nil {
-		return false, err
-	}
-	if stale {
-		return true, nil
-	}
-
-	// Check shared directory
-	sharedDir := filepath.Join(b.repoPath, b.config.Workflows.SharedRoot)
-	stale, err = b.checkDirectoryStale(sharedDir, indexInfo.ModTime())
-	if err != nil {
-		return false, err
+	for _, r := range b.roots() {
+		stale, err := b.checkDirectoryStale(filepath.Join(b.repoPath, r.dir), indexInfo.ModTime())
+		if err != nil {
+			return false, err
+		}
+		if stale {
+			return true, nil
+		}
 	}
 
-	return stale, nil
+	return false, nil
 }
 
 // checkDirectoryStale checks if any file in directory is newer than the index.
@@ -324,7 +572,7 @@ func (b *Builder) checkSubDirectoryStale(dir string, indexModTime time.Time) (bo
 			if stale {
 				return true, nil
 			}
-		} else if entry.Name() == "workflow.yaml" || entry.Name() == "workflow.yml" {
+		} else if workflows.IsWorkflowFile(entry.Name()) {
 			// Check workflow file mod time
 			info, err := entry.Info()
 			if err != nil {
@@ -345,12 +593,13 @@ func (i *Index) Search(query string) []WorkflowEntry {
 		return i.Workflows
 	}
 
+	queryTerms := NewNormalizer(i.Synonyms).QueryTerms(query)
 	query = strings.ToLower(query)
 	var results []WorkflowEntry
 
 	for _, entry := range i.Workflows {
 		searchable := strings.ToLower(entry.SearchText)
-		if strings.Contains(searchable, query) {
+		if strings.Contains(searchable, query) || (queryTerms != nil && hasTerms(entry.Terms, queryTerms)) {
 			results = append(results, entry)
 		}
 	}
@@ -412,29 +661,70 @@ type SearchResult struct {
 
 // SearchOptions contains search options.
 type SearchOptions struct {
-	Query        string
-	Tags         []string // Filter by tags
-	IdentityPath string   // Filter by identity path (e.g., "platform/chaz")
-	Mine         bool     // Filter by identity path only (user's workflows)
-	Shared       bool     // Filter by shared workflows only
-	MaxResults   int      // Limit results (0 for no limit)
+	Query         string   // Free text and field:value terms, see parseQuery
+	Regex         bool     // Treat query terms as regular expressions
+	Tags          []string // Filter by tags
+	IdentityPath  string   // Filter by identity path (e.g., "platform/chaz")
+	Mine          bool     // Filter by identity path only (user's workflows)
+	Shared        bool     // Filter by shared workflows only
+	IncludeDrafts bool     // Include drafts and templates, left out unless a kind: term asks for them
+	MaxResults    int      // Limit results (0 for no limit)
 }
 
-// FuzzySearch performs fuzzy search with ranking and filtering.
+// FuzzySearch performs fuzzy search with ranking and filtering. A query
+// that fails to parse (an unterminated quote or, in regex mode, an invalid
+// expression) matches nothing; use Query to get the error.
 func (i *Index) FuzzySearch(opts SearchOptions) []SearchResult {
+	results, err := i.Query(opts)
+	if err != nil {
+		return nil
+	}
+	return results
+}
+
+// Query performs a search like FuzzySearch and reports query syntax
+// errors. Field-scoped terms such as tag:deploy or cmd:"kubectl delete"
+// filter the results; free-text terms are ranked as before.
+func (i *Index) Query(opts SearchOptions) ([]SearchResult, error) {
 	if opts.Query == "" && len(opts.Tags) == 0 && opts.IdentityPath == "" && !opts.Mine && !opts.Shared {
 		// No filters, return all with basic scoring
-		results := make([]SearchResult, len(i.Workflows))
-		for j, entry := range i.Workflows {
-			results[j] = SearchResult{Entry: entry, Score: 1.0}
+		results := make([]SearchResult, 0, len(i.Workflows))
+		for _, entry := range i.Workflows {
+			if opts.IncludeDrafts || entry.kind() == KindWorkflow {
+				results = append(results, SearchResult{Entry: entry, Score: 1.0})
+			}
 		}
-		return results
+		return results, nil
 	}
 
-	query := strings.ToLower(opts.Query)
+	terms, err := parseQuery(opts.Query, opts.Regex)
+	if err != nil {
+		return nil, err
+	}
+	var fieldTerms, freeTerms []queryTerm
+	var freeText []string
+	includeDrafts := opts.IncludeDrafts
+	for _, term := range terms {
+		if term.Field == "kind" {
+			includeDrafts = true
+		}
+		if term.Field != "" {
+			fieldTerms = append(fieldTerms, term)
+		} else {
+			freeTerms = append(freeTerms, term)
+			freeText = append(freeText, term.Value)
+		}
+	}
+	query := strings.ToLower(strings.Join(freeText, " "))
+	normalizer := NewNormalizer(i.Synonyms)
+	queryTerms := normalizer.QueryTerms(query)
 	var results []SearchResult
 
 	for _, entry := range i.Workflows {
+		if !includeDrafts && entry.kind() != KindWorkflow {
+			continue
+		}
+
 		// Apply identity path filter
 		if opts.IdentityPath != "" {
 			// Entry path format: workflows/<identity-path>/<slug>/workflow.yaml
@@ -489,8 +779,31 @@ func (i *Index) FuzzySearch(opts SearchOptions) []SearchResult {
 			}
 		}
 
+		// Apply field-scoped terms
+		fieldMatch := true
+		for _, term := range fieldTerms {
+			if !matchFieldTerm(entry, term) {
+				fieldMatch = false
+				break
+			}
+		}
+		if !fieldMatch {
+			continue
+		}
+
 		// Score the entry
-		score, matches := i.scoreEntry(entry, query)
+		var score float64
+		var matches []string
+		if opts.Regex {
+			score, matches = scoreRegexTerms(entry, freeTerms)
+		} else {
+			score, matches = i.scoreEntry(entry, query, normalizer, queryTerms)
+		}
+		for _, term := range fieldTerms {
+			if !contains(matches, term.Field) {
+				matches = append(matches, term.Field)
+			}
+		}
 		if score > 0 {
 			results = append(results, SearchResult{
 				Entry:   entry,
@@ -510,11 +823,11 @@ func (i *Index) FuzzySearch(opts SearchOptions) []SearchResult {
 		results = results[:opts.MaxResults]
 	}
 
-	return results
+	return results, nil
 }
 
-// scoreEntry scores an entry against the query.
-func (i *Index) scoreEntry(entry WorkflowEntry, query string) (float64, []string) {
+// scoreEntry scores an entry against the query and its normalized terms.
+func (i *Index) scoreEntry(entry WorkflowEntry, query string, normalizer *Normalizer, queryTerms []string) (float64, []string) {
 	if query == "" {
 		return 1.0, []string{}
 	}
@@ -558,6 +871,18 @@ func (i *Index) scoreEntry(entry WorkflowEntry, query string) (float64, []string
 		if !contains(matches, "content") {
 			matches = append(matches, "content")
 		}
+	} else if queryTerms != nil && hasTerms(entry.Terms, queryTerms) {
+		// Same words in another form, e.g. "restarting k8s pods" for
+		// "Restart Kubernetes pod"; ranked below a literal title match
+		if hasTerms(normalizer.Terms(entry.Title), queryTerms) {
+			score += 40
+			if !contains(matches, "title") {
+				matches = append(matches, "title")
+			}
+		} else {
+			score += 10
+			matches = append(matches, "content")
+		}
 	}
 
 	// Tag exact or partial match
@@ -624,14 +949,46 @@ func contains(slice []string, value string) bool {
 	return false
 }
 
-// GetByPath retrieves a workflow entry by path.
+// GetByPath retrieves a workflow entry by path. A document of a
+// multi-document file is named by its location, "path#N"; the bare path
+// returns its first document.
 func (i *Index) GetByPath(path string) *WorkflowEntry {
+	var first *WorkflowEntry
 	for _, entry := range i.Workflows {
-		if entry.Path == path {
+		if entry.Location() == path {
 			return &entry
 		}
+		if entry.Path == path && (first == nil || entry.Doc < first.Doc) {
+			first = &entry
+		}
 	}
-	return nil
+	return first
+}
+
+// Location returns the entry's path, with "#N" appended for document N of
+// a multi-document file.
+func (e WorkflowEntry) Location() string {
+	if e.Doc > 0 {
+		return fmt.Sprintf("%s#%d", e.Path, e.Doc)
+	}
+	return e.Path
+}
+
+// kind returns the entry's kind; entries without one are workflows.
+func (e WorkflowEntry) kind() string {
+	if e.Kind == "" {
+		return KindWorkflow
+	}
+	return e.Kind
+}
+
+// KindBadge returns the label listings put before the title of a draft
+// or template, e.g. "[draft] ", or "" for a workflow.
+func KindBadge(kind string) string {
+	if kind == "" || kind == KindWorkflow {
+		return ""
+	}
+	return "[" + kind + "] "
 }
 
 // GetByID retrieves a workflow entry by ID.
@@ -1,8 +1,10 @@
 package index
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -187,6 +189,226 @@ func TestBuilder_Build(t *testing.T) {
 	}
 }
 
+func TestBuilder_Build_OtherFormats(t *testing.T) {
+	tmpDir, _, builder := setupTestIndex(t)
+
+	for _, format := range []workflows.Format{workflows.FormatTOML, workflows.FormatJSON} {
+		dir := filepath.Join(tmpDir, "workflows", "platform", "test", "restart-"+string(format))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		wf := &workflows.Workflow{
+			SchemaVersion: 1,
+			Title:         "Restart " + string(format),
+			Steps:         []workflows.Step{{Name: "Restart", Command: "systemctl restart app"}},
+		}
+		data, err := workflows.MarshalWorkflowAs(wf, format)
+		if err != nil {
+			t.Fatalf("failed to marshal workflow: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, workflows.FileName(format)), data, 0644); err != nil {
+			t.Fatalf("failed to write workflow: %v", err)
+		}
+	}
+
+	index, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	for _, want := range []string{
+		"workflows/platform/test/restart-toml/workflow.toml",
+		"workflows/platform/test/restart-json/workflow.json",
+	} {
+		if entry := index.GetByPath(want); entry == nil {
+			t.Errorf("GetByPath(%s) = nil", want)
+		}
+	}
+}
+
+func TestBuilder_Build_MultiDocument(t *testing.T) {
+	tmpDir, _, builder := setupTestIndex(t)
+
+	dir := filepath.Join(tmpDir, "workflows", "platform", "test", "restarts")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := "title: Restart API\nsteps:\n  - command: systemctl restart api\n---\n" +
+		"id: wf_worker\ntitle: Restart Worker\nsteps:\n  - command: systemctl restart worker\n"
+	if err := os.WriteFile(filepath.Join(dir, "workflow.yaml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	index, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	path := "workflows/platform/test/restarts/workflow.yaml"
+	api := index.GetByPath(path + "#1")
+	if api == nil || api.ID != "platform/test/restarts/restarts#1" || api.Title != "Restart API" {
+		t.Errorf("GetByPath(#1) = %+v, want a sub-ID", api)
+	}
+	worker := index.GetByPath(path + "#2")
+	if worker == nil || worker.ID != "wf_worker" || worker.Doc != 2 {
+		t.Errorf("GetByPath(#2) = %+v", worker)
+	}
+	if first := index.GetByPath(path); first == nil || first.Doc != 1 {
+		t.Errorf("GetByPath(bare path) = %+v, want document 1", first)
+	}
+}
+
+func TestBuilder_Build_DraftsAndTemplates(t *testing.T) {
+	tmpDir, cfg, builder := setupTestIndex(t)
+	cfg.Workflows.DraftRoot = "drafts"
+	cfg.Workflows.TemplateRoot = "templates"
+
+	files := map[string]string{
+		"drafts/platform/test/redis-oom/workflow.yaml": "title: Redis OOM\nsteps:\n  - command: redis-cli info memory\n",
+		"templates/rollout/workflow.yaml":              "title: Rollout Template\nsteps:\n  - command: kubectl rollout restart deploy/<app>\n",
+	}
+	for path, content := range files {
+		full := filepath.Join(tmpDir, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	index, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	draft := index.GetByPath("drafts/platform/test/redis-oom/workflow.yaml")
+	if draft == nil || draft.Kind != KindDraft || draft.ID != "drafts/platform/test/redis-oom/redis-oom" {
+		t.Errorf("draft entry = %+v, want kind %q with an ID under drafts/", draft, KindDraft)
+	}
+	template := index.GetByPath("templates/rollout/workflow.yaml")
+	if template == nil || template.Kind != KindTemplate {
+		t.Errorf("template entry = %+v, want kind %q", template, KindTemplate)
+	}
+	if wf := index.GetByID("wf_01ABC123DEF45678"); wf == nil || wf.Kind != KindWorkflow {
+		t.Errorf("workflow entry = %+v, want kind %q", wf, KindWorkflow)
+	}
+
+	titles := func(results []SearchResult) []string {
+		var titles []string
+		for _, r := range results {
+			titles = append(titles, r.Entry.Title)
+		}
+		return titles
+	}
+	tests := []struct {
+		name string
+		opts SearchOptions
+		want []string
+	}{
+		{"drafts left out", SearchOptions{Query: "redis"}, nil},
+		{"include drafts", SearchOptions{Query: "redis", IncludeDrafts: true}, []string{"Redis OOM"}},
+		{"kind term", SearchOptions{Query: "kind:template"}, []string{"Rollout Template"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := index.Query(tt.opts)
+			if err != nil {
+				t.Fatalf("Query() error = %v", err)
+			}
+			if got := titles(results); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("Query() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	all, _ := index.Query(SearchOptions{})
+	withDrafts, _ := index.Query(SearchOptions{IncludeDrafts: true})
+	if len(withDrafts) != len(all)+2 {
+		t.Errorf("Query(IncludeDrafts) = %d results, want %d", len(withDrafts), len(all)+2)
+	}
+}
+
+func TestBuilder_Build_Deterministic(t *testing.T) {
+	tmpDir, _, builder := setupTestIndex(t)
+
+	// Enough workflows to keep every worker busy, with duplicate titles so
+	// ordering depends on the path tiebreak
+	for i := 0; i < 50; i++ {
+		dir := filepath.Join(tmpDir, "workflows", "platform", "test", fmt.Sprintf("bulk-%02d", i))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		wf := &workflows.Workflow{
+			SchemaVersion: 1,
+			Title:         fmt.Sprintf("Bulk %d", i%5),
+			Steps:         []workflows.Step{{Name: "Step", Command: "true"}},
+		}
+		data, err := workflows.MarshalWorkflow(wf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "workflow.yaml"), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	first, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if len(first.Workflows) != 53 {
+		t.Fatalf("Workflows count = %d, want 53", len(first.Workflows))
+	}
+
+	for run := 0; run < 5; run++ {
+		again, err := builder.Build()
+		if err != nil {
+			t.Fatalf("Build() error = %v", err)
+		}
+		if again.ComputeChecksum() != first.ComputeChecksum() {
+			t.Fatalf("run %d: Build() output differs between runs", run)
+		}
+	}
+}
+
+// TestBuilder_ScanDirectory_Order verifies that top-level folders scanned
+// in parallel yield their workflow files in directory order.
+func TestBuilder_ScanDirectory_Order(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := &config.Config{Workflows: config.WorkflowsConfig{Root: "workflows"}}
+	builder := NewBuilder(tmpDir, cfg)
+
+	var want []string
+	for team := 0; team < 20; team++ {
+		for _, name := range []string{"backup", "deploy", "restore"} {
+			dir := filepath.Join(tmpDir, "workflows", fmt.Sprintf("team%02d", team), "alice", name)
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				t.Fatal(err)
+			}
+			path := filepath.Join(dir, "workflow.yaml")
+			if err := os.WriteFile(path, []byte("title: "+name+"\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			want = append(want, path)
+		}
+	}
+
+	var jobs []indexJob
+	if err := builder.scanDirectory(builder.roots()[0], &jobs); err != nil {
+		t.Fatalf("scanDirectory() error = %v", err)
+	}
+	got := make([]string, len(jobs))
+	for i, job := range jobs {
+		got[i] = job.path
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("scanDirectory() paths =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if jobs[0].identityPath != filepath.Join("team00", "alice", "backup") || jobs[0].slug != "backup" {
+		t.Errorf("first job = %+v, want identity team00/alice/backup and slug backup", jobs[0])
+	}
+}
+
 func TestBuilder_SaveAndLoad(t *testing.T) {
 	_, _, builder := setupTestIndex(t)
 
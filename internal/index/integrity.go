@@ -0,0 +1,190 @@
+package index
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// ErrCorrupt is returned when the index file can't be parsed or its
+// checksum doesn't match its contents.
+var ErrCorrupt = errors.New("search index is corrupt")
+
+// ProblemKind classifies an integrity problem.
+type ProblemKind string
+
+const (
+	// ProblemMissingIndex means no index file exists.
+	ProblemMissingIndex ProblemKind = "missing-index"
+	// ProblemCorrupt means the index can't be parsed or fails its checksum.
+	ProblemCorrupt ProblemKind = "corrupt"
+	// ProblemOutdatedSchema means the index was written by an older version.
+	ProblemOutdatedSchema ProblemKind = "outdated-schema"
+	// ProblemMissingFile means an indexed workflow no longer exists.
+	ProblemMissingFile ProblemKind = "missing-file"
+	// ProblemHashMismatch means a workflow changed since it was indexed.
+	ProblemHashMismatch ProblemKind = "hash-mismatch"
+	// ProblemUnindexed means a workflow exists but isn't in the index.
+	ProblemUnindexed ProblemKind = "unindexed"
+)
+
+// Problem is a single integrity problem found by Verify.
+type Problem struct {
+	Kind    ProblemKind
+	Path    string
+	Message string
+}
+
+// String returns a human-readable description of the problem.
+func (p Problem) String() string {
+	if p.Path != "" {
+		return fmt.Sprintf("%s: %s", p.Path, p.Message)
+	}
+	return p.Message
+}
+
+// hashContent returns the content hash stored for a workflow file.
+func hashContent(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// ComputeChecksum returns the checksum of the index entries.
+func (i *Index) ComputeChecksum() string {
+	// Entries marshal deterministically, so hashing the JSON is stable
+	data, _ := json.Marshal(i.Workflows)
+	return hashContent(data)
+}
+
+// VerifyChecksum reports whether the stored checksum matches the entries.
+func (i *Index) VerifyChecksum() bool {
+	return i.Checksum == i.ComputeChecksum()
+}
+
+// Verify checks the stored index against the workflow files on disk. It
+// returns the problems found; an empty result means the index is sound.
+func (b *Builder) Verify() ([]Problem, error) {
+	idx, err := b.Load()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []Problem{{Kind: ProblemMissingIndex, Message: "search index does not exist"}}, nil
+		}
+		if errors.Is(err, ErrCorrupt) {
+			return []Problem{{Kind: ProblemCorrupt, Message: err.Error()}}, nil
+		}
+		return nil, err
+	}
+
+	if idx.Version != CurrentSchemaVersion {
+		return []Problem{{
+			Kind:    ProblemOutdatedSchema,
+			Message: fmt.Sprintf("index schema version %d, want %d", idx.Version, CurrentSchemaVersion),
+		}}, nil
+	}
+
+	var problems []Problem
+	indexed := make(map[string]bool, len(idx.Workflows))
+	for _, entry := range idx.Workflows {
+		indexed[entry.Path] = true
+
+		data, err := os.ReadFile(filepath.Join(b.repoPath, entry.Path))
+		if err != nil {
+			if os.IsNotExist(err) {
+				problems = append(problems, Problem{Kind: ProblemMissingFile, Path: entry.Path, Message: "indexed workflow no longer exists"})
+				continue
+			}
+			return nil, err
+		}
+		if hashContent(data) != entry.Hash {
+			problems = append(problems, Problem{Kind: ProblemHashMismatch, Path: entry.Path, Message: "workflow changed since it was indexed"})
+		}
+	}
+
+	// Find workflows the index doesn't know about
+	for _, r := range b.roots() {
+		dir := filepath.Join(b.repoPath, r.dir)
+		err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return filepath.SkipDir
+				}
+				return err
+			}
+			if d.IsDir() || !workflows.IsWorkflowFile(d.Name()) {
+				return nil
+			}
+			rel, err := filepath.Rel(b.repoPath, path)
+			if err != nil {
+				return err
+			}
+			if !indexed[rel] {
+				problems = append(problems, Problem{Kind: ProblemUnindexed, Path: rel, Message: "workflow is missing from the index"})
+			}
+			return nil
+		})
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	return problems, nil
+}
+
+// Rebuild builds a fresh index and saves it. With readonly set in the
+// config the index is only built, leaving the repo untouched.
+func (b *Builder) Rebuild() (*Index, error) {
+	idx, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	if b.config.ReadOnly {
+		return idx, nil
+	}
+	if err := b.Save(idx); err != nil {
+		return nil, err
+	}
+	return idx, nil
+}
+
+// LoadOrRebuild loads the index, rebuilding it when it is missing,
+// corrupt or from an older schema. rebuilt reports whether a rebuild
+// happened, so callers can tell the user.
+func (b *Builder) LoadOrRebuild() (idx *Index, rebuilt bool, err error) {
+	idx, err = b.Load()
+	if err == nil && idx.Version == CurrentSchemaVersion {
+		return idx, false, nil
+	}
+	if err != nil && !os.IsNotExist(err) && !errors.Is(err, ErrCorrupt) {
+		return nil, false, err
+	}
+
+	idx, err = b.Rebuild()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to rebuild index: %w", err)
+	}
+	return idx, true, nil
+}
+
+// LoadFresh loads the index for a long-running reader, such as the web UI,
+// the API server or an SDK client, rebuilding it when workflow files
+// changed since it was saved as well as when LoadOrRebuild would.
+func (b *Builder) LoadFresh() (*Index, error) {
+	if stale, err := b.IsStale(); err == nil && stale {
+		idx, err := b.Rebuild()
+		if err != nil {
+			return nil, fmt.Errorf("failed to rebuild index: %w", err)
+		}
+		return idx, nil
+	}
+	idx, _, err := b.LoadOrRebuild()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load index: %w", err)
+	}
+	return idx, nil
+}
@@ -0,0 +1,249 @@
+package index
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// buildAndSave builds and saves an index for the test repo.
+func buildAndSave(t *testing.T, builder *Builder) *Index {
+	t.Helper()
+	idx, err := builder.Rebuild()
+	if err != nil {
+		t.Fatalf("Rebuild() error = %v", err)
+	}
+	return idx
+}
+
+// writeRawIndex writes an index as-is, without updating its checksum.
+func writeRawIndex(t *testing.T, builder *Builder, idx *Index) {
+	t.Helper()
+	data, err := json.Marshal(idx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(builder.GetIndexPath(), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// problemKinds returns the kinds of the given problems.
+func problemKinds(problems []Problem) []ProblemKind {
+	kinds := make([]ProblemKind, len(problems))
+	for i, p := range problems {
+		kinds[i] = p.Kind
+	}
+	return kinds
+}
+
+func TestBuilder_Save_SetsChecksums(t *testing.T) {
+	_, _, builder := setupTestIndex(t)
+	idx := buildAndSave(t, builder)
+
+	if idx.Checksum == "" {
+		t.Fatal("Checksum should be set on save")
+	}
+	for _, entry := range idx.Workflows {
+		if entry.Hash == "" {
+			t.Errorf("entry %s has no content hash", entry.Path)
+		}
+	}
+
+	loaded, err := builder.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !loaded.VerifyChecksum() {
+		t.Error("loaded index should pass its checksum")
+	}
+}
+
+func TestBuilder_Load_DetectsCorruption(t *testing.T) {
+	t.Run("truncated file", func(t *testing.T) {
+		_, _, builder := setupTestIndex(t)
+		buildAndSave(t, builder)
+
+		data, _ := os.ReadFile(builder.GetIndexPath())
+		if err := os.WriteFile(builder.GetIndexPath(), data[:len(data)/2], 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := builder.Load(); !errors.Is(err, ErrCorrupt) {
+			t.Errorf("Load() error = %v, want ErrCorrupt", err)
+		}
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		_, _, builder := setupTestIndex(t)
+		idx := buildAndSave(t, builder)
+
+		// Tamper with an entry without updating the checksum
+		idx.Workflows[0].Title = "Tampered"
+		writeRawIndex(t, builder, idx)
+
+		if _, err := builder.Load(); !errors.Is(err, ErrCorrupt) {
+			t.Errorf("Load() error = %v, want ErrCorrupt", err)
+		}
+
+		stale, err := builder.IsStale()
+		if err != nil || !stale {
+			t.Errorf("IsStale() = %v, %v; want true, nil", stale, err)
+		}
+	})
+}
+
+func TestBuilder_Verify(t *testing.T) {
+	t.Run("consistent", func(t *testing.T) {
+		_, _, builder := setupTestIndex(t)
+		buildAndSave(t, builder)
+
+		problems, err := builder.Verify()
+		if err != nil {
+			t.Fatalf("Verify() error = %v", err)
+		}
+		if len(problems) != 0 {
+			t.Errorf("Verify() = %v, want no problems", problems)
+		}
+	})
+
+	t.Run("missing index", func(t *testing.T) {
+		_, _, builder := setupTestIndex(t)
+
+		problems, err := builder.Verify()
+		if err != nil {
+			t.Fatalf("Verify() error = %v", err)
+		}
+		if len(problems) != 1 || problems[0].Kind != ProblemMissingIndex {
+			t.Errorf("Verify() = %v, want missing index", problems)
+		}
+	})
+
+	t.Run("changed, deleted and new workflows", func(t *testing.T) {
+		tmpDir, _, builder := setupTestIndex(t)
+		buildAndSave(t, builder)
+
+		changed := filepath.Join(tmpDir, "workflows", "platform", "test", "workflow1", "workflow.yaml")
+		f, err := os.OpenFile(changed, os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = f.WriteString("# edited\n")
+		_ = f.Close()
+
+		if err := os.RemoveAll(filepath.Join(tmpDir, "shared", "common")); err != nil {
+			t.Fatal(err)
+		}
+
+		newDir := filepath.Join(tmpDir, "workflows", "platform", "test", "workflow3")
+		if err := os.MkdirAll(newDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		data, _ := os.ReadFile(changed)
+		if err := os.WriteFile(filepath.Join(newDir, "workflow.yaml"), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		problems, err := builder.Verify()
+		if err != nil {
+			t.Fatalf("Verify() error = %v", err)
+		}
+
+		want := map[ProblemKind]bool{
+			ProblemHashMismatch: true,
+			ProblemMissingFile:  true,
+			ProblemUnindexed:    true,
+		}
+		got := problemKinds(problems)
+		if len(got) != len(want) {
+			t.Fatalf("Verify() = %v, want %d problems", problems, len(want))
+		}
+		for _, k := range got {
+			if !want[k] {
+				t.Errorf("unexpected problem kind %s", k)
+			}
+		}
+
+		// Rebuilding clears the problems
+		buildAndSave(t, builder)
+		problems, err = builder.Verify()
+		if err != nil {
+			t.Fatalf("Verify() error = %v", err)
+		}
+		if len(problems) != 0 {
+			t.Errorf("Verify() after rebuild = %v, want no problems", problems)
+		}
+	})
+}
+
+func TestBuilder_LoadOrRebuild(t *testing.T) {
+	_, _, builder := setupTestIndex(t)
+
+	// Missing index is built
+	idx, rebuilt, err := builder.LoadOrRebuild()
+	if err != nil {
+		t.Fatalf("LoadOrRebuild() error = %v", err)
+	}
+	if !rebuilt || len(idx.Workflows) != 3 {
+		t.Errorf("LoadOrRebuild() = %d workflows, rebuilt %v; want 3, true", len(idx.Workflows), rebuilt)
+	}
+
+	// Sound index is loaded as-is
+	_, rebuilt, err = builder.LoadOrRebuild()
+	if err != nil || rebuilt {
+		t.Errorf("LoadOrRebuild() rebuilt = %v, err = %v; want false, nil", rebuilt, err)
+	}
+
+	// Corrupt index is rebuilt
+	if err := os.WriteFile(builder.GetIndexPath(), []byte(`{"version": 2, "workf`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	idx, rebuilt, err = builder.LoadOrRebuild()
+	if err != nil {
+		t.Fatalf("LoadOrRebuild() error = %v", err)
+	}
+	if !rebuilt || len(idx.Workflows) != 3 {
+		t.Errorf("LoadOrRebuild() = %d workflows, rebuilt %v; want 3, true", len(idx.Workflows), rebuilt)
+	}
+}
+
+func TestBuilder_LoadFresh(t *testing.T) {
+	root, _, builder := setupTestIndex(t)
+	if _, err := builder.Rebuild(); err != nil {
+		t.Fatalf("Rebuild() error = %v", err)
+	}
+
+	// A workflow added after the index was saved makes it stale
+	path := writeTestWorkflow(t, filepath.Join(root, "workflows", "platform", "test", "added"), "Added")
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	idx, err := builder.LoadFresh()
+	if err != nil {
+		t.Fatalf("LoadFresh() error = %v", err)
+	}
+	if len(idx.Workflows) != 4 {
+		t.Errorf("LoadFresh() = %d workflows, want 4", len(idx.Workflows))
+	}
+}
+
+func TestBuilder_ReadOnly(t *testing.T) {
+	_, cfg, builder := setupTestIndex(t)
+	cfg.ReadOnly = true
+
+	idx, err := builder.LoadFresh()
+	if err != nil {
+		t.Fatalf("LoadFresh() error = %v", err)
+	}
+	if len(idx.Workflows) != 3 {
+		t.Errorf("LoadFresh() = %d workflows, want 3", len(idx.Workflows))
+	}
+	if _, err := os.Stat(builder.GetIndexPath()); !os.IsNotExist(err) {
+		t.Errorf("index saved in read-only mode: %v", err)
+	}
+}
@@ -0,0 +1,155 @@
+package index
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"reflect"
+)
+
+// MergeDriver is the name of the git merge driver that merges index files.
+const MergeDriver = "svf-index"
+
+// Merge performs a three-way merge of index entries keyed by location. An
+// entry changed on one side only takes that side; an entry changed on both
+// sides takes the more recently updated one. Entries added on either side
+// are kept, and entries removed on one side are dropped unless the other
+// side changed them. It only keeps the index from conflicting: a workflow
+// renamed on one side and changed on the other keeps an entry at each
+// path, so sync rebuilds the index from the merged files afterwards.
+func Merge(base, ours, theirs *Index) *Index {
+	baseEntries := entriesByLocation(base)
+	ourEntries := entriesByLocation(ours)
+	theirEntries := entriesByLocation(theirs)
+
+	merged := &Index{
+		Version:   CurrentSchemaVersion,
+		UpdatedAt: ours.UpdatedAt,
+		Workflows: []WorkflowEntry{},
+	}
+	// An older schema on any side can't be merged entry by entry reliably;
+	// leave the version behind so the next load rebuilds the index
+	for _, idx := range []*Index{ours, theirs} {
+		if idx.Version != CurrentSchemaVersion {
+			merged.Version = idx.Version
+		}
+	}
+	if theirs.UpdatedAt > merged.UpdatedAt {
+		merged.UpdatedAt = theirs.UpdatedAt
+	}
+
+	locations := make(map[string]bool)
+	for loc := range ourEntries {
+		locations[loc] = true
+	}
+	for loc := range theirEntries {
+		locations[loc] = true
+	}
+
+	for loc := range locations {
+		b, inBase := baseEntries[loc]
+		o, inOurs := ourEntries[loc]
+		t, inTheirs := theirEntries[loc]
+
+		switch {
+		case inOurs && inTheirs:
+			merged.Workflows = append(merged.Workflows, pickEntry(b, o, t, inBase))
+		case inOurs:
+			// Removed on their side: keep only if we changed it since
+			if !inBase || !reflect.DeepEqual(o, b) {
+				merged.Workflows = append(merged.Workflows, o)
+			}
+		case inTheirs:
+			if !inBase || !reflect.DeepEqual(t, b) {
+				merged.Workflows = append(merged.Workflows, t)
+			}
+		}
+	}
+
+	// Terms must all be built with the same synonyms; take ours, as a
+	// rebuild would from this checkout's config
+	merged.Synonyms = ours.Synonyms
+	if !maps.Equal(ours.Synonyms, theirs.Synonyms) {
+		normalizer := NewNormalizer(ours.Synonyms)
+		for j := range merged.Workflows {
+			merged.Workflows[j].Terms = normalizer.Terms(merged.Workflows[j].SearchText)
+		}
+	}
+
+	merged.sortEntries()
+	merged.Checksum = merged.ComputeChecksum()
+	return merged
+}
+
+// pickEntry chooses between two versions of an entry present on both sides.
+func pickEntry(base, ours, theirs WorkflowEntry, inBase bool) WorkflowEntry {
+	if inBase {
+		if reflect.DeepEqual(ours, base) {
+			return theirs
+		}
+		if reflect.DeepEqual(theirs, base) {
+			return ours
+		}
+	}
+	if theirs.UpdatedAt > ours.UpdatedAt {
+		return theirs
+	}
+	return ours
+}
+
+// entriesByLocation maps an index's entries by location.
+func entriesByLocation(idx *Index) map[string]WorkflowEntry {
+	entries := make(map[string]WorkflowEntry, len(idx.Workflows))
+	for _, entry := range idx.Workflows {
+		entries[entry.Location()] = entry
+	}
+	return entries
+}
+
+// MergeFiles merges the index files at basePath, oursPath and theirsPath,
+// writing the result to oursPath, as git expects of a merge driver. A
+// missing or empty base, as when both sides created the index, counts as
+// an empty index. It doesn't take the repository lock: it runs inside the
+// git merge of a sync, which already holds it.
+func MergeFiles(basePath, oursPath, theirsPath string) error {
+	base, err := readIndexFile(basePath, true)
+	if err != nil {
+		return err
+	}
+	ours, err := readIndexFile(oursPath, false)
+	if err != nil {
+		return err
+	}
+	theirs, err := readIndexFile(theirsPath, false)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(Merge(base, ours, theirs), "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := oursPath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, oursPath)
+}
+
+// readIndexFile reads an index file for merging. When optional is set, a
+// missing or empty file is an empty index.
+func readIndexFile(path string, optional bool) (*Index, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !(optional && os.IsNotExist(err)) {
+		return nil, err
+	}
+	idx := &Index{Version: CurrentSchemaVersion}
+	if len(data) == 0 && optional {
+		return idx, nil
+	}
+	if err := json.Unmarshal(data, idx); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrCorrupt, path, err)
+	}
+	return idx, nil
+}
@@ -0,0 +1,134 @@
+package index
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	deploy := WorkflowEntry{ID: "wf_deploy", Title: "Deploy", Path: "workflows/deploy/workflow.yaml", UpdatedAt: "2026-01-01T00:00:00Z", Hash: "a"}
+	restart := WorkflowEntry{ID: "wf_restart", Title: "Restart", Path: "workflows/restart/workflow.yaml", UpdatedAt: "2026-01-01T00:00:00Z", Hash: "b"}
+	rollback := WorkflowEntry{ID: "wf_rollback", Title: "Rollback", Path: "workflows/rollback/workflow.yaml", UpdatedAt: "2026-01-01T00:00:00Z", Hash: "c"}
+
+	deployOurs := deploy
+	deployOurs.Hash, deployOurs.UpdatedAt = "a2", "2026-02-01T00:00:00Z"
+	deployTheirs := deploy
+	deployTheirs.Hash, deployTheirs.UpdatedAt = "a3", "2026-03-01T00:00:00Z"
+	restartTheirs := restart
+	restartTheirs.Title = "Restart service"
+	backup := WorkflowEntry{ID: "wf_backup", Title: "Backup", Path: "workflows/backup/workflow.yaml", UpdatedAt: "2026-02-01T00:00:00Z"}
+	cleanup := WorkflowEntry{ID: "wf_cleanup", Title: "Cleanup", Path: "workflows/cleanup/workflow.yaml", UpdatedAt: "2026-03-01T00:00:00Z"}
+
+	tests := []struct {
+		name               string
+		base, ours, theirs []WorkflowEntry
+		want               []WorkflowEntry
+	}{
+		{
+			name:   "additions on both sides",
+			base:   []WorkflowEntry{deploy},
+			ours:   []WorkflowEntry{deploy, backup},
+			theirs: []WorkflowEntry{deploy, cleanup},
+			want:   []WorkflowEntry{backup, cleanup, deploy},
+		},
+		{
+			name:   "change on one side",
+			base:   []WorkflowEntry{deploy, restart},
+			ours:   []WorkflowEntry{deployOurs, restart},
+			theirs: []WorkflowEntry{deploy, restartTheirs},
+			want:   []WorkflowEntry{deployOurs, restartTheirs},
+		},
+		{
+			name:   "change on both sides takes the latest",
+			base:   []WorkflowEntry{deploy},
+			ours:   []WorkflowEntry{deployOurs},
+			theirs: []WorkflowEntry{deployTheirs},
+			want:   []WorkflowEntry{deployTheirs},
+		},
+		{
+			name:   "removal on one side",
+			base:   []WorkflowEntry{deploy, restart, rollback},
+			ours:   []WorkflowEntry{deploy, rollback},
+			theirs: []WorkflowEntry{deploy, restart},
+			want:   []WorkflowEntry{deploy},
+		},
+		{
+			name:   "removal against a change keeps the change",
+			base:   []WorkflowEntry{deploy},
+			ours:   []WorkflowEntry{},
+			theirs: []WorkflowEntry{deployTheirs},
+			want:   []WorkflowEntry{deployTheirs},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Merge(
+				&Index{Version: CurrentSchemaVersion, Workflows: tt.base},
+				&Index{Version: CurrentSchemaVersion, UpdatedAt: "2026-02-01T00:00:00Z", Workflows: tt.ours},
+				&Index{Version: CurrentSchemaVersion, UpdatedAt: "2026-03-01T00:00:00Z", Workflows: tt.theirs},
+			)
+			if !reflect.DeepEqual(got.Workflows, tt.want) {
+				t.Errorf("Merge() entries = %+v, want %+v", got.Workflows, tt.want)
+			}
+			if got.Version != CurrentSchemaVersion || got.UpdatedAt != "2026-03-01T00:00:00Z" || !got.VerifyChecksum() {
+				t.Errorf("Merge() = version %d, updated %s, checksum ok %v", got.Version, got.UpdatedAt, got.VerifyChecksum())
+			}
+		})
+	}
+
+	t.Run("older schema is left for a rebuild", func(t *testing.T) {
+		got := Merge(&Index{}, &Index{Version: CurrentSchemaVersion}, &Index{Version: CurrentSchemaVersion - 1})
+		if got.Version == CurrentSchemaVersion {
+			t.Errorf("Merge() version = %d, want an older version", got.Version)
+		}
+	})
+}
+
+func TestMergeFiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, idx *Index) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		data, err := json.Marshal(idx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	// Both sides created the index, so git passes an empty base
+	base := filepath.Join(dir, "base")
+	if err := os.WriteFile(base, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	ours := write("ours", &Index{Version: CurrentSchemaVersion, Workflows: []WorkflowEntry{{ID: "wf_a", Title: "A", Path: "a/workflow.yaml"}}})
+	theirs := write("theirs", &Index{Version: CurrentSchemaVersion, Workflows: []WorkflowEntry{{ID: "wf_b", Title: "B", Path: "b/workflow.yaml"}}})
+
+	if err := MergeFiles(base, ours, theirs); err != nil {
+		t.Fatalf("MergeFiles() error = %v", err)
+	}
+
+	builder := NewBuilder(dir, nil)
+	builder.config.Workflows.IndexPath = "ours"
+	idx, err := builder.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(idx.Workflows) != 2 || idx.Workflows[0].ID != "wf_a" || idx.Workflows[1].ID != "wf_b" {
+		t.Errorf("merged entries = %+v, want wf_a and wf_b", idx.Workflows)
+	}
+
+	if err := os.WriteFile(theirs, []byte("<<<<<<<"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := MergeFiles(base, ours, theirs); err == nil {
+		t.Error("MergeFiles() with a corrupt side succeeded, want error")
+	}
+}
@@ -0,0 +1,214 @@
+package index
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DefaultSynonyms map abbreviations people type to the words workflows
+// spell out, so a search for either finds both. The workflows.index.synonyms
+// config adds to and overrides them.
+var DefaultSynonyms = map[string]string{
+	"k8s":        "kubernetes",
+	"kube":       "kubernetes",
+	"db":         "database",
+	"pg":         "postgres",
+	"postgresql": "postgres",
+	"mongo":      "mongodb",
+	"prod":       "production",
+	"stg":        "staging",
+	"env":        "environment",
+	"auth":       "authentication",
+	"config":     "configuration",
+	"repo":       "repository",
+	"tf":         "terraform",
+	"gh":         "github",
+}
+
+// Normalizer turns text into search terms: lowercase words with synonyms
+// resolved and common English suffixes stripped, so "Restarting k8s pods"
+// and "restart kubernetes pod" have the same terms. The same normalizer
+// must build an index's terms and its queries'.
+type Normalizer struct {
+	synonyms map[string]string
+}
+
+// NewNormalizer returns a normalizer with the default synonyms plus extra,
+// which override them. A synonym mapped to itself disables a default.
+func NewNormalizer(extra map[string]string) *Normalizer {
+	synonyms := make(map[string]string, len(DefaultSynonyms)+len(extra))
+	for from, to := range DefaultSynonyms {
+		synonyms[from] = to
+	}
+	for from, to := range extra {
+		from, to = strings.ToLower(strings.TrimSpace(from)), strings.ToLower(strings.TrimSpace(to))
+		if from == "" {
+			continue
+		}
+		if to == "" || to == from {
+			delete(synonyms, from)
+			continue
+		}
+		synonyms[from] = to
+	}
+	return &Normalizer{synonyms: synonyms}
+}
+
+// Synonyms returns the synonyms the normalizer resolves.
+func (n *Normalizer) Synonyms() map[string]string {
+	return n.synonyms
+}
+
+// Terms returns the distinct, sorted terms of text.
+func (n *Normalizer) Terms(text string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, word := range words(text) {
+		for _, term := range n.normalize(word) {
+			if !seen[term] {
+				seen[term] = true
+				terms = append(terms, term)
+			}
+		}
+	}
+	sort.Strings(terms)
+	return terms
+}
+
+// QueryTerms returns the terms of a free-text query, or nil if the query
+// has characters other than words, spaces, hyphens and underscores: a
+// query like "/tmp/*" is meant literally.
+func (n *Normalizer) QueryTerms(query string) []string {
+	for _, r := range query {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsSpace(r) && r != '-' && r != '_' {
+			return nil
+		}
+	}
+	return n.Terms(query)
+}
+
+// normalize returns the terms of one lowercase word. A synonym may expand
+// to several words, e.g. "ha" = "high availability".
+func (n *Normalizer) normalize(word string) []string {
+	to, ok := n.synonyms[word]
+	if !ok {
+		// Plurals of an abbreviation, e.g. "dbs", are too short to stem
+		to, ok = n.synonyms[strings.TrimSuffix(word, "s")]
+	}
+	if !ok {
+		return []string{stem(word)}
+	}
+	var terms []string
+	for _, w := range words(to) {
+		terms = append(terms, stem(w))
+	}
+	return terms
+}
+
+// words splits lowercased text into runs of letters and digits.
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// stem strips common English suffixes: plurals, -ing, -ed and -ment, so
+// "deployments", "deploying" and "deployed" all become "deploy". It follows
+// the first steps of the Porter stemmer and is deliberately light: stems
+// need only be the same for forms of a word, not be words themselves, and
+// short words are left alone.
+func stem(word string) string {
+	if len(word) <= 3 {
+		return word
+	}
+	switch {
+	case strings.HasSuffix(word, "ies") && len(word) > 4:
+		word = word[:len(word)-3] + "y"
+	case strings.HasSuffix(word, "sses"):
+		word = word[:len(word)-2]
+	case strings.HasSuffix(word, "xes"), strings.HasSuffix(word, "ches"), strings.HasSuffix(word, "shes"):
+		word = word[:len(word)-2]
+	case strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") && !strings.HasSuffix(word, "us") && !strings.HasSuffix(word, "is"):
+		word = word[:len(word)-1]
+	}
+
+	for _, suffix := range []string{"ing", "ed"} {
+		if base, ok := strings.CutSuffix(word, suffix); ok && len(base) >= 3 && strings.ContainsAny(base, "aeiouy") {
+			word = restoreStem(base)
+			break
+		}
+	}
+	if base, ok := strings.CutSuffix(word, "ment"); ok && measure(base) > 1 {
+		word = base
+	}
+	// A final e comes and goes with suffixes: "restore", "restored"
+	if base, ok := strings.CutSuffix(word, "e"); ok && (measure(base) > 1 || measure(base) == 1 && !endsCVC(base)) {
+		word = base
+	}
+	return word
+}
+
+// restoreStem tidies a stem left by removing -ing or -ed: "stopp" becomes
+// "stop" and "shar" becomes "share", as the other forms of the word stem.
+func restoreStem(base string) string {
+	n := len(base)
+	switch {
+	case strings.HasSuffix(base, "at"), strings.HasSuffix(base, "bl"), strings.HasSuffix(base, "iz"):
+		return base + "e"
+	case base[n-1] == base[n-2] && isConsonant(base, n-1) && !strings.ContainsRune("lsz", rune(base[n-1])):
+		return base[:n-1]
+	case measure(base) == 1 && endsCVC(base):
+		return base + "e"
+	}
+	return base
+}
+
+// isConsonant reports whether the letter at i is a consonant; y is one
+// after a vowel or at the start.
+func isConsonant(s string, i int) bool {
+	switch s[i] {
+	case 'a', 'e', 'i', 'o', 'u':
+		return false
+	case 'y':
+		return i == 0 || !isConsonant(s, i-1)
+	}
+	return true
+}
+
+// measure counts the vowel-consonant sequences in s, roughly its syllables
+// after the first: 0 for "tr", 1 for "trouble", 2 for "troubles".
+func measure(s string) int {
+	m := 0
+	vowel := false
+	for i := range len(s) {
+		if isConsonant(s, i) {
+			if vowel {
+				m++
+			}
+			vowel = false
+		} else {
+			vowel = true
+		}
+	}
+	return m
+}
+
+// endsCVC reports whether s ends consonant-vowel-consonant with the last
+// not w, x or y, as short words such as "hop" and "shar" do.
+func endsCVC(s string) bool {
+	n := len(s)
+	return n >= 3 && isConsonant(s, n-3) && !isConsonant(s, n-2) && isConsonant(s, n-1) &&
+		!strings.ContainsRune("wxy", rune(s[n-1]))
+}
+
+// hasTerms reports whether all of want are in the sorted terms.
+func hasTerms(terms, want []string) bool {
+	for _, w := range want {
+		i := sort.SearchStrings(terms, w)
+		if i == len(terms) || terms[i] != w {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,140 @@
+package index
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestStem(t *testing.T) {
+	tests := map[string]string{
+		"deployments": "deploy",
+		"deploying":   "deploy",
+		"deployed":    "deploy",
+		"restarting":  "restart",
+		"stopped":     "stop",
+		"shared":      "share",
+		"restored":    "restor",
+		"restore":     "restor",
+		"policies":    "policy",
+		"fixes":       "fix",
+		"pods":        "pod",
+		"status":      "status",
+		"process":     "process",
+		"logs":        "log",
+		"red":         "red",
+	}
+	for word, want := range tests {
+		if got := stem(word); got != want {
+			t.Errorf("stem(%q) = %q, want %q", word, got, want)
+		}
+	}
+}
+
+func TestNormalizer_Terms(t *testing.T) {
+	tests := []struct {
+		name     string
+		synonyms map[string]string
+		text     string
+		want     []string
+	}{
+		{
+			name: "synonyms and stems",
+			text: "Restarting k8s pods",
+			want: []string{"kubernet", "pod", "restart"},
+		},
+		{
+			name: "plural abbreviation",
+			text: "Back up prod DBs",
+			want: []string{"back", "databas", "production", "up"},
+		},
+		{
+			name: "duplicates removed",
+			text: "db database databases",
+			want: []string{"databas"},
+		},
+		{
+			name:     "configured synonym",
+			synonyms: map[string]string{"ha": "high availability"},
+			text:     "HA failover",
+			want:     []string{"availability", "failover", "high"},
+		},
+		{
+			name:     "built-in synonym disabled",
+			synonyms: map[string]string{"tf": "tf"},
+			text:     "tf plan",
+			want:     []string{"plan", "tf"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewNormalizer(tt.synonyms).Terms(tt.text)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Terms(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizer_QueryTerms(t *testing.T) {
+	n := NewNormalizer(nil)
+	if got := n.QueryTerms("rolling-restart k8s"); !reflect.DeepEqual(got, []string{"kubernet", "restart", "roll"}) {
+		t.Errorf("QueryTerms() = %v", got)
+	}
+	if got := n.QueryTerms("/tmp/*"); got != nil {
+		t.Errorf("QueryTerms(%q) = %v, want nil", "/tmp/*", got)
+	}
+}
+
+func TestIndex_QueryNormalized(t *testing.T) {
+	tmpDir, cfg, _ := setupTestIndex(t)
+	writeTestWorkflow(t, filepath.Join(tmpDir, "workflows", "platform", "test", "restart"), "Restart Kubernetes pod")
+	cfg.Workflows.Index.Synonyms = map[string]string{"bounce": "restart"}
+	idx, err := NewBuilder(tmpDir, cfg).Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	for _, query := range []string{"restarting k8s pods", "bounce kube pod"} {
+		results, err := idx.Query(SearchOptions{Query: query})
+		if err != nil {
+			t.Fatalf("Query(%q) error = %v", query, err)
+		}
+		if len(results) != 1 || results[0].Entry.Title != "Restart Kubernetes pod" {
+			t.Errorf("Query(%q) = %+v, want the restart workflow", query, results)
+		}
+	}
+	if got := idx.Search("k8s"); len(got) != 1 {
+		t.Errorf("Search(%q) returned %d results, want 1", "k8s", len(got))
+	}
+}
+
+func TestBuilder_SynonymsChanged(t *testing.T) {
+	tmpDir, cfg, builder := setupTestIndex(t)
+	idx, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if err := builder.Save(idx); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	cfg.Workflows.Index.Synonyms = map[string]string{"wf": "workflow"}
+	changed := NewBuilder(tmpDir, cfg)
+	if stale, err := changed.IsStale(); err != nil || !stale {
+		t.Errorf("IsStale() = %v, %v after the synonyms changed, want true", stale, err)
+	}
+
+	// Update rebuilds every entry's terms, matching a full rebuild
+	if !changed.Update(idx, nil) {
+		t.Fatal("Update() = false after the synonyms changed")
+	}
+	full, err := changed.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if idx.ComputeChecksum() != full.ComputeChecksum() || !reflect.DeepEqual(idx.Synonyms, full.Synonyms) {
+		t.Error("updated index differs from a full rebuild")
+	}
+}
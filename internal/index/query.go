@@ -0,0 +1,215 @@
+package index
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// queryFields maps the field prefixes accepted in queries to the field
+// they search.
+var queryFields = map[string]string{
+	"title":       "title",
+	"desc":        "desc",
+	"description": "desc",
+	"tag":         "tag",
+	"tags":        "tag",
+	"cmd":         "cmd",
+	"command":     "cmd",
+	"path":        "path",
+	"id":          "id",
+	"kind":        "kind",
+}
+
+// queryTerm is a single term of a search query.
+type queryTerm struct {
+	// Field is the field the term is scoped to, or "" for free text.
+	Field string
+
+	// Value is the unquoted, unescaped term value.
+	Value string
+
+	// re is the compiled value in regex mode.
+	re *regexp.Regexp
+}
+
+// parseQuery splits a query into terms. Terms are separated by spaces;
+// double quotes group words into one term and a backslash escapes the
+// next character, so cmd:"kubectl delete" and cmd:kubectl\ delete are
+// the same term. A term prefixed with a known field (title:, desc:, tag:,
+// cmd:, path:, id:, kind:) only matches that field; anything else is free text.
+// In regex mode every term value is compiled as a case-insensitive
+// regular expression, and backslashes other than \" and \<space> are
+// passed through to it.
+func parseQuery(query string, regex bool) ([]queryTerm, error) {
+	var terms []queryTerm
+
+	var value strings.Builder
+	field := ""
+	inToken, inQuote, quoted := false, false, false
+
+	flush := func() error {
+		if !inToken {
+			return nil
+		}
+		term := queryTerm{Field: field, Value: value.String()}
+		// A bare "tag:" is searched for literally
+		if term.Field != "" && term.Value == "" && !quoted {
+			term = queryTerm{Value: field + ":"}
+		}
+		if regex {
+			re, err := regexp.Compile("(?i)" + term.Value)
+			if err != nil {
+				return fmt.Errorf("invalid regular expression %q: %w", term.Value, err)
+			}
+			term.re = re
+		}
+		terms = append(terms, term)
+
+		value.Reset()
+		field = ""
+		inToken, quoted = false, false
+		return nil
+	}
+
+	runes := []rune(query)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\\' && i+1 < len(runes):
+			i++
+			// Regex escapes like \s are kept; only quotes and spaces are unescaped
+			if regex && runes[i] != '"' && runes[i] != ' ' {
+				value.WriteRune(r)
+			}
+			value.WriteRune(runes[i])
+			inToken = true
+		case r == '"':
+			inQuote = !inQuote
+			inToken, quoted = true, true
+		case r == ' ' || r == '\t':
+			if inQuote {
+				value.WriteRune(r)
+				continue
+			}
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		case r == ':' && !inQuote && !quoted && field == "":
+			name, ok := queryFields[strings.ToLower(value.String())]
+			if !ok {
+				value.WriteRune(r)
+				inToken = true
+				continue
+			}
+			field = name
+			value.Reset()
+			inToken = true
+		default:
+			value.WriteRune(r)
+			inToken = true
+		}
+	}
+	if inQuote {
+		return nil, fmt.Errorf("unterminated quote in query %q", query)
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	return terms, nil
+}
+
+// fieldValues returns the values of an entry's field.
+func fieldValues(entry WorkflowEntry, field string) []string {
+	switch field {
+	case "title":
+		return []string{entry.Title}
+	case "desc":
+		return []string{entry.Description}
+	case "tag":
+		return entry.Tags
+	case "cmd":
+		return entry.Commands
+	case "path":
+		return []string{entry.Path}
+	case "id":
+		return []string{entry.ID}
+	case "kind":
+		return []string{entry.kind()}
+	}
+	return nil
+}
+
+// matchFieldTerm reports whether a field-scoped term matches an entry.
+// Tags and kinds must match exactly (ignoring case); other fields match if
+// they contain the value.
+func matchFieldTerm(entry WorkflowEntry, term queryTerm) bool {
+	for _, v := range fieldValues(entry, term.Field) {
+		switch {
+		case term.re != nil:
+			if term.re.MatchString(v) {
+				return true
+			}
+		case term.Field == "tag" || term.Field == "kind":
+			if strings.EqualFold(strings.TrimSpace(v), term.Value) {
+				return true
+			}
+		default:
+			if strings.Contains(strings.ToLower(v), strings.ToLower(term.Value)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// scoreRegexTerms scores an entry against free-text terms in regex mode.
+// Every term must match the title, tags, description or a command.
+func scoreRegexTerms(entry WorkflowEntry, terms []queryTerm) (float64, []string) {
+	if len(terms) == 0 {
+		return 1.0, []string{}
+	}
+
+	var score float64
+	var matches []string
+	for _, term := range terms {
+		var termScore float64
+		if term.re.MatchString(entry.Title) {
+			termScore += 50
+			if !contains(matches, "title") {
+				matches = append(matches, "title")
+			}
+		}
+		for _, tag := range entry.Tags {
+			if term.re.MatchString(tag) {
+				termScore += 15
+				if !contains(matches, "tags") {
+					matches = append(matches, "tags")
+				}
+				break
+			}
+		}
+		if term.re.MatchString(entry.Description) || anyMatch(term.re, entry.Commands) {
+			termScore += 10
+			if !contains(matches, "content") {
+				matches = append(matches, "content")
+			}
+		}
+		if termScore == 0 {
+			return 0, nil
+		}
+		score += termScore
+	}
+	return score, matches
+}
+
+// anyMatch reports whether re matches any of values.
+func anyMatch(re *regexp.Regexp, values []string) bool {
+	for _, v := range values {
+		if re.MatchString(v) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,159 @@
+package index
+
+import (
+	"testing"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    []queryTerm
+		wantErr bool
+	}{
+		{
+			name:  "free text",
+			query: "deploy api",
+			want:  []queryTerm{{Value: "deploy"}, {Value: "api"}},
+		},
+		{
+			name:  "fields",
+			query: `tag:deploy cmd:"kubectl delete"`,
+			want:  []queryTerm{{Field: "tag", Value: "deploy"}, {Field: "cmd", Value: "kubectl delete"}},
+		},
+		{
+			name:  "field aliases are case insensitive",
+			query: "Command:helm Tags:k8s description:prod",
+			want:  []queryTerm{{Field: "cmd", Value: "helm"}, {Field: "tag", Value: "k8s"}, {Field: "desc", Value: "prod"}},
+		},
+		{
+			name:  "backslash escapes",
+			query: `cmd:kubectl\ delete "say \"hi\""`,
+			want:  []queryTerm{{Field: "cmd", Value: "kubectl delete"}, {Value: `say "hi"`}},
+		},
+		{
+			name:  "unknown field is free text",
+			query: "http://example.com",
+			want:  []queryTerm{{Value: "http://example.com"}},
+		},
+		{
+			name:  "only the first colon splits",
+			query: "cmd:a:b",
+			want:  []queryTerm{{Field: "cmd", Value: "a:b"}},
+		},
+		{
+			name:  "bare field is free text",
+			query: "tag:",
+			want:  []queryTerm{{Value: "tag:"}},
+		},
+		{
+			name:    "unterminated quote",
+			query:   `cmd:"kubectl delete`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseQuery(tt.query, false)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseQuery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseQuery() = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i].Field != tt.want[i].Field || got[i].Value != tt.want[i].Value {
+					t.Errorf("term %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestIndex_QueryFields(t *testing.T) {
+	_, _, builder := setupTestIndex(t)
+
+	index, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		opts    SearchOptions
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "command phrase",
+			opts: SearchOptions{Query: `cmd:"docker push"`},
+			want: []string{"Deployment Workflow"},
+		},
+		{
+			name: "tag must match whole tag",
+			opts: SearchOptions{Query: "tag:deploy"},
+			want: nil,
+		},
+		{
+			name: "tag and command",
+			opts: SearchOptions{Query: "tag:shared cmd:rm"},
+			want: []string{"Shared Utility"},
+		},
+		{
+			name: "field and free text",
+			opts: SearchOptions{Query: "desc:test hello"},
+			want: []string{"Test Workflow 1"},
+		},
+		{
+			name: "path",
+			opts: SearchOptions{Query: "path:shared/"},
+			want: []string{"Shared Utility"},
+		},
+		{
+			name: "regex command",
+			opts: SearchOptions{Query: `cmd:^docker\s+(build|push)\b`, Regex: true},
+			want: []string{"Deployment Workflow"},
+		},
+		{
+			name: "regex free text",
+			opts: SearchOptions{Query: "^echo", Regex: true},
+			want: []string{"Test Workflow 1"},
+		},
+		{
+			name: "regex metacharacters are literal without --regex",
+			opts: SearchOptions{Query: "cmd:-rf /tmp/*"},
+			want: nil,
+		},
+		{
+			name:    "invalid regex",
+			opts:    SearchOptions{Query: "cmd:(", Regex: true},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := index.Query(tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Query() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(results) != len(tt.want) {
+				t.Fatalf("Query() returned %d results, want %d: %+v", len(results), len(tt.want), results)
+			}
+			for i, title := range tt.want {
+				if results[i].Entry.Title != title {
+					t.Errorf("result %d = %q, want %q", i, results[i].Entry.Title, title)
+				}
+			}
+		})
+	}
+
+	// FuzzySearch hides the error and matches nothing
+	if got := index.FuzzySearch(SearchOptions{Query: "cmd:(", Regex: true}); len(got) != 0 {
+		t.Errorf("FuzzySearch() with invalid regex = %d results, want 0", len(got))
+	}
+}
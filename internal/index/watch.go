@@ -0,0 +1,238 @@
+package index
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// DefaultWatchDebounce is how long Watch waits for file events to settle
+// before updating the index. Editors often write a file in several steps.
+const DefaultWatchDebounce = 200 * time.Millisecond
+
+// WatchOptions configures Watch.
+type WatchOptions struct {
+	// Debounce is the quiet period before applying changes.
+	// Zero means DefaultWatchDebounce.
+	Debounce time.Duration
+
+	// OnUpdate is called after the index is saved with the changed paths.
+	OnUpdate func(idx *Index, paths []string)
+
+	// OnError is called for errors that don't stop the watcher.
+	OnError func(err error)
+}
+
+// Update re-indexes the given paths in idx. Each path may be a workflow
+// file or a directory, absolute or relative to the repo. Entries for files
+// that no longer exist are removed and directories are rescanned, and all
+// entries' terms are rebuilt if the configured synonyms changed. Reports
+// whether the index changed.
+func (b *Builder) Update(idx *Index, paths []string) bool {
+	before := idx.ComputeChecksum()
+
+	var jobs []indexJob
+	for _, p := range paths {
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(b.repoPath, p)
+		}
+		p = filepath.Clean(p)
+
+		// Drop entries for the path and anything below it
+		kept := idx.Workflows[:0]
+		for _, entry := range idx.Workflows {
+			abs := filepath.Join(b.repoPath, entry.Path)
+			if abs != p && !strings.HasPrefix(abs, p+string(filepath.Separator)) {
+				kept = append(kept, entry)
+			}
+		}
+		idx.Workflows = kept
+
+		r, ok := b.rootFor(p)
+		if !ok {
+			continue
+		}
+		info, err := os.Stat(p)
+		if err != nil {
+			continue // Removed
+		}
+
+		if info.IsDir() {
+			identityPath, _ := filepath.Rel(filepath.Join(b.repoPath, r.dir), p)
+			if identityPath == "." {
+				identityPath = ""
+			}
+			var warnings []string
+			if err := b.scanDirectoryRecursive(p, identityPath, &jobs, &warnings, r); err != nil {
+				warnings = append(warnings, fmt.Sprintf("failed to scan %s: %v", p, err))
+			}
+			for _, w := range warnings {
+				fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+			}
+			continue
+		}
+
+		if !workflows.IsWorkflowFile(filepath.Base(p)) {
+			continue
+		}
+		dir := filepath.Dir(p)
+		identityPath, _ := filepath.Rel(filepath.Join(b.repoPath, r.dir), dir)
+		jobs = append(jobs, indexJob{
+			path:         p,
+			identityPath: identityPath,
+			slug:         filepath.Base(dir),
+			root:         r,
+		})
+	}
+
+	// Synonyms changed in the config since the terms were built
+	synonyms := b.synonyms()
+	synonymsChanged := !maps.Equal(idx.Synonyms, synonyms)
+	if synonymsChanged {
+		for j := range idx.Workflows {
+			idx.Workflows[j].Terms = b.normalizer.Terms(idx.Workflows[j].SearchText)
+		}
+		idx.Synonyms = synonyms
+	}
+
+	// A directory and a file inside it may both be listed
+	seen := make(map[string]bool)
+	for _, entry := range b.indexAll(jobs) {
+		if !seen[entry.Location()] {
+			seen[entry.Location()] = true
+			idx.Workflows = append(idx.Workflows, *entry)
+		}
+	}
+	idx.sortEntries()
+
+	if idx.ComputeChecksum() == before && !synonymsChanged {
+		return false
+	}
+	idx.UpdatedAt = b.clock.Now().Format(time.RFC3339)
+	return true
+}
+
+// rootFor returns the root containing path.
+func (b *Builder) rootFor(path string) (root, bool) {
+	for _, r := range b.roots() {
+		dir := filepath.Join(b.repoPath, r.dir)
+		if path == dir || strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return r, true
+		}
+	}
+	return root{}, false
+}
+
+// Watch keeps the saved index up to date as workflow files change until ctx
+// is canceled. The index is loaded (or rebuilt) first, then each batch of
+// file events is applied incrementally with Update and saved.
+func (b *Builder) Watch(ctx context.Context, opts WatchOptions) error {
+	if opts.Debounce <= 0 {
+		opts.Debounce = DefaultWatchDebounce
+	}
+	onError := opts.OnError
+	if onError == nil {
+		onError = func(err error) { fmt.Fprintf(os.Stderr, "Warning: %v\n", err) }
+	}
+
+	idx, _, err := b.LoadOrRebuild()
+	if err != nil {
+		return err
+	}
+
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("creating watcher: %w", err)
+	}
+	defer func() { _ = w.Close() }()
+
+	for _, r := range b.roots() {
+		if err := addWatchTree(w, filepath.Join(b.repoPath, r.dir)); err != nil {
+			return fmt.Errorf("watching %s: %w", r.dir, err)
+		}
+	}
+
+	pending := make(map[string]bool)
+	timer := time.NewTimer(opts.Debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event, ok := <-w.Events:
+			if !ok {
+				return nil
+			}
+			if event.Has(fsnotify.Create) {
+				// Watch new directories before files appear in them
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if err := addWatchTree(w, event.Name); err != nil {
+						onError(fmt.Errorf("watching %s: %w", event.Name, err))
+					}
+				}
+			}
+			if event.Has(fsnotify.Chmod) && !event.Has(fsnotify.Write) {
+				continue
+			}
+			if rel, err := filepath.Rel(b.repoPath, event.Name); err == nil {
+				pending[rel] = true
+			}
+			timer.Reset(opts.Debounce)
+
+		case err, ok := <-w.Errors:
+			if !ok {
+				return nil
+			}
+			onError(err)
+
+		case <-timer.C:
+			paths := make([]string, 0, len(pending))
+			for p := range pending {
+				paths = append(paths, p)
+			}
+			clear(pending)
+
+			if !b.Update(idx, paths) {
+				continue
+			}
+			if err := b.Save(idx); err != nil {
+				onError(fmt.Errorf("saving index: %w", err))
+				continue
+			}
+			if opts.OnUpdate != nil {
+				opts.OnUpdate(idx, paths)
+			}
+		}
+	}
+}
+
+// addWatchTree watches dir and every directory below it, skipping hidden
+// directories. A missing dir is ignored.
+func addWatchTree(w *fsnotify.Watcher, dir string) error {
+	return filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != dir && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		return w.Add(path)
+	})
+}
@@ -0,0 +1,138 @@
+package index
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// writeTestWorkflow writes a workflow with the given title to dir.
+func writeTestWorkflow(t *testing.T, dir, title string) string {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	data, err := workflows.MarshalWorkflow(&workflows.Workflow{
+		SchemaVersion: 1,
+		Title:         title,
+		Steps:         []workflows.Step{{Name: "Step", Command: "true"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "workflow.yaml")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestBuilder_Update(t *testing.T) {
+	tmpDir, _, builder := setupTestIndex(t)
+	idx, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	// Nothing changed
+	if builder.Update(idx, []string{"workflows/platform/test/workflow1/workflow.yaml"}) {
+		t.Error("Update() = true for an unchanged file")
+	}
+
+	// Edited file
+	wf1 := writeTestWorkflow(t, filepath.Join(tmpDir, "workflows", "platform", "test", "workflow1"), "Renamed Workflow")
+	if !builder.Update(idx, []string{wf1}) {
+		t.Fatal("Update() = false for an edited file")
+	}
+	if entry := idx.GetByPath("workflows/platform/test/workflow1/workflow.yaml"); entry == nil || entry.Title != "Renamed Workflow" {
+		t.Errorf("edited entry = %+v, want title %q", entry, "Renamed Workflow")
+	}
+
+	// New directory
+	writeTestWorkflow(t, filepath.Join(tmpDir, "workflows", "platform", "test", "nested", "added"), "Added Workflow")
+	if !builder.Update(idx, []string{filepath.Join(tmpDir, "workflows", "platform", "test", "nested")}) {
+		t.Fatal("Update() = false for a new directory")
+	}
+	if len(idx.Workflows) != 4 {
+		t.Errorf("Workflows count = %d, want 4", len(idx.Workflows))
+	}
+
+	// Removed directory
+	if err := os.RemoveAll(filepath.Join(tmpDir, "shared", "common")); err != nil {
+		t.Fatal(err)
+	}
+	if !builder.Update(idx, []string{"shared/common"}) {
+		t.Fatal("Update() = false for a removed directory")
+	}
+	if len(idx.Workflows) != 3 {
+		t.Errorf("Workflows count = %d, want 3", len(idx.Workflows))
+	}
+
+	// The result matches a full rebuild
+	full, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if idx.ComputeChecksum() != full.ComputeChecksum() {
+		t.Error("incremental index differs from a full rebuild")
+	}
+}
+
+func TestBuilder_Watch(t *testing.T) {
+	tmpDir, _, builder := setupTestIndex(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	updates := make(chan int, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- builder.Watch(ctx, WatchOptions{
+			Debounce: 20 * time.Millisecond,
+			OnUpdate: func(idx *Index, _ []string) { updates <- len(idx.Workflows) },
+		})
+	}()
+
+	// Wait for the initial index so the watcher is running
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := os.Stat(builder.GetIndexPath()); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("index was not built")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Retry the write until the watcher picks it up; it may not have
+	// registered its watches yet
+	dir := filepath.Join(tmpDir, "workflows", "platform", "test", "watched")
+	for got := 0; got != 4; {
+		writeTestWorkflow(t, dir, "Watched Workflow")
+		select {
+		case got = <-updates:
+		case <-time.After(500 * time.Millisecond):
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("watcher did not index the new workflow")
+		}
+	}
+
+	idx, err := builder.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if idx.GetByPath("workflows/platform/test/watched/workflow.yaml") == nil {
+		t.Error("saved index is missing the new workflow")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Watch() error = %v", err)
+	}
+}
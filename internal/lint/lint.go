@@ -0,0 +1,75 @@
+// Package lint finds problems in workflows: errors that would fail
+// validation and warnings about placeholders and dangerous commands.
+package lint
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/chazuruo/svf/internal/placeholders"
+	"github.com/chazuruo/svf/internal/runner"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// Severity levels for lint problems.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Problem is a problem found in a workflow.
+type Problem struct {
+	Severity string `json:"severity"`
+	Step     int    `json:"step,omitempty"` // 1-based step number, 0 for the whole workflow
+	Message  string `json:"message"`
+}
+
+// Check checks a workflow for errors (it would fail validation) and
+// warnings (unused or undocumented placeholders, dangerous commands).
+func Check(wf *workflows.Workflow) []Problem {
+	var problems []Problem
+
+	if err := wf.Validate(); err != nil {
+		problems = append(problems, Problem{Severity: SeverityError, Message: err.Error()})
+	}
+	if err := placeholders.ValidateAtLoadTime(wf); err != nil {
+		problems = append(problems, Problem{Severity: SeverityError, Message: err.Error()})
+	}
+
+	used := make(map[string]bool)
+	for _, name := range placeholders.CollectFromSteps(wf.Steps) {
+		used[name] = true
+		if _, ok := wf.Placeholders[name]; !ok && wf.CaptureStep(name) < 0 {
+			problems = append(problems, Problem{
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("placeholder <%s> is used but not declared, so it has no prompt or default", name),
+			})
+		}
+	}
+
+	var unused []string
+	for name := range wf.Placeholders {
+		if !used[name] {
+			unused = append(unused, name)
+		}
+	}
+	sort.Strings(unused)
+	for _, name := range unused {
+		problems = append(problems, Problem{
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("placeholder <%s> is declared but not used by any step", name),
+		})
+	}
+
+	for i, step := range wf.Steps {
+		if info := runner.CheckDangerous(step.Command); info != nil {
+			problems = append(problems, Problem{
+				Severity: SeverityWarning,
+				Step:     i + 1,
+				Message:  fmt.Sprintf("%s: %s", info.Name, info.Risk),
+			})
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,41 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+func TestCheck(t *testing.T) {
+	wf := &workflows.Workflow{
+		Title: "Cleanup",
+		Placeholders: map[string]workflows.Placeholder{
+			"unused": {},
+		},
+		Steps: []workflows.Step{{Command: "rm -rf /<dir>"}},
+	}
+
+	problems := Check(wf)
+	if len(problems) != 3 {
+		t.Fatalf("Check() = %v, want 3 problems", problems)
+	}
+	for _, p := range problems {
+		if p.Severity != SeverityWarning {
+			t.Errorf("Check() severity = %q, want warning: %s", p.Severity, p.Message)
+		}
+	}
+	if !strings.Contains(problems[0].Message, "<dir> is used but not declared") {
+		t.Errorf("problems[0] = %q, want undeclared <dir>", problems[0].Message)
+	}
+	if !strings.Contains(problems[1].Message, "<unused> is declared but not used") {
+		t.Errorf("problems[1] = %q, want unused <unused>", problems[1].Message)
+	}
+	if problems[2].Step != 1 {
+		t.Errorf("problems[2].Step = %d, want 1", problems[2].Step)
+	}
+
+	if got := Check(&workflows.Workflow{Title: "Empty"}); got[0].Severity != SeverityError {
+		t.Errorf("Check(empty) = %v, want an error", got)
+	}
+}
@@ -0,0 +1,358 @@
+// Package lock provides a per-repository file lock that serializes svf
+// operations which modify the repository or its index.
+//
+// The lock is a file created exclusively in the repository's .git
+// directory. It records the holder's PID, host and operation so a lock
+// left behind by a crashed process can be detected and broken. Locks are
+// reentrant within a process, so an operation holding the lock (such as
+// sync) can call others that take it (such as index rebuilds).
+package lock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTimeout is how long Acquire waits for a held lock.
+	DefaultTimeout = 5 * time.Second
+
+	// DefaultStaleAfter is the age after which a lock held by a process on
+	// another host is considered stale.
+	DefaultStaleAfter = time.Hour
+
+	// pollInterval is how often Acquire retries a held lock.
+	pollInterval = 100 * time.Millisecond
+
+	// incompleteGrace is how long an unreadable lock file (e.g. one being
+	// written) is respected before it is treated as stale.
+	incompleteGrace = 5 * time.Second
+
+	// fileName is the name of the lock file.
+	fileName = "svf.lock"
+)
+
+// ErrLocked is returned when another svf process holds the lock.
+var ErrLocked = errors.New("another svf operation is in progress")
+
+// Info describes the holder of a lock.
+type Info struct {
+	PID        int       `json:"pid"`
+	Host       string    `json:"host"`
+	Op         string    `json:"op"`
+	User       string    `json:"user,omitempty"`
+	AcquiredAt time.Time `json:"acquired_at"`
+}
+
+// Options configures Acquire.
+type Options struct {
+	// Op names the operation taking the lock (e.g. "sync"), shown to
+	// other processes that find the lock held.
+	Op string
+
+	// User identifies who takes the lock (e.g. an identity path), shown
+	// along with Op.
+	User string
+
+	// Timeout is how long to wait for a held lock (default DefaultTimeout).
+	// A negative value fails immediately.
+	Timeout time.Duration
+
+	// StaleAfter overrides DefaultStaleAfter.
+	StaleAfter time.Duration
+
+	// NoReentry treats the lock as held when this process already holds
+	// it, for locks that concurrent goroutines must not share, such as the
+	// run lock of a workflow the API server runs.
+	NoReentry bool
+}
+
+// Lock is a held repository lock.
+type Lock struct {
+	path string
+}
+
+// held tracks locks held by this process, for reentrancy.
+var (
+	heldMu sync.Mutex
+	held   = make(map[string]int)
+)
+
+// Path returns the lock file path for a repository. The lock lives in the
+// .git directory so it is never committed; repositories without one use a
+// hidden file in the root. Worktrees share the lock of the repository
+// they belong to.
+func Path(repoPath string) string {
+	if gitDir := commonGitDir(repoPath); gitDir != "" {
+		return filepath.Join(gitDir, fileName)
+	}
+	return filepath.Join(repoPath, "."+fileName)
+}
+
+// commonGitDir returns the git directory of the repository at repoPath,
+// like git rev-parse --git-common-dir: .git itself, or for a worktree,
+// whose .git is a file pointing into the main repository's, the main
+// repository's. It returns "" when there is none.
+func commonGitDir(repoPath string) string {
+	gitDir := filepath.Join(repoPath, ".git")
+	info, err := os.Stat(gitDir)
+	if err != nil {
+		return ""
+	}
+	if info.IsDir() {
+		return gitDir
+	}
+
+	// A worktree's .git file reads "gitdir: <dir>"; <dir>/commondir
+	// points from there to the main repository's git directory
+	data, err := os.ReadFile(gitDir)
+	if err != nil {
+		return ""
+	}
+	dir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return ""
+	}
+	dir = resolve(repoPath, strings.TrimSpace(dir))
+	if common, err := os.ReadFile(filepath.Join(dir, "commondir")); err == nil {
+		dir = resolve(dir, strings.TrimSpace(string(common)))
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return ""
+	}
+	return dir
+}
+
+// resolve returns path, resolved relative to base unless it is absolute.
+func resolve(base, path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(base, path)
+}
+
+// NamedPath returns the path of a lock on something within a repository
+// other than the repository itself, e.g. a workflow by its ID. Like the
+// repository lock, it lives in the .git directory when there is one.
+func NamedPath(repoPath, name string) string {
+	dir := filepath.Join(filepath.Dir(Path(repoPath)), "svf-locks")
+	if filepath.Dir(Path(repoPath)) == repoPath {
+		dir = filepath.Join(repoPath, ".svf-locks")
+	}
+	return filepath.Join(dir, name+".lock")
+}
+
+// Acquire takes the lock for a repository, waiting up to opts.Timeout
+// for another process to release it. Stale locks left by processes that
+// no longer exist are broken automatically. The returned error wraps
+// ErrLocked when the lock could not be taken in time.
+func Acquire(repoPath string, opts Options) (*Lock, error) {
+	return AcquireFile(Path(repoPath), opts)
+}
+
+// AcquireFile is Acquire for the lock file at path, such as a NamedPath.
+func AcquireFile(path string, opts Options) (*Lock, error) {
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+	staleAfter := opts.StaleAfter
+	if staleAfter == 0 {
+		staleAfter = DefaultStaleAfter
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		ok, info, err := tryAcquire(path, opts, staleAfter)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return &Lock{path: path}, nil
+		}
+
+		if time.Now().After(deadline) {
+			return nil, lockedError(path, info)
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// tryAcquire makes one attempt to take the lock, breaking it if stale.
+// It returns the holder info when the lock is held by another process.
+func tryAcquire(path string, opts Options, staleAfter time.Duration) (bool, *Info, error) {
+	heldMu.Lock()
+	defer heldMu.Unlock()
+
+	if held[path] > 0 {
+		if opts.NoReentry {
+			info, _ := readInfo(path)
+			return false, info, nil
+		}
+		held[path]++
+		return true, nil, nil
+	}
+
+	for {
+		err := create(path, opts)
+		if err == nil {
+			held[path] = 1
+			return true, nil, nil
+		}
+		if !os.IsExist(err) {
+			return false, nil, fmt.Errorf("failed to create lock file: %w", err)
+		}
+
+		info, stale := checkStale(path, staleAfter)
+		if !stale {
+			return false, info, nil
+		}
+
+		// Break the stale lock and retry immediately
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return false, nil, fmt.Errorf("failed to remove stale lock: %w", err)
+		}
+	}
+}
+
+// Release releases the lock. Releasing an already released lock is a no-op.
+func (l *Lock) Release() error {
+	if l == nil || l.path == "" {
+		return nil
+	}
+
+	heldMu.Lock()
+	defer heldMu.Unlock()
+
+	path := l.path
+	l.path = ""
+
+	if held[path] == 0 {
+		return nil
+	}
+	held[path]--
+	if held[path] > 0 {
+		return nil
+	}
+	delete(held, path)
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to release lock: %w", err)
+	}
+	return nil
+}
+
+// Read returns the current holder of a repository's lock. It returns an
+// error satisfying os.IsNotExist when the lock is free.
+func Read(repoPath string) (*Info, error) {
+	return ReadFile(Path(repoPath))
+}
+
+// ReadFile is Read for the lock file at path.
+func ReadFile(path string) (*Info, error) {
+	return readInfo(path)
+}
+
+// Break removes the lock file at path whoever holds it, for locks the
+// user knows to be abandoned. Breaking a free lock is a no-op.
+func Break(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to break lock: %w", err)
+	}
+	return nil
+}
+
+// create exclusively creates the lock file with this process's info.
+func create(path string, opts Options) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	host, _ := os.Hostname()
+	data, _ := json.Marshal(Info{
+		PID:        os.Getpid(),
+		Host:       host,
+		Op:         opts.Op,
+		User:       opts.User,
+		AcquiredAt: time.Now(),
+	})
+	_, werr := f.Write(data)
+	cerr := f.Close()
+	if werr != nil || cerr != nil {
+		_ = os.Remove(path)
+		if werr != nil {
+			return werr
+		}
+		return cerr
+	}
+	return nil
+}
+
+// readInfo reads a lock file.
+func readInfo(path string) (*Info, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var info Info
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("invalid lock file: %w", err)
+	}
+	return &info, nil
+}
+
+// checkStale reports whether the lock at path was left behind by a
+// process that is gone. It also returns the holder info when readable.
+func checkStale(path string, staleAfter time.Duration) (*Info, bool) {
+	info, err := readInfo(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// Released between our create and read; retry
+			return nil, true
+		}
+		// Unreadable: give a concurrent writer time to finish
+		st, statErr := os.Stat(path)
+		return nil, statErr == nil && time.Since(st.ModTime()) > incompleteGrace
+	}
+
+	host, _ := os.Hostname()
+	if info.Host == host {
+		return info, !processAlive(info.PID)
+	}
+
+	// We can't check processes on other hosts (e.g. a shared network
+	// filesystem), so fall back to the lock's age
+	return info, time.Since(info.AcquiredAt) > staleAfter
+}
+
+// lockedError describes who holds the lock.
+func lockedError(path string, info *Info) error {
+	if info == nil {
+		return fmt.Errorf("%w; if no other svf process is running, remove %s", ErrLocked, path)
+	}
+	return fmt.Errorf("%w (%s); if that process is no longer running, remove %s", ErrLocked, info, path)
+}
+
+// String describes the holder, e.g. "sync by team/alice, pid 42 on
+// laptop, started 2024-01-02T03:04:05Z".
+func (info Info) String() string {
+	op := info.Op
+	if op == "" {
+		op = "unknown operation"
+	}
+	if info.User != "" {
+		op += " by " + info.User
+	}
+	return fmt.Sprintf("%s, pid %d on %s, started %s", op, info.PID, info.Host, info.AcquiredAt.Format(time.RFC3339))
+}
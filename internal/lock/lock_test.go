@@ -0,0 +1,209 @@
+package lock
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeLockFile writes a lock file as if held by another process.
+func writeLockFile(t *testing.T, repo string, info Info) {
+	t.Helper()
+	data, err := json.Marshal(info)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(Path(repo), data, 0644))
+}
+
+func TestPath(t *testing.T) {
+	repo := t.TempDir()
+	assert.Equal(t, filepath.Join(repo, ".svf.lock"), Path(repo))
+
+	require.NoError(t, os.Mkdir(filepath.Join(repo, ".git"), 0755))
+	assert.Equal(t, filepath.Join(repo, ".git", "svf.lock"), Path(repo))
+}
+
+// TestPath_Worktree verifies that a worktree, whose .git is a file, uses
+// the lock of its main repository rather than a file in its working tree.
+func TestPath_Worktree(t *testing.T) {
+	main := t.TempDir()
+	gitDir := filepath.Join(main, ".git")
+	wtGitDir := filepath.Join(gitDir, "worktrees", "feature")
+	require.NoError(t, os.MkdirAll(wtGitDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(wtGitDir, "commondir"), []byte("../..\n"), 0644))
+
+	wt := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(wt, ".git"), []byte("gitdir: "+wtGitDir+"\n"), 0644))
+
+	assert.Equal(t, filepath.Join(gitDir, "svf.lock"), Path(wt))
+	assert.Equal(t, Path(main), Path(wt))
+}
+
+func TestAcquireRelease(t *testing.T) {
+	repo := t.TempDir()
+
+	l, err := Acquire(repo, Options{Op: "save"})
+	require.NoError(t, err)
+
+	info, err := Read(repo)
+	require.NoError(t, err)
+	assert.Equal(t, os.Getpid(), info.PID)
+	assert.Equal(t, "save", info.Op)
+
+	require.NoError(t, l.Release())
+	_, err = Read(repo)
+	assert.True(t, os.IsNotExist(err))
+
+	// Releasing twice is a no-op
+	require.NoError(t, l.Release())
+}
+
+func TestAcquire_Reentrant(t *testing.T) {
+	repo := t.TempDir()
+
+	outer, err := Acquire(repo, Options{Op: "sync"})
+	require.NoError(t, err)
+
+	inner, err := Acquire(repo, Options{Op: "index", Timeout: -1})
+	require.NoError(t, err)
+	require.NoError(t, inner.Release())
+
+	// Still held by the outer lock
+	info, err := Read(repo)
+	require.NoError(t, err)
+	assert.Equal(t, "sync", info.Op)
+
+	require.NoError(t, outer.Release())
+	_, err = Read(repo)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestAcquire_NoReentry(t *testing.T) {
+	repo := t.TempDir()
+
+	outer, err := Acquire(repo, Options{Op: "run Fail over"})
+	require.NoError(t, err)
+
+	_, err = Acquire(repo, Options{Op: "run Fail over", Timeout: -1, NoReentry: true})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrLocked))
+	assert.Contains(t, err.Error(), "run Fail over")
+
+	require.NoError(t, outer.Release())
+	l, err := Acquire(repo, Options{Timeout: -1, NoReentry: true})
+	require.NoError(t, err)
+	require.NoError(t, l.Release())
+}
+
+func TestAcquire_HeldByOtherProcess(t *testing.T) {
+	repo := t.TempDir()
+	host, _ := os.Hostname()
+
+	// Our parent process is alive, so its lock is respected
+	writeLockFile(t, repo, Info{PID: os.Getppid(), Host: host, Op: "sync", AcquiredAt: time.Now()})
+
+	start := time.Now()
+	_, err := Acquire(repo, Options{Op: "save", Timeout: 200 * time.Millisecond})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrLocked))
+	assert.Contains(t, err.Error(), "another svf operation is in progress")
+	assert.Contains(t, err.Error(), "sync")
+	assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+}
+
+func TestAcquire_WaitsForRelease(t *testing.T) {
+	repo := t.TempDir()
+	host, _ := os.Hostname()
+	writeLockFile(t, repo, Info{PID: os.Getppid(), Host: host, Op: "sync", AcquiredAt: time.Now()})
+
+	go func() {
+		time.Sleep(150 * time.Millisecond)
+		_ = os.Remove(Path(repo))
+	}()
+
+	l, err := Acquire(repo, Options{Op: "save", Timeout: 2 * time.Second})
+	require.NoError(t, err)
+	require.NoError(t, l.Release())
+}
+
+func TestAcquire_BreaksStaleLocks(t *testing.T) {
+	host, _ := os.Hostname()
+
+	tests := []struct {
+		name string
+		info Info
+	}{
+		{
+			name: "dead process on this host",
+			info: Info{PID: 999999999, Host: host, Op: "sync", AcquiredAt: time.Now()},
+		},
+		{
+			name: "old lock from another host",
+			info: Info{PID: 1, Host: "elsewhere.invalid", Op: "sync", AcquiredAt: time.Now().Add(-2 * time.Hour)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := t.TempDir()
+			writeLockFile(t, repo, tt.info)
+
+			l, err := Acquire(repo, Options{Op: "save", Timeout: -1})
+			require.NoError(t, err)
+			defer func() { _ = l.Release() }()
+
+			info, err := Read(repo)
+			require.NoError(t, err)
+			assert.Equal(t, os.Getpid(), info.PID)
+		})
+	}
+}
+
+func TestAcquire_RecentLockFromOtherHost(t *testing.T) {
+	repo := t.TempDir()
+	writeLockFile(t, repo, Info{PID: 1, Host: "elsewhere.invalid", Op: "sync", AcquiredAt: time.Now()})
+
+	_, err := Acquire(repo, Options{Op: "save", Timeout: -1})
+	assert.True(t, errors.Is(err, ErrLocked))
+}
+
+func TestNamedPath(t *testing.T) {
+	repo := t.TempDir()
+	assert.Equal(t, filepath.Join(repo, ".svf-locks", "abc.lock"), NamedPath(repo, "abc"))
+
+	require.NoError(t, os.Mkdir(filepath.Join(repo, ".git"), 0755))
+	assert.Equal(t, filepath.Join(repo, ".git", "svf-locks", "abc.lock"), NamedPath(repo, "abc"))
+}
+
+func TestAcquireFile_ShowsHolder(t *testing.T) {
+	path := NamedPath(t.TempDir(), "failover")
+	host, _ := os.Hostname()
+
+	l, err := AcquireFile(path, Options{Op: "run Failover", User: "team/alice"})
+	require.NoError(t, err)
+	info, err := ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "team/alice", info.User)
+	require.NoError(t, l.Release())
+
+	// Held by our parent process, which is alive
+	data, err := json.Marshal(Info{PID: os.Getppid(), Host: host, Op: "run Failover", User: "team/bob", AcquiredAt: time.Now()})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0644))
+	_, err = AcquireFile(path, Options{Op: "run Failover", Timeout: -1})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrLocked))
+	assert.Contains(t, err.Error(), "run Failover by team/bob")
+
+	// Breaking it frees it
+	require.NoError(t, Break(path))
+	require.NoError(t, Break(path))
+	l, err = AcquireFile(path, Options{Op: "run Failover", Timeout: -1})
+	require.NoError(t, err)
+	require.NoError(t, l.Release())
+}
@@ -0,0 +1,219 @@
+// Package packaging generates package manager manifests (a Homebrew
+// formula and a Scoop manifest) for a release from its checksums file. The
+// archive names come from the upgrade package, so package managers install
+// the same archives svf upgrade downloads.
+package packaging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/chazuruo/svf/internal/upgrade"
+)
+
+// Release describes a published release.
+type Release struct {
+	Name        string            // Binary and package name, e.g. "svf"
+	Version     string            // Release version; a leading "v" is dropped
+	Description string            // One-line description
+	Homepage    string            // Project homepage
+	License     string            // SPDX license identifier
+	Repo        string            // GitHub owner/name the release is published in
+	Checksums   map[string]string // SHA256 hashes by archive name
+}
+
+// version returns the version without the tag's "v".
+func (r Release) version() string {
+	return strings.TrimPrefix(r.Version, "v")
+}
+
+// baseURL returns where the release's assets are downloaded from.
+func (r Release) baseURL(version string) string {
+	return fmt.Sprintf("https://github.com/%s/releases/download/v%s", r.Repo, version)
+}
+
+// archive is one platform's release archive.
+type archive struct {
+	URL    string
+	SHA256 string
+}
+
+// archive returns the archive of platform p. Every archive a manifest
+// refers to must have a checksum.
+func (r Release) archive(p upgrade.Platform) (archive, error) {
+	name := upgrade.ArchiveName(r.Name, r.version(), p)
+	sum, ok := r.Checksums[name]
+	if !ok {
+		return archive{}, fmt.Errorf("no checksum for %s", name)
+	}
+	return archive{URL: r.baseURL(r.version()) + "/" + name, SHA256: sum}, nil
+}
+
+func (r Release) validate() error {
+	switch {
+	case r.Name == "":
+		return errors.New("release name is required")
+	case r.version() == "":
+		return errors.New("release version is required")
+	case r.Repo == "":
+		return errors.New("release repo is required")
+	}
+	return nil
+}
+
+var formulaTemplate = template.Must(template.New("formula").Parse(`# Generated by make manifests; do not edit.
+class {{.Class}} < Formula
+  desc "{{.Description}}"
+  homepage "{{.Homepage}}"
+  version "{{.Version}}"
+  license "{{.License}}"
+
+  on_macos do
+    on_intel do
+      url "{{.DarwinAMD64.URL}}"
+      sha256 "{{.DarwinAMD64.SHA256}}"
+    end
+    on_arm do
+      url "{{.DarwinARM64.URL}}"
+      sha256 "{{.DarwinARM64.SHA256}}"
+    end
+  end
+
+  on_linux do
+    on_intel do
+      url "{{.LinuxAMD64.URL}}"
+      sha256 "{{.LinuxAMD64.SHA256}}"
+    end
+    on_arm do
+      url "{{.LinuxARM64.URL}}"
+      sha256 "{{.LinuxARM64.SHA256}}"
+    end
+  end
+
+  def install
+    bin.install "{{.Name}}"
+  end
+
+  test do
+    system bin/"{{.Name}}", "version"
+  end
+end
+`))
+
+// Homebrew returns the Homebrew formula of the release, installing the
+// prebuilt macOS and Linux archives.
+func Homebrew(r Release) ([]byte, error) {
+	if err := r.validate(); err != nil {
+		return nil, err
+	}
+	data := struct {
+		Release
+		Class                                            string
+		DarwinAMD64, DarwinARM64, LinuxAMD64, LinuxARM64 archive
+	}{Release: r, Class: formulaClass(r.Name)}
+	data.Version = r.version()
+
+	for _, a := range []struct {
+		dst *archive
+		p   upgrade.Platform
+	}{
+		{&data.DarwinAMD64, upgrade.Platform{OS: "darwin", Arch: "amd64"}},
+		{&data.DarwinARM64, upgrade.Platform{OS: "darwin", Arch: "arm64"}},
+		{&data.LinuxAMD64, upgrade.Platform{OS: "linux", Arch: "amd64"}},
+		{&data.LinuxARM64, upgrade.Platform{OS: "linux", Arch: "arm64"}},
+	} {
+		var err error
+		if *a.dst, err = r.archive(a.p); err != nil {
+			return nil, err
+		}
+	}
+
+	var b bytes.Buffer
+	if err := formulaTemplate.Execute(&b, data); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// formulaClass returns the Ruby class name Homebrew expects for a
+// formula, e.g. "my-tool" -> "MyTool".
+func formulaClass(name string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return r == '-' || r == '_' || r == '.' }) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// scoopManifest is a Scoop app manifest.
+type scoopManifest struct {
+	Version      string                  `json:"version"`
+	Description  string                  `json:"description"`
+	Homepage     string                  `json:"homepage"`
+	License      string                  `json:"license"`
+	Architecture map[string]scoopArchive `json:"architecture"`
+	Bin          string                  `json:"bin"`
+	Checkver     string                  `json:"checkver"`
+	Autoupdate   scoopAutoupdate         `json:"autoupdate"`
+}
+
+type scoopArchive struct {
+	URL  string `json:"url"`
+	Hash string `json:"hash,omitempty"`
+}
+
+type scoopAutoupdate struct {
+	Architecture map[string]scoopArchive `json:"architecture"`
+	Hash         scoopHash               `json:"hash"`
+}
+
+type scoopHash struct {
+	URL string `json:"url"`
+}
+
+// scoopArchitectures maps Scoop's architecture names to the Windows
+// archives.
+var scoopArchitectures = map[string]upgrade.Platform{
+	"64bit": {OS: "windows", Arch: "amd64"},
+	"arm64": {OS: "windows", Arch: "arm64"},
+}
+
+// Scoop returns the Scoop manifest of the release, installing the
+// Windows archives. Its autoupdate section lets Scoop pick up later
+// releases from the same asset names and checksums file.
+func Scoop(r Release) ([]byte, error) {
+	if err := r.validate(); err != nil {
+		return nil, err
+	}
+	m := scoopManifest{
+		Version:      r.version(),
+		Description:  r.Description,
+		Homepage:     r.Homepage,
+		License:      r.License,
+		Architecture: make(map[string]scoopArchive),
+		Bin:          r.Name + ".exe",
+		Checkver:     "github",
+		Autoupdate: scoopAutoupdate{
+			Architecture: make(map[string]scoopArchive),
+			Hash:         scoopHash{URL: r.baseURL("$version") + "/" + upgrade.ChecksumsName(r.Name, "$version")},
+		},
+	}
+	for arch, p := range scoopArchitectures {
+		a, err := r.archive(p)
+		if err != nil {
+			return nil, err
+		}
+		m.Architecture[arch] = scoopArchive{URL: a.URL, Hash: a.SHA256}
+		m.Autoupdate.Architecture[arch] = scoopArchive{URL: r.baseURL("$version") + "/" + upgrade.ArchiveName(r.Name, "$version", p)}
+	}
+
+	data, err := json.MarshalIndent(m, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
@@ -0,0 +1,75 @@
+package packaging
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRelease() Release {
+	return Release{
+		Name:        "svf",
+		Version:     "v1.2.3",
+		Description: "Git-backed workflow automation tool",
+		Homepage:    "https://github.com/chazu/faire",
+		License:     "MIT",
+		Repo:        "chazu/faire",
+		Checksums: map[string]string{
+			"svf_1.2.3_darwin_amd64.tar.gz": "aaa",
+			"svf_1.2.3_darwin_arm64.tar.gz": "bbb",
+			"svf_1.2.3_linux_amd64.tar.gz":  "ccc",
+			"svf_1.2.3_linux_arm64.tar.gz":  "ddd",
+			"svf_1.2.3_windows_amd64.zip":   "eee",
+			"svf_1.2.3_windows_arm64.zip":   "fff",
+		},
+	}
+}
+
+func TestHomebrew(t *testing.T) {
+	data, err := Homebrew(testRelease())
+	require.NoError(t, err)
+	formula := string(data)
+
+	for _, want := range []string{
+		"class Svf < Formula",
+		`version "1.2.3"`,
+		`url "https://github.com/chazu/faire/releases/download/v1.2.3/svf_1.2.3_darwin_arm64.tar.gz"`,
+		`sha256 "bbb"`,
+		`sha256 "ddd"`,
+		`bin.install "svf"`,
+	} {
+		assert.Contains(t, formula, want)
+	}
+	// The arm archive follows on_arm, not on_intel
+	assert.Less(t, strings.Index(formula, "on_arm"), strings.Index(formula, `sha256 "bbb"`))
+
+	r := testRelease()
+	delete(r.Checksums, "svf_1.2.3_linux_arm64.tar.gz")
+	_, err = Homebrew(r)
+	assert.EqualError(t, err, "no checksum for svf_1.2.3_linux_arm64.tar.gz")
+}
+
+func TestScoop(t *testing.T) {
+	data, err := Scoop(testRelease())
+	require.NoError(t, err)
+
+	var m scoopManifest
+	require.NoError(t, json.Unmarshal(data, &m))
+	assert.Equal(t, "1.2.3", m.Version)
+	assert.Equal(t, "svf.exe", m.Bin)
+	assert.Equal(t, scoopArchive{URL: "https://github.com/chazu/faire/releases/download/v1.2.3/svf_1.2.3_windows_amd64.zip", Hash: "eee"}, m.Architecture["64bit"])
+	assert.Equal(t, "fff", m.Architecture["arm64"].Hash)
+	assert.Equal(t, "https://github.com/chazu/faire/releases/download/v$version/svf_$version_windows_arm64.zip", m.Autoupdate.Architecture["arm64"].URL)
+	assert.Equal(t, "https://github.com/chazu/faire/releases/download/v$version/svf_$version_checksums.txt", m.Autoupdate.Hash.URL)
+
+	_, err = Scoop(Release{Name: "svf", Repo: "chazu/faire"})
+	assert.EqualError(t, err, "release version is required")
+}
+
+func TestFormulaClass(t *testing.T) {
+	assert.Equal(t, "Svf", formulaClass("svf"))
+	assert.Equal(t, "MyTool", formulaClass("my-tool"))
+}
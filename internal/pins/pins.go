@@ -0,0 +1,132 @@
+// Package pins keeps the workflows a user has pinned as favorites.
+//
+// Pins are kept on the local machine only (never committed) in
+// $XDG_STATE_HOME/svf/pins.json, defaulting to ~/.local/state/svf/pins.json.
+package pins
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/chazuruo/svf/internal/config"
+)
+
+// Pin is a pinned workflow.
+type Pin struct {
+	ID string `json:"id"`
+	// Title is the workflow's title when it was pinned, shown if the
+	// workflow can't be found any more.
+	Title    string    `json:"title"`
+	PinnedAt time.Time `json:"pinned_at"`
+}
+
+// DefaultPath returns the default path of the pins file.
+func DefaultPath() (string, error) {
+	dir, err := config.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "pins.json"), nil
+}
+
+// Store reads and writes pins in a file.
+type Store struct {
+	path string
+}
+
+// NewStore creates a store for the file at path.
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// NewDefaultStore creates a store for the default file.
+func NewDefaultStore() (*Store, error) {
+	path, err := DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	return NewStore(path), nil
+}
+
+// List returns the pins in the order they were pinned.
+func (s *Store) List() ([]Pin, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read pins: %w", err)
+	}
+	var pins []Pin
+	if err := json.Unmarshal(data, &pins); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", s.path, err)
+	}
+	return pins, nil
+}
+
+// Add pins a workflow after the existing pins. It reports false if the
+// workflow was already pinned.
+func (s *Store) Add(p Pin) (bool, error) {
+	pins, err := s.List()
+	if err != nil {
+		return false, err
+	}
+	for _, existing := range pins {
+		if existing.ID == p.ID {
+			return false, nil
+		}
+	}
+	return true, s.write(append(pins, p))
+}
+
+// Remove unpins the workflow with the given ID. It reports false if the
+// workflow wasn't pinned.
+func (s *Store) Remove(id string) (bool, error) {
+	pins, err := s.List()
+	if err != nil {
+		return false, err
+	}
+	kept := pins[:0]
+	for _, p := range pins {
+		if p.ID != id {
+			kept = append(kept, p)
+		}
+	}
+	if len(kept) == len(pins) {
+		return false, nil
+	}
+	return true, s.write(kept)
+}
+
+// write replaces the pins file.
+func (s *Store) write(pins []Pin) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("failed to create pins directory: %w", err)
+	}
+	data, err := json.MarshalIndent(pins, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal pins: %w", err)
+	}
+
+	// Write atomically so a crash never loses the pins
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write pins: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write pins: %w", err)
+	}
+	return nil
+}
+
+// Ranks maps the ID of each pinned workflow to its position in pins.
+func Ranks(pins []Pin) map[string]int {
+	ranks := make(map[string]int, len(pins))
+	for i, p := range pins {
+		ranks[p.ID] = i
+	}
+	return ranks
+}
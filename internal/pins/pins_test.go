@@ -0,0 +1,48 @@
+package pins
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreAddRemove(t *testing.T) {
+	s := NewStore(filepath.Join(t.TempDir(), "svf", "pins.json"))
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	pins, err := s.List()
+	require.NoError(t, err)
+	assert.Empty(t, pins)
+
+	added, err := s.Add(Pin{ID: "a", Title: "Deploy", PinnedAt: at})
+	require.NoError(t, err)
+	assert.True(t, added)
+	added, err = s.Add(Pin{ID: "b", Title: "Rollback", PinnedAt: at})
+	require.NoError(t, err)
+	assert.True(t, added)
+
+	// Pinning twice keeps the first pin
+	added, err = s.Add(Pin{ID: "a", Title: "Deploy again"})
+	require.NoError(t, err)
+	assert.False(t, added)
+
+	pins, err = s.List()
+	require.NoError(t, err)
+	require.Len(t, pins, 2)
+	assert.Equal(t, "Deploy", pins[0].Title)
+	assert.Equal(t, map[string]int{"a": 0, "b": 1}, Ranks(pins))
+
+	removed, err := s.Remove("a")
+	require.NoError(t, err)
+	assert.True(t, removed)
+	removed, err = s.Remove("a")
+	require.NoError(t, err)
+	assert.False(t, removed)
+
+	pins, err = s.List()
+	require.NoError(t, err)
+	assert.Equal(t, []Pin{{ID: "b", Title: "Rollback", PinnedAt: at}}, pins)
+}
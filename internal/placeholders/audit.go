@@ -0,0 +1,87 @@
+package placeholders
+
+import (
+	"sort"
+
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// Usage describes how a workflow uses one placeholder.
+type Usage struct {
+	Name string
+
+	// Declared reports whether the workflow's placeholders section
+	// declares the placeholder, with the declaration in Placeholder.
+	Declared    bool
+	Placeholder workflows.Placeholder
+
+	// Steps are the indexes of the steps whose commands use it.
+	Steps []int
+
+	// CapturedBy is the index of the step that captures it from its
+	// output, or -1.
+	CapturedBy int
+
+	// Matrix reports whether the workflow's matrix supplies its values.
+	Matrix bool
+
+	// Presets are the names of the presets that set it, sorted.
+	Presets []string
+}
+
+// Unused reports whether the placeholder is declared but no step uses it.
+func (u Usage) Unused() bool {
+	return u.Declared && len(u.Steps) == 0
+}
+
+// Audit returns every placeholder the workflow declares or its steps use,
+// sorted by name. A step's <name> references to its own files are
+// not placeholders and are left out.
+func Audit(wf *workflows.Workflow) []Usage {
+	usages := make(map[string]*Usage)
+	get := func(name string) *Usage {
+		u, ok := usages[name]
+		if !ok {
+			u = &Usage{Name: name, CapturedBy: wf.CaptureStep(name)}
+			usages[name] = u
+		}
+		return u
+	}
+
+	for name, ph := range wf.Placeholders {
+		u := get(name)
+		u.Declared = true
+		u.Placeholder = ph
+	}
+	for i, step := range wf.Steps {
+		for _, name := range Extract(step.Command) {
+			if _, isFile := step.Files[name]; isFile {
+				continue
+			}
+			u := get(name)
+			u.Steps = append(u.Steps, i)
+		}
+	}
+	for name := range wf.Matrix {
+		if u, ok := usages[name]; ok {
+			u.Matrix = true
+		}
+	}
+	for preset, values := range wf.Presets {
+		for name := range values {
+			if u, ok := usages[name]; ok {
+				u.Presets = append(u.Presets, preset)
+			}
+		}
+	}
+
+	result := make([]Usage, 0, len(usages))
+	for _, u := range usages {
+		sort.Strings(u.Presets)
+		result = append(result, *u)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
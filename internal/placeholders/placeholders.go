@@ -37,15 +37,39 @@ func Extract(s string) []string {
 }
 
 // ExtractWithMetadata extracts all unique placeholders from a workflow and returns
-// their metadata including which steps they're used in.
+// their metadata including which steps they're used in. Placeholders that an
+// earlier step captures from its output are filled in while the workflow
+// runs, so they're left out.
 func ExtractWithMetadata(wf *workflows.Workflow) map[string]PlaceholderInfo {
+	return extractWithMetadata(wf, false)
+}
+
+// ExtractAll is like ExtractWithMetadata but includes captured placeholders,
+// for modes where svf doesn't see step output and the user must supply them.
+func ExtractAll(wf *workflows.Workflow) map[string]PlaceholderInfo {
+	return extractWithMetadata(wf, true)
+}
+
+func extractWithMetadata(wf *workflows.Workflow, includeCaptured bool) map[string]PlaceholderInfo {
 	result := make(map[string]PlaceholderInfo)
 
-	for i, step := range wf.Steps {
+	// Cleanup steps run after the steps, so they see every capture
+	steps := append(append([]workflows.Step(nil), wf.Steps...), wf.Cleanup...)
+	for i, step := range steps {
 		for _, name := range Extract(step.Command) {
+			capturedBy := wf.CaptureStep(name)
+			captured := capturedBy >= 0 && capturedBy < i
+			if captured && !includeCaptured {
+				continue
+			}
+
 			stepName := step.Name
-			if stepName == "" {
+			switch {
+			case stepName != "":
+			case i < len(wf.Steps):
 				stepName = fmt.Sprintf("Step %d", i+1)
+			default:
+				stepName = fmt.Sprintf("Cleanup %d", i-len(wf.Steps)+1)
 			}
 
 			info, exists := result[name]
@@ -64,6 +88,9 @@ func ExtractWithMetadata(wf *workflows.Workflow) map[string]PlaceholderInfo {
 					Secret:   ph.Secret,
 					UsedIn:   []string{stepName},
 				}
+				if captured && info.Prompt == "" {
+					info.Prompt = fmt.Sprintf("Value for <%s> (captured from step %d's output)", name, capturedBy+1)
+				}
 			} else {
 				// Add step to UsedIn if not already present
 				found := false
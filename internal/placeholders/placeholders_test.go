@@ -2,6 +2,8 @@
 package placeholders
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/chazuruo/svf/internal/workflows"
@@ -289,6 +291,36 @@ func TestExtractWithMetadata(t *testing.T) {
 	}
 }
 
+func TestExtractWithMetadata_Captured(t *testing.T) {
+	wf := &workflows.Workflow{
+		Title: "Restart pod",
+		Steps: []workflows.Step{
+			{
+				Command: "kubectl get pods -n <namespace> -o json",
+				Capture: map[string]workflows.Extractor{"pod": {JSONPath: ".items[0].metadata.name"}},
+			},
+			{Command: "kubectl delete pod <pod> -n <namespace>"},
+		},
+	}
+
+	result := ExtractWithMetadata(wf)
+	if _, ok := result["pod"]; ok {
+		t.Error("ExtractWithMetadata() should not include 'pod', which step 1 captures")
+	}
+	if _, ok := result["namespace"]; !ok {
+		t.Error("ExtractWithMetadata() missing 'namespace' placeholder")
+	}
+
+	all := ExtractAll(wf)
+	pod, ok := all["pod"]
+	if !ok {
+		t.Fatal("ExtractAll() missing captured 'pod' placeholder")
+	}
+	if want := "Value for <pod> (captured from step 1's output)"; pod.Prompt != want {
+		t.Errorf("pod.Prompt = %q, want %q", pod.Prompt, want)
+	}
+}
+
 func TestCollectFromSteps(t *testing.T) {
 	steps := []workflows.Step{
 		{Command: "echo <name>"},
@@ -380,3 +412,72 @@ func TestValidateAtLoadTime(t *testing.T) {
 		})
 	}
 }
+
+func TestAudit(t *testing.T) {
+	wf := &workflows.Workflow{
+		Title: "Deploy",
+		Placeholders: map[string]workflows.Placeholder{
+			"env":    {Default: "staging", Validate: "^(staging|prod)$"},
+			"region": {Prompt: "AWS region"},
+		},
+		Presets: map[string]map[string]string{
+			"prod":    {"env": "prod"},
+			"staging": {"env": "staging"},
+		},
+		Steps: []workflows.Step{
+			{Name: "build", Command: "make build ENV=<env>", Capture: map[string]workflows.Extractor{"version": {Regex: `v(\S+)`}}},
+			{Name: "deploy", Command: "deploy <env> <version> <tag> --values <values>", Files: map[string]string{"values": "values.yaml"}},
+		},
+	}
+
+	usages := Audit(wf)
+	names := make([]string, len(usages))
+	for i, u := range usages {
+		names[i] = u.Name
+	}
+	if got, want := strings.Join(names, ","), "env,region,tag,version"; got != want {
+		t.Fatalf("Audit() names = %s, want %s", got, want)
+	}
+
+	env := usages[0]
+	if !env.Declared || env.Placeholder.Default != "staging" || len(env.Steps) != 2 || strings.Join(env.Presets, ",") != "prod,staging" {
+		t.Errorf("unexpected env usage: %+v", env)
+	}
+	if !usages[1].Unused() {
+		t.Error("region is declared but unused")
+	}
+	if tag := usages[2]; tag.Declared || tag.Unused() || len(tag.Steps) != 1 || tag.Steps[0] != 1 {
+		t.Errorf("unexpected tag usage: %+v", tag)
+	}
+	if version := usages[3]; version.CapturedBy != 0 {
+		t.Errorf("version should be captured by step 0, got %d", version.CapturedBy)
+	}
+}
+
+func TestExtractWithMetadata_Cleanup(t *testing.T) {
+	wf := &workflows.Workflow{
+		Title: "Drain node",
+		Steps: []workflows.Step{
+			{
+				Command: "kubectl get nodes -o json",
+				Capture: map[string]workflows.Extractor{"node": {JSONPath: ".items[0].metadata.name"}},
+			},
+			{Command: "kubectl drain <node>"},
+		},
+		Cleanup: []workflows.Step{
+			{Command: "kubectl uncordon <node> --context <context>"},
+		},
+	}
+
+	result := ExtractWithMetadata(wf)
+	if _, ok := result["node"]; ok {
+		t.Error("ExtractWithMetadata() should not include 'node', which step 1 captures")
+	}
+	ctx, ok := result["context"]
+	if !ok {
+		t.Fatal("ExtractWithMetadata() missing 'context' placeholder of the cleanup step")
+	}
+	if want := []string{"Cleanup 1"}; !reflect.DeepEqual(ctx.UsedIn, want) {
+		t.Errorf("context.UsedIn = %v, want %v", ctx.UsedIn, want)
+	}
+}
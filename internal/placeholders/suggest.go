@@ -0,0 +1,275 @@
+package placeholders
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// Suggestion proposes turning a literal that repeats across steps into a
+// placeholder.
+type Suggestion struct {
+	Name  string // Proposed placeholder name
+	Value string // The literal, kept as the placeholder's default
+	Steps []int  // 0-based indexes of the steps using it
+}
+
+var (
+	// hostRegex matches DNS names such as db1.prod.example.com.
+	hostRegex = regexp.MustCompile(`^([a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?\.)+[a-zA-Z][a-zA-Z0-9-]*$`)
+
+	// idRegexes match identifiers: UUIDs, numeric IDs, prefixed cloud
+	// IDs like i-0abc1234 and ARNs. Hashes are checked by isHash.
+	idRegexes = []*regexp.Regexp{
+		regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`),
+		regexp.MustCompile(`^[0-9]{4,}$`),
+		regexp.MustCompile(`^[a-z]{1,5}-[0-9a-f]{8,}$`),
+		regexp.MustCompile(`^arn:`),
+	}
+
+	// hashRegex matches hex strings long enough to be commit or image hashes.
+	hashRegex = regexp.MustCompile(`^[0-9a-f]{7,}$`)
+
+	// fileExtensions rule out file names that look like hostnames.
+	fileExtensions = map[string]bool{
+		"go": true, "py": true, "js": true, "ts": true, "rb": true, "rs": true, "java": true,
+		"json": true, "yaml": true, "yml": true, "toml": true, "ini": true, "conf": true, "cfg": true,
+		"txt": true, "md": true, "log": true, "csv": true, "sql": true, "html": true, "css": true, "xml": true,
+		"sh": true, "bash": true, "zsh": true, "env": true, "lock": true, "mod": true, "sum": true,
+		"tar": true, "gz": true, "tgz": true, "zip": true, "pem": true, "key": true, "crt": true,
+		"tf": true, "tfvars": true, "service": true, "d": true,
+	}
+
+	// flagNames names the placeholders for common short flags.
+	flagNames = map[string]string{
+		"-n": "namespace",
+		"-h": "host",
+		"-u": "user",
+		"-p": "port",
+	}
+)
+
+// literal is a candidate value in a command.
+type literal struct {
+	value string
+	name  string // Name suggested by the flag it follows, if any
+}
+
+// Suggest finds literals used in more than one step that look like
+// hostnames, IP addresses, IDs or flag values such as namespaces, and
+// proposes placeholders for them, most widely used first. Names avoid
+// placeholders the workflow already has.
+func Suggest(wf *workflows.Workflow) []Suggestion {
+	type candidate struct {
+		name  string
+		kind  string
+		steps []int
+	}
+	candidates := make(map[string]*candidate)
+	var order []string
+
+	for i, step := range wf.Steps {
+		seen := make(map[string]bool)
+		for _, lit := range commandLiterals(step.Command) {
+			kind := literalKind(lit.value)
+			if kind == "" && lit.name == "" {
+				continue
+			}
+			c, ok := candidates[lit.value]
+			if !ok {
+				c = &candidate{kind: kind}
+				candidates[lit.value] = c
+				order = append(order, lit.value)
+			}
+			if c.name == "" {
+				c.name = lit.name
+			}
+			if !seen[lit.value] {
+				seen[lit.value] = true
+				c.steps = append(c.steps, i)
+			}
+		}
+	}
+
+	var values []string
+	for _, value := range order {
+		if len(candidates[value].steps) > 1 {
+			values = append(values, value)
+		}
+	}
+	sort.SliceStable(values, func(a, b int) bool {
+		return len(candidates[values[a]].steps) > len(candidates[values[b]].steps)
+	})
+
+	used := make(map[string]bool)
+	for name := range wf.Placeholders {
+		used[name] = true
+	}
+	for _, name := range CollectFromSteps(wf.Steps) {
+		used[name] = true
+	}
+
+	suggestions := make([]Suggestion, 0, len(values))
+	for _, value := range values {
+		c := candidates[value]
+		base := c.name
+		if base == "" {
+			base = c.kind
+		}
+		name := base
+		for n := 2; used[name]; n++ {
+			name = fmt.Sprintf("%s%d", base, n)
+		}
+		used[name] = true
+		suggestions = append(suggestions, Suggestion{Name: name, Value: value, Steps: c.steps})
+	}
+	return suggestions
+}
+
+// ApplySuggestion replaces the suggestion's literal with its placeholder in
+// every step and declares the placeholder with the literal as its default.
+func ApplySuggestion(wf *workflows.Workflow, s Suggestion) {
+	for i := range wf.Steps {
+		wf.Steps[i].Command = ReplaceLiteral(wf.Steps[i].Command, s.Value, "<"+s.Name+">")
+	}
+	if wf.Placeholders == nil {
+		wf.Placeholders = make(map[string]workflows.Placeholder)
+	}
+	wf.Placeholders[s.Name] = workflows.Placeholder{Default: s.Value}
+}
+
+// ReplaceLiteral replaces whole occurrences of value in command, leaving
+// it alone where it is part of a longer word, host or path segment.
+func ReplaceLiteral(command, value, replacement string) string {
+	if value == "" {
+		return command
+	}
+	var b strings.Builder
+	rest := command
+	offset := 0
+	for {
+		i := strings.Index(rest, value)
+		if i < 0 {
+			b.WriteString(rest)
+			return b.String()
+		}
+		start := offset + i
+		end := start + len(value)
+		if isWordByte(command, start-1) || isWordByte(command, end) {
+			b.WriteString(rest[:i+len(value)])
+		} else {
+			b.WriteString(rest[:i])
+			b.WriteString(replacement)
+		}
+		rest = rest[i+len(value):]
+		offset = end
+	}
+}
+
+// isWordByte reports whether command[i] continues a literal.
+func isWordByte(command string, i int) bool {
+	if i < 0 || i >= len(command) {
+		return false
+	}
+	c := command[i]
+	return c == '.' || c == '-' || c == '_' ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// commandLiterals splits a command into candidate values, naming those
+// that follow a flag after the flag.
+func commandLiterals(command string) []literal {
+	var literals []literal
+	fields := strings.Fields(command)
+	flag := ""
+	for i, field := range fields {
+		field = strings.Trim(field, `'"(),;`)
+		if field == "" || strings.ContainsAny(field, "<>$`*{}") {
+			flag = ""
+			continue
+		}
+
+		if strings.HasPrefix(field, "-") {
+			flag = ""
+			if name, value, ok := strings.Cut(field, "="); ok {
+				if value = strings.Trim(value, `'"`); value != "" && !strings.ContainsAny(value, "<>$") {
+					literals = append(literals, literal{value: value, name: flagName(name)})
+				}
+			} else if i > 0 {
+				flag = field
+			}
+			continue
+		}
+		if i == 0 {
+			continue
+		}
+
+		// Hosts inside user@host and URLs
+		if _, host, ok := strings.Cut(field, "@"); ok {
+			field = host
+		}
+		if _, rest, ok := strings.Cut(field, "://"); ok {
+			field, _, _ = strings.Cut(rest, "/")
+		}
+		if host, _, ok := strings.Cut(field, ":"); ok && literalKind(host) != "" {
+			field = host
+		}
+
+		lit := literal{value: field}
+		if flag != "" {
+			lit.name = flagName(flag)
+			flag = ""
+		}
+		literals = append(literals, lit)
+	}
+	return literals
+}
+
+// flagName names the placeholder for a flag's value: "--namespace" and
+// "-n" both give "namespace". Unknown short flags give "".
+func flagName(flag string) string {
+	if name, ok := flagNames[flag]; ok {
+		return name
+	}
+	if strings.HasPrefix(flag, "--") && len(flag) > 3 {
+		return strings.TrimPrefix(flag, "--")
+	}
+	return ""
+}
+
+// literalKind classifies a value as "host", "ip" or "id", or returns ""
+// for values that aren't worth a placeholder on their own.
+func literalKind(value string) string {
+	if len(value) < 3 || strings.HasPrefix(value, "/") || strings.HasPrefix(value, ".") {
+		return ""
+	}
+	if net.ParseIP(value) != nil {
+		return "ip"
+	}
+	for _, re := range idRegexes {
+		if re.MatchString(value) {
+			return "id"
+		}
+	}
+	if isHash(value) {
+		return "id"
+	}
+	if hostRegex.MatchString(value) {
+		ext := value[strings.LastIndex(value, ".")+1:]
+		if !fileExtensions[strings.ToLower(ext)] {
+			return "host"
+		}
+	}
+	return ""
+}
+
+// isHash reports whether value is a hex hash: it needs both digits and
+// letters so that words like "deadbeef" and plain numbers don't count.
+func isHash(value string) bool {
+	return hashRegex.MatchString(value) &&
+		strings.ContainsAny(value, "0123456789") && strings.ContainsAny(value, "abcdef")
+}
@@ -0,0 +1,118 @@
+package placeholders
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+func TestSuggest(t *testing.T) {
+	wf := &workflows.Workflow{
+		Steps: []workflows.Step{
+			{Command: "kubectl -n prod get pods"},
+			{Command: "kubectl logs api-7d9f8c --namespace prod"},
+			{Command: "ssh deploy@db1.example.com 'pg_dump app > /tmp/app.sql'"},
+			{Command: "scp deploy@db1.example.com:/tmp/app.sql . && kubectl -n prod rollout restart deploy/api"},
+			{Command: "cat config.yaml config.yaml"},
+			{Command: "cat config.yaml"},
+		},
+	}
+
+	got := Suggest(wf)
+	want := []Suggestion{
+		{Name: "namespace", Value: "prod", Steps: []int{0, 1, 3}},
+		{Name: "host", Value: "db1.example.com", Steps: []int{2, 3}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Suggest() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSuggest_Kinds(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		want    string
+	}{
+		{name: "ip", command: "ping 10.0.0.12", want: "ip"},
+		{name: "uuid", command: "curl /jobs 3f2a9c1e-5b7d-4e8f-9a0b-1c2d3e4f5a6b", want: "id"},
+		{name: "hash", command: "git show 4f9e2ab", want: "id"},
+		{name: "numeric id", command: "gh pr view 12345", want: "id"},
+		{name: "cloud id", command: "aws ec2 stop-instances i-0abc12345def", want: "id"},
+		{name: "url host", command: "curl https://api.example.com/health", want: "host"},
+		{name: "long flag", command: "gcloud compute ssh vm --zone=us-east1-b", want: "zone"},
+		{name: "plain word", command: "echo hello", want: ""},
+		{name: "file name", command: "cat main.go", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf := &workflows.Workflow{Steps: []workflows.Step{{Command: tt.command}, {Command: tt.command}}}
+			got := Suggest(wf)
+			if tt.want == "" {
+				if len(got) != 0 {
+					t.Errorf("Suggest() = %+v, want none", got)
+				}
+				return
+			}
+			if len(got) != 1 || got[0].Name != tt.want {
+				t.Errorf("Suggest() = %+v, want one named %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggest_AvoidsExistingNames(t *testing.T) {
+	wf := &workflows.Workflow{
+		Steps: []workflows.Step{
+			{Command: "ssh <host> uptime && ping 10.0.0.1 && ping a.example.com"},
+			{Command: "ping 10.0.0.1 && ping a.example.com"},
+		},
+		Placeholders: map[string]workflows.Placeholder{"ip": {}},
+	}
+
+	var names []string
+	for _, s := range Suggest(wf) {
+		names = append(names, s.Name)
+	}
+	if want := []string{"ip2", "host2"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("names = %v, want %v", names, want)
+	}
+}
+
+func TestReplaceLiteral(t *testing.T) {
+	tests := []struct {
+		command string
+		want    string
+	}{
+		{command: "kubectl -n prod get pods", want: "kubectl -n <ns> get pods"},
+		{command: "kubectl --namespace=prod get pods", want: "kubectl --namespace=<ns> get pods"},
+		{command: "echo production prod-db prod", want: "echo production prod-db <ns>"},
+		{command: "ls /srv/prod/logs", want: "ls /srv/<ns>/logs"},
+	}
+
+	for _, tt := range tests {
+		if got := ReplaceLiteral(tt.command, "prod", "<ns>"); got != tt.want {
+			t.Errorf("ReplaceLiteral(%q) = %q, want %q", tt.command, got, tt.want)
+		}
+	}
+}
+
+func TestApplySuggestion(t *testing.T) {
+	wf := &workflows.Workflow{
+		Steps: []workflows.Step{
+			{Command: "kubectl -n prod get pods"},
+			{Command: "echo done"},
+		},
+	}
+
+	ApplySuggestion(wf, Suggestion{Name: "namespace", Value: "prod", Steps: []int{0}})
+
+	if got := wf.Steps[0].Command; got != "kubectl -n <namespace> get pods" {
+		t.Errorf("Steps[0].Command = %q", got)
+	}
+	if got := wf.Placeholders["namespace"].Default; got != "prod" {
+		t.Errorf("namespace default = %q, want prod", got)
+	}
+}
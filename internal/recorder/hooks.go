@@ -33,8 +33,11 @@ func (h *HookGenerator) GenerateBashHook() string {
 GITSAVVY_CAPTURE_FILE="%s"
 GITSAVVY_SESSION_ID="%s"
 GITSAVVY_LAST_CMD=""
+GITSAVVY_TMUX_SESSION="${TMUX:+$(tmux display-message -p '#S' 2>/dev/null)}"
 
 _gitsavvy_capture() {
+    # Read $? first: any other command resets it
+    local exit_status=$?
     local cmd="$BASH_COMMAND"
     local cwd="$(pwd)"
     local ts="$(date +%%s)"
@@ -49,7 +52,7 @@ _gitsavvy_capture() {
     esac
 
     # Unit separator (0x1F) is unlikely to appear in commands
-    echo "${ts}${'\x1F'}${cwd}${'\x1F'}${cmd}" >> "$GITSAVVY_CAPTURE_FILE"
+    echo "${ts}${'\x1F'}${cwd}${'\x1F'}${cmd}${'\x1F'}${exit_status}${'\x1F'}${HOSTNAME}${'\x1F'}${GITSAVVY_TMUX_SESSION}" >> "$GITSAVVY_CAPTURE_FILE"
     _GITSAVVY_LAST_CMD="$cmd"
 }
 
@@ -65,8 +68,11 @@ func (h *HookGenerator) GenerateZshHook() string {
 GITSAVVY_CAPTURE_FILE="%s"
 GITSAVVY_SESSION_ID="%s"
 GITSAVVY_LAST_CMD=""
+GITSAVVY_TMUX_SESSION="${TMUX:+$(tmux display-message -p '#S' 2>/dev/null)}"
 
 _gitsavvy_precmd() {
+    # Read $? first: any other command resets it
+    local exit_status=$?
     local cmd="$history[$((HISTCMD-1))]"
     local cwd="$(pwd)"
     local ts="$(date +%%s)"
@@ -81,7 +87,7 @@ _gitsavvy_precmd() {
     esac
 
     # Unit separator (0x1F) is unlikely to appear in commands
-    echo "${ts}${'\x1F'}${cwd}${'\x1F'}${cmd}" >> "$GITSAVVY_CAPTURE_FILE"
+    echo "${ts}${'\x1F'}${cwd}${'\x1F'}${cmd}${'\x1F'}${exit_status}${'\x1F'}${HOST}${'\x1F'}${GITSAVVY_TMUX_SESSION}" >> "$GITSAVVY_CAPTURE_FILE"
     _GITSAVVY_LAST_CMD="$cmd"
 }
 
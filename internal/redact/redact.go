@@ -0,0 +1,93 @@
+// Package redact masks secrets in free-form text such as command output
+// and prompts.
+package redact
+
+import "regexp"
+
+// Redaction levels.
+const (
+	// None leaves text unchanged.
+	None = "none"
+	// Basic masks common credential patterns: API keys, passwords, tokens,
+	// secrets, email addresses and private key headers.
+	Basic = "basic"
+	// Strict additionally masks JWTs, credentials in URLs, whole private
+	// key blocks and long hex or base64 strings.
+	Strict = "strict"
+)
+
+// Placeholder replaces each redacted match.
+const Placeholder = "<REDACTED>"
+
+// basicPatterns are applied at the basic and strict levels.
+var basicPatterns = []pattern{
+	// API keys
+	{"api key", regexp.MustCompile(`(?i)(api[_-]?key|apikey|key)[\"']?\s*[:=]\s*[\"']?[a-zA-Z0-9_\-]{20,}[\"']?`)},
+	{"api key", regexp.MustCompile(`(?i)sk-[a-zA-Z0-9]{20,}`)},
+	{"api key", regexp.MustCompile(`(?i)pk-[a-zA-Z0-9]{20,}`)},
+	{"AWS access key", regexp.MustCompile(`(?i)AKIA[0-9A-Z]{16}`)},
+	// Passwords
+	{"password", regexp.MustCompile(`(?i)(password|passwd|pass)[\"']?\s*[:=]\s*[\"']?[^\s\"']+[\"']?`)},
+	// Tokens
+	{"token", regexp.MustCompile(`(?i)(token|access[_-]?token|refresh[_-]?token)[\"']?\s*[:=]\s*[\"']?[a-zA-Z0-9_\-\.~=]{20,}[\"']?`)},
+	{"GitHub token", regexp.MustCompile(`(?i)gh[pousr]_[a-zA-Z0-9]{36,}`)},
+	// Bearer tokens
+	{"bearer token", regexp.MustCompile(`(?i)bearer\s+[a-zA-Z0-9_\-\.~=]+`)},
+	// Secrets
+	{"secret", regexp.MustCompile(`(?i)(secret|secret[_-]?key|secret[_-]?id)[\"']?\s*[:=]\s*[\"']?[a-zA-Z0-9_\-]{16,}[\"']?`)},
+	// Email
+	{kindEmail, regexp.MustCompile(`[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}`)},
+	// Private keys
+	{"private key", regexp.MustCompile(`-----BEGIN\s+(RSA\s+)?PRIVATE\s+KEY-----`)},
+}
+
+// kindEmail is the kind of the email pattern, which Scan skips.
+const kindEmail = "email address"
+
+// pattern is a named secret pattern.
+type pattern struct {
+	kind string
+	re   *regexp.Regexp
+}
+
+// strictPatterns are applied before the basic ones at the strict level.
+// Text matched by a pattern's first and second groups, if any, is kept
+// around the placeholder.
+var strictPatterns = compile(
+	// Whole private key blocks
+	`(?s)-----BEGIN\s+[A-Z ]*PRIVATE\s+KEY-----.*?-----END\s+[A-Z ]*PRIVATE\s+KEY-----`,
+	// JWTs
+	`eyJ[a-zA-Z0-9_\-]{8,}\.[a-zA-Z0-9_\-]{8,}\.[a-zA-Z0-9_\-]+`,
+	// Credentials in URLs, keeping the scheme and host
+	`(://)[^/\s:@]+:[^/\s@]+(@)`,
+	// Long hex strings (hashes, keys)
+	`\b[0-9a-fA-F]{32,}\b`,
+	// Long base64 strings
+	`[A-Za-z0-9+/]{40,}={0,2}`,
+)
+
+// String masks secrets in s at the given level. Unknown levels are treated
+// as basic.
+func String(s, level string) string {
+	if s == "" || level == None {
+		return s
+	}
+
+	if level == Strict {
+		for _, re := range strictPatterns {
+			s = re.ReplaceAllString(s, "${1}"+Placeholder+"${2}")
+		}
+	}
+	for _, p := range basicPatterns {
+		s = p.re.ReplaceAllString(s, Placeholder)
+	}
+	return s
+}
+
+func compile(patterns ...string) []*regexp.Regexp {
+	res := make([]*regexp.Regexp, len(patterns))
+	for i, p := range patterns {
+		res[i] = regexp.MustCompile(p)
+	}
+	return res
+}
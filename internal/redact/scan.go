@@ -0,0 +1,59 @@
+package redact
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Finding is a likely raw secret found by Scan.
+type Finding struct {
+	// Line is the 1-based line of the secret.
+	Line int
+	// Kind describes the secret, e.g. "password".
+	Kind string
+	// Context is the line with secrets masked.
+	Context string
+}
+
+// String describes the finding without revealing the secret.
+func (f Finding) String() string {
+	return fmt.Sprintf("line %d: %s (%s)", f.Line, f.Kind, f.Context)
+}
+
+// maxContext is the longest Context kept, in bytes.
+const maxContext = 80
+
+// Scan reports the lines of s that appear to contain raw secrets, using
+// the basic patterns. Email addresses aren't secrets and are skipped, as
+// are values that refer to a secret rather than contain it: <placeholders>,
+// $VARIABLES and {{templates}}.
+func Scan(s string) []Finding {
+	var findings []Finding
+	for i, line := range strings.Split(s, "\n") {
+		for _, p := range basicPatterns {
+			if p.kind == kindEmail {
+				continue
+			}
+			if !containsLiteral(p, line) {
+				continue
+			}
+			context := strings.TrimSpace(String(line, Basic))
+			if len(context) > maxContext {
+				context = context[:maxContext] + "..."
+			}
+			findings = append(findings, Finding{Line: i + 1, Kind: p.kind, Context: context})
+			break
+		}
+	}
+	return findings
+}
+
+// containsLiteral reports whether p matches a literal value in line.
+func containsLiteral(p pattern, line string) bool {
+	for _, match := range p.re.FindAllString(line, -1) {
+		if !strings.ContainsAny(match, "<$") && !strings.Contains(match, "{{") {
+			return true
+		}
+	}
+	return false
+}
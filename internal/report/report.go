@@ -0,0 +1,370 @@
+// Package report renders run records as incident timelines to attach to
+// postmortems: what ran when, how long it took, what the operator
+// confirmed, and the (redacted) output.
+package report
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/chazuruo/svf/internal/redact"
+	"github.com/chazuruo/svf/internal/runlog"
+)
+
+// Format is a report format.
+type Format string
+
+const (
+	// FormatMarkdown renders Markdown.
+	FormatMarkdown Format = "md"
+	// FormatHTML renders a standalone HTML page.
+	FormatHTML Format = "html"
+)
+
+// Report sections, in the order they are rendered.
+const (
+	SectionSummary  = "summary"
+	SectionTimeline = "timeline"
+	SectionOutputs  = "outputs"
+	SectionFindings = "findings"
+)
+
+// Sections lists every section in order.
+var Sections = []string{SectionSummary, SectionTimeline, SectionOutputs, SectionFindings}
+
+// Options configures Render.
+type Options struct {
+	Format Format
+
+	// Sections to include (default: all).
+	Sections []string
+
+	// Redact is the redaction level applied to commands, output and
+	// errors on top of the redaction done when the run was recorded
+	// (default: strict).
+	Redact string
+}
+
+// ParseSections parses a comma-separated list of sections.
+func ParseSections(s string) ([]string, error) {
+	var sections []string
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !isSection(name) {
+			return nil, fmt.Errorf("unknown section %q: use %s", name, strings.Join(Sections, ", "))
+		}
+		sections = append(sections, name)
+	}
+	return sections, nil
+}
+
+func isSection(name string) bool {
+	for _, s := range Sections {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Render renders a run record as an incident timeline.
+func Render(rec *runlog.Record, opts Options) (string, error) {
+	if opts.Redact == "" {
+		opts.Redact = redact.Strict
+	}
+	sections := opts.Sections
+	if len(sections) == 0 {
+		sections = Sections
+	}
+	v := newView(rec, opts.Redact)
+	v.Sections = make(map[string]bool, len(sections))
+	for _, s := range sections {
+		v.Sections[s] = true
+	}
+
+	var buf bytes.Buffer
+	switch opts.Format {
+	case FormatMarkdown, "":
+		if err := markdownTemplate.Execute(&buf, v); err != nil {
+			return "", err
+		}
+	case FormatHTML:
+		if err := htmlTemplate.Execute(&buf, v); err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("unsupported format %q: use md or html", opts.Format)
+	}
+	return buf.String(), nil
+}
+
+// view is the data the templates render.
+type view struct {
+	Title      string
+	RunID      string
+	WorkflowID string
+	Status     string
+	Started    string
+	Finished   string
+	Duration   string
+	FailedStep string
+	Steps      []stepView
+	Findings   []findingView
+	Sections   map[string]bool
+}
+
+type stepView struct {
+	Number       int
+	Name         string
+	Command      string
+	Time         string // Wall-clock start, UTC
+	Offset       string // Start relative to the run's start
+	Duration     string
+	Result       string
+	Confirmation string
+	Output       string
+	Error        string
+}
+
+type findingView struct {
+	At     string
+	Source string
+	Text   string
+}
+
+func newView(rec *runlog.Record, level string) view {
+	v := view{
+		Title:      rec.WorkflowTitle,
+		RunID:      rec.ID,
+		WorkflowID: rec.WorkflowID,
+		Status:     rec.Status(),
+		Started:    formatTime(rec.StartedAt, "2006-01-02 15:04:05 MST"),
+		Finished:   formatTime(rec.FinishedAt, "2006-01-02 15:04:05 MST"),
+	}
+	if !rec.StartedAt.IsZero() && !rec.FinishedAt.IsZero() {
+		v.Duration = formatDuration(rec.FinishedAt.Sub(rec.StartedAt))
+	}
+	if failed := rec.FailedStep(); failed != nil {
+		v.FailedStep = fmt.Sprintf("%d. %s (exit %d)", failed.Index+1, stepName(*failed), failed.ExitCode)
+	}
+
+	for _, s := range rec.Steps {
+		sv := stepView{
+			Number:       s.Index + 1,
+			Name:         stepName(s),
+			Command:      redact.String(s.Command, level),
+			Time:         formatTime(s.StartedAt, "15:04:05"),
+			Duration:     formatDuration(s.Duration),
+			Result:       stepResult(s),
+			Confirmation: confirmationLabel(s.Confirmation),
+			Output:       strings.TrimRight(redact.String(s.Output, level), "\n"),
+			Error:        redact.String(s.Error, level),
+		}
+		if !s.StartedAt.IsZero() && !rec.StartedAt.IsZero() {
+			sv.Offset = "+" + formatDuration(s.StartedAt.Sub(rec.StartedAt))
+		}
+		v.Steps = append(v.Steps, sv)
+	}
+
+	for _, f := range rec.Findings {
+		v.Findings = append(v.Findings, findingView{
+			At:     formatTime(f.At, "2006-01-02 15:04:05 MST"),
+			Source: f.Source,
+			Text:   redact.String(f.Text, level),
+		})
+	}
+	return v
+}
+
+func stepName(s runlog.StepRecord) string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return fmt.Sprintf("Step %d", s.Index+1)
+}
+
+func stepResult(s runlog.StepRecord) string {
+	switch {
+	case s.Skipped && s.SkipReason != "":
+		return "skipped (" + s.SkipReason + ")"
+	case s.Skipped:
+		return "skipped"
+	case s.Success:
+		return "ok"
+	default:
+		return fmt.Sprintf("failed (exit %d)", s.ExitCode)
+	}
+}
+
+func confirmationLabel(c string) string {
+	switch c {
+	case "":
+		return ""
+	case "operator":
+		return "confirmed by operator"
+	case "auto":
+		return "auto-confirmed (--yes)"
+	default:
+		return c
+	}
+}
+
+// formatTime formats t in UTC, or returns "" for the zero time.
+func formatTime(t time.Time, layout string) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(layout)
+}
+
+// formatDuration rounds a duration for display.
+func formatDuration(d time.Duration) string {
+	switch {
+	case d < time.Second:
+		return d.Round(time.Millisecond).String()
+	case d < time.Minute:
+		return d.Round(100 * time.Millisecond).String()
+	default:
+		return d.Round(time.Second).String()
+	}
+}
+
+// cell escapes text for a Markdown table cell.
+func cell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// code renders text as inline Markdown code in a table cell.
+func code(s string) string {
+	if s == "" {
+		return ""
+	}
+	s = cell(s)
+	if strings.Contains(s, "`") {
+		return "`` " + s + " ``"
+	}
+	return "`" + s + "`"
+}
+
+// fence returns a code fence that doesn't occur in s.
+func fence(s string) string {
+	f := "```"
+	for strings.Contains(s, f) {
+		f += "`"
+	}
+	return f
+}
+
+var markdownTemplate = template.Must(template.New("md").Funcs(template.FuncMap{
+	"cell": cell, "code": code, "fence": fence,
+}).Parse(`# Incident timeline: {{.Title}}
+{{if .Sections.summary}}
+## Summary
+
+| | |
+|---|---|
+| Workflow | {{cell .Title}}{{if .WorkflowID}} ({{code .WorkflowID}}){{end}} |
+| Run | {{code .RunID}} |
+| Status | {{.Status}} |
+| Started | {{.Started}} |
+| Finished | {{.Finished}} |
+| Duration | {{.Duration}} |
+{{- if .FailedStep}}
+| Failed step | {{cell .FailedStep}} |
+{{- end}}
+{{end}}
+{{- if .Sections.timeline}}
+## Timeline
+
+| Time (UTC) | Step | Command | Result | Duration | Confirmation |
+|---|---|---|---|---|---|
+{{- range .Steps}}
+| {{.Time}}{{if .Offset}} ({{.Offset}}){{end}} | {{.Number}}. {{cell .Name}} | {{code .Command}} | {{.Result}} | {{.Duration}} | {{.Confirmation}} |
+{{- end}}
+{{end}}
+{{- if .Sections.outputs}}
+## Outputs
+{{range .Steps}}{{if or .Output .Error}}
+### {{.Number}}. {{.Name}}
+{{if .Output}}
+{{$f := fence .Output}}{{$f}}
+{{.Output}}
+{{$f}}
+{{end}}{{if .Error}}
+Error: {{.Error}}
+{{end}}{{end}}{{end}}{{end}}
+{{- if and .Sections.findings .Findings}}
+## Findings
+{{range .Findings}}
+**{{.Source}}** ({{.At}}):
+
+{{.Text}}
+{{end}}{{end}}`))
+
+var htmlTemplate = htmltemplate.Must(htmltemplate.New("html").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Incident timeline: {{.Title}}</title>
+<style>
+body { font-family: sans-serif; max-width: 60em; margin: 2em auto; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.6em; text-align: left; vertical-align: top; }
+pre { background: #f6f6f6; padding: 0.6em; overflow-x: auto; }
+</style>
+</head>
+<body>
+<h1>Incident timeline: {{.Title}}</h1>
+{{- if .Sections.summary}}
+<h2>Summary</h2>
+<table>
+<tr><th>Workflow</th><td>{{.Title}}{{if .WorkflowID}} (<code>{{.WorkflowID}}</code>){{end}}</td></tr>
+<tr><th>Run</th><td><code>{{.RunID}}</code></td></tr>
+<tr><th>Status</th><td>{{.Status}}</td></tr>
+<tr><th>Started</th><td>{{.Started}}</td></tr>
+<tr><th>Finished</th><td>{{.Finished}}</td></tr>
+<tr><th>Duration</th><td>{{.Duration}}</td></tr>
+{{- if .FailedStep}}
+<tr><th>Failed step</th><td>{{.FailedStep}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .Sections.timeline}}
+<h2>Timeline</h2>
+<table>
+<tr><th>Time (UTC)</th><th>Step</th><th>Command</th><th>Result</th><th>Duration</th><th>Confirmation</th></tr>
+{{- range .Steps}}
+<tr><td>{{.Time}}{{if .Offset}} ({{.Offset}}){{end}}</td><td>{{.Number}}. {{.Name}}</td><td><code>{{.Command}}</code></td><td>{{.Result}}</td><td>{{.Duration}}</td><td>{{.Confirmation}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .Sections.outputs}}
+<h2>Outputs</h2>
+{{- range .Steps}}{{if or .Output .Error}}
+<h3>{{.Number}}. {{.Name}}</h3>
+{{- if .Output}}
+<pre>{{.Output}}</pre>
+{{- end}}
+{{- if .Error}}
+<p>Error: {{.Error}}</p>
+{{- end}}
+{{- end}}{{end}}
+{{- end}}
+{{- if and .Sections.findings .Findings}}
+<h2>Findings</h2>
+{{- range .Findings}}
+<h3>{{.Source}} ({{.At}})</h3>
+<pre>{{.Text}}</pre>
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
@@ -0,0 +1,84 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chazuruo/svf/internal/runlog"
+)
+
+func testRecord() *runlog.Record {
+	start := time.Date(2026, 3, 14, 9, 0, 0, 0, time.UTC)
+	return &runlog.Record{
+		ID:            "run_20260314T090000_abcd1234",
+		WorkflowID:    "wf_deploy",
+		WorkflowTitle: "Deploy API",
+		StartedAt:     start,
+		FinishedAt:    start.Add(95 * time.Second),
+		Steps: []runlog.StepRecord{
+			{Index: 0, Name: "Build", Command: "make build | tee build.log", Success: true, Output: "ok\n", StartedAt: start.Add(time.Second), Duration: 30 * time.Second},
+			{Index: 1, Name: "Migrate", Command: "psql -c 'drop table cache'", ExitCode: 1, Output: "password=hunter2\nERROR: permission denied\n", Error: "exit status 1",
+				Confirmation: "operator", StartedAt: start.Add(40 * time.Second), Duration: 2 * time.Second},
+		},
+		Findings: []runlog.Finding{{At: start.Add(time.Hour), Source: "ai", Text: "The role lacks DROP privileges."}},
+	}
+}
+
+func TestRender_Markdown(t *testing.T) {
+	got, err := Render(testRecord(), Options{})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	for _, want := range []string{
+		"# Incident timeline: Deploy API",
+		"| Status | failed |",
+		"| Failed step | 2. Migrate (exit 1) |",
+		"| 09:00:01 (+1s) | 1. Build | `make build \\| tee build.log` | ok | 30s |  |",
+		"| 09:00:40 (+40s) | 2. Migrate | `psql -c 'drop table cache'` | failed (exit 1) | 2s | confirmed by operator |",
+		"<REDACTED>\nERROR: permission denied",
+		"Error: exit status 1",
+		"The role lacks DROP privileges.",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Render() missing %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "hunter2") {
+		t.Errorf("Render() leaked a secret:\n%s", got)
+	}
+}
+
+func TestRender_Sections(t *testing.T) {
+	sections, err := ParseSections("timeline, findings")
+	if err != nil {
+		t.Fatalf("ParseSections() error = %v", err)
+	}
+	got, err := Render(testRecord(), Options{Sections: sections})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if strings.Contains(got, "## Summary") || strings.Contains(got, "## Outputs") || !strings.Contains(got, "## Timeline") {
+		t.Errorf("Render() with timeline and findings =\n%s", got)
+	}
+
+	if _, err := ParseSections("timeline,commands"); err == nil {
+		t.Error("ParseSections() with an unknown section succeeded")
+	}
+}
+
+func TestRender_HTML(t *testing.T) {
+	rec := testRecord()
+	rec.Steps[0].Output = "<script>alert(1)</script>"
+	got, err := Render(rec, Options{Format: FormatHTML})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if !strings.Contains(got, "<h1>Incident timeline: Deploy API</h1>") || !strings.Contains(got, "&lt;script&gt;") {
+		t.Errorf("Render() HTML =\n%s", got)
+	}
+
+	if _, err := Render(rec, Options{Format: "pdf"}); err == nil {
+		t.Error("Render() with an unknown format succeeded")
+	}
+}
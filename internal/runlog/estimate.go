@@ -0,0 +1,51 @@
+package runlog
+
+import (
+	"sort"
+	"time"
+)
+
+// estimateRuns is how many of a workflow's most recent successful runs
+// EstimateStepDurations considers, so estimates follow recent changes.
+const estimateRuns = 20
+
+// EstimateStepDurations returns the typical duration of each of a
+// workflow's steps: the median over its most recent successful runs, with
+// records ordered newest first as returned by List. Steps that never ran
+// in those runs estimate to zero. It returns nil when the workflow has no
+// ID or no successful runs.
+func EstimateStepDurations(records []*Record, workflowID string, steps int) []time.Duration {
+	if workflowID == "" || steps == 0 {
+		return nil
+	}
+
+	samples := make([][]time.Duration, steps)
+	runs := 0
+	for _, r := range records {
+		if r.WorkflowID != workflowID || !r.Success || r.Canceled {
+			continue
+		}
+		for _, s := range r.Steps {
+			if s.Index < steps && !s.Skipped {
+				samples[s.Index] = append(samples[s.Index], s.Duration)
+			}
+		}
+		runs++
+		if runs == estimateRuns {
+			break
+		}
+	}
+	if runs == 0 {
+		return nil
+	}
+
+	estimates := make([]time.Duration, steps)
+	for i, d := range samples {
+		if len(d) == 0 {
+			continue
+		}
+		sort.Slice(d, func(a, b int) bool { return d[a] < d[b] })
+		estimates[i] = d[len(d)/2]
+	}
+	return estimates
+}
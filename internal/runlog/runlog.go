@@ -0,0 +1,314 @@
+// Package runlog persists local records of workflow runs.
+//
+// Run records are kept on the local machine only (never committed) under
+// $XDG_STATE_HOME/svf/runs, defaulting to ~/.local/state/svf/runs.
+package runlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/chazuruo/svf/internal/config"
+	svferrors "github.com/chazuruo/svf/internal/errors"
+)
+
+// maxOutputBytes caps the stored output of each step. The tail is kept
+// because errors usually appear at the end.
+const maxOutputBytes = 64 * 1024
+
+// ErrNotFound is returned when no run matches an ID.
+var ErrNotFound = fmt.Errorf("run %w", svferrors.ErrNotFound)
+
+// Record is a single workflow run.
+type Record struct {
+	ID            string       `json:"id"`
+	WorkflowID    string       `json:"workflow_id,omitempty"`
+	WorkflowTitle string       `json:"workflow_title"`
+	Repo          string       `json:"repo,omitempty"` // Path of the workflow repository
+	StartedAt     time.Time    `json:"started_at"`
+	FinishedAt    time.Time    `json:"finished_at"`
+	Success       bool         `json:"success"`
+	Canceled      bool         `json:"canceled,omitempty"`
+	Steps         []StepRecord `json:"steps"`
+	Findings      []Finding    `json:"findings,omitempty"`
+}
+
+// StepRecord is the outcome of one executed step.
+type StepRecord struct {
+	Index        int           `json:"index"`
+	Name         string        `json:"name"`
+	Command      string        `json:"command"`
+	ExitCode     int           `json:"exit_code"`
+	Success      bool          `json:"success"`
+	Skipped      bool          `json:"skipped,omitempty"`
+	SkipReason   string        `json:"skip_reason,omitempty"`  // e.g. "platform"
+	Confirmation string        `json:"confirmation,omitempty"` // "operator" or "auto" for confirmed dangerous commands
+	Output       string        `json:"output,omitempty"`
+	Error        string        `json:"error,omitempty"`
+	StartedAt    time.Time     `json:"started_at,omitzero"`
+	Duration     time.Duration `json:"duration"`
+}
+
+// Finding is a note attached to a run, such as an AI failure analysis.
+type Finding struct {
+	At     time.Time `json:"at"`
+	Source string    `json:"source"`
+	Text   string    `json:"text"`
+}
+
+// FailedStep returns the first failed (not skipped) step, or nil.
+func (r *Record) FailedStep() *StepRecord {
+	for i := range r.Steps {
+		if !r.Steps[i].Success && !r.Steps[i].Skipped {
+			return &r.Steps[i]
+		}
+	}
+	return nil
+}
+
+// Status returns a short status label for the run.
+func (r *Record) Status() string {
+	switch {
+	case r.Canceled:
+		return "canceled"
+	case r.Success:
+		return "succeeded"
+	default:
+		return "failed"
+	}
+}
+
+// NewID returns a new run ID. IDs sort by start time.
+func NewID(t time.Time) string {
+	return fmt.Sprintf("run_%s_%s", t.UTC().Format("20060102T150405"), uuid.NewString()[:8])
+}
+
+// idFormat is the form of the IDs NewID returns, where 0 stands for a
+// digit and f for a lowercase hex digit.
+const idFormat = "run_00000000T000000_ffffffff"
+
+// validID reports whether id has the form of the IDs NewID returns or,
+// with prefix, is the start of one. IDs name the record files, so this
+// also keeps them from reaching outside the store directory.
+func validID(id string, prefix bool) bool {
+	if id == "" || len(id) > len(idFormat) || (!prefix && len(id) != len(idFormat)) {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		switch idFormat[i] {
+		case '0':
+			if c < '0' || c > '9' {
+				return false
+			}
+		case 'f':
+			if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+				return false
+			}
+		default:
+			if c != idFormat[i] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// TruncateOutput keeps at most maxOutputBytes from the end of output.
+func TruncateOutput(output string) string {
+	if len(output) <= maxOutputBytes {
+		return output
+	}
+	return "...(truncated)\n" + output[len(output)-maxOutputBytes:]
+}
+
+// DefaultDir returns the default directory for run records.
+func DefaultDir() (string, error) {
+	dir, err := config.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "runs"), nil
+}
+
+// Store reads and writes run records in a directory.
+type Store struct {
+	dir string
+}
+
+// NewStore creates a store for dir.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// NewDefaultStore creates a store in the default directory.
+func NewDefaultStore() (*Store, error) {
+	dir, err := DefaultDir()
+	if err != nil {
+		return nil, err
+	}
+	return NewStore(dir), nil
+}
+
+// Dir returns the store directory.
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Save writes a record, assigning an ID if it has none.
+func (s *Store) Save(r *Record) error {
+	if r.ID == "" {
+		r.ID = NewID(r.StartedAt)
+	} else if !validID(r.ID, false) {
+		return fmt.Errorf("invalid run ID %q", r.ID)
+	}
+
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create run directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal run record: %w", err)
+	}
+
+	// Write atomically so a crash never leaves a partial record
+	path := s.Path(r.ID)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write run record: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write run record: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the record with the given ID.
+func (s *Store) Delete(id string) error {
+	if !validID(id, false) {
+		return fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	if err := os.Remove(s.Path(id)); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("%w: %s", ErrNotFound, id)
+		}
+		return fmt.Errorf("failed to delete run record: %w", err)
+	}
+	return nil
+}
+
+// Load reads a record by ID, unique ID prefix, or "last" for the most
+// recent run. Anything else, like a path, is not found.
+func (s *Store) Load(id string) (*Record, error) {
+	if id == "last" {
+		records, err := s.List()
+		if err != nil {
+			return nil, err
+		}
+		if len(records) == 0 {
+			return nil, ErrNotFound
+		}
+		return records[0], nil
+	}
+	if !validID(id, true) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+
+	if r, err := s.read(s.Path(id)); err == nil {
+		return r, nil
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	// Fall back to a unique prefix match
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to read run directory: %w", err)
+	}
+	var matches []string
+	for _, e := range entries {
+		name := strings.TrimSuffix(e.Name(), ".json")
+		if strings.HasSuffix(e.Name(), ".json") && strings.HasPrefix(name, id) {
+			matches = append(matches, name)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	case 1:
+		return s.read(s.Path(matches[0]))
+	default:
+		return nil, fmt.Errorf("run ID %q is ambiguous (%d matches)", id, len(matches))
+	}
+}
+
+// List returns all records, most recent first.
+func (s *Store) List() ([]*Record, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read run directory: %w", err)
+	}
+
+	var records []*Record
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		r, err := s.read(filepath.Join(s.dir, e.Name()))
+		if err != nil {
+			// Skip unreadable records rather than failing the listing
+			continue
+		}
+		records = append(records, r)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].StartedAt.After(records[j].StartedAt)
+	})
+	return records, nil
+}
+
+// AddFinding appends a finding to a stored record.
+func (s *Store) AddFinding(id string, f Finding) error {
+	r, err := s.Load(id)
+	if err != nil {
+		return err
+	}
+	if f.At.IsZero() {
+		f.At = time.Now()
+	}
+	r.Findings = append(r.Findings, f)
+	return s.Save(r)
+}
+
+// Path returns the file path for a record ID.
+func (s *Store) Path(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
+
+// read loads a record file.
+func (s *Store) read(path string) (*Record, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var r Record
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("failed to parse run record %s: %w", filepath.Base(path), err)
+	}
+	return &r, nil
+}
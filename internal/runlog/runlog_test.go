@@ -0,0 +1,145 @@
+package runlog
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreSaveLoad(t *testing.T) {
+	s := NewStore(t.TempDir())
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	r := &Record{
+		WorkflowTitle: "Deploy",
+		StartedAt:     start,
+		Steps: []StepRecord{
+			{Index: 0, Name: "build", Command: "make", Success: true},
+			{Index: 1, Name: "push", Command: "make push", ExitCode: 2, Output: "denied"},
+		},
+	}
+	require.NoError(t, s.Save(r))
+	assert.True(t, strings.HasPrefix(r.ID, "run_20260102T030405_"))
+
+	loaded, err := s.Load(r.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Deploy", loaded.WorkflowTitle)
+	assert.Equal(t, "failed", loaded.Status())
+	require.NotNil(t, loaded.FailedStep())
+	assert.Equal(t, "push", loaded.FailedStep().Name)
+
+	// Prefix and "last" lookups
+	byPrefix, err := s.Load(r.ID[:len(r.ID)-4])
+	require.NoError(t, err)
+	assert.Equal(t, r.ID, byPrefix.ID)
+
+	later := &Record{WorkflowTitle: "Later", StartedAt: start.Add(time.Hour), Success: true}
+	require.NoError(t, s.Save(later))
+	last, err := s.Load("last")
+	require.NoError(t, err)
+	assert.Equal(t, later.ID, last.ID)
+
+	_, err = s.Load("run_2026")
+	assert.Error(t, err, "expected ambiguous prefix error")
+
+	_, err = s.Load("nope")
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestStoreRejectsInvalidIDs(t *testing.T) {
+	root := t.TempDir()
+	s := NewStore(filepath.Join(root, "runs"))
+
+	// A record outside the store directory stays out of reach
+	outside := &Record{ID: "run_20260102T030405_abcd1234", WorkflowTitle: "Outside"}
+	require.NoError(t, NewStore(root).Save(outside))
+	for _, id := range []string{"../run_20260102T030405_abcd1234", "../run_2026", "/etc/passwd", "run_2026/../x"} {
+		_, err := s.Load(id)
+		assert.True(t, errors.Is(err, ErrNotFound), "Load(%q) error = %v", id, err)
+		assert.True(t, errors.Is(s.Delete(id), ErrNotFound), "Delete(%q)", id)
+	}
+
+	assert.Error(t, s.Save(&Record{ID: "../escape"}))
+	assert.True(t, validID(NewID(time.Now()), false))
+}
+
+func TestStoreList(t *testing.T) {
+	s := NewStore(t.TempDir())
+
+	records, err := s.List()
+	require.NoError(t, err)
+	assert.Empty(t, records)
+
+	base := time.Now()
+	for i := 0; i < 3; i++ {
+		require.NoError(t, s.Save(&Record{WorkflowTitle: "wf", StartedAt: base.Add(time.Duration(i) * time.Minute)}))
+	}
+
+	records, err = s.List()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.True(t, records[0].StartedAt.After(records[2].StartedAt))
+}
+
+func TestStoreAddFinding(t *testing.T) {
+	s := NewStore(t.TempDir())
+	r := &Record{WorkflowTitle: "wf", StartedAt: time.Now()}
+	require.NoError(t, s.Save(r))
+
+	require.NoError(t, s.AddFinding(r.ID, Finding{Source: "ai", Text: "check credentials"}))
+
+	loaded, err := s.Load(r.ID)
+	require.NoError(t, err)
+	require.Len(t, loaded.Findings, 1)
+	assert.Equal(t, "check credentials", loaded.Findings[0].Text)
+	assert.False(t, loaded.Findings[0].At.IsZero())
+}
+
+func TestTruncateOutput(t *testing.T) {
+	assert.Equal(t, "short", TruncateOutput("short"))
+
+	long := strings.Repeat("a", maxOutputBytes) + "END"
+	got := TruncateOutput(long)
+	assert.True(t, strings.HasPrefix(got, "...(truncated)\n"))
+	assert.True(t, strings.HasSuffix(got, "END"))
+}
+
+func TestEstimateStepDurations(t *testing.T) {
+	run := func(workflowID string, success bool, durations ...time.Duration) *Record {
+		r := &Record{WorkflowID: workflowID, Success: success}
+		for i, d := range durations {
+			r.Steps = append(r.Steps, StepRecord{Index: i, Success: true, Duration: d})
+		}
+		return r
+	}
+	records := []*Record{
+		run("wf_deploy", true, 10*time.Second, time.Minute),
+		run("wf_deploy", false, time.Hour),
+		run("wf_other", true, time.Hour, time.Hour),
+		run("wf_deploy", true, 12*time.Second),
+		run("wf_deploy", true, 30*time.Second, 3*time.Minute),
+	}
+
+	assert.Equal(t, []time.Duration{12 * time.Second, 3 * time.Minute, 0}, EstimateStepDurations(records, "wf_deploy", 3))
+	assert.Nil(t, EstimateStepDurations(records, "wf_new", 2))
+}
+
+func TestSummarizeUsage(t *testing.T) {
+	base := time.Date(2026, 3, 9, 12, 0, 0, 0, time.UTC)
+	records := []*Record{
+		{WorkflowID: "wf_deploy", StartedAt: base.Add(time.Hour)},
+		{WorkflowID: "wf_other", StartedAt: base},
+		{WorkflowID: "wf_deploy", StartedAt: base.Add(-time.Hour)},
+		{WorkflowTitle: "no id", StartedAt: base.Add(2 * time.Hour)},
+	}
+
+	usage := SummarizeUsage(records)
+	assert.Len(t, usage, 2)
+	assert.Equal(t, Usage{Runs: 2, LastRun: base.Add(time.Hour)}, usage["wf_deploy"])
+	assert.Equal(t, Usage{Runs: 1, LastRun: base}, usage["wf_other"])
+}
@@ -0,0 +1,28 @@
+package runlog
+
+import "time"
+
+// Usage summarizes the local runs of one workflow.
+type Usage struct {
+	Runs    int
+	LastRun time.Time
+}
+
+// SummarizeUsage counts the runs of each workflow in records and finds
+// when each was last started, by workflow ID. Runs of workflows without
+// an ID are left out.
+func SummarizeUsage(records []*Record) map[string]Usage {
+	usage := make(map[string]Usage)
+	for _, r := range records {
+		if r.WorkflowID == "" {
+			continue
+		}
+		u := usage[r.WorkflowID]
+		u.Runs++
+		if r.StartedAt.After(u.LastRun) {
+			u.LastRun = r.StartedAt
+		}
+		usage[r.WorkflowID] = u
+	}
+	return usage
+}
@@ -0,0 +1,205 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/chazuruo/svf/internal/placeholders"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// BatchOptions configures RunBatch.
+type BatchOptions struct {
+	// Params are placeholder values; placeholder defaults fill the rest.
+	Params map[string]string
+	// DryRun resolves every command without running any.
+	DryRun bool
+	// CheckDangerous refuses dangerous commands unless ConfirmDangerous
+	// is set.
+	CheckDangerous bool
+	// ConfirmDangerous allows dangerous commands to run.
+	ConfirmDangerous bool
+	// RepoRoot is the repository root relative step directories resolve
+	// against.
+	RepoRoot string
+	// StepTimeout stops steps that run longer; zero means no limit.
+	StepTimeout time.Duration
+}
+
+// BatchStep is the outcome of one step of a batch run.
+type BatchStep struct {
+	Name    string
+	Command string // With placeholders substituted
+	Ran     bool
+	Error   error // Why the step failed, if it did without running its command
+}
+
+// BatchResult is the outcome of RunBatch.
+type BatchResult struct {
+	Success    bool
+	ExitCode   int
+	FailedStep int // 1-based, 0 if none failed
+	Steps      []BatchStep
+	Results    []StepResult // Results of the steps that ran or were skipped
+	StartedAt  time.Time
+
+	// Params are the placeholder values the run ended with, including
+	// ones captured by its steps.
+	Params map[string]string
+}
+
+// ParamError is returned by RunBatch when a parameter fails its
+// placeholder's validation pattern.
+type ParamError struct {
+	Name string
+	Err  error
+}
+
+// Error implements error.
+func (e *ParamError) Error() string {
+	return fmt.Sprintf("invalid value for <%s>: %v", e.Name, e.Err)
+}
+
+// Unwrap returns the validation error.
+func (e *ParamError) Unwrap() error {
+	return e.Err
+}
+
+// ErrDangerous is returned by RunBatch when a workflow includes dangerous
+// commands that weren't confirmed.
+var ErrDangerous = errors.New("workflow contains dangerous commands")
+
+// RunBatch executes a workflow's steps in order without prompting, like
+// 'svf run --yes'. Every command is resolved before the first step runs,
+// so nothing runs if a placeholder is missing or invalid. Steps after a
+// failed one are not run, unless the failed step has continue_on_error.
+func RunBatch(ctx context.Context, wf *workflows.Workflow, opts BatchOptions) (BatchResult, error) {
+	for i := range wf.Steps {
+		wf.ApplyDefaults(&wf.Steps[i])
+	}
+
+	info := placeholders.ExtractWithMetadata(wf)
+	params := make(map[string]string)
+	for name, ph := range info {
+		if ph.Default != "" {
+			params[name] = ph.Default
+		}
+	}
+	for k, v := range opts.Params {
+		params[k] = v
+	}
+	for name, value := range params {
+		if err := placeholders.Validate(value, info[name].Validate); err != nil {
+			return BatchResult{}, &ParamError{Name: name, Err: err}
+		}
+	}
+
+	// Captured placeholders are filled in as steps run; until then they
+	// are shown as-is
+	preview := make(map[string]string, len(params))
+	for k, v := range params {
+		preview[k] = v
+	}
+	for _, step := range wf.Steps {
+		for name := range step.Capture {
+			if _, ok := preview[name]; !ok {
+				preview[name] = "<" + name + ">"
+			}
+		}
+	}
+	commands := make([]string, len(wf.Steps))
+	var dangerous []string
+	for i, step := range wf.Steps {
+		cmd, err := placeholders.Substitute(step.Command, preview)
+		if err != nil {
+			return BatchResult{}, err
+		}
+		commands[i] = cmd
+		if opts.CheckDangerous {
+			if info := CheckDangerous(cmd); info != nil {
+				dangerous = append(dangerous, fmt.Sprintf("step %d: %s", i+1, info.Name))
+			}
+		}
+	}
+	// Cleanup steps run unattended too when a step fails; those using a
+	// placeholder without a value are skipped then
+	if opts.CheckDangerous {
+		for i, step := range wf.Cleanup {
+			cmd, err := placeholders.Substitute(step.Command, preview)
+			if err != nil {
+				continue
+			}
+			if info := CheckDangerous(cmd); info != nil {
+				dangerous = append(dangerous, fmt.Sprintf("cleanup step %d: %s", i+1, info.Name))
+			}
+		}
+	}
+	if len(dangerous) > 0 && !opts.ConfirmDangerous && !opts.DryRun {
+		return BatchResult{}, fmt.Errorf("%w (%s)", ErrDangerous, strings.Join(dangerous, ", "))
+	}
+
+	res := BatchResult{
+		Success:   true,
+		Steps:     make([]BatchStep, len(wf.Steps)),
+		Results:   make([]StepResult, len(wf.Steps)),
+		StartedAt: time.Now(),
+		Params:    params,
+	}
+	for i, step := range wf.Steps {
+		res.Steps[i] = BatchStep{Name: step.Name, Command: commands[i]}
+		if !step.RunsOn(runtime.GOOS, runtime.GOARCH) {
+			res.Results[i] = PlatformSkip(i)
+			continue
+		}
+		if opts.DryRun || !res.Success {
+			continue
+		}
+
+		// Fill in values captured by earlier steps
+		cmd, err := placeholders.Substitute(step.Command, params)
+		if err != nil {
+			res.Steps[i].Error = err
+			res.Success = false
+			res.ExitCode = 21 // Missing parameter
+			res.FailedStep = i + 1
+			continue
+		}
+		res.Steps[i].Command = cmd
+
+		result := Exec(ctx, ExecConfig{
+			Command:     cmd,
+			Shell:       step.Shell,
+			CWD:         ResolveCWD(step.CWD, wf.Defaults.CWD, opts.RepoRoot),
+			Env:         step.Env,
+			AutoConfirm: true,
+			Timeout:     opts.StepTimeout,
+		})
+		captured := ApplyCaptures(&step, &result)
+		for k, v := range captured {
+			params[k] = v
+		}
+		res.Steps[i].Ran = true
+		res.Results[i] = StepResult{
+			Step:         i,
+			Success:      result.Success,
+			ExitCode:     result.ExitCode,
+			Output:       result.Output,
+			Duration:     result.Duration,
+			Error:        result.Error,
+			Captured:     captured,
+			StartedAt:    result.StartedAt,
+			Confirmation: result.Confirmation,
+		}
+
+		if !result.Success && !step.ContinueOnError {
+			res.Success = false
+			res.ExitCode = result.ExitCode
+			res.FailedStep = i + 1
+		}
+	}
+	return res, nil
+}
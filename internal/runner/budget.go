@@ -0,0 +1,42 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+)
+
+// budgetHookTimeout bounds how long an over-budget hook may run.
+const budgetHookTimeout = 30 * time.Second
+
+// BudgetEvent describes a step running past its max_duration.
+type BudgetEvent struct {
+	Workflow    string
+	Step        int // 1-based
+	StepName    string
+	MaxDuration time.Duration
+}
+
+// RunBudgetHook runs the runner.over_budget_hook command with sh, passing
+// the event in SVF_WORKFLOW, SVF_STEP, SVF_STEP_NAME and SVF_MAX_DURATION
+// (e.g. "30s"), so teams can page or post to a channel. The hook's output
+// is discarded; it is stopped after budgetHookTimeout.
+func RunBudgetHook(ctx context.Context, command string, e BudgetEvent) error {
+	ctx, cancel := context.WithTimeout(ctx, budgetHookTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Env = append(os.Environ(),
+		"SVF_WORKFLOW="+e.Workflow,
+		"SVF_STEP="+strconv.Itoa(e.Step),
+		"SVF_STEP_NAME="+e.StepName,
+		"SVF_MAX_DURATION="+e.MaxDuration.String(),
+	)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("over-budget hook failed: %w", err)
+	}
+	return nil
+}
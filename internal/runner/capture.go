@@ -0,0 +1,22 @@
+package runner
+
+import (
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// ApplyCaptures runs a successful step's extractors over its standard
+// output and returns the captured values. A capture that fails marks the
+// step failed, since later steps depend on it.
+func ApplyCaptures(step *workflows.Step, result *ExecResult) map[string]string {
+	if !result.Success || len(step.Capture) == 0 {
+		return nil
+	}
+	values, err := step.Captures(result.Stdout)
+	if err != nil {
+		result.Success = false
+		result.ExitCode = 1
+		result.Error = err
+		return nil
+	}
+	return values
+}
@@ -0,0 +1,60 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// CloudState is the active cloud identity. Fields are empty when they
+// weren't looked up.
+type CloudState struct {
+	AWSProfile string
+	AWSAccount string
+	GCPProject string
+}
+
+// CurrentAWSProfile returns the AWS profile the aws CLI would use.
+func CurrentAWSProfile() string {
+	if profile := os.Getenv("AWS_PROFILE"); profile != "" {
+		return profile
+	}
+	return "default"
+}
+
+// CurrentAWSAccount asks AWS STS which account the current credentials
+// belong to.
+func CurrentAWSAccount(ctx context.Context) (string, error) {
+	return commandOutput(ctx, "aws", "sts", "get-caller-identity", "--query", "Account", "--output", "text")
+}
+
+// CurrentGCPProject returns the active gcloud project.
+func CurrentGCPProject(ctx context.Context) (string, error) {
+	project, err := commandOutput(ctx, "gcloud", "config", "get-value", "project")
+	if err != nil {
+		return "", err
+	}
+	if project == "" || project == "(unset)" {
+		return "", fmt.Errorf("gcloud: no project is set")
+	}
+	return project, nil
+}
+
+// CloudMismatches compares a workflow's aws_profile, aws_account and
+// gcp_project with the active state, returning one message per field
+// that doesn't match.
+func CloudMismatches(wf *workflows.Workflow, state CloudState) []string {
+	var mismatches []string
+	if wf.AWSProfile != "" && wf.AWSProfile != state.AWSProfile {
+		mismatches = append(mismatches, fmt.Sprintf("AWS profile is %q, workflow expects %q", state.AWSProfile, wf.AWSProfile))
+	}
+	if wf.AWSAccount != "" && wf.AWSAccount != state.AWSAccount {
+		mismatches = append(mismatches, fmt.Sprintf("AWS account is %q, workflow expects %q", state.AWSAccount, wf.AWSAccount))
+	}
+	if wf.GCPProject != "" && wf.GCPProject != state.GCPProject {
+		mismatches = append(mismatches, fmt.Sprintf("gcloud project is %q, workflow expects %q", state.GCPProject, wf.GCPProject))
+	}
+	return mismatches
+}
@@ -0,0 +1,54 @@
+package runner
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+func TestCurrentAWSProfile(t *testing.T) {
+	t.Setenv("AWS_PROFILE", "")
+	if got := CurrentAWSProfile(); got != "default" {
+		t.Errorf("CurrentAWSProfile() = %q, want default", got)
+	}
+	t.Setenv("AWS_PROFILE", "prod")
+	if got := CurrentAWSProfile(); got != "prod" {
+		t.Errorf("CurrentAWSProfile() = %q, want prod", got)
+	}
+}
+
+func TestCloudMismatches(t *testing.T) {
+	state := CloudState{AWSProfile: "prod", AWSAccount: "123456789012", GCPProject: "acme-prod"}
+
+	tests := []struct {
+		name string
+		wf   workflows.Workflow
+		want []string
+	}{
+		{name: "no guardrails"},
+		{
+			name: "all match",
+			wf:   workflows.Workflow{AWSProfile: "prod", AWSAccount: "123456789012", GCPProject: "acme-prod"},
+		},
+		{
+			name: "wrong account",
+			wf:   workflows.Workflow{AWSProfile: "prod", AWSAccount: "210987654321"},
+			want: []string{`AWS account is "123456789012", workflow expects "210987654321"`},
+		},
+		{
+			name: "wrong profile and project",
+			wf:   workflows.Workflow{AWSProfile: "staging", GCPProject: "acme-staging"},
+			want: []string{
+				`AWS profile is "prod", workflow expects "staging"`,
+				`gcloud project is "acme-prod", workflow expects "acme-staging"`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		got := CloudMismatches(&tt.wf, state)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: CloudMismatches() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CWDError is returned when a step's working directory cannot be used.
+type CWDError struct {
+	Path string
+	Err  error
+}
+
+func (e *CWDError) Error() string {
+	if os.IsNotExist(e.Err) {
+		return fmt.Sprintf("working directory %s does not exist", e.Path)
+	}
+	return fmt.Sprintf("working directory %s: %v", e.Path, e.Err)
+}
+
+func (e *CWDError) Unwrap() error {
+	return e.Err
+}
+
+// ExpandPath expands a leading ~ and $VAR / ${VAR} references in a path.
+func ExpandPath(path string) string {
+	if path == "" {
+		return path
+	}
+
+	path = os.ExpandEnv(path)
+
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, strings.TrimPrefix(path, "~"))
+		}
+	}
+
+	return path
+}
+
+// ResolveCWD resolves the working directory for a step.
+// The step's CWD takes precedence over the workflow default; both are
+// expanded, and relative paths are resolved against repoRoot.
+func ResolveCWD(stepCWD, defaultCWD, repoRoot string) string {
+	cwd := stepCWD
+	if cwd == "" {
+		cwd = defaultCWD
+	}
+	cwd = ExpandPath(cwd)
+
+	if !filepath.IsAbs(cwd) && repoRoot != "" {
+		cwd = filepath.Join(ExpandPath(repoRoot), cwd)
+	}
+
+	return cwd
+}
+
+// ValidateCWD checks that dir exists and is a directory.
+// An empty dir is valid and means the current directory.
+func ValidateCWD(dir string) error {
+	if dir == "" {
+		return nil
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return &CWDError{Path: dir, Err: err}
+	}
+	if !info.IsDir() {
+		return &CWDError{Path: dir, Err: fmt.Errorf("not a directory")}
+	}
+
+	return nil
+}
+
+// NearestExistingDir returns the closest ancestor of dir that exists,
+// falling back to the current directory.
+func NearestExistingDir(dir string) string {
+	for dir != "" {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	if wd, err := os.Getwd(); err == nil {
+		return wd
+	}
+	return "."
+}
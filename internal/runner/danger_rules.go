@@ -0,0 +1,161 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DangerRulesFile is the repo-relative path of the repository's danger
+// rule pack: patterns the team has flagged as dangerous, checked in
+// addition to the built-in ones.
+const DangerRulesFile = ".svf/danger.yaml"
+
+// DangerRule is a dangerous command pattern from the rule pack.
+type DangerRule struct {
+	Name    string `yaml:"name"`
+	Pattern string `yaml:"pattern"`
+	Risk    string `yaml:"risk,omitempty"`
+}
+
+// dangerRulePack is the layout of DangerRulesFile.
+type dangerRulePack struct {
+	Rules []DangerRule `yaml:"rules"`
+}
+
+// Validate checks that the rule has a name and a valid pattern.
+func (r DangerRule) Validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return errors.New("danger rule name is required")
+	}
+	if strings.TrimSpace(r.Pattern) == "" {
+		return errors.New("danger rule pattern is required")
+	}
+	if _, err := regexp.Compile(r.Pattern); err != nil {
+		return fmt.Errorf("invalid danger rule pattern %q: %w", r.Pattern, err)
+	}
+	return nil
+}
+
+// LoadDangerRules reads the rule pack of the repository at repoPath. A
+// repository without one has no rules.
+func LoadDangerRules(repoPath string) ([]DangerRule, error) {
+	data, err := os.ReadFile(filepath.Join(repoPath, DangerRulesFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var pack dangerRulePack
+	if err := yaml.Unmarshal(data, &pack); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", DangerRulesFile, err)
+	}
+	for _, r := range pack.Rules {
+		if err := r.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", DangerRulesFile, err)
+		}
+	}
+	return pack.Rules, nil
+}
+
+// AddDangerRule appends a rule to the rule pack of the repository at
+// repoPath, creating the file if needed. A rule with the same pattern as
+// an existing one is an error.
+func AddDangerRule(repoPath string, rule DangerRule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	rules, err := LoadDangerRules(repoPath)
+	if err != nil {
+		return err
+	}
+	for _, r := range rules {
+		if r.Pattern == rule.Pattern {
+			return fmt.Errorf("pattern %q is already a danger rule (%s)", rule.Pattern, r.Name)
+		}
+	}
+
+	data, err := yaml.Marshal(dangerRulePack{Rules: append(rules, rule)})
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(repoPath, DangerRulesFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// DerivePattern suggests a danger rule pattern for a command: its leading
+// words, up to the first flag, placeholder, quote or shell operator, and
+// at most three of them, so that "kubectl delete namespace <env> --wait"
+// gives `\bkubectl\s+delete\s+namespace\b`. Commands with no such words
+// are matched literally.
+func DerivePattern(command string) string {
+	var words []string
+	for _, w := range strings.Fields(command) {
+		if len(words) == 3 || strings.ContainsAny(w, "<>\"'`$|&;()=") || strings.HasPrefix(w, "-") {
+			break
+		}
+		words = append(words, regexp.QuoteMeta(w))
+	}
+	if len(words) == 0 {
+		return regexp.QuoteMeta(strings.TrimSpace(command))
+	}
+	return `\b` + strings.Join(words, `\s+`) + `\b`
+}
+
+// compiledRule is a rule pack rule with its pattern compiled.
+type compiledRule struct {
+	rule    DangerRule
+	pattern *regexp.Regexp
+}
+
+// AddRules makes the checker also warn about commands matching the rules,
+// as loaded by LoadDangerRules. Rules are checked after the built-in
+// patterns.
+func (dc *DangerChecker) AddRules(rules []DangerRule) error {
+	for _, r := range rules {
+		if err := r.Validate(); err != nil {
+			return err
+		}
+		dc.rules = append(dc.rules, compiledRule{rule: r, pattern: regexp.MustCompile(r.Pattern)})
+	}
+	return nil
+}
+
+// LoadRules adds the rule pack of the repository at repoPath with
+// AddRules.
+func (dc *DangerChecker) LoadRules(repoPath string) error {
+	rules, err := LoadDangerRules(repoPath)
+	if err != nil {
+		return err
+	}
+	return dc.AddRules(rules)
+}
+
+// checkRules checks a command against the rules added with AddRules.
+func (dc *DangerChecker) checkRules(command string) *DangerInfo {
+	cmd := strings.TrimSpace(command)
+	for _, r := range dc.rules {
+		if r.pattern.MatchString(cmd) {
+			risk := r.rule.Risk
+			if risk == "" {
+				risk = "Flagged as dangerous by your team"
+			}
+			return &DangerInfo{
+				Name:    r.rule.Name,
+				Risk:    risk,
+				Pattern: r.rule.Pattern,
+				Command: cmd,
+			}
+		}
+	}
+	return nil
+}
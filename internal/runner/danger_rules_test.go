@@ -0,0 +1,75 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDerivePattern(t *testing.T) {
+	tests := []struct {
+		command string
+		want    string
+	}{
+		{"kubectl delete namespace <env> --wait", `\bkubectl\s+delete\s+namespace\b`},
+		{"terraform destroy -auto-approve", `\bterraform\s+destroy\b`},
+		{"redis-cli FLUSHALL", `\bredis-cli\s+FLUSHALL\b`},
+		{"psql -c 'DROP TABLE users'", `\bpsql\b`},
+		{"./scripts/wipe.sh all data now", `\b\./scripts/wipe\.sh\s+all\s+data\b`},
+		{"--force", `--force`},
+	}
+	for _, tt := range tests {
+		got := DerivePattern(tt.command)
+		if got != tt.want {
+			t.Errorf("DerivePattern(%q) = %q, want %q", tt.command, got, tt.want)
+		}
+	}
+}
+
+func TestDangerRules(t *testing.T) {
+	repo := t.TempDir()
+
+	rules, err := LoadDangerRules(repo)
+	if err != nil || rules != nil {
+		t.Fatalf("expected no rules without a rule pack, got %v, %v", rules, err)
+	}
+
+	rule := DangerRule{Name: "Namespace deletion", Pattern: DerivePattern("kubectl delete namespace prod")}
+	if err := AddDangerRule(repo, rule); err != nil {
+		t.Fatalf("AddDangerRule() error = %v", err)
+	}
+	if err := AddDangerRule(repo, rule); err == nil || !strings.Contains(err.Error(), "already a danger rule") {
+		t.Errorf("expected a duplicate pattern error, got %v", err)
+	}
+	if err := AddDangerRule(repo, DangerRule{Name: "Broken", Pattern: "("}); err == nil {
+		t.Error("expected an invalid pattern to be refused")
+	}
+
+	checker := NewDangerChecker(true)
+	if err := checker.LoadRules(repo); err != nil {
+		t.Fatalf("LoadRules() error = %v", err)
+	}
+	danger := checker.Check("kubectl  delete namespace staging")
+	if danger == nil || danger.Name != "Namespace deletion" || danger.Risk == "" {
+		t.Fatalf("expected the rule to match, got %+v", danger)
+	}
+	if checker.Check("kubectl get namespaces") != nil {
+		t.Error("expected no match for a harmless command")
+	}
+	// Built-in patterns still apply
+	if danger := checker.Check("git push --force"); danger == nil || danger.Name != "Force git push" {
+		t.Errorf("expected the built-in pattern to match, got %+v", danger)
+	}
+	if NewDangerChecker(false).Check("kubectl delete namespace prod") != nil {
+		t.Error("a disabled checker must not warn")
+	}
+
+	// A broken rule pack is reported
+	if err := os.WriteFile(filepath.Join(repo, DangerRulesFile), []byte("rules:\n  - name: x\n    pattern: \"(\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadDangerRules(repo); err == nil {
+		t.Error("expected an error for an invalid rule pack")
+	}
+}
@@ -128,6 +128,7 @@ func (d *DangerInfo) IsDangerous() bool {
 // DangerChecker provides dangerous command checking.
 type DangerChecker struct {
 	enabled bool
+	rules   []compiledRule
 }
 
 // NewDangerChecker creates a new danger checker.
@@ -140,7 +141,10 @@ func (dc *DangerChecker) Check(command string) *DangerInfo {
 	if !dc.enabled {
 		return nil
 	}
-	return CheckDangerous(command)
+	if danger := CheckDangerous(command); danger != nil {
+		return danger
+	}
+	return dc.checkRules(command)
 }
 
 // ShouldWarn returns true if warnings are enabled.
@@ -0,0 +1,72 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// KubeState is the active kubectl context and namespace.
+type KubeState struct {
+	Context   string
+	Namespace string
+}
+
+// CurrentKubeState asks kubectl for the active context and its namespace.
+// A context without a namespace uses "default", as kubectl does.
+func CurrentKubeState(ctx context.Context) (KubeState, error) {
+	kubeContext, err := commandOutput(ctx, "kubectl", "config", "current-context")
+	if err != nil {
+		return KubeState{}, err
+	}
+	namespace, err := commandOutput(ctx, "kubectl", "config", "view", "--minify", "-o", "jsonpath={..namespace}")
+	if err != nil {
+		return KubeState{}, err
+	}
+	if namespace == "" {
+		namespace = "default"
+	}
+	return KubeState{Context: kubeContext, Namespace: namespace}, nil
+}
+
+// commandOutput runs a CLI tool and returns its trimmed output, using the
+// tool's error message when it fails.
+func commandOutput(ctx context.Context, name string, args ...string) (string, error) {
+	out, err := exec.CommandContext(ctx, name, args...).Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			if msg := strings.TrimSpace(string(exitErr.Stderr)); msg != "" {
+				return "", fmt.Errorf("%s: %s", name, msg)
+			}
+		}
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// KubeMismatches compares a workflow's kube_context and kube_namespace
+// with the active state, returning one message per field that doesn't
+// match. Both fields accept glob patterns such as "prod-*".
+func KubeMismatches(wf *workflows.Workflow, state KubeState) []string {
+	var mismatches []string
+	if wf.KubeContext != "" && !kubeMatch(wf.KubeContext, state.Context) {
+		mismatches = append(mismatches, fmt.Sprintf("kubectl context is %q, workflow expects %q", state.Context, wf.KubeContext))
+	}
+	if wf.KubeNamespace != "" && !kubeMatch(wf.KubeNamespace, state.Namespace) {
+		mismatches = append(mismatches, fmt.Sprintf("kubectl namespace is %q, workflow expects %q", state.Namespace, wf.KubeNamespace))
+	}
+	return mismatches
+}
+
+// kubeMatch reports whether value matches pattern. Invalid patterns are
+// rejected by workflow validation, so they only match literally here.
+func kubeMatch(pattern, value string) bool {
+	if ok, err := path.Match(pattern, value); err == nil {
+		return ok
+	}
+	return pattern == value
+}
@@ -0,0 +1,44 @@
+package runner
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+func TestKubeMismatches(t *testing.T) {
+	state := KubeState{Context: "prod-eu", Namespace: "payments"}
+
+	tests := []struct {
+		name      string
+		context   string
+		namespace string
+		want      []string
+	}{
+		{name: "no guardrails"},
+		{name: "exact match", context: "prod-eu", namespace: "payments"},
+		{name: "pattern match", context: "prod-*"},
+		{
+			name:    "wrong context",
+			context: "staging",
+			want:    []string{`kubectl context is "prod-eu", workflow expects "staging"`},
+		},
+		{
+			name:      "wrong context and namespace",
+			context:   "staging-*",
+			namespace: "default",
+			want: []string{
+				`kubectl context is "prod-eu", workflow expects "staging-*"`,
+				`kubectl namespace is "payments", workflow expects "default"`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		wf := &workflows.Workflow{KubeContext: tt.context, KubeNamespace: tt.namespace}
+		got := KubeMismatches(wf, state)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: KubeMismatches() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+// openPTY opens a pseudo-terminal, returning its master and slave ends.
+func openPTY(t *testing.T) (master, slave *os.File) {
+	t.Helper()
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		t.Skipf("no pseudo-terminals: %v", err)
+	}
+	t.Cleanup(func() { master.Close() })
+
+	var unlock, n int32
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, master.Fd(), syscall.TIOCSPTLCK, uintptr(unsafe.Pointer(&unlock))); errno != 0 {
+		t.Skipf("unlocking pseudo-terminal: %v", errno)
+	}
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, master.Fd(), syscall.TIOCGPTN, uintptr(unsafe.Pointer(&n))); errno != 0 {
+		t.Skipf("naming pseudo-terminal: %v", errno)
+	}
+	slave, err = os.OpenFile(fmt.Sprintf("/dev/pts/%d", n), os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		t.Skipf("opening pseudo-terminal: %v", err)
+	}
+	return master, slave
+}
+
+// TestExecTerminalStep runs a step that prompts on the terminal, like sudo,
+// from a process in the foreground of a terminal, as svf usually is.
+func TestExecTerminalStep(t *testing.T) {
+	if os.Getenv("SVF_TEST_TERMINAL_STEP") == "1" {
+		result := Exec(context.Background(), ExecConfig{
+			Command: "read -r line < /dev/tty && echo got $line",
+			Shell:   "bash",
+		})
+		fmt.Print(result.Output)
+		os.Exit(0)
+	}
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash not available")
+	}
+
+	master, slave := openPTY(t)
+	cmd := exec.Command(os.Args[0], "-test.run=^TestExecTerminalStep$")
+	cmd.Env = append(os.Environ(), "SVF_TEST_TERMINAL_STEP=1")
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = slave, slave, slave
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true, Setctty: true, Ctty: 0}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	slave.Close()
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+
+	if _, err := master.WriteString("hello\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	output := make(chan string)
+	go func() {
+		var out strings.Builder
+		buf := make([]byte, 256)
+		for {
+			n, err := master.Read(buf)
+			out.Write(buf[:n])
+			if strings.Contains(out.String(), "got hello") || err != nil {
+				output <- out.String()
+				return
+			}
+		}
+	}()
+
+	select {
+	case out := <-output:
+		if !strings.Contains(out, "got hello") {
+			t.Errorf("step output = %q, want it to read the terminal", out)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("step reading the terminal hung")
+	}
+}
@@ -0,0 +1,68 @@
+//go:build !windows
+
+package runner
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// onForegroundTerminal reports whether svf runs in the foreground process
+// group of its controlling terminal. Tests replace it.
+var onForegroundTerminal = foregroundTerminal
+
+// setProcessGroup starts the command in a new process group, so signals
+// sent to the group reach everything the step spawned. It stays in svf's
+// session, keeping the terminal as its controlling terminal.
+//
+// When svf runs in the foreground of a terminal the command stays in svf's
+// group instead: a background group is stopped by SIGTTIN as soon as it
+// reads the terminal, as sudo, ssh and gpg do to prompt for passwords.
+// Interrupts from the terminal then reach the command and its children
+// directly.
+func setProcessGroup(cmd *exec.Cmd) {
+	if onForegroundTerminal() {
+		return
+	}
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// terminateProcessGroup sends SIGTERM to the command's process group and
+// SIGKILL after killGracePeriod if anything in it is still running. A
+// command left in svf's group is signalled on its own.
+func terminateProcessGroup(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	target := cmd.Process.Pid
+	if cmd.SysProcAttr != nil && cmd.SysProcAttr.Setpgid {
+		target = -target
+	}
+
+	err := syscall.Kill(target, syscall.SIGTERM)
+	if errors.Is(err, syscall.ESRCH) {
+		return nil
+	}
+	time.AfterFunc(killGracePeriod, func() {
+		_ = syscall.Kill(target, syscall.SIGKILL)
+	})
+	return err
+}
+
+// foregroundTerminal reports whether svf has a controlling terminal and
+// its process group is the terminal's foreground group.
+func foregroundTerminal() bool {
+	tty, err := os.Open("/dev/tty")
+	if err != nil {
+		return false
+	}
+	defer tty.Close()
+
+	var pgrp int32
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, tty.Fd(), syscall.TIOCGPGRP, uintptr(unsafe.Pointer(&pgrp)))
+	return errno == 0 && int(pgrp) == syscall.Getpgrp()
+}
@@ -0,0 +1,132 @@
+//go:build !windows
+
+package runner
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// waitForFile waits for a file to be written and returns its content.
+func waitForFile(t *testing.T, path string) string {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if data, err := os.ReadFile(path); err == nil && strings.HasSuffix(string(data), "\n") {
+			return strings.TrimSpace(string(data))
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %s", path)
+	return ""
+}
+
+// processGone reports whether pid exits within a few seconds.
+func processGone(pid int) bool {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if err := syscall.Kill(pid, 0); err != nil {
+			return true
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	return false
+}
+
+// outsideTerminal makes steps run in their own process group, as they do
+// when svf isn't in the foreground of a terminal.
+func outsideTerminal(t *testing.T) {
+	t.Helper()
+	orig := onForegroundTerminal
+	onForegroundTerminal = func() bool { return false }
+	t.Cleanup(func() { onForegroundTerminal = orig })
+}
+
+func TestExecCancelKillsProcessGroup(t *testing.T) {
+	outsideTerminal(t)
+	for _, stream := range []bool{false, true} {
+		t.Run("stream="+strconv.FormatBool(stream), func(t *testing.T) {
+			pidFile := filepath.Join(t.TempDir(), "pid")
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			done := make(chan ExecResult, 1)
+			go func() {
+				done <- Exec(ctx, ExecConfig{
+					Command: "sleep 30 & echo $! > " + pidFile + "; wait",
+					Shell:   "bash",
+					Stream:  stream,
+				})
+			}()
+
+			pid, err := strconv.Atoi(waitForFile(t, pidFile))
+			if err != nil {
+				t.Fatalf("bad pid: %v", err)
+			}
+			cancel()
+
+			select {
+			case result := <-done:
+				if result.Success || result.ExitCode != 13 {
+					t.Errorf("expected canceled exit code 13, got %d (success=%v)", result.ExitCode, result.Success)
+				}
+			case <-time.After(10 * time.Second):
+				t.Fatal("Exec did not return after cancel")
+			}
+
+			if !processGone(pid) {
+				_ = syscall.Kill(pid, syscall.SIGKILL)
+				t.Errorf("child process %d survived cancellation", pid)
+			}
+		})
+	}
+}
+
+func TestExecKeepsSession(t *testing.T) {
+	outsideTerminal(t)
+	if _, err := exec.LookPath("ps"); err != nil {
+		t.Skip("ps not available")
+	}
+	out, err := exec.Command("ps", "-o", "sid=", "-p", strconv.Itoa(os.Getpid())).Output()
+	if err != nil {
+		t.Skipf("ps failed: %v", err)
+	}
+
+	result := Exec(context.Background(), ExecConfig{Command: "ps -o sid=,pgid= -p $$", Shell: "bash"})
+	fields := strings.Fields(result.Output)
+	if !result.Success || len(fields) != 2 {
+		t.Fatalf("Exec() = %+v", result)
+	}
+	if fields[0] != strings.TrimSpace(string(out)) {
+		t.Errorf("step session = %s, want svf's session %s", fields[0], strings.TrimSpace(string(out)))
+	}
+	if fields[1] == strconv.Itoa(syscall.Getpgrp()) {
+		t.Errorf("step runs in svf's process group %s, want its own", fields[1])
+	}
+}
+
+func TestExecTimeout(t *testing.T) {
+	start := time.Now()
+	result := Exec(context.Background(), ExecConfig{
+		Command: "sleep 30",
+		Shell:   "bash",
+		Timeout: 200 * time.Millisecond,
+	})
+
+	if result.Success || result.ExitCode != 124 {
+		t.Errorf("expected timeout exit code 124, got %d (success=%v)", result.ExitCode, result.Success)
+	}
+	if result.Error == nil || !strings.Contains(result.Error.Error(), "timed out after 200ms") {
+		t.Errorf("expected timeout error, got %v", result.Error)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("timeout took %s", elapsed)
+	}
+}
@@ -0,0 +1,123 @@
+package runner
+
+import (
+	"strings"
+
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// LookPathFunc finds a command on the PATH, like exec.LookPath.
+type LookPathFunc func(file string) (string, error)
+
+// MissingRequirements returns the requirements whose commands aren't on
+// the PATH.
+func MissingRequirements(reqs []workflows.Requirement, lookPath LookPathFunc) []workflows.Requirement {
+	var missing []workflows.Requirement
+	for _, req := range reqs {
+		if _, err := lookPath(req.Command); err != nil {
+			missing = append(missing, req)
+		}
+	}
+	return missing
+}
+
+// InstallInstructions returns a command per install hint of req, for
+// people to copy.
+func InstallInstructions(req workflows.Requirement) []string {
+	var cmds []string
+	if req.Brew != "" {
+		cmds = append(cmds, "brew install "+req.Brew)
+	}
+	if req.Apt != "" {
+		cmds = append(cmds, "sudo apt-get install -y "+req.Apt)
+	}
+	return cmds
+}
+
+// InstallCommand returns the command that installs req with a package
+// manager found on the PATH, trying Homebrew before apt. It reports false
+// if no hinted package manager is installed.
+func InstallCommand(req workflows.Requirement, lookPath LookPathFunc) (string, bool) {
+	if req.Brew != "" {
+		if _, err := lookPath("brew"); err == nil {
+			return "brew install " + req.Brew, true
+		}
+	}
+	if req.Apt != "" {
+		if _, err := lookPath("apt-get"); err == nil {
+			// Containers often run as root without sudo
+			if _, err := lookPath("sudo"); err != nil {
+				return "apt-get install -y " + req.Apt, true
+			}
+			return "sudo apt-get install -y " + req.Apt, true
+		}
+	}
+	return "", false
+}
+
+// shellWords are builtins and keywords that start a command without
+// naming a program on the PATH.
+var shellWords = map[string]bool{
+	".": true, ":": true, "[": true, "[[": true, "alias": true, "break": true,
+	"case": true, "cd": true, "continue": true, "do": true, "done": true,
+	"echo": true, "elif": true, "else": true, "esac": true, "eval": true,
+	"exit": true, "export": true, "false": true, "fi": true, "for": true,
+	"function": true, "if": true, "local": true, "printf": true, "pwd": true,
+	"read": true, "return": true, "set": true, "shift": true, "source": true,
+	"test": true, "then": true, "trap": true, "true": true, "unset": true,
+	"until": true, "wait": true, "while": true, "{": true, "}": true,
+}
+
+// commandWrappers run the command that follows them.
+var commandWrappers = map[string]bool{
+	"sudo": true, "env": true, "time": true, "nohup": true, "exec": true,
+	"command": true, "xargs": true, "watch": true,
+}
+
+// CommandPrograms returns the programs a shell command runs: the first
+// word of each command in pipelines, lists and substitutions, after
+// variable assignments and wrappers such as sudo. Builtins, relative paths
+// and words with placeholders or expansions are left out, so the result
+// is what must be on the PATH. Quoting isn't parsed, so an operator inside
+// quotes can add a spurious program.
+func CommandPrograms(command string) []string {
+	separators := strings.NewReplacer("\\\n", " ", ">&", " ", "&>", " ", "&&", "\n", "||", "\n", "|", "\n", ";", "\n",
+		"&", "\n", "$(", "\n", "(", "\n", ")", "\n", "`", "\n")
+	seen := make(map[string]bool)
+	var programs []string
+	for _, part := range strings.Split(separators.Replace(command), "\n") {
+		wrapped := false
+		for _, word := range strings.Fields(part) {
+			if isAssignment(word) || commandWrappers[word] {
+				wrapped = wrapped || commandWrappers[word]
+				continue
+			}
+			// With options, which word is the wrapped program depends on
+			// the wrapper: sudo -u root systemctl
+			if wrapped && strings.HasPrefix(word, "-") {
+				break
+			}
+			if !shellWords[word] && !seen[word] && !strings.ContainsAny(word, "<>{}$\"'=*?") &&
+				(!strings.Contains(word, "/") || strings.HasPrefix(word, "/")) {
+				seen[word] = true
+				programs = append(programs, word)
+			}
+			break
+		}
+	}
+	return programs
+}
+
+// isAssignment reports whether word is a variable assignment like FOO=bar.
+func isAssignment(word string) bool {
+	name, _, ok := strings.Cut(word, "=")
+	if !ok || name == "" {
+		return false
+	}
+	for i, r := range name {
+		if r != '_' && (r < 'A' || r > 'Z') && (r < 'a' || r > 'z') && (i == 0 || r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
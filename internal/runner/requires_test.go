@@ -0,0 +1,75 @@
+package runner
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/stretchr/testify/assert"
+)
+
+// pathWith returns a LookPathFunc finding only the given commands.
+func pathWith(commands ...string) LookPathFunc {
+	return func(file string) (string, error) {
+		for _, c := range commands {
+			if c == file {
+				return "/usr/bin/" + c, nil
+			}
+		}
+		return "", errors.New("not found")
+	}
+}
+
+func TestMissingRequirements(t *testing.T) {
+	reqs := []workflows.Requirement{{Command: "git"}, {Command: "jq"}}
+	assert.Equal(t, []workflows.Requirement{{Command: "jq"}}, MissingRequirements(reqs, pathWith("git")))
+	assert.Empty(t, MissingRequirements(reqs, pathWith("git", "jq")))
+}
+
+func TestInstallCommand(t *testing.T) {
+	jq := workflows.Requirement{Command: "jq", Brew: "jq", Apt: "jq"}
+
+	tests := []struct {
+		name string
+		req  workflows.Requirement
+		path LookPathFunc
+		want string
+	}{
+		{"brew first", jq, pathWith("brew", "apt-get", "sudo"), "brew install jq"},
+		{"apt with sudo", jq, pathWith("apt-get", "sudo"), "sudo apt-get install -y jq"},
+		{"apt as root", jq, pathWith("apt-get"), "apt-get install -y jq"},
+		{"no brew hint", workflows.Requirement{Command: "jq", Apt: "jq"}, pathWith("brew"), ""},
+		{"no package manager", jq, pathWith(), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := InstallCommand(tt.req, tt.path)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.want != "", ok)
+		})
+	}
+
+	assert.Equal(t, []string{"brew install jq", "sudo apt-get install -y jq"}, InstallInstructions(jq))
+}
+
+func TestCommandPrograms(t *testing.T) {
+	tests := []struct {
+		command string
+		want    []string
+	}{
+		{"kubectl get pods -n prod", []string{"kubectl"}},
+		{"kubectl get pods | grep Running && echo done", []string{"kubectl", "grep"}},
+		{"AWS_PROFILE=prod sudo terraform apply", []string{"terraform"}},
+		{"sudo -u root systemctl restart nginx", nil},
+		{"cd /srv && ./deploy.sh; /usr/local/bin/helm list", []string{"/usr/local/bin/helm"}},
+		{"export TAG=$(git rev-parse HEAD)", []string{"git"}},
+		{"make build 2>&1 | tee build.log", []string{"make", "tee"}},
+		{"docker run \\\n  -v <dir>:/data image", []string{"docker"}},
+		{"<tool> --version; $EDITOR file", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			assert.Equal(t, tt.want, CommandPrograms(tt.command))
+		})
+	}
+}
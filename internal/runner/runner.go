@@ -4,7 +4,7 @@ package runner
 import (
 	"context"
 	"fmt"
-	"path/filepath"
+	"runtime"
 	"time"
 
 	"github.com/chazuruo/svf/internal/placeholders"
@@ -36,14 +36,29 @@ type RunResult struct {
 
 // StepResult contains the result of a single step.
 type StepResult struct {
-	Step     int // Step index
-	Success  bool
-	Skipped  bool
-	Canceled bool
-	ExitCode int
-	Output   string
-	Duration time.Duration
-	Error    error
+	Step       int // Step index
+	Success    bool
+	Skipped    bool
+	SkipReason string // Why the step was skipped, e.g. SkipPlatform; empty if the user skipped it
+	Canceled   bool
+	ExitCode   int
+	Output     string
+	Duration   time.Duration
+	Error      error
+	Captured   map[string]string // Values extracted by the step's captures
+
+	StartedAt    time.Time
+	Confirmation string // How a dangerous command was confirmed, e.g. ConfirmedByOperator
+}
+
+// SkipPlatform is the SkipReason of steps whose platforms don't include
+// the OS and architecture svf runs on.
+const SkipPlatform = "platform"
+
+// PlatformSkip returns the result of step i when it is skipped because it
+// doesn't run on this platform.
+func PlatformSkip(i int) StepResult {
+	return StepResult{Step: i, Success: true, Skipped: true, SkipReason: SkipPlatform}
 }
 
 // runner implements Runner.
@@ -55,6 +70,7 @@ type runner struct {
 	cwd           string
 	dangerChecker *DangerChecker
 	autoConfirm   *bool
+	goos, goarch  string
 }
 
 // NewRunner creates a new runner.
@@ -63,6 +79,8 @@ func NewRunner(opts ...Option) Runner {
 		shell:        "bash",
 		streamOutput: boolPtr(true),
 		confirmEach:  boolPtr(false),
+		goos:         runtime.GOOS,
+		goarch:       runtime.GOARCH,
 	}
 
 	for _, opt := range opts {
@@ -140,6 +158,15 @@ func WithAutoConfirm(autoConfirm bool) Option {
 	}
 }
 
+// WithPlatform sets the OS and architecture steps are matched against
+// (default: runtime.GOOS and runtime.GOARCH).
+func WithPlatform(goos, goarch string) Option {
+	return func(r *runner) {
+		r.goos = goos
+		r.goarch = goarch
+	}
+}
+
 // Run executes a workflow plan.
 func (r *runner) Run(ctx context.Context, plan Plan, sink OutputSink) (RunResult, error) {
 	startTime := time.Now()
@@ -152,10 +179,22 @@ func (r *runner) Run(ctx context.Context, plan Plan, sink OutputSink) (RunResult
 		plan.Workflow.ApplyDefaults(&plan.Workflow.Steps[i])
 	}
 
+	// Captured values are added for later steps without changing the plan
+	params := make(map[string]string, len(plan.Parameters))
+	for k, v := range plan.Parameters {
+		params[k] = v
+	}
+
 	// Execute each step
 	for i, step := range plan.Workflow.Steps {
+		// Steps limited to other platforms are skipped, not failed
+		if !step.RunsOn(r.goos, r.goarch) {
+			result.StepResults[i] = PlatformSkip(i)
+			continue
+		}
+
 		// Substitute placeholders in command using placeholders package
-		cmd, err := placeholders.Substitute(step.Command, plan.Parameters)
+		cmd, err := placeholders.Substitute(step.Command, params)
 		if err != nil {
 			// Check if we have any placeholders at all
 			phNames := placeholders.CollectFromSteps(plan.Workflow.Steps)
@@ -177,13 +216,16 @@ func (r *runner) Run(ctx context.Context, plan Plan, sink OutputSink) (RunResult
 		modifiedStep.Command = cmd
 
 		// Resolve working directory
-		cwd := step.CWD
-		if cwd == "" && plan.Workflow.Defaults.CWD != "" {
-			cwd = plan.Workflow.Defaults.CWD
-		}
-		if !filepath.IsAbs(cwd) && plan.RepoRoot != "" {
-			cwd = filepath.Join(plan.RepoRoot, cwd)
+		cwd := ResolveCWD(step.CWD, plan.Workflow.Defaults.CWD, plan.RepoRoot)
+		if err := ValidateCWD(cwd); err != nil {
+			result.StepResults[i] = StepResult{Step: i, ExitCode: 1, Error: err}
+			result.Success = false
+			result.FailedStep = i
+			result.ExitCode = 1
+			result.Duration = time.Since(startTime)
+			return result, fmt.Errorf("step %d: %w", i, err)
 		}
+		modifiedStep.CWD = cwd
 
 		// Configure executor with step-specific settings
 		executor := r.executor
@@ -205,9 +247,12 @@ func (r *runner) Run(ctx context.Context, plan Plan, sink OutputSink) (RunResult
 		}
 
 		// Execute step
-		stepResult := executor.ExecStep(ctx, &modifiedStep, plan.Parameters, plan.RepoRoot, sink)
+		stepResult := executor.ExecStep(ctx, &modifiedStep, params, plan.RepoRoot, sink)
 		stepResult.Step = i
 		result.StepResults[i] = stepResult
+		for k, v := range stepResult.Captured {
+			params[k] = v
+		}
 
 		// Check if step was canceled
 		if stepResult.Canceled {
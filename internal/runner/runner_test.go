@@ -3,9 +3,13 @@ package runner
 
 import (
 	"context"
+	"errors"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/chazuruo/svf/internal/workflows"
 )
@@ -87,6 +91,98 @@ func TestExecWithCWD(t *testing.T) {
 	}
 }
 
+func TestExecWithMissingCWD(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "does-not-exist")
+
+	result := Exec(context.Background(), ExecConfig{
+		Command: "pwd",
+		Shell:   "bash",
+		CWD:     missing,
+	})
+
+	if result.Success {
+		t.Fatal("expected failure for missing working directory")
+	}
+
+	var cwdErr *CWDError
+	if !errors.As(result.Error, &cwdErr) {
+		t.Fatalf("expected CWDError, got: %v", result.Error)
+	}
+	if cwdErr.Path != missing {
+		t.Errorf("expected path %s, got %s", missing, cwdErr.Path)
+	}
+	if !strings.Contains(result.Error.Error(), "does not exist") {
+		t.Errorf("expected clear error message, got: %v", result.Error)
+	}
+}
+
+func TestExpandPath(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	t.Setenv("SVF_TEST_DIR", "/tmp/svf")
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"", ""},
+		{"~", home},
+		{"~/src", filepath.Join(home, "src")},
+		{"$SVF_TEST_DIR/app", "/tmp/svf/app"},
+		{"${SVF_TEST_DIR}/app", "/tmp/svf/app"},
+		{"relative/dir", "relative/dir"},
+		{"/abs/~user", "/abs/~user"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := ExpandPath(tt.input); got != tt.expected {
+				t.Errorf("ExpandPath(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestResolveCWD(t *testing.T) {
+	t.Setenv("SVF_TEST_DIR", "/tmp/svf")
+
+	tests := []struct {
+		name       string
+		stepCWD    string
+		defaultCWD string
+		repoRoot   string
+		expected   string
+	}{
+		{"empty", "", "", "", ""},
+		{"repo root only", "", "", "/repo", "/repo"},
+		{"step relative", "sub", "other", "/repo", "/repo/sub"},
+		{"default relative", "", "other", "/repo", "/repo/other"},
+		{"step absolute", "/abs", "", "/repo", "/abs"},
+		{"env var", "$SVF_TEST_DIR", "", "/repo", "/tmp/svf"},
+		{"env var in default", "", "${SVF_TEST_DIR}/x", "/repo", "/tmp/svf/x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ResolveCWD(tt.stepCWD, tt.defaultCWD, tt.repoRoot)
+			if got != tt.expected {
+				t.Errorf("ResolveCWD() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNearestExistingDir(t *testing.T) {
+	tempDir := t.TempDir()
+
+	got := NearestExistingDir(filepath.Join(tempDir, "a", "b", "c"))
+	if got != tempDir {
+		t.Errorf("expected %s, got %s", tempDir, got)
+	}
+}
+
 func TestExecWithEnv(t *testing.T) {
 	ctx := context.Background()
 
@@ -173,6 +269,51 @@ func TestExecWithContextCancellation(t *testing.T) {
 	}
 }
 
+func TestExecOverBudget(t *testing.T) {
+	var calls atomic.Int32
+	config := ExecConfig{
+		Command:      "sleep 0.3",
+		Shell:        "bash",
+		MaxDuration:  50 * time.Millisecond,
+		OnOverBudget: func() { calls.Add(1) },
+	}
+	if result := Exec(context.Background(), config); !result.Success {
+		t.Fatalf("Exec() failed: %v", result.Error)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("OnOverBudget called %d times, want 1", got)
+	}
+
+	// A command finishing within its budget isn't reported
+	config.Command = "true"
+	config.MaxDuration = time.Minute
+	Exec(context.Background(), config)
+	time.Sleep(10 * time.Millisecond)
+	if got := calls.Load(); got != 1 {
+		t.Errorf("OnOverBudget called for a command within its budget")
+	}
+}
+
+func TestRunBudgetHook(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "event")
+	event := BudgetEvent{Workflow: "Deploy", Step: 2, StepName: "migrate", MaxDuration: 30 * time.Second}
+	hook := `echo "$SVF_WORKFLOW|$SVF_STEP|$SVF_STEP_NAME|$SVF_MAX_DURATION" > ` + out
+	if err := RunBudgetHook(context.Background(), hook, event); err != nil {
+		t.Fatalf("RunBudgetHook() error = %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(string(data)); got != "Deploy|2|migrate|30s" {
+		t.Errorf("hook saw %q, want %q", got, "Deploy|2|migrate|30s")
+	}
+
+	if err := RunBudgetHook(context.Background(), "exit 3", event); err == nil {
+		t.Error("RunBudgetHook() expected an error from a failing hook")
+	}
+}
+
 func TestSubstitute(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -433,6 +574,112 @@ func TestRunnerRun(t *testing.T) {
 			t.Errorf("expected 2 step results, got %d", len(result.StepResults))
 		}
 	})
+
+	t.Run("captured values", func(t *testing.T) {
+		wf := &workflows.Workflow{
+			Title: "Test Workflow",
+			Steps: []workflows.Step{
+				{
+					Command: "echo 'warning' >&2; echo 'release v1.4.2'",
+					Capture: map[string]workflows.Extractor{"version": {Regex: `v(\S+)`}},
+				},
+				{Command: "test <version> = 1.4.2"},
+			},
+		}
+
+		r := NewRunner(WithStreamOutput(false))
+		plan := Plan{Workflow: wf, Parameters: map[string]string{}}
+
+		result, err := r.Run(context.Background(), plan, nil)
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected workflow to succeed, got: %+v", result)
+		}
+		if got := result.StepResults[0].Captured["version"]; got != "1.4.2" {
+			t.Errorf("captured version = %q, want 1.4.2", got)
+		}
+		if _, ok := plan.Parameters["version"]; ok {
+			t.Error("captured values should not change the plan's parameters")
+		}
+	})
+
+	t.Run("failed capture", func(t *testing.T) {
+		wf := &workflows.Workflow{
+			Title: "Test Workflow",
+			Steps: []workflows.Step{
+				{Command: "echo '{}'", Capture: map[string]workflows.Extractor{"name": {JSONPath: ".name"}}},
+				{Command: "echo <name>"},
+			},
+		}
+
+		r := NewRunner(WithStreamOutput(false))
+		result, err := r.Run(context.Background(), Plan{Workflow: wf}, nil)
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if result.Success || result.FailedStep != 0 {
+			t.Errorf("expected failure at step 0, got: %+v", result)
+		}
+		if err := result.StepResults[0].Error; err == nil || !strings.Contains(err.Error(), "capture name") {
+			t.Errorf("expected capture error, got: %v", err)
+		}
+	})
+
+	t.Run("missing working directory", func(t *testing.T) {
+		wf := &workflows.Workflow{
+			Title: "Test Workflow",
+			Steps: []workflows.Step{
+				{Command: "echo step1", CWD: "missing"},
+			},
+		}
+
+		r := NewRunner(WithStreamOutput(false))
+		plan := Plan{
+			Workflow:   wf,
+			Parameters: map[string]string{},
+			RepoRoot:   t.TempDir(),
+		}
+
+		result, err := r.Run(context.Background(), plan, NewStdioSink())
+
+		var cwdErr *CWDError
+		if !errors.As(err, &cwdErr) {
+			t.Fatalf("expected CWDError, got: %v", err)
+		}
+		if result.Success {
+			t.Error("expected workflow to fail")
+		}
+		if result.FailedStep != 0 {
+			t.Errorf("expected failed step 0, got %d", result.FailedStep)
+		}
+	})
+
+	t.Run("other platform", func(t *testing.T) {
+		wf := &workflows.Workflow{
+			Title: "Test Workflow",
+			Steps: []workflows.Step{
+				{Command: "exit 1", Platforms: []string{"windows"}},
+				{Command: "echo step2", Platforms: []string{"linux/arm64", "darwin"}},
+			},
+		}
+
+		r := NewRunner(WithStreamOutput(false), WithPlatform("darwin", "amd64"))
+		result, err := r.Run(context.Background(), Plan{Workflow: wf}, nil)
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected workflow to succeed, got: %+v", result)
+		}
+		if got := result.StepResults[0]; !got.Skipped || got.SkipReason != SkipPlatform {
+			t.Errorf("expected step 0 to be skipped for its platform, got: %+v", got)
+		}
+		if got := result.StepResults[1]; got.Skipped || got.Output != "step2\n" {
+			t.Errorf("expected step 1 to run, got: %+v", got)
+		}
+	})
 }
 
 func TestExecConfig(t *testing.T) {
@@ -504,3 +751,22 @@ func TestExecWithDangerousCommand(t *testing.T) {
 		}
 	})
 }
+
+func TestExecStdout(t *testing.T) {
+	for _, stream := range []bool{true, false} {
+		result := Exec(context.Background(), ExecConfig{
+			Command: "echo out; echo err >&2",
+			Shell:   "bash",
+			Stream:  stream,
+		})
+		if !result.Success {
+			t.Fatalf("stream=%v: expected success, got %+v", stream, result)
+		}
+		if result.Stdout != "out\n" {
+			t.Errorf("stream=%v: Stdout = %q, want %q", stream, result.Stdout, "out\n")
+		}
+		if !strings.Contains(result.Output, "err") {
+			t.Errorf("stream=%v: Output %q should include stderr", stream, result.Output)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// SendTarget is a terminal multiplexer pane that commands are typed into
+// instead of being executed by svf, so they run in the user's existing
+// shell session and environment.
+type SendTarget struct {
+	// Kind is "tmux" or "screen".
+	Kind string
+
+	// Pane is a tmux target pane (e.g. "%3" or "work:1.0"), or a screen
+	// session optionally followed by "/window" (e.g. "work/2").
+	Pane string
+}
+
+// ParseSendTarget parses a target of the form "tmux:<pane>" or
+// "screen:<session>[/<window>]".
+func ParseSendTarget(s string) (SendTarget, error) {
+	kind, pane, ok := strings.Cut(s, ":")
+	if !ok || pane == "" {
+		return SendTarget{}, fmt.Errorf("invalid send target %q: use tmux:<pane> or screen:<session>[/<window>]", s)
+	}
+	switch kind {
+	case "tmux", "screen":
+		return SendTarget{Kind: kind, Pane: pane}, nil
+	default:
+		return SendTarget{}, fmt.Errorf("unsupported send target %q: use tmux or screen", kind)
+	}
+}
+
+// String describes the target, e.g. "tmux pane %3".
+func (t SendTarget) String() string {
+	if t.Kind == "screen" {
+		return "screen session " + t.Pane
+	}
+	return "tmux pane " + t.Pane
+}
+
+// screenStuffEscaper escapes the characters screen's stuff command reads
+// as escapes (\ and ^X control notation), so commands are typed literally.
+var screenStuffEscaper = strings.NewReplacer(`\`, `\\`, "^", `\^`)
+
+// commands returns the multiplexer invocations that type command into the
+// target and press Enter.
+func (t SendTarget) commands(command string) [][]string {
+	if t.Kind == "screen" {
+		args := []string{"screen", "-S"}
+		session, window, ok := strings.Cut(t.Pane, "/")
+		args = append(args, session)
+		if ok {
+			args = append(args, "-p", window)
+		}
+		return [][]string{append(args, "-X", "stuff", screenStuffEscaper.Replace(command)+"\n")}
+	}
+	// -l sends the text literally so words like "Enter" aren't key names
+	return [][]string{
+		{"tmux", "send-keys", "-t", t.Pane, "-l", command},
+		{"tmux", "send-keys", "-t", t.Pane, "Enter"},
+	}
+}
+
+// Send types command into the target and presses Enter. It returns once
+// the multiplexer has accepted the keys, not when the command finishes.
+func (t SendTarget) Send(ctx context.Context, command string) error {
+	for _, args := range t.commands(command) {
+		out, err := exec.CommandContext(ctx, args[0], args[1:]...).CombinedOutput()
+		if err != nil {
+			if msg := strings.TrimSpace(string(out)); msg != "" {
+				return fmt.Errorf("%s: %s", args[0], msg)
+			}
+			return fmt.Errorf("%s: %w", args[0], err)
+		}
+	}
+	return nil
+}
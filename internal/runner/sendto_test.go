@@ -0,0 +1,66 @@
+package runner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSendTarget(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    SendTarget
+		wantErr bool
+	}{
+		{in: "tmux:%3", want: SendTarget{Kind: "tmux", Pane: "%3"}},
+		{in: "tmux:work:1.0", want: SendTarget{Kind: "tmux", Pane: "work:1.0"}},
+		{in: "screen:work/2", want: SendTarget{Kind: "screen", Pane: "work/2"}},
+		{in: "tmux:", wantErr: true},
+		{in: "zellij:1", wantErr: true},
+		{in: "%3", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSendTarget(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSendTarget(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseSendTarget(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSendTarget_Commands(t *testing.T) {
+	tests := []struct {
+		target SendTarget
+		want   [][]string
+	}{
+		{
+			target: SendTarget{Kind: "tmux", Pane: "%3"},
+			want: [][]string{
+				{"tmux", "send-keys", "-t", "%3", "-l", "kubectl get pods"},
+				{"tmux", "send-keys", "-t", "%3", "Enter"},
+			},
+		},
+		{
+			target: SendTarget{Kind: "screen", Pane: "work/2"},
+			want:   [][]string{{"screen", "-S", "work", "-p", "2", "-X", "stuff", "kubectl get pods\n"}},
+		},
+		{
+			target: SendTarget{Kind: "screen", Pane: "work"},
+			want:   [][]string{{"screen", "-S", "work", "-X", "stuff", "kubectl get pods\n"}},
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.target.commands("kubectl get pods"); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s commands() = %q, want %q", tt.target, got, tt.want)
+		}
+	}
+
+	// screen's stuff reads \ and ^ as escapes
+	screen := SendTarget{Kind: "screen", Pane: "work"}
+	want := [][]string{{"screen", "-S", "work", "-X", "stuff", `grep '\^\\d' log` + "\n"}}
+	if got := screen.commands(`grep '^\d' log`); !reflect.DeepEqual(got, want) {
+		t.Errorf("screen commands() = %q, want %q", got, want)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"os/signal"
 	"strings"
 	"sync"
 	"syscall"
@@ -22,26 +23,48 @@ type ExecConfig struct {
 	Stream      bool              // Whether to stream output
 	DangerChecker *DangerChecker  // For dangerous command checking
 	AutoConfirm bool              // Auto-confirm dangerous commands
+	Timeout     time.Duration     // Terminate the command after this long (0 = no limit)
+	MaxDuration time.Duration     // How long the command is expected to take at most (0 = no budget)
+	OnOverBudget func()           // Called once, from another goroutine, when the command outruns MaxDuration
 }
 
+// killGracePeriod is how long a canceled command's process group has to
+// exit after SIGTERM before it is killed.
+const killGracePeriod = 3 * time.Second
+
 // ExecResult contains the result of executing a single command.
 type ExecResult struct {
-	Command    string
-	ExitCode   int
-	Success    bool
-	Output     string
-	Duration   time.Duration
-	Dangerous  bool
-	Danger     *DangerInfo
-	Error      error
+	Command   string
+	ExitCode  int
+	Success   bool
+	Output    string
+	Stdout    string // Standard output alone, for step captures
+	Duration  time.Duration
+	StartedAt time.Time
+	Dangerous bool
+	Danger    *DangerInfo
+	// Confirmation is how a dangerous command was allowed to run:
+	// ConfirmedByOperator or ConfirmedAutomatically. Empty otherwise.
+	Confirmation string
+	Error        error
 }
 
+// Confirmation values of ExecResult and StepResult.
+const (
+	// ConfirmedByOperator means the operator answered a confirmation
+	// prompt.
+	ConfirmedByOperator = "operator"
+	// ConfirmedAutomatically means the prompt was skipped with --yes.
+	ConfirmedAutomatically = "auto"
+)
+
 // Exec executes a single command with the given configuration.
 func Exec(ctx context.Context, config ExecConfig) ExecResult {
 	startTime := time.Now()
 
 	result := ExecResult{
-		Command: config.Command,
+		Command:   config.Command,
+		StartedAt: startTime,
 	}
 
 	// Check for dangerous commands
@@ -54,6 +77,7 @@ func Exec(ctx context.Context, config ExecConfig) ExecResult {
 			if config.AutoConfirm {
 				// Auto-confirm mode (--yes flag), show warning but proceed
 				fmt.Fprintf(os.Stderr, "Warning: %s\n", danger.Warning())
+				result.Confirmation = ConfirmedAutomatically
 			} else {
 				// Interactive mode, prompt for confirmation
 				confirmed, err := danger.Confirm()
@@ -70,10 +94,38 @@ func Exec(ctx context.Context, config ExecConfig) ExecResult {
 					result.Duration = time.Since(startTime)
 					return result
 				}
+				result.Confirmation = ConfirmedByOperator
 			}
 		}
 	}
 
+	// Validate working directory before spawning the shell
+	if err := ValidateCWD(config.CWD); err != nil {
+		result.Error = err
+		result.Success = false
+		result.ExitCode = 1
+		result.Duration = time.Since(startTime)
+		return result
+	}
+
+	// The command may not share svf's process group, so a terminal
+	// interrupt may reach only svf; turn it into a cancellation
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Bound the command's run time
+	if config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.Timeout)
+		defer cancel()
+	}
+
+	// Warn while the command is still running, not after it finishes
+	if config.MaxDuration > 0 && config.OnOverBudget != nil {
+		budget := time.AfterFunc(config.MaxDuration, config.OnOverBudget)
+		defer budget.Stop()
+	}
+
 	// Determine shell
 	shell := config.Shell
 	if shell == "" {
@@ -98,6 +150,12 @@ func Exec(ctx context.Context, config ExecConfig) ExecResult {
 		}
 	}
 
+	// Run in a process group of its own, where the terminal allows, so
+	// that canceling the command also stops everything it spawned
+	setProcessGroup(cmd)
+	cmd.Cancel = func() error { return terminateProcessGroup(cmd) }
+	cmd.WaitDelay = killGracePeriod + time.Second
+
 	// Set working directory
 	if config.CWD != "" {
 		cmd.Dir = config.CWD
@@ -112,7 +170,7 @@ func Exec(ctx context.Context, config ExecConfig) ExecResult {
 	}
 
 	// Execute and capture output
-	var output strings.Builder
+	var output, stdoutOnly strings.Builder
 	if config.Stream {
 		// Stream output in real-time
 		stdout, err := cmd.StdoutPipe()
@@ -139,6 +197,7 @@ func Exec(ctx context.Context, config ExecConfig) ExecResult {
 
 		// Use WaitGroup to wait for all goroutines
 		var wg sync.WaitGroup
+		var mu sync.Mutex
 		wg.Add(2)
 
 		// Read stdout
@@ -147,7 +206,10 @@ func Exec(ctx context.Context, config ExecConfig) ExecResult {
 			scanner := newLineScanner(stdout)
 			for scanner.Scan() {
 				line := scanner.Text()
+				mu.Lock()
 				output.WriteString(line + "\n")
+				stdoutOnly.WriteString(line + "\n")
+				mu.Unlock()
 			}
 		}()
 
@@ -157,18 +219,24 @@ func Exec(ctx context.Context, config ExecConfig) ExecResult {
 			scanner := newLineScanner(stderr)
 			for scanner.Scan() {
 				line := scanner.Text()
+				mu.Lock()
 				output.WriteString(line + "\n")
+				mu.Unlock()
 			}
 		}()
 
-		// Wait for completion
+		// Drain both pipes before Wait, which closes them
+		wg.Wait()
 		err = cmd.Wait()
-		wg.Wait() // Wait for all goroutines to finish
 
 		result.Output = output.String()
+		result.Stdout = stdoutOnly.String()
 		result.Duration = time.Since(startTime)
 
 		if err != nil {
+			if contextEnded(ctx, config.Timeout, &result) {
+				return result
+			}
 			if exitErr, ok := err.(*exec.ExitError); ok {
 				result.ExitCode = getExitCode(exitErr)
 				result.Success = false
@@ -180,13 +248,19 @@ func Exec(ctx context.Context, config ExecConfig) ExecResult {
 			return result
 		}
 	} else {
-		// Capture all output at once
-		out, err := cmd.CombinedOutput()
-		output.Write(out)
+		// Capture all output at once, keeping stdout for captures
+		combined := &lockedWriter{w: &output}
+		cmd.Stdout = io.MultiWriter(combined, &stdoutOnly)
+		cmd.Stderr = combined
+		err := cmd.Run()
 		result.Output = output.String()
+		result.Stdout = stdoutOnly.String()
 		result.Duration = time.Since(startTime)
 
 		if err != nil {
+			if contextEnded(ctx, config.Timeout, &result) {
+				return result
+			}
 			if exitErr, ok := err.(*exec.ExitError); ok {
 				result.ExitCode = getExitCode(exitErr)
 				result.Success = false
@@ -204,6 +278,39 @@ func Exec(ctx context.Context, config ExecConfig) ExecResult {
 	return result
 }
 
+// contextEnded records a command stopped by cancellation or its timeout.
+// It reports whether the context ended.
+func contextEnded(ctx context.Context, timeout time.Duration, result *ExecResult) bool {
+	switch ctx.Err() {
+	case nil:
+		return false
+	case context.DeadlineExceeded:
+		result.ExitCode = 124 // Timed out, as with timeout(1)
+		result.Error = ctx.Err()
+		if timeout > 0 {
+			result.Error = fmt.Errorf("timed out after %s", timeout)
+		}
+	default:
+		result.ExitCode = 13 // Canceled
+		result.Error = ctx.Err()
+	}
+	result.Success = false
+	return true
+}
+
+// lockedWriter serializes writes from a command's stdout and stderr
+// copiers into one buffer.
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
+
 // getExitCode extracts the exit code from an exec.ExitError.
 func getExitCode(err *exec.ExitError) int {
 	if status, ok := err.Sys().(syscall.WaitStatus); ok {
@@ -162,6 +162,7 @@ func (e *StepExecutor) ExecStep(ctx context.Context, step *workflows.Step, param
 	}
 
 	result := Exec(ctx, config)
+	captured := ApplyCaptures(step, &result)
 
 	// Write output to sink if streaming
 	if e.streamOutput && sink != nil && result.Output != "" {
@@ -173,13 +174,16 @@ func (e *StepExecutor) ExecStep(ctx context.Context, step *workflows.Step, param
 	}
 
 	return StepResult{
-		Success:  result.Success,
-		Skipped:  false,
-		Canceled: false,
-		ExitCode: result.ExitCode,
-		Output:   result.Output,
-		Duration: result.Duration,
-		Error:    result.Error,
+		Success:      result.Success,
+		Skipped:      false,
+		Canceled:     ctx.Err() != nil,
+		ExitCode:     result.ExitCode,
+		Output:       result.Output,
+		Duration:     result.Duration,
+		Error:        result.Error,
+		Captured:     captured,
+		StartedAt:    result.StartedAt,
+		Confirmation: result.Confirmation,
 	}
 }
 
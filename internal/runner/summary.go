@@ -0,0 +1,86 @@
+package runner
+
+import (
+	"sort"
+
+	"github.com/chazuruo/svf/internal/placeholders"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// MaskedValue replaces the values of secret placeholders in a Summary
+// and other reports of a run.
+const MaskedValue = "***"
+
+// Summary describes a run before its first step, for the user to confirm.
+type Summary struct {
+	Title string
+	Steps int
+
+	// Values are the placeholder values the run will use, sorted by name,
+	// with secret values masked. Values not set yet are "".
+	Values []SummaryValue
+
+	// Guardrails are the clusters and cloud accounts the workflow must
+	// run against, by field name, e.g. kube_context.
+	Guardrails []SummaryValue
+
+	// Dangerous are the 1-based numbers of steps whose commands the
+	// danger checker warns about.
+	Dangerous []int
+}
+
+// SummaryValue is a named value in a Summary.
+type SummaryValue struct {
+	Name  string
+	Value string
+}
+
+// Summarize describes running wf with the given placeholder values. A nil
+// checker finds no dangerous steps.
+func Summarize(wf *workflows.Workflow, values map[string]string, checker *DangerChecker) Summary {
+	s := Summary{Title: wf.Title, Steps: len(wf.Steps)}
+
+	info := placeholders.ExtractWithMetadata(wf)
+	names := make([]string, 0, len(info))
+	for name := range info {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value, ok := values[name]
+		if !ok {
+			value = info[name].Default
+		}
+		if info[name].Secret && value != "" {
+			value = MaskedValue
+		}
+		s.Values = append(s.Values, SummaryValue{Name: name, Value: value})
+	}
+
+	for _, g := range []SummaryValue{
+		{"kube_context", wf.KubeContext},
+		{"kube_namespace", wf.KubeNamespace},
+		{"aws_profile", wf.AWSProfile},
+		{"aws_account", wf.AWSAccount},
+		{"gcp_project", wf.GCPProject},
+	} {
+		if g.Value != "" {
+			s.Guardrails = append(s.Guardrails, g)
+		}
+	}
+
+	if checker != nil {
+		for i, step := range wf.Steps {
+			// Check the command as it will run when its values are known
+			command, err := placeholders.Substitute(step.Command, values)
+			if err != nil {
+				command = step.Command
+			}
+			if checker.Check(command) != nil {
+				s.Dangerous = append(s.Dangerous, i+1)
+			}
+		}
+	}
+
+	return s
+}
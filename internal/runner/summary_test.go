@@ -0,0 +1,46 @@
+package runner
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+func TestSummarize(t *testing.T) {
+	wf := &workflows.Workflow{
+		Title: "Reset cache",
+		Placeholders: map[string]workflows.Placeholder{
+			"token":  {Secret: true},
+			"region": {Default: "eu-west-1"},
+		},
+		Steps: []workflows.Step{
+			{Command: "login --token <token>"},
+			{Command: "rm -rf <dir>"},
+			{Command: "echo <region>"},
+		},
+		KubeContext: "prod-*",
+		AWSAccount:  "123456789012",
+	}
+
+	s := Summarize(wf, map[string]string{"token": "hunter2", "dir": "/"}, NewDangerChecker(true))
+
+	if s.Title != "Reset cache" || s.Steps != 3 {
+		t.Errorf("Summarize() title/steps = %q/%d", s.Title, s.Steps)
+	}
+	wantValues := []SummaryValue{{"dir", "/"}, {"region", "eu-west-1"}, {"token", "***"}}
+	if !reflect.DeepEqual(s.Values, wantValues) {
+		t.Errorf("Summarize() values = %v, want %v", s.Values, wantValues)
+	}
+	wantGuardrails := []SummaryValue{{"kube_context", "prod-*"}, {"aws_account", "123456789012"}}
+	if !reflect.DeepEqual(s.Guardrails, wantGuardrails) {
+		t.Errorf("Summarize() guardrails = %v, want %v", s.Guardrails, wantGuardrails)
+	}
+	if !reflect.DeepEqual(s.Dangerous, []int{2}) {
+		t.Errorf("Summarize() dangerous = %v, want [2]", s.Dangerous)
+	}
+
+	if s := Summarize(wf, nil, nil); len(s.Dangerous) != 0 {
+		t.Errorf("Summarize() without a checker found dangerous steps %v", s.Dangerous)
+	}
+}
@@ -0,0 +1,152 @@
+package runner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/chazuruo/svf/internal/placeholders"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// TestResult is the outcome of running a workflow test case.
+type TestResult struct {
+	Name       string
+	Commands   []string // Substituted command of each step that ran
+	FailedStep int      // Index of the step that stopped the run, or -1
+	Err        error    // Why the failed step failed
+	Failures   []string // Expectations that weren't met
+}
+
+// Passed reports whether every expectation was met.
+func (r TestResult) Passed() bool {
+	return len(r.Failures) == 0
+}
+
+// RunTest runs a workflow against a test case's mocked step outputs and
+// checks the expected outcome. Nothing is executed: each step's command is
+// substituted as in a real run, then its mock's output feeds the step's
+// captures and its exit code decides whether the run goes on.
+func RunTest(wf *workflows.Workflow, tc workflows.TestCase) TestResult {
+	result := TestResult{Name: tc.Name, FailedStep: -1}
+	fail := func(format string, args ...any) {
+		result.Failures = append(result.Failures, fmt.Sprintf(format, args...))
+	}
+
+	params, err := testParams(wf, tc)
+	if err != nil {
+		fail("%v", err)
+		return result
+	}
+
+	mocks := make(map[int]workflows.StepMock, len(tc.Steps))
+	for ref, mock := range tc.Steps {
+		mocks[wf.StepIndex(ref)] = mock
+	}
+
+	for i, step := range wf.Steps {
+		cmd, err := placeholders.Substitute(step.Command, params)
+		if err != nil {
+			result.FailedStep = i
+			result.Err = err
+			break
+		}
+		result.Commands = append(result.Commands, cmd)
+
+		mock := mocks[i]
+		if mock.Command != "" && mock.Command != cmd {
+			fail("step %d: command is %q, want %q", i+1, cmd, mock.Command)
+		}
+
+		exec := ExecResult{
+			Command:  cmd,
+			ExitCode: mock.ExitCode,
+			Success:  mock.ExitCode == 0,
+			Output:   mock.Stdout,
+			Stdout:   mock.Stdout,
+		}
+		for k, v := range ApplyCaptures(&step, &exec) {
+			params[k] = v
+		}
+		if !exec.Success && !step.ContinueOnError {
+			result.FailedStep = i
+			result.Err = exec.Error
+			if result.Err == nil {
+				result.Err = fmt.Errorf("exit code %d", exec.ExitCode)
+			}
+			break
+		}
+	}
+
+	checkTestExpect(wf, tc.Expect, params, &result)
+	return result
+}
+
+// testParams resolves a test case's placeholder values the way svf run
+// does: params, then the preset, then placeholder defaults. Values are
+// checked against the placeholders' validate patterns.
+func testParams(wf *workflows.Workflow, tc workflows.TestCase) (map[string]string, error) {
+	params := make(map[string]string, len(tc.Params))
+	for k, v := range tc.Params {
+		params[k] = v
+	}
+	if tc.Preset != "" {
+		var err error
+		if params, err = wf.ApplyPreset(tc.Preset, params); err != nil {
+			return nil, err
+		}
+	}
+
+	var missing []string
+	for name, info := range placeholders.ExtractWithMetadata(wf) {
+		value, ok := params[name]
+		if !ok {
+			if info.Default == "" {
+				missing = append(missing, name)
+				continue
+			}
+			value = info.Default
+			params[name] = value
+		}
+		if err := placeholders.Validate(value, info.Validate); err != nil {
+			return nil, fmt.Errorf("placeholder %s: %w", name, err)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("missing placeholder values: %s", strings.Join(missing, ", "))
+	}
+	return params, nil
+}
+
+// checkTestExpect compares a finished test run with the expected status,
+// failed step and placeholder values.
+func checkTestExpect(wf *workflows.Workflow, expect workflows.TestExpect, params map[string]string, result *TestResult) {
+	wantFailure := expect.Status == workflows.TestFailure || expect.FailedStep != ""
+	switch {
+	case wantFailure && result.FailedStep < 0:
+		result.Failures = append(result.Failures, "workflow succeeded, want failure")
+	case !wantFailure && result.FailedStep >= 0:
+		result.Failures = append(result.Failures, fmt.Sprintf("workflow failed at step %d (%v), want success", result.FailedStep+1, result.Err))
+	}
+	if expect.FailedStep != "" && result.FailedStep >= 0 {
+		if want := wf.StepIndex(expect.FailedStep); want != result.FailedStep {
+			result.Failures = append(result.Failures, fmt.Sprintf("workflow failed at step %d, want step %d", result.FailedStep+1, want+1))
+		}
+	}
+
+	names := make([]string, 0, len(expect.Values))
+	for name := range expect.Values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		got, ok := params[name]
+		switch {
+		case !ok:
+			result.Failures = append(result.Failures, fmt.Sprintf("<%s> was not set, want %q", name, expect.Values[name]))
+		case got != expect.Values[name]:
+			result.Failures = append(result.Failures, fmt.Sprintf("<%s> is %q, want %q", name, got, expect.Values[name]))
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package runner
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+func testCaseWorkflow() *workflows.Workflow {
+	return &workflows.Workflow{
+		Title: "Deploy",
+		Placeholders: map[string]workflows.Placeholder{
+			"env":      {Validate: "^(staging|prod)$"},
+			"replicas": {Default: "2"},
+		},
+		Presets: map[string]map[string]string{"prod": {"env": "prod", "replicas": "6"}},
+		Steps: []workflows.Step{
+			{
+				Name:    "create",
+				Command: "deployctl create --env <env>",
+				Capture: map[string]workflows.Extractor{"id": {JSONPath: ".id"}},
+			},
+			{Name: "scale", Command: "deployctl scale <id> --replicas <replicas>"},
+			{Name: "notify", Command: "notify <env> <id>", ContinueOnError: true},
+			{Name: "verify", Command: "deployctl status <id>"},
+		},
+	}
+}
+
+func TestRunTest(t *testing.T) {
+	wf := testCaseWorkflow()
+	tc := workflows.TestCase{
+		Name:   "happy path",
+		Params: map[string]string{"env": "staging"},
+		Steps: map[string]workflows.StepMock{
+			"create": {Stdout: `{"id": "d-42"}`},
+			"2":      {Command: "deployctl scale d-42 --replicas 2"},
+			"notify": {ExitCode: 1},
+		},
+		Expect: workflows.TestExpect{Values: map[string]string{"id": "d-42"}},
+	}
+
+	result := RunTest(wf, tc)
+	if !result.Passed() {
+		t.Fatalf("RunTest() failures: %v", result.Failures)
+	}
+	want := []string{
+		"deployctl create --env staging",
+		"deployctl scale d-42 --replicas 2",
+		"notify staging d-42",
+		"deployctl status d-42",
+	}
+	if !reflect.DeepEqual(result.Commands, want) {
+		t.Errorf("Commands = %v, want %v", result.Commands, want)
+	}
+}
+
+func TestRunTest_ExpectedFailure(t *testing.T) {
+	wf := testCaseWorkflow()
+	tc := workflows.TestCase{
+		Name:   "scale fails",
+		Preset: "prod",
+		Steps: map[string]workflows.StepMock{
+			"create": {Stdout: `{"id": "d-42"}`},
+			"scale":  {ExitCode: 3, Command: "deployctl scale d-42 --replicas 6"},
+		},
+		Expect: workflows.TestExpect{FailedStep: "scale"},
+	}
+
+	result := RunTest(wf, tc)
+	if !result.Passed() {
+		t.Fatalf("RunTest() failures: %v", result.Failures)
+	}
+	if result.FailedStep != 1 || len(result.Commands) != 2 {
+		t.Errorf("FailedStep = %d, Commands = %v", result.FailedStep, result.Commands)
+	}
+}
+
+func TestRunTest_Failures(t *testing.T) {
+	tests := []struct {
+		name string
+		tc   workflows.TestCase
+		want string
+	}{
+		{
+			name: "missing placeholder",
+			tc:   workflows.TestCase{},
+			want: "missing placeholder values: env",
+		},
+		{
+			name: "invalid value",
+			tc:   workflows.TestCase{Params: map[string]string{"env": "dev"}},
+			want: "placeholder env:",
+		},
+		{
+			name: "capture fails",
+			tc: workflows.TestCase{
+				Params: map[string]string{"env": "staging"},
+				Steps:  map[string]workflows.StepMock{"create": {Stdout: "not json"}},
+			},
+			want: "workflow failed at step 1 (capture id: output is not JSON",
+		},
+		{
+			name: "command mismatch",
+			tc: workflows.TestCase{
+				Params: map[string]string{"env": "staging"},
+				Steps: map[string]workflows.StepMock{
+					"create": {Stdout: `{"id": "d-1"}`, Command: "deployctl create --env prod"},
+				},
+			},
+			want: `step 1: command is "deployctl create --env staging", want "deployctl create --env prod"`,
+		},
+		{
+			name: "unexpected success",
+			tc: workflows.TestCase{
+				Params: map[string]string{"env": "staging"},
+				Steps:  map[string]workflows.StepMock{"create": {Stdout: `{"id": "d-1"}`}},
+				Expect: workflows.TestExpect{Status: workflows.TestFailure},
+			},
+			want: "workflow succeeded, want failure",
+		},
+		{
+			name: "wrong value",
+			tc: workflows.TestCase{
+				Params: map[string]string{"env": "staging"},
+				Steps:  map[string]workflows.StepMock{"create": {Stdout: `{"id": "d-1"}`}},
+				Expect: workflows.TestExpect{Values: map[string]string{"id": "d-2"}},
+			},
+			want: `<id> is "d-1", want "d-2"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := RunTest(testCaseWorkflow(), tt.tc)
+			if result.Passed() {
+				t.Fatal("RunTest() passed, want a failure")
+			}
+			if !strings.HasPrefix(result.Failures[0], tt.want) {
+				t.Errorf("Failures[0] = %q, want prefix %q", result.Failures[0], tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package runpath
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/chazuruo/svf/internal/audit"
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// RecordAccess appends a view or run of wf to the audit log if wf is
+// tagged sensitive. Access is refused when it can't be recorded. In
+// read-only mode it is recorded on this machine only.
+func RecordAccess(cfg *config.Config, wf *workflows.Workflow, action string) error {
+	if !audit.IsSensitive(wf) {
+		return nil
+	}
+
+	keyPath, err := audit.KeyPath()
+	if err != nil {
+		return fmt.Errorf("failed to record access to sensitive workflow: %w", err)
+	}
+	key, err := audit.LoadKey(keyPath)
+	if err != nil {
+		return fmt.Errorf("failed to record access to sensitive workflow: %w", err)
+	}
+	localDir, err := audit.LocalDir()
+	if err != nil {
+		return fmt.Errorf("failed to record access to sensitive workflow: %w", err)
+	}
+	host, _ := os.Hostname()
+	repoPath := cfg.Repo.Path
+	if cfg.ReadOnly {
+		repoPath = ""
+	}
+
+	_, err = audit.Record(repoPath, localDir, key, audit.Event{
+		At:       time.Now(),
+		Action:   action,
+		Workflow: wf.ID,
+		Title:    wf.Title,
+		Actor:    cfg.Identity.Path,
+		Host:     host,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record access to sensitive workflow: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package runpath
+
+import (
+	"testing"
+
+	"github.com/chazuruo/svf/internal/audit"
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+func TestRecordAccess(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	cfg := config.DefaultConfig()
+	cfg.Repo.Path = t.TempDir()
+	cfg.Identity.Path = "platform/alice"
+
+	if err := RecordAccess(cfg, &workflows.Workflow{ID: "wf_docs", Title: "Docs"}, audit.ActionView); err != nil {
+		t.Fatal(err)
+	}
+	sensitive := &workflows.Workflow{ID: "wf_db", Title: "Fail over DB", Tags: []string{audit.SensitiveTag}}
+	if err := RecordAccess(cfg, sensitive, audit.ActionRun); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := audit.Load(cfg.Repo.Path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("audit.Load() = %d entries, want 1 (only the sensitive workflow)", len(entries))
+	}
+	e := entries[0]
+	if e.Workflow != "wf_db" || e.Action != audit.ActionRun || e.Actor != "platform/alice" || !e.Verified {
+		t.Errorf("recorded %+v", e)
+	}
+}
@@ -0,0 +1,98 @@
+package runpath
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/placeholders"
+	runnerpkg "github.com/chazuruo/svf/internal/runner"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// Cleanup runs the workflow's cleanup steps after a run failed or was
+// aborted, like a finally block. results are the run's step results:
+// nothing is cleaned up when no step ran. Every cleanup step runs even if
+// an earlier one fails, and the run's cancellation doesn't stop them.
+// params are the run's placeholder values, including captured ones; steps
+// using a placeholder without a value are skipped. It returns the number
+// of cleanup steps that failed.
+func Cleanup(ctx context.Context, cfg *config.Config, wf *workflows.Workflow, results []runnerpkg.StepResult, params map[string]string, autoConfirm, stream bool, w io.Writer) int {
+	if len(wf.Cleanup) == 0 || !anyStepRan(results) {
+		return 0
+	}
+	ctx = context.WithoutCancel(ctx)
+
+	dangerChecker := runnerpkg.NewDangerChecker(cfg.Runner.DangerousCommandWarnings)
+	if err := dangerChecker.LoadRules(cfg.Repo.Path); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	fmt.Fprintf(w, "\nRunning %d cleanup steps...\n", len(wf.Cleanup))
+	failed := 0
+	for i, step := range wf.Cleanup {
+		wf.ApplyDefaults(&step)
+		name := step.Name
+		if name == "" {
+			name = fmt.Sprintf("Cleanup %d", i+1)
+		}
+		if !step.RunsOn(runtime.GOOS, runtime.GOARCH) {
+			fmt.Fprintf(w, "○ [cleanup %d/%d] %s skipped (other platform)\n", i+1, len(wf.Cleanup), name)
+			continue
+		}
+		cmd, err := placeholders.Substitute(step.Command, params)
+		if err != nil {
+			fmt.Fprintf(w, "○ [cleanup %d/%d] %s skipped (%v)\n", i+1, len(wf.Cleanup), name, err)
+			continue
+		}
+
+		result := runnerpkg.Exec(ctx, runnerpkg.ExecConfig{
+			Command:       cmd,
+			Shell:         step.Shell,
+			CWD:           runnerpkg.ResolveCWD(step.CWD, wf.Defaults.CWD, cfg.Repo.Path),
+			Env:           step.Env,
+			Stream:        stream && cfg.Runner.StreamOutput,
+			DangerChecker: dangerChecker,
+			AutoConfirm:   autoConfirm,
+			Timeout:       time.Duration(cfg.Runner.StepTimeout) * time.Second,
+		})
+		if stream && !cfg.Runner.StreamOutput && result.Output != "" {
+			fmt.Fprint(w, result.Output)
+		}
+		if result.Success {
+			fmt.Fprintf(w, "✓ [cleanup %d/%d] %s\n", i+1, len(wf.Cleanup), name)
+			continue
+		}
+		failed++
+		if result.ExitCode != 0 || result.Error == nil {
+			fmt.Fprintf(w, "✗ [cleanup %d/%d] %s, exit code %d\n", i+1, len(wf.Cleanup), name, result.ExitCode)
+		} else {
+			fmt.Fprintf(w, "✗ [cleanup %d/%d] %s: %v\n", i+1, len(wf.Cleanup), name, result.Error)
+		}
+	}
+	if failed > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: %d of %d cleanup steps failed\n", failed, len(wf.Cleanup))
+	}
+	return failed
+}
+
+// anyStepRan reports whether at least one of results is from a step that
+// was executed.
+func anyStepRan(results []runnerpkg.StepResult) bool {
+	for _, r := range results {
+		if StepRan(r) && !r.Skipped {
+			return true
+		}
+	}
+	return false
+}
+
+// StepRan reports whether a step result is from a step that was executed
+// or explicitly skipped, rather than an unset entry.
+func StepRan(r runnerpkg.StepResult) bool {
+	return r.Success || r.Skipped || r.Canceled || r.ExitCode != 0 || r.Duration > 0 || r.Error != nil || r.Output != ""
+}
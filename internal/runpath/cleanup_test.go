@@ -0,0 +1,60 @@
+package runpath
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chazuruo/svf/internal/config"
+	//nolint:staticcheck // SA1019 - Using runner for StepResult type
+	runnerpkg "github.com/chazuruo/svf/internal/runner"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+func TestCleanup(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.Repo.Path = dir
+	cfg.Runner.StreamOutput = false
+
+	wf := &workflows.Workflow{
+		Title: "Drain",
+		Steps: []workflows.Step{{Name: "cordon", Command: "true"}},
+		Cleanup: []workflows.Step{
+			{Name: "broken", Command: "exit 3"},
+			{Name: "uncordon", Command: "echo <node> > uncordoned"},
+			{Name: "unset", Command: "echo <missing>"},
+		},
+	}
+	failed := []runnerpkg.StepResult{{Step: 0, ExitCode: 1}}
+	params := map[string]string{"node": "node-1"}
+
+	// Nothing is cleaned up when no step ran
+	var out bytes.Buffer
+	if n := Cleanup(context.Background(), cfg, wf, []runnerpkg.StepResult{{}}, params, true, true, &out); n != 0 || out.Len() != 0 {
+		t.Fatalf("Cleanup() without a step run = %d, output %q", n, out.String())
+	}
+
+	// A canceled run still cleans up, and a failing cleanup step doesn't
+	// stop the next one
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if n := Cleanup(ctx, cfg, wf, failed, params, true, true, &out); n != 1 {
+		t.Errorf("Cleanup() = %d failed steps, want 1", n)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "uncordoned"))
+	if err != nil {
+		t.Fatalf("second cleanup step didn't run: %v\n%s", err, out.String())
+	}
+	if got := strings.TrimSpace(string(data)); got != "node-1" {
+		t.Errorf("cleanup step wrote %q, want node-1", got)
+	}
+	for _, want := range []string{"✗ [cleanup 1/3] broken, exit code 3", "✓ [cleanup 2/3] uncordon", "○ [cleanup 3/3] unset skipped"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in output:\n%s", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package runpath
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	runnerpkg "github.com/chazuruo/svf/internal/runner"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// currentAWSAccount and currentGCPProject look up the active cloud
+// identity. Tests replace them.
+var (
+	currentAWSAccount = runnerpkg.CurrentAWSAccount
+	currentGCPProject = runnerpkg.CurrentGCPProject
+)
+
+// CheckCloud refuses to run a workflow whose aws_profile, aws_account or
+// gcp_project don't match the active cloud identity. Only the fields the
+// workflow declares are looked up; ignore skips the check.
+func CheckCloud(ctx context.Context, wf *workflows.Workflow, ignore bool) error {
+	if ignore {
+		return nil
+	}
+
+	var problems []string
+	state := runnerpkg.CloudState{AWSProfile: runnerpkg.CurrentAWSProfile()}
+	if wf.AWSAccount != "" {
+		account, err := currentAWSAccount(ctx)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("could not read the AWS account: %v", err))
+		}
+		state.AWSAccount = account
+	}
+	if wf.GCPProject != "" {
+		project, err := currentGCPProject(ctx)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("could not read the gcloud project: %v", err))
+		}
+		state.GCPProject = project
+	}
+
+	if len(problems) == 0 {
+		problems = runnerpkg.CloudMismatches(wf, state)
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("refusing to run %s: %s", wf.Title, strings.Join(problems, "; "))
+}
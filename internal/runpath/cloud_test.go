@@ -0,0 +1,54 @@
+package runpath
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+func TestCheckCloud(t *testing.T) {
+	origAccount, origProject := currentAWSAccount, currentGCPProject
+	defer func() { currentAWSAccount, currentGCPProject = origAccount, origProject }()
+
+	lookups := 0
+	currentAWSAccount = func(context.Context) (string, error) {
+		lookups++
+		return "123456789012", nil
+	}
+	currentGCPProject = func(context.Context) (string, error) {
+		lookups++
+		return "", errors.New("gcloud: not found")
+	}
+	t.Setenv("AWS_PROFILE", "prod")
+
+	tests := []struct {
+		name        string
+		wf          workflows.Workflow
+		ignore      bool
+		wantErr     bool
+		wantLookups int
+	}{
+		{name: "no guardrails"},
+		{name: "profile only", wf: workflows.Workflow{AWSProfile: "prod"}},
+		{name: "wrong profile", wf: workflows.Workflow{AWSProfile: "staging"}, wantErr: true},
+		{name: "matching account", wf: workflows.Workflow{AWSAccount: "123456789012"}, wantLookups: 1},
+		{name: "wrong account", wf: workflows.Workflow{AWSAccount: "210987654321"}, wantErr: true, wantLookups: 1},
+		{name: "gcloud unavailable", wf: workflows.Workflow{GCPProject: "acme-prod"}, wantErr: true, wantLookups: 1},
+		{name: "ignored", wf: workflows.Workflow{AWSAccount: "210987654321"}, ignore: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lookups = 0
+			tt.wf.Title = "Rotate keys"
+			err := CheckCloud(context.Background(), &tt.wf, tt.ignore)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckCloud() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if lookups != tt.wantLookups {
+				t.Errorf("cloud looked up %d times, want %d", lookups, tt.wantLookups)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package runpath
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chazuruo/svf/internal/config"
+	runnerpkg "github.com/chazuruo/svf/internal/runner"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// currentKubeState reads the active kubectl context. Tests replace it.
+var currentKubeState = runnerpkg.CurrentKubeState
+
+// CheckKube refuses to run a workflow whose kube_context or kube_namespace
+// doesn't match the active kubectl context. With runner.kube_guard set to
+// "prompt", an interactive user (p != nil) may confirm the mismatch
+// instead; ignore skips the check.
+func CheckKube(ctx context.Context, cfg *config.Config, wf *workflows.Workflow, ignore bool, p Prompter) error {
+	if ignore || (wf.KubeContext == "" && wf.KubeNamespace == "") {
+		return nil
+	}
+
+	var mismatches []string
+	state, err := currentKubeState(ctx)
+	if err != nil {
+		mismatches = []string{fmt.Sprintf("could not read the kubectl context: %v", err)}
+	} else {
+		mismatches = runnerpkg.KubeMismatches(wf, state)
+	}
+	if len(mismatches) == 0 {
+		return nil
+	}
+
+	if cfg.Runner.KubeGuard == "prompt" && p != nil {
+		for _, m := range mismatches {
+			p.Printf("Warning: %s\n", m)
+		}
+		ok, err := p.Confirm(fmt.Sprintf("Run %s against this cluster anyway?", wf.Title), false)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+	}
+	return fmt.Errorf("refusing to run %s: %s", wf.Title, strings.Join(mismatches, "; "))
+}
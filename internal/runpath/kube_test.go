@@ -0,0 +1,71 @@
+package runpath
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/chazuruo/svf/internal/config"
+	runnerpkg "github.com/chazuruo/svf/internal/runner"
+	"github.com/chazuruo/svf/internal/tui"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+func TestCheckKube(t *testing.T) {
+	orig := currentKubeState
+	defer func() { currentKubeState = orig }()
+
+	var kubeErr error
+	calls := 0
+	currentKubeState = func(context.Context) (runnerpkg.KubeState, error) {
+		calls++
+		return runnerpkg.KubeState{Context: "staging", Namespace: "default"}, kubeErr
+	}
+
+	tests := []struct {
+		name      string
+		guard     string
+		context   string
+		ignore    bool
+		kubeErr   error
+		input     string
+		wantErr   bool
+		wantCalls int
+	}{
+		{name: "no guardrails", guard: "prompt", wantCalls: 0},
+		{name: "matching context", guard: "prompt", context: "staging", wantCalls: 1},
+		{name: "mismatch non-interactive", guard: "prompt", context: "prod", wantErr: true, wantCalls: 1},
+		{name: "mismatch confirmed", guard: "prompt", context: "prod", input: "y\n", wantCalls: 1},
+		{name: "mismatch declined", guard: "prompt", context: "prod", input: "n\n", wantErr: true, wantCalls: 1},
+		{name: "block ignores prompt", guard: "block", context: "prod", input: "y\n", wantErr: true, wantCalls: 1},
+		{name: "ignored", guard: "block", context: "prod", ignore: true, wantCalls: 0},
+		{name: "kubectl missing", guard: "prompt", context: "staging", kubeErr: errors.New("not found"), wantErr: true, wantCalls: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls, kubeErr = 0, tt.kubeErr
+			cfg := config.DefaultConfig()
+			cfg.Runner.KubeGuard = tt.guard
+			wf := &workflows.Workflow{Title: "Failover", KubeContext: tt.context}
+
+			var p Prompter
+			var out bytes.Buffer
+			if tt.input != "" {
+				p = tui.NewLinePrompter(strings.NewReader(tt.input), &out)
+			}
+
+			err := CheckKube(context.Background(), cfg, wf, tt.ignore, p)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckKube() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("kubectl read %d times, want %d", calls, tt.wantCalls)
+			}
+			if tt.input != "" && tt.guard == "prompt" && !strings.Contains(out.String(), `workflow expects "prod"`) {
+				t.Errorf("prompt output missing mismatch: %q", out.String())
+			}
+		})
+	}
+}
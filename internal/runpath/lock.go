@@ -0,0 +1,130 @@
+package runpath
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/lock"
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+// RunningError is returned when an exclusive workflow can't run because
+// another run holds its lock.
+type RunningError struct {
+	Title  string
+	Holder *lock.Info // Nil when unknown
+}
+
+// Error implements error.
+func (e *RunningError) Error() string {
+	if e.Holder == nil {
+		return fmt.Sprintf("%s is exclusive and another run holds its lock; if that run is over, run again with --break-lock", e.Title)
+	}
+	return fmt.Sprintf("%s is exclusive and already running (%s); if that run is over, run again with --break-lock", e.Title, e.Holder)
+}
+
+// LockName names the run lock of the workflow at ref after its ID, or its
+// slug when it has none. Documents of a multi-document file without an ID
+// get "#N" appended, so each has its own lock.
+func LockName(ref store.WorkflowRef, wf *workflows.Workflow) string {
+	if ref.ID != "" {
+		return ref.ID
+	}
+	if wf.ID != "" {
+		return wf.ID
+	}
+	name := ref.Slug
+	if name == "" {
+		name = store.Slugify(wf.Title)
+	}
+	if ref.Doc > 0 {
+		name = fmt.Sprintf("%s#%d", name, ref.Doc)
+	}
+	return name
+}
+
+// lockReleaseTimeout bounds releasing a remote run lock.
+const lockReleaseTimeout = 30 * time.Second
+
+// Lock takes the run lock of wf: a lock file in the repo, or with
+// runner.run_lock set to "remote", a lock ref on the repo's remote so
+// operators on other hosts see it too. The lock isn't reentrant, so
+// concurrent runs in one process exclude each other too. With breakLock,
+// a lock held by others is taken over, for runs known to be over that
+// left their lock behind.
+func Lock(ctx context.Context, repo gitrepo.Repo, cfg *config.Config, wf *workflows.Workflow, name string, breakLock bool) (func() error, error) {
+	host, _ := os.Hostname()
+	info := lock.Info{
+		PID:        os.Getpid(),
+		Host:       host,
+		Op:         "run " + wf.Title,
+		User:       lockUser(cfg),
+		AcquiredAt: time.Now(),
+	}
+
+	if cfg.Runner.RunLock != "remote" || cfg.Repo.Remote == "" {
+		path := lock.NamedPath(cfg.Repo.Path, name)
+		if breakLock {
+			if err := lock.Break(path); err != nil {
+				return nil, err
+			}
+		}
+		l, err := lock.AcquireFile(path, lock.Options{Op: info.Op, User: info.User, Timeout: -1, NoReentry: true})
+		if errors.Is(err, lock.ErrLocked) {
+			holder, _ := lock.ReadFile(path)
+			return nil, &RunningError{Title: wf.Title, Holder: holder}
+		}
+		if err != nil {
+			return nil, err
+		}
+		return l.Release, nil
+	}
+
+	remote := cfg.Repo.Remote
+	if breakLock {
+		if _, err := repo.ReadLock(ctx, remote, name); err == nil {
+			if err := repo.DeleteLock(ctx, remote, name); err != nil {
+				return nil, fmt.Errorf("failed to break run lock: %w", err)
+			}
+		}
+	}
+	data, err := json.Marshal(info)
+	if err != nil {
+		return nil, err
+	}
+	if err := repo.PushLock(ctx, remote, name, data); err != nil {
+		if !errors.Is(err, gitrepo.ErrLockHeld) {
+			return nil, fmt.Errorf("failed to take run lock on %s: %w", remote, err)
+		}
+		var holder *lock.Info
+		if data, err := repo.ReadLock(ctx, remote, name); err == nil {
+			holder = &lock.Info{}
+			if json.Unmarshal(data, holder) != nil {
+				holder = nil
+			}
+		}
+		return nil, &RunningError{Title: wf.Title, Holder: holder}
+	}
+	// The run's context is often cancelled by the time the lock is
+	// released, e.g. after Ctrl-C, and the lock must go all the same
+	return func() error {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), lockReleaseTimeout)
+		defer cancel()
+		return repo.DeleteLock(ctx, remote, name)
+	}, nil
+}
+
+// lockUser identifies the operator in run locks.
+func lockUser(cfg *config.Config) string {
+	if cfg.Identity.Path != "" {
+		return cfg.Identity.Path
+	}
+	return os.Getenv("USER")
+}
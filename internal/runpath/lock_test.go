@@ -0,0 +1,64 @@
+package runpath
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/testutil"
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+func TestLockName(t *testing.T) {
+	wf := &workflows.Workflow{Title: "Renew certs"}
+	tests := []struct {
+		ref  store.WorkflowRef
+		want string
+	}{
+		{store.WorkflowRef{ID: "wf_certs", Slug: "certs"}, "wf_certs"},
+		{store.WorkflowRef{Slug: "certs"}, "certs"},
+		{store.WorkflowRef{Slug: "certs", Doc: 2}, "certs#2"},
+		{store.WorkflowRef{}, "renew-certs"},
+	}
+	for _, tt := range tests {
+		if got := LockName(tt.ref, wf); got != tt.want {
+			t.Errorf("LockName(%+v) = %q, want %q", tt.ref, got, tt.want)
+		}
+	}
+}
+
+// ctxRepo fails lock deletion once its context is done, like git would.
+type ctxRepo struct {
+	*testutil.FakeRepo
+}
+
+func (r ctxRepo) DeleteLock(ctx context.Context, remote, name string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return r.FakeRepo.DeleteLock(ctx, remote, name)
+}
+
+func TestLock_ReleaseAfterCancel(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Repo.Path = t.TempDir()
+	cfg.Runner.RunLock = "remote"
+	repo := ctxRepo{testutil.NewFakeRepo(cfg.Repo.Path)}
+	wf := &workflows.Workflow{Title: "Fail over", Exclusive: true}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	release, err := Lock(ctx, repo, cfg, wf, "wf_failover", false)
+	if err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+
+	// Ctrl-C during the run
+	cancel()
+	if err := release(); err != nil {
+		t.Fatalf("release() error = %v", err)
+	}
+	if _, err := repo.ReadLock(context.Background(), cfg.Repo.Remote, "wf_failover"); err == nil {
+		t.Error("remote run lock was left behind")
+	}
+}
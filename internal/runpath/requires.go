@@ -0,0 +1,92 @@
+package runpath
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/chazuruo/svf/internal/config"
+	runnerpkg "github.com/chazuruo/svf/internal/runner"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// lookPath and runInstall find and install prerequisites. Tests replace
+// them.
+var (
+	lookPath   runnerpkg.LookPathFunc = exec.LookPath
+	runInstall                        = func(ctx context.Context, command string) error {
+		// Package managers may ask for a password or confirmation
+		cmd := exec.CommandContext(ctx, "sh", "-c", command)
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stderr, os.Stderr
+		return cmd.Run()
+	}
+)
+
+// CheckRequirements refuses to run a workflow while commands it requires
+// are missing, printing how to install them to w. With install, missing
+// commands are first installed from their hints using the package
+// managers on the PATH. Each install command is confirmed with p, with
+// the usual warning when it is dangerous; yes installs without asking,
+// and without either nothing is installed.
+func CheckRequirements(ctx context.Context, cfg *config.Config, wf *workflows.Workflow, install, yes bool, p Prompter, w io.Writer) error {
+	missing := runnerpkg.MissingRequirements(wf.Requires, lookPath)
+	if len(missing) == 0 {
+		return nil
+	}
+
+	if install && (yes || p != nil) {
+		checker := runnerpkg.NewDangerChecker(cfg.Runner.DangerousCommandWarnings)
+		if err := checker.LoadRules(cfg.Repo.Path); err != nil {
+			fmt.Fprintf(w, "Warning: %v\n", err)
+		}
+		for _, req := range missing {
+			command, ok := runnerpkg.InstallCommand(req, lookPath)
+			if !ok {
+				continue
+			}
+			danger := checker.Check(command)
+			if danger != nil {
+				fmt.Fprintf(w, "%s\n", danger.Warning())
+			}
+			if !yes {
+				ok, err := p.Confirm(fmt.Sprintf("Install %s with '%s'?", req.Command, command), danger == nil)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					continue
+				}
+			}
+			fmt.Fprintf(w, "Installing %s: %s\n", req.Command, command)
+			if err := runInstall(ctx, command); err != nil {
+				fmt.Fprintf(w, "Warning: installing %s failed: %v\n", req.Command, err)
+			}
+		}
+		missing = runnerpkg.MissingRequirements(missing, lookPath)
+		if len(missing) == 0 {
+			return nil
+		}
+	}
+
+	names := make([]string, len(missing))
+	fmt.Fprintf(w, "Missing prerequisites for %s:\n", wf.Title)
+	for i, req := range missing {
+		names[i] = req.Command
+		cmds := runnerpkg.InstallInstructions(req)
+		if len(cmds) == 0 {
+			fmt.Fprintf(w, "  %s (no install hint)\n", req.Command)
+			continue
+		}
+		fmt.Fprintf(w, "  %s:\n", req.Command)
+		for _, cmd := range cmds {
+			fmt.Fprintf(w, "    %s\n", cmd)
+		}
+	}
+	if !install {
+		fmt.Fprintf(w, "Install them, or run again with --install-missing.\n")
+	}
+	return fmt.Errorf("refusing to run %s: missing %s", wf.Title, strings.Join(names, ", "))
+}
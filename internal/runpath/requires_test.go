@@ -0,0 +1,86 @@
+package runpath
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/tui"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+func TestCheckRequirements(t *testing.T) {
+	origLook, origInstall := lookPath, runInstall
+	defer func() { lookPath, runInstall = origLook, origInstall }()
+
+	var installed map[string]bool
+	var ran []string
+	lookPath = func(file string) (string, error) {
+		if installed[file] {
+			return "/usr/bin/" + file, nil
+		}
+		return "", errors.New("not found")
+	}
+	runInstall = func(_ context.Context, command string) error {
+		ran = append(ran, command)
+		if command == "brew install jq" {
+			installed["jq"] = true
+		}
+		return nil
+	}
+
+	wf := &workflows.Workflow{
+		Title: "Report",
+		Requires: []workflows.Requirement{
+			{Command: "git"},
+			{Command: "jq", Brew: "jq", Apt: "jq"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		tools   []string
+		install bool
+		yes     bool
+		input   string
+		wantErr bool
+		wantRan []string
+		wantOut string
+	}{
+		{name: "all installed", tools: []string{"git", "jq"}},
+		{name: "missing prints instructions", tools: []string{"git", "brew"}, wantErr: true, wantOut: "brew install jq"},
+		{name: "installed with --yes", tools: []string{"git", "brew"}, install: true, yes: true, wantRan: []string{"brew install jq"}},
+		{name: "install confirmed", tools: []string{"git", "brew"}, install: true, input: "y\n", wantRan: []string{"brew install jq"}},
+		{name: "install declined", tools: []string{"git", "brew"}, install: true, input: "n\n", wantErr: true},
+		{name: "can't confirm", tools: []string{"git", "brew"}, install: true, wantErr: true},
+		{name: "no package manager", tools: []string{"git"}, install: true, yes: true, wantErr: true, wantOut: "sudo apt-get install -y jq"},
+		{name: "no hint", tools: []string{"jq", "brew"}, install: true, yes: true, wantErr: true, wantOut: "git (no install hint)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			installed, ran = map[string]bool{}, nil
+			for _, tool := range tt.tools {
+				installed[tool] = true
+			}
+			var p Prompter
+			if tt.input != "" {
+				p = tui.NewLinePrompter(strings.NewReader(tt.input), &bytes.Buffer{})
+			}
+
+			var out bytes.Buffer
+			err := CheckRequirements(context.Background(), config.DefaultConfig(), wf, tt.install, tt.yes, p, &out)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckRequirements() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if strings.Join(ran, ";") != strings.Join(tt.wantRan, ";") {
+				t.Errorf("ran %q, want %q", ran, tt.wantRan)
+			}
+			if !strings.Contains(out.String(), tt.wantOut) {
+				t.Errorf("output %q doesn't contain %q", out.String(), tt.wantOut)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package runpath
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// IsShared reports whether a workflow file is under the shared root.
+func IsShared(cfg *config.Config, path string) bool {
+	rel, err := filepath.Rel(cfg.Repo.Path, path)
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(rel, filepath.Clean(cfg.Workflows.SharedRoot)+string(filepath.Separator))
+}
+
+// ReviewWarning describes a stale review, or returns "" if the workflow
+// is reviewed or was never reviewed.
+func ReviewWarning(wf *workflows.Workflow) string {
+	if wf.ReviewStatus() != workflows.StaleReview {
+		return ""
+	}
+	return fmt.Sprintf("%s changed after it was reviewed by %s on %s; ask an owner to run 'svf review approve'",
+		wf.Title, wf.ReviewedBy, wf.ReviewedAt.Format("2006-01-02"))
+}
+
+// CheckReview enforces runner.require_review for shared workflows and
+// warns about stale reviews otherwise.
+func CheckReview(cfg *config.Config, wf *workflows.Workflow, path string) error {
+	state := wf.ReviewStatus()
+	if cfg.Runner.RequireReview && state != workflows.Reviewed && IsShared(cfg, path) {
+		return fmt.Errorf("refusing to run %s: shared workflow is %s and runner.require_review is set", wf.Title, state)
+	}
+	if warning := ReviewWarning(wf); warning != "" {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package runpath
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+func TestCheckReview(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Repo.Path = "/repo"
+	shared := filepath.Join(cfg.Repo.Path, "shared", "certs", "workflow.yaml")
+	own := filepath.Join(cfg.Repo.Path, "workflows", "platform", "alice", "certs", "workflow.yaml")
+
+	wf := &workflows.Workflow{
+		SchemaVersion: workflows.SchemaVersion,
+		Title:         "Rotate certificates",
+		Steps:         []workflows.Step{{Command: "certbot renew"}},
+	}
+
+	// Not enforced by default
+	if err := CheckReview(cfg, wf, shared); err != nil {
+		t.Errorf("CheckReview() without require_review error = %v", err)
+	}
+
+	cfg.Runner.RequireReview = true
+	if err := CheckReview(cfg, wf, shared); err == nil {
+		t.Error("CheckReview() for unreviewed shared workflow: expected error")
+	}
+	if err := CheckReview(cfg, wf, own); err != nil {
+		t.Errorf("CheckReview() for personal workflow error = %v", err)
+	}
+
+	if err := wf.Approve("platform/alice", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckReview(cfg, wf, shared); err != nil {
+		t.Errorf("CheckReview() for reviewed workflow error = %v", err)
+	}
+	if ReviewWarning(wf) != "" {
+		t.Errorf("ReviewWarning() for reviewed workflow = %q", ReviewWarning(wf))
+	}
+
+	wf.Steps[0].Command = "certbot renew --force-renewal"
+	if err := CheckReview(cfg, wf, shared); err == nil {
+		t.Error("CheckReview() for stale review: expected error")
+	}
+	if ReviewWarning(wf) == "" {
+		t.Error("ReviewWarning() for stale review is empty")
+	}
+}
@@ -0,0 +1,186 @@
+// Package runpath is the path every workflow run takes, whether started
+// from the CLI, the API server or the Go SDK: the guardrails checked
+// before a workflow runs and what is done once it has.
+package runpath
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/chazuruo/svf/internal/audit"
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	runnerpkg "github.com/chazuruo/svf/internal/runner"
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+// Prompter asks an interactive user to confirm a guardrail, such as a
+// mismatched kubectl context. tui.LinePrompter implements it.
+type Prompter interface {
+	Printf(format string, args ...interface{})
+	Confirm(question string, def bool) (bool, error)
+}
+
+// RefusedError is returned by Prepare and Run when a check refuses to
+// let a workflow run.
+type RefusedError struct {
+	Err error
+}
+
+// Error implements error.
+func (e *RefusedError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the check's error.
+func (e *RefusedError) Unwrap() error {
+	return e.Err
+}
+
+// Options configures Prepare.
+type Options struct {
+	// DryRun only shows the commands: the Kubernetes and cloud guardrails
+	// and the prerequisites aren't checked, and access is recorded as a
+	// view.
+	DryRun bool
+
+	// IgnoreKube and IgnoreCloud skip the Kubernetes and cloud guardrails.
+	IgnoreKube  bool
+	IgnoreCloud bool
+
+	// InstallMissing installs missing prerequisites, confirmed with
+	// Prompter unless Yes is set.
+	InstallMissing bool
+	Yes            bool
+
+	// Prompter, if set, lets the user confirm guardrails that allow it.
+	Prompter Prompter
+
+	// Out receives prerequisite install instructions and, for Run, the
+	// progress of cleanup steps (default os.Stderr).
+	Out io.Writer
+}
+
+// Prepare checks that the workflow at path may run: its review, the
+// Kubernetes and cloud guardrails and its prerequisites. Access to
+// sensitive workflows is recorded in the audit log. A refusal is returned
+// as a *RefusedError.
+func Prepare(ctx context.Context, cfg *config.Config, wf *workflows.Workflow, path string, opts Options) error {
+	if err := prepare(ctx, cfg, wf, path, opts); err != nil {
+		return &RefusedError{Err: err}
+	}
+	return nil
+}
+
+func prepare(ctx context.Context, cfg *config.Config, wf *workflows.Workflow, path string, opts Options) error {
+	if err := CheckReview(cfg, wf, path); err != nil {
+		return err
+	}
+
+	// Dry runs execute nothing, so they may target any cluster or account
+	// and don't need the prerequisites
+	if err := CheckKube(ctx, cfg, wf, opts.IgnoreKube || opts.DryRun, opts.Prompter); err != nil {
+		return err
+	}
+	if err := CheckCloud(ctx, wf, opts.IgnoreCloud || opts.DryRun); err != nil {
+		return err
+	}
+	if opts.DryRun {
+		// A dry run shows the commands without running them
+		return RecordAccess(cfg, wf, audit.ActionView)
+	}
+	if err := CheckRequirements(ctx, cfg, wf, opts.InstallMissing, opts.Yes, opts.Prompter, output(opts.Out)); err != nil {
+		return err
+	}
+	return RecordAccess(cfg, wf, audit.ActionRun)
+}
+
+// output returns out, or os.Stderr if it is nil.
+func output(out io.Writer) io.Writer {
+	if out == nil {
+		return os.Stderr
+	}
+	return out
+}
+
+// RunOptions configures Run.
+type RunOptions struct {
+	Options
+
+	// Params are placeholder values; placeholder defaults fill the rest.
+	Params map[string]string
+
+	// ConfirmDangerous allows dangerous commands to run.
+	ConfirmDangerous bool
+
+	// Repo holds the remote run locks of exclusive workflows when
+	// runner.run_lock is "remote" (default the repo at cfg.Repo.Path).
+	Repo gitrepo.Repo
+
+	// BreakLock takes over the run lock of an exclusive workflow held by
+	// a run that is over.
+	BreakLock bool
+
+	// Record, if set, is called after the steps ran, e.g. to save the run
+	// to history. It isn't called for dry runs.
+	Record func(wf *workflows.Workflow, results []runnerpkg.StepResult, started time.Time, success bool)
+}
+
+// Run runs the workflow at ref without prompting, like 'svf run --yes',
+// once Prepare allows it. An exclusive workflow runs only while it holds
+// its run lock; a *RunningError is returned when another run holds it. A
+// failed step stops the run and is reported in the result, not as an
+// error, and the workflow's cleanup steps run after it.
+func Run(ctx context.Context, cfg *config.Config, wf *workflows.Workflow, ref store.WorkflowRef, opts RunOptions) (runnerpkg.BatchResult, error) {
+	path := ref.Path
+	wf.ResolveCompanions(filepath.Dir(path))
+	if err := Prepare(ctx, cfg, wf, path, opts.Options); err != nil {
+		return runnerpkg.BatchResult{}, err
+	}
+
+	// Dry runs execute nothing, so they don't take run locks
+	if wf.Exclusive && !opts.DryRun {
+		repo := opts.Repo
+		if repo == nil {
+			repo = gitrepo.New(cfg.Repo.Path)
+		}
+		release, err := Lock(ctx, repo, cfg, wf, LockName(ref, wf), opts.BreakLock)
+		if err != nil {
+			return runnerpkg.BatchResult{}, err
+		}
+		defer func() {
+			if err := release(); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to release run lock: %v\n", err)
+			}
+		}()
+	}
+
+	// Cleanup steps run after a failed step, however the run ends
+	var res runnerpkg.BatchResult
+	defer func() {
+		if !opts.DryRun && !res.Success {
+			Cleanup(ctx, cfg, wf, res.Results, res.Params, true, false, output(opts.Out))
+		}
+	}()
+
+	res, err := runnerpkg.RunBatch(ctx, wf, runnerpkg.BatchOptions{
+		Params:           opts.Params,
+		DryRun:           opts.DryRun,
+		CheckDangerous:   cfg.Runner.DangerousCommandWarnings,
+		ConfirmDangerous: opts.ConfirmDangerous,
+		RepoRoot:         cfg.Repo.Path,
+		StepTimeout:      time.Duration(cfg.Runner.StepTimeout) * time.Second,
+	})
+	if err != nil {
+		return res, err
+	}
+	if !opts.DryRun && opts.Record != nil {
+		opts.Record(wf, res.Results, res.StartedAt, res.Success)
+	}
+	return res, nil
+}
@@ -0,0 +1,203 @@
+// Package stats records anonymized workflow usage in the repository.
+//
+// Each run appends one JSON line to .svf/stats/<YYYY-MM>.jsonl. Lines carry
+// only the workflow ID, the day and the outcome, plus a random nonce so
+// identical runs on different machines stay distinct. The files are
+// marked merge=union in .gitattributes so concurrent appends from
+// different clones merge without conflicts.
+package stats
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/chazuruo/svf/internal/gitrepo"
+)
+
+// Dir is the repo-relative directory holding usage stats.
+const Dir = ".svf/stats"
+
+// attributesLine makes git merge stats files by taking lines from both sides.
+const attributesLine = ".svf/stats/*.jsonl merge=union"
+
+// dateLayout is the day granularity events are recorded at.
+const dateLayout = "2006-01-02"
+
+// Event is one recorded run.
+type Event struct {
+	Nonce    string `json:"n"`
+	Workflow string `json:"workflow"`
+	Date     string `json:"date"`
+	Success  bool   `json:"success"`
+}
+
+// Usage summarizes the recorded runs of one workflow.
+type Usage struct {
+	Workflow string
+	Runs     int
+	Failures int
+	LastRun  time.Time
+}
+
+// FailureRate returns the fraction of runs that failed.
+func (u Usage) FailureRate() float64 {
+	if u.Runs == 0 {
+		return 0
+	}
+	return float64(u.Failures) / float64(u.Runs)
+}
+
+// Record appends a run of workflowID to the stats file for the month and
+// makes sure the union merge attribute is set.
+func Record(repoPath, workflowID string, success bool, at time.Time) error {
+	if workflowID == "" {
+		return fmt.Errorf("workflow has no ID")
+	}
+
+	dir := filepath.Join(repoPath, Dir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create stats directory: %w", err)
+	}
+	if err := ensureAttributes(repoPath); err != nil {
+		return err
+	}
+
+	line, err := json.Marshal(Event{
+		Nonce:    uuid.NewString()[:8],
+		Workflow: workflowID,
+		Date:     at.UTC().Format(dateLayout),
+		Success:  success,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal stats event: %w", err)
+	}
+
+	path := filepath.Join(dir, at.UTC().Format("2006-01")+".jsonl")
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open stats file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write stats file: %w", err)
+	}
+	return nil
+}
+
+// ensureAttributes adds the union merge attribute to .gitattributes.
+func ensureAttributes(repoPath string) error {
+	return gitrepo.EnsureAttribute(repoPath, attributesLine)
+}
+
+// Load reads every recorded event. Lines that don't parse, such as
+// leftovers from a bad merge, are skipped.
+func Load(repoPath string) ([]Event, error) {
+	files, err := filepath.Glob(filepath.Join(repoPath, Dir, "*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	var events []Event
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open stats file: %w", err)
+		}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var e Event
+			if err := json.Unmarshal(scanner.Bytes(), &e); err != nil || e.Workflow == "" {
+				continue
+			}
+			events = append(events, e)
+		}
+		err = scanner.Err()
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+	}
+	return events, nil
+}
+
+// Summarize groups events by workflow.
+func Summarize(events []Event) map[string]*Usage {
+	usage := make(map[string]*Usage)
+	for _, e := range events {
+		u, ok := usage[e.Workflow]
+		if !ok {
+			u = &Usage{Workflow: e.Workflow}
+			usage[e.Workflow] = u
+		}
+		u.Runs++
+		if !e.Success {
+			u.Failures++
+		}
+		if day, err := time.Parse(dateLayout, e.Date); err == nil && day.After(u.LastRun) {
+			u.LastRun = day
+		}
+	}
+	return usage
+}
+
+// MostRun returns up to n workflows by run count, most runs first.
+func MostRun(usage map[string]*Usage, n int) []Usage {
+	list := sortedUsage(usage, func(a, b Usage) bool {
+		if a.Runs != b.Runs {
+			return a.Runs > b.Runs
+		}
+		return a.Workflow < b.Workflow
+	})
+	if n > 0 && len(list) > n {
+		list = list[:n]
+	}
+	return list
+}
+
+// NotRunSince returns the workflow IDs with no run on or after cutoff,
+// in the order given.
+func NotRunSince(usage map[string]*Usage, workflowIDs []string, cutoff time.Time) []string {
+	var stale []string
+	for _, id := range workflowIDs {
+		if u, ok := usage[id]; ok && !u.LastRun.Before(cutoff.UTC().Truncate(24*time.Hour)) {
+			continue
+		}
+		stale = append(stale, id)
+	}
+	return stale
+}
+
+// FrequentlyFailing returns workflows with at least minRuns runs whose
+// failure rate is at least rate, worst first.
+func FrequentlyFailing(usage map[string]*Usage, minRuns int, rate float64) []Usage {
+	var failing []Usage
+	for _, u := range sortedUsage(usage, func(a, b Usage) bool {
+		if a.FailureRate() != b.FailureRate() {
+			return a.FailureRate() > b.FailureRate()
+		}
+		return a.Workflow < b.Workflow
+	}) {
+		if u.Runs >= minRuns && u.FailureRate() >= rate {
+			failing = append(failing, u)
+		}
+	}
+	return failing
+}
+
+// sortedUsage returns the usage values sorted by less.
+func sortedUsage(usage map[string]*Usage, less func(a, b Usage) bool) []Usage {
+	list := make([]Usage, 0, len(usage))
+	for _, u := range usage {
+		list = append(list, *u)
+	}
+	sort.Slice(list, func(i, j int) bool { return less(list[i], list[j]) })
+	return list
+}
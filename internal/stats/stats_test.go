@@ -0,0 +1,87 @@
+package stats
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordLoad(t *testing.T) {
+	repo := t.TempDir()
+	day := time.Date(2026, 3, 9, 15, 0, 0, 0, time.UTC)
+
+	require.NoError(t, Record(repo, "wf_a", true, day))
+	require.NoError(t, Record(repo, "wf_a", false, day.AddDate(0, 1, 0)))
+	require.NoError(t, Record(repo, "wf_b", true, day))
+	assert.Error(t, Record(repo, "", true, day))
+
+	// One file per month, union merged
+	files, err := filepath.Glob(filepath.Join(repo, Dir, "*.jsonl"))
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+	attrs, err := os.ReadFile(filepath.Join(repo, ".gitattributes"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(attrs), attributesLine))
+
+	// Garbage lines are skipped
+	f, err := os.OpenFile(files[0], os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	_, _ = f.WriteString("<<<<<<< HEAD\n")
+	require.NoError(t, f.Close())
+
+	events, err := Load(repo)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.Equal(t, "2026-03-09", events[0].Date)
+	assert.NotEqual(t, events[0].Nonce, events[1].Nonce)
+}
+
+func TestEnsureAttributesKeepsExisting(t *testing.T) {
+	repo := t.TempDir()
+	path := filepath.Join(repo, ".gitattributes")
+	require.NoError(t, os.WriteFile(path, []byte("*.png binary"), 0644))
+
+	require.NoError(t, ensureAttributes(repo))
+	require.NoError(t, ensureAttributes(repo))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "*.png binary\n"+attributesLine+"\n", string(data))
+}
+
+func TestReports(t *testing.T) {
+	events := []Event{
+		{Workflow: "deploy", Date: "2026-03-01", Success: true},
+		{Workflow: "deploy", Date: "2026-03-02", Success: true},
+		{Workflow: "deploy", Date: "2026-03-03", Success: false},
+		{Workflow: "flaky", Date: "2026-01-01", Success: false},
+		{Workflow: "flaky", Date: "2026-01-02", Success: false},
+		{Workflow: "flaky", Date: "2026-01-03", Success: true},
+		{Workflow: "rare", Date: "2025-06-01", Success: false},
+	}
+	usage := Summarize(events)
+
+	require.Contains(t, usage, "deploy")
+	assert.Equal(t, 3, usage["deploy"].Runs)
+	assert.Equal(t, 1, usage["deploy"].Failures)
+	assert.Equal(t, time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC), usage["deploy"].LastRun)
+
+	most := MostRun(usage, 2)
+	require.Len(t, most, 2)
+	assert.Equal(t, "deploy", most[0].Workflow)
+	assert.Equal(t, "flaky", most[1].Workflow)
+
+	cutoff := time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, []string{"flaky", "rare", "unused"},
+		NotRunSince(usage, []string{"deploy", "flaky", "rare", "unused"}, cutoff))
+
+	failing := FrequentlyFailing(usage, 3, 0.5)
+	require.Len(t, failing, 1)
+	assert.Equal(t, "flaky", failing[0].Workflow)
+	assert.InDelta(t, 2.0/3.0, failing[0].FailureRate(), 0.001)
+}
@@ -0,0 +1,277 @@
+// Package telemetry records anonymous svf feature usage on the local
+// machine.
+//
+// Nothing is ever uploaded. Usage is aggregated into counters per month in
+// $XDG_STATE_HOME/svf/telemetry.json, defaulting to
+// ~/.local/state/svf/telemetry.json: how often each command ran, which
+// flags were set and how often commands failed. Only command paths and
+// flag names are kept, never arguments, flag values or workflow content.
+// A Report summarizes the counters for users to share if they choose.
+package telemetry
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chazuruo/svf/internal/config"
+)
+
+// What can be recorded; see config's telemetry.record.
+const (
+	Commands = "commands"
+	Flags    = "flags"
+	Errors   = "errors"
+)
+
+// Categories lists everything that can be recorded.
+var Categories = []string{Commands, Flags, Errors}
+
+// monthLayout is the granularity usage is aggregated at.
+const monthLayout = "2006-01"
+
+// Counts are the usage counters of one month, keyed by command path
+// (e.g. "release create") or, for flags, command path and flag name
+// (e.g. "run --dry-run").
+type Counts struct {
+	Commands map[string]int `json:"commands,omitempty"`
+	Flags    map[string]int `json:"flags,omitempty"`
+	Errors   map[string]int `json:"errors,omitempty"`
+}
+
+// Data is the content of the telemetry file.
+type Data struct {
+	// Months maps a month (YYYY-MM) to its counters.
+	Months map[string]*Counts `json:"months"`
+}
+
+// Usage is one use of a command.
+type Usage struct {
+	// Command is the command path without the binary name.
+	Command string
+	// Flags are the names of the flags set, without values.
+	Flags []string
+	// Failed is set when the command returned an error.
+	Failed bool
+	At     time.Time
+}
+
+// DefaultPath returns the default path of the telemetry file.
+func DefaultPath() (string, error) {
+	dir, err := config.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "telemetry.json"), nil
+}
+
+// Store reads and writes usage counters in a file.
+type Store struct {
+	path string
+}
+
+// NewStore creates a store for the file at path.
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// NewDefaultStore creates a store for the default file.
+func NewDefaultStore() (*Store, error) {
+	path, err := DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	return NewStore(path), nil
+}
+
+// Path returns the path of the store's file.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Load returns the recorded counters; empty if nothing was recorded.
+func (s *Store) Load() (*Data, error) {
+	data := &Data{Months: map[string]*Counts{}}
+	content, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return data, nil
+		}
+		return nil, fmt.Errorf("failed to read telemetry: %w", err)
+	}
+	if err := json.Unmarshal(content, data); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", s.path, err)
+	}
+	if data.Months == nil {
+		data.Months = map[string]*Counts{}
+	}
+	return data, nil
+}
+
+// Record adds u to the counters of its month, counting only the
+// categories in include.
+func (s *Store) Record(u Usage, include []string) error {
+	if u.Command == "" || len(include) == 0 {
+		return nil
+	}
+	data, err := s.Load()
+	if err != nil {
+		return err
+	}
+	month := u.At.UTC().Format(monthLayout)
+	counts := data.Months[month]
+	if counts == nil {
+		counts = &Counts{}
+		data.Months[month] = counts
+	}
+
+	if slices.Contains(include, Commands) {
+		increment(&counts.Commands, u.Command)
+	}
+	if slices.Contains(include, Flags) {
+		for _, flag := range u.Flags {
+			increment(&counts.Flags, u.Command+" --"+flag)
+		}
+	}
+	if slices.Contains(include, Errors) && u.Failed {
+		increment(&counts.Errors, u.Command)
+	}
+	return s.write(data)
+}
+
+// Clear deletes everything recorded.
+func (s *Store) Clear() error {
+	if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to clear telemetry: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) write(data *Data) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("failed to create telemetry directory: %w", err)
+	}
+	content, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal telemetry: %w", err)
+	}
+	if err := os.WriteFile(s.path, append(content, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write telemetry: %w", err)
+	}
+	return nil
+}
+
+func increment(m *map[string]int, key string) {
+	if *m == nil {
+		*m = map[string]int{}
+	}
+	(*m)[key]++
+}
+
+// Count is a counter in a report.
+type Count struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// Report summarizes the counters of a range of months.
+type Report struct {
+	// From and To are the first and last month with usage (YYYY-MM).
+	From     string  `json:"from,omitempty"`
+	To       string  `json:"to,omitempty"`
+	Version  string  `json:"version"`
+	Platform string  `json:"platform"`
+	Commands []Count `json:"commands"`
+	Flags    []Count `json:"flags"`
+	Errors   []Count `json:"errors"`
+}
+
+// Summarize totals the counters of the months from since (YYYY-MM; empty
+// for all) on. version and platform describe the svf build.
+func Summarize(data *Data, since, version, platform string) *Report {
+	report := &Report{Version: version, Platform: platform}
+	commands, flags, errors := map[string]int{}, map[string]int{}, map[string]int{}
+	for month, counts := range data.Months {
+		if month < since || counts == nil {
+			continue
+		}
+		if report.From == "" || month < report.From {
+			report.From = month
+		}
+		if month > report.To {
+			report.To = month
+		}
+		add(commands, counts.Commands)
+		add(flags, counts.Flags)
+		add(errors, counts.Errors)
+	}
+	report.Commands = sortedCounts(commands)
+	report.Flags = sortedCounts(flags)
+	report.Errors = sortedCounts(errors)
+	return report
+}
+
+func add(total, counts map[string]int) {
+	for name, n := range counts {
+		total[name] += n
+	}
+}
+
+// sortedCounts orders counters by count, highest first, then by name.
+func sortedCounts(m map[string]int) []Count {
+	counts := make([]Count, 0, len(m))
+	for name, n := range m {
+		counts = append(counts, Count{Name: name, Count: n})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Name < counts[j].Name
+	})
+	return counts
+}
+
+// Empty reports whether the report has no usage.
+func (r *Report) Empty() bool {
+	return len(r.Commands) == 0 && len(r.Flags) == 0 && len(r.Errors) == 0
+}
+
+// Text renders the report for reading and pasting into an issue.
+func (r *Report) Text() string {
+	var b strings.Builder
+	period := "no usage recorded"
+	if r.From != "" {
+		period = r.From
+		if r.To != r.From {
+			period += " to " + r.To
+		}
+	}
+	fmt.Fprintf(&b, "svf usage, %s (svf %s, %s)\n", period, r.Version, r.Platform)
+	for _, section := range []struct {
+		title  string
+		counts []Count
+	}{
+		{"Commands", r.Commands},
+		{"Flags", r.Flags},
+		{"Errors", r.Errors},
+	} {
+		if len(section.counts) == 0 {
+			continue
+		}
+		width := 0
+		for _, c := range section.counts {
+			width = max(width, len(c.Name))
+		}
+		fmt.Fprintf(&b, "\n%s:\n", section.title)
+		for _, c := range section.counts {
+			fmt.Fprintf(&b, "  %-*s  %d\n", width, c.Name, c.Count)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,48 @@
+package telemetry
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreRecord(t *testing.T) {
+	s := NewStore(filepath.Join(t.TempDir(), "svf", "telemetry.json"))
+	sep := time.Date(2026, 9, 30, 12, 0, 0, 0, time.UTC)
+	oct := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	require.NoError(t, s.Record(Usage{Command: "run", Flags: []string{"dry-run"}, At: sep}, Categories))
+	require.NoError(t, s.Record(Usage{Command: "run", Failed: true, At: oct}, Categories))
+	require.NoError(t, s.Record(Usage{Command: "sync", Flags: []string{"strategy"}, Failed: true, At: oct}, []string{Commands}))
+	require.NoError(t, s.Record(Usage{Command: "search", At: oct}, nil))
+
+	data, err := s.Load()
+	require.NoError(t, err)
+	require.Len(t, data.Months, 2)
+	assert.Equal(t, map[string]int{"run": 1, "sync": 1}, data.Months["2026-10"].Commands)
+	assert.Equal(t, map[string]int{"run": 1}, data.Months["2026-10"].Errors)
+	assert.Empty(t, data.Months["2026-10"].Flags, "flags of sync weren't to be recorded")
+	assert.Equal(t, map[string]int{"run --dry-run": 1}, data.Months["2026-09"].Flags)
+
+	report := Summarize(data, "", "1.2.3", "linux/amd64")
+	assert.Equal(t, "2026-09", report.From)
+	assert.Equal(t, "2026-10", report.To)
+	assert.Equal(t, []Count{{"run", 2}, {"sync", 1}}, report.Commands)
+	assert.Equal(t, "svf usage, 2026-09 to 2026-10 (svf 1.2.3, linux/amd64)\n\n"+
+		"Commands:\n  run   2\n  sync  1\n\n"+
+		"Flags:\n  run --dry-run  1\n\n"+
+		"Errors:\n  run  1\n", report.Text())
+
+	report = Summarize(data, "2026-10", "1.2.3", "linux/amd64")
+	assert.Equal(t, "2026-10", report.From)
+	assert.Empty(t, report.Flags)
+
+	require.NoError(t, s.Clear())
+	data, err = s.Load()
+	require.NoError(t, err)
+	assert.True(t, Summarize(data, "", "1.2.3", "linux/amd64").Empty())
+	require.NoError(t, s.Clear(), "clearing twice is fine")
+}
@@ -0,0 +1,1054 @@
+package testutil
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chazuruo/svf/internal/clock"
+	"github.com/chazuruo/svf/internal/gitrepo"
+)
+
+// FakeCommit is a commit recorded by a FakeRepo.
+type FakeCommit struct {
+	Hash    string
+	Parent  string
+	Message string
+	Author  string // The user.email config value when committed
+	Time    time.Time
+	Files   map[string][]byte // Repo-relative paths (slash-separated) to content
+}
+
+// FakeRepo is an in-memory gitrepo.Repo. The working tree is the directory
+// at Path, as the store reads and writes files there, but the index,
+// commits, branches and remotes exist only in memory, so tests run without
+// git.
+//
+// Remote operations return the canned FetchResult and IntegrateResult, and
+// any method can be made to fail by setting Errors[method name].
+type FakeRepo struct {
+	// FetchResult is returned by Fetch.
+	FetchResult gitrepo.FetchResult
+	// IntegrateResult is returned by Integrate.
+	IntegrateResult gitrepo.IntegrateResult
+	// Errors makes the named methods, like "Push", return an error.
+	Errors map[string]error
+	// Pushed records "remote/branch" for each Push.
+	Pushed []string
+
+	mu          sync.Mutex
+	path        string
+	clock       clock.Clock
+	initialized bool
+	branch      string
+	branches    map[string]string // Branch name to head commit hash
+	commits     map[string]*FakeCommit
+	index       map[string][]byte // Staged content
+	config      map[string]string
+	conflicts   []string
+	details     map[string]gitrepo.ConflictDetails // Conflicted path to its versions
+	mergeOp     gitrepo.MergeOperation
+	worktrees   map[string]string // Worktree path to branch
+	tags        []fakeTag         // Oldest first
+	locks       map[string][]byte // Lock name to content, on any remote
+}
+
+// fakeTag is a tag and the commit it points at.
+type fakeTag struct {
+	name string
+	hash string
+}
+
+// NewFakeRepo returns an initialized fake repository whose working tree is
+// path, on branch "main" with no commits. An empty .git directory is
+// created so that files svf keeps out of commits, like the lock, go there.
+func NewFakeRepo(path string) *FakeRepo {
+	r := &FakeRepo{
+		Errors:    make(map[string]error),
+		path:      path,
+		clock:     clock.Real,
+		branches:  make(map[string]string),
+		commits:   make(map[string]*FakeCommit),
+		index:     make(map[string][]byte),
+		config:    make(map[string]string),
+		details:   make(map[string]gitrepo.ConflictDetails),
+		worktrees: make(map[string]string),
+		locks:     make(map[string][]byte),
+	}
+	r.init("main")
+	_ = os.MkdirAll(filepath.Join(path, ".git"), 0755)
+	return r
+}
+
+// SetClock sets the clock used for commit times.
+func (r *FakeRepo) SetClock(c clock.Clock) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clock = c
+}
+
+// SetConfig sets a git config value returned by GetConfig.
+func (r *FakeRepo) SetConfig(key, value string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.config[key] = value
+}
+
+// SetConflicts marks paths as conflicted until they are resolved, as in
+// a merge when none is in progress.
+func (r *FakeRepo) SetConflicts(paths ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.conflicts = append([]string(nil), paths...)
+	if len(paths) > 0 && r.mergeOp == gitrepo.OpNone {
+		r.mergeOp = gitrepo.OpMerge
+	}
+}
+
+// SetConflictDetails sets the versions GetConflictDetails returns for a
+// conflicted path and ResolveFile checks out.
+func (r *FakeRepo) SetConflictDetails(details gitrepo.ConflictDetails) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.details[details.Path] = details
+}
+
+// Commits returns the current branch's commits, newest first.
+func (r *FakeRepo) Commits() []FakeCommit {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var commits []FakeCommit
+	for hash := r.branches[r.branch]; hash != ""; hash = r.commits[hash].Parent {
+		commits = append(commits, *r.commits[hash])
+	}
+	return commits
+}
+
+// fail returns the injected error for method, if any.
+func (r *FakeRepo) fail(method string) error {
+	if err := r.Errors[method]; err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	return nil
+}
+
+// Path returns the repository path.
+func (r *FakeRepo) Path() string {
+	return r.path
+}
+
+// Init (re)initializes the repository with an empty history.
+func (r *FakeRepo) Init(ctx context.Context, opts gitrepo.InitOptions) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.fail("Init"); err != nil {
+		return err
+	}
+	branch := opts.DefaultBranch
+	if branch == "" {
+		branch = "main"
+	}
+	r.init(branch)
+	return os.MkdirAll(filepath.Join(r.path, ".git"), 0755)
+}
+
+func (r *FakeRepo) init(branch string) {
+	r.initialized = true
+	r.branch = branch
+	r.branches = map[string]string{branch: ""}
+	r.commits = make(map[string]*FakeCommit)
+	r.index = make(map[string][]byte)
+}
+
+// Status compares the working tree, index and HEAD like git status.
+func (r *FakeRepo) Status(ctx context.Context) (gitrepo.Status, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	status := gitrepo.Status{Branch: r.branch}
+	if err := r.fail("Status"); err != nil {
+		return status, err
+	}
+
+	worktree, err := r.readTree()
+	if err != nil {
+		return status, err
+	}
+	head := r.headFiles()
+
+	paths := make(map[string]bool)
+	for _, files := range []map[string][]byte{worktree, r.index, head} {
+		for p := range files {
+			paths[p] = true
+		}
+	}
+
+	for _, p := range sortedKeys(paths) {
+		entry := gitrepo.StatusEntry{Path: p, X: '.', Y: '.'}
+		_, inIndex := r.index[p]
+		if !inIndex {
+			if _, ok := worktree[p]; ok {
+				entry.X, entry.Y = '?', '?'
+			}
+		} else {
+			entry.X = changeCode(head, r.index, p)
+			entry.Y = changeCode(r.index, worktree, p)
+		}
+		if entry.X == '.' && entry.Y == '.' {
+			continue
+		}
+		status.Entries = append(status.Entries, entry)
+	}
+	for _, p := range r.conflicts {
+		status.Entries = append(status.Entries, gitrepo.StatusEntry{Path: p, X: 'U', Y: 'U'})
+	}
+	status.Dirty = len(status.Entries) > 0
+	status.Conflicted = len(r.conflicts) > 0
+	status.Conflicts = append([]string(nil), r.conflicts...)
+	return status, nil
+}
+
+// changeCode returns git's status letter for path going from one tree to
+// another: A(dded), D(eleted), M(odified) or '.' for unchanged.
+func changeCode(from, to map[string][]byte, path string) byte {
+	before, inFrom := from[path]
+	after, inTo := to[path]
+	switch {
+	case !inFrom && inTo:
+		return 'A'
+	case inFrom && !inTo:
+		return 'D'
+	case inFrom && !bytes.Equal(before, after):
+		return 'M'
+	}
+	return '.'
+}
+
+// IsInitialized reports whether Init has been called.
+func (r *FakeRepo) IsInitialized(ctx context.Context) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.initialized
+}
+
+// Close does nothing.
+func (r *FakeRepo) Close() error {
+	return nil
+}
+
+// Add stages a file, or its removal if it no longer exists. Like git,
+// adding a directory stages every change below it.
+func (r *FakeRepo) Add(ctx context.Context, path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.fail("Add"); err != nil {
+		return err
+	}
+
+	rel := path
+	if filepath.IsAbs(path) {
+		var err error
+		if rel, err = filepath.Rel(r.path, path); err != nil {
+			return err
+		}
+	}
+	rel = filepath.ToSlash(filepath.Clean(rel))
+
+	if info, err := os.Stat(filepath.Join(r.path, filepath.FromSlash(rel))); err == nil && info.IsDir() {
+		worktree, err := r.readTree()
+		if err != nil {
+			return err
+		}
+		prefix := rel + "/"
+		for p := range r.index {
+			if _, ok := worktree[p]; !ok && strings.HasPrefix(p, prefix) {
+				delete(r.index, p)
+			}
+		}
+		for p, data := range worktree {
+			if strings.HasPrefix(p, prefix) {
+				r.index[p] = data
+				r.resolve(p)
+			}
+		}
+		return nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(r.path, filepath.FromSlash(rel)))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		delete(r.index, rel)
+	case err != nil:
+		return err
+	default:
+		r.index[rel] = data
+	}
+	r.resolve(rel)
+	return nil
+}
+
+// AddAll stages the whole working tree.
+func (r *FakeRepo) AddAll(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.fail("AddAll"); err != nil {
+		return err
+	}
+	worktree, err := r.readTree()
+	if err != nil {
+		return err
+	}
+	r.index = worktree
+	r.conflicts = nil
+	return nil
+}
+
+// CommitAll commits the index. Like git, it fails when nothing is staged.
+func (r *FakeRepo) CommitAll(ctx context.Context, message string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.fail("CommitAll"); err != nil {
+		return "", err
+	}
+
+	head := r.headFiles()
+	if len(changedPaths(head, r.index)) == 0 {
+		return "", errors.New("nothing to commit, working tree clean")
+	}
+	return r.commit(message), nil
+}
+
+// commit records the index as a new commit on the current branch.
+func (r *FakeRepo) commit(message string) string {
+	parent := r.branches[r.branch]
+	files := make(map[string][]byte, len(r.index))
+	for p, data := range r.index {
+		files[p] = data
+	}
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s\x00%s\x00%d", parent, message, len(r.commits))))
+	commit := &FakeCommit{
+		Hash:    hex.EncodeToString(sum[:]),
+		Parent:  parent,
+		Message: message,
+		Author:  r.config["user.email"],
+		Time:    r.clock.Now(),
+		Files:   files,
+	}
+	r.commits[commit.Hash] = commit
+	r.branches[r.branch] = commit.Hash
+	return commit.Hash
+}
+
+// AmendCommit replaces the branch's last commit with one holding the
+// index. An empty message keeps the last commit's message.
+func (r *FakeRepo) AmendCommit(ctx context.Context, message string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.fail("AmendCommit"); err != nil {
+		return "", err
+	}
+
+	last := r.commits[r.branches[r.branch]]
+	if last == nil {
+		return "", errors.New("nothing to amend: no commits yet")
+	}
+	if message == "" {
+		message = last.Message
+	}
+	files := make(map[string][]byte, len(r.index))
+	for p, data := range r.index {
+		files[p] = data
+	}
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s\x00%s\x00%d", last.Parent, message, len(r.commits))))
+	commit := &FakeCommit{
+		Hash:    hex.EncodeToString(sum[:]),
+		Parent:  last.Parent,
+		Message: message,
+		Author:  last.Author,
+		Time:    r.clock.Now(),
+		Files:   files,
+	}
+	r.commits[commit.Hash] = commit
+	r.branches[r.branch] = commit.Hash
+	return commit.Hash, nil
+}
+
+// GetCurrentBranch returns the current branch name.
+func (r *FakeRepo) GetCurrentBranch(ctx context.Context) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.fail("GetCurrentBranch"); err != nil {
+		return "", err
+	}
+	return r.branch, nil
+}
+
+// GetConfig returns a value set with SetConfig.
+func (r *FakeRepo) GetConfig(ctx context.Context, key string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	value, ok := r.config[key]
+	if !ok {
+		return "", fmt.Errorf("config %s is not set", key)
+	}
+	return value, nil
+}
+
+// HasConflicts reports whether any paths are conflicted.
+func (r *FakeRepo) HasConflicts(ctx context.Context) (bool, error) {
+	conflicts, err := r.GetConflicts(ctx)
+	return len(conflicts) > 0, err
+}
+
+// GetConflicts returns the paths set with SetConflicts that are not yet
+// resolved.
+func (r *FakeRepo) GetConflicts(ctx context.Context) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.fail("GetConflicts"); err != nil {
+		return nil, err
+	}
+	return append([]string{}, r.conflicts...), nil
+}
+
+// GetMergeState returns the operation left by Integrate or SetConflicts
+// and the unresolved paths.
+func (r *FakeRepo) GetMergeState(ctx context.Context) (gitrepo.MergeState, error) {
+	conflicts, err := r.GetConflicts(ctx)
+	if err != nil {
+		return gitrepo.MergeState{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return gitrepo.MergeState{Operation: r.mergeOp, Conflicts: conflicts}, nil
+}
+
+// GetConflictDetails returns the versions set with SetConflictDetails and
+// the working tree file.
+func (r *FakeRepo) GetConflictDetails(ctx context.Context, path string) (gitrepo.ConflictDetails, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.fail("GetConflictDetails"); err != nil {
+		return gitrepo.ConflictDetails{}, err
+	}
+	conflicted := false
+	for _, p := range r.conflicts {
+		conflicted = conflicted || p == path
+	}
+	if !conflicted {
+		return gitrepo.ConflictDetails{}, fmt.Errorf("%s: %w", path, gitrepo.ErrNotConflicted)
+	}
+
+	details := r.details[path]
+	details.Path = path
+	working, err := os.ReadFile(filepath.Join(r.path, filepath.FromSlash(path)))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return details, err
+	}
+	details.Working = working
+	return details, nil
+}
+
+// ResolveFile writes the chosen side set with SetConflictDetails to the
+// working tree, if any, and stages the path.
+func (r *FakeRepo) ResolveFile(ctx context.Context, path string, resolution gitrepo.Resolution) error {
+	r.mu.Lock()
+	details, known := r.details[path]
+	r.mu.Unlock()
+
+	var content []byte
+	switch resolution {
+	case gitrepo.ResolveOurs:
+		content = details.Ours
+	case gitrepo.ResolveTheirs:
+		content = details.Theirs
+	case gitrepo.ResolveManual:
+		known = false
+	default:
+		return fmt.Errorf("unknown resolution: %s", resolution)
+	}
+
+	if known {
+		full := filepath.Join(r.path, filepath.FromSlash(path))
+		if content == nil {
+			if err := os.Remove(full); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+		} else if err := os.WriteFile(full, content, 0644); err != nil {
+			return err
+		}
+	}
+	return r.Add(ctx, path)
+}
+
+// ContinueMerge commits the index once no conflicts remain.
+func (r *FakeRepo) ContinueMerge(ctx context.Context) error {
+	return r.finishMerge(gitrepo.OpMerge, "ContinueMerge", true)
+}
+
+// ContinueRebase commits the index once no conflicts remain.
+func (r *FakeRepo) ContinueRebase(ctx context.Context) error {
+	return r.finishMerge(gitrepo.OpRebase, "ContinueRebase", true)
+}
+
+// AbortMerge forgets the merge and its conflicts.
+func (r *FakeRepo) AbortMerge(ctx context.Context) error {
+	return r.finishMerge(gitrepo.OpMerge, "AbortMerge", false)
+}
+
+// AbortRebase forgets the rebase and its conflicts.
+func (r *FakeRepo) AbortRebase(ctx context.Context) error {
+	return r.finishMerge(gitrepo.OpRebase, "AbortRebase", false)
+}
+
+// finishMerge ends the op in progress, committing the index if commit is
+// set or dropping the conflicts otherwise.
+func (r *FakeRepo) finishMerge(op gitrepo.MergeOperation, method string, commit bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.fail(method); err != nil {
+		return err
+	}
+	if r.mergeOp != op {
+		return fmt.Errorf("no %s in progress", op)
+	}
+	if commit {
+		if len(r.conflicts) > 0 {
+			return fmt.Errorf("unresolved conflicts: %s", strings.Join(r.conflicts, ", "))
+		}
+		r.commit(fmt.Sprintf("Finish %s", op))
+	}
+	r.conflicts = nil
+	r.details = make(map[string]gitrepo.ConflictDetails)
+	r.mergeOp = gitrepo.OpNone
+	return nil
+}
+
+// resolve removes path from the conflicts.
+func (r *FakeRepo) resolve(path string) {
+	for i, p := range r.conflicts {
+		if p == path {
+			r.conflicts = append(r.conflicts[:i], r.conflicts[i+1:]...)
+			return
+		}
+	}
+}
+
+// Fetch returns FetchResult.
+func (r *FakeRepo) Fetch(ctx context.Context, remote string) (gitrepo.FetchResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.fail("Fetch"); err != nil {
+		return gitrepo.FetchResult{}, err
+	}
+	return r.FetchResult, nil
+}
+
+// Integrate returns IntegrateResult, marking its ConflictFiles conflicted.
+func (r *FakeRepo) Integrate(ctx context.Context, strategy gitrepo.IntegrateStrategy) (gitrepo.IntegrateResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.fail("Integrate"); err != nil {
+		return gitrepo.IntegrateResult{}, err
+	}
+	if r.IntegrateResult.Conflicts {
+		r.conflicts = append([]string(nil), r.IntegrateResult.ConflictFiles...)
+		r.mergeOp = gitrepo.OpMerge
+		if strategy == gitrepo.StrategyRebase {
+			r.mergeOp = gitrepo.OpRebase
+		}
+	}
+	return r.IntegrateResult, nil
+}
+
+// CreateBranch creates a branch at HEAD and switches to it.
+func (r *FakeRepo) CreateBranch(ctx context.Context, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.fail("CreateBranch"); err != nil {
+		return err
+	}
+	if _, ok := r.branches[name]; ok {
+		return fmt.Errorf("a branch named '%s' already exists", name)
+	}
+	r.branches[name] = r.branches[r.branch]
+	r.branch = name
+	return nil
+}
+
+// Checkout switches to an existing branch. The working tree is left as is.
+func (r *FakeRepo) Checkout(ctx context.Context, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.fail("Checkout"); err != nil {
+		return err
+	}
+	if _, ok := r.branches[name]; !ok {
+		return fmt.Errorf("pathspec '%s' did not match any branch", name)
+	}
+	r.branch = name
+	r.index = r.headFiles()
+	return nil
+}
+
+// Push records the push in Pushed.
+func (r *FakeRepo) Push(ctx context.Context, remote, branch string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.fail("Push"); err != nil {
+		return err
+	}
+	r.Pushed = append(r.Pushed, remote+"/"+branch)
+	return nil
+}
+
+// AddWorktree creates a branch at start, or HEAD if start is empty, and
+// writes its files to path.
+func (r *FakeRepo) AddWorktree(ctx context.Context, path, branch, start string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.fail("AddWorktree"); err != nil {
+		return err
+	}
+	if _, ok := r.branches[branch]; ok {
+		return fmt.Errorf("a branch named '%s' already exists", branch)
+	}
+	hash := r.branches[r.branch]
+	if start != "" {
+		var err error
+		if hash, err = r.resolveRev(start); err != nil {
+			return err
+		}
+	}
+	var files map[string][]byte
+	if commit := r.commits[hash]; commit != nil {
+		files = commit.Files
+	}
+	for p, data := range files {
+		dest := filepath.Join(path, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(dest, data, 0644); err != nil {
+			return err
+		}
+	}
+	r.branches[branch] = hash
+	r.worktrees[path] = branch
+	return nil
+}
+
+// RemoveWorktree deletes a worktree's directory.
+func (r *FakeRepo) RemoveWorktree(ctx context.Context, path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.fail("RemoveWorktree"); err != nil {
+		return err
+	}
+	if _, ok := r.worktrees[path]; !ok {
+		return fmt.Errorf("'%s' is not a working tree", path)
+	}
+	delete(r.worktrees, path)
+	return os.RemoveAll(path)
+}
+
+// PruneWorktrees forgets worktrees whose directories are gone.
+func (r *FakeRepo) PruneWorktrees(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for path := range r.worktrees {
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			delete(r.worktrees, path)
+		}
+	}
+	return nil
+}
+
+// ResolveRev resolves HEAD, a branch or tag name or a commit hash prefix.
+func (r *FakeRepo) ResolveRev(ctx context.Context, rev string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.resolveRev(rev)
+}
+
+func (r *FakeRepo) resolveRev(rev string) (string, error) {
+	hash := ""
+	if rev == "HEAD" {
+		hash = r.branches[r.branch]
+	} else if h, ok := r.branches[rev]; ok {
+		hash = h
+	} else if h := r.tagHash(rev); h != "" {
+		hash = h
+	} else if len(rev) >= 4 {
+		for h := range r.commits {
+			if strings.HasPrefix(h, rev) {
+				hash = h
+				break
+			}
+		}
+	}
+	if hash == "" {
+		return "", fmt.Errorf("unknown revision %q", rev)
+	}
+	return hash, nil
+}
+
+// MergeBase returns the closest ancestor of b that is also an ancestor
+// of a.
+func (r *FakeRepo) MergeBase(ctx context.Context, a, b string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	aHash, err := r.resolveRev(a)
+	if err != nil {
+		return "", err
+	}
+	bHash, err := r.resolveRev(b)
+	if err != nil {
+		return "", err
+	}
+	ancestors := map[string]bool{}
+	for h := aHash; h != ""; h = r.commits[h].Parent {
+		ancestors[h] = true
+	}
+	for h := bHash; h != ""; h = r.commits[h].Parent {
+		if ancestors[h] {
+			return h, nil
+		}
+	}
+	return "", fmt.Errorf("%s and %s have no common ancestor", a, b)
+}
+
+// ShowFile returns a file's content at a revision.
+func (r *FakeRepo) ShowFile(ctx context.Context, rev, path string) ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	hash, err := r.resolveRev(rev)
+	if err != nil {
+		return nil, err
+	}
+	data, ok := r.commits[hash].Files[filepath.ToSlash(path)]
+	if !ok {
+		return nil, fmt.Errorf("%s:%s: %w", rev, filepath.ToSlash(path), os.ErrNotExist)
+	}
+	return data, nil
+}
+
+// ChangedFiles returns the paths that differ between two revisions.
+func (r *FakeRepo) ChangedFiles(ctx context.Context, from, to string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fromHash, err := r.resolveRev(from)
+	if err != nil {
+		return nil, err
+	}
+	toHash, err := r.resolveRev(to)
+	if err != nil {
+		return nil, err
+	}
+	return changedPaths(r.commits[fromHash].Files, r.commits[toHash].Files), nil
+}
+
+// ListFiles returns the paths under dir at a revision, sorted.
+func (r *FakeRepo) ListFiles(ctx context.Context, rev, dir string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	hash, err := r.resolveRev(rev)
+	if err != nil {
+		return nil, err
+	}
+	prefix := ""
+	if d := filepath.ToSlash(filepath.Clean(dir)); d != "." {
+		prefix = d + "/"
+	}
+	paths := []string{}
+	for p := range r.commits[hash].Files {
+		if strings.HasPrefix(p, prefix) {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// Tag tags HEAD. The message is ignored.
+func (r *FakeRepo) Tag(ctx context.Context, name, message string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.fail("Tag"); err != nil {
+		return err
+	}
+	if r.tagHash(name) != "" {
+		return fmt.Errorf("tag '%s' already exists", name)
+	}
+	head := r.branches[r.branch]
+	if head == "" {
+		return fmt.Errorf("failed to resolve 'HEAD' as a valid ref")
+	}
+	r.tags = append(r.tags, fakeTag{name: name, hash: head})
+	return nil
+}
+
+// ListTags returns the tags, newest first.
+func (r *FakeRepo) ListTags(ctx context.Context) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.fail("ListTags"); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(r.tags))
+	for i := len(r.tags) - 1; i >= 0; i-- {
+		names = append(names, r.tags[i].name)
+	}
+	return names, nil
+}
+
+// PushLock takes a lock unless it is already taken. The remote is
+// ignored: all remotes share the same locks.
+func (r *FakeRepo) PushLock(ctx context.Context, remote, name string, content []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.fail("PushLock"); err != nil {
+		return err
+	}
+	if _, ok := r.locks[name]; ok {
+		return fmt.Errorf("%s: %w", name, gitrepo.ErrLockHeld)
+	}
+	r.locks[name] = append([]byte(nil), content...)
+	return nil
+}
+
+// ReadLock returns the content of a taken lock.
+func (r *FakeRepo) ReadLock(ctx context.Context, remote, name string) ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.fail("ReadLock"); err != nil {
+		return nil, err
+	}
+	content, ok := r.locks[name]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", name, os.ErrNotExist)
+	}
+	return content, nil
+}
+
+// DeleteLock releases a lock.
+func (r *FakeRepo) DeleteLock(ctx context.Context, remote, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.fail("DeleteLock"); err != nil {
+		return err
+	}
+	delete(r.locks, name)
+	return nil
+}
+
+// tagHash returns the commit a tag points at, or "" if there is no such tag.
+// PathAuthors returns the distinct authors of the commits on the current
+// branch changing files at or under path, most recent first.
+func (r *FakeRepo) PathAuthors(ctx context.Context, path string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.fail("PathAuthors"); err != nil {
+		return nil, err
+	}
+	var authors []string
+	for _, c := range r.history(r.branches[r.branch]) {
+		if len(r.touched(c, path)) > 0 && c.Author != "" && !slices.Contains(authors, c.Author) {
+			authors = append(authors, c.Author)
+		}
+	}
+	return authors, nil
+}
+
+// PathBranches returns the branches with commits changing files at or
+// under path, by their most recent such commit.
+func (r *FakeRepo) PathBranches(ctx context.Context, path string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.fail("PathBranches"); err != nil {
+		return nil, err
+	}
+	latest := make(map[string]time.Time)
+	for branch, head := range r.branches {
+		for _, c := range r.history(head) {
+			if len(r.touched(c, path)) > 0 {
+				latest[branch] = c.Time
+				break
+			}
+		}
+	}
+	branches := make([]string, 0, len(latest))
+	for branch := range latest {
+		branches = append(branches, branch)
+	}
+	sort.Slice(branches, func(i, j int) bool {
+		if !latest[branches[i]].Equal(latest[branches[j]]) {
+			return latest[branches[i]].After(latest[branches[j]])
+		}
+		return branches[i] < branches[j]
+	})
+	return branches, nil
+}
+
+// LastCommitTimes returns when each file under paths was last changed on
+// the current branch.
+func (r *FakeRepo) LastCommitTimes(ctx context.Context, paths ...string) (map[string]time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.fail("LastCommitTimes"); err != nil {
+		return nil, err
+	}
+	times := make(map[string]time.Time)
+	for _, c := range r.history(r.branches[r.branch]) {
+		for _, p := range r.touched(c, paths...) {
+			if _, ok := times[p]; !ok {
+				if _, exists := c.Files[p]; exists {
+					times[p] = c.Time
+				}
+			}
+		}
+	}
+	return times, nil
+}
+
+// FileChanges returns the changes the commits on the current branch made
+// to the files named one of names under dirs, oldest first.
+func (r *FakeRepo) FileChanges(ctx context.Context, names []string, dirs ...string) ([]gitrepo.FileChange, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.fail("FileChanges"); err != nil {
+		return nil, err
+	}
+	history := r.history(r.branches[r.branch])
+	slices.Reverse(history)
+	var changes []gitrepo.FileChange
+	for _, c := range history {
+		var parent map[string][]byte
+		if p := r.commits[c.Parent]; p != nil {
+			parent = p.Files
+		}
+		for _, p := range r.touched(c, dirs...) {
+			if slices.Contains(names, path.Base(p)) {
+				changes = append(changes, gitrepo.FileChange{Path: p, Time: c.Time, Before: parent[p], After: c.Files[p]})
+			}
+		}
+	}
+	return changes, nil
+}
+
+// history returns the commits reachable from hash, newest first.
+func (r *FakeRepo) history(hash string) []*FakeCommit {
+	var commits []*FakeCommit
+	for c := r.commits[hash]; c != nil; c = r.commits[c.Parent] {
+		commits = append(commits, c)
+	}
+	return commits
+}
+
+// touched returns the paths at or under paths that commit c changed.
+func (r *FakeRepo) touched(c *FakeCommit, paths ...string) []string {
+	var parent map[string][]byte
+	if p := r.commits[c.Parent]; p != nil {
+		parent = p.Files
+	}
+	var touched []string
+	for _, changed := range changedPaths(parent, c.Files) {
+		for _, p := range paths {
+			p = filepath.ToSlash(filepath.Clean(p))
+			if p == "." || changed == p || strings.HasPrefix(changed, p+"/") {
+				touched = append(touched, changed)
+				break
+			}
+		}
+	}
+	return touched
+}
+
+func (r *FakeRepo) tagHash(name string) string {
+	for _, t := range r.tags {
+		if t.name == name {
+			return t.hash
+		}
+	}
+	return ""
+}
+
+// headFiles returns a copy of the files at HEAD.
+func (r *FakeRepo) headFiles() map[string][]byte {
+	files := make(map[string][]byte)
+	if commit := r.commits[r.branches[r.branch]]; commit != nil {
+		for p, data := range commit.Files {
+			files[p] = data
+		}
+	}
+	return files
+}
+
+// readTree reads the working tree, skipping .git.
+func (r *FakeRepo) readTree() (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	err := filepath.WalkDir(r.path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(r.path, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = data
+		return nil
+	})
+	return files, err
+}
+
+// changedPaths returns the sorted paths whose content differs.
+func changedPaths(from, to map[string][]byte) []string {
+	changed := make(map[string]bool)
+	for p, data := range from {
+		if other, ok := to[p]; !ok || !bytes.Equal(data, other) {
+			changed[p] = true
+		}
+	}
+	for p := range to {
+		if _, ok := from[p]; !ok {
+			changed[p] = true
+		}
+	}
+	return sortedKeys(changed)
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Verify FakeRepo implements gitrepo.Repo.
+var _ gitrepo.Repo = (*FakeRepo)(nil)
@@ -0,0 +1,114 @@
+package testutil
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFakeRepo(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	repo := NewFakeRepo(dir)
+	clk := NewFakeClock(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	repo.SetClock(clk)
+
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("a.txt", "one")
+	status, err := repo.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if !status.Dirty || len(status.Entries) != 1 || status.Entries[0].X != '?' {
+		t.Fatalf("Status() = %+v, want a.txt untracked", status)
+	}
+
+	if _, err := repo.CommitAll(ctx, "empty"); err == nil {
+		t.Error("CommitAll() with nothing staged succeeded")
+	}
+	if err := repo.Add(ctx, filepath.Join(dir, "a.txt")); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	first, err := repo.CommitAll(ctx, "add a")
+	if err != nil {
+		t.Fatalf("CommitAll() error = %v", err)
+	}
+	if status, _ := repo.Status(ctx); status.Dirty {
+		t.Errorf("Status() after commit = %+v, want clean", status)
+	}
+
+	clk.Advance(time.Hour)
+	write("a.txt", "two")
+	write("b.txt", "new")
+	if err := repo.AddAll(ctx); err != nil {
+		t.Fatalf("AddAll() error = %v", err)
+	}
+	second, err := repo.CommitAll(ctx, "update")
+	if err != nil {
+		t.Fatalf("CommitAll() error = %v", err)
+	}
+
+	commits := repo.Commits()
+	if len(commits) != 2 || commits[0].Hash != second || commits[0].Parent != first {
+		t.Fatalf("Commits() = %+v", commits)
+	}
+	if !commits[0].Time.Equal(clk.Now()) {
+		t.Errorf("commit time = %v, want %v", commits[0].Time, clk.Now())
+	}
+
+	data, err := repo.ShowFile(ctx, first, "a.txt")
+	if err != nil || string(data) != "one" {
+		t.Errorf("ShowFile(first) = %q, %v; want one", data, err)
+	}
+	if _, err := repo.ShowFile(ctx, first, "b.txt"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("ShowFile(first, b.txt) error = %v, want not exist", err)
+	}
+	changed, err := repo.ChangedFiles(ctx, first, "HEAD")
+	if err != nil || !reflect.DeepEqual(changed, []string{"a.txt", "b.txt"}) {
+		t.Errorf("ChangedFiles() = %v, %v", changed, err)
+	}
+	if base, err := repo.MergeBase(ctx, "HEAD", first); err != nil || base != first {
+		t.Errorf("MergeBase(HEAD, first) = %s, %v; want %s", base, err, first)
+	}
+
+	times, err := repo.LastCommitTimes(ctx, ".")
+	if err != nil || len(times) != 2 || !times["a.txt"].Equal(clk.Now()) {
+		t.Errorf("LastCommitTimes() = %v, %v; want a.txt and b.txt at %v", times, err, clk.Now())
+	}
+	fileChanges, err := repo.FileChanges(ctx, []string{"a.txt"}, ".")
+	if err != nil || len(fileChanges) != 2 || fileChanges[0].Before != nil || string(fileChanges[1].Before) != "one" || string(fileChanges[1].After) != "two" {
+		t.Errorf("FileChanges() = %+v, %v; want a.txt added, then changed", fileChanges, err)
+	}
+}
+
+func TestFakeRepo_Errors(t *testing.T) {
+	ctx := context.Background()
+	repo := NewFakeRepo(t.TempDir())
+	boom := errors.New("boom")
+	repo.Errors["Push"] = boom
+
+	if err := repo.Push(ctx, "origin", "main"); !errors.Is(err, boom) {
+		t.Errorf("Push() error = %v, want boom", err)
+	}
+	if len(repo.Pushed) != 0 {
+		t.Errorf("Pushed = %v, want none", repo.Pushed)
+	}
+
+	delete(repo.Errors, "Push")
+	if err := repo.Push(ctx, "origin", "main"); err != nil {
+		t.Fatalf("Push() error = %v", err)
+	}
+	if !reflect.DeepEqual(repo.Pushed, []string{"origin/main"}) {
+		t.Errorf("Pushed = %v", repo.Pushed)
+	}
+}
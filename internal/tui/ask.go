@@ -3,15 +3,19 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/viewport"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/chazuruo/svf/internal/ai"
 	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/diff"
+	"github.com/chazuruo/svf/internal/tui/theme"
 	"github.com/chazuruo/svf/internal/workflows"
 )
 
@@ -27,6 +31,10 @@ const (
 	AskStateGenerating
 	// AskStateReviewing means user is reviewing the generated workflow.
 	AskStateReviewing
+	// AskStateRefining means user is typing follow-up instructions.
+	AskStateRefining
+	// AskStateComparing means user is reviewing a diff of a refined workflow.
+	AskStateComparing
 	// AskStateFinished means the flow is complete.
 	AskStateFinished
 )
@@ -50,9 +58,23 @@ type AskModel struct {
 	// Workflow editor
 	workflowEditor WorkflowEditorModel
 
+	// Refinement input and the pending refined workflow
+	refineInput     textarea.Model
+	refinedWorkflow *workflows.Workflow
+	refineDiff      []diff.Line
+	diffViewport    viewport.Model
+
 	// AI provider
 	provider ai.Provider
 
+	// estimate describes the size and cost of the request being sent
+	estimate ai.Estimate
+
+	// retries receives the provider's retry and fallback notices;
+	// retryStatus is the latest one for the request being sent.
+	retries     chan ai.RetryStatus
+	retryStatus *ai.RetryStatus
+
 	// Error state
 	errorMsg string
 	quit     bool
@@ -75,6 +97,12 @@ type AskOptions struct {
 	As        string // "workflow" or "step"
 	Identity  string
 	NoCommit  bool
+
+	// History holds redacted shell commands sent as context.
+	History []string
+
+	// Existing holds workflows whose summaries are sent as examples.
+	Existing []*workflows.Workflow
 }
 
 // NewAskModel creates a new ask model.
@@ -86,24 +114,30 @@ func NewAskModel(ctx context.Context, cfg *config.Config, opts *AskOptions) *Ask
 	ti.Focus()
 	ti.ShowLineNumbers = false
 
+	// Create refinement textarea
+	ri := textarea.New()
+	ri.Placeholder = "e.g. add a rollback step, use helm instead of kubectl..."
+	ri.SetHeight(4)
+	ri.ShowLineNumbers = false
+
 	// Styles
 	headerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("86")).
+		Foreground(theme.Current().Accent).
 		Bold(true)
 
 	labelStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("242")).
+		Foreground(theme.Current().Muted).
 		Width(15)
 
 	infoStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("245"))
+		Foreground(theme.Current().Muted)
 
 	errorStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("203")).
+		Foreground(theme.Current().Error).
 		Bold(true)
 
 	successStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("86")).
+		Foreground(theme.Current().Accent).
 		Bold(true)
 
 	return &AskModel{
@@ -112,6 +146,9 @@ func NewAskModel(ctx context.Context, cfg *config.Config, opts *AskOptions) *Ask
 		opts:         opts,
 		state:        AskStatePrompting,
 		promptInput:  ti,
+		refineInput:  ri,
+		diffViewport: viewport.New(80, 20),
+		retries:      make(chan ai.RetryStatus, 4),
 		headerStyle:  headerStyle,
 		labelStyle:   labelStyle,
 		infoStyle:    infoStyle,
@@ -127,13 +164,20 @@ func (m *AskModel) Init() tea.Cmd {
 
 // Update updates the ask model.
 func (m *AskModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	var cmds []tea.Cmd
-
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		return m.handleKey(msg)
 
+	case retryStatusMsg:
+		m.retryStatus = &msg.Status
+		return m, m.waitForRetry(msg.done)
+
 	case generateWorkflowMsg:
+		m.retryStatus = nil
+		if msg.Previous != nil {
+			return m.handleRefined(msg)
+		}
+
 		// Workflow generation complete
 		if msg.Error != nil {
 			m.errorMsg = fmt.Sprintf("Failed to generate workflow: %v", msg.Error)
@@ -146,7 +190,13 @@ func (m *AskModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	// Update child components based on state
+	return m.updateState(msg)
+}
+
+// updateState forwards a message to the component for the current state.
+func (m *AskModel) updateState(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+
 	switch m.state {
 	case AskStatePrompting:
 		var cmd tea.Cmd
@@ -194,6 +244,16 @@ func (m *AskModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.quit = true
 			return m, tea.Quit
 		}
+
+	case AskStateRefining:
+		var cmd tea.Cmd
+		m.refineInput, cmd = m.refineInput.Update(msg)
+		cmds = append(cmds, cmd)
+
+	case AskStateComparing:
+		var cmd tea.Cmd
+		m.diffViewport, cmd = m.diffViewport.Update(msg)
+		cmds = append(cmds, cmd)
 	}
 
 	return m, tea.Batch(cmds...)
@@ -211,7 +271,7 @@ func (m *AskModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if m.state == AskStatePrompting {
 			// Submit prompt and move to redaction
 			prompt := m.promptInput.Value()
-			if strings.TrimSpace(prompt) == "" {
+			if strings.TrimSpace(prompt) == "" && len(m.opts.History) == 0 {
 				m.errorMsg = "Please enter a description"
 				return m, nil
 		}
@@ -223,19 +283,140 @@ func (m *AskModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.state = AskStateRedacting
 			return m, nil
 		}
+		if m.state == AskStateRefining {
+			instructions := strings.TrimSpace(m.refineInput.Value())
+			if instructions == "" {
+				m.errorMsg = "Please describe the changes you want"
+				return m, nil
+			}
+			m.errorMsg = ""
+			m.refineInput.Blur()
+			m.state = AskStateGenerating
+			return m, m.refineWorkflow(instructions)
+		}
+
+	case tea.KeyCtrlR:
+		if m.state == AskStateReviewing {
+			// Ask the AI to revise the current workflow
+			m.errorMsg = ""
+			m.refineInput.Reset()
+			m.state = AskStateRefining
+			return m, m.refineInput.Focus()
+		}
+
+	case tea.KeyEsc:
+		if m.state == AskStateRefining {
+			m.state = AskStateReviewing
+			return m, nil
+		}
+	}
+
+	if m.state == AskStateComparing {
+		switch msg.String() {
+		case "y", "enter":
+			// Accept the revision
+			m.generatedWorkflow = m.refinedWorkflow
+			m.workflowEditor = NewWorkflowEditor(m.ctx, m.refinedWorkflow)
+			m.refinedWorkflow = nil
+			m.state = AskStateReviewing
+			return m, nil
+		case "n", "esc":
+			// Keep the previous version
+			m.refinedWorkflow = nil
+			m.state = AskStateReviewing
+			return m, nil
+		}
+	}
+
+	return m.updateState(msg)
+}
+
+// handleRefined handles the result of a refinement request.
+func (m *AskModel) handleRefined(msg generateWorkflowMsg) (tea.Model, tea.Cmd) {
+	if msg.Error != nil {
+		m.errorMsg = fmt.Sprintf("Failed to refine workflow: %v", msg.Error)
+		m.state = AskStateReviewing
+		return m, nil
+	}
+
+	lines, err := diff.Workflows(msg.Previous, msg.Workflow)
+	if err != nil {
+		m.errorMsg = fmt.Sprintf("Failed to compare workflows: %v", err)
+		m.state = AskStateReviewing
+		return m, nil
 	}
 
+	m.refinedWorkflow = msg.Workflow
+	m.refineDiff = lines
+	m.diffViewport.SetContent(m.renderDiff())
+	m.diffViewport.GotoTop()
+	m.state = AskStateComparing
 	return m, nil
 }
 
+// refineWorkflow is a tea.Cmd that asks the provider to revise the
+// workflow currently being reviewed.
+func (m *AskModel) refineWorkflow(instructions string) tea.Cmd {
+	previous := m.workflowEditor.GetWorkflow()
+	provider := m.provider
+
+	req := ai.GenerateRequest{
+		Prompt:   ai.Redact(instructions),
+		Previous: previous,
+		Options: ai.GenerateOptions{
+			IncludePlaceholders: true,
+		},
+	}
+	m.estimate = ai.NewEstimate(m.buildAIConfig(), ai.EstimateRequest(req))
+	m.resetRetryStatus()
+	done := make(chan struct{})
+
+	refine := func() tea.Msg {
+		defer close(done)
+		if provider == nil {
+			var err error
+			provider, err = ai.NewProvider(m.buildAIConfig())
+			if err != nil {
+				return generateWorkflowMsg{Previous: previous, Error: err}
+			}
+			if provider == nil {
+				return generateWorkflowMsg{Previous: previous, Error: fmt.Errorf("AI provider not configured")}
+			}
+		}
+
+		wf, err := provider.GenerateWorkflow(m.ctx, req)
+		if err != nil {
+			return generateWorkflowMsg{Previous: previous, Error: err}
+		}
+
+		return generateWorkflowMsg{Previous: previous, Workflow: wf}
+	}
+	return tea.Batch(refine, m.waitForRetry(done))
+}
+
 // generateWorkflow is a tea.Cmd that generates the workflow.
 func (m *AskModel) generateWorkflow() tea.Cmd {
-	return func() tea.Msg {
-		// Get redacted prompt
-		prompt := m.redactionModel.GetRedactedContent()
+	// Get redacted prompt
+	prompt := m.redactionModel.GetRedactedContent()
+
+	req := ai.GenerateRequest{
+		Prompt: prompt,
+		Options: ai.GenerateOptions{
+			IncludePlaceholders: true,
+		},
+	}
+	if len(m.opts.History) > 0 || len(m.opts.Existing) > 0 {
+		req.Context = &ai.GenerateContext{History: m.opts.History, ExistingWorkflows: m.opts.Existing}
+	}
+
+	// Build AI config
+	aiCfg := m.buildAIConfig()
+	m.estimate = ai.NewEstimate(aiCfg, ai.EstimateRequest(req))
+	m.resetRetryStatus()
+	done := make(chan struct{})
 
-		// Build AI config
-		aiCfg := m.buildAIConfig()
+	generate := func() tea.Msg {
+		defer close(done)
 
 		// Create provider
 		provider, err := ai.NewProvider(aiCfg)
@@ -250,13 +431,6 @@ func (m *AskModel) generateWorkflow() tea.Cmd {
 		m.provider = provider
 
 		// Generate workflow
-		req := ai.GenerateRequest{
-			Prompt: prompt,
-			Options: ai.GenerateOptions{
-				IncludePlaceholders: true,
-			},
-		}
-
 		wf, err := provider.GenerateWorkflow(m.ctx, req)
 		if err != nil {
 			return generateWorkflowMsg{Error: err}
@@ -264,6 +438,33 @@ func (m *AskModel) generateWorkflow() tea.Cmd {
 
 		return generateWorkflowMsg{Workflow: wf}
 	}
+	return tea.Batch(generate, m.waitForRetry(done))
+}
+
+// resetRetryStatus clears the retry notices of the previous request.
+func (m *AskModel) resetRetryStatus() {
+	m.retryStatus = nil
+	for {
+		select {
+		case <-m.retries:
+		default:
+			return
+		}
+	}
+}
+
+// waitForRetry is a tea.Cmd that waits for the next retry notice of the
+// request that closes done when it finishes.
+func (m *AskModel) waitForRetry(done <-chan struct{}) tea.Cmd {
+	retries := m.retries
+	return func() tea.Msg {
+		select {
+		case s := <-retries:
+			return retryStatusMsg{Status: s, done: done}
+		case <-done:
+			return nil
+		}
+	}
 }
 
 // buildAIConfig builds AI config from options and global config.
@@ -291,6 +492,20 @@ func (m *AskModel) buildAIConfig() *ai.Config {
 	if m.cfg.AI.BaseURL != "" {
 		aiCfg.BaseURL = m.cfg.AI.BaseURL
 	}
+	aiCfg.MaxPromptTokens = m.cfg.AI.MaxPromptTokens
+	aiCfg.Truncation = ai.Truncation(m.cfg.AI.Truncation)
+	aiCfg.Retry.MaxAttempts = m.cfg.AI.MaxAttempts
+	retries := m.retries
+	aiCfg.OnRetry = func(s ai.RetryStatus) {
+		// Drop notices rather than stall the request if the view is behind
+		select {
+		case retries <- s:
+		default:
+		}
+	}
+	for _, fb := range m.cfg.AI.Fallbacks {
+		aiCfg.Fallbacks = append(aiCfg.Fallbacks, ai.NewFallback(fb.Provider, fb.Model, fb.BaseURL, fb.APIKeyEnv))
+	}
 
 	return aiCfg
 }
@@ -305,7 +520,11 @@ func (m *AskModel) View() string {
 	case AskStateGenerating:
 		return m.renderGeneratingView()
 	case AskStateReviewing:
-		return m.workflowEditor.View()
+		return m.renderReviewView()
+	case AskStateRefining:
+		return m.renderRefineView()
+	case AskStateComparing:
+		return m.renderCompareView()
 	case AskStateFinished:
 		return m.renderFinishedView()
 	}
@@ -336,7 +555,7 @@ func (m *AskModel) renderPromptView() string {
 
 	// Footer
 	footerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241")).
+		Foreground(theme.Current().Muted).
 		MarginTop(1)
 
 	footer := footerStyle.Render(
@@ -359,11 +578,19 @@ func (m *AskModel) renderGeneratingView() string {
 
 	// Show provider info
 	providerName := "unknown"
-	if m.provider != nil {
+	if m.retryStatus != nil {
+		providerName = m.retryStatus.Provider
+	} else if m.provider != nil {
 		providerName = m.provider.Name()
 	}
 	b.WriteString(m.infoStyle.Render(fmt.Sprintf("Provider: %s", providerName)))
 	b.WriteString("\n")
+	if m.retryStatus != nil {
+		b.WriteString(m.errorStyle.Render(retryStatusLine(*m.retryStatus)))
+		b.WriteString("\n")
+	}
+	b.WriteString(m.infoStyle.Render(fmt.Sprintf("Estimated: %s", m.estimate)))
+	b.WriteString("\n")
 
 	b.WriteString(m.infoStyle.Render("This may take a few moments..."))
 	b.WriteString("\n\n")
@@ -374,6 +601,79 @@ func (m *AskModel) renderGeneratingView() string {
 		Render(b.String())
 }
 
+// renderReviewView renders the workflow editor with the refinement hint.
+func (m *AskModel) renderReviewView() string {
+	var b strings.Builder
+
+	b.WriteString(m.workflowEditor.View())
+	b.WriteString("\n")
+	if m.errorMsg != "" {
+		b.WriteString(m.errorStyle.Render("⚠️  " + m.errorMsg))
+		b.WriteString("\n")
+	}
+	b.WriteString(m.infoStyle.Render(" [Ctrl+R]: refine with AI"))
+
+	return b.String()
+}
+
+// renderRefineView renders the refinement instructions input.
+func (m *AskModel) renderRefineView() string {
+	var b strings.Builder
+
+	b.WriteString(m.headerStyle.Render("✨ Refine Workflow"))
+	b.WriteString("\n\n")
+
+	b.WriteString("Describe what to change. The current workflow is sent along\n")
+	b.WriteString("with your instructions, and the revision is shown as a diff.\n\n")
+
+	if m.errorMsg != "" {
+		b.WriteString(m.errorStyle.Render("⚠️  " + m.errorMsg))
+		b.WriteString("\n\n")
+	}
+
+	b.WriteString(m.refineInput.View())
+	b.WriteString("\n\n")
+	b.WriteString(m.infoStyle.Render(" [Ctrl+S]: send  [Esc]: back"))
+
+	return b.String()
+}
+
+// renderCompareView renders the diff between the previous and refined workflow.
+func (m *AskModel) renderCompareView() string {
+	var b strings.Builder
+
+	b.WriteString(m.headerStyle.Render("Revised Workflow"))
+	b.WriteString("\n\n")
+	if !diff.HasChanges(m.refineDiff) {
+		b.WriteString(m.infoStyle.Render("The revision made no changes."))
+		b.WriteString("\n\n")
+	}
+	b.WriteString(m.diffViewport.View())
+	b.WriteString("\n\n")
+	b.WriteString(m.infoStyle.Render(" [y/Enter]: accept  [n/Esc]: keep previous  [↑/↓]: scroll"))
+
+	return b.String()
+}
+
+// renderDiff renders the refinement diff with added and removed lines colored.
+func (m *AskModel) renderDiff() string {
+	addStyle := lipgloss.NewStyle().Foreground(theme.Current().Success)
+	delStyle := lipgloss.NewStyle().Foreground(theme.Current().Error)
+
+	lines := make([]string, len(m.refineDiff))
+	for i, l := range m.refineDiff {
+		switch l.Op {
+		case diff.Insert:
+			lines[i] = addStyle.Render(l.String())
+		case diff.Delete:
+			lines[i] = delStyle.Render(l.String())
+		default:
+			lines[i] = m.infoStyle.Render(l.String())
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
 // renderFinishedView renders the finished state.
 func (m *AskModel) renderFinishedView() string {
 	var b strings.Builder
@@ -419,8 +719,33 @@ func (m *AskModel) DidCancel() bool {
 	return m.canceled
 }
 
+// retryStatusLine describes a retry notice in the generating view, e.g.
+// "Attempt 2/3, waiting 8s (status 429)".
+func retryStatusLine(s ai.RetryStatus) string {
+	reason := "request failed"
+	var status *ai.StatusError
+	if errors.As(s.Err, &status) {
+		reason = fmt.Sprintf("status %d", status.StatusCode)
+	}
+	if s.Fallback {
+		return fmt.Sprintf("Falling back to %s (%s)", s.Provider, reason)
+	}
+	return fmt.Sprintf("Attempt %d/%d, waiting %s (%s)", s.Attempt, s.MaxAttempts, s.Wait, reason)
+}
+
+// retryStatusMsg is a message sent when a request is retried or falls
+// back to another provider.
+type retryStatusMsg struct {
+	Status ai.RetryStatus
+
+	done <-chan struct{}
+}
+
 // generateWorkflowMsg is a message sent when workflow generation is complete.
 type generateWorkflowMsg struct {
 	Workflow *workflows.Workflow
 	Error    error
+
+	// Previous is set when the workflow is a refinement of Previous.
+	Previous *workflows.Workflow
 }
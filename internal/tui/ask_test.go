@@ -0,0 +1,128 @@
+// Package tui provides tests for the ask flow.
+package tui
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chazuruo/svf/internal/ai"
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// refineProvider is a fake provider that records refinement requests.
+type refineProvider struct {
+	last *ai.GenerateRequest
+}
+
+func (p *refineProvider) Name() string { return "fake-refine" }
+
+func (p *refineProvider) GenerateWorkflow(ctx context.Context, req ai.GenerateRequest) (*workflows.Workflow, error) {
+	p.last = &req
+	return &workflows.Workflow{
+		Title: "Deploy",
+		Steps: []workflows.Step{{Name: "apply", Command: "helm upgrade app ./chart"}},
+	}, nil
+}
+
+func (p *refineProvider) Explain(ctx context.Context, req ai.ExplainRequest) (string, error) {
+	return "", nil
+}
+
+func TestAskModelRefine(t *testing.T) {
+	provider := &refineProvider{}
+	ai.RegisterProvider("fake-refine", func(cfg *ai.Config) (ai.Provider, error) {
+		return provider, nil
+	})
+
+	m := NewAskModel(context.Background(), &config.Config{}, &AskOptions{Provider: "fake-refine"})
+	original := &workflows.Workflow{
+		Title: "Deploy",
+		Steps: []workflows.Step{{Name: "apply", Command: "kubectl apply -f app.yaml"}},
+	}
+	m.Update(generateWorkflowMsg{Workflow: original})
+	if m.state != AskStateReviewing {
+		t.Fatalf("expected reviewing state, got %v", m.state)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	if m.state != AskStateRefining {
+		t.Fatalf("expected refining state, got %v", m.state)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("use helm")})
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if cmd == nil {
+		t.Fatal("expected a refinement command")
+	}
+	runCmd(m, cmd)
+
+	if provider.last == nil || provider.last.Previous == nil || provider.last.Prompt != "use helm" {
+		t.Fatalf("expected refinement request with previous workflow, got %+v", provider.last)
+	}
+	if m.state != AskStateComparing {
+		t.Fatalf("expected comparing state, got %v", m.state)
+	}
+	view := m.View()
+	if !strings.Contains(view, "- ") || !strings.Contains(view, "helm upgrade") {
+		t.Errorf("expected diff in view, got:\n%s", view)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	if m.state != AskStateReviewing {
+		t.Fatalf("expected reviewing state after accept, got %v", m.state)
+	}
+	if got := m.GetWorkflow().Steps[0].Command; got != "helm upgrade app ./chart" {
+		t.Errorf("expected refined workflow, got %q", got)
+	}
+}
+
+// runCmd runs cmd, and each command of a batch in turn, passing the
+// messages to m.
+func runCmd(m *AskModel, cmd tea.Cmd) {
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		for _, c := range batch {
+			runCmd(m, c)
+		}
+		return
+	}
+	if msg != nil {
+		m.Update(msg)
+	}
+}
+
+func TestAskModelRetryStatus(t *testing.T) {
+	m := NewAskModel(context.Background(), &config.Config{}, &AskOptions{})
+	m.state = AskStateGenerating
+	done := make(chan struct{})
+
+	m.Update(retryStatusMsg{Status: ai.RetryStatus{
+		Provider:    "openai",
+		Attempt:     2,
+		MaxAttempts: 3,
+		Wait:        8 * time.Second,
+		Err:         &ai.StatusError{StatusCode: 429},
+	}, done: done})
+	if view := m.View(); !strings.Contains(view, "Attempt 2/3, waiting 8s (status 429)") {
+		t.Errorf("expected retry status in view, got:\n%s", view)
+	}
+
+	m.Update(retryStatusMsg{Status: ai.RetryStatus{
+		Provider: "ollama",
+		Err:      &ai.StatusError{StatusCode: 503},
+		Fallback: true,
+	}, done: done})
+	view := m.View()
+	if !strings.Contains(view, "Falling back to ollama (status 503)") || !strings.Contains(view, "Provider: ollama") {
+		t.Errorf("expected fallback status in view, got:\n%s", view)
+	}
+
+	m.Update(generateWorkflowMsg{Workflow: &workflows.Workflow{Title: "Deploy"}})
+	if m.retryStatus != nil {
+		t.Error("expected retry status to be cleared once generation completes")
+	}
+}
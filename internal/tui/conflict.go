@@ -2,16 +2,21 @@
 package tui
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/tui/theme"
 )
 
 // DiffViewMode controls how diffs are displayed.
@@ -24,6 +29,18 @@ const (
 	DiffViewSideBySide
 )
 
+// ConflictSource holds the conflicted files to resolve: a repo in the
+// middle of a merge or rebase, or a single conflicted file such as a
+// gitrepo.FileConflict.
+type ConflictSource interface {
+	// Path is the directory the conflicted paths are relative to.
+	Path() string
+	// GetConflictDetails returns the versions of a conflicted path.
+	GetConflictDetails(ctx context.Context, path string) (gitrepo.ConflictDetails, error)
+	// ResolveFile resolves a conflicted path.
+	ResolveFile(ctx context.Context, path string, resolution gitrepo.Resolution) error
+}
+
 // ConflictResolverModel is a Bubble Tea model for resolving merge conflicts.
 type ConflictResolverModel struct {
 	// ConflictedFiles is the list of files with conflicts.
@@ -62,6 +79,9 @@ type ConflictResolverModel struct {
 	// TheirsViewport is the "theirs" diff viewer (for side-by-side).
 	TheirsViewport viewport.Model
 
+	// Editor is the textarea for manual resolution.
+	Editor textarea.Model
+
 	// Resolved indicates which files have been resolved.
 	Resolved map[string]bool
 
@@ -71,6 +91,10 @@ type ConflictResolverModel struct {
 	// Aborted indicates if user aborted.
 	Aborted bool
 
+	// ctx and repo read and resolve the conflicts.
+	ctx  context.Context
+	repo ConflictSource
+
 	// styles
 	normalStyle   lipgloss.Style
 	selectedStyle lipgloss.Style
@@ -79,6 +103,17 @@ type ConflictResolverModel struct {
 	headerStyle   lipgloss.Style
 	markerStyle   lipgloss.Style
 
+	// editRegions classifies the editor's lines for the gutter. It is a
+	// pointer so the editor's prompt func sees updates.
+	editRegions *[]conflictRegion
+
+	// editNotice is a message shown while editing, e.g. on save errors.
+	editNotice string
+
+	// confirmMarkers is set after a save was refused because conflict
+	// markers remain; saving again saves anyway.
+	confirmMarkers bool
+
 	// width and height
 	width  int
 	height int
@@ -94,10 +129,24 @@ const (
 	ConflictStateResolving
 	// ConflictStateFinished means resolution is complete.
 	ConflictStateFinished
+	// ConflictStateEditing means user is editing a file by hand.
+	ConflictStateEditing
+)
+
+// conflictRegion classifies a line of a conflicted file.
+type conflictRegion int
+
+const (
+	regionNone conflictRegion = iota
+	regionMarker
+	regionOurs
+	regionBase
+	regionTheirs
 )
 
-// NewConflictResolverModel creates a new conflict resolver model.
-func NewConflictResolverModel(conflictedFiles []string) ConflictResolverModel {
+// NewConflictResolverModel creates a new conflict resolver model for the
+// repo-relative conflicted paths of repo.
+func NewConflictResolverModel(ctx context.Context, repo ConflictSource, conflictedFiles []string) ConflictResolverModel {
 	// Create file list
 	items := make([]list.Item, len(conflictedFiles))
 	for i, file := range conflictedFiles {
@@ -113,6 +162,14 @@ func NewConflictResolverModel(conflictedFiles []string) ConflictResolverModel {
 	l.SetShowHelp(false)
 	l.Title = "Conflicted Files"
 
+	// Create manual resolution editor
+	editor := textarea.New()
+	editor.ShowLineNumbers = true
+	editor.CharLimit = 0
+	editor.MaxHeight = 9999
+	editor.SetWidth(80)
+	editor.SetHeight(20)
+
 	// Create viewports
 	vp := viewport.New(80, 20)
 	oursVp := viewport.New(40, 20)
@@ -120,21 +177,21 @@ func NewConflictResolverModel(conflictedFiles []string) ConflictResolverModel {
 
 	// Styles
 	normalStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("251"))
+		Foreground(theme.Current().Text)
 	selectedStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("229")).
+		Foreground(theme.Current().Selected).
 		Bold(true)
 	oursStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("green")).
-		Background(lipgloss.Color("235"))
+		Foreground(theme.Current().Success).
+		Background(theme.Current().Surface)
 	theirsStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("blue")).
-		Background(lipgloss.Color("235"))
+		Foreground(theme.Current().Info).
+		Background(theme.Current().Surface)
 	headerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("86")).
+		Foreground(theme.Current().Accent).
 		Bold(true)
 	markerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("196")).
+		Foreground(theme.Current().Danger).
 		Bold(true)
 
 	return ConflictResolverModel{
@@ -145,8 +202,12 @@ func NewConflictResolverModel(conflictedFiles []string) ConflictResolverModel {
 		Viewport:        vp,
 		OursViewport:    oursVp,
 		TheirsViewport:  theirsVp,
+		Editor:          editor,
+		editRegions:     &[]conflictRegion{},
 		Resolved:        make(map[string]bool),
 		DiffMode:        DiffViewUnified,
+		ctx:             ctx,
+		repo:            repo,
 		normalStyle:     normalStyle,
 		selectedStyle:   selectedStyle,
 		oursStyle:       oursStyle,
@@ -165,6 +226,10 @@ func (m ConflictResolverModel) Init() tea.Cmd {
 func (m ConflictResolverModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
+	if m.State == ConflictStateEditing {
+		return m.updateEditing(msg)
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
@@ -187,20 +252,26 @@ func (m ConflictResolverModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "o":
 			if m.State == ConflictStateResolving {
 				// Accept "ours" version
-				m.resolveConflict(m.ConflictedFiles[m.CurrentFile], "ours")
+				m.resolveConflict(m.ConflictedFiles[m.CurrentFile], gitrepo.ResolveOurs)
 			}
 
 		case "t":
 			if m.State == ConflictStateResolving {
 				// Accept "theirs" version
-				m.resolveConflict(m.ConflictedFiles[m.CurrentFile], "theirs")
+				m.resolveConflict(m.ConflictedFiles[m.CurrentFile], gitrepo.ResolveTheirs)
 			}
 
 		case "m":
 			if m.State == ConflictStateResolving {
-				// Open in external editor (mg)
+				// Edit the conflicted content in place
+				return m, m.startEditing(m.ConflictedFiles[m.CurrentFile])
+			}
+
+		case "e":
+			if m.State == ConflictStateResolving {
+				// Open in external editor
 				m.openInEditor(m.ConflictedFiles[m.CurrentFile])
-				m.resolveConflict(m.ConflictedFiles[m.CurrentFile], "manual")
+				m.resolveConflict(m.ConflictedFiles[m.CurrentFile], gitrepo.ResolveManual)
 			}
 
 		case "a":
@@ -238,6 +309,8 @@ func (m ConflictResolverModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.OursViewport.Height = msg.Height - 10
 		m.TheirsViewport.Width = (msg.Width - 50) / 2
 		m.TheirsViewport.Height = msg.Height - 10
+		m.Editor.SetWidth(msg.Width - 4)
+		m.Editor.SetHeight(msg.Height - 8)
 	}
 
 	// Update child components
@@ -282,6 +355,8 @@ func (m ConflictResolverModel) View() string {
 		return m.fileListView()
 	case ConflictStateResolving:
 		return m.resolveView()
+	case ConflictStateEditing:
+		return m.editView()
 	default:
 		return ""
 	}
@@ -369,7 +444,7 @@ func (m ConflictResolverModel) unifiedDiffView() string {
 		Width(width).
 		Height(m.height - 10).
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("240")).
+		BorderForeground(theme.Current().Border).
 		Render(b.String())
 }
 
@@ -405,14 +480,14 @@ func (m ConflictResolverModel) sideBySideDiffView() string {
 		Width(panelWidth).
 		Height(m.height - 10).
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("green")).
+		BorderForeground(theme.Current().Success).
 		Render(oursPanel)
 
 	theirsStyled := lipgloss.NewStyle().
 		Width(panelWidth).
 		Height(m.height - 10).
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("blue")).
+		BorderForeground(theme.Current().Info).
 		Render(theirsPanel)
 
 	return lipgloss.JoinHorizontal(lipgloss.Top, oursStyled, theirsStyled)
@@ -428,7 +503,9 @@ func (m ConflictResolverModel) actionsView() string {
 	b.WriteString("\n\n")
 	b.WriteString("  [t] Accept 'theirs' (their changes)")
 	b.WriteString("\n\n")
-	b.WriteString("  [m] Manual edit (opens mg)")
+	b.WriteString("  [m] Manual edit")
+	b.WriteString("\n\n")
+	b.WriteString("  [e] Edit in $EDITOR")
 	b.WriteString("\n\n")
 	b.WriteString("  [v] Toggle diff view")
 	b.WriteString("\n\n")
@@ -441,10 +518,237 @@ func (m ConflictResolverModel) actionsView() string {
 		Width(width).
 		Height(m.height - 10).
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("240")).
+		BorderForeground(theme.Current().Border).
 		Render(b.String())
 }
 
+// editView shows the manual resolution editor.
+func (m ConflictResolverModel) editView() string {
+	var b strings.Builder
+
+	filePath := m.ConflictedFiles[m.CurrentFile]
+	b.WriteString(m.headerStyle.Render(fmt.Sprintf("Editing: %s", filePath)))
+	b.WriteString("\n\n")
+	b.WriteString(m.Editor.View())
+	b.WriteString("\n\n")
+
+	if m.editNotice != "" {
+		b.WriteString(m.markerStyle.Render(m.editNotice))
+		b.WriteString("\n")
+	}
+	b.WriteString(fmt.Sprintf("%d conflict(s) left  [ctrl+n/ctrl+p] Next/prev conflict  [ctrl+s] Save and stage  [esc] Cancel",
+		len(conflictMarkerLines(m.Editor.Value()))))
+
+	return b.String()
+}
+
+// startEditing loads a conflicted file into the editor.
+func (m *ConflictResolverModel) startEditing(filePath string) tea.Cmd {
+	content, err := os.ReadFile(m.fullPath(filePath))
+	if err != nil {
+		m.editNotice = ""
+		m.DiffContent = fmt.Sprintf("Error reading file: %v", err)
+		m.Viewport.SetContent(m.DiffContent)
+		return nil
+	}
+
+	m.Editor.SetValue(string(content))
+	m.Editor.SetPromptFunc(2, m.editorPrompt)
+	m.refreshRegions()
+
+	// Start at the first conflict
+	m.moveEditorTo(0)
+	if lines := conflictMarkerLines(m.Editor.Value()); len(lines) > 0 {
+		m.moveEditorTo(lines[0])
+	}
+
+	m.editNotice = ""
+	m.confirmMarkers = false
+	m.State = ConflictStateEditing
+	return m.Editor.Focus()
+}
+
+// updateEditing handles messages while editing a file by hand.
+func (m ConflictResolverModel) updateEditing(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.String() {
+		case "esc":
+			m.Editor.Blur()
+			m.editNotice = ""
+			m.State = ConflictStateResolving
+			return m, nil
+
+		case "ctrl+n", "ctrl+p":
+			dir := 1
+			if key.String() == "ctrl+p" {
+				dir = -1
+			}
+			if row, ok := nextConflictLine(conflictMarkerLines(m.Editor.Value()), m.Editor.Line(), dir); ok {
+				m.moveEditorTo(row)
+			}
+			return m, nil
+
+		case "ctrl+s":
+			m.saveEdit()
+			return m, nil
+		}
+	}
+
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		m.width = size.Width
+		m.height = size.Height
+		m.Editor.SetWidth(size.Width - 4)
+		m.Editor.SetHeight(size.Height - 8)
+	}
+
+	var cmd tea.Cmd
+	m.Editor, cmd = m.Editor.Update(msg)
+	if _, ok := msg.(tea.KeyMsg); ok {
+		m.confirmMarkers = false
+		m.refreshRegions()
+	}
+	return m, cmd
+}
+
+// saveEdit writes the edited content and stages the file. Saving with
+// conflict markers left needs a second confirmation.
+func (m *ConflictResolverModel) saveEdit() {
+	filePath := m.ConflictedFiles[m.CurrentFile]
+	fullPath := m.fullPath(filePath)
+	content := m.Editor.Value()
+
+	if remaining := len(conflictMarkerLines(content)); remaining > 0 && !m.confirmMarkers {
+		m.confirmMarkers = true
+		m.editNotice = fmt.Sprintf("%d conflict(s) still have markers. Press ctrl+s again to save anyway.", remaining)
+		return
+	}
+
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(fullPath); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	if err := os.WriteFile(fullPath, []byte(content), mode); err != nil {
+		m.editNotice = fmt.Sprintf("Error saving: %v", err)
+		return
+	}
+
+	m.Editor.Blur()
+	m.editNotice = ""
+	m.resolveConflict(filePath, gitrepo.ResolveManual)
+}
+
+// fullPath returns the working tree path of a repo-relative file.
+func (m *ConflictResolverModel) fullPath(filePath string) string {
+	if filepath.IsAbs(filePath) {
+		return filePath
+	}
+	return filepath.Join(m.repo.Path(), filePath)
+}
+
+// moveEditorTo moves the editor cursor to the start of a line.
+func (m *ConflictResolverModel) moveEditorTo(row int) {
+	// Bounded by the line count, in case soft-wrapped lines need
+	// several moves or the target is past the end
+	for i := m.Editor.LineCount() * 4; i > 0 && m.Editor.Line() < row; i-- {
+		m.Editor.CursorDown()
+	}
+	for i := m.Editor.LineCount() * 4; i > 0 && m.Editor.Line() > row; i-- {
+		m.Editor.CursorUp()
+	}
+	m.Editor.CursorStart()
+}
+
+// refreshRegions reclassifies the editor's lines for the gutter.
+func (m *ConflictResolverModel) refreshRegions() {
+	*m.editRegions = classifyConflictLines(m.Editor.Value())
+}
+
+// editorPrompt renders the editor gutter, coloring conflict markers and
+// the ours, base and theirs sides of each conflict.
+func (m ConflictResolverModel) editorPrompt(lineIdx int) string {
+	regions := *m.editRegions
+	if lineIdx >= len(regions) {
+		return "  "
+	}
+	switch regions[lineIdx] {
+	case regionMarker:
+		return m.markerStyle.Render("▶ ")
+	case regionOurs:
+		return m.oursStyle.Render("│ ")
+	case regionBase:
+		return lipgloss.NewStyle().Foreground(theme.Current().Muted).Render("│ ")
+	case regionTheirs:
+		return m.theirsStyle.Render("│ ")
+	default:
+		return "  "
+	}
+}
+
+// classifyConflictLines classifies each line of content as a conflict
+// marker, a side of a conflict, or ordinary text.
+func classifyConflictLines(content string) []conflictRegion {
+	lines := strings.Split(content, "\n")
+	regions := make([]conflictRegion, len(lines))
+
+	current := regionNone
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "<<<<<<<"):
+			regions[i] = regionMarker
+			current = regionOurs
+		case strings.HasPrefix(line, "|||||||") && current == regionOurs:
+			regions[i] = regionMarker
+			current = regionBase
+		case strings.HasPrefix(line, "=======") && (current == regionOurs || current == regionBase):
+			regions[i] = regionMarker
+			current = regionTheirs
+		case strings.HasPrefix(line, ">>>>>>>") && current == regionTheirs:
+			regions[i] = regionMarker
+			current = regionNone
+		default:
+			regions[i] = current
+		}
+	}
+	return regions
+}
+
+// conflictMarkerLines returns the line numbers (0-based) where conflicts
+// start.
+func conflictMarkerLines(content string) []int {
+	var lines []int
+	for i, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, "<<<<<<<") {
+			lines = append(lines, i)
+		}
+	}
+	return lines
+}
+
+// nextConflictLine returns the first conflict start after cur (dir > 0)
+// or before it (dir < 0), wrapping around.
+func nextConflictLine(starts []int, cur, dir int) (int, bool) {
+	if len(starts) == 0 {
+		return 0, false
+	}
+	if dir > 0 {
+		for _, l := range starts {
+			if l > cur {
+				return l, true
+			}
+		}
+		return starts[0], true
+	}
+	for i := len(starts) - 1; i >= 0; i-- {
+		if starts[i] < cur {
+			return starts[i], true
+		}
+	}
+	return starts[len(starts)-1], true
+}
+
 // finishedView shows the final state.
 func (m ConflictResolverModel) finishedView() string {
 	var b strings.Builder
@@ -484,8 +788,7 @@ func (m ConflictResolverModel) finishedView() string {
 
 // loadDiffForFile loads the diff content for a conflicted file.
 func (m *ConflictResolverModel) loadDiffForFile(filePath string) {
-	// Read the actual conflicted file content
-	content, err := os.ReadFile(filePath)
+	details, err := m.repo.GetConflictDetails(m.ctx, filePath)
 	if err != nil {
 		m.DiffContent = fmt.Sprintf("Error reading file: %v", err)
 		m.OursContent = m.DiffContent
@@ -494,11 +797,11 @@ func (m *ConflictResolverModel) loadDiffForFile(filePath string) {
 	}
 
 	// Parse conflict markers for unified view
-	m.DiffContent = m.formatUnifiedDiff(string(content))
+	m.DiffContent = m.formatUnifiedDiff(string(details.Working))
 
-	// Get ours and theirs versions for side-by-side view
-	m.OursContent = m.getOursVersion(filePath)
-	m.TheirsContent = m.getTheirsVersion(filePath)
+	// Ours and theirs versions for side-by-side view
+	m.OursContent = m.formatVersion(details.Ours)
+	m.TheirsContent = m.formatVersion(details.Theirs)
 
 	// Reset viewport to top
 	m.Viewport.SetContent(m.DiffContent)
@@ -553,28 +856,13 @@ func (m *ConflictResolverModel) formatUnifiedDiff(content string) string {
 	return result.String()
 }
 
-// getOursVersion extracts the "ours" version of the file.
-func (m *ConflictResolverModel) getOursVersion(filePath string) string {
-	// Use git show to get our version
-	cmd := exec.Command("git", "show", ":2:"+filePath)
-	output, err := cmd.Output()
-	if err != nil {
-		return fmt.Sprintf("Error loading ours: %v", err)
-	}
-
-	return m.formatWithLineNumbers(string(output))
-}
-
-// getTheirsVersion extracts the "theirs" version of the file.
-func (m *ConflictResolverModel) getTheirsVersion(filePath string) string {
-	// Use git show to get their version
-	cmd := exec.Command("git", "show", ":3:"+filePath)
-	output, err := cmd.Output()
-	if err != nil {
-		return fmt.Sprintf("Error loading theirs: %v", err)
+// formatVersion formats one side of a conflict, which is nil when the
+// side deleted the file.
+func (m *ConflictResolverModel) formatVersion(content []byte) string {
+	if content == nil {
+		return "(deleted)"
 	}
-
-	return m.formatWithLineNumbers(string(output))
+	return m.formatWithLineNumbers(string(content))
 }
 
 // formatWithLineNumbers adds line numbers to content.
@@ -592,36 +880,16 @@ func (m *ConflictResolverModel) formatWithLineNumbers(content string) string {
 	return result.String()
 }
 
-// resolveConflict resolves a conflict using the specified strategy.
-func (m *ConflictResolverModel) resolveConflict(filePath, strategy string) {
-	var cmd *exec.Cmd
-
-	switch strategy {
-	case "ours":
-		cmd = exec.Command("git", "checkout", "--ours", filePath)
-	case "theirs":
-		cmd = exec.Command("git", "checkout", "--theirs", filePath)
-	case "manual":
-		// File was edited externally, just mark as resolved
-		cmd = exec.Command("git", "add", filePath)
-	}
-
-	if cmd != nil {
-		if err := cmd.Run(); err != nil {
-			// Log error but continue
-			fmt.Fprintf(os.Stderr, "Error resolving %s: %v\n", filePath, err)
-		}
+// resolveConflict resolves a conflict using the specified resolution.
+func (m *ConflictResolverModel) resolveConflict(filePath string, resolution gitrepo.Resolution) {
+	if err := m.repo.ResolveFile(m.ctx, filePath, resolution); err != nil {
+		// Log error but continue
+		fmt.Fprintf(os.Stderr, "Error resolving %s: %v\n", filePath, err)
 	}
 
 	// Mark file as resolved
 	m.Resolved[filePath] = true
 
-	// If using ours/theirs, need to add the file
-	if strategy == "ours" || strategy == "theirs" {
-		cmd = exec.Command("git", "add", filePath)
-		_ = cmd.Run()
-	}
-
 	// Go back to file list
 	m.State = ConflictStateSelecting
 
@@ -642,6 +910,11 @@ func (m *ConflictResolverModel) resolveConflict(filePath, strategy string) {
 
 // openInEditor opens a file in the configured editor.
 func (m *ConflictResolverModel) openInEditor(filePath string) {
+	openFileInEditor(m.fullPath(filePath))
+}
+
+// openFileInEditor opens a file in $EDITOR (default mg) attached to the terminal.
+func openFileInEditor(filePath string) {
 	// Use mg as the editor
 	editor := os.Getenv("EDITOR")
 	if editor == "" {
@@ -714,11 +987,11 @@ func (d conflictFileDelegate) Render(w io.Writer, m list.Model, index int, listI
 
 	// Styles
 	selectedStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("229")).
+		Foreground(theme.Current().Selected).
 		Bold(true)
 
 	normalStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("251"))
+		Foreground(theme.Current().Text)
 
 	var text string
 	if index == m.Index() {
@@ -742,9 +1015,9 @@ type ConflictResolverResult struct {
 	TotalCount int
 }
 
-// RunConflictResolver runs the conflict resolver TUI.
-// Returns the result of the resolution session.
-func RunConflictResolver(conflictedFiles []string) (*ConflictResolverResult, error) {
+// RunConflictResolver runs the conflict resolver TUI on the conflicted
+// paths of repo. Returns the result of the resolution session.
+func RunConflictResolver(ctx context.Context, repo ConflictSource, conflictedFiles []string) (*ConflictResolverResult, error) {
 	if len(conflictedFiles) == 0 {
 		return &ConflictResolverResult{
 			Aborted:       false,
@@ -754,7 +1027,7 @@ func RunConflictResolver(conflictedFiles []string) (*ConflictResolverResult, err
 	}
 
 	// Create the model
-	model := NewConflictResolverModel(conflictedFiles)
+	model := NewConflictResolverModel(ctx, repo, conflictedFiles)
 
 	// Create the program
 	p := tea.NewProgram(model, tea.WithAltScreen())
@@ -780,3 +1053,65 @@ func RunConflictResolver(conflictedFiles []string) (*ConflictResolverResult, err
 		TotalCount:    len(conflictedFiles),
 	}, nil
 }
+
+// RunConflictResolverLine resolves conflicts with sequential line prompts.
+// It is the fallback for RunConflictResolver when no TUI is available.
+func RunConflictResolverLine(ctx context.Context, repo ConflictSource, conflictedFiles []string, p *LinePrompter) (*ConflictResolverResult, error) {
+	result := &ConflictResolverResult{TotalCount: len(conflictedFiles)}
+
+	choices := []Choice{
+		{Key: "o", Label: "ours"},
+		{Key: "t", Label: "theirs"},
+		{Key: "m", Label: "manual edit"},
+		{Key: "s", Label: "skip"},
+		{Key: "a", Label: "abort"},
+	}
+
+	for i, file := range conflictedFiles {
+		p.Printf("\nConflict %d/%d: %s\n", i+1, len(conflictedFiles), file)
+		if details, err := repo.GetConflictDetails(ctx, file); err == nil {
+			p.Printf("%s\n", conflictSummary(string(details.Working)))
+		}
+
+		choice, err := p.Choose("Resolve with", choices, "")
+		if err != nil {
+			return nil, err
+		}
+
+		var resolution gitrepo.Resolution
+		switch choice {
+		case "o":
+			resolution = gitrepo.ResolveOurs
+		case "t":
+			resolution = gitrepo.ResolveTheirs
+		case "m":
+			openFileInEditor(filepath.Join(repo.Path(), file))
+			resolution = gitrepo.ResolveManual
+		case "s":
+			continue
+		case "a":
+			result.Aborted = true
+			return result, nil
+		}
+
+		if err := repo.ResolveFile(ctx, file, resolution); err != nil {
+			p.Printf("Error resolving %s: %v\n", file, err)
+			continue
+		}
+		p.Printf("✓ Resolved %s (%s)\n", file, resolution)
+		result.ResolvedCount++
+	}
+
+	return result, nil
+}
+
+// conflictSummary describes the conflict hunks in content.
+func conflictSummary(content string) string {
+	hunks := 0
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, "<<<<<<<") {
+			hunks++
+		}
+	}
+	return fmt.Sprintf("  %d conflict hunk(s)", hunks)
+}
@@ -0,0 +1,192 @@
+package tui
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/testutil"
+)
+
+const conflictedYAML = `title: Deploy
+<<<<<<< HEAD
+command: make deploy
+=======
+command: make release
+>>>>>>> origin/main
+tags: [ops]
+<<<<<<< HEAD
+shell: bash
+||||||| base
+shell: sh
+=======
+shell: zsh
+>>>>>>> origin/main
+`
+
+func TestClassifyConflictLines(t *testing.T) {
+	got := classifyConflictLines(conflictedYAML)
+	want := []conflictRegion{
+		regionNone, regionMarker, regionOurs, regionMarker, regionTheirs, regionMarker,
+		regionNone, regionMarker, regionOurs, regionMarker, regionBase, regionMarker, regionTheirs, regionMarker,
+		regionNone,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("classifyConflictLines() = %v, want %v", got, want)
+	}
+}
+
+func TestNextConflictLine(t *testing.T) {
+	starts := conflictMarkerLines(conflictedYAML)
+	if !reflect.DeepEqual(starts, []int{1, 7}) {
+		t.Fatalf("conflictMarkerLines() = %v, want [1 7]", starts)
+	}
+
+	tests := []struct {
+		cur, dir, want int
+	}{
+		{0, 1, 1},
+		{1, 1, 7},
+		{7, 1, 1}, // wraps
+		{8, -1, 7},
+		{7, -1, 1},
+		{1, -1, 7}, // wraps
+	}
+	for _, tt := range tests {
+		if got, ok := nextConflictLine(starts, tt.cur, tt.dir); !ok || got != tt.want {
+			t.Errorf("nextConflictLine(%d, %d) = %d, %v; want %d", tt.cur, tt.dir, got, ok, tt.want)
+		}
+	}
+	if _, ok := nextConflictLine(nil, 0, 1); ok {
+		t.Error("nextConflictLine() with no conflicts should report false")
+	}
+}
+
+func TestConflictResolver_ManualEdit(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "workflow.yaml")
+	if err := os.WriteFile(path, []byte(conflictedYAML), 0600); err != nil {
+		t.Fatal(err)
+	}
+	repo := testutil.NewFakeRepo(dir)
+	repo.SetConflicts("workflow.yaml")
+
+	m := NewConflictResolverModel(context.Background(), repo, []string{"workflow.yaml"})
+	m.State = ConflictStateResolving
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+	m = model.(ConflictResolverModel)
+	if m.State != ConflictStateEditing {
+		t.Fatalf("State = %v, want editing", m.State)
+	}
+	if m.Editor.Line() != 1 {
+		t.Errorf("cursor line = %d, want the first conflict", m.Editor.Line())
+	}
+
+	// Jump to the next conflict
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlN})
+	m = model.(ConflictResolverModel)
+	if m.Editor.Line() != 7 {
+		t.Errorf("cursor line after ctrl+n = %d, want 7", m.Editor.Line())
+	}
+
+	// Saving with markers left needs confirmation
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m = model.(ConflictResolverModel)
+	if m.State != ConflictStateEditing || m.editNotice == "" {
+		t.Fatal("save with markers should ask for confirmation")
+	}
+
+	// Resolve by hand and save
+	m.Editor.SetValue("title: Deploy\ncommand: make release\n")
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m = model.(ConflictResolverModel)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "title: Deploy\ncommand: make release\n" {
+		t.Errorf("saved content = %q", data)
+	}
+	if !m.Resolved["workflow.yaml"] {
+		t.Error("file should be marked resolved after saving")
+	}
+	if conflicts, _ := repo.GetConflicts(context.Background()); len(conflicts) != 0 {
+		t.Errorf("conflicts after saving = %v, want the file staged", conflicts)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("file mode = %v, want 0600 preserved", info.Mode().Perm())
+	}
+}
+
+func TestConflictResolver_TakeSide(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "workflow.yaml"), []byte(conflictedYAML), 0644); err != nil {
+		t.Fatal(err)
+	}
+	repo := testutil.NewFakeRepo(dir)
+	repo.SetConflicts("workflow.yaml")
+	repo.SetConflictDetails(gitrepo.ConflictDetails{
+		Path:   "workflow.yaml",
+		Ours:   []byte("title: Deploy\ncommand: make deploy\n"),
+		Theirs: []byte("title: Deploy\ncommand: make release\n"),
+	})
+
+	m := NewConflictResolverModel(ctx, repo, []string{"workflow.yaml"})
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = model.(ConflictResolverModel)
+	if !strings.Contains(m.TheirsContent, "make release") || !strings.Contains(m.DiffContent, "make deploy") {
+		t.Fatalf("expected both sides loaded, got ours %q, theirs %q", m.OursContent, m.TheirsContent)
+	}
+
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	m = model.(ConflictResolverModel)
+	if !m.Finished || !m.Resolved["workflow.yaml"] {
+		t.Fatal("taking theirs should resolve the only conflict")
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "workflow.yaml"))
+	if string(data) != "title: Deploy\ncommand: make release\n" {
+		t.Errorf("workflow.yaml = %q, want theirs", data)
+	}
+	if state, _ := repo.GetMergeState(ctx); len(state.Conflicts) != 0 {
+		t.Errorf("conflicts = %v, want none", state.Conflicts)
+	}
+}
+
+func TestRunConflictResolverLine(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	for _, name := range []string{"a.yaml", "b.yaml"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(conflictedYAML), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	repo := testutil.NewFakeRepo(dir)
+	repo.SetConflicts("a.yaml", "b.yaml")
+	repo.SetConflictDetails(gitrepo.ConflictDetails{Path: "a.yaml", Ours: []byte("ours\n"), Theirs: []byte("theirs\n")})
+
+	var out strings.Builder
+	p := NewLinePrompter(strings.NewReader("o\ns\n"), &out)
+	result, err := RunConflictResolverLine(ctx, repo, []string{"a.yaml", "b.yaml"}, p)
+	if err != nil {
+		t.Fatalf("RunConflictResolverLine() error = %v", err)
+	}
+	if result.ResolvedCount != 1 || result.TotalCount != 2 || result.Aborted {
+		t.Errorf("result = %+v, want 1 of 2 resolved", result)
+	}
+	if !strings.Contains(out.String(), "2 conflict hunk(s)") {
+		t.Errorf("expected the hunk summary, got:\n%s", out.String())
+	}
+	if conflicts, _ := repo.GetConflicts(ctx); len(conflicts) != 1 || conflicts[0] != "b.yaml" {
+		t.Errorf("conflicts = %v, want [b.yaml]", conflicts)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "a.yaml")); string(data) != "ours\n" {
+		t.Errorf("a.yaml = %q, want ours", data)
+	}
+}
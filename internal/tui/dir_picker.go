@@ -0,0 +1,216 @@
+// Package tui provides Bubble Tea models for svf.
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	//nolint:staticcheck // SA1019 - Using runner for path helpers
+	runnerpkg "github.com/chazuruo/svf/internal/runner"
+	"github.com/chazuruo/svf/internal/tui/theme"
+)
+
+// maxPickerEntries limits how many subdirectories are listed at once.
+const maxPickerEntries = 10
+
+// DirPickerModel is a small directory picker used when a step's working
+// directory is missing. It is embedded in other models rather than run
+// as a program on its own.
+type DirPickerModel struct {
+	// Missing is the directory that could not be found.
+	Missing string
+
+	// Input holds the path being edited.
+	Input textinput.Model
+
+	// Entries are the subdirectories of the directory being browsed.
+	Entries []string
+
+	// Cursor is the selected entry index, or -1 when the input is focused.
+	Cursor int
+
+	// Err is the last validation error.
+	Err string
+
+	// Chosen is set once a valid directory is accepted.
+	Chosen string
+
+	// Done indicates the picker finished (chosen or canceled).
+	Done bool
+
+	// Canceled indicates the user dismissed the picker.
+	Canceled bool
+}
+
+// NewDirPickerModel creates a picker starting at the nearest existing
+// ancestor of missing.
+func NewDirPickerModel(missing string) DirPickerModel {
+	start := runnerpkg.NearestExistingDir(missing)
+
+	ti := textinput.New()
+	ti.Placeholder = "/path/to/directory"
+	ti.CharLimit = 4096
+	ti.Width = 60
+	ti.SetValue(start)
+	ti.CursorEnd()
+	ti.Focus()
+
+	m := DirPickerModel{
+		Missing: missing,
+		Input:   ti,
+		Cursor:  -1,
+	}
+	m.refresh()
+	return m
+}
+
+// Update handles key input for the picker.
+func (m DirPickerModel) Update(msg tea.Msg) (DirPickerModel, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "esc", "ctrl+c":
+		m.Done = true
+		m.Canceled = true
+		return m, nil
+
+	case "up":
+		if m.Cursor >= 0 {
+			m.Cursor--
+		}
+		return m, nil
+
+	case "down":
+		if m.Cursor < len(m.Entries)-1 {
+			m.Cursor++
+		}
+		return m, nil
+
+	case "tab":
+		// Descend into the selected entry
+		if m.Cursor >= 0 && m.Cursor < len(m.Entries) {
+			m.Input.SetValue(filepath.Join(m.browseDir(), m.Entries[m.Cursor]))
+			m.Input.CursorEnd()
+			m.Cursor = -1
+			m.refresh()
+		}
+		return m, nil
+
+	case "enter":
+		path := m.Input.Value()
+		if m.Cursor >= 0 && m.Cursor < len(m.Entries) {
+			path = filepath.Join(m.browseDir(), m.Entries[m.Cursor])
+		}
+		path = runnerpkg.ExpandPath(strings.TrimSpace(path))
+		if err := runnerpkg.ValidateCWD(path); err != nil {
+			m.Err = err.Error()
+			return m, nil
+		}
+		m.Chosen = path
+		m.Done = true
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.Input, cmd = m.Input.Update(msg)
+	m.Cursor = -1
+	m.Err = ""
+	m.refresh()
+	return m, cmd
+}
+
+// View renders the picker.
+func (m DirPickerModel) View() string {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(theme.Current().Accent).
+		Bold(true)
+	errorStyle := lipgloss.NewStyle().
+		Foreground(theme.Current().Error)
+	dimStyle := lipgloss.NewStyle().
+		Foreground(theme.Current().Muted)
+	selectedStyle := lipgloss.NewStyle().
+		Foreground(theme.Current().Selected).
+		Bold(true)
+
+	b.WriteString(titleStyle.Render("Working directory not found"))
+	b.WriteString("\n\n")
+	b.WriteString(errorStyle.Render(fmt.Sprintf("  %s does not exist", m.Missing)))
+	b.WriteString("\n\n")
+	b.WriteString("Choose a directory to run this step in:\n\n")
+	b.WriteString(m.Input.View())
+	b.WriteString("\n\n")
+
+	if len(m.Entries) == 0 {
+		b.WriteString(dimStyle.Render("  (no subdirectories)"))
+		b.WriteString("\n")
+	}
+	for i, entry := range m.Entries {
+		if i == m.Cursor {
+			b.WriteString(selectedStyle.Render("→ " + entry + "/"))
+		} else {
+			b.WriteString(dimStyle.Render("  " + entry + "/"))
+		}
+		b.WriteString("\n")
+	}
+
+	if m.Err != "" {
+		b.WriteString("\n")
+		b.WriteString(errorStyle.Render("  " + m.Err))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(dimStyle.Render("[↑/↓] Select  [Tab] Open  [Enter] Use directory  [Esc] Cancel"))
+
+	return b.String()
+}
+
+// browseDir returns the directory whose children are listed.
+func (m DirPickerModel) browseDir() string {
+	return runnerpkg.NearestExistingDir(runnerpkg.ExpandPath(m.Input.Value()))
+}
+
+// refresh reloads the subdirectory listing for the current input.
+func (m *DirPickerModel) refresh() {
+	m.Entries = nil
+
+	dir := m.browseDir()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	// Filter by the partially typed final component, if any
+	input := runnerpkg.ExpandPath(m.Input.Value())
+	prefix := ""
+	if input != dir && filepath.Dir(input) == dir {
+		prefix = filepath.Base(input)
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() || strings.HasPrefix(name, ".") {
+			continue
+		}
+		if prefix != "" && !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		m.Entries = append(m.Entries, name)
+	}
+
+	sort.Strings(m.Entries)
+	if len(m.Entries) > maxPickerEntries {
+		m.Entries = m.Entries[:maxPickerEntries]
+	}
+}
@@ -0,0 +1,107 @@
+// Package tui provides Bubble Tea models for svf.
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chazuruo/svf/internal/tui/theme"
+)
+
+// ExplainViewModel shows an explanation in a scrollable viewport and
+// optionally lets the user save it (e.g. to a run record).
+type ExplainViewModel struct {
+	// Title is shown above the explanation.
+	Title string
+
+	// Subtitle is shown under the title (e.g. the failed command).
+	Subtitle string
+
+	// Viewport displays the explanation text.
+	Viewport viewport.Model
+
+	// CanSave enables the save key.
+	CanSave bool
+
+	// Saved is set when the user chose to save the explanation.
+	Saved bool
+}
+
+// NewExplainViewModel creates a viewer for text.
+func NewExplainViewModel(title, subtitle, text string, canSave bool) ExplainViewModel {
+	vp := viewport.New(80, 20)
+	vp.SetContent(text)
+
+	return ExplainViewModel{
+		Title:    title,
+		Subtitle: subtitle,
+		Viewport: vp,
+		CanSave:  canSave,
+	}
+}
+
+// Init initializes the model.
+func (m ExplainViewModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages.
+func (m ExplainViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		// Leave room for the header and footer
+		m.Viewport.Width = msg.Width
+		m.Viewport.Height = max(1, msg.Height-6)
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return m, tea.Quit
+		case "a":
+			if m.CanSave {
+				m.Saved = true
+				return m, tea.Quit
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	m.Viewport, cmd = m.Viewport.Update(msg)
+	return m, cmd
+}
+
+// View renders the model.
+func (m ExplainViewModel) View() string {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(theme.Current().Accent).
+		Bold(true)
+	dimStyle := lipgloss.NewStyle().
+		Foreground(theme.Current().Muted)
+
+	b.WriteString(titleStyle.Render(m.Title))
+	b.WriteString("\n")
+	if m.Subtitle != "" {
+		b.WriteString(dimStyle.Render(m.Subtitle))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(m.Viewport.View())
+	b.WriteString("\n\n")
+
+	help := "[↑/↓] Scroll  [q] Quit"
+	if m.CanSave {
+		help = "[↑/↓] Scroll  [a] Append to run record  [q] Quit"
+	}
+	b.WriteString(dimStyle.Render(help))
+
+	return b.String()
+}
+
+// DidSave returns true if the user chose to save the explanation.
+func (m ExplainViewModel) DidSave() bool {
+	return m.Saved
+}
@@ -3,13 +3,16 @@ package tui
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/chazuruo/svf/internal/history"
+	"github.com/chazuruo/svf/internal/tui/theme"
 )
 
 // HistoryPickerModel is a Bubble Tea model for selecting commands from shell history.
@@ -66,12 +69,12 @@ func NewHistoryPickerModel(commands []history.Command) HistoryPickerModel {
 
 	// Styles
 	normalStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240"))
+		Foreground(theme.Current().Border)
 	selectedStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("229")).
+		Foreground(theme.Current().Selected).
 		Bold(true)
 	previewStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("242"))
+		Foreground(theme.Current().Muted)
 
 	return HistoryPickerModel{
 		Commands:     commands,
@@ -190,7 +193,7 @@ func (m HistoryPickerModel) View() string {
 
 	// Header
 	b.WriteString("\n  ")
-	b.WriteString(lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("229")).Render("Shell History Picker"))
+	b.WriteString(lipgloss.NewStyle().Bold(true).Foreground(theme.Current().Selected).Render("Shell History Picker"))
 	b.WriteString("\n\n")
 
 	// Help text
@@ -228,7 +231,7 @@ func (m HistoryPickerModel) renderListColumn(width int) string {
 
 	// List header
 	b.WriteString("  ")
-	b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render(
+	b.WriteString(lipgloss.NewStyle().Foreground(theme.Current().Muted).Render(
 		fmt.Sprintf("%d commands, %d selected", len(m.Filtered), len(m.Selected)),
 	))
 	b.WriteString("\n\n")
@@ -257,7 +260,7 @@ func (m HistoryPickerModel) renderListColumn(width int) string {
 			}
 
 			// Command (truncated)
-			cmdText := cmd.Command
+			cmdText := cmd.Summary()
 			if len(cmdText) > 40 {
 				cmdText = cmdText[:37] + "..."
 			}
@@ -268,7 +271,7 @@ func (m HistoryPickerModel) renderListColumn(width int) string {
 				style = m.selectedStyle
 			}
 			if isSelected {
-				style = style.Foreground(lipgloss.Color("green"))
+				style = style.Foreground(theme.Current().Success)
 			}
 
 			line += style.Render(cmdText)
@@ -280,7 +283,7 @@ func (m HistoryPickerModel) renderListColumn(width int) string {
 	return lipgloss.NewStyle().
 		Width(width).
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("240")).
+		BorderForeground(theme.Current().Border).
 		Render(b.String())
 }
 
@@ -306,11 +309,26 @@ func (m HistoryPickerModel) renderPreviewColumn(width int) string {
 	if cmd.CWD != "" {
 		b.WriteString("  CWD:   " + cmd.CWD + "\n")
 	}
+	if cmd.Timestamp > 0 {
+		b.WriteString("  Ran:   " + time.Unix(cmd.Timestamp, 0).Format("2006-01-02 15:04:05") + "\n")
+	}
+	if cmd.Duration > 0 {
+		b.WriteString(fmt.Sprintf("  Took:  %s\n", time.Duration(cmd.Duration)*time.Second))
+	}
+	if cmd.ExitStatus != nil {
+		b.WriteString(fmt.Sprintf("  Exit:  %d\n", *cmd.ExitStatus))
+	}
+	if cmd.Host != "" {
+		b.WriteString("  Host:  " + cmd.Host + "\n")
+	}
+	if cmd.Session != "" {
+		b.WriteString("  tmux:  " + cmd.Session + "\n")
+	}
 
 	return lipgloss.NewStyle().
 		Width(width).
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("240")).
+		BorderForeground(theme.Current().Border).
 		Render(b.String())
 }
 
@@ -330,7 +348,7 @@ func (m HistoryPickerModel) helpText() string {
 		)
 	}
 
-	return lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render(
+	return lipgloss.NewStyle().Foreground(theme.Current().Muted).Render(
 		strings.Join(parts, " • "),
 	)
 }
@@ -370,3 +388,87 @@ func (m HistoryPickerModel) DidQuit() bool {
 func (m HistoryPickerModel) DidConfirm() bool {
 	return m.Confirmed
 }
+
+// PickHistoryLine selects commands with a numbered list and a line prompt.
+// It is the fallback for HistoryPickerModel when no TUI is available.
+// Returns nil if the user selected nothing.
+func PickHistoryLine(commands []history.Command, p *LinePrompter) ([]history.Command, error) {
+	for i, cmd := range commands {
+		p.Printf("%4d. %s\n", i+1, cmd.Summary())
+	}
+	p.Printf("\n")
+
+	for {
+		answer, err := p.Ask("Select commands (e.g. 1,3-5, all; empty to quit)", "")
+		if err != nil {
+			return nil, err
+		}
+		if answer == "" {
+			return nil, nil
+		}
+
+		indices, err := ParseSelection(answer, len(commands))
+		if err != nil {
+			p.Printf("%v\n", err)
+			continue
+		}
+
+		result := make([]history.Command, 0, len(indices))
+		for _, idx := range indices {
+			result = append(result, commands[idx])
+		}
+		return result, nil
+	}
+}
+
+// ParseSelection parses a selection like "1,3-5" or "all" into sorted,
+// de-duplicated zero-based indices for a list of n items.
+func ParseSelection(s string, n int) ([]int, error) {
+	s = strings.TrimSpace(s)
+	if strings.EqualFold(s, "all") || s == "*" {
+		result := make([]int, n)
+		for i := range result {
+			result[i] = i
+		}
+		return result, nil
+	}
+
+	seen := make(map[int]bool)
+	var result []int
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		lo, hi := part, part
+		if i := strings.Index(part, "-"); i > 0 {
+			lo, hi = part[:i], part[i+1:]
+		}
+
+		var start, end int
+		if _, err := fmt.Sscanf(lo, "%d", &start); err != nil {
+			return nil, fmt.Errorf("invalid selection %q", part)
+		}
+		if _, err := fmt.Sscanf(hi, "%d", &end); err != nil {
+			return nil, fmt.Errorf("invalid selection %q", part)
+		}
+		if start < 1 || end > n || start > end {
+			return nil, fmt.Errorf("selection %q out of range 1-%d", part, n)
+		}
+
+		for i := start; i <= end; i++ {
+			if !seen[i-1] {
+				seen[i-1] = true
+				result = append(result, i-1)
+			}
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("nothing selected")
+	}
+
+	sort.Ints(result)
+	return result, nil
+}
@@ -0,0 +1,155 @@
+// Package tui provides Bubble Tea models for svf.
+package tui
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/chazuruo/svf/internal/i18n"
+)
+
+// ErrNoInput is returned by line-mode prompts when input is exhausted.
+var ErrNoInput = errors.New("no input available (use --yes or --no-tui for non-interactive mode)")
+
+// IsTerminal reports whether f is attached to a terminal.
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// Available reports whether a full-screen TUI can be used: both stdin and
+// stdout must be terminals and TERM must not be "dumb".
+func Available() bool {
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return IsTerminal(os.Stdin) && IsTerminal(os.Stdout)
+}
+
+// LinePrompter asks questions one line at a time. It is the plain fallback
+// for every TUI when a full-screen terminal is unavailable, and works with
+// dumb terminals, screen readers, and piped input.
+type LinePrompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// NewLinePrompter creates a prompter reading from in and writing to out.
+func NewLinePrompter(in io.Reader, out io.Writer) *LinePrompter {
+	return &LinePrompter{
+		in:  bufio.NewReader(in),
+		out: out,
+	}
+}
+
+// NewStdioLinePrompter creates a prompter on stdin and stdout.
+func NewStdioLinePrompter() *LinePrompter {
+	return NewLinePrompter(os.Stdin, os.Stdout)
+}
+
+// Printf writes formatted output.
+func (p *LinePrompter) Printf(format string, args ...interface{}) {
+	_, _ = fmt.Fprintf(p.out, format, args...)
+}
+
+// readLine reads a trimmed line, returning ErrNoInput at EOF with no data.
+func (p *LinePrompter) readLine() (string, error) {
+	line, err := p.in.ReadString('\n')
+	if err != nil {
+		if err == io.EOF && line != "" {
+			return strings.TrimSpace(line), nil
+		}
+		if err == io.EOF {
+			return "", ErrNoInput
+		}
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// Ask prompts for a free-form answer, returning def on an empty line.
+func (p *LinePrompter) Ask(question, def string) (string, error) {
+	if def != "" {
+		p.Printf("%s [%s]: ", question, def)
+	} else {
+		p.Printf("%s: ", question)
+	}
+
+	answer, err := p.readLine()
+	if err != nil {
+		p.Printf("\n")
+		return "", err
+	}
+	if answer == "" {
+		return def, nil
+	}
+	return answer, nil
+}
+
+// Confirm prompts for a yes/no answer.
+func (p *LinePrompter) Confirm(question string, def bool) (bool, error) {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+
+	for {
+		p.Printf("%s [%s]: ", question, hint)
+		answer, err := p.readLine()
+		if err != nil {
+			p.Printf("\n")
+			return false, err
+		}
+
+		switch strings.ToLower(answer) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		p.Printf("%s\n", i18n.T("prompt.answer_yes_no"))
+	}
+}
+
+// Choice is a single-key option offered by Choose.
+type Choice struct {
+	Key   string
+	Label string
+}
+
+// Choose prompts until the user picks one of choices, returning its key.
+// An empty line selects def when def is non-empty.
+func (p *LinePrompter) Choose(question string, choices []Choice, def string) (string, error) {
+	labels := make([]string, len(choices))
+	for i, c := range choices {
+		labels[i] = fmt.Sprintf("[%s] %s", c.Key, c.Label)
+	}
+
+	for {
+		p.Printf("%s  %s: ", question, strings.Join(labels, "  "))
+		answer, err := p.readLine()
+		if err != nil {
+			p.Printf("\n")
+			return "", err
+		}
+
+		if answer == "" && def != "" {
+			return def, nil
+		}
+		for _, c := range choices {
+			if strings.EqualFold(answer, c.Key) {
+				return c.Key, nil
+			}
+		}
+		p.Printf("%s\n", i18n.T("prompt.unknown_choice", answer))
+	}
+}
@@ -0,0 +1,279 @@
+// Package tui provides tests for line-mode fallbacks.
+package tui
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/chazuruo/svf/internal/history"
+	//nolint:staticcheck // SA1019 - Using runner for Plan type
+	runnerpkg "github.com/chazuruo/svf/internal/runner"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+func newTestPrompter(input string) (*LinePrompter, *bytes.Buffer) {
+	var out bytes.Buffer
+	return NewLinePrompter(strings.NewReader(input), &out), &out
+}
+
+func TestLinePrompterAsk(t *testing.T) {
+	p, out := newTestPrompter("value\n\n")
+
+	got, err := p.Ask("Name", "def")
+	if err != nil || got != "value" {
+		t.Errorf("expected value, got %q (%v)", got, err)
+	}
+
+	got, err = p.Ask("Name", "def")
+	if err != nil || got != "def" {
+		t.Errorf("expected default, got %q (%v)", got, err)
+	}
+
+	if !strings.Contains(out.String(), "Name [def]: ") {
+		t.Errorf("expected prompt with default, got %q", out.String())
+	}
+
+	if _, err := p.Ask("Name", ""); !errors.Is(err, ErrNoInput) {
+		t.Errorf("expected ErrNoInput at EOF, got %v", err)
+	}
+}
+
+func TestLinePrompterConfirm(t *testing.T) {
+	p, _ := newTestPrompter("maybe\ny\n\nN\n")
+
+	if ok, err := p.Confirm("Continue?", false); err != nil || !ok {
+		t.Errorf("expected yes after retry, got %v (%v)", ok, err)
+	}
+	if ok, err := p.Confirm("Continue?", true); err != nil || !ok {
+		t.Errorf("expected default yes, got %v (%v)", ok, err)
+	}
+	if ok, err := p.Confirm("Continue?", true); err != nil || ok {
+		t.Errorf("expected no, got %v (%v)", ok, err)
+	}
+}
+
+func TestLinePrompterChoose(t *testing.T) {
+	p, _ := newTestPrompter("x\nS\n\n")
+	choices := []Choice{{Key: "r", Label: "run"}, {Key: "s", Label: "skip"}}
+
+	if got, err := p.Choose("", choices, "r"); err != nil || got != "s" {
+		t.Errorf("expected s, got %q (%v)", got, err)
+	}
+	if got, err := p.Choose("", choices, "r"); err != nil || got != "r" {
+		t.Errorf("expected default r, got %q (%v)", got, err)
+	}
+}
+
+func TestParseSelection(t *testing.T) {
+	tests := []struct {
+		input    string
+		n        int
+		expected []int
+		wantErr  bool
+	}{
+		{"1", 3, []int{0}, false},
+		{"3,1", 3, []int{0, 2}, false},
+		{"1-3", 5, []int{0, 1, 2}, false},
+		{"2-3, 2", 5, []int{1, 2}, false},
+		{"all", 3, []int{0, 1, 2}, false},
+		{"0", 3, nil, true},
+		{"4", 3, nil, true},
+		{"3-1", 3, nil, true},
+		{"abc", 3, nil, true},
+		{",", 3, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseSelection(tt.input, tt.n)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSelection(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ParseSelection(%q) = %v, want %v", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPickHistoryLine(t *testing.T) {
+	commands := []history.Command{
+		{Command: "git status"},
+		{Command: "make build"},
+		{Command: "make test"},
+	}
+	p, _ := newTestPrompter("9\n2-3\n")
+
+	got, err := PickHistoryLine(commands, p)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[0].Command != "make build" || got[1].Command != "make test" {
+		t.Errorf("unexpected selection: %+v", got)
+	}
+}
+
+func TestRedactLine(t *testing.T) {
+	content := "deploy with password=hunter22 please"
+	p, _ := newTestPrompter("y\ny\n")
+
+	got, ok, err := RedactLine(content, p)
+	if err != nil || !ok {
+		t.Fatalf("expected confirmation, got ok=%v err=%v", ok, err)
+	}
+	if strings.Contains(got, "hunter22") {
+		t.Errorf("expected secret to be redacted, got %q", got)
+	}
+}
+
+func TestEditWorkflowLine(t *testing.T) {
+	wf := &workflows.Workflow{
+		Title: "Old",
+		Steps: []workflows.Step{
+			{Name: "one", Command: "echo 1"},
+			{Name: "two", Command: "echo 2"},
+		},
+	}
+	// title, description, tags, keep step one, delete step two,
+	// add a step (name, command), stop adding, save
+	input := "New\n\nops, deploy\nk\nd\ny\nthree\necho 3\nn\ny\n"
+	p, _ := newTestPrompter(input)
+
+	saved, err := EditWorkflowLine(wf, p)
+	if err != nil || !saved {
+		t.Fatalf("expected save, got saved=%v err=%v", saved, err)
+	}
+	if wf.Title != "New" {
+		t.Errorf("expected title New, got %q", wf.Title)
+	}
+	if !reflect.DeepEqual(wf.Tags, []string{"ops", "deploy"}) {
+		t.Errorf("unexpected tags: %v", wf.Tags)
+	}
+	if len(wf.Steps) != 2 || wf.Steps[0].Command != "echo 1" || wf.Steps[1].Command != "echo 3" {
+		t.Errorf("unexpected steps: %+v", wf.Steps)
+	}
+}
+
+func TestRunWorkflowLine(t *testing.T) {
+	wf := &workflows.Workflow{
+		Title: "Test",
+		Steps: []workflows.Step{
+			{Name: "greet", Command: "echo hello <name>", Links: []workflows.Link{{Title: "Docs", URL: "https://example.com/<name>"}}},
+			{Name: "skipped", Command: "false"},
+		},
+	}
+	plan := runnerpkg.Plan{Workflow: wf, Parameters: map[string]string{}}
+
+	// placeholder value, start, run first step, skip second
+	p, out := newTestPrompter("world\ny\nr\ns\n")
+
+	result, err := RunWorkflowLine(context.Background(), plan, nil, p)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Success {
+		t.Errorf("expected success, got %+v", result)
+	}
+	if !strings.Contains(out.String(), "hello world") {
+		t.Errorf("expected command output, got %q", out.String())
+	}
+	if !strings.Contains(out.String(), "→ Docs: https://example.com/world") {
+		t.Errorf("expected the step's link, got %q", out.String())
+	}
+	if !result.Results[1].Skipped {
+		t.Error("expected second step to be skipped")
+	}
+}
+
+func TestRunWorkflowLineDeclineSummary(t *testing.T) {
+	wf := &workflows.Workflow{
+		Title: "Test",
+		Steps: []workflows.Step{{Name: "one", Command: "echo ran"}},
+	}
+	p, out := newTestPrompter("n\n")
+
+	result, err := RunWorkflowLine(context.Background(), runnerpkg.Plan{Workflow: wf}, nil, p)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Canceled {
+		t.Errorf("expected a canceled run, got %+v", result)
+	}
+	if !strings.Contains(out.String(), "Ready to run: Test") || strings.Contains(out.String(), "ran\n") {
+		t.Errorf("expected only the summary in output, got %q", out.String())
+	}
+}
+
+func TestRunWorkflowLineNoInput(t *testing.T) {
+	wf := &workflows.Workflow{
+		Title: "Test",
+		Steps: []workflows.Step{{Name: "one", Command: "echo 1"}},
+	}
+	p, _ := newTestPrompter("")
+
+	_, err := RunWorkflowLine(context.Background(), runnerpkg.Plan{Workflow: wf}, nil, p)
+	if !errors.Is(err, ErrNoInput) {
+		t.Errorf("expected ErrNoInput, got %v", err)
+	}
+}
+
+func TestRunWorkflowLineCapture(t *testing.T) {
+	wf := &workflows.Workflow{
+		Title: "Test",
+		Steps: []workflows.Step{
+			{
+				Name:    "list",
+				Command: "printf 'NAME STATUS\\napi-1 Running\\n'",
+				Capture: map[string]workflows.Extractor{"pod": {Line: 2, Field: 1}},
+			},
+			{Name: "use", Command: "echo restarting <pod>"},
+		},
+	}
+	plan := runnerpkg.Plan{Workflow: wf, Parameters: map[string]string{}}
+
+	// No prompt for <pod>: start and run both steps
+	p, out := newTestPrompter("\nr\nr\n")
+
+	result, err := RunWorkflowLine(context.Background(), plan, nil, p)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Success {
+		t.Errorf("expected success, got %+v", result)
+	}
+	if !strings.Contains(out.String(), "restarting api-1") {
+		t.Errorf("expected captured value in output, got %q", out.String())
+	}
+}
+
+func TestRunWorkflowLinePreset(t *testing.T) {
+	wf := &workflows.Workflow{
+		Title: "Test",
+		Steps: []workflows.Step{{Name: "deploy", Command: "echo deploying <env> x<replicas> as <user>"}},
+		Presets: map[string]map[string]string{
+			"prod":    {"env": "prod", "replicas": "6"},
+			"staging": {"env": "staging", "replicas": "2"},
+		},
+	}
+	plan := runnerpkg.Plan{Workflow: wf, Parameters: map[string]string{"replicas": "3"}}
+
+	// Pick an unknown number, then staging; <user> is still prompted
+	p, out := newTestPrompter("7\n2\nalice\n\nr\n")
+
+	result, err := RunWorkflowLine(context.Background(), plan, nil, p)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Success {
+		t.Errorf("expected success, got %+v", result)
+	}
+	for _, want := range []string{"1. prod (env=prod replicas=6)", "Enter a number from 1 to 2.", "deploying staging x3 as alice"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in output, got %q", want, out.String())
+		}
+	}
+}
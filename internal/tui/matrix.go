@@ -0,0 +1,229 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chazuruo/svf/internal/i18n"
+	"github.com/chazuruo/svf/internal/tui/theme"
+)
+
+// MatrixStatus is the state of one matrix combination.
+type MatrixStatus int
+
+const (
+	// MatrixPending means the combination hasn't started.
+	MatrixPending MatrixStatus = iota
+	// MatrixRunning means the combination is running.
+	MatrixRunning
+	// MatrixSucceeded means every step of the combination succeeded.
+	MatrixSucceeded
+	// MatrixFailed means a step of the combination failed.
+	MatrixFailed
+	// MatrixSkipped means the matrix was canceled before the combination ran.
+	MatrixSkipped
+)
+
+// Icon returns the status icon shown in the dashboard and summaries.
+func (s MatrixStatus) Icon() string {
+	switch s {
+	case MatrixRunning:
+		return "▶"
+	case MatrixSucceeded:
+		return "✓"
+	case MatrixFailed:
+		return "✗"
+	case MatrixSkipped:
+		return "-"
+	default:
+		return "·"
+	}
+}
+
+// MatrixResult is the outcome of running one combination.
+type MatrixResult struct {
+	Success    bool
+	Canceled   bool
+	FailedStep int // 0-based index of the failed step
+	ExitCode   int
+	Duration   time.Duration
+	Err        error
+}
+
+// MatrixRow is one combination in the matrix dashboard.
+type MatrixRow struct {
+	Label  string
+	Status MatrixStatus
+	Result MatrixResult
+}
+
+// Detail describes the row's result, e.g. "failed at step 2 (exit code 3)".
+func (r MatrixRow) Detail() string {
+	switch r.Status {
+	case MatrixRunning:
+		return i18n.T("matrix.running")
+	case MatrixSucceeded:
+		return r.Result.Duration.Round(time.Millisecond).String()
+	case MatrixFailed:
+		if r.Result.Canceled {
+			return i18n.T("matrix.canceled")
+		}
+		if r.Result.Err != nil && r.Result.ExitCode == 0 {
+			return r.Result.Err.Error()
+		}
+		return i18n.T("matrix.failed_at", r.Result.FailedStep+1, r.Result.ExitCode)
+	case MatrixSkipped:
+		return i18n.T("matrix.skipped")
+	default:
+		return ""
+	}
+}
+
+// MatrixRunFunc runs the combination at index. It should stop when ctx is
+// canceled.
+type MatrixRunFunc func(ctx context.Context, index int) MatrixResult
+
+// matrixDoneMsg is sent when a combination finishes.
+type matrixDoneMsg struct {
+	Index  int
+	Result MatrixResult
+}
+
+// MatrixModel is a dashboard that runs matrix combinations one after
+// another and shows the status of each.
+type MatrixModel struct {
+	Title    string
+	Rows     []MatrixRow
+	Run      MatrixRunFunc
+	Current  int
+	Canceled bool
+	cancel   context.CancelFunc
+}
+
+// NewMatrixModel creates a dashboard for the combinations with the given
+// labels.
+func NewMatrixModel(title string, labels []string, run MatrixRunFunc) MatrixModel {
+	rows := make([]MatrixRow, len(labels))
+	for i, label := range labels {
+		rows[i] = MatrixRow{Label: label}
+	}
+	return MatrixModel{Title: title, Rows: rows, Run: run}
+}
+
+// Init starts the first combination.
+func (m MatrixModel) Init() tea.Cmd {
+	return m.startCmd(0)
+}
+
+// startCmd returns a command that runs the combination at index.
+func (m MatrixModel) startCmd(index int) tea.Cmd {
+	if index >= len(m.Rows) {
+		return tea.Quit
+	}
+	return func() tea.Msg {
+		return matrixStartMsg{Index: index}
+	}
+}
+
+// matrixStartMsg asks the model to start a combination, so its cancel
+// function is stored on the model before the run begins.
+type matrixStartMsg struct {
+	Index int
+}
+
+// Update handles messages.
+func (m MatrixModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case matrixStartMsg:
+		if m.Canceled {
+			return m, tea.Quit
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		m.cancel = cancel
+		m.Current = msg.Index
+		m.Rows[msg.Index].Status = MatrixRunning
+		run := m.Run
+		return m, func() tea.Msg {
+			defer cancel()
+			return matrixDoneMsg{Index: msg.Index, Result: run(ctx, msg.Index)}
+		}
+
+	case matrixDoneMsg:
+		m.cancel = nil
+		row := &m.Rows[msg.Index]
+		row.Result = msg.Result
+		row.Status = MatrixFailed
+		if msg.Result.Success {
+			row.Status = MatrixSucceeded
+		}
+		if m.Canceled {
+			m.skipRemaining(msg.Index + 1)
+			return m, tea.Quit
+		}
+		return m, m.startCmd(msg.Index + 1)
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c", "esc":
+			if m.Canceled {
+				return m, nil
+			}
+			m.Canceled = true
+			if m.cancel != nil {
+				m.cancel()
+				return m, nil
+			}
+			m.skipRemaining(m.Current)
+			return m, tea.Quit
+		}
+	}
+	return m, nil
+}
+
+// skipRemaining marks combinations from index on as skipped.
+func (m *MatrixModel) skipRemaining(index int) {
+	for i := index; i < len(m.Rows); i++ {
+		if m.Rows[i].Status == MatrixPending {
+			m.Rows[i].Status = MatrixSkipped
+		}
+	}
+}
+
+// View renders the dashboard.
+func (m MatrixModel) View() string {
+	t := theme.Current()
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(t.Accent)
+	mutedStyle := lipgloss.NewStyle().Foreground(t.Muted)
+	statusStyles := map[MatrixStatus]lipgloss.Style{
+		MatrixPending:   mutedStyle,
+		MatrixRunning:   lipgloss.NewStyle().Foreground(t.Info),
+		MatrixSucceeded: lipgloss.NewStyle().Foreground(t.Success),
+		MatrixFailed:    lipgloss.NewStyle().Foreground(t.Error),
+		MatrixSkipped:   mutedStyle,
+	}
+
+	width := 0
+	for _, row := range m.Rows {
+		width = max(width, len(row.Label))
+	}
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(i18n.T("matrix.title", m.Title, len(m.Rows))))
+	b.WriteString("\n\n")
+	for _, row := range m.Rows {
+		style := statusStyles[row.Status]
+		fmt.Fprintf(&b, "  %s %-*s  %s\n", style.Render(row.Status.Icon()), width, row.Label, mutedStyle.Render(row.Detail()))
+	}
+	b.WriteString("\n")
+	if m.Canceled {
+		b.WriteString(mutedStyle.Render(i18n.T("matrix.canceling")))
+	} else {
+		b.WriteString(mutedStyle.Render(i18n.T("matrix.cancel")))
+	}
+	b.WriteString("\n")
+	return b.String()
+}
@@ -0,0 +1,74 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestMatrixModel_RunsEachCombination(t *testing.T) {
+	var ran []int
+	m := NewMatrixModel("Deploy", []string{"region=us-east-1", "region=eu-west-1"}, func(_ context.Context, i int) MatrixResult {
+		ran = append(ran, i)
+		return MatrixResult{Success: i == 0, ExitCode: 3 * i}
+	})
+
+	// Drive the model like the Bubble Tea runtime would
+	var model tea.Model = m
+	msg := m.Init()()
+	for msg != nil {
+		var cmd tea.Cmd
+		model, cmd = model.Update(msg)
+		if cmd == nil {
+			break
+		}
+		msg = cmd()
+		if _, ok := msg.(tea.QuitMsg); ok {
+			break
+		}
+	}
+
+	final := model.(MatrixModel)
+	if len(ran) != 2 {
+		t.Fatalf("expected 2 combinations to run, got %v", ran)
+	}
+	if final.Rows[0].Status != MatrixSucceeded || final.Rows[1].Status != MatrixFailed {
+		t.Errorf("unexpected statuses: %v, %v", final.Rows[0].Status, final.Rows[1].Status)
+	}
+	view := final.View()
+	if !strings.Contains(view, "failed at step 1 (exit code 3)") {
+		t.Errorf("view missing failure detail:\n%s", view)
+	}
+}
+
+func TestMatrixModel_Cancel(t *testing.T) {
+	m := NewMatrixModel("Deploy", []string{"a=1", "a=2", "a=3"}, func(ctx context.Context, _ int) MatrixResult {
+		<-ctx.Done()
+		return MatrixResult{Canceled: true, ExitCode: 13}
+	})
+
+	model, cmd := m.Update(m.Init()())
+	run := cmd
+
+	// Quitting while a combination runs cancels it and skips the rest
+	model, cmd = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	if cmd != nil {
+		t.Error("expected to wait for the running combination")
+	}
+	model, cmd = model.Update(run())
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("expected quit after the canceled combination finished")
+	}
+
+	final := model.(MatrixModel)
+	if final.Rows[0].Detail() != "canceled" {
+		t.Errorf("Rows[0].Detail() = %q, want canceled", final.Rows[0].Detail())
+	}
+	for _, row := range final.Rows[1:] {
+		if row.Status != MatrixSkipped {
+			t.Errorf("expected %s to be skipped, got %v", row.Label, row.Status)
+		}
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/chazuruo/svf/internal/tui/theme"
 	"github.com/chazuruo/svf/internal/workflows"
 )
 
@@ -266,12 +267,12 @@ func (m *PlaceholderEditorModel) View() string {
 // renderListView renders the placeholder list view.
 func (m *PlaceholderEditorModel) renderListView() string {
 	titleStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("86")). // Pink
+		Foreground(theme.Current().Accent). // Pink
 		Bold(true).
 		MarginBottom(1)
 
 	footerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241")). // Grey
+		Foreground(theme.Current().Muted). // Grey
 		MarginTop(1)
 
 	title := titleStyle.Render("Placeholder Editor")
@@ -290,16 +291,16 @@ func (m *PlaceholderEditorModel) renderListView() string {
 // renderEditView renders the edit view for a single placeholder.
 func (m *PlaceholderEditorModel) renderEditView() string {
 	titleStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("86")). // Pink
+		Foreground(theme.Current().Accent). // Pink
 		Bold(true).
 		MarginBottom(1)
 
 	labelStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("242")). // Grey
+		Foreground(theme.Current().Muted). // Grey
 		Width(12)
 
 	highlightStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("226")). // Yellow
+		Foreground(theme.Current().Warning). // Yellow
 		Bold(true)
 
 	title := titleStyle.Render("Edit Placeholder: " + m.currentPlaceholder)
@@ -318,7 +319,7 @@ func (m *PlaceholderEditorModel) renderEditView() string {
 
 	// Footer
 	footerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241")). // Grey
+		Foreground(theme.Current().Muted). // Grey
 		MarginTop(1)
 
 	footer := footerStyle.Render(
@@ -348,14 +349,14 @@ func (d placeholderDelegate) Render(w io.Writer, m list.Model, index int, listIt
 
 	// Styles
 	selectedStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("229")).
+		Foreground(theme.Current().Selected).
 		Bold(true)
 
 	normalStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("251"))
+		Foreground(theme.Current().Text)
 
 	undefStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("203"))
+		Foreground(theme.Current().Error)
 
 	// Choose style based on selection and definition
 	var nameStyle lipgloss.Style
@@ -384,10 +385,10 @@ func (d placeholderDelegate) Render(w io.Writer, m list.Model, index int, listIt
 
 	// Second line: default value
 	if p.placeholder.Default != "" {
-		defaultText := lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Render("  Default: " + p.placeholder.Default)
+		defaultText := lipgloss.NewStyle().Foreground(theme.Current().Muted).Render("  Default: " + p.placeholder.Default)
 		_, _ = fmt.Fprintf(w, "%s\n", defaultText)
 	} else if p.placeholder.Secret {
-		secretText := lipgloss.NewStyle().Foreground(lipgloss.Color("203")).Render("  [secret]")
+		secretText := lipgloss.NewStyle().Foreground(theme.Current().Error).Render("  [secret]")
 		_, _ = fmt.Fprintf(w, "%s\n", secretText)
 	}
 }
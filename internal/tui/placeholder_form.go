@@ -0,0 +1,141 @@
+package tui
+
+import (
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/chazuruo/svf/internal/i18n"
+	"github.com/chazuruo/svf/internal/placeholders"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// usesPlaceholderForm reports whether placeholders are asked for all at
+// once in a form (placeholders.prompt_style = "form") rather than one at
+// a time.
+func (m RunnerModel) usesPlaceholderForm() bool {
+	return m.Config != nil && m.Config.Placeholders.PromptStyle == "form"
+}
+
+// startPrompting prompts for the placeholders without a value, in the
+// form or one at a time. It moves on to the summary if there are none.
+func (m *RunnerModel) startPrompting() tea.Cmd {
+	m.CurrentPlaceholder = ""
+	missing := missingPlaceholders(m.Plan.Workflow, m.PlaceholderInfo, m.Placeholders)
+	if len(missing) == 0 {
+		m.State = StateSummary
+		return nil
+	}
+	if m.usesPlaceholderForm() {
+		m.placeholderForm, m.formValues = newPlaceholderForm(m.PlaceholderInfo, missing)
+		return m.placeholderForm.Init()
+	}
+	m.CurrentPlaceholder = missing[0]
+	m.setupPlaceholderInput()
+	return nil
+}
+
+// missingPlaceholders returns the placeholders in info without a value in
+// params, in the order the workflow first uses them.
+func missingPlaceholders(wf *workflows.Workflow, info map[string]placeholders.PlaceholderInfo, params map[string]string) []string {
+	var missing []string
+	seen := make(map[string]bool)
+	for _, name := range placeholders.CollectFromSteps(append(append([]workflows.Step(nil), wf.Steps...), wf.Cleanup...)) {
+		seen[name] = true
+		if _, ok := info[name]; ok {
+			if _, ok := params[name]; !ok {
+				missing = append(missing, name)
+			}
+		}
+	}
+	// Placeholders used outside step commands, e.g. in a cwd
+	var rest []string
+	for name := range info {
+		if _, ok := params[name]; !ok && !seen[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	return append(missing, rest...)
+}
+
+// newPlaceholderForm builds a form asking for the placeholders in names
+// at once, with their defaults filled in. Tab and shift+tab move between
+// fields; enter on the last one submits. The values entered are kept in
+// the returned map.
+func newPlaceholderForm(info map[string]placeholders.PlaceholderInfo, names []string) (*huh.Form, map[string]*string) {
+	values := make(map[string]*string, len(names))
+	fields := make([]huh.Field, 0, len(names))
+	for _, name := range names {
+		ph := info[name]
+		value := ph.Default
+		values[name] = &value
+
+		title := ph.Prompt
+		if title == "" {
+			title = i18n.T("placeholders.value_for", name)
+		}
+		var description []string
+		if len(ph.UsedIn) > 0 {
+			description = append(description, i18n.T("placeholders.used_in", strings.Join(ph.UsedIn, ", ")))
+		}
+		if ph.Default != "" && !ph.Secret {
+			description = append(description, i18n.T("placeholders.default", ph.Default))
+		}
+
+		input := huh.NewInput().
+			Key(name).
+			Title(title).
+			Description(strings.Join(description, " · ")).
+			Placeholder(i18n.T("placeholders.enter")).
+			Value(&value)
+		if ph.Secret {
+			input = input.EchoMode(huh.EchoModePassword)
+		}
+		if pattern := ph.Validate; pattern != "" {
+			input = input.Validate(func(s string) error {
+				return placeholders.Validate(s, pattern)
+			})
+		}
+		fields = append(fields, input)
+	}
+
+	form := huh.NewForm(huh.NewGroup(fields...).Title(i18n.T("placeholders.title"))).
+		WithShowHelp(true)
+	return form, values
+}
+
+// handlePlaceholderForm passes msg to the placeholder form. Once it is
+// submitted the values are kept and the run is summarized; aborting it
+// cancels the run.
+func (m RunnerModel) handlePlaceholderForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	updated, cmd := m.placeholderForm.Update(msg)
+	m.placeholderForm = updated.(*huh.Form)
+
+	switch m.placeholderForm.State {
+	case huh.StateAborted:
+		m.placeholderForm = nil
+		m.Canceled = true
+		m.Finished = true
+		m.State = StateFinished
+		return m, tea.Quit
+	case huh.StateCompleted:
+		if m.Placeholders == nil {
+			m.Placeholders = make(map[string]string)
+		}
+		for name, value := range m.formValues {
+			m.Placeholders[name] = *value
+		}
+		m.placeholderForm = nil
+		m.formValues = nil
+		m.State = StateSummary
+		return m, nil
+	}
+	return m, cmd
+}
+
+// placeholderFormView renders the placeholder form.
+func (m RunnerModel) placeholderFormView() string {
+	return m.placeholderForm.View()
+}
@@ -0,0 +1,103 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/chazuruo/svf/internal/config"
+	runnerpkg "github.com/chazuruo/svf/internal/runner"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+func newFormTestModel(style string, presets map[string]map[string]string) RunnerModel {
+	wf := &workflows.Workflow{
+		Title: "Deploy",
+		Steps: []workflows.Step{
+			{Name: "login", Command: "login --token <token>"},
+			{Name: "deploy", Command: "deploy <env> <replicas>"},
+		},
+		Placeholders: map[string]workflows.Placeholder{
+			"token":    {Prompt: "API token", Default: "hunter2", Secret: true},
+			"replicas": {Default: "3", Validate: "^[0-9]+$"},
+		},
+		Presets: presets,
+	}
+	cfg := config.DefaultConfig()
+	cfg.Placeholders.PromptStyle = style
+	return NewRunnerModelWithConfig(runnerpkg.Plan{Workflow: wf}, cfg)
+}
+
+func TestRunnerPlaceholderForm(t *testing.T) {
+	m := newFormTestModel("form", nil)
+	if m.State != StatePrompting || m.placeholderForm == nil {
+		t.Fatalf("expected the placeholder form, got state %v", m.State)
+	}
+	if m.Init() == nil {
+		t.Error("expected Init to start the form")
+	}
+	if got := missingPlaceholders(m.Plan.Workflow, m.PlaceholderInfo, nil); strings.Join(got, ",") != "token,env,replicas" {
+		t.Errorf("expected placeholders in order of use, got %v", got)
+	}
+
+	view := m.View()
+	for _, want := range []string{"API token", "Value for <env>", "Value for <replicas>", "Default: 3"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in form view:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "hunter2") {
+		t.Errorf("secret default shown in form view:\n%s", view)
+	}
+
+	// Submitting keeps every value, defaults included
+	*m.formValues["env"] = "staging"
+	m.placeholderForm.State = huh.StateCompleted
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	m = updated.(RunnerModel)
+	if m.State != StateSummary || m.placeholderForm != nil {
+		t.Fatalf("expected StateSummary after submitting, got %v", m.State)
+	}
+	want := map[string]string{"token": "hunter2", "env": "staging", "replicas": "3"}
+	for name, value := range want {
+		if m.Placeholders[name] != value {
+			t.Errorf("<%s> = %q, want %q", name, m.Placeholders[name], value)
+		}
+	}
+
+	// Ctrl+C cancels the run
+	canceled := newFormTestModel("form", nil)
+	updated, _ = canceled.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	if c := updated.(RunnerModel); !c.Canceled || c.State != StateFinished {
+		t.Errorf("expected ctrl+c to cancel, got state %v", c.State)
+	}
+}
+
+func TestRunnerPlaceholderForm_PerStep(t *testing.T) {
+	m := newFormTestModel("per-step", nil)
+	if m.placeholderForm != nil {
+		t.Fatal("per-step prompting shouldn't use the form")
+	}
+	m = sendKeys(m, "enter")
+	if m.CurrentPlaceholder == "" || m.State != StatePrompting {
+		t.Errorf("expected a prompt for the next placeholder, got %v", m.State)
+	}
+}
+
+func TestRunnerPlaceholderForm_AfterPreset(t *testing.T) {
+	m := newFormTestModel("form", map[string]map[string]string{"staging": {"env": "staging"}})
+	if !m.ChoosingPreset || m.placeholderForm != nil {
+		t.Fatal("expected presets to be offered before the form")
+	}
+	m = sendKeys(m, "1")
+	if m.placeholderForm == nil {
+		t.Fatal("expected the form for the placeholders the preset leaves")
+	}
+	if _, ok := m.formValues["env"]; ok {
+		t.Error("the form shouldn't ask for <env> set by the preset")
+	}
+	if len(m.formValues) != 2 {
+		t.Errorf("expected the form to ask for <token> and <replicas>, got %d fields", len(m.formValues))
+	}
+}
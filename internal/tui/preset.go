@@ -0,0 +1,135 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chazuruo/svf/internal/i18n"
+	"github.com/chazuruo/svf/internal/placeholders"
+	"github.com/chazuruo/svf/internal/tui/theme"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// presetsMissing reports whether the workflow has presets that would fill
+// a placeholder that has no value yet.
+func presetsMissing(wf *workflows.Workflow, phInfo map[string]placeholders.PlaceholderInfo, params map[string]string) bool {
+	for _, preset := range wf.Presets {
+		for name := range preset {
+			if _, used := phInfo[name]; !used {
+				continue
+			}
+			if _, ok := params[name]; !ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// applyPresetValues copies the preset's values for used placeholders into
+// params, keeping values that are already set.
+func applyPresetValues(wf *workflows.Workflow, name string, phInfo map[string]placeholders.PlaceholderInfo, params map[string]string) {
+	for key, value := range wf.Presets[name] {
+		if _, used := phInfo[key]; !used {
+			continue
+		}
+		if _, ok := params[key]; !ok {
+			params[key] = value
+		}
+	}
+}
+
+// presetLabel describes a preset, e.g. "staging (env=staging replicas=2)".
+func presetLabel(wf *workflows.Workflow, name string) string {
+	return fmt.Sprintf("%s (%s)", name, workflows.MatrixLabel(wf.Presets[name]))
+}
+
+// choosePresetLine offers the workflow's presets, applying the chosen one
+// to params. Placeholders the preset doesn't fill are prompted for after.
+func choosePresetLine(wf *workflows.Workflow, phInfo map[string]placeholders.PlaceholderInfo, params map[string]string, p *LinePrompter) error {
+	names := wf.PresetNames()
+	p.Printf("%s\n", i18n.T("presets.list"))
+	for i, name := range names {
+		p.Printf("  %d. %s\n", i+1, presetLabel(wf, name))
+	}
+	for {
+		answer, err := p.Ask(i18n.T("presets.ask"), "")
+		if err != nil {
+			return err
+		}
+		if answer == "" {
+			return nil
+		}
+		if i, err := strconv.Atoi(answer); err == nil && i >= 1 && i <= len(names) {
+			applyPresetValues(wf, names[i-1], phInfo, params)
+			return nil
+		}
+		p.Printf("%s\n", i18n.T("presets.invalid", len(names)))
+	}
+}
+
+// handlePresetChoice handles keys while the presets are offered. Digits
+// pick a preset; enter or n skips to prompting for each value.
+func (m RunnerModel) handlePresetChoice(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	names := m.Plan.Workflow.PresetNames()
+	switch keyMsg.String() {
+	case "ctrl+c", "q":
+		m.Canceled = true
+		m.Finished = true
+		m.State = StateFinished
+		return m, tea.Quit
+	case "enter", "n", "esc":
+	default:
+		i, err := strconv.Atoi(keyMsg.String())
+		if err != nil || i < 1 || i > len(names) {
+			return m, nil
+		}
+		if m.Placeholders == nil {
+			m.Placeholders = make(map[string]string)
+		}
+		applyPresetValues(m.Plan.Workflow, names[i-1], m.PlaceholderInfo, m.Placeholders)
+	}
+
+	m.ChoosingPreset = false
+	return m, m.startPrompting()
+}
+
+// presetView renders the list of presets offered before prompting.
+func (m RunnerModel) presetView() string {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(theme.Current().Accent).
+		Bold(true).
+		MarginBottom(1)
+	b.WriteString(titleStyle.Render(i18n.T("presets.title") + "\n\n"))
+
+	textStyle := lipgloss.NewStyle().Foreground(theme.Current().Text)
+	for i, name := range m.Plan.Workflow.PresetNames() {
+		if i == 9 {
+			break
+		}
+		b.WriteString(textStyle.Render(fmt.Sprintf("  [%d] %s", i+1, presetLabel(m.Plan.Workflow, name))))
+		b.WriteString("\n")
+	}
+
+	footerStyle := lipgloss.NewStyle().
+		Foreground(theme.Current().Muted).
+		MarginTop(2)
+	b.WriteString(footerStyle.Render("\n" + i18n.T("presets.footer")))
+
+	return lipgloss.NewStyle().
+		Width(80).
+		Padding(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.Current().Border).
+		Render(b.String())
+}
@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	runnerpkg "github.com/chazuruo/svf/internal/runner"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+func newPresetTestModel() RunnerModel {
+	wf := &workflows.Workflow{
+		Title: "Deploy",
+		Steps: []workflows.Step{{Name: "deploy", Command: "deploy <env> <replicas>"}},
+		Presets: map[string]map[string]string{
+			"prod":    {"env": "prod", "replicas": "6"},
+			"staging": {"env": "staging"},
+		},
+	}
+	return NewRunnerModel(runnerpkg.Plan{Workflow: wf}, nil, false, false)
+}
+
+func TestRunnerPresetChoice(t *testing.T) {
+	m := newPresetTestModel()
+	if m.State != StatePrompting || !m.ChoosingPreset {
+		t.Fatalf("expected presets to be offered first, got state %v choosing=%v", m.State, m.ChoosingPreset)
+	}
+	if view := m.View(); !strings.Contains(view, "[2] staging (env=staging)") {
+		t.Errorf("expected presets in view, got %q", view)
+	}
+
+	// Out-of-range digits are ignored
+	m = sendKeys(m, "5")
+	if !m.ChoosingPreset {
+		t.Fatal("an unknown preset number should keep the presets open")
+	}
+
+	// A full preset fills every placeholder
+	full := sendKeys(m, "1")
+	if full.State != StateSummary || full.Placeholders["env"] != "prod" || full.Placeholders["replicas"] != "6" {
+		t.Errorf("expected prod values and StateSummary, got %v %v", full.State, full.Placeholders)
+	}
+
+	// A partial preset prompts for the rest
+	partial := sendKeys(m, "2")
+	if partial.State != StatePrompting || partial.CurrentPlaceholder != "replicas" {
+		t.Errorf("expected a prompt for <replicas>, got %v %q", partial.State, partial.CurrentPlaceholder)
+	}
+
+	// Enter skips the presets
+	skipped := sendKeys(m, "enter")
+	if skipped.ChoosingPreset || len(skipped.Placeholders) != 0 || skipped.CurrentPlaceholder == "" {
+		t.Errorf("expected plain prompting, got %v %q", skipped.Placeholders, skipped.CurrentPlaceholder)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/chazuruo/svf/internal/tui/theme"
 )
 
 // RedactionModel is a Bubble Tea model for redacting sensitive data.
@@ -272,33 +273,33 @@ func NewRedactionModel(content string) RedactionModel {
 
 	// Styles
 	normalStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("251"))
+		Foreground(theme.Current().Text)
 	selectedStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("229")).
+		Foreground(theme.Current().Selected).
 		Bold(true)
 	redactedStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241")).
-		Background(lipgloss.Color("235"))
+		Foreground(theme.Current().Muted).
+		Background(theme.Current().Surface)
 	warningStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("226")).
+		Foreground(theme.Current().Warning).
 		Bold(true)
 	headerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("86")).
+		Foreground(theme.Current().Accent).
 		Bold(true)
 	labelStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("242")).
+		Foreground(theme.Current().Muted).
 		Width(18)
 	infoStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("245"))
+		Foreground(theme.Current().Muted)
 	successStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("86")).
+		Foreground(theme.Current().Accent).
 		Bold(true)
 	errorStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("203")).
+		Foreground(theme.Current().Error).
 		Bold(true)
 	editAppliedStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("212")).
-		Background(lipgloss.Color("236")).
+		Foreground(theme.Current().Highlight).
+		Background(theme.Current().Surface).
 		Padding(0, 1)
 
 	return RedactionModel{
@@ -619,7 +620,7 @@ func (m RedactionModel) reviewView() string {
 
 		// Action groups
 		actionStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("251")).
+			Foreground(theme.Current().Text).
 			Width(25)
 
 		redactActions := actionStyle.Render(
@@ -661,7 +662,7 @@ func (m RedactionModel) reviewView() string {
 
 		// Action groups for redacting state
 		actionStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("251")).
+			Foreground(theme.Current().Text).
 			Width(25)
 
 		modifyActions := actionStyle.Render(
@@ -727,7 +728,7 @@ func (m RedactionModel) editView() string {
 	typeColor := m.getTypeColor(item.itemType)
 	typeBadge := lipgloss.NewStyle().
 		Foreground(typeColor).
-		Background(lipgloss.Color("235")).
+		Background(theme.Current().Surface).
 		Padding(0, 1).
 		Render(string(item.itemType))
 
@@ -760,7 +761,7 @@ func (m RedactionModel) editView() string {
 
 	// Mode indicator
 	modeStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241")).
+		Foreground(theme.Current().Muted).
 		Italic(true)
 	modeText := "Quick Mode"
 	if !m.editQuickMode {
@@ -788,7 +789,7 @@ func (m RedactionModel) editView() string {
 
 		// Quick action hints
 		quickHintStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("245")).
+			Foreground(theme.Current().Muted).
 			MarginBottom(1)
 		b.WriteString(quickHintStyle.Render("Quick Actions:"))
 		b.WriteString("\n")
@@ -814,14 +815,14 @@ func (m RedactionModel) editView() string {
 
 	// Additional info
 	infoStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("244")).
+		Foreground(theme.Current().Muted).
 		Italic(true)
 	b.WriteString(infoStyle.Render(fmt.Sprintf("Length: %d characters", len(item.original))))
 	b.WriteString("\n\n")
 
 	// Footer
 	footerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241")).
+		Foreground(theme.Current().Muted).
 		MarginTop(1)
 
 	footer := footerStyle.Render(
@@ -834,22 +835,22 @@ func (m RedactionModel) editView() string {
 }
 
 // getTypeColor returns a color for the given sensitive type.
-func (m RedactionModel) getTypeColor(st SensitiveType) lipgloss.Color {
+func (m RedactionModel) getTypeColor(st SensitiveType) lipgloss.TerminalColor {
 	switch st {
 	case TypeAPIKey:
-		return lipgloss.Color("203") // Red/pink
+		return theme.Current().Error // Red/pink
 	case TypePassword, TypeSecret, TypePrivateKey:
-		return lipgloss.Color("196") // Bright red
+		return theme.Current().Danger // Bright red
 	case TypeToken, TypeBearer, TypeAuthHeader:
-		return lipgloss.Color("226") // Yellow
+		return theme.Current().Warning // Yellow
 	case TypeEmail:
-		return lipgloss.Color("86") // Cyan
+		return theme.Current().Accent // Cyan
 	case TypeCookie, TypeSession:
-		return lipgloss.Color("212") // Pink/purple
+		return theme.Current().Highlight // Pink/purple
 	case TypeCredential:
-		return lipgloss.Color("208") // Orange
+		return theme.Current().Caution // Orange
 	default:
-		return lipgloss.Color("245") // Grey
+		return theme.Current().Muted // Grey
 	}
 }
 
@@ -865,7 +866,7 @@ func (m RedactionModel) confirmView() string {
 
 	// Summary section
 	summaryStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("86")).
+		Foreground(theme.Current().Accent).
 		Bold(true).
 		MarginBottom(1)
 	b.WriteString(summaryStyle.Render("Summary:"))
@@ -912,7 +913,7 @@ func (m RedactionModel) confirmView() string {
 	// Detailed changes if any
 	if redactedCount > 0 {
 		detailStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("86")).
+			Foreground(theme.Current().Accent).
 			Bold(true).
 			MarginBottom(1)
 		b.WriteString(detailStyle.Render("Redacted Items:"))
@@ -948,7 +949,7 @@ func (m RedactionModel) confirmView() string {
 
 	// Content preview
 	previewStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("86")).
+		Foreground(theme.Current().Accent).
 		Bold(true).
 		MarginBottom(1)
 	b.WriteString(previewStyle.Render("Redacted Content Preview:"))
@@ -956,7 +957,7 @@ func (m RedactionModel) confirmView() string {
 
 	previewBoxStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("240")).
+		BorderForeground(theme.Current().Border).
 		Padding(0, 1)
 
 	// Show preview
@@ -1004,7 +1005,7 @@ func (m RedactionModel) itemListView() string {
 
 	headerText := fmt.Sprintf(" Detected Items (%d/%d redacted)", redactedCount, totalCount)
 	headerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("86")).
+		Foreground(theme.Current().Accent).
 		Bold(true)
 
 	b.WriteString(headerStyle.Render(headerText))
@@ -1012,7 +1013,7 @@ func (m RedactionModel) itemListView() string {
 
 	if len(m.List.Items()) == 0 {
 		noItemsStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("245")).
+			Foreground(theme.Current().Muted).
 			Italic(true)
 		b.WriteString(noItemsStyle.Render("No sensitive items detected."))
 	} else {
@@ -1024,7 +1025,7 @@ func (m RedactionModel) itemListView() string {
 		Width(width).
 		Height(m.height - 10).
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("240")).
+		BorderForeground(theme.Current().Border).
 		Render(b.String())
 }
 
@@ -1037,7 +1038,7 @@ func (m RedactionModel) contentPreviewView() string {
 
 	headerText := fmt.Sprintf(" Content Preview (%d changes)", redactedCount)
 	headerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("86")).
+		Foreground(theme.Current().Accent).
 		Bold(true)
 
 	b.WriteString(headerStyle.Render(headerText))
@@ -1095,7 +1096,7 @@ func (m RedactionModel) contentPreviewView() string {
 		Width(width).
 		Height(m.height - 10).
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("240")).
+		BorderForeground(theme.Current().Border).
 		Render(b.String())
 }
 
@@ -1190,11 +1191,11 @@ func (d redactionDelegate) Render(w io.Writer, m list.Model, index int, listItem
 
 	// Styles
 	selectedStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("229")).
+		Foreground(theme.Current().Selected).
 		Bold(true)
 
 	normalStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("251"))
+		Foreground(theme.Current().Text)
 
 	// Type color coding
 	typeColor := d.getTypeColor(r.itemType)
@@ -1208,7 +1209,7 @@ func (d redactionDelegate) Render(w io.Writer, m list.Model, index int, listItem
 	}
 
 	indexStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("245")).
+		Foreground(theme.Current().Muted).
 		Width(3).
 		Align(lipgloss.Right)
 
@@ -1229,35 +1230,35 @@ func (d redactionDelegate) Render(w io.Writer, m list.Model, index int, listItem
 
 	// Third line: status/replacement
 	if r.redacted == "<REDACTED>" {
-		statusText := lipgloss.NewStyle().Foreground(lipgloss.Color("226")).Render("  [REDACTED]")
+		statusText := lipgloss.NewStyle().Foreground(theme.Current().Warning).Render("  [REDACTED]")
 		_, _ = fmt.Fprintf(w, "%s\n", statusText)
 	} else if r.redacted != r.original {
-		statusText := lipgloss.NewStyle().Foreground(lipgloss.Color("86")).Render(fmt.Sprintf("  → %s", truncateString(r.redacted, 40)))
+		statusText := lipgloss.NewStyle().Foreground(theme.Current().Accent).Render(fmt.Sprintf("  → %s", truncateString(r.redacted, 40)))
 		_, _ = fmt.Fprintf(w, "%s\n", statusText)
 	} else {
 		// Show "[unchanged]" in dim color
-		statusText := lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Render("  [unchanged]")
+		statusText := lipgloss.NewStyle().Foreground(theme.Current().Muted).Render("  [unchanged]")
 		_, _ = fmt.Fprintf(w, "%s\n", statusText)
 	}
 }
 
 // getTypeColor returns a color for the given sensitive type.
-func (d redactionDelegate) getTypeColor(st SensitiveType) lipgloss.Color {
+func (d redactionDelegate) getTypeColor(st SensitiveType) lipgloss.TerminalColor {
 	switch st {
 	case TypeAPIKey:
-		return lipgloss.Color("203") // Red/pink
+		return theme.Current().Error // Red/pink
 	case TypePassword, TypeSecret, TypePrivateKey:
-		return lipgloss.Color("196") // Bright red
+		return theme.Current().Danger // Bright red
 	case TypeToken, TypeBearer, TypeAuthHeader:
-		return lipgloss.Color("226") // Yellow
+		return theme.Current().Warning // Yellow
 	case TypeEmail:
-		return lipgloss.Color("86") // Cyan
+		return theme.Current().Accent // Cyan
 	case TypeCookie, TypeSession:
-		return lipgloss.Color("212") // Pink/purple
+		return theme.Current().Highlight // Pink/purple
 	case TypeCredential:
-		return lipgloss.Color("208") // Orange
+		return theme.Current().Caution // Orange
 	default:
-		return lipgloss.Color("245") // Grey
+		return theme.Current().Muted // Grey
 	}
 }
 
@@ -1307,3 +1308,36 @@ func detectSensitiveItems(content string) []RedactedItem {
 
 	return items
 }
+
+// RedactLine reviews detected sensitive items with sequential line prompts
+// and returns the redacted content. ok is false if the user declined to
+// continue. It is the fallback for RedactionModel when no TUI is available.
+func RedactLine(content string, p *LinePrompter) (redacted string, ok bool, err error) {
+	items := detectSensitiveItems(content)
+	if len(items) == 0 {
+		return content, true, nil
+	}
+
+	p.Printf("Found %d potentially sensitive item(s):\n", len(items))
+
+	redacted = content
+	for i, item := range items {
+		p.Printf("\n%d. [%s] %s\n", i+1, item.Type, truncateString(item.Original, 60))
+
+		redact, err := p.Confirm("Redact this value?", true)
+		if err != nil {
+			return "", false, err
+		}
+		if redact {
+			redacted = strings.ReplaceAll(redacted, item.Original, "<REDACTED>")
+		}
+	}
+
+	p.Printf("\nContent to send:\n%s\n\n", redacted)
+
+	ok, err = p.Confirm("Send this content?", true)
+	if err != nil {
+		return "", false, err
+	}
+	return redacted, ok, nil
+}
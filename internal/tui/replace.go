@@ -0,0 +1,227 @@
+// Package tui provides Bubble Tea models for svf.
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chazuruo/svf/internal/tui/theme"
+)
+
+// ReplaceMatch is a proposed replacement in a step command.
+type ReplaceMatch struct {
+	// Location describes where the match is, e.g. the workflow and step.
+	Location string
+
+	// Before is the command as it is now.
+	Before string
+
+	// After is the command with only this match replaced.
+	After string
+
+	// Accepted is set if the replacement should be applied.
+	Accepted bool
+}
+
+// ReplaceReviewModel is a Bubble Tea model for accepting or rejecting
+// replacements one match at a time.
+type ReplaceReviewModel struct {
+	// Matches are the proposed replacements.
+	Matches []ReplaceMatch
+
+	// Confirmed indicates the user applied the accepted replacements.
+	Confirmed bool
+
+	// Canceled indicates the user quit without applying anything.
+	Canceled bool
+
+	cursor int
+
+	// styles
+	normalStyle   lipgloss.Style
+	selectedStyle lipgloss.Style
+	headerStyle   lipgloss.Style
+	removedStyle  lipgloss.Style
+	addedStyle    lipgloss.Style
+	mutedStyle    lipgloss.Style
+}
+
+// NewReplaceReviewModel creates a review model. Matches start rejected.
+func NewReplaceReviewModel(matches []ReplaceMatch) ReplaceReviewModel {
+	return ReplaceReviewModel{
+		Matches:       matches,
+		normalStyle:   lipgloss.NewStyle().Foreground(theme.Current().Text),
+		selectedStyle: lipgloss.NewStyle().Foreground(theme.Current().Selected).Bold(true),
+		headerStyle:   lipgloss.NewStyle().Foreground(theme.Current().Accent).Bold(true),
+		removedStyle:  lipgloss.NewStyle().Foreground(theme.Current().Error),
+		addedStyle:    lipgloss.NewStyle().Foreground(theme.Current().Success),
+		mutedStyle:    lipgloss.NewStyle().Foreground(theme.Current().Muted),
+	}
+}
+
+// Init initializes the model.
+func (m ReplaceReviewModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles key presses: y/n accept or reject the current match and
+// move on, space toggles it, a/N accept or reject all, enter applies and
+// q/esc cancels.
+func (m ReplaceReviewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "ctrl+c", "q", "esc":
+		m.Canceled = true
+		return m, tea.Quit
+	case "enter":
+		m.Confirmed = true
+		return m, tea.Quit
+	}
+	if len(m.Matches) == 0 {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.Matches)-1 {
+			m.cursor++
+		}
+	case "y":
+		m.Matches[m.cursor].Accepted = true
+		m.advance()
+	case "n":
+		m.Matches[m.cursor].Accepted = false
+		m.advance()
+	case " ":
+		m.Matches[m.cursor].Accepted = !m.Matches[m.cursor].Accepted
+	case "a":
+		m.setAll(true)
+	case "N":
+		m.setAll(false)
+	}
+	return m, nil
+}
+
+// advance moves the cursor to the next match, if any.
+func (m *ReplaceReviewModel) advance() {
+	if m.cursor < len(m.Matches)-1 {
+		m.cursor++
+	}
+}
+
+// setAll accepts or rejects every match.
+func (m *ReplaceReviewModel) setAll(accepted bool) {
+	for i := range m.Matches {
+		m.Matches[i].Accepted = accepted
+	}
+}
+
+// View renders the match list with the current match's change.
+func (m ReplaceReviewModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(m.headerStyle.Render(fmt.Sprintf("Review replacements (%d of %d accepted)", m.AcceptedCount(), len(m.Matches))))
+	b.WriteString("\n\n")
+
+	for i, match := range m.Matches {
+		mark := "[ ]"
+		if match.Accepted {
+			mark = "[x]"
+		}
+		line := fmt.Sprintf("%s %s", mark, match.Location)
+		if i == m.cursor {
+			b.WriteString(m.selectedStyle.Render("> " + line))
+		} else {
+			b.WriteString(m.normalStyle.Render("  " + line))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(m.Matches) > 0 {
+		current := m.Matches[m.cursor]
+		b.WriteString("\n")
+		b.WriteString(m.removedStyle.Render("- " + current.Before))
+		b.WriteString("\n")
+		b.WriteString(m.addedStyle.Render("+ " + current.After))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.mutedStyle.Render("y: accept  n: reject  space: toggle  a/N: accept/reject all  enter: apply  q: cancel"))
+	return b.String()
+}
+
+// AcceptedCount returns the number of accepted matches.
+func (m ReplaceReviewModel) AcceptedCount() int {
+	n := 0
+	for _, match := range m.Matches {
+		if match.Accepted {
+			n++
+		}
+	}
+	return n
+}
+
+// RunReplaceReview shows the review TUI and returns the matches with the
+// user's decisions. ok is false if the user canceled.
+func RunReplaceReview(matches []ReplaceMatch) (reviewed []ReplaceMatch, ok bool, err error) {
+	p := tea.NewProgram(NewReplaceReviewModel(matches), tea.WithAltScreen())
+	finalModel, err := p.Run()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to run replace review: %w", err)
+	}
+
+	result := finalModel.(ReplaceReviewModel)
+	if result.Canceled {
+		return nil, false, nil
+	}
+	return result.Matches, true, nil
+}
+
+// ReviewReplacementsLine asks about each match with sequential line
+// prompts. It is the fallback for RunReplaceReview when no TUI is
+// available.
+func ReviewReplacementsLine(matches []ReplaceMatch, p *LinePrompter) (reviewed []ReplaceMatch, ok bool, err error) {
+	reviewed = append([]ReplaceMatch(nil), matches...)
+
+	choices := []Choice{
+		{Key: "y", Label: "accept"},
+		{Key: "n", Label: "reject"},
+		{Key: "a", Label: "accept all remaining"},
+		{Key: "q", Label: "cancel"},
+	}
+
+	for i := range reviewed {
+		p.Printf("\nMatch %d/%d: %s\n", i+1, len(reviewed), reviewed[i].Location)
+		p.Printf("  - %s\n  + %s\n", reviewed[i].Before, reviewed[i].After)
+
+		choice, err := p.Choose("Replace", choices, "n")
+		if err != nil {
+			return nil, false, err
+		}
+
+		switch choice {
+		case "y":
+			reviewed[i].Accepted = true
+		case "a":
+			for j := i; j < len(reviewed); j++ {
+				reviewed[j].Accepted = true
+			}
+			return reviewed, true, nil
+		case "q":
+			return nil, false, nil
+		}
+	}
+
+	return reviewed, true, nil
+}
@@ -0,0 +1,77 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func testReplaceMatches() []ReplaceMatch {
+	return []ReplaceMatch{
+		{Location: "a step 1", Before: "old", After: "new"},
+		{Location: "a step 2", Before: "old old", After: "new old"},
+		{Location: "b step 1", Before: "x old", After: "x new"},
+	}
+}
+
+func TestReplaceReviewModel_Keys(t *testing.T) {
+	var m tea.Model = NewReplaceReviewModel(testReplaceMatches())
+
+	for _, key := range []string{"y", "n", " "} {
+		var msg tea.KeyMsg
+		if key == " " {
+			msg = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}
+		} else {
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		}
+		m, _ = m.Update(msg)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	got := m.(ReplaceReviewModel)
+	if !got.Confirmed || got.Canceled {
+		t.Fatalf("Confirmed = %v, Canceled = %v; want confirmed", got.Confirmed, got.Canceled)
+	}
+	want := []bool{true, false, true}
+	for i, match := range got.Matches {
+		if match.Accepted != want[i] {
+			t.Errorf("match %d Accepted = %v, want %v", i, match.Accepted, want[i])
+		}
+	}
+	if got.AcceptedCount() != 2 {
+		t.Errorf("AcceptedCount() = %d, want 2", got.AcceptedCount())
+	}
+}
+
+func TestReplaceReviewModel_AcceptAllAndCancel(t *testing.T) {
+	var m tea.Model = NewReplaceReviewModel(testReplaceMatches())
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	if got := m.(ReplaceReviewModel).AcceptedCount(); got != 3 {
+		t.Errorf("AcceptedCount() after a = %d, want 3", got)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if !m.(ReplaceReviewModel).Canceled {
+		t.Error("Canceled = false after esc")
+	}
+}
+
+func TestReviewReplacementsLine(t *testing.T) {
+	p, _ := newTestPrompter("n\na\n")
+
+	got, ok, err := ReviewReplacementsLine(testReplaceMatches(), p)
+	if err != nil || !ok {
+		t.Fatalf("ReviewReplacementsLine() ok = %v, err = %v", ok, err)
+	}
+	want := []bool{false, true, true}
+	for i, match := range got {
+		if match.Accepted != want[i] {
+			t.Errorf("match %d Accepted = %v, want %v", i, match.Accepted, want[i])
+		}
+	}
+
+	p, _ = newTestPrompter("q\n")
+	if _, ok, err := ReviewReplacementsLine(testReplaceMatches(), p); err != nil || ok {
+		t.Errorf("ReviewReplacementsLine() after q: ok = %v, err = %v; want canceled", ok, err)
+	}
+}
@@ -5,20 +5,28 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"path/filepath"
+	"os"
+	"runtime"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/chazuruo/svf/internal/clock"
 	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/i18n"
 	"github.com/chazuruo/svf/internal/placeholders"
 	//nolint:staticcheck // SA1019 - Using runner for Exec, DangerChecker, Plan types (deprecated but needed)
 	runnerpkg "github.com/chazuruo/svf/internal/runner"
+	"github.com/chazuruo/svf/internal/tui/theme"
 	"github.com/chazuruo/svf/internal/workflows"
 )
 
@@ -51,6 +59,15 @@ type RunnerModel struct {
 	// PlaceholderError is any error from placeholder validation.
 	PlaceholderError string
 
+	// placeholderForm asks for all placeholders at once when
+	// placeholders.prompt_style is "form"; formValues holds its answers.
+	placeholderForm *huh.Form
+	formValues      map[string]*string
+
+	// ChoosingPreset is set while offering the workflow's presets before
+	// prompting for placeholder values.
+	ChoosingPreset bool
+
 	// State is the current runner state.
 	State RunnerState
 
@@ -59,6 +76,55 @@ type RunnerModel struct {
 	EditingStep      bool // Editing current step
 	EditedStep       workflows.Step // Temporary storage for edited step
 
+	// Paused is set while the run is paused: no further step starts until
+	// it is resumed. A step already running finishes first.
+	Paused bool
+
+	// ShowLinks is set while the current step's links panel is expanded.
+	ShowLinks bool
+
+	// linkCursor is the selected link in the links panel.
+	linkCursor int
+
+	// PickingCWD is set while choosing a replacement working directory.
+	PickingCWD bool
+
+	// CWDPicker is the directory picker shown when a step's CWD is missing.
+	CWDPicker DirPickerModel
+
+	// cwdOverrides maps step index to a user-chosen working directory.
+	cwdOverrides map[int]string
+
+	// ConfirmingTerminate is set while asking whether to terminate a
+	// running step before quitting.
+	ConfirmingTerminate bool
+
+	// terminating is set once a running step has been told to stop; the
+	// runner quits when it finishes.
+	terminating bool
+
+	// stepCancel cancels the running step, killing its process group.
+	stepCancel context.CancelFunc
+
+	// runStarted and stepStarted are when the first and the current step
+	// started running, for the elapsed timers.
+	runStarted  time.Time
+	stepStarted time.Time
+
+	// overBudget is set once the running step outruns its max_duration.
+	overBudget bool
+
+	// stepEstimates are the typical step durations from past runs, for
+	// the ETA. Nil when there is no history.
+	stepEstimates []time.Duration
+
+	// pipeline is the pipeline header, or "" when the workflow runs on
+	// its own.
+	pipeline string
+
+	// clock tells the time for the timers.
+	clock clock.Clock
+
 	// List is the step list component.
 	List list.Model
 
@@ -68,6 +134,27 @@ type RunnerModel struct {
 	// Output contains the latest command output.
 	Output strings.Builder
 
+	// Log is the cumulative output of all executed steps.
+	Log []runLogEntry
+
+	// SearchingLog is set while typing a log search query.
+	SearchingLog bool
+
+	// LogSearchInput is the text input for log search.
+	LogSearchInput textinput.Model
+
+	// LogQuery is the active log search query.
+	LogQuery string
+
+	// StatusMessage is a transient message shown above the log.
+	StatusMessage string
+
+	// logMatches are log line numbers matching LogQuery.
+	logMatches []int
+
+	// logMatchIndex is the current match in logMatches.
+	logMatchIndex int
+
 	// Help is the keybindings help.
 	Help help.Model
 
@@ -89,6 +176,11 @@ type RunnerModel struct {
 	// AutoConfirm dangerous commands
 	AutoConfirm bool
 
+	// FlaggedCommands are the commands of steps the user flagged as
+	// dangerous after running them, for adding to the repository's danger
+	// rules once the run ends.
+	FlaggedCommands []string
+
 	// StreamOutput controls whether to stream command output
 	StreamOutput bool
 
@@ -121,6 +213,13 @@ type runnerKeyMap struct {
 	ToggleHelp  key.Binding
 	ShowPlace   key.Binding
 	Enter       key.Binding
+	Search      key.Binding
+	NextMatch   key.Binding
+	PrevMatch   key.Binding
+	Copy        key.Binding
+	FlagDanger  key.Binding
+	Links       key.Binding
+	Pause       key.Binding
 }
 
 // RunnerState represents the current state of the runner.
@@ -137,6 +236,9 @@ const (
 	StateStepResult
 	// StateFinished means the workflow is complete.
 	StateFinished
+	// StateSummary means showing what the run will do before the first
+	// step, until the user confirms.
+	StateSummary
 )
 
 // RunnerMsg is sent when a step finishes.
@@ -144,43 +246,82 @@ type RunnerMsg struct {
 	Result runnerpkg.StepResult
 }
 
+// cwdMissingMsg is sent when a step's working directory does not exist.
+type cwdMissingMsg struct {
+	Step int
+	Path string
+}
+
 // OutputMsg is sent when there's new output.
 type OutputMsg string
 
+// timerTickMsg refreshes the elapsed timers while a step runs.
+type timerTickMsg time.Time
+
+// budgetHookMsg reports the result of the over-budget hook.
+type budgetHookMsg struct{ err error }
+
 // newRunnerKeyMap creates the key bindings for the runner.
 func newRunnerKeyMap() runnerKeyMap {
 	return runnerKeyMap{
 		Run: key.NewBinding(
 			key.WithKeys("enter"),
-			key.WithHelp("enter", "run step"),
+			key.WithHelp("enter", i18n.T("runner.key.run")),
 		),
 		Skip: key.NewBinding(
 			key.WithKeys("s"),
-			key.WithHelp("s", "skip"),
+			key.WithHelp("s", i18n.T("runner.key.skip")),
 		),
 		Rerun: key.NewBinding(
 			key.WithKeys("r"),
-			key.WithHelp("r", "rerun"),
+			key.WithHelp("r", i18n.T("runner.key.rerun")),
 		),
 		Quit: key.NewBinding(
 			key.WithKeys("q", "ctrl+c"),
-			key.WithHelp("q", "quit"),
+			key.WithHelp("q", i18n.T("runner.key.quit")),
 		),
 		EditStep: key.NewBinding(
 			key.WithKeys("e"),
-			key.WithHelp("e", "edit step"),
+			key.WithHelp("e", i18n.T("runner.key.edit")),
 		),
 		ToggleHelp: key.NewBinding(
 			key.WithKeys("?"),
-			key.WithHelp("?", "help"),
+			key.WithHelp("?", i18n.T("runner.key.help")),
 		),
 		ShowPlace: key.NewBinding(
 			key.WithKeys("p"),
-			key.WithHelp("p", "placeholders"),
+			key.WithHelp("p", i18n.T("runner.key.placeholders")),
 		),
 		Enter: key.NewBinding(
 			key.WithKeys("enter"),
-			key.WithHelp("enter", "confirm"),
+			key.WithHelp("enter", i18n.T("runner.key.confirm")),
+		),
+		Search: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", i18n.T("runner.key.search")),
+		),
+		NextMatch: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n/N", i18n.T("runner.key.match")),
+		),
+		PrevMatch: key.NewBinding(
+			key.WithKeys("N"),
+		),
+		Copy: key.NewBinding(
+			key.WithKeys("y"),
+			key.WithHelp("y", i18n.T("runner.key.copy")),
+		),
+		FlagDanger: key.NewBinding(
+			key.WithKeys("!"),
+			key.WithHelp("!", i18n.T("runner.key.flag_danger")),
+		),
+		Links: key.NewBinding(
+			key.WithKeys("l"),
+			key.WithHelp("l", i18n.T("runner.key.links")),
+		),
+		Pause: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", i18n.T("runner.key.pause")),
 		),
 	}
 }
@@ -193,9 +334,26 @@ func NewRunnerModel(plan runnerpkg.Plan, dangerChecker *runnerpkg.DangerChecker,
 // NewRunnerModelWithConfig creates a new runner model with full config support.
 func NewRunnerModelWithConfig(plan runnerpkg.Plan, cfg *config.Config) RunnerModel {
 	dangerChecker := runnerpkg.NewDangerChecker(cfg.Runner.DangerousCommandWarnings)
+	if err := dangerChecker.LoadRules(cfg.Repo.Path); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
 	return newRunnerModelWithContext(plan, dangerChecker, false, cfg.Runner.StreamOutput, cfg)
 }
 
+// SetStepEstimates sets the typical duration of each step, as from
+// runlog.EstimateStepDurations, to show an ETA while running. Without
+// estimates no ETA is shown.
+func (m *RunnerModel) SetStepEstimates(estimates []time.Duration) {
+	m.stepEstimates = estimates
+}
+
+// SetPipeline marks the workflow as the position-th (1-based) of a
+// pipeline of the given workflow titles, shown as a header above the
+// steps.
+func (m *RunnerModel) SetPipeline(position int, titles []string) {
+	m.pipeline = i18n.T("run.pipeline", position, len(titles), titles[position-1])
+}
+
 func newRunnerModelWithContext(plan runnerpkg.Plan, dangerChecker *runnerpkg.DangerChecker, autoConfirm bool, streamOutput bool, cfg *config.Config) RunnerModel {
 	// Extract placeholder info
 	phInfo := placeholders.ExtractWithMetadata(plan.Workflow)
@@ -205,7 +363,7 @@ func newRunnerModelWithContext(plan runnerpkg.Plan, dangerChecker *runnerpkg.Dan
 	for i, step := range plan.Workflow.Steps {
 		name := step.Name
 		if name == "" {
-			name = fmt.Sprintf("Step %d", i+1)
+			name = i18n.T("runner.step", i+1)
 		}
 		items = append(items, runnerStepItem{index: i, name: name})
 	}
@@ -222,8 +380,14 @@ func newRunnerModelWithContext(plan runnerpkg.Plan, dangerChecker *runnerpkg.Dan
 	// Create help
 	h := help.New()
 
-	// Determine initial state - start with prompting if we have placeholders
-	initialState := StateReady
+	// Create log search input
+	si := textinput.New()
+	si.Placeholder = i18n.T("runner.search")
+	si.Prompt = "/"
+
+	// Determine initial state - start with prompting if we have
+	// placeholders, then summarize the run before it starts
+	initialState := StateSummary
 	if len(phInfo) > 0 && len(plan.Parameters) == 0 {
 		// We have placeholders but no values, start in prompting mode
 		initialState = StatePrompting
@@ -231,26 +395,26 @@ func newRunnerModelWithContext(plan runnerpkg.Plan, dangerChecker *runnerpkg.Dan
 
 	// Styles
 	normalStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240"))
+		Foreground(theme.Current().Muted)
 	selectedStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("229")).
+		Foreground(theme.Current().Selected).
 		Bold(true)
 	successStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("green"))
+		Foreground(theme.Current().Success)
 	errorStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("red"))
+		Foreground(theme.Current().Error)
 	runningStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("yellow"))
+		Foreground(theme.Current().Warning)
 	pendingStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("242"))
+		Foreground(theme.Current().Muted)
 	dimStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("245"))
+		Foreground(theme.Current().Muted)
 	accentStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("86")) // Pink/cyan
+		Foreground(theme.Current().Accent)
 	borderStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240"))
+		Foreground(theme.Current().Border)
 
-	return RunnerModel{
+	m := RunnerModel{
 		Plan:            plan,
 		Config:          cfg,
 		CurrentStep:     0,
@@ -258,14 +422,18 @@ func newRunnerModelWithContext(plan runnerpkg.Plan, dangerChecker *runnerpkg.Dan
 		Placeholders:    plan.Parameters,
 		PlaceholderInfo: phInfo,
 		State:           initialState,
+		ChoosingPreset:  initialState == StatePrompting && presetsMissing(plan.Workflow, phInfo, plan.Parameters),
 		List:            l,
 		Viewport:        vp,
+		LogSearchInput:  si,
 		Help:            h,
 		ShowHelp:        true,
 		Finished:        false,
 		DangerChecker:   dangerChecker,
 		AutoConfirm:     autoConfirm,
 		StreamOutput:    streamOutput,
+		cwdOverrides:    make(map[int]string),
+		clock:           clock.Real,
 		keyMap:          newRunnerKeyMap(),
 		normalStyle:     normalStyle,
 		selectedStyle:   selectedStyle,
@@ -277,10 +445,19 @@ func newRunnerModelWithContext(plan runnerpkg.Plan, dangerChecker *runnerpkg.Dan
 		accentStyle:     accentStyle,
 		borderStyle:     borderStyle,
 	}
+
+	// Build the placeholder form up front so Init can focus it
+	if m.State == StatePrompting && !m.ChoosingPreset && m.usesPlaceholderForm() {
+		m.placeholderForm, m.formValues = newPlaceholderForm(phInfo, missingPlaceholders(plan.Workflow, phInfo, plan.Parameters))
+	}
+	return m
 }
 
 // Init implements tea.Model.
 func (m RunnerModel) Init() tea.Cmd {
+	if m.placeholderForm != nil {
+		return m.placeholderForm.Init()
+	}
 	return nil
 }
 
@@ -293,6 +470,11 @@ func (m RunnerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.handlePrompting(msg)
 	}
 
+	// Wait for the run to be confirmed
+	if key, ok := msg.(tea.KeyMsg); ok && m.State == StateSummary {
+		return m.handleSummary(key)
+	}
+
 	// Handle finished state
 	if m.Finished {
 		if msg, ok := msg.(tea.KeyMsg); ok && msg.String() == "enter" {
@@ -301,6 +483,14 @@ func (m RunnerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	if m.PickingCWD {
+		return m.handleCWDPicking(msg)
+	}
+
+	if m.SearchingLog {
+		return m.handleLogSearch(msg)
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		// Handle key messages based on current sub-state
@@ -317,14 +507,38 @@ func (m RunnerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.handleStepEditing(msg)
 		}
 
+		if m.ShowLinks {
+			return m.handleLinks(msg)
+		}
+
+		if m.ConfirmingTerminate {
+			return m.handleTerminateConfirm(msg)
+		}
+
 		// Normal mode key bindings
 		switch {
 		case key.Matches(msg, m.keyMap.Quit):
+			if m.State == StateRunning {
+				if !m.terminating {
+					m.ConfirmingTerminate = true
+					m.StatusMessage = i18n.T("runner.confirm_terminate")
+				}
+				return m, nil
+			}
 			m.Canceled = true
 			m.Finished = true
 			m.State = StateFinished
 			return m, tea.Quit
 
+		case key.Matches(msg, m.keyMap.Pause):
+			m.Paused = !m.Paused
+			m.StatusMessage = ""
+			return m, nil
+
+		case m.Paused && (key.Matches(msg, m.keyMap.Run) || key.Matches(msg, m.keyMap.Skip) || key.Matches(msg, m.keyMap.Rerun)):
+			// Nothing starts while paused
+			return m, nil
+
 		case key.Matches(msg, m.keyMap.Run):
 			if m.State == StateReady || m.State == StateStepResult {
 				// Run next step
@@ -391,16 +605,83 @@ func (m RunnerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, m.keyMap.ShowPlace):
 			m.ShowPlaceholders = true
 			return m, nil
+
+		case key.Matches(msg, m.keyMap.Search):
+			m.SearchingLog = true
+			m.StatusMessage = ""
+			m.LogSearchInput.SetValue(m.LogQuery)
+			m.LogSearchInput.CursorEnd()
+			return m, m.LogSearchInput.Focus()
+
+		case key.Matches(msg, m.keyMap.NextMatch), key.Matches(msg, m.keyMap.PrevMatch):
+			if len(m.logMatches) > 0 {
+				if key.Matches(msg, m.keyMap.NextMatch) {
+					m.logMatchIndex = (m.logMatchIndex + 1) % len(m.logMatches)
+				} else {
+					m.logMatchIndex = (m.logMatchIndex - 1 + len(m.logMatches)) % len(m.logMatches)
+				}
+				m.StatusMessage = i18n.T("runner.match", m.logMatchIndex+1, len(m.logMatches))
+				m.jumpToMatch()
+			}
+			return m, nil
+
+		case key.Matches(msg, m.keyMap.Copy):
+			step, output, ok := m.stepOutputForCopy()
+			if !ok {
+				m.StatusMessage = i18n.T("runner.copy_empty", step+1)
+				return m, nil
+			}
+			return m, copyStepOutput(step, output)
+
+		case key.Matches(msg, m.keyMap.FlagDanger):
+			if m.State == StateStepResult || m.State == StateReady {
+				m.flagLastStep()
+			}
+			return m, nil
+
+		case key.Matches(msg, m.keyMap.Links):
+			if len(m.currentLinks()) == 0 {
+				m.StatusMessage = i18n.T("runner.no_links")
+				return m, nil
+			}
+			m.ShowLinks = true
+			m.linkCursor = 0
+			return m, nil
 		}
 
+	case cwdMissingMsg:
+		// Ask for a replacement directory before running the step
+		m.PickingCWD = true
+		m.CWDPicker = NewDirPickerModel(msg.Path)
+		m.State = StateReady
+		return m, nil
+
 	case RunnerMsg:
 		// Step finished
 		m.StepResults[msg.Result.Step] = msg.Result
+		if len(msg.Result.Captured) > 0 && m.Placeholders == nil {
+			m.Placeholders = make(map[string]string)
+		}
+		for name, value := range msg.Result.Captured {
+			m.Placeholders[name] = value
+		}
 		m.Output.Reset()
 		m.Output.WriteString(msg.Result.Output)
-		m.Viewport.SetContent(m.Output.String())
-		m.Viewport.GotoBottom()
+		m.appendLog(msg.Result.Step, msg.Result.Output, !msg.Result.Success)
 		m.State = StateStepResult
+		m.stepCancel = nil
+		if m.ConfirmingTerminate {
+			m.ConfirmingTerminate = false
+			m.StatusMessage = ""
+		}
+
+		// The user quit while the step was running
+		if m.terminating {
+			m.Canceled = true
+			m.Finished = true
+			m.State = StateFinished
+			return m, tea.Quit
+		}
 
 		if msg.Result.Success {
 			m.CurrentStep++
@@ -434,11 +715,31 @@ func (m RunnerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		}
 
+	case timerTickMsg:
+		// Keep ticking while a step runs; the view reads the clock
+		if m.State == StateRunning {
+			return m, tea.Batch(timerTick(), m.checkBudget())
+		}
+		return m, nil
+
+	case clipboardMsg:
+		if msg.err != nil {
+			m.StatusMessage = i18n.T("runner.copy_failed", msg.err)
+		} else {
+			m.StatusMessage = i18n.T("runner.copied", msg.step+1)
+		}
+		return m, nil
+
+	case budgetHookMsg:
+		if msg.err != nil {
+			m.StatusMessage = i18n.T("runner.hook_failed", msg.err)
+		}
+		return m, nil
+
 	case OutputMsg:
 		// New output during execution
 		m.Output.WriteString(string(msg))
-		m.Viewport.SetContent(m.Output.String())
-		m.Viewport.GotoBottom()
+		m.appendOutput(m.CurrentStep, string(msg))
 
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -495,6 +796,43 @@ func (m RunnerModel) handleStepEditing(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// handleCWDPicking routes messages to the directory picker and runs the
+// step once a directory has been chosen.
+func (m RunnerModel) handleCWDPicking(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		m.width = size.Width
+		m.height = size.Height
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.CWDPicker, cmd = m.CWDPicker.Update(msg)
+	if !m.CWDPicker.Done {
+		return m, cmd
+	}
+
+	m.PickingCWD = false
+	stepIndex := m.CurrentStep
+
+	if m.CWDPicker.Canceled {
+		// Fail the step with a clear error instead of a cryptic exec error
+		err := &runnerpkg.CWDError{Path: m.CWDPicker.Missing, Err: os.ErrNotExist}
+		return m, func() tea.Msg {
+			return RunnerMsg{Result: runnerpkg.StepResult{
+				Step:     stepIndex,
+				Success:  false,
+				ExitCode: 1,
+				Output:   err.Error(),
+				Error:    err,
+			}}
+		}
+	}
+
+	m.cwdOverrides[stepIndex] = m.CWDPicker.Chosen
+	run := m.runStep(stepIndex)
+	return m, run
+}
+
 // View implements tea.Model.
 func (m RunnerModel) View() string {
 	if m.Finished {
@@ -505,6 +843,14 @@ func (m RunnerModel) View() string {
 		return m.promptingView()
 	}
 
+	if m.State == StateSummary {
+		return m.summaryView()
+	}
+
+	if m.PickingCWD {
+		return m.CWDPicker.View()
+	}
+
 	if m.ShowPlaceholders {
 		return m.placeholdersView()
 	}
@@ -520,24 +866,100 @@ func (m RunnerModel) View() string {
 	return lipgloss.JoinHorizontal(lipgloss.Top, leftPanel, rightPanel)
 }
 
+// handleSummary handles key messages on the run summary.
+func (m RunnerModel) handleSummary(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		m.State = StateReady
+	case "q", "esc", "ctrl+c":
+		m.Canceled = true
+		m.Finished = true
+		m.State = StateFinished
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// summaryView renders what the run will do, for confirmation before the
+// first step.
+func (m RunnerModel) summaryView() string {
+	summary := runnerpkg.Summarize(m.Plan.Workflow, m.Placeholders, m.DangerChecker)
+
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(theme.Current().Accent).
+		Bold(true)
+	b.WriteString(titleStyle.Render(i18n.T("summary.title", summary.Title)))
+	b.WriteString("\n\n")
+	if m.pipeline != "" {
+		b.WriteString(m.dimStyle.Render(m.pipeline))
+		b.WriteString("\n")
+	}
+	b.WriteString(i18n.T("summary.steps", summary.Steps))
+	b.WriteString("\n\n")
+
+	if len(summary.Values) > 0 {
+		b.WriteString(i18n.T("summary.values"))
+		b.WriteString("\n")
+		for _, v := range summary.Values {
+			value := v.Value
+			if value == "" {
+				value = i18n.T("values.not_set")
+			}
+			b.WriteString(fmt.Sprintf("  %-20s %s\n", v.Name, m.dimStyle.Render(value)))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(summary.Guardrails) > 0 {
+		b.WriteString(i18n.T("summary.guardrails"))
+		b.WriteString("\n")
+		for _, g := range summary.Guardrails {
+			b.WriteString(fmt.Sprintf("  %-20s %s\n", g.Name, m.dimStyle.Render(g.Value)))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(summary.Dangerous) > 0 {
+		steps := make([]string, len(summary.Dangerous))
+		for i, n := range summary.Dangerous {
+			steps[i] = fmt.Sprint(n)
+		}
+		b.WriteString(m.runningStyle.Render(i18n.T("summary.dangerous", len(summary.Dangerous), strings.Join(steps, ", "))))
+	} else {
+		b.WriteString(m.successStyle.Render(i18n.T("summary.no_dangerous")))
+	}
+	b.WriteString("\n\n")
+
+	b.WriteString(m.dimStyle.Render(i18n.T("summary.footer")))
+
+	return lipgloss.NewStyle().
+		Width(70).
+		Padding(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.Current().Border).
+		Render(b.String())
+}
+
 // placeholdersView renders the placeholder values view.
 func (m RunnerModel) placeholdersView() string {
 	var b strings.Builder
 
 	// Title
 	titleStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("86")).
+		Foreground(theme.Current().Accent).
 		Bold(true).
 		MarginBottom(1)
 
-	b.WriteString(titleStyle.Render("Placeholder Values\n\n"))
+	b.WriteString(titleStyle.Render(i18n.T("values.title") + "\n\n"))
 
 	// Header
 	headerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("242")).
+		Foreground(theme.Current().Muted).
 		Bold(true)
 
-	b.WriteString(headerStyle.Render(fmt.Sprintf("%-20s %s", "Name", "Value")))
+	b.WriteString(headerStyle.Render(fmt.Sprintf("%-20s %s", i18n.T("values.name"), i18n.T("values.value"))))
 	b.WriteString("\n")
 	b.WriteString(strings.Repeat("-", 60))
 	b.WriteString("\n")
@@ -548,21 +970,22 @@ func (m RunnerModel) placeholdersView() string {
 		if value == "" {
 			value = info.Default
 		}
+		notSet := i18n.T("values.not_set")
 		if value == "" {
-			value = "(not set)"
+			value = notSet
 		}
 
 		// Mask secret values
-		if info.Secret && value != "(not set)" {
+		if info.Secret && value != notSet {
 			value = "***"
 		}
 
 		nameStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("251")).
+			Foreground(theme.Current().Text).
 			Width(20)
 
 		valueStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("245"))
+			Foreground(theme.Current().Muted)
 
 		b.WriteString(nameStyle.Render(name))
 		b.WriteString(valueStyle.Render(value))
@@ -571,16 +994,16 @@ func (m RunnerModel) placeholdersView() string {
 
 	// Footer help
 	footerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241")).
+		Foreground(theme.Current().Muted).
 		MarginTop(2)
 
-	b.WriteString(footerStyle.Render("[Enter/Esc/P] Close"))
+	b.WriteString(footerStyle.Render(i18n.T("values.footer")))
 
 	return lipgloss.NewStyle().
 		Width(70).
 		Padding(1, 2).
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("240")).
+		BorderForeground(theme.Current().Border).
 		Render(b.String())
 }
 
@@ -590,7 +1013,7 @@ func (m RunnerModel) editingStepView() string {
 
 	// Title
 	titleStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("86")).
+		Foreground(theme.Current().Accent).
 		Bold(true).
 		MarginBottom(1)
 
@@ -598,7 +1021,7 @@ func (m RunnerModel) editingStepView() string {
 
 	// Current command
 	cmdStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("251")).
+		Foreground(theme.Current().Text).
 		MarginBottom(1)
 
 	if m.EditedStep.Command != "" {
@@ -616,14 +1039,14 @@ func (m RunnerModel) editingStepView() string {
 
 	// Note about editing
 	noteStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("242")).
+		Foreground(theme.Current().Muted).
 		Italic(true)
 
 	b.WriteString(noteStyle.Render("Note: Full step editing not yet implemented.\nThis view shows the current step for reference.\n\n"))
 
 	// Footer help
 	footerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241")).
+		Foreground(theme.Current().Muted).
 		MarginTop(1)
 
 	b.WriteString(footerStyle.Render("[Esc] Cancel  [Enter] Continue"))
@@ -632,12 +1055,19 @@ func (m RunnerModel) editingStepView() string {
 		Width(70).
 		Padding(1, 2).
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("240")).
+		BorderForeground(theme.Current().Border).
 		Render(b.String())
 }
 
 // promptingView renders the placeholder prompting view.
 func (m RunnerModel) promptingView() string {
+	if m.ChoosingPreset {
+		return m.presetView()
+	}
+	if m.placeholderForm != nil {
+		return m.placeholderFormView()
+	}
+
 	var b strings.Builder
 
 	// If we don't have a current placeholder set, find one
@@ -653,7 +1083,7 @@ func (m RunnerModel) promptingView() string {
 
 	// If still no placeholder, we're done
 	if m.CurrentPlaceholder == "" {
-		m.State = StateReady
+		m.State = StateSummary
 		return m.View()
 	}
 
@@ -661,20 +1091,20 @@ func (m RunnerModel) promptingView() string {
 
 	// Title
 	titleStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("86")). // Pink
+		Foreground(theme.Current().Accent). // Pink
 		Bold(true).
 		MarginBottom(1)
 
-	b.WriteString(titleStyle.Render("Workflow Placeholders\n\n"))
+	b.WriteString(titleStyle.Render(i18n.T("placeholders.title") + "\n\n"))
 
 	// Prompt text
 	promptText := info.Prompt
 	if promptText == "" {
-		promptText = fmt.Sprintf("Enter value for <%s>", info.Name)
+		promptText = i18n.T("placeholders.enter_for", info.Name)
 	}
 
 	promptStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("251")).
+		Foreground(theme.Current().Text).
 		MarginBottom(1)
 
 	b.WriteString(promptStyle.Render(promptText + "\n"))
@@ -682,28 +1112,28 @@ func (m RunnerModel) promptingView() string {
 	// Usage info
 	if len(info.UsedIn) > 0 {
 		usageStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("242")).
+			Foreground(theme.Current().Muted).
 			MarginBottom(1)
 
-		b.WriteString(usageStyle.Render("Used in: " + strings.Join(info.UsedIn, ", ") + "\n\n"))
+		b.WriteString(usageStyle.Render(i18n.T("placeholders.used_in", strings.Join(info.UsedIn, ", ")) + "\n\n"))
 	}
 
 	// Default value hint
 	if info.Default != "" {
 		hintStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("244")).
+			Foreground(theme.Current().Muted).
 			MarginBottom(1)
 
-		b.WriteString(hintStyle.Render(fmt.Sprintf("Default: %s\n\n", info.Default)))
+		b.WriteString(hintStyle.Render(i18n.T("placeholders.default", info.Default) + "\n\n"))
 	}
 
 	// Error message
 	if m.PlaceholderError != "" {
 		errorStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("203")).
+			Foreground(theme.Current().Error).
 			MarginBottom(1)
 
-		b.WriteString(errorStyle.Render("Error: " + m.PlaceholderError + "\n\n"))
+		b.WriteString(errorStyle.Render(i18n.T("placeholders.error", m.PlaceholderError) + "\n\n"))
 	}
 
 	// Input field
@@ -711,19 +1141,19 @@ func (m RunnerModel) promptingView() string {
 
 	// Help footer
 	footerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241")).
+		Foreground(theme.Current().Muted).
 		MarginTop(2)
 
 	remaining := len(m.PlaceholderInfo) - len(m.Placeholders)
 	b.WriteString(footerStyle.Render(
-		fmt.Sprintf("\n\n[Enter] Submit (%d remaining) [Ctrl+C] Cancel", remaining),
+		"\n\n"+i18n.T("placeholders.footer", remaining),
 	))
 
 	return lipgloss.NewStyle().
 		Width(80).
 		Padding(1, 2).
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("240")).
+		BorderForeground(theme.Current().Border).
 		Render(b.String())
 }
 
@@ -731,7 +1161,11 @@ func (m RunnerModel) promptingView() string {
 func (m RunnerModel) stepListView() string {
 	var b strings.Builder
 
-	b.WriteString(" Steps\n\n")
+	if m.pipeline != "" {
+		b.WriteString(" " + m.accentStyle.Render(m.pipeline) + "\n\n")
+	}
+	b.WriteString(" " + i18n.T("runner.steps") + "\n\n")
+	now := m.clock.Now()
 
 	// Render list with custom styling
 	for i, item := range m.List.Items() {
@@ -761,7 +1195,9 @@ func (m RunnerModel) stepListView() string {
 		// Status icon
 		icon := " "
 		if i < m.CurrentStep {
-			if m.StepResults[i].Success {
+			if m.StepResults[i].Skipped {
+				icon = "–"
+			} else if m.StepResults[i].Success {
 				icon = "✓"
 			} else {
 				icon = "✗"
@@ -771,15 +1207,32 @@ func (m RunnerModel) stepListView() string {
 		}
 
 		line := fmt.Sprintf("%s %s", icon, step.name)
+		if i == m.CurrentStep && m.State == StateRunning {
+			line += " " + formatTimer(now.Sub(m.stepStarted))
+			if m.overBudget {
+				line += " ⚠"
+			}
+		}
 		b.WriteString(style.Render(line))
 		b.WriteString("\n")
 	}
 
+	// Run timers, once the first step has started
+	if !m.runStarted.IsZero() {
+		timers := i18n.T("runner.elapsed", formatTimer(now.Sub(m.runStarted)))
+		if left, ok := m.eta(now); ok && !m.Finished {
+			timers += " · " + i18n.T("runner.eta", formatTimer(left))
+		}
+		b.WriteString("\n")
+		b.WriteString(m.dimStyle.Render(" " + timers))
+		b.WriteString("\n")
+	}
+
 	width := 30
 	return lipgloss.NewStyle().
 		Width(width).
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("240")).
+		BorderForeground(theme.Current().Border).
 		Render(b.String())
 }
 
@@ -794,7 +1247,16 @@ func (m RunnerModel) outputView() string {
 	} else {
 		keys = []key.Binding{m.keyMap.Run, m.keyMap.Skip, m.keyMap.Quit}
 	}
-	keys = append(keys, m.keyMap.EditStep, m.keyMap.ShowPlace, m.keyMap.ToggleHelp)
+	keys = append(keys, m.keyMap.Pause, m.keyMap.EditStep, m.keyMap.ShowPlace, m.keyMap.Search, m.keyMap.Copy, m.keyMap.ToggleHelp)
+	if m.State == StateStepResult {
+		keys = append(keys, m.keyMap.FlagDanger)
+	}
+	if len(m.currentLinks()) > 0 {
+		keys = append(keys, m.keyMap.Links)
+	}
+	if len(m.logMatches) > 0 {
+		keys = append(keys, m.keyMap.NextMatch)
+	}
 
 	// Calculate viewport height (leave room for help if shown)
 	viewportHeight := m.height - 8
@@ -805,9 +1267,29 @@ func (m RunnerModel) outputView() string {
 		viewportHeight = 10
 	}
 
+	links := m.linksView()
+	if links != "" {
+		viewportHeight -= strings.Count(links, "\n")
+	}
+
+	if banner := m.pausedView(); banner != "" {
+		b.WriteString(banner)
+		viewportHeight -= strings.Count(banner, "\n")
+	}
 	m.Viewport.Height = viewportHeight
 
-	b.WriteString(" Output\n\n")
+	b.WriteString(links)
+	b.WriteString(" " + i18n.T("runner.log") + "\n")
+	switch {
+	case m.SearchingLog:
+		b.WriteString(m.LogSearchInput.View())
+	case m.StatusMessage != "":
+		b.WriteString(m.dimStyle.Render(" " + m.StatusMessage))
+	case m.overBudget && m.State == StateRunning:
+		maxDuration := m.Plan.Workflow.Steps[m.CurrentStep].MaxDuration
+		b.WriteString(m.runningStyle.Render(" " + i18n.T("runner.over_budget", formatTimer(maxDuration))))
+	}
+	b.WriteString("\n")
 	b.WriteString(m.Viewport.View())
 
 	if m.ShowHelp {
@@ -831,12 +1313,36 @@ func (m RunnerModel) outputView() string {
 		Width(width).
 		Height(m.height - 2).
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("240")).
+		BorderForeground(theme.Current().Border).
 		Render(b.String())
 }
 
+// pausedView renders the banner shown while the run is paused, or "".
+func (m RunnerModel) pausedView() string {
+	if !m.Paused {
+		return ""
+	}
+	banner := i18n.T("runner.paused")
+	if m.State == StateRunning {
+		banner = i18n.T("runner.pausing")
+	}
+	style := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(theme.Current().Warning).
+		Border(lipgloss.NormalBorder(), false, false, true, false).
+		BorderForeground(theme.Current().Warning)
+	return style.Render(" "+banner) + "\n"
+}
+
 // handlePrompting handles key messages when prompting for placeholders.
 func (m RunnerModel) handlePrompting(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.ChoosingPreset {
+		return m.handlePresetChoice(msg)
+	}
+	if m.placeholderForm != nil {
+		return m.handlePlaceholderForm(msg)
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
@@ -881,9 +1387,9 @@ func (m RunnerModel) handlePrompting(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 
-			// If no more placeholders, move to ready state
+			// If no more placeholders, summarize the run
 			if m.CurrentPlaceholder == "" {
-				m.State = StateReady
+				m.State = StateSummary
 				return m, nil
 			}
 
@@ -907,12 +1413,12 @@ func (m *RunnerModel) setupPlaceholderInput() {
 
 	// Create input
 	ti := textinput.New()
-	ti.Placeholder = "Enter value"
+	ti.Placeholder = i18n.T("placeholders.enter")
 
 	// Set default value in the input
 	if info.Default != "" {
 		ti.SetValue(info.Default)
-		ti.Placeholder = fmt.Sprintf("Default: %s", info.Default)
+		ti.Placeholder = i18n.T("placeholders.default", info.Default)
 	}
 
 	// Set prompt text
@@ -920,6 +1426,10 @@ func (m *RunnerModel) setupPlaceholderInput() {
 		ti.Placeholder = info.Prompt
 	}
 
+	if info.Secret {
+		ti.EchoMode = textinput.EchoPassword
+	}
+
 	ti.Focus()
 	m.PlaceholderInput = ti
 }
@@ -930,27 +1440,27 @@ func (m RunnerModel) finishedView() string {
 
 	if m.Success {
 		b.WriteString("\n")
-		b.WriteString(m.successStyle.Render("✓ Workflow completed successfully!"))
+		b.WriteString(m.successStyle.Render(i18n.T("runner.succeeded")))
 		b.WriteString("\n\n")
 	} else if m.Canceled {
 		b.WriteString("\n")
-		b.WriteString(m.dimStyle.Render("Workflow canceled."))
+		b.WriteString(m.dimStyle.Render(i18n.T("runner.canceled")))
 		b.WriteString("\n\n")
 	} else {
 		b.WriteString("\n")
-		b.WriteString(m.errorStyle.Render("✗ Workflow failed."))
+		b.WriteString(m.errorStyle.Render(i18n.T("runner.failed")))
 		b.WriteString("\n\n")
 	}
 
 	// Show summary
-	b.WriteString(m.dimStyle.Render("Step Results:\n\n"))
+	b.WriteString(m.dimStyle.Render(i18n.T("runner.results") + "\n\n"))
 	for i, result := range m.StepResults {
 		if i >= len(m.Plan.Workflow.Steps) {
 			break
 		}
 		name := m.Plan.Workflow.Steps[i].Name
 		if name == "" {
-			name = fmt.Sprintf("Step %d", i+1)
+			name = i18n.T("runner.step", i+1)
 		}
 
 		status := "✓"
@@ -963,12 +1473,17 @@ func (m RunnerModel) finishedView() string {
 			status = "○"
 			style = m.dimStyle
 		}
+		if result.SkipReason == runnerpkg.SkipPlatform {
+			status = "–"
+			style = m.dimStyle
+			name += " " + i18n.T("runner.skipped_platform")
+		}
 
 		b.WriteString(fmt.Sprintf("   %s %s\n", style.Render(status), name))
 	}
 
 	b.WriteString("\n")
-	b.WriteString(m.dimStyle.Render("Press Enter to exit...\n"))
+	b.WriteString(m.dimStyle.Render(i18n.T("runner.exit") + "\n"))
 
 	return lipgloss.NewStyle().
 		Width(m.width).
@@ -976,81 +1491,207 @@ func (m RunnerModel) finishedView() string {
 		Render(b.String())
 }
 
-// runStep executes a step and returns a command.
-func (m RunnerModel) runStep(stepIndex int) tea.Cmd {
-	return func() tea.Msg {
-		// Mark state as running
-		m.State = StateRunning
+// handleTerminateConfirm handles the answer to the terminate prompt shown
+// when quitting during a running step.
+func (m RunnerModel) handleTerminateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.ConfirmingTerminate = false
+	switch msg.String() {
+	case "y", "Y":
+		if m.stepCancel == nil {
+			m.StatusMessage = ""
+			return m, nil
+		}
+		m.terminating = true
+		m.StatusMessage = i18n.T("runner.terminating")
+		m.stepCancel()
+	default:
+		m.StatusMessage = ""
+	}
+	return m, nil
+}
 
-		// Get the step
-		step := m.Plan.Workflow.Steps[stepIndex]
+// runStep marks a step as running and returns a command that executes it.
+// The step runs until it finishes or stepCancel is called.
+func (m *RunnerModel) runStep(stepIndex int) tea.Cmd {
+	m.State = StateRunning
+	ctx, cancel := context.WithCancel(context.Background())
+	m.stepCancel = cancel
+
+	m.stepStarted = m.clock.Now()
+	m.overBudget = false
+	if m.runStarted.IsZero() {
+		m.runStarted = m.stepStarted
+	}
 
-		// Substitute placeholders using placeholders package
-		cmd, err := placeholders.Substitute(step.Command, m.Placeholders)
-		if err != nil {
-			// Check if we have any placeholders at all
-			phNames := placeholders.CollectFromSteps(m.Plan.Workflow.Steps)
-			if len(phNames) == 0 {
-				// No placeholders in workflow, use original command
-				cmd = step.Command
-			} else {
-				// We have placeholders but substitution failed
-				result := runnerpkg.StepResult{
-					Step:     stepIndex,
-					Success:  false,
-					ExitCode: 21,
-					Output:   fmt.Sprintf("Placeholder substitution failed: %v", err),
-					Duration: 0,
-					Error:    err,
-				}
-				return RunnerMsg{Result: result}
-			}
-		}
+	model := *m
+	return tea.Batch(func() tea.Msg {
+		defer cancel()
+		return model.execStep(ctx, stepIndex)
+	}, timerTick())
+}
+
+// flagLastStep flags the command of the last step run as dangerous.
+func (m *RunnerModel) flagLastStep() {
+	if len(m.Log) == 0 {
+		return
+	}
+	step := m.Log[len(m.Log)-1].Step
+	command := m.Plan.Workflow.Steps[step].Command
+	if command == "" {
+		return
+	}
+	if !slices.Contains(m.FlaggedCommands, command) {
+		m.FlaggedCommands = append(m.FlaggedCommands, command)
+	}
+	m.StatusMessage = i18n.T("runner.flagged", step+1)
+}
+
+// checkBudget flags the running step once it outruns its max_duration,
+// returning the command running the over-budget hook, if one is set.
+func (m *RunnerModel) checkBudget() tea.Cmd {
+	step := m.Plan.Workflow.Steps[m.CurrentStep]
+	if m.overBudget || step.MaxDuration == 0 || m.clock.Now().Sub(m.stepStarted) <= step.MaxDuration {
+		return nil
+	}
+	m.overBudget = true
+	if m.Config == nil || m.Config.Runner.OverBudgetHook == "" {
+		return nil
+	}
+	hook := m.Config.Runner.OverBudgetHook
+	event := runnerpkg.BudgetEvent{
+		Workflow:    m.Plan.Workflow.Title,
+		Step:        m.CurrentStep + 1,
+		StepName:    step.Name,
+		MaxDuration: step.MaxDuration,
+	}
+	return func() tea.Msg {
+		return budgetHookMsg{err: runnerpkg.RunBudgetHook(context.Background(), hook, event)}
+	}
+}
+
+// timerTick schedules the next refresh of the elapsed timers.
+func timerTick() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+		return timerTickMsg(t)
+	})
+}
 
-		// Resolve working directory
-		cwd := step.CWD
-		if cwd == "" && m.Plan.Workflow.Defaults.CWD != "" {
-			cwd = m.Plan.Workflow.Defaults.CWD
+// eta estimates the time left in the run from the step estimates: the
+// remaining estimate of the running step plus those of the steps after
+// it. ok is false without estimates.
+func (m RunnerModel) eta(now time.Time) (left time.Duration, ok bool) {
+	if len(m.stepEstimates) == 0 {
+		return 0, false
+	}
+	for i := m.CurrentStep; i < len(m.stepEstimates); i++ {
+		est := m.stepEstimates[i]
+		if i == m.CurrentStep && m.State == StateRunning {
+			est -= now.Sub(m.stepStarted)
 		}
-		if !filepath.IsAbs(cwd) && m.Plan.RepoRoot != "" {
-			cwd = filepath.Join(m.Plan.RepoRoot, cwd)
+		if est > 0 {
+			left += est
 		}
+	}
+	return left, true
+}
+
+// formatTimer formats a duration as m:ss, or h:mm:ss from an hour.
+func formatTimer(d time.Duration) string {
+	d = d.Round(time.Second)
+	h, m, s := int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%d:%02d", m, s)
+}
 
-		// Get shell
-		shell := step.Shell
-		if shell == "" {
-			shell = "bash"
-			// Check config for default shell if available
-			if m.Config != nil && m.Config.Runner.DefaultShell != "" {
-				shell = m.Config.Runner.DefaultShell
+// execStep executes a step and returns its result message.
+func (m RunnerModel) execStep(ctx context.Context, stepIndex int) tea.Msg {
+	// Get the step
+	step := m.Plan.Workflow.Steps[stepIndex]
+	if !step.RunsOn(runtime.GOOS, runtime.GOARCH) {
+		result := runnerpkg.PlatformSkip(stepIndex)
+		result.Output = i18n.T("runner.skipped_platform")
+		return RunnerMsg{Result: result}
+	}
+
+	// Substitute placeholders using placeholders package
+	cmd, err := placeholders.Substitute(step.Command, m.Placeholders)
+	if err != nil {
+		// Check if we have any placeholders at all
+		phNames := placeholders.CollectFromSteps(m.Plan.Workflow.Steps)
+		if len(phNames) == 0 {
+			// No placeholders in workflow, use original command
+			cmd = step.Command
+		} else {
+			// We have placeholders but substitution failed
+			result := runnerpkg.StepResult{
+				Step:     stepIndex,
+				Success:  false,
+				ExitCode: 21,
+				Output:   fmt.Sprintf("Placeholder substitution failed: %v", err),
+				Duration: 0,
+				Error:    err,
 			}
+			return RunnerMsg{Result: result}
 		}
+	}
 
-		// Execute step using runner.Exec
-		execConfig := runnerpkg.ExecConfig{
-			Command:       cmd,
-			Shell:         shell,
-			CWD:           cwd,
-			Env:           step.Env,
-			Stream:        m.StreamOutput,
-			DangerChecker: m.DangerChecker,
-			AutoConfirm:   m.AutoConfirm,
+	// Resolve working directory, preferring a directory picked earlier
+	cwd := runnerpkg.ResolveCWD(step.CWD, m.Plan.Workflow.Defaults.CWD, m.Plan.RepoRoot)
+	if override, ok := m.cwdOverrides[stepIndex]; ok {
+		cwd = override
+	}
+	if err := runnerpkg.ValidateCWD(cwd); err != nil {
+		return cwdMissingMsg{Step: stepIndex, Path: cwd}
+	}
+
+	// Get shell
+	shell := step.Shell
+	if shell == "" {
+		shell = "bash"
+		// Check config for default shell if available
+		if m.Config != nil && m.Config.Runner.DefaultShell != "" {
+			shell = m.Config.Runner.DefaultShell
 		}
+	}
 
-		execResult := runnerpkg.Exec(context.Background(), execConfig)
+	// Execute step using runner.Exec
+	execConfig := runnerpkg.ExecConfig{
+		Command:       cmd,
+		Shell:         shell,
+		CWD:           cwd,
+		Env:           step.Env,
+		Stream:        m.StreamOutput,
+		DangerChecker: m.DangerChecker,
+		AutoConfirm:   m.AutoConfirm,
+	}
+	if m.Config != nil {
+		execConfig.Timeout = time.Duration(m.Config.Runner.StepTimeout) * time.Second
+	}
 
-		// Convert to StepResult
-		result := runnerpkg.StepResult{
-			Step:     stepIndex,
-			Success:  execResult.Success,
-			ExitCode: execResult.ExitCode,
-			Output:   execResult.Output,
-			Duration: execResult.Duration,
-			Error:    execResult.Error,
-		}
+	execResult := runnerpkg.Exec(ctx, execConfig)
+	ran := execResult.Success
+	captured := runnerpkg.ApplyCaptures(&step, &execResult)
+	if ran && !execResult.Success {
+		// Show why a step that exited cleanly failed
+		execResult.Output += fmt.Sprintf("\n%v\n", execResult.Error)
+	}
 
-		return RunnerMsg{Result: result}
+	// Convert to StepResult
+	result := runnerpkg.StepResult{
+		Step:         stepIndex,
+		Success:      execResult.Success,
+		ExitCode:     execResult.ExitCode,
+		Output:       execResult.Output,
+		Duration:     execResult.Duration,
+		Error:        execResult.Error,
+		Captured:     captured,
+		StartedAt:    execResult.StartedAt,
+		Confirmation: execResult.Confirmation,
 	}
+
+	return RunnerMsg{Result: result}
 }
 
 // Batch combines multiple commands.
@@ -1105,11 +1746,11 @@ func (d runnerStepDelegate) Render(w io.Writer, m list.Model, index int, listIte
 
 	// Styles
 	selectedStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("229")).
+		Foreground(theme.Current().Selected).
 		Bold(true)
 
 	normalStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("251"))
+		Foreground(theme.Current().Text)
 
 	var text string
 	if index == m.Index() {
@@ -1121,3 +1762,248 @@ func (d runnerStepDelegate) Render(w io.Writer, m list.Model, index int, listIte
 	_, _ = fmt.Fprint(w, text)
 }
 
+
+// LineRunResult is the outcome of RunWorkflowLine.
+type LineRunResult struct {
+	Success  bool
+	Canceled bool
+	Results  []runnerpkg.StepResult
+	// Params are the placeholder values the run ended with, including
+	// captured ones.
+	Params map[string]string
+}
+
+// printSummaryLine prints a run summary for line mode.
+func printSummaryLine(summary runnerpkg.Summary, p *LinePrompter) {
+	p.Printf("\n%s\n", i18n.T("summary.title", summary.Title))
+	p.Printf("  %s\n", i18n.T("summary.steps", summary.Steps))
+	if len(summary.Values) > 0 {
+		p.Printf("  %s\n", i18n.T("summary.values"))
+		for _, v := range summary.Values {
+			p.Printf("    %s = %s\n", v.Name, v.Value)
+		}
+	}
+	if len(summary.Guardrails) > 0 {
+		p.Printf("  %s\n", i18n.T("summary.guardrails"))
+		for _, g := range summary.Guardrails {
+			p.Printf("    %s = %s\n", g.Name, g.Value)
+		}
+	}
+	if len(summary.Dangerous) > 0 {
+		steps := make([]string, len(summary.Dangerous))
+		for i, n := range summary.Dangerous {
+			steps[i] = fmt.Sprint(n)
+		}
+		p.Printf("  %s\n", i18n.T("summary.dangerous", len(summary.Dangerous), strings.Join(steps, ", ")))
+	}
+}
+
+// RunWorkflowLine runs a plan with sequential line prompts. It is the
+// fallback for RunnerModel when no TUI is available.
+func RunWorkflowLine(ctx context.Context, plan runnerpkg.Plan, cfg *config.Config, p *LinePrompter) (*LineRunResult, error) {
+	wf := plan.Workflow
+	params := make(map[string]string)
+	for k, v := range plan.Parameters {
+		params[k] = v
+	}
+	result := &LineRunResult{Results: make([]runnerpkg.StepResult, len(wf.Steps)), Params: params}
+
+	// Prompt for missing placeholder values
+	phInfo := placeholders.ExtractWithMetadata(wf)
+	if presetsMissing(wf, phInfo, params) {
+		if err := choosePresetLine(wf, phInfo, params, p); err != nil {
+			return nil, err
+		}
+	}
+	names := make([]string, 0, len(phInfo))
+	for name := range phInfo {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, ok := params[name]; ok {
+			continue
+		}
+		info := phInfo[name]
+		question := info.Prompt
+		if question == "" {
+			question = i18n.T("placeholders.value_for", name)
+		}
+		for {
+			value, err := p.Ask(question, info.Default)
+			if err != nil {
+				return nil, err
+			}
+			if err := placeholders.Validate(value, info.Validate); err != nil {
+				p.Printf("%v\n", err)
+				continue
+			}
+			params[name] = value
+			break
+		}
+	}
+
+	shell := "bash"
+	var dangerChecker *runnerpkg.DangerChecker
+	var stepTimeout time.Duration
+	var budgetHook string
+	if cfg != nil {
+		if cfg.Runner.DefaultShell != "" {
+			shell = cfg.Runner.DefaultShell
+		}
+		dangerChecker = runnerpkg.NewDangerChecker(cfg.Runner.DangerousCommandWarnings)
+		if err := dangerChecker.LoadRules(cfg.Repo.Path); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		stepTimeout = time.Duration(cfg.Runner.StepTimeout) * time.Second
+		budgetHook = cfg.Runner.OverBudgetHook
+	}
+
+	// Summarize the run and confirm it before the first step
+	printSummaryLine(runnerpkg.Summarize(wf, params, dangerChecker), p)
+	start, err := p.Confirm(i18n.T("summary.confirm"), true)
+	if err != nil {
+		return nil, err
+	}
+	if !start {
+		result.Canceled = true
+		return result, nil
+	}
+
+	for i := 0; i < len(wf.Steps); i++ {
+		step := wf.Steps[i]
+		if !step.RunsOn(runtime.GOOS, runtime.GOARCH) {
+			p.Printf("\n%s\n  %s\n", i18n.T("line.step", i+1, len(wf.Steps), step.Name), i18n.T("runner.skipped_platform"))
+			result.Results[i] = runnerpkg.PlatformSkip(i)
+			continue
+		}
+		cmd, err := placeholders.Substitute(step.Command, params)
+		if err != nil {
+			return nil, fmt.Errorf("step %d: %w", i+1, err)
+		}
+
+		p.Printf("\n%s\n  $ %s\n", i18n.T("line.step", i+1, len(wf.Steps), step.Name), cmd)
+		for _, link := range step.Links {
+			url, err := placeholders.Substitute(link.URL, params)
+			if err != nil {
+				url = link.URL
+			}
+			if link.Title != "" {
+				p.Printf("  → %s: %s\n", link.Title, url)
+			} else {
+				p.Printf("  → %s\n", url)
+			}
+		}
+
+		choice, err := p.Choose("", []Choice{
+			{Key: "r", Label: i18n.T("line.run")},
+			{Key: "s", Label: i18n.T("line.skip")},
+			{Key: "q", Label: i18n.T("line.quit")},
+		}, "r")
+		if err != nil {
+			return nil, err
+		}
+		switch choice {
+		case "s":
+			result.Results[i] = runnerpkg.StepResult{Step: i, Success: true, Skipped: true}
+			continue
+		case "q":
+			result.Canceled = true
+			return result, nil
+		}
+
+		// Resolve the working directory, asking for a replacement if missing
+		cwd := runnerpkg.ResolveCWD(step.CWD, wf.Defaults.CWD, plan.RepoRoot)
+		for runnerpkg.ValidateCWD(cwd) != nil {
+			p.Printf("%s\n", i18n.T("line.cwd_missing", cwd))
+			cwd, err = p.Ask(i18n.T("line.cwd_ask"), runnerpkg.NearestExistingDir(cwd))
+			if err != nil {
+				return nil, err
+			}
+			cwd = runnerpkg.ExpandPath(cwd)
+		}
+
+		stepShell := step.Shell
+		if stepShell == "" {
+			stepShell = shell
+		}
+
+		execResult := runnerpkg.Exec(ctx, runnerpkg.ExecConfig{
+			Command:       cmd,
+			Shell:         stepShell,
+			CWD:           cwd,
+			Env:           step.Env,
+			DangerChecker: dangerChecker,
+			Timeout:       stepTimeout,
+			MaxDuration:   step.MaxDuration,
+			OnOverBudget: func() {
+				p.Printf("%s\n", i18n.T("line.over_budget", i+1, step.MaxDuration))
+				if budgetHook == "" {
+					return
+				}
+				event := runnerpkg.BudgetEvent{Workflow: wf.Title, Step: i + 1, StepName: step.Name, MaxDuration: step.MaxDuration}
+				if err := runnerpkg.RunBudgetHook(ctx, budgetHook, event); err != nil {
+					p.Printf("%s\n", i18n.T("runner.hook_failed", err))
+				}
+			},
+		})
+		ran := execResult.Success
+		captured := runnerpkg.ApplyCaptures(&step, &execResult)
+		for name, value := range captured {
+			params[name] = value
+		}
+		if execResult.Output != "" {
+			p.Printf("%s", execResult.Output)
+			if !strings.HasSuffix(execResult.Output, "\n") {
+				p.Printf("\n")
+			}
+		}
+
+		result.Results[i] = runnerpkg.StepResult{
+			Step:         i,
+			Success:      execResult.Success,
+			ExitCode:     execResult.ExitCode,
+			Output:       execResult.Output,
+			Duration:     execResult.Duration,
+			Error:        execResult.Error,
+			Captured:     captured,
+			StartedAt:    execResult.StartedAt,
+			Confirmation: execResult.Confirmation,
+		}
+
+		if execResult.ExitCode == 13 {
+			result.Canceled = true
+			return result, nil
+		}
+		if execResult.Success {
+			p.Printf("%s\n", i18n.T("line.step_ok", i+1, execResult.Duration.Round(time.Millisecond)))
+			continue
+		}
+
+		p.Printf("%s\n", i18n.T("line.step_failed", i+1, execResult.ExitCode))
+		if execResult.Error != nil && (execResult.Output == "" || ran) {
+			p.Printf("%s\n", i18n.T("line.error", execResult.Error))
+		}
+		if step.ContinueOnError {
+			continue
+		}
+
+		choice, err = p.Choose("", []Choice{
+			{Key: "r", Label: i18n.T("line.retry")},
+			{Key: "s", Label: i18n.T("line.skip")},
+			{Key: "q", Label: i18n.T("line.quit")},
+		}, "")
+		if err != nil {
+			return nil, err
+		}
+		switch choice {
+		case "r":
+			i--
+		case "q":
+			return result, nil
+		}
+	}
+
+	result.Success = true
+	return result, nil
+}
@@ -0,0 +1,23 @@
+package tui
+
+import "testing"
+
+func TestRunnerFlagDanger(t *testing.T) {
+	m := newLogTestModel()
+
+	// Nothing has run yet
+	m = sendKeys(m, "!")
+	if len(m.FlaggedCommands) != 0 {
+		t.Fatalf("expected nothing flagged before a step runs, got %v", m.FlaggedCommands)
+	}
+
+	m = sendKeys(m, "enter")
+	m = sendResult(m, 0, "ok", true)
+	m = sendKeys(m, "!", "!")
+	if len(m.FlaggedCommands) != 1 || m.FlaggedCommands[0] != "make build" {
+		t.Errorf("expected the first step's command flagged once, got %v", m.FlaggedCommands)
+	}
+	if m.StatusMessage == "" {
+		t.Error("expected a status message after flagging")
+	}
+}
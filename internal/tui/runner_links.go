@@ -0,0 +1,124 @@
+// Package tui provides Bubble Tea models for svf.
+package tui
+
+import (
+	"os/exec"
+	"runtime"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chazuruo/svf/internal/i18n"
+	"github.com/chazuruo/svf/internal/placeholders"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// openBrowser opens links; tests replace it.
+var openBrowser = OpenURL
+
+// OpenURL opens a URL in the default browser.
+func OpenURL(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	return cmd.Run()
+}
+
+// currentLinks returns the links of the current step, or of the last step
+// once all have run.
+func (m RunnerModel) currentLinks() []workflows.Link {
+	steps := m.Plan.Workflow.Steps
+	if len(steps) == 0 {
+		return nil
+	}
+	return steps[min(m.CurrentStep, len(steps)-1)].Links
+}
+
+// linkURL returns a link's URL with the placeholder values filled in, or
+// as written when some are missing.
+func (m RunnerModel) linkURL(link workflows.Link) string {
+	url, err := placeholders.Substitute(link.URL, m.Placeholders)
+	if err != nil {
+		return link.URL
+	}
+	return url
+}
+
+// handleLinks handles key messages while the links panel is expanded.
+func (m RunnerModel) handleLinks(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	links := m.currentLinks()
+	if len(links) == 0 {
+		m.ShowLinks = false
+		return m, nil
+	}
+	m.linkCursor = min(m.linkCursor, len(links)-1)
+
+	switch msg.String() {
+	case "up", "k":
+		if m.linkCursor > 0 {
+			m.linkCursor--
+		}
+	case "down", "j":
+		if m.linkCursor < len(links)-1 {
+			m.linkCursor++
+		}
+	case "o", "enter":
+		url := m.linkURL(links[m.linkCursor])
+		if err := openBrowser(url); err != nil {
+			m.StatusMessage = i18n.T("runner.link_failed", url)
+		} else {
+			m.StatusMessage = i18n.T("runner.link_opened", url)
+		}
+	case "l", "esc", "q":
+		m.ShowLinks = false
+	case "ctrl+c":
+		m.ShowLinks = false
+		return m.Update(msg)
+	}
+	return m, nil
+}
+
+// linksView renders the current step's links: a one-line header when
+// collapsed, or the list with the selected link when expanded. It is
+// empty for steps without links.
+func (m RunnerModel) linksView() string {
+	links := m.currentLinks()
+	if len(links) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	if !m.ShowLinks {
+		b.WriteString(m.accentStyle.Render(" ▸ " + i18n.T("runner.links", len(links))))
+		b.WriteString(m.dimStyle.Render(" · " + i18n.T("runner.links_expand")))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	b.WriteString(m.accentStyle.Render(" ▾ " + i18n.T("runner.links", len(links))))
+	b.WriteString(m.dimStyle.Render(" · " + i18n.T("runner.links_keys")))
+	b.WriteString("\n")
+	cursor := min(m.linkCursor, len(links)-1)
+	for i, link := range links {
+		// Untitled links show their URL, with the placeholders filled in
+		label := link.Title
+		if label == "" {
+			label = m.linkURL(link)
+		}
+		if i == cursor {
+			b.WriteString(" " + m.selectedStyle.Render("› "+label))
+		} else {
+			b.WriteString("   " + label)
+		}
+		if link.Title != "" {
+			b.WriteString("  " + m.dimStyle.Render(m.linkURL(link)))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,82 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	runnerpkg "github.com/chazuruo/svf/internal/runner"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+func newLinksTestModel() RunnerModel {
+	wf := &workflows.Workflow{
+		Title: "Failover",
+		Steps: []workflows.Step{
+			{Name: "promote", Command: "promote <host>", Links: []workflows.Link{
+				{URL: "https://grafana.example.com/d/db?var-host=<host>"},
+				{Title: "Runbook", URL: "https://wiki.example.com/failover"},
+			}},
+			{Name: "verify", Command: "verify"},
+		},
+	}
+	plan := runnerpkg.Plan{Workflow: wf, Parameters: map[string]string{"host": "db2"}}
+	m := sendKeys(NewRunnerModel(plan, nil, false, false), "enter")
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+	return updated.(RunnerModel)
+}
+
+func TestRunnerLinks(t *testing.T) {
+	var opened []string
+	openBrowser = func(url string) error {
+		opened = append(opened, url)
+		return nil
+	}
+	defer func() { openBrowser = OpenURL }()
+
+	m := newLinksTestModel()
+	if view := m.View(); !strings.Contains(view, "Links (2)") || strings.Contains(view, "Runbook") {
+		t.Errorf("expected the collapsed links panel:\n%s", view)
+	}
+
+	m = sendKeys(m, "l")
+	if !m.ShowLinks {
+		t.Fatal("expected l to expand the links panel")
+	}
+	view := m.View()
+	for _, want := range []string{"https://grafana.example.com/d/db?var-host=db2", "Runbook", "https://wiki.example.com/failover"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in the expanded panel:\n%s", want, view)
+		}
+	}
+
+	// Panel keys don't reach the runner: s selects nothing and skips nothing
+	m = sendKeys(m, "s", "j", "o")
+	if m.CurrentStep != 0 {
+		t.Errorf("expected no skip while the panel is open, got step %d", m.CurrentStep)
+	}
+	if len(opened) != 1 || opened[0] != "https://wiki.example.com/failover" {
+		t.Errorf("expected the second link opened, got %v", opened)
+	}
+
+	m = sendKeys(m, "k", "o", "esc")
+	if len(opened) != 2 || opened[1] != "https://grafana.example.com/d/db?var-host=db2" {
+		t.Errorf("expected the first link opened with its placeholder filled in, got %v", opened)
+	}
+	if m.ShowLinks {
+		t.Error("expected esc to collapse the panel")
+	}
+}
+
+func TestRunnerLinksNone(t *testing.T) {
+	m := newLinksTestModel()
+	m = sendResult(m, 0, "promoted\n", true)
+
+	if strings.Contains(m.View(), "Links (") {
+		t.Error("expected no links panel for a step without links")
+	}
+	m = sendKeys(m, "l")
+	if m.ShowLinks || m.StatusMessage != "This step has no links" {
+		t.Errorf("expected a status message, got ShowLinks=%v %q", m.ShowLinks, m.StatusMessage)
+	}
+}
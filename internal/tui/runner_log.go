@@ -0,0 +1,220 @@
+// Package tui provides Bubble Tea models for svf.
+package tui
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// runLogEntry is the output of one step execution in the cumulative log.
+type runLogEntry struct {
+	Step   int
+	Name   string
+	Output string
+	Failed bool
+
+	// Running is set while output streams in for a step that hasn't
+	// finished yet.
+	Running bool
+}
+
+// stepName returns the name shown for a step in the log.
+func (m *RunnerModel) stepName(step int) string {
+	if step < len(m.Plan.Workflow.Steps) && m.Plan.Workflow.Steps[step].Name != "" {
+		return m.Plan.Workflow.Steps[step].Name
+	}
+	return fmt.Sprintf("Step %d", step+1)
+}
+
+// runningEntry returns the log entry of the running step, if it has one.
+func (m *RunnerModel) runningEntry(step int) *runLogEntry {
+	if n := len(m.Log); n > 0 && m.Log[n-1].Running && m.Log[n-1].Step == step {
+		return &m.Log[n-1]
+	}
+	return nil
+}
+
+// appendOutput adds output streamed by a running step to its log entry,
+// starting the entry with the step's first output.
+func (m *RunnerModel) appendOutput(step int, output string) {
+	entry := m.runningEntry(step)
+	if entry == nil {
+		m.Log = append(m.Log, runLogEntry{Step: step, Name: m.stepName(step), Running: true})
+		entry = &m.Log[len(m.Log)-1]
+	}
+	entry.Output += output
+	m.refreshLog()
+	m.Viewport.GotoBottom()
+}
+
+// appendLog records a step's output in the cumulative log, replacing what
+// it streamed while running.
+func (m *RunnerModel) appendLog(step int, output string, failed bool) {
+	done := runLogEntry{
+		Step:   step,
+		Name:   m.stepName(step),
+		Output: strings.TrimRight(output, "\n"),
+		Failed: failed,
+	}
+	if entry := m.runningEntry(step); entry != nil {
+		*entry = done
+	} else {
+		m.Log = append(m.Log, done)
+	}
+	m.refreshLog()
+	m.Viewport.GotoBottom()
+}
+
+// logLines flattens the log into display lines with per-step markers.
+func (m RunnerModel) logLines() []string {
+	var lines []string
+	for _, entry := range m.Log {
+		marker := fmt.Sprintf("── Step %d: %s ", entry.Step+1, entry.Name)
+		if entry.Failed {
+			marker += "(failed) "
+		}
+		lines = append(lines, marker+strings.Repeat("─", max(0, 40-len([]rune(marker)))))
+		if entry.Output != "" {
+			lines = append(lines, strings.Split(entry.Output, "\n")...)
+		}
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+// refreshLog re-renders the log into the viewport, highlighting search matches.
+func (m *RunnerModel) refreshLog() {
+	lines := m.logLines()
+	query := strings.ToLower(m.LogQuery)
+
+	m.logMatches = nil
+	rendered := make([]string, len(lines))
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "── Step "):
+			rendered[i] = m.accentStyle.Render(line)
+		case query != "" && strings.Contains(strings.ToLower(line), query):
+			m.logMatches = append(m.logMatches, i)
+			rendered[i] = m.selectedStyle.Render(line)
+		default:
+			rendered[i] = line
+		}
+	}
+
+	if m.logMatchIndex >= len(m.logMatches) {
+		m.logMatchIndex = 0
+	}
+
+	m.Viewport.SetContent(strings.Join(rendered, "\n"))
+}
+
+// jumpToMatch scrolls the viewport to the current search match.
+func (m *RunnerModel) jumpToMatch() {
+	if len(m.logMatches) == 0 {
+		return
+	}
+	line := m.logMatches[m.logMatchIndex]
+	m.Viewport.SetYOffset(max(0, line-m.Viewport.Height/2))
+}
+
+// handleLogSearch handles key input while typing a log search query.
+func (m RunnerModel) handleLogSearch(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "esc":
+		m.SearchingLog = false
+		m.LogQuery = ""
+		m.refreshLog()
+		return m, nil
+
+	case "enter":
+		m.SearchingLog = false
+		m.LogQuery = m.LogSearchInput.Value()
+		m.logMatchIndex = 0
+		m.refreshLog()
+		if len(m.logMatches) == 0 && m.LogQuery != "" {
+			m.StatusMessage = fmt.Sprintf("No matches for %q", m.LogQuery)
+		} else if m.LogQuery != "" {
+			m.StatusMessage = fmt.Sprintf("%d match(es) for %q", len(m.logMatches), m.LogQuery)
+		}
+		m.jumpToMatch()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.LogSearchInput, cmd = m.LogSearchInput.Update(msg)
+	return m, cmd
+}
+
+// stepOutputForCopy returns the latest output of the selected step. ok is
+// false when the step has none.
+func (m RunnerModel) stepOutputForCopy() (step int, output string, ok bool) {
+	step = m.List.Index()
+	for i := len(m.Log) - 1; i >= 0; i-- {
+		if m.Log[i].Step == step {
+			return step, m.Log[i].Output, m.Log[i].Output != ""
+		}
+	}
+	return step, "", false
+}
+
+// clipboardMsg reports the result of copying a step's output.
+type clipboardMsg struct {
+	step int
+	err  error
+}
+
+// copyStepOutput copies a step's output in the background, so a slow
+// clipboard tool doesn't freeze the UI.
+func copyStepOutput(step int, output string) tea.Cmd {
+	return func() tea.Msg {
+		return clipboardMsg{step: step, err: copyToClipboard(output)}
+	}
+}
+
+// clipboardCommands are tried in order to copy text to the clipboard.
+var clipboardCommands = [][]string{
+	{"pbcopy"},
+	{"wl-copy"},
+	{"xclip", "-selection", "clipboard"},
+	{"xsel", "--clipboard", "--input"},
+	{"clip.exe"},
+}
+
+// clipboardWaitDelay bounds the wait for a clipboard tool's output once it
+// has exited: xclip and xsel leave a child serving the selection that
+// keeps the tool's stderr open.
+const clipboardWaitDelay = time.Second
+
+// copyToClipboard copies text using the first available clipboard tool.
+func copyToClipboard(text string) error {
+	for _, args := range clipboardCommands {
+		path, err := exec.LookPath(args[0])
+		if err != nil {
+			continue
+		}
+		var stderr bytes.Buffer
+		cmd := exec.Command(path, args[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		cmd.Stderr = &stderr
+		cmd.WaitDelay = clipboardWaitDelay
+		if err := cmd.Run(); err != nil && !errors.Is(err, exec.ErrWaitDelay) {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return fmt.Errorf("%s: %w: %s", args[0], err, msg)
+			}
+			return fmt.Errorf("%s: %w", args[0], err)
+		}
+		return nil
+	}
+	return fmt.Errorf("no clipboard tool found (install pbcopy, wl-copy, xclip, or xsel)")
+}
@@ -0,0 +1,200 @@
+// Package tui provides tests for the runner log.
+package tui
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	//nolint:staticcheck // SA1019 - Using runner for Plan type
+	runnerpkg "github.com/chazuruo/svf/internal/runner"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+func newLogTestModel() RunnerModel {
+	wf := &workflows.Workflow{
+		Title: "Test",
+		Steps: []workflows.Step{
+			{Name: "build", Command: "make build"},
+			{Name: "test", Command: "make test"},
+		},
+	}
+	// Confirm the run summary so tests start at the first step
+	return sendKeys(NewRunnerModel(runnerpkg.Plan{Workflow: wf}, nil, false, false), "enter")
+}
+
+func sendResult(m RunnerModel, step int, output string, success bool) RunnerModel {
+	updated, _ := m.Update(RunnerMsg{Result: runnerpkg.StepResult{
+		Step:    step,
+		Output:  output,
+		Success: success,
+	}})
+	return updated.(RunnerModel)
+}
+
+func sendKeys(m RunnerModel, keys ...string) RunnerModel {
+	for _, k := range keys {
+		var msg tea.KeyMsg
+		switch k {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		}
+		updated, _ := m.Update(msg)
+		m = updated.(RunnerModel)
+	}
+	return m
+}
+
+func TestRunnerLogAccumulates(t *testing.T) {
+	m := newLogTestModel()
+	m = sendResult(m, 0, "compiled ok\n", true)
+	m = sendResult(m, 1, "FAIL: TestFoo\n", false)
+
+	if len(m.Log) != 2 {
+		t.Fatalf("expected 2 log entries, got %d", len(m.Log))
+	}
+
+	lines := strings.Join(m.logLines(), "\n")
+	for _, want := range []string{"── Step 1: build", "compiled ok", "── Step 2: test (failed)", "FAIL: TestFoo"} {
+		if !strings.Contains(lines, want) {
+			t.Errorf("expected log to contain %q, got:\n%s", want, lines)
+		}
+	}
+}
+
+func TestRunnerLogStreamsToRunningStep(t *testing.T) {
+	m := newLogTestModel()
+	m = sendResult(m, 0, "compiled ok\n", true)
+
+	m.CurrentStep = 1
+	updated, _ := m.Update(OutputMsg("running tests\n"))
+	m = updated.(RunnerModel)
+
+	if len(m.Log) != 2 || m.Log[0].Output != "compiled ok" || m.Log[1].Step != 1 {
+		t.Fatalf("expected streamed output in a new entry for step 2, got %+v", m.Log)
+	}
+
+	m = sendResult(m, 1, "running tests\nok\n", true)
+	if len(m.Log) != 2 || m.Log[1].Output != "running tests\nok" || m.Log[1].Running {
+		t.Errorf("expected the result to replace the streamed entry, got %+v", m.Log)
+	}
+}
+
+func TestCopyToClipboardReportsToolError(t *testing.T) {
+	if _, err := exec.LookPath("false"); err != nil {
+		t.Skip("false not available")
+	}
+	orig := clipboardCommands
+	clipboardCommands = [][]string{{"false"}}
+	defer func() { clipboardCommands = orig }()
+
+	err := copyToClipboard("text")
+	if err == nil || strings.Contains(err.Error(), "no clipboard tool") {
+		t.Errorf("expected the tool's error, got %v", err)
+	}
+}
+
+func TestCopyToClipboardDoesNotWaitForSelectionOwner(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	orig := clipboardCommands
+	// Like xclip, leave a child holding stderr open after exiting
+	clipboardCommands = [][]string{{"sh", "-c", "cat >/dev/null; sleep 5 &"}}
+	defer func() { clipboardCommands = orig }()
+
+	start := time.Now()
+	if err := copyToClipboard("text"); err != nil {
+		t.Fatalf("copyToClipboard() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("copyToClipboard() took %v, want it not to wait for the child", elapsed)
+	}
+}
+
+func TestRunnerLogSearch(t *testing.T) {
+	m := newLogTestModel()
+	m = sendResult(m, 0, "alpha\nbeta\nalpha again", true)
+
+	m = sendKeys(m, "/")
+	if !m.SearchingLog {
+		t.Fatal("expected / to start log search")
+	}
+	m = sendKeys(m, "A", "l", "p", "h", "a", "enter")
+
+	if m.SearchingLog {
+		t.Error("expected enter to finish search")
+	}
+	if m.LogQuery != "Alpha" {
+		t.Errorf("expected query Alpha, got %q", m.LogQuery)
+	}
+	if len(m.logMatches) != 2 {
+		t.Fatalf("expected 2 case-insensitive matches, got %d", len(m.logMatches))
+	}
+
+	m = sendKeys(m, "n")
+	if m.logMatchIndex != 1 {
+		t.Errorf("expected n to advance to match 1, got %d", m.logMatchIndex)
+	}
+	m = sendKeys(m, "n")
+	if m.logMatchIndex != 0 {
+		t.Errorf("expected n to wrap to match 0, got %d", m.logMatchIndex)
+	}
+	m = sendKeys(m, "N")
+	if m.logMatchIndex != 1 {
+		t.Errorf("expected N to wrap back to match 1, got %d", m.logMatchIndex)
+	}
+
+	m = sendKeys(m, "/", "esc")
+	if m.LogQuery != "" || len(m.logMatches) != 0 {
+		t.Errorf("expected esc to clear search, got query %q with %d matches", m.LogQuery, len(m.logMatches))
+	}
+}
+
+func TestRunnerLogStepOutputForCopy(t *testing.T) {
+	m := newLogTestModel()
+	if _, _, ok := m.stepOutputForCopy(); ok {
+		t.Error("expected nothing to copy before any step runs")
+	}
+
+	m = sendResult(m, 0, "built", true)
+	m = sendResult(m, 1, "tested", true)
+
+	m.List.Select(0)
+	step, output, ok := m.stepOutputForCopy()
+	if !ok || step != 0 || output != "built" {
+		t.Errorf("expected output of selected step 0, got step=%d output=%q ok=%v", step, output, ok)
+	}
+
+	// A step without output has nothing to copy, not another step's output
+	m = newLogTestModel()
+	m = sendResult(m, 0, "built", true)
+	m.List.Select(1)
+	if step, output, ok := m.stepOutputForCopy(); ok || step != 1 {
+		t.Errorf("expected nothing to copy for step 1, got step=%d output=%q ok=%v", step, output, ok)
+	}
+}
+
+func TestRunnerCopyRunsInBackground(t *testing.T) {
+	m := newLogTestModel()
+	m = sendResult(m, 0, "built", true)
+	m.List.Select(0)
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = updated.(RunnerModel)
+	if cmd == nil {
+		t.Fatal("expected copying to return a command")
+	}
+
+	updated, _ = m.Update(clipboardMsg{step: 0})
+	m = updated.(RunnerModel)
+	if !strings.Contains(m.StatusMessage, "step 1") {
+		t.Errorf("expected the copied status, got %q", m.StatusMessage)
+	}
+}
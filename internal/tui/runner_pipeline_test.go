@@ -0,0 +1,18 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRunnerPipelineHeader(t *testing.T) {
+	m := newLogTestModel()
+	if strings.Contains(m.View(), "Pipeline") {
+		t.Fatal("expected no pipeline header outside a pipeline")
+	}
+
+	m.SetPipeline(2, []string{"Build", "Test", "Release"})
+	if view := m.View(); !strings.Contains(view, "Pipeline 2/3: Test") {
+		t.Errorf("expected the pipeline header in the view, got:\n%s", view)
+	}
+}
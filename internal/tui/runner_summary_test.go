@@ -0,0 +1,53 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	runnerpkg "github.com/chazuruo/svf/internal/runner"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+func newSummaryTestModel() RunnerModel {
+	wf := &workflows.Workflow{
+		Title: "Rotate",
+		Steps: []workflows.Step{
+			{Name: "login", Command: "login --token <token>"},
+			{Name: "wipe", Command: "rm -rf /var/cache/app"},
+		},
+		Placeholders: map[string]workflows.Placeholder{"token": {Secret: true}},
+	}
+	plan := runnerpkg.Plan{Workflow: wf, Parameters: map[string]string{"token": "hunter2"}}
+	return NewRunnerModel(plan, runnerpkg.NewDangerChecker(true), false, false)
+}
+
+func TestRunnerSummary(t *testing.T) {
+	m := newSummaryTestModel()
+	if m.State != StateSummary {
+		t.Fatalf("expected the run summary first, got %v", m.State)
+	}
+
+	view := m.View()
+	for _, want := range []string{"Ready to run: Rotate", "Steps: 2", "token", "***", "1 dangerous step(s): 2"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in summary:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "hunter2") {
+		t.Error("secret value shown in summary")
+	}
+
+	// Other keys don't start the run
+	if m = sendKeys(m, "s"); m.State != StateSummary {
+		t.Errorf("expected the summary to stay open, got %v", m.State)
+	}
+
+	if started := sendKeys(m, "enter"); started.State != StateReady {
+		t.Errorf("expected StateReady after confirming, got %v", started.State)
+	}
+
+	canceled := sendKeys(m, "q")
+	if !canceled.Canceled || !canceled.Finished || canceled.CurrentStep != 0 {
+		t.Errorf("expected a canceled run with no steps, got canceled=%v finished=%v step=%d", canceled.Canceled, canceled.Finished, canceled.CurrentStep)
+	}
+}
@@ -0,0 +1,49 @@
+package tui
+
+import "testing"
+
+func TestRunnerQuitWhileRunningConfirms(t *testing.T) {
+	m := newLogTestModel()
+	m = sendKeys(m, "enter")
+	if m.State != StateRunning {
+		t.Fatalf("expected StateRunning after enter, got %v", m.State)
+	}
+
+	canceled := false
+	m.stepCancel = func() { canceled = true }
+
+	// Declining keeps the step running
+	m = sendKeys(m, "q")
+	if !m.ConfirmingTerminate {
+		t.Fatal("expected a terminate confirmation when quitting mid-step")
+	}
+	m = sendKeys(m, "n")
+	if m.ConfirmingTerminate || canceled || m.Finished {
+		t.Fatal("declining should leave the step running")
+	}
+
+	// Confirming cancels the step and quits once it finishes
+	m = sendKeys(m, "q", "y")
+	if !canceled {
+		t.Fatal("confirming should cancel the running step")
+	}
+	if m.Finished {
+		t.Fatal("runner should wait for the step to stop before finishing")
+	}
+
+	m = sendResult(m, 0, "", false)
+	if !m.Finished || !m.Canceled {
+		t.Errorf("expected a canceled, finished run; got finished=%v canceled=%v", m.Finished, m.Canceled)
+	}
+}
+
+func TestRunnerQuitWhenIdle(t *testing.T) {
+	m := newLogTestModel()
+	m = sendKeys(m, "q")
+	if m.ConfirmingTerminate {
+		t.Error("no confirmation expected when no step is running")
+	}
+	if !m.Finished || !m.Canceled {
+		t.Errorf("expected a canceled, finished run; got finished=%v canceled=%v", m.Finished, m.Canceled)
+	}
+}
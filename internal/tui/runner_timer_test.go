@@ -0,0 +1,118 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chazuruo/svf/internal/testutil"
+)
+
+func TestRunnerTimers(t *testing.T) {
+	clk := testutil.NewFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	m := newLogTestModel()
+	m.clock = clk
+	m.SetStepEstimates([]time.Duration{30 * time.Second, 20 * time.Second})
+
+	if strings.Contains(m.View(), "Elapsed") {
+		t.Error("no timers expected before the first step runs")
+	}
+
+	m = sendKeys(m, "enter")
+	clk.Advance(5 * time.Second)
+	view := m.View()
+	for _, want := range []string{"build 0:05", "Elapsed 0:05", "ETA 0:45"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in view:\n%s", want, view)
+		}
+	}
+
+	// An overrunning step doesn't make the ETA negative
+	clk.Advance(time.Minute)
+	if view := m.View(); !strings.Contains(view, "Elapsed 1:05") || !strings.Contains(view, "ETA 0:20") {
+		t.Errorf("unexpected timers in view:\n%s", view)
+	}
+
+	// Without history there is no ETA
+	m.SetStepEstimates(nil)
+	if strings.Contains(m.View(), "ETA") {
+		t.Error("no ETA expected without estimates")
+	}
+}
+
+func TestRunnerBudget(t *testing.T) {
+	clk := testutil.NewFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	m := newLogTestModel()
+	m.clock = clk
+	m.Plan.Workflow.Steps[0].MaxDuration = 10 * time.Second
+
+	tick := func() {
+		updated, _ := m.Update(timerTickMsg(clk.Now()))
+		m = updated.(RunnerModel)
+	}
+
+	m = sendKeys(m, "enter")
+	clk.Advance(5 * time.Second)
+	tick()
+	if strings.Contains(m.View(), "⚠") {
+		t.Errorf("no warning expected within the budget:\n%s", m.View())
+	}
+
+	clk.Advance(10 * time.Second)
+	tick()
+	view := m.View()
+	for _, want := range []string{"build 0:15 ⚠", "Taking longer than the expected 0:10"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in view:\n%s", want, view)
+		}
+	}
+
+	// The next step starts within its budget
+	m = sendResult(m, 0, "", true)
+	m = sendKeys(m, "enter")
+	if m.overBudget {
+		t.Error("overBudget still set for the next step")
+	}
+}
+
+func TestFormatTimer(t *testing.T) {
+	tests := map[time.Duration]string{
+		0:                "0:00",
+		65 * time.Second: "1:05",
+		time.Hour + 2*time.Minute + 3*time.Second: "1:02:03",
+	}
+	for d, want := range tests {
+		if got := formatTimer(d); got != want {
+			t.Errorf("formatTimer(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestRunnerPause(t *testing.T) {
+	m := newLogTestModel()
+
+	m = sendKeys(m, " ")
+	if !m.Paused || !strings.Contains(m.View(), "PAUSED — press space to resume") {
+		t.Fatalf("expected the paused banner:\n%s", m.View())
+	}
+	m = sendKeys(m, "enter", "s")
+	if m.State != StateReady || m.CurrentStep != 0 {
+		t.Errorf("steps must not start while paused; state %v, step %d", m.State, m.CurrentStep)
+	}
+
+	// Pausing while a step runs takes effect once it finishes
+	m = sendKeys(m, " ", "enter", " ")
+	if m.State != StateRunning || !strings.Contains(m.View(), "PAUSED after this step") {
+		t.Fatalf("expected a pending pause while the step runs:\n%s", m.View())
+	}
+	m = sendResult(m, 0, "", true)
+	m = sendKeys(m, "enter")
+	if m.State != StateStepResult || m.CurrentStep != 1 {
+		t.Errorf("the next step must not start while paused; state %v, step %d", m.State, m.CurrentStep)
+	}
+
+	m = sendKeys(m, " ")
+	if m.Paused || strings.Contains(m.View(), "PAUSED") {
+		t.Errorf("expected the run to resume:\n%s", m.View())
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/charmbracelet/bubbles/viewport"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/chazuruo/svf/internal/index"
+	"github.com/chazuruo/svf/internal/tui/theme"
 )
 
 // SearchModel is a Bubble Tea model for fuzzy search workflows.
@@ -42,9 +43,17 @@ type SearchModel struct {
 	SelectedEntry *index.WorkflowEntry
 
 	// Filter options
-	Tags   []string
-	Mine   bool
-	Shared bool
+	Tags          []string
+	Mine          bool
+	Shared        bool
+	Regex         bool
+	IncludeDrafts bool
+
+	// Order, if set, reorders the results of each search.
+	Order func([]index.SearchResult)
+
+	// Pinned holds the IDs of workflows to mark as pinned.
+	Pinned map[string]bool
 
 	// styles
 	normalStyle   lipgloss.Style
@@ -67,17 +76,17 @@ func NewSearchModel(idx *index.Index) SearchModel {
 
 	// Styles
 	normalStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240"))
+		Foreground(theme.Current().Border)
 	selectedStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("229")).
+		Foreground(theme.Current().Selected).
 		Bold(true)
 	previewStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("242"))
+		Foreground(theme.Current().Muted)
 	headerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("86")).
+		Foreground(theme.Current().Accent).
 		Bold(true)
 	metadataStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241"))
+		Foreground(theme.Current().Muted)
 
 	return SearchModel{
 		Index:        idx,
@@ -150,6 +159,11 @@ func (m SearchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.Mine = false
 			}
 			m.PerformSearch()
+
+		case "ctrl+d":
+			// Toggle --include-drafts
+			m.IncludeDrafts = !m.IncludeDrafts
+			m.PerformSearch()
 		}
 	}
 
@@ -239,9 +253,12 @@ func (m SearchModel) renderResultsColumn(width int) string {
 			isCursor := i == m.cursor
 
 			line := "  "
+			if m.Pinned[result.Entry.ID] {
+				line += "★ "
+			}
 
-			// Title
-			line += result.Entry.Title
+			// Title, marked if it is a draft or template
+			line += index.KindBadge(result.Entry.Kind) + result.Entry.Title
 
 			// Style
 			style := m.normalStyle
@@ -279,7 +296,7 @@ func (m SearchModel) renderResultsColumn(width int) string {
 	return lipgloss.NewStyle().
 		Width(width).
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("240")).
+		BorderForeground(theme.Current().Border).
 		Render(b.String())
 }
 
@@ -303,6 +320,12 @@ func (m SearchModel) renderPreviewColumn(width int) string {
 	b.WriteString("  ID:\n")
 	b.WriteString("    " + m.metadataStyle.Render(entry.ID) + "\n\n")
 
+	// Kind, for drafts and templates
+	if index.KindBadge(entry.Kind) != "" {
+		b.WriteString("  Kind:\n")
+		b.WriteString("    " + m.metadataStyle.Render(entry.Kind) + "\n\n")
+	}
+
 	// Path
 	b.WriteString("  Path:\n")
 	b.WriteString("    " + m.metadataStyle.Render(entry.Path) + "\n\n")
@@ -323,7 +346,7 @@ func (m SearchModel) renderPreviewColumn(width int) string {
 	return lipgloss.NewStyle().
 		Width(width).
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("240")).
+		BorderForeground(theme.Current().Border).
 		Render(b.String())
 }
 
@@ -335,6 +358,7 @@ func (m SearchModel) helpText() string {
 		"[Enter] Select",
 		"[Ctrl+N] Mine only",
 		"[Ctrl+S] Shared only",
+		"[Ctrl+D] Drafts too",
 		"[q] Quit",
 	)
 
@@ -351,6 +375,9 @@ func (m SearchModel) getFilterIndicators() string {
 	if m.Shared {
 		filters = append(filters, "shared")
 	}
+	if m.IncludeDrafts {
+		filters = append(filters, "drafts")
+	}
 	if len(m.Tags) > 0 {
 		filters = append(filters, fmt.Sprintf("tags:%s", strings.Join(m.Tags, ",")))
 	}
@@ -363,14 +390,19 @@ func (m *SearchModel) PerformSearch() {
 	query := m.SearchInput.Value()
 
 	opts := index.SearchOptions{
-		Query:      query,
-		Tags:       m.Tags,
-		Mine:       m.Mine,
-		Shared:     m.Shared,
-		MaxResults: 0,
+		Query:         query,
+		Tags:          m.Tags,
+		Mine:          m.Mine,
+		Shared:        m.Shared,
+		Regex:         m.Regex,
+		IncludeDrafts: m.IncludeDrafts,
+		MaxResults:    0,
 	}
 
 	m.Results = m.Index.FuzzySearch(opts)
+	if m.Order != nil {
+		m.Order(m.Results)
+	}
 
 	// Reset cursor if out of bounds
 	if m.cursor >= len(m.Results) {
@@ -392,3 +424,42 @@ func (m SearchModel) DidQuit() bool {
 func (m SearchModel) DidConfirm() bool {
 	return m.Confirmed
 }
+
+// SelectSearchResultLine lists results and prompts for one by number.
+// It is the fallback for SearchModel when no TUI is available. Returns nil
+// if the user selected nothing.
+func SelectSearchResultLine(results []index.SearchResult, p *LinePrompter) (*index.WorkflowEntry, error) {
+	if len(results) == 0 {
+		p.Printf("No results found.\n")
+		return nil, nil
+	}
+
+	for i, result := range results {
+		entry := result.Entry
+		p.Printf("%3d. %s%s", i+1, index.KindBadge(entry.Kind), entry.Title)
+		if len(entry.Tags) > 0 {
+			p.Printf(" [%s]", strings.Join(entry.Tags, ", "))
+		}
+		p.Printf("\n     %s\n", entry.ID)
+	}
+	p.Printf("\n")
+
+	for {
+		answer, err := p.Ask("Select a workflow (number, empty to quit)", "")
+		if err != nil {
+			return nil, err
+		}
+		if answer == "" {
+			return nil, nil
+		}
+
+		var n int
+		if _, err := fmt.Sscanf(answer, "%d", &n); err != nil || n < 1 || n > len(results) {
+			p.Printf("Please enter a number between 1 and %d.\n", len(results))
+			continue
+		}
+
+		entry := results[n-1].Entry
+		return &entry, nil
+	}
+}
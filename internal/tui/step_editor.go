@@ -8,6 +8,7 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/chazuruo/svf/internal/tui/theme"
 	"github.com/chazuruo/svf/internal/workflows"
 )
 
@@ -136,16 +137,16 @@ func (m *StepEditorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 // View renders the step editor.
 func (m *StepEditorModel) View() string {
 	titleStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("86")).
+		Foreground(theme.Current().Accent).
 		Bold(true).
 		MarginBottom(1)
 
 	labelStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("242")).
+		Foreground(theme.Current().Muted).
 		Width(12)
 
 	highlightStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("226")).
+		Foreground(theme.Current().Warning).
 		Bold(true)
 
 	title := titleStyle.Render(fmt.Sprintf("Edit Step %d", m.StepIndex+1))
@@ -170,7 +171,7 @@ func (m *StepEditorModel) View() string {
 
 	// Footer
 	footerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241")).
+		Foreground(theme.Current().Muted).
 		MarginTop(1)
 
 	footer := footerStyle.Render(
@@ -0,0 +1,192 @@
+// Package theme provides named color palettes for svf's TUI models.
+package theme
+
+import (
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// Default is the name of the default theme.
+	Default = "default"
+	// Light is the name of the theme tuned for light terminal backgrounds.
+	Light = "light"
+	// HighContrast is the name of the high-contrast theme.
+	HighContrast = "high-contrast"
+	// NoColor is the name of the theme that emits no color at all.
+	NoColor = "no-color"
+)
+
+// Theme is a palette of semantic colors used by the TUI models.
+type Theme struct {
+	// Name is the theme name as used in config.
+	Name string
+
+	// Accent is used for titles, headers, and emphasis.
+	Accent lipgloss.TerminalColor
+	// Text is used for normal body text.
+	Text lipgloss.TerminalColor
+	// Selected is used for the focused or selected item.
+	Selected lipgloss.TerminalColor
+	// Muted is used for secondary text, hints, and help.
+	Muted lipgloss.TerminalColor
+	// Border is used for borders and separators.
+	Border lipgloss.TerminalColor
+	// Success is used for completed steps and confirmations.
+	Success lipgloss.TerminalColor
+	// Warning is used for warnings and highlighted values.
+	Warning lipgloss.TerminalColor
+	// Caution is used for medium-severity items.
+	Caution lipgloss.TerminalColor
+	// Error is used for errors and failures.
+	Error lipgloss.TerminalColor
+	// Danger is used for high-severity items such as conflict markers.
+	Danger lipgloss.TerminalColor
+	// Info is used for informational items.
+	Info lipgloss.TerminalColor
+	// Highlight is used for secondary emphasis.
+	Highlight lipgloss.TerminalColor
+	// Surface is a subtle background for badges and highlighted blocks.
+	Surface lipgloss.TerminalColor
+}
+
+var themes = map[string]*Theme{
+	Default: {
+		Name:      Default,
+		Accent:    lipgloss.AdaptiveColor{Light: "30", Dark: "86"},
+		Text:      lipgloss.AdaptiveColor{Light: "236", Dark: "251"},
+		Selected:  lipgloss.AdaptiveColor{Light: "130", Dark: "229"},
+		Muted:     lipgloss.AdaptiveColor{Light: "243", Dark: "242"},
+		Border:    lipgloss.AdaptiveColor{Light: "250", Dark: "240"},
+		Success:   lipgloss.AdaptiveColor{Light: "28", Dark: "green"},
+		Warning:   lipgloss.AdaptiveColor{Light: "136", Dark: "226"},
+		Caution:   lipgloss.AdaptiveColor{Light: "166", Dark: "208"},
+		Error:     lipgloss.AdaptiveColor{Light: "160", Dark: "203"},
+		Danger:    lipgloss.AdaptiveColor{Light: "124", Dark: "196"},
+		Info:      lipgloss.AdaptiveColor{Light: "25", Dark: "blue"},
+		Highlight: lipgloss.AdaptiveColor{Light: "127", Dark: "212"},
+		Surface:   lipgloss.AdaptiveColor{Light: "254", Dark: "235"},
+	},
+	Light: {
+		Name:      Light,
+		Accent:    lipgloss.Color("30"),
+		Text:      lipgloss.Color("236"),
+		Selected:  lipgloss.Color("130"),
+		Muted:     lipgloss.Color("243"),
+		Border:    lipgloss.Color("250"),
+		Success:   lipgloss.Color("28"),
+		Warning:   lipgloss.Color("136"),
+		Caution:   lipgloss.Color("166"),
+		Error:     lipgloss.Color("160"),
+		Danger:    lipgloss.Color("124"),
+		Info:      lipgloss.Color("25"),
+		Highlight: lipgloss.Color("127"),
+		Surface:   lipgloss.Color("254"),
+	},
+	HighContrast: {
+		Name:      HighContrast,
+		Accent:    lipgloss.AdaptiveColor{Light: "0", Dark: "15"},
+		Text:      lipgloss.AdaptiveColor{Light: "0", Dark: "15"},
+		Selected:  lipgloss.AdaptiveColor{Light: "4", Dark: "11"},
+		Muted:     lipgloss.AdaptiveColor{Light: "0", Dark: "15"},
+		Border:    lipgloss.AdaptiveColor{Light: "0", Dark: "15"},
+		Success:   lipgloss.AdaptiveColor{Light: "2", Dark: "10"},
+		Warning:   lipgloss.AdaptiveColor{Light: "3", Dark: "11"},
+		Caution:   lipgloss.AdaptiveColor{Light: "3", Dark: "11"},
+		Error:     lipgloss.AdaptiveColor{Light: "1", Dark: "9"},
+		Danger:    lipgloss.AdaptiveColor{Light: "1", Dark: "9"},
+		Info:      lipgloss.AdaptiveColor{Light: "4", Dark: "14"},
+		Highlight: lipgloss.AdaptiveColor{Light: "5", Dark: "13"},
+		Surface:   lipgloss.NoColor{},
+	},
+	NoColor: {
+		Name:      NoColor,
+		Accent:    lipgloss.NoColor{},
+		Text:      lipgloss.NoColor{},
+		Selected:  lipgloss.NoColor{},
+		Muted:     lipgloss.NoColor{},
+		Border:    lipgloss.NoColor{},
+		Success:   lipgloss.NoColor{},
+		Warning:   lipgloss.NoColor{},
+		Caution:   lipgloss.NoColor{},
+		Error:     lipgloss.NoColor{},
+		Danger:    lipgloss.NoColor{},
+		Info:      lipgloss.NoColor{},
+		Highlight: lipgloss.NoColor{},
+		Surface:   lipgloss.NoColor{},
+	},
+}
+
+// aliases maps alternative names to themes.
+var aliases = map[string]string{
+	"dark":     Default,
+	"contrast": HighContrast,
+	"none":     NoColor,
+}
+
+var (
+	current      = themes[Default]
+	currentMutex sync.RWMutex
+)
+
+// Get returns the theme with the given name.
+func Get(name string) (*Theme, bool) {
+	if alias, ok := aliases[name]; ok {
+		name = alias
+	}
+	t, ok := themes[name]
+	return t, ok
+}
+
+// Names returns the names of all built-in themes.
+func Names() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Current returns the active theme.
+func Current() *Theme {
+	currentMutex.RLock()
+	defer currentMutex.RUnlock()
+	return current
+}
+
+// Set makes t the active theme.
+func Set(t *Theme) {
+	if t == nil {
+		return
+	}
+	currentMutex.Lock()
+	defer currentMutex.Unlock()
+	current = t
+}
+
+// NoColorEnv reports whether the NO_COLOR environment variable is set.
+// See https://no-color.org.
+func NoColorEnv() bool {
+	return os.Getenv("NO_COLOR") != ""
+}
+
+// Resolve picks the theme to use for the given config name.
+// The no-color theme wins when noColor is true or NO_COLOR is set.
+// An empty name selects the default theme. ok is false when name is
+// unknown, in which case the default theme is returned.
+func Resolve(name string, noColor bool) (t *Theme, ok bool) {
+	if noColor || NoColorEnv() {
+		return themes[NoColor], true
+	}
+	if name == "" {
+		return themes[Default], true
+	}
+	if t, ok := Get(name); ok {
+		return t, true
+	}
+	return themes[Default], false
+}
@@ -0,0 +1,94 @@
+package theme
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestGet(t *testing.T) {
+	for _, name := range Names() {
+		th, ok := Get(name)
+		if !ok {
+			t.Fatalf("expected theme %q to exist", name)
+		}
+		if th.Name != name {
+			t.Errorf("expected name %q, got %q", name, th.Name)
+		}
+	}
+
+	if th, ok := Get("dark"); !ok || th.Name != Default {
+		t.Errorf("expected dark to alias the default theme")
+	}
+
+	if _, ok := Get("nope"); ok {
+		t.Error("expected unknown theme lookup to fail")
+	}
+}
+
+func TestResolve(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+
+	tests := []struct {
+		name     string
+		input    string
+		noColor  bool
+		expected string
+		ok       bool
+	}{
+		{"empty uses default", "", false, Default, true},
+		{"named theme", Light, false, Light, true},
+		{"unknown falls back", "solarized", false, Default, false},
+		{"flag forces no-color", Light, true, NoColor, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			th, ok := Resolve(tt.input, tt.noColor)
+			if th.Name != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, th.Name)
+			}
+			if ok != tt.ok {
+				t.Errorf("expected ok=%v, got %v", tt.ok, ok)
+			}
+		})
+	}
+}
+
+func TestResolveNoColorEnv(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+
+	th, _ := Resolve(HighContrast, false)
+	if th.Name != NoColor {
+		t.Errorf("expected NO_COLOR to select %q, got %q", NoColor, th.Name)
+	}
+}
+
+func TestNoColorThemeHasNoColors(t *testing.T) {
+	th, _ := Get(NoColor)
+	colors := []lipgloss.TerminalColor{
+		th.Accent, th.Text, th.Selected, th.Muted, th.Border, th.Success,
+		th.Warning, th.Caution, th.Error, th.Danger, th.Info, th.Highlight, th.Surface,
+	}
+	for i, c := range colors {
+		if _, ok := c.(lipgloss.NoColor); !ok {
+			t.Errorf("color %d is %T, expected lipgloss.NoColor", i, c)
+		}
+	}
+}
+
+func TestSetAndCurrent(t *testing.T) {
+	orig := Current()
+	defer Set(orig)
+
+	light, _ := Get(Light)
+	Set(light)
+	if Current().Name != Light {
+		t.Errorf("expected current theme %q, got %q", Light, Current().Name)
+	}
+
+	Set(nil)
+	if Current().Name != Light {
+		t.Error("expected Set(nil) to be ignored")
+	}
+}
@@ -0,0 +1,129 @@
+// Package tuitest renders Bubble Tea models for snapshot tests and compares
+// the output against golden files.
+//
+// Golden files live in the calling package's testdata/golden directory.
+// Run the tests with -update (or UPDATE_GOLDEN=1) to rewrite them after an
+// intended view change, and review the diff before committing.
+package tuitest
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// update rewrites golden files instead of comparing against them.
+var update = flag.Bool("update", false, "update golden files")
+
+// ansiRegex matches CSI sequences (colors, cursor movement) and OSC
+// sequences (titles, hyperlinks).
+var ansiRegex = regexp.MustCompile("\x1b\\[[0-9;?]*[ -/]*[@-~]|\x1b\\][^\x07\x1b]*(\x07|\x1b\\\\)")
+
+// Send passes msgs to m in order and returns the updated model. Commands
+// returned by Update are ignored.
+func Send(m tea.Model, msgs ...tea.Msg) tea.Model {
+	for _, msg := range msgs {
+		m, _ = m.Update(msg)
+	}
+	return m
+}
+
+// Keys converts key names like "enter", "ctrl+c" or "y" to key messages
+// for Send.
+func Keys(keys ...string) []tea.Msg {
+	msgs := make([]tea.Msg, len(keys))
+	for i, k := range keys {
+		msgs[i] = key(k)
+	}
+	return msgs
+}
+
+// key converts a key name to a key message.
+func key(k string) tea.KeyMsg {
+	switch k {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	case "tab":
+		return tea.KeyMsg{Type: tea.KeyTab}
+	case "up":
+		return tea.KeyMsg{Type: tea.KeyUp}
+	case "down":
+		return tea.KeyMsg{Type: tea.KeyDown}
+	case "left":
+		return tea.KeyMsg{Type: tea.KeyLeft}
+	case "right":
+		return tea.KeyMsg{Type: tea.KeyRight}
+	case "backspace":
+		return tea.KeyMsg{Type: tea.KeyBackspace}
+	case " ", "space":
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	case "ctrl+c":
+		return tea.KeyMsg{Type: tea.KeyCtrlC}
+	case "ctrl+s":
+		return tea.KeyMsg{Type: tea.KeyCtrlS}
+	default:
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+	}
+}
+
+// Render sizes m to width x height, passes it msgs and returns its view
+// as plain text: escape sequences are stripped and trailing spaces
+// trimmed from each line, so snapshots don't depend on the terminal.
+func Render(m tea.Model, width, height int, msgs ...tea.Msg) string {
+	m = Send(m, tea.WindowSizeMsg{Width: width, Height: height})
+	m = Send(m, msgs...)
+	return Plain(m.View())
+}
+
+// Plain strips escape sequences and trailing spaces from rendered output.
+func Plain(view string) string {
+	lines := strings.Split(ansiRegex.ReplaceAllString(view, ""), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n") + "\n"
+}
+
+// Updating reports whether golden files are being rewritten.
+func Updating() bool {
+	return *update || os.Getenv("UPDATE_GOLDEN") != ""
+}
+
+// AssertGolden compares got with testdata/golden/<name>.golden, or writes
+// it there when updating.
+func AssertGolden(t testing.TB, name, got string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", "golden", name+".golden")
+	if Updating() {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create golden dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatalf("failed to write golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run with -update to create it): %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("view does not match %s (run with -update to accept)\n--- want\n%s\n--- got\n%s", path, want, got)
+	}
+}
+
+// Snapshot renders m like Render and compares the view with the golden
+// file for name.
+func Snapshot(t testing.TB, name string, m tea.Model, width, height int, msgs ...tea.Msg) {
+	t.Helper()
+	AssertGolden(t, name, Render(m, width, height, msgs...))
+}
@@ -0,0 +1,44 @@
+package tuitest
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// sizeModel shows its size and the keys it received.
+type sizeModel struct {
+	width, height int
+	keys          string
+}
+
+func (m sizeModel) Init() tea.Cmd { return nil }
+
+func (m sizeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+	case tea.KeyMsg:
+		m.keys += "[" + msg.String() + "]"
+	}
+	return m, nil
+}
+
+func (m sizeModel) View() string {
+	return "\x1b[1;31msize\x1b[0m   \n" + string(rune('0'+m.width/10)) + string(rune('0'+m.width%10)) + " " + m.keys + "\n\n"
+}
+
+func TestRender(t *testing.T) {
+	got := Render(sizeModel{}, 42, 10, Keys("enter", "y", "ctrl+c", " ")...)
+	want := "size\n42 [enter][y][ctrl+c][ ]\n"
+	if got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+}
+
+func TestPlain(t *testing.T) {
+	got := Plain("\x1b]8;;https://example.com\x07link\x1b]8;;\x07 \x1b[38;2;1;2;3mtext\x1b[m  ")
+	if got != "link text\n" {
+		t.Errorf("Plain() = %q", got)
+	}
+}
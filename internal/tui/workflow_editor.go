@@ -12,6 +12,7 @@ import (
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/chazuruo/svf/internal/tui/theme"
 	"github.com/chazuruo/svf/internal/workflows"
 )
 
@@ -37,6 +38,8 @@ type WorkflowEditorModel struct {
 	editing    editingState
 	quit       bool
 	saved      bool
+	// saveErr is the validation error from the last save attempt
+	saveErr    error
 	// Sub-models
 	stepEditor       *StepEditorModel
 	placeholderEditor *PlaceholderEditorModel
@@ -298,6 +301,12 @@ func (m WorkflowEditorModel) handlePlaceholderEditing(msg tea.Msg) (tea.Model, t
 func (m WorkflowEditorModel) handleEditorMsg(msg WorkflowEditorMsg) (tea.Model, tea.Cmd) {
 	switch msg.Type {
 	case "save":
+		// Stay in the editor until the workflow is valid
+		if err := m.workflow.Validate(); err != nil {
+			m.saveErr = err
+			return m, nil
+		}
+		m.saveErr = nil
 		m.saved = true
 		m.quit = true
 		return m, tea.Quit
@@ -374,12 +383,12 @@ func (m WorkflowEditorModel) View() string {
 // renderHeader renders the header with title, description, and tags inputs.
 func (m WorkflowEditorModel) renderHeader() string {
 	titleStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("86")).
+		Foreground(theme.Current().Accent).
 		Bold(true).
 		MarginBottom(1)
 
 	labelStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("242")).
+		Foreground(theme.Current().Muted).
 		Width(8)
 
 	titleLabel := labelStyle.Render("Title:")
@@ -407,7 +416,7 @@ func (m WorkflowEditorModel) renderSteps() string {
 // renderFooter renders the footer with help text.
 func (m WorkflowEditorModel) renderFooter() string {
 	helpStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241")).
+		Foreground(theme.Current().Muted).
 		MarginTop(1)
 
 	help := " [Ctrl+S]: save [Ctrl+Q]: quit [Enter]: edit step [Ctrl+N]: new step\n" +
@@ -418,6 +427,11 @@ func (m WorkflowEditorModel) renderFooter() string {
 			" [Ctrl+J/K]: move step [Del]: delete step [Ctrl+P]: placeholders [↑/↓]: navigate"
 	}
 
+	if m.saveErr != nil {
+		errStyle := lipgloss.NewStyle().Foreground(theme.Current().Error)
+		return errStyle.Render(" Cannot save: "+m.saveErr.Error()) + "\n" + helpStyle.Render(help)
+	}
+
 	return helpStyle.Render(help)
 }
 
@@ -453,11 +467,11 @@ func (d stepDelegate) Render(w io.Writer, m list.Model, index int, listItem list
 
 	// Styles
 	selectedStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("229")).
+		Foreground(theme.Current().Selected).
 		Bold(true)
 
 	normalStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("251"))
+		Foreground(theme.Current().Text)
 
 	var text string
 	if index == m.Index() {
@@ -518,3 +532,73 @@ func (m *WorkflowEditorModel) DidQuit() bool {
 func (m *WorkflowEditorModel) DidSave() bool {
 	return m.saved
 }
+
+// EditWorkflowLine edits a workflow with sequential line prompts. It is the
+// fallback for WorkflowEditorModel when no TUI is available. saved is false
+// if the user chose not to save.
+func EditWorkflowLine(wf *workflows.Workflow, p *LinePrompter) (saved bool, err error) {
+	if wf.Title, err = p.Ask("Title", wf.Title); err != nil {
+		return false, err
+	}
+	if wf.Description, err = p.Ask("Description", wf.Description); err != nil {
+		return false, err
+	}
+	tags, err := p.Ask("Tags (comma-separated)", strings.Join(wf.Tags, ", "))
+	if err != nil {
+		return false, err
+	}
+	wf.Tags = parseTags(tags)
+
+	// Review existing steps
+	var steps []workflows.Step
+	for i, step := range wf.Steps {
+		p.Printf("\nStep %d/%d: %s\n  $ %s\n", i+1, len(wf.Steps), step.Name, step.Command)
+
+		choice, err := p.Choose("", []Choice{
+			{Key: "k", Label: "keep"},
+			{Key: "e", Label: "edit"},
+			{Key: "d", Label: "delete"},
+		}, "k")
+		if err != nil {
+			return false, err
+		}
+
+		switch choice {
+		case "d":
+			continue
+		case "e":
+			if step.Name, err = p.Ask("  Name", step.Name); err != nil {
+				return false, err
+			}
+			if step.Command, err = p.Ask("  Command", step.Command); err != nil {
+				return false, err
+			}
+		}
+		steps = append(steps, step)
+	}
+
+	// Add new steps
+	for {
+		add, err := p.Confirm("\nAdd a step?", len(steps) == 0)
+		if err != nil {
+			return false, err
+		}
+		if !add {
+			break
+		}
+
+		var step workflows.Step
+		if step.Name, err = p.Ask("  Name", fmt.Sprintf("Step %d", len(steps)+1)); err != nil {
+			return false, err
+		}
+		if step.Command, err = p.Ask("  Command", ""); err != nil {
+			return false, err
+		}
+		if step.Command != "" {
+			steps = append(steps, step)
+		}
+	}
+	wf.Steps = steps
+
+	return p.Confirm("\nSave workflow?", true)
+}
@@ -0,0 +1,34 @@
+package tui
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+func TestWorkflowEditor_SaveValidates(t *testing.T) {
+	wf := &workflows.Workflow{SchemaVersion: workflows.SchemaVersion}
+	m := NewWorkflowEditor(context.Background(), wf)
+
+	// Invalid workflows keep the editor open with an error
+	model, cmd := m.Update(WorkflowEditorMsg{Type: "save"})
+	m = model.(WorkflowEditorModel)
+	if cmd != nil || m.DidSave() {
+		t.Fatal("saving an invalid workflow should not quit")
+	}
+	if m.saveErr == nil {
+		t.Error("saveErr should be set after a failed save")
+	}
+
+	wf.Title = "Valid"
+	wf.Steps = []workflows.Step{{Name: "run", Command: "echo ok"}}
+	model, cmd = m.Update(WorkflowEditorMsg{Type: "save"})
+	m = model.(WorkflowEditorModel)
+	if cmd == nil || !m.DidSave() {
+		t.Error("saving a valid workflow should quit with the workflow saved")
+	}
+	if m.saveErr != nil {
+		t.Errorf("saveErr = %v after a successful save", m.saveErr)
+	}
+}
@@ -0,0 +1,33 @@
+package upgrade
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ArchiveName returns the name of the release archive of binary for the
+// platform, following goreleaser's {binary}_{version}_{os}_{arch}.{ext}
+// naming. version may be a tag: goreleaser drops the leading "v".
+func ArchiveName(binary, version string, p Platform) string {
+	return fmt.Sprintf("%s_%s_%s_%s%s", binary, strings.TrimPrefix(version, "v"), p.OS, p.Arch, p.ArchiveExtension())
+}
+
+// ChecksumsName returns the name of the checksums file of a release.
+func ChecksumsName(binary, version string) string {
+	return fmt.Sprintf("%s_%s_checksums.txt", binary, strings.TrimPrefix(version, "v"))
+}
+
+// ParseChecksums parses a sha256sum-style checksums file into SHA256
+// hashes by file name.
+func ParseChecksums(data string) map[string]string {
+	sums := make(map[string]string)
+	for _, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		// Binary mode marks the name with a leading "*"
+		sums[strings.TrimPrefix(fields[1], "*")] = fields[0]
+	}
+	return sums
+}
@@ -0,0 +1,44 @@
+package upgrade
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveName(t *testing.T) {
+	assert.Equal(t, "svf_1.2.3_darwin_arm64.tar.gz", ArchiveName("svf", "v1.2.3", Platform{OS: "darwin", Arch: "arm64"}))
+	assert.Equal(t, "svf_1.2.3_windows_amd64.zip", ArchiveName("svf", "1.2.3", Platform{OS: "windows", Arch: "amd64"}))
+	assert.Equal(t, "svf_1.2.3_checksums.txt", ChecksumsName("svf", "v1.2.3"))
+}
+
+func TestAssetFinder(t *testing.T) {
+	release := &Release{
+		TagName: "v1.2.3",
+		Assets: []Asset{
+			{Name: "svf_1.2.3_checksums.txt"},
+			{Name: "svf_1.2.3_linux_amd64.tar.gz", URL: "https://example.com/linux"},
+		},
+	}
+	finder := NewAssetFinder(Platform{OS: "linux", Arch: "amd64"}, "svf")
+
+	binary, err := finder.FindBinary(release)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/linux", binary.URL)
+
+	checksums, err := finder.FindChecksum(release)
+	require.NoError(t, err)
+	assert.Equal(t, "svf_1.2.3_checksums.txt", checksums.Name)
+
+	_, err = NewAssetFinder(Platform{OS: "darwin", Arch: "arm64"}, "svf").FindBinary(release)
+	assert.Error(t, err)
+}
+
+func TestParseChecksums(t *testing.T) {
+	sums := ParseChecksums("abc  svf_1.2.3_linux_amd64.tar.gz\ndef *svf_1.2.3_windows_amd64.zip\n\n")
+	assert.Equal(t, map[string]string{
+		"svf_1.2.3_linux_amd64.tar.gz": "abc",
+		"svf_1.2.3_windows_amd64.zip":  "def",
+	}, sums)
+}
@@ -22,20 +22,9 @@ func NewAssetFinder(platform Platform, binaryName string) *AssetFinder {
 
 // FindBinary finds the binary asset for the current platform.
 func (f *AssetFinder) FindBinary(release *Release) (*Asset, error) {
-	// goreleaser naming: {binary}_{version}_{os}_{arch}.{ext}
-	pattern := fmt.Sprintf("%s_%s_%s.%s",
-		f.binaryName,
-		release.TagName,
-		f.platform.OS,
-		f.platform.Arch,
-	)
-	ext := f.platform.ArchiveExtension()
-	fullPattern := pattern + strings.TrimPrefix(ext, ".")
-
-	for _, asset := range release.Assets {
-		if asset.Name == fullPattern {
-			return &asset, nil
-		}
+	name := ArchiveName(f.binaryName, release.TagName, f.platform)
+	if asset := findAsset(release, name); asset != nil {
+		return asset, nil
 	}
 
 	return nil, NewError(ExitGenericError, fmt.Sprintf("No binary found for platform %s", f.platform.String()), nil)
@@ -43,32 +32,24 @@ func (f *AssetFinder) FindBinary(release *Release) (*Asset, error) {
 
 // FindChecksum finds the checksums file.
 func (f *AssetFinder) FindChecksum(release *Release) (*Asset, error) {
-	// goreleaser naming: {binary}_{version}_checksums.txt
-	pattern := fmt.Sprintf("%s_%s_checksums.txt", f.binaryName, release.TagName)
-
-	for _, asset := range release.Assets {
-		if asset.Name == pattern {
-			return &asset, nil
-		}
-	}
-
 	// Checksums are optional
-	return nil, nil
+	return findAsset(release, ChecksumsName(f.binaryName, release.TagName)), nil
 }
 
 // FindSignature finds the signature file.
 func (f *AssetFinder) FindSignature(release *Release) (*Asset, error) {
-	// goreleaser naming: {binary}_{version}_checksums.txt.sig
-	pattern := fmt.Sprintf("%s_%s_checksums.txt.sig", f.binaryName, release.TagName)
+	// Signatures are optional
+	return findAsset(release, ChecksumsName(f.binaryName, release.TagName)+".sig"), nil
+}
 
+// findAsset returns the release's asset called name, or nil.
+func findAsset(release *Release, name string) *Asset {
 	for _, asset := range release.Assets {
-		if asset.Name == pattern {
-			return &asset, nil
+		if asset.Name == name {
+			return &asset
 		}
 	}
-
-	// Signatures are optional
-	return nil, nil
+	return nil
 }
 
 // ExtractBinaryName extracts the binary name from the archive filename.
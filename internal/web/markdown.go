@@ -0,0 +1,127 @@
+package web
+
+import (
+	"html"
+	"html/template"
+	"regexp"
+	"strings"
+)
+
+var (
+	// inlineCodeRegex matches `code` spans
+	inlineCodeRegex = regexp.MustCompile("`([^`]+)`")
+	// boldRegex matches **bold** text
+	boldRegex = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	// tableDividerRegex matches the |---|---| line under a table header
+	tableDividerRegex = regexp.MustCompile(`^\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?$`)
+)
+
+// renderMarkdown converts the subset of Markdown used by workflow READMEs
+// (headings, fenced code, lists, pipe tables, paragraphs, inline code and
+// bold) to HTML. Everything else is escaped and shown as text, so custom
+// README templates can't inject markup.
+func renderMarkdown(src string) template.HTML {
+	var out strings.Builder
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+
+	var para []string
+	flushPara := func() {
+		if len(para) > 0 {
+			out.WriteString("<p>" + renderInline(strings.Join(para, " ")) + "</p>\n")
+			para = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			flushPara()
+
+		case strings.HasPrefix(trimmed, "```"):
+			flushPara()
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, lines[i])
+			}
+			out.WriteString(`<pre class="code"><code>` + html.EscapeString(strings.Join(code, "\n")) + "</code></pre>\n")
+
+		case strings.HasPrefix(trimmed, "#"):
+			flushPara()
+			level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+			if level > 6 {
+				level = 6
+			}
+			tag := string(rune('0' + level))
+			out.WriteString("<h" + tag + ">" + renderInline(strings.TrimSpace(trimmed[level:])) + "</h" + tag + ">\n")
+
+		case strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* "):
+			flushPara()
+			out.WriteString("<ul>\n")
+			for ; i < len(lines); i++ {
+				item := strings.TrimSpace(lines[i])
+				if !strings.HasPrefix(item, "- ") && !strings.HasPrefix(item, "* ") {
+					i--
+					break
+				}
+				out.WriteString("<li>" + renderInline(item[2:]) + "</li>\n")
+			}
+			out.WriteString("</ul>\n")
+
+		case strings.HasPrefix(trimmed, "|") && i+1 < len(lines) && tableDividerRegex.MatchString(strings.TrimSpace(lines[i+1])):
+			flushPara()
+			out.WriteString("<table>\n<thead><tr>")
+			for _, cell := range tableCells(trimmed) {
+				out.WriteString("<th>" + renderInline(cell) + "</th>")
+			}
+			out.WriteString("</tr></thead>\n<tbody>\n")
+			for i += 2; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "|"); i++ {
+				out.WriteString("<tr>")
+				for _, cell := range tableCells(strings.TrimSpace(lines[i])) {
+					out.WriteString("<td>" + renderInline(cell) + "</td>")
+				}
+				out.WriteString("</tr>\n")
+			}
+			i--
+			out.WriteString("</tbody>\n</table>\n")
+
+		default:
+			para = append(para, trimmed)
+		}
+	}
+	flushPara()
+
+	return template.HTML(out.String())
+}
+
+// renderInline escapes text and applies inline code and bold. <br>, which
+// README tables use for multi-line commands, is kept as a line break.
+func renderInline(s string) string {
+	s = strings.ReplaceAll(html.EscapeString(s), "&lt;br&gt;", "<br>")
+	s = inlineCodeRegex.ReplaceAllString(s, "<code>$1</code>")
+	return boldRegex.ReplaceAllString(s, "<strong>$1</strong>")
+}
+
+// tableCells splits a pipe table row into trimmed cells, honouring the
+// \| escape that README tables use for pipes in commands.
+func tableCells(row string) []string {
+	row = strings.TrimSuffix(strings.TrimPrefix(row, "|"), "|")
+
+	var cells []string
+	var cell strings.Builder
+	for j := 0; j < len(row); j++ {
+		switch {
+		case row[j] == '\\' && j+1 < len(row) && row[j+1] == '|':
+			cell.WriteByte('|')
+			j++
+		case row[j] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(row[j])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
@@ -0,0 +1,34 @@
+package web
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	src := "# Deploy <api>\n\nRolls out the **API**.\n\n## Tags\n\n- k8s\n- prod\n\n" +
+		"| # | Step | Command |\n|---|------|---------|\n| 1 | Apply | `kubectl apply \\| tee` |\n\n" +
+		"```\nkubectl rollout status <deploy>\n```\n"
+
+	got := string(renderMarkdown(src))
+
+	for _, want := range []string{
+		"<h1>Deploy &lt;api&gt;</h1>",
+		"<p>Rolls out the <strong>API</strong>.</p>",
+		"<ul>\n<li>k8s</li>\n<li>prod</li>\n</ul>",
+		"<th>Command</th>",
+		"<td><code>kubectl apply | tee</code></td>",
+		"<pre class=\"code\"><code>kubectl rollout status &lt;deploy&gt;</code></pre>",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("renderMarkdown() missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestRenderMarkdown_EscapesHTML(t *testing.T) {
+	got := string(renderMarkdown("<script>alert(1)</script>"))
+	if strings.Contains(got, "<script>") {
+		t.Errorf("renderMarkdown() did not escape HTML: %s", got)
+	}
+}
@@ -0,0 +1,221 @@
+// Package web serves a read-only HTML view of the workflow library, for
+// people who browse runbooks without the CLI.
+package web
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/index"
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+// Server renders the search index and workflow READMEs over HTTP. It
+// never writes to the workflows; the only files it may write are the
+// search index, when it is missing or corrupt, and whatever CheckView
+// records, such as the audit log.
+type Server struct {
+	config  *config.Config
+	builder *index.Builder
+	pages   map[string]*template.Template
+	opts    Options
+}
+
+// Options configures a Server.
+type Options struct {
+	// CheckView, if set, is called before a workflow is shown and can
+	// refuse it, e.g. to record access to sensitive workflows.
+	CheckView func(wf *workflows.Workflow) error
+}
+
+// New creates a server for the repository in cfg.
+func New(cfg *config.Config, opts Options) (*Server, error) {
+	pages := make(map[string]*template.Template)
+	for _, name := range []string{"index.html", "workflow.html"} {
+		tmpl, err := template.ParseFS(templateFS, "templates/layout.html", "templates/"+name)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", name, err)
+		}
+		pages[name] = tmpl
+	}
+
+	return &Server{
+		config:  cfg,
+		builder: index.NewBuilder(cfg.Repo.Path, cfg),
+		pages:   pages,
+		opts:    opts,
+	}, nil
+}
+
+// Handler returns the HTTP handler serving the UI.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.handleIndex)
+	mux.HandleFunc("GET /workflows/{path...}", s.handleWorkflow)
+	return mux
+}
+
+// tagCount is a tag and the number of workflows carrying it.
+type tagCount struct {
+	Name  string
+	Count int
+}
+
+// indexPage is the data for index.html.
+type indexPage struct {
+	Query   string
+	Tag     string
+	Error   string
+	Tags    []tagCount
+	Results []index.SearchResult
+	Total   int
+}
+
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	idx, err := s.builder.LoadFresh()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	page := indexPage{
+		Query: r.URL.Query().Get("q"),
+		Tag:   r.URL.Query().Get("tag"),
+		Tags:  countTags(idx),
+		Total: len(idx.Workflows),
+	}
+
+	opts := index.SearchOptions{Query: page.Query}
+	if page.Tag != "" {
+		opts.Tags = []string{page.Tag}
+	}
+	page.Results, err = idx.Query(opts)
+	if err != nil {
+		page.Error = fmt.Sprintf("invalid query: %v", err)
+	}
+
+	s.render(w, "index.html", page)
+}
+
+// workflowPage is the data for workflow.html.
+type workflowPage struct {
+	Entry  index.WorkflowEntry
+	Readme template.HTML
+	// RunRef is what to pass to 'svf run' for the workflow.
+	RunRef string
+}
+
+// runRef returns the reference 'svf run' takes for wf at entry: its first
+// alias, its ID, or its repo-relative location when it has neither. The
+// index's generated IDs don't resolve, so only wf's own ID is used.
+func runRef(entry index.WorkflowEntry, wf *workflows.Workflow) string {
+	switch {
+	case len(wf.Aliases) > 0:
+		return wf.Aliases[0]
+	case wf.ID != "":
+		return wf.ID
+	default:
+		return entry.Location()
+	}
+}
+
+func (s *Server) handleWorkflow(w http.ResponseWriter, r *http.Request) {
+	idx, err := s.builder.LoadFresh()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Only indexed workflows are served, so the path can't escape the repo
+	// Documents of a multi-document file are chosen with ?doc=N
+	path := r.PathValue("path")
+	if doc := r.URL.Query().Get("doc"); doc != "" {
+		path += "#" + doc
+	}
+	entry := idx.GetByPath(path)
+	if entry == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	path = filepath.Join(s.config.Repo.Path, entry.Path)
+	wf, err := loadDocument(path, entry.Doc)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to load workflow: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if s.opts.CheckView != nil {
+		if err := s.opts.CheckView(wf); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+	}
+
+	readme, err := s.readme(path, entry.Path, wf)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	s.render(w, "workflow.html", workflowPage{Entry: *entry, Readme: renderMarkdown(readme), RunRef: runRef(*entry, wf)})
+}
+
+// readme returns the README.md next to the workflow at path, rendering
+// one from wf when the repo doesn't have it.
+func (s *Server) readme(path, relPath string, wf *workflows.Workflow) (string, error) {
+	if data, err := os.ReadFile(filepath.Join(filepath.Dir(path), "README.md")); err == nil {
+		return string(data), nil
+	}
+	return store.RenderReadme(nil, wf, relPath)
+}
+
+// loadDocument loads the workflow at the 1-based doc of the file at path,
+// or its only workflow when doc is 0.
+func loadDocument(path string, doc int) (*workflows.Workflow, error) {
+	wfs, err := workflows.LoadAll(path)
+	if err != nil {
+		return nil, err
+	}
+	i := max(doc-1, 0)
+	if i >= len(wfs) {
+		return nil, fmt.Errorf("%s has no document %d", path, doc)
+	}
+	return wfs[i], nil
+}
+
+// render executes a page template.
+func (s *Server) render(w http.ResponseWriter, name string, data any) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := s.pages[name].ExecuteTemplate(w, "layout", data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// countTags returns the tags in the index with their workflow counts,
+// sorted by name.
+func countTags(idx *index.Index) []tagCount {
+	counts := make(map[string]int)
+	for _, entry := range idx.Workflows {
+		for _, tag := range entry.Tags {
+			counts[strings.ToLower(tag)]++
+		}
+	}
+
+	tags := make([]tagCount, 0, len(counts))
+	for name, count := range counts {
+		tags = append(tags, tagCount{Name: name, Count: count})
+	}
+	sort.Slice(tags, func(a, b int) bool { return tags[a].Name < tags[b].Name })
+	return tags
+}
@@ -0,0 +1,209 @@
+package web
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// setupServer creates a repo with three workflow files, one of them
+// holding two workflows, and a server for it.
+func setupServer(t *testing.T, opts Options) *httptest.Server {
+	t.Helper()
+	dir := t.TempDir()
+	write := func(rel, content string) {
+		t.Helper()
+		path := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("workflows/team/alice/deploy/workflow.yaml", `schema_version: 1
+title: Deploy API
+tags: [k8s]
+steps:
+  - name: Apply
+    command: kubectl apply -f api.yaml
+`)
+	write("shared/backup/workflow.yaml", `schema_version: 1
+id: wf_backup
+title: Backup database
+tags: [db]
+steps:
+  - command: pg_dump prod
+`)
+	write("shared/backup/README.md", "# Backup database\n\nNightly backup runbook.\n")
+	write("workflows/team/alice/certs/workflow.yaml", `schema_version: 1
+title: Renew certs
+steps:
+  - command: certbot renew
+---
+schema_version: 1
+title: Revoke cert
+steps:
+  - command: certbot revoke
+`)
+
+	cfg := config.DefaultConfig()
+	cfg.Repo.Path = dir
+	srv, err := New(cfg, opts)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// get fetches a page and returns its status and body.
+func get(t *testing.T, url string) (int, string) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, string(body)
+}
+
+func TestServer_Index(t *testing.T) {
+	ts := setupServer(t, Options{})
+
+	tests := []struct {
+		name    string
+		path    string
+		want    []string
+		notWant []string
+	}{
+		{name: "all", path: "/", want: []string{"Deploy API", "Backup database", "k8s (1)"}},
+		{name: "search", path: "/?q=deploy", want: []string{"Deploy API"}, notWant: []string{"shared/backup"}},
+		{name: "tag", path: "/?tag=db", want: []string{"Backup database"}, notWant: []string{"workflows/team/alice/deploy"}},
+		{name: "bad query", path: "/?q=%22unterminated", want: []string{"invalid query"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := get(t, ts.URL+tt.path)
+			if status != http.StatusOK {
+				t.Fatalf("GET %s = %d", tt.path, status)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(body, want) {
+					t.Errorf("GET %s missing %q", tt.path, want)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(body, notWant) {
+					t.Errorf("GET %s should not contain %q", tt.path, notWant)
+				}
+			}
+		})
+	}
+}
+
+func TestServer_ReadOnlyIndex(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "workflows", "team", "alice", "deploy", "workflow.yaml")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("schema_version: 1\ntitle: Deploy API\nsteps:\n  - command: kubectl apply\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Repo.Path = dir
+	cfg.ReadOnly = true
+	srv, err := New(cfg, Options{})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+
+	if status, body := get(t, ts.URL+"/"); status != http.StatusOK || !strings.Contains(body, "Deploy API") {
+		t.Errorf("GET / = %d, body missing workflow:\n%s", status, body)
+	}
+	if _, err := os.Stat(filepath.Join(dir, cfg.Workflows.IndexPath)); !os.IsNotExist(err) {
+		t.Errorf("index saved in read-only mode: %v", err)
+	}
+}
+
+func TestServer_Workflow(t *testing.T) {
+	ts := setupServer(t, Options{})
+
+	// Rendered from the workflow when there is no README.md
+	status, body := get(t, ts.URL+"/workflows/workflows/team/alice/deploy/workflow.yaml")
+	if status != http.StatusOK || !strings.Contains(body, "<code>kubectl apply -f api.yaml</code>") {
+		t.Errorf("GET deploy = %d, body missing command:\n%s", status, body)
+	}
+	// Workflows without an ID or alias are run by their path
+	if !strings.Contains(body, "<code>svf run workflows/team/alice/deploy/workflow.yaml</code>") {
+		t.Errorf("GET deploy body missing run command:\n%s", body)
+	}
+
+	// The repo's README.md when there is one
+	status, body = get(t, ts.URL+"/workflows/shared/backup/workflow.yaml")
+	if status != http.StatusOK || !strings.Contains(body, "Nightly backup runbook.") {
+		t.Errorf("GET backup = %d, body missing README text:\n%s", status, body)
+	}
+	if !strings.Contains(body, "<code>svf run wf_backup</code>") {
+		t.Errorf("GET backup body missing run command:\n%s", body)
+	}
+
+	// A document of a multi-document file is rendered from that document
+	status, body = get(t, ts.URL+"/workflows/workflows/team/alice/certs/workflow.yaml?doc=2")
+	if status != http.StatusOK || !strings.Contains(body, "certbot revoke") || strings.Contains(body, "certbot renew") {
+		t.Errorf("GET certs#2 = %d, want only the second document:\n%s", status, body)
+	}
+	if !strings.Contains(body, "<code>svf run workflows/team/alice/certs/workflow.yaml#2</code>") {
+		t.Errorf("GET certs#2 body missing run command:\n%s", body)
+	}
+
+	// Only indexed workflows are served
+	for _, path := range []string{"/workflows/shared/backup/README.md", "/workflows/../../etc/passwd"} {
+		if status, _ := get(t, ts.URL+path); status != http.StatusNotFound {
+			t.Errorf("GET %s = %d, want 404", path, status)
+		}
+	}
+}
+
+func TestServer_Workflow_CheckView(t *testing.T) {
+	var viewed []string
+	ts := setupServer(t, Options{
+		CheckView: func(wf *workflows.Workflow) error {
+			viewed = append(viewed, wf.Title)
+			if wf.Title == "Backup database" {
+				return errors.New("audit log unavailable")
+			}
+			return nil
+		},
+	})
+
+	if status, _ := get(t, ts.URL+"/workflows/workflows/team/alice/deploy/workflow.yaml"); status != http.StatusOK {
+		t.Errorf("GET deploy = %d, want 200", status)
+	}
+	// Refused even though the page comes from the repo's README.md
+	status, body := get(t, ts.URL+"/workflows/shared/backup/workflow.yaml")
+	if status != http.StatusForbidden || strings.Contains(body, "Nightly backup runbook.") {
+		t.Errorf("GET backup = %d, want 403 without the README:\n%s", status, body)
+	}
+	if want := []string{"Deploy API", "Backup database"}; !slices.Equal(viewed, want) {
+		t.Errorf("CheckView() saw %v, want %v", viewed, want)
+	}
+}
@@ -0,0 +1,263 @@
+package workflows
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Extractor selects a value from a step's standard output. Exactly one of
+// JSONPath, Regex or the Line/Field selectors is used.
+type Extractor struct {
+	JSONPath  string `yaml:"jsonpath,omitempty"`  // e.g. ".items[0].metadata.name"
+	Regex     string `yaml:"regex,omitempty"`     // First match, or its first group
+	Line      int    `yaml:"line,omitempty"`      // 1-based line; negative counts from the end
+	Field     int    `yaml:"field,omitempty"`     // 1-based field of the line; negative counts from the end
+	Separator string `yaml:"separator,omitempty"` // Field separator (default: whitespace)
+}
+
+// captureNameRegex matches names that can be used as placeholders.
+var captureNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_-]*$`)
+
+// Validate checks that the extractor selects exactly one way and that its
+// pattern or path parses.
+func (e Extractor) Validate() error {
+	kinds := 0
+	if e.JSONPath != "" {
+		kinds++
+		if _, err := parseJSONPath(e.JSONPath); err != nil {
+			return err
+		}
+	}
+	if e.Regex != "" {
+		kinds++
+		if _, err := regexp.Compile(e.Regex); err != nil {
+			return fmt.Errorf("invalid regex pattern: %w", err)
+		}
+	}
+	if e.Line != 0 || e.Field != 0 {
+		kinds++
+	}
+	switch {
+	case kinds == 0:
+		return errors.New("set one of jsonpath, regex, or line/field")
+	case kinds > 1:
+		return errors.New("use only one of jsonpath, regex, or line/field")
+	case e.Separator != "" && e.Field == 0:
+		return errors.New("separator requires field")
+	}
+	return nil
+}
+
+// Extract returns the value the extractor selects from output.
+func (e Extractor) Extract(output string) (string, error) {
+	switch {
+	case e.JSONPath != "":
+		return extractJSONPath(output, e.JSONPath)
+	case e.Regex != "":
+		re, err := regexp.Compile(e.Regex)
+		if err != nil {
+			return "", err
+		}
+		m := re.FindStringSubmatch(output)
+		if m == nil {
+			return "", fmt.Errorf("no match for %q", e.Regex)
+		}
+		if len(m) > 1 {
+			return m[1], nil
+		}
+		return m[0], nil
+	default:
+		return extractLineField(output, e.Line, e.Field, e.Separator)
+	}
+}
+
+// validateCaptures checks a step's capture names and extractors.
+func validateCaptures(captures map[string]Extractor) error {
+	names := make([]string, 0, len(captures))
+	for name := range captures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !captureNameRegex.MatchString(name) {
+			return fmt.Errorf("invalid capture name %q: use letters, digits, '_' and '-'", name)
+		}
+		if err := captures[name].Validate(); err != nil {
+			return fmt.Errorf("capture %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Captures applies a step's extractors to its standard output and returns
+// the captured values by name.
+func (s *Step) Captures(output string) (map[string]string, error) {
+	if len(s.Capture) == 0 {
+		return nil, nil
+	}
+	names := make([]string, 0, len(s.Capture))
+	for name := range s.Capture {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	values := make(map[string]string, len(names))
+	for _, name := range names {
+		value, err := s.Capture[name].Extract(output)
+		if err != nil {
+			return nil, fmt.Errorf("capture %s: %w", name, err)
+		}
+		values[name] = value
+	}
+	return values, nil
+}
+
+// CaptureStep returns the index of the first step that captures name, or
+// -1 if no step does.
+func (w *Workflow) CaptureStep(name string) int {
+	for i, step := range w.Steps {
+		if _, ok := step.Capture[name]; ok {
+			return i
+		}
+	}
+	return -1
+}
+
+// parseJSONPath splits a path like "$.items[0].metadata.name" or
+// `.data["tls.crt"]` into object keys (strings) and array indexes (ints).
+func parseJSONPath(path string) ([]any, error) {
+	p := strings.TrimPrefix(path, "$")
+	var parts []any
+	for p != "" {
+		switch {
+		case strings.HasPrefix(p, "."):
+			p = p[1:]
+			end := strings.IndexAny(p, ".[")
+			if end < 0 {
+				end = len(p)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("invalid jsonpath %q: empty key", path)
+			}
+			parts = append(parts, p[:end])
+			p = p[end:]
+		case strings.HasPrefix(p, "["):
+			end := strings.Index(p, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("invalid jsonpath %q: missing ]", path)
+			}
+			inner := p[1:end]
+			if key, err := strconv.Unquote(inner); err == nil && strings.HasPrefix(inner, `"`) {
+				parts = append(parts, key)
+			} else if n, err := strconv.Atoi(inner); err == nil {
+				parts = append(parts, n)
+			} else {
+				return nil, fmt.Errorf("invalid jsonpath %q: bad index [%s]", path, inner)
+			}
+			p = p[end+1:]
+		default:
+			return nil, fmt.Errorf("invalid jsonpath %q: expected . or [", path)
+		}
+	}
+	return parts, nil
+}
+
+// extractJSONPath parses output as JSON and returns the value at path.
+// Strings are returned as-is; other values are returned as JSON.
+func extractJSONPath(output, path string) (string, error) {
+	parts, err := parseJSONPath(path)
+	if err != nil {
+		return "", err
+	}
+
+	dec := json.NewDecoder(strings.NewReader(output))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return "", fmt.Errorf("output is not JSON: %w", err)
+	}
+
+	for _, part := range parts {
+		switch key := part.(type) {
+		case string:
+			obj, ok := value.(map[string]any)
+			if !ok {
+				return "", fmt.Errorf("%s: .%s is not an object key", path, key)
+			}
+			if value, ok = obj[key]; !ok {
+				return "", fmt.Errorf("%s: no key %q", path, key)
+			}
+		case int:
+			arr, ok := value.([]any)
+			if !ok {
+				return "", fmt.Errorf("%s: [%d] is not an array index", path, key)
+			}
+			if key < 0 {
+				key += len(arr)
+			}
+			if key < 0 || key >= len(arr) {
+				return "", fmt.Errorf("%s: index out of range (%d items)", path, len(arr))
+			}
+			value = arr[key]
+		}
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return "", fmt.Errorf("%s is null", path)
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+}
+
+// extractLineField returns a line of output, or a field of that line.
+// A zero line selects the first line.
+func extractLineField(output string, line, field int, sep string) (string, error) {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	if line == 0 {
+		line = 1
+	}
+	i, ok := selectIndex(line, len(lines))
+	if !ok {
+		return "", fmt.Errorf("line %d out of range (%d lines)", line, len(lines))
+	}
+	text := strings.TrimRight(lines[i], "\r")
+	if field == 0 {
+		return strings.TrimSpace(text), nil
+	}
+
+	var fields []string
+	if sep == "" {
+		fields = strings.Fields(text)
+	} else {
+		fields = strings.Split(text, sep)
+	}
+	j, ok := selectIndex(field, len(fields))
+	if !ok {
+		return "", fmt.Errorf("field %d out of range (%d fields on line %d)", field, len(fields), i+1)
+	}
+	return strings.TrimSpace(fields[j]), nil
+}
+
+// selectIndex converts a 1-based position, negative from the end, to an
+// index into n items.
+func selectIndex(pos, n int) (int, bool) {
+	if pos < 0 {
+		pos += n + 1
+	}
+	return pos - 1, pos >= 1 && pos <= n
+}
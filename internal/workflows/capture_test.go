@@ -0,0 +1,91 @@
+package workflows
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractor_Extract(t *testing.T) {
+	podsJSON := `{"items": [{"metadata": {"name": "api-7f9c", "labels": {"app.kubernetes.io/name": "api"}}, "spec": {"replicas": 3}}]}`
+	table := "NAME       READY   STATUS\napi-7f9c   1/1     Running\ndb-0       1/1     Running\n"
+
+	tests := []struct {
+		name      string
+		extractor Extractor
+		output    string
+		want      string
+		wantErr   string
+	}{
+		{name: "jsonpath string", extractor: Extractor{JSONPath: ".items[0].metadata.name"}, output: podsJSON, want: "api-7f9c"},
+		{name: "jsonpath root $", extractor: Extractor{JSONPath: "$.items[-1].spec.replicas"}, output: podsJSON, want: "3"},
+		{name: "jsonpath quoted key", extractor: Extractor{JSONPath: `.items[0].metadata.labels["app.kubernetes.io/name"]`}, output: podsJSON, want: "api"},
+		{name: "jsonpath object", extractor: Extractor{JSONPath: ".items[0].spec"}, output: podsJSON, want: `{"replicas":3}`},
+		{name: "jsonpath missing key", extractor: Extractor{JSONPath: ".items[0].status"}, output: podsJSON, wantErr: `no key "status"`},
+		{name: "jsonpath out of range", extractor: Extractor{JSONPath: ".items[2]"}, output: podsJSON, wantErr: "out of range"},
+		{name: "jsonpath not JSON", extractor: Extractor{JSONPath: ".a"}, output: table, wantErr: "not JSON"},
+		{name: "regex group", extractor: Extractor{Regex: `(db-\d+)\s+1/1`}, output: table, want: "db-0"},
+		{name: "regex whole match", extractor: Extractor{Regex: `api-[0-9a-f]+`}, output: table, want: "api-7f9c"},
+		{name: "regex no match", extractor: Extractor{Regex: `cache-\d+`}, output: table, wantErr: "no match"},
+		{name: "line", extractor: Extractor{Line: 2}, output: table, want: "api-7f9c   1/1     Running"},
+		{name: "line and field", extractor: Extractor{Line: -1, Field: 1}, output: table, want: "db-0"},
+		{name: "field of first line", extractor: Extractor{Field: -1}, output: table, want: "STATUS"},
+		{name: "separator", extractor: Extractor{Field: 2, Separator: ":"}, output: "user:1000:1000\n", want: "1000"},
+		{name: "line out of range", extractor: Extractor{Line: 5}, output: table, wantErr: "line 5 out of range"},
+		{name: "field out of range", extractor: Extractor{Line: 2, Field: 4}, output: table, wantErr: "field 4 out of range"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.extractor.Extract(tt.output)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExtractor_Validate(t *testing.T) {
+	assert.NoError(t, Extractor{JSONPath: ".a[0]"}.Validate())
+	assert.NoError(t, Extractor{Line: 1, Field: 2, Separator: ","}.Validate())
+
+	assert.ErrorContains(t, Extractor{}.Validate(), "set one of")
+	assert.ErrorContains(t, Extractor{JSONPath: ".a", Regex: "a"}.Validate(), "only one")
+	assert.ErrorContains(t, Extractor{Line: 1, Separator: ","}.Validate(), "separator requires field")
+	assert.ErrorContains(t, Extractor{Regex: "("}.Validate(), "invalid regex")
+	assert.ErrorContains(t, Extractor{JSONPath: "items"}.Validate(), "expected . or [")
+	assert.ErrorContains(t, Extractor{JSONPath: ".a[x]"}.Validate(), "bad index")
+}
+
+func TestStep_Captures(t *testing.T) {
+	step := Step{
+		Command: "kubectl get pods -o json",
+		Capture: map[string]Extractor{
+			"pod_name": {JSONPath: ".items[0].metadata.name"},
+			"replicas": {JSONPath: ".items[0].spec.replicas"},
+		},
+	}
+	require.NoError(t, step.Validate())
+
+	values, err := step.Captures(`{"items": [{"metadata": {"name": "api-7f9c"}, "spec": {"replicas": 3}}]}`)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"pod_name": "api-7f9c", "replicas": "3"}, values)
+
+	_, err = step.Captures(`{"items": []}`)
+	assert.ErrorContains(t, err, "capture pod_name")
+
+	step.Capture["bad name"] = Extractor{Line: 1}
+	assert.ErrorContains(t, step.Validate(), "invalid capture name")
+}
+
+func TestWorkflow_CaptureStep(t *testing.T) {
+	wf := &Workflow{Steps: []Step{
+		{Command: "true"},
+		{Command: "kubectl get pods", Capture: map[string]Extractor{"pod": {Line: 2, Field: 1}}},
+	}}
+	assert.Equal(t, 1, wf.CaptureStep("pod"))
+	assert.Equal(t, -1, wf.CaptureStep("node"))
+}
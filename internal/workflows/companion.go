@@ -0,0 +1,135 @@
+package workflows
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// validateCompanion checks that a companion file path stays inside the
+// workflow's directory.
+func validateCompanion(p string) error {
+	clean := filepath.Clean(filepath.FromSlash(p))
+	if filepath.IsAbs(clean) || clean == "." || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("invalid companion file %q: use a path inside the workflow directory, e.g. ./restart.sh", p)
+	}
+	return nil
+}
+
+// validateCompanions checks a step's script and files.
+func validateCompanions(s *Step) error {
+	if s.Script != "" {
+		if s.Command != "" {
+			return fmt.Errorf("use either command or script, not both")
+		}
+		if err := validateCompanion(s.Script); err != nil {
+			return err
+		}
+	}
+
+	names := make([]string, 0, len(s.Files))
+	for name := range s.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !captureNameRegex.MatchString(name) {
+			return fmt.Errorf("invalid file name %q: use letters, digits, '_' and '-'", name)
+		}
+		if err := validateCompanion(s.Files[name]); err != nil {
+			return fmt.Errorf("file %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// CompanionFiles returns the files the workflow's steps reference as
+// scripts or files, relative to the workflow file's directory, sorted and
+// without duplicates.
+func (w *Workflow) CompanionFiles() []string {
+	seen := make(map[string]bool)
+	var files []string
+	add := func(p string) {
+		clean := filepath.ToSlash(filepath.Clean(filepath.FromSlash(p)))
+		if !seen[clean] {
+			seen[clean] = true
+			files = append(files, clean)
+		}
+	}
+	for _, step := range w.Steps {
+		if step.Script != "" {
+			add(step.Script)
+		}
+		for _, p := range step.Files {
+			add(p)
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// ResolveCompanions points the workflow's steps at their companion files
+// in dir, the workflow file's directory: a script step runs its script in
+// the step's shell, and a step's <name> placeholders for its files become
+// the files' paths. It is applied before running, after which the steps
+// no longer depend on the working directory.
+func (w *Workflow) ResolveCompanions(dir string) {
+	for i := range w.Steps {
+		step := &w.Steps[i]
+		if step.Script != "" {
+			step.Command = ". " + quoteCompanion(filepath.Join(dir, filepath.FromSlash(step.Script)))
+		}
+		for name, p := range step.Files {
+			step.Command = strings.ReplaceAll(step.Command, "<"+name+">", quoteCompanion(filepath.Join(dir, filepath.FromSlash(p))))
+		}
+	}
+}
+
+// quoteCompanion single-quotes a path for the shell.
+func quoteCompanion(p string) string {
+	return "'" + strings.ReplaceAll(p, "'", `'\''`) + "'"
+}
+
+// CopyCompanions copies the workflow's companion files from srcDir to
+// dstDir, keeping their relative paths and file modes. It is used when a
+// workflow file moves, as on import or export.
+func CopyCompanions(wf *Workflow, srcDir, dstDir string) error {
+	if filepath.Clean(srcDir) == filepath.Clean(dstDir) {
+		return nil
+	}
+	for _, p := range wf.CompanionFiles() {
+		if err := copyFile(filepath.Join(srcDir, filepath.FromSlash(p)), filepath.Join(dstDir, filepath.FromSlash(p))); err != nil {
+			return fmt.Errorf("failed to copy companion file %s: %w", p, err)
+		}
+	}
+	return nil
+}
+
+// copyFile copies src to dst, creating dst's directory.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,68 @@
+package workflows
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func companionTestWorkflow() *Workflow {
+	return &Workflow{
+		Title: "Deploy",
+		Steps: []Step{
+			{Name: "restart", Script: "./restart.sh"},
+			{Name: "upgrade", Command: "helm upgrade api -f <values>", Files: map[string]string{"values": "config/values.yaml"}},
+			{Name: "again", Script: "restart.sh"},
+		},
+	}
+}
+
+func TestStep_ValidateCompanions(t *testing.T) {
+	assert.NoError(t, companionTestWorkflow().Validate())
+
+	step := Step{Command: "true", Script: "run.sh"}
+	assert.EqualError(t, step.Validate(), "use either command or script, not both")
+
+	step = Step{Script: "../shared/run.sh"}
+	assert.EqualError(t, step.Validate(), `invalid companion file "../shared/run.sh": use a path inside the workflow directory, e.g. ./restart.sh`)
+
+	step = Step{Command: "cat <f>", Files: map[string]string{"f": "/etc/passwd"}}
+	assert.ErrorContains(t, step.Validate(), "file f: invalid companion file")
+}
+
+func TestWorkflow_CompanionFiles(t *testing.T) {
+	assert.Equal(t, []string{"config/values.yaml", "restart.sh"}, companionTestWorkflow().CompanionFiles())
+}
+
+func TestWorkflow_ResolveCompanions(t *testing.T) {
+	wf := companionTestWorkflow()
+	wf.ResolveCompanions("/repo/workflows/deploy")
+
+	assert.Equal(t, ". '"+filepath.FromSlash("/repo/workflows/deploy/restart.sh")+"'", wf.Steps[0].Command)
+	assert.Equal(t, "helm upgrade api -f '"+filepath.FromSlash("/repo/workflows/deploy/config/values.yaml")+"'", wf.Steps[1].Command)
+}
+
+func TestCopyCompanions(t *testing.T) {
+	src, dst := t.TempDir(), t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(src, "restart.sh"), []byte("systemctl restart api\n"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "config"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "config", "values.yaml"), []byte("replicas: 3\n"), 0644))
+
+	wf := companionTestWorkflow()
+	require.NoError(t, CopyCompanions(wf, src, dst))
+
+	data, err := os.ReadFile(filepath.Join(dst, "config", "values.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "replicas: 3\n", string(data))
+	info, err := os.Stat(filepath.Join(dst, "restart.sh"))
+	require.NoError(t, err)
+	if os.PathSeparator == '/' {
+		assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+	}
+
+	require.NoError(t, os.Remove(filepath.Join(src, "restart.sh")))
+	assert.ErrorContains(t, CopyCompanions(wf, src, t.TempDir()), "failed to copy companion file restart.sh")
+}
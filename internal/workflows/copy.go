@@ -0,0 +1,34 @@
+package workflows
+
+import (
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Copy returns a deep copy of the workflow to start a new one from. The
+// copy gets the given title and loses what belongs to the original: its
+// ID, aliases, owners and review. Tags are kept unless keepTags is false.
+func (w *Workflow) Copy(title string, keepTags bool) (*Workflow, error) {
+	data, err := yaml.Marshal(w)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy workflow: %w", err)
+	}
+	c := &Workflow{}
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("failed to copy workflow: %w", err)
+	}
+
+	c.Title = title
+	c.ID = ""
+	c.Aliases = nil
+	c.Owners = nil
+	c.ReviewedBy = ""
+	c.ReviewedAt = time.Time{}
+	c.ReviewedHash = ""
+	if !keepTags {
+		c.Tags = nil
+	}
+	return c, nil
+}
@@ -0,0 +1,44 @@
+package workflows
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopy(t *testing.T) {
+	wf := &Workflow{
+		SchemaVersion: SchemaVersion,
+		ID:            "wf_01HV3K8Q0000000000000000AA",
+		Title:         "Rotate certificates",
+		Tags:          []string{"tls"},
+		Aliases:       []string{"certs"},
+		Owners:        []string{"platform/alice"},
+		Placeholders:  map[string]Placeholder{"host": {Prompt: "Host"}},
+		Steps:         []Step{{Command: "certbot renew --cert-name <host>", Env: map[string]string{"A": "1"}}},
+	}
+	require.NoError(t, wf.Approve("platform/alice", time.Now()))
+
+	c, err := wf.Copy("Rotate staging certificates", true)
+	require.NoError(t, err)
+	assert.Equal(t, "Rotate staging certificates", c.Title)
+	assert.Empty(t, c.ID)
+	assert.Empty(t, c.Aliases)
+	assert.Empty(t, c.Owners)
+	assert.Equal(t, Unreviewed, c.ReviewStatus())
+	assert.Equal(t, []string{"tls"}, c.Tags)
+	assert.Equal(t, wf.Steps, c.Steps)
+	require.NoError(t, c.Validate())
+
+	// The copy shares nothing with the original
+	c.Steps[0].Env["A"] = "2"
+	c.Placeholders["host"] = Placeholder{Prompt: "Staging host"}
+	assert.Equal(t, "1", wf.Steps[0].Env["A"])
+	assert.Equal(t, "Host", wf.Placeholders["host"].Prompt)
+
+	c, err = wf.Copy("Untagged", false)
+	require.NoError(t, err)
+	assert.Empty(t, c.Tags)
+}
@@ -0,0 +1,155 @@
+package workflows
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is a workflow file format.
+type Format string
+
+const (
+	// FormatYAML is the default workflow format.
+	FormatYAML Format = "yaml"
+	// FormatTOML stores workflows as TOML.
+	FormatTOML Format = "toml"
+	// FormatJSON stores workflows as JSON.
+	FormatJSON Format = "json"
+)
+
+// FileNames are the workflow file names the store and index recognize, in
+// order of preference when a directory holds more than one.
+var FileNames = []string{"workflow.yaml", "workflow.yml", "workflow.toml", "workflow.json"}
+
+// IsWorkflowFile reports whether name is a workflow file name.
+func IsWorkflowFile(name string) bool {
+	for _, n := range FileNames {
+		if name == n {
+			return true
+		}
+	}
+	return false
+}
+
+// FindFile returns the path of the workflow file in dir, or "" if there is
+// none.
+func FindFile(dir string) string {
+	for _, name := range FileNames {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// FileName returns the workflow file name for a format.
+func FileName(format Format) string {
+	switch format {
+	case FormatTOML:
+		return "workflow.toml"
+	case FormatJSON:
+		return "workflow.json"
+	default:
+		return "workflow.yaml"
+	}
+}
+
+// ParseFormat parses a format name, accepting "yml" for YAML. An empty
+// name is YAML.
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "", "yaml", "yml":
+		return FormatYAML, nil
+	case "toml":
+		return FormatTOML, nil
+	case "json":
+		return FormatJSON, nil
+	}
+	return "", fmt.Errorf("unknown workflow format %q (must be yaml, toml or json)", name)
+}
+
+// FormatForPath returns the format of a workflow file from its extension.
+// Unknown extensions are read as YAML.
+func FormatForPath(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		return FormatTOML
+	case ".json":
+		return FormatJSON
+	default:
+		return FormatYAML
+	}
+}
+
+// UnmarshalWorkflowAs unmarshals and validates a workflow in the given
+// format.
+//
+// TOML and JSON documents are decoded generically and re-encoded as YAML,
+// so every format shares the YAML field names and custom unmarshalers.
+func UnmarshalWorkflowAs(data []byte, format Format) (*Workflow, error) {
+	if format == FormatYAML || format == "" {
+		return UnmarshalWorkflow(data)
+	}
+
+	var doc map[string]interface{}
+	switch format {
+	case FormatTOML:
+		if err := toml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal workflow: %w", err)
+		}
+	case FormatJSON:
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal workflow: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown workflow format %q", format)
+	}
+
+	yamlData, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal workflow: %w", err)
+	}
+	return UnmarshalWorkflow(yamlData)
+}
+
+// MarshalWorkflowAs marshals a workflow in the given format. Keys of TOML
+// and JSON output are sorted.
+func MarshalWorkflowAs(wf *Workflow, format Format) ([]byte, error) {
+	data, err := MarshalWorkflow(wf)
+	if err != nil || format == FormatYAML || format == "" {
+		return data, err
+	}
+
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to marshal workflow: %w", err)
+	}
+
+	switch format {
+	case FormatTOML:
+		var buf bytes.Buffer
+		if err := toml.NewEncoder(&buf).Encode(doc); err != nil {
+			return nil, fmt.Errorf("failed to marshal workflow: %w", err)
+		}
+		return buf.Bytes(), nil
+	case FormatJSON:
+		// Keep <placeholder> markers readable instead of \u003c escapes
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(doc); err != nil {
+			return nil, fmt.Errorf("failed to marshal workflow: %w", err)
+		}
+		return buf.Bytes(), nil
+	}
+	return nil, fmt.Errorf("unknown workflow format %q", format)
+}
@@ -0,0 +1,101 @@
+package workflows
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func formatTestWorkflow() *Workflow {
+	confirm := true
+	return &Workflow{
+		SchemaVersion: SchemaVersion,
+		ID:            "wf_01HV3K8Q",
+		Title:         "Deploy",
+		Tags:          []string{"deploy", "prod"},
+		Defaults:      Defaults{Shell: "bash", ConfirmEachStep: &confirm},
+		Placeholders: map[string]Placeholder{
+			"env":   {Prompt: "Environment", Default: "staging", Validate: "^(staging|prod)$"},
+			"token": {Secret: true},
+		},
+		Steps: []Step{
+			{
+				Name:         "create",
+				Command:      "deployctl create --env <env>",
+				Env:          map[string]string{"TOKEN": "<token>"},
+				Confirmation: &StepConfirmation{Prompt: "Create it?"},
+				Capture:      map[string]Extractor{"id": {JSONPath: ".id"}},
+			},
+			{Name: "scale", Command: "deployctl scale <id>", ContinueOnError: true, Confirmation: &StepConfirmation{}},
+		},
+		Presets:    map[string]map[string]string{"prod": {"env": "prod"}},
+		ReviewedBy: "platform/alice",
+		ReviewedAt: time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC),
+	}
+}
+
+func TestMarshalWorkflowAs_RoundTrip(t *testing.T) {
+	for _, format := range []Format{FormatYAML, FormatTOML, FormatJSON} {
+		t.Run(string(format), func(t *testing.T) {
+			wf := formatTestWorkflow()
+			data, err := MarshalWorkflowAs(wf, format)
+			require.NoError(t, err)
+
+			got, err := UnmarshalWorkflowAs(data, format)
+			require.NoError(t, err)
+			assert.Equal(t, wf, got)
+		})
+	}
+}
+
+func TestUnmarshalWorkflowAs(t *testing.T) {
+	tomlData := `schema_version = 1
+title = "Restart"
+
+[[steps]]
+name = "restart"
+command = "systemctl restart <service>"
+confirmation = "Really restart?"
+`
+	wf, err := UnmarshalWorkflowAs([]byte(tomlData), FormatTOML)
+	require.NoError(t, err)
+	assert.Equal(t, "Restart", wf.Title)
+	assert.Equal(t, "Really restart?", wf.Steps[0].Confirmation.Prompt)
+
+	jsonData := `{"title": "Restart", "steps": [{"command": "systemctl restart <service>", "confirmation": true}]}`
+	wf, err = UnmarshalWorkflowAs([]byte(jsonData), FormatJSON)
+	require.NoError(t, err)
+	assert.NotNil(t, wf.Steps[0].Confirmation)
+
+	_, err = UnmarshalWorkflowAs([]byte(`{"title": "No steps"}`), FormatJSON)
+	assert.ErrorContains(t, err, "workflow must have at least one step")
+
+	_, err = UnmarshalWorkflowAs([]byte(`title = `), FormatTOML)
+	assert.ErrorContains(t, err, "failed to unmarshal workflow")
+}
+
+func TestFormatForPath(t *testing.T) {
+	assert.Equal(t, FormatYAML, FormatForPath("a/workflow.yaml"))
+	assert.Equal(t, FormatYAML, FormatForPath("a/workflow.yml"))
+	assert.Equal(t, FormatTOML, FormatForPath("a/workflow.toml"))
+	assert.Equal(t, FormatJSON, FormatForPath("a/WORKFLOW.JSON"))
+	assert.Equal(t, FormatYAML, FormatForPath("-"))
+
+	assert.True(t, IsWorkflowFile("workflow.toml"))
+	assert.False(t, IsWorkflowFile("tests.yaml"))
+	assert.Equal(t, "workflow.json", FileName(FormatJSON))
+
+	format, err := ParseFormat("yml")
+	require.NoError(t, err)
+	assert.Equal(t, FormatYAML, format)
+	_, err = ParseFormat("xml")
+	assert.Error(t, err)
+}
+
+func TestMarshalWorkflowAs_JSONKeepsPlaceholders(t *testing.T) {
+	data, err := MarshalWorkflowAs(formatTestWorkflow(), FormatJSON)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"command": "deployctl create --env <env>"`)
+}
@@ -0,0 +1,73 @@
+package workflows
+
+import (
+	"crypto/rand"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// IDPrefix is the prefix of generated workflow IDs.
+const IDPrefix = "wf_"
+
+// crockford is the Crockford base32 alphabet used by ULIDs.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// idRegex matches generated workflow IDs.
+var idRegex = regexp.MustCompile(`^wf_[0-9A-HJKMNP-TV-Z]{26}$`)
+
+// NewID returns a new workflow ID: "wf_" followed by a ULID. IDs sort by
+// creation time and stay stable when a workflow is renamed or moved.
+func NewID() string {
+	return newIDAt(time.Now())
+}
+
+// newIDAt returns a new workflow ID with the given timestamp.
+func newIDAt(t time.Time) string {
+	var entropy [10]byte
+	if _, err := rand.Read(entropy[:]); err != nil {
+		// crypto/rand never fails on supported platforms
+		panic(err)
+	}
+
+	// 48-bit millisecond timestamp followed by 80 bits of randomness,
+	// encoded as 26 base32 characters
+	ms := uint64(t.UnixMilli())
+	var b strings.Builder
+	b.WriteString(IDPrefix)
+	for i := 9; i >= 0; i-- {
+		b.WriteByte(crockford[(ms>>(uint(i)*5))&0x1f])
+	}
+
+	// Encode the 80 random bits, 5 bits at a time
+	var acc uint64
+	bits := 0
+	for _, v := range entropy {
+		acc = acc<<8 | uint64(v)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			b.WriteByte(crockford[(acc>>uint(bits))&0x1f])
+		}
+	}
+
+	return b.String()
+}
+
+// IsGeneratedID reports whether s has the form of an ID made by NewID.
+func IsGeneratedID(s string) bool {
+	return idRegex.MatchString(s)
+}
+
+// IDTime returns the creation time encoded in an ID made by NewID, and
+// false for any other ID.
+func IDTime(id string) (time.Time, bool) {
+	if !IsGeneratedID(id) {
+		return time.Time{}, false
+	}
+	var ms uint64
+	for _, c := range id[len(IDPrefix) : len(IDPrefix)+10] {
+		ms = ms<<5 | uint64(strings.IndexRune(crockford, c))
+	}
+	return time.UnixMilli(int64(ms)), true
+}
@@ -0,0 +1,53 @@
+package workflows
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewID(t *testing.T) {
+	id := NewID()
+	assert.True(t, IsGeneratedID(id), id)
+	assert.Len(t, id, len(IDPrefix)+26)
+
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		id := NewID()
+		assert.False(t, seen[id], "duplicate ID %s", id)
+		seen[id] = true
+	}
+}
+
+func TestNewID_SortsByTime(t *testing.T) {
+	base := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	ids := []string{
+		newIDAt(base.Add(2 * time.Hour)),
+		newIDAt(base),
+		newIDAt(base.Add(time.Millisecond)),
+	}
+	sorted := append([]string(nil), ids...)
+	sort.Strings(sorted)
+
+	assert.Equal(t, []string{ids[1], ids[2], ids[0]}, sorted)
+}
+
+func TestIsGeneratedID(t *testing.T) {
+	assert.False(t, IsGeneratedID(""))
+	assert.False(t, IsGeneratedID("wf_123"))
+	assert.False(t, IsGeneratedID("deploy-api"))
+	// I, L, O and U are not in the Crockford alphabet
+	assert.False(t, IsGeneratedID("wf_01HZZZZZZZZZZZZZZZZZZZZZZI"))
+}
+
+func TestIDTime(t *testing.T) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 6e6, time.UTC)
+	got, ok := IDTime(newIDAt(at))
+	assert.True(t, ok)
+	assert.True(t, got.Equal(at), "IDTime() = %v, want %v", got, at)
+
+	_, ok = IDTime("deploy-api")
+	assert.False(t, ok)
+}
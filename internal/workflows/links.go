@@ -0,0 +1,66 @@
+package workflows
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Link points a step at a dashboard, document or other page to check
+// while running it. In YAML a link is either a bare URL or a mapping with
+// a title:
+//
+//	links:
+//	  - https://grafana.example.com/d/db
+//	  - title: Failover runbook
+//	    url: https://wiki.example.com/db-failover
+type Link struct {
+	Title string `yaml:"title,omitempty"`
+	URL   string `yaml:"url"`
+}
+
+// Label returns the link's title, or its URL when it has none.
+func (l Link) Label() string {
+	if l.Title != "" {
+		return l.Title
+	}
+	return l.URL
+}
+
+// MarshalYAML writes links without a title as a bare URL.
+func (l Link) MarshalYAML() (interface{}, error) {
+	if l.Title == "" {
+		return l.URL, nil
+	}
+	type plain Link
+	return plain(l), nil
+}
+
+// UnmarshalYAML reads a link from a bare URL or a mapping.
+func (l *Link) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*l = Link{URL: value.Value}
+		return nil
+	}
+	type plain Link
+	var p plain
+	if err := value.Decode(&p); err != nil {
+		return err
+	}
+	*l = Link(p)
+	return nil
+}
+
+// validateLinks checks that each link has a web URL. Other schemes are
+// refused since opening a link hands it to the desktop, and file: or
+// custom-scheme URLs in a shared runbook could do more than show a page.
+func validateLinks(links []Link) error {
+	for _, l := range links {
+		lower := strings.ToLower(l.URL)
+		if !strings.HasPrefix(lower, "https://") && !strings.HasPrefix(lower, "http://") {
+			return fmt.Errorf("invalid link %q: use an http or https URL", l.URL)
+		}
+	}
+	return nil
+}
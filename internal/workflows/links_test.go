@@ -0,0 +1,42 @@
+package workflows
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestLink_YAML(t *testing.T) {
+	data := []byte(`command: make failover
+links:
+  - https://grafana.example.com/d/db
+  - title: Failover runbook
+    url: https://wiki.example.com/db-failover
+`)
+	var step Step
+	require.NoError(t, yaml.Unmarshal(data, &step))
+	assert.Equal(t, []Link{
+		{URL: "https://grafana.example.com/d/db"},
+		{Title: "Failover runbook", URL: "https://wiki.example.com/db-failover"},
+	}, step.Links)
+	assert.Equal(t, "https://grafana.example.com/d/db", step.Links[0].Label())
+	assert.Equal(t, "Failover runbook", step.Links[1].Label())
+
+	out, err := yaml.Marshal(&step)
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "- https://grafana.example.com/d/db\n")
+	assert.Contains(t, string(out), "title: Failover runbook")
+}
+
+func TestStep_ValidateLinks(t *testing.T) {
+	step := Step{Command: "true", Links: []Link{{URL: "https://grafana.example.com/d/<host>"}}}
+	assert.NoError(t, step.Validate())
+
+	step.Links = []Link{{Title: "Notes", URL: "file:///etc/passwd"}}
+	assert.EqualError(t, step.Validate(), `invalid link "file:///etc/passwd": use an http or https URL`)
+
+	step.Links = []Link{{Title: "Notes"}}
+	assert.EqualError(t, step.Validate(), `invalid link "": use an http or https URL`)
+}
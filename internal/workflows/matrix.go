@@ -0,0 +1,72 @@
+package workflows
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// validateMatrix checks that matrix keys are placeholder names and that
+// each key has at least one value.
+func validateMatrix(matrix map[string][]string) error {
+	for _, key := range sortedMatrixKeys(matrix) {
+		if !captureNameRegex.MatchString(key) {
+			return fmt.Errorf("invalid matrix key %q: use letters, digits, '_' and '-'", key)
+		}
+		if len(matrix[key]) == 0 {
+			return fmt.Errorf("matrix key %s has no values", key)
+		}
+	}
+	return nil
+}
+
+// MatrixCombinations returns every combination of the matrix values, with
+// keys varying slowest in sorted order. An empty matrix has no
+// combinations.
+func MatrixCombinations(matrix map[string][]string) []map[string]string {
+	keys := sortedMatrixKeys(matrix)
+	if len(keys) == 0 {
+		return nil
+	}
+
+	combos := []map[string]string{{}}
+	for _, key := range keys {
+		var next []map[string]string
+		for _, combo := range combos {
+			for _, value := range matrix[key] {
+				c := make(map[string]string, len(combo)+1)
+				for k, v := range combo {
+					c[k] = v
+				}
+				c[key] = value
+				next = append(next, c)
+			}
+		}
+		combos = next
+	}
+	return combos
+}
+
+// MatrixLabel describes a combination, e.g. "env=prod region=eu-west-1".
+func MatrixLabel(combo map[string]string) string {
+	keys := make([]string, 0, len(combo))
+	for key := range combo {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = key + "=" + combo[key]
+	}
+	return strings.Join(parts, " ")
+}
+
+func sortedMatrixKeys(matrix map[string][]string) []string {
+	keys := make([]string, 0, len(matrix))
+	for key := range matrix {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
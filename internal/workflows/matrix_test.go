@@ -0,0 +1,26 @@
+package workflows
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatrixCombinations(t *testing.T) {
+	assert.Nil(t, MatrixCombinations(nil))
+
+	combos := MatrixCombinations(map[string][]string{
+		"region": {"us-east-1", "eu-west-1"},
+		"env":    {"staging", "prod"},
+	})
+	var labels []string
+	for _, c := range combos {
+		labels = append(labels, MatrixLabel(c))
+	}
+	assert.Equal(t, []string{
+		"env=staging region=us-east-1",
+		"env=staging region=eu-west-1",
+		"env=prod region=us-east-1",
+		"env=prod region=eu-west-1",
+	}, labels)
+}
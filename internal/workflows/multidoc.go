@@ -0,0 +1,96 @@
+package workflows
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UnmarshalWorkflows unmarshals every workflow in a file. YAML files may
+// hold several workflows as separate documents ("---"); other formats hold
+// one. Each workflow is validated, and errors name the 1-based document.
+func UnmarshalWorkflows(data []byte, format Format) ([]*Workflow, error) {
+	if format != FormatYAML && format != "" {
+		wf, err := UnmarshalWorkflowAs(data, format)
+		if err != nil {
+			return nil, err
+		}
+		return []*Workflow{wf}, nil
+	}
+
+	var docs []*Workflow
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for n := 1; ; n++ {
+		var node yaml.Node
+		if err := dec.Decode(&node); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("failed to unmarshal workflow: document %d: %w", n, err)
+		}
+		if isEmptyDocument(&node) {
+			n--
+			continue
+		}
+
+		var wf Workflow
+		if err := node.Decode(&wf); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal workflow: document %d: %w", n, err)
+		}
+		if err := wf.Validate(); err != nil {
+			return nil, fmt.Errorf("workflow validation failed: document %d: %w", n, err)
+		}
+		docs = append(docs, &wf)
+	}
+
+	if len(docs) == 0 {
+		// Report the same error as a single empty workflow
+		return nil, fmt.Errorf("workflow validation failed: %w", (&Workflow{}).Validate())
+	}
+	return docs, nil
+}
+
+// isEmptyDocument reports whether a YAML document has no content, as with
+// a trailing "---".
+func isEmptyDocument(node *yaml.Node) bool {
+	return node.Kind == yaml.DocumentNode && (len(node.Content) == 0 ||
+		(node.Content[0].Kind == yaml.ScalarNode && node.Content[0].Tag == "!!null"))
+}
+
+// MarshalWorkflows marshals workflows into one file. Several workflows
+// are only possible in YAML, as documents separated by "---".
+func MarshalWorkflows(wfs []*Workflow, format Format) ([]byte, error) {
+	if len(wfs) == 1 {
+		return MarshalWorkflowAs(wfs[0], format)
+	}
+	if format != FormatYAML && format != "" {
+		return nil, fmt.Errorf("%s workflow files hold a single workflow", format)
+	}
+
+	var buf bytes.Buffer
+	for i, wf := range wfs {
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		data, err := MarshalWorkflow(wf)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(data)
+	}
+	return buf.Bytes(), nil
+}
+
+// LoadAll reads every workflow in a file, in the format given by its
+// extension.
+func LoadAll(path string) ([]*Workflow, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return UnmarshalWorkflows(data, FormatForPath(path))
+}
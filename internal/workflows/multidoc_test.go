@@ -0,0 +1,53 @@
+package workflows
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const multiDocYAML = `schema_version: 1
+title: Restart API
+steps:
+  - command: systemctl restart api
+---
+schema_version: 1
+title: Restart Worker
+steps:
+  - command: systemctl restart worker
+---
+`
+
+func TestUnmarshalWorkflows(t *testing.T) {
+	wfs, err := UnmarshalWorkflows([]byte(multiDocYAML), FormatYAML)
+	require.NoError(t, err)
+	require.Len(t, wfs, 2)
+	assert.Equal(t, "Restart API", wfs[0].Title)
+	assert.Equal(t, "Restart Worker", wfs[1].Title)
+
+	// A single document is a single workflow
+	wfs, err = UnmarshalWorkflows([]byte("title: One\nsteps:\n  - command: true\n"), FormatYAML)
+	require.NoError(t, err)
+	assert.Len(t, wfs, 1)
+
+	_, err = UnmarshalWorkflows([]byte(multiDocYAML+"title: Broken\n"), FormatYAML)
+	assert.EqualError(t, err, "workflow validation failed: document 3: workflow must have at least one step")
+
+	_, err = UnmarshalWorkflows([]byte("---\n"), FormatYAML)
+	assert.EqualError(t, err, "workflow validation failed: workflow title is required")
+}
+
+func TestMarshalWorkflows_RoundTrip(t *testing.T) {
+	wfs, err := UnmarshalWorkflows([]byte(multiDocYAML), FormatYAML)
+	require.NoError(t, err)
+
+	data, err := MarshalWorkflows(wfs, FormatYAML)
+	require.NoError(t, err)
+	got, err := UnmarshalWorkflows(data, FormatYAML)
+	require.NoError(t, err)
+	assert.Equal(t, wfs, got)
+
+	_, err = MarshalWorkflows(wfs, FormatJSON)
+	assert.EqualError(t, err, "json workflow files hold a single workflow")
+}
@@ -1,10 +1,25 @@
 package workflows
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	"io"
 	"os"
+
+	"gopkg.in/yaml.v3"
 )
 
+// Load reads and unmarshals a workflow file in the format given by its
+// extension: workflow.yaml, workflow.toml or workflow.json.
+func Load(path string) (*Workflow, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return UnmarshalWorkflowAs(data, FormatForPath(path))
+}
+
 // LoadYAML reads and unmarshals a workflow from a YAML file.
 //
 // LoadYAML combines file reading with validation - it returns an error
@@ -42,3 +57,67 @@ func LoadYAMLReader(r io.Reader) (*Workflow, error) {
 	}
 	return UnmarshalWorkflow(data)
 }
+
+// StepLine returns the 1-based line in YAML data where the step at index
+// (0-based) starts, for opening an editor at that step.
+func StepLine(data []byte, index int) (int, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return 0, err
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return 0, errors.New("workflow YAML is not a mapping")
+	}
+
+	root := doc.Content[0]
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != "steps" {
+			continue
+		}
+		steps := root.Content[i+1]
+		if steps.Kind != yaml.SequenceNode {
+			return 0, errors.New("workflow steps are not a list")
+		}
+		if index < 0 || index >= len(steps.Content) {
+			return 0, fmt.Errorf("step %d out of range (workflow has %d steps)", index+1, len(steps.Content))
+		}
+		return steps.Content[index].Line, nil
+	}
+	return 0, errors.New("workflow has no steps")
+}
+
+// StepLines returns the 1-based line in YAML data where each step starts,
+// for the workflow in document doc (1-based) of a multi-document file, or
+// in the first document if doc is 0.
+func StepLines(data []byte, doc int) ([]int, error) {
+	if doc < 1 {
+		doc = 1
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	var node yaml.Node
+	for i := 0; i < doc; i++ {
+		node = yaml.Node{}
+		if err := dec.Decode(&node); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, fmt.Errorf("document %d not found", doc)
+			}
+			return nil, err
+		}
+	}
+	if len(node.Content) == 0 || node.Content[0].Kind != yaml.MappingNode {
+		return nil, errors.New("workflow YAML is not a mapping")
+	}
+
+	root := node.Content[0]
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != "steps" {
+			continue
+		}
+		lines := make([]int, 0, len(root.Content[i+1].Content))
+		for _, step := range root.Content[i+1].Content {
+			lines = append(lines, step.Line)
+		}
+		return lines, nil
+	}
+	return nil, errors.New("workflow has no steps")
+}
@@ -0,0 +1,62 @@
+package workflows
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStepLine(t *testing.T) {
+	data := []byte(`schema_version: 1
+title: Deploy
+steps:
+  - name: build
+    command: make build
+  - name: deploy
+    command: |
+      kubectl apply -f k8s/
+  - command: echo done
+`)
+
+	for index, want := range []int{4, 6, 9} {
+		line, err := StepLine(data, index)
+		require.NoError(t, err)
+		assert.Equal(t, want, line, "step %d", index+1)
+	}
+
+	_, err := StepLine(data, 3)
+	assert.EqualError(t, err, "step 4 out of range (workflow has 3 steps)")
+
+	_, err = StepLine([]byte("title: Empty\n"), 0)
+	assert.EqualError(t, err, "workflow has no steps")
+}
+
+func TestStepLines(t *testing.T) {
+	lines, err := StepLines([]byte(multiDocYAML), 2)
+	require.NoError(t, err)
+	assert.Equal(t, []int{9}, lines)
+
+	lines, err = StepLines([]byte(multiDocYAML), 0)
+	require.NoError(t, err)
+	assert.Equal(t, []int{4}, lines)
+
+	_, err = StepLines([]byte(multiDocYAML), 4)
+	assert.EqualError(t, err, "document 4 not found")
+}
+
+func TestStepLine_MarshaledWorkflow(t *testing.T) {
+	wf := &Workflow{
+		SchemaVersion: SchemaVersion,
+		Title:         "T",
+		Steps:         []Step{{Name: "a", Command: "true"}, {Name: "b", Command: "false"}},
+	}
+	data, err := MarshalWorkflow(wf)
+	require.NoError(t, err)
+
+	line, err := StepLine(data, 1)
+	require.NoError(t, err)
+	lines := strings.Split(string(data), "\n")
+	assert.Contains(t, lines[line-1], "name: b")
+}
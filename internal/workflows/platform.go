@@ -0,0 +1,59 @@
+package workflows
+
+import (
+	"fmt"
+	"strings"
+)
+
+// knownOS and knownArch are the GOOS and GOARCH values accepted in a
+// step's platforms, so that typos such as "macos" fail validation instead
+// of silently skipping the step everywhere.
+var (
+	knownOS = map[string]bool{
+		"aix": true, "android": true, "darwin": true, "dragonfly": true,
+		"freebsd": true, "illumos": true, "ios": true, "js": true,
+		"linux": true, "netbsd": true, "openbsd": true, "plan9": true,
+		"solaris": true, "wasip1": true, "windows": true,
+	}
+	knownArch = map[string]bool{
+		"386": true, "amd64": true, "arm": true, "arm64": true,
+		"loong64": true, "mips": true, "mipsle": true, "mips64": true,
+		"mips64le": true, "ppc64": true, "ppc64le": true, "riscv64": true,
+		"s390x": true, "wasm": true,
+	}
+)
+
+// RunsOn reports whether the step runs on the given GOOS and GOARCH. A
+// step without platforms runs everywhere; otherwise one entry must match,
+// where an entry is an OS ("linux"), an architecture ("arm64") or both
+// ("darwin/arm64").
+func (s *Step) RunsOn(goos, goarch string) bool {
+	if len(s.Platforms) == 0 {
+		return true
+	}
+	for _, p := range s.Platforms {
+		opsys, arch, hasArch := strings.Cut(p, "/")
+		switch {
+		case hasArch && opsys == goos && arch == goarch:
+			return true
+		case !hasArch && (p == goos || p == goarch):
+			return true
+		}
+	}
+	return false
+}
+
+// validatePlatforms checks that each of a step's platforms names a known
+// OS, architecture or OS/architecture pair.
+func validatePlatforms(platforms []string) error {
+	for _, p := range platforms {
+		opsys, arch, hasArch := strings.Cut(p, "/")
+		switch {
+		case hasArch && (!knownOS[opsys] || !knownArch[arch]):
+			return fmt.Errorf("invalid platform %q: use GOOS/GOARCH, e.g. linux/amd64", p)
+		case !hasArch && !knownOS[p] && !knownArch[p]:
+			return fmt.Errorf("invalid platform %q: use a GOOS or GOARCH value, e.g. linux, darwin or arm64", p)
+		}
+	}
+	return nil
+}
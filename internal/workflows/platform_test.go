@@ -0,0 +1,32 @@
+package workflows
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStep_RunsOn(t *testing.T) {
+	step := Step{Command: "brew upgrade", Platforms: []string{"darwin", "linux/arm64"}}
+	assert.True(t, step.RunsOn("darwin", "amd64"))
+	assert.True(t, step.RunsOn("linux", "arm64"))
+	assert.False(t, step.RunsOn("linux", "amd64"))
+	assert.False(t, step.RunsOn("windows", "arm64"))
+
+	step.Platforms = []string{"arm64"}
+	assert.True(t, step.RunsOn("windows", "arm64"))
+
+	step.Platforms = nil
+	assert.True(t, step.RunsOn("plan9", "386"))
+}
+
+func TestStep_ValidatePlatforms(t *testing.T) {
+	step := Step{Command: "true", Platforms: []string{"linux", "darwin/arm64", "amd64"}}
+	assert.NoError(t, step.Validate())
+
+	step.Platforms = []string{"macos"}
+	assert.EqualError(t, step.Validate(), `invalid platform "macos": use a GOOS or GOARCH value, e.g. linux, darwin or arm64`)
+
+	step.Platforms = []string{"linux/x86_64"}
+	assert.EqualError(t, step.Validate(), `invalid platform "linux/x86_64": use GOOS/GOARCH, e.g. linux/amd64`)
+}
@@ -0,0 +1,73 @@
+package workflows
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// validatePresets checks that preset names and their keys are placeholder
+// names, and that values pass the placeholder's validate pattern.
+func validatePresets(presets map[string]map[string]string, phs map[string]Placeholder) error {
+	for _, name := range sortedPresetNames(presets) {
+		if !captureNameRegex.MatchString(name) {
+			return fmt.Errorf("invalid preset name %q: use letters, digits, '_' and '-'", name)
+		}
+		preset := presets[name]
+		keys := make([]string, 0, len(preset))
+		for key := range preset {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if !captureNameRegex.MatchString(key) {
+				return fmt.Errorf("preset %s: invalid placeholder name %q", name, key)
+			}
+			pattern := phs[key].Validate
+			if pattern == "" {
+				continue
+			}
+			// Bad patterns are reported by placeholder validation
+			if ok, err := regexp.MatchString(pattern, preset[key]); err == nil && !ok {
+				return fmt.Errorf("preset %s: value %q for %s does not match pattern %s", name, preset[key], key, pattern)
+			}
+		}
+	}
+	return nil
+}
+
+// PresetNames returns the workflow's preset names in sorted order.
+func (w *Workflow) PresetNames() []string {
+	return sortedPresetNames(w.Presets)
+}
+
+// ApplyPreset returns params with the named preset's values added. Values
+// already in params win, so --param overrides a preset.
+func (w *Workflow) ApplyPreset(name string, params map[string]string) (map[string]string, error) {
+	preset, ok := w.Presets[name]
+	if !ok {
+		if len(w.Presets) == 0 {
+			return nil, fmt.Errorf("workflow %s has no presets", w.Title)
+		}
+		return nil, fmt.Errorf("unknown preset %q (available: %s)", name, strings.Join(w.PresetNames(), ", "))
+	}
+
+	merged := make(map[string]string, len(preset)+len(params))
+	for k, v := range preset {
+		merged[k] = v
+	}
+	for k, v := range params {
+		merged[k] = v
+	}
+	return merged, nil
+}
+
+func sortedPresetNames(presets map[string]map[string]string) []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
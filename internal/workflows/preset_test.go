@@ -0,0 +1,30 @@
+package workflows
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyPreset(t *testing.T) {
+	wf := &Workflow{
+		Title: "Deploy",
+		Presets: map[string]map[string]string{
+			"staging": {"env": "staging", "replicas": "2"},
+			"prod":    {"env": "prod", "replicas": "6"},
+		},
+	}
+
+	assert.Equal(t, []string{"prod", "staging"}, wf.PresetNames())
+
+	params, err := wf.ApplyPreset("staging", map[string]string{"replicas": "3"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"env": "staging", "replicas": "3"}, params)
+
+	_, err = wf.ApplyPreset("qa", nil)
+	assert.EqualError(t, err, `unknown preset "qa" (available: prod, staging)`)
+
+	_, err = (&Workflow{Title: "Plain"}).ApplyPreset("qa", nil)
+	assert.EqualError(t, err, "workflow Plain has no presets")
+}
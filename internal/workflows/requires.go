@@ -0,0 +1,69 @@
+package workflows
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Requirement is a command a workflow needs on the PATH, with optional
+// hints for installing it. A requirement without hints can be written as
+// just the command name:
+//
+//	requires:
+//	  - git
+//	  - command: jq
+//	    brew: jq
+//	    apt: jq
+type Requirement struct {
+	Command string `yaml:"command"`
+	Brew    string `yaml:"brew,omitempty"` // Homebrew package providing the command
+	Apt     string `yaml:"apt,omitempty"`  // apt package providing the command
+}
+
+// HasInstallHint reports whether the requirement says how to install it.
+func (r Requirement) HasInstallHint() bool {
+	return r.Brew != "" || r.Apt != ""
+}
+
+// MarshalYAML writes requirements without hints as the command name.
+func (r Requirement) MarshalYAML() (interface{}, error) {
+	if !r.HasInstallHint() {
+		return r.Command, nil
+	}
+	type plain Requirement
+	return plain(r), nil
+}
+
+// UnmarshalYAML reads a requirement from a command name or a mapping.
+func (r *Requirement) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*r = Requirement{Command: value.Value}
+		return nil
+	}
+	type plain Requirement
+	var p plain
+	if err := value.Decode(&p); err != nil {
+		return err
+	}
+	*r = Requirement(p)
+	return nil
+}
+
+// validateRequires checks that every requirement names a single command
+// and that package names can't smuggle in shell syntax.
+func validateRequires(requires []Requirement) error {
+	for _, req := range requires {
+		if req.Command == "" {
+			return errors.New("requires: command is required")
+		}
+		for _, name := range []string{req.Command, req.Brew, req.Apt} {
+			if strings.ContainsAny(name, " \t\n;&|$`'\"<>()") {
+				return fmt.Errorf("requires: invalid name %q", name)
+			}
+		}
+	}
+	return nil
+}
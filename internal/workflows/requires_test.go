@@ -0,0 +1,48 @@
+package workflows
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequires_YAML(t *testing.T) {
+	data := []byte(`
+title: Report
+requires:
+  - git
+  - command: jq
+    brew: jq
+    apt: jq
+steps:
+  - command: git log --format=%H | jq -R .
+`)
+
+	wf, err := UnmarshalWorkflow(data)
+	require.NoError(t, err)
+	assert.Equal(t, []Requirement{{Command: "git"}, {Command: "jq", Brew: "jq", Apt: "jq"}}, wf.Requires)
+
+	out, err := MarshalWorkflow(wf)
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "requires:\n    - git\n    - command: jq\n      brew: jq\n      apt: jq\n")
+}
+
+func TestRequires_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     Requirement
+		wantErr bool
+	}{
+		{"command only", Requirement{Command: "jq"}, false},
+		{"hints", Requirement{Command: "kubectl", Brew: "kubernetes-cli", Apt: "kubectl"}, false},
+		{"no command", Requirement{Brew: "jq"}, true},
+		{"shell in package", Requirement{Command: "jq", Apt: "jq; rm -rf ~"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf := &Workflow{Title: "T", Steps: []Step{{Command: "true"}}, Requires: []Requirement{tt.req}}
+			assert.Equal(t, tt.wantErr, wf.Validate() != nil)
+		})
+	}
+}
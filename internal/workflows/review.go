@@ -0,0 +1,87 @@
+package workflows
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ReviewState is the review status of a workflow.
+type ReviewState int
+
+const (
+	// Unreviewed means the workflow has never been approved.
+	Unreviewed ReviewState = iota
+	// Reviewed means the workflow is unchanged since its last approval.
+	Reviewed
+	// StaleReview means the workflow changed after its last approval.
+	StaleReview
+)
+
+// String returns a short label for the state.
+func (s ReviewState) String() string {
+	switch s {
+	case Reviewed:
+		return "reviewed"
+	case StaleReview:
+		return "stale review"
+	default:
+		return "unreviewed"
+	}
+}
+
+// ContentHash returns a hash of the workflow without its review metadata
+// or aliases, so approving a workflow or renaming its short links doesn't
+// change it.
+func (w *Workflow) ContentHash() (string, error) {
+	c := *w
+	c.Aliases = nil
+	c.ReviewedBy = ""
+	c.ReviewedAt = time.Time{}
+	c.ReviewedHash = ""
+
+	data, err := yaml.Marshal(&c)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal workflow: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:16], nil
+}
+
+// ReviewStatus reports whether the workflow is unreviewed, reviewed, or
+// changed since it was last reviewed.
+func (w *Workflow) ReviewStatus() ReviewState {
+	if w.ReviewedHash == "" {
+		return Unreviewed
+	}
+	hash, err := w.ContentHash()
+	if err != nil || hash != w.ReviewedHash {
+		return StaleReview
+	}
+	return Reviewed
+}
+
+// Approve records a review of the workflow's current content.
+func (w *Workflow) Approve(by string, at time.Time) error {
+	hash, err := w.ContentHash()
+	if err != nil {
+		return err
+	}
+	w.ReviewedBy = by
+	w.ReviewedAt = at.UTC().Truncate(time.Second)
+	w.ReviewedHash = hash
+	return nil
+}
+
+// IsOwner reports whether identity is listed in the workflow's owners.
+func (w *Workflow) IsOwner(identity string) bool {
+	for _, owner := range w.Owners {
+		if owner == identity {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package workflows
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReviewStatus(t *testing.T) {
+	wf := &Workflow{
+		SchemaVersion: SchemaVersion,
+		Title:         "Rotate certificates",
+		Owners:        []string{"platform/alice"},
+		Steps:         []Step{{Command: "certbot renew"}},
+	}
+	assert.Equal(t, Unreviewed, wf.ReviewStatus())
+
+	at := time.Date(2026, 3, 9, 12, 30, 15, 500, time.UTC)
+	require.NoError(t, wf.Approve("platform/alice", at))
+	assert.Equal(t, Reviewed, wf.ReviewStatus())
+	assert.Equal(t, "platform/alice", wf.ReviewedBy)
+	assert.Equal(t, at.Truncate(time.Second), wf.ReviewedAt)
+
+	// Review metadata survives a round trip and stays valid
+	data, err := MarshalWorkflow(wf)
+	require.NoError(t, err)
+	loaded, err := UnmarshalWorkflow(data)
+	require.NoError(t, err)
+	assert.Equal(t, Reviewed, loaded.ReviewStatus())
+
+	loaded.Steps[0].Command = "certbot renew --force-renewal"
+	assert.Equal(t, StaleReview, loaded.ReviewStatus())
+	assert.Equal(t, "stale review", loaded.ReviewStatus().String())
+}
+
+func TestUnreviewedWorkflowOmitsReviewFields(t *testing.T) {
+	wf := &Workflow{SchemaVersion: SchemaVersion, Title: "T", Steps: []Step{{Command: "true"}}}
+	data, err := MarshalWorkflow(wf)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "reviewed")
+	assert.NotContains(t, string(data), "owners")
+}
+
+func TestIsOwner(t *testing.T) {
+	wf := &Workflow{Owners: []string{"platform/alice", "platform/bob"}}
+	assert.True(t, wf.IsOwner("platform/bob"))
+	assert.False(t, wf.IsOwner("platform"))
+}
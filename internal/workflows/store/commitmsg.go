@@ -0,0 +1,85 @@
+package store
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/chazuruo/svf/internal/diff"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// defaultCommitTemplate is used when git.commit_template is unset.
+const defaultCommitTemplate = "workflow({scope}): {action} {slug}"
+
+// commitMessage builds the message for saving wf in dir, where previous is
+// the workflow as it was before the save (nil for a new workflow). The
+// subject expands template; the body lists the steps that changed.
+func (s *FileSystemStore) commitMessage(wf, previous *workflows.Workflow, dir string) string {
+	template := s.config.Git.CommitTemplate
+	if template == "" {
+		template = defaultCommitTemplate
+	}
+	action := "update"
+	if previous == nil {
+		action = "add"
+	}
+	subject := strings.NewReplacer(
+		"{scope}", s.commitScope(dir),
+		"{action}", action,
+		"{slug}", filepath.Base(dir),
+		"{title}", wf.Title,
+	).Replace(template)
+
+	if previous == nil {
+		return subject
+	}
+	var body []string
+	for _, c := range diff.Semantic(previous, wf) {
+		if c.Section == diff.SectionStep {
+			// Just the header line, without the field details
+			body = append(body, strings.SplitN(c.String(), "\n", 2)[0])
+		}
+	}
+	if len(body) == 0 {
+		return subject
+	}
+	return subject + "\n\n" + strings.Join(body, "\n")
+}
+
+// commitScope returns the scope of a workflow directory for commit
+// messages: the identity path under the workflows root, such as
+// "platform/chaz", or the repo-relative parent directory otherwise, such
+// as "shared".
+func (s *FileSystemStore) commitScope(dir string) string {
+	rel, err := filepath.Rel(s.repo.Path(), filepath.Dir(dir))
+	if err != nil {
+		return ""
+	}
+	rel = filepath.ToSlash(rel)
+	if scope := strings.TrimPrefix(rel, s.config.Workflows.Root+"/"); scope != rel {
+		return scope
+	}
+	return rel
+}
+
+// previousDocument returns the workflow a save to path replaces: the
+// document with wf's ID, the document doc, or a single-document file's
+// workflow. It returns nil for a new file or document.
+func previousDocument(path string, wf *workflows.Workflow, doc int) *workflows.Workflow {
+	existing, err := workflows.LoadAll(path)
+	if err != nil {
+		return nil
+	}
+	for _, other := range existing {
+		if other.ID != "" && other.ID == wf.ID {
+			return other
+		}
+	}
+	if doc >= 1 && doc <= len(existing) {
+		return existing[doc-1]
+	}
+	if len(existing) == 1 {
+		return existing[0]
+	}
+	return nil
+}
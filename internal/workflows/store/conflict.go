@@ -0,0 +1,100 @@
+package store
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+
+	svferrors "github.com/chazuruo/svf/internal/errors"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// Loaded is a workflow file's content as a workflow was loaded from it,
+// so saving can tell whether the file changed in the meantime.
+type Loaded struct {
+	// Blob is the git blob hash of Content, as git hash-object computes
+	// it, so it can be compared with the file in any revision.
+	Blob    string
+	Content []byte
+}
+
+// NewLoaded records content as loaded.
+func NewLoaded(content []byte) *Loaded {
+	return &Loaded{Blob: BlobHash(content), Content: content}
+}
+
+// BlobHash returns the git blob hash of content.
+func BlobHash(content []byte) string {
+	h := sha1.New()
+	fmt.Fprintf(h, "blob %d\x00", len(content))
+	h.Write(content)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ConflictError is returned by Save when the workflow file changed since
+// the workflow being saved was loaded from it. Saving again with Base set
+// to the current content, e.g. after merging, or with Force overwrites it.
+type ConflictError struct {
+	// Path is the workflow file.
+	Path string
+	// Base is the file's content when the workflow was loaded.
+	Base []byte
+	// Theirs is the file's content now, nil if it was deleted.
+	Theirs []byte
+}
+
+func (e *ConflictError) Error() string {
+	if e.Theirs == nil {
+		return fmt.Sprintf("%s was deleted since the workflow was loaded", e.Path)
+	}
+	return fmt.Sprintf("%s changed since the workflow was loaded", e.Path)
+}
+
+// Is reports whether target is svferrors.ErrConflict.
+func (e *ConflictError) Is(target error) bool {
+	return target == svferrors.ErrConflict
+}
+
+// ReplaceDocument returns a workflow file in format with wf in place of
+// the document of content with wf's ID, or else of document doc. If
+// content holds a single workflow, or can't be parsed, wf replaces it
+// whole.
+func ReplaceDocument(content []byte, format workflows.Format, wf *workflows.Workflow, doc int) ([]byte, error) {
+	docs, err := workflows.UnmarshalWorkflows(content, format)
+	if err != nil || len(docs) < 2 {
+		return workflows.MarshalWorkflows([]*workflows.Workflow{wf}, format)
+	}
+	for i, other := range docs {
+		if other.ID != "" && other.ID == wf.ID {
+			doc = i + 1
+			break
+		}
+	}
+	if doc < 1 || doc > len(docs) {
+		return nil, fmt.Errorf("file holds %d workflows; cannot tell which one to replace", len(docs))
+	}
+	docs[doc-1] = wf
+	return workflows.MarshalWorkflows(docs, format)
+}
+
+// FindDocument returns the workflow in content, a workflow file in
+// format, with the given ID, or else document doc (1-based; 0 for a file
+// with a single workflow).
+func FindDocument(content []byte, format workflows.Format, id string, doc int) (*workflows.Workflow, error) {
+	docs, err := workflows.UnmarshalWorkflows(content, format)
+	if err != nil {
+		return nil, err
+	}
+	for _, wf := range docs {
+		if id != "" && wf.ID == id {
+			return wf, nil
+		}
+	}
+	switch {
+	case doc == 0 && len(docs) == 1:
+		return docs[0], nil
+	case doc >= 1 && doc <= len(docs):
+		return docs[doc-1], nil
+	}
+	return nil, fmt.Errorf("file holds %d workflows, none with ID %s", len(docs), id)
+}
@@ -5,13 +5,17 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/chazuruo/svf/internal/clock"
 	"github.com/chazuruo/svf/internal/config"
 	"github.com/chazuruo/svf/internal/gitrepo"
 	"github.com/chazuruo/svf/internal/index"
+	"github.com/chazuruo/svf/internal/lock"
+	"github.com/chazuruo/svf/internal/redact"
 	"github.com/chazuruo/svf/internal/workflows"
 )
 
@@ -22,10 +26,26 @@ type FileSystemStore struct {
 	index       *index.Index
 	indexMutex  sync.RWMutex
 	indexLoaded bool
+	clock       clock.Clock
+
+	// loaded holds the workflow files as Load read them, by path, so Save
+	// doesn't overwrite changes made since.
+	loaded      map[string]*Loaded
+	loadedMutex sync.Mutex
+}
+
+// Option configures a FileSystemStore.
+type Option func(*FileSystemStore)
+
+// WithClock sets the clock used for workflow and index timestamps.
+func WithClock(c clock.Clock) Option {
+	return func(s *FileSystemStore) {
+		s.clock = c
+	}
 }
 
 // New creates a new FileSystemStore.
-func New(repo gitrepo.Repo, cfg *config.Config) (*FileSystemStore, error) {
+func New(repo gitrepo.Repo, cfg *config.Config, opts ...Option) (*FileSystemStore, error) {
 	if repo == nil {
 		return nil, fmt.Errorf("repo cannot be nil")
 	}
@@ -33,14 +53,27 @@ func New(repo gitrepo.Repo, cfg *config.Config) (*FileSystemStore, error) {
 		return nil, fmt.Errorf("config cannot be nil")
 	}
 
-	return &FileSystemStore{
+	s := &FileSystemStore{
 		repo:   repo,
 		config: cfg,
-	}, nil
+		clock:  clock.Real,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
 }
 
 // List returns workflow references matching the given filter.
+// Unless filter.Verify is set, workflows are listed from the saved search
+// index, without reading their files, when there is one.
 func (s *FileSystemStore) List(ctx context.Context, filter Filter) ([]WorkflowRef, error) {
+	if !filter.Verify {
+		if refs, ok := s.listFromIndex(filter); ok {
+			return refs, nil
+		}
+	}
+
 	var refs []WorkflowRef
 
 	// Load index if we need to filter by tags or search
@@ -73,35 +106,70 @@ func (s *FileSystemStore) List(ctx context.Context, filter Filter) ([]WorkflowRe
 		return refs, nil
 	}
 
-	err := filepath.Walk(workflowRoot, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
+	for _, r := range s.listRoots(filter) {
+		err := filepath.Walk(r.dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				if r.kind != index.KindWorkflow && os.IsNotExist(err) {
+					return filepath.SkipDir
+				}
+				return err
+			}
 
-		// Skip directories
-		if info.IsDir() {
-			return nil
-		}
+			// Skip directories
+			if info.IsDir() {
+				return nil
+			}
 
-		// Only process workflow.yaml files
-		if info.Name() != "workflow.yaml" && info.Name() != "workflow.yml" {
-			return nil
-		}
+			// Only process workflow files
+			if !workflows.IsWorkflowFile(info.Name()) {
+				return nil
+			}
+
+			// Apply filter
+			fileRefs, err := s.pathToRefs(path)
+			if err != nil {
+				return err
+			}
+
+			for _, ref := range fileRefs {
+				ref.Kind = r.kind
+				if s.matchesFilter(ref, filter, path) {
+					refs = append(refs, ref)
+				}
+			}
 
-		// Apply filter
-		ref, err := s.pathToRef(path)
+			return nil
+		})
 		if err != nil {
-			return err
+			return refs, err
 		}
+	}
 
-		if s.matchesFilter(ref, filter, path) {
-			refs = append(refs, ref)
-		}
+	return refs, nil
+}
 
-		return nil
-	})
+// listRoot is a folder List lists workflows from.
+type listRoot struct {
+	dir  string
+	kind string
+}
 
-	return refs, err
+// listRoots returns the folders List lists from: the workflows root, and
+// the drafts and templates roots if filter includes drafts.
+func (s *FileSystemStore) listRoots(filter Filter) []listRoot {
+	roots := []listRoot{{filepath.Join(s.repo.Path(), s.config.Workflows.Root), index.KindWorkflow}}
+	if !filter.IncludeDrafts {
+		return roots
+	}
+	for _, r := range []listRoot{
+		{s.config.Workflows.DraftRoot, index.KindDraft},
+		{s.config.Workflows.TemplateRoot, index.KindTemplate},
+	} {
+		if r.dir != "" {
+			roots = append(roots, listRoot{filepath.Join(s.repo.Path(), r.dir), r.kind})
+		}
+	}
+	return roots
 }
 
 // Load reads a workflow from the store by its reference.
@@ -110,8 +178,20 @@ func (s *FileSystemStore) Load(ctx context.Context, ref WorkflowRef) (*workflows
 	if err != nil {
 		return nil, fmt.Errorf("failed to read workflow: %w", err)
 	}
+	s.setLoaded(ref.Path, data)
+
+	if ref.Doc > 0 {
+		wfs, err := workflows.UnmarshalWorkflows(data, workflows.FormatForPath(ref.Path))
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal workflow: %w", err)
+		}
+		if ref.Doc > len(wfs) {
+			return nil, fmt.Errorf("%s has %d workflows, no document %d", ref.Path, len(wfs), ref.Doc)
+		}
+		return wfs[ref.Doc-1], nil
+	}
 
-	wf, err := workflows.UnmarshalWorkflow(data)
+	wf, err := workflows.UnmarshalWorkflowAs(data, workflows.FormatForPath(ref.Path))
 	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal workflow: %w", err)
 	}
@@ -121,65 +201,138 @@ func (s *FileSystemStore) Load(ctx context.Context, ref WorkflowRef) (*workflows
 
 // Save writes a workflow to the store.
 func (s *FileSystemStore) Save(ctx context.Context, wf *workflows.Workflow, opts SaveOptions) (WorkflowRef, error) {
-	// Generate slug if not set
-	slug := wf.ID
-	if slug == "" {
-		slug = Slugify(wf.Title)
-		if slug == "" {
-			return WorkflowRef{}, fmt.Errorf("cannot generate slug from title")
-		}
+	if err := s.checkWritable(); err != nil {
+		return WorkflowRef{}, err
+	}
+
+	// Assign a stable ID so the workflow can be found after renames
+	isNew := wf.ID == ""
+	if isNew {
+		wf.ID = workflows.NewID()
 	}
 
-	// Determine the save path
-	dirPath, err := s.resolvePath(slug, opts)
+	// Serialize with other svf processes writing to the repo
+	l, err := lock.Acquire(s.repo.Path(), lock.Options{Op: "save"})
 	if err != nil {
 		return WorkflowRef{}, err
 	}
+	defer func() { _ = l.Release() }()
 
-	workflowPath := filepath.Join(dirPath, "workflow.yaml")
+	// Update a workflow in place when its location is known, either given
+	// or found by ID; otherwise derive a new location from the title
+	workflowPath, doc := opts.Path, opts.Doc
+	if workflowPath == "" && !isNew {
+		workflowPath, doc, err = s.findByID(wf.ID)
+		if err != nil {
+			return WorkflowRef{}, err
+		}
+	}
+
+	var dirPath string
+	if workflowPath != "" {
+		dirPath = filepath.Dir(workflowPath)
+	} else {
+		slug := Slugify(wf.Title)
+		if slug == "" {
+			return WorkflowRef{}, fmt.Errorf("cannot generate slug from title")
+		}
+
+		dirPath, err = s.resolvePath(slug, opts)
+		if err != nil {
+			return WorkflowRef{}, err
+		}
+		format, err := workflows.ParseFormat(s.config.Workflows.Format)
+		if err != nil {
+			return WorkflowRef{}, err
+		}
+		workflowPath = filepath.Join(dirPath, workflows.FileName(format))
 
-	// Check if file exists and Force is not set
-	if _, err := os.Stat(workflowPath); err == nil && !opts.Force {
-		return WorkflowRef{}, fmt.Errorf("workflow already exists at %s (use Force to overwrite)", workflowPath)
+		// Check if a workflow file exists and Force is not set
+		if existing := workflows.FindFile(dirPath); existing != "" {
+			if !opts.Force {
+				return WorkflowRef{}, fmt.Errorf("workflow already exists at %s (use Force to overwrite)", existing)
+			}
+			// Overwrite in place rather than leave two workflow files
+			workflowPath = existing
+		}
 	}
+	slug := filepath.Base(dirPath)
 
 	// Create directory if needed
 	if err := os.MkdirAll(dirPath, 0755); err != nil {
 		return WorkflowRef{}, fmt.Errorf("failed to create directory: %w", err)
 	}
 
-	// Marshal workflow to YAML
-	data, err := workflows.MarshalWorkflow(wf)
+	// Bring companion files along from where the workflow came from
+	if opts.SourceDir != "" {
+		if err := workflows.CopyCompanions(wf, opts.SourceDir, dirPath); err != nil {
+			return WorkflowRef{}, err
+		}
+	}
+
+	// Don't overwrite changes made to the file since it was loaded
+	if !opts.Force {
+		if err := s.checkUnchanged(workflowPath, opts.Base); err != nil {
+			return WorkflowRef{}, err
+		}
+	}
+
+	// Remember what is replaced, to describe the change in the commit
+	var previous *workflows.Workflow
+	if opts.Commit && opts.Message == "" {
+		previous = previousDocument(workflowPath, wf, doc)
+	}
+
+	// Replace the workflow's document when the file holds several
+	docs, doc, err := spliceDocument(workflowPath, wf, doc)
+	if err != nil {
+		return WorkflowRef{}, err
+	}
+
+	// Marshal workflow in its file's format
+	data, err := workflows.MarshalWorkflows(docs, workflows.FormatForPath(workflowPath))
 	if err != nil {
 		return WorkflowRef{}, fmt.Errorf("failed to marshal workflow: %w", err)
 	}
 
-	// Write workflow.yaml
+	// Keep raw secrets out of the repo's history
+	if s.config.Git.ScanSecrets && !opts.AllowSecrets {
+		if findings := redact.Scan(string(data)); len(findings) > 0 {
+			return WorkflowRef{}, &SecretsError{Path: s.relPath(workflowPath), Findings: findings}
+		}
+	}
+
+	// Write the workflow file
 	if err := os.WriteFile(workflowPath, data, 0644); err != nil {
 		return WorkflowRef{}, fmt.Errorf("failed to write workflow: %w", err)
 	}
+	s.setLoaded(workflowPath, data)
 
 	// Generate README.md (optional)
-	readmePath := filepath.Join(dirPath, "README.md")
-	if err := s.generateReadme(readmePath, wf); err != nil {
+	if _, err := s.writeReadme(workflowPath, docs...); err != nil {
 		// Don't fail on README error
 		fmt.Fprintf(os.Stderr, "Warning: failed to generate README: %v\n", err)
 	}
+	s.refreshIndex(workflowPath)
 
 	ref := WorkflowRef{
 		ID:        wf.ID,
 		Slug:      slug,
+		Aliases:   wf.Aliases,
+		Title:     wf.Title,
+		Tags:      wf.Tags,
 		Path:      workflowPath,
-		UpdatedAt: time.Now(),
+		Doc:       doc,
+		UpdatedAt: s.clock.Now(),
 	}
 
 	// Auto-commit if requested
 	if opts.Commit {
 		message := opts.Message
-		if message == "" {
-			message = fmt.Sprintf("Save workflow: %s", wf.Title)
+		if message == "" && !opts.Amend {
+			message = s.commitMessage(wf, previous, dirPath)
 		}
-		if err := s.commitWorkflow(ctx, workflowPath, message); err != nil {
+		if err := s.commitWorkflow(ctx, workflowPath, message, opts.Amend); err != nil {
 			return WorkflowRef{}, fmt.Errorf("failed to commit: %w", err)
 		}
 	}
@@ -187,18 +340,177 @@ func (s *FileSystemStore) Save(ctx context.Context, wf *workflows.Workflow, opts
 	return ref, nil
 }
 
-// Delete removes a workflow from the store.
+// Delete removes a workflow from the store. A workflow sharing its file
+// with others loses only its document; otherwise its directory is removed,
+// or just its own files when workflows are nested below it.
 func (s *FileSystemStore) Delete(ctx context.Context, ref WorkflowRef) error {
-	// Delete the workflow directory (containing workflow.yaml and README.md)
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
 	workflowDir := filepath.Dir(ref.Path)
 
-	if err := os.RemoveAll(workflowDir); err != nil {
+	l, err := lock.Acquire(s.repo.Path(), lock.Options{Op: "delete"})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = l.Release() }()
+	defer s.refreshIndex(ref.Path)
+
+	if ref.Doc > 0 {
+		docs, err := workflows.LoadAll(ref.Path)
+		if err != nil {
+			return fmt.Errorf("failed to load workflow file: %w", err)
+		}
+		if len(docs) > 1 {
+			if ref.Doc > len(docs) {
+				return fmt.Errorf("%s has no document %d", ref.Path, ref.Doc)
+			}
+			docs = append(docs[:ref.Doc-1], docs[ref.Doc:]...)
+			data, err := workflows.MarshalWorkflows(docs, workflows.FormatForPath(ref.Path))
+			if err != nil {
+				return fmt.Errorf("failed to marshal workflows: %w", err)
+			}
+			if err := os.WriteFile(ref.Path, data, 0644); err != nil {
+				return fmt.Errorf("failed to write workflow: %w", err)
+			}
+			if _, err := s.writeReadme(ref.Path, docs...); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to generate README: %v\n", err)
+			}
+			return nil
+		}
+	}
+
+	nested, err := hasNestedWorkflow(workflowDir)
+	if err != nil {
 		return fmt.Errorf("failed to delete workflow: %w", err)
 	}
+	if !nested {
+		if err := os.RemoveAll(workflowDir); err != nil {
+			return fmt.Errorf("failed to delete workflow: %w", err)
+		}
+		return nil
+	}
+
+	// Keep the nested workflows, removing only this one's files
+	files := []string{filepath.Base(ref.Path), "README.md", workflows.TestsFile}
+	if wfs, err := workflows.LoadAll(ref.Path); err == nil {
+		for _, wf := range wfs {
+			files = append(files, wf.CompanionFiles()...)
+		}
+	}
+	for _, f := range files {
+		if err := os.Remove(filepath.Join(workflowDir, filepath.FromSlash(f))); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to delete workflow: %w", err)
+		}
+	}
+	return nil
+}
+
+// hasNestedWorkflow reports whether a directory below dir holds a workflow.
+func hasNestedWorkflow(dir string) (bool, error) {
+	found := false
+	err := filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && p != dir && workflows.FindFile(p) != "" {
+			found = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return found, err
+}
+
+// checkWritable refuses writes in read-only mode, so no command can
+// write to the repo there, whether or not the CLI hides it.
+func (s *FileSystemStore) checkWritable() error {
+	if s.config.ReadOnly {
+		return fmt.Errorf("the workflow repo is %w (readonly = true in the config)", ErrReadOnly)
+	}
+	return nil
+}
+
+// Loaded returns the content of the workflow file at path as Load last
+// read it (or Save last wrote it), or nil if it wasn't loaded.
+func (s *FileSystemStore) Loaded(path string) *Loaded {
+	s.loadedMutex.Lock()
+	defer s.loadedMutex.Unlock()
+	return s.loaded[filepath.Clean(path)]
+}
+
+func (s *FileSystemStore) setLoaded(path string, content []byte) {
+	s.loadedMutex.Lock()
+	defer s.loadedMutex.Unlock()
+	if s.loaded == nil {
+		s.loaded = make(map[string]*Loaded)
+	}
+	s.loaded[filepath.Clean(path)] = NewLoaded(content)
+}
 
+// checkUnchanged returns a *ConflictError if the workflow file at path
+// differs from base, defaulting to the file as it was loaded. Files that
+// weren't loaded aren't checked.
+func (s *FileSystemStore) checkUnchanged(path string, base *Loaded) error {
+	if base == nil {
+		base = s.Loaded(path)
+	}
+	if base == nil {
+		return nil
+	}
+	current, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &ConflictError{Path: s.relPath(path), Base: base.Content}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read workflow: %w", err)
+	}
+	if BlobHash(current) != base.Blob {
+		return &ConflictError{Path: s.relPath(path), Base: base.Content, Theirs: current}
+	}
 	return nil
 }
 
+// findByID returns the path and document of the workflow with the given
+// ID, or "" if there is none.
+func (s *FileSystemStore) findByID(id string) (string, int, error) {
+	// The index may be behind the files, which must be found where they are
+	refs, err := s.List(context.Background(), Filter{Verify: true})
+	if err != nil {
+		return "", 0, err
+	}
+	for _, ref := range refs {
+		if ref.ID == id {
+			return ref.Path, ref.Doc, nil
+		}
+	}
+	return "", 0, nil
+}
+
+// spliceDocument returns the workflows to write to path when saving wf:
+// wf alone, or, if path holds several workflows, all of them with wf in
+// place of the document with its ID or else of document doc. It also
+// returns wf's document number, 0 for a single-workflow file.
+func spliceDocument(path string, wf *workflows.Workflow, doc int) ([]*workflows.Workflow, int, error) {
+	existing, err := workflows.LoadAll(path)
+	if err != nil || len(existing) < 2 {
+		// A new file, or one being replaced
+		return []*workflows.Workflow{wf}, 0, nil
+	}
+
+	for i, other := range existing {
+		if other.ID != "" && other.ID == wf.ID {
+			doc = i + 1
+			break
+		}
+	}
+	if doc < 1 || doc > len(existing) {
+		return nil, 0, fmt.Errorf("%s holds %d workflows; cannot tell which one to replace", path, len(existing))
+	}
+	existing[doc-1] = wf
+	return existing, doc, nil
+}
+
 // resolvePath determines the directory path for a workflow based on its slug.
 func (s *FileSystemStore) resolvePath(slug string, opts SaveOptions) (string, error) {
 	repoPath := s.repo.Path()
@@ -216,11 +528,12 @@ func (s *FileSystemStore) resolvePath(slug string, opts SaveOptions) (string, er
 	return workflowPath, nil
 }
 
-// pathToRef converts a file path to a WorkflowRef.
-func (s *FileSystemStore) pathToRef(path string) (WorkflowRef, error) {
+// pathToRefs converts a workflow file path to WorkflowRefs, one for each
+// workflow in the file.
+func (s *FileSystemStore) pathToRefs(path string) ([]WorkflowRef, error) {
 	info, err := os.Stat(path)
 	if err != nil {
-		return WorkflowRef{}, err
+		return nil, err
 	}
 
 	// Extract slug from path
@@ -228,12 +541,121 @@ func (s *FileSystemStore) pathToRef(path string) (WorkflowRef, error) {
 	dir := filepath.Dir(path)
 	slug := filepath.Base(dir)
 
-	return WorkflowRef{
-		ID:        "", // ID is loaded from the workflow file
+	ref := WorkflowRef{
 		Slug:      slug,
 		Path:      path,
 		UpdatedAt: info.ModTime(),
-	}, nil
+	}
+
+	// Files that can't be parsed are still listed, without identifiers
+	wfs, err := workflows.LoadAll(path)
+	if err != nil {
+		return []WorkflowRef{ref}, nil
+	}
+	if len(wfs) == 1 {
+		ref.ID, ref.Aliases = wfs[0].ID, wfs[0].Aliases
+		ref.Title, ref.Tags = wfs[0].Title, wfs[0].Tags
+		return []WorkflowRef{ref}, nil
+	}
+
+	refs := make([]WorkflowRef, len(wfs))
+	for i, wf := range wfs {
+		refs[i] = ref
+		refs[i].ID, refs[i].Aliases, refs[i].Doc = wf.ID, wf.Aliases, i+1
+		refs[i].Title, refs[i].Tags = wf.Title, wf.Tags
+	}
+	return refs, nil
+}
+
+// listFromIndex lists the workflows under the workflows root, and the
+// drafts and templates roots if filter includes drafts, from the saved
+// search index. It reports false when there is no usable index.
+func (s *FileSystemStore) listFromIndex(filter Filter) ([]WorkflowRef, bool) {
+	builder := index.NewBuilder(s.repo.Path(), s.config, index.WithClock(s.clock))
+	idx, err := builder.Load()
+	if err != nil || idx.Version != index.CurrentSchemaVersion {
+		return nil, false
+	}
+
+	s.indexMutex.Lock()
+	s.index = idx
+	s.indexLoaded = true
+	s.indexMutex.Unlock()
+
+	roots := s.listRoots(filter)
+	refs := []WorkflowRef{}
+	for _, entry := range idx.Workflows {
+		path := filepath.Join(s.repo.Path(), entry.Path)
+		for _, r := range roots {
+			if !strings.HasPrefix(path, r.dir+string(filepath.Separator)) {
+				continue
+			}
+			ref := entryToRef(entry, r.dir, path)
+			ref.Kind = r.kind
+			if s.matchesFilter(ref, filter, path) {
+				refs = append(refs, ref)
+			}
+			break
+		}
+	}
+
+	// Match the order of walking the files
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].Path != refs[j].Path {
+			return refs[i].Path < refs[j].Path
+		}
+		return refs[i].Doc < refs[j].Doc
+	})
+	return refs, true
+}
+
+// entryToRef returns the reference for an index entry of the workflow at
+// path under root.
+func entryToRef(entry index.WorkflowEntry, root, path string) WorkflowRef {
+	dir := filepath.Dir(path)
+	ref := WorkflowRef{
+		ID:      entry.ID,
+		Slug:    filepath.Base(dir),
+		Aliases: entry.Aliases,
+		Title:   entry.Title,
+		Tags:    entry.Tags,
+		Path:    path,
+		Doc:     entry.Doc,
+	}
+	ref.UpdatedAt, _ = time.Parse(time.RFC3339, entry.UpdatedAt)
+
+	// The index makes up IDs for workflows without one; don't report them
+	identityPath, err := filepath.Rel(root, dir)
+	if err == nil {
+		generated := identityPath + "/" + ref.Slug
+		if entry.Kind == index.KindDraft || entry.Kind == index.KindTemplate {
+			// Generated IDs of drafts and templates start with their folder
+			generated = filepath.ToSlash(filepath.Dir(entry.Path)) + "/" + ref.Slug
+		}
+		if entry.Doc > 0 {
+			generated = fmt.Sprintf("%s#%d", generated, entry.Doc)
+		}
+		if entry.ID == generated {
+			ref.ID = ""
+		}
+	}
+	return ref
+}
+
+// refreshIndex updates the saved search index's entries for paths, if
+// there is an index, so listing from it stays current.
+func (s *FileSystemStore) refreshIndex(paths ...string) {
+	builder := index.NewBuilder(s.repo.Path(), s.config, index.WithClock(s.clock))
+	idx, err := builder.Load()
+	if err != nil || idx.Version != index.CurrentSchemaVersion {
+		return
+	}
+	if !builder.Update(idx, paths) {
+		return
+	}
+	if err := builder.Save(idx); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to save index: %v\n", err)
+	}
 }
 
 // matchesFilter checks if a workflow reference matches the given filter.
@@ -271,7 +693,7 @@ func (s *FileSystemStore) matchesFilter(ref WorkflowRef, filter Filter, path str
 		}
 
 		// Find the entry in the index
-		entry := s.index.GetByPath(relPath)
+		entry := s.index.GetByPath(WorkflowRef{Path: relPath, Doc: ref.Doc}.Location())
 		if entry == nil {
 			// Workflow not in index, skip it
 			return false
@@ -309,7 +731,7 @@ func (s *FileSystemStore) matchesFilter(ref WorkflowRef, filter Filter, path str
 		matches := false
 		searchResults := s.index.Search(filter.Search)
 		for _, result := range searchResults {
-			if result.Path == relPath {
+			if result.Path == relPath && result.Doc == ref.Doc {
 				matches = true
 				break
 			}
@@ -322,45 +744,30 @@ func (s *FileSystemStore) matchesFilter(ref WorkflowRef, filter Filter, path str
 	return true
 }
 
-// generateReadme creates a README.md file for the workflow.
-func (s *FileSystemStore) generateReadme(path string, wf *workflows.Workflow) error {
-	content := fmt.Sprintf("# %s\n\n", wf.Title)
-
-	if wf.Description != "" {
-		content += wf.Description + "\n\n"
-	}
-
-	if len(wf.Tags) > 0 {
-		content += "## Tags\n\n"
-		for _, tag := range wf.Tags {
-			content += fmt.Sprintf("- %s\n", tag)
-		}
-		content += "\n"
+// commitWorkflow adds and commits a workflow file, amending the last
+// commit if amend is set. Only the workflow's directory and the search
+// index are staged, so unrelated files in the working tree stay out of
+// the commit.
+func (s *FileSystemStore) commitWorkflow(ctx context.Context, path, message string, amend bool) error {
+	// Stage the directory so workflow.yaml, README.md and companions go together
+	if err := s.repo.Add(ctx, filepath.Dir(path)); err != nil {
+		return fmt.Errorf("failed to add files: %w", err)
 	}
-
-	if len(wf.Steps) > 0 {
-		content += "## Steps\n\n"
-		for i, step := range wf.Steps {
-			name := step.Name
-			if name == "" {
-				name = fmt.Sprintf("Step %d", i+1)
+	if indexPath := filepath.Join(s.repo.Path(), s.config.Workflows.IndexPath); s.config.Workflows.IndexPath != "" {
+		if _, err := os.Stat(indexPath); err == nil {
+			if err := s.repo.Add(ctx, indexPath); err != nil {
+				return fmt.Errorf("failed to add index: %w", err)
 			}
-			content += fmt.Sprintf("### %s\n\n", name)
-			content += fmt.Sprintf("```\n%s\n```\n\n", step.Command)
 		}
 	}
 
-	return os.WriteFile(path, []byte(content), 0644)
-}
-
-// commitWorkflow adds and commits a workflow file.
-func (s *FileSystemStore) commitWorkflow(ctx context.Context, path, message string) error {
-	// Add all changes to ensure workflow.yaml and README.md are both staged
-	if err := s.repo.AddAll(ctx); err != nil {
-		return fmt.Errorf("failed to add files: %w", err)
-	}
-
 	// Commit
+	if amend {
+		if _, err := s.repo.AmendCommit(ctx, message); err != nil {
+			return fmt.Errorf("failed to amend commit: %w", err)
+		}
+		return nil
+	}
 	if _, err := s.repo.CommitAll(ctx, message); err != nil {
 		return fmt.Errorf("failed to commit: %w", err)
 	}
@@ -373,10 +780,10 @@ func (s *FileSystemStore) loadIndex(ctx context.Context) error {
 	s.indexMutex.Lock()
 	defer s.indexMutex.Unlock()
 
-	builder := index.NewBuilder(s.repo.Path(), s.config)
+	builder := index.NewBuilder(s.repo.Path(), s.config, index.WithClock(s.clock))
 	idx, err := builder.Load()
 	if err != nil {
-		// If the index doesn't exist, try to build it
+		// If the index doesn't exist or is corrupt, rebuild it
 		idx, err = builder.Build()
 		if err != nil {
 			return fmt.Errorf("failed to build index: %w", err)
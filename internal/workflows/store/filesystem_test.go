@@ -2,44 +2,28 @@ package store
 
 import (
 	"context"
+	"errors"
+	"maps"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/chazuruo/svf/internal/config"
 	"github.com/chazuruo/svf/internal/gitrepo"
 	"github.com/chazuruo/svf/internal/index"
+	"github.com/chazuruo/svf/internal/testutil"
 	"github.com/chazuruo/svf/internal/workflows"
 )
 
-// setupGitConfig configures git user.name and user.email for testing.
-func setupGitConfig(tmpDir string) {
-	ctx := context.Background()
-	// Set git config for the test repository
-	cmd := exec.CommandContext(ctx, "git", "config", "user.email", "test@example.com")
-	cmd.Dir = tmpDir
-	_ = cmd.Run()
-
-	cmd = exec.CommandContext(ctx, "git", "config", "user.name", "Test User")
-	cmd.Dir = tmpDir
-	_ = cmd.Run()
-}
-
-// setupTestRepo creates a temporary Git repository for testing.
-func setupTestRepo(t *testing.T) (string, gitrepo.Repo, *config.Config) {
+// setupTestRepo creates a fake repository in a temporary directory.
+func setupTestRepo(t *testing.T) (string, *testutil.FakeRepo, *config.Config) {
 	t.Helper()
 
 	// Create temp directory
 	tmpDir := t.TempDir()
-
-	// Initialize git repo
-	repo := gitrepo.New(tmpDir)
-	ctx := context.Background()
-	if err := repo.Init(ctx, gitrepo.InitOptions{}); err != nil {
-		t.Fatalf("failed to init repo: %v", err)
-	}
+	repo := testutil.NewFakeRepo(tmpDir)
 
 	// Create test config
 	cfg := &config.Config{
@@ -152,16 +136,13 @@ func TestFileSystemStore_Save(t *testing.T) {
 	t.Run("save with auto-commit", func(t *testing.T) {
 		wf := makeTestWorkflow("Commit Test", makeTestStep("true"))
 
-		// Setup git config for commits
-		setupGitConfig(repo.Path())
-
 		// Create an initial commit first so HEAD exists
 		initialFile := filepath.Join(repo.Path(), "initial.txt")
 		_ = os.WriteFile(initialFile, []byte("initial"), 0644)
 		_ = repo.Add(ctx, "initial.txt")
 		_, _ = repo.CommitAll(ctx, "initial commit")
 
-		_, err := store.Save(ctx, wf, SaveOptions{
+		ref, err := store.Save(ctx, wf, SaveOptions{
 			Commit:  true,
 			Message: "test commit",
 		})
@@ -169,13 +150,68 @@ func TestFileSystemStore_Save(t *testing.T) {
 			t.Fatalf("Save() with commit error = %v", err)
 		}
 
-		// Verify git status shows clean (committed)
+		// Verify the workflow is committed; files of the earlier, uncommitted
+		// saves stay out of the commit
 		status, err := repo.Status(ctx)
 		if err != nil {
 			t.Fatalf("failed to get status: %v", err)
 		}
-		if status.Dirty {
-			t.Error("expected clean status after commit")
+		dir, _ := filepath.Rel(repo.Path(), filepath.Dir(ref.Path))
+		for _, e := range status.Entries {
+			if strings.HasPrefix(e.Path, filepath.ToSlash(dir)+"/") {
+				t.Errorf("%s not committed: %+v", e.Path, e)
+			}
+		}
+		if len(status.Entries) == 0 {
+			t.Error("uncommitted workflows of earlier saves were committed too")
+		}
+		if commits := repo.Commits(); len(commits) != 2 || commits[0].Message != "test commit" {
+			t.Errorf("commits = %+v, want the workflow commit on top of the initial one", commits)
+		}
+	})
+
+	t.Run("save generates commit messages", func(t *testing.T) {
+		wf := makeTestWorkflow("Deploy API", workflows.Step{Name: "build", Command: "make"})
+		if _, err := store.Save(ctx, wf, SaveOptions{Commit: true}); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		if got := repo.Commits()[0].Message; got != "workflow(platform/test): add deploy-api" {
+			t.Errorf("message = %q", got)
+		}
+
+		wf.Steps = append(wf.Steps, workflows.Step{Name: "ship", Command: "make deploy"})
+		if _, err := store.Save(ctx, wf, SaveOptions{Commit: true}); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		if got, want := repo.Commits()[0].Message, "workflow(platform/test): update deploy-api\n\n+ step 2 \"ship\""; got != want {
+			t.Errorf("message = %q, want %q", got, want)
+		}
+
+		// Amending keeps the last message and replaces the commit
+		count := len(repo.Commits())
+		wf.Steps[1].Command = "make deploy ENV=prod"
+		if _, err := store.Save(ctx, wf, SaveOptions{Commit: true, Amend: true}); err != nil {
+			t.Fatalf("Save() with amend error = %v", err)
+		}
+		commits := repo.Commits()
+		if len(commits) != count || !strings.HasPrefix(commits[0].Message, "workflow(platform/test): update deploy-api") {
+			t.Errorf("commits after amend = %d, top %q; want %d with the last message", len(commits), commits[0].Message, count)
+		}
+	})
+
+	t.Run("save uses the store clock", func(t *testing.T) {
+		now := time.Date(2025, 3, 14, 9, 26, 53, 0, time.UTC)
+		clocked, err := New(repo, cfg, WithClock(testutil.NewFakeClock(now)))
+		if err != nil {
+			t.Fatalf("failed to create store: %v", err)
+		}
+
+		ref, err := clocked.Save(ctx, makeTestWorkflow("Clock Test", makeTestStep("true")), SaveOptions{})
+		if err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		if !ref.UpdatedAt.Equal(now) {
+			t.Errorf("UpdatedAt = %v, want %v", ref.UpdatedAt, now)
 		}
 	})
 
@@ -198,6 +234,28 @@ func TestFileSystemStore_Save(t *testing.T) {
 			t.Errorf("README not created at %s", readmePath)
 		}
 	})
+
+	t.Run("save copies companion files", func(t *testing.T) {
+		src := t.TempDir()
+		if err := os.WriteFile(filepath.Join(src, "restart.sh"), []byte("systemctl restart api\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		wf := makeTestWorkflow("Companion Test", workflows.Step{Script: "./restart.sh"})
+
+		ref, err := store.Save(ctx, wf, SaveOptions{SourceDir: src})
+		if err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		data, err := os.ReadFile(filepath.Join(filepath.Dir(ref.Path), "restart.sh"))
+		if err != nil || string(data) != "systemctl restart api\n" {
+			t.Errorf("companion file = %q, %v; want it next to the workflow", data, err)
+		}
+
+		_, err = store.Save(ctx, makeTestWorkflow("Missing Companion", workflows.Step{Script: "gone.sh"}), SaveOptions{SourceDir: src})
+		if err == nil || !strings.Contains(err.Error(), "failed to copy companion file gone.sh") {
+			t.Errorf("Save() error = %v, want a copy error", err)
+		}
+	})
 }
 
 func TestFileSystemStore_Load(t *testing.T) {
@@ -240,6 +298,53 @@ func TestFileSystemStore_Load(t *testing.T) {
 			t.Errorf("Steps count = %d, want %d", len(loadedWf.Steps), len(originalWf.Steps))
 		}
 	})
+
+	t.Run("save and load in the configured format", func(t *testing.T) {
+		tomlCfg := *cfg
+		tomlCfg.Workflows.Format = "toml"
+		tomlStore, err := New(repo, &tomlCfg)
+		if err != nil {
+			t.Fatalf("failed to create store: %v", err)
+		}
+
+		ref, err := tomlStore.Save(ctx, makeTestWorkflow("TOML Test", makeTestStep("echo <name>")), SaveOptions{})
+		if err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		if filepath.Base(ref.Path) != "workflow.toml" {
+			t.Fatalf("Path = %s, want workflow.toml", ref.Path)
+		}
+
+		// The default store finds and loads it, and keeps its format on save
+		refs, err := store.List(ctx, Filter{})
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		found := false
+		for _, r := range refs {
+			found = found || r.Path == ref.Path
+		}
+		if !found {
+			t.Fatalf("List() = %v, want %s", refs, ref.Path)
+		}
+
+		loaded, err := store.Load(ctx, ref)
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if loaded.Steps[0].Command != "echo <name>" {
+			t.Errorf("Command = %q", loaded.Steps[0].Command)
+		}
+
+		loaded.Description = "updated"
+		updated, err := store.Save(ctx, loaded, SaveOptions{})
+		if err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		if updated.Path != ref.Path {
+			t.Errorf("updated Path = %s, want %s", updated.Path, ref.Path)
+		}
+	})
 }
 
 func TestFileSystemStore_List(t *testing.T) {
@@ -421,12 +526,150 @@ func TestFileSystemStore_List(t *testing.T) {
 	})
 }
 
-func TestFileSystemStore_Delete(t *testing.T) {
+// TestFileSystemStore_List_IncludeDrafts verifies that drafts and templates
+// are listed only when asked for, whether List reads the files or the index.
+func TestFileSystemStore_List_IncludeDrafts(t *testing.T) {
+	_, repo, cfg := setupTestRepo(t)
+	cfg.Workflows.TemplateRoot = "templates"
+	store, err := New(repo, cfg)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	ctx := context.Background()
+
+	if _, err := store.Save(ctx, makeTestWorkflow("Published", makeTestStep("true")), SaveOptions{}); err != nil {
+		t.Fatalf("failed to save workflow: %v", err)
+	}
+	for path, title := range map[string]string{
+		"drafts/platform/test/wip/workflow.yaml": "Work In Progress",
+		"templates/rollout/workflow.yaml":        "Rollout",
+	} {
+		full := filepath.Join(repo.Path(), path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte("title: "+title+"\nsteps:\n  - command: true\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	kinds := func(filter Filter) map[string]string {
+		t.Helper()
+		refs, err := store.List(ctx, filter)
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		got := map[string]string{}
+		for _, ref := range refs {
+			got[ref.Title] = ref.Kind
+			// IDs the index makes up for drafts aren't reported either
+			if ref.Kind != index.KindWorkflow && ref.ID != "" {
+				t.Errorf("List() %s ID = %q, want none", ref.Title, ref.ID)
+			}
+		}
+		return got
+	}
+
+	want := map[string]string{
+		"Published":        index.KindWorkflow,
+		"Work In Progress": index.KindDraft,
+		"Rollout":          index.KindTemplate,
+	}
+	for _, fromIndex := range []bool{false, true} {
+		if fromIndex {
+			if _, err := index.NewBuilder(repo.Path(), cfg).Rebuild(); err != nil {
+				t.Fatalf("failed to build index: %v", err)
+			}
+		}
+		if got := kinds(Filter{Verify: !fromIndex}); len(got) != 1 {
+			t.Errorf("List() = %v, want only the workflow (index: %v)", got, fromIndex)
+		}
+		if got := kinds(Filter{Verify: !fromIndex, IncludeDrafts: true}); !maps.Equal(got, want) {
+			t.Errorf("List(IncludeDrafts) = %v, want %v (index: %v)", got, want, fromIndex)
+		}
+	}
+}
+
+// TestFileSystemStore_List_FromIndex verifies that List serves from the
+// saved index, which Save and Delete keep current, unless asked to verify.
+func TestFileSystemStore_List_FromIndex(t *testing.T) {
 	_, repo, cfg := setupTestRepo(t)
 	store, err := New(repo, cfg)
 	if err != nil {
 		t.Fatalf("failed to create store: %v", err)
 	}
+	ctx := context.Background()
+
+	first, err := store.Save(ctx, makeTestWorkflow("First", makeTestStep("true")), SaveOptions{})
+	if err != nil {
+		t.Fatalf("failed to save workflow: %v", err)
+	}
+	unnamed := filepath.Join(repo.Path(), "workflows", "platform", "test", "unnamed", "workflow.yaml")
+	if err := os.MkdirAll(filepath.Dir(unnamed), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(unnamed, []byte("title: Unnamed\nsteps:\n  - command: true\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := index.NewBuilder(repo.Path(), cfg).Rebuild(); err != nil {
+		t.Fatalf("failed to build index: %v", err)
+	}
+
+	titles := func(filter Filter) map[string]string {
+		t.Helper()
+		refs, err := store.List(ctx, filter)
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		got := map[string]string{}
+		for _, ref := range refs {
+			got[ref.Slug] = ref.Title + "|" + ref.ID
+		}
+		return got
+	}
+
+	// Edit a file behind the index's back: only Verify sees the change
+	data, err := os.ReadFile(first.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(first.Path, []byte(strings.Replace(string(data), "First", "Renamed", 1)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := titles(Filter{})["first"]; got != "First|"+first.ID {
+		t.Errorf("List() first = %q, want the indexed title", got)
+	}
+	if got := titles(Filter{Verify: true})["first"]; got != "Renamed|"+first.ID {
+		t.Errorf("List(Verify) first = %q, want the file's title", got)
+	}
+
+	// IDs the index makes up aren't reported
+	if got := titles(Filter{})["unnamed"]; got != "Unnamed|" {
+		t.Errorf("List() unnamed = %q, want no ID", got)
+	}
+
+	// Saves and deletes update the index
+	second, err := store.Save(ctx, makeTestWorkflow("Second", makeTestStep("true")), SaveOptions{})
+	if err != nil {
+		t.Fatalf("failed to save workflow: %v", err)
+	}
+	if _, ok := titles(Filter{})["second"]; !ok {
+		t.Error("List() doesn't include a workflow saved after indexing")
+	}
+	if err := store.Delete(ctx, second); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, ok := titles(Filter{})["second"]; ok {
+		t.Error("List() includes a deleted workflow")
+	}
+}
+
+func TestFileSystemStore_Delete(t *testing.T) {
+	tmpDir, repo, cfg := setupTestRepo(t)
+	store, err := New(repo, cfg)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
 
 	ctx := context.Background()
 
@@ -458,6 +701,79 @@ func TestFileSystemStore_Delete(t *testing.T) {
 			t.Error("workflow directory still exists after delete")
 		}
 	})
+
+	t.Run("keeps nested workflows", func(t *testing.T) {
+		parent, err := store.Save(ctx, makeTestWorkflow("Parent", makeTestStep("true")), SaveOptions{})
+		if err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		nestedPath := filepath.Join(filepath.Dir(parent.Path), "child", "workflow.yaml")
+		child, err := store.Save(ctx, makeTestWorkflow("Child", makeTestStep("true")), SaveOptions{Path: nestedPath})
+		if err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+
+		if err := store.Delete(ctx, parent); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		if _, err := os.Stat(parent.Path); !os.IsNotExist(err) {
+			t.Error("workflow file still exists after delete")
+		}
+		if _, err := os.Stat(child.Path); err != nil {
+			t.Errorf("nested workflow removed: %v", err)
+		}
+	})
+
+	t.Run("removes one document", func(t *testing.T) {
+		dir := filepath.Join(tmpDir, cfg.Workflows.Root, cfg.Identity.Path, "pair")
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, "workflow.yaml")
+		data := "schema_version: 1\ntitle: First\nsteps:\n  - command: \"true\"\n---\nschema_version: 1\ntitle: Second\nsteps:\n  - command: \"false\"\n"
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := store.Delete(ctx, WorkflowRef{Path: path, Doc: 1}); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		wfs, err := workflows.LoadAll(path)
+		if err != nil {
+			t.Fatalf("LoadAll() error = %v", err)
+		}
+		if len(wfs) != 1 || wfs[0].Title != "Second" {
+			t.Errorf("file holds %+v, want only Second", wfs)
+		}
+	})
+}
+
+func TestFileSystemStore_ReadOnly(t *testing.T) {
+	_, repo, cfg := setupTestRepo(t)
+	store, err := New(repo, cfg)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+
+	ctx := context.Background()
+	ref, err := store.Save(ctx, makeTestWorkflow("Keep Me", makeTestStep("true")), SaveOptions{})
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	cfg.ReadOnly = true
+	if _, err := store.Save(ctx, makeTestWorkflow("New", makeTestStep("true")), SaveOptions{}); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Save() error = %v, want ErrReadOnly", err)
+	}
+	if err := store.Delete(ctx, ref); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Delete() error = %v, want ErrReadOnly", err)
+	}
+	if _, err := store.RegenerateReadme(ctx, ref); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("RegenerateReadme() error = %v, want ErrReadOnly", err)
+	}
+	if _, err := os.Stat(ref.Path); err != nil {
+		t.Errorf("workflow removed in read-only mode: %v", err)
+	}
 }
 
 func TestSlugify(t *testing.T) {
@@ -535,3 +851,186 @@ func TestGenerateUniqueSlug(t *testing.T) {
 		}
 	})
 }
+
+func TestFileSystemStore_MultiDocument(t *testing.T) {
+	tmpDir, repo, cfg := setupTestRepo(t)
+	s, err := New(repo, cfg)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	ctx := context.Background()
+
+	dir := filepath.Join(tmpDir, "workflows", "platform", "test", "restarts")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "workflow.yaml")
+	content := `title: Restart API
+steps:
+  - command: systemctl restart api
+---
+id: wf_worker
+title: Restart Worker
+steps:
+  - command: systemctl restart worker
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	refs, err := s.List(ctx, Filter{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(refs) != 2 || refs[0].Doc != 1 || refs[1].Doc != 2 || refs[1].ID != "wf_worker" {
+		t.Fatalf("List() = %+v, want one ref per document", refs)
+	}
+
+	ref, err := Resolve(ctx, s, tmpDir, cfg.Workflows.Root, "restarts#2")
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if ref.Location() != path+"#2" {
+		t.Errorf("Location() = %s, want %s#2", ref.Location(), path)
+	}
+	if _, err := Resolve(ctx, s, tmpDir, cfg.Workflows.Root, "restarts"); err == nil {
+		t.Error("Resolve(slug) of a multi-document file succeeded, want ambiguous")
+	}
+
+	worker, err := s.Load(ctx, ref)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if worker.Title != "Restart Worker" {
+		t.Fatalf("Load() title = %q", worker.Title)
+	}
+
+	// Updating one document keeps the other and writes no new directory
+	worker.Steps[0].Command = "systemctl restart worker --force"
+	saved, err := s.Save(ctx, worker, SaveOptions{})
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if saved.Path != path || saved.Doc != 2 {
+		t.Errorf("Save() = %s#%d, want %s#2", saved.Path, saved.Doc, path)
+	}
+
+	api, err := s.Load(ctx, refs[0])
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	api.Description = "Restarts the API"
+	if _, err := s.Save(ctx, api, SaveOptions{Path: path, Doc: 1}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	wfs, err := workflows.LoadAll(path)
+	if err != nil {
+		t.Fatalf("LoadAll() error = %v", err)
+	}
+	if len(wfs) != 2 || wfs[0].Description != "Restarts the API" || wfs[1].Steps[0].Command != "systemctl restart worker --force" {
+		t.Errorf("file holds %+v", wfs)
+	}
+
+	_, err = s.Save(ctx, makeTestWorkflow("Other", makeTestStep("true")), SaveOptions{Path: path})
+	if err == nil || !strings.Contains(err.Error(), "holds 2 workflows") {
+		t.Errorf("Save() without a document error = %v, want holds 2 workflows", err)
+	}
+}
+
+func TestFileSystemStore_Save_ScansSecrets(t *testing.T) {
+	_, repo, cfg := setupTestRepo(t)
+	cfg.Git.ScanSecrets = true
+	store, err := New(repo, cfg)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	ctx := context.Background()
+
+	wf := makeTestWorkflow("Connect DB", makeTestStep("mysql --password=hunter2 -h db"))
+	_, err = store.Save(ctx, wf, SaveOptions{Commit: true})
+	var secretsErr *SecretsError
+	if !errors.As(err, &secretsErr) {
+		t.Fatalf("Save() error = %v, want a SecretsError", err)
+	}
+	if len(secretsErr.Findings) != 1 || secretsErr.Findings[0].Kind != "password" {
+		t.Errorf("Findings = %v, want one password", secretsErr.Findings)
+	}
+	if strings.Contains(err.Error(), "hunter2") {
+		t.Errorf("error reveals the secret: %v", err)
+	}
+	if strings.HasPrefix(secretsErr.Path, "/") {
+		t.Errorf("Path = %s, want it relative to the repo", secretsErr.Path)
+	}
+	if len(repo.Commits()) != 0 {
+		t.Errorf("expected no commit, got %d", len(repo.Commits()))
+	}
+
+	// Placeholders are fine, and the override saves anyway
+	wf = makeTestWorkflow("Connect DB", makeTestStep("mysql --password=<password> -h db"))
+	if _, err := store.Save(ctx, wf, SaveOptions{}); err != nil {
+		t.Errorf("Save() with a placeholder error = %v", err)
+	}
+	wf = makeTestWorkflow("Connect DB Raw", makeTestStep("mysql --password=hunter2 -h db"))
+	if _, err := store.Save(ctx, wf, SaveOptions{AllowSecrets: true}); err != nil {
+		t.Errorf("Save() with AllowSecrets error = %v", err)
+	}
+}
+
+func TestFileSystemStore_Save_DetectsChangesSinceLoad(t *testing.T) {
+	_, repo, cfg := setupTestRepo(t)
+	store, err := New(repo, cfg)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	ctx := context.Background()
+
+	ref, err := store.Save(ctx, makeTestWorkflow("Deploy", makeTestStep("make deploy")), SaveOptions{})
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	wf, err := store.Load(ctx, ref)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	loaded := store.Loaded(ref.Path)
+	if loaded == nil {
+		t.Fatal("Loaded() = nil after Load")
+	}
+
+	// Someone else changes the file
+	theirs := []byte(strings.Replace(string(loaded.Content), "make deploy", "make deploy-all", 1))
+	if err := os.WriteFile(ref.Path, theirs, 0644); err != nil {
+		t.Fatalf("failed to write workflow: %v", err)
+	}
+
+	wf.Steps[0].Command = "make deploy-now"
+	_, err = store.Save(ctx, wf, SaveOptions{Path: ref.Path})
+	var conflictErr *ConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("Save() error = %v, want a ConflictError", err)
+	}
+	if string(conflictErr.Theirs) != string(theirs) || string(conflictErr.Base) != string(loaded.Content) {
+		t.Errorf("ConflictError versions = %q, %q", conflictErr.Base, conflictErr.Theirs)
+	}
+	if strings.HasPrefix(conflictErr.Path, "/") {
+		t.Errorf("Path = %s, want it relative to the repo", conflictErr.Path)
+	}
+	if data, _ := os.ReadFile(ref.Path); string(data) != string(theirs) {
+		t.Error("Save() overwrote the changed file")
+	}
+
+	// Saving on top of the current content, or forcing, overwrites it
+	if _, err := store.Save(ctx, wf, SaveOptions{Path: ref.Path, Base: NewLoaded(theirs)}); err != nil {
+		t.Errorf("Save() with the current Base error = %v", err)
+	}
+	if err := os.WriteFile(ref.Path, theirs, 0644); err != nil {
+		t.Fatalf("failed to write workflow: %v", err)
+	}
+	if _, err := store.Save(ctx, wf, SaveOptions{Path: ref.Path, Force: true}); err != nil {
+		t.Errorf("Save() with Force error = %v", err)
+	}
+	if data, _ := os.ReadFile(ref.Path); !strings.Contains(string(data), "make deploy-now") {
+		t.Errorf("saved workflow = %s, want the new command", data)
+	}
+}
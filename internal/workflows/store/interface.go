@@ -28,9 +28,40 @@ type SaveOptions struct {
 	// Commit creates a git commit after saving if true.
 	Commit bool
 
-	// Message is the commit message to use (defaults to auto-generated).
+	// Message is the commit message to use. It defaults to one generated
+	// from git.commit_template, with a body listing the changed steps.
 	Message string
 
-	// Force allows overwriting an existing workflow if true.
+	// Amend amends the last commit instead of creating a new one. Without
+	// a Message, the last commit's message is kept.
+	Amend bool
+
+	// Force allows overwriting an existing workflow if true, including
+	// one whose file changed since it was loaded.
 	Force bool
+
+	// Base is the workflow file's content as the workflow being saved was
+	// loaded. If the file differs from it, Save returns a *ConflictError
+	// rather than overwrite the changes. It defaults to what the store's
+	// own Load read; set it when saving through another store, e.g. one
+	// on a worktree, or after merging the changes.
+	Base *Loaded
+
+	// Path saves to an existing workflow.yaml path instead of deriving the
+	// location from the workflow's slug.
+	Path string
+
+	// Doc is the document of a multi-document file at Path to replace,
+	// as in WorkflowRef.Doc. A document with the workflow's ID is
+	// replaced regardless.
+	Doc int
+
+	// SourceDir is the directory the workflow's companion files (step
+	// scripts and files) are read from, such as the directory of an
+	// imported file. They are copied next to the saved workflow file.
+	SourceDir string
+
+	// AllowSecrets saves the workflow even if it appears to contain raw
+	// secrets while git.scan_secrets is set.
+	AllowSecrets bool
 }
@@ -0,0 +1,217 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// DefaultReadmeTemplatePath is the repo-relative README template used when
+// workflows.readme_template is unset and the file exists.
+const DefaultReadmeTemplatePath = ".svf/templates/README.md.tmpl"
+
+// builtinReadmeTemplate renders the README used when the repo has no
+// template of its own.
+const builtinReadmeTemplate = "# {{.Title}}\n\n" +
+	"{{with .Description}}{{.}}\n\n{{end}}" +
+	"{{if .Tags}}## Tags\n\n{{range .Tags}}- {{.}}\n{{end}}\n{{end}}" +
+	"{{if .Steps}}## Steps\n\n{{range $i, $step := .Steps}}### {{stepName $i $step}}\n\n```\n{{$step.Command}}\n```\n\n{{end}}{{end}}"
+
+// ReadmeData is the data passed to README templates. The workflow's fields
+// (Title, Description, Tags, Steps, Placeholders, Defaults, ...) are
+// available directly.
+type ReadmeData struct {
+	*workflows.Workflow
+
+	// Slug is the workflow's directory name.
+	Slug string
+
+	// Path is the repo-relative path to workflow.yaml.
+	Path string
+
+	// PlaceholderNames lists the placeholder names in sorted order.
+	PlaceholderNames []string
+
+	// StepsTable is a Markdown table of the steps.
+	StepsTable string
+
+	// PlaceholderDocs is a Markdown list documenting the placeholders.
+	PlaceholderDocs string
+}
+
+// readmeFuncs are the functions available to README templates.
+var readmeFuncs = template.FuncMap{
+	"join":     strings.Join,
+	"stepName": stepName,
+	"inc":      func(i int) int { return i + 1 },
+}
+
+// ParseReadmeTemplate parses a README template.
+func ParseReadmeTemplate(text string) (*template.Template, error) {
+	return template.New("README.md").Funcs(readmeFuncs).Parse(text)
+}
+
+// RenderReadme renders a workflow's README with tmpl, or the built-in
+// template if tmpl is nil. path is the repo-relative workflow.yaml path.
+func RenderReadme(tmpl *template.Template, wf *workflows.Workflow, path string) (string, error) {
+	if tmpl == nil {
+		tmpl = template.Must(ParseReadmeTemplate(builtinReadmeTemplate))
+	}
+
+	names := make([]string, 0, len(wf.Placeholders))
+	for name := range wf.Placeholders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	data := ReadmeData{
+		Workflow:         wf,
+		Slug:             filepath.Base(filepath.Dir(path)),
+		Path:             filepath.ToSlash(path),
+		PlaceholderNames: names,
+		StepsTable:       stepsTable(wf.Steps),
+		PlaceholderDocs:  placeholderDocs(wf.Placeholders, names),
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("rendering README template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// readmeTemplate loads the repo's README template, or returns nil to use
+// the built-in one.
+func (s *FileSystemStore) readmeTemplate() (*template.Template, error) {
+	path := s.config.Workflows.ReadmeTemplate
+	explicit := path != ""
+	if !explicit {
+		path = DefaultReadmeTemplatePath
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(s.repo.Path(), path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) && !explicit {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading README template: %w", err)
+	}
+
+	tmpl, err := ParseReadmeTemplate(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("parsing README template %s: %w", path, err)
+	}
+	return tmpl, nil
+}
+
+// writeReadme renders and writes README.md next to a workflow file, one
+// section after another for the workflows of a multi-document file. It
+// reports whether the file changed.
+func (s *FileSystemStore) writeReadme(workflowPath string, wfs ...*workflows.Workflow) (bool, error) {
+	tmpl, err := s.readmeTemplate()
+	if err != nil {
+		return false, err
+	}
+
+	rel, err := filepath.Rel(s.repo.Path(), workflowPath)
+	if err != nil {
+		rel = workflowPath
+	}
+	sections := make([]string, len(wfs))
+	for i, wf := range wfs {
+		if sections[i], err = RenderReadme(tmpl, wf, rel); err != nil {
+			return false, err
+		}
+	}
+	content := strings.Join(sections, "\n")
+
+	readmePath := filepath.Join(filepath.Dir(workflowPath), "README.md")
+	if existing, err := os.ReadFile(readmePath); err == nil && string(existing) == content {
+		return false, nil
+	}
+	if err := os.WriteFile(readmePath, []byte(content), 0644); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RegenerateReadme rewrites the README.md of an existing workflow from the
+// current template. It reports whether the file changed.
+func (s *FileSystemStore) RegenerateReadme(ctx context.Context, ref WorkflowRef) (bool, error) {
+	if err := s.checkWritable(); err != nil {
+		return false, err
+	}
+	if ref.Doc > 0 {
+		wfs, err := workflows.LoadAll(ref.Path)
+		if err != nil {
+			return false, err
+		}
+		return s.writeReadme(ref.Path, wfs...)
+	}
+	wf, err := s.Load(ctx, ref)
+	if err != nil {
+		return false, err
+	}
+	return s.writeReadme(ref.Path, wf)
+}
+
+// stepName returns a step's name, or "Step N" for unnamed steps.
+func stepName(i int, step workflows.Step) string {
+	if step.Name != "" {
+		return step.Name
+	}
+	return fmt.Sprintf("Step %d", i+1)
+}
+
+// stepsTable renders steps as a Markdown table.
+func stepsTable(steps []workflows.Step) string {
+	var b strings.Builder
+	b.WriteString("| # | Step | Command |\n|---|------|---------|\n")
+	for i, step := range steps {
+		fmt.Fprintf(&b, "| %d | %s | `%s` |\n", i+1, tableCell(stepName(i, step)), tableCell(step.Command))
+	}
+	return b.String()
+}
+
+// placeholderDocs renders placeholders as a Markdown list.
+func placeholderDocs(placeholders map[string]workflows.Placeholder, names []string) string {
+	var b strings.Builder
+	for _, name := range names {
+		p := placeholders[name]
+		fmt.Fprintf(&b, "- **`<%s>`**", name)
+		if p.Prompt != "" {
+			fmt.Fprintf(&b, ": %s", p.Prompt)
+		}
+		var notes []string
+		if p.Default != "" && !p.Secret {
+			notes = append(notes, fmt.Sprintf("default `%s`", p.Default))
+		}
+		if p.Validate != "" {
+			notes = append(notes, fmt.Sprintf("must match `%s`", p.Validate))
+		}
+		if p.Secret {
+			notes = append(notes, "secret")
+		}
+		if len(notes) > 0 {
+			fmt.Fprintf(&b, " (%s)", strings.Join(notes, ", "))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// tableCell escapes text for a Markdown table cell.
+func tableCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
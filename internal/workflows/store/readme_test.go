@@ -0,0 +1,116 @@
+package store
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+func TestRenderReadme_Builtin(t *testing.T) {
+	wf := &workflows.Workflow{
+		SchemaVersion: 1,
+		Title:         "Deploy",
+		Description:   "Ship it",
+		Tags:          []string{"ops", "prod"},
+		Steps: []workflows.Step{
+			{Name: "Build", Command: "make build"},
+			{Command: "make deploy"},
+		},
+	}
+
+	got, err := RenderReadme(nil, wf, "workflows/team/deploy/workflow.yaml")
+	if err != nil {
+		t.Fatalf("RenderReadme() error = %v", err)
+	}
+
+	want := "# Deploy\n\nShip it\n\n## Tags\n\n- ops\n- prod\n\n## Steps\n\n" +
+		"### Build\n\n```\nmake build\n```\n\n" +
+		"### Step 2\n\n```\nmake deploy\n```\n\n"
+	if got != want {
+		t.Errorf("RenderReadme() =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestRenderReadme_Custom(t *testing.T) {
+	wf := &workflows.Workflow{
+		SchemaVersion: 1,
+		Title:         "Rotate",
+		Steps:         []workflows.Step{{Name: "Rotate", Command: "rotate --env <env> | tee log"}},
+		Placeholders: map[string]workflows.Placeholder{
+			"env":   {Prompt: "Environment", Default: "staging"},
+			"token": {Prompt: "API token", Secret: true, Default: "hunter2"},
+		},
+	}
+
+	tmpl, err := ParseReadmeTemplate("{{.Slug}} ({{.Path}})\n{{.StepsTable}}{{.PlaceholderDocs}}{{join .PlaceholderNames \",\"}}")
+	if err != nil {
+		t.Fatalf("ParseReadmeTemplate() error = %v", err)
+	}
+	got, err := RenderReadme(tmpl, wf, "workflows/team/rotate/workflow.yaml")
+	if err != nil {
+		t.Fatalf("RenderReadme() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"rotate (workflows/team/rotate/workflow.yaml)",
+		"| 1 | Rotate | `rotate --env <env> \\| tee log` |",
+		"- **`<env>`**: Environment (default `staging`)",
+		"- **`<token>`**: API token (secret)",
+		"env,token",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("RenderReadme() missing %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "hunter2") {
+		t.Error("secret default must not be rendered")
+	}
+}
+
+func TestFileSystemStore_ReadmeTemplate(t *testing.T) {
+	tmpDir, repo, cfg := setupTestRepo(t)
+	ctx := context.Background()
+
+	store, err := New(repo, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, err := store.Save(ctx, makeTestWorkflow("Templated", makeTestStep("echo hi")), SaveOptions{})
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	readmePath := filepath.Join(filepath.Dir(ref.Path), "README.md")
+
+	// No changes without a template change
+	changed, err := store.RegenerateReadme(ctx, ref)
+	if err != nil || changed {
+		t.Fatalf("RegenerateReadme() = %v, %v; want false, nil", changed, err)
+	}
+
+	// Repo-level template is picked up
+	tmplPath := filepath.Join(tmpDir, DefaultReadmeTemplatePath)
+	if err := os.MkdirAll(filepath.Dir(tmplPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(tmplPath, []byte("Wiki: {{.Title}}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	changed, err = store.RegenerateReadme(ctx, ref)
+	if err != nil || !changed {
+		t.Fatalf("RegenerateReadme() = %v, %v; want true, nil", changed, err)
+	}
+	data, _ := os.ReadFile(readmePath)
+	if string(data) != "Wiki: Templated\n" {
+		t.Errorf("README = %q, want templated output", data)
+	}
+
+	// An explicitly configured template must exist
+	cfg.Workflows.ReadmeTemplate = "missing.tmpl"
+	if _, err := store.RegenerateReadme(ctx, ref); err == nil {
+		t.Error("RegenerateReadme() with a missing configured template should fail")
+	}
+}
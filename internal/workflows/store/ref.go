@@ -1,6 +1,9 @@
 package store
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // WorkflowRef is a lightweight reference to a workflow.
 type WorkflowRef struct {
@@ -10,13 +13,37 @@ type WorkflowRef struct {
 	// Slug is the URL-friendly identifier.
 	Slug string
 
+	// Aliases are short names the workflow can also be referenced by.
+	Aliases []string
+
+	// Title and Tags are the workflow's, for listing without loading it.
+	Title string
+	Tags  []string
+
 	// Path is the full path to the workflow.yaml file.
 	Path string
 
+	// Kind is index.KindWorkflow, or index.KindDraft or index.KindTemplate
+	// for drafts and templates.
+	Kind string
+
+	// Doc is the 1-based document holding the workflow in a
+	// multi-document file, or 0 if the file holds one workflow.
+	Doc int
+
 	// UpdatedAt is the last modification time.
 	UpdatedAt time.Time
 }
 
+// Location returns the workflow's path, with "#N" appended for document N
+// of a multi-document file.
+func (r WorkflowRef) Location() string {
+	if r.Doc > 0 {
+		return fmt.Sprintf("%s#%d", r.Path, r.Doc)
+	}
+	return r.Path
+}
+
 // Filter defines criteria for filtering workflows.
 type Filter struct {
 	// IdentityPath filters workflows by identity path (e.g., "platform/chaz").
@@ -27,4 +54,11 @@ type Filter struct {
 
 	// Search performs a text search across title and description.
 	Search string
+
+	// Verify lists from the workflow files themselves rather than the
+	// search index. It is slower but sees changes the index has missed.
+	Verify bool
+
+	// IncludeDrafts lists drafts and templates too.
+	IncludeDrafts bool
 }
@@ -0,0 +1,155 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	svferrors "github.com/chazuruo/svf/internal/errors"
+)
+
+// minIDPrefix is the shortest ID prefix accepted when resolving.
+const minIDPrefix = 6
+
+// ErrNotFound is returned when no workflow matches a reference.
+var ErrNotFound = fmt.Errorf("workflow %w", svferrors.ErrNotFound)
+
+// AmbiguousError is returned when a reference matches several workflows.
+type AmbiguousError struct {
+	Query   string
+	Matches []WorkflowRef
+}
+
+// Error implements error.
+func (e *AmbiguousError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%q matches %d workflows; use an ID or path instead:", e.Query, len(e.Matches))
+	for _, m := range e.Matches {
+		id := m.ID
+		if id == "" {
+			id = "(no id)"
+		}
+		fmt.Fprintf(&b, "\n  %s  %s", id, m.Location())
+	}
+	return b.String()
+}
+
+// Resolve finds the workflow a user refers to. query may be an ID (or a
+// unique prefix of one), an alias, a slug, or a path to the workflow directory or
+// workflow.yaml file, relative to the repo, the workflows root, or
+// absolute. A workflow in a multi-document file is named by appending "#N"
+// to its slug or path. Returns an error wrapping ErrNotFound when nothing matches and
+// an *AmbiguousError when several workflows match equally well.
+func Resolve(ctx context.Context, s Store, repoPath, workflowsRoot, query string) (WorkflowRef, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return WorkflowRef{}, fmt.Errorf("%w: empty reference", ErrNotFound)
+	}
+
+	// The listing may come from the saved index, which misses workflows
+	// added since it was built and keeps ones since moved or deleted; on a
+	// miss, or a match whose file is gone, the files themselves decide
+	refs, err := s.List(ctx, Filter{})
+	if err != nil {
+		return WorkflowRef{}, err
+	}
+	ref, err := resolveIn(refs, repoPath, workflowsRoot, query)
+	if errors.Is(err, ErrNotFound) || (err == nil && !fileExists(ref.Path)) {
+		if refs, err = s.List(ctx, Filter{Verify: true}); err != nil {
+			return WorkflowRef{}, err
+		}
+		ref, err = resolveIn(refs, repoPath, workflowsRoot, query)
+	}
+	return ref, err
+}
+
+// resolveIn finds the workflow query refers to among refs.
+func resolveIn(refs []WorkflowRef, repoPath, workflowsRoot, query string) (WorkflowRef, error) {
+	// Matchers in order of precedence; the first with any match wins
+	matchers := []func(WorkflowRef) bool{
+		// Exact ID
+		func(r WorkflowRef) bool { return r.ID != "" && r.ID == query },
+		// Path
+		func(r WorkflowRef) bool { return pathMatches(r, repoPath, workflowsRoot, query) },
+		// Alias
+		func(r WorkflowRef) bool { return slices.Contains(r.Aliases, query) },
+		// Slug, or "slug#N" for a document of a multi-document file
+		func(r WorkflowRef) bool {
+			return r.Slug == query || (r.Doc > 0 && fmt.Sprintf("%s#%d", r.Slug, r.Doc) == query)
+		},
+		// ID prefix
+		func(r WorkflowRef) bool {
+			return len(query) >= minIDPrefix && r.ID != "" && strings.HasPrefix(r.ID, query)
+		},
+	}
+
+	for _, match := range matchers {
+		var matches []WorkflowRef
+		for _, r := range refs {
+			if match(r) {
+				matches = append(matches, r)
+			}
+		}
+		switch len(matches) {
+		case 0:
+			continue
+		case 1:
+			return matches[0], nil
+		default:
+			sort.Slice(matches, func(i, j int) bool { return matches[i].Path < matches[j].Path })
+			return WorkflowRef{}, &AmbiguousError{Query: query, Matches: matches}
+		}
+	}
+
+	return WorkflowRef{}, fmt.Errorf("%w: %s", ErrNotFound, query)
+}
+
+// fileExists reports whether path exists.
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// pathMatches reports whether query names the workflow's file or directory.
+func pathMatches(r WorkflowRef, repoPath, workflowsRoot, query string) bool {
+	q := filepath.Clean(query)
+	file := filepath.Clean(r.Path)
+	dir := filepath.Dir(file)
+
+	candidates := []string{file, dir}
+	if rel, err := filepath.Rel(repoPath, dir); err == nil {
+		candidates = append(candidates, rel, filepath.Join(rel, filepath.Base(file)))
+		// Identity-relative form, e.g. "platform/alice/deploy"
+		if root := filepath.Clean(workflowsRoot); root != "." {
+			if inner, err := filepath.Rel(root, rel); err == nil && !strings.HasPrefix(inner, "..") {
+				candidates = append(candidates, inner)
+			}
+		}
+	}
+
+	// A document of a multi-document file is also named by "#N"
+	if r.Doc > 0 {
+		for _, c := range candidates {
+			candidates = append(candidates, fmt.Sprintf("%s#%d", c, r.Doc))
+		}
+	}
+
+	// Absolute or ./relative queries are resolved against the cwd
+	if filepath.IsAbs(q) || strings.HasPrefix(query, "./") || strings.HasPrefix(query, "../") {
+		if abs, err := filepath.Abs(q); err == nil {
+			q = abs
+		}
+	}
+
+	for _, c := range candidates {
+		if c == q {
+			return true
+		}
+	}
+	return false
+}
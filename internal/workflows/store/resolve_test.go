@@ -0,0 +1,214 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chazuruo/svf/internal/index"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// saveAs saves a workflow under the given identity path.
+func saveAs(t *testing.T, s *FileSystemStore, identity string, wf *workflows.Workflow) WorkflowRef {
+	t.Helper()
+	s.config.Identity.Path = identity
+	ref, err := s.Save(context.Background(), wf, SaveOptions{})
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	return ref
+}
+
+func TestResolve(t *testing.T) {
+	tmpDir, repo, cfg := setupTestRepo(t)
+	s, err := New(repo, cfg)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	ctx := context.Background()
+
+	alice := saveAs(t, s, "team/alice", makeTestWorkflow("Deploy", makeTestStep("make deploy")))
+	bob := saveAs(t, s, "team/bob", makeTestWorkflow("Deploy", makeTestStep("make deploy")))
+	backupWf := makeTestWorkflow("Backup DB", makeTestStep("pg_dump"))
+	backupWf.Aliases = []string{"nightly-dump"}
+	backup := saveAs(t, s, "team/bob", backupWf)
+
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{name: "id", query: backup.ID, want: backup.Path},
+		{name: "id prefix", query: backup.ID[:len(backup.ID)-4], want: backup.Path},
+		{name: "unique slug", query: "backup-db", want: backup.Path},
+		{name: "alias", query: "nightly-dump", want: backup.Path},
+		{name: "repo-relative dir", query: "workflows/team/alice/deploy", want: alice.Path},
+		{name: "repo-relative file", query: "workflows/team/bob/deploy/workflow.yaml", want: bob.Path},
+		{name: "identity-relative", query: "team/bob/deploy", want: bob.Path},
+		{name: "absolute dir", query: filepath.Join(tmpDir, "workflows", "team", "alice", "deploy"), want: alice.Path},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, err := Resolve(ctx, s, tmpDir, cfg.Workflows.Root, tt.query)
+			if err != nil {
+				t.Fatalf("Resolve(%q) error = %v", tt.query, err)
+			}
+			if ref.Path != tt.want {
+				t.Errorf("Resolve(%q) = %s, want %s", tt.query, ref.Path, tt.want)
+			}
+		})
+	}
+
+	t.Run("ambiguous slug", func(t *testing.T) {
+		_, err := Resolve(ctx, s, tmpDir, cfg.Workflows.Root, "deploy")
+		var amb *AmbiguousError
+		if !errors.As(err, &amb) {
+			t.Fatalf("Resolve() error = %v, want AmbiguousError", err)
+		}
+		if len(amb.Matches) != 2 {
+			t.Errorf("got %d matches, want 2", len(amb.Matches))
+		}
+		if !strings.Contains(err.Error(), alice.ID) || !strings.Contains(err.Error(), bob.ID) {
+			t.Errorf("error should list both IDs: %v", err)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := Resolve(ctx, s, tmpDir, cfg.Workflows.Root, "nope")
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("Resolve() error = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("short prefixes are not IDs", func(t *testing.T) {
+		_, err := Resolve(ctx, s, tmpDir, cfg.Workflows.Root, "wf_")
+		if err == nil {
+			t.Error("Resolve() should not match a too-short prefix")
+		}
+	})
+}
+
+func TestResolve_StaleIndex(t *testing.T) {
+	tmpDir, repo, cfg := setupTestRepo(t)
+	s, err := New(repo, cfg)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	ctx := context.Background()
+
+	moved := saveAs(t, s, "team/alice", makeTestWorkflow("Rotate Keys", makeTestStep("make rotate")))
+	if _, err := index.NewBuilder(tmpDir, cfg).Rebuild(); err != nil {
+		t.Fatalf("failed to build index: %v", err)
+	}
+
+	// A workflow added behind the index's back is still found
+	added := filepath.Join(tmpDir, "workflows", "team", "alice", "restart", "workflow.yaml")
+	if err := os.MkdirAll(filepath.Dir(added), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(added, []byte("schema_version: 1\nid: wf_restart\ntitle: Restart\nsteps:\n  - command: \"true\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ref, err := Resolve(ctx, s, tmpDir, cfg.Workflows.Root, "restart")
+	if err != nil {
+		t.Fatalf("Resolve() of a workflow added after indexing error = %v", err)
+	}
+	if ref.Path != added || ref.ID != "wf_restart" {
+		t.Errorf("Resolve() = %s (%s), want %s (wf_restart)", ref.Path, ref.ID, added)
+	}
+
+	// A workflow moved behind the index's back resolves to its new path
+	newPath := filepath.Join(tmpDir, "workflows", "team", "bob", "rotate-keys", "workflow.yaml")
+	if err := os.MkdirAll(filepath.Dir(filepath.Dir(newPath)), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(filepath.Dir(moved.Path), filepath.Dir(newPath)); err != nil {
+		t.Fatal(err)
+	}
+	ref, err = Resolve(ctx, s, tmpDir, cfg.Workflows.Root, moved.ID)
+	if err != nil {
+		t.Fatalf("Resolve() of a moved workflow error = %v", err)
+	}
+	if ref.Path != newPath {
+		t.Errorf("Resolve() = %s, want %s", ref.Path, newPath)
+	}
+}
+
+func TestFileSystemStore_Save_StableIdentity(t *testing.T) {
+	_, repo, cfg := setupTestRepo(t)
+	s, err := New(repo, cfg)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	ctx := context.Background()
+
+	wf := makeTestWorkflow("Restart Service", makeTestStep("systemctl restart app"))
+	ref, err := s.Save(ctx, wf, SaveOptions{})
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if !workflows.IsGeneratedID(wf.ID) || ref.ID != wf.ID {
+		t.Fatalf("Save() should assign an ID, got %q (ref %q)", wf.ID, ref.ID)
+	}
+	if ref.Slug != "restart-service" {
+		t.Errorf("Slug = %s, want restart-service", ref.Slug)
+	}
+
+	// Renaming keeps the workflow where it is
+	wf.Title = "Restart Service Safely"
+	renamed, err := s.Save(ctx, wf, SaveOptions{})
+	if err != nil {
+		t.Fatalf("Save() after rename error = %v", err)
+	}
+	if renamed.Path != ref.Path || renamed.ID != ref.ID {
+		t.Errorf("renamed workflow moved: %s -> %s", ref.Path, renamed.Path)
+	}
+
+	refs, err := s.List(ctx, Filter{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(refs) != 1 || refs[0].ID != wf.ID {
+		t.Errorf("List() = %+v, want one workflow with ID %s", refs, wf.ID)
+	}
+}
+
+func TestFileSystemStore_Save_InPlace(t *testing.T) {
+	_, repo, cfg := setupTestRepo(t)
+	s, err := New(repo, cfg)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	ctx := context.Background()
+
+	// A legacy workflow without an ID in a directory not matching its title
+	dir := filepath.Join(repo.Path(), "workflows", "platform", "test", "legacy-name")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := workflows.MarshalWorkflow(makeTestWorkflow("Something Else", makeTestStep("true")))
+	path := filepath.Join(dir, "workflow.yaml")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	wf, err := s.Load(ctx, WorkflowRef{Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, err := s.Save(ctx, wf, SaveOptions{Path: path})
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if ref.Path != path || ref.Slug != "legacy-name" {
+		t.Errorf("Save() = %s (%s), want in place at %s", ref.Path, ref.Slug, path)
+	}
+	if saved, err := workflows.Load(path); err != nil || saved.ID != wf.ID || wf.ID == "" {
+		t.Errorf("saved file has ID %v (%v), want %q", saved, err, wf.ID)
+	}
+}
@@ -0,0 +1,185 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+// ErrReadOnly is returned when saving to or deleting from a read-only
+// store: a RevisionStore, or a FileSystemStore with readonly set in the
+// config.
+var ErrReadOnly = errors.New("read-only")
+
+var errRevisionReadOnly = fmt.Errorf("workflows at a revision are %w", ErrReadOnly)
+
+// RevisionStore is a read-only Store of the workflows as of a git
+// revision, read from the object store so the working copy is neither
+// read nor touched. Refs have the paths the files would have in the
+// working copy, so Resolve matches them as it does the working copy's.
+type RevisionStore struct {
+	repo   gitrepo.Repo
+	config *config.Config
+	rev    string
+}
+
+// NewRevisionStore returns a store of the workflows at rev, which may be
+// a commit, branch or tag.
+func NewRevisionStore(ctx context.Context, repo gitrepo.Repo, cfg *config.Config, rev string) (*RevisionStore, error) {
+	if _, err := repo.ResolveRev(ctx, rev); err != nil {
+		return nil, fmt.Errorf("unknown revision %q: %w", rev, err)
+	}
+	return &RevisionStore{repo: repo, config: cfg, rev: rev}, nil
+}
+
+// Rev returns the revision the store reads.
+func (s *RevisionStore) Rev() string {
+	return s.rev
+}
+
+// List returns the workflows at the revision matching the filter.
+func (s *RevisionStore) List(ctx context.Context, filter Filter) ([]WorkflowRef, error) {
+	files, err := s.repo.ListFiles(ctx, s.rev, s.config.Workflows.Root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workflows at %s: %w", s.rev, err)
+	}
+
+	refs := []WorkflowRef{}
+	for _, file := range files {
+		if !workflows.IsWorkflowFile(filepath.Base(file)) {
+			continue
+		}
+		fileRefs, err := s.fileRefs(ctx, file)
+		if err != nil {
+			return nil, err
+		}
+		for _, ref := range fileRefs {
+			if s.matchesFilter(ref, filter, file) {
+				refs = append(refs, ref)
+			}
+		}
+	}
+	return refs, nil
+}
+
+// fileRefs returns a ref for each workflow in the repo-relative file.
+func (s *RevisionStore) fileRefs(ctx context.Context, file string) ([]WorkflowRef, error) {
+	ref := WorkflowRef{
+		Slug: filepath.Base(filepath.Dir(filepath.FromSlash(file))),
+		Path: filepath.Join(s.repo.Path(), filepath.FromSlash(file)),
+	}
+
+	// Files that can't be parsed are still listed, without identifiers
+	wfs, err := s.readAll(ctx, file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if err != nil {
+		return []WorkflowRef{ref}, nil
+	}
+	if len(wfs) == 1 {
+		ref.ID, ref.Aliases = wfs[0].ID, wfs[0].Aliases
+		ref.Title, ref.Tags = wfs[0].Title, wfs[0].Tags
+		return []WorkflowRef{ref}, nil
+	}
+
+	refs := make([]WorkflowRef, len(wfs))
+	for i, wf := range wfs {
+		refs[i] = ref
+		refs[i].ID, refs[i].Aliases, refs[i].Doc = wf.ID, wf.Aliases, i+1
+		refs[i].Title, refs[i].Tags = wf.Title, wf.Tags
+	}
+	return refs, nil
+}
+
+// matchesFilter reports whether a ref passes the identity and tag filters.
+func (s *RevisionStore) matchesFilter(ref WorkflowRef, filter Filter, file string) bool {
+	if filter.IdentityPath != "" {
+		root := filepath.ToSlash(filepath.Clean(s.config.Workflows.Root))
+		if !strings.HasPrefix(file, root+"/"+filter.IdentityPath+"/") {
+			return false
+		}
+	}
+	for _, tag := range filter.Tags {
+		if !slices.Contains(ref.Tags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// Load reads a workflow as of the revision.
+func (s *RevisionStore) Load(ctx context.Context, ref WorkflowRef) (*workflows.Workflow, error) {
+	file, err := s.RelPath(ref)
+	if err != nil {
+		return nil, err
+	}
+	wfs, err := s.readAll(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+	if ref.Doc == 0 {
+		if len(wfs) != 1 {
+			return nil, fmt.Errorf("%s has %d workflows at %s; name one with #N", file, len(wfs), s.rev)
+		}
+		return wfs[0], nil
+	}
+	if ref.Doc > len(wfs) {
+		return nil, fmt.Errorf("%s has %d workflows at %s, no document %d", file, len(wfs), s.rev, ref.Doc)
+	}
+	return wfs[ref.Doc-1], nil
+}
+
+// ReadFile returns the content of a file relative to a workflow's
+// directory, such as a companion file, as of the revision.
+func (s *RevisionStore) ReadFile(ctx context.Context, ref WorkflowRef, name string) ([]byte, error) {
+	file, err := s.RelPath(ref)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ShowFile(ctx, s.rev, path.Join(path.Dir(file), filepath.ToSlash(name)))
+}
+
+// RelPath returns a ref's repo-relative, slash-separated path.
+func (s *RevisionStore) RelPath(ref WorkflowRef) (string, error) {
+	rel, err := filepath.Rel(s.repo.Path(), ref.Path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("%s is outside the repository", ref.Path)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// readAll parses the workflows in a repo-relative file at the revision.
+func (s *RevisionStore) readAll(ctx context.Context, file string) ([]*workflows.Workflow, error) {
+	data, err := s.repo.ShowFile(ctx, s.rev, file)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%s did not exist at %s: %w", file, s.rev, err)
+		}
+		return nil, fmt.Errorf("failed to read %s at %s: %w", file, s.rev, err)
+	}
+	wfs, err := workflows.UnmarshalWorkflows(data, workflows.FormatForPath(file))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s at %s: %w", file, s.rev, err)
+	}
+	return wfs, nil
+}
+
+// Save fails: the revision can't be changed.
+func (s *RevisionStore) Save(ctx context.Context, wf *workflows.Workflow, opts SaveOptions) (WorkflowRef, error) {
+	return WorkflowRef{}, errRevisionReadOnly
+}
+
+// Delete fails: the revision can't be changed.
+func (s *RevisionStore) Delete(ctx context.Context, ref WorkflowRef) error {
+	return errRevisionReadOnly
+}
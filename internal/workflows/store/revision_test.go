@@ -0,0 +1,88 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/testutil"
+	"github.com/chazuruo/svf/internal/workflows"
+)
+
+func TestRevisionStore(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.DefaultConfig()
+	cfg.Repo.Path = t.TempDir()
+	repo := testutil.NewFakeRepo(cfg.Repo.Path)
+
+	write := func(path, content string) {
+		t.Helper()
+		full := filepath.Join(cfg.Repo.Path, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("workflows/ops/deploy/workflow.yaml", "id: wf_deploy\ntitle: Deploy\ntags: [k8s]\nsteps:\n  - command: deploy\n")
+	write("workflows/dev/pair/workflow.yaml", "title: Up\nsteps:\n  - command: up\n---\ntitle: Down\nsteps:\n  - command: down\n")
+	write("notes.txt", "not a workflow\n")
+	if err := repo.AddAll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.CommitAll(ctx, "add workflows"); err != nil {
+		t.Fatal(err)
+	}
+	// Later working copy changes aren't seen
+	write("workflows/ops/deploy/workflow.yaml", "id: wf_deploy\ntitle: Changed\nsteps:\n  - command: changed\n")
+
+	if _, err := NewRevisionStore(ctx, repo, cfg, "v9"); err == nil {
+		t.Error("NewRevisionStore() with an unknown revision should fail")
+	}
+	s, err := NewRevisionStore(ctx, repo, cfg, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refs, err := s.List(ctx, Filter{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(refs) != 3 {
+		t.Fatalf("List() = %+v, want 3 refs", refs)
+	}
+	if tagged, _ := s.List(ctx, Filter{Tags: []string{"k8s"}}); len(tagged) != 1 || tagged[0].ID != "wf_deploy" {
+		t.Errorf("List(k8s) = %+v, want the deploy workflow", tagged)
+	}
+	if mine, _ := s.List(ctx, Filter{IdentityPath: "dev"}); len(mine) != 2 || mine[1].Doc != 2 {
+		t.Errorf("List(dev) = %+v, want both documents of pair", mine)
+	}
+
+	ref, err := Resolve(ctx, s, cfg.Repo.Path, cfg.Workflows.Root, "deploy")
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	wf, err := s.Load(ctx, ref)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if wf.Title != "Deploy" || wf.Steps[0].Command != "deploy" {
+		t.Errorf("Load() = %s %q, want the committed workflow", wf.Title, wf.Steps[0].Command)
+	}
+
+	ref, err = Resolve(ctx, s, cfg.Repo.Path, cfg.Workflows.Root, "pair#2")
+	if err != nil {
+		t.Fatalf("Resolve(pair#2) error = %v", err)
+	}
+	if wf, err := s.Load(ctx, ref); err != nil || wf.Title != "Down" {
+		t.Errorf("Load(pair#2) = %v, %v; want Down", wf, err)
+	}
+
+	if _, err := s.Save(ctx, &workflows.Workflow{Title: "New"}, SaveOptions{}); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Save() error = %v, want ErrReadOnly", err)
+	}
+}
@@ -0,0 +1,36 @@
+package store
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/chazuruo/svf/internal/redact"
+)
+
+// SecretsError is returned by Save when git.scan_secrets is set and the
+// workflow appears to contain raw secrets.
+type SecretsError struct {
+	// Path is the workflow file, relative to the repo when inside it.
+	Path     string
+	Findings []redact.Finding
+}
+
+func (e *SecretsError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "refusing to save %s: it appears to contain raw secrets:", e.Path)
+	for _, f := range e.Findings {
+		fmt.Fprintf(&b, "\n  %s", f)
+	}
+	b.WriteString("\nReplace them with <placeholders> or environment variables, or save anyway with --allow-secrets")
+	return b.String()
+}
+
+// relPath returns path relative to the repo, or unchanged outside it.
+func (s *FileSystemStore) relPath(path string) string {
+	rel, err := filepath.Rel(s.repo.Path(), path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return rel
+}
@@ -0,0 +1,123 @@
+package workflows
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TestsFile is the name of the file next to workflow.yaml that can hold
+// test cases instead of the workflow's tests section.
+const TestsFile = "tests.yaml"
+
+// TestCase runs a workflow against canned step outputs so its placeholder
+// substitution, captures and failure handling can be checked without
+// running anything.
+type TestCase struct {
+	Name   string              `yaml:"name"`             // Required
+	Params map[string]string   `yaml:"params,omitempty"` // Placeholder values, as with --param
+	Preset string              `yaml:"preset,omitempty"` // Preset to apply, as with --preset
+	Steps  map[string]StepMock `yaml:"steps,omitempty"`  // Mocks keyed by step name or 1-based number
+	Expect TestExpect          `yaml:"expect,omitempty"`
+}
+
+// StepMock is what a step does in a test. Steps without a mock succeed
+// with no output.
+type StepMock struct {
+	Stdout   string `yaml:"stdout,omitempty"`    // Output the step's captures read
+	ExitCode int    `yaml:"exit_code,omitempty"` // Non-zero fails the step
+	Command  string `yaml:"command,omitempty"`   // Expected command after substitution
+}
+
+// TestExpect is the outcome a test case expects.
+type TestExpect struct {
+	Status     string            `yaml:"status,omitempty"`      // "success" (default) or "failure"
+	FailedStep string            `yaml:"failed_step,omitempty"` // Step name or 1-based number that fails
+	Values     map[string]string `yaml:"values,omitempty"`      // Placeholder values after the run, including captures
+}
+
+// Test statuses.
+const (
+	TestSuccess = "success"
+	TestFailure = "failure"
+)
+
+// testsFile is the layout of a tests.yaml file.
+type testsFile struct {
+	Tests []TestCase `yaml:"tests"`
+}
+
+// ParseTests parses the test cases of a tests.yaml file.
+func ParseTests(data []byte) ([]TestCase, error) {
+	var f testsFile
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse tests: %w", err)
+	}
+	return f.Tests, nil
+}
+
+// StepIndex returns the index of the step a test refers to, by 1-based
+// number or by name, or -1 if there is no such step.
+func (w *Workflow) StepIndex(ref string) int {
+	if n, err := strconv.Atoi(ref); err == nil {
+		if n >= 1 && n <= len(w.Steps) {
+			return n - 1
+		}
+		return -1
+	}
+	for i, step := range w.Steps {
+		if step.Name == ref {
+			return i
+		}
+	}
+	return -1
+}
+
+// ValidateTests checks that test cases are named uniquely and refer to
+// steps and presets the workflow has.
+func (w *Workflow) ValidateTests(tests []TestCase) error {
+	seen := make(map[string]bool)
+	for i, tc := range tests {
+		if tc.Name == "" {
+			return fmt.Errorf("test %d: name is required", i+1)
+		}
+		if seen[tc.Name] {
+			return fmt.Errorf("duplicate test name %q", tc.Name)
+		}
+		seen[tc.Name] = true
+
+		refs := make([]string, 0, len(tc.Steps))
+		for ref := range tc.Steps {
+			refs = append(refs, ref)
+		}
+		sort.Strings(refs)
+		for _, ref := range refs {
+			if w.StepIndex(ref) < 0 {
+				return fmt.Errorf("test %s: unknown step %q", tc.Name, ref)
+			}
+		}
+
+		if tc.Preset != "" {
+			if _, ok := w.Presets[tc.Preset]; !ok {
+				return fmt.Errorf("test %s: unknown preset %q", tc.Name, tc.Preset)
+			}
+		}
+
+		switch tc.Expect.Status {
+		case "", TestSuccess, TestFailure:
+		default:
+			return fmt.Errorf("test %s: invalid status %q (use %s or %s)", tc.Name, tc.Expect.Status, TestSuccess, TestFailure)
+		}
+		if ref := tc.Expect.FailedStep; ref != "" {
+			if w.StepIndex(ref) < 0 {
+				return fmt.Errorf("test %s: unknown failed_step %q", tc.Name, ref)
+			}
+			if tc.Expect.Status == TestSuccess {
+				return fmt.Errorf("test %s: failed_step requires status %s", tc.Name, TestFailure)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package workflows
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTests(t *testing.T) {
+	data := []byte(`tests:
+  - name: happy path
+    params:
+      env: staging
+    steps:
+      "1":
+        stdout: '{"id": "abc"}'
+      deploy:
+        exit_code: 2
+    expect:
+      status: failure
+      failed_step: deploy
+`)
+
+	tests, err := ParseTests(data)
+	require.NoError(t, err)
+	require.Len(t, tests, 1)
+	assert.Equal(t, "happy path", tests[0].Name)
+	assert.Equal(t, map[string]string{"env": "staging"}, tests[0].Params)
+	assert.Equal(t, `{"id": "abc"}`, tests[0].Steps["1"].Stdout)
+	assert.Equal(t, 2, tests[0].Steps["deploy"].ExitCode)
+	assert.Equal(t, TestExpect{Status: TestFailure, FailedStep: "deploy"}, tests[0].Expect)
+
+	_, err = ParseTests([]byte("tests: ["))
+	assert.Error(t, err)
+}
+
+func TestStepIndex(t *testing.T) {
+	wf := &Workflow{Steps: []Step{{Name: "build"}, {Name: "deploy"}}}
+
+	assert.Equal(t, 0, wf.StepIndex("1"))
+	assert.Equal(t, 1, wf.StepIndex("deploy"))
+	assert.Equal(t, -1, wf.StepIndex("3"))
+	assert.Equal(t, -1, wf.StepIndex("0"))
+	assert.Equal(t, -1, wf.StepIndex("test"))
+}
+
+func TestValidateTests(t *testing.T) {
+	wf := &Workflow{
+		Title:   "Deploy",
+		Steps:   []Step{{Name: "deploy", Command: "echo"}},
+		Presets: map[string]map[string]string{"prod": {"env": "prod"}},
+	}
+
+	assert.NoError(t, wf.ValidateTests([]TestCase{{Name: "ok", Preset: "prod", Steps: map[string]StepMock{"deploy": {}}}}))
+
+	tests := []struct {
+		name  string
+		tests []TestCase
+		want  string
+	}{
+		{"missing name", []TestCase{{}}, "test 1: name is required"},
+		{"duplicate", []TestCase{{Name: "a"}, {Name: "a"}}, `duplicate test name "a"`},
+		{"unknown step", []TestCase{{Name: "a", Steps: map[string]StepMock{"2": {}}}}, `test a: unknown step "2"`},
+		{"unknown preset", []TestCase{{Name: "a", Preset: "qa"}}, `test a: unknown preset "qa"`},
+		{"bad status", []TestCase{{Name: "a", Expect: TestExpect{Status: "passed"}}}, `test a: invalid status "passed" (use success or failure)`},
+		{"failed step on success", []TestCase{{Name: "a", Expect: TestExpect{Status: TestSuccess, FailedStep: "1"}}}, "test a: failed_step requires status failure"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.EqualError(t, wf.ValidateTests(tt.tests), tt.want)
+		})
+	}
+}
@@ -3,9 +3,13 @@ package workflows
 import (
 	"errors"
 	"fmt"
+	"path"
 	"regexp"
+	"time"
 
 	"gopkg.in/yaml.v3"
+
+	svferrors "github.com/chazuruo/svf/internal/errors"
 )
 
 // SchemaVersion is the current workflow schema version
@@ -18,9 +22,37 @@ type Workflow struct {
 	Title         string                   `yaml:"title"`                   // Required
 	Description   string                   `yaml:"description,omitempty"`
 	Tags          []string                 `yaml:"tags,omitempty"`
+	Aliases       []string                 `yaml:"aliases,omitempty"`       // Short names to reference the workflow by
 	Defaults      Defaults                 `yaml:"defaults,omitempty"`
 	Placeholders  map[string]Placeholder   `yaml:"placeholders,omitempty"`
 	Steps         []Step                   `yaml:"steps"`
+	Cleanup       []Step                   `yaml:"cleanup,omitempty"`        // Run after the steps when a run fails or is aborted
+	Matrix        map[string][]string      `yaml:"matrix,omitempty"`         // Placeholder value lists; runs once per combination
+	Presets       map[string]map[string]string `yaml:"presets,omitempty"`    // Named sets of placeholder values, chosen with --preset
+	Tests         []TestCase               `yaml:"tests,omitempty"`          // Mocked runs checked by svf test
+	Next          string                   `yaml:"next,omitempty"`           // Workflow to run after this one, by ID, alias, slug or path
+	KubeContext   string                   `yaml:"kube_context,omitempty"`   // kubectl context the steps must run against
+	KubeNamespace string                   `yaml:"kube_namespace,omitempty"` // kubectl namespace the steps must run against
+	AWSProfile    string                   `yaml:"aws_profile,omitempty"`    // AWS_PROFILE the steps must run with
+	AWSAccount    string                   `yaml:"aws_account,omitempty"`    // AWS account ID the steps must run against
+	GCPProject    string                   `yaml:"gcp_project,omitempty"`    // gcloud project the steps must run against
+	Requires      []Requirement            `yaml:"requires,omitempty"`       // Commands the steps need on the PATH
+	Exclusive     bool                     `yaml:"exclusive,omitempty"`      // Only one run at a time; svf run takes a lock first
+	Metadata      *Metadata                `yaml:"metadata,omitempty"`       // Environment the workflow was created in
+	Owners        []string                 `yaml:"owners,omitempty"`        // Identity paths allowed to approve reviews
+	ReviewedBy    string                   `yaml:"reviewed_by,omitempty"`   // Identity path of the last approver
+	ReviewedAt    time.Time                `yaml:"reviewed_at,omitempty"`   // Time of the last approval
+	ReviewedHash  string                   `yaml:"reviewed_hash,omitempty"` // ContentHash at the last approval
+}
+
+// Metadata records the environment a workflow was recorded or generated
+// in, for runbooks that only work with specific tool versions.
+type Metadata struct {
+	OS         string            `yaml:"os,omitempty"`
+	Arch       string            `yaml:"arch,omitempty"`
+	Shell      string            `yaml:"shell,omitempty"`
+	Tools      map[string]string `yaml:"tools,omitempty"` // Version output by tool name
+	CapturedAt time.Time         `yaml:"captured_at,omitempty"`
 }
 
 // Defaults specifies default values for workflow steps
@@ -33,12 +65,30 @@ type Defaults struct {
 // Step represents a single step in a workflow
 type Step struct {
 	Name            string            `yaml:"name,omitempty"`            // Step name/identifier
-	Command         string            `yaml:"command"`                   // Required command to execute
+	Command         string            `yaml:"command,omitempty"`         // Command to execute (required unless script is set)
+	Script          string            `yaml:"script,omitempty"`          // Companion script to run instead, relative to the workflow file
+	Files           map[string]string `yaml:"files,omitempty"`           // Companion files by placeholder name, relative to the workflow file
 	Shell           string            `yaml:"shell,omitempty"`           // Override default shell
 	CWD             string            `yaml:"cwd,omitempty"`             // Override default working directory
 	Env             map[string]string `yaml:"env,omitempty"`             // Environment variables
 	ContinueOnError bool              `yaml:"continue_on_error,omitempty"` // Continue if this step fails
 	Confirmation    *StepConfirmation `yaml:"confirmation,omitempty"`    // Confirmation prompt
+	Capture         map[string]Extractor `yaml:"capture,omitempty"`      // Values to extract from stdout into placeholders
+	Platforms       []string          `yaml:"platforms,omitempty"`       // OS, arch or OS/arch the step runs on (default: all)
+	Links           []Link            `yaml:"links,omitempty"`           // Dashboards and docs to check while running the step
+	MaxDuration     time.Duration     `yaml:"max_duration,omitempty"`    // How long the step normally takes at most, e.g. 30s; the runner warns past it
+	Provenance      *Provenance       `yaml:"provenance,omitempty"`      // Where a recorded command was run
+}
+
+// Provenance records where and how a step's command ran when it was
+// recorded, from shell history or the record hooks. Fields the source
+// didn't record are left empty.
+type Provenance struct {
+	RanAt      time.Time `yaml:"ran_at,omitempty"`
+	Host       string    `yaml:"host,omitempty"`
+	Session    string    `yaml:"session,omitempty"`     // tmux session name
+	Duration   int64     `yaml:"duration,omitempty"`    // Seconds
+	ExitStatus *int      `yaml:"exit_status,omitempty"`
 }
 
 // StepConfirmation defines the confirmation behavior for a step
@@ -83,8 +133,16 @@ type Placeholder struct {
 	Secret   bool   `yaml:"secret,omitempty"`   // Mask value in output
 }
 
-// Validate validates the workflow structure and content
+// Validate validates the workflow structure and content. Errors are
+// *svferrors.ValidationError.
 func (w *Workflow) Validate() error {
+	if err := w.validate(); err != nil {
+		return &svferrors.ValidationError{Err: err}
+	}
+	return nil
+}
+
+func (w *Workflow) validate() error {
 	// Title is required
 	if w.Title == "" {
 		return errors.New("workflow title is required")
@@ -101,6 +159,11 @@ func (w *Workflow) Validate() error {
 			return fmt.Errorf("step %d: %w", i, err)
 		}
 	}
+	for i, step := range w.Cleanup {
+		if err := step.Validate(); err != nil {
+			return fmt.Errorf("cleanup step %d: %w", i, err)
+		}
+	}
 
 	// Validate placeholders
 	for name, ph := range w.Placeholders {
@@ -109,15 +172,89 @@ func (w *Workflow) Validate() error {
 		}
 	}
 
+	if err := validateMatrix(w.Matrix); err != nil {
+		return err
+	}
+	if err := validatePresets(w.Presets, w.Placeholders); err != nil {
+		return err
+	}
+	if err := w.ValidateTests(w.Tests); err != nil {
+		return err
+	}
+
+	if err := validateRequires(w.Requires); err != nil {
+		return err
+	}
+
+	if w.Next != "" && w.Next == w.ID {
+		return errors.New("next must name another workflow, not this one")
+	}
+
+	// Validate aliases
+	for _, alias := range w.Aliases {
+		if err := ValidateAlias(alias); err != nil {
+			return err
+		}
+	}
+
+	// Validate Kubernetes guardrails
+	if _, err := path.Match(w.KubeContext, ""); err != nil {
+		return fmt.Errorf("invalid kube_context pattern %q: %w", w.KubeContext, err)
+	}
+	if _, err := path.Match(w.KubeNamespace, ""); err != nil {
+		return fmt.Errorf("invalid kube_namespace pattern %q: %w", w.KubeNamespace, err)
+	}
+
+	if w.AWSAccount != "" && !awsAccountRegex.MatchString(w.AWSAccount) {
+		return fmt.Errorf("invalid aws_account %q: use the 12-digit account ID", w.AWSAccount)
+	}
+
+	return nil
+}
+
+// awsAccountRegex matches AWS account IDs.
+var awsAccountRegex = regexp.MustCompile(`^[0-9]{12}$`)
+
+// aliasRegex matches valid aliases: lowercase words joined by hyphens.
+var aliasRegex = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// ValidateAlias checks that an alias is a lowercase, hyphenated name like
+// "db-failover", so it can't be mistaken for a path.
+func ValidateAlias(alias string) error {
+	if !aliasRegex.MatchString(alias) {
+		return fmt.Errorf("invalid alias %q: use lowercase letters, digits and hyphens", alias)
+	}
 	return nil
 }
 
+// HasAlias reports whether alias is one of the workflow's aliases.
+func (w *Workflow) HasAlias(alias string) bool {
+	for _, a := range w.Aliases {
+		if a == alias {
+			return true
+		}
+	}
+	return false
+}
+
 // Validate validates a step
 func (s *Step) Validate() error {
-	if s.Command == "" {
+	if s.Command == "" && s.Script == "" {
 		return errors.New("step command is required")
 	}
-	return nil
+	if err := validateCompanions(s); err != nil {
+		return err
+	}
+	if err := validatePlatforms(s.Platforms); err != nil {
+		return err
+	}
+	if err := validateLinks(s.Links); err != nil {
+		return err
+	}
+	if s.MaxDuration < 0 {
+		return fmt.Errorf("max_duration must be positive; got %s", s.MaxDuration)
+	}
+	return validateCaptures(s.Capture)
 }
 
 // ValidatePlaceholder validates a placeholder's regex pattern
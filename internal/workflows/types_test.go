@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -32,6 +33,31 @@ steps:
 	assert.Equal(t, `echo "Hello, World!"`, wf.Steps[0].Command)
 }
 
+func TestUnmarshalWorkflow_Metadata(t *testing.T) {
+	data := []byte(`
+schema_version: 1
+title: Apply
+steps:
+  - command: terraform apply
+metadata:
+  os: linux
+  shell: bash
+  tools:
+    terraform: Terraform v1.6.2
+  captured_at: 2026-10-16T09:00:00Z
+`)
+
+	wf, err := UnmarshalWorkflow(data)
+	require.NoError(t, err)
+	require.NotNil(t, wf.Metadata)
+	assert.Equal(t, "linux", wf.Metadata.OS)
+	assert.Equal(t, "Terraform v1.6.2", wf.Metadata.Tools["terraform"])
+
+	out, err := MarshalWorkflow(wf)
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "terraform: Terraform v1.6.2")
+}
+
 func TestUnmarshalWorkflow_ValidWithPlaceholders(t *testing.T) {
 	data := []byte(`
 schema_version: 1
@@ -545,3 +571,99 @@ func jsonMarshalWorkflow(wf *Workflow) ([]byte, error) {
 	// Use encoding/json for stable output
 	return json.MarshalIndent(wf, "", "  ")
 }
+
+func TestValidate_Aliases(t *testing.T) {
+	wf := &Workflow{Title: "T", Steps: []Step{{Command: "true"}}, Aliases: []string{"db-failover", "db2"}}
+	assert.NoError(t, wf.Validate())
+	assert.True(t, wf.HasAlias("db2"))
+
+	for _, alias := range []string{"", "DB", "db/failover", "-db", "db failover"} {
+		wf.Aliases = []string{alias}
+		assert.Error(t, wf.Validate(), "alias %q", alias)
+	}
+}
+
+func TestValidate_Next(t *testing.T) {
+	wf := &Workflow{ID: "wf_01HV3K8Q0000000000000000AA", Title: "T", Steps: []Step{{Command: "true"}}, Next: "verify-deploy"}
+	assert.NoError(t, wf.Validate())
+
+	wf.Next = wf.ID
+	assert.ErrorContains(t, wf.Validate(), "next")
+}
+
+func TestValidate_KubePatterns(t *testing.T) {
+	wf := &Workflow{Title: "T", Steps: []Step{{Command: "true"}}, KubeContext: "prod-*", KubeNamespace: "payments"}
+	assert.NoError(t, wf.Validate())
+
+	wf.KubeContext = "prod-["
+	assert.ErrorContains(t, wf.Validate(), "kube_context")
+
+	wf.KubeContext = ""
+	wf.KubeNamespace = "[a-"
+	assert.ErrorContains(t, wf.Validate(), "kube_namespace")
+}
+
+func TestValidate_AWSAccount(t *testing.T) {
+	wf := &Workflow{Title: "T", Steps: []Step{{Command: "true"}}, AWSAccount: "123456789012"}
+	assert.NoError(t, wf.Validate())
+
+	for _, account := range []string{"12345", "prod", "1234567890123"} {
+		wf.AWSAccount = account
+		assert.ErrorContains(t, wf.Validate(), "aws_account", "account %q", account)
+	}
+}
+
+func TestValidate_Matrix(t *testing.T) {
+	wf := &Workflow{Title: "T", Steps: []Step{{Command: "true"}}, Matrix: map[string][]string{"region": {"us-east-1"}}}
+	assert.NoError(t, wf.Validate())
+
+	wf.Matrix = map[string][]string{"region": {}}
+	assert.ErrorContains(t, wf.Validate(), "has no values")
+
+	wf.Matrix = map[string][]string{"bad key": {"a"}}
+	assert.ErrorContains(t, wf.Validate(), "invalid matrix key")
+}
+
+func TestValidate_Presets(t *testing.T) {
+	wf := &Workflow{
+		Title:        "T",
+		Steps:        []Step{{Command: "deploy <env>"}},
+		Placeholders: map[string]Placeholder{"env": {Validate: "^(staging|prod)$"}},
+		Presets:      map[string]map[string]string{"staging": {"env": "staging", "replicas": "2"}},
+	}
+	assert.NoError(t, wf.Validate())
+
+	wf.Presets = map[string]map[string]string{"bad name": {"env": "prod"}}
+	assert.ErrorContains(t, wf.Validate(), "invalid preset name")
+
+	wf.Presets = map[string]map[string]string{"dev": {"env": "dev"}}
+	assert.ErrorContains(t, wf.Validate(), `preset dev: value "dev" for env does not match`)
+}
+
+func TestStep_MaxDuration(t *testing.T) {
+	wf, err := UnmarshalWorkflow([]byte("schema_version: 1\ntitle: Drain\nsteps:\n  - command: kubectl drain node-1\n    max_duration: 2m30s\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 150*time.Second, wf.Steps[0].MaxDuration)
+
+	data, err := MarshalWorkflow(wf)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "max_duration: 2m30s")
+
+	// A bare number would be nanoseconds, so it is refused
+	_, err = UnmarshalWorkflow([]byte("schema_version: 1\ntitle: Drain\nsteps:\n  - command: kubectl drain node-1\n    max_duration: 30\n"))
+	assert.Error(t, err)
+
+	step := Step{Command: "true", MaxDuration: -time.Second}
+	assert.EqualError(t, step.Validate(), "max_duration must be positive; got -1s")
+}
+
+func TestWorkflow_Cleanup(t *testing.T) {
+	wf, err := UnmarshalWorkflow([]byte("schema_version: 1\ntitle: Drain\nsteps:\n  - command: kubectl cordon node-1\ncleanup:\n  - name: uncordon\n    command: kubectl uncordon node-1\n"))
+	require.NoError(t, err)
+	require.Len(t, wf.Cleanup, 1)
+	assert.Equal(t, "kubectl uncordon node-1", wf.Cleanup[0].Command)
+	require.NoError(t, wf.Validate())
+
+	wf.Cleanup = append(wf.Cleanup, Step{Name: "empty"})
+	assert.ErrorContains(t, wf.Validate(), "cleanup step 1:")
+}
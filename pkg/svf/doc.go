@@ -0,0 +1,31 @@
+// Package svf is the supported Go API for embedding svf in other tools:
+// listing and searching a workflow repository, loading workflows and
+// running them non-interactively, without shelling out to the CLI.
+//
+//	client, err := svf.Open(ctx, svf.Options{})
+//	if err != nil {
+//		return err
+//	}
+//	hits, err := client.Search(ctx, svf.SearchOptions{Query: "tag:deploy"})
+//	...
+//	res, err := client.Run(ctx, "deploy", svf.RunOptions{
+//		Params: map[string]string{"env": "staging"},
+//	})
+//
+// # Compatibility
+//
+// This package follows semantic versioning, reported by APIVersion,
+// independently of the svf release it ships with. Within a major version:
+//
+//   - exported identifiers are not removed or renamed, and function
+//     signatures don't change;
+//   - fields may be added to structs, so construct them with field names;
+//   - methods may be added to Client;
+//   - error values and types documented here keep their meaning.
+//
+// Workflow, Step, Defaults and Placeholder are aliases of the types svf
+// reads workflow files into. Their fields follow the workflow schema,
+// which only grows within a schema version.
+//
+// Nothing under internal/ is covered by these guarantees.
+package svf
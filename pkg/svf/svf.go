@@ -0,0 +1,273 @@
+package svf
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/index"
+	"github.com/chazuruo/svf/internal/redact"
+	runnerpkg "github.com/chazuruo/svf/internal/runner"
+	"github.com/chazuruo/svf/internal/runpath"
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/chazuruo/svf/internal/workflows/store"
+)
+
+// APIVersion is the semantic version of this package's API.
+const APIVersion = "1.0.0"
+
+// Workflow types, as read from workflow files.
+type (
+	Workflow    = workflows.Workflow
+	Step        = workflows.Step
+	Defaults    = workflows.Defaults
+	Placeholder = workflows.Placeholder
+)
+
+// ErrNotFound is returned when a reference matches no workflow.
+var ErrNotFound = store.ErrNotFound
+
+// ErrDangerous is returned by Run when a workflow includes dangerous
+// commands and RunOptions.ConfirmDangerous isn't set.
+var ErrDangerous = runnerpkg.ErrDangerous
+
+// RefusedError is returned by Run when one of svf's checks refuses to run
+// a workflow, the same checks 'svf run' makes.
+type RefusedError = runpath.RefusedError
+
+// RunningError is returned by Run when the workflow is exclusive and
+// another run, here or elsewhere, holds its run lock.
+type RunningError = runpath.RunningError
+
+// ParamError is returned by Run when a parameter fails its placeholder's
+// validation pattern.
+type ParamError = runnerpkg.ParamError
+
+// Options configures Open.
+type Options struct {
+	// ConfigPath is the svf config file. Empty uses the same file as the
+	// CLI, or the defaults if there is none.
+	ConfigPath string
+	// RepoPath overrides the config's repository path.
+	RepoPath string
+}
+
+// Client gives access to one workflow repository.
+type Client struct {
+	config  *config.Config
+	store   store.Store
+	builder *index.Builder
+}
+
+// Open opens the workflow repository configured for svf.
+func Open(ctx context.Context, opts Options) (*Client, error) {
+	var cfg *config.Config
+	var err error
+	if opts.ConfigPath != "" {
+		cfg, err = config.Load(opts.ConfigPath)
+	} else {
+		cfg, err = config.LoadWithDefaults()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	if opts.RepoPath != "" {
+		cfg.Repo.Path = opts.RepoPath
+	}
+
+	if !gitrepo.New(cfg.Repo.Path).IsInitialized(ctx) {
+		return nil, fmt.Errorf("repository not initialized at %s", cfg.Repo.Path)
+	}
+	return newClient(cfg)
+}
+
+// newClient creates a client for cfg's repository.
+func newClient(cfg *config.Config) (*Client, error) {
+	str, err := store.New(gitrepo.New(cfg.Repo.Path), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create store: %w", err)
+	}
+	return &Client{config: cfg, store: str, builder: index.NewBuilder(cfg.Repo.Path, cfg)}, nil
+}
+
+// Entry summarizes an indexed workflow.
+type Entry struct {
+	ID          string
+	Title       string
+	Description string
+	Path        string // Repo-relative path to the workflow file
+	Tags        []string
+	Aliases     []string
+	UpdatedAt   string
+}
+
+// List returns every workflow in the repository, sorted like 'svf list'.
+func (c *Client) List(ctx context.Context) ([]Entry, error) {
+	idx, err := c.builder.LoadFresh()
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]Entry, len(idx.Workflows))
+	for i, e := range idx.Workflows {
+		entries[i] = newEntry(e)
+	}
+	return entries, nil
+}
+
+// SearchOptions configures Search.
+type SearchOptions struct {
+	// Query uses the 'svf search' syntax, e.g. "deploy tag:k8s".
+	Query string
+	// Tags restricts results to workflows with all of these tags.
+	Tags []string
+	// Regex treats query terms as regular expressions.
+	Regex bool
+	// MaxResults limits the results; zero means no limit.
+	MaxResults int
+}
+
+// SearchResult is a search hit.
+type SearchResult struct {
+	Entry
+	Score   float64
+	Matches []string // Matched field names
+}
+
+// Search returns the workflows matching opts, best match first.
+func (c *Client) Search(ctx context.Context, opts SearchOptions) ([]SearchResult, error) {
+	idx, err := c.builder.LoadFresh()
+	if err != nil {
+		return nil, err
+	}
+	results, err := idx.Query(index.SearchOptions{
+		Query:      opts.Query,
+		Tags:       opts.Tags,
+		Regex:      opts.Regex,
+		MaxResults: opts.MaxResults,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid query: %w", err)
+	}
+	hits := make([]SearchResult, len(results))
+	for i, res := range results {
+		hits[i] = SearchResult{Entry: newEntry(res.Entry), Score: res.Score, Matches: res.Matches}
+	}
+	return hits, nil
+}
+
+// Load loads a workflow by ID, alias, slug or path.
+func (c *Client) Load(ctx context.Context, ref string) (*Workflow, error) {
+	_, wf, err := c.load(ctx, ref)
+	return wf, err
+}
+
+// RunOptions configures Run.
+type RunOptions struct {
+	// Params are placeholder values; placeholder defaults fill the rest.
+	Params map[string]string
+	// DryRun resolves every command without running any.
+	DryRun bool
+	// ConfirmDangerous allows dangerous commands to run.
+	ConfirmDangerous bool
+}
+
+// StepResult is the outcome of one step of a run.
+type StepResult struct {
+	Name     string
+	Command  string // With placeholders substituted
+	Ran      bool
+	Skipped  string // Why the step didn't run, e.g. "platform"
+	Success  bool
+	ExitCode int
+	Output   string // Redacted according to runner.redact_logs
+	Error    string
+	Duration time.Duration
+}
+
+// RunResult is the outcome of Run.
+type RunResult struct {
+	Success    bool
+	ExitCode   int
+	FailedStep int // 1-based, 0 if none failed
+	Steps      []StepResult
+}
+
+// Run executes a workflow's steps in order without prompting, like
+// 'svf run --yes'. Nothing runs if a parameter is missing or invalid, if
+// the workflow has unconfirmed dangerous commands, or if one of svf's
+// checks refuses it (a *RefusedError), e.g. an unreviewed shared workflow
+// or a mismatched kubectl context, or if the workflow is exclusive and
+// already running (a *RunningError). A failed step stops the run and is
+// reported in the result, not as an error.
+//
+// Runs are not added to the svf history.
+func (c *Client) Run(ctx context.Context, ref string, opts RunOptions) (*RunResult, error) {
+	wfRef, wf, err := c.load(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := runpath.Run(ctx, c.config, wf, wfRef, runpath.RunOptions{
+		Options:          runpath.Options{DryRun: opts.DryRun, Out: io.Discard},
+		Params:           opts.Params,
+		ConfirmDangerous: opts.ConfirmDangerous,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := &RunResult{
+		Success:    res.Success,
+		ExitCode:   res.ExitCode,
+		FailedStep: res.FailedStep,
+		Steps:      make([]StepResult, len(res.Steps)),
+	}
+	level := c.config.Runner.RedactLogs
+	for i, step := range res.Steps {
+		result := res.Results[i]
+		sr := StepResult{Name: step.Name, Command: step.Command, Skipped: result.SkipReason}
+		if step.Error != nil {
+			sr.Error = step.Error.Error()
+		}
+		if step.Ran {
+			sr.Ran = true
+			sr.Success = result.Success
+			sr.ExitCode = result.ExitCode
+			sr.Output = redact.String(result.Output, level)
+			sr.Duration = result.Duration
+			if result.Error != nil {
+				sr.Error = redact.String(result.Error.Error(), level)
+			}
+		}
+		out.Steps[i] = sr
+	}
+	return out, nil
+}
+
+// load resolves and loads a workflow, returning its file path.
+func (c *Client) load(ctx context.Context, query string) (store.WorkflowRef, *Workflow, error) {
+	ref, err := store.Resolve(ctx, c.store, c.config.Repo.Path, c.config.Workflows.Root, query)
+	if err != nil {
+		return store.WorkflowRef{}, nil, err
+	}
+	wf, err := c.store.Load(ctx, ref)
+	if err != nil {
+		return store.WorkflowRef{}, nil, fmt.Errorf("failed to load workflow: %w", err)
+	}
+	return ref, wf, nil
+}
+
+func newEntry(e index.WorkflowEntry) Entry {
+	return Entry{
+		ID:          e.ID,
+		Title:       e.Title,
+		Description: e.Description,
+		Path:        e.Path,
+		Tags:        e.Tags,
+		Aliases:     e.Aliases,
+		UpdatedAt:   e.UpdatedAt,
+	}
+}
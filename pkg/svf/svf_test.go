@@ -0,0 +1,136 @@
+package svf
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/chazuruo/svf/internal/config"
+	"github.com/chazuruo/svf/internal/gitrepo"
+	"github.com/chazuruo/svf/internal/workflows"
+	"github.com/chazuruo/svf/internal/workflows/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupClient creates a repo with greeting and cleanup workflows and a
+// client for it.
+func setupClient(t *testing.T) *Client {
+	t.Helper()
+	dir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.Repo.Path = dir
+	cfg.Identity.Path = "team/alice"
+
+	str, err := store.New(gitrepo.New(dir), cfg)
+	require.NoError(t, err)
+	for _, wf := range []*workflows.Workflow{
+		{
+			ID:    "wf_greet",
+			Title: "Greet",
+			Tags:  []string{"demo"},
+			Placeholders: map[string]workflows.Placeholder{
+				"name": {Default: "world", Validate: "^[a-z]+$"},
+			},
+			Steps: []workflows.Step{
+				{Name: "Say hello", Command: "echo hello <name>"},
+				{Name: "Fail", Command: "exit 3"},
+				{Name: "Never", Command: "echo unreachable"},
+			},
+		},
+		{
+			ID:    "wf_cleanup",
+			Title: "Cleanup",
+			Steps: []workflows.Step{{Name: "Wipe", Command: "rm -rf /tmp/svf-sdk-test-nothing"}},
+		},
+	} {
+		wf.SchemaVersion = workflows.SchemaVersion
+		_, err := str.Save(context.Background(), wf, store.SaveOptions{})
+		require.NoError(t, err)
+	}
+
+	client, err := newClient(cfg)
+	require.NoError(t, err)
+	return client
+}
+
+func TestClient_ListAndSearch(t *testing.T) {
+	client := setupClient(t)
+	ctx := context.Background()
+
+	entries, err := client.List(ctx)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	hits, err := client.Search(ctx, SearchOptions{Query: "tag:demo"})
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+	assert.Equal(t, "wf_greet", hits[0].ID)
+
+	_, err = client.Search(ctx, SearchOptions{Query: `"unterminated`})
+	assert.Error(t, err)
+}
+
+func TestClient_Load(t *testing.T) {
+	client := setupClient(t)
+
+	wf, err := client.Load(context.Background(), "wf_greet")
+	require.NoError(t, err)
+	assert.Equal(t, "Greet", wf.Title)
+
+	_, err = client.Load(context.Background(), "nope")
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestClient_Run(t *testing.T) {
+	client := setupClient(t)
+	ctx := context.Background()
+
+	res, err := client.Run(ctx, "wf_greet", RunOptions{Params: map[string]string{"name": "gopher"}})
+	require.NoError(t, err)
+	assert.False(t, res.Success)
+	assert.Equal(t, 3, res.ExitCode)
+	assert.Equal(t, 2, res.FailedStep)
+	require.Len(t, res.Steps, 3)
+	assert.Equal(t, "hello gopher\n", res.Steps[0].Output)
+	assert.True(t, res.Steps[1].Ran)
+	assert.False(t, res.Steps[2].Ran)
+
+	_, err = client.Run(ctx, "wf_greet", RunOptions{Params: map[string]string{"name": "NOT VALID"}})
+	var invalid *ParamError
+	assert.True(t, errors.As(err, &invalid))
+
+	_, err = client.Run(ctx, "wf_cleanup", RunOptions{})
+	assert.True(t, errors.Is(err, ErrDangerous))
+
+	res, err = client.Run(ctx, "wf_cleanup", RunOptions{DryRun: true})
+	require.NoError(t, err)
+	assert.False(t, res.Steps[0].Ran)
+}
+
+func TestClient_Run_RequireReview(t *testing.T) {
+	client := setupClient(t)
+	client.config.Runner.RequireReview = true
+	client.config.Workflows.SharedRoot = filepath.Join(client.config.Workflows.Root, "shared")
+	ctx := context.Background()
+
+	str, err := store.New(gitrepo.New(client.config.Repo.Path), client.config)
+	require.NoError(t, err)
+	path := filepath.Join(client.config.Repo.Path, client.config.Workflows.SharedRoot, "certs", "workflow.yaml")
+	_, err = str.Save(ctx, &workflows.Workflow{
+		SchemaVersion: workflows.SchemaVersion,
+		ID:            "wf_certs",
+		Title:         "Rotate certs",
+		Steps:         []workflows.Step{{Name: "Touch", Command: "echo rotated"}},
+	}, store.SaveOptions{Path: path})
+	require.NoError(t, err)
+
+	_, err = client.Run(ctx, "wf_certs", RunOptions{})
+	var refused *RefusedError
+	assert.True(t, errors.As(err, &refused), "err = %v", err)
+
+	// Personal workflows don't need a review
+	_, err = client.Run(ctx, "wf_greet", RunOptions{})
+	assert.NoError(t, err)
+}